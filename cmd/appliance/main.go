@@ -1,12 +1,37 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"github.com/sourcegraph/sourcegraph/cmd/appliance/shared"
 	"github.com/sourcegraph/sourcegraph/internal/sanitycheck"
 	"github.com/sourcegraph/sourcegraph/internal/service/svcmain"
 )
 
+// subcommands run instead of the appliance service, and print their results to
+// stdout.
+var subcommands = map[string]func(args []string) error{
+	"list-images":         func(args []string) error { return shared.ListImages(args, os.Stdout) },
+	"render":              func(args []string) error { return shared.Render(context.Background(), args, os.Stdout) },
+	"diff":                func(args []string) error { return shared.Diff(context.Background(), args, os.Stdout) },
+	"convert-helm-values": func(args []string) error { return shared.ConvertHelmValues(args, os.Stdout, os.Stderr) },
+	"validate-images":     func(args []string) error { return shared.ValidateImages(context.Background(), args, os.Stdout) },
+}
+
 func main() {
 	sanitycheck.Pass()
+
+	if len(os.Args) >= 2 {
+		if subcommand, ok := subcommands[os.Args[1]]; ok {
+			if err := subcommand(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	svcmain.SingleServiceMainWithoutConf(shared.Service, svcmain.OutOfBandConfiguration{})
 }
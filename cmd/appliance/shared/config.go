@@ -2,7 +2,9 @@ package shared
 
 import (
 	"path/filepath"
+	"time"
 
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
@@ -18,7 +20,12 @@ type Config struct {
 	k8sConfig *rest.Config
 	metrics   metricsConfig
 	grpc      grpcConfig
+	health    healthConfig
+	webhooks  webhooksConfig
 	namespace string
+
+	strictSpecDecoding bool
+	selfDeployment     types.NamespacedName
 }
 
 func (c *Config) Load() {
@@ -39,11 +46,26 @@ func (c *Config) Load() {
 	c.metrics.addr = c.Get("APPLIANCE_METRICS_ADDR", ":8080", "Appliance metrics server address.")
 	c.metrics.secure = c.GetBool("APPLIANCE_METRICS_SECURE", "false", "Appliance metrics server uses https.")
 	c.grpc.addr = c.Get("APPLIANCE_GRPC_ADDR", ":9000", "Appliance gRPC address.")
+	c.health.addr = c.Get("APPLIANCE_HEALTH_ADDR", ":8081", "Appliance health server address, serving /healthz, /readyz and /status.")
+	c.health.readinessWindow = c.GetInterval("APPLIANCE_READINESS_WINDOW", "15m", "How long after its last reconcile an appliance ConfigMap keeps the appliance ready.")
+	c.health.resyncInterval = c.GetInterval("APPLIANCE_RESYNC_INTERVAL", "5m", "Interval at which appliance ConfigMaps are reconciled again in the absence of changes. 0 disables it.")
+	c.webhooks.enabled = c.GetBool("APPLIANCE_WEBHOOKS_ENABLED", "false", "Serve the admission webhooks that convert Sourcegraph specs to the latest API version and reject invalid ones.")
+	c.webhooks.port = c.GetInt("APPLIANCE_WEBHOOK_PORT", "9443", "Port of the admission webhook server.")
+	c.webhooks.certDir = c.GetOptional("APPLIANCE_WEBHOOK_CERT_DIR", "Directory containing the tls.crt and tls.key of the admission webhook server. Defaults to the controller-runtime default.")
 	c.namespace = c.Get("APPLIANCE_NAMESPACE", cache.AllNamespaces, "Namespace to monitor. Defaults to all.")
+	c.strictSpecDecoding = c.GetBool("APPLIANCE_STRICT_SPEC_DECODING", "false", "Reject Sourcegraph specs with unknown fields, instead of only warning about them.")
+	c.selfDeployment.Name = c.GetOptional("APPLIANCE_DEPLOYMENT_NAME", "Name of the Deployment that runs the appliance, which specs with operatorUpdatePolicy Automatic update. Unset disables automatic updates.")
+	c.selfDeployment.Namespace = c.GetOptional("APPLIANCE_DEPLOYMENT_NAMESPACE", "Namespace of the Deployment that runs the appliance.")
 }
 
 func (c *Config) Validate() error {
 	var errs error
+	if c.health.resyncInterval > 0 && c.health.resyncInterval >= c.health.readinessWindow {
+		errs = errors.Append(errs, errors.New("APPLIANCE_RESYNC_INTERVAL must be shorter than APPLIANCE_READINESS_WINDOW"))
+	}
+	if c.selfDeployment.Name != "" && c.selfDeployment.Namespace == "" {
+		errs = errors.Append(errs, errors.New("APPLIANCE_DEPLOYMENT_NAMESPACE must be set along with APPLIANCE_DEPLOYMENT_NAME"))
+	}
 	return errs
 }
 
@@ -52,6 +74,18 @@ type metricsConfig struct {
 	secure bool
 }
 
+type webhooksConfig struct {
+	enabled bool
+	port    int
+	certDir string
+}
+
 type grpcConfig struct {
 	addr string
 }
+
+type healthConfig struct {
+	addr            string
+	readinessWindow time.Duration
+	resyncInterval  time.Duration
+}
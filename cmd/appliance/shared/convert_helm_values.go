@@ -0,0 +1,35 @@
+package shared
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ConvertHelmValues implements the convert-helm-values subcommand, which
+// prints the spec equivalent to the values.yaml of a deployment with the
+// Helm chart, and the values it couldn't map to warnOut.
+func ConvertHelmValues(args []string, out, warnOut io.Writer) error {
+	flags := flag.NewFlagSet("convert-helm-values", flag.ContinueOnError)
+	valuesPath := flags.String("values", "-", "Path to the values.yaml of the Helm chart, or - for stdin.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	values, err := readSpec(*valuesPath)
+	if err != nil {
+		return err
+	}
+	spec, warnings, err := config.ConvertHelmValuesYAML(values)
+	for _, warning := range warnings {
+		fmt.Fprintf(warnOut, "warning: %s\n", warning)
+	}
+	if err != nil {
+		return errors.Wrap(err, "converting Helm values")
+	}
+	_, err = out.Write(spec)
+	return err
+}
@@ -0,0 +1,208 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/appliance/reconciler"
+)
+
+// reconcileState is the state of the reconciler that the health endpoints
+// report on.
+type reconcileState interface {
+	// InformersSynced reports whether the caches of the manager have synced,
+	// so that the reconciler sees the cluster.
+	InformersSynced() bool
+
+	// ReconcileResults returns the result of the last reconcile of every
+	// appliance ConfigMap.
+	ReconcileResults() []reconciler.ReconcileResult
+}
+
+// managerState is the reconcileState of a running manager.
+type managerState struct {
+	tracker *reconciler.ReconcileTracker
+	synced  atomic.Bool
+}
+
+// waitForSync marks the informers of mgr as synced once they are. It blocks
+// until then, or until ctx is canceled.
+func (s *managerState) waitForSync(ctx context.Context, mgr ctrl.Manager) {
+	if mgr.GetCache().WaitForCacheSync(ctx) {
+		s.synced.Store(true)
+	}
+}
+
+func (s *managerState) InformersSynced() bool { return s.synced.Load() }
+
+func (s *managerState) ReconcileResults() []reconciler.ReconcileResult {
+	return s.tracker.Results()
+}
+
+// healthHandler serves the health endpoints of the appliance:
+//
+//   - /healthz is the liveness probe. It succeeds as long as the process
+//     serves requests.
+//   - /readyz is the readiness probe. It succeeds once the informers have
+//     synced, and every appliance ConfigMap has been reconciled within the
+//     readiness window. It fails until the first ConfigMap is reconciled.
+//   - /status returns the details behind /readyz as JSON, along with the
+//     state of every service of every ConfigMap.
+//
+// A reconcile that fails still counts as recent: readiness is about the
+// reconciler making progress, while the errors are reported by /status and
+// by the status of the ConfigMap.
+type healthHandler struct {
+	state reconcileState
+
+	// readinessWindow is how long after its last reconcile a ConfigMap is
+	// still considered up to date.
+	readinessWindow time.Duration
+
+	now func() time.Time
+}
+
+func newHealthHandler(state reconcileState, readinessWindow time.Duration) http.Handler {
+	h := &healthHandler{state: state, readinessWindow: readinessWindow, now: time.Now}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", h.serveLiveness)
+	mux.HandleFunc("/readyz", h.serveReadiness)
+	mux.HandleFunc("/status", h.serveStatus)
+	return mux
+}
+
+func (h *healthHandler) serveLiveness(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+func (h *healthHandler) serveReadiness(w http.ResponseWriter, _ *http.Request) {
+	status := h.status()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !status.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		for _, reason := range status.Reasons {
+			_, _ = fmt.Fprintln(w, reason)
+		}
+		return
+	}
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+func (h *healthHandler) serveStatus(w http.ResponseWriter, _ *http.Request) {
+	status := h.status()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !status.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// healthStatus is the body of /status.
+type healthStatus struct {
+	Live            bool              `json:"live"`
+	Ready           bool              `json:"ready"`
+	Reasons         []string          `json:"reasons,omitempty"`
+	InformersSynced bool              `json:"informersSynced"`
+	ConfigMaps      []configMapHealth `json:"configMaps"`
+}
+
+// configMapHealth is the state of an appliance ConfigMap.
+type configMapHealth struct {
+	Namespace         string          `json:"namespace"`
+	Name              string          `json:"name"`
+	LastReconcileTime time.Time       `json:"lastReconcileTime"`
+	Error             string          `json:"error,omitempty"`
+	Ready             bool            `json:"ready"`
+	Services          []serviceHealth `json:"services"`
+}
+
+// serviceHealth is the state of a service of an appliance ConfigMap, taken
+// from the conditions of its status.
+type serviceHealth struct {
+	Name       string `json:"name"`
+	Reconciled bool   `json:"reconciled"`
+	// Available is missing for services without workloads.
+	Available *bool  `json:"available,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+func (h *healthHandler) status() healthStatus {
+	status := healthStatus{
+		Live:            true,
+		InformersSynced: h.state.InformersSynced(),
+		ConfigMaps:      []configMapHealth{},
+	}
+	if !status.InformersSynced {
+		status.Reasons = append(status.Reasons, "informers have not synced")
+	}
+
+	results := h.state.ReconcileResults()
+	if len(results) == 0 {
+		status.Reasons = append(status.Reasons, "no appliance ConfigMap has been reconciled")
+	}
+
+	now := h.now()
+	for _, result := range results {
+		if age := now.Sub(result.Time); age > h.readinessWindow {
+			status.Reasons = append(status.Reasons, fmt.Sprintf(
+				"%s was last reconciled %s ago, longer than %s",
+				result.ConfigMap, age.Round(time.Second), h.readinessWindow,
+			))
+		}
+		status.ConfigMaps = append(status.ConfigMaps, newConfigMapHealth(result))
+	}
+
+	status.Ready = len(status.Reasons) == 0
+	return status
+}
+
+func newConfigMapHealth(result reconciler.ReconcileResult) configMapHealth {
+	cm := configMapHealth{
+		Namespace:         result.ConfigMap.Namespace,
+		Name:              result.ConfigMap.Name,
+		LastReconcileTime: result.Time,
+		Ready:             meta.IsStatusConditionTrue(result.Status.Conditions, config.ConditionReady),
+		Services:          make([]serviceHealth, 0, len(result.Status.Services)),
+	}
+	if result.Err != nil {
+		cm.Error = result.Err.Error()
+	}
+
+	for _, svc := range result.Status.Services {
+		health := serviceHealth{
+			Name:       svc.Name,
+			Reconciled: meta.IsStatusConditionTrue(svc.Conditions, config.ConditionReconciled),
+		}
+		if available := meta.FindStatusCondition(svc.Conditions, config.ConditionAvailable); available != nil {
+			ok := available.Status == metav1.ConditionTrue
+			health.Available = &ok
+		}
+		health.Message = serviceMessage(svc.Conditions)
+		cm.Services = append(cm.Services, health)
+	}
+	return cm
+}
+
+// serviceMessage returns the message of the first condition of a service
+// that is not true, which explains what is wrong with it.
+func serviceMessage(conditions []metav1.Condition) string {
+	for _, condition := range conditions {
+		if condition.Status != metav1.ConditionTrue {
+			return condition.Message
+		}
+	}
+	return ""
+}
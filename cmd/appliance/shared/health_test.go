@@ -0,0 +1,123 @@
+package shared
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/appliance/reconciler"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestHealthHandler(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	cm := types.NamespacedName{Namespace: "sourcegraph", Name: "sg"}
+
+	readyStatus := config.SourcegraphStatus{
+		Conditions: []metav1.Condition{{Type: config.ConditionReady, Status: metav1.ConditionTrue}},
+		Services: []config.ServiceStatus{
+			{Name: "frontend", Conditions: []metav1.Condition{
+				{Type: config.ConditionReconciled, Status: metav1.ConditionTrue},
+				{Type: config.ConditionAvailable, Status: metav1.ConditionTrue},
+			}},
+			{Name: "gitserver", Conditions: []metav1.Condition{
+				{Type: config.ConditionReconciled, Status: metav1.ConditionTrue},
+				{Type: config.ConditionAvailable, Status: metav1.ConditionFalse, Message: "1 of 2 pods available"},
+			}},
+			{Name: "cadvisor", Conditions: []metav1.Condition{
+				{Type: config.ConditionReconciled, Status: metav1.ConditionTrue},
+			}},
+		},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		state     fakeReconcileState
+		wantReady bool
+	}{
+		{
+			name:      "recently reconciled",
+			state:     fakeReconcileState{synced: true, results: []reconciler.ReconcileResult{{ConfigMap: cm, Time: now.Add(-time.Minute), Status: readyStatus}}},
+			wantReady: true,
+		},
+		{
+			name:      "recently failed",
+			state:     fakeReconcileState{synced: true, results: []reconciler.ReconcileResult{{ConfigMap: cm, Time: now.Add(-time.Minute), Err: errors.New("boom")}}},
+			wantReady: true,
+		},
+		{
+			name:  "stale reconcile",
+			state: fakeReconcileState{synced: true, results: []reconciler.ReconcileResult{{ConfigMap: cm, Time: now.Add(-time.Hour), Status: readyStatus}}},
+		},
+		{
+			name:  "never reconciled",
+			state: fakeReconcileState{synced: true},
+		},
+		{
+			name:  "informers not synced",
+			state: fakeReconcileState{results: []reconciler.ReconcileResult{{ConfigMap: cm, Time: now, Status: readyStatus}}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &healthHandler{state: tc.state, readinessWindow: 15 * time.Minute, now: func() time.Time { return now }}
+
+			rec := httptest.NewRecorder()
+			h.serveLiveness(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			require.Equal(t, http.StatusOK, rec.Code, "always live")
+
+			rec = httptest.NewRecorder()
+			h.serveReadiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if tc.wantReady {
+				require.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+				require.NotEmpty(t, rec.Body.String(), "the reasons are reported")
+			}
+
+			rec = httptest.NewRecorder()
+			h.serveStatus(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+			var status healthStatus
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+			require.True(t, status.Live)
+			require.Equal(t, tc.wantReady, status.Ready)
+			require.Equal(t, tc.state.synced, status.InformersSynced)
+			require.Len(t, status.ConfigMaps, len(tc.state.results))
+		})
+	}
+
+	t.Run("per-service status", func(t *testing.T) {
+		state := fakeReconcileState{synced: true, results: []reconciler.ReconcileResult{{ConfigMap: cm, Time: now, Status: readyStatus}}}
+		h := &healthHandler{state: state, readinessWindow: 15 * time.Minute, now: func() time.Time { return now }}
+
+		status := h.status()
+		require.Len(t, status.ConfigMaps, 1)
+		got := status.ConfigMaps[0]
+		require.Equal(t, "sourcegraph", got.Namespace)
+		require.Equal(t, "sg", got.Name)
+		require.True(t, got.Ready)
+		require.Empty(t, got.Error)
+
+		available, unavailable := true, false
+		require.Equal(t, []serviceHealth{
+			{Name: "frontend", Reconciled: true, Available: &available},
+			{Name: "gitserver", Reconciled: true, Available: &unavailable, Message: "1 of 2 pods available"},
+			{Name: "cadvisor", Reconciled: true},
+		}, got.Services)
+	})
+}
+
+type fakeReconcileState struct {
+	synced  bool
+	results []reconciler.ReconcileResult
+}
+
+func (s fakeReconcileState) InformersSynced() bool { return s.synced }
+
+func (s fakeReconcileState) ReconcileResults() []reconciler.ReconcileResult { return s.results }
@@ -0,0 +1,53 @@
+package shared
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ListImages implements the list-images subcommand, which prints every image
+// that a spec pulls, e.g. to mirror them before an air-gapped deployment.
+func ListImages(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("list-images", flag.ContinueOnError)
+	specPath := flags.String("spec", "-", "Path to the Sourcegraph spec, the YAML stored under the spec key of the appliance ConfigMap, or - for stdin.")
+	format := flags.String("format", "text", "Output format: text, one image per line, or json.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := readSpec(*specPath)
+	if err != nil {
+		return err
+	}
+
+	sg, err := config.NewConfigFromYAML(data)
+	if err != nil {
+		return errors.Wrap(err, "parsing spec")
+	}
+	if err := sg.Validate(); err != nil {
+		return errors.Wrap(err, "validating spec")
+	}
+	images, err := config.ListImages(&sg)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		for _, image := range images {
+			fmt.Fprintln(out, image)
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(images)
+	default:
+		return errors.Newf("unknown format %q, expected text or json", *format)
+	}
+}
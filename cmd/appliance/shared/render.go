@@ -0,0 +1,107 @@
+package shared
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/reconciler"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Render implements the render subcommand, which prints the objects that the
+// appliance would create for a spec as multi-document YAML, without touching
+// a cluster.
+func Render(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	specPath := flags.String("spec", "-", "Path to the Sourcegraph spec, the YAML stored under the spec key of the appliance ConfigMap, or - for stdin.")
+	namespace := flags.String("namespace", "default", "Namespace that the appliance ConfigMap is in.")
+	configMap := flags.String("configmap", "sg", "Name of the appliance ConfigMap.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	spec, err := readSpec(*specPath)
+	if err != nil {
+		return err
+	}
+	// The reconciler logs every object it creates, which would drown out the
+	// output.
+	ctrl.SetLogger(logr.Discard())
+	objs, err := reconciler.Render(ctx, types.NamespacedName{Namespace: *namespace, Name: *configMap}, spec)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		data, err := k8syaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrapf(err, "marshalling %s %s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName())
+		}
+		fmt.Fprintf(out, "---\n%s", data)
+	}
+	return nil
+}
+
+// Diff implements the diff subcommand, which prints the objects that the next
+// reconcile of an appliance ConfigMap would create, update, or delete, if its
+// spec were replaced. The cluster is only read from.
+func Diff(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	specPath := flags.String("spec", "", "Path to the new Sourcegraph spec, or - for stdin. Defaults to the spec in the appliance ConfigMap.")
+	namespace := flags.String("namespace", "default", "Namespace that the appliance ConfigMap is in.")
+	configMap := flags.String("configmap", "sg", "Name of the appliance ConfigMap.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var spec []byte
+	if *specPath != "" {
+		var err error
+		if spec, err = readSpec(*specPath); err != nil {
+			return err
+		}
+	}
+
+	ctrl.SetLogger(logr.Discard())
+	k8sConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "creating kubernetes client config")
+	}
+	k8sClient, err := client.New(k8sConfig, client.Options{})
+	if err != nil {
+		return errors.Wrap(err, "creating kubernetes client")
+	}
+	changes, err := reconciler.Diff(ctx, k8sClient, types.NamespacedName{Namespace: *namespace, Name: *configMap}, spec)
+	if err != nil {
+		return err
+	}
+
+	counts := map[reconciler.ChangeAction]int{}
+	for _, change := range changes {
+		fmt.Fprintln(out, change)
+		counts[change.Action]++
+	}
+	fmt.Fprintf(out, "%d to create, %d to update, %d to delete.\n",
+		counts[reconciler.ChangeCreate], counts[reconciler.ChangeUpdate], counts[reconciler.ChangeDelete])
+	return nil
+}
+
+func readSpec(path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	return data, errors.Wrap(err, "reading spec")
+}
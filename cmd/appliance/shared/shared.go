@@ -3,6 +3,7 @@ package shared
 import (
 	"context"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -13,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/sourcegraph/log"
 	sglogr "github.com/sourcegraph/log/logr"
@@ -23,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/grpc/defaults"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/service"
+	"github.com/sourcegraph/sourcegraph/internal/version"
 )
 
 var onlyOneSignalHandler = make(chan struct{})
@@ -41,7 +44,7 @@ func Start(ctx context.Context, observationCtx *observation.Context, ready servi
 
 	app := appliance.NewAppliance(k8sClient)
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	options := ctrl.Options{
 		Logger: logr,
 		Metrics: metricsserver.Options{
 			BindAddress:   config.metrics.addr,
@@ -52,20 +55,38 @@ func Start(ctx context.Context, observationCtx *observation.Context, ready servi
 				config.namespace: {},
 			},
 		},
-	})
+	}
+	if config.webhooks.enabled {
+		options.WebhookServer = webhook.NewServer(webhook.Options{
+			Port:    config.webhooks.port,
+			CertDir: config.webhooks.certDir,
+		})
+	}
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		logger.Error("unable to start manager", log.Error(err))
 		return err
 	}
 
-	if err = (&reconciler.Reconciler{
+	tracker := reconciler.NewReconcileTracker()
+	r := &reconciler.Reconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("sourcegraph-appliance"),
-	}).SetupWithManager(mgr); err != nil {
+
+		StrictSpecDecoding: config.strictSpecDecoding,
+		Tracker:            tracker,
+		ResyncInterval:     config.health.resyncInterval,
+		OperatorVersion:    operatorVersion(),
+		OperatorDeployment: config.selfDeployment,
+	}
+	if err = r.SetupWithManager(mgr); err != nil {
 		logger.Error("unable to create the appliance controller", log.Error(err))
 		return err
 	}
+	if config.webhooks.enabled {
+		r.SetupWebhooksWithManager(mgr)
+	}
 
 	// Mark health server as ready
 	ready()
@@ -78,6 +99,12 @@ func Start(ctx context.Context, observationCtx *observation.Context, ready servi
 
 	grpcServer := makeGRPCServer(logger, app)
 
+	state := &managerState{tracker: tracker}
+	healthServer := &http.Server{
+		Addr:    config.health.addr,
+		Handler: newHealthHandler(state, config.health.readinessWindow),
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 	ctx = shutdownOnSignal(ctx)
 
@@ -99,8 +126,23 @@ func Start(ctx context.Context, observationCtx *observation.Context, ready servi
 		return nil
 	})
 
+	g.Go(func() error {
+		logger.Info("health server listening", log.String("address", healthServer.Addr))
+		if err := healthServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("problem running health server", log.Error(err))
+			return err
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		state.waitForSync(ctx, mgr)
+		return nil
+	})
+
 	g.Go(func() error {
 		<-ctx.Done()
+		_ = healthServer.Shutdown(context.Background())
 		grpcServer.GracefulStop()
 		logger.Info("shutting down gRPC server gracefully")
 		return ctx.Err()
@@ -135,3 +177,12 @@ func shutdownOnSignal(ctx context.Context) context.Context {
 
 	return ctx
 }
+
+// operatorVersion returns the version of the appliance, or an empty one for
+// dev builds, which have no images to update to.
+func operatorVersion() string {
+	if v := version.Version(); !version.IsDev(v) {
+		return v
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package shared
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ValidateImages implements the validate-images subcommand, which checks the
+// default images of every version that the appliance ships, and with -network,
+// that their registry serves their digests.
+func ValidateImages(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("validate-images", flag.ContinueOnError)
+	network := flags.Bool("network", false, "Also check that the registry serves the digest of every image.")
+	imageRepository := flags.String("image-repository", config.NewDefaultConfig().Spec.ImageRepository, "Repository to look the images up in with -network, like the imageRepository of a spec.")
+	concurrency := flags.Int("concurrency", 8, "Maximum number of concurrent registry requests with -network.")
+	timeout := flags.Duration("timeout", 5*time.Minute, "Timeout of the registry checks with -network.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *concurrency < 1 {
+		return errors.New("-concurrency must be at least 1")
+	}
+
+	if err := config.ValidateImageManifests(); err != nil {
+		return errors.Wrap(err, "invalid image manifests")
+	}
+	fmt.Fprintln(out, "Image manifests are valid.")
+	if !*network {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+	unreachable, err := config.CheckImageDigests(ctx, http.DefaultClient, *imageRepository, *concurrency)
+	if err != nil {
+		return errors.Wrap(err, "checking image digests")
+	}
+	if len(unreachable) == 0 {
+		fmt.Fprintf(out, "Every digest is served by %s.\n", *imageRepository)
+		return nil
+	}
+	version := ""
+	for _, u := range unreachable {
+		if u.Version != version {
+			version = u.Version
+			fmt.Fprintf(out, "\nVersion %s:\n", version)
+		}
+		fmt.Fprintf(out, "  %s\n", u)
+	}
+	return errors.Newf("%d digests are not served by %s", len(unreachable), *imageRepository)
+}
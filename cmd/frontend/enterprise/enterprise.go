@@ -41,8 +41,15 @@ type Services struct {
 	CodeInsightsDataExportHandler http.Handler
 
 	// Handler for exporting search jobs data.
-	SearchJobsDataExportHandler http.Handler
-	SearchJobsLogsHandler       http.Handler
+	SearchJobsDataExportHandler    http.Handler
+	SearchJobsCSVExportHandler     http.Handler
+	SearchJobsParquetExportHandler http.Handler
+	SearchJobsLogsHandler          http.Handler
+	SearchJobsSummaryHandler       http.Handler
+
+	// Handler for inspecting and force-failing or requeueing the tasks of
+	// search jobs, for site admins.
+	SearchJobsTasksHandler http.Handler
 
 	// Handler for completions stream.
 	NewChatCompletionsStreamHandler NewChatCompletionsStreamHandler
@@ -121,7 +128,11 @@ func DefaultServices() Services {
 		NewChatCompletionsStreamHandler: func() http.Handler { return makeNotFoundHandler("chat completions streaming endpoint") },
 		NewCodeCompletionsHandler:       func() http.Handler { return makeNotFoundHandler("code completions streaming endpoint") },
 		SearchJobsDataExportHandler:     makeNotFoundHandler("search jobs data export handler"),
+		SearchJobsCSVExportHandler:      makeNotFoundHandler("search jobs csv export handler"),
+		SearchJobsParquetExportHandler:  makeNotFoundHandler("search jobs parquet export handler"),
 		SearchJobsLogsHandler:           makeNotFoundHandler("search jobs logs handler"),
+		SearchJobsSummaryHandler:        makeNotFoundHandler("search jobs summary handler"),
+		SearchJobsTasksHandler:          makeNotFoundHandler("search jobs tasks handler"),
 	}
 }
 
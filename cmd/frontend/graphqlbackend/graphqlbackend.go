@@ -429,6 +429,10 @@ func NewSchemaWithCompletionsResolver(db database.DB, completionsResolver Comple
 	return NewSchema(db, gitserver.NewClient("graphql.schemaresolver"), []OptionalResolver{{CompletionsResolver: completionsResolver}})
 }
 
+func NewSchemaWithSearchJobsResolver(db database.DB, searchJobs SearchJobsResolver) (*graphql.Schema, error) {
+	return NewSchema(db, gitserver.NewClient("graphql.schemaresolver"), []OptionalResolver{{SearchJobsResolver: searchJobs}})
+}
+
 func NewSchema(
 	db database.DB,
 	gitserverClient gitserver.Client,
@@ -14,8 +14,10 @@ type SearchJobsResolver interface {
 	CreateSearchJob(ctx context.Context, args *CreateSearchJobArgs) (SearchJobResolver, error)
 	CancelSearchJob(ctx context.Context, args *CancelSearchJobArgs) (*EmptyResponse, error)
 	DeleteSearchJob(ctx context.Context, args *DeleteSearchJobArgs) (*EmptyResponse, error)
+	UpdateSearchJobMetadata(ctx context.Context, args *UpdateSearchJobMetadataArgs) (SearchJobResolver, error)
 
 	// Queries
+	SearchJob(ctx context.Context, args *SearchJobArgs) (SearchJobResolver, error)
 	SearchJobs(ctx context.Context, args *SearchJobsArgs) (*graphqlutil.ConnectionResolver[SearchJobResolver], error)
 	ValidateSearchJob(ctx context.Context, args *CreateSearchJobArgs) (*EmptyResponse, error)
 
@@ -33,7 +35,34 @@ type ValidateSearchJobQueryResolver interface {
 }
 
 type CreateSearchJobArgs struct {
-	Query string
+	Query         string
+	MaxResults    *int32
+	WebhookURL    *string
+	WebhookSecret *string
+	Priority      *string
+
+	MaxDurationSeconds *int32
+
+	SampleRate *float64
+	SampleSeed *BigInt
+
+	DeduplicateResults *bool
+
+	Description *string
+	Labels      *[]SearchJobLabelInput
+
+	IdempotencyKey *string
+}
+
+type SearchJobLabelInput struct {
+	Key   string
+	Value string
+}
+
+type UpdateSearchJobMetadataArgs struct {
+	ID          graphql.ID
+	Description string
+	Labels      []SearchJobLabelInput
 }
 
 type SearchJobResolver interface {
@@ -42,11 +71,32 @@ type SearchJobResolver interface {
 	State(ctx context.Context) string
 	Creator(ctx context.Context) (*UserResolver, error)
 	CreatedAt() gqlutil.DateTime
+	UpdatedAt() gqlutil.DateTime
 	StartedAt(ctx context.Context) *gqlutil.DateTime
 	FinishedAt(ctx context.Context) *gqlutil.DateTime
 	URL(ctx context.Context) (*string, error)
 	LogURL(ctx context.Context) (*string, error)
 	RepoStats(ctx context.Context) (SearchJobStatsResolver, error)
+	Truncated() bool
+	Priority() string
+	Deadline() *gqlutil.DateTime
+	DeadlineExceeded() bool
+	Sample() SearchJobSampleResolver
+	DeduplicateResults() bool
+	Description() string
+	Labels() []SearchJobLabelResolver
+}
+
+type SearchJobLabelResolver interface {
+	Key() string
+	Value() string
+}
+
+type SearchJobSampleResolver interface {
+	Rate() float64
+	Seed() BigInt
+	SampledTaskCount() int32
+	TotalTaskCount() int32
 }
 
 type SearchJobStatsResolver interface {
@@ -86,6 +136,7 @@ type SearchJobsArgs struct {
 	graphqlutil.ConnectionResolverArgs
 	Query      *string
 	States     *[]string
+	Labels     *[]SearchJobLabelInput
 	OrderBy    string
 	Descending bool
 	UserIDs    *[]graphql.ID
@@ -354,7 +354,11 @@ func makeExternalAPI(db database.DB, logger sglog.Logger, schema *graphql.Schema
 			NewComputeStreamHandler:         enterprise.NewComputeStreamHandler,
 			CodeInsightsDataExportHandler:   enterprise.CodeInsightsDataExportHandler,
 			SearchJobsDataExportHandler:     enterprise.SearchJobsDataExportHandler,
+			SearchJobsCSVExportHandler:      enterprise.SearchJobsCSVExportHandler,
+			SearchJobsParquetExportHandler:  enterprise.SearchJobsParquetExportHandler,
 			SearchJobsLogsHandler:           enterprise.SearchJobsLogsHandler,
+			SearchJobsSummaryHandler:        enterprise.SearchJobsSummaryHandler,
+			SearchJobsTasksHandler:          enterprise.SearchJobsTasksHandler,
 			NewDotcomLicenseCheckHandler:    enterprise.NewDotcomLicenseCheckHandler,
 			NewChatCompletionsStreamHandler: enterprise.NewChatCompletionsStreamHandler,
 			NewCodeCompletionsHandler:       enterprise.NewCodeCompletionsHandler,
@@ -3,6 +3,7 @@ package executorqueue
 import (
 	"github.com/sourcegraph/log"
 
+	confpkg "github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -22,7 +23,7 @@ func Init(
 	batchesWorkspaceFileExistsHandler := enterpriseServices.BatchesChangesFileGetHandler
 
 	accessToken := func() string {
-		return conf.SiteConfig().ExecutorsAccessToken
+		return confpkg.ExecutorsAccessTokenOf(conf.SiteConfig())
 	}
 
 	logger := log.Scoped("executorqueue")
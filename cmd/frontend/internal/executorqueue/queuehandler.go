@@ -244,7 +244,7 @@ func validateJobRequest(
 		// 🚨 SECURITY: Use constant-time comparisons to avoid leaking the verification
 		// code via timing attack. It is not important to avoid leaking the *length* of
 		// the code, because the length of verification codes is constant.
-		if subtle.ConstantTimeCompare([]byte(authToken), []byte(conf.SiteConfig().ExecutorsAccessToken)) == 1 {
+		if subtle.ConstantTimeCompare([]byte(authToken), []byte(conf.ExecutorsAccessToken())) == 1 {
 			return true
 		} else {
 			w.WriteHeader(http.StatusForbidden)
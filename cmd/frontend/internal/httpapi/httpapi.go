@@ -79,8 +79,12 @@ type Handlers struct {
 	CodeInsightsDataExportHandler http.Handler
 
 	// Search jobs
-	SearchJobsDataExportHandler http.Handler
-	SearchJobsLogsHandler       http.Handler
+	SearchJobsDataExportHandler    http.Handler
+	SearchJobsCSVExportHandler     http.Handler
+	SearchJobsParquetExportHandler http.Handler
+	SearchJobsLogsHandler          http.Handler
+	SearchJobsSummaryHandler       http.Handler
+	SearchJobsTasksHandler         http.Handler
 
 	// Dotcom license check
 	NewDotcomLicenseCheckHandler enterprise.NewDotcomLicenseCheckHandler
@@ -168,7 +172,13 @@ func NewHandler(
 	m.Path("/insights/export/{id}").Methods("GET").Handler(handlers.CodeInsightsDataExportHandler)
 	m.Path("/search/stream").Methods("GET").Handler(frontendsearch.StreamHandler(db))
 	m.Path("/search/export/{id}.jsonl").Methods("GET").Handler(handlers.SearchJobsDataExportHandler)
+	m.Path("/search/export/{id}.csv").Methods("GET").Handler(handlers.SearchJobsCSVExportHandler)
+	m.Path("/search/export/{id}.parquet").Methods("GET").Handler(handlers.SearchJobsParquetExportHandler)
 	m.Path("/search/export/{id}.log").Methods("GET").Handler(handlers.SearchJobsLogsHandler)
+	m.Path("/search/export/{id}.summary.json").Methods("GET").Handler(handlers.SearchJobsSummaryHandler)
+	m.Path("/search/jobs/{id}/tasks").Methods("GET").Handler(handlers.SearchJobsTasksHandler)
+	m.Path("/search/jobs/tasks/{taskID}").Methods("GET").Handler(handlers.SearchJobsTasksHandler)
+	m.Path("/search/jobs/tasks/{taskID}/{action:fail|requeue}").Methods("POST").Handler(handlers.SearchJobsTasksHandler)
 
 	m.Path("/completions/stream").Methods("POST").Handler(handlers.NewChatCompletionsStreamHandler())
 	m.Path("/completions/code").Methods("POST").Handler(handlers.NewCodeCompletionsHandler())
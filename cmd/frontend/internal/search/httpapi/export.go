@@ -1,10 +1,13 @@
 package httpapi
 
 import (
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -14,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -22,8 +26,23 @@ func filenamePrefix(jobID int) string {
 	return fmt.Sprintf("search-jobs_%d_%s", jobID, time.Now().Format("2006-01-02_150405"))
 }
 
-func ServeSearchJobDownload(logger log.Logger, svc *service.Service) http.HandlerFunc {
-	logger = logger.With(log.String("handler", "ServeSearchJobDownload"))
+// resultsSchemaVersionHeader is the response header with the version of the
+// schema of the results, see types.ResultsSchemaVersion.
+const resultsSchemaVersionHeader = "X-Sourcegraph-Search-Job-Results-Schema-Version"
+
+// The response headers which mark the results of a sampled search job, see
+// types.ResultsSample. They are only set for sampled results.
+const (
+	resultsSampleRateHeader   = "X-Sourcegraph-Search-Job-Sample-Rate"
+	resultsSampleSeedHeader   = "X-Sourcegraph-Search-Job-Sample-Seed"
+	resultsSampledTasksHeader = "X-Sourcegraph-Search-Job-Sampled-Tasks"
+	resultsTotalTasksHeader   = "X-Sourcegraph-Search-Job-Total-Tasks"
+)
+
+// ServeSearchJobDownload serves the results of a search job in the given
+// format.
+func ServeSearchJobDownload(logger log.Logger, svc *service.Service, format service.ResultFormat) http.HandlerFunc {
+	logger = logger.With(log.String("handler", "ServeSearchJobDownload"), log.String("format", format.String()))
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		jobIDStr := mux.Vars(r)["id"]
@@ -33,15 +52,93 @@ func ServeSearchJobDownload(logger log.Logger, svc *service.Service) http.Handle
 			return
 		}
 
-		writerTo, err := svc.GetSearchJobResultsWriterTo(r.Context(), int64(jobID))
+		results, err := svc.GetSearchJobResultsWriterTo(r.Context(), int64(jobID), format)
 		if err != nil {
 			httpError(w, err)
 			return
 		}
+		var writerTo io.WriterTo = results
+
+		// Exports of large jobs are big but compress well, so we compress
+		// them ourselves for clients which accept it. Clients which don't get
+		// the results uncompressed. Parquet files are already compressed.
+		if format != service.ResultFormatParquet && acceptsGzip(r) {
+			writerTo = gzipWriterTo{writerTo}
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Add("Vary", "Accept-Encoding")
+		}
+
+		// Consumers check the version to detect changes of the columns and
+		// the order of the results.
+		w.Header().Set(resultsSchemaVersionHeader, strconv.Itoa(types.ResultsSchemaVersion))
+		// Consumers must not mistake a sample for the complete results.
+		if sample := results.Sample; sample != nil {
+			w.Header().Set(resultsSampleRateHeader, strconv.FormatFloat(sample.Rate, 'g', -1, 64))
+			w.Header().Set(resultsSampleSeedHeader, strconv.FormatInt(sample.Seed, 10))
+			w.Header().Set(resultsSampledTasksHeader, strconv.Itoa(sample.SampledTasks))
+			w.Header().Set(resultsTotalTasksHeader, strconv.Itoa(sample.TotalTasks))
+		}
+
+		filename := filenamePrefix(jobID) + "." + format.String()
+		logger := logger.With(log.Int("jobID", jobID))
+		switch format {
+		case service.ResultFormatJSONL:
+			writeJSON(logger, w, filename, writerTo)
+		case service.ResultFormatParquet:
+			writeParquet(logger, w, filename, writerTo)
+		default:
+			writeCSV(logger, w, filename, writerTo)
+		}
+	}
+}
+
+// acceptsGzip returns true if the Accept-Encoding header of r allows a gzip
+// encoded response.
+func acceptsGzip(r *http.Request) bool {
+	for _, header := range r.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(header, ",") {
+			name, params, _ := strings.Cut(coding, ";")
+			if strings.TrimSpace(name) != "gzip" {
+				continue
+			}
+			// "gzip;q=0" means the client does not accept gzip.
+			q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q=")
+			if ok {
+				if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// gzipWriterTo gzip compresses the output of an io.WriterTo. The number of
+// bytes it reports is the number of compressed bytes.
+type gzipWriterTo struct {
+	io.WriterTo
+}
 
-		filename := filenamePrefix(jobID) + ".jsonl"
-		writeJSON(logger.With(log.Int("jobID", jobID)), w, filename, writerTo)
+func (g gzipWriterTo) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	zw := gzip.NewWriter(cw)
+	if _, err := g.WriterTo.WriteTo(zw); err != nil {
+		return cw.n, err
 	}
+	err := zw.Close()
+	return cw.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
 }
 
 func ServeSearchJobLogs(logger log.Logger, svc *service.Service) http.HandlerFunc {
@@ -66,6 +163,35 @@ func ServeSearchJobLogs(logger log.Logger, svc *service.Service) http.HandlerFun
 	}
 }
 
+// ServeSearchJobSummary serves the summary of a search job, which is written
+// once the job finished, see service.GetSearchJobSummary.
+func ServeSearchJobSummary(logger log.Logger, svc *service.Service) http.HandlerFunc {
+	logger = logger.With(log.String("handler", "ServeSearchJobSummary"))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobIDStr := mux.Vars(r)["id"]
+		jobID, err := strconv.Atoi(jobIDStr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		summary, err := svc.GetSearchJobSummary(r.Context(), int64(jobID))
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+
+		filename := filenamePrefix(jobID) + ".summary.json"
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+		w.WriteHeader(200)
+		if err := json.NewEncoder(w).Encode(summary); err != nil {
+			logger.Warn("failed while writing search job summary response", log.Int("jobID", jobID), log.Error(err))
+		}
+	}
+}
+
 func writeCSV(logger log.Logger, w http.ResponseWriter, filenameNoQuotes string, writerTo io.WriterTo) {
 	w.Header().Set("Content-Type", "text/csv")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filenameNoQuotes))
@@ -86,11 +212,23 @@ func writeJSON(logger log.Logger, w http.ResponseWriter, filenameNoQuotes string
 	}
 }
 
+func writeParquet(logger log.Logger, w http.ResponseWriter, filenameNoQuotes string, writerTo io.WriterTo) {
+	w.Header().Set("Content-Type", "application/vnd.apache.parquet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filenameNoQuotes))
+	w.WriteHeader(200)
+	n, err := writerTo.WriteTo(w)
+	if err != nil {
+		logger.Warn("failed while writing search job parquet response", log.String("filename", filenameNoQuotes), log.Int64("bytesWritten", n), log.Error(err))
+	}
+}
+
 func httpError(w http.ResponseWriter, err error) {
 	switch {
-	case errors.Is(err, auth.ErrMustBeSiteAdminOrSameUser):
+	case errors.Is(err, auth.ErrMustBeSiteAdminOrSameUser), errors.Is(err, auth.ErrMustBeSiteAdmin), errors.HasType(err, &service.InaccessibleReposError{}):
 		http.Error(w, err.Error(), http.StatusForbidden)
-	case errors.Is(err, store.ErrNoResults):
+	case errors.Is(err, service.ErrTaskStateConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, store.ErrNoResults), errors.Is(err, service.ErrSearchJobSummaryNotFound):
 		http.Error(w, err.Error(), http.StatusNotFound)
 	default:
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2,7 +2,10 @@ package httpapi
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -46,7 +49,7 @@ func TestServeSearchJobDownload(t *testing.T) {
 	svc := service.New(observationCtx, s, mockUploadStore, service.NewSearcherFake())
 
 	router := mux.NewRouter()
-	router.HandleFunc("/{id}.json", ServeSearchJobDownload(logger, svc))
+	router.HandleFunc("/{id}.json", ServeSearchJobDownload(logger, svc, service.ResultFormatJSONL))
 
 	// no job
 	{
@@ -60,26 +63,70 @@ func TestServeSearchJobDownload(t *testing.T) {
 	}
 
 	// no blobs
+	bobID, err := createUser(bs, "bob")
+	require.NoError(t, err)
 	{
 		// create job
-		userID, err := createUser(bs, "bob")
-		require.NoError(t, err)
 		userCtx := actor.WithActor(context.Background(), &actor.Actor{
-			UID: userID,
+			UID: bobID,
 		})
-		_, err = svc.CreateSearchJob(userCtx, "1@rev1")
+		_, err = svc.CreateSearchJob(userCtx, "1@rev1", service.CreateSearchJobOpts{})
 		require.NoError(t, err)
 
 		req, err := http.NewRequest(http.MethodGet, "/1.json", nil)
 		require.NoError(t, err)
 
-		req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: userID}))
+		req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: bobID}))
 		w := httptest.NewRecorder()
 		w.Body = &bytes.Buffer{}
 		router.ServeHTTP(w, req)
 
 		require.Equal(t, http.StatusOK, w.Code)
 		require.Equal(t, "", w.Body.String())
+		require.Empty(t, w.Header().Get("Content-Encoding"))
+		require.Equal(t, "2", w.Header().Get(resultsSchemaVersionHeader))
+		require.Empty(t, w.Header().Get(resultsSampleRateHeader))
+	}
+
+	// no blobs, gzip encoded
+	{
+		req, err := http.NewRequest(http.MethodGet, "/1.json", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept-Encoding", "deflate, gzip;q=0.8")
+
+		req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: bobID}))
+		w := httptest.NewRecorder()
+		w.Body = &bytes.Buffer{}
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		zr, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		require.Equal(t, "", string(body))
+	}
+
+	// sampled results are marked
+	{
+		userCtx := actor.WithActor(context.Background(), &actor.Actor{UID: bobID})
+		job, err := svc.CreateSearchJob(userCtx, "1@rev1", service.CreateSearchJobOpts{SampleRate: 0.5, SampleSeed: 7})
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/%d.json", job.ID), nil)
+		require.NoError(t, err)
+
+		req = req.WithContext(userCtx)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "0.5", w.Header().Get(resultsSampleRateHeader))
+		require.Equal(t, "7", w.Header().Get(resultsSampleSeedHeader))
+		// The job wasn't expanded yet.
+		require.Equal(t, "0", w.Header().Get(resultsSampledTasksHeader))
+		require.Equal(t, "0", w.Header().Get(resultsTotalTasksHeader))
 	}
 
 	// wrong user
@@ -98,6 +145,26 @@ func TestServeSearchJobDownload(t *testing.T) {
 	}
 }
 
+func TestAcceptsGzip(t *testing.T) {
+	for header, want := range map[string]bool{
+		"":                     false,
+		"gzip":                 true,
+		"deflate, gzip":        true,
+		"gzip;q=0.5, deflate":  true,
+		"gzip; q=0":            false,
+		"deflate, br":          false,
+		"x-gzip":               false,
+		"identity, gzip;q=1.0": true,
+	} {
+		req, err := http.NewRequest(http.MethodGet, "/1.csv", nil)
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set("Accept-Encoding", header)
+		}
+		require.Equal(t, want, acceptsGzip(req), header)
+	}
+}
+
 func createUser(store *basestore.Store, username string) (int32, error) {
 	admin := username == "admin"
 	q := sqlf.Sprintf(`INSERT INTO users(username, site_admin) VALUES(%s, %s) RETURNING id`, username, admin)
@@ -0,0 +1,111 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+// ServeSearchJobTasks serves the task introspection endpoints of search jobs,
+// which let site admins inspect and unblock the queue of a job without raw
+// SQL:
+//
+//	GET  /search/jobs/{id}/tasks?state=failed&after=0&first=100
+//	GET  /search/jobs/tasks/{taskID}
+//	POST /search/jobs/tasks/{taskID}/fail?reason=...
+//	POST /search/jobs/tasks/{taskID}/requeue
+//
+// The service checks that the actor is a site admin and audits the changes.
+func ServeSearchJobTasks(logger log.Logger, svc *service.Service) http.HandlerFunc {
+	logger = logger.With(log.String("handler", "ServeSearchJobTasks"))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		ctx := r.Context()
+
+		if jobIDStr, ok := vars["id"]; ok {
+			jobID, err := strconv.ParseInt(jobIDStr, 10, 64)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			args, err := parseListTasksArgs(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			tasks, err := svc.ListTasks(ctx, jobID, args)
+			if err != nil {
+				httpError(w, err)
+				return
+			}
+			if tasks == nil {
+				tasks = []types.SearchJobTask{}
+			}
+			writeTasksJSON(logger, w, tasks)
+			return
+		}
+
+		taskID, err := strconv.ParseInt(vars["taskID"], 10, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch vars["action"] {
+		case "fail":
+			err = svc.ForceFailTask(ctx, taskID, r.FormValue("reason"))
+		case "requeue":
+			err = svc.ForceRequeueTask(ctx, taskID)
+		case "":
+			// We only read the task below.
+		default:
+			http.Error(w, "unknown action", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+
+		// Actions return the task after the change, so that support sees its
+		// new state right away.
+		task, err := svc.GetTask(ctx, taskID)
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		writeTasksJSON(logger, w, task)
+	}
+}
+
+func parseListTasksArgs(r *http.Request) (args service.ListTasksArgs, err error) {
+	q := r.URL.Query()
+	args.State = types.JobState(q.Get("state"))
+	if after := q.Get("after"); after != "" {
+		if args.After, err = strconv.ParseInt(after, 10, 64); err != nil {
+			return args, err
+		}
+	}
+	if first := q.Get("first"); first != "" {
+		if args.First, err = strconv.Atoi(first); err != nil {
+			return args, err
+		}
+	}
+	return args, nil
+}
+
+func writeTasksJSON(logger log.Logger, w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warn("failed while writing search job tasks response", log.Error(err))
+	}
+}
@@ -44,8 +44,12 @@ func Init(
 	svc := service.New(observationCtx, store, uploadStore, newSearcher)
 
 	enterpriseServices.SearchJobsResolver = resolvers.New(logger, db, svc)
-	enterpriseServices.SearchJobsDataExportHandler = httpapi.ServeSearchJobDownload(logger, svc)
+	enterpriseServices.SearchJobsDataExportHandler = httpapi.ServeSearchJobDownload(logger, svc, service.ResultFormatJSONL)
+	enterpriseServices.SearchJobsCSVExportHandler = httpapi.ServeSearchJobDownload(logger, svc, service.ResultFormatCSV)
+	enterpriseServices.SearchJobsParquetExportHandler = httpapi.ServeSearchJobDownload(logger, svc, service.ResultFormatParquet)
 	enterpriseServices.SearchJobsLogsHandler = httpapi.ServeSearchJobLogs(logger, svc)
+	enterpriseServices.SearchJobsSummaryHandler = httpapi.ServeSearchJobSummary(logger, svc)
+	enterpriseServices.SearchJobsTasksHandler = httpapi.ServeSearchJobTasks(logger, svc)
 
 	return nil
 }
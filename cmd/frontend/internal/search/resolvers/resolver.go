@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 // Resolver is the GraphQL resolver of all things related to search jobs.
@@ -40,12 +41,54 @@ func New(logger log.Logger, db database.DB, svc *service.Service) graphqlbackend
 var _ graphqlbackend.SearchJobsResolver = &Resolver{}
 
 func (r *Resolver) CreateSearchJob(ctx context.Context, args *graphqlbackend.CreateSearchJobArgs) (graphqlbackend.SearchJobResolver, error) {
-	job, err := r.svc.CreateSearchJob(ctx, args.Query)
+	opts := service.CreateSearchJobOpts{
+		WebhookURL:    pointers.Deref(args.WebhookURL, ""),
+		WebhookSecret: pointers.Deref(args.WebhookSecret, ""),
+
+		DeduplicateResults: pointers.Deref(args.DeduplicateResults, false),
+		Description:        pointers.Deref(args.Description, ""),
+
+		IdempotencyKey: pointers.Deref(args.IdempotencyKey, ""),
+	}
+	if args.Labels != nil {
+		labels, err := labelsFromGraphQL(*args.Labels)
+		if err != nil {
+			return nil, err
+		}
+		opts.Labels = labels
+	}
+	if args.MaxResults != nil {
+		opts.MaxResults = int(*args.MaxResults)
+	}
+	if args.MaxDurationSeconds != nil {
+		opts.MaxDuration = time.Duration(*args.MaxDurationSeconds) * time.Second
+	}
+	if args.SampleRate != nil {
+		// 0 would search every revision, which isn't a sample.
+		if *args.SampleRate <= 0 {
+			return nil, errors.New("the sample rate of a search job must be greater than 0 and at most 1")
+		}
+		opts.SampleRate = *args.SampleRate
+	}
+	if args.SampleSeed != nil {
+		opts.SampleSeed = int64(*args.SampleSeed)
+	}
+	if args.Priority != nil {
+		priority, err := priorityFromGraphQL(*args.Priority)
+		if err != nil {
+			return nil, err
+		}
+		opts.Priority = priority
+	}
+
+	job, err := r.svc.CreateSearchJob(ctx, args.Query, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return newSearchJobResolver(r.db, r.svc, job), nil
+	resolver := newSearchJobResolver(r.db, r.svc, job)
+	resolver.pinPrimary = true
+	return resolver, nil
 }
 
 func (r *Resolver) CancelSearchJob(ctx context.Context, args *graphqlbackend.CancelSearchJobArgs) (*graphqlbackend.EmptyResponse, error) {
@@ -66,6 +109,39 @@ func (r *Resolver) DeleteSearchJob(ctx context.Context, args *graphqlbackend.Del
 	return &graphqlbackend.EmptyResponse{}, r.svc.DeleteSearchJob(ctx, jobID)
 }
 
+func (r *Resolver) UpdateSearchJobMetadata(ctx context.Context, args *graphqlbackend.UpdateSearchJobMetadataArgs) (graphqlbackend.SearchJobResolver, error) {
+	jobID, err := UnmarshalSearchJobID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := labelsFromGraphQL(args.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := r.svc.UpdateSearchJobMetadata(ctx, jobID, args.Description, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := newSearchJobResolver(r.db, r.svc, job)
+	resolver.pinPrimary = true
+	return resolver, nil
+}
+
+// labelsFromGraphQL returns the labels of a search job given as GraphQL
+// input. Each key may only be given once.
+func labelsFromGraphQL(inputs []graphqlbackend.SearchJobLabelInput) (map[string]string, error) {
+	labels := make(map[string]string, len(inputs))
+	for _, label := range inputs {
+		if _, ok := labels[label.Key]; ok {
+			return nil, errors.Newf("duplicate search job label %q", label.Key)
+		}
+		labels[label.Key] = label.Value
+	}
+	return labels, nil
+}
+
 func newSearchJobConnectionResolver(ctx context.Context, db database.DB, service *service.Service, args *graphqlbackend.SearchJobsArgs) (*graphqlutil.ConnectionResolver[graphqlbackend.SearchJobResolver], error) {
 	var states []string
 	if args.States != nil {
@@ -88,6 +164,15 @@ func newSearchJobConnectionResolver(ctx context.Context, db database.DB, service
 		query = *args.Query
 	}
 
+	var labels map[string]string
+	if args.Labels != nil {
+		var err error
+		labels, err = labelsFromGraphQL(*args.Labels)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	s := &searchJobsConnectionStore{
 		ctx:     ctx,
 		db:      db,
@@ -95,13 +180,16 @@ func newSearchJobConnectionResolver(ctx context.Context, db database.DB, service
 		states:  states,
 		query:   query,
 		userIDs: ids,
+		labels:  labels,
 	}
 	return graphqlutil.NewConnectionResolver[graphqlbackend.SearchJobResolver](
 		s,
 		&args.ConnectionResolverArgs,
 		&graphqlutil.ConnectionResolverOptions{
-			Ascending: !args.Descending,
-			OrderBy:   database.OrderBy{{Field: normalize(args.OrderBy)}, {Field: "id"}}},
+			Ascending:   !args.Descending,
+			OrderBy:     database.OrderBy{{Field: normalize(args.OrderBy)}, {Field: "id"}},
+			MaxPageSize: service.MaxSearchJobsPageSize,
+		},
 	)
 }
 
@@ -121,21 +209,20 @@ type searchJobsConnectionStore struct {
 	states  []string
 	query   string
 	userIDs []int32
+	labels  map[string]string
 }
 
 func (s *searchJobsConnectionStore) ComputeTotal(ctx context.Context) (int32, error) {
-	// TODO (stefan) add "Count" method to service
-	jobs, err := s.service.ListSearchJobs(ctx, store.ListArgs{States: s.states, Query: s.query, UserIDs: s.userIDs})
+	count, err := s.service.CountSearchJobs(ctx, store.ListArgs{States: s.states, Query: s.query, UserIDs: s.userIDs, Labels: s.labels})
 	if err != nil {
 		return 0, err
 	}
 
-	count := int32(len(jobs))
 	return int32(count), nil
 }
 
 func (s *searchJobsConnectionStore) ComputeNodes(ctx context.Context, args *database.PaginationArgs) ([]graphqlbackend.SearchJobResolver, error) {
-	jobs, err := s.service.ListSearchJobs(ctx, store.ListArgs{PaginationArgs: args, States: s.states, Query: s.query, UserIDs: s.userIDs})
+	jobs, err := s.service.ListSearchJobs(ctx, store.ListArgs{PaginationArgs: args, States: s.states, Query: s.query, UserIDs: s.userIDs, Labels: s.labels})
 	if err != nil {
 		return nil, err
 	}
@@ -161,12 +248,14 @@ func (s *searchJobsConnectionStore) MarshalCursor(node graphqlbackend.SearchJobR
 	switch column {
 	case "created_at":
 		value = node.CreatedAt().Format(time.RFC3339Nano)
+	case "updated_at":
+		value = node.UpdatedAt().Format(time.RFC3339Nano)
 	case "agg_state":
 		value = strings.ToLower(node.State(s.ctx))
 	case "query":
 		value = node.Query()
 	default:
-		return nil, errors.New(fmt.Sprintf("invalid OrderBy.Field. Expected one of (created_at, agg_state, query). Actual: %s", column))
+		return nil, errors.New(fmt.Sprintf("invalid OrderBy.Field. Expected one of (created_at, updated_at, agg_state, query). Actual: %s", column))
 	}
 
 	id, err := UnmarshalSearchJobID(node.ID())
@@ -212,13 +301,17 @@ func (s *searchJobsConnectionStore) UnmarshalCursor(cursor string, orderBy datab
 	}
 
 	switch column {
-	case "created_at", "agg_state", "query":
+	case "created_at", "updated_at", "agg_state", "query":
 		return []any{values[0], id}, nil
 	default:
 		return nil, errors.New("Invalid OrderBy Field.")
 	}
 }
 
+func (r *Resolver) SearchJob(ctx context.Context, args *graphqlbackend.SearchJobArgs) (graphqlbackend.SearchJobResolver, error) {
+	return r.searchJobByID(ctx, args.ID)
+}
+
 func (r *Resolver) SearchJobs(ctx context.Context, args *graphqlbackend.SearchJobsArgs) (*graphqlutil.ConnectionResolver[graphqlbackend.SearchJobResolver], error) {
 	return newSearchJobConnectionResolver(ctx, r.db, r.svc, args)
 }
@@ -0,0 +1,322 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestUnmarshalSearchJobID(t *testing.T) {
+	id, err := UnmarshalSearchJobID(relay.MarshalID(searchJobIDKind, int64(42)))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), id)
+
+	// IDs of other kinds of nodes are rejected, even if their spec is a
+	// valid search job ID.
+	_, err = UnmarshalSearchJobID(relay.MarshalID("User", int64(42)))
+	require.Error(t, err)
+
+	_, err = UnmarshalSearchJobID("not an ID")
+	require.Error(t, err)
+}
+
+func TestSearchJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	enabled := true
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{SearchJobs: &enabled},
+			// The subtests create more jobs than a user may run by default.
+			SearchLimits: &schema.SearchLimits{MaxActiveSearchJobsPerUser: -1},
+		}})
+	defer conf.Mock(nil)
+
+	logger := logtest.Scoped(t)
+	observationCtx := observation.TestContextTB(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	uploadStore := mocks.NewMockStore()
+	uploadStore.ListFunc.SetDefaultHook(func(context.Context, string) (*iterator.Iterator[string], error) {
+		return iterator.New(func() ([]string, error) { return nil, nil }), nil
+	})
+	svc := service.New(observationCtx, store.New(db, observationCtx), uploadStore, service.NewSearcherFake())
+
+	gqlSchema, err := graphqlbackend.NewSchemaWithSearchJobsResolver(db, New(logger, db, svc))
+	require.NoError(t, err)
+
+	aliceCtx := actor.WithActor(context.Background(), actor.FromUser(createUser(t, db, "alice")))
+	malloryCtx := actor.WithActor(context.Background(), actor.FromUser(createUser(t, db, "mallory")))
+
+	var ids []graphql.ID
+	for _, query := range []string{"1@rev1", "1@rev2", "2@rev3"} {
+		var res struct {
+			CreateSearchJob struct {
+				ID    graphql.ID
+				Query string
+				State string
+			}
+		}
+		mustExec(t, aliceCtx, gqlSchema, `
+mutation($query: String!) {
+	createSearchJob(query: $query) { id query state }
+}`, map[string]any{"query": query}, &res)
+		require.Equal(t, query, res.CreateSearchJob.Query)
+		require.Equal(t, "QUEUED", res.CreateSearchJob.State)
+		ids = append(ids, res.CreateSearchJob.ID)
+	}
+
+	const getSearchJob = `
+query($id: ID!) {
+	searchJob(id: $id) { id query }
+}`
+
+	t.Run("get", func(t *testing.T) {
+		var res struct {
+			SearchJob struct {
+				ID    graphql.ID
+				Query string
+			}
+		}
+		mustExec(t, aliceCtx, gqlSchema, getSearchJob, map[string]any{"id": ids[1]}, &res)
+		require.Equal(t, ids[1], res.SearchJob.ID)
+		require.Equal(t, "1@rev2", res.SearchJob.Query)
+	})
+
+	t.Run("paginate", func(t *testing.T) {
+		const listSearchJobs = `
+query($after: String) {
+	searchJobs(first: 2, after: $after) {
+		totalCount
+		nodes { id }
+		pageInfo { hasNextPage endCursor }
+	}
+}`
+		type page struct {
+			SearchJobs struct {
+				TotalCount int
+				Nodes      []struct{ ID graphql.ID }
+				PageInfo   struct {
+					HasNextPage bool
+					EndCursor   *string
+				}
+			}
+		}
+
+		var got []graphql.ID
+		var after *string
+		for range ids {
+			var res page
+			mustExec(t, aliceCtx, gqlSchema, listSearchJobs, map[string]any{"after": after}, &res)
+			require.Equal(t, len(ids), res.SearchJobs.TotalCount)
+			for _, node := range res.SearchJobs.Nodes {
+				got = append(got, node.ID)
+			}
+			if !res.SearchJobs.PageInfo.HasNextPage {
+				break
+			}
+			after = res.SearchJobs.PageInfo.EndCursor
+		}
+		require.Equal(t, ids, got)
+
+		// Other users don't see the jobs of alice.
+		var res page
+		mustExec(t, malloryCtx, gqlSchema, listSearchJobs, map[string]any{"after": nil}, &res)
+		require.Zero(t, res.SearchJobs.TotalCount)
+		require.Empty(t, res.SearchJobs.Nodes)
+	})
+
+	t.Run("sample", func(t *testing.T) {
+		const createSampledSearchJob = `
+mutation($rate: Float!, $seed: BigInt) {
+	createSearchJob(query: "1@rev1", sampleRate: $rate, sampleSeed: $seed) {
+		sample { rate seed sampledTaskCount totalTaskCount }
+	}
+}`
+		var res struct {
+			CreateSearchJob struct {
+				Sample *struct {
+					Rate             float64
+					Seed             string
+					SampledTaskCount int
+					TotalTaskCount   int
+				}
+			}
+		}
+		mustExec(t, aliceCtx, gqlSchema, createSampledSearchJob, map[string]any{"rate": 0.25, "seed": "42"}, &res)
+		require.NotNil(t, res.CreateSearchJob.Sample)
+		require.Equal(t, 0.25, res.CreateSearchJob.Sample.Rate)
+		require.Equal(t, "42", res.CreateSearchJob.Sample.Seed)
+		require.Zero(t, res.CreateSearchJob.Sample.TotalTaskCount)
+
+		// A rate of 1 searches every revision, so it's no sample.
+		mustExec(t, aliceCtx, gqlSchema, createSampledSearchJob, map[string]any{"rate": 1.0}, &res)
+		require.Nil(t, res.CreateSearchJob.Sample)
+
+		result := gqlSchema.Exec(aliceCtx, createSampledSearchJob, "", map[string]any{"rate": 0.0})
+		requireErrorMessage(t, result, "the sample rate of a search job must be greater than 0 and at most 1")
+	})
+
+	t.Run("deduplicate results", func(t *testing.T) {
+		var res struct {
+			CreateSearchJob struct{ DeduplicateResults bool }
+		}
+		mustExec(t, aliceCtx, gqlSchema, `
+mutation {
+	createSearchJob(query: "1@rev1", deduplicateResults: true) { deduplicateResults }
+}`, nil, &res)
+		require.True(t, res.CreateSearchJob.DeduplicateResults)
+
+		// Jobs don't deduplicate their results by default.
+		var get struct {
+			SearchJob struct{ DeduplicateResults bool }
+		}
+		mustExec(t, aliceCtx, gqlSchema, `query($id: ID!) { searchJob(id: $id) { deduplicateResults } }`, map[string]any{"id": ids[0]}, &get)
+		require.False(t, get.SearchJob.DeduplicateResults)
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		type label struct{ Key, Value string }
+		type metadata struct {
+			ID          graphql.ID
+			Description string
+			Labels      []label
+		}
+
+		var created struct{ CreateSearchJob metadata }
+		mustExec(t, aliceCtx, gqlSchema, `
+mutation {
+	createSearchJob(query: "1@rev1", description: "audit", labels: [{key: "team", value: "search"}, {key: "env", value: "prod"}]) {
+		id description labels { key value }
+	}
+}`, nil, &created)
+		require.Equal(t, "audit", created.CreateSearchJob.Description)
+		require.Equal(t, []label{{"env", "prod"}, {"team", "search"}}, created.CreateSearchJob.Labels)
+		id := created.CreateSearchJob.ID
+
+		const updateMetadata = `
+mutation($id: ID!, $labels: [SearchJobLabelInput!]!) {
+	updateSearchJobMetadata(id: $id, description: "weekly audit", labels: $labels) {
+		id description labels { key value }
+	}
+}`
+		var updated struct{ UpdateSearchJobMetadata metadata }
+		mustExec(t, aliceCtx, gqlSchema, updateMetadata, map[string]any{"id": id, "labels": []any{map[string]any{"key": "team", "value": "code-search"}}}, &updated)
+		require.Equal(t, "weekly audit", updated.UpdateSearchJobMetadata.Description)
+		require.Equal(t, []label{{"team", "code-search"}}, updated.UpdateSearchJobMetadata.Labels)
+
+		var list struct {
+			SearchJobs struct {
+				Nodes []struct{ ID graphql.ID }
+			}
+		}
+		mustExec(t, aliceCtx, gqlSchema, `
+query {
+	searchJobs(first: 10, labels: [{key: "team", value: "code-search"}]) { nodes { id } }
+}`, nil, &list)
+		require.Len(t, list.SearchJobs.Nodes, 1)
+		require.Equal(t, id, list.SearchJobs.Nodes[0].ID)
+
+		result := gqlSchema.Exec(malloryCtx, updateMetadata, "", map[string]any{"id": id, "labels": []any{}})
+		requireErrorMessage(t, result, "must be authenticated as user with id 1")
+
+		result = gqlSchema.Exec(aliceCtx, updateMetadata, "", map[string]any{"id": id, "labels": []any{
+			map[string]any{"key": "team", "value": "a"},
+			map[string]any{"key": "team", "value": "b"},
+		}})
+		requireErrorMessage(t, result, `duplicate search job label "team"`)
+
+		result = gqlSchema.Exec(aliceCtx, updateMetadata, "", map[string]any{"id": id, "labels": []any{
+			map[string]any{"key": "", "value": "a"},
+		}})
+		requireErrorMessage(t, result, "the labels of a search job must have a key")
+	})
+
+	t.Run("idempotency key", func(t *testing.T) {
+		const createSearchJob = `
+mutation($query: String!) {
+	createSearchJob(query: $query, idempotencyKey: "retry") { id }
+}`
+		var first, retried struct{ CreateSearchJob struct{ ID graphql.ID } }
+		mustExec(t, aliceCtx, gqlSchema, createSearchJob, map[string]any{"query": "1@rev1"}, &first)
+		mustExec(t, aliceCtx, gqlSchema, createSearchJob, map[string]any{"query": "1@rev1"}, &retried)
+		require.Equal(t, first.CreateSearchJob.ID, retried.CreateSearchJob.ID)
+
+		result := gqlSchema.Exec(aliceCtx, createSearchJob, "", map[string]any{"query": "1@rev2"})
+		requireErrorMessage(t, result, service.ErrIdempotencyKeyConflict.Error())
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		vars := map[string]any{"id": ids[0]}
+
+		result := gqlSchema.Exec(malloryCtx, getSearchJob, "", vars)
+		requireErrorMessage(t, result, auth.ErrMustBeSiteAdminOrSameUser.Error())
+
+		result = gqlSchema.Exec(malloryCtx, `mutation($id: ID!) { cancelSearchJob(id: $id) { alwaysNil } }`, "", vars)
+		requireErrorMessage(t, result, auth.ErrMustBeSiteAdminOrSameUser.Error())
+
+		result = gqlSchema.Exec(malloryCtx, `mutation($id: ID!) { deleteSearchJob(id: $id) { alwaysNil } }`, "", vars)
+		requireErrorMessage(t, result, auth.ErrMustBeSiteAdminOrSameUser.Error())
+
+		// The job is untouched.
+		var res struct{ SearchJob struct{ State string } }
+		mustExec(t, aliceCtx, gqlSchema, `query($id: ID!) { searchJob(id: $id) { state } }`, vars, &res)
+		require.Equal(t, "QUEUED", res.SearchJob.State)
+	})
+
+	t.Run("cancel and delete", func(t *testing.T) {
+		vars := map[string]any{"id": ids[2]}
+		mustExec(t, aliceCtx, gqlSchema, `mutation($id: ID!) { cancelSearchJob(id: $id) { alwaysNil } }`, vars, nil)
+		mustExec(t, aliceCtx, gqlSchema, `mutation($id: ID!) { deleteSearchJob(id: $id) { alwaysNil } }`, vars, nil)
+
+		result := gqlSchema.Exec(aliceCtx, getSearchJob, "", vars)
+		require.NotEmpty(t, result.Errors)
+	})
+}
+
+func createUser(t *testing.T, db database.DB, username string) int32 {
+	t.Helper()
+
+	var id int32
+	err := db.QueryRowContext(context.Background(), "INSERT INTO users (username) VALUES ($1) RETURNING id", username).Scan(&id)
+	require.NoError(t, err)
+	return id
+}
+
+// mustExec runs query against gqlSchema and decodes its result into res,
+// unless res is nil.
+func mustExec(t *testing.T, ctx context.Context, gqlSchema *graphql.Schema, query string, vars map[string]any, res any) {
+	t.Helper()
+
+	result := gqlSchema.Exec(ctx, query, "", vars)
+	require.Empty(t, result.Errors)
+	if res != nil {
+		require.NoError(t, json.Unmarshal(result.Data, res))
+	}
+}
+
+func requireErrorMessage(t *testing.T, result *graphql.Response, message string) {
+	t.Helper()
+
+	require.Len(t, result.Errors, 1)
+	require.Equal(t, message, result.Errors[0].Message)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"slices"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"golang.org/x/exp/maps"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -14,13 +16,18 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 const searchJobIDKind = "SearchJob"
 
 func UnmarshalSearchJobID(id graphql.ID) (int64, error) {
+	if kind := relay.UnmarshalKind(id); kind != searchJobIDKind {
+		return 0, errors.Newf("expected a %q ID, got %q", searchJobIDKind, kind)
+	}
 	var v int64
 	err := relay.UnmarshalSpec(id, &v)
 	return v, err
@@ -37,6 +44,10 @@ type searchJobResolver struct {
 	Job *types.ExhaustiveSearchJob
 	db  database.DB
 	svc *service.Service
+
+	// pinPrimary is set for jobs returned by mutations, whose fields must be
+	// read from the primary since a replica may not have the writes yet.
+	pinPrimary bool
 }
 
 func (r *searchJobResolver) ID() graphql.ID {
@@ -67,6 +78,88 @@ func (r *searchJobResolver) CreatedAt() gqlutil.DateTime {
 	return *gqlutil.FromTime(r.Job.CreatedAt)
 }
 
+func (r *searchJobResolver) UpdatedAt() gqlutil.DateTime {
+	return *gqlutil.FromTime(r.Job.UpdatedAt)
+}
+
+func (r *searchJobResolver) Truncated() bool {
+	return r.Job.Truncated
+}
+
+func (r *searchJobResolver) Priority() string {
+	return r.Job.Priority.ToGraphQL()
+}
+
+func (r *searchJobResolver) Deadline() *gqlutil.DateTime {
+	return gqlutil.FromTime(r.Job.Deadline)
+}
+
+func (r *searchJobResolver) DeadlineExceeded() bool {
+	return r.Job.DeadlineExceeded
+}
+
+func (r *searchJobResolver) DeduplicateResults() bool {
+	return r.Job.DeduplicateResults
+}
+
+func (r *searchJobResolver) Description() string {
+	return r.Job.Description
+}
+
+func (r *searchJobResolver) Labels() []graphqlbackend.SearchJobLabelResolver {
+	keys := maps.Keys(r.Job.Labels)
+	slices.Sort(keys)
+
+	labels := make([]graphqlbackend.SearchJobLabelResolver, 0, len(keys))
+	for _, key := range keys {
+		labels = append(labels, searchJobLabelResolver{key: key, value: r.Job.Labels[key]})
+	}
+	return labels
+}
+
+type searchJobLabelResolver struct {
+	key, value string
+}
+
+func (r searchJobLabelResolver) Key() string   { return r.key }
+func (r searchJobLabelResolver) Value() string { return r.value }
+
+func (r *searchJobResolver) Sample() graphqlbackend.SearchJobSampleResolver {
+	sample := r.Job.Sample()
+	if sample == nil {
+		return nil
+	}
+	return searchJobSampleResolver{sample}
+}
+
+type searchJobSampleResolver struct {
+	sample *types.ResultsSample
+}
+
+func (r searchJobSampleResolver) Rate() float64 {
+	return r.sample.Rate
+}
+
+func (r searchJobSampleResolver) Seed() graphqlbackend.BigInt {
+	return graphqlbackend.BigInt(r.sample.Seed)
+}
+
+func (r searchJobSampleResolver) SampledTaskCount() int32 {
+	return int32(r.sample.SampledTasks)
+}
+
+func (r searchJobSampleResolver) TotalTaskCount() int32 {
+	return int32(r.sample.TotalTasks)
+}
+
+func priorityFromGraphQL(s string) (types.JobPriority, error) {
+	priority, ok := types.JobPriorityFromGraphQL(s)
+	if !ok {
+		return 0, errors.Errorf("unknown search job priority %q", s)
+	}
+	return priority, nil
+}
+
 func (r *searchJobResolver) StartedAt(ctx context.Context) *gqlutil.DateTime {
 	return gqlutil.FromTime(r.Job.StartedAt)
 }
@@ -98,6 +191,9 @@ func (r *searchJobResolver) LogURL(ctx context.Context) (*string, error) {
 }
 
 func (r *searchJobResolver) RepoStats(ctx context.Context) (graphqlbackend.SearchJobStatsResolver, error) {
+	if r.pinPrimary {
+		ctx = store.PinPrimary(ctx)
+	}
 	repoRevStats, err := r.svc.GetAggregateRepoRevState(ctx, r.Job.ID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,358 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/audit/audittest"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbfixture"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestExhaustiveSearch_AdminAccess(t *testing.T) {
+	require := require.New(t)
+	logger, exportLogs := logtest.Captured(t)
+	env := newTestEnv(t, withLogger(logger))
+
+	bobID := dbfixture.User(t, env.db, dbfixture.WithUsername("bob")).ID
+	adminID := dbfixture.User(t, env.db, dbfixture.WithUsername("admin"), dbfixture.WithSiteAdmin()).ID
+
+	bobCtx := actor.WithActor(context.Background(), actor.FromUser(bobID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+
+	aliceJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	_, err = env.svc.CreateSearchJob(bobCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// Site admins can list the jobs of all users.
+	jobs, err := env.svc.ListSearchJobs(adminCtx, store.ListArgs{AllUsers: true})
+	require.NoError(err)
+	var usernames []string
+	for _, job := range jobs {
+		usernames = append(usernames, job.InitiatorUsername)
+	}
+	sort.Strings(usernames)
+	require.Equal([]string{"alice", "bob"}, usernames)
+
+	// Other users can't.
+	_, err = env.svc.ListSearchJobs(bobCtx, store.ListArgs{AllUsers: true})
+	require.ErrorIs(err, auth.ErrMustBeSiteAdmin)
+
+	// Accessing your own job isn't audited.
+	_, err = env.svc.GetSearchJob(env.userCtx, aliceJob.ID)
+	require.NoError(err)
+
+	// Accessing the job of another user as site admin is audited.
+	_, err = env.svc.GetSearchJob(adminCtx, aliceJob.ID)
+	require.NoError(err)
+	err = env.svc.CancelSearchJob(adminCtx, aliceJob.ID)
+	require.NoError(err)
+	err = env.svc.DeleteSearchJob(adminCtx, aliceJob.ID)
+	require.NoError(err)
+
+	var actions []string
+	for _, entry := range exportLogs() {
+		if fields, ok := audittest.ExtractAuditFields(entry); ok {
+			require.Equal("search job", fields.Entity)
+			actions = append(actions, fmt.Sprintf("%s:%v", fields.Action, entry.Fields["adminAccess"]))
+		}
+	}
+	require.Equal([]string{"created:false", "created:false", "viewed:true", "canceled:true", "deleted:true"}, actions)
+}
+
+func TestExhaustiveSearch_AuditLog(t *testing.T) {
+	require := require.New(t)
+	logger, exportLogs := logtest.Captured(t)
+	env := newTestEnv(t, withLogger(logger))
+
+	adminID := dbfixture.User(t, env.db, dbfixture.WithUsername("admin"), dbfixture.WithSiteAdmin()).ID
+
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	_, err = env.svc.GetSearchJobResultsWriterTo(env.userCtx, job.ID, service.ResultFormatJSONL)
+	require.NoError(err)
+	err = env.svc.CancelSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+	// Canceling a canceled job changes nothing, so it isn't audited.
+	err = env.svc.CancelSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+	err = env.svc.DeleteSearchJob(adminCtx, job.ID)
+	require.NoError(err)
+
+	// The query can be left out of the audit log.
+	enabled := true
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExperimentalFeatures: &schema.ExperimentalFeatures{SearchJobs: &enabled},
+		Log:                  &schema.Log{AuditLog: &schema.AuditLog{RedactSearchJobQueries: true}},
+	}})
+	redactedJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev2", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	type auditEntry struct {
+		action      string
+		actorUID    any
+		id          any
+		initiatorID any
+		query       any
+		adminAccess any
+	}
+	var got []auditEntry
+	for _, entry := range exportLogs() {
+		fields, ok := audittest.ExtractAuditFields(entry)
+		if !ok {
+			continue
+		}
+		require.Equal("search job", fields.Entity)
+		actorFields := entry.Fields["audit"].(map[string]any)["actor"].(map[string]any)
+		got = append(got, auditEntry{
+			action:      fields.Action,
+			actorUID:    actorFields["actorUID"],
+			id:          fmt.Sprint(entry.Fields["id"]),
+			initiatorID: fmt.Sprint(entry.Fields["initiatorID"]),
+			query:       entry.Fields["query"],
+			adminAccess: entry.Fields["adminAccess"],
+		})
+	}
+
+	alice, admin := fmt.Sprint(env.userID), fmt.Sprint(adminID)
+	id, redactedID := fmt.Sprint(job.ID), fmt.Sprint(redactedJob.ID)
+	require.Equal([]auditEntry{
+		{action: "created", actorUID: alice, id: id, initiatorID: alice, query: "1@rev1", adminAccess: false},
+		{action: "downloaded", actorUID: alice, id: id, initiatorID: alice, query: "1@rev1", adminAccess: false},
+		{action: "canceled", actorUID: alice, id: id, initiatorID: alice, query: "1@rev1", adminAccess: false},
+		{action: "deleted", actorUID: admin, id: id, initiatorID: alice, query: "1@rev1", adminAccess: true},
+		{action: "created", actorUID: alice, id: redactedID, initiatorID: alice, query: "REDACTED", adminAccess: false},
+	}, got)
+}
+
+// TestNoDirectTimeNow checks that the workers read the time from the clock of
+// their config, so that the tests which replace the clock control it.
+func TestExhaustiveSearch_DownloadPermissionCheck(t *testing.T) {
+	// Enforce repository permissions.
+	authz.SetProviders(false, nil)
+	defer authz.SetProviders(true, nil)
+
+	require := require.New(t)
+	logger, exportLogs := logtest.Captured(t)
+	env := newTestEnv(t, withLogger(logger))
+	mockConf := func(mode string) {
+		enabled := true
+		conf.Mock(&conf.Unified{
+			SiteConfiguration: schema.SiteConfiguration{
+				ExperimentalFeatures:              &schema.ExperimentalFeatures{SearchJobs: &enabled},
+				SearchJobsDownloadPermissionCheck: mode,
+			}})
+	}
+
+	alice, err := env.db.Users().GetByID(env.userCtx, env.userID)
+	require.NoError(err)
+	adminID := dbfixture.User(t, env.db, dbfixture.WithUsername("admin"), dbfixture.WithSiteAdmin()).ID
+	dbfixture.Repo(t, env.db, dbfixture.WithRepoID(1), dbfixture.WithRepoName("repoa"), dbfixture.WithRepoOwner(alice))
+	dbfixture.Repo(t, env.db, dbfixture.WithRepoID(2), dbfixture.WithRepoName("repob"), dbfixture.WithRepoOwner(alice))
+
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 2@rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// Alice loses access to repob after the job ran.
+	_, err = env.db.Perms().SetRepoPerms(env.workerCtx, 2, nil, authz.SourceAPI)
+	require.NoError(err)
+
+	download := func(ctx context.Context) ([][]string, error) {
+		writerTo, err := env.svc.GetSearchJobResultsWriterTo(ctx, job.ID, service.ResultFormatCSV)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		_, err = writerTo.WriteTo(&buf)
+		require.NoError(err)
+		got := parseCSV(t, buf.String())
+		require.Equal(types.ResultsColumns, got[0])
+		got = got[1:]
+		sort.Slice(got, func(i, j int) bool {
+			return strings.Join(got[i], ",") < strings.Join(got[j], ",")
+		})
+		return got, nil
+	}
+
+	rowA := []string{"repoa", "1", "rev1", "rev1", "path/to/file.go", "", ""}
+	rowB := []string{"repob", "2", "rev1", "rev1", "path/to/file.go", "", ""}
+
+	// The check is off by default.
+	got, err := download(env.userCtx)
+	require.NoError(err)
+	require.Equal([][]string{rowA, rowB}, got)
+
+	// "filter" leaves out the results of repob.
+	mockConf("filter")
+	got, err = download(env.userCtx)
+	require.NoError(err)
+	require.Equal([][]string{rowA}, got)
+
+	// "refuse" fails the download.
+	mockConf("refuse")
+	_, err = download(env.userCtx)
+	var inaccessibleErr *service.InaccessibleReposError
+	require.ErrorAs(err, &inaccessibleErr)
+	require.Equal(1, inaccessibleErr.Count)
+
+	// Site admins bypass the check, which is audited.
+	got, err = download(adminCtx)
+	require.NoError(err)
+	require.Equal([][]string{rowA, rowB}, got)
+
+	var actions []string
+	for _, entry := range exportLogs() {
+		if fields, ok := audittest.ExtractAuditFields(entry); ok && fields.Action == "downloadPermissionCheckBypassed" {
+			actions = append(actions, fmt.Sprintf("%s:%v", fields.Action, entry.Fields["adminAccess"]))
+		}
+	}
+	require.Equal([]string{"downloadPermissionCheckBypassed:true"}, actions)
+}
+
+func TestExhaustiveSearch_ResultsURL(t *testing.T) {
+	require := require.New(t)
+	logger, exportLogs := logtest.Captured(t)
+	env := newTestEnv(t,
+		withSiteConfig(schema.SiteConfiguration{ExternalURL: "https://sourcegraph.example.com"}),
+		withLogger(logger),
+		withRepos("repoa", "repob"))
+
+	// presigningSvc uses the same blobs through a store which can presign URLs.
+	presigningStore := &presigningUploadStore{MockStore: env.uploadStore}
+	presigningSvc := service.New(env.observationCtx, env.store, presigningStore, service.NewSearcherFake())
+
+	malloryID := dbfixture.User(t, env.db, dbfixture.WithUsername("mallory")).ID
+
+	malloryCtx := actor.WithActor(context.Background(), actor.FromUser(malloryID))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 2@rev2", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	exportURL := fmt.Sprintf("https://sourcegraph.example.com/.api/search/export/%d.jsonl", job.ID)
+
+	// The results of a running job are streamed, even if the store can presign.
+	resultsURL, err := presigningSvc.GetSearchJobResultsURL(env.userCtx, job.ID, service.ResultFormatJSONL, 0)
+	require.NoError(err)
+	require.Equal(&service.SearchJobResultsURL{URL: exportURL}, resultsURL)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		notifier: &fakeNotifier{},
+		config:   testConfig(5),
+	}
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
+	}
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// The store can't presign, so we fall back to the export API.
+	resultsURL, err = env.svc.GetSearchJobResultsURL(env.userCtx, job.ID, service.ResultFormatJSONL, 0)
+	require.NoError(err)
+	require.Equal(&service.SearchJobResultsURL{URL: exportURL}, resultsURL)
+
+	// The store can presign, so the results are aggregated into one blob.
+	before := time.Now()
+	resultsURL, err = presigningSvc.GetSearchJobResultsURL(env.userCtx, job.ID, service.ResultFormatJSONL, 0)
+	require.NoError(err)
+	require.True(resultsURL.Presigned)
+	key := fmt.Sprintf("%d-results.v%d.jsonl", job.ID, types.ResultsSchemaVersion)
+	require.Equal("https://blobs.example.com/"+key, resultsURL.URL)
+	require.Equal(service.DefaultResultsURLExpiry, presigningStore.expiry)
+	require.WithinDuration(before.Add(service.DefaultResultsURLExpiry), resultsURL.ExpiresAt, time.Minute)
+
+	var want bytes.Buffer
+	writerTo, err := env.svc.GetSearchJobResultsWriterTo(env.userCtx, job.ID, service.ResultFormatJSONL)
+	require.NoError(err)
+	_, err = writerTo.WriteTo(&want)
+	require.NoError(err)
+	require.NotEmpty(want.String())
+	require.Equal(want.String(), env.bucket[key])
+
+	// The aggregated blob doesn't show up in the results themselves.
+	var got bytes.Buffer
+	writerTo, err = env.svc.GetSearchJobResultsWriterTo(env.userCtx, job.ID, service.ResultFormatJSONL)
+	require.NoError(err)
+	_, err = writerTo.WriteTo(&got)
+	require.NoError(err)
+	require.Equal(want.String(), got.String())
+
+	// Only the initiator and site admins may get a URL.
+	_, err = presigningSvc.GetSearchJobResultsURL(malloryCtx, job.ID, service.ResultFormatJSONL, time.Minute)
+	require.ErrorIs(err, auth.ErrMustBeSiteAdminOrSameUser)
+
+	var issued int
+	for _, entry := range exportLogs() {
+		if fields, ok := audittest.ExtractAuditFields(entry); ok && fields.Action == "resultsURLIssued" {
+			issued++
+		}
+	}
+	require.Equal(3, issued)
+}
+
+// presigningUploadStore is an upload store which can presign URLs.
+type presigningUploadStore struct {
+	*mocks.MockStore
+	expiry time.Duration
+}
+
+func (s *presigningUploadStore) PresignGet(_ context.Context, key string, expiry time.Duration) (string, error) {
+	s.expiry = expiry
+	return "https://blobs.example.com/" + key, nil
+}
@@ -0,0 +1,256 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbfixture"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestExhaustiveSearch_Batching(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t)
+
+	for i, name := range []api.RepoName{"repoa", "repob", "repoc", "repod", "repoe"} {
+		dbfixture.Repo(t, env.db, dbfixture.WithRepoID(api.RepoID(i+1)), dbfixture.WithRepoName(name))
+	}
+	// repoe is too large to be batched.
+	_, err := env.db.ExecContext(context.Background(), "UPDATE gitserver_repos SET repo_size_bytes = CASE WHEN repo_id = 5 THEN 1048576 ELSE 100 END")
+	require.NoError(err)
+
+	// The first search of repoc fails with a transient error.
+	var mu sync.Mutex
+	var attempts map[string]int
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(_ context.Context, repoRev types.RepositoryRevision) error {
+			mu.Lock()
+			key := fmt.Sprintf("%d@%s", repoRev.Repository, repoRev.Revision)
+			attempts[key]++
+			attempt := attempts[key]
+			mu.Unlock()
+
+			if key == "3@rev1" && attempt == 1 {
+				return errors.Wrap(context.DeadlineExceeded, "searching repoc")
+			}
+			return nil
+		}}
+	}
+
+	// run runs a job with config, and returns its CSV results and the number
+	// of blobs written for it, which includes its summary.
+	run := func(config config) (int64, string, int) {
+		mu.Lock()
+		attempts = map[string]int{}
+		mu.Unlock()
+		blobsBefore := len(env.bucket)
+
+		searchJob := &searchJob{
+			workerDB: env.db,
+			config:   config,
+		}
+		routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+		require.NoError(err)
+		for _, routine := range routines {
+			go routine.Start()
+		}
+		defer func() {
+			for _, routine := range routines {
+				require.NoError(routine.Stop(context.Background()))
+			}
+		}()
+
+		job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 2@rev1 3@rev1 4@rev1 5@rev1 1@rev2", service.CreateSearchJobOpts{})
+		require.NoError(err)
+		require.Eventually(func() bool {
+			return !searchJob.hasWork(env.workerCtx)
+		}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		require.NoError(err)
+		// 1 search job + 5 repo jobs + 6 repo revision jobs
+		require.Equal(&types.RepoRevJobStats{Total: 12, Completed: 12}, stats)
+
+		mu.Lock()
+		require.Equal(map[string]int{"1@rev1": 1, "2@rev1": 1, "3@rev1": 2, "4@rev1": 1, "5@rev1": 1, "1@rev2": 1}, attempts, "only the failed search is retried")
+		mu.Unlock()
+
+		writerTo, err := env.svc.GetSearchJobResultsWriterTo(env.userCtx, job.ID, service.ResultFormatCSV)
+		require.NoError(err)
+		var buf bytes.Buffer
+		_, err = writerTo.WriteTo(&buf)
+		require.NoError(err)
+		return job.ID, buf.String(), len(env.bucket) - blobsBefore
+	}
+
+	_, unbatched, unbatchedBlobs := run(testConfig(5))
+	require.Equal(7, unbatchedBlobs)
+
+	config := testConfig(5)
+	config.TaskBatchSize = 3
+	config.TaskBatchMaxRepoSizeBytes = 1000
+	config.TaskBatchFillTimeout = 50 * time.Millisecond
+	jobID, batched, batchedBlobs := run(config)
+
+	require.Equal(unbatched, batched, "batching doesn't change the results")
+	require.Len(parseCSV(t, batched), 7)
+	require.Less(batchedBlobs, unbatchedBlobs)
+
+	// Only the tasks of small repositories are batched.
+	rows, err := env.store.Query(env.workerCtx, sqlf.Sprintf(`
+SELECT rj.repo_id, rrj.batch_id IS NOT NULL
+FROM exhaustive_search_repo_revision_jobs rrj
+JOIN exhaustive_search_repo_jobs rj ON rj.id = rrj.search_repo_job_id
+WHERE rj.search_job_id = %env.store`, jobID))
+	require.NoError(err)
+	defer rows.Close()
+	batchedRepos := map[int32]bool{}
+	for rows.Next() {
+		var repoID int32
+		var isBatched bool
+		require.NoError(rows.Scan(&repoID, &isBatched))
+		batchedRepos[repoID] = batchedRepos[repoID] || isBatched
+	}
+	require.NoError(rows.Err())
+	require.Equal(map[int32]bool{1: true, 2: true, 3: true, 4: true, 5: false}, batchedRepos)
+}
+
+// BenchmarkExhaustiveSearch_TaskBatching runs a job over 10k tiny repositories
+// with and without task batching, and reports the number of database round
+// trips that the job takes.
+func BenchmarkExhaustiveSearch_TaskBatching(b *testing.B) {
+	enabled := true
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{SearchJobs: &enabled}}})
+	defer conf.Mock(nil)
+
+	const numRepos = 10_000
+
+	require := require.New(b)
+	observationCtx := observation.TestContextTB(b)
+	logger := observationCtx.Logger
+
+	mockUploadStore, _ := newMockUploadStore(b)
+	handle := &countingHandle{
+		TransactableHandle: basestore.NewHandleWithDB(logger, dbtest.NewDB(b), sql.TxOptions{}),
+		queries:            new(atomic.Int64),
+	}
+	db := database.NewDBWith(logger, basestore.NewWithHandle(handle))
+	s := store.New(db, observation.TestContextTB(b))
+	svc := service.New(observationCtx, s, mockUploadStore, service.NewSearcherFake())
+
+	userID := dbfixture.User(b, db, dbfixture.WithUsername("alice")).ID
+	_, err := db.ExecContext(context.Background(), "INSERT INTO repo (id, name) SELECT i, 'repo' || i FROM generate_series(1, $1) i", numRepos)
+	require.NoError(err)
+	_, err = db.ExecContext(context.Background(), "UPDATE gitserver_repos SET repo_size_bytes = 100")
+	require.NoError(err)
+
+	var q strings.Builder
+	for i := 1; i <= numRepos; i++ {
+		fmt.Fprintf(&q, "%d@rev ", i)
+	}
+
+	for _, batchSize := range []int{0, 100} {
+		b.Run(fmt.Sprintf("batch size %d", batchSize), func(b *testing.B) {
+			require := require.New(b)
+			workerCtx, cancel := context.WithCancel(actor.WithInternalActor(context.Background()))
+			defer cancel()
+			userCtx := actor.WithActor(context.Background(), actor.FromUser(userID))
+
+			config := testConfig(10)
+			config.TaskBatchSize = batchSize
+			config.TaskBatchMaxRepoSizeBytes = 1000
+			config.TaskBatchFillTimeout = 50 * time.Millisecond
+			searchJob := &searchJob{
+				workerDB: db,
+				config:   config,
+			}
+			routines, err := searchJob.newSearchJobRoutines(workerCtx, observationCtx, mockUploadStore, func(*observation.Context, database.DB) service.NewSearcher {
+				return service.NewSearcherFake()
+			})
+			require.NoError(err)
+			for _, routine := range routines {
+				go routine.Start()
+			}
+			defer func() {
+				for _, routine := range routines {
+					require.NoError(routine.Stop(context.Background()))
+				}
+			}()
+
+			// Wait for the routines to start polling, so that we only count
+			// the round trips of the job.
+			time.Sleep(100 * time.Millisecond)
+			handle.queries.Store(0)
+			b.ResetTimer()
+
+			for range b.N {
+				job, err := svc.CreateSearchJob(userCtx, q.String(), service.CreateSearchJobOpts{})
+				require.NoError(err)
+				require.Eventually(func() bool {
+					return !searchJob.hasWork(workerCtx)
+				}, 5*time.Minute, 10*time.Millisecond)
+
+				stats, err := svc.GetAggregateRepoRevState(userCtx, job.ID)
+				require.NoError(err)
+				require.Equal(&types.RepoRevJobStats{Total: 2*numRepos + 1, Completed: 2*numRepos + 1}, stats)
+			}
+
+			b.StopTimer()
+			b.ReportMetric(float64(handle.queries.Load())/float64(b.N), "queries/op")
+		})
+	}
+}
+
+// countingHandle counts the statements that are run on a handle and on the
+// transactions started from it.
+type countingHandle struct {
+	basestore.TransactableHandle
+	queries *atomic.Int64
+}
+
+func (h *countingHandle) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	h.queries.Add(1)
+	return h.TransactableHandle.QueryContext(ctx, query, args...)
+}
+
+func (h *countingHandle) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	h.queries.Add(1)
+	return h.TransactableHandle.QueryRowContext(ctx, query, args...)
+}
+
+func (h *countingHandle) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	h.queries.Add(1)
+	return h.TransactableHandle.ExecContext(ctx, query, args...)
+}
+
+func (h *countingHandle) Transact(ctx context.Context) (basestore.TransactableHandle, error) {
+	h.queries.Add(1)
+	tx, err := h.TransactableHandle.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &countingHandle{TransactableHandle: tx, queries: h.queries}, nil
+}
@@ -0,0 +1,106 @@
+package search
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/derision-test/glock"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/hostname"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// envConfig is the part of config which is read from the environment.
+type envConfig struct {
+	env.BaseConfig
+
+	RetentionPeriod          time.Duration
+	NumJobWorkers            int
+	NumRepoWorkers           int
+	NumRevisionWorkers       int
+	MaxRevisionAttempts      int
+	BackendRequestsPerSecond int
+	BackendBurst             int
+	MaxRevisionsPerJob       int
+	MaxRevisionsPerCodeHost  int
+	CodeHostMaxRevisions     map[string]int
+	TaskBatchSize            int
+	TaskBatchMaxRepoSize     int
+	TaskBatchFillTimeout     time.Duration
+	MaxLogLinesPerJob        int
+	StalledMaxAge            time.Duration
+	ShutdownGracePeriod      time.Duration
+}
+
+var envConfigInst = &envConfig{}
+
+func (c *envConfig) Load() {
+	c.RetentionPeriod = c.GetInterval("SEARCH_JOBS_RETENTION_PERIOD", "720h", "How long search jobs and their results are kept. Set to 0 to keep them forever.")
+	c.NumJobWorkers = c.GetInt("SEARCH_JOBS_NUM_JOB_WORKERS", "5", "The number of search jobs which are expanded into repositories concurrently.")
+	c.NumRepoWorkers = c.GetInt("SEARCH_JOBS_NUM_REPO_WORKERS", "5", "The number of repositories whose revisions are resolved concurrently.")
+	c.NumRevisionWorkers = c.GetInt("SEARCH_JOBS_NUM_REVISION_WORKERS", "5", "The number of repository revisions which are searched concurrently.")
+	c.MaxRevisionAttempts = c.GetInt("SEARCH_JOBS_MAX_REVISION_ATTEMPTS", "5", "How often the search of a repository revision is attempted before it fails because of transient errors.")
+	c.BackendRequestsPerSecond = c.GetInt("SEARCH_JOBS_BACKEND_REQUESTS_PER_SECOND", "0", "The number of searches and revision resolutions per second all search job workers of a process may send to gitserver and searcher. Set to 0 for no limit.")
+	c.BackendBurst = c.GetInt("SEARCH_JOBS_BACKEND_BURST", "10", "The number of searches and revision resolutions search job workers may send at once before SEARCH_JOBS_BACKEND_REQUESTS_PER_SECOND applies.")
+	c.MaxRevisionsPerJob = c.GetInt("SEARCH_JOBS_MAX_REVISIONS_PER_JOB", "0", "The number of revisions of the same search job which are searched concurrently. Set to 0 for no limit.")
+	c.MaxRevisionsPerCodeHost = c.GetInt("SEARCH_JOBS_MAX_REVISIONS_PER_CODE_HOST", "0", "The number of revisions of repositories of the same code host which are searched concurrently, unless SEARCH_JOBS_CODE_HOST_MAX_REVISIONS has a ceiling for the code host. Set to 0 for no limit.")
+	c.TaskBatchSize = c.GetInt("SEARCH_JOBS_TASK_BATCH_SIZE", "0", "The number of revisions of small repositories of the same code host which are searched as one task. Set to 0 to search every revision on its own.")
+	c.TaskBatchMaxRepoSize = c.GetInt("SEARCH_JOBS_TASK_BATCH_MAX_REPO_SIZE_BYTES", "10485760", "The size of the largest repository whose revisions are batched, see SEARCH_JOBS_TASK_BATCH_SIZE.")
+	c.TaskBatchFillTimeout = c.GetInterval("SEARCH_JOBS_TASK_BATCH_FILL_TIMEOUT", "5s", "How long a batch of revisions waits for more revisions before it is searched, see SEARCH_JOBS_TASK_BATCH_SIZE.")
+	c.MaxLogLinesPerJob = c.GetInt("SEARCH_JOBS_MAX_LOG_LINES_PER_JOB", "10000", "The number of lines the workers write to the log of a search job. Set to 0 for no limit.")
+	c.StalledMaxAge = c.GetInterval("SEARCH_JOBS_STALLED_MAX_AGE", store.DefaultStalledMaxAge.String(), "How long a search job task may go without a heartbeat before it is requeued.")
+	c.ShutdownGracePeriod = c.GetInterval("SEARCH_JOBS_SHUTDOWN_GRACE_PERIOD", "20s", "How long search job tasks may run once the worker stops before they are requeued. Keep it below the termination grace period of the pod.")
+
+	// SEARCH_JOBS_CODE_HOST_MAX_REVISIONS is a JSON object with the ceiling of
+	// each code host by its external service ID.
+	codeHostMaxRevisions := c.Get("SEARCH_JOBS_CODE_HOST_MAX_REVISIONS", "{}", `The number of revisions of repositories of each code host which are searched concurrently, as a JSON object keyed by the external service ID of the code host, e.g. {"https://github.com/": 4}. Set to 0 for no limit.`)
+	if err := json.Unmarshal([]byte(codeHostMaxRevisions), &c.CodeHostMaxRevisions); err != nil {
+		c.AddError(errors.Errorf("invalid JSON object of integers %q for SEARCH_JOBS_CODE_HOST_MAX_REVISIONS: %s", codeHostMaxRevisions, err))
+	}
+}
+
+func (c *envConfig) Validate() error {
+	if err := c.BaseConfig.Validate(); err != nil {
+		return err
+	}
+	return c.workerConfig().validate()
+}
+
+// workerConfig returns the config of the workers with the values read from the
+// environment.
+func (c *envConfig) workerConfig() config {
+	return config{
+		WorkerInterval:    1 * time.Second,
+		HeartbeatInterval: 5 * time.Second,
+		RetentionPeriod:   c.RetentionPeriod,
+
+		NumJobWorkers:      c.NumJobWorkers,
+		NumRepoWorkers:     c.NumRepoWorkers,
+		NumRevisionWorkers: c.NumRevisionWorkers,
+
+		BackendRequestsPerSecond: float64(c.BackendRequestsPerSecond),
+		BackendBurst:             c.BackendBurst,
+		MaxRevisionsPerJob:       c.MaxRevisionsPerJob,
+		MaxRevisionsPerCodeHost:  c.MaxRevisionsPerCodeHost,
+		CodeHostMaxRevisions:     c.CodeHostMaxRevisions,
+		MaxLogLinesPerJob:        c.MaxLogLinesPerJob,
+
+		MaxRevisionAttempts: c.MaxRevisionAttempts,
+		RetryBackoff:        10 * time.Second,
+
+		TaskBatchSize:             c.TaskBatchSize,
+		TaskBatchMaxRepoSizeBytes: int64(c.TaskBatchMaxRepoSize),
+		TaskBatchFillTimeout:      c.TaskBatchFillTimeout,
+
+		StalledMaxAge:    c.StalledMaxAge,
+		ResetterInterval: 1 * time.Minute,
+
+		ShutdownGracePeriod: c.ShutdownGracePeriod,
+
+		Clock: glock.NewRealClock(),
+
+		WorkerHostname: hostname.Get(),
+	}
+}
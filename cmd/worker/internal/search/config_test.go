@@ -0,0 +1,60 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvConfigDefaults(t *testing.T) {
+	c := envConfig{}
+	c.SetMockGetter(mapGetter(nil))
+	c.Load()
+	require.NoError(t, c.Validate())
+
+	cfg := c.workerConfig()
+	require.Equal(t, 30*24*time.Hour, cfg.RetentionPeriod)
+	require.Equal(t, 5, cfg.NumRevisionWorkers)
+	require.Equal(t, int64(10*1024*1024), cfg.TaskBatchMaxRepoSizeBytes)
+	require.Equal(t, 60*time.Second, cfg.StalledMaxAge)
+	require.Empty(t, cfg.CodeHostMaxRevisions)
+}
+
+func TestEnvConfigCodeHostMaxRevisions(t *testing.T) {
+	c := envConfig{}
+	c.SetMockGetter(mapGetter(map[string]string{
+		"SEARCH_JOBS_CODE_HOST_MAX_REVISIONS": `{"https://github.com/": 4}`,
+	}))
+	c.Load()
+	require.NoError(t, c.Validate())
+	require.Equal(t, map[string]int{"https://github.com/": 4}, c.workerConfig().CodeHostMaxRevisions)
+}
+
+func TestEnvConfigInvalid(t *testing.T) {
+	for name, env := range map[string]map[string]string{
+		"malformed JSON":      {"SEARCH_JOBS_CODE_HOST_MAX_REVISIONS": `{"https://github.com/": "four"}`},
+		"malformed int":       {"SEARCH_JOBS_NUM_REVISION_WORKERS": "five"},
+		"malformed duration":  {"SEARCH_JOBS_STALLED_MAX_AGE": "1 minute"},
+		"out of range values": {"SEARCH_JOBS_NUM_REVISION_WORKERS": "0"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := envConfig{}
+			c.SetMockGetter(mapGetter(env))
+			// Malformed values are reported by Validate instead of panicking.
+			require.NotPanics(t, c.Load)
+			for k := range env {
+				require.ErrorContains(t, c.Validate(), k)
+			}
+		})
+	}
+}
+
+func mapGetter(env map[string]string) func(name, defaultValue, description string) string {
+	return func(name, defaultValue, description string) string {
+		if v, ok := env[name]; ok {
+			return v
+		}
+		return defaultValue
+	}
+}
@@ -2,7 +2,6 @@ package search
 
 import (
 	"context"
-	"time"
 
 	"github.com/sourcegraph/log"
 
@@ -26,72 +25,98 @@ func newExhaustiveSearchWorker(
 	newSearcher service.NewSearcher,
 	config config,
 ) goroutine.BackgroundRoutine {
+	abandoned, abandon := context.WithCancel(context.Background())
 	handler := &exhaustiveSearchHandler{
 		logger:      log.Scoped("exhaustive-search"),
 		store:       exhaustiveSearchStore,
 		newSearcher: newSearcher,
+		abandoned:   abandoned,
 	}
 
 	opts := workerutil.WorkerOptions{
 		Name:              "exhaustive_search_worker",
 		Description:       "runs the exhaustive search",
-		NumHandlers:       5,
+		NumHandlers:       config.NumJobWorkers,
 		Interval:          config.WorkerInterval,
-		HeartbeatInterval: 15 * time.Second,
+		HeartbeatInterval: config.HeartbeatInterval,
+		WorkerHostname:    config.WorkerHostname,
 		Metrics:           workerutil.NewMetrics(observationCtx, "exhaustive_search_worker"),
 	}
 
-	return dbworker.NewWorker[*types.ExhaustiveSearchJob](ctx, workerStore, handler, opts)
+	workerHandler, workerStore := withFaults[*types.ExhaustiveSearchJob](handler, workerStore, config)
+	worker := dbworker.NewWorker[*types.ExhaustiveSearchJob](ctx, workerStore, workerHandler, opts)
+	return newGracefulWorker(worker, config.ShutdownGracePeriod, abandon)
 }
 
 type exhaustiveSearchHandler struct {
 	logger      log.Logger
 	store       *store.Store
 	newSearcher service.NewSearcher
+
+	// abandoned is canceled once the worker stops and the grace period ran
+	// out. The job is retried, and only creates the tasks which are missing.
+	abandoned context.Context
 }
 
 var _ workerutil.Handler[*types.ExhaustiveSearchJob] = &exhaustiveSearchHandler{}
+var _ workerutil.WithHooks[*types.ExhaustiveSearchJob] = &exhaustiveSearchHandler{}
 
 func (h *exhaustiveSearchHandler) Handle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchJob) (err error) {
 	// TODO observability? read other handlers to see if we are missing stuff
 
+	ctx, cancel := withAbandon(ctx, h.abandoned)
+	defer cancel()
+
 	userID := record.InitiatorID
-	ctx = actor.WithActor(ctx, actor.FromUser(userID))
+	// The search runs with the permissions of the initiator, but the store is
+	// only called with the internal actor of the worker.
+	userCtx := actor.WithActor(ctx, actor.FromUser(userID))
 
-	q, err := h.newSearcher.NewSearch(ctx, userID, record.Query)
+	q, err := h.newSearcher.NewSearch(userCtx, userID, record.Query)
 	if err != nil {
 		return err
 	}
 
-	tx, err := h.store.Transact(ctx)
+	// The repository revision specs are inserted while they are resolved, in
+	// batches which are committed on their own. If the worker crashes, the job
+	// is retried and only creates the tasks which are missing. The job is
+	// starting until all tasks exist, see types.ExhaustiveSearchJob.ExpandedAt.
+	created, err := h.store.CreateExhaustiveSearchRepoJobs(ctx, record.ID, q.RepositoryRevSpecs(userCtx))
 	if err != nil {
 		return err
 	}
-	defer func() { err = tx.Done(err) }()
-
-	it := q.RepositoryRevSpecs(ctx)
-	for it.Next() {
-		repoRevSpec := it.Current()
-		_, err := tx.CreateExhaustiveSearchRepoJob(ctx, types.ExhaustiveSearchRepoJob{
-			RepoID:      repoRevSpec.Repository,
-			RefSpec:     repoRevSpec.RevisionSpecifiers.String(),
-			SearchJobID: record.ID,
-		})
-		if err != nil {
-			return err
-		}
-	}
+	logger.Debug("created repo jobs", log.Int64("searchJobID", record.ID), log.Int("created", created))
 
-	return it.Err()
+	return h.store.MarkSearchJobExpanded(ctx, record.ID)
+}
+
+func (h *exhaustiveSearchHandler) PreHandle(context.Context, log.Logger, *types.ExhaustiveSearchJob) {
+}
+
+// PostHandle runs after the record was moved to its new state. A job fails on
+// its own if it can't resolve its repositories.
+func (h *exhaustiveSearchHandler) PostHandle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchJob) {
+	enqueueNotifications(ctx, logger, h.store, record.ID)
+}
+
+// enqueueNotifications enqueues the notifications of search job id if the task
+// which just finished was the last one of the job. The handlers call it from
+// PostHandle, since the worker only moves a record to its final state after
+// Handle returned.
+func enqueueNotifications(ctx context.Context, logger log.Logger, s *store.Store, id int64) {
+	if _, err := s.EnqueueSearchJobNotifications(ctx, id); err != nil {
+		logger.Error("failed to enqueue search job notifications", log.Int64("searchJobID", id), log.Error(err))
+	}
 }
 
 func newExhaustiveSearchWorkerResetter(
 	observationCtx *observation.Context,
 	workerStore dbworkerstore.Store[*types.ExhaustiveSearchJob],
+	config config,
 ) *dbworker.Resetter[*types.ExhaustiveSearchJob] {
 	options := dbworker.ResetterOptions{
 		Name:     "exhaustive_search_worker_resetter",
-		Interval: 1 * time.Minute,
+		Interval: config.ResetterInterval,
 		Metrics:  dbworker.NewResetterMetrics(observationCtx, "exhaustive_search_worker"),
 	}
 
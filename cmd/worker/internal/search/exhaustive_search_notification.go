@@ -0,0 +1,269 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// notifier sends the notifications of finished search jobs.
+type notifier interface {
+	// SendEmail tells the initiator of job that it finished in state.
+	SendEmail(ctx context.Context, job *types.ExhaustiveSearchJob, state types.JobState) error
+
+	// SendWebhook posts payload to webhookURL. The request is signed with
+	// secret.
+	SendWebhook(ctx context.Context, webhookURL, secret string, payload []byte) error
+}
+
+// newExhaustiveSearchNotificationWorker creates a background routine that sends the notifications of finished search jobs and writes their summaries.
+func newExhaustiveSearchNotificationWorker(
+	ctx context.Context,
+	observationCtx *observation.Context,
+	workerStore dbworkerstore.Store[*types.ExhaustiveSearchJobNotification],
+	exhaustiveSearchStore *store.Store,
+	svc *service.Service,
+	notifier notifier,
+	config config,
+) goroutine.BackgroundRoutine {
+	abandoned, abandon := context.WithCancel(context.Background())
+	handler := &exhaustiveSearchNotificationHandler{
+		store:     exhaustiveSearchStore,
+		service:   svc,
+		notifier:  notifier,
+		abandoned: abandoned,
+	}
+
+	opts := workerutil.WorkerOptions{
+		Name:              "exhaustive_search_notification_worker",
+		Description:       "notifies users that their search job finished and writes its summary",
+		NumHandlers:       5,
+		Interval:          config.WorkerInterval,
+		HeartbeatInterval: config.HeartbeatInterval,
+		WorkerHostname:    config.WorkerHostname,
+		Metrics:           workerutil.NewMetrics(observationCtx, "exhaustive_search_notification_worker"),
+	}
+
+	worker := dbworker.NewWorker[*types.ExhaustiveSearchJobNotification](ctx, workerStore, handler, opts)
+	return newGracefulWorker(worker, config.ShutdownGracePeriod, abandon)
+}
+
+type exhaustiveSearchNotificationHandler struct {
+	store    *store.Store
+	service  *service.Service
+	notifier notifier
+
+	// abandoned is canceled once the worker stops and the grace period ran
+	// out. The notification is retried.
+	abandoned context.Context
+}
+
+var _ workerutil.Handler[*types.ExhaustiveSearchJobNotification] = &exhaustiveSearchNotificationHandler{}
+
+func (h *exhaustiveSearchNotificationHandler) Handle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchJobNotification) error {
+	ctx, cancel := withAbandon(ctx, h.abandoned)
+	defer cancel()
+
+	job, err := h.store.GetExhaustiveSearchJob(ctx, record.SearchJobID)
+	if err != nil {
+		return err
+	}
+
+	switch record.Kind {
+	case types.NotificationKindEmail:
+		return h.notifier.SendEmail(ctx, job, record.JobState)
+
+	case types.NotificationKindWebhook:
+		webhookURL, secret, err := h.store.GetExhaustiveSearchJobWebhook(ctx, job.ID)
+		if err != nil {
+			return err
+		}
+		if webhookURL == "" {
+			return nil
+		}
+
+		payload, err := json.Marshal(newWebhookPayload(job, record.JobState))
+		if err != nil {
+			return err
+		}
+
+		return h.notifier.SendWebhook(ctx, webhookURL, secret, payload)
+
+	case types.NotificationKindSummary:
+		return h.service.WriteSearchJobSummary(ctx, job.ID)
+
+	default:
+		return errcode.MakeNonRetryable(errors.Newf("unknown notification kind %q", record.Kind))
+	}
+}
+
+// webhookPayload is the body of the webhook request sent when a search job
+// finished.
+type webhookPayload struct {
+	ID         string `json:"id"`
+	Query      string `json:"query"`
+	State      string `json:"state"`
+	Truncated  bool   `json:"truncated"`
+	ResultsURL string `json:"resultsURL"`
+}
+
+func newWebhookPayload(job *types.ExhaustiveSearchJob, state types.JobState) webhookPayload {
+	return webhookPayload{
+		// Same as the ID of the SearchJob GraphQL type.
+		ID:         string(relay.MarshalID("SearchJob", job.ID)),
+		Query:      job.Query,
+		State:      state.ToGraphQL(),
+		Truncated:  job.Truncated,
+		ResultsURL: externalURL(fmt.Sprintf("/.api/search/export/%d.jsonl", job.ID)),
+	}
+}
+
+func externalURL(path string) string {
+	u, err := url.JoinPath(conf.ExternalURL(), path)
+	if err != nil {
+		return path
+	}
+	return u
+}
+
+func newExhaustiveSearchNotificationWorkerResetter(
+	observationCtx *observation.Context,
+	workerStore dbworkerstore.Store[*types.ExhaustiveSearchJobNotification],
+	config config,
+) *dbworker.Resetter[*types.ExhaustiveSearchJobNotification] {
+	options := dbworker.ResetterOptions{
+		Name:     "exhaustive_search_notification_worker_resetter",
+		Interval: config.ResetterInterval,
+		Metrics:  dbworker.NewResetterMetrics(observationCtx, "exhaustive_search_notification_worker"),
+	}
+
+	resetter := dbworker.NewResetter(observationCtx.Logger, workerStore, options)
+	return resetter
+}
+
+// webhookEventType is sent in the X-Sourcegraph-Webhook-Event-Type header,
+// like the event types of outbound webhooks.
+const webhookEventType = "search_job:finished"
+
+// defaultNotifier sends emails with txemail and webhooks with client.
+type defaultNotifier struct {
+	db     database.DB
+	client *http.Client
+}
+
+var _ notifier = &defaultNotifier{}
+
+var notificationEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `Your search job {{.State}}`,
+	Text: `
+Your search job for the query
+
+  {{.Query}}
+
+{{.State}}.{{if .Truncated}} It stopped after reaching its result limit.{{end}}
+
+See your search jobs: {{.URL}}
+`,
+	HTML: `
+<p>Your search job for the query <code>{{.Query}}</code> {{.State}}.{{if .Truncated}} It stopped after reaching its result limit.{{end}}</p>
+
+<p><a href="{{.URL}}">See your search jobs</a></p>
+`,
+})
+
+func (n *defaultNotifier) SendEmail(ctx context.Context, job *types.ExhaustiveSearchJob, state types.JobState) error {
+	email, verified, err := n.db.UserEmails().GetPrimaryEmail(ctx, job.InitiatorID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return errcode.MakeNonRetryable(errors.Errorf("unable to send email to user ID %d with unknown email address", job.InitiatorID))
+		}
+		return errors.Wrapf(err, "get primary email for userID=%d", job.InitiatorID)
+	}
+	if !verified {
+		return errcode.MakeNonRetryable(errors.Newf("unable to send email to user ID %d's unverified primary email address", job.InitiatorID))
+	}
+
+	stateText := string(state)
+	if state == types.JobStateCanceled {
+		stateText = "was canceled"
+	}
+
+	return txemail.Send(ctx, "search-job", txtypes.Message{
+		To:       []string{email},
+		Template: notificationEmailTemplates,
+		Data: struct {
+			Query     string
+			State     string
+			Truncated bool
+			URL       string
+		}{
+			Query:     job.Query,
+			State:     stateText,
+			Truncated: job.Truncated,
+			URL:       externalURL("/search-jobs"),
+		},
+	})
+}
+
+func (n *defaultNotifier) SendWebhook(ctx context.Context, webhookURL, secret string, payload []byte) error {
+	// The URL was checked when the job was created, but the host may resolve
+	// to a different address by now.
+	if err := outbound.CheckURL(webhookURL); err != nil {
+		return errcode.MakeNonRetryable(errors.Wrap(err, "checking webhook URL"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "building request")
+	}
+	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("X-Sourcegraph-Webhook-Event-Type", webhookEventType)
+	req.Header.Add("X-Sourcegraph-Webhook-Signature", signPayload(secret, payload))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "sending webhook")
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// signPayload returns the hex encoded HMAC-SHA256 of payload, the same
+// signature outbound webhooks use.
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,300 @@
+package search
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestExhaustiveSearch_Notifications(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	completedJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 2@rev2", service.CreateSearchJobOpts{
+		WebhookURL:    "https://1.2.3.4/hook",
+		WebhookSecret: "secret",
+	})
+	require.NoError(err)
+	failedJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev3 2@rev4", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	canceledJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev5", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// Searching rev4 fails for good and searching rev5 runs until its job is
+	// canceled.
+	started := make(chan struct{})
+	var startedOnce sync.Once
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(ctx context.Context, repoRev types.RepositoryRevision) error {
+			switch repoRev.Revision {
+			case "rev4":
+				return errcode.MakeNonRetryable(errors.New("search failed"))
+			case "rev5":
+				startedOnce.Do(func() { close(started) })
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		}}
+	}
+
+	notifier := &fakeNotifier{}
+	searchJob := &searchJob{
+		workerDB: env.db,
+		notifier: notifier,
+		config:   testConfig(5),
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	select {
+	case <-started:
+	case <-time.After(tTimeout(t, 10*time.Second)):
+		t.Fatal("timed out waiting for the search of rev5 to start")
+	}
+	err = env.svc.CancelSearchJob(env.userCtx, canceledJob.ID)
+	require.NoError(err)
+
+	// The notifications are enqueued after the last task was marked, so we
+	// wait for all of them before we wait for the workers to be idle.
+	require.Eventually(func() bool {
+		emails, _ := notifier.get()
+		return len(emails) == 3
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	emails, webhooks := notifier.get()
+	require.ElementsMatch([]string{
+		fmt.Sprintf("%d:completed", completedJob.ID),
+		fmt.Sprintf("%d:failed", failedJob.ID),
+		fmt.Sprintf("%d:canceled", canceledJob.ID),
+	}, emails)
+
+	require.Len(webhooks, 1)
+	require.Equal("https://1.2.3.4/hook", webhooks[0].url)
+	require.Equal("secret", webhooks[0].secret)
+	var payload webhookPayload
+	require.NoError(json.Unmarshal(webhooks[0].payload, &payload))
+	require.Equal("1@rev1 2@rev2", payload.Query)
+	require.Equal("COMPLETED", payload.State)
+}
+
+func TestExhaustiveSearch_Summary(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	cleanJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 2@rev2", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	partialJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev3 2@rev4", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// Jobs only have a summary once they finished.
+	_, err = env.svc.GetSearchJobSummary(env.userCtx, cleanJob.ID)
+	require.ErrorIs(err, service.ErrSearchJobSummaryNotFound)
+
+	// Searching rev4 fails for good.
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(ctx context.Context, repoRev types.RepositoryRevision) error {
+			if repoRev.Revision == "rev4" {
+				return errcode.MakeNonRetryable(errors.New("search failed"))
+			}
+			return nil
+		}}
+	}
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		notifier: &fakeNotifier{},
+		config:   testConfig(5),
+	}
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	summarySchema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(types.SearchJobSummarySchema))
+	require.NoError(err)
+
+	// summary validates the summary which was written next to the results of
+	// job id against the schema, and returns it as the service reads it.
+	summary := func(id int64) *types.SearchJobSummary {
+		t.Helper()
+
+		raw, ok := env.bucket[fmt.Sprintf("%d-summary.json", id)]
+		require.True(ok, "no summary written for job %d", id)
+		res, err := summarySchema.Validate(gojsonschema.NewStringLoader(raw))
+		require.NoError(err)
+		for _, e := range res.Errors() {
+			t.Errorf("summary of job %d: %s", id, e)
+		}
+
+		got, err := env.svc.GetSearchJobSummary(env.userCtx, id)
+		require.NoError(err)
+		require.NotNil(got.StartedAt)
+		require.False(got.FinishedAt.Before(*got.StartedAt))
+		require.False(got.StartedAt.Before(got.CreatedAt))
+		return got
+	}
+
+	{
+		got := summary(cleanJob.ID)
+		require.Equal(types.SummarySchemaVersion, got.SchemaVersion)
+		require.Equal(cleanJob.ID, got.JobID)
+		require.Equal("1@rev1 2@rev2", got.Query)
+		require.Equal("jsonl", got.Format)
+		require.Equal(types.ResultsSchemaVersion, got.ResultsSchemaVersion)
+		require.Equal(types.JobStateCompleted, got.State)
+		require.True(got.Complete)
+		require.Equal(types.SearchJobSummaryInitiator{ID: env.userID, Username: "alice"}, got.Initiator)
+		require.Equal(types.SearchJobSummaryTasks{Total: 2, Completed: 2}, got.Tasks)
+		require.Equal(2, got.ResultCount)
+		require.Positive(got.BytesWritten)
+		require.False(got.Truncated || got.DeadlineExceeded || got.Sampled)
+		require.Empty(got.FailedRepositories)
+	}
+
+	{
+		got := summary(partialJob.ID)
+		require.Equal(types.JobStateFailed, got.State)
+		require.False(got.Complete)
+		require.Equal(types.SearchJobSummaryTasks{Total: 2, Completed: 1, Failed: 1}, got.Tasks)
+		require.Equal(1, got.ResultCount)
+		require.Len(got.FailedRepositories, 1)
+		failure := got.FailedRepositories[0]
+		require.Equal(api.RepoName("repob"), failure.Repository)
+		require.Equal(api.RepoID(2), failure.RepositoryID)
+		require.Equal("rev4", failure.Revision)
+		require.Contains(failure.FailureMessage, "search failed")
+	}
+
+	summaryUploads := func() map[int64]int {
+		uploads := map[int64]int{}
+		for _, call := range env.uploadStore.UploadFunc.History() {
+			for _, id := range []int64{cleanJob.ID, partialJob.ID} {
+				if call.Arg1 == fmt.Sprintf("%d-summary.json", id) {
+					uploads[id]++
+				}
+			}
+		}
+		return uploads
+	}
+
+	// Each summary is written once, by the only worker which saw the job
+	// finish.
+	require.Equal(map[int64]int{cleanJob.ID: 1, partialJob.ID: 1}, summaryUploads())
+
+	// Only the workers write summaries.
+	err = env.svc.WriteSearchJobSummary(env.userCtx, cleanJob.ID)
+	require.Error(err)
+
+	// A job which is retried gets a new summary once it finished again.
+	_, err = env.svc.RetryFailedTasks(env.userCtx, partialJob.ID)
+	require.NoError(err)
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+	require.Equal(map[int64]int{cleanJob.ID: 1, partialJob.ID: 2}, summaryUploads())
+	require.Equal(types.SearchJobSummaryTasks{Total: 2, Completed: 1, Failed: 1}, summary(partialJob.ID).Tasks)
+}
+
+func TestDefaultNotifier_SendWebhook(t *testing.T) {
+	outbound.SetTestDenyList()
+	t.Cleanup(outbound.ResetDenyList)
+
+	payload := []byte(`{"state":"COMPLETED"}`)
+
+	var gotSignature, gotEventType string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get("X-Sourcegraph-Webhook-Signature")
+		gotEventType = r.Header.Get("X-Sourcegraph-Webhook-Event-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	t.Cleanup(srv.Close)
+
+	n := &defaultNotifier{client: srv.Client()}
+	err := n.SendWebhook(context.Background(), srv.URL, "secret", payload)
+	require.NoError(t, err)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(payload)
+	require.Equal(t, hex.EncodeToString(mac.Sum(nil)), gotSignature)
+	require.Equal(t, webhookEventType, gotEventType)
+	require.Equal(t, payload, gotBody)
+
+	// Requests to denied hosts are not sent.
+	err = n.SendWebhook(context.Background(), "http://169.254.169.254/hook", "secret", payload)
+	require.Error(t, err)
+}
+
+// fakeNotifier records notifications instead of sending them.
+type fakeNotifier struct {
+	mu       sync.Mutex
+	emails   []string
+	webhooks []fakeWebhook
+}
+
+type fakeWebhook struct {
+	url, secret string
+	payload     []byte
+}
+
+func (n *fakeNotifier) SendEmail(_ context.Context, job *types.ExhaustiveSearchJob, state types.JobState) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.emails = append(n.emails, fmt.Sprintf("%d:%s", job.ID, state))
+	return nil
+}
+
+func (n *fakeNotifier) SendWebhook(_ context.Context, webhookURL, secret string, payload []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.webhooks = append(n.webhooks, fakeWebhook{url: webhookURL, secret: secret, payload: payload})
+	return nil
+}
+
+func (n *fakeNotifier) get() ([]string, []fakeWebhook) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.emails...), append([]fakeWebhook(nil), n.webhooks...)
+}
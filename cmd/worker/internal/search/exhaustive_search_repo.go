@@ -2,7 +2,6 @@ package search
 
 import (
 	"context"
-	"time"
 
 	"github.com/sourcegraph/log"
 
@@ -24,35 +23,62 @@ func newExhaustiveSearchRepoWorker(
 	workerStore dbworkerstore.Store[*types.ExhaustiveSearchRepoJob],
 	exhaustiveSearchStore *store.Store,
 	newSearcher service.NewSearcher,
+	limiter *loadLimiter,
 	config config,
 ) goroutine.BackgroundRoutine {
+	abandoned, abandon := context.WithCancel(context.Background())
 	handler := &exhaustiveSearchRepoHandler{
 		logger:      log.Scoped("exhaustive-search-repo"),
 		store:       exhaustiveSearchStore,
 		newSearcher: newSearcher,
+		limiter:     limiter,
+		abandoned:   abandoned,
+
+		batchOpts: store.TaskBatchOptions{
+			Size:             config.TaskBatchSize,
+			MaxRepoSizeBytes: config.TaskBatchMaxRepoSizeBytes,
+			FillTimeout:      config.TaskBatchFillTimeout,
+		},
 	}
 
 	opts := workerutil.WorkerOptions{
 		Name:              "exhaustive_search_repo_worker",
 		Description:       "runs the exhaustive search on a repository",
-		NumHandlers:       5,
+		NumHandlers:       config.NumRepoWorkers,
 		Interval:          config.WorkerInterval,
-		HeartbeatInterval: 15 * time.Second,
+		HeartbeatInterval: config.HeartbeatInterval,
+		WorkerHostname:    config.WorkerHostname,
 		Metrics:           workerutil.NewMetrics(observationCtx, "exhaustive_search_repo_worker"),
 	}
 
-	return dbworker.NewWorker[*types.ExhaustiveSearchRepoJob](ctx, workerStore, handler, opts)
+	workerHandler, workerStore := withFaults[*types.ExhaustiveSearchRepoJob](handler, workerStore, config)
+	worker := dbworker.NewWorker[*types.ExhaustiveSearchRepoJob](ctx, workerStore, workerHandler, opts)
+	return newGracefulWorker(worker, config.ShutdownGracePeriod, abandon)
 }
 
 type exhaustiveSearchRepoHandler struct {
 	logger      log.Logger
 	store       *store.Store
 	newSearcher service.NewSearcher
+	limiter     *loadLimiter
+
+	// abandoned is canceled once the worker stops and the grace period ran
+	// out. The repository is retried, and only creates the tasks which are
+	// missing.
+	abandoned context.Context
+
+	// batchOpts batch the revisions of small repositories, unless its size
+	// is below 2.
+	batchOpts store.TaskBatchOptions
 }
 
 var _ workerutil.Handler[*types.ExhaustiveSearchRepoJob] = &exhaustiveSearchRepoHandler{}
+var _ workerutil.WithHooks[*types.ExhaustiveSearchRepoJob] = &exhaustiveSearchRepoHandler{}
+
+func (h *exhaustiveSearchRepoHandler) Handle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoJob) (err error) {
+	ctx, cancel := withAbandon(ctx, h.abandoned)
+	defer cancel()
 
-func (h *exhaustiveSearchRepoHandler) Handle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoJob) error {
 	repoRevSpec := types.RepositoryRevSpecs{
 		Repository:         record.RepoID,
 		RevisionSpecifiers: types.RevisionSpecifiers(record.RefSpec),
@@ -63,18 +89,35 @@ func (h *exhaustiveSearchRepoHandler) Handle(ctx context.Context, logger log.Log
 		return err
 	}
 
+	// The deadline is checked before every task, so it also holds if the
+	// workers restarted in the meantime.
+	if !parent.Deadline.IsZero() {
+		exceeded, err := h.store.SkipTasksAfterDeadline(ctx, parent.ID, record.ID, 0)
+		if err != nil || exceeded {
+			return err
+		}
+	}
+
 	userID := parent.InitiatorID
-	ctx = actor.WithActor(ctx, actor.FromUser(userID))
+	// The search runs with the permissions of the initiator, but the store is
+	// only called with the internal actor of the worker.
+	userCtx := actor.WithActor(ctx, actor.FromUser(userID))
 
-	q, err := h.newSearcher.NewSearch(ctx, userID, parent.Query)
+	q, err := h.newSearcher.NewSearch(userCtx, userID, parent.Query)
 	if err != nil {
 		return err
 	}
 
-	repoRevisions, err := q.ResolveRepositoryRevSpec(ctx, repoRevSpec)
+	// Resolving the revisions calls gitserver for every revision spec.
+	if err := h.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	repoRevisions, err := service.ResolveRepositoryRevisions(userCtx, q, repoRevSpec)
 	if err != nil {
 		return err
 	}
+	sampled := service.SampleRepositoryRevisions(repoRevisions, parent.SampleRate, parent.SampleSeed)
 
 	tx, err := h.store.Transact(ctx)
 	if err != nil {
@@ -82,11 +125,31 @@ func (h *exhaustiveSearchRepoHandler) Handle(ctx context.Context, logger log.Log
 	}
 	defer func() { err = tx.Done(err) }()
 
-	for _, repoRev := range repoRevisions {
-		_, err := tx.CreateExhaustiveSearchRepoRevisionJob(ctx, types.ExhaustiveSearchRepoRevisionJob{
+	// The worker may have died after it created the tasks, but before it
+	// marked the record as completed. The resetter requeues the record then,
+	// and we must not create the tasks again.
+	created, err := tx.HasRepoRevisionJobs(ctx, record.ID)
+	if err != nil || created {
+		return err
+	}
+
+	if parent.Sample() != nil {
+		if err := tx.AddSampledTasks(ctx, parent.ID, len(sampled), len(repoRevisions)); err != nil {
+			return err
+		}
+	}
+
+	for _, repoRev := range sampled {
+		job := types.ExhaustiveSearchRepoRevisionJob{
 			SearchRepoJobID: record.ID,
 			Revision:        repoRev.Revision,
-		})
+			CommitID:        repoRev.CommitID,
+		}
+		if h.batchOpts.Size > 1 {
+			_, _, err = tx.CreateBatchedRepoRevisionJob(ctx, job, h.batchOpts)
+		} else {
+			_, err = tx.CreateExhaustiveSearchRepoRevisionJob(ctx, job)
+		}
 		if err != nil {
 			return err
 		}
@@ -95,13 +158,21 @@ func (h *exhaustiveSearchRepoHandler) Handle(ctx context.Context, logger log.Log
 	return nil
 }
 
+func (h *exhaustiveSearchRepoHandler) PreHandle(context.Context, log.Logger, *types.ExhaustiveSearchRepoJob) {
+}
+
+func (h *exhaustiveSearchRepoHandler) PostHandle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoJob) {
+	enqueueNotifications(ctx, logger, h.store, record.SearchJobID)
+}
+
 func newExhaustiveSearchRepoWorkerResetter(
 	observationCtx *observation.Context,
 	workerStore dbworkerstore.Store[*types.ExhaustiveSearchRepoJob],
+	config config,
 ) *dbworker.Resetter[*types.ExhaustiveSearchRepoJob] {
 	options := dbworker.ResetterOptions{
 		Name:     "exhaustive_search_repo_worker_resetter",
-		Interval: 1 * time.Minute,
+		Interval: config.ResetterInterval,
 		Metrics:  dbworker.NewResetterMetrics(observationCtx, "exhaustive_search_repo_worker"),
 	}
 
@@ -3,17 +3,23 @@ package search
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/derision-test/glock"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
 
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
@@ -29,25 +35,47 @@ func newExhaustiveSearchRepoRevisionWorker(
 	exhaustiveSearchStore *store.Store,
 	newSearcher service.NewSearcher,
 	uploadStore uploadstore.Store,
+	metrics *metrics,
+	limiter *loadLimiter,
 	config config,
 ) goroutine.BackgroundRoutine {
+	if config.Faults != nil {
+		uploadStore = faultyUploadStore{Store: uploadStore, faults: config.Faults}
+	}
+
+	abandoned, abandon := context.WithCancel(context.Background())
 	handler := &exhaustiveSearchRepoRevHandler{
 		logger:      log.Scoped("exhaustive-search-repo-revision"),
 		store:       exhaustiveSearchStore,
 		newSearcher: newSearcher,
-		uploadStore: uploadStore,
+		uploadStore: meteredUploadStore{Store: uploadStore, chunkBytes: metrics.chunkBytes},
+		metrics:     metrics,
+		limiter:     limiter,
+
+		maxAttempts:   config.MaxRevisionAttempts,
+		retryBackoff:  config.RetryBackoff,
+		postponeDelay: config.WorkerInterval,
+		maxLogLines:   config.MaxLogLinesPerJob,
+		abandoned:     abandoned,
+		clock:         config.Clock,
+
+		workerHostname:  config.WorkerHostname,
+		workerStartedAt: config.WorkerStartedAt,
 	}
 
 	opts := workerutil.WorkerOptions{
 		Name:              "exhaustive_search_repo_revision_worker",
 		Description:       "runs the exhaustive search on a revision of a repository",
-		NumHandlers:       5,
+		NumHandlers:       config.NumRevisionWorkers,
 		Interval:          config.WorkerInterval,
-		HeartbeatInterval: 15 * time.Second,
+		HeartbeatInterval: config.HeartbeatInterval,
+		WorkerHostname:    config.WorkerHostname,
 		Metrics:           workerutil.NewMetrics(observationCtx, "exhaustive_search_repo_revision_worker"),
 	}
 
-	return dbworker.NewWorker[*types.ExhaustiveSearchRepoRevisionJob](ctx, workerStore, handler, opts)
+	workerHandler, workerStore := withFaults[*types.ExhaustiveSearchRepoRevisionJob](handler, workerStore, config)
+	worker := dbworker.NewWorker[*types.ExhaustiveSearchRepoRevisionJob](ctx, workerStore, workerHandler, opts)
+	return newGracefulWorker(worker, config.ShutdownGracePeriod, abandon)
 }
 
 type exhaustiveSearchRepoRevHandler struct {
@@ -55,43 +83,553 @@ type exhaustiveSearchRepoRevHandler struct {
 	store       *store.Store
 	newSearcher service.NewSearcher
 	uploadStore uploadstore.Store
+	metrics     *metrics
+	limiter     *loadLimiter
+
+	// maxAttempts is how often we search a revision before we give up on
+	// transient errors.
+	maxAttempts int
+
+	// retryBackoff is the delay before the second attempt. It doubles with
+	// every further attempt.
+	retryBackoff time.Duration
+
+	// postponeDelay is how long a revision is put back into the queue if its
+	// job searches as many revisions concurrently as it may.
+	postponeDelay time.Duration
+
+	// maxLogLines caps the number of lines of the log of a job.
+	maxLogLines int
+
+	// abandoned is canceled once the worker stops and the grace period ran
+	// out. The revisions which are still searched are put back into the
+	// queue.
+	abandoned context.Context
+
+	// clock is consulted for the time records are requeued after and for
+	// the duration of the searches.
+	clock glock.Clock
+
+	// workerHostname and workerStartedAt identify this process. The worker
+	// stores the hostname on the records it dequeues, and the handler adds
+	// the start time.
+	workerHostname  string
+	workerStartedAt time.Time
 }
 
 var _ workerutil.Handler[*types.ExhaustiveSearchRepoRevisionJob] = &exhaustiveSearchRepoRevHandler{}
+var _ workerutil.WithHooks[*types.ExhaustiveSearchRepoRevisionJob] = &exhaustiveSearchRepoRevHandler{}
+var _ workerutil.WithPreDequeue = &exhaustiveSearchRepoRevHandler{}
+
+// PreDequeue leaves the revisions of code hosts which are at their ceiling in
+// the queue. The order of the queue makes the workers take turns between the
+// other code hosts. Of a batch of revisions, only the task which searches the
+// batch is dequeued.
+func (h *exhaustiveSearchRepoRevHandler) PreDequeue(context.Context, log.Logger) (bool, any, error) {
+	conds := []*sqlf.Query{store.RevSearchJobDequeueCondition()}
+	if saturated := h.limiter.saturatedCodeHosts(); len(saturated) > 0 {
+		conds = append(conds, sqlf.Sprintf("exhaustive_search_repo_revision_jobs.code_host != ALL(%s)", pq.Array(saturated)))
+	}
+	return true, conds, nil
+}
+
+func (h *exhaustiveSearchRepoRevHandler) Handle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoRevisionJob) (err error) {
+	ctx, cancel := withAbandon(ctx, h.abandoned)
+	defer cancel()
+
+	defer func() {
+		// The worker stopped before the search finished. handle aborted the
+		// upload of the results, so the next worker searches the revision
+		// from scratch.
+		if err != nil && h.abandoned.Err() != nil {
+			err = h.handBack(ctx, logger, record, err)
+		}
+	}()
+
+	return h.handle(ctx, logger, record)
+}
+
+// handBack puts record back into the queue, without counting an attempt. err is
+// returned if record isn't processing anymore, for example since its job was
+// canceled.
+func (h *exhaustiveSearchRepoRevHandler) handBack(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoRevisionJob, err error) error {
+	// ctx is canceled already.
+	requeued, requeueErr := h.store.PostponeRepoRevisionJob(context.WithoutCancel(ctx), record.ID, h.clock.Now())
+	if requeueErr != nil {
+		return errors.Append(err, requeueErr)
+	}
+	if !requeued {
+		return err
+	}
+	h.metrics.tasks.WithLabelValues("handed_back").Inc()
+	logger.Info("handed back repo revision job since the worker is stopping")
+	return nil
+}
+
+func (h *exhaustiveSearchRepoRevHandler) handle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoRevisionJob) error {
+	// The worker identity is only for debugging, so we don't fail the task
+	// if we can't record it.
+	if err := h.store.SetRepoRevisionJobWorkerStartedAt(ctx, record.ID, h.workerStartedAt); err != nil {
+		logger.Warn("failed to record the start time of the worker", log.Error(err))
+	}
 
-func (h *exhaustiveSearchRepoRevHandler) Handle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoRevisionJob) error {
 	jobID, query, repoRev, initiatorID, err := h.store.GetQueryRepoRev(ctx, record)
 	if err != nil {
 		return err
 	}
 
-	ctx = actor.WithActor(ctx, actor.FromUser(initiatorID))
+	// Like the repo worker, we check the deadline before every task.
+	exceeded, err := h.store.SkipTasksAfterDeadline(ctx, jobID, 0, record.ID)
+	if err != nil {
+		return err
+	}
+	if exceeded {
+		h.metrics.tasks.WithLabelValues("skipped").Inc()
+		h.logTask(ctx, logger, jobID, repoRev, types.SearchJobLogEventSkipped, "the job reached its deadline")
+		return nil
+	}
 
-	q, err := h.newSearcher.NewSearch(ctx, initiatorID, query)
+	release, ok := h.limiter.acquireJob(jobID)
+	if !ok {
+		// We don't wait for a slot of the job, so this handler can search
+		// revisions of other jobs in the meantime.
+		postponed, err := h.store.PostponeRepoRevisionJob(ctx, record.ID, h.clock.Now().Add(h.postponeDelay))
+		if err != nil {
+			return err
+		}
+		if !postponed {
+			return errors.New("repo revision job is not processing anymore")
+		}
+		h.metrics.tasks.WithLabelValues("postponed").Inc()
+		return nil
+	}
+	defer release()
+
+	// The search runs with the permissions of the initiator, but the store is
+	// only called with the internal actor of the worker.
+	userCtx := actor.WithActor(ctx, actor.FromUser(initiatorID))
+
+	q, err := h.newSearcher.NewSearch(userCtx, initiatorID, query)
 	if err != nil {
 		return err
 	}
 
-	w, err := service.NewJSONWriter(ctx, h.uploadStore, fmt.Sprintf("%d-%d", jobID, record.ID))
+	if record.BatchID != 0 {
+		return h.handleBatch(ctx, userCtx, logger, q, record, jobID, repoRev)
+	}
+
+	w, err := service.NewJSONWriter(ctx, h.uploadStore, service.ResultsKeyPrefix(jobID, record.ID))
 	if err != nil {
 		return err
 	}
+	// The results are only kept if the search succeeded. Otherwise their
+	// upload is aborted, so no partial results are left behind.
+	defer func() {
+		if err := w.Abort(); err != nil {
+			logger.Warn("failed to abort upload of results", log.Error(err))
+		}
+	}()
+
+	// This blocks until the search may call the backends. The job may be
+	// canceled in the meantime.
+	if err := h.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	h.logTask(ctx, logger, jobID, repoRev, types.SearchJobLogEventStarted, fmt.Sprintf("attempt %d on worker %s", record.NumFailures+1, h.workerHostname))
+
+	cw := &countingMatchWriter{MatchWriter: w}
+	start := h.clock.Now()
+	err = q.Search(userCtx, repoRev, cw)
+	h.metrics.taskDuration.Observe(h.clock.Since(start).Seconds())
+
+	// The job was canceled while searching, don't keep the results.
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
 
-	err = q.Search(ctx, repoRev, w)
-	if closeErr := w.Flush(); closeErr != nil {
-		err = errors.Append(err, closeErr)
+	// We only count the results of successful searches, since failed searches
+	// are retried.
+	if err == nil {
+		limitReached, err := h.store.AddResultCount(ctx, jobID, cw.count)
+		if err != nil {
+			return err
+		}
+		// The job reached its result limit while we were searching, so our
+		// results are discarded.
+		if limitReached {
+			h.metrics.tasks.WithLabelValues("succeeded").Inc()
+			h.logTask(ctx, logger, jobID, repoRev, types.SearchJobLogEventFinished, "the results were discarded since the job reached its result limit")
+			return nil
+		}
 	}
 
-	return err
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		if err := h.retry(ctx, logger, record, err); err != nil {
+			h.metrics.tasks.WithLabelValues("failed").Inc()
+			h.logTask(ctx, logger, jobID, repoRev, types.SearchJobLogEventFailed, err.Error())
+			return failureMessageError{err}
+		}
+		h.metrics.tasks.WithLabelValues("retried").Inc()
+		h.logTask(ctx, logger, jobID, repoRev, types.SearchJobLogEventRetried, err.Error())
+		return nil
+	}
+
+	// The results are stored, so a failure to count their bytes doesn't fail
+	// the task.
+	if err := h.store.AddBytesWritten(ctx, jobID, w.BytesWritten()); err != nil {
+		logger.Warn("failed to count the bytes of results", log.Error(err))
+	}
+
+	h.metrics.tasks.WithLabelValues("succeeded").Inc()
+	h.logTask(ctx, logger, jobID, repoRev, types.SearchJobLogEventFinished, fmt.Sprintf("%d results", cw.count))
+	return nil
+}
+
+// handleBatch searches the revisions of the batch of record, which is the task
+// of the batch with the lowest ID that is still to be searched. The matches of
+// a revision are kept in memory until its search succeeded, so a failed search
+// leaves no partial results behind, and the matches of all revisions which
+// succeeded are stored as one result chunk. The revisions which failed are
+// retried or failed on their own, like revisions which aren't batched, so a
+// retry only searches them again.
+func (h *exhaustiveSearchRepoRevHandler) handleBatch(ctx, userCtx context.Context, logger log.Logger, q service.SearchQuery, record *types.ExhaustiveSearchRepoRevisionJob, jobID int64, repoRev types.RepositoryRevision) error {
+	others, err := h.store.ListRepoRevisionBatchMembers(ctx, record)
+	if err != nil {
+		return err
+	}
+	members := append([]types.RepoRevisionBatchMember{{ExhaustiveSearchRepoRevisionJob: *record, RepoRev: repoRev}}, others...)
+
+	var lines []types.SearchJobLogLine
+	logLine := func(repoRev types.RepositoryRevision, event types.SearchJobLogEvent, message string) {
+		lines = append(lines, types.SearchJobLogLine{
+			SearchJobID: jobID,
+			RepoID:      repoRev.Repository,
+			Revision:    repoRev.Revision,
+			Event:       event,
+			Message:     store.TruncateFailureMessage(message),
+		})
+	}
+
+	type searchResult struct {
+		member  types.RepoRevisionBatchMember
+		matches []result.Match
+		err     error
+	}
+	results := make([]searchResult, 0, len(members))
+	startedAt := h.clock.Now()
+	for _, m := range members {
+		// Every search waits for the backends, like the searches of
+		// revisions which aren't batched.
+		if err := h.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		logLine(m.RepoRev, types.SearchJobLogEventStarted, fmt.Sprintf("attempt %d on worker %s in batch %d", m.NumFailures+1, h.workerHostname, record.BatchID))
+
+		bw := &bufferedMatchWriter{}
+		start := h.clock.Now()
+		err := q.Search(userCtx, m.RepoRev, bw)
+		h.metrics.taskDuration.Observe(h.clock.Since(start).Seconds())
+
+		// The job was canceled while searching, don't keep the results. The
+		// other revisions are still queued.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		results = append(results, searchResult{member: m, matches: bw.matches, err: err})
+	}
+
+	// We only count the results of successful searches, since failed searches
+	// are retried.
+	var (
+		count   int
+		chunkID int64
+		chunk   [][]result.Match
+	)
+	for _, r := range results {
+		if r.err == nil {
+			if chunk == nil {
+				chunkID = r.member.ID
+			}
+			count += len(r.matches)
+			chunk = append(chunk, r.matches)
+		}
+	}
+	var limitReached bool
+	if len(chunk) > 0 {
+		if limitReached, err = h.store.AddResultCount(ctx, jobID, count); err != nil {
+			return err
+		}
+	}
+
+	// The chunk is named after the first revision which succeeded. It is
+	// completed afterwards, so a later attempt of the batch never replaces
+	// the chunk.
+	var writeErr error
+	if count > 0 && !limitReached {
+		var bytesWritten int64
+		bytesWritten, writeErr = h.writeBatchResults(ctx, logger, service.ResultsKeyPrefix(jobID, chunkID), chunk)
+		// The results are stored, so a failure to count their bytes doesn't
+		// fail the tasks.
+		if writeErr == nil {
+			if err := h.store.AddBytesWritten(ctx, jobID, bytesWritten); err != nil {
+				logger.Warn("failed to count the bytes of results", log.Error(err))
+			}
+		}
+	}
+
+	var (
+		outcomes      []store.BatchMemberOutcome
+		recordOutcome store.BatchMemberOutcome
+		recordErr     error
+		retryBackoff  time.Duration
+	)
+	for _, r := range results {
+		err := r.err
+		if err == nil {
+			err = writeErr
+		}
+
+		outcome := store.BatchMemberOutcome{ID: r.member.ID, State: types.JobStateCompleted}
+		var failErr error
+		switch {
+		case err == nil:
+			h.metrics.tasks.WithLabelValues("succeeded").Inc()
+			if limitReached {
+				logLine(r.member.RepoRev, types.SearchJobLogEventFinished, "the results were discarded since the job reached its result limit")
+			} else {
+				logLine(r.member.RepoRev, types.SearchJobLogEventFinished, fmt.Sprintf("%d results", len(r.matches)))
+			}
+		default:
+			backoff, permanentErr := h.backoff(int(r.member.NumFailures)+1, err)
+			if permanentErr != nil {
+				outcome.State = types.JobStateFailed
+				outcome.FailureMessage = permanentErr.Error()
+				failErr = permanentErr
+				h.metrics.tasks.WithLabelValues("failed").Inc()
+				logLine(r.member.RepoRev, types.SearchJobLogEventFailed, permanentErr.Error())
+			} else {
+				outcome.State = types.JobStateQueued
+				outcome.FailureMessage = err.Error()
+				failErr = err
+				retryBackoff = max(retryBackoff, backoff)
+				h.metrics.tasks.WithLabelValues("retried").Inc()
+				logLine(r.member.RepoRev, types.SearchJobLogEventRetried, err.Error())
+			}
+		}
+
+		if r.member.ID == record.ID {
+			recordOutcome, recordErr = outcome, failErr
+		} else {
+			outcomes = append(outcomes, outcome)
+		}
+	}
+
+	defer func() {
+		if err := h.store.AppendSearchJobLogLines(ctx, jobID, lines, h.maxLogLines); err != nil {
+			logger.Warn("failed to write search job log", log.Error(err))
+		}
+	}()
+
+	// The revisions which are retried wait as long as the one which failed
+	// most often.
+	retryAfter := h.clock.Now().Add(retryBackoff)
+	if err := h.store.FinishRepoRevisionBatchMembers(ctx, record, startedAt, retryAfter, outcomes); err != nil {
+		return err
+	}
+
+	switch recordOutcome.State {
+	case types.JobStateFailed:
+		return failureMessageError{recordErr}
+	case types.JobStateQueued:
+		requeued, err := h.store.RequeueRepoRevisionJob(ctx, record.ID, retryAfter, recordErr.Error())
+		if err != nil {
+			// The worker marks the record as errored, so it is still retried.
+			return errors.Append(recordErr, err)
+		}
+		if !requeued {
+			return recordErr
+		}
+	}
+	return nil
+}
+
+// writeBatchResults stores the matches of the revisions of a batch which
+// succeeded as one result chunk with prefix, and returns its size in bytes.
+func (h *exhaustiveSearchRepoRevHandler) writeBatchResults(ctx context.Context, logger log.Logger, prefix string, chunk [][]result.Match) (int64, error) {
+	w, err := service.NewJSONWriter(ctx, h.uploadStore, prefix)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := w.Abort(); err != nil {
+			logger.Warn("failed to abort upload of results", log.Error(err))
+		}
+	}()
+
+	for _, matches := range chunk {
+		for _, match := range matches {
+			if err := w.Write(match); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return 0, err
+	}
+	return w.BytesWritten(), nil
+}
+
+// logTask appends a line about the search of repoRev to the log of job jobID.
+// The log only helps users find out what happened, so a line which can't be
+// written doesn't fail the search.
+func (h *exhaustiveSearchRepoRevHandler) logTask(ctx context.Context, logger log.Logger, jobID int64, repoRev types.RepositoryRevision, event types.SearchJobLogEvent, message string) {
+	err := h.store.AppendSearchJobLogLine(ctx, types.SearchJobLogLine{
+		SearchJobID: jobID,
+		RepoID:      repoRev.Repository,
+		Revision:    repoRev.Revision,
+		Event:       event,
+		Message:     store.TruncateFailureMessage(message),
+	}, h.maxLogLines)
+	if err != nil {
+		logger.Warn("failed to write search job log", log.String("event", string(event)), log.Error(err))
+	}
+}
+
+// failureMessageError truncates the message of the error returned by a
+// search. The worker stores it as the failure message of the record, which we
+// show to users for every failed revision of a job.
+type failureMessageError struct {
+	error
+}
+
+func (e failureMessageError) Error() string {
+	return store.TruncateFailureMessage(e.error.Error())
+}
+
+func (e failureMessageError) Unwrap() error {
+	return e.error
+}
+
+// maxRetryBackoff caps the delay between two attempts of a search.
+const maxRetryBackoff = 10 * time.Minute
+
+// retry requeues record with an exponential backoff if the search failed with
+// a transient error and record has attempts left. Otherwise it returns err as
+// a non-retryable error, so the worker marks record as failed right away.
+func (h *exhaustiveSearchRepoRevHandler) retry(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoRevisionJob, err error) error {
+	attempt := int(record.NumFailures) + 1
+	backoff, failErr := h.backoff(attempt, err)
+	if failErr != nil {
+		return failErr
+	}
+
+	requeued, requeueErr := h.store.RequeueRepoRevisionJob(ctx, record.ID, h.clock.Now().Add(backoff), err.Error())
+	if requeueErr != nil {
+		// The worker marks the record as errored, so it is still retried.
+		return errors.Append(err, requeueErr)
+	}
+	if !requeued {
+		return err
+	}
+
+	logger.Warn("requeued repo revision job after transient error",
+		log.Int("attempt", attempt),
+		log.Duration("backoff", backoff),
+		log.Error(err),
+	)
+	return nil
+}
+
+// backoff returns how long a revision whose search failed with err on attempt
+// waits before it is searched again. It returns err as a non-retryable error
+// instead if err isn't transient or the revision has no attempts left.
+func (h *exhaustiveSearchRepoRevHandler) backoff(attempt int, err error) (time.Duration, error) {
+	if !isTransientSearchError(err) {
+		return 0, errcode.MakeNonRetryable(err)
+	}
+	if attempt >= h.maxAttempts {
+		return 0, errcode.MakeNonRetryable(errors.Wrapf(err, "giving up after %d attempts", attempt))
+	}
+
+	backoff := h.retryBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	return backoff, nil
+}
+
+// isTransientSearchError returns true if a search which failed with err may
+// succeed if we try again later, for example after a network timeout or an
+// internal service responding with a 5xx. Errors which say the revision or
+// repository doesn't exist or the user may not search it are permanent. Errors
+// we know nothing about are considered transient.
+func isTransientSearchError(err error) bool {
+	if errcode.IsNonRetryable(err) || errcode.IsUnauthorized(err) || errcode.IsForbidden(err) || errcode.IsRepoDenied(err) {
+		return false
+	}
+
+	switch code := errcode.HTTP(err); {
+	case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests:
+		return true
+	case code >= 400 && code < 500:
+		return false
+	}
+
+	return true
+}
+
+// countingMatchWriter counts the matches written to MatchWriter.
+type countingMatchWriter struct {
+	service.MatchWriter
+	count int
+}
+
+func (w *countingMatchWriter) Write(match result.Match) error {
+	w.count++
+	return w.MatchWriter.Write(match)
+}
+
+// bufferedMatchWriter keeps the matches written to it in memory. It buffers
+// the matches of a revision of a batch until its search finished.
+type bufferedMatchWriter struct {
+	matches []result.Match
+}
+
+func (w *bufferedMatchWriter) Write(match result.Match) error {
+	w.matches = append(w.matches, match)
+	return nil
+}
+
+// PreHandle counts record against the ceiling of its code host. It runs on the
+// dequeue loop, so the next PreDequeue sees it.
+func (h *exhaustiveSearchRepoRevHandler) PreHandle(_ context.Context, _ log.Logger, record *types.ExhaustiveSearchRepoRevisionJob) {
+	h.limiter.acquireCodeHost(record.CodeHost)
+}
+
+func (h *exhaustiveSearchRepoRevHandler) PostHandle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoRevisionJob) {
+	h.limiter.releaseCodeHost(record.CodeHost)
+
+	jobID, _, _, _, err := h.store.GetQueryRepoRev(ctx, record)
+	if err != nil {
+		logger.Error("failed to get search job", log.Error(err))
+		return
+	}
+	enqueueNotifications(ctx, logger, h.store, jobID)
 }
 
 func newExhaustiveSearchRepoRevisionWorkerResetter(
 	observationCtx *observation.Context,
 	workerStore dbworkerstore.Store[*types.ExhaustiveSearchRepoRevisionJob],
+	config config,
 ) *dbworker.Resetter[*types.ExhaustiveSearchRepoRevisionJob] {
 	options := dbworker.ResetterOptions{
 		Name:     "exhaustive_search_repo_revision_worker_resetter",
-		Interval: 1 * time.Minute,
+		Interval: config.ResetterInterval,
 		Metrics:  dbworker.NewResetterMetrics(observationCtx, "exhaustive_search_repo_revision_worker"),
 	}
 
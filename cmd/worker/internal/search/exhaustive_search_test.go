@@ -3,8 +3,8 @@ package search
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"io"
 	"sort"
 	"strings"
 	"sync"
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
@@ -19,67 +20,84 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
-	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbfixture"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
-	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
-	"github.com/sourcegraph/sourcegraph/lib/iterator"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestExhaustiveSearch(t *testing.T) {
 	// This test exercises the full worker infra from the time a search job is
-	// created until it is done.
-
+	// created until it is done. With more than one handler per worker, the
+	// repo revision jobs write their results concurrently, which -race checks.
+	//
+	// The subtests run in parallel, so the global state they share is set up
+	// once for all of them. Cleanups of t run after the parallel subtests.
 	enabled := true
 	conf.Mock(&conf.Unified{
 		SiteConfiguration: schema.SiteConfiguration{
 			ExperimentalFeatures: &schema.ExperimentalFeatures{SearchJobs: &enabled}}})
-	defer conf.Mock(nil)
+	t.Cleanup(func() { conf.Mock(nil) })
+	jobLogsBatchSize := service.JobLogsBatchSize
+	service.JobLogsBatchSize = 2
+	t.Cleanup(func() { service.JobLogsBatchSize = jobLogsBatchSize })
+
+	for _, concurrency := range []int{1, 4} {
+		t.Run(fmt.Sprintf("concurrency=%d", concurrency), func(t *testing.T) {
+			t.Parallel()
+			testExhaustiveSearch(t, concurrency)
+		})
+	}
+}
 
+func testExhaustiveSearch(t *testing.T, concurrency int) {
 	require := require.New(t)
-	observationCtx := observation.TestContextTB(t)
-	logger := observationCtx.Logger
-
-	mockUploadStore, bucket := newMockUploadStore(t)
-	db := database.NewDB(logger, dbtest.NewDB(t))
-	s := store.New(db, observation.TestContextTB(t))
-	svc := service.New(observationCtx, s, mockUploadStore, service.NewSearcherFake())
+	env := newTestEnv(t, withParallelDB(), withRepos("repoa", "repob"))
 
-	userID := insertRow(t, s.Store, "users", "username", "alice")
-	userBadID := insertRow(t, s.Store, "users", "username", "mallory")
-	insertRow(t, s.Store, "repo", "id", 1, "name", "repoa")
-	insertRow(t, s.Store, "repo", "id", 2, "name", "repob")
+	userBadID := dbfixture.User(t, env.db, dbfixture.WithUsername("mallory")).ID
 
-	workerCtx, cancel1 := context.WithCancel(actor.WithInternalActor(context.Background()))
-	defer cancel1()
-	userCtx, cancel2 := context.WithCancel(actor.WithActor(context.Background(), actor.FromUser(userID)))
-	defer cancel2()
-
-	query := "1@rev1 1@rev2 2@rev3"
+	// The revision of repob needs quoting in CSV.
+	query := `1@rev1 1@rev2 2@rev,"tricky"`
 
 	// Create a job
-	job, err := svc.CreateSearchJob(userCtx, query)
+	job, err := env.svc.CreateSearchJob(env.userCtx, query, service.CreateSearchJobOpts{})
 	require.NoError(err)
 
 	// Do some assertions on the job before it runs
 	{
-		require.Equal(userID, job.InitiatorID)
+		require.Equal(env.userID, job.InitiatorID)
 		require.Equal(query, job.Query)
 		require.Equal(types.JobStateQueued, job.State)
+		require.Equal(types.JobCreationSourceAPI, job.CreationSource)
+		require.Equal("alice", job.InitiatorUsername)
 		require.NotZero(job.CreatedAt)
 		require.NotZero(job.UpdatedAt)
-		job2, err := svc.GetSearchJob(userCtx, job.ID)
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
 		require.NoError(err)
 		require.Equal(job, job2)
+
+		// Only the search job exists, the repo and repo revision jobs are
+		// created by the workers.
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(&types.RepoRevJobStats{
+			Total:      1,
+			InProgress: 1,
+		}, stats)
+
+		// The job is queued, so it can't be deleted until it is canceled.
+		err = env.svc.DeleteSearchJob(env.userCtx, job.ID)
+		require.ErrorIs(err, service.ErrSearchJobRunning)
 	}
 
 	// TODO these sort of tests need to live somewhere that makes more sense.
 	// But for now we have a fully functioning setup here lets test List.
 	{
-		jobs, err := svc.ListSearchJobs(userCtx, store.ListArgs{})
+		jobs, err := env.svc.ListSearchJobs(env.userCtx, store.ListArgs{})
 		require.NoError(err)
 
 		require.Equal([]*types.ExhaustiveSearchJob{job}, jobs)
@@ -88,17 +106,18 @@ func TestExhaustiveSearch(t *testing.T) {
 	// Now that the job is created, we start up all the worker routines for
 	// exhaustive search and wait until there are no more jobs left.
 	searchJob := &searchJob{
-		workerDB: db,
-		config: config{
-			WorkerInterval: 10 * time.Millisecond,
-		},
+		workerDB: env.db,
+		config:   testConfig(concurrency),
 	}
 
 	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
 		return service.NewSearcherFake()
 	}
 
-	routines, err := searchJob.newSearchJobRoutines(workerCtx, observationCtx, mockUploadStore, newSearcherFactory)
+	// The workers log the errors of their handlers rather than returning
+	// them, so they are captured to check that the happy path has none.
+	workerObservationCtx := observation.TestContextCaptured(t)
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, workerObservationCtx.Context, env.uploadStore, newSearcherFactory)
 	require.NoError(err)
 	for _, routine := range routines {
 		go routine.Start()
@@ -108,27 +127,34 @@ func TestExhaustiveSearch(t *testing.T) {
 		}()
 	}
 	require.Eventually(func() bool {
-		return !searchJob.hasWork(workerCtx)
+		// Best effort check that the stats are consistent while the workers
+		// are transitioning rows. We can't assert on require in here since
+		// this runs in a different goroutine.
+		if stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID); err != nil {
+			t.Errorf("failed to get stats: %s", err)
+		} else if stats.Total != stats.Completed+stats.Failed+stats.InProgress || stats.Total > 6 {
+			t.Errorf("inconsistent stats: %+v", stats)
+		}
+
+		return !searchJob.hasWork(env.workerCtx)
 	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+	workerObservationCtx.RequireNoErrors(t)
 
 	// Assert that we ended up writing the expected results. This validates
 	// that somehow the work happened (but doesn't dive into the guts of how
 	// we co-ordinate our workers)
 	{
-		var vals []string
-		for _, v := range bucket {
-			vals = append(vals, v)
-		}
+		vals := blobContents(t, env.bucket)
 		sort.Strings(vals)
 		require.Equal([]string{`{"type":"path","path":"path/to/file.go","repositoryID":1,"repository":"repo1","commit":"rev1","language":"Go"}
 `, `{"type":"path","path":"path/to/file.go","repositoryID":1,"repository":"repo1","commit":"rev2","language":"Go"}
-`, `{"type":"path","path":"path/to/file.go","repositoryID":2,"repository":"repo2","commit":"rev3","language":"Go"}
+`, `{"type":"path","path":"path/to/file.go","repositoryID":2,"repository":"repo2","commit":"rev,\"tricky\"","language":"Go"}
 `}, vals)
 	}
 
 	// Minor assertion that the job is regarded as finished.
 	{
-		job2, err := svc.GetSearchJob(userCtx, job.ID)
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
 		require.NoError(err)
 		// Only the WorkerJob fields should change. And in that case we will
 		// only assert on State since the rest are non-deterministic.
@@ -137,11 +163,23 @@ func TestExhaustiveSearch(t *testing.T) {
 		// ignore AggState. We fetched the job at different stages of its lifecycle so
 		// the states differ.
 		job2.AggState = job.AggState
+		// The job finished creating its tasks.
+		require.True(job.ExpandedAt.IsZero())
+		require.False(job2.ExpandedAt.IsZero())
+		job2.ExpandedAt = job.ExpandedAt
+		// The job counted the bytes of the blobs it wrote.
+		var size int64
+		for _, blob := range env.bucket {
+			size += int64(len(blob))
+		}
+		require.Zero(job.BytesWritten)
+		require.Equal(size, job2.BytesWritten)
+		job2.BytesWritten = job.BytesWritten
 		require.Equal(job, job2)
 	}
 
 	{
-		stats, err := svc.GetAggregateRepoRevState(userCtx, job.ID)
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
 		require.NoError(err)
 		require.Equal(&types.RepoRevJobStats{
 			Total:      6,
@@ -151,125 +189,513 @@ func TestExhaustiveSearch(t *testing.T) {
 		}, stats)
 	}
 
+	// Every completed repo revision job carries the identity of the worker
+	// which processed it.
+	{
+		rows, err := env.store.Store.Query(env.workerCtx, sqlf.Sprintf("SELECT state, worker_hostname, worker_started_at FROM exhaustive_search_repo_revision_jobs"))
+		require.NoError(err)
+		n := 0
+		for rows.Next() {
+			var state, workerHostname string
+			var workerStartedAt time.Time
+			require.NoError(rows.Scan(&state, &workerHostname, &workerStartedAt))
+			require.Equal("completed", state)
+			require.Equal(testWorkerHostname, workerHostname)
+			require.True(testWorkerStartedAt.Equal(workerStartedAt), "got worker_started_at %s", workerStartedAt)
+			n++
+		}
+		require.NoError(rows.Err())
+		require.NoError(rows.Close())
+		require.Equal(3, n)
+	}
+
+	// The metrics of the workers match the 3 revisions searched.
+	{
+		values := gatherMetrics(t, env.observationCtx.Registerer.(prometheus.Gatherer))
+		require.Equal(3.0, values["src_search_jobs_tasks_total{outcome=succeeded}"])
+		require.Zero(values["src_search_jobs_tasks_total{outcome=failed}"])
+		require.Zero(values["src_search_jobs_tasks_total{outcome=retried}"])
+		require.Equal(3.0, values["src_search_jobs_task_duration_seconds_count"])
+
+		// Every revision uploads one blob.
+		var size int
+		for _, blob := range env.bucket {
+			size += len(blob)
+		}
+		require.Equal(3.0, values["src_search_jobs_result_chunk_bytes_count"])
+		require.Equal(float64(size), values["src_search_jobs_result_chunk_bytes_sum"])
+
+		for _, tier := range []string{"job", "repo", "revision", "notification"} {
+			for _, state := range []string{"queued", "processing"} {
+				key := fmt.Sprintf("src_search_jobs_queue_size{state=%s,tier=%s}", state, tier)
+				value, ok := values[key]
+				require.True(ok, key)
+				require.Zero(value, key)
+			}
+		}
+	}
+
 	// Assert that we can write the job logs to a writer and that the number of
 	// lines and columns matches our expectation.
 	{
-		service.JobLogsIterLimit = 2
-		writerTo, err := svc.GetSearchJobLogsWriterTo(userCtx, job.ID)
+		writerTo, err := env.svc.GetSearchJobLogsWriterTo(env.userCtx, job.ID)
+		require.NoError(err)
+		var buf bytes.Buffer
+		_, err = writerTo.WriteTo(&buf)
+		require.NoError(err)
+		records := parseCSV(t, buf.String())
+		// 1 header + 3 rows
+		require.Equal(4, len(records), fmt.Sprintf("got %q", buf))
+		require.Equal([]string{"repository", "revision", "commit", "started_at", "finished_at", "status", "failure_message", "worker", "worker_started_at"}, records[0])
+		for _, record := range records[1:] {
+			require.Equal(9, len(record))
+			require.Equal(testWorkerHostname, record[7])
+			require.Equal(testWorkerStartedAt.Format(time.RFC3339), record[8])
+		}
+		var revisions, commits []string
+		for _, record := range records[1:] {
+			revisions = append(revisions, record[1])
+			commits = append(commits, record[2])
+		}
+		require.ElementsMatch([]string{"rev1", "rev2", `rev,"tricky"`}, revisions)
+		require.ElementsMatch([]string{"rev1", "rev2", `rev,"tricky"`}, commits)
+	}
+
+	// Assert that the results are aggregated into a single CSV ordered by
+	// repository and revision.
+	{
+		writerTo, err := env.svc.GetSearchJobResultsWriterTo(env.userCtx, job.ID, service.ResultFormatCSV)
 		require.NoError(err)
 		var buf bytes.Buffer
 		_, err = writerTo.WriteTo(&buf)
 		require.NoError(err)
-		lines := strings.Split(buf.String(), "\n")
-		// 1 header + 3 rows + 1 newline
-		require.Equal(5, len(lines), fmt.Sprintf("got %q", buf))
-		require.Equal("repository,revision,started_at,finished_at,status,failure_message", lines[0])
-		// We should use the CSV reader to parse this but since we know none of the
-		// columns have a "," in the context of this test, this is fine.
-		require.Equal(6, len(strings.Split(lines[1], ",")))
+		require.Equal([][]string{
+			types.ResultsColumns,
+			{"repoa", "1", "rev1", "rev1", "path/to/file.go", "", ""},
+			{"repoa", "1", "rev2", "rev2", "path/to/file.go", "", ""},
+			{"repob", "2", `rev,"tricky"`, `rev,"tricky"`, "path/to/file.go", "", ""},
+		}, parseCSV(t, buf.String()))
 	}
 
 	// Assert that we fail without writing anything if the user is not allowed
 	// to view the logs
 	{
 		userBadCtx := actor.WithActor(context.Background(), actor.FromUser(userBadID))
-		_, err = svc.GetSearchJobLogsWriterTo(userBadCtx, job.ID)
+		_, err = env.svc.GetSearchJobLogsWriterTo(userBadCtx, job.ID)
 		require.ErrorIs(err, auth.ErrMustBeSiteAdminOrSameUser)
 	}
 
-	// Assert that cancellation affects the number of rows we expect. This is a bit
-	// counterintuitive at this point because we have already completed the job.
-	// However, cancellation affects the rows independently of the job state.
+	// A completed job can't be canceled.
 	{
-		wantCount := 6
-		gotCount, err := s.CancelSearchJob(userCtx, job.ID)
-		require.NoError(err)
-		require.Equal(wantCount, gotCount)
+		err = env.svc.CancelSearchJob(env.userCtx, job.ID)
+		require.ErrorIs(err, service.ErrSearchJobFinished)
+	}
+
+	// Only the owner may delete the job.
+	{
+		userBadCtx := actor.WithActor(context.Background(), actor.FromUser(userBadID))
+		err = env.svc.DeleteSearchJob(userBadCtx, job.ID)
+		require.ErrorIs(err, auth.ErrMustBeSiteAdminOrSameUser)
+		// 3 result blobs + the summary
+		require.Equal(4, len(env.bucket))
 	}
 
 	// Delete should remove the job from the database and the uploadstore.
 	{
-		require.Equal(3, len(bucket))
-		err = svc.DeleteSearchJob(userCtx, job.ID)
+		require.Equal(4, len(env.bucket))
+		err = env.svc.DeleteSearchJob(env.userCtx, job.ID)
 		require.NoError(err)
-		require.Equal(0, len(bucket))
-		_, err = svc.GetSearchJob(userCtx, job.ID)
+		require.Equal(0, len(env.bucket))
+		_, err = env.svc.GetSearchJob(env.userCtx, job.ID)
 		require.Error(err)
+
+		// The repo and repo revision jobs are deleted with the job.
+		for _, table := range []string{"exhaustive_search_repo_jobs", "exhaustive_search_repo_revision_jobs"} {
+			count, err := basestore.ScanInt(env.store.Store.QueryRow(env.workerCtx, sqlf.Sprintf("SELECT COUNT(*) FROM %s", sqlf.Sprintf(table))))
+			require.NoError(err)
+			require.Zero(count, table)
+		}
+
+		// Deleting the job again is a no-op.
+		err = env.svc.DeleteSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
 	}
 }
 
-// insertRow is a helper for inserting a row into a table. It assumes the
-// table has an autogenerated column called id and it will return that value.
-func insertRow(t testing.TB, store *basestore.Store, table string, keyValues ...any) int32 {
-	var columns, values []*sqlf.Query
-	for i, kv := range keyValues {
-		if i%2 == 0 {
-			columns = append(columns, sqlf.Sprintf(kv.(string)))
-		} else {
-			values = append(values, sqlf.Sprintf("%v", kv))
+func TestExhaustiveSearch_Cancel(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 2@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// Searching rev2 runs until the job is canceled. It then still finds a
+	// match, which must not be uploaded.
+	started := make(chan struct{})
+	var startedOnce sync.Once
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(ctx context.Context, repoRev types.RepositoryRevision) error {
+			if repoRev.Revision == "rev2" {
+				startedOnce.Do(func() { close(started) })
+				<-ctx.Done()
+			}
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	// Wait until rev2 is being searched and everything else completed.
+	select {
+	case <-started:
+	case <-time.After(tTimeout(t, 10*time.Second)):
+		t.Fatal("timed out waiting for the search of rev2 to start")
+	}
+	require.Eventually(func() bool {
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		return err == nil && stats.Completed == 5
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	err = env.svc.CancelSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	{
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(types.JobStateCanceled, job2.AggState)
+
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(&types.RepoRevJobStats{
+			Total:     5,
+			Completed: 5,
+		}, stats)
+	}
+
+	// Only the results of rev1 and rev3 were uploaded.
+	{
+		var commits []string
+		for _, v := range blobContents(t, env.bucket) {
+			var m struct{ Commit string }
+			require.NoError(json.Unmarshal([]byte(v), &m))
+			commits = append(commits, m.Commit)
 		}
+		sort.Strings(commits)
+		require.Equal([]string{"rev1", "rev3"}, commits)
 	}
-	q := sqlf.Sprintf(`INSERT INTO %s(%s) VALUES(%s) RETURNING id`, sqlf.Sprintf(table), sqlf.Join(columns, ", "), sqlf.Join(values, ", "))
-	row := store.QueryRow(context.Background(), q)
-	var id int32
-	if err := row.Scan(&id); err != nil {
-		t.Fatal(err)
+
+	// Canceling a canceled job is a no-op.
+	{
+		err = env.svc.CancelSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
 	}
-	return id
 }
 
-// tTimeout returns the duration until t's deadline. If there is no deadline
-// or the deadline is further away than max, then max is returned.
-func tTimeout(t *testing.T, max time.Duration) time.Duration {
-	deadline, ok := t.Deadline()
-	if !ok {
-		return max
+func TestExhaustiveSearch_DuplicateRevisions(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa"))
+
+	// Both revisions resolve to the commit "rev1".
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@refs/heads/rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
 	}
-	timeout := time.Until(deadline)
-	if max < timeout {
-		return max
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
 	}
-	return timeout
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// 1 search job + 1 repo job + 1 repo revision job
+	stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(&types.RepoRevJobStats{Total: 3, Completed: 3}, stats)
+	// 1 result blob + the summary
+	require.Len(env.bucket, 2)
+
+	// The result has the spec of the first revision and the resolved commit.
+	writerTo, err := env.svc.GetSearchJobResultsWriterTo(env.userCtx, job.ID, service.ResultFormatCSV)
+	require.NoError(err)
+	var buf bytes.Buffer
+	_, err = writerTo.WriteTo(&buf)
+	require.NoError(err)
+	require.Equal([][]string{
+		types.ResultsColumns,
+		{"repoa", "1", "rev1", "rev1", "path/to/file.go", "", ""},
+	}, parseCSV(t, buf.String()))
 }
 
-func newMockUploadStore(t *testing.T) (*mocks.MockStore, map[string]string) {
-	t.Helper()
+func TestExhaustiveSearch_Sample(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa"))
+
+	var revs []string
+	for i := 1; i <= 20; i++ {
+		revs = append(revs, fmt.Sprintf("1@rev%d", i))
+	}
+	query := strings.Join(revs, " ")
 
-	// Each entry in bucket corresponds to one 1 uploaded csv file.
-	mu := sync.Mutex{}
-	bucket := make(map[string]string)
+	_, err := env.svc.CreateSearchJob(env.userCtx, query, service.CreateSearchJobOpts{SampleRate: 1.5})
+	require.Error(err)
 
-	mockStore := mocks.NewMockStore()
-	mockStore.UploadFunc.SetDefaultHook(func(ctx context.Context, key string, r io.Reader) (int64, error) {
-		b, err := io.ReadAll(r)
-		if err != nil {
-			return 0, err
+	job, err := env.svc.CreateSearchJob(env.userCtx, query, service.CreateSearchJobOpts{SampleRate: 0.5, SampleSeed: 42})
+	require.NoError(err)
+	// The duplicate has the same seed, so it searches the same sample.
+	duplicate, err := env.svc.DuplicateSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(int64(42), duplicate.SampleSeed)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
+	}
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// searched returns the revisions that job id found results in, which
+	// NewSearcherFake does in every revision.
+	searched := func(id int64) []string {
+		writerTo, err := env.svc.GetSearchJobResultsWriterTo(env.userCtx, id, service.ResultFormatCSV)
+		require.NoError(err)
+		var buf bytes.Buffer
+		_, err = writerTo.WriteTo(&buf)
+		require.NoError(err)
+		var revisions []string
+		for _, row := range parseCSV(t, buf.String())[1:] {
+			revisions = append(revisions, row[2])
 		}
+		sort.Strings(revisions)
+		return revisions
+	}
+
+	want := []string{"rev1", "rev11", "rev13", "rev18", "rev19", "rev4", "rev5", "rev6", "rev9"}
+	for _, id := range []int64{job.ID, duplicate.ID} {
+		require.Equal(want, searched(id))
+
+		got, err := env.svc.GetSearchJob(env.userCtx, id)
+		require.NoError(err)
+		require.Equal(types.JobStateCompleted, got.AggState)
+		require.Equal(len(want), got.SampledTaskCount)
+		require.Equal(len(revs), got.TotalTaskCount)
+	}
+}
+
+func TestExhaustiveSearch_Estimate(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	// rev1 and refs/heads/rev1 resolve to the same commit.
+	query := "1@rev1 1@refs/heads/rev1 1@rev2 2@rev3"
+
+	estimate, err := env.svc.EstimateSearchJob(env.userCtx, query)
+	require.NoError(err)
+	require.Equal(&service.SearchJobEstimate{RepoCount: 2, RevisionCount: 3, Size: service.EstimateSizeSmall}, estimate)
+
+	// Estimating doesn't create anything.
+	count := func(table string) int {
+		n, err := basestore.ScanInt(env.store.QueryRow(env.workerCtx, sqlf.Sprintf("SELECT COUNT(*) FROM %s", sqlf.Sprintf(table))))
+		require.NoError(err)
+		return n
+	}
+	require.Zero(count("exhaustive_search_jobs"))
 
-		mu.Lock()
-		bucket[key] = string(b)
-		mu.Unlock()
+	_, err = env.svc.CreateSearchJob(env.userCtx, query, service.CreateSearchJobOpts{})
+	require.NoError(err)
 
-		return int64(len(b)), nil
-	})
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
+	}
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
 
-	mockStore.DeleteFunc.SetDefaultHook(func(ctx context.Context, key string) error {
-		mu.Lock()
-		delete(bucket, key)
-		mu.Unlock()
+	// The estimate matches the tasks the workers created.
+	require.Equal(estimate.RepoCount, count("exhaustive_search_repo_jobs"))
+	require.Equal(estimate.RevisionCount, count("exhaustive_search_repo_revision_jobs"))
+}
 
-		return nil
-	})
+func TestExhaustiveSearch_Logs(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
 
-	mockStore.ListFunc.SetDefaultHook(func(ctx context.Context, prefix string) (*iterator.Iterator[string], error) {
-		var keys []string
-		mu.Lock()
-		for k := range bucket {
-			keys = append(keys, k)
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 2@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+
+	// The search of rev3 fails for good.
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(_ context.Context, repoRev types.RepositoryRevision) error {
+			if repoRev.Revision == "rev3" {
+				return errcode.MakeNonRetryable(errors.New("forced failure"))
+			}
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	lines, err := env.svc.GetSearchJobLogs(env.userCtx, job.ID, service.GetSearchJobLogsArgs{})
+	require.NoError(err)
+
+	// Every revision has one line when it started and one when it finished
+	// or failed.
+	events := map[string][]types.SearchJobLogEvent{}
+	for _, line := range lines {
+		require.Equal(job.ID, line.SearchJobID)
+		key := fmt.Sprintf("%s@%s", line.RepoName, line.Revision)
+		events[key] = append(events[key], line.Event)
+		if line.Event == types.SearchJobLogEventFailed {
+			require.Equal("forced failure", line.Message)
 		}
-		mu.Unlock()
-		return iterator.From(keys), nil
-	})
+	}
+	require.Equal(map[string][]types.SearchJobLogEvent{
+		"repoa@rev1": {types.SearchJobLogEventStarted, types.SearchJobLogEventFinished},
+		"repoa@rev2": {types.SearchJobLogEventStarted, types.SearchJobLogEventFinished},
+		"repob@rev3": {types.SearchJobLogEventStarted, types.SearchJobLogEventFailed},
+	}, events)
+
+	// The log can be paged through.
+	page, err := env.svc.GetSearchJobLogs(env.userCtx, job.ID, service.GetSearchJobLogsArgs{After: lines[0].ID, First: 2})
+	require.NoError(err)
+	require.Equal(lines[1:3], page)
+
+	// Other users can't read the log of the job.
+	otherUserID := dbfixture.User(t, env.db, dbfixture.WithUsername("bob")).ID
+	_, err = env.svc.GetSearchJobLogs(actor.WithActor(context.Background(), actor.FromUser(otherUserID)), job.ID, service.GetSearchJobLogsArgs{})
+	require.ErrorIs(err, auth.ErrMustBeSiteAdminOrSameUser)
+}
+
+func TestExhaustiveSearch_Progress(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	malloryID := dbfixture.User(t, env.db, dbfixture.WithUsername("mallory")).ID
+	adminID := dbfixture.User(t, env.db, dbfixture.WithUsername("admin"), dbfixture.WithSiteAdmin()).ID
 
-	return mockStore, bucket
+	malloryCtx := actor.WithActor(context.Background(), actor.FromUser(malloryID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 2@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// Before the workers ran, only the job itself exists.
+	progress, err := env.svc.JobProgress(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(&types.SearchJobProgress{Total: 1, Remaining: 1}, progress)
+
+	backlog, err := env.svc.GlobalBacklog(adminCtx)
+	require.NoError(err)
+	require.Equal(&types.SearchJobsBacklog{Jobs: 1}, backlog)
+
+	// Only the initiator and site admins may see the progress of a job, and
+	// only site admins the backlog of all jobs.
+	_, err = env.svc.JobProgress(malloryCtx, job.ID)
+	require.ErrorIs(err, auth.ErrMustBeSiteAdminOrSameUser)
+	_, err = env.svc.GlobalBacklog(env.userCtx)
+	require.ErrorIs(err, auth.ErrMustBeSiteAdmin)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		notifier: &fakeNotifier{},
+		config:   testConfig(5),
+	}
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
+	}
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// 1 search job + 2 repo jobs + 3 repo revision jobs
+	progress, err = env.svc.JobProgress(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(&types.SearchJobProgress{Total: 6, Remaining: 0}, progress)
+
+	backlog, err = env.svc.GlobalBacklog(adminCtx)
+	require.NoError(err)
+	require.Zero(backlog.Total())
 }
@@ -0,0 +1,198 @@
+package search
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// faults injects failures into the workers, so resilience tests can check
+// that retries, resets of stalled records and the idempotency of the handlers
+// produce the same results as a job which ran without failures. Each knob is
+// the probability that a fault happens. Only tests set config.Faults, there is
+// no way to enable it from the environment.
+//
+// The notification worker has no faults, since notifications are sent at
+// least once and aren't part of the results.
+type faults struct {
+	// HandlerDelay is the probability that a handler waits for up to
+	// MaxHandlerDelay before it runs, which shuffles the order of tasks.
+	HandlerDelay    float64
+	MaxHandlerDelay time.Duration
+
+	// UploadFailure is the probability that the upload of a chunk of results
+	// fails after the writer wrote part of it. The store discards it, like a
+	// failed multipart upload.
+	UploadFailure float64
+
+	// Crash is the probability that the worker dies after a handler
+	// succeeded, but before it marked the record as completed. The record
+	// stays processing until the resetter requeues it.
+	Crash float64
+
+	// DropHeartbeat is the probability that the heartbeat of the records a
+	// worker processes is lost.
+	DropHeartbeat float64
+
+	// Seed seeds the decisions, so a failing run can be reproduced as far as
+	// the scheduling of the handlers allows.
+	Seed uint64
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// validate returns an error if the workers can't finish their records with f.
+func (f *faults) validate() error {
+	for _, p := range []struct {
+		name  string
+		value float64
+	}{
+		{"HandlerDelay", f.HandlerDelay},
+		{"UploadFailure", f.UploadFailure},
+		{"Crash", f.Crash},
+		{"DropHeartbeat", f.DropHeartbeat},
+	} {
+		if p.value < 0 || p.value >= 1 {
+			return errors.Newf("fault injection: %s must be at least 0 and less than 1, got %v", p.name, p.value)
+		}
+	}
+	if f.MaxHandlerDelay < 0 {
+		return errors.Newf("fault injection: MaxHandlerDelay must not be negative, got %s", f.MaxHandlerDelay)
+	}
+	return nil
+}
+
+// inject returns true with probability p.
+func (f *faults) inject(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.rand == nil {
+		f.rand = rand.New(rand.NewPCG(f.Seed, 0))
+	}
+	return f.rand.Float64() < p
+}
+
+// handlerDelay returns how long a handler waits before it runs.
+func (f *faults) handlerDelay() time.Duration {
+	if f.MaxHandlerDelay <= 0 || !f.inject(f.HandlerDelay) {
+		return 0
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return time.Duration(f.rand.Int64N(int64(f.MaxHandlerDelay)))
+}
+
+// withFaults wraps handler and workerStore of a worker if config injects
+// faults.
+func withFaults[T workerutil.Record](handler workerutil.Handler[T], workerStore dbworkerstore.Store[T], config config) (workerutil.Handler[T], dbworkerstore.Store[T]) {
+	if config.Faults == nil {
+		return handler, workerStore
+	}
+	return faultyHandler[T]{Handler: handler, faults: config.Faults, clock: config.Clock},
+		faultyWorkerStore[T]{Store: workerStore, faults: config.Faults}
+}
+
+// errInjectedUploadFailure fails uploads of faultyUploadStore. The search of a
+// revision treats it like any other transient error.
+var errInjectedUploadFailure = errors.New("injected upload failure")
+
+// faultyHandler delays the records of Handler. It forwards the hooks Handler
+// implements, so the worker behaves the same otherwise.
+type faultyHandler[T workerutil.Record] struct {
+	workerutil.Handler[T]
+	faults *faults
+	clock  glock.Clock
+}
+
+var _ workerutil.WithHooks[*types.ExhaustiveSearchRepoRevisionJob] = faultyHandler[*types.ExhaustiveSearchRepoRevisionJob]{}
+var _ workerutil.WithPreDequeue = faultyHandler[*types.ExhaustiveSearchRepoRevisionJob]{}
+
+func (h faultyHandler[T]) Handle(ctx context.Context, logger log.Logger, record T) error {
+	if d := h.faults.handlerDelay(); d > 0 {
+		// The handler notices itself if ctx is done.
+		select {
+		case <-ctx.Done():
+		case <-h.clock.After(d):
+		}
+	}
+	return h.Handler.Handle(ctx, logger, record)
+}
+
+func (h faultyHandler[T]) PreDequeue(ctx context.Context, logger log.Logger) (bool, any, error) {
+	if p, ok := h.Handler.(workerutil.WithPreDequeue); ok {
+		return p.PreDequeue(ctx, logger)
+	}
+	return true, nil, nil
+}
+
+func (h faultyHandler[T]) PreHandle(ctx context.Context, logger log.Logger, record T) {
+	if p, ok := h.Handler.(workerutil.WithHooks[T]); ok {
+		p.PreHandle(ctx, logger, record)
+	}
+}
+
+func (h faultyHandler[T]) PostHandle(ctx context.Context, logger log.Logger, record T) {
+	if p, ok := h.Handler.(workerutil.WithHooks[T]); ok {
+		p.PostHandle(ctx, logger, record)
+	}
+}
+
+// faultyWorkerStore drops heartbeats and leaves records processing instead of
+// marking them as completed.
+type faultyWorkerStore[T workerutil.Record] struct {
+	dbworkerstore.Store[T]
+	faults *faults
+}
+
+// Heartbeat reports all ids as known, since the worker would cancel them
+// otherwise. The records only look stalled if enough heartbeats in a row are
+// lost.
+func (s faultyWorkerStore[T]) Heartbeat(ctx context.Context, ids []string, options dbworkerstore.HeartbeatOptions) (knownIDs, cancelIDs []string, err error) {
+	if s.faults.inject(s.faults.DropHeartbeat) {
+		return ids, nil, nil
+	}
+	return s.Store.Heartbeat(ctx, ids, options)
+}
+
+// MarkComplete reports that the record isn't processing anymore, like the
+// worker would see it after a restart.
+func (s faultyWorkerStore[T]) MarkComplete(ctx context.Context, id int, options dbworkerstore.MarkFinalOptions) (bool, error) {
+	if s.faults.inject(s.faults.Crash) {
+		return false, nil
+	}
+	return s.Store.MarkComplete(ctx, id, options)
+}
+
+// faultyUploadStore fails uploads after it read part of them.
+type faultyUploadStore struct {
+	uploadstore.Store
+	faults *faults
+}
+
+func (s faultyUploadStore) Upload(ctx context.Context, key string, r io.Reader) (int64, error) {
+	if !s.faults.inject(s.faults.UploadFailure) {
+		return s.Store.Upload(ctx, key, r)
+	}
+	// The writer blocks until we read what it wrote, so it fails in the
+	// middle of the results.
+	n, err := io.CopyN(io.Discard, r, 64)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, err
+	}
+	return n, errInjectedUploadFailure
+}
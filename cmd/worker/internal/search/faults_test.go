@@ -0,0 +1,117 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbfixture"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+func TestExhaustiveSearch_Faults(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	require := require.New(t)
+	env := newTestEnv(t)
+
+	// 8 repositories with 4 revisions each, every revision has one result.
+	var revs []string
+	var want [][]string
+	for repo := 1; repo <= 8; repo++ {
+		name := fmt.Sprintf("repo%d", repo)
+		dbfixture.Repo(t, env.db, dbfixture.WithRepoID(api.RepoID(repo)), dbfixture.WithRepoName(name))
+		for rev := 1; rev <= 4; rev++ {
+			revs = append(revs, fmt.Sprintf("%d@rev%d", repo, rev))
+			want = append(want, []string{name, strconv.Itoa(repo), fmt.Sprintf("rev%d", rev), fmt.Sprintf("rev%d", rev), "path/to/file.go", "", ""})
+		}
+	}
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, strings.Join(revs, " "), service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// Every record is likely to run into a fault, and some run into several.
+	// Heartbeats are dropped independently, so a record practically never
+	// misses enough of them in a row to stall.
+	seed := rand.Uint64()
+	t.Logf("fault seed: %d", seed)
+	cfg := testConfig(4)
+	cfg.MaxRevisionAttempts = 20
+	cfg.Faults = &faults{
+		HandlerDelay:    0.3,
+		MaxHandlerDelay: 50 * time.Millisecond,
+		UploadFailure:   0.2,
+		Crash:           0.1,
+		DropHeartbeat:   0.3,
+		Seed:            seed,
+	}
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   cfg,
+	}
+
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 60*time.Second), 10*time.Millisecond)
+
+	// All tasks completed once, despite the retries and resets.
+	stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(&types.RepoRevJobStats{Total: 41, Completed: 41}, stats) // 1 search job + 8 repo jobs + 32 repo rev jobs
+
+	job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(types.JobStateCompleted, job2.State)
+
+	// No result is missing or duplicated.
+	writerTo, err := env.svc.GetSearchJobResultsWriterTo(env.userCtx, job.ID, service.ResultFormatCSV)
+	require.NoError(err)
+	var buf bytes.Buffer
+	_, err = writerTo.WriteTo(&buf)
+	require.NoError(err)
+	got := parseCSV(t, buf.String())
+	require.Equal(types.ResultsColumns, got[0])
+	got = got[1:]
+	sort.Slice(got, func(i, j int) bool {
+		return strings.Join(got[i], ",") < strings.Join(got[j], ",")
+	})
+	require.Equal(want, got)
+}
+
+func TestSearchJob_InvalidFaults(t *testing.T) {
+	cfg := testConfig(1)
+	// Workers which always crash would never finish a record.
+	cfg.Faults = &faults{Crash: 1}
+	searchJob := &searchJob{config: cfg}
+
+	_, err := searchJob.newSearchJobRoutines(context.Background(), observation.TestContextTB(t), nil, nil)
+	require.ErrorContains(t, err, "fault injection: Crash must be at least 0 and less than 1")
+}
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+// gracefulWorker stops its worker within gracePeriod, for example when the
+// pod is terminated during a deploy. Stop stops dequeuing and gives the
+// handlers in flight gracePeriod to finish. Then it calls abandon, which
+// cancels the handlers that are still running, so they put their records back
+// into the queue instead of leaving them in processing until the resetter
+// finds them.
+type gracefulWorker[T workerutil.Record] struct {
+	*workerutil.Worker[T]
+
+	gracePeriod time.Duration
+	abandon     context.CancelFunc
+
+	once sync.Once
+	err  error
+}
+
+// newGracefulWorker wraps worker. abandon must cancel the context the handlers
+// of worker pass to withAbandon.
+func newGracefulWorker[T workerutil.Record](worker *workerutil.Worker[T], gracePeriod time.Duration, abandon context.CancelFunc) *gracefulWorker[T] {
+	return &gracefulWorker[T]{
+		Worker:      worker,
+		gracePeriod: gracePeriod,
+		abandon:     abandon,
+	}
+}
+
+// Stop stops the worker. It returns once all handlers returned, which takes at
+// most the grace period and the time the handlers need to hand back their
+// records, or once ctx is done. Further calls return the result of the first.
+func (w *gracefulWorker[T]) Stop(ctx context.Context) error {
+	w.once.Do(func() {
+		stopped := make(chan error, 1)
+		go func() { stopped <- w.Worker.Stop(ctx) }()
+
+		timer := time.NewTimer(w.gracePeriod)
+		defer timer.Stop()
+
+		select {
+		case w.err = <-stopped:
+			return
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+
+		w.abandon()
+		select {
+		case w.err = <-stopped:
+		case <-ctx.Done():
+			w.err = ctx.Err()
+		}
+	})
+	return w.err
+}
+
+// withAbandon returns a context which is canceled once ctx or abandoned is
+// done. Handlers call it with the context their gracefulWorker cancels.
+func withAbandon(ctx, abandoned context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(abandoned, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,100 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+func TestExhaustiveSearch_Shutdown(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa"))
+
+	_, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	config := testConfig(1)
+	config.ShutdownGracePeriod = 50 * time.Millisecond
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   config,
+	}
+
+	// The search writes its results, but doesn't finish before the worker
+	// stops.
+	started := make(chan struct{}, 1)
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return blockingNewSearcher{NewSearcher: service.NewSearcherFake(), started: started}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+	}
+
+	select {
+	case <-started:
+	case <-time.After(tTimeout(t, 10*time.Second)):
+		t.Fatal("the search of the revision didn't start")
+	}
+
+	// Stop doesn't wait for the search, and can be called again.
+	start := time.Now()
+	for _, routine := range routines {
+		require.NoError(routine.Stop(context.Background()))
+	}
+	require.Less(time.Since(start), 5*time.Second)
+	revisionWorker := routines[2]
+	require.NoError(revisionWorker.Stop(context.Background()))
+
+	// The revision is back in the queue without a failed attempt, and its
+	// partial results were discarded.
+	var state string
+	var numFailures int
+	err = env.db.QueryRowContext(env.workerCtx, "SELECT state, num_failures FROM exhaustive_search_repo_revision_jobs").Scan(&state, &numFailures)
+	require.NoError(err)
+	require.Equal("queued", state)
+	require.Zero(numFailures)
+	require.Empty(env.bucket)
+
+	values := gatherMetrics(t, env.observationCtx.Registerer.(prometheus.Gatherer))
+	require.Equal(1.0, values["src_search_jobs_tasks_total{outcome=handed_back}"])
+}
+
+// blockingNewSearcher returns searches which write their results and then
+// block until they are canceled. started receives a value for every search.
+type blockingNewSearcher struct {
+	service.NewSearcher
+	started chan<- struct{}
+}
+
+func (b blockingNewSearcher) NewSearch(ctx context.Context, userID int32, q string) (service.SearchQuery, error) {
+	sq, err := b.NewSearcher.NewSearch(ctx, userID, q)
+	if err != nil {
+		return nil, err
+	}
+	return blockingSearchQuery{SearchQuery: sq, started: b.started}, nil
+}
+
+type blockingSearchQuery struct {
+	service.SearchQuery
+	started chan<- struct{}
+}
+
+func (b blockingSearchQuery) Search(ctx context.Context, repoRev types.RepositoryRevision, w service.MatchWriter) error {
+	if err := b.SearchQuery.Search(ctx, repoRev, w); err != nil {
+		return err
+	}
+	b.started <- struct{}{}
+	<-ctx.Done()
+	return ctx.Err()
+}
@@ -0,0 +1,337 @@
+package search
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbfixture"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// hookNewSearcher wraps a NewSearcher so that hook is called before every
+// search. If hook returns an error the search is skipped.
+type hookNewSearcher struct {
+	service.NewSearcher
+	hook func(context.Context, types.RepositoryRevision) error
+}
+
+func (h hookNewSearcher) NewSearch(ctx context.Context, userID int32, q string) (service.SearchQuery, error) {
+	sq, err := h.NewSearcher.NewSearch(ctx, userID, q)
+	if err != nil {
+		return nil, err
+	}
+	return hookSearchQuery{SearchQuery: sq, hook: h.hook}, nil
+}
+
+type hookSearchQuery struct {
+	service.SearchQuery
+	hook func(context.Context, types.RepositoryRevision) error
+}
+
+func (h hookSearchQuery) Search(ctx context.Context, repoRev types.RepositoryRevision, w service.MatchWriter) error {
+	if err := h.hook(ctx, repoRev); err != nil {
+		return err
+	}
+	return h.SearchQuery.Search(ctx, repoRev, w)
+}
+
+// parseCSV parses s with encoding/csv, so quoted values are validated as
+// well.
+func parseCSV(t *testing.T, s string) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(strings.NewReader(s)).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+// gatherMetrics returns the values of the metrics in g keyed by their name and
+// labels, e.g. "name{a=1,b=2}". Histograms have a "_count" and "_sum" entry.
+func gatherMetrics(t *testing.T, g prometheus.Gatherer) map[string]float64 {
+	t.Helper()
+
+	families, err := g.Gather()
+	require.NoError(t, err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			key := family.GetName()
+			if len(m.GetLabel()) > 0 {
+				var labels []string
+				for _, l := range m.GetLabel() {
+					labels = append(labels, l.GetName()+"="+l.GetValue())
+				}
+				key += "{" + strings.Join(labels, ",") + "}"
+			}
+
+			switch {
+			case m.GetCounter() != nil:
+				values[key] = m.GetCounter().GetValue()
+			case m.GetGauge() != nil:
+				values[key] = m.GetGauge().GetValue()
+			case m.GetHistogram() != nil:
+				values[key+"_count"] = float64(m.GetHistogram().GetSampleCount())
+				values[key+"_sum"] = m.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	return values
+}
+
+// testConfig returns the config of a searchJob which polls for work often and
+// runs concurrency handlers in each worker.
+func testConfig(concurrency int) config {
+	return config{
+		WorkerInterval:     10 * time.Millisecond,
+		HeartbeatInterval:  10 * time.Millisecond,
+		NumJobWorkers:      concurrency,
+		NumRepoWorkers:     concurrency,
+		NumRevisionWorkers: concurrency,
+
+		MaxRevisionAttempts: 3,
+		RetryBackoff:        time.Millisecond,
+
+		StalledMaxAge:    time.Second,
+		ResetterInterval: 10 * time.Millisecond,
+
+		ShutdownGracePeriod: time.Second,
+
+		WorkerHostname:  testWorkerHostname,
+		WorkerStartedAt: testWorkerStartedAt,
+	}
+}
+
+// testWorkerHostname and testWorkerStartedAt identify the workers of the tests
+// on the tasks they claim.
+var (
+	testWorkerHostname  = "test-worker"
+	testWorkerStartedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+)
+
+// tTimeout returns the duration until t's deadline. If there is no deadline
+// or the deadline is further away than max, then max is returned.
+func tTimeout(t *testing.T, max time.Duration) time.Duration {
+	deadline, ok := t.Deadline()
+	if !ok {
+		return max
+	}
+	timeout := time.Until(deadline)
+	if max < timeout {
+		return max
+	}
+	return timeout
+}
+
+// blobContents returns the decompressed content of the result blobs in
+// bucket.
+func blobContents(t *testing.T, bucket map[string]string) []string {
+	t.Helper()
+
+	var contents []string
+	for key, v := range bucket {
+		if !strings.HasSuffix(key, ".gz") {
+			contents = append(contents, v)
+			continue
+		}
+		zr, err := gzip.NewReader(strings.NewReader(v))
+		require.NoError(t, err)
+		b, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		contents = append(contents, string(b))
+	}
+	return contents
+}
+
+func newMockUploadStore(t testing.TB) (*mocks.MockStore, map[string]string) {
+	t.Helper()
+
+	// Each entry in bucket corresponds to one 1 uploaded csv file.
+	mu := sync.Mutex{}
+	bucket := make(map[string]string)
+
+	mockStore := mocks.NewMockStore()
+	mockStore.UploadFunc.SetDefaultHook(func(ctx context.Context, key string, r io.Reader) (int64, error) {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return 0, err
+		}
+
+		mu.Lock()
+		bucket[key] = string(b)
+		mu.Unlock()
+
+		return int64(len(b)), nil
+	})
+
+	mockStore.GetFunc.SetDefaultHook(func(ctx context.Context, key string) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return io.NopCloser(strings.NewReader(bucket[key])), nil
+	})
+
+	mockStore.DeleteFunc.SetDefaultHook(func(ctx context.Context, key string) error {
+		mu.Lock()
+		delete(bucket, key)
+		mu.Unlock()
+
+		return nil
+	})
+
+	mockStore.ListFunc.SetDefaultHook(func(ctx context.Context, prefix string) (*iterator.Iterator[string], error) {
+		var keys []string
+		mu.Lock()
+		for k := range bucket {
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
+		}
+		mu.Unlock()
+		return iterator.From(keys), nil
+	})
+
+	return mockStore, bucket
+}
+
+// testEnv is the setup that the tests of the workers share: search jobs are
+// enabled, and the database has the user alice and the repositories of
+// withRepos. The contexts are canceled once the test is done.
+type testEnv struct {
+	observationCtx *observation.Context
+	logger         log.Logger
+	db             database.DB
+	store          *store.Store
+	svc            *service.Service
+
+	// uploadStore keeps the uploaded blobs in bucket, by key.
+	uploadStore *mocks.MockStore
+	bucket      map[string]string
+
+	// userID is the ID of alice, who userCtx acts as.
+	userID    int32
+	userCtx   context.Context
+	workerCtx context.Context
+}
+
+type testEnvOptions struct {
+	repos      []api.RepoName
+	siteConfig schema.SiteConfiguration
+	logger     log.Logger
+	clock      glock.Clock
+	parallel   bool
+}
+
+type testEnvOption func(*testEnvOptions)
+
+// withRepos creates repositories with the given names, and IDs counting up
+// from 1.
+func withRepos(names ...api.RepoName) testEnvOption {
+	return func(o *testEnvOptions) { o.repos = names }
+}
+
+// withSiteConfig mocks the site config, on top of which search jobs are
+// enabled.
+func withSiteConfig(c schema.SiteConfiguration) testEnvOption {
+	return func(o *testEnvOptions) { o.siteConfig = c }
+}
+
+// withLogger makes the service and the database log to logger, e.g. to
+// capture the logs with logtest.Captured.
+func withLogger(logger log.Logger) testEnvOption {
+	return func(o *testEnvOptions) { o.logger = logger }
+}
+
+// withClock makes the store read the time from clock.
+func withClock(clock glock.Clock) testEnvOption {
+	return func(o *testEnvOptions) { o.clock = clock }
+}
+
+// withParallelDB is for tests that run in parallel. They use a database of
+// their own, and the site config isn't mocked, since it is global: the caller
+// has to enable search jobs once for all of them.
+func withParallelDB() testEnvOption {
+	return func(o *testEnvOptions) { o.parallel = true }
+}
+
+// newTestEnv sets up the database, the store and the service of a test, with
+// the user alice and the repos of withRepos. The contexts are canceled when the
+// test finishes.
+func newTestEnv(t *testing.T, opts ...testEnvOption) *testEnv {
+	t.Helper()
+
+	var o testEnvOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if !o.parallel {
+		enabled := true
+		o.siteConfig.ExperimentalFeatures = &schema.ExperimentalFeatures{SearchJobs: &enabled}
+		conf.Mock(&conf.Unified{SiteConfiguration: o.siteConfig})
+		t.Cleanup(func() { conf.Mock(nil) })
+	}
+
+	observationCtx := observation.TestContextTB(t)
+	if o.logger != nil {
+		observationCtx = observation.ContextWithLogger(o.logger, observationCtx)
+	}
+	logger := observationCtx.Logger
+
+	var db database.DB
+	if o.parallel {
+		db = database.NewDB(logger, dbtest.NewParallelDB(t, dbtest.WithLeakDetection()))
+	} else {
+		db = database.NewDB(logger, dbtest.NewDB(t))
+	}
+	s := store.New(db, observation.TestContextTB(t))
+	if o.clock != nil {
+		s = store.NewWithClock(db, observation.TestContextTB(t), o.clock)
+	}
+	uploadStore, bucket := newMockUploadStore(t)
+	svc := service.New(observationCtx, s, uploadStore, service.NewSearcherFake())
+
+	userID := dbfixture.User(t, db, dbfixture.WithUsername("alice")).ID
+	for i, name := range o.repos {
+		dbfixture.Repo(t, db, dbfixture.WithRepoID(api.RepoID(i+1)), dbfixture.WithRepoName(name))
+	}
+
+	workerCtx, cancel := context.WithCancel(actor.WithInternalActor(context.Background()))
+	t.Cleanup(cancel)
+	userCtx, cancel := context.WithCancel(actor.WithActor(context.Background(), actor.FromUser(userID)))
+	t.Cleanup(cancel)
+
+	return &testEnv{
+		observationCtx: observationCtx,
+		logger:         logger,
+		db:             db,
+		store:          s,
+		svc:            svc,
+		uploadStore:    uploadStore,
+		bucket:         bucket,
+		userID:         userID,
+		userCtx:        userCtx,
+		workerCtx:      workerCtx,
+	}
+}
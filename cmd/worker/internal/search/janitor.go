@@ -0,0 +1,105 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+)
+
+const (
+	janitorInterval = 1 * time.Hour
+
+	// janitorBatchSize is the number of jobs the janitor deletes at once. Every
+	// job is deleted on its own, so this only bounds the size of the list
+	// query.
+	janitorBatchSize = 100
+
+	// incompleteUploadMaxAge is how old an upload of results has to be before
+	// the janitor considers it abandoned. Uploads take as long as the search
+	// of a revision, which is much shorter.
+	incompleteUploadMaxAge = 24 * time.Hour
+)
+
+type janitor struct {
+	logger    log.Logger
+	svc       *service.Service
+	retention time.Duration
+	clock     glock.Clock
+
+	deletedCounter prometheus.Counter
+}
+
+// newJanitor returns a background routine which deletes search jobs and their
+// results once they are older than retention. It also releases expired
+// idempotency keys and cleans up the uploads of results which were abandoned by
+// workers.
+func newJanitor(ctx context.Context, observationCtx *observation.Context, svc *service.Service, retention time.Duration, clock glock.Clock) goroutine.BackgroundRoutine {
+	j := &janitor{
+		logger:    observationCtx.Logger.Scoped("janitor"),
+		svc:       svc,
+		retention: retention,
+		clock:     clock,
+		deletedCounter: promauto.With(observationCtx.Registerer).NewCounter(prometheus.CounterOpts{
+			Name: "src_search_jobs_janitor_deleted_total",
+			Help: "Total number of expired search jobs deleted by the janitor.",
+		}),
+	}
+
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		j,
+		goroutine.WithName("exhaustive_search_janitor"),
+		goroutine.WithDescription("deletes expired search jobs and their results"),
+		goroutine.WithInterval(janitorInterval),
+	)
+}
+
+func (j *janitor) Handle(ctx context.Context) error {
+	before := j.clock.Now().Add(-j.retention)
+
+	total := 0
+	for {
+		deleted, err := j.svc.DeleteExpiredSearchJobs(ctx, before, janitorBatchSize)
+		total += deleted
+		j.deletedCounter.Add(float64(deleted))
+		if err != nil {
+			j.logger.Error("failed to delete expired search jobs", log.Int("deleted", total), log.Error(err))
+			return err
+		}
+		if deleted < janitorBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		j.logger.Info("deleted expired search jobs", log.Int("deleted", total))
+	}
+
+	// Expired idempotency keys are released so their users can use them
+	// again. Keys of deleted jobs are gone with their jobs.
+	released, err := j.svc.ReleaseExpiredIdempotencyKeys(ctx, j.clock.Now())
+	if err != nil {
+		j.logger.Error("failed to release expired idempotency keys", log.Error(err))
+		return err
+	}
+	if released > 0 {
+		j.logger.Info("released expired idempotency keys", log.Int("released", released))
+	}
+
+	// Workers abort the uploads of failed searches, but not if they die while
+	// uploading.
+	if err := j.svc.AbortIncompleteUploads(ctx, incompleteUploadMaxAge); err != nil {
+		j.logger.Error("failed to abort incomplete uploads", log.Error(err))
+		return err
+	}
+
+	return nil
+}
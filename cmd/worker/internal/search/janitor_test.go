@@ -0,0 +1,54 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+)
+
+func TestJanitor(t *testing.T) {
+	require := require.New(t)
+	clock := glock.NewMockClockAt(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	env := newTestEnv(t, withClock(clock))
+
+	// The old job is still queued, so the janitor has to cancel it first.
+	oldJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	clock.Advance(31 * 24 * time.Hour)
+	recentJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	for _, id := range []int64{oldJob.ID, recentJob.ID} {
+		_, err := env.uploadStore.Upload(env.workerCtx, fmt.Sprintf("%d-1", id), strings.NewReader("result\n"))
+		require.NoError(err)
+	}
+
+	j := &janitor{
+		logger:         env.logger,
+		svc:            env.svc,
+		retention:      30 * 24 * time.Hour,
+		clock:          clock,
+		deletedCounter: prometheus.NewCounter(prometheus.CounterOpts{Name: "test"}),
+	}
+	err = j.Handle(env.workerCtx)
+	require.NoError(err)
+
+	_, err = env.svc.GetSearchJob(env.userCtx, oldJob.ID)
+	require.ErrorIs(err, store.ErrNoResults)
+	_, err = env.svc.GetSearchJob(env.userCtx, recentJob.ID)
+	require.NoError(err)
+
+	require.Equal(map[string]string{fmt.Sprintf("%d-1", recentJob.ID): "result\n"}, env.bucket)
+
+	// Only internal actors may list the jobs of all users.
+	_, err = env.store.ListExpiredExhaustiveSearchJobIDs(env.userCtx, clock.Now(), janitorBatchSize)
+	require.Error(err)
+}
@@ -5,6 +5,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/derision-test/glock"
+	"github.com/sourcegraph/log"
+
 	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
 	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
@@ -12,18 +15,168 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/hostname"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/search/client"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
-// config stores shared config we can override in each worker. We don't expose
-// it as an env.Config since we currently only use it for testing.
+// config stores shared config we can override in each worker. The values read
+// from the environment are loaded by envConfig.
 type config struct {
 	// WorkerInterval sets WorkerOptions.Interval for every worker
 	WorkerInterval time.Duration
+
+	// HeartbeatInterval sets WorkerOptions.HeartbeatInterval for every
+	// worker. It also bounds how long a canceled job keeps running, since
+	// workers learn about cancellation on their heartbeat.
+	HeartbeatInterval time.Duration
+
+	// RetentionPeriod is how long search jobs and their results are kept
+	// before the janitor deletes them. The janitor is disabled if it is 0.
+	RetentionPeriod time.Duration
+
+	// NumJobWorkers, NumRepoWorkers and NumRevisionWorkers set
+	// WorkerOptions.NumHandlers of the search job, repo job and repo revision
+	// job workers. The repo revision workers run the searches, so
+	// NumRevisionWorkers bounds the load on searcher and gitserver.
+	NumJobWorkers      int
+	NumRepoWorkers     int
+	NumRevisionWorkers int
+
+	// BackendRequestsPerSecond and BackendBurst rate limit the searches and
+	// revision resolutions of all workers of a process, so search jobs don't
+	// degrade interactive search. Handlers wait until they may call gitserver
+	// and searcher. The rate is unlimited if BackendRequestsPerSecond is 0.
+	BackendRequestsPerSecond float64
+	BackendBurst             int
+
+	// MaxRevisionsPerJob is how many revisions of the same job are searched
+	// concurrently in a process, so a large job doesn't take all revision
+	// workers. Further revisions of the job are put back into the queue. It
+	// is unlimited if 0.
+	MaxRevisionsPerJob int
+
+	// MaxRevisionsPerCodeHost is how many revisions of repositories of the
+	// same code host are searched concurrently in a process, so a slow code
+	// host doesn't take all revision workers. CodeHostMaxRevisions overrides
+	// it for the code hosts it has a ceiling for, keyed by their external
+	// service ID, for example "https://github.com/". Revisions of a code host
+	// stay in the queue while it's at its ceiling, and the workers take turns
+	// between the other code hosts. A ceiling of 0 is unlimited.
+	MaxRevisionsPerCodeHost int
+	CodeHostMaxRevisions    map[string]int
+
+	// MaxLogLinesPerJob caps the number of lines the workers write to the log
+	// of a job. It is unlimited if 0.
+	MaxLogLinesPerJob int
+
+	// MaxRevisionAttempts is how often the search of a revision is attempted
+	// before it fails because of transient errors.
+	MaxRevisionAttempts int
+
+	// RetryBackoff is the delay before the search of a revision which failed
+	// with a transient error is retried. It doubles with every attempt.
+	RetryBackoff time.Duration
+
+	// TaskBatchSize is how many revisions of repositories of the same code
+	// host which are at most TaskBatchMaxRepoSizeBytes large are searched as
+	// one task, so that the workers of jobs over many tiny repositories don't
+	// spend more time on the queue than on the searches. A batch which isn't
+	// full is searched once TaskBatchFillTimeout passed. Revisions are
+	// searched one by one if TaskBatchSize is below 2.
+	TaskBatchSize             int
+	TaskBatchMaxRepoSizeBytes int64
+	TaskBatchFillTimeout      time.Duration
+
+	// StalledMaxAge is how long a record may go without a heartbeat before
+	// the resetters assume its worker died and requeue it. A record which
+	// stalled too often is marked as failed.
+	StalledMaxAge time.Duration
+
+	// ResetterInterval is how often the resetters look for stalled records.
+	ResetterInterval time.Duration
+
+	// ShutdownGracePeriod is how long the handlers in flight may run once the
+	// workers stop. Revisions which are still searched afterwards are put
+	// back into the queue.
+	ShutdownGracePeriod time.Duration
+
+	// Clock is consulted by the store, the handlers and the janitor for the
+	// current time. It is the real clock if nil.
+	Clock glock.Clock
+
+	// WorkerHostname and WorkerStartedAt identify this process on the repo
+	// revision jobs it claims, so we can tell which worker processed a task.
+	// They default to the hostname, that is the pod name, and the time of Clock
+	// when the workers are set up, which is right after the process started.
+	WorkerHostname  string
+	WorkerStartedAt time.Time
+
+	// Faults injects failures into the search job, repo job and repo revision
+	// job workers if it is non-nil. Only tests set it.
+	Faults *faults
+}
+
+// validate returns an error if the workers can't be started with c.
+func (c config) validate() error {
+	for _, n := range []struct {
+		name  string
+		value int
+	}{
+		{"SEARCH_JOBS_NUM_JOB_WORKERS", c.NumJobWorkers},
+		{"SEARCH_JOBS_NUM_REPO_WORKERS", c.NumRepoWorkers},
+		{"SEARCH_JOBS_NUM_REVISION_WORKERS", c.NumRevisionWorkers},
+		{"SEARCH_JOBS_MAX_REVISION_ATTEMPTS", c.MaxRevisionAttempts},
+	} {
+		if n.value < 1 {
+			return errors.Newf("%s must be at least 1, got %d", n.name, n.value)
+		}
+	}
+
+	if c.BackendRequestsPerSecond < 0 {
+		return errors.Newf("SEARCH_JOBS_BACKEND_REQUESTS_PER_SECOND must not be negative, got %v", c.BackendRequestsPerSecond)
+	}
+	if c.BackendRequestsPerSecond > 0 && c.BackendBurst < 1 {
+		return errors.Newf("SEARCH_JOBS_BACKEND_BURST must be at least 1, got %d", c.BackendBurst)
+	}
+	if c.MaxRevisionsPerJob < 0 {
+		return errors.Newf("SEARCH_JOBS_MAX_REVISIONS_PER_JOB must not be negative, got %d", c.MaxRevisionsPerJob)
+	}
+	if c.MaxRevisionsPerCodeHost < 0 {
+		return errors.Newf("SEARCH_JOBS_MAX_REVISIONS_PER_CODE_HOST must not be negative, got %d", c.MaxRevisionsPerCodeHost)
+	}
+	for codeHost, n := range c.CodeHostMaxRevisions {
+		if n < 0 {
+			return errors.Newf("SEARCH_JOBS_CODE_HOST_MAX_REVISIONS must not be negative, got %d for %q", n, codeHost)
+		}
+	}
+	if c.TaskBatchSize < 0 {
+		return errors.Newf("SEARCH_JOBS_TASK_BATCH_SIZE must not be negative, got %d", c.TaskBatchSize)
+	}
+	if c.TaskBatchSize > 1 && c.TaskBatchFillTimeout <= 0 {
+		return errors.Newf("SEARCH_JOBS_TASK_BATCH_FILL_TIMEOUT must be positive, got %s", c.TaskBatchFillTimeout)
+	}
+	if c.MaxLogLinesPerJob < 0 {
+		return errors.Newf("SEARCH_JOBS_MAX_LOG_LINES_PER_JOB must not be negative, got %d", c.MaxLogLinesPerJob)
+	}
+	if c.ShutdownGracePeriod < 0 {
+		return errors.Newf("SEARCH_JOBS_SHUTDOWN_GRACE_PERIOD must not be negative, got %s", c.ShutdownGracePeriod)
+	}
+
+	// The resetters only look at whole seconds. Records of healthy workers
+	// must not look stalled between two heartbeats.
+	if c.StalledMaxAge < time.Second || c.StalledMaxAge <= c.HeartbeatInterval {
+		return errors.Newf("SEARCH_JOBS_STALLED_MAX_AGE must be at least 1s and longer than the heartbeat interval %s, got %s", c.HeartbeatInterval, c.StalledMaxAge)
+	}
+	if c.Faults != nil {
+		return c.Faults.validate()
+	}
+	return nil
 }
 
 type searchJob struct {
@@ -33,20 +186,18 @@ type searchJob struct {
 	// for testing
 	workerDB database.DB
 
-	once         sync.Once
-	err          error
-	workerStores []interface {
-		QueuedCount(context.Context, bool) (int, error)
-	}
+	// notifier if non-nil is used instead of sending emails and webhooks. Used
+	// for testing
+	notifier notifier
+
+	once    sync.Once
+	err     error
+	store   *store.Store
 	workers []goroutine.BackgroundRoutine
 }
 
 func NewSearchJob() job.Job {
-	return &searchJob{
-		config: config{
-			WorkerInterval: 1 * time.Second,
-		},
-	}
+	return &searchJob{}
 }
 
 func (j *searchJob) Description() string {
@@ -54,10 +205,12 @@ func (j *searchJob) Description() string {
 }
 
 func (j *searchJob) Config() []env.Config {
-	return []env.Config{uploadstore.ConfigInst}
+	return []env.Config{uploadstore.ConfigInst, envConfigInst}
 }
 
 func (j *searchJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	j.config = envConfigInst.workerConfig()
+
 	workCtx := actor.WithInternalActor(context.Background())
 
 	uploadStore, err := uploadstore.New(workCtx, observationCtx, uploadstore.ConfigInst)
@@ -81,6 +234,19 @@ func (j *searchJob) newSearchJobRoutines(
 	newSearcherFactory func(*observation.Context, database.DB) service.NewSearcher,
 ) ([]goroutine.BackgroundRoutine, error) {
 	j.once.Do(func() {
+		if j.err = j.config.validate(); j.err != nil {
+			return
+		}
+		if j.config.Clock == nil {
+			j.config.Clock = glock.NewRealClock()
+		}
+		if j.config.WorkerHostname == "" {
+			j.config.WorkerHostname = hostname.Get()
+		}
+		if j.config.WorkerStartedAt.IsZero() {
+			j.config.WorkerStartedAt = j.config.Clock.Now()
+		}
+
 		db := j.workerDB
 		if db == nil {
 			db, j.err = workerdb.InitDB(observationCtx)
@@ -91,32 +257,57 @@ func (j *searchJob) newSearchJobRoutines(
 
 		newSearcher := newSearcherFactory(observationCtx, db)
 
-		exhaustiveSearchStore := store.New(db, observationCtx)
+		exhaustiveSearchStore := store.NewWithClock(db, observationCtx, j.config.Clock)
 
-		searchWorkerStore := store.NewExhaustiveSearchJobWorkerStore(observationCtx, db.Handle())
-		repoWorkerStore := store.NewRepoSearchJobWorkerStore(observationCtx, db.Handle())
-		revWorkerStore := store.NewRevSearchJobWorkerStore(observationCtx, db.Handle())
+		searchWorkerStore := store.NewExhaustiveSearchJobWorkerStore(observationCtx, db.Handle(), j.config.StalledMaxAge)
+		repoWorkerStore := store.NewRepoSearchJobWorkerStore(observationCtx, db.Handle(), j.config.StalledMaxAge)
+		revWorkerStore := store.NewRevSearchJobWorkerStore(observationCtx, db.Handle(), j.config.StalledMaxAge)
+		notificationWorkerStore := store.NewNotificationWorkerStore(observationCtx, db.Handle(), j.config.StalledMaxAge)
 
-		j.workerStores = append(j.workerStores,
-			searchWorkerStore,
-			repoWorkerStore,
-			revWorkerStore,
-		)
+		j.store = exhaustiveSearchStore
+
+		registerQueueMetrics(observationCtx, "job", searchWorkerStore)
+		registerQueueMetrics(observationCtx, "repo", repoWorkerStore)
+		registerQueueMetrics(observationCtx, "revision", revWorkerStore)
+		registerQueueMetrics(observationCtx, "notification", notificationWorkerStore)
+		metrics := newMetrics(observationCtx)
+		limiter := newLoadLimiter(j.config)
+
+		notifier := j.notifier
+		if notifier == nil {
+			notifier = &defaultNotifier{db: db, client: httpcli.UncachedExternalClient}
+		}
 
 		observationCtx = observation.ContextWithLogger(
 			observationCtx.Logger.Scoped("routines"),
 			observationCtx,
 		)
 
+		svc := service.New(observationCtx, exhaustiveSearchStore, uploadStore, newSearcher)
+
+		// The workers only start once we return, so nothing is processing
+		// here yet, except on other worker processes.
+		if err := recoverStalledSearchJobs(workCtx, observationCtx.Logger, exhaustiveSearchStore, j.config.StalledMaxAge,
+			searchWorkerStore, repoWorkerStore, revWorkerStore, notificationWorkerStore); err != nil {
+			// The resetters requeue the stalled records eventually.
+			observationCtx.Logger.Error("failed to recover stalled search jobs", log.Error(err))
+		}
+
 		j.workers = []goroutine.BackgroundRoutine{
 			newExhaustiveSearchWorker(workCtx, observationCtx, searchWorkerStore, exhaustiveSearchStore, newSearcher, j.config),
-			newExhaustiveSearchRepoWorker(workCtx, observationCtx, repoWorkerStore, exhaustiveSearchStore, newSearcher, j.config),
-			newExhaustiveSearchRepoRevisionWorker(workCtx, observationCtx, revWorkerStore, exhaustiveSearchStore, newSearcher, uploadStore, j.config),
+			newExhaustiveSearchRepoWorker(workCtx, observationCtx, repoWorkerStore, exhaustiveSearchStore, newSearcher, limiter, j.config),
+			newExhaustiveSearchRepoRevisionWorker(workCtx, observationCtx, revWorkerStore, exhaustiveSearchStore, newSearcher, uploadStore, metrics, limiter, j.config),
+			newExhaustiveSearchNotificationWorker(workCtx, observationCtx, notificationWorkerStore, exhaustiveSearchStore, svc, notifier, j.config),
 
 			// resetters
-			newExhaustiveSearchWorkerResetter(observationCtx, searchWorkerStore),
-			newExhaustiveSearchRepoWorkerResetter(observationCtx, repoWorkerStore),
-			newExhaustiveSearchRepoRevisionWorkerResetter(observationCtx, revWorkerStore),
+			newExhaustiveSearchWorkerResetter(observationCtx, searchWorkerStore, j.config),
+			newExhaustiveSearchRepoWorkerResetter(observationCtx, repoWorkerStore, j.config),
+			newExhaustiveSearchRepoRevisionWorkerResetter(observationCtx, revWorkerStore, j.config),
+			newExhaustiveSearchNotificationWorkerResetter(observationCtx, notificationWorkerStore, j.config),
+		}
+
+		if j.config.RetentionPeriod > 0 {
+			j.workers = append(j.workers, newJanitor(workCtx, observationCtx, svc, j.config.RetentionPeriod, j.config.Clock))
 		}
 	})
 
@@ -124,12 +315,9 @@ func (j *searchJob) newSearchJobRoutines(
 }
 
 // hasWork returns true if any of the workers have work in its queue or is
-// processing something. This is only exposed for tests.
+// processing something. This is only exposed for tests. ctx must be an
+// internal actor or a site admin.
 func (j *searchJob) hasWork(ctx context.Context) bool {
-	for _, w := range j.workerStores {
-		if count, _ := w.QueuedCount(ctx, true); count > 0 {
-			return true
-		}
-	}
-	return false
+	backlog, err := j.store.GetSearchJobsBacklog(ctx)
+	return err == nil && backlog.Total() > 0
 }
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grafana/regexp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestSearchJob_InvalidConfig(t *testing.T) {
+	cfg := testConfig(1)
+	cfg.NumRevisionWorkers = 0
+	searchJob := &searchJob{config: cfg}
+
+	// The config is validated before the database is used.
+	_, err := searchJob.newSearchJobRoutines(context.Background(), observation.TestContextTB(t), nil, nil)
+	require.ErrorContains(t, err, "SEARCH_JOBS_NUM_REVISION_WORKERS must be at least 1")
+}
+
+func TestNoDirectTimeNow(t *testing.T) {
+	directTimeNow := regexp.MustCompile(`\btime\.(Now|Since|Until)\(`)
+
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		b, err := os.ReadFile(file)
+		require.NoError(t, err)
+		for i, line := range strings.Split(string(b), "\n") {
+			if directTimeNow.MatchString(line) {
+				t.Errorf("%s:%d reads the current time directly, use the clock of the config instead: %s", file, i+1, strings.TrimSpace(line))
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// loadLimiter bounds the load the search job workers of a process put on
+// gitserver and searcher. One loadLimiter is shared by the handlers of the
+// repo and repo revision workers.
+type loadLimiter struct {
+	// limiter is the token bucket a handler waits on before it calls the
+	// backends. It is nil if the rate is unlimited.
+	limiter *rate.Limiter
+
+	// maxRevisionsPerJob is how many revisions of the same job are searched
+	// concurrently. It is unlimited if 0.
+	maxRevisionsPerJob int
+
+	// maxRevisionsPerCodeHost is how many revisions of the same code host
+	// are searched concurrently, unless codeHostMaxRevisions has a ceiling
+	// for the code host. It is unlimited if 0.
+	maxRevisionsPerCodeHost int
+	codeHostMaxRevisions    map[string]int
+
+	mu               sync.Mutex
+	running          map[int64]int
+	runningCodeHosts map[string]int
+}
+
+// newLoadLimiter returns a loadLimiter which allows c.BackendRequestsPerSecond
+// calls to the backends with bursts of up to c.BackendBurst calls, and
+// c.MaxRevisionsPerJob concurrent searches per job. The concurrent searches per
+// code host are limited by c.CodeHostMaxRevisions, or else by
+// c.MaxRevisionsPerCodeHost. A limit of 0 disables it.
+func newLoadLimiter(c config) *loadLimiter {
+	l := &loadLimiter{
+		maxRevisionsPerJob:      c.MaxRevisionsPerJob,
+		maxRevisionsPerCodeHost: c.MaxRevisionsPerCodeHost,
+		codeHostMaxRevisions:    c.CodeHostMaxRevisions,
+		running:                 map[int64]int{},
+		runningCodeHosts:        map[string]int{},
+	}
+	if c.BackendRequestsPerSecond > 0 {
+		l.limiter = rate.NewLimiter(rate.Limit(c.BackendRequestsPerSecond), c.BackendBurst)
+	}
+	return l
+}
+
+// Wait blocks until the caller may call the backends. It returns an error if
+// ctx is done first, for example because the job was canceled.
+func (l *loadLimiter) Wait(ctx context.Context) error {
+	if l.limiter == nil {
+		return ctx.Err()
+	}
+	return l.limiter.Wait(ctx)
+}
+
+// acquireJob takes one of the slots of job id. It returns false if all slots
+// of the job are taken. Otherwise release has to be called once the search is
+// done.
+func (l *loadLimiter) acquireJob(id int64) (release func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxRevisionsPerJob > 0 && l.running[id] >= l.maxRevisionsPerJob {
+		return nil, false
+	}
+	l.running[id]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if l.running[id]--; l.running[id] <= 0 {
+				delete(l.running, id)
+			}
+		})
+	}, true
+}
+
+// codeHostLimit returns how many revisions of codeHost are searched
+// concurrently. It is unlimited if 0.
+func (l *loadLimiter) codeHostLimit(codeHost string) int {
+	if n, ok := l.codeHostMaxRevisions[codeHost]; ok {
+		return n
+	}
+	return l.maxRevisionsPerCodeHost
+}
+
+// saturatedCodeHosts returns the code hosts of which as many revisions are
+// searched as may be. The workers don't dequeue revisions of them until a
+// search finishes.
+func (l *loadLimiter) saturatedCodeHosts() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var saturated []string
+	for codeHost, n := range l.runningCodeHosts {
+		if limit := l.codeHostLimit(codeHost); limit > 0 && n >= limit {
+			saturated = append(saturated, codeHost)
+		}
+	}
+	sort.Strings(saturated)
+	return saturated
+}
+
+// acquireCodeHost counts a search of a revision of codeHost until
+// releaseCodeHost is called. It doesn't check the ceiling of the code host,
+// since the workers only dequeue revisions of code hosts which aren't
+// saturated.
+func (l *loadLimiter) acquireCodeHost(codeHost string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.runningCodeHosts[codeHost]++
+}
+
+// releaseCodeHost is the counterpart of acquireCodeHost.
+func (l *loadLimiter) releaseCodeHost(codeHost string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.runningCodeHosts[codeHost]--; l.runningCodeHosts[codeHost] <= 0 {
+		delete(l.runningCodeHosts, codeHost)
+	}
+}
@@ -0,0 +1,130 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+func TestExhaustiveSearch_RateLimit(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa"))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 1@rev3 1@rev4 1@rev5", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// All revisions could be searched at once, but the limiter only lets one
+	// search through every 50ms.
+	const interval = 50 * time.Millisecond
+	config := testConfig(5)
+	config.BackendRequestsPerSecond = float64(time.Second / interval)
+	config.BackendBurst = 1
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   config,
+	}
+
+	var mu sync.Mutex
+	var searchedAt []time.Time
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(context.Context, types.RepositoryRevision) error {
+			mu.Lock()
+			searchedAt = append(searchedAt, time.Now())
+			mu.Unlock()
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(types.JobStateCompleted, job2.AggState)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(searchedAt, 5)
+	sort.Slice(searchedAt, func(i, j int) bool { return searchedAt[i].Before(searchedAt[j]) })
+	for i := 1; i < len(searchedAt); i++ {
+		// Leave some slack for the timer of the limiter.
+		require.GreaterOrEqual(searchedAt[i].Sub(searchedAt[i-1]), interval*4/5, "searches %d and %d ran too close to each other", i-1, i)
+	}
+}
+
+func TestExhaustiveSearch_RateLimitCancel(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa"))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// Resolving the revisions of repo 1 takes the only token, so both
+	// revisions wait on the limiter until the job is canceled.
+	config := testConfig(2)
+	config.BackendRequestsPerSecond = 1.0 / float64(time.Hour/time.Second)
+	config.BackendBurst = 1
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   config,
+	}
+
+	var searches atomic.Int32
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(context.Context, types.RepositoryRevision) error {
+			searches.Add(1)
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	require.Eventually(func() bool {
+		processing, _, err := basestore.ScanFirstInt(env.store.Query(env.workerCtx, sqlf.Sprintf(
+			"SELECT COUNT(*) FROM exhaustive_search_repo_revision_jobs WHERE state = 'processing'")))
+		return err == nil && processing == 2
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	err = env.svc.CancelSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(types.JobStateCanceled, job2.AggState)
+	require.Zero(searches.Load())
+	require.Empty(blobContents(t, env.bucket))
+}
@@ -0,0 +1,472 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbfixture"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestExhaustiveSearch_MaxResults(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	// Every revision has 1 result, so the job is truncated after the first
+	// search.
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 2@rev3", service.CreateSearchJobOpts{MaxResults: 1})
+	require.NoError(err)
+	require.Equal(1, job.MaxResults)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+
+	// rev1 is searched first. The searches of the other revisions only
+	// finish after the job was truncated, if they run at all.
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(ctx context.Context, repoRev types.RepositoryRevision) error {
+			for repoRev.Revision != "rev1" {
+				job, err := env.svc.GetSearchJob(ctx, job.ID)
+				if err != nil {
+					return err
+				}
+				if job.Truncated {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	{
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
+		require.True(job2.Truncated)
+		require.Equal(types.JobStateCompleted, job2.AggState)
+	}
+
+	// Only the results of rev1 were uploaded.
+	{
+		vals := blobContents(t, env.bucket)
+		require.Equal([]string{`{"type":"path","path":"path/to/file.go","repositoryID":1,"repository":"repo1","commit":"rev1","language":"Go"}
+`}, vals)
+	}
+}
+
+func TestExhaustiveSearch_Deadline(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	_, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{MaxDuration: -time.Second})
+	require.Error(err)
+
+	// expiredJob reached its deadline before the workers started.
+	expiredJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 2@rev2", service.CreateSearchJobOpts{MaxDuration: time.Nanosecond})
+	require.NoError(err)
+	require.False(expiredJob.Deadline.IsZero())
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev3 2@rev4", service.CreateSearchJobOpts{MaxDuration: time.Hour})
+	require.NoError(err)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		notifier: &fakeNotifier{},
+		config:   testConfig(5),
+	}
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
+	}
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// The repo jobs of expiredJob were skipped, so none of its revisions were
+	// searched.
+	{
+		job2, err := env.svc.GetSearchJob(env.userCtx, expiredJob.ID)
+		require.NoError(err)
+		require.True(job2.DeadlineExceeded)
+		require.Equal(types.JobStateCompleted, job2.AggState)
+
+		progress, err := env.svc.JobProgress(env.userCtx, expiredJob.ID)
+		require.NoError(err)
+		require.Equal(&types.SearchJobProgress{Total: 3, Remaining: 0}, progress)
+	}
+
+	{
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
+		require.False(job2.DeadlineExceeded)
+		require.Equal(types.JobStateCompleted, job2.AggState)
+	}
+
+	// Only the revisions of job were searched.
+	{
+		vals := blobContents(t, env.bucket)
+		require.Len(vals, 2)
+		for _, val := range vals {
+			require.NotContains(val, `"commit":"rev1"`)
+			require.NotContains(val, `"commit":"rev2"`)
+		}
+	}
+}
+
+func TestExhaustiveSearch_MaxRevisionsPerJob(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa"))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 1@rev3 1@rev4", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	config := testConfig(4)
+	config.MaxRevisionsPerJob = 1
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   config,
+	}
+
+	var running, maxRunning atomic.Int32
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(context.Context, types.RepositoryRevision) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// Postponed revisions were searched later, none of them failed.
+	job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(types.JobStateCompleted, job2.AggState)
+	require.Len(blobContents(t, env.bucket), 4)
+	require.Equal(int32(1), maxRunning.Load())
+
+	values := gatherMetrics(t, env.observationCtx.Registerer.(prometheus.Gatherer))
+	require.Equal(4.0, values["src_search_jobs_tasks_total{outcome=succeeded}"])
+}
+
+func TestExhaustiveSearch_MaxRevisionsPerCodeHost(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	codeHosts := map[api.RepoID]string{1: "https://github.com/", 2: "https://gitlab.example.com/"}
+	for id, codeHost := range codeHosts {
+		err := env.store.Exec(env.workerCtx, sqlf.Sprintf("UPDATE repo SET external_service_id = %s WHERE id = %s", codeHost, id))
+		require.NoError(err)
+	}
+
+	var revs []string
+	for i := range 8 {
+		revs = append(revs, fmt.Sprintf("1@rev%d", i), fmt.Sprintf("2@rev%d", i))
+	}
+	job, err := env.svc.CreateSearchJob(env.userCtx, strings.Join(revs, " "), service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// github.com has its own ceiling, the other code host gets the default.
+	config := testConfig(8)
+	config.MaxRevisionsPerCodeHost = 4
+	config.CodeHostMaxRevisions = map[string]int{"https://github.com/": 1}
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   config,
+	}
+
+	var mu sync.Mutex
+	running := map[string]int{}
+	maxRunning := map[string]int{}
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(_ context.Context, repoRev types.RepositoryRevision) error {
+			codeHost := codeHosts[repoRev.Repository]
+			mu.Lock()
+			running[codeHost]++
+			maxRunning[codeHost] = max(maxRunning[codeHost], running[codeHost])
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running[codeHost]--
+			mu.Unlock()
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// Revisions of a code host at its ceiling stayed in the queue while the
+	// other code host was searched, none of them failed.
+	job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(types.JobStateCompleted, job2.AggState)
+	require.Len(blobContents(t, env.bucket), 16)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(1, maxRunning["https://github.com/"])
+	require.LessOrEqual(maxRunning["https://gitlab.example.com/"], 4)
+}
+
+func TestExhaustiveSearch_Duplicate(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa"))
+
+	bobID := dbfixture.User(t, env.db, dbfixture.WithUsername("bob")).ID
+	adminID := dbfixture.User(t, env.db, dbfixture.WithUsername("admin"), dbfixture.WithSiteAdmin()).ID
+
+	bobCtx := actor.WithActor(context.Background(), actor.FromUser(bobID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+
+	original, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{
+		MaxResults: 10,
+		Priority:   types.JobPriorityLow,
+	})
+	require.NoError(err)
+	require.Zero(original.CreatedFromJobID)
+
+	requireDuplicate := func(job *types.ExhaustiveSearchJob, initiatorID int32) {
+		t.Helper()
+		require.NotEqual(original.ID, job.ID)
+		require.Equal(initiatorID, job.InitiatorID)
+		require.Equal(original.Query, job.Query)
+		require.Equal(original.MaxResults, job.MaxResults)
+		require.Equal(original.Priority, job.Priority)
+		require.Equal(original.ID, job.CreatedFromJobID)
+
+		// The tasks of the original job aren't copied, the workers resolve
+		// the repositories and revisions of the new job again.
+		stats, err := env.svc.GetAggregateRepoRevState(adminCtx, job.ID)
+		require.NoError(err)
+		require.Equal(&types.RepoRevJobStats{Total: 1, InProgress: 1}, stats)
+	}
+
+	// Alice duplicates her own job.
+	job, err := env.svc.DuplicateSearchJob(env.userCtx, original.ID)
+	require.NoError(err)
+	requireDuplicate(job, env.userID)
+
+	// Site admins can duplicate the jobs of other users. The duplicate is
+	// owned by them.
+	job, err = env.svc.DuplicateSearchJob(adminCtx, original.ID)
+	require.NoError(err)
+	requireDuplicate(job, adminID)
+
+	// Other users can't.
+	_, err = env.svc.DuplicateSearchJob(bobCtx, original.ID)
+	require.ErrorIs(err, auth.ErrMustBeSiteAdminOrSameUser)
+	jobs, err := env.svc.ListSearchJobs(bobCtx, store.ListArgs{})
+	require.NoError(err)
+	require.Empty(jobs)
+}
+
+func TestExhaustiveSearch_IdempotencyKey(t *testing.T) {
+	require := require.New(t)
+	clock := glock.NewMockClockAt(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	env := newTestEnv(t,
+		withSiteConfig(schema.SiteConfiguration{
+			SearchLimits: &schema.SearchLimits{SearchJobIdempotencyWindowSeconds: 3600},
+		}),
+		withClock(clock),
+		withRepos("repoa"))
+
+	bobID := dbfixture.User(t, env.db, dbfixture.WithUsername("bob")).ID
+
+	bobCtx := actor.WithActor(context.Background(), actor.FromUser(bobID))
+	internalCtx := actor.WithInternalActor(context.Background())
+
+	opts := service.CreateSearchJobOpts{IdempotencyKey: "request-1"}
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1", opts)
+	require.NoError(err)
+
+	// A retry with the same key and query returns the same job.
+	retried, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1", opts)
+	require.NoError(err)
+	require.Equal(job.ID, retried.ID)
+	count, err := env.svc.CountSearchJobs(env.userCtx, store.ListArgs{})
+	require.NoError(err)
+	require.Equal(1, count)
+
+	// The same key with a different query is a conflict.
+	_, err = env.svc.CreateSearchJob(env.userCtx, "1@rev2", opts)
+	require.ErrorIs(err, service.ErrIdempotencyKeyConflict)
+
+	// Keys are scoped to their user.
+	bobJob, err := env.svc.CreateSearchJob(bobCtx, "1@rev1", opts)
+	require.NoError(err)
+	require.NotEqual(job.ID, bobJob.ID)
+
+	// Once the key expired, it creates a fresh job, even before the janitor
+	// released it.
+	clock.Advance(2 * time.Hour)
+	fresh, err := env.svc.CreateSearchJob(env.userCtx, "1@rev2", opts)
+	require.NoError(err)
+	require.NotEqual(job.ID, fresh.ID)
+	retried, err = env.svc.CreateSearchJob(env.userCtx, "1@rev2", opts)
+	require.NoError(err)
+	require.Equal(fresh.ID, retried.ID)
+
+	// The janitor releases the expired key of bob, but not the key of the
+	// fresh job.
+	clock.Advance(30 * time.Minute)
+	released, err := env.svc.ReleaseExpiredIdempotencyKeys(internalCtx, clock.Now())
+	require.NoError(err)
+	require.Equal(1, released)
+	retried, err = env.svc.CreateSearchJob(env.userCtx, "1@rev2", opts)
+	require.NoError(err)
+	require.Equal(fresh.ID, retried.ID)
+
+	_, err = env.svc.ReleaseExpiredIdempotencyKeys(env.userCtx, clock.Now())
+	require.Error(err)
+
+	_, err = env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{IdempotencyKey: strings.Repeat("x", service.MaxIdempotencyKeyLength+1)})
+	require.Error(err)
+}
+
+func TestExhaustiveSearch_Quota(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t,
+		withSiteConfig(schema.SiteConfiguration{
+			SearchLimits: &schema.SearchLimits{
+				MaxActiveSearchJobsPerUser: 1,
+				MaxActiveSearchJobs:        3,
+			},
+		}),
+		withRepos("repoa"))
+
+	bobID := dbfixture.User(t, env.db, dbfixture.WithUsername("bob")).ID
+	adminID := dbfixture.User(t, env.db, dbfixture.WithUsername("admin"), dbfixture.WithSiteAdmin()).ID
+
+	bobCtx := actor.WithActor(context.Background(), actor.FromUser(bobID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+
+	var quotaErr *service.QuotaError
+
+	// Alice hits her own limit.
+	aliceJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	_, err = env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.ErrorAs(err, &quotaErr)
+	require.Equal(&service.QuotaError{Limit: 1, PerUser: true}, quotaErr)
+
+	// Site admins are exempt from the limit per user, but not from the limit
+	// of the instance, which bob hits.
+	for range 2 {
+		_, err = env.svc.CreateSearchJob(adminCtx, "1@rev1", service.CreateSearchJobOpts{})
+		require.NoError(err)
+	}
+	_, err = env.svc.CreateSearchJob(bobCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.ErrorAs(err, &quotaErr)
+	require.Equal(&service.QuotaError{Limit: 3}, quotaErr)
+
+	// Canceled jobs don't count.
+	err = env.svc.CancelSearchJob(env.userCtx, aliceJob.ID)
+	require.NoError(err)
+	_, err = env.svc.CreateSearchJob(env.userCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	_, err = env.svc.CreateSearchJob(bobCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.ErrorAs(err, &quotaErr)
+
+	// Neither do finished jobs.
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
+	}
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	active, err := env.store.CountActiveSearchJobs(env.workerCtx, 0)
+	require.NoError(err)
+	require.Zero(active)
+	_, err = env.svc.CreateSearchJob(bobCtx, "1@rev1", service.CreateSearchJobOpts{})
+	require.NoError(err)
+}
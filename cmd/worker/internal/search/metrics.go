@@ -0,0 +1,104 @@
+package search
+
+import (
+	"context"
+	"io"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+)
+
+// metrics are the metrics of the search job workers which the metrics of
+// workerutil and dbworker don't cover.
+type metrics struct {
+	// tasks counts the attempts to search a revision by outcome: "succeeded",
+	// "failed", "retried", "skipped", "postponed" or "handed_back". A retried
+	// attempt is requeued with a backoff. A skipped attempt didn't search
+	// since the job reached its deadline. A postponed attempt didn't search
+	// since the job already searched as many revisions concurrently as it
+	// may. A handed back attempt was requeued since the worker stopped.
+	tasks *prometheus.CounterVec
+
+	// taskDuration is the time it took to search a revision.
+	taskDuration prometheus.Histogram
+
+	// chunkBytes is the size of the result blobs written to the upload store.
+	chunkBytes prometheus.Histogram
+}
+
+func newMetrics(observationCtx *observation.Context) *metrics {
+	factory := promauto.With(observationCtx.Registerer)
+	return &metrics{
+		tasks: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "src_search_jobs_tasks_total",
+			Help: "Total number of attempts to search a revision of a search job by outcome.",
+		}, []string{"outcome"}),
+		taskDuration: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "src_search_jobs_task_duration_seconds",
+			Help:    "Time spent searching a revision of a search job.",
+			Buckets: prometheus.ExponentialBuckets(0.1, 4, 8),
+		}),
+		chunkBytes: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "src_search_jobs_result_chunk_bytes",
+			Help:    "Size of the compressed result blobs of search jobs written to the upload store.",
+			Buckets: prometheus.ExponentialBuckets(1024, 4, 10),
+		}),
+	}
+}
+
+// queueCounter is the part of dbworkerstore.Store the queue metrics need.
+type queueCounter interface {
+	QueuedCount(context.Context, bool) (int, error)
+}
+
+// registerQueueMetrics registers gauges for the number of queued and
+// processing records of the worker tier. They are sampled from workerStore on
+// every scrape.
+func registerQueueMetrics(observationCtx *observation.Context, tier string, workerStore queueCounter) {
+	logger := observationCtx.Logger.Scoped("queueMetrics")
+
+	count := func(includeProcessing bool) int {
+		count, err := workerStore.QueuedCount(context.Background(), includeProcessing)
+		if err != nil {
+			logger.Error("failed to count search job records", log.String("tier", tier), log.Error(err))
+			return 0
+		}
+		return count
+	}
+
+	observationCtx.Registerer.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "src_search_jobs_queue_size",
+		Help:        "Number of records of a search job worker tier by state.",
+		ConstLabels: prometheus.Labels{"tier": tier, "state": "queued"},
+	}, func() float64 {
+		return float64(count(false))
+	}))
+
+	// QueuedCount only counts processing records together with the queued
+	// ones.
+	observationCtx.Registerer.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "src_search_jobs_queue_size",
+		Help:        "Number of records of a search job worker tier by state.",
+		ConstLabels: prometheus.Labels{"tier": tier, "state": "processing"},
+	}, func() float64 {
+		return float64(max(count(true)-count(false), 0))
+	}))
+}
+
+// meteredUploadStore observes the size of every blob uploaded to Store.
+type meteredUploadStore struct {
+	uploadstore.Store
+	chunkBytes prometheus.Histogram
+}
+
+func (s meteredUploadStore) Upload(ctx context.Context, key string, r io.Reader) (int64, error) {
+	n, err := s.Store.Upload(ctx, key, r)
+	if err == nil {
+		s.chunkBytes.Observe(float64(n))
+	}
+	return n, err
+}
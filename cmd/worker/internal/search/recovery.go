@@ -0,0 +1,68 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// stalledResetter is implemented by the worker stores of all records of search
+// jobs.
+type stalledResetter interface {
+	ResetStalled(ctx context.Context) (resetLastHeartbeatsByIDs, failedLastHeartbeatsByIDs map[int]time.Duration, err error)
+}
+
+// recoverStalledSearchJobs runs once when the workers start, before they
+// process anything. If all workers were down for a while, e.g. during an
+// outage of the cluster, the records they processed stalled. The resetters
+// requeue stalled records eventually, but nothing fixes up a search job whose
+// worker died after it created all tasks, or enqueues the notifications of a
+// job whose last task stalled.
+//
+// It repairs the stalled jobs with store.RepairStalledSearchJobs, requeues
+// their orphaned records right away with the resetters, and then enqueues the
+// notifications of the jobs which finished. Every step checks the records
+// again, so workers which start at the same time may run it concurrently.
+func recoverStalledSearchJobs(ctx context.Context, logger log.Logger, s *store.Store, stalledMaxAge time.Duration, resetters ...stalledResetter) error {
+	ids, err := s.RepairStalledSearchJobs(ctx, stalledMaxAge)
+	if err != nil {
+		return errors.Wrap(err, "repairing stalled search jobs")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Repaired search jobs aren't processing anymore, so the resetter of the
+	// search jobs only requeues the ones which didn't create all tasks yet.
+	requeued, failed := 0, 0
+	for _, r := range resetters {
+		reset, resetFailed, err := r.ResetStalled(ctx)
+		if err != nil {
+			return errors.Wrap(err, "resetting stalled records")
+		}
+		requeued += len(reset)
+		failed += len(resetFailed)
+	}
+
+	notified := 0
+	for _, id := range ids {
+		enqueued, err := s.EnqueueSearchJobNotifications(ctx, id)
+		if err != nil {
+			return errors.Wrapf(err, "enqueueing notifications of search job %d", id)
+		}
+		if enqueued {
+			notified++
+		}
+	}
+
+	logger.Info("recovered stalled search jobs",
+		log.Int("jobs", len(ids)),
+		log.Int("requeued", requeued),
+		log.Int("failed", failed),
+		log.Int("finished", notified))
+	return nil
+}
@@ -0,0 +1,206 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+func TestExhaustiveSearch_Stalled(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa"))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	stalledJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// stalledJob was reset too often already, so it fails the next time it
+	// stalls.
+	err = env.store.Exec(env.workerCtx, sqlf.Sprintf("UPDATE exhaustive_search_jobs SET num_resets = 5 WHERE id = %s", stalledJob.ID))
+	require.NoError(err)
+
+	// A worker claims both jobs and dies before it sends a heartbeat.
+	deadWorkerStore := store.NewExhaustiveSearchJobWorkerStore(env.observationCtx, env.db.Handle(), store.DefaultStalledMaxAge)
+	for range 2 {
+		_, ok, err := deadWorkerStore.Dequeue(env.workerCtx, "dead-worker", nil)
+		require.NoError(err)
+		require.True(ok)
+	}
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// The resetter requeued job and the workers finished it.
+	{
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(&types.RepoRevJobStats{Total: 4, Completed: 4}, stats)
+		// 2 result blobs + the summary of job. stalledJob has no summary,
+		// since the resetter failed it without a worker to notify.
+		require.Len(env.bucket, 3)
+
+		numResets, err := basestore.ScanInt(env.store.QueryRow(env.workerCtx, sqlf.Sprintf("SELECT num_resets FROM exhaustive_search_jobs WHERE id = %s", job.ID)))
+		require.NoError(err)
+		require.Equal(1, numResets)
+	}
+
+	// The resetter gave up on stalledJob.
+	{
+		job2, err := env.svc.GetSearchJob(env.userCtx, stalledJob.ID)
+		require.NoError(err)
+		require.Equal(types.JobStateFailed, job2.State)
+		require.Contains(job2.FailureMessage, "stalled")
+	}
+}
+
+func TestExhaustiveSearch_ColdStart(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa"))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	finishedJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	createTasks := func(searchJobID int64, revs ...string) {
+		repoJobID, err := env.store.CreateExhaustiveSearchRepoJob(env.workerCtx, types.ExhaustiveSearchRepoJob{
+			SearchJobID: searchJobID,
+			RepoID:      1,
+			RefSpec:     strings.Join(revs, ":"),
+		})
+		require.NoError(err)
+		for _, rev := range revs {
+			_, err := env.store.CreateExhaustiveSearchRepoRevisionJob(env.workerCtx, types.ExhaustiveSearchRepoRevisionJob{
+				SearchRepoJobID: repoJobID,
+				Revision:        rev,
+			})
+			require.NoError(err)
+		}
+	}
+	createTasks(job.ID, "rev1", "rev2")
+	createTasks(finishedJob.ID, "rev3")
+
+	// All workers went down an hour ago. Both jobs had created their tasks,
+	// but their workers died before they marked them completed. The worker
+	// searching 1@rev2 died too, while the last task of finishedJob had
+	// finished.
+	stale := time.Now().Add(-time.Hour)
+	for _, q := range []*sqlf.Query{
+		sqlf.Sprintf("UPDATE exhaustive_search_jobs SET state = 'processing', started_at = %s, last_heartbeat_at = %s, expanded_at = %s", stale, stale, stale),
+		sqlf.Sprintf("UPDATE exhaustive_search_repo_jobs SET state = 'completed', started_at = %s, finished_at = %s", stale, stale),
+		sqlf.Sprintf("UPDATE exhaustive_search_repo_revision_jobs SET state = 'completed', started_at = %s, finished_at = %s WHERE revision <> 'rev2'", stale, stale),
+		sqlf.Sprintf("UPDATE exhaustive_search_repo_revision_jobs SET state = 'processing', started_at = %s, last_heartbeat_at = %s WHERE revision = 'rev2'", stale, stale),
+	} {
+		require.NoError(env.store.Exec(env.workerCtx, q))
+	}
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return service.NewSearcherFake()
+	}
+
+	countNotifications := func(id int64) int {
+		n, err := basestore.ScanInt(env.store.QueryRow(env.workerCtx, sqlf.Sprintf("SELECT COUNT(*) FROM exhaustive_search_job_notifications WHERE search_job_id = %s", id)))
+		require.NoError(err)
+		return n
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+
+	// The startup pass repaired both jobs before the workers started.
+	{
+		for _, id := range []int64{job.ID, finishedJob.ID} {
+			state, err := basestore.ScanAny[string](env.store.QueryRow(env.workerCtx, sqlf.Sprintf("SELECT state FROM exhaustive_search_jobs WHERE id = %s", id)))
+			require.NoError(err)
+			require.Equal("completed", state, "job %d wasn't marked completed", id)
+		}
+
+		// The orphaned task was requeued...
+		var state string
+		var numResets int
+		err := env.store.QueryRow(env.workerCtx, sqlf.Sprintf("SELECT state, num_resets FROM exhaustive_search_repo_revision_jobs WHERE revision = 'rev2'")).Scan(&state, &numResets)
+		require.NoError(err)
+		require.Equal("queued", state)
+		require.Equal(1, numResets)
+
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(types.JobStateQueued, job2.AggState)
+		require.Zero(countNotifications(job.ID))
+
+		// ...and finishedJob finished, so its initiator is notified.
+		job2, err = env.svc.GetSearchJob(env.userCtx, finishedJob.ID)
+		require.NoError(err)
+		require.Equal(types.JobStateCompleted, job2.AggState)
+		require.Equal(2, countNotifications(finishedJob.ID))
+	}
+
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// The workers finished job like any other job.
+	{
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(&types.RepoRevJobStats{Total: 4, Completed: 4}, stats)
+
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(types.JobStateCompleted, job2.AggState)
+
+		require.Equal(2, countNotifications(job.ID))
+		require.Equal(2, countNotifications(finishedJob.ID), "finishedJob is only notified once")
+
+		// The results of 1@rev2 + the summaries of both jobs
+		require.Len(env.bucket, 3)
+		require.Contains(env.bucket, fmt.Sprintf("%d-summary.json", job.ID))
+		require.Contains(env.bucket, fmt.Sprintf("%d-summary.json", finishedJob.ID))
+	}
+}
@@ -0,0 +1,569 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/keegancsmith/sqlf"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbfixture"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestExhaustiveSearch_RetryFailedTasks(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	userBadID := dbfixture.User(t, env.db, dbfixture.WithUsername("mallory")).ID
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 2@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// Searching rev2 fails until we flip fail.
+	var fail atomic.Bool
+	fail.Store(true)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(_ context.Context, repoRev types.RepositoryRevision) error {
+			if repoRev.Revision == "rev2" && fail.Load() {
+				return errcode.MakeNonRetryable(errors.New("search of rev2 failed"))
+			}
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	waitForJob := func() {
+		require.Eventually(func() bool {
+			return !searchJob.hasWork(env.workerCtx)
+		}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+	}
+
+	waitForJob()
+
+	// The search of rev2 failed, everything else completed.
+	{
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(&types.RepoRevJobStats{
+			Total:     6,
+			Completed: 5,
+			Failed:    1,
+		}, stats)
+
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(types.JobStateFailed, job2.AggState)
+		// 2 result blobs + the summary
+		require.Equal(3, len(env.bucket))
+	}
+
+	// Only the owner may retry the job.
+	{
+		userBadCtx := actor.WithActor(context.Background(), actor.FromUser(userBadID))
+		_, err = env.svc.RetryFailedTasks(userBadCtx, job.ID)
+		require.ErrorIs(err, auth.ErrMustBeSiteAdminOrSameUser)
+	}
+
+	// Retrying only requeues the failed task.
+	{
+		fail.Store(false)
+
+		count, err := env.svc.RetryFailedTasks(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(1, count)
+	}
+
+	waitForJob()
+
+	{
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(&types.RepoRevJobStats{
+			Total:     6,
+			Completed: 6,
+		}, stats)
+
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(types.JobStateCompleted, job2.AggState)
+		// 3 result blobs + the summary, which was written again
+		require.Equal(4, len(env.bucket))
+	}
+
+	// Nothing failed, so retrying again is a no-op.
+	{
+		count, err := env.svc.RetryFailedTasks(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(0, count)
+	}
+}
+
+func TestExhaustiveSearch_ForceRequeueTask(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	adminID := dbfixture.User(t, env.db, dbfixture.WithUsername("admin"), dbfixture.WithSiteAdmin()).ID
+
+	adminCtx, cancel := context.WithCancel(actor.WithActor(context.Background(), actor.FromUser(adminID)))
+	defer cancel()
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 2@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	// Searching rev2 fails until we flip fail.
+	var fail atomic.Bool
+	fail.Store(true)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(_ context.Context, repoRev types.RepositoryRevision) error {
+			if repoRev.Revision == "rev2" && fail.Load() {
+				return errcode.MakeNonRetryable(errors.New("search of rev2 failed"))
+			}
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	waitForJob := func() {
+		require.Eventually(func() bool {
+			return !searchJob.hasWork(env.workerCtx)
+		}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+	}
+
+	waitForJob()
+
+	// Support finds the failed task with the worker which ran it.
+	tasks, err := env.svc.ListTasks(adminCtx, job.ID, service.ListTasksArgs{State: types.JobStateFailed})
+	require.NoError(err)
+	require.Len(tasks, 1)
+	failed := tasks[0]
+	require.Equal("rev2", failed.Revision)
+	require.Equal(api.RepoName("repoa"), failed.RepoName)
+	require.Equal(job.ID, failed.SearchJobID)
+	require.Equal("search of rev2 failed", failed.FailureMessage)
+	require.NotEmpty(failed.WorkerHostname)
+	require.False(failed.FinishedAt.IsZero())
+
+	completed, err := env.svc.ListTasks(adminCtx, job.ID, service.ListTasksArgs{State: types.JobStateCompleted})
+	require.NoError(err)
+	require.Len(completed, 5)
+
+	// Only site admins may inspect or requeue tasks, even of their own jobs.
+	{
+		_, err := env.svc.ListTasks(env.userCtx, job.ID, service.ListTasksArgs{})
+		require.ErrorIs(err, auth.ErrMustBeSiteAdmin)
+		_, err = env.svc.GetTask(env.userCtx, failed.ID)
+		require.ErrorIs(err, auth.ErrMustBeSiteAdmin)
+		err = env.svc.ForceRequeueTask(env.userCtx, failed.ID)
+		require.ErrorIs(err, auth.ErrMustBeSiteAdmin)
+	}
+
+	// Completed tasks can't be requeued, and unknown tasks don't exist.
+	{
+		err := env.svc.ForceRequeueTask(adminCtx, completed[0].ID)
+		require.ErrorIs(err, service.ErrTaskStateConflict)
+		err = env.svc.ForceRequeueTask(adminCtx, 9999)
+		require.ErrorIs(err, store.ErrNoResults)
+	}
+
+	// The requeued task starts over with all its retries.
+	{
+		fail.Store(false)
+
+		require.NoError(env.svc.ForceRequeueTask(adminCtx, failed.ID))
+		task, err := env.svc.GetTask(adminCtx, failed.ID)
+		require.NoError(err)
+		require.Contains([]types.JobState{types.JobStateQueued, types.JobStateProcessing, types.JobStateCompleted}, task.State)
+		require.Equal(failed.NumResets+1, task.NumResets)
+	}
+
+	waitForJob()
+
+	// The workers completed it like any other task.
+	{
+		task, err := env.svc.GetTask(adminCtx, failed.ID)
+		require.NoError(err)
+		require.Equal(types.JobStateCompleted, task.State)
+		require.Empty(task.FailureMessage)
+		require.Zero(task.NumFailures)
+
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(&types.RepoRevJobStats{
+			Total:     6,
+			Completed: 6,
+		}, stats)
+
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(types.JobStateCompleted, job2.AggState)
+		// 3 result blobs + the summary, which was written again
+		require.Equal(4, len(env.bucket))
+	}
+
+	// A completed task can't be failed either.
+	err = env.svc.ForceFailTask(adminCtx, failed.ID, "stuck")
+	require.ErrorIs(err, service.ErrTaskStateConflict)
+}
+
+func TestExhaustiveSearch_Resume(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	malloryID := dbfixture.User(t, env.db, dbfixture.WithUsername("mallory")).ID
+
+	malloryCtx := actor.WithActor(context.Background(), actor.FromUser(malloryID))
+
+	// While block is set, the searches of rev2 and rev3 run until their job is
+	// canceled.
+	var block atomic.Bool
+	var startedMu sync.Mutex
+	started := map[string]bool{}
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(ctx context.Context, repoRev types.RepositoryRevision) error {
+			if repoRev.Revision == "rev1" || !block.Load() {
+				return nil
+			}
+			startedMu.Lock()
+			started[repoRev.Revision] = true
+			startedMu.Unlock()
+			<-ctx.Done()
+			return ctx.Err()
+		}}
+	}
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		notifier: &fakeNotifier{},
+		config:   testConfig(5),
+	}
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+	waitIdle := func() {
+		require.Eventually(func() bool {
+			return !searchJob.hasWork(env.workerCtx)
+		}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+	}
+	results := func(id int64) string {
+		var buf bytes.Buffer
+		writerTo, err := env.svc.GetSearchJobResultsWriterTo(env.userCtx, id, service.ResultFormatJSONL)
+		require.NoError(err)
+		_, err = writerTo.WriteTo(&buf)
+		require.NoError(err)
+		return buf.String()
+	}
+
+	// The baseline runs without being canceled.
+	baselineJob, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 2@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	waitIdle()
+	baseline := results(baselineJob.ID)
+	require.Len(strings.Split(strings.TrimSpace(baseline), "\n"), 3)
+
+	// Only canceled jobs can be resumed.
+	err = env.svc.ResumeSearchJob(env.userCtx, baselineJob.ID)
+	require.ErrorIs(err, service.ErrSearchJobNotCanceled)
+
+	// We cancel job once rev1 was searched and the searches of rev2 and rev3
+	// started.
+	block.Store(true)
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 2@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+	require.Eventually(func() bool {
+		startedMu.Lock()
+		defer startedMu.Unlock()
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		return err == nil && stats.Completed == 1 && len(started) == 2
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+	err = env.svc.CancelSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+	waitIdle()
+
+	{
+		stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(int32(1), stats.Completed)
+	}
+
+	// Only the initiator and site admins may resume a job.
+	err = env.svc.ResumeSearchJob(malloryCtx, job.ID)
+	require.ErrorIs(err, auth.ErrMustBeSiteAdminOrSameUser)
+
+	block.Store(false)
+	err = env.svc.ResumeSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+
+	{
+		job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+		require.NoError(err)
+		require.False(job2.Cancel)
+		require.NotEqual(types.JobStateCanceled, job2.AggState)
+	}
+
+	waitIdle()
+
+	job2, err := env.svc.GetSearchJob(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(types.JobStateCompleted, job2.AggState)
+
+	// The result of rev1 was kept and the results of rev2 and rev3 were
+	// written once.
+	require.Equal(baseline, results(job.ID))
+}
+
+func TestExhaustiveSearch_TransientErrors(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa"))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 1@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+	require.Equal(3, searchJob.config.MaxRevisionAttempts)
+
+	// The search of rev1 fails twice with transient errors before it
+	// succeeds, rev2 doesn't exist and the search of rev3 times out every
+	// time.
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(_ context.Context, repoRev types.RepositoryRevision) error {
+			mu.Lock()
+			attempts[repoRev.Revision]++
+			attempt := attempts[repoRev.Revision]
+			mu.Unlock()
+
+			switch repoRev.Revision {
+			case "rev1":
+				switch attempt {
+				case 1:
+					return errors.Wrap(context.DeadlineExceeded, "searching rev1")
+				case 2:
+					return &errcode.HTTPErr{Status: http.StatusServiceUnavailable}
+				}
+			case "rev2":
+				return &gitdomain.RevisionNotFoundError{Repo: "repoa", Spec: "rev2"}
+			case "rev3":
+				return errors.Wrap(context.DeadlineExceeded, "searching rev3")
+			}
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	// 1 search job + 1 repo job + 3 repo revision jobs
+	stats, err := env.svc.GetAggregateRepoRevState(env.userCtx, job.ID)
+	require.NoError(err)
+	require.Equal(&types.RepoRevJobStats{Total: 5, Completed: 3, Failed: 2}, stats)
+	// 1 result blob + the summary
+	require.Len(env.bucket, 2)
+
+	mu.Lock()
+	require.Equal(map[string]int{"rev1": 3, "rev2": 1, "rev3": 3}, attempts)
+	mu.Unlock()
+
+	// Every failed attempt is counted and the last error is kept.
+	rows, err := env.store.Query(env.workerCtx, sqlf.Sprintf("SELECT revision, state, num_failures, failure_message FROM exhaustive_search_repo_revision_jobs ORDER BY revision"))
+	require.NoError(err)
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var revision, state, failureMessage string
+		var numFailures int
+		require.NoError(rows.Scan(&revision, &state, &numFailures, &dbutil.NullString{S: &failureMessage}))
+		got = append(got, fmt.Sprintf("%s %s %d %s", revision, state, numFailures, failureMessage))
+	}
+	require.NoError(rows.Err())
+	require.Equal([]string{
+		"rev1 completed 2 Status 503",
+		"rev2 failed 1 revision not found: repoa@rev2",
+		"rev3 failed 3 giving up after 3 attempts: searching rev3: context deadline exceeded",
+	}, got)
+}
+
+func TestExhaustiveSearch_RetryBackoff(t *testing.T) {
+	require := require.New(t)
+	clock := glock.NewMockClockAt(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	env := newTestEnv(t, withClock(clock), withRepos("repoa"))
+
+	searchJobID, err := env.store.CreateExhaustiveSearchJob(env.userCtx, types.ExhaustiveSearchJob{InitiatorID: env.userID, Query: "1@rev1"})
+	require.NoError(err)
+	repoJobID, err := env.store.CreateExhaustiveSearchRepoJob(env.workerCtx, types.ExhaustiveSearchRepoJob{SearchJobID: searchJobID, RepoID: 1, RefSpec: "rev1"})
+	require.NoError(err)
+	id, err := env.store.CreateExhaustiveSearchRepoRevisionJob(env.workerCtx, types.ExhaustiveSearchRepoRevisionJob{SearchRepoJobID: repoJobID, Revision: "rev1"})
+	require.NoError(err)
+
+	h := &exhaustiveSearchRepoRevHandler{
+		store:        env.store,
+		maxAttempts:  5,
+		retryBackoff: 4 * time.Minute,
+		clock:        clock,
+	}
+
+	// The backoff doubles with every attempt until it reaches
+	// maxRetryBackoff.
+	for attempt, wantBackoff := range []time.Duration{4 * time.Minute, 8 * time.Minute, maxRetryBackoff, maxRetryBackoff} {
+		err := env.store.Exec(env.workerCtx, sqlf.Sprintf("UPDATE exhaustive_search_repo_revision_jobs SET state = 'processing' WHERE id = %s", id))
+		require.NoError(err)
+
+		record := &types.ExhaustiveSearchRepoRevisionJob{ID: id, WorkerJob: types.WorkerJob{NumFailures: int64(attempt)}}
+		require.NoError(h.retry(env.workerCtx, env.logger, record, errors.New("connection reset")))
+
+		var processAfter time.Time
+		var numFailures int
+		err = env.store.QueryRow(env.workerCtx, sqlf.Sprintf("SELECT process_after, num_failures FROM exhaustive_search_repo_revision_jobs WHERE id = %s", id)).Scan(&processAfter, &numFailures)
+		require.NoError(err)
+		require.Equal(attempt+1, numFailures)
+		require.True(clock.Now().Add(wantBackoff).Equal(processAfter), "attempt %d: got process_after %s, want %s from now", attempt+1, processAfter, wantBackoff)
+
+		clock.Advance(wantBackoff)
+	}
+
+	// The last attempt isn't retried.
+	record := &types.ExhaustiveSearchRepoRevisionJob{ID: id, WorkerJob: types.WorkerJob{NumFailures: 4}}
+	err = h.retry(env.workerCtx, env.logger, record, errors.New("connection reset"))
+	require.True(errcode.IsNonRetryable(err))
+}
+
+func TestExhaustiveSearch_FailedTasks(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t, withRepos("repoa", "repob"))
+
+	job, err := env.svc.CreateSearchJob(env.userCtx, "1@rev1 1@rev2 2@rev3", service.CreateSearchJobOpts{})
+	require.NoError(err)
+
+	searchJob := &searchJob{
+		workerDB: env.db,
+		config:   testConfig(5),
+	}
+
+	// rev1 doesn't exist and the search of rev3 fails with an error which is
+	// too long to be stored.
+	longMessage := strings.Repeat("x", 2*store.MaxFailureMessageLength)
+	newSearcherFactory := func(_ *observation.Context, _ database.DB) service.NewSearcher {
+		return hookNewSearcher{NewSearcher: service.NewSearcherFake(), hook: func(_ context.Context, repoRev types.RepositoryRevision) error {
+			switch repoRev.Revision {
+			case "rev1":
+				return &gitdomain.RevisionNotFoundError{Repo: "repoa", Spec: "rev1"}
+			case "rev3":
+				return errcode.MakeNonRetryable(errors.New(longMessage))
+			}
+			return nil
+		}}
+	}
+
+	routines, err := searchJob.newSearchJobRoutines(env.workerCtx, env.observationCtx, env.uploadStore, newSearcherFactory)
+	require.NoError(err)
+	for _, routine := range routines {
+		go routine.Start()
+		defer func() {
+			err := routine.Stop(context.Background())
+			require.NoError(err)
+		}()
+	}
+
+	require.Eventually(func() bool {
+		return !searchJob.hasWork(env.workerCtx)
+	}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+	tasks, err := env.svc.ListFailedTasks(env.userCtx, job.ID, service.ListFailedTasksArgs{})
+	require.NoError(err)
+	require.Len(tasks, 2)
+
+	byRev := map[string]types.SearchJobLog{}
+	for _, task := range tasks {
+		byRev[task.Revision] = task
+	}
+
+	require.Equal(api.RepoName("repoa"), byRev["rev1"].RepoName)
+	require.Equal("revision not found: repoa@rev1", byRev["rev1"].FailureMessage)
+
+	require.Equal(api.RepoName("repob"), byRev["rev3"].RepoName)
+	require.Equal(store.TruncateFailureMessage(longMessage), byRev["rev3"].FailureMessage)
+	require.Less(len(byRev["rev3"].FailureMessage), len(longMessage))
+
+	// Failed tasks can be paged through.
+	page, err := env.svc.ListFailedTasks(env.userCtx, job.ID, service.ListFailedTasksArgs{First: 1})
+	require.NoError(err)
+	require.Equal(tasks[:1], page)
+	page, err = env.svc.ListFailedTasks(env.userCtx, job.ID, service.ListFailedTasksArgs{After: page[0].ID, First: 1})
+	require.NoError(err)
+	require.Equal(tasks[1:], page)
+
+	// Other users can't see the failures of the job.
+	otherUserID := dbfixture.User(t, env.db, dbfixture.WithUsername("bob")).ID
+	_, err = env.svc.ListFailedTasks(actor.WithActor(context.Background(), actor.FromUser(otherUserID)), job.ID, service.ListFailedTasksArgs{})
+	require.Error(err)
+}
@@ -9,6 +9,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/sourcegraph/sourcegraph/dev/go-mockgen-transformer/config"
+	"github.com/sourcegraph/sourcegraph/dev/go-mockgen-transformer/strict"
 )
 
 type sliceFlag []string
@@ -23,6 +24,23 @@ func (i *sliceFlag) Set(value string) error {
 }
 
 func main() {
+	// go-mockgen-transformer strict IN OUT writes the mock file IN, generated by
+	// go-mockgen, with the helpers of package strict to OUT.
+	if len(os.Args) == 4 && os.Args[1] == "strict" {
+		src, err := os.ReadFile(os.Args[2])
+		if err != nil {
+			panic(err)
+		}
+		out, err := strict.Generate(src)
+		if err != nil {
+			panic(err)
+		}
+		if err := os.WriteFile(os.Args[3], out, 0o644); err != nil {
+			panic(err)
+		}
+		return
+	}
+
 	payload, err := config.ReadManifest("mockgen.yaml")
 	if err != nil {
 		panic(err)
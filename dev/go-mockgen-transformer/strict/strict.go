@@ -0,0 +1,406 @@
+// Package strict extends the mocks generated by go-mockgen with constructors
+// whose methods fail the test instead of panicking when they aren't stubbed,
+// and with helpers which check that the hooks pushed to the mocks were called.
+package strict
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Generate returns the mock file src, generated by go-mockgen, with the strict
+// helpers of every mock in it. For a mock MockX of the interface X, these are:
+//
+//   - NewStrictMockXWithT(t), which returns a mock whose methods fail t with
+//     their arguments and return zero values, unless overwritten. Unlike
+//     NewStrictMockX, the test reports every unexpected call and keeps running.
+//   - (*MockX).AssertExpectations(t), which fails t for every hook pushed with
+//     PushHook or PushReturn which was not called.
+//
+// The helpers follow the declarations of go-mockgen they belong to, like the
+// constructors of the mock and the methods of its function structs.
+func Generate(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	mocks, err := findMocks(fset, file)
+	if err != nil {
+		return nil, err
+	}
+	if len(mocks) == 0 {
+		return src, nil
+	}
+
+	var inserts []insert
+	for _, m := range mocks {
+		var buf bytes.Buffer
+		if err := constructorTemplate.Execute(&buf, m); err != nil {
+			return nil, err
+		}
+		inserts = append(inserts, insert{offset: m.after, text: buf.String()})
+
+		for _, meth := range m.Methods {
+			buf.Reset()
+			if err := assertTemplate.Execute(&buf, meth); err != nil {
+				return nil, err
+			}
+			inserts = append(inserts, insert{offset: meth.after, text: buf.String()})
+		}
+	}
+	sort.SliceStable(inserts, func(i, j int) bool { return inserts[i].offset < inserts[j].offset })
+
+	var out bytes.Buffer
+	importsEnd, err := addImport(&out, fset, file, src, "testing")
+	if err != nil {
+		return nil, err
+	}
+	last := importsEnd
+	for _, ins := range inserts {
+		out.Write(src[last:ins.offset])
+		out.WriteString("\n")
+		out.WriteString(ins.text)
+		last = ins.offset
+	}
+	out.Write(src[last:])
+	return format.Source(out.Bytes())
+}
+
+// insert is text to insert into the source at offset.
+type insert struct {
+	offset int
+	text   string
+}
+
+type mock struct {
+	// Name is the name of the mock type, e.g. MockUserStore.
+	Name string
+	// Interface is the name of the mocked interface, e.g. UserStore.
+	Interface string
+	// Constructor is the name of the strict constructor of go-mockgen, e.g.
+	// NewStrictMockUserStore.
+	Constructor string
+	Methods     []method
+
+	// after is the offset of the end of the strict constructor of go-mockgen.
+	after int
+}
+
+type method struct {
+	// Name is the name of the method, e.g. GetByID.
+	Name string
+	// Field is the name of the field of the mock which holds the hooks of the
+	// method, and FuncType the name of its type, e.g. GetByIDFunc and
+	// UserStoreGetByIDFunc.
+	Field    string
+	FuncType string
+	Params   []string
+	Results  []string
+
+	// after is the offset of the end of the History method of the function
+	// struct.
+	after int
+}
+
+// ConstructorDoc returns the doc comment of the strict constructor.
+func (m mock) ConstructorDoc() string {
+	return comment(fmt.Sprintf("%sWithT creates a new mock of the %s interface. All methods fail t with their arguments and return zero values for all results, unless overwritten.", m.Constructor, m.Interface))
+}
+
+// AssertExpectationsDoc returns the doc comment of AssertExpectations.
+func (m mock) AssertExpectationsDoc() string {
+	return comment(fmt.Sprintf("AssertExpectations fails t for every hook pushed to the methods of the %s instance with PushHook or PushReturn which was not called.", m.Name))
+}
+
+// comment wraps text into line comments, like go-mockgen does.
+func comment(text string) string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > 76 {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	lines = append(lines, line)
+	return "// " + strings.Join(lines, "\n// ")
+}
+
+func (m method) ParamList() string {
+	params := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		params[i] = fmt.Sprintf("v%d %s", i, p)
+	}
+	return strings.Join(params, ", ")
+}
+
+func (m method) ResultList() string {
+	if len(m.Results) == 0 {
+		return ""
+	}
+	results := make([]string, len(m.Results))
+	for i, r := range m.Results {
+		results[i] = fmt.Sprintf("r%d %s", i, r)
+	}
+	return "(" + strings.Join(results, ", ") + ")"
+}
+
+func (m method) Format() string {
+	verbs := make([]string, len(m.Params))
+	for i := range verbs {
+		verbs[i] = "%v"
+	}
+	return m.Name + "(" + strings.Join(verbs, ", ") + ")"
+}
+
+func (m method) Args() string {
+	args := make([]string, len(m.Params))
+	for i := range args {
+		args[i] = fmt.Sprintf(", v%d", i)
+	}
+	return strings.Join(args, "")
+}
+
+// findMocks returns the mocks of file, in the order of their declarations. A
+// mock is a struct type with a NewStrict constructor, whose fields are
+// pointers to the function structs of its methods, which in turn hold the
+// default hook of the method.
+func findMocks(fset *token.FileSet, file *ast.File) ([]mock, error) {
+	hookTypes := map[string]*ast.FuncType{}
+	constructors := map[string]int{}
+	histories := map[string]int{}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			end := fset.Position(decl.End()).Offset
+			if decl.Recv == nil {
+				constructors[decl.Name.Name] = end
+			} else if decl.Name.Name == "History" {
+				if star, ok := decl.Recv.List[0].Type.(*ast.StarExpr); ok {
+					if ident, ok := star.X.(*ast.Ident); ok {
+						histories[ident.Name] = end
+					}
+				}
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					if len(field.Names) == 1 && field.Names[0].Name == "defaultHook" {
+						if ft, ok := field.Type.(*ast.FuncType); ok {
+							hookTypes[ts.Name.Name] = ft
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var mocks []mock
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			after, ok := constructors["NewStrict"+ts.Name.Name]
+			if !ok {
+				continue
+			}
+			if _, ok := constructors["NewStrict"+ts.Name.Name+"WithT"]; ok {
+				// The file already has the strict helpers of the mock.
+				continue
+			}
+
+			m := mock{Name: ts.Name.Name, Constructor: "NewStrict" + ts.Name.Name, after: after}
+			for _, field := range st.Fields.List {
+				star, ok := field.Type.(*ast.StarExpr)
+				if !ok || len(field.Names) != 1 {
+					continue
+				}
+				ident, ok := star.X.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				hookType, ok := hookTypes[ident.Name]
+				if !ok {
+					continue
+				}
+				history, ok := histories[ident.Name]
+				if !ok {
+					return nil, fmt.Errorf("%s has no History method", ident.Name)
+				}
+
+				name := strings.TrimSuffix(field.Names[0].Name, "Func")
+				meth := method{Name: name, Field: field.Names[0].Name, FuncType: ident.Name, after: history}
+				var err error
+				if meth.Params, err = fieldTypes(fset, hookType.Params); err != nil {
+					return nil, err
+				}
+				if meth.Results, err = fieldTypes(fset, hookType.Results); err != nil {
+					return nil, err
+				}
+				m.Methods = append(m.Methods, meth)
+
+				if m.Interface == "" {
+					m.Interface = strings.TrimSuffix(ident.Name, field.Names[0].Name)
+				}
+			}
+			if len(m.Methods) > 0 {
+				mocks = append(mocks, m)
+			}
+		}
+	}
+	return mocks, nil
+}
+
+// fieldTypes returns the source of the types of the fields, once per name.
+func fieldTypes(fset *token.FileSet, fields *ast.FieldList) ([]string, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	var types []string
+	for _, field := range fields.List {
+		var buf bytes.Buffer
+		if err := printer.Fprint(&buf, fset, field.Type); err != nil {
+			return nil, err
+		}
+		for range max(len(field.Names), 1) {
+			types = append(types, buf.String())
+		}
+	}
+	return types, nil
+}
+
+// addImport writes src up to the end of its imports to out, with path added to
+// them, and returns the offset in src it stopped at. The standard library
+// imports come first, as goimports groups them.
+func addImport(out *bytes.Buffer, fset *token.FileSet, file *ast.File, src []byte, path string) (int, error) {
+	var decl *ast.GenDecl
+	for _, d := range file.Decls {
+		if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			decl = gd
+			break
+		}
+	}
+	if decl == nil {
+		// Without imports, the import goes right after the package clause.
+		end := fset.Position(file.Name.End()).Offset
+		out.Write(src[:end])
+		fmt.Fprintf(out, "\n\nimport %q\n", path)
+		return end, nil
+	}
+
+	end := fset.Position(decl.End()).Offset
+	for _, imp := range file.Imports {
+		if imp.Path.Value == strconv.Quote(path) {
+			out.Write(src[:end])
+			return end, nil
+		}
+	}
+
+	var std, other []string
+	for _, spec := range append(decl.Specs, &ast.ImportSpec{Path: &ast.BasicLit{Value: strconv.Quote(path)}}) {
+		imp := spec.(*ast.ImportSpec)
+		line := imp.Path.Value
+		if imp.Name != nil {
+			line = imp.Name.Name + " " + line
+		}
+		p, _ := strconv.Unquote(imp.Path.Value)
+		if strings.Contains(strings.Split(p, "/")[0], ".") {
+			other = append(other, line)
+		} else {
+			std = append(std, line)
+		}
+	}
+	byPath := func(lines []string) func(i, j int) bool {
+		return func(i, j int) bool {
+			return importPath(lines[i]) < importPath(lines[j])
+		}
+	}
+	sort.Slice(std, byPath(std))
+	sort.Slice(other, byPath(other))
+
+	out.Write(src[:fset.Position(decl.Pos()).Offset])
+	out.WriteString("import (\n")
+	for _, line := range std {
+		out.WriteString("\t" + line + "\n")
+	}
+	if len(std) > 0 && len(other) > 0 {
+		out.WriteString("\n")
+	}
+	for _, line := range other {
+		out.WriteString("\t" + line + "\n")
+	}
+	out.WriteString(")")
+	return end, nil
+}
+
+func importPath(line string) string {
+	return line[strings.Index(line, `"`):]
+}
+
+var constructorTemplate = template.Must(template.New("constructor").Parse(`
+{{.ConstructorDoc}}
+func {{.Constructor}}WithT(t testing.TB) *{{.Name}} {
+	return &{{.Name}}{
+{{- range .Methods}}
+		{{.Field}}: &{{.FuncType}}{
+			defaultHook: func({{.ParamList}}) {{.ResultList}} {
+				t.Errorf("unexpected call of {{$.Name}}.{{.Format}}"{{.Args}})
+				return
+			},
+		},
+{{- end}}
+	}
+}
+
+{{.AssertExpectationsDoc}}
+func (m *{{.Name}}) AssertExpectations(t testing.TB) {
+	t.Helper()
+{{- range .Methods}}
+	m.{{.Field}}.assertExpectations(t, "{{$.Name}}.{{.Name}}")
+{{- end}}
+}
+`))
+
+var assertTemplate = template.Must(template.New("assert").Parse(`
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *{{.FuncType}}) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+`))
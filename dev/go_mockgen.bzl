@@ -1,7 +1,7 @@
 load("//dev:go_mockgen_rule.bzl", "go_mockgen_generate")
 load("//dev:write_generated_to_source_files.bzl", "write_generated_to_source_files")
 
-def go_mockgen(name, manifests, deps, out):
+def go_mockgen(name, manifests, deps, out, strict_helpers = False):
     gen_file = "_" + out
 
     go_mockgen_generate(
@@ -9,6 +9,7 @@ def go_mockgen(name, manifests, deps, out):
         deps = deps,
         out = gen_file,
         manifests = manifests,
+        strict_helpers = strict_helpers,
     )
 
     write_generated_to_source_files(
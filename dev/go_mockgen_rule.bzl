@@ -4,6 +4,12 @@ def _go_mockgen_run(ctx):
     dst = ctx.actions.declare_file(ctx.attr.out)
     config_dst = ctx.actions.declare_file("mockgen.yaml")
 
+    # With strict_helpers, go-mockgen writes to an intermediate file, which the
+    # transformer extends with the strict helpers of the mocks.
+    mockgen_dst = dst
+    if ctx.attr.strict_helpers:
+        mockgen_dst = ctx.actions.declare_file(ctx.attr.out + "_nostrict")
+
     stdlib_root = "{path}/{os}_{arch}".format(
         path = ctx.attr._go_stdlib[GoSource].stdlib.libs[0].path,
         os = ctx.attr._go_stdlib[GoSource].mode.goos,
@@ -16,7 +22,7 @@ def _go_mockgen_run(ctx):
         "--final-generated-file",
         ctx.label.package + "/" + ctx.attr.out[1:],
         "--intermediary-generated-file",
-        dst.path,
+        mockgen_dst.path,
         "--stdlibroot",
         stdlib_root,
         "--goimports",
@@ -65,7 +71,7 @@ def _go_mockgen_run(ctx):
         mnemonic = "GoMockgen",
         arguments = ["--manifest-dir", config_dst.dirname],
         executable = ctx.executable._gomockgen,
-        outputs = [dst],
+        outputs = [mockgen_dst],
         tools = [ctx.executable._goimports],
         inputs = depset(
             direct = action_direct_deps,
@@ -74,6 +80,16 @@ def _go_mockgen_run(ctx):
         progress_message = "Running go-mockgen to generate %s" % dst.short_path,
     )
 
+    if ctx.attr.strict_helpers:
+        ctx.actions.run(
+            mnemonic = "GoMockgenStrict",
+            arguments = ["strict", mockgen_dst.path, dst.path],
+            executable = ctx.executable._gomockgen_transformer,
+            outputs = [dst],
+            inputs = [mockgen_dst],
+            progress_message = "Adding strict helpers to %s" % dst.short_path,
+        )
+
     return [
         DefaultInfo(
             files = depset([dst]),
@@ -95,6 +111,10 @@ go_mockgen_generate = rule(
             allow_files = True,
             mandatory = False,
         ),
+        "strict_helpers": attr.bool(
+            default = False,
+            doc = "Add NewStrictMock*WithT constructors and AssertExpectations helpers to the mocks.",
+        ),
         "_gomockgen_transformer": attr.label(
             default = Label("//dev/go-mockgen-transformer:go-mockgen-transformer"),
             executable = True,
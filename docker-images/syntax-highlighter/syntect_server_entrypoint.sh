@@ -1,7 +1,7 @@
 #!/bin/sh
 
-# This entrypoint exists to inject the environment variable WORKERS as a
-# command line argument.
+# This entrypoint exists to inject the environment variables WORKERS and
+# TIMEOUT as command line arguments.
 
 # Note: {{.Port}} is a templated variable used by http-server-stabilizer
 
@@ -9,6 +9,7 @@ exec /usr/local/bin/http-server-stabilizer \
      -listen=:9238 \
      -prometheus-app-name=syntax_highlighter \
      -workers="$WORKERS" \
+     -timeout="$TIMEOUT" \
      -- \
      env \
      "ROCKET_PORT={{.Port}}" \
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/grafana/regexp"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// PrometheusAlert is a rule of an alert that Sourcegraph's Prometheus image
+// ships with. It fires when Aggregator((Query) Comparator Threshold) is true,
+// or, if DataMustExist is set, when the query returns no data.
+type PrometheusAlert struct {
+	// Name is the name of the alert, <level>_<service>_<observable>. Some
+	// alerts have several rules, e.g. one per Redis instance.
+	Name       string `json:"name"`
+	Service    string `json:"service"`
+	Observable string `json:"observable"`
+	Level      string `json:"level"`
+	// Description describes the alert, with {threshold} standing for the
+	// threshold.
+	Description   string  `json:"description"`
+	Aggregator    string  `json:"aggregator"`
+	Query         string  `json:"query"`
+	Comparator    string  `json:"comparator"`
+	Threshold     float64 `json:"threshold"`
+	For           string  `json:"for,omitempty"`
+	DataMustExist bool    `json:"dataMustExist,omitempty"`
+}
+
+// Expr returns the expression of the alert rule, with the given threshold.
+func (a PrometheusAlert) Expr(threshold float64) string {
+	expr := fmt.Sprintf("%s((%s) %s %s)", a.Aggregator, a.Query, a.Comparator, formatThreshold(threshold))
+	if a.DataMustExist {
+		expr = fmt.Sprintf("(%s) or (absent(%s) == 1)", expr, a.Query)
+	}
+	return expr
+}
+
+// DescriptionWithThreshold returns the description of the alert, with the
+// given threshold.
+func (a PrometheusAlert) DescriptionWithThreshold(threshold float64) string {
+	return strings.Replace(a.Description, "{threshold}", formatThreshold(threshold), 1)
+}
+
+func formatThreshold(threshold float64) string {
+	return strconv.FormatFloat(threshold, 'g', -1, 64)
+}
+
+// PrometheusAlerts returns the rules of the alerts that Sourcegraph's
+// Prometheus image ships with, in the order of the alerts reference.
+var PrometheusAlerts = sync.OnceValue(func() []PrometheusAlert {
+	var alerts []PrometheusAlert
+	if err := yaml.Unmarshal(PrometheusAlertsCatalog, &alerts); err != nil {
+		// The catalog is embedded, and checked by the tests.
+		panic(errors.Wrap(err, "decoding the Prometheus alerts catalog"))
+	}
+	return alerts
+})
+
+// prometheusAlertNames returns the names of the alerts that Sourcegraph's
+// Prometheus image ships with, sorted.
+func prometheusAlertNames() []string {
+	var names []string
+	for _, alert := range PrometheusAlerts() {
+		names = append(names, alert.Name)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// OverriddenAlertsRegex returns a regex that matches the names of the alerts
+// whose thresholds are overridden, or the empty string if there are none.
+func (c *PrometheusAlertingSpec) OverriddenAlertsRegex() string {
+	return alternationRegex(sortedKeys(c.Thresholds))
+}
+
+// SilencedAlertsRegex returns a regex that matches the names of the silenced
+// alerts, or the empty string if there are none.
+func (c *PrometheusAlertingSpec) SilencedAlertsRegex() string {
+	names := slices.Clone(c.Silenced)
+	slices.Sort(names)
+	return alternationRegex(slices.Compact(names))
+}
+
+func alternationRegex(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return "(" + strings.Join(quoted, "|") + ")"
+}
+
+// prometheusRules is a Prometheus rules file.
+type prometheusRules struct {
+	Groups []prometheusRuleGroup `json:"groups"`
+}
+
+type prometheusRuleGroup struct {
+	Name  string           `json:"name"`
+	Rules []prometheusRule `json:"rules"`
+}
+
+type prometheusRule struct {
+	Alert  string            `json:"alert"`
+	Expr   string            `json:"expr"`
+	For    string            `json:"for,omitempty"`
+	Labels map[string]string `json:"labels"`
+}
+
+// AlertRules renders the rules of the alerts whose thresholds are
+// overridden, or returns nil if there are none. The rules are labeled like the
+// ones that the Prometheus image ships with, except for their alert_type,
+// which is "appliance" rather than "builtin", so that the generated config can
+// drop the alerts of the shipped rules instead.
+func (c *PrometheusAlertingSpec) AlertRules() ([]byte, error) {
+	if c == nil || len(c.Thresholds) == 0 {
+		return nil, nil
+	}
+	group := prometheusRuleGroup{Name: "appliance-alert-thresholds"}
+	for _, alert := range PrometheusAlerts() {
+		threshold, ok := c.Thresholds[alert.Name]
+		if !ok {
+			continue
+		}
+		group.Rules = append(group.Rules, prometheusRule{
+			Alert: alert.Name,
+			Expr:  alert.Expr(threshold),
+			For:   alert.For,
+			Labels: map[string]string{
+				"alert_type":   "appliance",
+				"description":  alert.DescriptionWithThreshold(threshold),
+				"level":        alert.Level,
+				"name":         alert.Observable,
+				"service_name": alert.Service,
+			},
+		})
+	}
+	return yaml.Marshal(prometheusRules{Groups: []prometheusRuleGroup{group}})
+}
+
+// Scheme, Host, and PathPrefix return the parts of the URL of the
+// Alertmanager that Prometheus' config needs.
+func (c AlertmanagerSpec) Scheme() string     { return c.url().Scheme }
+func (c AlertmanagerSpec) Host() string       { return c.url().Host }
+func (c AlertmanagerSpec) PathPrefix() string { return c.url().Path }
+
+func (c AlertmanagerSpec) url() *url.URL {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		// Invalid URLs are reported by validation.
+		return &url.URL{}
+	}
+	return u
+}
+
+// BundledAlertmanager returns whether the Alertmanager that runs alongside
+// Prometheus is enabled.
+func (c PrometheusSpec) BundledAlertmanager() bool {
+	return c.Alerting == nil || !c.Alerting.DisableBundledAlertmanager
+}
+
+func (c *PrometheusAlertingSpec) validate() error {
+	var errs error
+	known := prometheusAlertNames()
+	for _, name := range sortedKeys(c.Thresholds) {
+		if err := validateAlertName(known, name); err != nil {
+			errs = errors.Append(errs, errors.Wrap(err, "thresholds"))
+		}
+	}
+	for _, name := range c.Silenced {
+		if err := validateAlertName(known, name); err != nil {
+			errs = errors.Append(errs, errors.Wrap(err, "silenced"))
+		}
+	}
+	for i, am := range c.Alertmanagers {
+		if u, err := url.Parse(am.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			errs = errors.Append(errs, errors.Newf("alertmanagers[%d].url: %q is not an http or https URL", i, am.URL))
+		}
+	}
+	return errs
+}
+
+// validateAlertName checks that an alert is one of the known ones, suggesting
+// the closest ones if it isn't, e.g. for a typo.
+func validateAlertName(known []string, name string) error {
+	if _, found := slices.BinarySearch(known, name); found {
+		return nil
+	}
+	suggestions := closestNames(known, name, 3)
+	if len(suggestions) == 0 {
+		return errors.Newf("unknown alert %q, see the alerts reference for their names", name)
+	}
+	return errors.Newf("unknown alert %q, did you mean %s?", name, strings.Join(quoteAll(suggestions), " or "))
+}
+
+// closestNames returns up to n of the names that are closest to name by edit
+// distance, closest first, among those close enough to be a typo of it or
+// that it is a prefix of.
+func closestNames(names []string, name string, n int) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	maxDistance := max(len(name)/4, 2)
+	var candidates []candidate
+	for _, other := range names {
+		if d := editDistance(name, other); d <= maxDistance || strings.HasPrefix(other, name) {
+			candidates = append(candidates, candidate{other, d})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+	var closest []string
+	for i := 0; i < len(candidates) && i < n; i++ {
+		closest = append(closest, candidates[i].name)
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func quoteAll(names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return quoted
+}
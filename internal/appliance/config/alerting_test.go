@@ -0,0 +1,112 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusAlerts(t *testing.T) {
+	alerts := PrometheusAlerts()
+	require.NotEmpty(t, alerts)
+	for _, alert := range alerts {
+		assert.Equal(t, alert.Level+"_"+alert.Service+"_"+alert.Observable, alert.Name)
+		assert.Contains(t, []string{"warning", "critical"}, alert.Level, alert.Name)
+		assert.Contains(t, []string{"<", "<=", ">", ">="}, alert.Comparator, alert.Name)
+		assert.Contains(t, alert.Description, "{threshold}", alert.Name)
+		assert.NotEmpty(t, alert.Query, alert.Name)
+		if alert.For != "" {
+			assert.Regexp(t, prometheusDurationPattern, alert.For, alert.Name)
+		}
+	}
+}
+
+func TestPrometheusAlertExpr(t *testing.T) {
+	alerts := map[string]PrometheusAlert{}
+	for _, alert := range PrometheusAlerts() {
+		alerts[alert.Name] = alert
+	}
+
+	diskSpace := alerts["critical_gitserver_disk_space_remaining"]
+	assert.Equal(t, "min(((src_gitserver_disk_space_available / src_gitserver_disk_space_total) * 100) < 10)", diskSpace.Expr(diskSpace.Threshold))
+	assert.Equal(t, "min(((src_gitserver_disk_space_available / src_gitserver_disk_space_total) * 100) < 12.5)", diskSpace.Expr(12.5))
+	assert.Equal(t, "gitserver: less than 12.5% disk space remaining for 10m0s", diskSpace.DescriptionWithThreshold(12.5))
+
+	janitor := alerts["warning_worker_worker_job_codeintel-upload-janitor_count"]
+	assert.Equal(t,
+		`(min((sum(src_worker_jobs{job=~"^worker.*",job_name="codeintel-upload-janitor"})) < 2)) or (absent(sum(src_worker_jobs{job=~"^worker.*",job_name="codeintel-upload-janitor"})) == 1)`,
+		janitor.Expr(2))
+}
+
+func TestPrometheusAlertingSpecAlertRules(t *testing.T) {
+	rules, err := (*PrometheusAlertingSpec)(nil).AlertRules()
+	require.NoError(t, err)
+	assert.Nil(t, rules)
+
+	rules, err = (&PrometheusAlertingSpec{
+		Thresholds: map[string]float64{"warning_gitserver_disk_space_remaining": 25},
+	}).AlertRules()
+	require.NoError(t, err)
+	assert.Equal(t, `groups:
+- name: appliance-alert-thresholds
+  rules:
+  - alert: warning_gitserver_disk_space_remaining
+    expr: min(((src_gitserver_disk_space_available / src_gitserver_disk_space_total)
+      * 100) < 25)
+    labels:
+      alert_type: appliance
+      description: 'gitserver: less than 25% disk space remaining'
+      level: warning
+      name: disk_space_remaining
+      service_name: gitserver
+`, string(rules))
+}
+
+func TestPrometheusAlertingSpecValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		spec     PrometheusAlertingSpec
+		wantErrs []string
+	}{
+		{
+			name: "valid",
+			spec: PrometheusAlertingSpec{
+				Thresholds:                 map[string]float64{"warning_gitserver_disk_space_remaining": 25},
+				Silenced:                   []string{"critical_redis_redis-store_up"},
+				Alertmanagers:              []AlertmanagerSpec{{URL: "http://alertmanager.monitoring:9093"}},
+				DisableBundledAlertmanager: true,
+			},
+		},
+		{
+			name: "typo",
+			spec: PrometheusAlertingSpec{
+				Thresholds: map[string]float64{"warning_gitserver_disk_space_remaning": 25},
+			},
+			wantErrs: []string{`thresholds: unknown alert "warning_gitserver_disk_space_remaning", did you mean "warning_gitserver_disk_space_remaining" or "critical_gitserver_disk_space_remaining"?`},
+		},
+		{
+			name: "unknown",
+			spec: PrometheusAlertingSpec{
+				Silenced:      []string{"warning_frontend_everything_is_fine"},
+				Alertmanagers: []AlertmanagerSpec{{URL: "alertmanager:9093"}},
+			},
+			wantErrs: []string{
+				`silenced: unknown alert "warning_frontend_everything_is_fine", see the alerts reference for their names`,
+				`alertmanagers[0].url: "alertmanager:9093" is not an http or https URL`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.validate()
+			if len(tc.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tc.wantErrs {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
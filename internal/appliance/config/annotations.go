@@ -4,4 +4,86 @@ const (
 	AnnotationKeyManaged        = "appliance.sourcegraph.com/managed"
 	AnnotationKeyCurrentVersion = "appliance.sourcegraph.com/currentVersion"
 	AnnotationKeyConfigHash     = "appliance.sourcegraph.com/configHash"
+
+	// AnnotationKeyLastApplied is set on every object that the appliance
+	// manages, and records what the appliance last set on it as JSON, so
+	// that changes made by hand can be told apart from fields that the
+	// appliance doesn't manage. The values of Secrets and ConfigMaps are
+	// recorded as hashes.
+	AnnotationKeyLastApplied = "appliance.sourcegraph.com/lastApplied"
+
+	// AnnotationKeyValidationErrors is set on the spec ConfigMap while its
+	// spec is invalid, and lists every problem found.
+	AnnotationKeyValidationErrors = "appliance.sourcegraph.com/validationErrors"
+
+	// AnnotationKeyImageVersion is set on the spec ConfigMap while the default
+	// images of another version than the requested one are used, because of
+	// ImageVersionFallback.
+	AnnotationKeyImageVersion = "appliance.sourcegraph.com/imageVersion"
+
+	// AnnotationKeyMaintenanceMode is set on the spec ConfigMap while
+	// Sourcegraph is scaled down for maintenance.
+	AnnotationKeyMaintenanceMode = "appliance.sourcegraph.com/maintenanceMode"
+
+	// AnnotationKeyReplicasBeforeMaintenance is set on Deployments and
+	// StatefulSets that are scaled down for maintenance, and records the
+	// number of replicas to restore afterwards.
+	AnnotationKeyReplicasBeforeMaintenance = "appliance.sourcegraph.com/replicasBeforeMaintenance"
+
+	// AnnotationKeyFailedBackups is set on the spec ConfigMap while the last
+	// scheduled backup of a database has failed, and lists those databases.
+	AnnotationKeyFailedBackups = "appliance.sourcegraph.com/failedBackups"
+
+	// AnnotationKeyRestoreFrom is set on database restore Jobs, and records
+	// the backup that they restore.
+	AnnotationKeyRestoreFrom = "appliance.sourcegraph.com/restoreFrom"
+
+	// AnnotationKeyStatus is set on the spec ConfigMap after every reconcile,
+	// and holds its SourcegraphStatus as JSON.
+	AnnotationKeyStatus = "appliance.sourcegraph.com/status"
+
+	// AnnotationKeyResolvedConfig is set on the spec ConfigMap after every
+	// successful reconcile, and holds the ResolvedConfig of its spec as YAML.
+	AnnotationKeyResolvedConfig = "appliance.sourcegraph.com/resolvedConfig"
+
+	// AnnotationKeyReady mirrors the status of the Ready condition, "True" or
+	// "False", so that it can be waited for with kubectl wait --for=jsonpath.
+	AnnotationKeyReady = "appliance.sourcegraph.com/ready"
+
+	// AnnotationKeyConfigChecksum is set on the pod templates of services
+	// whose config file is generated, e.g. redis, and holds a checksum of it,
+	// so that changing the config rolls their pods.
+	AnnotationKeyConfigChecksum = "appliance.sourcegraph.com/configChecksum"
+
+	// AnnotationKeyDependencyChecksum is set on the pod templates of
+	// Deployments, StatefulSets, and DaemonSets that reference Secrets or
+	// ConfigMaps, and holds a checksum of their content, so that rotating a
+	// credential rolls exactly the pods that use it.
+	AnnotationKeyDependencyChecksum = "appliance.sourcegraph.com/dependencyChecksum"
+
+	// AnnotationKeyRotateNow can be set on the spec ConfigMap to restart
+	// every service. Its value is part of every dependency checksum, so
+	// setting it to a new value, e.g. the current time, rolls every pod once.
+	AnnotationKeyRotateNow = "appliance.sourcegraph.com/rotateNow"
+
+	// LabelKeyExtraWorker is set on the Deployments of extra workers, and
+	// holds their key in WorkerSpec.ExtraWorkers, so that those removed from
+	// the spec can be found and deleted.
+	LabelKeyExtraWorker = "appliance.sourcegraph.com/extraWorker"
+
+	// AnnotationKeyConfirmDeletion can be set on the spec ConfigMap to let it
+	// be deleted while its DataRetentionPolicy is Retain. The data is kept
+	// either way.
+	AnnotationKeyConfirmDeletion = "appliance.sourcegraph.com/confirmDeletion"
+
+	// LabelKeyRetainedFrom is set on the PersistentVolumeClaims and Secrets
+	// that were kept because of DataRetentionPolicyRetain, and holds the name
+	// of the spec ConfigMap that they were kept from. It is removed when the
+	// appliance adopts them again.
+	LabelKeyRetainedFrom = "appliance.sourcegraph.com/retainedFrom"
+
+	// FinalizerDataRetention is set on the spec ConfigMap while its
+	// DataRetentionPolicy is Retain, so that deleting it can be blocked
+	// until it is confirmed, and what it retains labeled.
+	FinalizerDataRetention = "appliance.sourcegraph.com/dataRetention"
 )
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// The API versions of a spec. A spec selects one with its apiVersion field,
+// and specs without one are v1alpha1, which is the shape of the Go types of
+// this package. Other versions are converted to it when they are decoded, so
+// the rest of the appliance only ever sees one shape.
+//
+// To rename a field, add a new version whose renames move it, make it
+// LatestAPIVersion, and keep every older version in apiVersions, so that
+// existing specs keep working.
+const (
+	APIGroup        = "appliance.sourcegraph.com"
+	SourcegraphKind = "Sourcegraph"
+
+	APIVersionV1Alpha1 = APIGroup + "/v1alpha1"
+	APIVersionV1Beta1  = APIGroup + "/v1beta1"
+
+	// LatestAPIVersion is the version that specs are converted to on
+	// admission, see DefaultSpecYAML.
+	LatestAPIVersion = APIVersionV1Beta1
+)
+
+// fieldRenames maps the JSON keys of the fields of a struct type of this
+// package to their key in an API version.
+type fieldRenames map[reflect.Type]map[string]string
+
+// apiVersions are the supported API versions, with the fields that they name
+// differently from v1alpha1.
+var apiVersions = map[string]fieldRenames{
+	APIVersionV1Alpha1: nil,
+
+	// v1beta1 drops the "Config" suffix of the fields that every service has.
+	APIVersionV1Beta1: {
+		reflect.TypeOf(StandardConfig{}): {
+			"containerConfig":        "containers",
+			"persistentVolumeConfig": "storage",
+			"podTemplateConfig":      "podTemplate",
+		},
+	},
+}
+
+// SupportedAPIVersions returns the API versions that a spec may use, in order.
+func SupportedAPIVersions() []string {
+	versions := make([]string, 0, len(apiVersions))
+	for version := range apiVersions {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// UnknownAPIVersionError is returned for a spec with an apiVersion or kind that
+// the appliance doesn't support, e.g. one written for a newer appliance.
+type UnknownAPIVersionError struct {
+	APIVersion string
+	Kind       string
+}
+
+func (e *UnknownAPIVersionError) Error() string {
+	if e.Kind != "" && e.Kind != SourcegraphKind {
+		return fmt.Sprintf("unsupported kind %q, expected %q", e.Kind, SourcegraphKind)
+	}
+	return fmt.Sprintf("unsupported apiVersion %q, this appliance supports %s", e.APIVersion, strings.Join(SupportedAPIVersions(), ", "))
+}
+
+// ConvertSpecYAML converts a spec to apiVersion, and sets its apiVersion and
+// kind. Fields that apiVersion doesn't rename are copied as they are, including
+// unknown ones. The spec is returned as it is if it is already in apiVersion.
+func ConvertSpecYAML(data []byte, apiVersion string) ([]byte, error) {
+	to, ok := apiVersions[apiVersion]
+	if !ok {
+		return nil, &UnknownAPIVersionError{APIVersion: apiVersion}
+	}
+	doc, from, err := decodeVersionedDoc(data)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	if doc["apiVersion"] == apiVersion && doc["kind"] == SourcegraphKind {
+		return data, nil
+	}
+
+	sgType := reflect.TypeOf(Sourcegraph{})
+	if err := renameFields(doc, sgType, apiVersions[from], true, ""); err != nil {
+		return nil, err
+	}
+	if err := renameFields(doc, sgType, to, false, ""); err != nil {
+		return nil, err
+	}
+	doc["apiVersion"] = apiVersion
+	doc["kind"] = SourcegraphKind
+	return yaml.Marshal(doc)
+}
+
+// DefaultSpecYAML is applied to specs on admission. It converts them to
+// LatestAPIVersion, so that specs are stored in the same version regardless of
+// the version they were written in.
+//
+// The defaults of the spec's size are deliberately not written to it. They are
+// merged over the spec every time it is decoded, so that an upgraded appliance
+// applies its new defaults to specs that don't override them.
+func DefaultSpecYAML(data []byte) ([]byte, error) {
+	return ConvertSpecYAML(data, LatestAPIVersion)
+}
+
+// convertToInternalVersion converts a spec in any supported API version to
+// v1alpha1, which is the shape of Sourcegraph, before it is decoded. Specs in
+// v1alpha1 are returned as they are.
+func convertToInternalVersion(data []byte) ([]byte, error) {
+	doc, from, err := decodeVersionedDoc(data)
+	if err != nil || apiVersions[from] == nil {
+		return data, err
+	}
+	if err := renameFields(doc, reflect.TypeOf(Sourcegraph{}), apiVersions[from], true, ""); err != nil {
+		return nil, err
+	}
+	doc["apiVersion"] = APIVersionV1Alpha1
+	return json.Marshal(doc)
+}
+
+// decodeVersionedDoc decodes a spec as a generic document, and returns the API
+// version that it is in.
+func decodeVersionedDoc(data []byte) (map[string]any, string, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, "", err
+	}
+	apiVersion, _ := doc["apiVersion"].(string)
+	kind, _ := doc["kind"].(string)
+	if apiVersion == "" {
+		apiVersion = APIVersionV1Alpha1
+	}
+	if _, ok := apiVersions[apiVersion]; !ok || (kind != "" && kind != SourcegraphKind) {
+		return nil, "", &UnknownAPIVersionError{APIVersion: apiVersion, Kind: kind}
+	}
+	return doc, apiVersion, nil
+}
+
+// renameFields renames the fields of doc, a value of type t decoded as a
+// generic document, from their v1alpha1 keys to those of renames, or back if
+// reverse is set. The structs of other packages, e.g. Kubernetes types, are
+// left alone. A document with both keys of a renamed field is rejected, since
+// one of them would be lost.
+func renameFields(doc any, t reflect.Type, renames fieldRenames, reverse bool, path string) error {
+	if len(renames) == 0 {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		m, _ := doc.(map[string]any)
+		for key, value := range m {
+			if err := renameFields(value, t.Elem(), renames, reverse, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		s, _ := doc.([]any)
+		for i, value := range s {
+			if err := renameFields(value, t.Elem(), renames, reverse, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		m, ok := doc.(map[string]any)
+		if !ok || t.PkgPath() != configPkgPath {
+			return nil
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if field.Anonymous && name == "" {
+				// Embedded structs are inlined.
+				if err := renameFields(m, field.Type, renames, reverse, path); err != nil {
+					return err
+				}
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			key := name
+			if renamed, ok := renames[t][name]; ok {
+				from, to := name, renamed
+				if reverse {
+					from, to = renamed, name
+				}
+				if value, ok := m[from]; ok {
+					if _, ok := m[to]; ok {
+						return errors.Newf("%s: only one of %s and %s can be set", strings.TrimPrefix(path, "."), from, to)
+					}
+					delete(m, from)
+					m[to] = value
+				}
+				key = to
+			}
+			if err := renameFields(m[key], field.Type, renames, reverse, path+"."+key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+// TestConvertSpecYAML_RoundTrip converts a spec with every field set to every
+// API version and back, and checks that nothing is lost or changed on the way.
+func TestConvertSpecYAML_RoundTrip(t *testing.T) {
+	var sg Sourcegraph
+	fillEveryField(reflect.ValueOf(&sg).Elem(), map[reflect.Type]bool{})
+	sg.APIVersion = APIVersionV1Alpha1
+	sg.Kind = SourcegraphKind
+	data, err := json.Marshal(sg)
+	require.NoError(t, err)
+	// Decode the spec once, so that the values of other packages are
+	// normalized, e.g. the cached string of a resource.Quantity.
+	var want Sourcegraph
+	require.NoError(t, json.Unmarshal(data, &want))
+
+	for _, version := range SupportedAPIVersions() {
+		t.Run(version, func(t *testing.T) {
+			converted, err := ConvertSpecYAML(data, version)
+			require.NoError(t, err)
+			back, err := ConvertSpecYAML(converted, APIVersionV1Alpha1)
+			require.NoError(t, err)
+
+			var got Sourcegraph
+			require.NoError(t, yaml.Unmarshal(back, &got))
+			require.Equal(t, want, got)
+
+			// Every field survives decoding the converted spec too.
+			decoded, warnings, err := DecodeConfigYAML(converted, DecodeOptions{Strict: true})
+			require.NoError(t, err)
+			require.Empty(t, warnings)
+			decoded.APIVersion = APIVersionV1Alpha1
+			require.Equal(t, want, decoded)
+		})
+	}
+}
+
+func TestConvertSpecYAML_V1Beta1(t *testing.T) {
+	v1alpha1 := []byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  frontend:
+    containerConfig:
+      frontend:
+        image: frontend:custom
+    podTemplateConfig:
+      nodeSelector:
+        pool: web
+  gitServer:
+    persistentVolumeConfig:
+      storageSize: 500Gi
+  pgsql:
+    backup:
+      persistentVolumeConfig:
+        storageSize: 100Gi
+  worker:
+    extraWorkers:
+      batches:
+        containerConfig:
+          worker:
+            image: worker:custom
+`)
+	v1beta1, err := ConvertSpecYAML(v1alpha1, APIVersionV1Beta1)
+	require.NoError(t, err)
+	require.YAMLEq(t, `
+apiVersion: appliance.sourcegraph.com/v1beta1
+kind: Sourcegraph
+spec:
+  requestedVersion: "5.3.9104"
+  frontend:
+    containers:
+      frontend:
+        image: frontend:custom
+    podTemplate:
+      nodeSelector:
+        pool: web
+  gitServer:
+    storage:
+      storageSize: 500Gi
+  pgsql:
+    backup:
+      persistentVolumeConfig:
+        storageSize: 100Gi
+  worker:
+    extraWorkers:
+      batches:
+        containers:
+          worker:
+            image: worker:custom
+`, string(v1beta1), "only the fields of StandardConfig are renamed")
+
+	// Both versions decode to the same config.
+	want, err := NewConfigFromYAML(v1alpha1)
+	require.NoError(t, err)
+	got, err := NewConfigFromYAML(v1beta1)
+	require.NoError(t, err)
+	want.TypeMeta = got.TypeMeta
+	require.Equal(t, want, got)
+
+	// A spec that is already in the version is kept as it is.
+	again, err := ConvertSpecYAML(v1beta1, APIVersionV1Beta1)
+	require.NoError(t, err)
+	require.Equal(t, string(v1beta1), string(again))
+}
+
+func TestConvertSpecYAML_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{
+			name: "unknown version",
+			spec: "apiVersion: appliance.sourcegraph.com/v2\nspec: {}\n",
+			err:  `unsupported apiVersion "appliance.sourcegraph.com/v2", this appliance supports appliance.sourcegraph.com/v1alpha1, appliance.sourcegraph.com/v1beta1`,
+		},
+		{
+			name: "unknown kind",
+			spec: "apiVersion: appliance.sourcegraph.com/v1beta1\nkind: Deployment\n",
+			err:  `unsupported kind "Deployment", expected "Sourcegraph"`,
+		},
+		{
+			name: "both keys of a renamed field",
+			spec: "apiVersion: appliance.sourcegraph.com/v1beta1\nspec:\n  frontend:\n    containers: {}\n    containerConfig: {}\n",
+			err:  "spec.frontend: only one of containers and containerConfig can be set",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ConvertSpecYAML([]byte(tc.spec), APIVersionV1Alpha1)
+			require.EqualError(t, err, tc.err)
+			_, err = NewConfigFromYAML([]byte(tc.spec))
+			require.EqualError(t, err, tc.err)
+		})
+	}
+
+	_, err := ConvertSpecYAML([]byte("spec: {}\n"), "v1")
+	var versionErr *UnknownAPIVersionError
+	require.ErrorAs(t, err, &versionErr)
+}
+
+func TestDefaultSpecYAML(t *testing.T) {
+	defaulted, err := DefaultSpecYAML([]byte("spec:\n  frontend:\n    containerConfig: {}\n"))
+	require.NoError(t, err)
+	require.YAMLEq(t, `
+apiVersion: appliance.sourcegraph.com/v1beta1
+kind: Sourcegraph
+spec:
+  frontend:
+    containers: {}
+`, string(defaulted), "the defaults of the size are not written to the spec")
+}
+
+// fillEveryField sets every field of v, which must be settable, to a value
+// that isn't the zero value, so that it is encoded even if it is omitted when
+// empty. Like fill, it leaves the structs of other packages zero.
+func fillEveryField(v reflect.Value, filling map[reflect.Type]bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type().PkgPath() != configPkgPath || filling[v.Type()] {
+			return
+		}
+		filling[v.Type()] = true
+		defer delete(filling, v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillEveryField(v.Field(i), filling)
+			}
+		}
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillEveryField(v.Elem(), filling)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		fillEveryField(key, filling)
+		value := reflect.New(v.Type().Elem()).Elem()
+		fillEveryField(value, filling)
+		v.SetMapIndex(key, value)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillEveryField(v.Index(0), filling)
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	}
+}
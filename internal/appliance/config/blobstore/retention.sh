@@ -0,0 +1,48 @@
+#!/bin/sh
+# Expires the objects of the blobstore, which stores each bucket in a
+# directory of /data. Every line of $RULES_FILE is a bucket or prefix and the
+# number of minutes its objects are kept. While the volume is fuller than
+# $HIGH_WATER_MARK_PERCENT, the oldest objects of those prefixes are deleted
+# early. The usage of the volume is served as Prometheus metrics on
+# $METRICS_PORT.
+set -u
+
+metrics_dir=/tmp/metrics
+mkdir -p "$metrics_dir"
+httpd -p "$METRICS_PORT" -h "$metrics_dir"
+
+# usage prints the capacity and used bytes of the volume.
+usage() {
+  df -P -k /data | awk 'NR == 2 { print $2 * 1024, $3 * 1024 }'
+}
+
+used_percent() {
+  df -P /data | awk 'NR == 2 { sub("%", "", $5); print $5 }'
+}
+
+while true; do
+  while read -r prefix minutes; do
+    if [ -e "/data/$prefix" ] && [ "$minutes" -gt 0 ]; then
+      find "/data/$prefix" -type f -mmin "+$minutes" -delete
+    fi
+  done <"$RULES_FILE"
+
+  while [ "$(used_percent)" -gt "$HIGH_WATER_MARK_PERCENT" ]; do
+    oldest=$(while read -r prefix _; do
+      [ -e "/data/$prefix" ] && find "/data/$prefix" -type f -exec stat -c '%Y %n' {} +
+    done <"$RULES_FILE" | sort -n | head -n 100 | cut -d ' ' -f 2-)
+    [ -n "$oldest" ] || break
+    echo "$oldest" | while read -r file; do rm -f "$file"; done
+  done
+
+  usage | awk '{
+    print "# HELP blobstore_volume_capacity_bytes Capacity of the blobstore volume."
+    print "# TYPE blobstore_volume_capacity_bytes gauge"
+    print "blobstore_volume_capacity_bytes", $1
+    print "# HELP blobstore_volume_used_bytes Used bytes of the blobstore volume."
+    print "# TYPE blobstore_volume_used_bytes gauge"
+    print "blobstore_volume_used_bytes", $2
+  }' >"$metrics_dir/metrics.tmp" && mv "$metrics_dir/metrics.tmp" "$metrics_dir/metrics"
+
+  sleep "$CHECK_INTERVAL_SECONDS"
+done
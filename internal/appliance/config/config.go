@@ -1,6 +1,12 @@
 package config
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"encoding/json"
+
+	"github.com/grafana/regexp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
 
 type StandardComponent interface {
 	Disableable
@@ -9,6 +15,29 @@ type StandardComponent interface {
 	GetPodTemplateConfig() PodTemplateConfig
 	GetServiceAccountAnnotations() map[string]string
 	GetPrometheusPort() *int
+	GetTLSConfig() *TLSConfig
+	GetImagePullSecrets() []string
+	GetServiceAccountConfig() ServiceAccountConfig
+	GetPodSecurityContext() *corev1.PodSecurityContext
+	GetContainerSecurityContext() *corev1.SecurityContext
+	GetEnv() map[string]string
+	GetEnvFrom() []SecretOrConfigMapRef
+	GetSidecars() []corev1.Container
+	GetExtraInitContainers() []corev1.Container
+	GetExtraVolumes() []corev1.Volume
+	GetExtraVolumeMounts() []corev1.VolumeMount
+	GetPriorityClassName() *string
+	GetDisruptionBudget() *DisruptionBudgetConfig
+	GetLabels() map[string]string
+	GetAnnotations() map[string]string
+	GetIPFamilyPolicy() corev1.IPFamilyPolicy
+	GetIPFamilies() []corev1.IPFamily
+	GetDisableProxy() bool
+	GetTopologySpreadConstraints() []corev1.TopologySpreadConstraint
+	GetLivenessProbe() *ProbeConfig
+	GetReadinessProbe() *ProbeConfig
+	GetStartupProbe() *ProbeConfig
+	GetStrategy() *StrategyConfig
 }
 
 type Disableable interface {
@@ -22,6 +51,126 @@ type StandardConfig struct {
 	PodTemplateConfig         PodTemplateConfig          `json:"podTemplateConfig,omitempty"`
 	PrometheusPort            *int                       `json:"prometheusPort,omitempty"`
 	ServiceAccountAnnotations map[string]string          `json:"serviceAccountAnnotations,omitempty"`
+	TLS                       *TLSConfig                 `json:"tls,omitempty"`
+
+	// ImagePullSecrets are appended to SourcegraphSpec.ImagePullSecrets for
+	// this service's pods.
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+
+	// ServiceAccount configures the ServiceAccount that this service's pods
+	// run as, e.g. to grant them cloud credentials via workload identity.
+	ServiceAccount *ServiceAccountConfig `json:"serviceAccount,omitempty"`
+
+	// PodSecurityContext and ContainerSecurityContext are the security
+	// contexts of this service's pods, and of every container in them. Each
+	// replaces the service's default from NewDefaultConfig entirely, rather
+	// than being merged with it.
+	PodSecurityContext       *PodSecurityContext `json:"podSecurityContext,omitempty"`
+	ContainerSecurityContext *SecurityContext    `json:"containerSecurityContext,omitempty"`
+
+	// Env and EnvFrom add env vars to the main container of this service's
+	// pods. Env vars that the appliance sets itself, e.g. database connection
+	// details, take precedence.
+	Env     map[string]string      `json:"env,omitempty"`
+	EnvFrom []SecretOrConfigMapRef `json:"envFrom,omitempty"`
+
+	// Sidecars are added as-is to this service's pods, after the containers
+	// that the appliance manages. ExtraVolumes are added to the pods too, and
+	// can be mounted into the main container with ExtraVolumeMounts, e.g. to
+	// share a socket with a sidecar.
+	Sidecars          []corev1.Container   `json:"sidecars,omitempty"`
+	ExtraVolumes      []corev1.Volume      `json:"extraVolumes,omitempty"`
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+
+	// ExtraInitContainers are added as-is to this service's pods, after the
+	// init containers that the appliance manages, and may mount ExtraVolumes
+	// too.
+	ExtraInitContainers []corev1.Container `json:"extraInitContainers,omitempty"`
+
+	// PriorityClassName overrides SourcegraphSpec.PriorityClassName for this
+	// service's pods. Set it to the empty string to use no PriorityClass.
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// DisruptionBudget configures the PodDisruptionBudget of services that
+	// can run more than one replica.
+	DisruptionBudget *DisruptionBudgetConfig `json:"disruptionBudget,omitempty"`
+
+	// Labels and Annotations are added to this service's objects and pod
+	// templates, overriding SourcegraphSpec.Labels and
+	// SourcegraphSpec.Annotations.
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// IPFamilyPolicy and IPFamilies override SourcegraphSpec.IPFamilyPolicy
+	// and SourcegraphSpec.IPFamilies for this service's Services.
+	IPFamilyPolicy corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+	IPFamilies     []corev1.IPFamily     `json:"ipFamilies,omitempty"`
+
+	// DisableProxy stops this service from using SourcegraphSpec.HTTPProxy
+	// and SourcegraphSpec.HTTPSProxy, e.g. for gitserver if the proxy breaks
+	// the git protocol.
+	DisableProxy bool `json:"disableProxy,omitempty"`
+
+	// TopologySpreadConstraints spread this service's pods across nodes or
+	// zones. By default, services running more than one replica are spread
+	// across nodes where possible. Constraints without a LabelSelector select
+	// the service's pods. Set an empty list to not spread pods at all.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// LivenessProbe, ReadinessProbe, and StartupProbe override the timing of
+	// the probes of the main container of this service's pods, e.g. to give
+	// a large gitserver more time to start. Unset fields keep the appliance's
+	// defaults, and the probe handlers stay managed by the appliance. A
+	// startup probe is added to containers without one using the handler of
+	// their liveness probe.
+	LivenessProbe  *ProbeConfig `json:"livenessProbe,omitempty"`
+	ReadinessProbe *ProbeConfig `json:"readinessProbe,omitempty"`
+	StartupProbe   *ProbeConfig `json:"startupProbe,omitempty"`
+
+	// Strategy configures how a new version of this service's pods is rolled
+	// out. Unset fields keep the service's default from NewDefaultConfig.
+	Strategy *StrategyConfig `json:"strategy,omitempty"`
+}
+
+// RolloutStrategyType is how a service rolls out new versions of its pods.
+type RolloutStrategyType string
+
+const (
+	// RollingUpdateStrategy replaces pods gradually. Deployments are bound by
+	// MaxSurge and MaxUnavailable, and StatefulSets replace one pod at a
+	// time, from the highest ordinal down to Partition.
+	RollingUpdateStrategy RolloutStrategyType = "RollingUpdate"
+	// RecreateStrategy terminates the old pods before starting new ones, so
+	// that two versions never run at the same time, e.g. against the same
+	// database files. StatefulSets already terminate each pod before
+	// replacing it, so for them this is the same as RollingUpdateStrategy.
+	RecreateStrategy RolloutStrategyType = "Recreate"
+	// OnDeleteStrategy only replaces the pods of a StatefulSet when they are
+	// deleted, e.g. by an admin. Deployments don't support it.
+	OnDeleteStrategy RolloutStrategyType = "OnDelete"
+)
+
+// StrategyConfig configures the rollout of a service. MaxSurge and
+// MaxUnavailable only apply to Deployments rolling out with
+// RollingUpdateStrategy, and Partition only to StatefulSets, e.g. to roll a
+// new version out to the last gitserver shards first.
+type StrategyConfig struct {
+	Type           RolloutStrategyType `json:"type,omitempty"`
+	MaxSurge       *intstr.IntOrString `json:"maxSurge,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// Partition is the lowest ordinal of the StatefulSet pods that are
+	// updated. Pods with a lower ordinal keep running the previous version.
+	Partition *int32 `json:"partition,omitempty"`
+}
+
+// ProbeConfig overrides the timing of a probe. See corev1.Probe for the
+// meaning of each field.
+type ProbeConfig struct {
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	TimeoutSeconds      *int32 `json:"timeoutSeconds,omitempty"`
+	PeriodSeconds       *int32 `json:"periodSeconds,omitempty"`
+	SuccessThreshold    *int32 `json:"successThreshold,omitempty"`
+	FailureThreshold    *int32 `json:"failureThreshold,omitempty"`
 }
 
 type ContainerConfig struct {
@@ -39,11 +188,97 @@ type ContainerConfig struct {
 	EnvVars map[string]string `json:"envVars,omitempty"`
 }
 
+var containerImageRegexp = regexp.MustCompile(`(.+)/([^:]+):(.+)`)
+
+// ImageFor returns the image of a container whose default image is
+// defaultImage. Image, if set, replaces the name, tag, and digest of the
+// default image, which keeps its repository.
+func (c ContainerConfig) ImageFor(defaultImage string) string {
+	if c.Image == "" {
+		return defaultImage
+	}
+	return containerImageRegexp.ReplaceAllString(defaultImage, "$1/"+c.Image)
+}
+
 type PersistentVolumeConfig struct {
 	StorageSize      string  `json:"storageSize,omitempty"`
 	StorageClassName *string `json:"storageClassName,omitempty"`
 }
 
+// TLSConfig configures a service to serve HTTPS. The certificate is mounted at
+// /etc/sourcegraph/tls, and SRC_TLS_CERT_FILE and SRC_TLS_KEY_FILE point the
+// service's main HTTP listener at it. Debug and metrics listeners stay plain
+// HTTP.
+type TLSConfig struct {
+	// SecretName is the name of a Secret in the same namespace containing
+	// tls.crt and tls.key. If it also contains ca.crt, that CA is added to
+	// the trust bundle of every service.
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// ServiceAccountConfig configures a service's ServiceAccount. Set at most one
+// of Name and Annotations.
+type ServiceAccountConfig struct {
+	// Name is the name of an existing ServiceAccount to run as. The appliance
+	// won't create or modify it, and deletes the ServiceAccount it would
+	// otherwise manage for this service.
+	Name string `json:"name,omitempty"`
+
+	// Annotations are added to the ServiceAccount that the appliance manages
+	// for this service, e.g. eks.amazonaws.com/role-arn for IRSA or
+	// iam.gke.io/gcp-service-account for GKE workload identity.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// DisruptionBudgetConfig configures a service's PodDisruptionBudget. By
+// default, one is only created while the service runs more than one replica,
+// and allows one pod at a time to be disrupted. Setting this config creates one
+// regardless of the replica count, unless Disabled is set. Set at most one of
+// MinAvailable and MaxUnavailable.
+type DisruptionBudgetConfig struct {
+	Disabled       bool                `json:"disabled,omitempty"`
+	MinAvailable   *intstr.IntOrString `json:"minAvailable,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// SecretOrConfigMapRef sets an env var from a key of a Secret or ConfigMap in
+// the same namespace. Set exactly one of SecretName and ConfigMapName.
+type SecretOrConfigMapRef struct {
+	// Name is the name of the env var.
+	Name          string `json:"name"`
+	SecretName    string `json:"secretName,omitempty"`
+	ConfigMapName string `json:"configMapName,omitempty"`
+	Key           string `json:"key"`
+}
+
+// PodSecurityContext is a corev1.PodSecurityContext that replaces, rather than
+// merges into, any value it is decoded over. Specs are decoded on top of
+// NewDefaultConfig, and a partial merge with the defaults could produce a
+// security context that the admin never wrote.
+type PodSecurityContext corev1.PodSecurityContext
+
+func (c *PodSecurityContext) UnmarshalJSON(data []byte) error {
+	var sc corev1.PodSecurityContext
+	if err := json.Unmarshal(data, &sc); err != nil {
+		return err
+	}
+	*c = PodSecurityContext(sc)
+	return nil
+}
+
+// SecurityContext is a corev1.SecurityContext that replaces, rather than
+// merges into, any value it is decoded over. See PodSecurityContext.
+type SecurityContext corev1.SecurityContext
+
+func (c *SecurityContext) UnmarshalJSON(data []byte) error {
+	var sc corev1.SecurityContext
+	if err := json.Unmarshal(data, &sc); err != nil {
+		return err
+	}
+	*c = SecurityContext(sc)
+	return nil
+}
+
 // PodTemplateConfig is a config that applies to all Pod templates produced by a Service. If this needs
 // to differ between pod templates, split another service definition.
 type PodTemplateConfig struct {
@@ -63,3 +298,37 @@ func (c StandardConfig) GetPrometheusPort() *int                 { return c.Prom
 func (c StandardConfig) GetServiceAccountAnnotations() map[string]string {
 	return c.ServiceAccountAnnotations
 }
+func (c StandardConfig) GetTLSConfig() *TLSConfig      { return c.TLS }
+func (c StandardConfig) GetImagePullSecrets() []string { return c.ImagePullSecrets }
+func (c StandardConfig) GetServiceAccountConfig() ServiceAccountConfig {
+	if c.ServiceAccount == nil {
+		return ServiceAccountConfig{}
+	}
+	return *c.ServiceAccount
+}
+func (c StandardConfig) GetPodSecurityContext() *corev1.PodSecurityContext {
+	return (*corev1.PodSecurityContext)(c.PodSecurityContext).DeepCopy()
+}
+func (c StandardConfig) GetContainerSecurityContext() *corev1.SecurityContext {
+	return (*corev1.SecurityContext)(c.ContainerSecurityContext).DeepCopy()
+}
+func (c StandardConfig) GetEnv() map[string]string                    { return c.Env }
+func (c StandardConfig) GetEnvFrom() []SecretOrConfigMapRef           { return c.EnvFrom }
+func (c StandardConfig) GetSidecars() []corev1.Container              { return c.Sidecars }
+func (c StandardConfig) GetExtraInitContainers() []corev1.Container   { return c.ExtraInitContainers }
+func (c StandardConfig) GetExtraVolumes() []corev1.Volume             { return c.ExtraVolumes }
+func (c StandardConfig) GetExtraVolumeMounts() []corev1.VolumeMount   { return c.ExtraVolumeMounts }
+func (c StandardConfig) GetPriorityClassName() *string                { return c.PriorityClassName }
+func (c StandardConfig) GetDisruptionBudget() *DisruptionBudgetConfig { return c.DisruptionBudget }
+func (c StandardConfig) GetLabels() map[string]string                 { return c.Labels }
+func (c StandardConfig) GetAnnotations() map[string]string            { return c.Annotations }
+func (c StandardConfig) GetIPFamilyPolicy() corev1.IPFamilyPolicy     { return c.IPFamilyPolicy }
+func (c StandardConfig) GetIPFamilies() []corev1.IPFamily             { return c.IPFamilies }
+func (c StandardConfig) GetDisableProxy() bool                        { return c.DisableProxy }
+func (c StandardConfig) GetTopologySpreadConstraints() []corev1.TopologySpreadConstraint {
+	return c.TopologySpreadConstraints
+}
+func (c StandardConfig) GetLivenessProbe() *ProbeConfig  { return c.LivenessProbe }
+func (c StandardConfig) GetReadinessProbe() *ProbeConfig { return c.ReadinessProbe }
+func (c StandardConfig) GetStartupProbe() *ProbeConfig   { return c.StartupProbe }
+func (c StandardConfig) GetStrategy() *StrategyConfig    { return c.Strategy }
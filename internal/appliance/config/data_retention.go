@@ -0,0 +1,41 @@
+package config
+
+import (
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DataRetentionPolicy is what happens to the data of Sourcegraph, i.e. its
+// PersistentVolumeClaims and the Secrets that the appliance generates, when
+// the services that use them are disabled, or the appliance ConfigMap is
+// deleted.
+type DataRetentionPolicy string
+
+const (
+	// DataRetentionPolicyRetain keeps the data, labeled with
+	// LabelKeyRetainedFrom, so that enabling the services again, or
+	// reinstalling Sourcegraph in the same namespace, adopts it again.
+	// Deleting the appliance ConfigMap is blocked until it has the
+	// AnnotationKeyConfirmDeletion annotation.
+	DataRetentionPolicyRetain DataRetentionPolicy = "Retain"
+
+	// DataRetentionPolicyDelete deletes the data along with the services
+	// that use it, and with the appliance ConfigMap.
+	DataRetentionPolicyDelete DataRetentionPolicy = "Delete"
+)
+
+// OrDefault returns the policy, or DataRetentionPolicyRetain if it's unset.
+func (p DataRetentionPolicy) OrDefault() DataRetentionPolicy {
+	if p == "" {
+		return DataRetentionPolicyRetain
+	}
+	return p
+}
+
+func validateDataRetentionPolicy(policy DataRetentionPolicy) error {
+	switch policy {
+	case "", DataRetentionPolicyRetain, DataRetentionPolicyDelete:
+		return nil
+	}
+	return errors.Newf("dataRetentionPolicy: %q must be one of %q or %q",
+		policy, DataRetentionPolicyRetain, DataRetentionPolicyDelete)
+}
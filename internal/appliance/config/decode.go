@@ -0,0 +1,208 @@
+package config
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	sjson "sigs.k8s.io/json"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DecodeOptions configure DecodeConfigYAML.
+type DecodeOptions struct {
+	// Strict fails the decoding of a spec with unknown fields. Otherwise,
+	// they are only reported as warnings, so that a spec written for a newer
+	// appliance can still be applied by an older one.
+	Strict bool
+}
+
+// FieldWarning is a problem with a field of a spec that doesn't stop the spec
+// from being applied.
+type FieldWarning struct {
+	// Path is the dotted path of the field, e.g. "spec.gitServer.replicas".
+	Path string
+
+	// Deprecated is true if the field is deprecated, and false if it is
+	// unknown.
+	Deprecated bool
+
+	Message string
+}
+
+func (w FieldWarning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+// UnknownFieldsError is returned by DecodeConfigYAML in strict mode for a
+// spec with unknown fields.
+type UnknownFieldsError struct {
+	Paths []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return "unknown fields: " + strings.Join(e.Paths, ", ")
+}
+
+// deprecatedField is a field of the spec that is still accepted, but on its
+// way out.
+type deprecatedField struct {
+	// path is the dotted path of the field, e.g. "spec.gitServer.replicas".
+	path string
+
+	// replacement is the path of the field that replaces this one, if any.
+	// The value of the deprecated field is moved there, unless the spec sets
+	// the replacement as well.
+	replacement string
+}
+
+// deprecatedFields are the deprecated fields of the spec. When renaming or
+// removing a field, keep accepting the old one here for at least a release,
+// so that existing specs keep working while their owners are warned to update
+// them.
+var deprecatedFields = []deprecatedField{}
+
+// DecodeConfigYAML decodes a spec like NewConfigFromYAML does, and reports the
+// fields of the spec that are unknown, most likely because of a typo, or
+// deprecated. The values of renamed fields are moved to their replacement.
+// Specs in any supported API version are converted to v1alpha1 first, and
+// other versions fail with an *UnknownAPIVersionError.
+//
+// In strict mode, a spec with unknown fields fails with an
+// *UnknownFieldsError, alongside the spec decoded as if it wasn't strict.
+func DecodeConfigYAML(data []byte, opts DecodeOptions) (Sourcegraph, []FieldWarning, error) {
+	data, err := convertToInternalVersion(data)
+	if err != nil {
+		return Sourcegraph{}, nil, err
+	}
+
+	data, warnings, err := migrateDeprecatedFields(data)
+	if err != nil {
+		return Sourcegraph{}, nil, err
+	}
+
+	sg, err := decodeOverSizeDefaults(data)
+	if err != nil {
+		return sg, warnings, err
+	}
+
+	unknown, err := unknownFields(data)
+	if err != nil {
+		return sg, warnings, err
+	}
+	for _, path := range unknown {
+		warnings = append(warnings, FieldWarning{Path: path, Message: "unknown field"})
+	}
+	if opts.Strict && len(unknown) > 0 {
+		return sg, warnings, &UnknownFieldsError{Paths: unknown}
+	}
+	return sg, warnings, nil
+}
+
+// unknownFields returns the paths of the fields of a spec that Sourcegraph
+// has no field for, in order.
+func unknownFields(data []byte) ([]string, error) {
+	doc, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var sg Sourcegraph
+	strictErrs, err := sjson.UnmarshalStrict(doc, &sg, sjson.DisallowUnknownFields)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, strictErr := range strictErrs {
+		quoted, ok := strings.CutPrefix(strictErr.Error(), "unknown field ")
+		if !ok {
+			continue
+		}
+		path, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %q", strictErr)
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// migrateDeprecatedFields warns about every deprecated field that a spec sets,
+// and moves its value to its replacement. The spec is only re-encoded, as
+// JSON, if it sets any.
+func migrateDeprecatedFields(data []byte) ([]byte, []FieldWarning, error) {
+	if len(deprecatedFields) == 0 {
+		return data, nil, nil
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []FieldWarning
+	for _, field := range deprecatedFields {
+		value, ok := removeFieldPath(doc, field.path)
+		if !ok {
+			continue
+		}
+		if field.replacement == "" {
+			warnings = append(warnings, FieldWarning{Path: field.path, Deprecated: true, Message: "deprecated, and has no effect"})
+			continue
+		}
+		warnings = append(warnings, FieldWarning{Path: field.path, Deprecated: true, Message: "deprecated, use " + field.replacement + " instead"})
+		if _, ok := lookupFieldPath(doc, field.replacement); !ok {
+			setFieldPath(doc, strings.Split(field.replacement, "."), value)
+		}
+	}
+	if len(warnings) == 0 {
+		return data, nil, nil
+	}
+
+	data, err := json.Marshal(doc)
+	return data, warnings, err
+}
+
+func lookupFieldPath(doc map[string]any, path string) (any, bool) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := doc[key].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		doc = child
+	}
+	value, ok := doc[keys[len(keys)-1]]
+	return value, ok
+}
+
+func removeFieldPath(doc map[string]any, path string) (any, bool) {
+	parent, key := doc, path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		p, ok := lookupFieldPath(doc, path[:i])
+		if !ok {
+			return nil, false
+		}
+		if parent, ok = p.(map[string]any); !ok {
+			return nil, false
+		}
+		key = path[i+1:]
+	}
+	value, ok := parent[key]
+	delete(parent, key)
+	return value, ok
+}
+
+func setFieldPath(doc map[string]any, keys []string, value any) {
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := doc[key].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			doc[key] = child
+		}
+		doc = child
+	}
+	doc[keys[len(keys)-1]] = value
+}
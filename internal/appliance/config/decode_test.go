@@ -0,0 +1,92 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestDecodeConfigYAML(t *testing.T) {
+	t.Run("valid spec", func(t *testing.T) {
+		sg, warnings, err := DecodeConfigYAML([]byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  gitServer:
+    replicas: 3
+  worker:
+    extraWorkers:
+      codeintel:
+        jobAllowlist: [codeintel-upload-janitor]
+`), DecodeOptions{Strict: true})
+		require.NoError(t, err)
+		require.Empty(t, warnings)
+		require.Equal(t, int32(3), sg.Spec.GitServer.Replicas)
+	})
+
+	t.Run("unknown nested field", func(t *testing.T) {
+		spec := []byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  gitServer:
+    replcias: 3
+  worker:
+    extraWorkers:
+      codeintel:
+        jobAllowList: [codeintel-upload-janitor]
+`)
+		want := []FieldWarning{
+			{Path: "spec.gitServer.replcias", Message: "unknown field"},
+			{Path: "spec.worker.extraWorkers.codeintel.jobAllowList", Message: "unknown field"},
+		}
+
+		// Lenient decoding ignores the unknown fields, and applies the rest.
+		sg, warnings, err := DecodeConfigYAML(spec, DecodeOptions{})
+		require.NoError(t, err)
+		require.Equal(t, want, warnings)
+		require.Equal(t, NewDefaultConfig().Spec.GitServer.Replicas, sg.Spec.GitServer.Replicas)
+		require.Equal(t, "5.3.9104", sg.Spec.RequestedVersion)
+
+		_, warnings, err = DecodeConfigYAML(spec, DecodeOptions{Strict: true})
+		var unknownFieldsErr *UnknownFieldsError
+		require.True(t, errors.As(err, &unknownFieldsErr))
+		require.Equal(t, []string{"spec.gitServer.replcias", "spec.worker.extraWorkers.codeintel.jobAllowList"}, unknownFieldsErr.Paths)
+		require.Equal(t, want, warnings)
+	})
+
+	t.Run("deprecated field", func(t *testing.T) {
+		defer func(fields []deprecatedField) { deprecatedFields = fields }(deprecatedFields)
+		deprecatedFields = []deprecatedField{
+			{path: "spec.gitServer.shards", replacement: "spec.gitServer.replicas"},
+			{path: "spec.searcher.cacheDir"},
+		}
+
+		sg, warnings, err := DecodeConfigYAML([]byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  gitServer:
+    shards: 4
+  searcher:
+    cacheDir: /mnt/cache
+`), DecodeOptions{Strict: true})
+		require.NoError(t, err)
+		require.Equal(t, []FieldWarning{
+			{Path: "spec.gitServer.shards", Deprecated: true, Message: "deprecated, use spec.gitServer.replicas instead"},
+			{Path: "spec.searcher.cacheDir", Deprecated: true, Message: "deprecated, and has no effect"},
+		}, warnings)
+		require.Equal(t, int32(4), sg.Spec.GitServer.Replicas)
+
+		// The replacement takes precedence over the deprecated field.
+		sg, warnings, err = DecodeConfigYAML([]byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  gitServer:
+    shards: 4
+    replicas: 2
+`), DecodeOptions{})
+		require.NoError(t, err)
+		require.Len(t, warnings, 1)
+		require.Equal(t, int32(2), sg.Spec.GitServer.Replicas)
+	})
+}
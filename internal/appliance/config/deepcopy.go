@@ -0,0 +1,978 @@
+package config
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// The config types have DeepCopy and DeepCopyInto methods like the types of
+// the Kubernetes API, so that a config can be copied without sharing the
+// pointers, maps and slices of its fields. Copying a config by value shares
+// them, so that modifying one copy may modify the other, e.g. the defaults of
+// NewDefaultConfig.
+//
+// Rather than being generated, the methods are implemented with
+// deepCopyValue, so that they cover the fields added later. Every struct type
+// of the config needs them, which TestDeepCopyMethods checks, as a struct
+// which embeds StandardConfig would otherwise promote the methods of
+// StandardConfig.
+
+var (
+	_ runtime.Object = &Sourcegraph{}
+	_ runtime.Object = &SourcegraphList{}
+)
+
+// deepCopyInto sets out to a copy of in that shares no memory with it.
+func deepCopyInto[T any](in, out *T) {
+	reflect.ValueOf(out).Elem().Set(deepCopyValue(reflect.ValueOf(in).Elem()))
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *Sourcegraph) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *SourcegraphList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies in into out. PodSecurityContext is copied like a
+// corev1.PodSecurityContext.
+func (in *PodSecurityContext) DeepCopyInto(out *PodSecurityContext) {
+	(*corev1.PodSecurityContext)(in).DeepCopyInto((*corev1.PodSecurityContext)(out))
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *PodSecurityContext) DeepCopy() *PodSecurityContext {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecurityContext)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out. SecurityContext is copied like a
+// corev1.SecurityContext.
+func (in *SecurityContext) DeepCopyInto(out *SecurityContext) {
+	(*corev1.SecurityContext)(in).DeepCopyInto((*corev1.SecurityContext)(out))
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *SecurityContext) DeepCopy() *SecurityContext {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityContext)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *AlertmanagerSpec) DeepCopyInto(out *AlertmanagerSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *AlertmanagerSpec) DeepCopy() *AlertmanagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *AutoscalingConfig) DeepCopyInto(out *AutoscalingConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *AutoscalingConfig) DeepCopy() *AutoscalingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *BlobstoreRetentionConfig) DeepCopyInto(out *BlobstoreRetentionConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *BlobstoreRetentionConfig) DeepCopy() *BlobstoreRetentionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BlobstoreRetentionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *BlobstoreSpec) DeepCopyInto(out *BlobstoreSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *BlobstoreSpec) DeepCopy() *BlobstoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BlobstoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *CadvisorSpec) DeepCopyInto(out *CadvisorSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *CadvisorSpec) DeepCopy() *CadvisorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CadvisorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *CodeDBSpec) DeepCopyInto(out *CodeDBSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *CodeDBSpec) DeepCopy() *CodeDBSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CodeDBSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *CodeHostRateLimitConfig) DeepCopyInto(out *CodeHostRateLimitConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *CodeHostRateLimitConfig) DeepCopy() *CodeHostRateLimitConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CodeHostRateLimitConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *ConnectionPoolerSpec) DeepCopyInto(out *ConnectionPoolerSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *ConnectionPoolerSpec) DeepCopy() *ConnectionPoolerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionPoolerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *ContainerConfig) DeepCopyInto(out *ContainerConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *ContainerConfig) DeepCopy() *ContainerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *DatabaseBackupSpec) DeepCopyInto(out *DatabaseBackupSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *DatabaseBackupSpec) DeepCopy() *DatabaseBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *DatabaseConnectionSpec) DeepCopyInto(out *DatabaseConnectionSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *DatabaseConnectionSpec) DeepCopy() *DatabaseConnectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseConnectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *DisruptionBudgetConfig) DeepCopyInto(out *DisruptionBudgetConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *DisruptionBudgetConfig) DeepCopy() *DisruptionBudgetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionBudgetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *EmbeddingsSpec) DeepCopyInto(out *EmbeddingsSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *EmbeddingsSpec) DeepCopy() *EmbeddingsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbeddingsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *ExecutorKubernetesSpec) DeepCopyInto(out *ExecutorKubernetesSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *ExecutorKubernetesSpec) DeepCopy() *ExecutorKubernetesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutorKubernetesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *ExecutorsSpec) DeepCopyInto(out *ExecutorsSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *ExecutorsSpec) DeepCopy() *ExecutorsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutorsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *ExternalRedisSpec) DeepCopyInto(out *ExternalRedisSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *ExternalRedisSpec) DeepCopy() *ExternalRedisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalRedisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *ExternalStorageSpec) DeepCopyInto(out *ExternalStorageSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *ExternalStorageSpec) DeepCopy() *ExternalStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *FrontendSpec) DeepCopyInto(out *FrontendSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *FrontendSpec) DeepCopy() *FrontendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FrontendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *GitServerSpec) DeepCopyInto(out *GitServerSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *GitServerSpec) DeepCopy() *GitServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *GrafanaSpec) DeepCopyInto(out *GrafanaSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *GrafanaSpec) DeepCopy() *GrafanaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *IndexedSearchIndexerSpec) DeepCopyInto(out *IndexedSearchIndexerSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *IndexedSearchIndexerSpec) DeepCopy() *IndexedSearchIndexerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexedSearchIndexerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *IndexedSearchSpec) DeepCopyInto(out *IndexedSearchSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *IndexedSearchSpec) DeepCopy() *IndexedSearchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexedSearchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *IngressSpec) DeepCopy() *IngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *MaintenanceModeSpec) DeepCopyInto(out *MaintenanceModeSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *MaintenanceModeSpec) DeepCopy() *MaintenanceModeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceModeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *MigrationGateSpec) DeepCopyInto(out *MigrationGateSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *MigrationGateSpec) DeepCopy() *MigrationGateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationGateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *NetworkPoliciesSpec) DeepCopyInto(out *NetworkPoliciesSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *NetworkPoliciesSpec) DeepCopy() *NetworkPoliciesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPoliciesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *OtelCollectorSpec) DeepCopyInto(out *OtelCollectorSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *OtelCollectorSpec) DeepCopy() *OtelCollectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OtelCollectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *PGSQLSpec) DeepCopyInto(out *PGSQLSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *PGSQLSpec) DeepCopy() *PGSQLSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGSQLSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *PersistentVolumeConfig) DeepCopyInto(out *PersistentVolumeConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *PersistentVolumeConfig) DeepCopy() *PersistentVolumeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *PodTemplateConfig) DeepCopyInto(out *PodTemplateConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *PodTemplateConfig) DeepCopy() *PodTemplateConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplateConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *PostgresExporterConfig) DeepCopyInto(out *PostgresExporterConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *PostgresExporterConfig) DeepCopy() *PostgresExporterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresExporterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *PostgresExporterSpec) DeepCopyInto(out *PostgresExporterSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *PostgresExporterSpec) DeepCopy() *PostgresExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresExporterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *PreciseCodeIntelSpec) DeepCopyInto(out *PreciseCodeIntelSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *PreciseCodeIntelSpec) DeepCopy() *PreciseCodeIntelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PreciseCodeIntelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *ProbeConfig) DeepCopyInto(out *ProbeConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *ProbeConfig) DeepCopy() *ProbeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *PrometheusAlertingSpec) DeepCopyInto(out *PrometheusAlertingSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *PrometheusAlertingSpec) DeepCopy() *PrometheusAlertingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusAlertingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *PrometheusRemoteWriteSpec) DeepCopyInto(out *PrometheusRemoteWriteSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *PrometheusRemoteWriteSpec) DeepCopy() *PrometheusRemoteWriteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRemoteWriteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *PrometheusSpec) DeepCopyInto(out *PrometheusSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *PrometheusSpec) DeepCopy() *PrometheusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *RedisSpec) DeepCopyInto(out *RedisSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *RedisSpec) DeepCopy() *RedisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *RemoteWriteBasicAuth) DeepCopyInto(out *RemoteWriteBasicAuth) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *RemoteWriteBasicAuth) DeepCopy() *RemoteWriteBasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteWriteBasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *RepoUpdaterSpec) DeepCopyInto(out *RepoUpdaterSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *RepoUpdaterSpec) DeepCopy() *RepoUpdaterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepoUpdaterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *RockskipConfig) DeepCopyInto(out *RockskipConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *RockskipConfig) DeepCopy() *RockskipConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RockskipConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *SearcherSpec) DeepCopyInto(out *SearcherSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *SearcherSpec) DeepCopy() *SearcherSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SearcherSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *SecretOrConfigMapRef) DeepCopyInto(out *SecretOrConfigMapRef) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *SecretOrConfigMapRef) DeepCopy() *SecretOrConfigMapRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretOrConfigMapRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *ServiceAccountConfig) DeepCopyInto(out *ServiceAccountConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *ServiceAccountConfig) DeepCopy() *ServiceAccountConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *ServiceStatus) DeepCopyInto(out *ServiceStatus) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *ServiceStatus) DeepCopy() *ServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *SiteConfigSpec) DeepCopyInto(out *SiteConfigSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *SiteConfigSpec) DeepCopy() *SiteConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *Sourcegraph) DeepCopyInto(out *Sourcegraph) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *Sourcegraph) DeepCopy() *Sourcegraph {
+	if in == nil {
+		return nil
+	}
+	out := new(Sourcegraph)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *SourcegraphList) DeepCopyInto(out *SourcegraphList) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *SourcegraphList) DeepCopy() *SourcegraphList {
+	if in == nil {
+		return nil
+	}
+	out := new(SourcegraphList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *SourcegraphSpec) DeepCopyInto(out *SourcegraphSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *SourcegraphSpec) DeepCopy() *SourcegraphSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SourcegraphSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *SourcegraphStatus) DeepCopyInto(out *SourcegraphStatus) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *SourcegraphStatus) DeepCopy() *SourcegraphStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SourcegraphStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *StandardConfig) DeepCopyInto(out *StandardConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *StandardConfig) DeepCopy() *StandardConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StandardConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *StorageClassSpec) DeepCopyInto(out *StorageClassSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *StorageClassSpec) DeepCopy() *StorageClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *StrategyConfig) DeepCopyInto(out *StrategyConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *StrategyConfig) DeepCopy() *StrategyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StrategyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *SymbolsSpec) DeepCopyInto(out *SymbolsSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *SymbolsSpec) DeepCopy() *SymbolsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SymbolsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *SyntectServerSpec) DeepCopyInto(out *SyntectServerSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *SyntectServerSpec) DeepCopy() *SyntectServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SyntectServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *TLSConfig) DeepCopy() *TLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *TempDirConfig) DeepCopyInto(out *TempDirConfig) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *TempDirConfig) DeepCopy() *TempDirConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TempDirConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies in into out.
+func (in *WorkerSpec) DeepCopyInto(out *WorkerSpec) {
+	deepCopyInto(in, out)
+}
+
+// DeepCopy returns a copy of in that shares no memory with it.
+func (in *WorkerSpec) DeepCopy() *WorkerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
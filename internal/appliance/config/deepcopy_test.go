@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewDefaultConfigSharesNoMemory modifies every pointer, map and slice of
+// a config built from the defaults, and checks that the defaults are left
+// as they are. It fails if a default shares memory between configs, e.g. a
+// pointer extracted into a package-level variable.
+func TestNewDefaultConfigSharesNoMemory(t *testing.T) {
+	for name, newConfig := range map[string]func(t *testing.T) Sourcegraph{
+		"NewDefaultConfig": func(*testing.T) Sourcegraph {
+			return NewDefaultConfig()
+		},
+		"NewDefaultConfigForSize": func(t *testing.T) Sourcegraph {
+			sg, err := NewDefaultConfigForSize(DeploymentSizeM)
+			require.NoError(t, err)
+			return sg
+		},
+		"MergeWithDefaults": func(t *testing.T) Sourcegraph {
+			sg, err := MergeWithDefaults(Sourcegraph{})
+			require.NoError(t, err)
+			return sg
+		},
+		"NewConfigFromYAML": func(t *testing.T) Sourcegraph {
+			sg, err := NewConfigFromYAML([]byte("spec:\n  frontend:\n    replicas: 3\n"))
+			require.NoError(t, err)
+			return sg
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			first := newConfig(t)
+			second := newConfig(t)
+
+			*first.Spec.Frontend.PrometheusPort = 8080
+			mutate(reflect.ValueOf(&first).Elem())
+			require.NotEqual(t, second, first)
+
+			assert.Equal(t, newConfig(t), second)
+		})
+	}
+}
+
+// TestDeepCopyMethods checks that every struct type of the config has its own
+// DeepCopy and DeepCopyInto methods, rather than none or promoted ones.
+func TestDeepCopyMethods(t *testing.T) {
+	for _, typ := range configStructTypes() {
+		ptr := reflect.PointerTo(typ)
+
+		deepCopy, ok := ptr.MethodByName("DeepCopy")
+		if !ok || deepCopy.Type.NumOut() != 1 || deepCopy.Type.Out(0) != ptr {
+			t.Errorf("%s has no DeepCopy method returning a %s, see deepcopy.go", typ, ptr)
+		}
+		deepCopyInto, ok := ptr.MethodByName("DeepCopyInto")
+		if !ok || deepCopyInto.Type.NumIn() != 2 || deepCopyInto.Type.In(1) != ptr {
+			t.Errorf("%s has no DeepCopyInto method taking a %s, see deepcopy.go", typ, ptr)
+		}
+	}
+}
+
+// TestDeepCopyCoversEveryField sets every pointer, map and slice of every
+// config type, and checks that its DeepCopy copies them, so that a field
+// which DeepCopy doesn't cover is caught.
+func TestDeepCopyCoversEveryField(t *testing.T) {
+	for _, typ := range configStructTypes() {
+		in := reflect.New(typ)
+		fill(in.Elem(), map[reflect.Type]bool{})
+
+		out := in.MethodByName("DeepCopy").Call(nil)[0]
+		require.Equal(t, in.Interface(), out.Interface(), "DeepCopy of %s", typ)
+		for _, path := range sharedMemory(in.Elem(), out.Elem(), typ.Name()) {
+			t.Errorf("DeepCopy shares %s", path)
+		}
+	}
+}
+
+// configStructTypes returns the struct types of this package which are part
+// of a SourcegraphList.
+func configStructTypes() []reflect.Type {
+	var types []reflect.Type
+	seen := map[reflect.Type]bool{}
+	var walk func(reflect.Type)
+	walk = func(t reflect.Type) {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Array:
+			walk(t.Elem())
+		case reflect.Struct:
+			if t.PkgPath() != configPkgPath || seen[t] {
+				return
+			}
+			seen[t] = true
+			types = append(types, t)
+			for i := 0; i < t.NumField(); i++ {
+				walk(t.Field(i).Type)
+			}
+		}
+	}
+	walk(reflect.TypeOf(SourcegraphList{}))
+	return types
+}
+
+// fill sets every pointer, map and slice of v, which must be settable. The
+// structs of other packages are left zero, as their DeepCopy is generated.
+// Types which contain themselves are filled once.
+func fill(v reflect.Value, filling map[reflect.Type]bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type().PkgPath() != configPkgPath || filling[v.Type()] {
+			return
+		}
+		filling[v.Type()] = true
+		defer delete(filling, v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i), filling)
+			}
+		}
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), filling)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		value := reflect.New(v.Type().Elem()).Elem()
+		fill(value, filling)
+		v.SetMapIndex(reflect.Zero(v.Type().Key()), value)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0), filling)
+	}
+}
+
+// sharedMemory returns the paths of the pointers, maps and slices which a and
+// b share.
+func sharedMemory(a, b reflect.Value, path string) []string {
+	var shared []string
+	switch a.Kind() {
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if a.Type().Field(i).IsExported() {
+				shared = append(shared, sharedMemory(a.Field(i), b.Field(i), path+"."+a.Type().Field(i).Name)...)
+			}
+		}
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return nil
+		}
+		if a.Pointer() == b.Pointer() {
+			return []string{path}
+		}
+		shared = sharedMemory(a.Elem(), b.Elem(), path)
+	case reflect.Map:
+		if a.IsNil() || b.IsNil() {
+			return nil
+		}
+		if a.Pointer() == b.Pointer() {
+			return []string{path}
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			if value := b.MapIndex(iter.Key()); value.IsValid() {
+				shared = append(shared, sharedMemory(iter.Value(), value, fmt.Sprintf("%s[%v]", path, iter.Key()))...)
+			}
+		}
+	case reflect.Slice:
+		if a.Len() == 0 || b.Len() == 0 {
+			return nil
+		}
+		if a.Pointer() == b.Pointer() {
+			return []string{path}
+		}
+		for i := 0; i < min(a.Len(), b.Len()); i++ {
+			shared = append(shared, sharedMemory(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return shared
+}
+
+// mutate changes every scalar that v points to, and adds a key to every map
+// of v, which must be settable.
+func mutate(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				mutate(v.Field(i))
+			}
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		switch elem := v.Elem(); elem.Kind() {
+		case reflect.Bool:
+			elem.SetBool(!elem.Bool())
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			elem.SetInt(elem.Int() + 1)
+		case reflect.String:
+			elem.SetString(elem.String() + "-mutated")
+		default:
+			mutate(elem)
+		}
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		v.SetMapIndex(reflect.ValueOf("mutated").Convert(v.Type().Key()), reflect.Zero(v.Type().Elem()))
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			mutate(v.Index(i))
+		}
+	}
+}
@@ -1,19 +1,56 @@
 package config
 
 import (
-	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
-// NewDefaultConfig
+var (
+	defaultsMutatorsMu sync.RWMutex
+	defaultsMutators   []func(*Sourcegraph)
+)
+
+// RegisterDefaultsMutator registers a function that adjusts the config that
+// NewDefaultConfig returns, so that a distribution of the appliance can change
+// defaults such as the image repository or storage sizes without patching
+// this file. It is meant to be called from an init function. Mutators run in
+// the order they were registered, and size presets are applied on top of
+// their changes.
+func RegisterDefaultsMutator(mutate func(*Sourcegraph)) {
+	defaultsMutatorsMu.Lock()
+	defer defaultsMutatorsMu.Unlock()
+	defaultsMutators = append(defaultsMutators, mutate)
+}
+
+// NewDefaultConfig returns the defaults of every setting, adjusted by the
+// registered defaults mutators.
 //
-// Warning: never extract `ptr.To(thing)` into a package-level variable! If you
-// do this, reconciling a config that overrides a default value for that
-// pointer, will affect the subsequent _default_ for all future resources
-// reconciled.
+// The result is a deep copy that shares no memory with the defaults, nor with
+// the results of other calls, so that reconciling a config that overrides a
+// default through a pointer, e.g. PrometheusPort, can't affect the defaults
+// of the configs reconciled later. Mutators may set pointers that they share
+// between calls for the same reason. Likewise, copy a config with DeepCopy
+// rather than by value, and apply a partial spec to the defaults with
+// MergeWithDefaults.
 func NewDefaultConfig() Sourcegraph {
+	sg := newBuiltinDefaultConfig()
+
+	defaultsMutatorsMu.RLock()
+	defer defaultsMutatorsMu.RUnlock()
+	for _, mutate := range defaultsMutators {
+		mutate(&sg)
+	}
+	return *sg.DeepCopy()
+}
+
+// newBuiltinDefaultConfig returns the defaults before any mutators are
+// applied, for NewDefaultConfig to copy.
+func newBuiltinDefaultConfig() Sourcegraph {
 	return Sourcegraph{
 		Spec: SourcegraphSpec{
 			// Global config
@@ -25,12 +62,37 @@ func NewDefaultConfig() Sourcegraph {
 					PersistentVolumeConfig: PersistentVolumeConfig{
 						StorageSize: "100Gi",
 					},
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
 				},
 			},
+			Frontend: FrontendSpec{
+				StandardConfig: StandardConfig{
+					PrometheusPort:           pointers.Ptr(6060),
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+					Strategy:                 rollingUpdateStrategy(2, 0),
+				},
+				Replicas: 2,
+			},
 			RepoUpdater: RepoUpdaterSpec{
+				StandardConfig: StandardConfig{
+					PrometheusPort:           pointers.Ptr(6060),
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+				},
+			},
+			Searcher: SearcherSpec{
 				StandardConfig: StandardConfig{
 					PrometheusPort: pointers.Ptr(6060),
+					PersistentVolumeConfig: PersistentVolumeConfig{
+						StorageSize: "26Gi",
+					},
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+					Strategy:                 rollingUpdateStrategy(1, 1),
 				},
+				Replicas: 1,
 			},
 			Symbols: SymbolsSpec{
 				StandardConfig: StandardConfig{
@@ -38,6 +100,8 @@ func NewDefaultConfig() Sourcegraph {
 					PersistentVolumeConfig: PersistentVolumeConfig{
 						StorageSize: "12Gi",
 					},
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
 				},
 				Replicas: 1,
 			},
@@ -47,15 +111,31 @@ func NewDefaultConfig() Sourcegraph {
 					PersistentVolumeConfig: PersistentVolumeConfig{
 						StorageSize: "200Gi",
 					},
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+					Strategy:                 &StrategyConfig{Type: RollingUpdateStrategy},
+				},
+				Replicas: 1,
+			},
+			IndexedSearch: IndexedSearchSpec{
+				StandardConfig: StandardConfig{
+					PrometheusPort: pointers.Ptr(6070),
+					PersistentVolumeConfig: PersistentVolumeConfig{
+						StorageSize: "200Gi",
+					},
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
 				},
 				Replicas: 1,
 			},
 			PGSQL: PGSQLSpec{
 				StandardConfig: StandardConfig{
-					PrometheusPort: pointers.Ptr(9187),
 					PersistentVolumeConfig: PersistentVolumeConfig{
 						StorageSize: "200Gi",
 					},
+					PodSecurityContext:       restrictedPodSecurityContext(999, 999, 999),
+					ContainerSecurityContext: restrictedContainerSecurityContext(999, 999),
+					Strategy:                 &StrategyConfig{Type: RecreateStrategy},
 				},
 				DatabaseConnection: &DatabaseConnectionSpec{
 					Host:     "pgsql",
@@ -64,6 +144,9 @@ func NewDefaultConfig() Sourcegraph {
 					Password: "password",
 					Database: "sg",
 				},
+				Exporter: &PostgresExporterConfig{
+					PrometheusPort: pointers.Ptr(DefaultPostgresExporterPort),
+				},
 			},
 			RedisCache: RedisSpec{
 				StandardConfig: StandardConfig{
@@ -71,7 +154,12 @@ func NewDefaultConfig() Sourcegraph {
 					PersistentVolumeConfig: PersistentVolumeConfig{
 						StorageSize: "100Gi",
 					},
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 1000),
+					ContainerSecurityContext: restrictedContainerSecurityContext(999, 1000),
+					Strategy:                 &StrategyConfig{Type: RecreateStrategy},
 				},
+				MaxMemory:       "6Gi",
+				MaxMemoryPolicy: "allkeys-lru",
 			},
 			RedisStore: RedisSpec{
 				StandardConfig: StandardConfig{
@@ -79,27 +167,40 @@ func NewDefaultConfig() Sourcegraph {
 					PersistentVolumeConfig: PersistentVolumeConfig{
 						StorageSize: "100Gi",
 					},
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 1000),
+					ContainerSecurityContext: restrictedContainerSecurityContext(999, 1000),
+					Strategy:                 &StrategyConfig{Type: RecreateStrategy},
 				},
+				MaxMemory:       "6Gi",
+				MaxMemoryPolicy: "noeviction",
 			},
 			SyntectServer: SyntectServerSpec{
 				StandardConfig: StandardConfig{
-					PrometheusPort: pointers.Ptr(6060),
+					PrometheusPort:           pointers.Ptr(6060),
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+					Strategy:                 rollingUpdateStrategy(1, 0),
 				},
 				Replicas: 1,
 			},
 			PreciseCodeIntel: PreciseCodeIntelSpec{
 				StandardConfig: StandardConfig{
-					PrometheusPort: pointers.Ptr(6060),
+					PrometheusPort:           pointers.Ptr(6060),
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+					Strategy:                 rollingUpdateStrategy(1, 1),
 				},
 				NumWorkers: 4,
 				Replicas:   2,
 			},
 			CodeInsights: CodeDBSpec{
 				StandardConfig: StandardConfig{
-					PrometheusPort: pointers.Ptr(9187),
 					PersistentVolumeConfig: PersistentVolumeConfig{
 						StorageSize: "200Gi",
 					},
+					PodSecurityContext:       restrictedPodSecurityContext(70, 70, 70),
+					ContainerSecurityContext: restrictedContainerSecurityContext(70, 70),
+					Strategy:                 &StrategyConfig{Type: RecreateStrategy},
 				},
 				DatabaseConnection: &DatabaseConnectionSpec{
 					Host:     "codeinsights-db",
@@ -108,13 +209,18 @@ func NewDefaultConfig() Sourcegraph {
 					Password: "password",
 					Database: "postgres",
 				},
+				Exporter: &PostgresExporterConfig{
+					PrometheusPort: pointers.Ptr(DefaultPostgresExporterPort),
+				},
 			},
 			CodeIntel: CodeDBSpec{
 				StandardConfig: StandardConfig{
-					PrometheusPort: pointers.Ptr(9187),
 					PersistentVolumeConfig: PersistentVolumeConfig{
 						StorageSize: "200Gi",
 					},
+					PodSecurityContext:       restrictedPodSecurityContext(999, 999, 999),
+					ContainerSecurityContext: restrictedContainerSecurityContext(999, 999),
+					Strategy:                 &StrategyConfig{Type: RecreateStrategy},
 				},
 				DatabaseConnection: &DatabaseConnectionSpec{
 					Host:     "codeintel-db",
@@ -123,12 +229,18 @@ func NewDefaultConfig() Sourcegraph {
 					Password: "password",
 					Database: "sg",
 				},
+				Exporter: &PostgresExporterConfig{
+					PrometheusPort: pointers.Ptr(DefaultPostgresExporterPort),
+				},
 			},
 			Prometheus: PrometheusSpec{
 				StandardConfig: StandardConfig{
 					PersistentVolumeConfig: PersistentVolumeConfig{
 						StorageSize: "200Gi",
 					},
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+					Strategy:                 &StrategyConfig{Type: RecreateStrategy},
 				},
 			},
 			Cadvisor: CadvisorSpec{
@@ -136,11 +248,53 @@ func NewDefaultConfig() Sourcegraph {
 					// cadvisor is opt-in due to the privilege requirements
 					Disabled:       true,
 					PrometheusPort: pointers.Ptr(48080),
+					// cadvisor reads container stats from the host, which needs a
+					// privileged container. It is the only service that does not
+					// satisfy the "restricted" Pod Security Standard by default.
+					ContainerSecurityContext: &SecurityContext{
+						Privileged: pointers.Ptr(true),
+					},
 				},
 			},
+			Grafana: GrafanaSpec{
+				StandardConfig: StandardConfig{
+					Disabled: true,
+					PersistentVolumeConfig: PersistentVolumeConfig{
+						StorageSize: "2Gi",
+					},
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+					Strategy:                 &StrategyConfig{Type: RecreateStrategy},
+				},
+			},
+			OtelCollector: OtelCollectorSpec{
+				StandardConfig: StandardConfig{
+					Disabled:                 true,
+					PrometheusPort:           pointers.Ptr(8888),
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+				},
+			},
+			Executors: ExecutorsSpec{
+				StandardConfig: StandardConfig{
+					// Executors are opt-in, since they need permission to run
+					// Jobs, and a token shared with the frontend.
+					Disabled: true,
+					PersistentVolumeConfig: PersistentVolumeConfig{
+						StorageSize: "10Gi",
+					},
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+				},
+				Replicas: 1,
+				Queues:   []string{"batches", "codeintel"},
+			},
 			Worker: WorkerSpec{
 				StandardConfig: StandardConfig{
-					PrometheusPort: pointers.Ptr(6060),
+					PrometheusPort:           pointers.Ptr(6060),
+					PodSecurityContext:       restrictedPodSecurityContext(100, 101, 101),
+					ContainerSecurityContext: restrictedContainerSecurityContext(100, 101),
+					Strategy:                 rollingUpdateStrategy(1, 1),
 				},
 				Replicas: 1,
 			},
@@ -148,6 +302,44 @@ func NewDefaultConfig() Sourcegraph {
 	}
 }
 
+// rollingUpdateStrategy returns a RollingUpdate strategy for a Deployment
+// bound by maxSurge and maxUnavailable pods.
+func rollingUpdateStrategy(maxSurge, maxUnavailable int32) *StrategyConfig {
+	return &StrategyConfig{
+		Type:           RollingUpdateStrategy,
+		MaxSurge:       pointers.Ptr(intstr.FromInt32(maxSurge)),
+		MaxUnavailable: pointers.Ptr(intstr.FromInt32(maxUnavailable)),
+	}
+}
+
+// restrictedPodSecurityContext and restrictedContainerSecurityContext return
+// security contexts that satisfy the "restricted" Pod Security Standard.
+// https://kubernetes.io/docs/concepts/security/pod-security-standards/#restricted
+func restrictedPodSecurityContext(user, group, fsGroup int64) *PodSecurityContext {
+	return &PodSecurityContext{
+		RunAsUser:           pointers.Ptr(user),
+		RunAsGroup:          pointers.Ptr(group),
+		RunAsNonRoot:        pointers.Ptr(true),
+		FSGroup:             pointers.Ptr(fsGroup),
+		FSGroupChangePolicy: pointers.Ptr(corev1.FSGroupChangeOnRootMismatch),
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+func restrictedContainerSecurityContext(user, group int64) *SecurityContext {
+	return &SecurityContext{
+		RunAsUser:                pointers.Ptr(user),
+		RunAsGroup:               pointers.Ptr(group),
+		AllowPrivilegeEscalation: pointers.Ptr(false),
+		ReadOnlyRootFilesystem:   pointers.Ptr(true),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
 // Images
 
 // Map of version to map of service to image tag
@@ -160,8 +352,16 @@ var defaultImagesForVersion_5_3_9104 = map[string]string{
 	"blobstore":                 "blobstore:5.3.2@sha256:d625be1eefe61cc42f94498e3c588bf212c4159c8b20c519db84eae4ff715efa",
 	"cadvisor":                  "cadvisor:5.3.2@sha256:3860cce1f7ef0278c0d785f66baf69dd2bece19610a2fd6eaa54c03095f2f105",
 	"codeinsights-db":           "codeinsights-db:5.3.2@sha256:c4a1bd3908658e1c09558a638e378e5570d5f669d27f9f867eeda25fe60cb88f",
+	"batcheshelper":             "batcheshelper:5.3.2",
 	"codeintel-db":              "codeintel-db:5.3.2@sha256:1e0e93661a65c832b9697048c797f9894dfb502e2e1da2b8209f0018a6632b79",
+	"executor":                  "executor-kubernetes:5.3.2",
+	"frontend":                  "frontend:5.3.2",
 	"gitserver":                 "gitserver:5.3.2@sha256:6c6042cf3e5f3f16de9b82e3d4ab1647f8bb924cd315245bd7a3162f5489e8c4",
+	"grafana":                   "grafana:5.3.2",
+	"indexed-search":            "indexed-searcher:5.3.2",
+	"indexed-search-indexer":    "search-indexer:5.3.2",
+	"otel-collector":            "opentelemetry-collector:5.3.2",
+	"pgbouncer":                 "pgbouncer:5.3.2",
 	"pgsql":                     "postgres-12-alpine:5.3.2@sha256:1e0e93661a65c832b9697048c797f9894dfb502e2e1da2b8209f0018a6632b79",
 	"pgsql-exporter":            "postgres_exporter:5.3.2@sha256:b9fa66fbcb4cc2d466487259db4ae2deacd7651dac4a9e28c9c7fc36523699d0",
 	"precise-code-intel-worker": "precise-code-intel-worker:5.3.2@sha256:6142093097f5757afe772cffd131c1be54bb77335232011254733f51ffb2d6c6",
@@ -170,19 +370,65 @@ var defaultImagesForVersion_5_3_9104 = map[string]string{
 	"redis-exporter":            "redis_exporter:5.3.2@sha256:21a9dd9214483a42b11d58bf99e4f268f44257a4f67acd436d458797a31b7786",
 	"redis-store":               "redis-store:5.3.2@sha256:0e3270a5eb293c158093f41145810eb5a154f61a74c9a896690dfdecd1b98b39",
 	"repo-updater":              "repo-updater:5.3.2@sha256:5a414aa030c7e0922700664a43b449ee5f3fafa68834abef93988c5992c747c6",
+	"searcher":                  "searcher:5.3.2",
 	"symbols":                   "symbols:5.3.2@sha256:dd7f923bdbd5dbd231b749a7483110d40d59159084477b9fff84afaf58aad98e",
 	"syntect-server":            "syntax-highlighter:5.3.2@sha256:3d16ab2a0203fea85063dcfe2e9d476540ef3274c28881dc4bbd5ca77933d8e8",
 	"worker":                    "worker:5.3.2@sha256:776168bb53a0b094f51bfec3d0d38e2938a07bb840b665b645ccf2637f0e779f",
 }
 
+// Map of version to the phases of upgrades to it from an earlier minor
+// version, in order. Like the default images, these are generated by the
+// release tooling. Upgrades to versions missing from here roll out every
+// component at once.
+var upgradePhases = map[string][]UpgradePhase{}
+
+// Map of version to the image of the appliance that ships with it, which the
+// appliance moves to with OperatorUpdatePolicyAutomatic. Like the default
+// images, these are generated by the release tooling.
+var operatorImages = map[string]string{
+	"5.3.9104": "appliance:5.3.9104",
+}
+
+// GetDefaultImage returns the image reference of a component for the requested
+// version, or for the phase of the upgrade to it that is rolling out, pulled
+// from ImageRepository, or from the path rendered by
+// ImageRepositoryPathTemplate if set. The tag and digest of the default image
+// are kept as they are, so that a mirror serves exactly the same image.
 func GetDefaultImage(sg *Sourcegraph, component string) (string, error) {
-	images, ok := defaultImages[sg.Spec.RequestedVersion]
-	if !ok {
-		return "", errors.Newf("no default images found for version %s", sg.Spec.RequestedVersion)
+	version, err := componentImageVersion(sg, component)
+	if err != nil {
+		return "", err
 	}
-	image, ok := images[component]
+	image, ok := defaultImages[version][component]
 	if !ok {
-		return "", errors.Newf("no default image found for service %s", component)
+		return "", errors.Newf("no default image found for service %s in version %s", component, version)
+	}
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing default image of service %s", component)
+	}
+	ref.Name, err = mirrorRepository(sg.Spec, component, ref.Name)
+	if err != nil {
+		return "", err
+	}
+	return ref.String(), nil
+}
+
+// ResolveAllImages returns the image reference of every component for the
+// requested version, i.e. exactly what will be pulled, keyed by component.
+func ResolveAllImages(sg *Sourcegraph) (map[string]string, error) {
+	version, err := ResolveImageVersion(sg)
+	if err != nil {
+		return nil, err
+	}
+	images := defaultImages[version]
+	resolved := make(map[string]string, len(images))
+	for component := range images {
+		image, err := GetDefaultImage(sg, component)
+		if err != nil {
+			return nil, err
+		}
+		resolved[component] = image
 	}
-	return fmt.Sprintf("%s/%s", sg.Spec.ImageRepository, image), nil
+	return resolved, nil
 }
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func TestNewDefaultConfig_OverrideFrontendReplicas(t *testing.T) {
+	sg := NewDefaultConfig()
+	require.NoError(t, yaml.Unmarshal([]byte("spec:\n  frontend:\n    replicas: 3\n"), &sg))
+
+	want := NewDefaultConfig()
+	want.Spec.Frontend.Replicas = 3
+	assert.Equal(t, want, sg)
+}
+
+func TestNewDefaultConfig_OverrideSecurityContextsReplacesDefaults(t *testing.T) {
+	sg := NewDefaultConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+spec:
+  pgsql:
+    podSecurityContext:
+      runAsUser: 2000
+    containerSecurityContext:
+      readOnlyRootFilesystem: false
+`), &sg))
+
+	assert.Equal(t, &PodSecurityContext{RunAsUser: pointers.Ptr[int64](2000)}, sg.Spec.PGSQL.PodSecurityContext)
+	assert.Equal(t, &SecurityContext{ReadOnlyRootFilesystem: pointers.Ptr(false)}, sg.Spec.PGSQL.ContainerSecurityContext)
+	assert.Equal(t, NewDefaultConfig().Spec.Frontend, sg.Spec.Frontend)
+}
+
+func TestGetDefaultImage_Frontend(t *testing.T) {
+	sg := NewDefaultConfig()
+	sg.Spec.RequestedVersion = "5.3.9104"
+	image, err := GetDefaultImage(&sg, "frontend")
+	require.NoError(t, err)
+	assert.Equal(t, "index.docker.io/sourcegraph/frontend:5.3.2", image)
+}
+
+func TestRegisterDefaultsMutator(t *testing.T) {
+	t.Cleanup(func() { defaultsMutators = nil })
+
+	// The mutator shares a pointer between calls, which must not leak into
+	// the configs.
+	sharedPort := pointers.Ptr(7070)
+	RegisterDefaultsMutator(func(sg *Sourcegraph) {
+		sg.Spec.ImageRepository = "registry.example.com/sourcegraph"
+		sg.Spec.GitServer.PersistentVolumeConfig.StorageSize = "500Gi"
+		sg.Spec.Frontend.PrometheusPort = sharedPort
+	})
+
+	first := NewDefaultConfig()
+	second := NewDefaultConfig()
+	assert.Equal(t, "registry.example.com/sourcegraph", first.Spec.ImageRepository)
+	assert.Equal(t, "500Gi", first.Spec.GitServer.PersistentVolumeConfig.StorageSize)
+	assert.Equal(t, 7070, *first.Spec.Frontend.PrometheusPort)
+
+	*first.Spec.Frontend.PrometheusPort = 8080
+	first.Spec.GitServer.PersistentVolumeConfig.StorageSize = "1Ti"
+	first.Spec.Frontend.PodSecurityContext.RunAsUser = pointers.Ptr[int64](0)
+	assert.Equal(t, 7070, *second.Spec.Frontend.PrometheusPort)
+	assert.Equal(t, "500Gi", second.Spec.GitServer.PersistentVolumeConfig.StorageSize)
+	assert.Equal(t, NewDefaultConfig(), second)
+
+	// Size presets are applied on top of the mutated defaults.
+	sized, err := NewDefaultConfigForSize(DeploymentSizeXS)
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/sourcegraph", sized.Spec.ImageRepository)
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DriftPolicy is what the appliance does about drift: changes made by hand,
+// e.g. with kubectl edit, to the fields that it sets on the objects it
+// manages.
+type DriftPolicy string
+
+const (
+	// DriftPolicyRevert reverts drift on every reconcile.
+	DriftPolicyRevert DriftPolicy = "Revert"
+
+	// DriftPolicyIgnore keeps drift, until the spec changes what the
+	// appliance sets on the drifted field.
+	DriftPolicyIgnore DriftPolicy = "Ignore"
+
+	// DriftPolicyReport keeps drift like DriftPolicyIgnore, but reports it
+	// as a warning.
+	DriftPolicyReport DriftPolicy = "Report"
+)
+
+// OrDefault returns the policy, or DriftPolicyRevert if it's unset.
+func (p DriftPolicy) OrDefault() DriftPolicy {
+	if p == "" {
+		return DriftPolicyRevert
+	}
+	return p
+}
+
+func validateDriftPolicy(policy DriftPolicy) error {
+	switch policy {
+	case "", DriftPolicyRevert, DriftPolicyIgnore, DriftPolicyReport:
+		return nil
+	}
+	return errors.Newf("driftPolicy: %q must be one of %q, %q, or %q",
+		policy, DriftPolicyRevert, DriftPolicyIgnore, DriftPolicyReport)
+}
@@ -5,19 +5,34 @@ import (
 )
 
 var (
+	//go:embed blobstore/retention.sh
+	//go:embed grafana/datasources.yml
+	//go:embed otel/collector.yml.gotmpl
 	//go:embed postgres/*
+	//go:embed prometheus/alerts.yml
 	//go:embed prometheus/default.yml.gotmpl
+	//go:embed redis/redis.conf.gotmpl
 	fs embed.FS
 
+	BlobstoreRetentionScript        []byte
 	PgsqlConfig                     []byte
+	PrometheusAlertsCatalog         []byte
 	PrometheusDefaultConfigTemplate []byte
 	CodeIntelConfig                 []byte
 	CodeInsightsConfig              []byte
+	GrafanaDatasourcesConfig        []byte
+	OtelCollectorConfigTemplate     []byte
+	RedisConfigTemplate             []byte
 )
 
 func init() {
+	BlobstoreRetentionScript, _ = fs.ReadFile("blobstore/retention.sh")
 	CodeIntelConfig, _ = fs.ReadFile("postgres/codeintel.conf")
 	CodeInsightsConfig, _ = fs.ReadFile("postgres/codeinsights.conf")
 	PgsqlConfig, _ = fs.ReadFile("postgres/pgsql.conf")
+	PrometheusAlertsCatalog, _ = fs.ReadFile("prometheus/alerts.yml")
 	PrometheusDefaultConfigTemplate, _ = fs.ReadFile("prometheus/default.yml.gotmpl")
+	GrafanaDatasourcesConfig, _ = fs.ReadFile("grafana/datasources.yml")
+	OtelCollectorConfigTemplate, _ = fs.ReadFile("otel/collector.yml.gotmpl")
+	RedisConfigTemplate, _ = fs.ReadFile("redis/redis.conf.gotmpl")
 }
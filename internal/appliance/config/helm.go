@@ -0,0 +1,545 @@
+package config
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	sjson "sigs.k8s.io/json"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ConvertHelmValuesYAML converts the values.yaml of a deployment with the
+// Sourcegraph Helm chart into the equivalent spec, minimized like
+// MarshalMinimalYAML does. See ConvertHelmValues.
+func ConvertHelmValuesYAML(data []byte) ([]byte, []FieldWarning, error) {
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, nil, errors.Wrap(err, "parsing Helm values")
+	}
+	sg, warnings, err := ConvertHelmValues(values)
+	if err != nil {
+		return nil, warnings, err
+	}
+	spec, err := MarshalMinimalYAML(sg)
+	return spec, warnings, err
+}
+
+// ConvertHelmValues converts the values of the Sourcegraph Helm chart into
+// the equivalent config, on top of the defaults. It maps the documented
+// values of each service: enabled, image, replicaCount, resources,
+// storageSize, env, nodeSelector, affinity, and tolerations, as well as the
+// global image repository, version, and pull secrets, and the storage class.
+// Unless the values pin the version of the images, the latest version that
+// the appliance has images for is requested.
+//
+// Values that have no equivalent in the spec, or can't be decoded, don't fail
+// the conversion. They are reported as warnings instead, whose Path is the
+// dotted path of the value in the Helm values, e.g. "frontend.podAnnotations",
+// so that they can be carried over by hand. The converted config is
+// validated, and returned along with the error if it is invalid.
+func ConvertHelmValues(values map[string]any) (Sourcegraph, []FieldWarning, error) {
+	c := &helmConverter{sg: NewDefaultConfig()}
+	// The chart's version is baked into it rather than set in its values,
+	// so the latest version is requested unless the values pin another.
+	versions := supportedVersions()
+	c.sg.Spec.RequestedVersion = versions[len(versions)-1]
+
+	// The version picks the default images that image overrides apply to, so
+	// the global values go first.
+	if global, ok := c.object("sourcegraph", values["sourcegraph"]); ok {
+		c.convertGlobal(global)
+	}
+	for _, key := range sortedKeys(values) {
+		switch key {
+		case "sourcegraph":
+		case "storageClass":
+			if storageClass, ok := c.object(key, values[key]); ok {
+				c.convertStorageClass(storageClass)
+			}
+		default:
+			svc, ok := helmServices[key]
+			if !ok {
+				c.unmapped(key)
+				continue
+			}
+			if service, ok := c.object(key, values[key]); ok {
+				c.convertService(key, svc, service)
+			}
+		}
+	}
+
+	sort.SliceStable(c.warnings, func(i, j int) bool { return c.warnings[i].Path < c.warnings[j].Path })
+	if err := c.sg.Validate(); err != nil {
+		return c.sg, c.warnings, errors.Wrap(err, "the converted spec is invalid")
+	}
+	return c.sg, c.warnings, nil
+}
+
+// helmService is a service of the Helm chart.
+type helmService struct {
+	// service is the JSON name of the field of SourcegraphSpec that configures
+	// the service, e.g. gitServer.
+	service string
+
+	// container is the container of the service that the image and resources
+	// of the service's values apply to.
+	container string
+
+	// containerOnly is set for the services of the chart that are a container
+	// of another service of the spec, e.g. the indexer of indexed search. Only
+	// their image and resources are mapped.
+	containerOnly bool
+
+	config   func(*SourcegraphSpec) *StandardConfig
+	replicas func(*SourcegraphSpec) *int32
+
+	// extra maps the values of the service that are specific to it.
+	extra map[string]func(c *helmConverter, path string, value any)
+}
+
+// helmServices maps the top-level keys of the Helm values to the services of
+// the spec.
+var helmServices = map[string]helmService{
+	"blobstore": {
+		service:   "blobstore",
+		container: "blobstore",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.Blobstore.StandardConfig },
+	},
+	"cadvisor": {
+		service:   "cadvisor",
+		container: "cadvisor",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.Cadvisor.StandardConfig },
+	},
+	"codeInsightsDB": {
+		service:   "codeInsights",
+		container: "codeinsights",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.CodeInsights.StandardConfig },
+	},
+	"codeIntelDB": {
+		service:   "codeIntel",
+		container: "codeintel-db",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.CodeIntel.StandardConfig },
+	},
+	"frontend": {
+		service:   "frontend",
+		container: "frontend",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.Frontend.StandardConfig },
+		replicas:  func(s *SourcegraphSpec) *int32 { return &s.Frontend.Replicas },
+		extra: map[string]func(*helmConverter, string, any){
+			"ingress": (*helmConverter).convertIngress,
+		},
+	},
+	"gitserver": {
+		service:   "gitServer",
+		container: "gitserver",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.GitServer.StandardConfig },
+		replicas:  func(s *SourcegraphSpec) *int32 { return &s.GitServer.Replicas },
+		extra: map[string]func(*helmConverter, string, any){
+			"sshSecret": func(c *helmConverter, path string, value any) {
+				c.decode(path, value, &c.sg.Spec.GitServer.SSHSecret)
+			},
+		},
+	},
+	"grafana": {
+		service:   "grafana",
+		container: "grafana",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.Grafana.StandardConfig },
+		extra: map[string]func(*helmConverter, string, any){
+			"existingConfig": func(c *helmConverter, path string, value any) {
+				c.decode(path, value, &c.sg.Spec.Grafana.ExistingConfigMap)
+			},
+		},
+	},
+	"indexedSearch": {
+		service:   "indexedSearch",
+		container: "zoekt-webserver",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.IndexedSearch.StandardConfig },
+		replicas:  func(s *SourcegraphSpec) *int32 { return &s.IndexedSearch.Replicas },
+	},
+	"indexedSearchIndexer": {
+		service:       "indexedSearch",
+		container:     "zoekt-indexserver",
+		containerOnly: true,
+		config:        func(s *SourcegraphSpec) *StandardConfig { return &s.IndexedSearch.StandardConfig },
+	},
+	"pgsql": {
+		service:   "pgsql",
+		container: "pgsql",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.PGSQL.StandardConfig },
+	},
+	"preciseCodeIntel": {
+		service:   "preciseCodeIntel",
+		container: "precise-code-intel-worker",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.PreciseCodeIntel.StandardConfig },
+		replicas:  func(s *SourcegraphSpec) *int32 { return &s.PreciseCodeIntel.Replicas },
+	},
+	"prometheus": {
+		service:   "prometheus",
+		container: "prometheus",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.Prometheus.StandardConfig },
+		extra: map[string]func(*helmConverter, string, any){
+			"existingConfig": func(c *helmConverter, path string, value any) {
+				c.decode(path, value, &c.sg.Spec.Prometheus.ExistingConfigMap)
+			},
+			"privileged": func(c *helmConverter, path string, value any) {
+				c.decode(path, value, &c.sg.Spec.Prometheus.Privileged)
+			},
+		},
+	},
+	"redisCache": {
+		service:   "redisCache",
+		container: "redis-cache",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.RedisCache.StandardConfig },
+	},
+	"redisStore": {
+		service:   "redisStore",
+		container: "redis-store",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.RedisStore.StandardConfig },
+	},
+	"repoUpdater": {
+		service:   "repoUpdater",
+		container: "repo-updater",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.RepoUpdater.StandardConfig },
+	},
+	"searcher": {
+		service:   "searcher",
+		container: "searcher",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.Searcher.StandardConfig },
+		replicas:  func(s *SourcegraphSpec) *int32 { return &s.Searcher.Replicas },
+	},
+	"symbols": {
+		service:   "symbols",
+		container: "symbols",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.Symbols.StandardConfig },
+		replicas:  func(s *SourcegraphSpec) *int32 { return &s.Symbols.Replicas },
+	},
+	"syntectServer": {
+		service:   "syntectServer",
+		container: "syntect-server",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.SyntectServer.StandardConfig },
+		replicas:  func(s *SourcegraphSpec) *int32 { return &s.SyntectServer.Replicas },
+	},
+	"worker": {
+		service:   "worker",
+		container: "worker",
+		config:    func(s *SourcegraphSpec) *StandardConfig { return &s.Worker.StandardConfig },
+		replicas:  func(s *SourcegraphSpec) *int32 { return &s.Worker.Replicas },
+	},
+}
+
+// component returns the key of the default image of the service's container.
+func (s helmService) component() string {
+	for _, ctr := range serviceContainers[s.service] {
+		if ctr.name == s.container {
+			return ctr.component
+		}
+	}
+	return ""
+}
+
+type helmConverter struct {
+	sg       Sourcegraph
+	warnings []FieldWarning
+}
+
+func (c *helmConverter) warn(path, message string) {
+	c.warnings = append(c.warnings, FieldWarning{Path: path, Message: message})
+}
+
+func (c *helmConverter) unmapped(path string) {
+	c.warn(path, "has no equivalent in the Sourcegraph spec")
+}
+
+// object returns value as a map, warning if it isn't one.
+func (c *helmConverter) object(path string, value any) (map[string]any, bool) {
+	if value == nil {
+		return nil, false
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		c.warn(path, "expected an object")
+	}
+	return m, ok
+}
+
+// decode decodes value into target, and reports whether it succeeded. Fields
+// of value that target lacks are reported as unmapped.
+func (c *helmConverter) decode(path string, value, target any) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.warn(path, err.Error())
+		return false
+	}
+	strictErrs, err := sjson.UnmarshalStrict(data, target, sjson.DisallowUnknownFields)
+	if err != nil {
+		c.warn(path, "invalid value: "+err.Error())
+		return false
+	}
+	for _, strictErr := range strictErrs {
+		if quoted, ok := strings.CutPrefix(strictErr.Error(), "unknown field "); ok {
+			if field, err := strconv.Unquote(quoted); err == nil {
+				c.unmapped(path + "." + field)
+				continue
+			}
+		}
+		c.warn(path, strictErr.Error())
+	}
+	return true
+}
+
+func (c *helmConverter) convertGlobal(values map[string]any) {
+	spec := &c.sg.Spec
+	for _, key := range sortedKeys(values) {
+		path, value := "sourcegraph."+key, values[key]
+		switch key {
+		case "image":
+			var image struct {
+				Repository string `json:"repository"`
+				DefaultTag string `json:"defaultTag"`
+				PullPolicy string `json:"pullPolicy"`
+			}
+			if !c.decode(path, value, &image) {
+				continue
+			}
+			if image.Repository != "" {
+				spec.ImageRepository = image.Repository
+			}
+			if image.DefaultTag != "" {
+				c.convertVersion(path+".defaultTag", image.DefaultTag)
+			}
+			if image.PullPolicy != "" {
+				c.unmapped(path + ".pullPolicy")
+			}
+		case "imagePullSecrets":
+			var refs []corev1.LocalObjectReference
+			if !c.decode(path, value, &refs) {
+				continue
+			}
+			for _, ref := range refs {
+				spec.ImagePullSecrets = append(spec.ImagePullSecrets, ref.Name)
+			}
+		case "labels":
+			c.decode(path, value, &spec.Labels)
+		case "priorityClassName":
+			c.decode(path, value, &spec.PriorityClassName)
+		default:
+			c.unmapped(path)
+		}
+	}
+}
+
+// convertVersion requests the version of the default tag of the chart's
+// images. A patch release without images of its own falls back to the images
+// of an earlier patch release, see ImageVersionFallback.
+func (c *helmConverter) convertVersion(path, tag string) {
+	version, _, _ := strings.Cut(strings.TrimPrefix(tag, "v"), "@")
+	sg := Sourcegraph{Spec: SourcegraphSpec{RequestedVersion: version, ImageVersionFallback: true}}
+	resolved, err := ResolveImageVersion(&sg)
+	if err != nil {
+		c.warn(path, "the appliance has no images for version "+strconv.Quote(version)+", requesting "+c.sg.Spec.RequestedVersion+" instead")
+		return
+	}
+	c.sg.Spec.RequestedVersion = version
+	c.sg.Spec.ImageVersionFallback = resolved != version
+}
+
+func (c *helmConverter) convertStorageClass(values map[string]any) {
+	storageClass := &c.sg.Spec.StorageClass
+	for _, key := range sortedKeys(values) {
+		path, value := "storageClass."+key, values[key]
+		switch key {
+		case "create":
+			c.decode(path, value, &storageClass.Create)
+		case "name":
+			c.decode(path, value, &storageClass.Name)
+		case "provisioner":
+			c.decode(path, value, &storageClass.Provisioner)
+		case "type":
+			c.decode(path, value, &storageClass.Type)
+		case "parameters":
+			c.decode(path, value, &storageClass.Parameters)
+		default:
+			c.unmapped(path)
+		}
+	}
+}
+
+func (c *helmConverter) convertService(name string, svc helmService, values map[string]any) {
+	cfg := svc.config(&c.sg.Spec)
+	for _, key := range sortedKeys(values) {
+		path, value := name+"."+key, values[key]
+		switch {
+		case key == "image":
+			c.convertImage(path, svc, cfg, value)
+		case key == "resources":
+			var resources corev1.ResourceRequirements
+			if c.decode(path, value, &resources) {
+				ctr := cfg.ContainerConfig[svc.container]
+				ctr.Resources = &resources
+				c.setContainerConfig(cfg, svc.container, ctr)
+			}
+		case svc.containerOnly:
+			c.unmapped(path)
+		case key == "enabled":
+			var enabled bool
+			if c.decode(path, value, &enabled) {
+				cfg.Disabled = !enabled
+			}
+		case key == "replicaCount" && svc.replicas != nil:
+			c.decode(path, value, svc.replicas(&c.sg.Spec))
+		case key == "storageSize":
+			c.decode(path, value, &cfg.PersistentVolumeConfig.StorageSize)
+		case key == "env":
+			c.convertEnv(path, cfg, value)
+		case key == "nodeSelector":
+			c.decode(path, value, &cfg.PodTemplateConfig.NodeSelector)
+		case key == "affinity":
+			c.decode(path, value, &cfg.PodTemplateConfig.Affinity)
+		case key == "tolerations":
+			c.decode(path, value, &cfg.PodTemplateConfig.Tolerations)
+		case svc.extra[key] != nil:
+			svc.extra[key](c, path, value)
+		default:
+			c.unmapped(path)
+		}
+	}
+}
+
+// convertImage overrides the image of the service's container. The chart's
+// name and defaultTag replace the name and tag of the default image.
+func (c *helmConverter) convertImage(path string, svc helmService, cfg *StandardConfig, value any) {
+	var image struct {
+		Name       string `json:"name"`
+		DefaultTag string `json:"defaultTag"`
+		PullPolicy string `json:"pullPolicy"`
+	}
+	if !c.decode(path, value, &image) {
+		return
+	}
+	if image.PullPolicy != "" {
+		c.unmapped(path + ".pullPolicy")
+	}
+	if image.Name == "" && image.DefaultTag == "" {
+		return
+	}
+
+	version, err := ResolveImageVersion(&c.sg)
+	if err != nil {
+		c.warn(path, err.Error())
+		return
+	}
+	ref, err := parseImageReference(defaultImages[version][svc.component()])
+	if err != nil {
+		c.warn(path, err.Error())
+		return
+	}
+	if image.Name != "" {
+		ref.Name = image.Name
+	}
+	if image.DefaultTag != "" {
+		ref.Tag, ref.Digest, _ = strings.Cut(image.DefaultTag, "@")
+	}
+
+	ctr := cfg.ContainerConfig[svc.container]
+	ctr.Image = ref.String()
+	c.setContainerConfig(cfg, svc.container, ctr)
+}
+
+func (c *helmConverter) setContainerConfig(cfg *StandardConfig, name string, ctr ContainerConfig) {
+	if cfg.ContainerConfig == nil {
+		cfg.ContainerConfig = map[string]ContainerConfig{}
+	}
+	cfg.ContainerConfig[name] = ctr
+}
+
+// convertEnv converts the env vars of the chart, which are keyed by name and
+// have a value or valueFrom each, like corev1.EnvVar. Values from keys of
+// Secrets and ConfigMaps become EnvFrom references.
+func (c *helmConverter) convertEnv(path string, cfg *StandardConfig, value any) {
+	env, ok := c.object(path, value)
+	if !ok {
+		return
+	}
+	for _, name := range sortedKeys(env) {
+		varPath := path + "." + name
+		var envVar struct {
+			Value     any                  `json:"value"`
+			ValueFrom *corev1.EnvVarSource `json:"valueFrom"`
+		}
+		if !c.decode(varPath, env[name], &envVar) {
+			continue
+		}
+
+		switch from := envVar.ValueFrom; {
+		case from == nil:
+			if cfg.Env == nil {
+				cfg.Env = map[string]string{}
+			}
+			cfg.Env[name] = helmScalar(envVar.Value)
+		case from.SecretKeyRef != nil:
+			cfg.EnvFrom = append(cfg.EnvFrom, SecretOrConfigMapRef{Name: name, SecretName: from.SecretKeyRef.Name, Key: from.SecretKeyRef.Key})
+		case from.ConfigMapKeyRef != nil:
+			cfg.EnvFrom = append(cfg.EnvFrom, SecretOrConfigMapRef{Name: name, ConfigMapName: from.ConfigMapKeyRef.Name, Key: from.ConfigMapKeyRef.Key})
+		default:
+			c.warn(varPath+".valueFrom", "only secretKeyRef and configMapKeyRef have an equivalent in the Sourcegraph spec")
+		}
+	}
+}
+
+// helmScalar formats a scalar of the Helm values as the string that Helm
+// would render it as.
+func helmScalar(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// convertIngress converts the ingress of the frontend.
+func (c *helmConverter) convertIngress(path string, value any) {
+	var ingress struct {
+		Enabled          *bool             `json:"enabled"`
+		Annotations      map[string]string `json:"annotations"`
+		Host             string            `json:"host"`
+		IngressClassName string            `json:"ingressClassName"`
+		TLSSecret        string            `json:"tlsSecret"`
+	}
+	if !c.decode(path, value, &ingress) {
+		return
+	}
+
+	spec := c.sg.Spec.Frontend.Ingress
+	if spec == nil {
+		spec = &IngressSpec{}
+	}
+	if ingress.Enabled != nil {
+		spec.Disabled = !*ingress.Enabled
+	}
+	if ingress.Annotations != nil {
+		spec.Annotations = ingress.Annotations
+	}
+	if ingress.Host != "" {
+		spec.Host = ingress.Host
+	}
+	if ingress.IngressClassName != "" {
+		spec.IngressClassName = ingress.IngressClassName
+	}
+	if ingress.TLSSecret != "" {
+		spec.TLSSecret = ingress.TLSSecret
+	}
+	c.sg.Spec.Frontend.Ingress = spec
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestConvertHelmValuesYAML(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		wantWarnings []string
+	}{
+		{name: "minimal"},
+		{name: "customized"},
+		{
+			name: "unmappable",
+			wantWarnings: []string{
+				"extraResources: has no equivalent in the Sourcegraph spec",
+				"frontend.env.POD_IP.valueFrom: only secretKeyRef and configMapKeyRef have an equivalent in the Sourcegraph spec",
+				"frontend.ingress.path: has no equivalent in the Sourcegraph spec",
+				"frontend.podAnnotations: has no equivalent in the Sourcegraph spec",
+				"indexedSearchIndexer.storageSize: has no equivalent in the Sourcegraph spec",
+				"jaeger: has no equivalent in the Sourcegraph spec",
+				"pgsql.replicaCount: has no equivalent in the Sourcegraph spec",
+				"sourcegraph.image.pullPolicy: has no equivalent in the Sourcegraph spec",
+				"sourcegraph.podAnnotations: has no equivalent in the Sourcegraph spec",
+				"storageClass.reclaimPolicy: has no equivalent in the Sourcegraph spec",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := os.ReadFile(filepath.Join("testdata", "helm", tc.name+".values.yaml"))
+			require.NoError(t, err)
+
+			obtained, warnings, err := ConvertHelmValuesYAML(values)
+			require.NoError(t, err)
+
+			goldenFilePath := filepath.Join("testdata", "helm", tc.name+".spec.yaml")
+			if len(os.Args) > 0 && os.Args[len(os.Args)-1] == "appliance-update-golden-files" {
+				require.NoError(t, os.WriteFile(goldenFilePath, obtained, 0600))
+			}
+			golden, err := os.ReadFile(goldenFilePath)
+			require.NoError(t, err)
+			assert.Equal(t, string(golden), string(obtained))
+
+			var got []string
+			for _, w := range warnings {
+				got = append(got, w.String())
+			}
+			assert.Equal(t, tc.wantWarnings, got)
+
+			sg, err := NewConfigFromYAML(obtained)
+			require.NoError(t, err)
+			require.NoError(t, sg.Validate())
+		})
+	}
+}
+
+func TestConvertHelmValues(t *testing.T) {
+	values := map[string]any{
+		"sourcegraph": map[string]any{
+			"image": map[string]any{"defaultTag": "5.3.9104"},
+		},
+		"gitserver": map[string]any{
+			"replicaCount": float64(3),
+			"image":        map[string]any{"defaultTag": "5.3.2-patched"},
+		},
+		"indexedSearchIndexer": map[string]any{
+			"image":     map[string]any{"name": "search-indexer-custom"},
+			"resources": map[string]any{"limits": map[string]any{"memory": "16G"}},
+		},
+		"worker": map[string]any{
+			"env": map[string]any{
+				"COUNT": map[string]any{"value": float64(2)},
+				"TOKEN": map[string]any{"valueFrom": map[string]any{"secretKeyRef": map[string]any{"name": "worker", "key": "token"}}},
+			},
+		},
+	}
+	sg, warnings, err := ConvertHelmValues(values)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	spec := sg.Spec
+	assert.Equal(t, "5.3.9104", spec.RequestedVersion)
+	assert.Equal(t, int32(3), spec.GitServer.Replicas)
+	assert.Equal(t, "gitserver:5.3.2-patched", spec.GitServer.ContainerConfig["gitserver"].Image)
+
+	indexer := spec.IndexedSearch.ContainerConfig["zoekt-indexserver"]
+	assert.Equal(t, "search-indexer-custom:5.3.2", indexer.Image)
+	assert.Equal(t, resource.MustParse("16G"), indexer.Resources.Limits[corev1.ResourceMemory])
+	assert.NotContains(t, spec.IndexedSearch.ContainerConfig, "zoekt-webserver")
+
+	assert.Equal(t, map[string]string{"COUNT": "2"}, spec.Worker.Env)
+	assert.Equal(t, []SecretOrConfigMapRef{{Name: "TOKEN", SecretName: "worker", Key: "token"}}, spec.Worker.EnvFrom)
+}
+
+func TestConvertHelmValues_Warnings(t *testing.T) {
+	sg, warnings, err := ConvertHelmValues(map[string]any{
+		"sourcegraph": map[string]any{
+			"image": map[string]any{"defaultTag": "4.5.1"},
+		},
+		"frontend": map[string]any{
+			"replicaCount": "many",
+			"resources":    map[string]any{"requests": map[string]any{"cpu": "1"}, "claims": "all"},
+		},
+		"searcher": "big",
+	})
+	require.NoError(t, err)
+
+	var got []string
+	for _, w := range warnings {
+		got = append(got, w.Path)
+	}
+	assert.Equal(t, []string{
+		"frontend.replicaCount",
+		"frontend.resources",
+		"searcher",
+		"sourcegraph.image.defaultTag",
+	}, got)
+	assert.Equal(t, NewDefaultConfig().Spec.Frontend.Replicas, sg.Spec.Frontend.Replicas, "invalid values are skipped")
+	assert.Equal(t, "5.3.9104", sg.Spec.RequestedVersion, "the latest version is requested instead")
+}
+
+func TestConvertHelmValues_VersionFallback(t *testing.T) {
+	sg, warnings, err := ConvertHelmValues(map[string]any{
+		"sourcegraph": map[string]any{
+			"image": map[string]any{"defaultTag": "5.3.9105@sha256:0123456789abcdef0123456789abcdef"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, "5.3.9105", sg.Spec.RequestedVersion)
+	assert.True(t, sg.Spec.ImageVersionFallback, "the images of 5.3.9104 are used")
+}
+
+func TestConvertHelmValues_Invalid(t *testing.T) {
+	_, _, err := ConvertHelmValues(map[string]any{
+		"gitserver": map[string]any{"storageSize": "lots"},
+	})
+	require.ErrorContains(t, err, "spec.gitServer.persistentVolumeConfig.storageSize")
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ValidateImageManifests checks that the default images of every version, and
+// the phases of the upgrades to them, are consistent: every component that the
+// appliance runs has an image, every image reference parses and has a tag,
+// every digest is well-formed, every phase refers to images that exist, and
+// every image of the appliance itself belongs to a version with default
+// images. Every problem is reported. It is run by the unit tests and by the
+// validate-images subcommand, so that the release tooling can't ship broken
+// manifests.
+//
+// Versions with a pre-release suffix, e.g. 5.4.0-transitional, hold the
+// transitional images that upgrade phases roll out, and only need the
+// components that those phases use.
+func ValidateImageManifests() error {
+	return errors.Append(
+		validateImageManifests(defaultImages, upgradePhases),
+		validateOperatorImages(operatorImages, defaultImages),
+	)
+}
+
+func validateImageManifests(images map[string]map[string]string, phases map[string][]UpgradePhase) error {
+	required := requiredComponents()
+
+	var errs error
+	for _, version := range sortedKeys(images) {
+		manifest := images[version]
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			errs = errors.Append(errs, errors.Newf("version %s: not a semantic version", version))
+			continue
+		}
+		if v.Prerelease() == "" {
+			for _, component := range required {
+				if _, ok := manifest[component]; !ok {
+					errs = errors.Append(errs, errors.Newf("version %s: no image for component %s", version, component))
+				}
+			}
+		}
+		for _, component := range sortedKeys(manifest) {
+			if !slices.Contains(required, component) {
+				errs = errors.Append(errs, errors.Newf("version %s: component %s is not run by the appliance", version, component))
+			}
+			if err := validateDefaultImage(manifest[component]); err != nil {
+				errs = errors.Append(errs, errors.Wrapf(err, "version %s: component %s", version, component))
+			}
+		}
+	}
+
+	for _, version := range sortedKeys(phases) {
+		if _, ok := images[version]; !ok {
+			errs = errors.Append(errs, errors.Newf("upgrade to %s: the version has no images", version))
+		}
+		for _, phase := range phases[version] {
+			for _, component := range sortedKeys(phase.Components) {
+				phaseVersion := phase.Components[component]
+				if _, ok := images[phaseVersion][component]; !ok {
+					errs = errors.Append(errs, errors.Newf("upgrade to %s: phase %q rolls out component %s to version %s, which has no image for it",
+						version, phase.Name, component, phaseVersion))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// validateDefaultImage checks a default image reference. Unlike the images
+// set in a spec, default images must have a tag, so that the version they
+// belong to shows, and their digests must be SHA-256 ones, which registries
+// serve.
+func validateDefaultImage(image string) error {
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return err
+	}
+	if ref.Tag == "" {
+		return errors.Newf("image %q has no tag", image)
+	}
+	if ref.Digest == "" {
+		return nil
+	}
+	algorithm, hex, _ := strings.Cut(ref.Digest, ":")
+	if algorithm != "sha256" || len(hex) != 64 || strings.ToLower(hex) != hex {
+		return errors.Newf("image %q has a malformed digest, expected sha256: followed by 64 lowercase hex digits", image)
+	}
+	return nil
+}
+
+// requiredComponents returns the components of the default images of the
+// containers that the appliance runs, sorted.
+func requiredComponents() []string {
+	seen := map[string]struct{}{}
+	for _, containers := range serviceContainers {
+		for _, ctr := range containers {
+			seen[ctr.component] = struct{}{}
+		}
+	}
+	return sortedKeys(seen)
+}
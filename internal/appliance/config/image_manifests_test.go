@@ -0,0 +1,190 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateImageManifests(t *testing.T) {
+	require.NoError(t, ValidateImageManifests())
+}
+
+func TestValidateImageManifests_Corrupted(t *testing.T) {
+	const digest = "sha256:982220e0fd8ce55a73798fa7e814a482c4807c412f054c8440c5970b610239b7"
+	manifest := func(mutate func(images map[string]string)) map[string]string {
+		images := make(map[string]string, len(defaultImagesForVersion_5_3_9104))
+		for component, image := range defaultImagesForVersion_5_3_9104 {
+			images[component] = image
+		}
+		mutate(images)
+		return images
+	}
+
+	for _, tc := range []struct {
+		name    string
+		images  map[string]map[string]string
+		phases  map[string][]UpgradePhase
+		wantErr string
+	}{
+		{
+			name: "malformed version",
+			images: map[string]map[string]string{
+				"latest": defaultImagesForVersion_5_3_9104,
+			},
+			wantErr: "version latest: not a semantic version",
+		},
+		{
+			name: "missing component",
+			images: map[string]map[string]string{
+				"5.3.9104": manifest(func(images map[string]string) { delete(images, "gitserver") }),
+			},
+			wantErr: "version 5.3.9104: no image for component gitserver",
+		},
+		{
+			name: "unknown component",
+			images: map[string]map[string]string{
+				"5.3.9104": manifest(func(images map[string]string) { images["gitserver-next"] = "gitserver-next:5.3.2" }),
+			},
+			wantErr: "version 5.3.9104: component gitserver-next is not run by the appliance",
+		},
+		{
+			name: "unparseable reference",
+			images: map[string]map[string]string{
+				"5.3.9104": manifest(func(images map[string]string) { images["frontend"] = "frontend:5.3.2:extra" }),
+			},
+			wantErr: `version 5.3.9104: component frontend: invalid image "frontend:5.3.2:extra"`,
+		},
+		{
+			name: "missing tag",
+			images: map[string]map[string]string{
+				"5.3.9104": manifest(func(images map[string]string) { images["frontend"] = "frontend@" + digest }),
+			},
+			wantErr: `version 5.3.9104: component frontend: image "frontend@` + digest + `" has no tag`,
+		},
+		{
+			name: "truncated digest",
+			images: map[string]map[string]string{
+				"5.3.9104": manifest(func(images map[string]string) { images["frontend"] = "frontend:5.3.2@" + digest[:40] }),
+			},
+			wantErr: `version 5.3.9104: component frontend: image "frontend:5.3.2@` + digest[:40] + `" has a malformed digest`,
+		},
+		{
+			name: "uppercase digest",
+			images: map[string]map[string]string{
+				"5.3.9104": manifest(func(images map[string]string) {
+					images["frontend"] = "frontend:5.3.2@sha256:" + strings.ToUpper(digest[7:])
+				}),
+			},
+			wantErr: "has a malformed digest",
+		},
+		{
+			name: "digest of another algorithm",
+			images: map[string]map[string]string{
+				"5.3.9104": manifest(func(images map[string]string) { images["frontend"] = "frontend:5.3.2@sha512:" + digest[7:] }),
+			},
+			wantErr: "has a malformed digest",
+		},
+		{
+			name: "upgrade to a version without images",
+			images: map[string]map[string]string{
+				"5.3.9104": defaultImagesForVersion_5_3_9104,
+			},
+			phases: map[string][]UpgradePhase{
+				"5.4.0": {{Name: "databases", Components: map[string]string{"pgsql": "5.3.9104"}}},
+			},
+			wantErr: "upgrade to 5.4.0: the version has no images",
+		},
+		{
+			name: "phase without image",
+			images: map[string]map[string]string{
+				"5.3.9104":           defaultImagesForVersion_5_3_9104,
+				"5.3.9104-migration": {"worker": "worker:5.3.9104-migration"},
+			},
+			phases: map[string][]UpgradePhase{
+				"5.3.9104": {{Name: "data migrations", Components: map[string]string{"frontend": "5.3.9104-migration"}}},
+			},
+			wantErr: `upgrade to 5.3.9104: phase "data migrations" rolls out component frontend to version 5.3.9104-migration, which has no image for it`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateImageManifests(tc.images, tc.phases)
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+
+	// Transitional versions only need the images that phases roll out.
+	require.NoError(t, validateImageManifests(map[string]map[string]string{
+		"5.3.9104":           defaultImagesForVersion_5_3_9104,
+		"5.3.9104-migration": {"worker": "worker:5.3.9104-migration"},
+	}, map[string][]UpgradePhase{
+		"5.3.9104": {{Name: "data migrations", Components: map[string]string{"worker": "5.3.9104-migration"}}},
+	}))
+}
+
+func TestCheckImageDigests(t *testing.T) {
+	const (
+		served   = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		missing  = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+		tokenURL = "/token"
+	)
+	var lookups, tokens atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenURL {
+			tokens.Add(1)
+			assert.Equal(t, "repository:sourcegraph/frontend:pull", r.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+tokenURL+`",service="test",scope="repository:sourcegraph/frontend:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		lookups.Add(1)
+		assert.Equal(t, http.MethodHead, r.Method)
+		assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json")
+		if strings.HasSuffix(r.URL.Path, "/manifests/"+served) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	original := defaultImages
+	t.Cleanup(func() { defaultImages = original })
+	defaultImages = map[string]map[string]string{
+		"5.3.2": {
+			"frontend": "frontend:5.3.2@" + served,
+			"searcher": "searcher:5.3.2",
+		},
+		"5.3.9104": {
+			"frontend": "frontend:5.3.9104@" + served,
+			"worker":   "worker:5.3.9104@" + missing,
+			"symbols":  "symbols:5.3.9104@" + missing,
+		},
+	}
+
+	registry := strings.TrimPrefix(server.URL, "https://")
+	unreachable, err := CheckImageDigests(context.Background(), server.Client(), registry+"/sourcegraph", 2)
+	require.NoError(t, err)
+	var got []string
+	for _, u := range unreachable {
+		got = append(got, u.Version+" "+u.String())
+	}
+	assert.Equal(t, []string{
+		"5.3.9104 " + registry + "/sourcegraph/symbols:5.3.9104@" + missing + " (symbols): manifest not found",
+		"5.3.9104 " + registry + "/sourcegraph/worker:5.3.9104@" + missing + " (worker): manifest not found",
+	}, got)
+	assert.Equal(t, int32(4), lookups.Load(), "images without a digest are skipped")
+	assert.LessOrEqual(t, tokens.Load(), int32(2), "tokens are reused, rather than fetched for every lookup")
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// UnreachableImage is a default image whose digest a registry doesn't serve.
+type UnreachableImage struct {
+	Version   string
+	Component string
+	// Image is the reference that was looked up, in the registry that it was
+	// looked up in.
+	Image string
+	Err   error
+}
+
+func (u UnreachableImage) String() string {
+	return fmt.Sprintf("%s (%s): %s", u.Image, u.Component, u.Err)
+}
+
+// manifestMediaTypes are the media types of the manifests and indexes that
+// default images may have.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// CheckImageDigests checks, with the OCI distribution API, that the registry
+// of imageRepository serves the digest of every default image of every
+// version, looking them up like ImageRepository would pull them. Images
+// without a digest are skipped. At most concurrency lookups are made at a
+// time. The unreachable images are returned sorted by version and component;
+// the error is only set if ctx is done.
+func CheckImageDigests(ctx context.Context, client *http.Client, imageRepository string, concurrency int) ([]UnreachableImage, error) {
+	registry := &registryClient{client: client, tokens: map[string]string{}}
+	spec := SourcegraphSpec{ImageRepository: imageRepository}
+
+	var mu sync.Mutex
+	var unreachable []UnreachableImage
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for _, version := range sortedKeys(defaultImages) {
+		for _, component := range sortedKeys(defaultImages[version]) {
+			ref, err := parseImageReference(defaultImages[version][component])
+			if err != nil || ref.Digest == "" {
+				// Broken references are reported by ValidateImageManifests.
+				continue
+			}
+			ref.Name, err = mirrorRepository(spec, component, ref.Name)
+			if err != nil {
+				_ = g.Wait()
+				return nil, err
+			}
+			g.Go(func() error {
+				if err := registry.checkManifest(ctx, ref.Name, ref.Digest); err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					unreachable = append(unreachable, UnreachableImage{Version: version, Component: component, Image: ref.String(), Err: err})
+				}
+				return nil
+			})
+		}
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(unreachable, func(i, j int) bool {
+		if unreachable[i].Version != unreachable[j].Version {
+			return unreachable[i].Version < unreachable[j].Version
+		}
+		return unreachable[i].Component < unreachable[j].Component
+	})
+	return unreachable, nil
+}
+
+// registryClient looks up manifests with the OCI distribution API, fetching
+// anonymous bearer tokens for registries that require them, e.g. Docker Hub.
+type registryClient struct {
+	client *http.Client
+
+	mu sync.Mutex
+	// tokens are the bearer tokens fetched so far, by realm and scope.
+	tokens map[string]string
+}
+
+// checkManifest checks that the registry of repository serves the manifest
+// with the given digest.
+func (c *registryClient) checkManifest(ctx context.Context, repository, digest string) error {
+	host, name, ok := strings.Cut(repository, "/")
+	if !ok || !strings.ContainsAny(host, ".:") && host != "localhost" {
+		// Like Docker, a repository without a registry host is on Docker
+		// Hub.
+		host, name = "docker.io", repository
+	}
+	if host == "docker.io" || host == "index.docker.io" {
+		host = "registry-1.docker.io"
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+	manifestURL := (&url.URL{Scheme: "https", Host: host, Path: "/v2/" + name + "/manifests/" + digest}).String()
+
+	resp, err := c.head(ctx, manifestURL, "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := c.token(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return errors.Wrap(err, "authenticating")
+		}
+		if resp, err = c.head(ctx, manifestURL, token); err != nil {
+			return err
+		}
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return errors.New("manifest not found")
+	default:
+		return errors.Newf("unexpected status %s", resp.Status)
+	}
+}
+
+func (c *registryClient) head(ctx context.Context, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// token returns an anonymous bearer token for the WWW-Authenticate challenge
+// of a registry, e.g.
+//
+//	Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:sourcegraph/frontend:pull"
+func (c *registryClient) token(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", errors.Newf("unsupported authentication challenge %q", challenge)
+	}
+	realm, query := "", url.Values{}
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		value = strings.Trim(value, `"`)
+		if key == "realm" {
+			realm = value
+		} else if key == "service" || key == "scope" {
+			query.Set(key, value)
+		}
+	}
+	if realm == "" {
+		return "", errors.Newf("authentication challenge %q has no realm", challenge)
+	}
+	tokenURL := realm + "?" + query.Encode()
+
+	c.mu.Lock()
+	token, ok := c.tokens[tokenURL]
+	c.mu.Unlock()
+	if ok {
+		return token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Newf("fetching token: unexpected status %s", resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", errors.Wrap(err, "decoding token")
+	}
+	token = body.Token
+	if token == "" {
+		token = body.AccessToken
+	}
+
+	c.mu.Lock()
+	c.tokens[tokenURL] = token
+	c.mu.Unlock()
+	return token, nil
+}
@@ -0,0 +1,352 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/semver"
+	"github.com/grafana/regexp"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ResolveImageVersion returns the version whose default images are used for
+// the requested version. That's the requested version itself, unless it has no
+// images and ImageVersionFallback is set, in which case it's the closest
+// earlier patch release of the same minor version.
+func ResolveImageVersion(sg *Sourcegraph) (string, error) {
+	return resolveImageVersion(sg.Spec.RequestedVersion, sg.Spec.ImageVersionFallback)
+}
+
+func resolveImageVersion(requested string, fallback bool) (string, error) {
+	if _, ok := defaultImages[requested]; ok {
+		return requested, nil
+	}
+	unknownVersionErr := errors.Newf("no default images found for version %s, supported versions are: %s",
+		requested, strings.Join(supportedVersions(), ", "))
+	if !fallback {
+		return "", unknownVersionErr
+	}
+
+	requestedVersion, err := semver.NewVersion(requested)
+	if err != nil {
+		return "", unknownVersionErr
+	}
+	var closest *semver.Version
+	var closestVersion string
+	for version := range defaultImages {
+		v, err := semver.NewVersion(version)
+		if err != nil ||
+			v.Major() != requestedVersion.Major() ||
+			v.Minor() != requestedVersion.Minor() ||
+			!v.LessThan(requestedVersion) {
+			continue
+		}
+		if closest == nil || closest.LessThan(v) {
+			closest, closestVersion = v, version
+		}
+	}
+	if closest == nil {
+		return "", errors.Wrapf(unknownVersionErr, "no earlier patch release of %d.%d to fall back to", requestedVersion.Major(), requestedVersion.Minor())
+	}
+	return closestVersion, nil
+}
+
+// supportedVersions returns the versions that have default images, oldest
+// first.
+func supportedVersions() []string {
+	versions := make([]string, 0, len(defaultImages))
+	for version := range defaultImages {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		vi, erri := semver.NewVersion(versions[i])
+		vj, errj := semver.NewVersion(versions[j])
+		if erri != nil || errj != nil {
+			return versions[i] < versions[j]
+		}
+		return vi.LessThan(vj)
+	})
+	return versions
+}
+
+// imageReference is a parsed image reference of the form
+// name[:tag][@digest], where name may include a registry host and port.
+type imageReference struct {
+	Name   string
+	Tag    string
+	Digest string
+}
+
+var (
+	imageTagRegexp    = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	imageDigestRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[0-9a-fA-F]{32,}$`)
+)
+
+func parseImageReference(image string) (imageReference, error) {
+	var ref imageReference
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if !imageDigestRegexp.MatchString(ref.Digest) {
+			return ref, errors.Newf("invalid digest %q in image %q", ref.Digest, image)
+		}
+	}
+	// A colon after the last slash separates the tag, while one before it
+	// belongs to the registry's port.
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+		if !imageTagRegexp.MatchString(ref.Tag) {
+			return ref, errors.Newf("invalid tag %q in image %q", ref.Tag, image)
+		}
+	}
+	if err := validateImageRepository(name); err != nil {
+		return ref, errors.Wrapf(err, "invalid image %q", image)
+	}
+	ref.Name = name
+	return ref, nil
+}
+
+func validateImageRepository(name string) error {
+	if name == "" {
+		return errors.New("repository is empty")
+	}
+	if strings.ContainsAny(name, "@ ") {
+		return errors.Newf("repository %q must not contain a digest or whitespace", name)
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		return errors.Newf("repository %q must not contain a tag", name)
+	}
+	for _, component := range strings.Split(name, "/") {
+		if component == "" {
+			return errors.Newf("repository %q has an empty path component", name)
+		}
+	}
+	return nil
+}
+
+func (r imageReference) String() string {
+	s := r.Name
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// imageRepositoryPathTemplateData is what ImageRepositoryPathTemplate is
+// rendered with.
+type imageRepositoryPathTemplateData struct {
+	// Repository is the ImageRepository.
+	Repository string
+	// Name is the name of the default image, e.g. postgres-12-alpine.
+	Name string
+	// Component is the component that the image is for, e.g. pgsql.
+	Component string
+}
+
+// mirrorRepository returns the repository that a default image is pulled from.
+func mirrorRepository(spec SourcegraphSpec, component, name string) (string, error) {
+	repository := strings.TrimSuffix(spec.ImageRepository, "/")
+	if spec.ImageRepositoryPathTemplate == "" {
+		mirrored := repository + "/" + name
+		if err := validateImageRepository(mirrored); err != nil {
+			return "", errors.Wrap(err, "invalid imageRepository")
+		}
+		return mirrored, nil
+	}
+
+	tmpl, err := template.New("imageRepositoryPathTemplate").Option("missingkey=error").Parse(spec.ImageRepositoryPathTemplate)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing imageRepositoryPathTemplate")
+	}
+	var mirrored strings.Builder
+	if err := tmpl.Execute(&mirrored, imageRepositoryPathTemplateData{
+		Repository: repository,
+		Name:       name,
+		Component:  component,
+	}); err != nil {
+		return "", errors.Wrap(err, "rendering imageRepositoryPathTemplate")
+	}
+	if err := validateImageRepository(mirrored.String()); err != nil {
+		return "", errors.Wrapf(err, "imageRepositoryPathTemplate rendered an invalid repository for service %s", component)
+	}
+	return mirrored.String(), nil
+}
+
+// serviceContainer is a container that the appliance runs for a service.
+type serviceContainer struct {
+	// name is the name of the container, which is also its key in the
+	// service's ContainerConfig.
+	name string
+	// component is the key of the container's default image.
+	component string
+}
+
+// serviceContainers maps the JSON name of every *Spec field of SourcegraphSpec
+// to the containers that the appliance runs for it. ListImages fails for
+// fields missing from here, so that new services can't be forgotten.
+var serviceContainers = map[string][]serviceContainer{
+	"blobstore": {
+		{name: "blobstore", component: "blobstore"},
+		{name: "retention", component: "alpine"},
+	},
+	"cadvisor": {{name: "cadvisor", component: "cadvisor"}},
+	"codeInsights": {
+		{name: "codeinsights", component: "codeinsights-db"},
+		{name: "correct-data-dir-permissions", component: "alpine"},
+		{name: "pgsql-exporter", component: "pgsql-exporter"},
+		// The connection pooler runs in a Deployment of its own, but is
+		// configured along with the database.
+		{name: "pgbouncer", component: "pgbouncer"},
+	},
+	"codeIntel": {
+		{name: "codeintel-db", component: "codeintel-db"},
+		{name: "correct-data-dir-permissions", component: "alpine"},
+		{name: "pgsql-exporter", component: "pgsql-exporter"},
+		{name: "pgbouncer", component: "pgbouncer"},
+	},
+	"executors": {
+		{name: "executor", component: "executor"},
+		// batcheshelper doesn't run in the executors' pods, but in the
+		// pods of their Jobs, so overriding its image in the
+		// ContainerConfig overrides the image of the Jobs' steps.
+		{name: "batcheshelper", component: "batcheshelper"},
+	},
+	"frontend": {
+		{name: "frontend", component: "frontend"},
+		{name: "wait-for-databases", component: "alpine"},
+	},
+	"gitServer": {{name: "gitserver", component: "gitserver"}},
+	"grafana":   {{name: "grafana", component: "grafana"}},
+	"indexedSearch": {
+		{name: "zoekt-webserver", component: "indexed-search"},
+		{name: "zoekt-indexserver", component: "indexed-search-indexer"},
+	},
+	"otelCollector": {{name: "otel-collector", component: "otel-collector"}},
+	"pgsql": {
+		{name: "pgsql", component: "pgsql"},
+		{name: "correct-data-dir-permissions", component: "alpine"},
+		{name: "pgsql-exporter", component: "pgsql-exporter"},
+		{name: "pgbouncer", component: "pgbouncer"},
+	},
+	"preciseCodeIntel": {
+		{name: "precise-code-intel-worker", component: "precise-code-intel-worker"},
+		{name: "wait-for-databases", component: "alpine"},
+	},
+	"prometheus": {{name: "prometheus", component: "prometheus"}},
+	"redisCache": {
+		{name: "redis-cache", component: "redis-cache"},
+		{name: "redis-exporter", component: "redis-exporter"},
+	},
+	"redisStore": {
+		{name: "redis-store", component: "redis-store"},
+		{name: "redis-exporter", component: "redis-exporter"},
+	},
+	"repoUpdater":   {{name: "repo-updater", component: "repo-updater"}},
+	"searcher":      {{name: "searcher", component: "searcher"}},
+	"symbols":       {{name: "symbols", component: "symbols"}},
+	"syntectServer": {{name: "syntect-server", component: "syntect-server"}},
+	"worker": {
+		{name: "worker", component: "worker"},
+		{name: "wait-for-databases", component: "alpine"},
+	},
+
+	// Embeddings run in the worker, and the indexer and Postgres exporters
+	// run in the indexed-search and database pods.
+	"embeddings":           nil,
+	"indexedSearchIndexer": nil,
+	"postgresExporter":     nil,
+
+	// These configure the deployment as a whole rather than a service.
+	"maintenanceMode": nil,
+	"migrationGate":   nil,
+	"monitoring":      nil,
+	"networkPolicies": nil,
+	"storageClass":    nil,
+}
+
+// ListImages returns every image that the appliance pulls for the spec, sorted
+// and deduplicated, e.g. to mirror them for an air-gapped deployment. It
+// honors each container's image override and the services' sidecars, and
+// leaves out services that are disabled or replaced by external ones.
+func ListImages(sg *Sourcegraph) ([]string, error) {
+	containerImages, err := ResolveContainerImages(sg)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]struct{}{}
+	for _, image := range containerImages {
+		seen[image] = struct{}{}
+	}
+
+	images := make([]string, 0, len(seen))
+	for image := range seen {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// ResolveContainerImages returns the image of every container that the
+// appliance runs for the spec, keyed by the JSON name of its service and its
+// own name, e.g. "frontend/frontend". Like ListImages, it honors image
+// overrides and includes sidecars.
+func ResolveContainerImages(sg *Sourcegraph) (map[string]string, error) {
+	images := map[string]string{}
+	spec := reflect.ValueOf(sg.Spec)
+	for i := 0; i < spec.NumField(); i++ {
+		field := spec.Type().Field(i)
+		if !isServiceSpecField(field) {
+			continue
+		}
+		name := jsonFieldName(field)
+		containers, ok := serviceContainers[name]
+		if !ok {
+			return nil, errors.Newf("the images of spec.%s are unknown", name)
+		}
+		cfg, ok := spec.Field(i).Interface().(StandardComponent)
+		if !ok || !sg.Spec.runsService(name, cfg) {
+			continue
+		}
+
+		for _, ctr := range containers {
+			image, err := GetDefaultImage(sg, ctr.component)
+			if err != nil {
+				return nil, err
+			}
+			images[name+"/"+ctr.name] = cfg.GetContainerConfig()[ctr.name].ImageFor(image)
+		}
+		for _, sidecar := range cfg.GetSidecars() {
+			images[name+"/"+sidecar.Name] = sidecar.Image
+		}
+		for _, initCtr := range cfg.GetExtraInitContainers() {
+			images[name+"/"+initCtr.Name] = initCtr.Image
+		}
+	}
+	return images, nil
+}
+
+// runsService returns whether the appliance runs containers for a service.
+func (s SourcegraphSpec) runsService(name string, cfg StandardComponent) bool {
+	if cfg.IsDisabled() {
+		return false
+	}
+	if external, ok := cfg.(interface{ IsExternal() bool }); ok && external.IsExternal() {
+		return false
+	}
+	return name != "prometheus" || s.Monitoring.GetMode() == MonitoringModeBundled
+}
+
+func isServiceSpecField(field reflect.StructField) bool {
+	return field.Type.Kind() == reflect.Struct && strings.HasSuffix(field.Type.Name(), "Spec")
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
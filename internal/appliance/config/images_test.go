@@ -0,0 +1,392 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseImageReference(t *testing.T) {
+	for _, tc := range []struct {
+		image   string
+		want    imageReference
+		wantErr string
+	}{
+		{
+			image: "frontend",
+			want:  imageReference{Name: "frontend"},
+		},
+		{
+			image: "frontend:5.3.2",
+			want:  imageReference{Name: "frontend", Tag: "5.3.2"},
+		},
+		{
+			image: "gitserver:5.3.2@sha256:6c6042cf3e5f3f16de9b82e3d4ab1647f8bb924cd315245bd7a3162f5489e8c4",
+			want: imageReference{
+				Name:   "gitserver",
+				Tag:    "5.3.2",
+				Digest: "sha256:6c6042cf3e5f3f16de9b82e3d4ab1647f8bb924cd315245bd7a3162f5489e8c4",
+			},
+		},
+		{
+			image: "registry.example.com:5000/sourcegraph/gitserver@sha256:6c6042cf3e5f3f16de9b82e3d4ab1647f8bb924cd315245bd7a3162f5489e8c4",
+			want: imageReference{
+				Name:   "registry.example.com:5000/sourcegraph/gitserver",
+				Digest: "sha256:6c6042cf3e5f3f16de9b82e3d4ab1647f8bb924cd315245bd7a3162f5489e8c4",
+			},
+		},
+		{
+			image: "registry.example.com:5000/sourcegraph/frontend",
+			want:  imageReference{Name: "registry.example.com:5000/sourcegraph/frontend"},
+		},
+		{
+			image:   "frontend:5.3.2@sha256:short",
+			wantErr: `invalid digest "sha256:short"`,
+		},
+		{
+			image:   "frontend:5.3.2:extra",
+			wantErr: `repository "frontend:5.3.2" must not contain a tag`,
+		},
+		{
+			image:   "frontend:-5.3.2",
+			wantErr: `invalid tag "-5.3.2"`,
+		},
+		{
+			image:   ":5.3.2",
+			wantErr: "repository is empty",
+		},
+		{
+			image:   "sourcegraph//frontend:5.3.2",
+			wantErr: `repository "sourcegraph//frontend" has an empty path component`,
+		},
+	} {
+		t.Run(tc.image, func(t *testing.T) {
+			ref, err := parseImageReference(tc.image)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, ref)
+			assert.Equal(t, tc.image, ref.String())
+		})
+	}
+}
+
+func TestGetDefaultImage_Mirrors(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		imageRepository string
+		pathTemplate    string
+		component       string
+		want            string
+		wantErr         string
+	}{
+		{
+			name:      "docker.io default with tag and digest",
+			component: "gitserver",
+			want:      "index.docker.io/sourcegraph/gitserver:5.3.2@sha256:6c6042cf3e5f3f16de9b82e3d4ab1647f8bb924cd315245bd7a3162f5489e8c4",
+		},
+		{
+			name:      "docker.io default with tag only",
+			component: "frontend",
+			want:      "index.docker.io/sourcegraph/frontend:5.3.2",
+		},
+		{
+			name:            "GCR mirror",
+			imageRepository: "us-docker.pkg.dev/acme-prod/sourcegraph",
+			component:       "pgsql",
+			want:            "us-docker.pkg.dev/acme-prod/sourcegraph/postgres-12-alpine:5.3.2@sha256:1e0e93661a65c832b9697048c797f9894dfb502e2e1da2b8209f0018a6632b79",
+		},
+		{
+			name:            "GCR mirror with a trailing slash",
+			imageRepository: "gcr.io/acme-prod/sourcegraph/",
+			component:       "frontend",
+			want:            "gcr.io/acme-prod/sourcegraph/frontend:5.3.2",
+		},
+		{
+			name:            "Harbor project with nested paths",
+			imageRepository: "harbor.acme.internal:8443/platform/mirrors/sourcegraph",
+			component:       "syntect-server",
+			want:            "harbor.acme.internal:8443/platform/mirrors/sourcegraph/syntax-highlighter:5.3.2@sha256:3d16ab2a0203fea85063dcfe2e9d476540ef3274c28881dc4bbd5ca77933d8e8",
+		},
+		{
+			name:            "Harbor project with flattened names",
+			imageRepository: "harbor.acme.internal/platform",
+			pathTemplate:    "{{.Repository}}/sourcegraph-{{.Name}}",
+			component:       "symbols",
+			want:            "harbor.acme.internal/platform/sourcegraph-symbols:5.3.2@sha256:dd7f923bdbd5dbd231b749a7483110d40d59159084477b9fff84afaf58aad98e",
+		},
+		{
+			name:            "template renaming components",
+			imageRepository: "registry.acme.internal",
+			pathTemplate:    "{{.Repository}}/sourcegraph/{{.Component}}",
+			component:       "indexed-search",
+			want:            "registry.acme.internal/sourcegraph/indexed-search:5.3.2",
+		},
+		{
+			name:         "template that adds a tag",
+			pathTemplate: "{{.Repository}}/{{.Name}}:latest",
+			component:    "frontend",
+			wantErr:      `repository "index.docker.io/sourcegraph/frontend:latest" must not contain a tag`,
+		},
+		{
+			name:         "template with an unknown field",
+			pathTemplate: "{{.Repository}}/{{.Image}}",
+			component:    "frontend",
+			wantErr:      "rendering imageRepositoryPathTemplate",
+		},
+		{
+			name:         "malformed template",
+			pathTemplate: "{{.Repository}/{{.Name}}",
+			component:    "frontend",
+			wantErr:      "parsing imageRepositoryPathTemplate",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sg := NewDefaultConfig()
+			sg.Spec.RequestedVersion = "5.3.9104"
+			if tc.imageRepository != "" {
+				sg.Spec.ImageRepository = tc.imageRepository
+			}
+			sg.Spec.ImageRepositoryPathTemplate = tc.pathTemplate
+
+			image, err := GetDefaultImage(&sg, tc.component)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, image)
+		})
+	}
+}
+
+func TestResolveAllImages(t *testing.T) {
+	sg := NewDefaultConfig()
+	sg.Spec.RequestedVersion = "5.3.9104"
+	sg.Spec.ImageRepository = "harbor.acme.internal/platform"
+	sg.Spec.ImageRepositoryPathTemplate = "{{.Repository}}/sourcegraph-{{.Name}}"
+
+	images, err := ResolveAllImages(&sg)
+	require.NoError(t, err)
+	assert.Len(t, images, len(defaultImagesForVersion_5_3_9104))
+	assert.Equal(t, "harbor.acme.internal/platform/sourcegraph-frontend:5.3.2", images["frontend"])
+	assert.Equal(t, "harbor.acme.internal/platform/sourcegraph-alpine-3.14:5.3.2@sha256:982220e0fd8ce55a73798fa7e814a482c4807c412f054c8440c5970b610239b7", images["alpine"])
+
+	sg.Spec.RequestedVersion = "1.2.3"
+	_, err = ResolveAllImages(&sg)
+	assert.ErrorContains(t, err, "no default images found for version 1.2.3")
+}
+
+func TestResolveImageVersion(t *testing.T) {
+	original := defaultImages
+	t.Cleanup(func() { defaultImages = original })
+	defaultImages = map[string]map[string]string{
+		"5.2.7":    {"frontend": "frontend:5.2.7"},
+		"5.3.2":    {"frontend": "frontend:5.3.2"},
+		"5.3.9104": {"frontend": "frontend:5.3.9104"},
+		"5.4.1":    {"frontend": "frontend:5.4.1"},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		requested string
+		fallback  bool
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "exact match",
+			requested: "5.3.2",
+			want:      "5.3.2",
+		},
+		{
+			name:      "exact match with fallback",
+			requested: "5.3.2",
+			fallback:  true,
+			want:      "5.3.2",
+		},
+		{
+			name:      "unknown patch without fallback",
+			requested: "5.3.9105",
+			wantErr:   "no default images found for version 5.3.9105, supported versions are: 5.2.7, 5.3.2, 5.3.9104, 5.4.1",
+		},
+		{
+			name:      "unknown patch falls back to the closest earlier patch",
+			requested: "5.3.9105",
+			fallback:  true,
+			want:      "5.3.9104",
+		},
+		{
+			name:      "unknown patch between known patches",
+			requested: "5.3.100",
+			fallback:  true,
+			want:      "5.3.2",
+		},
+		{
+			name:      "no earlier patch of the minor version",
+			requested: "5.4.0",
+			fallback:  true,
+			wantErr:   "no earlier patch release of 5.4 to fall back to",
+		},
+		{
+			name:      "never falls back to another minor version",
+			requested: "5.5.0",
+			fallback:  true,
+			wantErr:   "no earlier patch release of 5.5 to fall back to",
+		},
+		{
+			name:      "malformed version with fallback",
+			requested: "latest",
+			fallback:  true,
+			wantErr:   "no default images found for version latest, supported versions are:",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sg := NewDefaultConfig()
+			sg.Spec.RequestedVersion = tc.requested
+			sg.Spec.ImageVersionFallback = tc.fallback
+
+			version, err := ResolveImageVersion(&sg)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, version)
+
+			image, err := GetDefaultImage(&sg, "frontend")
+			require.NoError(t, err)
+			assert.Equal(t, "index.docker.io/sourcegraph/frontend:"+tc.want, image)
+		})
+	}
+}
+
+func TestServiceContainersCoverSourcegraphSpec(t *testing.T) {
+	fields := map[string]bool{}
+	specType := reflect.TypeOf(SourcegraphSpec{})
+	for i := 0; i < specType.NumField(); i++ {
+		field := specType.Field(i)
+		if !isServiceSpecField(field) {
+			continue
+		}
+		name := jsonFieldName(field)
+		fields[name] = true
+		assert.Contains(t, serviceContainers, name, "add the containers of SourcegraphSpec.%s to serviceContainers", field.Name)
+	}
+	for name, containers := range serviceContainers {
+		assert.True(t, fields[name], "serviceContainers has an entry for %s, which isn't a field of SourcegraphSpec", name)
+		for _, ctr := range containers {
+			assert.Contains(t, defaultImagesForVersion_5_3_9104, ctr.component, "container %s of %s", ctr.name, name)
+		}
+	}
+}
+
+func TestListImages(t *testing.T) {
+	defaultImages := []string{
+		"index.docker.io/sourcegraph/alpine-3.14:5.3.2@sha256:982220e0fd8ce55a73798fa7e814a482c4807c412f054c8440c5970b610239b7",
+		"index.docker.io/sourcegraph/blobstore:5.3.2@sha256:d625be1eefe61cc42f94498e3c588bf212c4159c8b20c519db84eae4ff715efa",
+		"index.docker.io/sourcegraph/codeinsights-db:5.3.2@sha256:c4a1bd3908658e1c09558a638e378e5570d5f669d27f9f867eeda25fe60cb88f",
+		"index.docker.io/sourcegraph/codeintel-db:5.3.2@sha256:1e0e93661a65c832b9697048c797f9894dfb502e2e1da2b8209f0018a6632b79",
+		"index.docker.io/sourcegraph/frontend:5.3.2",
+		"index.docker.io/sourcegraph/gitserver:5.3.2@sha256:6c6042cf3e5f3f16de9b82e3d4ab1647f8bb924cd315245bd7a3162f5489e8c4",
+		"index.docker.io/sourcegraph/indexed-searcher:5.3.2",
+		"index.docker.io/sourcegraph/pgbouncer:5.3.2",
+		"index.docker.io/sourcegraph/postgres-12-alpine:5.3.2@sha256:1e0e93661a65c832b9697048c797f9894dfb502e2e1da2b8209f0018a6632b79",
+		"index.docker.io/sourcegraph/postgres_exporter:5.3.2@sha256:b9fa66fbcb4cc2d466487259db4ae2deacd7651dac4a9e28c9c7fc36523699d0",
+		"index.docker.io/sourcegraph/precise-code-intel-worker:5.3.2@sha256:6142093097f5757afe772cffd131c1be54bb77335232011254733f51ffb2d6c6",
+		"index.docker.io/sourcegraph/prometheus:5.3.2@sha256:1b5c003fb39628f79e7655ba33f9ca119ddc4be021602ede3cc1674ef99fcdad",
+		"index.docker.io/sourcegraph/redis-cache:5.3.2@sha256:ed79dada4d1a2bd85fb8450dffe227283ab6ae0e7ce56dc5056fbb8202d95624",
+		"index.docker.io/sourcegraph/redis-store:5.3.2@sha256:0e3270a5eb293c158093f41145810eb5a154f61a74c9a896690dfdecd1b98b39",
+		"index.docker.io/sourcegraph/redis_exporter:5.3.2@sha256:21a9dd9214483a42b11d58bf99e4f268f44257a4f67acd436d458797a31b7786",
+		"index.docker.io/sourcegraph/repo-updater:5.3.2@sha256:5a414aa030c7e0922700664a43b449ee5f3fafa68834abef93988c5992c747c6",
+		"index.docker.io/sourcegraph/search-indexer:5.3.2",
+		"index.docker.io/sourcegraph/searcher:5.3.2",
+		"index.docker.io/sourcegraph/symbols:5.3.2@sha256:dd7f923bdbd5dbd231b749a7483110d40d59159084477b9fff84afaf58aad98e",
+		"index.docker.io/sourcegraph/syntax-highlighter:5.3.2@sha256:3d16ab2a0203fea85063dcfe2e9d476540ef3274c28881dc4bbd5ca77933d8e8",
+		"index.docker.io/sourcegraph/worker:5.3.2@sha256:776168bb53a0b094f51bfec3d0d38e2938a07bb840b665b645ccf2637f0e779f",
+	}
+
+	for _, tc := range []struct {
+		name       string
+		mutate     func(sg *Sourcegraph)
+		want       []string
+		wantAdded  []string
+		wantRemove []string
+	}{
+		{
+			name:   "defaults",
+			mutate: func(sg *Sourcegraph) {},
+			want:   defaultImages,
+		},
+		{
+			name: "enabled opt-in services",
+			mutate: func(sg *Sourcegraph) {
+				sg.Spec.Cadvisor.Disabled = false
+				sg.Spec.Grafana.Disabled = false
+				sg.Spec.OtelCollector.Disabled = false
+			},
+			wantAdded: []string{
+				"index.docker.io/sourcegraph/cadvisor:5.3.2@sha256:3860cce1f7ef0278c0d785f66baf69dd2bece19610a2fd6eaa54c03095f2f105",
+				"index.docker.io/sourcegraph/grafana:5.3.2",
+				"index.docker.io/sourcegraph/opentelemetry-collector:5.3.2",
+			},
+		},
+		{
+			name: "disabled and external services",
+			mutate: func(sg *Sourcegraph) {
+				sg.Spec.Symbols.Disabled = true
+				sg.Spec.Blobstore.ExternalStorage = &ExternalStorageSpec{Backend: ExternalStorageBackendGCS, Bucket: "uploads"}
+				sg.Spec.RedisCache.External = &ExternalRedisSpec{Endpoint: "redis://cache.example.com:6379"}
+				sg.Spec.Monitoring.Mode = MonitoringModeServiceMonitor
+			},
+			wantRemove: []string{
+				"index.docker.io/sourcegraph/symbols:5.3.2@sha256:dd7f923bdbd5dbd231b749a7483110d40d59159084477b9fff84afaf58aad98e",
+				"index.docker.io/sourcegraph/blobstore:5.3.2@sha256:d625be1eefe61cc42f94498e3c588bf212c4159c8b20c519db84eae4ff715efa",
+				"index.docker.io/sourcegraph/redis-cache:5.3.2@sha256:ed79dada4d1a2bd85fb8450dffe227283ab6ae0e7ce56dc5056fbb8202d95624",
+				"index.docker.io/sourcegraph/prometheus:5.3.2@sha256:1b5c003fb39628f79e7655ba33f9ca119ddc4be021602ede3cc1674ef99fcdad",
+			},
+		},
+		{
+			name: "container image overrides and sidecars",
+			mutate: func(sg *Sourcegraph) {
+				sg.Spec.Frontend.ContainerConfig = map[string]ContainerConfig{
+					"frontend": {Image: "frontend:5.3.2-patched"},
+				}
+				sg.Spec.Worker.Sidecars = []corev1.Container{{Name: "proxy", Image: "registry.example.com/proxy:1.0"}}
+			},
+			wantAdded: []string{
+				"index.docker.io/sourcegraph/frontend:5.3.2-patched",
+				"registry.example.com/proxy:1.0",
+			},
+			wantRemove: []string{"index.docker.io/sourcegraph/frontend:5.3.2"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sg := NewDefaultConfig()
+			sg.Spec.RequestedVersion = "5.3.9104"
+			tc.mutate(&sg)
+
+			want := tc.want
+			if want == nil {
+				for _, image := range defaultImages {
+					if !slices.Contains(tc.wantRemove, image) {
+						want = append(want, image)
+					}
+				}
+				want = append(want, tc.wantAdded...)
+				sort.Strings(want)
+			}
+
+			images, err := ListImages(&sg)
+			require.NoError(t, err)
+			assert.Equal(t, want, images)
+		})
+	}
+}
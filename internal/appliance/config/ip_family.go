@@ -0,0 +1,65 @@
+package config
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// IPFamiliesFor returns the IP family policy and IP families of a service's
+// Services: the spec-wide ones, each overridden by the service's own. Empty
+// values leave the choice to the cluster.
+func (s SourcegraphSpec) IPFamiliesFor(cfg Disableable) (corev1.IPFamilyPolicy, []corev1.IPFamily) {
+	policy, families := s.IPFamilyPolicy, s.IPFamilies
+	if c, ok := cfg.(StandardComponent); ok {
+		if c.GetIPFamilyPolicy() != "" {
+			policy = c.GetIPFamilyPolicy()
+		}
+		if len(c.GetIPFamilies()) > 0 {
+			families = c.GetIPFamilies()
+		}
+	}
+	return policy, families
+}
+
+// ListenHostFor returns the host that a service's components bind to when
+// they must name one explicitly: "::" if its Services use IPv6, which also
+// accepts IPv4 connections, and "0.0.0.0" otherwise, as IPv6 may be disabled
+// on the nodes.
+func (s SourcegraphSpec) ListenHostFor(cfg Disableable) string {
+	policy, families := s.IPFamiliesFor(cfg)
+	if policy == corev1.IPFamilyPolicyRequireDualStack || slices.Contains(families, corev1.IPv6Protocol) {
+		return "::"
+	}
+	return "0.0.0.0"
+}
+
+// validateIPFamilies checks that an IP family policy and IP families are
+// accepted by the Kubernetes API server, alone and together.
+func validateIPFamilies(policy corev1.IPFamilyPolicy, families []corev1.IPFamily) error {
+	var errs error
+	switch policy {
+	case "", corev1.IPFamilyPolicySingleStack, corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack:
+	default:
+		errs = errors.Append(errs, errors.Newf("ipFamilyPolicy: %q must be one of %q, %q, or %q",
+			policy, corev1.IPFamilyPolicySingleStack, corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack))
+	}
+
+	for i, family := range families {
+		switch {
+		case family != corev1.IPv4Protocol && family != corev1.IPv6Protocol:
+			errs = errors.Append(errs, errors.Newf("ipFamilies[%d]: %q must be %q or %q", i, family, corev1.IPv4Protocol, corev1.IPv6Protocol))
+		case slices.Index(families, family) < i:
+			errs = errors.Append(errs, errors.Newf("ipFamilies[%d]: %q is listed twice", i, family))
+		}
+	}
+	if len(families) > 2 {
+		errs = errors.Append(errs, errors.Newf("ipFamilies: must list at most 2 families, got %d", len(families)))
+	} else if len(families) == 2 && policy != corev1.IPFamilyPolicyPreferDualStack && policy != corev1.IPFamilyPolicyRequireDualStack {
+		errs = errors.Append(errs, errors.Newf("ipFamilies: listing 2 families requires an ipFamilyPolicy of %q or %q",
+			corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack))
+	}
+	return errs
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIPFamiliesFor(t *testing.T) {
+	spec := NewDefaultConfig().Spec
+	policy, families := spec.IPFamiliesFor(spec.Frontend)
+	assert.Equal(t, corev1.IPFamilyPolicy(""), policy)
+	assert.Nil(t, families)
+	assert.Equal(t, "0.0.0.0", spec.ListenHostFor(spec.Frontend))
+
+	spec.IPFamilyPolicy = corev1.IPFamilyPolicySingleStack
+	spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	spec.Worker.IPFamilyPolicy = corev1.IPFamilyPolicyPreferDualStack
+	spec.Searcher.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol}
+
+	policy, families = spec.IPFamiliesFor(spec.Frontend)
+	assert.Equal(t, corev1.IPFamilyPolicySingleStack, policy)
+	assert.Equal(t, []corev1.IPFamily{corev1.IPv6Protocol}, families)
+	assert.Equal(t, "::", spec.ListenHostFor(spec.Frontend))
+
+	policy, families = spec.IPFamiliesFor(spec.Worker)
+	assert.Equal(t, corev1.IPFamilyPolicyPreferDualStack, policy)
+	assert.Equal(t, []corev1.IPFamily{corev1.IPv6Protocol}, families)
+
+	policy, families = spec.IPFamiliesFor(spec.Searcher)
+	assert.Equal(t, corev1.IPFamilyPolicySingleStack, policy)
+	assert.Equal(t, []corev1.IPFamily{corev1.IPv4Protocol}, families)
+	assert.Equal(t, "0.0.0.0", spec.ListenHostFor(spec.Searcher))
+}
+
+func TestListenHostFor_RequireDualStack(t *testing.T) {
+	spec := NewDefaultConfig().Spec
+	spec.IPFamilyPolicy = corev1.IPFamilyPolicyRequireDualStack
+	assert.Equal(t, "::", spec.ListenHostFor(spec.OtelCollector))
+}
@@ -0,0 +1,28 @@
+package config
+
+// MaintenanceModeSpec stops all traffic to Sourcegraph, e.g. during a
+// migration or backup, without deleting any data.
+type MaintenanceModeSpec struct {
+	// Enabled scales every Deployment and StatefulSet down to zero replicas.
+	// Their previous replica counts are restored once it is unset again.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// KeepDatabasesUp leaves pgsql, codeintel-db and codeinsights-db running
+	// while in maintenance mode.
+	KeepDatabasesUp bool `json:"keepDatabasesUp,omitempty"`
+}
+
+// ScaledDown reports whether a service's workloads should be scaled down to
+// zero replicas for maintenance.
+func (m MaintenanceModeSpec) ScaledDown(cfg Disableable) bool {
+	if !m.Enabled {
+		return false
+	}
+	if m.KeepDatabasesUp {
+		switch cfg.(type) {
+		case PGSQLSpec, CodeDBSpec:
+			return false
+		}
+	}
+	return true
+}
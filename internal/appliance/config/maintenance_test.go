@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceModeScaledDown(t *testing.T) {
+	spec := NewDefaultConfig().Spec
+
+	assert.False(t, spec.MaintenanceMode.ScaledDown(spec.Frontend))
+	assert.False(t, spec.MaintenanceMode.ScaledDown(spec.PGSQL))
+
+	spec.MaintenanceMode.Enabled = true
+	for _, cfg := range []Disableable{spec.Frontend, spec.GitServer, spec.PGSQL, spec.CodeIntel, spec.CodeInsights} {
+		assert.True(t, spec.MaintenanceMode.ScaledDown(cfg), "%T", cfg)
+	}
+
+	spec.MaintenanceMode.KeepDatabasesUp = true
+	for _, cfg := range []Disableable{spec.Frontend, spec.GitServer, spec.RedisStore} {
+		assert.True(t, spec.MaintenanceMode.ScaledDown(cfg), "%T", cfg)
+	}
+	for _, cfg := range []Disableable{spec.PGSQL, spec.CodeIntel, spec.CodeInsights} {
+		assert.False(t, spec.MaintenanceMode.ScaledDown(cfg), "%T", cfg)
+	}
+}
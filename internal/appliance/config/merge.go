@@ -0,0 +1,236 @@
+package config
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// MergeWithDefaults returns user merged on top of the defaults for its size,
+// i.e. NewDefaultConfigForSize(user.Spec.Size):
+//
+//   - nil pointers, maps, and slices inherit the default
+//   - non-nil pointers to scalars, e.g. PrometheusPort or a maxSurge
+//     intstr.IntOrString, override the default, even if they point to the
+//     zero value
+//   - non-nil pointers to structs are merged with the default field by field,
+//     except for security contexts, which replace it (see PodSecurityContext)
+//   - maps are merged key by key, and slices replace the default
+//   - other fields override the default unless they are the zero value
+//
+// These are the semantics of decoding YAML over the defaults, as
+// NewConfigFromYAML does, except that an unset field can't be told apart from
+// a zero one in Go. Set a field to the zero value through a pointer, or merge
+// with MergeDocumentWithDefaults.
+//
+// The result shares no memory with user, so that either can be modified
+// without affecting the other.
+func MergeWithDefaults(user Sourcegraph) (Sourcegraph, error) {
+	sg, err := NewDefaultConfigForSize(user.Spec.Size)
+	if err != nil {
+		return sg, err
+	}
+	mergeValue(reflect.ValueOf(&sg).Elem(), reflect.ValueOf(&user).Elem(), nil)
+	return sg, nil
+}
+
+// MergeDocumentWithDefaults is MergeWithDefaults for a user config that was
+// decoded from doc, its YAML or JSON in any supported API version. The fields
+// that doc sets override the default even if they are the zero value, e.g.
+// replicas: 0 or disabled: false, and so does null for pointers, maps, and
+// slices. Fields that doc doesn't set inherit the default.
+func MergeDocumentWithDefaults(user Sourcegraph, doc []byte) (Sourcegraph, error) {
+	sg, err := NewDefaultConfigForSize(user.Spec.Size)
+	if err != nil {
+		return sg, err
+	}
+	data, err := convertToInternalVersion(doc)
+	if err != nil {
+		return sg, err
+	}
+	data, _, err = migrateDeprecatedFields(data)
+	if err != nil {
+		return sg, err
+	}
+	var set map[string]any
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return sg, err
+	}
+	mergeValue(reflect.ValueOf(&sg).Elem(), reflect.ValueOf(&user).Elem(), set)
+	return sg, nil
+}
+
+// ExtraWorker returns the config of the extra worker called name, merged over
+// that of the worker as described by ExtraWorkers.
+func (c WorkerSpec) ExtraWorker(name string) WorkerSpec {
+	merged := deepCopyValue(reflect.ValueOf(c)).Interface().(WorkerSpec)
+	merged.JobAllowlist = nil
+	merged.JobDenylist = nil
+	merged.ExtraWorkers = nil
+	mergeValue(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(c.ExtraWorkers[name]), nil)
+	return merged
+}
+
+var (
+	podSecurityContextType = reflect.TypeOf(PodSecurityContext{})
+	securityContextType    = reflect.TypeOf(SecurityContext{})
+	intOrStringType        = reflect.TypeOf(intstr.IntOrString{})
+)
+
+// mergeValue merges src into dst, which must be settable. See
+// MergeWithDefaults.
+//
+// If set is the decoded document that src was decoded from, it tells which
+// fields were set, so that zero values override dst as well, see
+// MergeDocumentWithDefaults. Otherwise set is nil, and only non-zero values
+// do.
+func mergeValue(dst, src reflect.Value, set any) {
+	explicit := set != nil
+	if hasDeepCopy(src.Type()) {
+		// Kubernetes types, e.g. corev1.Affinity, are replaced as a whole,
+		// like the Kubernetes API does for most of them.
+		if explicit || !src.IsZero() {
+			dst.Set(deepCopyValue(src))
+		}
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Struct:
+		fields, _ := set.(map[string]any)
+		for i := 0; i < src.NumField(); i++ {
+			field := src.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if !explicit {
+				mergeValue(dst.Field(i), src.Field(i), nil)
+				continue
+			}
+			name := jsonFieldName(field)
+			if name == "" && field.Anonymous {
+				// Embedded structs are inlined, e.g. StandardConfig.
+				mergeValue(dst.Field(i), src.Field(i), set)
+			} else if value, ok := fields[name]; ok {
+				mergeValue(dst.Field(i), src.Field(i), setOrNull(value))
+			}
+		}
+	case reflect.Pointer:
+		if src.IsNil() {
+			if explicit {
+				dst.Set(reflect.Zero(dst.Type()))
+			}
+			return
+		}
+		elem := src.Type().Elem()
+		if dst.IsNil() || elem.Kind() != reflect.Struct || elem == podSecurityContextType || elem == securityContextType || elem == intOrStringType {
+			dst.Set(deepCopyValue(src))
+			return
+		}
+		// dst was copied from the defaults, so it is safe to merge into.
+		mergeValue(dst.Elem(), src.Elem(), set)
+	case reflect.Map:
+		if src.IsNil() {
+			if explicit {
+				dst.Set(reflect.Zero(dst.Type()))
+			}
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), deepCopyValue(iter.Value()))
+		}
+	case reflect.Slice:
+		if explicit || !src.IsNil() {
+			dst.Set(deepCopyValue(src))
+		}
+	default:
+		if explicit || !src.IsZero() {
+			dst.Set(src)
+		}
+	}
+}
+
+// explicitNull marks a field that the document sets to null, since a nil set
+// stands for a document that isn't known.
+type explicitNull struct{}
+
+// setOrNull returns the value of a field of the document for mergeValue.
+func setOrNull(value any) any {
+	if value == nil {
+		return explicitNull{}
+	}
+	return value
+}
+
+// deepCopyValue returns a copy of v that shares no memory with it.
+func deepCopyValue(v reflect.Value) reflect.Value {
+	if hasDeepCopy(v.Type()) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		return v.MethodByName("DeepCopy").Call(nil)[0]
+	}
+
+	out := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Struct:
+		// Unexported fields are copied as they are, as they can't be set
+		// individually.
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				out.Field(i).Set(deepCopyValue(v.Field(i)))
+			}
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			out.Set(reflect.New(v.Type().Elem()))
+			out.Elem().Set(deepCopyValue(v.Elem()))
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			out.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+			iter := v.MapRange()
+			for iter.Next() {
+				out.SetMapIndex(iter.Key(), deepCopyValue(iter.Value()))
+			}
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			out.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			for i := 0; i < v.Len(); i++ {
+				out.Index(i).Set(deepCopyValue(v.Index(i)))
+			}
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			out.Set(deepCopyValue(v.Elem()))
+		}
+	default:
+		out.Set(v)
+	}
+	return out
+}
+
+// configPkgPath is the import path of this package.
+var configPkgPath = reflect.TypeOf(Sourcegraph{}).PkgPath()
+
+// hasDeepCopy reports whether t has a generated DeepCopy method returning a t,
+// like Kubernetes API types do. The types of this package are excluded, as
+// their DeepCopy is implemented with deepCopyValue, see deepcopy.go.
+func hasDeepCopy(t reflect.Type) bool {
+	named := t
+	if named.Kind() == reflect.Pointer {
+		named = named.Elem()
+	}
+	if named.PkgPath() == configPkgPath {
+		return false
+	}
+	m, ok := t.MethodByName("DeepCopy")
+	return ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1 && m.Type.Out(0) == t
+}
@@ -0,0 +1,281 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func TestMergeWithDefaults_EmptySpecYieldsDefaults(t *testing.T) {
+	for _, size := range []DeploymentSize{"", DeploymentSizeXS, DeploymentSizeS, DeploymentSizeM, DeploymentSizeL, DeploymentSizeXL} {
+		t.Run(string(size), func(t *testing.T) {
+			want, err := NewDefaultConfigForSize(size)
+			require.NoError(t, err)
+			want.Spec.Size = size
+
+			sg, err := MergeWithDefaults(Sourcegraph{Spec: SourcegraphSpec{Size: size}})
+			require.NoError(t, err)
+			assert.Equal(t, want, sg)
+		})
+	}
+}
+
+func TestMergeWithDefaults_UnknownSize(t *testing.T) {
+	_, err := MergeWithDefaults(Sourcegraph{Spec: SourcegraphSpec{Size: "XXL"}})
+	assert.ErrorContains(t, err, `got "XXL"`)
+}
+
+func TestMergeWithDefaults_PartialStructInheritsDefaults(t *testing.T) {
+	sg, err := MergeWithDefaults(Sourcegraph{Spec: SourcegraphSpec{
+		Symbols: SymbolsSpec{
+			StandardConfig: StandardConfig{
+				PersistentVolumeConfig: PersistentVolumeConfig{StorageSize: "50Gi"},
+			},
+		},
+		PGSQL: PGSQLSpec{
+			DatabaseConnection: &DatabaseConnectionSpec{Host: "db.example.com"},
+		},
+	}})
+	require.NoError(t, err)
+
+	want := NewDefaultConfig()
+	want.Spec.Symbols.PersistentVolumeConfig.StorageSize = "50Gi"
+	want.Spec.PGSQL.DatabaseConnection.Host = "db.example.com"
+	assert.Equal(t, want, sg)
+	assert.Equal(t, pointers.Ptr(6060), sg.Spec.Symbols.PrometheusPort)
+}
+
+func TestMergeWithDefaults_ExplicitZeroPointerOverrides(t *testing.T) {
+	sg, err := MergeWithDefaults(Sourcegraph{Spec: SourcegraphSpec{
+		Frontend: FrontendSpec{
+			StandardConfig: StandardConfig{
+				PrometheusPort:    pointers.Ptr(0),
+				PriorityClassName: pointers.Ptr(""),
+				Strategy:          &StrategyConfig{MaxSurge: pointers.Ptr(intstr.FromInt32(0))},
+			},
+		},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, pointers.Ptr(0), sg.Spec.Frontend.PrometheusPort)
+	assert.Equal(t, pointers.Ptr(""), sg.Spec.Frontend.PriorityClassName)
+	// An IntOrString is a scalar, so a zero maxSurge replaces the default of
+	// 2, and the rest of the strategy is inherited.
+	assert.Equal(t, &StrategyConfig{
+		Type:           RollingUpdateStrategy,
+		MaxSurge:       pointers.Ptr(intstr.FromInt32(0)),
+		MaxUnavailable: pointers.Ptr(intstr.FromInt32(0)),
+	}, sg.Spec.Frontend.Strategy)
+}
+
+func TestMergeWithDefaults_SecurityContextsReplaceDefaults(t *testing.T) {
+	sg, err := MergeWithDefaults(Sourcegraph{Spec: SourcegraphSpec{
+		PGSQL: PGSQLSpec{
+			StandardConfig: StandardConfig{
+				PodSecurityContext: &PodSecurityContext{RunAsUser: pointers.Ptr[int64](2000)},
+			},
+		},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, &PodSecurityContext{RunAsUser: pointers.Ptr[int64](2000)}, sg.Spec.PGSQL.PodSecurityContext)
+	assert.Equal(t, NewDefaultConfig().Spec.PGSQL.ContainerSecurityContext, sg.Spec.PGSQL.ContainerSecurityContext)
+}
+
+func TestMergeWithDefaults_SlicesReplaceAndMapsMerge(t *testing.T) {
+	defaults := NewDefaultConfig()
+	defaults.Spec.Worker.Env = map[string]string{"A": "default", "B": "default"}
+	defaults.Spec.Worker.ImagePullSecrets = []string{"default-creds"}
+
+	sg := *defaults.DeepCopy()
+	mergeValue(reflect.ValueOf(&sg).Elem(), reflect.ValueOf(Sourcegraph{Spec: SourcegraphSpec{
+		Worker: WorkerSpec{
+			StandardConfig: StandardConfig{
+				Env:              map[string]string{"B": "user", "C": "user"},
+				ImagePullSecrets: []string{"user-creds"},
+			},
+		},
+	}}), nil)
+	assert.Equal(t, defaults.Spec.Worker.PrometheusPort, sg.Spec.Worker.PrometheusPort)
+	assert.Equal(t, map[string]string{"A": "default", "B": "user", "C": "user"}, sg.Spec.Worker.Env)
+	assert.Equal(t, []string{"user-creds"}, sg.Spec.Worker.ImagePullSecrets)
+}
+
+func TestMergeDocumentWithDefaults_ZeroValuesOverride(t *testing.T) {
+	doc := []byte(`
+spec:
+  cadvisor:
+    disabled: false
+  frontend:
+    replicas: 0
+  worker:
+    prometheusPort: null
+`)
+	var user Sourcegraph
+	require.NoError(t, yaml.Unmarshal(doc, &user))
+
+	defaults := NewDefaultConfig()
+	require.True(t, defaults.Spec.Cadvisor.Disabled)
+	require.NotZero(t, defaults.Spec.Frontend.Replicas)
+	require.NotNil(t, defaults.Spec.Worker.PrometheusPort)
+
+	// MergeWithDefaults can't tell the zero values from unset fields.
+	sg, err := MergeWithDefaults(user)
+	require.NoError(t, err)
+	assert.Equal(t, defaults, sg)
+
+	sg, err = MergeDocumentWithDefaults(user, doc)
+	require.NoError(t, err)
+	want := NewDefaultConfig()
+	want.Spec.Cadvisor.Disabled = false
+	want.Spec.Frontend.Replicas = 0
+	want.Spec.Worker.PrometheusPort = nil
+	assert.Equal(t, want, sg)
+
+	// It agrees with decoding the document over the defaults.
+	decoded, err := NewConfigFromYAML(doc)
+	require.NoError(t, err)
+	assert.Equal(t, decoded, sg)
+}
+
+func TestMergeDocumentWithDefaults_UnsetFieldsInheritDefaults(t *testing.T) {
+	doc := []byte(`
+spec:
+  symbols:
+    persistentVolumeConfig:
+      storageSize: 50Gi
+`)
+	var user Sourcegraph
+	require.NoError(t, yaml.Unmarshal(doc, &user))
+
+	sg, err := MergeDocumentWithDefaults(user, doc)
+	require.NoError(t, err)
+	want := NewDefaultConfig()
+	want.Spec.Symbols.PersistentVolumeConfig.StorageSize = "50Gi"
+	assert.Equal(t, want, sg)
+}
+
+func TestMergeWithDefaults_ResultDoesNotAliasInputs(t *testing.T) {
+	user := Sourcegraph{Spec: SourcegraphSpec{
+		Frontend: FrontendSpec{
+			StandardConfig: StandardConfig{
+				PrometheusPort: pointers.Ptr(7070),
+				ContainerConfig: map[string]ContainerConfig{
+					"frontend": {
+						Resources: &corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+						},
+					},
+				},
+			},
+		},
+	}}
+	sg, err := MergeWithDefaults(user)
+	require.NoError(t, err)
+
+	*sg.Spec.Frontend.PrometheusPort = 8080
+	sg.Spec.Frontend.ContainerConfig["frontend"].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("2Gi")
+	*sg.Spec.Searcher.PrometheusPort = 8080
+
+	assert.Equal(t, 7070, *user.Spec.Frontend.PrometheusPort)
+	assert.Equal(t, "1Gi", pointers.Ptr(user.Spec.Frontend.ContainerConfig["frontend"].Resources.Limits[corev1.ResourceMemory]).String())
+	again, err := MergeWithDefaults(Sourcegraph{})
+	require.NoError(t, err)
+	assert.Equal(t, NewDefaultConfig(), again)
+}
+
+func TestDeepCopy(t *testing.T) {
+	sg := NewDefaultConfig()
+	sg.Spec.Frontend.Env = map[string]string{"A": "a"}
+	sg.Spec.Frontend.ImagePullSecrets = []string{"creds"}
+
+	cp := sg.DeepCopy()
+	require.Equal(t, sg, *cp)
+
+	*cp.Spec.Frontend.PrometheusPort = 8080
+	*cp.Spec.PGSQL.PodSecurityContext.RunAsUser = 0
+	cp.Spec.PGSQL.DatabaseConnection.Host = "elsewhere"
+	cp.Spec.Frontend.Env["A"] = "b"
+	cp.Spec.Frontend.ImagePullSecrets[0] = "other"
+	assert.Equal(t, NewDefaultConfig().Spec.PGSQL, sg.Spec.PGSQL)
+	assert.Equal(t, pointers.Ptr(6060), sg.Spec.Frontend.PrometheusPort)
+	assert.Equal(t, map[string]string{"A": "a"}, sg.Spec.Frontend.Env)
+	assert.Equal(t, []string{"creds"}, sg.Spec.Frontend.ImagePullSecrets)
+}
+
+func FuzzMergeWithDefaults(f *testing.F) {
+	f.Add("")
+	f.Add("spec:\n  size: M\n")
+	f.Add("spec:\n  symbols:\n    persistentVolumeConfig:\n      storageSize: 50Gi\n")
+	f.Add("spec:\n  frontend:\n    prometheusPort: 0\n    env:\n      A: b\n    imagePullSecrets: [creds]\n")
+	f.Add("spec:\n  pgsql:\n    databaseConnection:\n      host: db\n    podSecurityContext:\n      runAsUser: 2000\n")
+	f.Add("spec:\n  searcher:\n    containerConfig:\n      searcher:\n        resources:\n          limits:\n            memory: 1Gi\n")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		var user Sourcegraph
+		if err := yaml.Unmarshal([]byte(data), &user); err != nil {
+			return
+		}
+		if _, ok := sizePresets[user.Spec.Size]; !ok {
+			user.Spec.Size = ""
+		}
+
+		merged, err := MergeWithDefaults(user)
+		require.NoError(t, err)
+
+		// Merging is idempotent.
+		again, err := MergeWithDefaults(merged)
+		require.NoError(t, err)
+		require.Equal(t, merged, again)
+
+		// Merging doesn't modify the defaults, nor alias them.
+		empty, err := MergeWithDefaults(Sourcegraph{Spec: SourcegraphSpec{Size: user.Spec.Size}})
+		require.NoError(t, err)
+		defaults, err := NewDefaultConfigForSize(user.Spec.Size)
+		require.NoError(t, err)
+		defaults.Spec.Size = user.Spec.Size
+		require.Equal(t, defaults, empty)
+	})
+}
+
+func TestWorkerSpecExtraWorker(t *testing.T) {
+	sg, err := NewConfigFromYAML([]byte(`
+spec:
+  worker:
+    replicas: 2
+    jobDenylist: [insights-job]
+    podTemplateConfig:
+      nodeSelector:
+        pool: workers
+    extraWorkers:
+      insights:
+        jobAllowlist: [insights-job]
+        containerConfig:
+          worker:
+            resources:
+              limits:
+                memory: 8G
+`))
+	require.NoError(t, err)
+
+	insights := sg.Spec.Worker.ExtraWorker("insights")
+	assert.Equal(t, []string{"insights-job"}, insights.JobAllowlist)
+	assert.Nil(t, insights.JobDenylist)
+	assert.Nil(t, insights.ExtraWorkers)
+
+	// Everything else is inherited from the worker, unless overridden.
+	assert.Equal(t, int32(2), insights.Replicas)
+	assert.Equal(t, map[string]string{"pool": "workers"}, insights.PodTemplateConfig.NodeSelector)
+	assert.Equal(t, sg.Spec.Worker.PodSecurityContext, insights.PodSecurityContext)
+	assert.Equal(t, resource.MustParse("8G"), insights.ContainerConfig["worker"].Resources.Limits[corev1.ResourceMemory])
+
+	// The worker's own config is left as it is.
+	assert.Equal(t, []string{"insights-job"}, sg.Spec.Worker.JobDenylist)
+	assert.Nil(t, sg.Spec.Worker.ContainerConfig)
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Minimize returns the smallest config that MergeWithDefaults turns back into
+// sg, i.e. one with only the fields of sg that differ from the defaults for its
+// size. This is what a user actually changed, e.g. to share for support.
+//
+// MergeWithDefaults can't tell an unset field from a zero one, so a field that
+// is zero or nil where the default isn't, e.g. spec.cadvisor.disabled set to
+// false, can't be expressed, and neither can a map that lacks a key of the
+// default. Such fields are reported as an error, along with the config
+// minimized as far as possible. MarshalMinimalYAML can express all but the
+// missing map keys.
+func Minimize(sg Sourcegraph) (Sourcegraph, error) {
+	minimal, m, err := minimize(sg)
+	if err != nil {
+		return minimal, err
+	}
+	errs := m.errs
+	for _, f := range m.fields {
+		if f.zero {
+			errs = errors.Append(errs, errors.Newf("%s: the zero value can't override the default", strings.Join(f.path, ".")))
+		}
+	}
+	return minimal, errs
+}
+
+// MarshalMinimalYAML returns the YAML of the minimized sg, see Minimize, which
+// NewConfigFromYAML decodes back into sg. Unlike with MergeWithDefaults, fields
+// that are zero where the default isn't are set explicitly.
+func MarshalMinimalYAML(sg Sourcegraph) ([]byte, error) {
+	_, m, err := minimize(sg)
+	if err != nil {
+		return nil, err
+	}
+	if m.errs != nil {
+		return nil, m.errs
+	}
+
+	// Build the document from the fields that differ, rather than from the
+	// minimized config, since the latter has empty structs, and lacks the
+	// zero values.
+	doc := map[string]any{}
+	for _, f := range m.fields {
+		data, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		setFieldPath(doc, f.path, value)
+	}
+	return yaml.Marshal(doc)
+}
+
+func minimize(sg Sourcegraph) (Sourcegraph, *minimizer, error) {
+	defaults, err := NewDefaultConfigForSize(sg.Spec.Size)
+	if err != nil {
+		return Sourcegraph{}, nil, err
+	}
+	var minimal Sourcegraph
+	m := &minimizer{}
+	m.minimize(reflect.ValueOf(&minimal).Elem(), reflect.ValueOf(defaults), reflect.ValueOf(sg), nil)
+	return minimal, m, nil
+}
+
+// minimizer computes the inverse of mergeValue.
+type minimizer struct {
+	// fields are the fields that differ from the default.
+	fields []minimalField
+
+	// errs are the differences that can't be expressed at all.
+	errs error
+}
+
+// minimalField is a field that differs from the default, by the JSON path of
+// the field.
+type minimalField struct {
+	path  []string
+	value any
+
+	// zero is true if the field is zero or nil where the default isn't.
+	zero bool
+}
+
+// set sets dst to val, and records it as the field at path.
+func (m *minimizer) set(dst, val reflect.Value, path []string) {
+	dst.Set(val)
+	m.fields = append(m.fields, minimalField{path: path, value: val.Interface()})
+}
+
+// add sets dst, which must be settable and zero, to val, which has no default.
+// Structs, and pointers and maps of them, are recorded field by field, so that
+// MarshalMinimalYAML leaves out their zero fields.
+func (m *minimizer) add(dst, val reflect.Value, path []string) {
+	if hasDeepCopy(val.Type()) {
+		m.set(dst, deepCopyValue(val), path)
+		return
+	}
+	switch val.Kind() {
+	case reflect.Struct:
+		m.fields = append(m.fields, minimalField{path: path, value: struct{}{}})
+		m.minimize(dst, reflect.Zero(val.Type()), val, path)
+	case reflect.Pointer:
+		elem := val.Type().Elem()
+		if val.IsNil() || elem.Kind() != reflect.Struct || elem == podSecurityContextType || elem == securityContextType {
+			m.set(dst, deepCopyValue(val), path)
+			return
+		}
+		dst.Set(reflect.New(elem))
+		m.add(dst.Elem(), val.Elem(), path)
+	case reflect.Map:
+		if val.IsNil() {
+			return
+		}
+		m.fields = append(m.fields, minimalField{path: path, value: struct{}{}})
+		dst.Set(reflect.MakeMapWithSize(val.Type(), val.Len()))
+		iter := val.MapRange()
+		for iter.Next() {
+			value := reflect.New(val.Type().Elem()).Elem()
+			m.add(value, iter.Value(), append(append([]string{}, path...), fmt.Sprint(iter.Key().Interface())))
+			dst.SetMapIndex(iter.Key(), value)
+		}
+	default:
+		m.set(dst, deepCopyValue(val), path)
+	}
+}
+
+// minimize sets dst, which must be settable and zero, so that merging it into
+// def yields val. path is the JSON path of dst.
+func (m *minimizer) minimize(dst, def, val reflect.Value, path []string) {
+	if reflect.DeepEqual(def.Interface(), val.Interface()) {
+		return
+	}
+
+	if hasDeepCopy(val.Type()) {
+		// Kubernetes types replace the default as a whole.
+		if val.IsZero() {
+			m.zero(val, path)
+			return
+		}
+		m.set(dst, deepCopyValue(val), path)
+		return
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		for i := 0; i < val.NumField(); i++ {
+			field := val.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := path
+			if name := jsonFieldName(field); name != "" {
+				fieldPath = append(append([]string{}, path...), name)
+			}
+			m.minimize(dst.Field(i), def.Field(i), val.Field(i), fieldPath)
+		}
+	case reflect.Pointer:
+		if val.IsNil() {
+			m.zero(val, path)
+			return
+		}
+		elem := val.Type().Elem()
+		if def.IsNil() {
+			m.add(dst, val, path)
+			return
+		}
+		if elem.Kind() != reflect.Struct || elem == podSecurityContextType || elem == securityContextType {
+			m.set(dst, deepCopyValue(val), path)
+			return
+		}
+		minimal := reflect.New(elem)
+		m.minimize(minimal.Elem(), def.Elem(), val.Elem(), path)
+		if !minimal.Elem().IsZero() {
+			dst.Set(minimal)
+		}
+	case reflect.Map:
+		if val.IsNil() {
+			m.zero(val, path)
+			return
+		}
+		if def.IsNil() {
+			m.add(dst, val, path)
+			return
+		}
+		iter := def.MapRange()
+		for iter.Next() {
+			if !val.MapIndex(iter.Key()).IsValid() {
+				m.errs = errors.Append(m.errs, errors.Newf("%s: missing key %v of the default can't be removed", strings.Join(path, "."), iter.Key()))
+			}
+		}
+		iter = val.MapRange()
+		for iter.Next() {
+			if d := def.MapIndex(iter.Key()); d.IsValid() && reflect.DeepEqual(d.Interface(), iter.Value().Interface()) {
+				continue
+			}
+			if dst.IsNil() {
+				dst.Set(reflect.MakeMap(val.Type()))
+			}
+			// Map values replace the default as a whole.
+			value := reflect.New(val.Type().Elem()).Elem()
+			m.add(value, iter.Value(), append(append([]string{}, path...), fmt.Sprint(iter.Key().Interface())))
+			dst.SetMapIndex(iter.Key(), value)
+		}
+	case reflect.Slice:
+		if val.IsNil() {
+			m.zero(val, path)
+			return
+		}
+		m.set(dst, deepCopyValue(val), path)
+	default:
+		if val.IsZero() {
+			m.zero(val, path)
+			return
+		}
+		m.set(dst, val, path)
+	}
+}
+
+// zero records that the field at path is zero or nil where the default isn't.
+// In JSON, a nil pointer, map, or slice is null, and a scalar is its zero
+// value. A zero struct can't be expressed, as it is merged into the default.
+func (m *minimizer) zero(val reflect.Value, path []string) {
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		m.fields = append(m.fields, minimalField{path: path, value: nil, zero: true})
+	case reflect.Struct:
+		m.errs = errors.Append(m.errs, errors.Newf("%s: can't be reset to the zero value", strings.Join(path, ".")))
+	default:
+		m.fields = append(m.fields, minimalField{path: path, value: val.Interface(), zero: true})
+	}
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+const minimizeSpec = `
+spec:
+  requestedVersion: "5.3.9104"
+  size: M
+  frontend:
+    prometheusPort: 0
+    containerConfig:
+      frontend:
+        resources:
+          limits:
+            memory: 16Gi
+  gitServer:
+    persistentVolumeConfig:
+      storageSize: 4Ti
+    podTemplateConfig:
+      nodeSelector:
+        pool: gitserver
+    containerSecurityContext:
+      runAsUser: 1000
+  pgsql:
+    database:
+      host: db.example.com
+  worker:
+    jobAllowlist: [codeintel-upload-janitor]
+    extraWorkers:
+      batches:
+        jobAllowlist: [batches-bulk-processor]
+`
+
+func TestMinimize_RoundTrip(t *testing.T) {
+	_, warnings, err := DecodeConfigYAML([]byte(minimizeSpec), DecodeOptions{Strict: true})
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	for _, spec := range []string{"", "spec:\n  size: XL\n", minimizeSpec} {
+		sg, err := NewConfigFromYAML([]byte(spec))
+		require.NoError(t, err)
+
+		minimal, err := Minimize(sg)
+		require.NoError(t, err)
+		merged, err := MergeWithDefaults(minimal)
+		require.NoError(t, err)
+		assert.Equal(t, sg, merged)
+
+		data, err := MarshalMinimalYAML(sg)
+		require.NoError(t, err)
+		decoded, err := NewConfigFromYAML(data)
+		require.NoError(t, err)
+		assert.Equal(t, sg, decoded)
+	}
+}
+
+func TestMinimize_OnlyChangedFields(t *testing.T) {
+	sg, err := NewConfigFromYAML([]byte(minimizeSpec))
+	require.NoError(t, err)
+
+	minimal, err := Minimize(sg)
+	require.NoError(t, err)
+	assert.Equal(t, Sourcegraph{Spec: SourcegraphSpec{
+		RequestedVersion: "5.3.9104",
+		Size:             DeploymentSizeM,
+		Frontend: FrontendSpec{
+			StandardConfig: StandardConfig{
+				PrometheusPort:  pointers.Ptr(0),
+				ContainerConfig: sg.Spec.Frontend.ContainerConfig,
+			},
+		},
+		GitServer: GitServerSpec{
+			StandardConfig: StandardConfig{
+				PersistentVolumeConfig: PersistentVolumeConfig{StorageSize: "4Ti"},
+				PodTemplateConfig:      PodTemplateConfig{NodeSelector: map[string]string{"pool": "gitserver"}},
+				// Security contexts replace the default as a whole.
+				ContainerSecurityContext: sg.Spec.GitServer.ContainerSecurityContext,
+			},
+		},
+		PGSQL: PGSQLSpec{
+			DatabaseConnection: &DatabaseConnectionSpec{Host: "db.example.com"},
+		},
+		Worker: WorkerSpec{
+			JobAllowlist: []string{"codeintel-upload-janitor"},
+			ExtraWorkers: map[string]WorkerSpec{
+				"batches": {JobAllowlist: []string{"batches-bulk-processor"}},
+			},
+		},
+	}}, minimal)
+	assert.Equal(t, resource.MustParse("16Gi"), minimal.Spec.Frontend.ContainerConfig["frontend"].Resources.Limits[corev1.ResourceMemory])
+
+	data, err := MarshalMinimalYAML(sg)
+	require.NoError(t, err)
+	assert.Equal(t, `spec:
+  frontend:
+    containerConfig:
+      frontend:
+        resources:
+          limits:
+            memory: 16Gi
+    prometheusPort: 0
+  gitServer:
+    containerSecurityContext:
+      runAsUser: 1000
+    persistentVolumeConfig:
+      storageSize: 4Ti
+    podTemplateConfig:
+      nodeSelector:
+        pool: gitserver
+  pgsql:
+    database:
+      host: db.example.com
+  requestedVersion: 5.3.9104
+  size: M
+  worker:
+    extraWorkers:
+      batches:
+        jobAllowlist:
+        - batches-bulk-processor
+    jobAllowlist:
+    - codeintel-upload-janitor
+`, string(data))
+}
+
+func TestMinimize_ZeroOverridesDefault(t *testing.T) {
+	sg, err := NewConfigFromYAML([]byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  cadvisor:
+    disabled: false
+  worker:
+    prometheusPort: null
+`))
+	require.NoError(t, err)
+	require.False(t, sg.Spec.Cadvisor.Disabled)
+	require.Nil(t, sg.Spec.Worker.PrometheusPort)
+
+	// MergeWithDefaults can't tell these fields from unset ones.
+	_, err = Minimize(sg)
+	assert.ErrorContains(t, err, "spec.cadvisor.disabled: the zero value can't override the default")
+	assert.ErrorContains(t, err, "spec.worker.prometheusPort: the zero value can't override the default")
+
+	// YAML can.
+	data, err := MarshalMinimalYAML(sg)
+	require.NoError(t, err)
+	assert.Equal(t, `spec:
+  cadvisor:
+    disabled: false
+  requestedVersion: 5.3.9104
+  worker:
+    prometheusPort: null
+`, string(data))
+	decoded, err := NewConfigFromYAML(data)
+	require.NoError(t, err)
+	assert.Equal(t, sg, decoded)
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"github.com/Masterminds/semver"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// OperatorUpdatePolicy is whether the appliance updates itself when another
+// version of Sourcegraph is requested.
+type OperatorUpdatePolicy string
+
+const (
+	// OperatorUpdatePolicyManual leaves the image of the appliance alone. It
+	// is updated like any other Deployment, e.g. with kubectl or Helm.
+	OperatorUpdatePolicyManual OperatorUpdatePolicy = "Manual"
+
+	// OperatorUpdatePolicyAutomatic moves the appliance to its image for the
+	// requested version before rolling out that version, so that the
+	// appliance and Sourcegraph are upgraded in lockstep.
+	OperatorUpdatePolicyAutomatic OperatorUpdatePolicy = "Automatic"
+)
+
+// OperatorComponent is the component of the image of the appliance, which
+// ImageRepositoryPathTemplate is rendered with.
+const OperatorComponent = "appliance"
+
+// OrDefault returns the policy, or OperatorUpdatePolicyManual if it's unset.
+func (p OperatorUpdatePolicy) OrDefault() OperatorUpdatePolicy {
+	if p == "" {
+		return OperatorUpdatePolicyManual
+	}
+	return p
+}
+
+// validateOperatorUpdatePolicy checks the policy of the spec, and that the
+// appliance has an image for the requested version if it updates itself.
+func validateOperatorUpdatePolicy(sg *Sourcegraph) error {
+	switch policy := sg.Spec.OperatorUpdatePolicy; policy {
+	case "", OperatorUpdatePolicyManual:
+		return nil
+	case OperatorUpdatePolicyAutomatic:
+		if _, err := ResolveImageVersion(sg); err != nil {
+			// The unsupported version is reported on its own.
+			return nil
+		}
+		_, _, err := GetOperatorImage(sg)
+		return errors.Wrap(err, "operatorUpdatePolicy")
+	default:
+		return errors.Newf("operatorUpdatePolicy: %q must be one of %q or %q",
+			policy, OperatorUpdatePolicyManual, OperatorUpdatePolicyAutomatic)
+	}
+}
+
+// ErrOperatorDowngrade is returned by ValidateOperatorUpdate when the appliance
+// would move to an image of an older version than the one Sourcegraph runs.
+var ErrOperatorDowngrade = errors.New("the appliance can't be downgraded below the current version")
+
+// GetOperatorImage returns the image of the appliance for the requested
+// version, pulled from ImageRepository like the default images, and the
+// version it belongs to. Like the default images, that's the version that
+// ResolveImageVersion resolves the requested version to. Unlike them, it
+// doesn't depend on the phase of an upgrade: the appliance moves to the
+// requested version before the upgrade starts.
+func GetOperatorImage(sg *Sourcegraph) (version, image string, err error) {
+	version, err = ResolveImageVersion(sg)
+	if err != nil {
+		return "", "", err
+	}
+	operatorImage, ok := operatorImages[version]
+	if !ok {
+		return "", "", errors.Newf("no appliance image found for version %s", version)
+	}
+	ref, err := parseImageReference(operatorImage)
+	if err != nil {
+		return "", "", errors.Wrap(err, "parsing the appliance image")
+	}
+	ref.Name, err = mirrorRepository(sg.Spec, OperatorComponent, ref.Name)
+	if err != nil {
+		return "", "", err
+	}
+	return version, ref.String(), nil
+}
+
+// ValidateOperatorUpdate checks that the appliance may move to its image of
+// version target, while Sourcegraph runs the current version. Newer appliances
+// manage older versions of Sourcegraph during upgrades, but an older
+// appliance doesn't know about the newer version, so target must not be older
+// than current. An empty current version is a new deployment.
+func ValidateOperatorUpdate(current, target string) error {
+	if current == "" {
+		return nil
+	}
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return errors.Wrapf(err, "parsing current version %q", current)
+	}
+	targetVersion, err := semver.NewVersion(target)
+	if err != nil {
+		return errors.Wrapf(err, "parsing appliance version %q", target)
+	}
+	if targetVersion.LessThan(currentVersion) {
+		return errors.Wrapf(ErrOperatorDowngrade, "not moving the appliance to version %s, since Sourcegraph runs version %s", target, current)
+	}
+	return nil
+}
+
+// validateOperatorImages checks the images of the appliance: each belongs to a
+// version with default images, and is a valid default image reference.
+func validateOperatorImages(images map[string]string, defaults map[string]map[string]string) error {
+	var errs error
+	for _, version := range sortedKeys(images) {
+		if _, ok := defaults[version]; !ok {
+			errs = errors.Append(errs, errors.Newf("appliance image of version %s: the version has no default images", version))
+		}
+		if err := validateDefaultImage(images[version]); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "appliance image of version %s", version))
+		}
+	}
+	return errs
+}
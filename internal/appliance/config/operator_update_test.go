@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOperatorImage(t *testing.T) {
+	sg := NewDefaultConfig()
+	sg.Spec.RequestedVersion = "5.3.9104"
+	version, image, err := GetOperatorImage(&sg)
+	require.NoError(t, err)
+	assert.Equal(t, "5.3.9104", version)
+	assert.Equal(t, "index.docker.io/sourcegraph/appliance:5.3.9104", image)
+
+	// The appliance is pulled from the mirror like the default images.
+	sg.Spec.ImageRepository = "registry.example.com/mirror"
+	sg.Spec.ImageRepositoryPathTemplate = "{{.Repository}}/{{.Component}}"
+	_, image, err = GetOperatorImage(&sg)
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/mirror/appliance:5.3.9104", image)
+
+	// Patch builds fall back to the appliance of an earlier patch release.
+	sg.Spec.RequestedVersion = "5.3.9105"
+	sg.Spec.ImageVersionFallback = true
+	version, _, err = GetOperatorImage(&sg)
+	require.NoError(t, err)
+	assert.Equal(t, "5.3.9104", version)
+
+	original := operatorImages
+	t.Cleanup(func() { operatorImages = original })
+	operatorImages = map[string]string{}
+	_, _, err = GetOperatorImage(&sg)
+	require.EqualError(t, err, "no appliance image found for version 5.3.9104")
+}
+
+func TestValidateOperatorUpdate(t *testing.T) {
+	require.NoError(t, ValidateOperatorUpdate("", "5.3.9104"))
+	require.NoError(t, ValidateOperatorUpdate("5.3.9104", "5.3.9104"))
+	require.NoError(t, ValidateOperatorUpdate("5.2.7", "5.3.9104"))
+
+	err := ValidateOperatorUpdate("5.4.0", "5.3.9104")
+	require.ErrorIs(t, err, ErrOperatorDowngrade)
+	require.ErrorContains(t, err, "not moving the appliance to version 5.3.9104, since Sourcegraph runs version 5.4.0")
+}
+
+func TestValidateOperatorImages(t *testing.T) {
+	err := validateOperatorImages(map[string]string{
+		"5.3.9104": "appliance",
+		"5.4.0":    "appliance:5.4.0",
+	}, defaultImages)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `appliance image of version 5.3.9104: image "appliance" has no tag`)
+	assert.Contains(t, err.Error(), "appliance image of version 5.4.0: the version has no default images")
+}
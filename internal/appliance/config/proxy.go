@@ -0,0 +1,44 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// UsesProxy reports whether a service sends outgoing requests through
+// HTTPProxy or HTTPSProxy.
+func (s SourcegraphSpec) UsesProxy(cfg StandardComponent) bool {
+	return (s.HTTPProxy != "" || s.HTTPSProxy != "") && !cfg.GetDisableProxy()
+}
+
+// validateEgress checks the proxy and trusted CA settings.
+func (s SourcegraphSpec) validateEgress() error {
+	var errs error
+	for _, proxy := range []struct{ field, value string }{
+		{"httpProxy", s.HTTPProxy},
+		{"httpsProxy", s.HTTPSProxy},
+	} {
+		if proxy.value == "" {
+			continue
+		}
+		u, err := url.Parse(proxy.value)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+			errs = errors.Append(errs, errors.Newf("%s: %q is not an http, https, or socks5 URL", proxy.field, proxy.value))
+		}
+	}
+	for i, host := range s.NoProxy {
+		if host == "" || strings.ContainsAny(host, ", \t\n") {
+			errs = errors.Append(errs, errors.Newf("noProxy[%d]: %q is not a host, domain, or CIDR", i, host))
+		}
+	}
+	if ref := s.TrustedCACertsConfigMapRef; ref != nil {
+		for _, msg := range validation.IsDNS1123Subdomain(ref.Name) {
+			errs = errors.Append(errs, errors.Newf("trustedCACertsConfigMapRef.name: %q is not a valid name: %s", ref.Name, msg))
+		}
+	}
+	return errs
+}
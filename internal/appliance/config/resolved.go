@@ -0,0 +1,66 @@
+package config
+
+import (
+	"sigs.k8s.io/yaml"
+)
+
+// RedactedValue replaces the secret values of a spec in its resolved config.
+const RedactedValue = "REDACTED"
+
+// ResolvedConfig is what the appliance deploys for a spec: the spec after it
+// was merged over the defaults of its size, and the images that were resolved
+// for it, with secret values redacted. It answers what the appliance actually
+// decided without merging NewDefaultConfig, size presets and overrides by hand.
+type ResolvedConfig struct {
+	Spec SourcegraphSpec `json:"spec"`
+
+	// Images are the images of the containers that the appliance runs, see
+	// ResolveContainerImages.
+	Images map[string]string `json:"images,omitempty"`
+}
+
+// Resolve returns the resolved config of sg.
+func Resolve(sg *Sourcegraph) (ResolvedConfig, error) {
+	images, err := ResolveContainerImages(sg)
+	if err != nil {
+		return ResolvedConfig{}, err
+	}
+	return ResolvedConfig{Spec: sg.Spec.Redacted(), Images: images}, nil
+}
+
+// MarshalResolvedYAML returns the YAML of the resolved config of sg. It is the
+// same for the same spec, so that recording it doesn't churn.
+func MarshalResolvedYAML(sg *Sourcegraph) ([]byte, error) {
+	resolved, err := Resolve(sg)
+	if err != nil {
+		return nil, err
+	}
+	// Map keys are sorted, and struct fields keep their order.
+	return yaml.Marshal(resolved)
+}
+
+// Redacted returns a copy of the spec with the values that the appliance keeps
+// in Secrets replaced by RedactedValue, e.g. database passwords and the inline
+// site configuration. References to Secrets are kept, as they aren't secret.
+func (s SourcegraphSpec) Redacted() SourcegraphSpec {
+	redacted := *s.DeepCopy()
+	redact := func(value *string) {
+		if *value != "" {
+			*value = RedactedValue
+		}
+	}
+	for _, db := range []*DatabaseConnectionSpec{
+		redacted.PGSQL.DatabaseConnection,
+		redacted.CodeIntel.DatabaseConnection,
+		redacted.CodeInsights.DatabaseConnection,
+	} {
+		if db != nil {
+			redact(&db.Password)
+		}
+	}
+	if redacted.SiteConfig != nil {
+		redact(&redacted.SiteConfig.JSON)
+	}
+	redact(&redacted.MaintenancePassword)
+	return redacted
+}
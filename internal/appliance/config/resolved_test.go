@@ -0,0 +1,72 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestResolve(t *testing.T) {
+	sg, err := NewConfigFromYAML([]byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  maintenancePassword: hunter2
+  pgsql:
+    database:
+      host: db.example.com
+      password: pgsql-secret
+  codeIntel:
+    database:
+      password: codeintel-secret
+  siteConfig:
+    json: '{"auth.providers": [{"type": "builtin", "clientSecret": "site-secret"}]}'
+  redisCache:
+    external:
+      endpoint: redis.example.com:6379
+      authSecretRef:
+        name: redis-auth
+        key: password
+  frontend:
+    containerConfig:
+      frontend:
+        image: frontend:custom
+`))
+	require.NoError(t, err)
+
+	resolved, err := Resolve(&sg)
+	require.NoError(t, err)
+
+	// Secret values are redacted, without touching the spec they came from.
+	require.Equal(t, RedactedValue, resolved.Spec.PGSQL.DatabaseConnection.Password)
+	require.Equal(t, "db.example.com", resolved.Spec.PGSQL.DatabaseConnection.Host)
+	require.Equal(t, RedactedValue, resolved.Spec.CodeIntel.DatabaseConnection.Password)
+	require.Equal(t, RedactedValue, resolved.Spec.CodeInsights.DatabaseConnection.Password, "default passwords are redacted too")
+	require.Equal(t, RedactedValue, resolved.Spec.SiteConfig.JSON)
+	require.Equal(t, RedactedValue, resolved.Spec.MaintenancePassword)
+	require.Equal(t, &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"},
+		Key:                  "password",
+	}, resolved.Spec.RedisCache.External.AuthSecretRef)
+	require.Equal(t, "pgsql-secret", sg.Spec.PGSQL.DatabaseConnection.Password)
+
+	// Defaults and overrides are both resolved.
+	require.Equal(t, sg.Spec.GitServer.Replicas, resolved.Spec.GitServer.Replicas)
+	require.Equal(t, "index.docker.io/sourcegraph/frontend:custom", resolved.Images["frontend/frontend"])
+	gitserver, err := GetDefaultImage(&sg, "gitserver")
+	require.NoError(t, err)
+	require.Equal(t, gitserver, resolved.Images["gitServer/gitserver"])
+	require.NotContains(t, resolved.Images, "redisCache/redis-cache")
+
+	data, err := MarshalResolvedYAML(&sg)
+	require.NoError(t, err)
+	for _, secret := range []string{"hunter2", "pgsql-secret", "codeintel-secret", "site-secret"} {
+		require.False(t, strings.Contains(string(data), secret), "%s is not redacted", secret)
+	}
+	for range 5 {
+		again, err := MarshalResolvedYAML(&sg)
+		require.NoError(t, err)
+		require.Equal(t, string(data), string(again))
+	}
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DeploymentSize is a preset of replicas and storage sizes, following the
+// sizes of the Sourcegraph resource estimator.
+type DeploymentSize string
+
+const (
+	DeploymentSizeXS DeploymentSize = "XS"
+	DeploymentSizeS  DeploymentSize = "S"
+	DeploymentSizeM  DeploymentSize = "M"
+	DeploymentSizeL  DeploymentSize = "L"
+	DeploymentSizeXL DeploymentSize = "XL"
+)
+
+// sizePreset holds the defaults that differ between deployment sizes.
+type sizePreset struct {
+	frontendReplicas         int32
+	gitServerReplicas        int32
+	indexedSearchReplicas    int32
+	preciseCodeIntelReplicas int32
+	searcherReplicas         int32
+	symbolsReplicas          int32
+
+	blobstoreStorage     string
+	codeInsightsStorage  string
+	codeIntelStorage     string
+	gitServerStorage     string
+	indexedSearchStorage string
+	pgsqlStorage         string
+	searcherStorage      string
+	symbolsStorage       string
+}
+
+// sizePresets must keep DeploymentSizeS equal to the defaults of
+// NewDefaultConfig, so that choosing it changes nothing.
+var sizePresets = map[DeploymentSize]sizePreset{
+	DeploymentSizeXS: {
+		frontendReplicas:         1,
+		gitServerReplicas:        1,
+		indexedSearchReplicas:    1,
+		preciseCodeIntelReplicas: 1,
+		searcherReplicas:         1,
+		symbolsReplicas:          1,
+		blobstoreStorage:         "50Gi",
+		codeInsightsStorage:      "50Gi",
+		codeIntelStorage:         "50Gi",
+		gitServerStorage:         "50Gi",
+		indexedSearchStorage:     "50Gi",
+		pgsqlStorage:             "50Gi",
+		searcherStorage:          "26Gi",
+		symbolsStorage:           "12Gi",
+	},
+	DeploymentSizeS: {
+		frontendReplicas:         2,
+		gitServerReplicas:        1,
+		indexedSearchReplicas:    1,
+		preciseCodeIntelReplicas: 2,
+		searcherReplicas:         1,
+		symbolsReplicas:          1,
+		blobstoreStorage:         "100Gi",
+		codeInsightsStorage:      "200Gi",
+		codeIntelStorage:         "200Gi",
+		gitServerStorage:         "200Gi",
+		indexedSearchStorage:     "200Gi",
+		pgsqlStorage:             "200Gi",
+		searcherStorage:          "26Gi",
+		symbolsStorage:           "12Gi",
+	},
+	DeploymentSizeM: {
+		frontendReplicas:         2,
+		gitServerReplicas:        2,
+		indexedSearchReplicas:    2,
+		preciseCodeIntelReplicas: 2,
+		searcherReplicas:         2,
+		symbolsReplicas:          2,
+		blobstoreStorage:         "200Gi",
+		codeInsightsStorage:      "200Gi",
+		codeIntelStorage:         "400Gi",
+		gitServerStorage:         "400Gi",
+		indexedSearchStorage:     "400Gi",
+		pgsqlStorage:             "400Gi",
+		searcherStorage:          "52Gi",
+		symbolsStorage:           "24Gi",
+	},
+	DeploymentSizeL: {
+		frontendReplicas:         3,
+		gitServerReplicas:        3,
+		indexedSearchReplicas:    3,
+		preciseCodeIntelReplicas: 3,
+		searcherReplicas:         3,
+		symbolsReplicas:          3,
+		blobstoreStorage:         "400Gi",
+		codeInsightsStorage:      "400Gi",
+		codeIntelStorage:         "800Gi",
+		gitServerStorage:         "800Gi",
+		indexedSearchStorage:     "800Gi",
+		pgsqlStorage:             "800Gi",
+		searcherStorage:          "100Gi",
+		symbolsStorage:           "50Gi",
+	},
+	DeploymentSizeXL: {
+		frontendReplicas:         4,
+		gitServerReplicas:        4,
+		indexedSearchReplicas:    4,
+		preciseCodeIntelReplicas: 4,
+		searcherReplicas:         4,
+		symbolsReplicas:          4,
+		blobstoreStorage:         "800Gi",
+		codeInsightsStorage:      "800Gi",
+		codeIntelStorage:         "1600Gi",
+		gitServerStorage:         "1600Gi",
+		indexedSearchStorage:     "1600Gi",
+		pgsqlStorage:             "1600Gi",
+		searcherStorage:          "200Gi",
+		symbolsStorage:           "100Gi",
+	},
+}
+
+// NewDefaultConfigForSize returns NewDefaultConfig scaled to the given
+// deployment size. The empty size returns NewDefaultConfig unchanged.
+func NewDefaultConfigForSize(size DeploymentSize) (Sourcegraph, error) {
+	sg := NewDefaultConfig()
+	if size == "" {
+		return sg, nil
+	}
+	preset, ok := sizePresets[size]
+	if !ok {
+		return sg, errors.Newf("size must be one of %q, %q, %q, %q, or %q, got %q",
+			DeploymentSizeXS, DeploymentSizeS, DeploymentSizeM, DeploymentSizeL, DeploymentSizeXL, size)
+	}
+
+	spec := &sg.Spec
+	spec.Frontend.Replicas = preset.frontendReplicas
+	spec.GitServer.Replicas = preset.gitServerReplicas
+	spec.IndexedSearch.Replicas = preset.indexedSearchReplicas
+	spec.PreciseCodeIntel.Replicas = preset.preciseCodeIntelReplicas
+	spec.Searcher.Replicas = preset.searcherReplicas
+	spec.Symbols.Replicas = preset.symbolsReplicas
+
+	spec.Blobstore.PersistentVolumeConfig.StorageSize = preset.blobstoreStorage
+	spec.CodeInsights.PersistentVolumeConfig.StorageSize = preset.codeInsightsStorage
+	spec.CodeIntel.PersistentVolumeConfig.StorageSize = preset.codeIntelStorage
+	spec.GitServer.PersistentVolumeConfig.StorageSize = preset.gitServerStorage
+	spec.IndexedSearch.PersistentVolumeConfig.StorageSize = preset.indexedSearchStorage
+	spec.PGSQL.PersistentVolumeConfig.StorageSize = preset.pgsqlStorage
+	spec.Searcher.PersistentVolumeConfig.StorageSize = preset.searcherStorage
+	spec.Symbols.PersistentVolumeConfig.StorageSize = preset.symbolsStorage
+	return sg, nil
+}
+
+// NewConfigFromYAML decodes a spec on top of the defaults for its size, so
+// that anything it sets explicitly takes precedence over the size preset. An
+// unknown size is left for Validate to report. Unknown fields are ignored; see
+// DecodeConfigYAML to find them.
+func NewConfigFromYAML(data []byte) (Sourcegraph, error) {
+	sg, _, err := DecodeConfigYAML(data, DecodeOptions{})
+	return sg, err
+}
+
+func decodeOverSizeDefaults(data []byte) (Sourcegraph, error) {
+	sg := NewDefaultConfig()
+	if err := yaml.Unmarshal(data, &sg); err != nil {
+		return sg, err
+	}
+	if _, ok := sizePresets[sg.Spec.Size]; !ok {
+		return sg, nil
+	}
+
+	sized, err := NewDefaultConfigForSize(sg.Spec.Size)
+	if err != nil {
+		return sg, err
+	}
+	if err := yaml.Unmarshal(data, &sized); err != nil {
+		return sg, err
+	}
+	return sized, nil
+}
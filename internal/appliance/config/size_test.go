@@ -0,0 +1,143 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+// TestNewDefaultConfigForSize_Golden renders the defaults of every size, so
+// that changes to the presets are reviewed deliberately. To regenerate the
+// golden files, run:
+//
+//	go test -run TestNewDefaultConfigForSize_Golden -args appliance-update-golden-files
+func TestNewDefaultConfigForSize_Golden(t *testing.T) {
+	for _, size := range []DeploymentSize{DeploymentSizeXS, DeploymentSizeS, DeploymentSizeM, DeploymentSizeL, DeploymentSizeXL} {
+		t.Run(string(size), func(t *testing.T) {
+			sg, err := NewDefaultConfigForSize(size)
+			require.NoError(t, err)
+			obtained, err := yaml.Marshal(sg)
+			require.NoError(t, err)
+
+			goldenFilePath := filepath.Join("testdata", "size-presets", string(size)+".yaml")
+			if len(os.Args) > 0 && os.Args[len(os.Args)-1] == "appliance-update-golden-files" {
+				require.NoError(t, os.MkdirAll(filepath.Dir(goldenFilePath), 0700))
+				require.NoError(t, os.WriteFile(goldenFilePath, obtained, 0600))
+			}
+
+			golden, err := os.ReadFile(goldenFilePath)
+			require.NoError(t, err)
+			assert.Equal(t, string(golden), string(obtained))
+		})
+	}
+}
+
+func TestNewDefaultConfigForSize_SMatchesDefaults(t *testing.T) {
+	sg, err := NewDefaultConfigForSize(DeploymentSizeS)
+	require.NoError(t, err)
+	assert.Equal(t, NewDefaultConfig(), sg)
+}
+
+func TestNewDefaultConfigForSize_Unknown(t *testing.T) {
+	_, err := NewDefaultConfigForSize("XXL")
+	assert.ErrorContains(t, err, `size must be one of "XS", "S", "M", "L", or "XL", got "XXL"`)
+}
+
+func TestNewConfigFromYAML_ExplicitValuesOverrideSize(t *testing.T) {
+	sg, err := NewConfigFromYAML([]byte(`
+spec:
+  size: L
+  gitServer:
+    replicas: 5
+  pgsql:
+    persistentVolumeConfig:
+      storageSize: 300Gi
+`))
+	require.NoError(t, err)
+
+	want, err := NewDefaultConfigForSize(DeploymentSizeL)
+	require.NoError(t, err)
+	want.Spec.Size = DeploymentSizeL
+	want.Spec.GitServer.Replicas = 5
+	want.Spec.PGSQL.PersistentVolumeConfig.StorageSize = "300Gi"
+	assert.Equal(t, want, sg)
+}
+
+func TestNewConfigFromYAML_NoSize(t *testing.T) {
+	sg, err := NewConfigFromYAML([]byte("spec:\n  frontend:\n    replicas: 3\n"))
+	require.NoError(t, err)
+
+	want := NewDefaultConfig()
+	want.Spec.Frontend.Replicas = 3
+	assert.Equal(t, want, sg)
+}
+
+func TestSourcegraphValidate_Size(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "autoscaling with the size's replicas",
+			yaml: `
+spec:
+  requestedVersion: "5.3.9104"
+  size: XL
+  searcher:
+    autoscaling:
+      maxReplicas: 8
+`,
+		},
+		{
+			name: "autoscaling with explicit replicas",
+			yaml: `
+spec:
+  requestedVersion: "5.3.9104"
+  size: XL
+  searcher:
+    replicas: 2
+    autoscaling:
+      maxReplicas: 8
+`,
+			wantErr: "spec.searcher: replicas and autoscaling are mutually exclusive",
+		},
+		{
+			name: "external blobstore with the size's storage size",
+			yaml: `
+spec:
+  requestedVersion: "5.3.9104"
+  size: M
+  blobstore:
+    externalStorage:
+      backend: GCS
+      bucket: uploads
+`,
+		},
+		{
+			name: "unknown size",
+			yaml: `
+spec:
+  requestedVersion: "5.3.9104"
+  size: huge
+`,
+			wantErr: `spec: size must be one of "XS", "S", "M", "L", or "XL", got "huge"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sg, err := NewConfigFromYAML([]byte(tc.yaml))
+			require.NoError(t, err)
+
+			err = sg.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func TestImagePullSecretsFor_AppendsServiceSecretsToGlobal(t *testing.T) {
+	spec := SourcegraphSpec{
+		ImagePullSecrets: []string{"registry-creds", "mirror-creds"},
+		Worker: WorkerSpec{
+			StandardConfig: StandardConfig{
+				ImagePullSecrets: []string{"worker-creds", "registry-creds"},
+				PodTemplateConfig: PodTemplateConfig{
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "worker-creds"}, {Name: "legacy-creds"}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []corev1.LocalObjectReference{
+		{Name: "registry-creds"},
+		{Name: "mirror-creds"},
+		{Name: "worker-creds"},
+		{Name: "legacy-creds"},
+	}, spec.ImagePullSecretsFor(spec.Worker))
+	assert.Equal(t, []corev1.LocalObjectReference{
+		{Name: "registry-creds"},
+		{Name: "mirror-creds"},
+	}, spec.ImagePullSecretsFor(spec.Frontend))
+}
+
+func TestImagePullSecretsFor_NilWhenUnset(t *testing.T) {
+	spec := NewDefaultConfig().Spec
+	assert.Nil(t, spec.ImagePullSecretsFor(spec.Frontend))
+}
+
+func TestPriorityClassNameFor(t *testing.T) {
+	spec := NewDefaultConfig().Spec
+	assert.Equal(t, "", spec.PriorityClassNameFor(spec.GitServer, "sg"))
+
+	spec.PriorityClassName = "high"
+	spec.Worker.PriorityClassName = pointers.Ptr("low")
+	assert.Equal(t, "high", spec.PriorityClassNameFor(spec.GitServer, "sg"))
+	assert.Equal(t, "low", spec.PriorityClassNameFor(spec.Worker, "sg"))
+
+	spec.ManagePriorityClasses = true
+	spec.Frontend.PriorityClassName = pointers.Ptr("")
+	assert.Equal(t, "sg-sourcegraph-critical", spec.PriorityClassNameFor(spec.GitServer, "sg"))
+	assert.Equal(t, "sg-sourcegraph-critical", spec.PriorityClassNameFor(spec.PGSQL, "sg"))
+	assert.Equal(t, "sg-sourcegraph-critical", spec.PriorityClassNameFor(spec.CodeIntel, "sg"))
+	assert.Equal(t, "sg-sourcegraph-critical", spec.PriorityClassNameFor(spec.RedisStore, "sg"))
+	assert.Equal(t, "sg-sourcegraph-standard", spec.PriorityClassNameFor(spec.Searcher, "sg"))
+	assert.Equal(t, "low", spec.PriorityClassNameFor(spec.Worker, "sg"))
+	assert.Equal(t, "", spec.PriorityClassNameFor(spec.Frontend, "sg"))
+
+	spec.NamePrefix = "staging"
+	assert.Equal(t, "sg-staging-sourcegraph-critical", spec.PriorityClassNameFor(spec.GitServer, "sg"))
+}
+
+func TestObjectName(t *testing.T) {
+	spec := NewDefaultConfig().Spec
+	assert.Equal(t, "pgsql", spec.ObjectName("pgsql"))
+	assert.Equal(t, "pgsql", spec.ServiceHost("pgsql", "pgsql"))
+
+	spec.NamePrefix = "staging"
+	assert.Equal(t, "staging-pgsql", spec.ObjectName("pgsql"))
+	assert.Equal(t, "staging-pgsql", spec.ServiceHost("pgsql", "pgsql"))
+	assert.Equal(t, "db.example.com", spec.ServiceHost("db.example.com", "pgsql"))
+}
+
+func TestSymbolsSpecGetCacheSizeMB(t *testing.T) {
+	symbols := NewDefaultConfig().Spec.Symbols
+	cacheSizeMB, err := symbols.GetCacheSizeMB()
+	require.NoError(t, err)
+	assert.Equal(t, 11059, cacheSizeMB)
+
+	// The default follows the size of the persistent volume.
+	symbols.PersistentVolumeConfig.StorageSize = "100Gi"
+	cacheSizeMB, err = symbols.GetCacheSizeMB()
+	require.NoError(t, err)
+	assert.Equal(t, 92160, cacheSizeMB)
+
+	// Unless the cache size is pinned.
+	symbols.CacheSizeMB = pointers.Ptr(50000)
+	cacheSizeMB, err = symbols.GetCacheSizeMB()
+	require.NoError(t, err)
+	assert.Equal(t, 50000, cacheSizeMB)
+}
+
+func TestBlobstoreRetentionConfigGetTTLs(t *testing.T) {
+	retention := &BlobstoreRetentionConfig{TTLs: map[string]string{"search-jobs": "24h", "lsif-uploads/tmp": "1h"}}
+	assert.Equal(t, map[string]string{
+		"batch-changes":    "720h",
+		"lsif-uploads":     "168h",
+		"lsif-uploads/tmp": "1h",
+		"search-jobs":      "24h",
+	}, retention.GetTTLs())
+	assert.Equal(t, int32(90), retention.GetHighWaterMarkPercent())
+	assert.Equal(t, int32(85), retention.GetWarningThresholdPercent())
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types of SourcegraphStatus and ServiceStatus.
+const (
+	// ConditionReady is true once every service has been reconciled and has
+	// rolled out.
+	ConditionReady = "Ready"
+
+	// ConditionReconciled is true if the appliance last managed to create,
+	// update, or delete all objects of a service.
+	ConditionReconciled = "Reconciled"
+
+	// ConditionAvailable is true if all workloads of a service have rolled
+	// out, and all their pods are available. Services without workloads,
+	// e.g. disabled ones, lack this condition.
+	ConditionAvailable = "Available"
+
+	// ConditionSchedulable is false if a workload of a service requests more
+	// replicas than there are nodes that its pods can be scheduled on. It is
+	// only a warning, and doesn't affect the Ready condition, since several
+	// replicas may share a node. It is only set if
+	// SourcegraphSpec.CheckSchedulableNodes is, and only for services
+	// running more than one replica.
+	ConditionSchedulable = "Schedulable"
+
+	// ConditionShardsStable is false while gitserver changes its number of
+	// replicas, which redistributes repositories across them, or while a
+	// reduction is blocked by GitServerSpec.AllowScaleDown. Like
+	// ConditionSchedulable, it is only a warning.
+	ConditionShardsStable = "ShardsStable"
+
+	// ConditionDiskSpaceAvailable is false if the volume of the blobstore is
+	// fuller than BlobstoreRetentionConfig.WarningThresholdPercent. Like
+	// ConditionSchedulable, it is only a warning. It is only set if the
+	// bundled blobstore has a retention config, whose sidecar reports the
+	// usage of the volume.
+	ConditionDiskSpaceAvailable = "DiskSpaceAvailable"
+
+	// ConditionInSync lists the fields of the objects of a service that were
+	// changed by hand, rather than by the appliance. It is only false if
+	// SourcegraphSpec.DriftPolicy is Report, since the drift is kept then.
+	// Like ConditionSchedulable, it is only a warning.
+	ConditionInSync = "InSync"
+
+	// ConditionSpecFieldsRecognized is false if the spec sets fields that are
+	// unknown, e.g. because of a typo, or deprecated. Unknown fields are
+	// ignored unless the appliance decodes specs strictly, in which case
+	// they make the spec invalid instead.
+	ConditionSpecFieldsRecognized = "SpecFieldsRecognized"
+
+	// ConditionSiteConfigValid is false if the site configuration of
+	// SourcegraphSpec.SiteConfig doesn't match the site configuration
+	// schema, or can't be read, in which case the frontend isn't rolled out.
+	// It is only set on the frontend, if the spec has a site configuration.
+	ConditionSiteConfigValid = "SiteConfigValid"
+
+	// ConditionChangesApplied is false if the spec changes fields of an
+	// object of a service that can't be updated once the object exists, e.g.
+	// the storage class of a PersistentVolumeClaim. The object keeps running
+	// as it is, and the service isn't ready, until the change is reverted or
+	// the object is replaced: by the appliance for StatefulSets, if
+	// SourcegraphSpec.AllowStatefulSetRecreate is set, by hand otherwise. It
+	// is only set while a change is pending.
+	ConditionChangesApplied = "ChangesApplied"
+)
+
+// Condition reasons of SourcegraphStatus and ServiceStatus.
+const (
+	ReasonInvalidSpec       = "InvalidSpec"
+	ReasonServicesReady     = "ServicesReady"
+	ReasonServicesNotReady  = "ServicesNotReady"
+	ReasonUpgradeInProgress = "UpgradeInProgress"
+
+	ReasonOperatorUpdateInProgress = "OperatorUpdateInProgress"
+
+	ReasonReconcileSucceeded = "ReconcileSucceeded"
+	ReasonReconcileFailed    = "ReconcileFailed"
+
+	ReasonRolloutComplete   = "RolloutComplete"
+	ReasonRolloutInProgress = "RolloutInProgress"
+
+	ReasonEnoughNodes       = "EnoughNodes"
+	ReasonInsufficientNodes = "InsufficientNodes"
+	ReasonNodesUnknown      = "NodesUnknown"
+
+	ReasonShardsUnchanged  = "ShardsUnchanged"
+	ReasonReplicasChanged  = "ReplicasChanged"
+	ReasonScaleDownBlocked = "ScaleDownBlocked"
+
+	ReasonDiskUsageNormal  = "DiskUsageNormal"
+	ReasonDiskUsageHigh    = "DiskUsageHigh"
+	ReasonDiskUsageUnknown = "DiskUsageUnknown"
+
+	ReasonNoDrift       = "NoDrift"
+	ReasonDriftReverted = "DriftReverted"
+	ReasonDriftIgnored  = "DriftIgnored"
+	ReasonDriftDetected = "DriftDetected"
+
+	ReasonFieldsRecognized = "FieldsRecognized"
+	ReasonUnknownFields    = "UnknownFields"
+	ReasonDeprecatedFields = "DeprecatedFields"
+
+	ReasonSiteConfigValid   = "SiteConfigValid"
+	ReasonSiteConfigInvalid = "SiteConfigInvalid"
+
+	ReasonImmutableFieldsChanged = "ImmutableFieldsChanged"
+)
+
+// ServiceStatus is the observed state of a service.
+type ServiceStatus struct {
+	// Name is the name of the service, e.g. gitserver.
+	Name string `json:"name"`
+
+	// Conditions are the Reconciled, Available, Schedulable, ShardsStable,
+	// DiskSpaceAvailable, SiteConfigValid, InSync, and ChangesApplied
+	// conditions of the service.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Service returns the status of the service called name, adding it if it's
+// missing.
+func (s *SourcegraphStatus) Service(name string) *ServiceStatus {
+	for i := range s.Services {
+		if s.Services[i].Name == name {
+			return &s.Services[i]
+		}
+	}
+	s.Services = append(s.Services, ServiceStatus{Name: name})
+	return &s.Services[len(s.Services)-1]
+}
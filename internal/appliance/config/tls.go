@@ -0,0 +1,76 @@
+package config
+
+import "sort"
+
+// TLSSecretName returns the name of the certificate Secret that a service
+// should serve HTTPS with, or the empty string if it serves plain HTTP.
+func (s SourcegraphSpec) TLSSecretName(cfg StandardComponent) string {
+	if tls := cfg.GetTLSConfig(); tls != nil && tls.SecretName != "" {
+		return tls.SecretName
+	}
+	if s.GlobalTLSSecretRef != nil {
+		return s.GlobalTLSSecretRef.Name
+	}
+	return ""
+}
+
+// TLSSecretsByService returns the certificate Secret name of each enabled
+// service that serves HTTPS, keyed by the service's spec field name. It is
+// nil when no service serves HTTPS.
+func (s SourcegraphSpec) TLSSecretsByService() map[string]string {
+	var secrets map[string]string
+	for name, cfg := range s.standardComponents() {
+		if cfg.IsDisabled() {
+			continue
+		}
+		if secret := s.TLSSecretName(cfg); secret != "" {
+			if secrets == nil {
+				secrets = map[string]string{}
+			}
+			secrets[name] = secret
+		}
+	}
+	return secrets
+}
+
+// TLSSecretNames returns the sorted, de-duplicated names of all certificate
+// Secrets in use by enabled services.
+func (s SourcegraphSpec) TLSSecretNames() []string {
+	seen := map[string]struct{}{}
+	var names []string
+	for _, secret := range s.TLSSecretsByService() {
+		if _, ok := seen[secret]; ok {
+			continue
+		}
+		seen[secret] = struct{}{}
+		names = append(names, secret)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s SourcegraphSpec) standardComponents() map[string]StandardComponent {
+	return map[string]StandardComponent{
+		"blobstore":        s.Blobstore,
+		"cadvisor":         s.Cadvisor,
+		"codeInsights":     s.CodeInsights,
+		"codeIntel":        s.CodeIntel,
+		"embeddings":       s.Embeddings,
+		"executors":        s.Executors,
+		"frontend":         s.Frontend,
+		"gitServer":        s.GitServer,
+		"grafana":          s.Grafana,
+		"indexedSearch":    s.IndexedSearch,
+		"otelCollector":    s.OtelCollector,
+		"pgsql":            s.PGSQL,
+		"preciseCodeIntel": s.PreciseCodeIntel,
+		"prometheus":       s.Prometheus,
+		"redisCache":       s.RedisCache,
+		"redisStore":       s.RedisStore,
+		"repoUpdater":      s.RepoUpdater,
+		"searcher":         s.Searcher,
+		"symbols":          s.Symbols,
+		"syntectServer":    s.SyntectServer,
+		"worker":           s.Worker,
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTLSSecretName_PerServiceOverridesGlobal(t *testing.T) {
+	spec := SourcegraphSpec{
+		GlobalTLSSecretRef: &corev1.LocalObjectReference{Name: "global-tls"},
+		Blobstore: BlobstoreSpec{
+			StandardConfig: StandardConfig{TLS: &TLSConfig{SecretName: "blobstore-tls"}},
+		},
+	}
+
+	assert.Equal(t, "blobstore-tls", spec.TLSSecretName(spec.Blobstore))
+	assert.Equal(t, "global-tls", spec.TLSSecretName(spec.Frontend))
+}
+
+func TestTLSSecretsByService_OnlyEnabledServicesWithTLS(t *testing.T) {
+	spec := SourcegraphSpec{
+		Blobstore: BlobstoreSpec{
+			StandardConfig: StandardConfig{TLS: &TLSConfig{SecretName: "blobstore-tls"}},
+		},
+		Prometheus: PrometheusSpec{
+			StandardConfig: StandardConfig{TLS: &TLSConfig{SecretName: "prometheus-tls"}},
+		},
+		Worker: WorkerSpec{
+			StandardConfig: StandardConfig{Disabled: true, TLS: &TLSConfig{SecretName: "worker-tls"}},
+		},
+		Grafana: GrafanaSpec{
+			StandardConfig: StandardConfig{TLS: &TLSConfig{SecretName: "blobstore-tls"}},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"blobstore":  "blobstore-tls",
+		"grafana":    "blobstore-tls",
+		"prometheus": "prometheus-tls",
+	}, spec.TLSSecretsByService())
+	assert.Equal(t, []string{"blobstore-tls", "prometheus-tls"}, spec.TLSSecretNames())
+}
+
+func TestTLSSecretsByService_NilWithoutTLS(t *testing.T) {
+	assert.Nil(t, NewDefaultConfig().Spec.TLSSecretsByService())
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// validateTopologySpreadConstraints checks the fields of topology spread
+// constraints that the Kubernetes API server would reject.
+func validateTopologySpreadConstraints(constraints []corev1.TopologySpreadConstraint) error {
+	var errs error
+	for i, c := range constraints {
+		if c.MaxSkew < 1 {
+			errs = errors.Append(errs, errors.Newf("topologySpreadConstraints[%d].maxSkew: must be at least 1, got %d", i, c.MaxSkew))
+		}
+		for _, msg := range validation.IsQualifiedName(c.TopologyKey) {
+			errs = errors.Append(errs, errors.Newf("topologySpreadConstraints[%d].topologyKey: %q is not a valid label key: %s", i, c.TopologyKey, msg))
+		}
+		switch c.WhenUnsatisfiable {
+		case corev1.DoNotSchedule, corev1.ScheduleAnyway:
+		default:
+			errs = errors.Append(errs, errors.Newf("topologySpreadConstraints[%d].whenUnsatisfiable: %q must be %q or %q",
+				i, c.WhenUnsatisfiable, corev1.DoNotSchedule, corev1.ScheduleAnyway))
+		}
+	}
+	return errs
+}
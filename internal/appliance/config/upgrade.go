@@ -0,0 +1,162 @@
+package config
+
+import (
+	"github.com/Masterminds/semver"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ErrDowngrade is returned by ValidateUpgradePolicy when the requested version
+// is older than the current one.
+var ErrDowngrade = errors.New("downgrades are not supported")
+
+// ValidateUpgradePolicy checks that moving from the current version to the
+// requested one follows the documented upgrade policy: patch upgrades, and
+// upgrades of one minor version at a time, where the first minor version of
+// the next major version follows the last minor version of the previous one.
+// An empty current version is a new deployment, which can start at any
+// version.
+//
+// See https://sourcegraph.com/docs/admin/updates
+func ValidateUpgradePolicy(current, requested string) error {
+	if current == "" {
+		return nil
+	}
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return errors.Wrapf(err, "parsing current version %q", current)
+	}
+	requestedVersion, err := semver.NewVersion(requested)
+	if err != nil {
+		return errors.Wrapf(err, "parsing requested version %q", requested)
+	}
+
+	switch {
+	case requestedVersion.LessThan(currentVersion):
+		return errors.Wrapf(ErrDowngrade, "cannot go from %s back to %s: restore a backup taken before the upgrade, or set allowDowngrade if the database schema is known to be compatible", current, requested)
+	case requestedVersion.Major() == currentVersion.Major():
+		if requestedVersion.Minor() > currentVersion.Minor()+1 {
+			return errors.Newf("cannot upgrade from %s to %s, as upgrades must go one minor version at a time: upgrade to %d.%d first",
+				current, requested, currentVersion.Major(), currentVersion.Minor()+1)
+		}
+	case requestedVersion.Major() == currentVersion.Major()+1 && requestedVersion.Minor() == 0:
+	default:
+		return errors.Newf("cannot upgrade from %s to %s, as upgrades must go one minor version at a time: upgrade to the last %d.x version, then to %d.0",
+			current, requested, currentVersion.Major(), currentVersion.Major()+1)
+	}
+	return nil
+}
+
+// ValidateUpgrade checks the upgrade from the last successfully deployed
+// version to the requested one against ValidateUpgradePolicy, unless the spec
+// opts out of it, and checks that every phase of the upgrade has images.
+func (sg *Sourcegraph) ValidateUpgrade() error {
+	for phase, upgradePhase := range sg.UpgradePhases() {
+		if _, err := ResolveImagesForPhase(sg, phase); err != nil {
+			return errors.Wrapf(err, "spec.requestedVersion: phase %q of the upgrade from %s", upgradePhase.Name, sg.Status.CurrentVersion)
+		}
+	}
+	if sg.Spec.SkipUpgradeValidation {
+		return nil
+	}
+	err := ValidateUpgradePolicy(sg.Status.CurrentVersion, sg.Spec.RequestedVersion)
+	if sg.Spec.AllowDowngrade && errors.Is(err, ErrDowngrade) {
+		return nil
+	}
+	return errors.Wrap(err, "spec.requestedVersion")
+}
+
+// UpgradePhase is a step of an upgrade that can't roll out every component at
+// once, e.g. because the databases must be upgraded before the services that
+// use them, or because a component must briefly run a transitional image.
+type UpgradePhase struct {
+	// Name describes the phase, e.g. "databases".
+	Name string `json:"name"`
+
+	// Components maps the components that the phase rolls out to the version
+	// whose default image they run from then on, until a later phase rolls
+	// them out again. Components that no phase has rolled out yet keep
+	// running the images of the current version.
+	Components map[string]string `json:"components"`
+}
+
+// UpgradeStatus is the progress of an upgrade that rolls out in phases.
+type UpgradeStatus struct {
+	// Version is the version being upgraded to. The upgrade starts over if
+	// another version is requested.
+	Version string `json:"version"`
+
+	// Phase is the index of the phase of UpgradePhases that is rolling out.
+	// It is the number of phases once they have all rolled out, and every
+	// component is being rolled out to the requested version.
+	Phase int `json:"phase"`
+}
+
+// UpgradePhases returns the phases of the upgrade from the current version to
+// the requested one, in order, or nil if every component is rolled out at
+// once. That's the case for new deployments, patch upgrades, and upgrades to
+// versions without phases.
+func (sg *Sourcegraph) UpgradePhases() []UpgradePhase {
+	if sg.Status.CurrentVersion == "" {
+		return nil
+	}
+	current, err := semver.NewVersion(sg.Status.CurrentVersion)
+	if err != nil {
+		return nil
+	}
+	requested, err := semver.NewVersion(sg.Spec.RequestedVersion)
+	if err != nil || !current.LessThan(requested) ||
+		(current.Major() == requested.Major() && current.Minor() == requested.Minor()) {
+		return nil
+	}
+	imageVersion, err := ResolveImageVersion(sg)
+	if err != nil {
+		return nil
+	}
+	return upgradePhases[imageVersion]
+}
+
+// ResolveImagesForPhase returns the image reference of every component while
+// the given phase of the upgrade to the requested version rolls out, keyed by
+// component, like ResolveAllImages. Phase len(sg.UpgradePhases()) is the end
+// of the upgrade, when every component runs the images of the requested
+// version.
+func ResolveImagesForPhase(sg *Sourcegraph, phase int) (map[string]string, error) {
+	if phases := sg.UpgradePhases(); phase < 0 || phase > len(phases) {
+		return nil, errors.Newf("the upgrade from %s to %s has no phase %d", sg.Status.CurrentVersion, sg.Spec.RequestedVersion, phase)
+	}
+	phased := *sg
+	phased.Status.Upgrade = &UpgradeStatus{Version: sg.Spec.RequestedVersion, Phase: phase}
+	return ResolveAllImages(&phased)
+}
+
+// componentImageVersion returns the version whose default image a component
+// runs: the version that the last phase of the upgrade in progress rolled it
+// out to, or the requested version if no upgrade is in progress.
+func componentImageVersion(sg *Sourcegraph, component string) (string, error) {
+	version, err := ResolveImageVersion(sg)
+	if err != nil {
+		return "", err
+	}
+	upgrade := sg.Status.Upgrade
+	phases := sg.UpgradePhases()
+	if upgrade == nil || upgrade.Version != sg.Spec.RequestedVersion || upgrade.Phase >= len(phases) {
+		return version, nil
+	}
+	for i := upgrade.Phase; i >= 0; i-- {
+		if phaseVersion, ok := phases[i].Components[component]; ok {
+			return phaseVersion, nil
+		}
+	}
+
+	// Components that are new in the requested version have no image in the
+	// current one, and are rolled out to it straight away.
+	currentVersion, err := resolveImageVersion(sg.Status.CurrentVersion, sg.Spec.ImageVersionFallback)
+	if err != nil {
+		return "", errors.Wrapf(err, "resolving the images of the current version %s", sg.Status.CurrentVersion)
+	}
+	if _, ok := defaultImages[currentVersion][component]; !ok {
+		return version, nil
+	}
+	return currentVersion, nil
+}
@@ -0,0 +1,305 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUpgradePolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		current   string
+		requested string
+		wantErr   string
+	}{
+		{
+			name:      "new deployment",
+			current:   "",
+			requested: "5.3.9104",
+		},
+		{
+			name:      "same version",
+			current:   "5.3.9104",
+			requested: "5.3.9104",
+		},
+		{
+			name:      "patch upgrade",
+			current:   "5.3.2",
+			requested: "5.3.9104",
+		},
+		{
+			name:      "single minor upgrade",
+			current:   "5.2.7",
+			requested: "5.3.9104",
+		},
+		{
+			name:      "single minor upgrade to an older patch",
+			current:   "5.2.7",
+			requested: "5.3.0",
+		},
+		{
+			name:      "multi-minor upgrade",
+			current:   "5.2.7",
+			requested: "5.4.0",
+			wantErr:   "cannot upgrade from 5.2.7 to 5.4.0, as upgrades must go one minor version at a time: upgrade to 5.3 first",
+		},
+		{
+			name:      "major upgrade to the first minor version",
+			current:   "5.11.1",
+			requested: "6.0.0",
+		},
+		{
+			name:      "major upgrade past the first minor version",
+			current:   "5.11.1",
+			requested: "6.1.0",
+			wantErr:   "cannot upgrade from 5.11.1 to 6.1.0, as upgrades must go one minor version at a time: upgrade to the last 5.x version, then to 6.0",
+		},
+		{
+			name:      "multi-major upgrade",
+			current:   "4.5.1",
+			requested: "6.0.0",
+			wantErr:   "upgrade to the last 4.x version, then to 5.0",
+		},
+		{
+			name:      "patch downgrade",
+			current:   "5.3.9104",
+			requested: "5.3.2",
+			wantErr:   "cannot go from 5.3.9104 back to 5.3.2",
+		},
+		{
+			name:      "minor downgrade",
+			current:   "5.3.9104",
+			requested: "5.2.7",
+			wantErr:   "downgrades are not supported",
+		},
+		{
+			name:      "major downgrade",
+			current:   "6.0.0",
+			requested: "5.11.1",
+			wantErr:   "downgrades are not supported",
+		},
+		{
+			name:      "malformed requested version",
+			current:   "5.3.9104",
+			requested: "latest",
+			wantErr:   `parsing requested version "latest"`,
+		},
+		{
+			name:      "empty requested version",
+			current:   "5.3.9104",
+			requested: "",
+			wantErr:   `parsing requested version ""`,
+		},
+		{
+			name:      "malformed current version",
+			current:   "five.three",
+			requested: "5.3.9104",
+			wantErr:   `parsing current version "five.three"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateUpgradePolicy(tc.current, tc.requested)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSourcegraphValidateUpgrade(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		mutate  func(sg *Sourcegraph)
+		wantErr string
+	}{
+		{
+			name: "supported upgrade",
+			mutate: func(sg *Sourcegraph) {
+				sg.Status.CurrentVersion = "5.2.7"
+			},
+		},
+		{
+			name: "multi-minor upgrade",
+			mutate: func(sg *Sourcegraph) {
+				sg.Status.CurrentVersion = "5.1.0"
+			},
+			wantErr: "spec.requestedVersion: cannot upgrade from 5.1.0 to 5.3.9104",
+		},
+		{
+			name: "multi-minor upgrade with validation skipped",
+			mutate: func(sg *Sourcegraph) {
+				sg.Status.CurrentVersion = "5.1.0"
+				sg.Spec.SkipUpgradeValidation = true
+			},
+		},
+		{
+			name: "downgrade",
+			mutate: func(sg *Sourcegraph) {
+				sg.Status.CurrentVersion = "5.4.0"
+			},
+			wantErr: "spec.requestedVersion: cannot go from 5.4.0 back to 5.3.9104",
+		},
+		{
+			name: "allowed downgrade",
+			mutate: func(sg *Sourcegraph) {
+				sg.Status.CurrentVersion = "5.4.0"
+				sg.Spec.AllowDowngrade = true
+			},
+		},
+		{
+			name: "allowing downgrades doesn't allow multi-minor upgrades",
+			mutate: func(sg *Sourcegraph) {
+				sg.Status.CurrentVersion = "5.1.0"
+				sg.Spec.AllowDowngrade = true
+			},
+			wantErr: "cannot upgrade from 5.1.0 to 5.3.9104",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sg := NewDefaultConfig()
+			sg.Spec.RequestedVersion = "5.3.9104"
+			tc.mutate(&sg)
+
+			err := sg.ValidateUpgrade()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+// setUpgradePhases replaces the default images and upgrade phases with those
+// of a two-phase upgrade from 5.3.2 to 5.4.0: the database first, then the
+// worker runs a transitional image, e.g. to migrate data, before everything
+// else is upgraded.
+func setUpgradePhases(t *testing.T) {
+	t.Helper()
+	originalImages, originalPhases := defaultImages, upgradePhases
+	t.Cleanup(func() { defaultImages, upgradePhases = originalImages, originalPhases })
+	defaultImages = map[string]map[string]string{
+		"5.3.2": {
+			"frontend": "frontend:5.3.2",
+			"pgsql":    "postgres-12-alpine:5.3.2",
+			"worker":   "worker:5.3.2",
+		},
+		"5.4.0": {
+			"blobstore": "blobstore:5.4.0",
+			"frontend":  "frontend:5.4.0",
+			"pgsql":     "postgres-16-alpine:5.4.0",
+			"worker":    "worker:5.4.0",
+		},
+		"5.4.0-transitional": {
+			"worker": "worker:5.4.0-transitional",
+		},
+	}
+	upgradePhases = map[string][]UpgradePhase{
+		"5.4.0": {
+			{Name: "databases", Components: map[string]string{"pgsql": "5.4.0"}},
+			{Name: "data migrations", Components: map[string]string{"worker": "5.4.0-transitional"}},
+		},
+	}
+}
+
+func TestResolveImagesForPhase(t *testing.T) {
+	setUpgradePhases(t)
+	sg := NewDefaultConfig()
+	sg.Spec.ImageRepository = "registry.example.com"
+	sg.Spec.RequestedVersion = "5.4.0"
+	sg.Status.CurrentVersion = "5.3.2"
+	require.Len(t, sg.UpgradePhases(), 2)
+
+	for _, tc := range []struct {
+		phase int
+		want  map[string]string
+	}{
+		{
+			phase: 0,
+			want: map[string]string{
+				"blobstore": "registry.example.com/blobstore:5.4.0",
+				"frontend":  "registry.example.com/frontend:5.3.2",
+				"pgsql":     "registry.example.com/postgres-16-alpine:5.4.0",
+				"worker":    "registry.example.com/worker:5.3.2",
+			},
+		},
+		{
+			phase: 1,
+			want: map[string]string{
+				"blobstore": "registry.example.com/blobstore:5.4.0",
+				"frontend":  "registry.example.com/frontend:5.3.2",
+				"pgsql":     "registry.example.com/postgres-16-alpine:5.4.0",
+				"worker":    "registry.example.com/worker:5.4.0-transitional",
+			},
+		},
+		{
+			phase: 2,
+			want: map[string]string{
+				"blobstore": "registry.example.com/blobstore:5.4.0",
+				"frontend":  "registry.example.com/frontend:5.4.0",
+				"pgsql":     "registry.example.com/postgres-16-alpine:5.4.0",
+				"worker":    "registry.example.com/worker:5.4.0",
+			},
+		},
+	} {
+		images, err := ResolveImagesForPhase(&sg, tc.phase)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, images, "phase %d", tc.phase)
+	}
+	_, err := ResolveImagesForPhase(&sg, 3)
+	assert.ErrorContains(t, err, "the upgrade from 5.3.2 to 5.4.0 has no phase 3")
+
+	// GetDefaultImage resolves the images of the phase that is rolling out,
+	// and those of the requested version once every phase has.
+	sg.Status.Upgrade = &UpgradeStatus{Version: "5.4.0", Phase: 1}
+	image, err := GetDefaultImage(&sg, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/worker:5.4.0-transitional", image)
+	sg.Status.Upgrade.Phase = 2
+	image, err = GetDefaultImage(&sg, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/worker:5.4.0", image)
+
+	// The progress of an upgrade to another version is disregarded.
+	sg.Status.Upgrade = &UpgradeStatus{Version: "5.4.1", Phase: 0}
+	image, err = GetDefaultImage(&sg, "frontend")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/frontend:5.4.0", image)
+}
+
+func TestUpgradePhases(t *testing.T) {
+	setUpgradePhases(t)
+	for _, tc := range []struct {
+		name       string
+		current    string
+		requested  string
+		wantPhases int
+	}{
+		{name: "minor upgrade", current: "5.3.2", requested: "5.4.0", wantPhases: 2},
+		{name: "new deployment", current: "", requested: "5.4.0"},
+		{name: "same version", current: "5.4.0", requested: "5.4.0"},
+		{name: "downgrade", current: "5.4.0", requested: "5.3.2"},
+		{name: "upgrade without phases", current: "5.2.7", requested: "5.3.2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sg := NewDefaultConfig()
+			sg.Spec.RequestedVersion = tc.requested
+			sg.Status.CurrentVersion = tc.current
+			assert.Len(t, sg.UpgradePhases(), tc.wantPhases)
+		})
+	}
+}
+
+func TestSourcegraphValidateUpgrade_Phases(t *testing.T) {
+	setUpgradePhases(t)
+	upgradePhases["5.4.0"][1].Components["worker"] = "5.4.0-missing"
+
+	sg := NewDefaultConfig()
+	sg.Spec.RequestedVersion = "5.4.0"
+	sg.Status.CurrentVersion = "5.3.2"
+	assert.ErrorContains(t, sg.ValidateUpgrade(), `spec.requestedVersion: phase "data migrations" of the upgrade from 5.3.2: no default image found for service worker in version 5.4.0-missing`)
+}
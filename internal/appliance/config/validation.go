@@ -0,0 +1,900 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/regexp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Validate checks the whole spec, so that a broken config is rejected before
+// any of it is applied. Every problem is reported, each prefixed with the JSON
+// path of the field it's about, e.g. spec.gitServer.replicas.
+func (sg *Sourcegraph) Validate() error {
+	var errs error
+	spec := sg.Spec
+
+	// Fields that conflict with a default are checked against the defaults
+	// of the deployment size.
+	defaults, err := NewDefaultConfigForSize(spec.Size)
+	errs = appendFieldErrors(errs, "spec", err)
+
+	if _, err := ResolveImageVersion(sg); err != nil {
+		errs = errors.Append(errs, errors.Wrapf(err, "spec.requestedVersion: %q is not a supported version", spec.RequestedVersion))
+	} else if _, err := ResolveAllImages(sg); err != nil {
+		errs = appendFieldErrors(errs, "spec", err)
+	}
+
+	errs = appendFieldErrors(errs, "spec", validateNamePrefix(spec.NamePrefix))
+	errs = appendFieldErrors(errs, "spec", validateMetadata(spec.Labels, spec.Annotations))
+	errs = appendFieldErrors(errs, "spec", spec.validateEgress())
+	errs = appendFieldErrors(errs, "spec", validateDriftPolicy(spec.DriftPolicy))
+	errs = appendFieldErrors(errs, "spec", validateDataRetentionPolicy(spec.DataRetentionPolicy))
+	errs = appendFieldErrors(errs, "spec", validateOperatorUpdatePolicy(sg))
+	ipFamiliesErr := validateIPFamilies(spec.IPFamilyPolicy, spec.IPFamilies)
+	errs = appendFieldErrors(errs, "spec", ipFamiliesErr)
+
+	components := spec.standardComponents()
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cfg := components[name]
+		path := "spec." + name
+		if size := cfg.GetPersistentVolumeConfig().StorageSize; size != "" {
+			if _, err := resource.ParseQuantity(size); err != nil {
+				errs = errors.Append(errs, errors.Newf("%s.persistentVolumeConfig.storageSize: %q is not a valid quantity", path, size))
+			}
+		}
+		if port := cfg.GetPrometheusPort(); port != nil && (*port < 1 || *port > 65535) {
+			errs = errors.Append(errs, errors.Newf("%s.prometheusPort: %d is not between 1 and 65535", path, *port))
+		}
+		errs = appendFieldErrors(errs, path, cfg.GetServiceAccountConfig().Validate())
+		if budget := cfg.GetDisruptionBudget(); budget != nil {
+			errs = appendFieldErrors(errs, path, budget.Validate())
+		}
+		for _, ref := range cfg.GetEnvFrom() {
+			errs = appendFieldErrors(errs, path, ref.Validate())
+		}
+		errs = appendFieldErrors(errs, path, validateMetadata(cfg.GetLabels(), cfg.GetAnnotations()))
+		// A service's own IP families are checked together with the
+		// spec-wide ones they are combined with, unless those are already
+		// reported as invalid.
+		if (cfg.GetIPFamilyPolicy() != "" || len(cfg.GetIPFamilies()) > 0) && ipFamiliesErr == nil {
+			errs = appendFieldErrors(errs, path, validateIPFamilies(spec.IPFamiliesFor(cfg)))
+		}
+		errs = appendFieldErrors(errs, path, validateTopologySpreadConstraints(cfg.GetTopologySpreadConstraints()))
+		errs = appendFieldErrors(errs, path, validateProbes(cfg))
+		if strategy := cfg.GetStrategy(); strategy != nil {
+			errs = appendFieldErrors(errs, path, strategy.Validate())
+		}
+	}
+
+	for _, replicas := range []struct {
+		path  string
+		count int32
+	}{
+		{"spec.executors.replicas", spec.Executors.Replicas},
+		{"spec.frontend.replicas", spec.Frontend.Replicas},
+		{"spec.gitServer.replicas", spec.GitServer.Replicas},
+		{"spec.indexedSearch.replicas", spec.IndexedSearch.Replicas},
+		{"spec.preciseCodeIntel.replicas", spec.PreciseCodeIntel.Replicas},
+		{"spec.searcher.replicas", spec.Searcher.Replicas},
+		{"spec.symbols.replicas", spec.Symbols.Replicas},
+		{"spec.syntectServer.replicas", spec.SyntectServer.Replicas},
+		{"spec.worker.replicas", spec.Worker.Replicas},
+	} {
+		if replicas.count < 0 {
+			errs = errors.Append(errs, errors.Newf("%s: must not be negative, got %d", replicas.path, replicas.count))
+		}
+	}
+
+	errs = appendFieldErrors(errs, "spec.codeInsights.database", spec.CodeInsights.DatabaseConnection.validate())
+	errs = appendFieldErrors(errs, "spec.codeIntel.database", spec.CodeIntel.DatabaseConnection.validate())
+	errs = appendFieldErrors(errs, "spec.pgsql.database", spec.PGSQL.DatabaseConnection.validate())
+
+	errs = appendFieldErrors(errs, "spec.codeInsights.backup", spec.CodeInsights.Backup.validate())
+	errs = appendFieldErrors(errs, "spec.codeIntel.backup", spec.CodeIntel.Backup.validate())
+	errs = appendFieldErrors(errs, "spec.pgsql.backup", spec.PGSQL.Backup.validate())
+	errs = appendFieldErrors(errs, "spec.codeInsights.exporter", spec.CodeInsights.Exporter.validate())
+	errs = appendFieldErrors(errs, "spec.codeIntel.exporter", spec.CodeIntel.Exporter.validate())
+	errs = appendFieldErrors(errs, "spec.pgsql.exporter", spec.PGSQL.Exporter.validate())
+	errs = appendFieldErrors(errs, "spec.codeInsights.connectionPooler", spec.CodeInsights.ConnectionPooler.validate())
+	errs = appendFieldErrors(errs, "spec.codeIntel.connectionPooler", spec.CodeIntel.ConnectionPooler.validate())
+	errs = appendFieldErrors(errs, "spec.pgsql.connectionPooler", spec.PGSQL.ConnectionPooler.validate())
+
+	errs = appendFieldErrors(errs, "spec.redisCache", spec.RedisCache.validate("redis-cache"))
+	errs = appendFieldErrors(errs, "spec.redisStore", spec.RedisStore.validate("redis-store"))
+	errs = appendFieldErrors(errs, "spec.preciseCodeIntel", spec.PreciseCodeIntel.validate())
+	errs = appendFieldErrors(errs, "spec.repoUpdater", spec.RepoUpdater.validate())
+	errs = appendFieldErrors(errs, "spec.worker", spec.Worker.validate())
+	errs = appendFieldErrors(errs, "spec.executors", spec.Executors.validate())
+	errs = appendFieldErrors(errs, "spec.symbols", spec.Symbols.validate())
+	errs = appendFieldErrors(errs, "spec.syntectServer", spec.SyntectServer.validate())
+	errs = appendFieldErrors(errs, "spec.prometheus", spec.Prometheus.validate())
+	errs = appendFieldErrors(errs, "spec.blobstore", spec.Blobstore.validate(defaults.Spec.Blobstore.PersistentVolumeConfig.StorageSize))
+	if spec.Frontend.Ingress != nil {
+		errs = appendFieldErrors(errs, "spec.frontend", spec.Frontend.Ingress.Validate())
+	}
+	errs = appendFieldErrors(errs, "spec.siteConfig", spec.SiteConfig.validate())
+	errs = appendFieldErrors(errs, "spec", spec.NetworkPolicies.Validate())
+	errs = appendFieldErrors(errs, "spec.migrationGate", spec.MigrationGate.validate())
+	errs = appendFieldErrors(errs, "spec", spec.Monitoring.Validate())
+	errs = appendFieldErrors(errs, "spec.cadvisor", spec.Cadvisor.Validate())
+	errs = appendFieldErrors(errs, "spec.otelCollector", spec.OtelCollector.Validate())
+	errs = appendFieldErrors(errs, "spec.preciseCodeIntel", spec.PreciseCodeIntel.Autoscaling.validate(spec.PreciseCodeIntel.Replicas, defaults.Spec.PreciseCodeIntel.Replicas))
+	errs = appendFieldErrors(errs, "spec.searcher", spec.Searcher.Autoscaling.validate(spec.Searcher.Replicas, defaults.Spec.Searcher.Replicas))
+	errs = appendFieldErrors(errs, "spec.symbols", spec.Symbols.Autoscaling.validate(spec.Symbols.Replicas, defaults.Spec.Symbols.Replicas))
+	errs = appendFieldErrors(errs, "spec.syntectServer", spec.SyntectServer.Autoscaling.validate(spec.SyntectServer.Replicas, defaults.Spec.SyntectServer.Replicas))
+	return errs
+}
+
+// Warnings reports the settings of a valid spec that are likely to cause
+// problems, each with the JSON path of the field it's about.
+func (sg *Sourcegraph) Warnings() []FieldWarning {
+	defaults, err := NewDefaultConfigForSize(sg.Spec.Size)
+	if err != nil {
+		// Reported by Validate.
+		return nil
+	}
+
+	var warnings []FieldWarning
+	if syntect := sg.Spec.SyntectServer; syntect.scaledWithoutSizing(defaults.Spec.SyntectServer) {
+		warnings = append(warnings, FieldWarning{
+			Path:    "spec.syntectServer.replicas",
+			Message: fmt.Sprintf("raised to %d without configuring the resources of the syntect-server container; syntect-server is memory-bound, so size its memory for the number of workers before adding replicas", syntect.Replicas),
+		})
+	}
+	return warnings
+}
+
+// scaledWithoutSizing returns true if the number of replicas is raised above
+// the default, but the resources of the syntect-server container are left at
+// their defaults.
+func (c SyntectServerSpec) scaledWithoutSizing(defaults SyntectServerSpec) bool {
+	if c.Replicas <= max(defaults.Replicas, 1) {
+		return false
+	}
+	ctr := c.ContainerConfig["syntect-server"]
+	if ctr.BestEffortQOS {
+		// Deliberately unsized.
+		return false
+	}
+	return ctr.Resources == nil || reflect.DeepEqual(ctr.Resources, defaults.ContainerConfig["syntect-server"].Resources)
+}
+
+func (c MigrationGateSpec) validate() error {
+	if c.SchemaVersionConfigMap == "" {
+		return nil
+	}
+	if problems := validation.IsDNS1123Subdomain(c.SchemaVersionConfigMap); len(problems) > 0 {
+		return errors.Newf("schemaVersionConfigMap: %q is not a valid ConfigMap name: %s", c.SchemaVersionConfigMap, strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// maxNamePrefixLength leaves room in every object name, and in the names
+// that Kubernetes derives from them, such as those of a StatefulSet's pods,
+// for the longest of our names.
+const maxNamePrefixLength = 20
+
+func validateNamePrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	var errs error
+	for _, msg := range validation.IsDNS1123Label(prefix) {
+		errs = errors.Append(errs, errors.Newf("namePrefix: %q is not a valid DNS label: %s", prefix, msg))
+	}
+	if len(prefix) > maxNamePrefixLength {
+		errs = errors.Append(errs, errors.Newf("namePrefix: %q is longer than %d characters", prefix, maxNamePrefixLength))
+	}
+	return errs
+}
+
+// validateMetadata checks that custom labels and annotations are accepted by
+// the Kubernetes API server.
+func validateMetadata(labels, annotations map[string]string) error {
+	var errs error
+	for _, key := range sortedKeys(labels) {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = errors.Append(errs, errors.Newf("labels: %q is not a valid key: %s", key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(labels[key]) {
+			errs = errors.Append(errs, errors.Newf("labels.%s: %q is not a valid value: %s", key, labels[key], msg))
+		}
+	}
+	for _, key := range sortedKeys(annotations) {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = errors.Append(errs, errors.Newf("annotations: %q is not a valid key: %s", key, msg))
+		}
+	}
+	return errs
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendFieldErrors appends each of the errors in err to errs, prefixed with
+// the JSON path of the field they're about.
+func appendFieldErrors(errs error, path string, err error) error {
+	if err == nil {
+		return errs
+	}
+	if multi, ok := err.(errors.MultiError); ok {
+		for _, err := range multi.Errors() {
+			errs = errors.Append(errs, errors.Wrap(err, path))
+		}
+		return errs
+	}
+	return errors.Append(errs, errors.Wrap(err, path))
+}
+
+// validate checks that custom database connection details are complete, since
+// the services would otherwise fall back to defaults for the missing ones and
+// connect to the wrong database.
+func (c *DatabaseConnectionSpec) validate() error {
+	if c == nil {
+		return nil
+	}
+	var errs error
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"host", c.Host},
+		{"port", c.Port},
+		{"user", c.User},
+		{"password", c.Password},
+		{"database", c.Database},
+	} {
+		if field.value == "" {
+			errs = errors.Append(errs, errors.Newf("%s is required", field.name))
+		}
+	}
+	return errs
+}
+
+var (
+	cronScheduleMacros = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+	backupNamePattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+)
+
+// validate checks the fields of a backup config that the API server would
+// only reject once the CronJob or Job is created.
+func (b *DatabaseBackupSpec) validate() error {
+	if b == nil {
+		return nil
+	}
+	var errs error
+	if b.Schedule != "" && !slices.Contains(cronScheduleMacros, b.Schedule) && len(strings.Fields(b.Schedule)) != 5 {
+		errs = errors.Append(errs, errors.Newf("schedule: %q is not a cron schedule", b.Schedule))
+	}
+	if b.Retention < 0 {
+		errs = errors.Append(errs, errors.Newf("retention: must not be negative, got %d", b.Retention))
+	}
+	if size := b.PersistentVolumeConfig.StorageSize; size != "" {
+		if _, err := resource.ParseQuantity(size); err != nil {
+			errs = errors.Append(errs, errors.Newf("persistentVolumeConfig.storageSize: %q is not a valid quantity", size))
+		}
+	}
+	if b.RestoreFrom != "" && !backupNamePattern.MatchString(b.RestoreFrom) {
+		errs = errors.Append(errs, errors.Newf("restoreFrom: %q is not the name of a backup", b.RestoreFrom))
+	}
+	return errs
+}
+
+// validate checks that the site configuration is given either inline or in a
+// fully referenced Secret. Its content is checked against the site
+// configuration schema when the frontend is reconciled, since a Secret can
+// only be read then.
+func (c *SiteConfigSpec) validate() error {
+	if c == nil {
+		return nil
+	}
+	switch {
+	case c.JSON != "" && c.SecretRef != nil:
+		return errors.New("json and secretRef are mutually exclusive")
+	case c.JSON == "" && c.SecretRef == nil:
+		return errors.New("one of json or secretRef is required")
+	case c.SecretRef != nil && (c.SecretRef.Name == "" || c.SecretRef.Key == ""):
+		return errors.New("secretRef: name and key are required")
+	}
+	return nil
+}
+
+// validate checks that the secret and ConfigMap that the exporter reads from
+// are fully referenced, as its pod would otherwise fail to start.
+func (e *PostgresExporterConfig) validate() error {
+	if e == nil {
+		return nil
+	}
+	var errs error
+	if ref := e.DSNSecretRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+		errs = errors.Append(errs, errors.New("dsnSecretRef: name and key are required"))
+	}
+	if ref := e.CustomQueriesConfigMapRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+		errs = errors.Append(errs, errors.New("customQueriesConfigMapRef: name and key are required"))
+	}
+	return errs
+}
+
+// validate checks the settings of a connection pooler, which PgBouncer would
+// only reject once it starts.
+func (p *ConnectionPoolerSpec) validate() error {
+	if p == nil {
+		return nil
+	}
+	var errs error
+	if p.PoolMode != "" && !slices.Contains(PoolModes, p.PoolMode) {
+		errs = errors.Append(errs, errors.Newf("poolMode: must be one of %s, got %q", strings.Join(PoolModes, ", "), p.PoolMode))
+	}
+	if p.DefaultPoolSize < 0 {
+		errs = errors.Append(errs, errors.Newf("defaultPoolSize: must not be negative, got %d", p.DefaultPoolSize))
+	}
+	if p.MaxClientConnections < 0 {
+		errs = errors.Append(errs, errors.Newf("maxClientConnections: must not be negative, got %d", p.MaxClientConnections))
+	}
+	return errs
+}
+
+var redisMaxMemoryPolicies = []string{
+	"noeviction",
+	"allkeys-lru",
+	"allkeys-lfu",
+	"allkeys-random",
+	"volatile-lru",
+	"volatile-lfu",
+	"volatile-random",
+	"volatile-ttl",
+}
+
+// validate checks the tuning of a bundled redis whose container is called
+// containerName. MaxMemory must leave room for redis' overhead within a
+// configured memory limit, or redis is OOM killed before it starts evicting.
+func (c RedisSpec) validate(containerName string) error {
+	var errs error
+	if c.MaxMemory != "" {
+		maxMemory, err := resource.ParseQuantity(c.MaxMemory)
+		if err != nil {
+			errs = errors.Append(errs, errors.Newf("maxMemory: %q is not a valid quantity", c.MaxMemory))
+		} else if resources := c.ContainerConfig[containerName].Resources; resources != nil {
+			if limit, ok := resources.Limits[corev1.ResourceMemory]; ok && maxMemory.Cmp(limit) >= 0 {
+				errs = errors.Append(errs, errors.Newf("maxMemory: %s must be less than the memory limit of the %s container, %s", c.MaxMemory, containerName, limit.String()))
+			}
+		}
+	}
+	if c.MaxMemoryPolicy != "" && !slices.Contains(redisMaxMemoryPolicies, c.MaxMemoryPolicy) {
+		errs = errors.Append(errs, errors.Newf("maxMemoryPolicy: %q is not one of %s", c.MaxMemoryPolicy, strings.Join(redisMaxMemoryPolicies, ", ")))
+	}
+	return errs
+}
+
+// validateProbes checks the probe overrides of a service against the limits
+// that the Kubernetes API server enforces.
+func validateProbes(cfg StandardComponent) error {
+	var errs error
+	for _, probe := range []struct {
+		name   string
+		cfg    *ProbeConfig
+		single bool // whether successThreshold must be 1
+	}{
+		{"livenessProbe", cfg.GetLivenessProbe(), true},
+		{"readinessProbe", cfg.GetReadinessProbe(), false},
+		{"startupProbe", cfg.GetStartupProbe(), true},
+	} {
+		if probe.cfg == nil {
+			continue
+		}
+		if n := probe.cfg.InitialDelaySeconds; n != nil && *n < 0 {
+			errs = errors.Append(errs, errors.Newf("%s.initialDelaySeconds: must not be negative, got %d", probe.name, *n))
+		}
+		for _, field := range []struct {
+			name  string
+			value *int32
+		}{
+			{"timeoutSeconds", probe.cfg.TimeoutSeconds},
+			{"periodSeconds", probe.cfg.PeriodSeconds},
+			{"successThreshold", probe.cfg.SuccessThreshold},
+			{"failureThreshold", probe.cfg.FailureThreshold},
+		} {
+			if field.value != nil && *field.value < 1 {
+				errs = errors.Append(errs, errors.Newf("%s.%s: must be positive, got %d", probe.name, field.name, *field.value))
+			}
+		}
+		if n := probe.cfg.SuccessThreshold; probe.single && n != nil && *n > 1 {
+			errs = errors.Append(errs, errors.Newf("%s.successThreshold: must be 1, got %d", probe.name, *n))
+		}
+	}
+	return errs
+}
+
+func (c PreciseCodeIntelSpec) validate() error {
+	var errs error
+	if c.NumWorkers < 1 {
+		errs = errors.Append(errs, errors.Newf("numWorkers: must be at least 1, got %d", c.NumWorkers))
+	}
+	if c.UploadPollInterval != "" {
+		if interval, err := time.ParseDuration(c.UploadPollInterval); err != nil || interval <= 0 {
+			errs = errors.Append(errs, errors.Newf("uploadPollInterval: %q is not a positive duration", c.UploadPollInterval))
+		}
+	}
+	if c.MaxUploadSize != "" {
+		if size, err := resource.ParseQuantity(c.MaxUploadSize); err != nil || size.Sign() <= 0 {
+			errs = errors.Append(errs, errors.Newf("maxUploadSize: %q is not a positive quantity", c.MaxUploadSize))
+		}
+	}
+	if tempDir := c.TempDir; tempDir != nil {
+		if tempDir.SizeLimit != "" {
+			if _, err := resource.ParseQuantity(tempDir.SizeLimit); err != nil {
+				errs = errors.Append(errs, errors.Newf("tempDir.sizeLimit: %q is not a valid quantity", tempDir.SizeLimit))
+			}
+			if tempDir.PersistentVolume != nil {
+				errs = errors.Append(errs, errors.New("tempDir.sizeLimit cannot be set with tempDir.persistentVolume, whose storageSize is its size"))
+			}
+		}
+		if pv := tempDir.PersistentVolume; pv != nil {
+			if _, err := resource.ParseQuantity(pv.StorageSize); err != nil {
+				errs = errors.Append(errs, errors.Newf("tempDir.persistentVolume.storageSize: %q is not a valid quantity", pv.StorageSize))
+			}
+		}
+	}
+	return errs
+}
+
+func (c RepoUpdaterSpec) validate() error {
+	var errs error
+	if c.SyncInterval != "" {
+		if interval, err := time.ParseDuration(c.SyncInterval); err != nil || interval <= 0 {
+			errs = errors.Append(errs, errors.Newf("syncInterval: %q is not a positive duration", c.SyncInterval))
+		}
+	}
+	for _, field := range []struct {
+		name  string
+		value *int32
+	}{
+		{"maxConcurrentSyncers", c.MaxConcurrentSyncers},
+		{"maxConcurrentRequests", c.MaxConcurrentRequests},
+	} {
+		if field.value != nil && *field.value < 1 {
+			errs = errors.Append(errs, errors.Newf("%s: must be positive, got %d", field.name, *field.value))
+		}
+	}
+	if limit := c.DefaultRateLimit; limit != nil {
+		if limit.RequestsPerHour < 1 {
+			errs = errors.Append(errs, errors.Newf("defaultRateLimit.requestsPerHour: must be positive, got %d", limit.RequestsPerHour))
+		}
+		if limit.Burst != nil && *limit.Burst < 1 {
+			errs = errors.Append(errs, errors.Newf("defaultRateLimit.burst: must be positive, got %d", *limit.Burst))
+		}
+	}
+	return errs
+}
+
+func (c WorkerSpec) validate() error {
+	errs := c.validateJobs()
+	for _, name := range sortedKeys(c.ExtraWorkers) {
+		extra := c.ExtraWorkers[name]
+		for _, msg := range validation.IsDNS1123Label("worker-" + name) {
+			errs = errors.Append(errs, errors.Newf("extraWorkers: %q is not a valid name: %s", name, msg))
+		}
+		errs = appendFieldErrors(errs, "extraWorkers."+name, extra.validateJobs())
+		if len(extra.ExtraWorkers) > 0 {
+			errs = errors.Append(errs, errors.Newf("extraWorkers.%s.extraWorkers: extra workers can't have extra workers of their own", name))
+		}
+	}
+	return errs
+}
+
+func (c WorkerSpec) validateJobs() error {
+	if len(c.JobAllowlist) > 0 && len(c.JobDenylist) > 0 {
+		return errors.New("jobAllowlist and jobDenylist are mutually exclusive")
+	}
+	return nil
+}
+
+// executorQueues are the queues that executors can process jobs from.
+var executorQueues = []string{"batches", "codeintel"}
+
+func (c ExecutorsSpec) validate() error {
+	var errs error
+	if len(c.Queues) == 0 {
+		errs = errors.Append(errs, errors.New("queues: at least one queue must be processed"))
+	}
+	for _, queue := range c.Queues {
+		if !slices.Contains(executorQueues, queue) {
+			errs = errors.Append(errs, errors.Newf("queues: unknown queue %q, valid queues are %s", queue, strings.Join(executorQueues, " and ")))
+		}
+	}
+	if ref := c.AccessTokenSecretRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+		errs = errors.Append(errs, errors.New("accessTokenSecretRef: name and key must be set"))
+	}
+	if ns := c.Kubernetes.Namespace; ns != "" {
+		for _, msg := range validation.IsDNS1123Label(ns) {
+			errs = errors.Append(errs, errors.Newf("kubernetes.namespace: %q is not a valid namespace: %s", ns, msg))
+		}
+	}
+	if n := c.Kubernetes.MaxConcurrentJobs; n != nil && *n < 1 {
+		errs = errors.Append(errs, errors.Newf("kubernetes.maxConcurrentJobs: must be positive, got %d", *n))
+	}
+	return errs
+}
+
+func (c SyntectServerSpec) validate() error {
+	var errs error
+	if n := c.NumWorkers; n != nil && *n < 1 {
+		errs = errors.Append(errs, errors.Newf("numWorkers: must be positive, got %d", *n))
+	}
+	if c.RequestTimeout != "" {
+		if timeout, err := time.ParseDuration(c.RequestTimeout); err != nil || timeout <= 0 {
+			errs = errors.Append(errs, errors.Newf("requestTimeout: %q is not a positive duration", c.RequestTimeout))
+		}
+	}
+	if c.MaxFileSize != "" {
+		if size, err := resource.ParseQuantity(c.MaxFileSize); err != nil || size.Sign() <= 0 {
+			errs = errors.Append(errs, errors.Newf("maxFileSize: %q is not a positive quantity", c.MaxFileSize))
+		}
+	}
+	return errs
+}
+
+var (
+	prometheusDurationPattern  = regexp.MustCompile(`^([0-9]+y)?([0-9]+w)?([0-9]+d)?([0-9]+h)?([0-9]+m)?([0-9]+s)?([0-9]+ms)?$`)
+	prometheusLabelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+func (c PrometheusSpec) validate() error {
+	var errs error
+	if c.RetentionTime != "" && (!prometheusDurationPattern.MatchString(c.RetentionTime) || !strings.ContainsAny(c.RetentionTime, "123456789")) {
+		errs = errors.Append(errs, errors.Newf("retentionTime: %q is not a positive Prometheus duration, e.g. 30d", c.RetentionTime))
+	}
+	if c.RetentionSize != "" {
+		if size, err := resource.ParseQuantity(c.RetentionSize); err != nil || size.Sign() <= 0 {
+			errs = errors.Append(errs, errors.Newf("retentionSize: %q is not a positive quantity", c.RetentionSize))
+		} else if storageSize, err := resource.ParseQuantity(c.PersistentVolumeConfig.StorageSize); err == nil && size.Cmp(storageSize) > 0 {
+			// An unparseable storage size is reported with the other
+			// persistent volume configs.
+			errs = errors.Append(errs, errors.Newf("retentionSize: %s is larger than the %s persistent volume", c.RetentionSize, c.PersistentVolumeConfig.StorageSize))
+		}
+	}
+
+	if c.ExistingConfigMap != "" && (len(c.ExternalLabels) > 0 || len(c.RemoteWrite) > 0) {
+		errs = errors.Append(errs, errors.New("externalLabels and remoteWrite are rendered into the generated config, and can't be used with existingConfigMap"))
+	}
+	if c.ExistingConfigMap != "" && c.Alerting != nil {
+		errs = errors.Append(errs, errors.New("alerting is rendered into the generated config, and can't be used with existingConfigMap"))
+	}
+	if c.Alerting != nil {
+		errs = appendFieldErrors(errs, "alerting", c.Alerting.validate())
+	}
+	for _, name := range sortedKeys(c.ExternalLabels) {
+		if !prometheusLabelNamePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+			errs = errors.Append(errs, errors.Newf("externalLabels: %q is not a valid label name", name))
+		}
+	}
+
+	names := make(map[string]bool, len(c.RemoteWrite))
+	for i, rw := range c.RemoteWrite {
+		path := fmt.Sprintf("remoteWrite[%d]", i)
+		for _, msg := range validation.IsDNS1123Label(rw.Name) {
+			errs = errors.Append(errs, errors.Newf("%s.name: %q is not a valid name: %s", path, rw.Name, msg))
+		}
+		if names[rw.Name] {
+			errs = errors.Append(errs, errors.Newf("%s.name: %q is used by another endpoint", path, rw.Name))
+		}
+		names[rw.Name] = true
+		if u, err := url.Parse(rw.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			errs = errors.Append(errs, errors.Newf("%s.url: %q is not an http or https URL", path, rw.URL))
+		}
+		if auth := rw.BasicAuth; auth != nil {
+			if auth.Username == "" || auth.PasswordSecret == "" {
+				errs = errors.Append(errs, errors.Newf("%s.basicAuth: username and passwordSecret are required", path))
+			}
+			if rw.BearerTokenSecret != "" {
+				errs = errors.Append(errs, errors.Newf("%s: only one of basicAuth and bearerTokenSecret can be set", path))
+			}
+		}
+	}
+	return errs
+}
+
+func (c SymbolsSpec) validate() error {
+	var errs error
+	if c.CacheSizeMB != nil {
+		if *c.CacheSizeMB < 1 {
+			errs = errors.Append(errs, errors.Newf("cacheSizeMB: must be positive, got %d", *c.CacheSizeMB))
+		} else if storageSize, err := resource.ParseQuantity(c.PersistentVolumeConfig.StorageSize); err == nil && int64(*c.CacheSizeMB)*1024*1024 > storageSize.Value() {
+			// An unparseable storage size is reported with the other
+			// persistent volume configs.
+			errs = errors.Append(errs, errors.Newf("cacheSizeMB: %d does not fit on the %s persistent volume", *c.CacheSizeMB, c.PersistentVolumeConfig.StorageSize))
+		}
+	}
+	if rockskip := c.Rockskip; rockskip != nil {
+		if n := rockskip.MinRepoSizeMB; n != nil && *n < 0 {
+			errs = errors.Append(errs, errors.Newf("rockskip.minRepoSizeMB: must not be negative, got %d", *n))
+		}
+		if n := rockskip.MaxConcurrentlyIndexing; n != nil && *n < 1 {
+			errs = errors.Append(errs, errors.Newf("rockskip.maxConcurrentlyIndexing: must be positive, got %d", *n))
+		}
+	}
+	return errs
+}
+
+// Validate checks that the blobstore config is internally consistent.
+func (c BlobstoreSpec) Validate() error {
+	return c.validate(NewDefaultConfig().Spec.Blobstore.PersistentVolumeConfig.StorageSize)
+}
+
+// validate is Validate with the default storage size passed, since it depends
+// on the deployment size.
+func (c BlobstoreSpec) validate(defaultStorageSize string) error {
+	var errs error
+	if c.Retention != nil {
+		errs = errors.Append(errs, c.Retention.validate())
+	}
+
+	ext := c.ExternalStorage
+	if ext == nil {
+		return errs
+	}
+
+	if c.Retention != nil {
+		errs = errors.Append(errs, errors.New("retention cannot be set when externalStorage is configured, configure the lifecycle of the bucket instead"))
+	}
+	switch ext.Backend {
+	case ExternalStorageBackendS3:
+		if ext.Bucket == "" {
+			errs = errors.Append(errs, errors.New("externalStorage.bucket must be set for the S3 backend"))
+		}
+		if ext.CredentialsSecret == "" {
+			errs = errors.Append(errs, errors.New("externalStorage.credentialsSecret must be set for the S3 backend"))
+		}
+	case ExternalStorageBackendGCS:
+		if ext.Bucket == "" {
+			errs = errors.Append(errs, errors.New("externalStorage.bucket must be set for the GCS backend"))
+		}
+	default:
+		errs = errors.Append(errs, errors.Newf("externalStorage.backend must be one of %q or %q, got %q", ExternalStorageBackendS3, ExternalStorageBackendGCS, ext.Backend))
+	}
+
+	// The storage size is only meaningful for the bundled blobstore, so a value
+	// that differs from the default is most likely a mistake.
+	if size := c.PersistentVolumeConfig.StorageSize; size != "" && size != defaultStorageSize {
+		errs = errors.Append(errs, errors.New("persistentVolumeConfig.storageSize cannot be set when externalStorage is configured"))
+	}
+
+	return errs
+}
+
+// validate checks that the TTLs are durations of prefixes within buckets, and
+// that the thresholds are percentages.
+func (c *BlobstoreRetentionConfig) validate() error {
+	var errs error
+	prefixes := make([]string, 0, len(c.TTLs))
+	for prefix := range c.TTLs {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		if prefix == "" || path.IsAbs(prefix) || path.Clean(prefix) != prefix || strings.HasPrefix(prefix, "..") {
+			errs = errors.Append(errs, errors.Newf("retention.ttls: %q is not a bucket or a prefix within one", prefix))
+		}
+		if ttl, err := time.ParseDuration(c.TTLs[prefix]); err != nil || ttl < 0 || (ttl > 0 && ttl < time.Minute) {
+			errs = errors.Append(errs, errors.Newf("retention.ttls.%s: %q is not 0 or a duration of at least a minute", prefix, c.TTLs[prefix]))
+		}
+	}
+	if p := c.HighWaterMarkPercent; p != nil && (*p < 1 || *p > 100) {
+		errs = errors.Append(errs, errors.Newf("retention.highWaterMarkPercent: must be between 1 and 100, got %d", *p))
+	}
+	if p := c.WarningThresholdPercent; p != nil && (*p < 1 || *p > 100) {
+		errs = errors.Append(errs, errors.Newf("retention.warningThresholdPercent: must be between 1 and 100, got %d", *p))
+	}
+	return errs
+}
+
+// Validate checks that the container runtime is known and that the host
+// paths are absolute.
+func (c CadvisorSpec) Validate() error {
+	var errs error
+	switch c.ContainerRuntime {
+	case "", CadvisorContainerRuntimeDocker, CadvisorContainerRuntimeContainerd:
+	default:
+		errs = errors.Append(errs, errors.Newf("containerRuntime must be one of %q or %q, got %q",
+			CadvisorContainerRuntimeDocker, CadvisorContainerRuntimeContainerd, c.ContainerRuntime))
+	}
+	if c.RuntimeSocketPath != "" && !path.IsAbs(c.RuntimeSocketPath) {
+		errs = errors.Append(errs, errors.Newf("runtimeSocketPath: %q is not an absolute path", c.RuntimeSocketPath))
+	}
+	for i, p := range c.ExtraHostPaths {
+		if !path.IsAbs(p) {
+			errs = errors.Append(errs, errors.Newf("extraHostPaths[%d]: %q is not an absolute path", i, p))
+		}
+	}
+	return errs
+}
+
+// Validate checks that the otel collector config is internally consistent.
+func (c OtelCollectorSpec) Validate() error {
+	if c.CustomConfigYAML == "" {
+		return nil
+	}
+	var parsed map[string]any
+	if err := yaml.Unmarshal([]byte(c.CustomConfigYAML), &parsed); err != nil {
+		return errors.Wrap(err, "customConfigYAML is not valid YAML")
+	}
+	return nil
+}
+
+// Validate checks that the ingress config is internally consistent.
+func (c IngressSpec) Validate() error {
+	var errs error
+	if kind := c.GetKind(); kind != IngressKindIngress && kind != IngressKindRoute {
+		errs = errors.Append(errs, errors.Newf("ingress.kind must be one of %q or %q, got %q", IngressKindIngress, IngressKindRoute, kind))
+	}
+	if c.TLSSecret != "" && c.Host == "" {
+		errs = errors.Append(errs, errors.New("ingress.host must be set when ingress.tlsSecret is configured"))
+	}
+	return errs
+}
+
+// Validate checks that the mode is known and that the extra CIDRs parse.
+func (c NetworkPoliciesSpec) Validate() error {
+	var errs error
+	switch mode := c.GetMode(); mode {
+	case NetworkPolicyModeNone, NetworkPolicyModeNamespaceIsolated, NetworkPolicyModeStrict:
+	default:
+		errs = errors.Append(errs, errors.Newf("networkPolicies.mode must be one of %q, %q, or %q, got %q", NetworkPolicyModeNone, NetworkPolicyModeNamespaceIsolated, NetworkPolicyModeStrict, mode))
+	}
+	for _, cidr := range c.ExtraAllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = errors.Append(errs, errors.Newf("networkPolicies.extraAllowedCIDRs: %q is not a valid CIDR", cidr))
+		}
+	}
+	return errs
+}
+
+// Validate checks that the mode is known.
+func (c MonitoringSpec) Validate() error {
+	switch mode := c.GetMode(); mode {
+	case MonitoringModeBundled, MonitoringModeServiceMonitor:
+		return nil
+	default:
+		return errors.Newf("monitoring.mode must be one of %q or %q, got %q", MonitoringModeBundled, MonitoringModeServiceMonitor, mode)
+	}
+}
+
+// Validate checks that at most one way of choosing a ServiceAccount is used.
+func (c ServiceAccountConfig) Validate() error {
+	if c.Name != "" && len(c.Annotations) > 0 {
+		return errors.New("serviceAccount.name and serviceAccount.annotations are mutually exclusive: annotations can only be applied to a ServiceAccount managed by the appliance")
+	}
+	return nil
+}
+
+// Validate checks that autoscaling isn't combined with a fixed number of
+// replicas.
+func (c PreciseCodeIntelSpec) Validate() error {
+	return c.Autoscaling.validate(c.Replicas, NewDefaultConfig().Spec.PreciseCodeIntel.Replicas)
+}
+
+// Validate checks that autoscaling isn't combined with a fixed number of
+// replicas.
+func (c SearcherSpec) Validate() error {
+	return c.Autoscaling.validate(c.Replicas, NewDefaultConfig().Spec.Searcher.Replicas)
+}
+
+// Validate checks that autoscaling isn't combined with a fixed number of
+// replicas.
+func (c SymbolsSpec) Validate() error {
+	return c.Autoscaling.validate(c.Replicas, NewDefaultConfig().Spec.Symbols.Replicas)
+}
+
+// Validate checks that autoscaling isn't combined with a fixed number of
+// replicas.
+func (c SyntectServerSpec) Validate() error {
+	return c.Autoscaling.validate(c.Replicas, NewDefaultConfig().Spec.SyntectServer.Replicas)
+}
+
+// validate checks that the autoscaling bounds and targets are sensible. The
+// default replica count is passed so that an explicitly configured one can be
+// told apart.
+func (c *AutoscalingConfig) validate(replicas, defaultReplicas int32) error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	var errs error
+	if replicas != defaultReplicas {
+		errs = errors.Append(errs, errors.New("replicas and autoscaling are mutually exclusive: the HorizontalPodAutoscaler manages the number of replicas"))
+	}
+	if c.MaxReplicas < 1 {
+		errs = errors.Append(errs, errors.New("autoscaling.maxReplicas must be at least 1"))
+	}
+	if c.MinReplicas != nil && (*c.MinReplicas < 1 || *c.MinReplicas > c.MaxReplicas) {
+		errs = errors.Append(errs, errors.New("autoscaling.minReplicas must be between 1 and autoscaling.maxReplicas"))
+	}
+	if c.TargetCPUUtilizationPercentage != nil && *c.TargetCPUUtilizationPercentage < 1 {
+		errs = errors.Append(errs, errors.New("autoscaling.targetCPUUtilizationPercentage must be at least 1"))
+	}
+	if c.TargetMemoryUtilizationPercentage != nil && *c.TargetMemoryUtilizationPercentage < 1 {
+		errs = errors.Append(errs, errors.New("autoscaling.targetMemoryUtilizationPercentage must be at least 1"))
+	}
+	return errs
+}
+
+// Validate checks that at most one of minAvailable and maxUnavailable is set.
+func (c DisruptionBudgetConfig) Validate() error {
+	if c.MinAvailable != nil && c.MaxUnavailable != nil {
+		return errors.New("disruptionBudget.minAvailable and disruptionBudget.maxUnavailable are mutually exclusive")
+	}
+	return nil
+}
+
+// Validate checks the strategy type and that the rollout parameters are not
+// negative. Whether the type suits the kind of workload is checked when the
+// service is reconciled.
+func (c StrategyConfig) Validate() error {
+	var errs error
+	switch c.Type {
+	case "", RollingUpdateStrategy, RecreateStrategy, OnDeleteStrategy:
+	default:
+		errs = errors.Append(errs, errors.Newf("strategy.type: %q is not one of %s, %s, %s", c.Type, RollingUpdateStrategy, RecreateStrategy, OnDeleteStrategy))
+	}
+	for _, field := range []struct {
+		name  string
+		value *intstr.IntOrString
+	}{
+		{"maxSurge", c.MaxSurge},
+		{"maxUnavailable", c.MaxUnavailable},
+	} {
+		if field.value == nil {
+			continue
+		}
+		if scaled, err := intstr.GetScaledValueFromIntOrPercent(field.value, 100, true); err != nil {
+			errs = errors.Append(errs, errors.Newf("strategy.%s: %q is not an integer or a percentage", field.name, field.value.String()))
+		} else if scaled < 0 {
+			errs = errors.Append(errs, errors.Newf("strategy.%s: must not be negative, got %s", field.name, field.value.String()))
+		}
+	}
+	if c.Partition != nil && *c.Partition < 0 {
+		errs = errors.Append(errs, errors.Newf("strategy.partition: must not be negative, got %d", *c.Partition))
+	}
+	return errs
+}
+
+// Validate checks that the env var reference is complete.
+func (r SecretOrConfigMapRef) Validate() error {
+	var errs error
+	if r.Name == "" {
+		errs = errors.Append(errs, errors.New("envFrom: name is required"))
+	}
+	if r.Key == "" {
+		errs = errors.Append(errs, errors.Newf("envFrom %s: key is required", r.Name))
+	}
+	if (r.SecretName == "") == (r.ConfigMapName == "") {
+		errs = errors.Append(errs, errors.Newf("envFrom %s: set exactly one of secretName and configMapName", r.Name))
+	}
+	return errs
+}
@@ -0,0 +1,43 @@
+// Package alertcatalog checks the alerts that the appliance's config ships
+// against the monitoring definitions. It lives apart from the config package
+// so that the config package doesn't depend on the monitoring module.
+package alertcatalog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/monitoring/definitions"
+	"github.com/sourcegraph/sourcegraph/monitoring/monitoring"
+)
+
+// TestPrometheusAlertsCatalog checks that config/prometheus/alerts.yml lists
+// the alerts of monitoring/definitions, since the thresholds of the spec are
+// validated against it and rendered into rules with it.
+func TestPrometheusAlertsCatalog(t *testing.T) {
+	want, err := monitoring.AlertRules(definitions.Default()...)
+	require.NoError(t, err)
+
+	var got []monitoring.AlertRule
+	for _, alert := range config.PrometheusAlerts() {
+		var d time.Duration
+		if alert.For != "" {
+			d, err = time.ParseDuration(alert.For)
+			require.NoError(t, err, alert.Name)
+		}
+		got = append(got, monitoring.AlertRule{
+			Name:        alert.Name,
+			Service:     alert.Service,
+			Observable:  alert.Observable,
+			Level:       alert.Level,
+			Description: alert.DescriptionWithThreshold(alert.Threshold),
+			Query:       alert.Expr(alert.Threshold),
+			Threshold:   alert.Threshold,
+			For:         d,
+		})
+	}
+	require.Equal(t, want, got, "config/prometheus/alerts.yml is out of date with monitoring/definitions")
+}
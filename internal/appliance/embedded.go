@@ -0,0 +1,59 @@
+package appliance
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/appliance/reconciler"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Result is the outcome of a single reconcile: the status of Sourcegraph, and
+// when to reconcile again, e.g. to check on a rollout.
+type Result = reconciler.Result
+
+// Reconcile deploys sg with c in a single pass, the same way the appliance
+// does whenever its ConfigMap changes, but without a controller manager,
+// caches, or leader election. The spec is stored in the appliance ConfigMap
+// named after sg, which owns the deployed objects like it does when the
+// appliance manages them.
+//
+// It is meant for integration tests and dev tooling that need a Sourcegraph
+// deployment. Reconciling again until the status is ready is up to the
+// caller.
+func Reconcile(ctx context.Context, c client.Client, sg *config.Sourcegraph) (Result, error) {
+	name, spec, err := embeddedSpec(sg)
+	if err != nil {
+		return Result{}, err
+	}
+	return reconciler.ReconcileSpec(ctx, c, name, spec)
+}
+
+// RenderOnly returns the objects that Reconcile would create for sg in an
+// empty namespace, without reading from or writing to a cluster. See
+// reconciler.Render.
+func RenderOnly(ctx context.Context, sg *config.Sourcegraph) ([]client.Object, error) {
+	name, spec, err := embeddedSpec(sg)
+	if err != nil {
+		return nil, err
+	}
+	return reconciler.Render(ctx, name, spec)
+}
+
+// embeddedSpec returns the name of the appliance ConfigMap of sg, and the
+// spec to store in it.
+func embeddedSpec(sg *config.Sourcegraph) (types.NamespacedName, []byte, error) {
+	if sg.Name == "" || sg.Namespace == "" {
+		return types.NamespacedName{}, nil, errors.New("the Sourcegraph config must have a name and a namespace, which its ConfigMap is stored under")
+	}
+	// The name and namespace are those of the ConfigMap, rather than part of
+	// the spec.
+	spec, err := config.MarshalMinimalYAML(config.Sourcegraph{Spec: sg.Spec})
+	if err != nil {
+		return types.NamespacedName{}, nil, errors.Wrap(err, "encoding spec")
+	}
+	return types.NamespacedName{Namespace: sg.Namespace, Name: sg.Name}, spec, nil
+}
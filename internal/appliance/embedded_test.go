@@ -0,0 +1,137 @@
+package appliance
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bazelbuild/rules_go/go/runfiles"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/appliance/reconciler"
+)
+
+func TestReconcile(t *testing.T) {
+	// No controller manager runs against this cluster, so the only reconciles
+	// are the ones the test makes.
+	testEnv := &envtest.Environment{
+		AttachControlPlaneOutput: true,
+		BinaryAssetsDirectory:    kubebuilderAssetPath(t),
+	}
+	apiServerCfg := testEnv.ControlPlane.GetAPIServer()
+	apiServerCfg.Configure().Set("bind-address", "127.0.0.1")
+	apiServerCfg.Configure().Set("advertise-address", "127.0.0.1")
+	cfg, err := testEnv.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, testEnv.Stop()) })
+
+	ctx := context.Background()
+	c, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	require.NoError(t, err)
+	require.NoError(t, c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "embedded"}}))
+
+	sg := config.NewDefaultConfig()
+	sg.Name = "sg"
+	sg.Namespace = "embedded"
+	sg.Spec.RequestedVersion = "5.3.9104"
+	name := types.NamespacedName{Namespace: sg.Namespace, Name: sg.Name}
+
+	_, err = Reconcile(ctx, c, &sg)
+	require.NoError(t, err)
+	changes, err := reconciler.Diff(ctx, c, name, nil)
+	require.NoError(t, err)
+	require.Empty(t, changes, "the first reconcile leaves nothing to do")
+
+	before := resourceVersions(t, c, sg.Namespace)
+	require.NotEmpty(t, before)
+	_, err = Reconcile(ctx, c, &sg)
+	require.NoError(t, err)
+	require.Equal(t, before, resourceVersions(t, c, sg.Namespace), "the second reconcile changes nothing")
+
+	// The spec is validated like the controller does, but the error is
+	// returned rather than only recorded in the status.
+	sg.Spec.GitServer.Replicas = -1
+	_, err = Reconcile(ctx, c, &sg)
+	require.ErrorContains(t, err, "invalid spec")
+}
+
+func TestRenderOnly(t *testing.T) {
+	sg := config.NewDefaultConfig()
+	_, err := RenderOnly(context.Background(), &sg)
+	require.Error(t, err, "a name and namespace are required")
+
+	sg.Name = "sg"
+	sg.Namespace = "embedded"
+	sg.Spec.RequestedVersion = "5.3.9104"
+	objs, err := RenderOnly(context.Background(), &sg)
+	require.NoError(t, err)
+	var deployments []string
+	for _, obj := range objs {
+		require.Equal(t, "embedded", obj.GetNamespace())
+		if obj.GetObjectKind().GroupVersionKind().Kind == "Deployment" {
+			deployments = append(deployments, obj.GetName())
+		}
+	}
+	require.Contains(t, deployments, "repo-updater")
+}
+
+// resourceVersions returns the resource versions of the objects that the
+// appliance manages in namespace, other than its ConfigMap, which records the
+// status.
+func resourceVersions(t *testing.T, c client.Client, namespace string) map[string]string {
+	t.Helper()
+	versions := map[string]string{}
+	for kind, list := range map[string]client.ObjectList{
+		"Deployment":            &appsv1.DeploymentList{},
+		"StatefulSet":           &appsv1.StatefulSetList{},
+		"ConfigMap":             &corev1.ConfigMapList{},
+		"Secret":                &corev1.SecretList{},
+		"Service":               &corev1.ServiceList{},
+		"ServiceAccount":        &corev1.ServiceAccountList{},
+		"PersistentVolumeClaim": &corev1.PersistentVolumeClaimList{},
+	} {
+		require.NoError(t, c.List(context.Background(), list, client.InNamespace(namespace)))
+		items, err := meta.ExtractList(list)
+		require.NoError(t, err)
+		for _, item := range items {
+			obj := item.(client.Object)
+			if _, ok := obj.GetAnnotations()[config.AnnotationKeyManaged]; ok {
+				continue
+			}
+			versions[kind+"/"+obj.GetName()] = obj.GetResourceVersion()
+		}
+	}
+	return versions
+}
+
+func kubebuilderAssetPath(t *testing.T) string {
+	t.Helper()
+	if os.Getenv("BAZEL_TEST") == "" {
+		// If we're using `go test`, which can be convenient for local dev, we
+		// expect setup-envtest to be present on the developer machine.
+		setupEnvTestCmd := exec.Command("setup-envtest", "use", "1.28.0", "--bin-dir", "/tmp/envtest", "-p", "path")
+		var envtestOut bytes.Buffer
+		setupEnvTestCmd.Stdout = &envtestOut
+		require.NoError(t, setupEnvTestCmd.Run(), "Did you remember to `go install sigs.k8s.io/controller-runtime/tools/setup-envtest@latest`?")
+		return strings.TrimSpace(envtestOut.String())
+	}
+
+	assetPaths := strings.Split(os.Getenv("KUBEBUILDER_ASSET_PATHS"), " ")
+	require.Greater(t, len(assetPaths), 0)
+	arbAssetPath, err := runfiles.Rlocation(assetPaths[0])
+	require.NoError(t, err)
+	return filepath.Dir(arbAssetPath)
+}
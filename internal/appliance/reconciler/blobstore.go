@@ -1,20 +1,45 @@
 package reconciler
 
 import (
+	"bufio"
 	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/configmap"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// blobstoreMetricsPort is the port that the retention sidecar serves the
+	// usage of the blobstore volume on.
+	blobstoreMetricsPort = 9001
+
+	// blobstoreRetentionInterval is how often the retention sidecar deletes
+	// expired objects.
+	blobstoreRetentionInterval = 10 * time.Minute
+
+	// diskUsagePollInterval is how often the appliance checks the usage of
+	// the blobstore volume once everything has rolled out.
+	diskUsagePollInterval = 5 * time.Minute
 )
 
 func (r *Reconciler) reconcileBlobstore(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
@@ -26,6 +51,10 @@ func (r *Reconciler) reconcileBlobstore(ctx context.Context, sg *config.Sourcegr
 		return err
 	}
 
+	if err := r.reconcileBlobstoreRetentionConfigMap(ctx, sg, owner); err != nil {
+		return err
+	}
+
 	if err := r.reconcileBlobstoreDeployments(ctx, sg, owner); err != nil {
 		return err
 	}
@@ -34,7 +63,7 @@ func (r *Reconciler) reconcileBlobstore(ctx context.Context, sg *config.Sourcegr
 }
 
 func buildBlobstorePersistentVolumeClaim(sg *config.Sourcegraph) (corev1.PersistentVolumeClaim, error) {
-	return pvc.NewPersistentVolumeClaim("blobstore", sg.Namespace, sg.Spec.Blobstore)
+	return pvc.NewPersistentVolumeClaim(sg.Spec.ObjectName("blobstore"), sg.Namespace, sg.Spec.Blobstore)
 }
 
 func (r *Reconciler) reconcileBlobstorePersistentVolumeClaims(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
@@ -43,13 +72,13 @@ func (r *Reconciler) reconcileBlobstorePersistentVolumeClaims(ctx context.Contex
 		return err
 	}
 
-	return reconcileObject(ctx, r, sg.Spec.Blobstore, &p, &corev1.PersistentVolumeClaim{}, sg, owner)
+	return reconcileObject(ctx, r, bundledBlobstoreConfig{sg.Spec.Blobstore}, &p, &corev1.PersistentVolumeClaim{}, sg, owner)
 }
 
 func buildBlobstoreService(sg *config.Sourcegraph) corev1.Service {
 	name := "blobstore"
 
-	s := service.NewService(name, sg.Namespace, sg.Spec.Blobstore)
+	s := service.NewService(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.Blobstore)
 	s.Spec.Ports = []corev1.ServicePort{
 		{
 			Name:       name,
@@ -57,8 +86,15 @@ func buildBlobstoreService(sg *config.Sourcegraph) corev1.Service {
 			TargetPort: intstr.FromString(name),
 		},
 	}
+	if sg.Spec.Blobstore.HasRetention() {
+		s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{
+			Name:       "metrics",
+			Port:       blobstoreMetricsPort,
+			TargetPort: intstr.FromString("metrics"),
+		})
+	}
 	s.Spec.Selector = map[string]string{
-		"app": name,
+		"app": sg.Spec.ObjectName(name),
 	}
 
 	return s
@@ -66,10 +102,49 @@ func buildBlobstoreService(sg *config.Sourcegraph) corev1.Service {
 
 func (r *Reconciler) reconcileBlobstoreServices(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	s := buildBlobstoreService(sg)
-	return reconcileObject(ctx, r, sg.Spec.Blobstore, &s, &corev1.Service{}, sg, owner)
+	return reconcileObject(ctx, r, bundledBlobstoreConfig{sg.Spec.Blobstore}, &s, &corev1.Service{}, sg, owner)
+}
+
+// buildBlobstoreRetentionConfigMap returns the ConfigMap of the retention
+// sidecar: its script, and its rules, one line per bucket or prefix with the
+// number of minutes its objects are kept.
+func buildBlobstoreRetentionConfigMap(sg *config.Sourcegraph) (corev1.ConfigMap, error) {
+	cm := configmap.NewConfigMap(sg.Spec.ObjectName("blobstore-retention"), sg.Namespace)
+	if sg.Spec.Blobstore.Retention == nil {
+		return cm, nil
+	}
+
+	ttls := sg.Spec.Blobstore.Retention.GetTTLs()
+	prefixes := make([]string, 0, len(ttls))
+	for prefix := range ttls {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	var rules strings.Builder
+	for _, prefix := range prefixes {
+		ttl, err := time.ParseDuration(ttls[prefix])
+		if err != nil {
+			return corev1.ConfigMap{}, errors.Wrapf(err, "parsing TTL of %s", prefix)
+		}
+		fmt.Fprintf(&rules, "%s %d\n", prefix, int64(ttl.Minutes()))
+	}
+	cm.Data = map[string]string{
+		"retention.sh": string(config.BlobstoreRetentionScript),
+		"rules":        rules.String(),
+	}
+	return cm, nil
 }
 
-func buildBlobstoreDeployment(sg *config.Sourcegraph) (appsv1.Deployment, error) {
+func (r *Reconciler) reconcileBlobstoreRetentionConfigMap(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cm, err := buildBlobstoreRetentionConfigMap(sg)
+	if err != nil {
+		return err
+	}
+	return reconcileObject(ctx, r, blobstoreRetentionConfig{sg.Spec.Blobstore}, &cm, &corev1.ConfigMap{}, sg, owner)
+}
+
+func (r *Reconciler) buildBlobstoreDeployment(sg *config.Sourcegraph, owner client.Object) (appsv1.Deployment, error) {
 	name := "blobstore"
 
 	containerPorts := []corev1.ContainerPort{{
@@ -120,30 +195,180 @@ func buildBlobstoreDeployment(sg *config.Sourcegraph) (appsv1.Deployment, error)
 			Name: "blobstore-data",
 			VolumeSource: corev1.VolumeSource{
 				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-					ClaimName: "blobstore",
+					ClaimName: sg.Spec.ObjectName("blobstore"),
 				},
 			},
 		},
 	}
 
-	podTemplate := pod.NewPodTemplate(name, sg.Spec.Blobstore)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), sg.Spec.Blobstore)
 	podTemplate.Template.Spec.Containers = []corev1.Container{defaultContainer}
 	podTemplate.Template.Spec.Volumes = podVolumes
 
+	if retention := sg.Spec.Blobstore.Retention; sg.Spec.Blobstore.HasRetention() {
+		retentionImage, err := config.GetDefaultImage(sg, "alpine")
+		if err != nil {
+			return appsv1.Deployment{}, err
+		}
+		retentionContainer := container.NewContainer("retention", sg.Spec.Blobstore, config.ContainerConfig{
+			Image: retentionImage,
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10m"),
+					corev1.ResourceMemory: resource.MustParse("50M"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("50M"),
+				},
+			},
+		})
+		retentionContainer.Command = []string{"/bin/sh", "/retention/retention.sh"}
+		retentionContainer.Env = append(retentionContainer.Env,
+			corev1.EnvVar{Name: "RULES_FILE", Value: "/retention/rules"},
+			corev1.EnvVar{Name: "HIGH_WATER_MARK_PERCENT", Value: strconv.Itoa(int(retention.GetHighWaterMarkPercent()))},
+			corev1.EnvVar{Name: "METRICS_PORT", Value: strconv.Itoa(blobstoreMetricsPort)},
+			corev1.EnvVar{Name: "CHECK_INTERVAL_SECONDS", Value: strconv.Itoa(int(blobstoreRetentionInterval.Seconds()))},
+		)
+		retentionContainer.Ports = []corev1.ContainerPort{{Name: "metrics", ContainerPort: blobstoreMetricsPort}}
+		retentionContainer.VolumeMounts = []corev1.VolumeMount{
+			{Name: "blobstore-data", MountPath: "/data"},
+			{Name: "retention", MountPath: "/retention"},
+			{Name: "retention-tmp", MountPath: "/tmp"},
+		}
+		podTemplate.Template.Spec.Containers = append(podTemplate.Template.Spec.Containers, retentionContainer)
+		podTemplate.Template.Spec.Volumes = append(podTemplate.Template.Spec.Volumes,
+			pod.NewVolumeFromConfigMap("retention", sg.Spec.ObjectName("blobstore-retention")),
+			pod.NewVolumeEmptyDir("retention-tmp"))
+	}
+
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, sg.Spec.Blobstore, owner); err != nil {
+		return appsv1.Deployment{}, err
+	}
+
 	defaultDeployment := deployment.NewDeployment(
-		name,
+		sg.Spec.ObjectName(name),
 		sg.Namespace,
 		sg.Spec.RequestedVersion,
 	)
+	if err := applyDeploymentStrategy(&defaultDeployment, sg.Spec.Blobstore); err != nil {
+		return appsv1.Deployment{}, err
+	}
 	defaultDeployment.Spec.Template = podTemplate.Template
 
 	return defaultDeployment, nil
 }
 
 func (r *Reconciler) reconcileBlobstoreDeployments(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	d, err := buildBlobstoreDeployment(sg)
+	d, err := r.buildBlobstoreDeployment(sg, owner)
 	if err != nil {
 		return err
 	}
-	return reconcileObject(ctx, r, sg.Spec.Blobstore, &d, &appsv1.Deployment{}, sg, owner)
+	return reconcileObject(ctx, r, bundledBlobstoreConfig{sg.Spec.Blobstore}, &d, &appsv1.Deployment{}, sg, owner)
+}
+
+// bundledBlobstoreConfig wraps a BlobstoreSpec for the resources that make up
+// the in-cluster blobstore, which must not exist when external object storage
+// is configured.
+type bundledBlobstoreConfig struct {
+	config.BlobstoreSpec
+}
+
+func (c bundledBlobstoreConfig) IsDisabled() bool {
+	return c.Disabled || c.IsExternal()
+}
+
+// blobstoreRetentionConfig wraps a BlobstoreSpec for the ConfigMap of the
+// retention sidecar, which only exists if the bundled blobstore has a
+// retention config.
+type blobstoreRetentionConfig struct {
+	config.BlobstoreSpec
+}
+
+func (c blobstoreRetentionConfig) IsDisabled() bool {
+	return !c.HasRetention()
+}
+
+// setBlobstoreDiskSpaceCondition sets the DiskSpaceAvailable condition of the
+// blobstore from the usage of its volume that the retention sidecar reports.
+func (r *Reconciler) setBlobstoreDiskSpaceCondition(ctx context.Context, svc *config.ServiceStatus, sg *config.Sourcegraph) {
+	if !sg.Spec.Blobstore.HasRetention() {
+		meta.RemoveStatusCondition(&svc.Conditions, config.ConditionDiskSpaceAvailable)
+		return
+	}
+
+	capacity, used, err := r.fetchBlobstoreUsage(ctx, sg)
+	if err != nil {
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:    config.ConditionDiskSpaceAvailable,
+			Status:  metav1.ConditionUnknown,
+			Reason:  config.ReasonDiskUsageUnknown,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	percent := used / capacity * 100
+	message := fmt.Sprintf("The blobstore volume is %.0f%% full, %.1f of %.1f GiB are used.", percent, used/(1<<30), capacity/(1<<30))
+	if threshold := sg.Spec.Blobstore.Retention.GetWarningThresholdPercent(); percent > float64(threshold) {
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:    config.ConditionDiskSpaceAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  config.ReasonDiskUsageHigh,
+			Message: fmt.Sprintf("%s Writes fail once it is full, expire objects sooner with spec.blobstore.retention.ttls or grow it.", message),
+		})
+		return
+	}
+	meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+		Type:    config.ConditionDiskSpaceAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  config.ReasonDiskUsageNormal,
+		Message: message,
+	})
+}
+
+// fetchBlobstoreUsage returns the capacity and used bytes of the blobstore
+// volume from the metrics of the retention sidecar.
+func (r *Reconciler) fetchBlobstoreUsage(ctx context.Context, sg *config.Sourcegraph) (capacity, used float64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("http://%s.%s.svc:%d/metrics", sg.Spec.ObjectName("blobstore"), sg.Namespace, blobstoreMetricsPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "fetching blobstore metrics")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, errors.Newf("fetching blobstore metrics: unexpected status %s", resp.Status)
+	}
+
+	metrics := map[string]float64{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if value, err := strconv.ParseFloat(fields[1], 64); err == nil {
+			metrics[fields[0]] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, errors.Wrap(err, "reading blobstore metrics")
+	}
+
+	capacity, used = metrics["blobstore_volume_capacity_bytes"], metrics["blobstore_volume_used_bytes"]
+	if capacity <= 0 {
+		return 0, 0, errors.New("the blobstore hasn't reported the capacity of its volume yet")
+	}
+	return capacity, used, nil
 }
@@ -1,5 +1,20 @@
 package reconciler
 
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
 // Simple test cases in which we want to assert that a given configmap causes a
 // certain set of resources to be deployed can go here. sg and golden fixtures
 // are in testdata/ and named after the test case name.
@@ -10,6 +25,9 @@ func (suite *ApplianceTestSuite) TestDeployBlobstore() {
 		{
 			name: "blobstore/default",
 		},
+		{
+			name: "blobstore/with-retention",
+		},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -17,3 +35,45 @@ func (suite *ApplianceTestSuite) TestDeployBlobstore() {
 		})
 	}
 }
+
+func TestSetBlobstoreDiskSpaceCondition(t *testing.T) {
+	ctx := context.Background()
+	var metrics string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" || metrics == "" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, metrics)
+	}))
+	defer srv.Close()
+
+	// Requests to the blobstore Service are sent to the test server instead.
+	dialer := &net.Dialer{}
+	r := &Reconciler{HTTPClient: &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, srv.Listener.Addr().String())
+		},
+	}}}
+	sg := &config.Sourcegraph{}
+	sg.Namespace = "sourcegraph"
+	sg.Spec.Blobstore.Retention = &config.BlobstoreRetentionConfig{}
+	var svc config.ServiceStatus
+
+	r.setBlobstoreDiskSpaceCondition(ctx, &svc, sg)
+	require.Equal(t, metav1.ConditionUnknown, meta.FindStatusCondition(svc.Conditions, config.ConditionDiskSpaceAvailable).Status)
+
+	metrics = "# TYPE blobstore_volume_capacity_bytes gauge\nblobstore_volume_capacity_bytes 1.073741824e+11\nblobstore_volume_used_bytes 4.294967296e+10\n"
+	r.setBlobstoreDiskSpaceCondition(ctx, &svc, sg)
+	requireCondition(t, svc.Conditions, config.ConditionDiskSpaceAvailable, metav1.ConditionTrue, "The blobstore volume is 40% full, 40.0 of 100.0 GiB are used.")
+
+	metrics = "blobstore_volume_capacity_bytes 1.073741824e+11\nblobstore_volume_used_bytes 9.663676416e+10\n"
+	r.setBlobstoreDiskSpaceCondition(ctx, &svc, sg)
+	requireCondition(t, svc.Conditions, config.ConditionDiskSpaceAvailable, metav1.ConditionFalse,
+		"The blobstore volume is 90% full, 90.0 of 100.0 GiB are used. Writes fail once it is full, expire objects sooner with spec.blobstore.retention.ttls or grow it.")
+
+	// Without retention, there is no sidecar to report the usage.
+	sg.Spec.Blobstore.Retention = nil
+	r.setBlobstoreDiskSpaceCondition(ctx, &svc, sg)
+	require.Nil(t, meta.FindStatusCondition(svc.Conditions, config.ConditionDiskSpaceAvailable))
+}
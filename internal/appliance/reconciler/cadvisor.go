@@ -3,6 +3,8 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -13,7 +15,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/daemonset"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
@@ -53,33 +54,62 @@ func (r *Reconciler) reconcileCadvisorDaemonset(ctx context.Context, sg *config.
 		"--store_container_labels=false",
 		"--whitelisted_container_labels=io.kubernetes.container.name,io.kubernetes.pod.name,io.kubernetes.pod.namespace,io.kubernetes.pod.uid",
 	}
-	ctr.VolumeMounts = []corev1.VolumeMount{
-		{Name: "rootfs", MountPath: "/rootfs", ReadOnly: true},
-		{Name: "var-run", MountPath: "/var/run", ReadOnly: true},
-		{Name: "sys", MountPath: "/sys", ReadOnly: true},
-		{Name: "docker", MountPath: "/var/lib/docker", ReadOnly: true},
-		{Name: "disk", MountPath: "/dev/disk", ReadOnly: true},
-		{Name: "kmsg", MountPath: "/dev/kmsg", ReadOnly: true},
-	}
 	ctr.Ports = []corev1.ContainerPort{
 		{Name: "http", ContainerPort: 48080},
 	}
-	ctr.SecurityContext = &corev1.SecurityContext{
-		Privileged: pointers.Ptr(true),
-	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
-	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
-	podTemplate.Template.Spec.ServiceAccountName = name
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
 	podTemplate.Template.Spec.AutomountServiceAccountToken = pointers.Ptr(false)
-	podTemplate.Template.Spec.Volumes = []corev1.Volume{
-		pod.NewVolumeHostPath("rootfs", "/"),
-		pod.NewVolumeHostPath("var-run", "/var/run"),
-		pod.NewVolumeHostPath("sys", "/sys"),
-		pod.NewVolumeHostPath("docker", "/var/lib/docker"),
-		pod.NewVolumeHostPath("disk", "/dev/disk"),
-		pod.NewVolumeHostPath("kmsg", "/dev/kmsg"),
+	podTemplate.Template.Spec.RuntimeClassName = cfg.RuntimeClassName
+	if len(cfg.NodeSelector) > 0 {
+		nodeSelector := maps.Clone(podTemplate.Template.Spec.NodeSelector)
+		if nodeSelector == nil {
+			nodeSelector = map[string]string{}
+		}
+		maps.Copy(nodeSelector, cfg.NodeSelector)
+		podTemplate.Template.Spec.NodeSelector = nodeSelector
+	}
+
+	// By default, cadvisor finds docker's socket and data directory by itself
+	// under /var/run and /var/lib/docker. Neither exists on nodes that only
+	// run containerd, e.g. Bottlerocket ones, so they aren't mounted there.
+	socket := cfg.SocketPath()
+	socketMount := cadvisorHostMount{name: "runtime-socket", hostPath: socket, mountPath: socket}
+	var mounts []cadvisorHostMount
+	if cfg.ContainerRuntime == config.CadvisorContainerRuntimeContainerd {
+		ctr.Args = append(ctr.Args, "--containerd="+socket)
+		mounts = []cadvisorHostMount{
+			{name: "rootfs", hostPath: "/", mountPath: "/rootfs"},
+			{name: "sys", hostPath: "/sys", mountPath: "/sys"},
+			{name: "disk", hostPath: "/dev/disk", mountPath: "/dev/disk"},
+			{name: "kmsg", hostPath: "/dev/kmsg", mountPath: "/dev/kmsg"},
+			socketMount,
+		}
+	} else {
+		mounts = []cadvisorHostMount{
+			{name: "rootfs", hostPath: "/", mountPath: "/rootfs"},
+			{name: "var-run", hostPath: "/var/run", mountPath: "/var/run"},
+			{name: "sys", hostPath: "/sys", mountPath: "/sys"},
+			{name: "docker", hostPath: "/var/lib/docker", mountPath: "/var/lib/docker"},
+			{name: "disk", hostPath: "/dev/disk", mountPath: "/dev/disk"},
+			{name: "kmsg", hostPath: "/dev/kmsg", mountPath: "/dev/kmsg"},
+		}
+		if cfg.RuntimeSocketPath != "" {
+			ctr.Args = append(ctr.Args, "--docker=unix://"+socket)
+			if !strings.HasPrefix(socket, "/var/run/") {
+				mounts = append(mounts, socketMount)
+			}
+		}
 	}
+	for i, hostPath := range cfg.ExtraHostPaths {
+		mounts = append(mounts, cadvisorHostMount{name: fmt.Sprintf("extra-host-path-%d", i), hostPath: hostPath, mountPath: hostPath})
+	}
+	for _, m := range mounts {
+		ctr.VolumeMounts = append(ctr.VolumeMounts, corev1.VolumeMount{Name: m.name, MountPath: m.mountPath, ReadOnly: true})
+		podTemplate.Template.Spec.Volumes = append(podTemplate.Template.Spec.Volumes, pod.NewVolumeHostPath(m.name, m.hostPath))
+	}
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
 	podTemplate.Template.Spec.SecurityContext = nil
 
 	// Usually we set the prometheus scrape annotations on a Service (and scrape
@@ -97,14 +127,24 @@ func (r *Reconciler) reconcileCadvisorDaemonset(ctx context.Context, sg *config.
 		podTemplate.Template.Annotations = annotations
 	}
 
-	ds := daemonset.New(name, sg.Namespace, sg.Spec.RequestedVersion)
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	ds := daemonset.New(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
 	ds.Spec.Template = podTemplate.Template
 
 	return reconcileObject(ctx, r, cfg, &ds, &appsv1.DaemonSet{}, sg, owner)
 }
 
+// cadvisorHostMount is a path on the node that is mounted read-only into
+// cadvisor.
+type cadvisorHostMount struct {
+	name      string
+	hostPath  string
+	mountPath string
+}
+
 func (r *Reconciler) reconcileCadvisorServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.Cadvisor
-	sa := serviceaccount.NewServiceAccount("cadvisor", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, cfg, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("cadvisor"), sg.Spec.Cadvisor)
 }
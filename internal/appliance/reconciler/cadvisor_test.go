@@ -5,6 +5,9 @@ func (suite *ApplianceTestSuite) TestDeployCadvisor() {
 		name string
 	}{
 		{name: "cadvisor/default"},
+		{name: "cadvisor/with-bottlerocket"},
+		{name: "cadvisor/with-containerd"},
+		{name: "cadvisor/with-image-pull-secrets"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -12,3 +15,9 @@ func (suite *ApplianceTestSuite) TestDeployCadvisor() {
 		})
 	}
 }
+
+func (suite *ApplianceTestSuite) TestCadvisorDeletedWhenDisabled() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("cadvisor/with-containerd")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "standard/everything-disabled")
+	suite.makeGoldenAssertions(namespace, "cadvisor/subsequent-disable")
+}
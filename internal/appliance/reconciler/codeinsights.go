@@ -16,7 +16,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/secret"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/statefulset"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
@@ -31,15 +30,18 @@ func (r *Reconciler) reconcileCodeInsights(ctx context.Context, sg *config.Sourc
 	if err := r.reconcileCodeInsightsConfigMap(ctx, sg, owner); err != nil {
 		return err
 	}
-	if err := r.reconcileCodeInsightsSecret(ctx, sg, owner); err != nil {
-		return err
-	}
 	if err := r.reconcileCodeInsightsService(ctx, sg, owner); err != nil {
 		return err
 	}
 	if err := r.reconcileCodeInsightsServiceAccount(ctx, sg, owner); err != nil {
 		return err
 	}
+	if err := r.reconcileDatabaseBackups(ctx, sg, owner, "codeinsights-db", sg.Spec.ObjectName("codeinsights-db-auth"), sg.Spec.CodeInsights, sg.Spec.CodeInsights.Backup); err != nil {
+		return err
+	}
+	if err := r.reconcileConnectionPooler(ctx, sg, owner, "codeinsights-db", sg.Spec.ObjectName("codeinsights-db-auth"), sg.Spec.CodeInsights, sg.Spec.CodeInsights.ConnectionPooler); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -65,14 +67,8 @@ func (r *Reconciler) reconcileCodeInsightsStatefulSet(ctx context.Context, sg *c
 			},
 		},
 	})
-	ctr.SecurityContext = &corev1.SecurityContext{
-		RunAsUser:                pointers.Ptr[int64](70),
-		RunAsGroup:               pointers.Ptr[int64](70),
-		AllowPrivilegeEscalation: pointers.Ptr(false),
-		ReadOnlyRootFilesystem:   pointers.Ptr(true),
-	}
 
-	databaseSecretName := "codeinsights-db-auth"
+	databaseSecretName := sg.Spec.ObjectName("codeinsights-db-auth")
 	ctr.Env = append(ctr.Env, container.EnvVarsPostgres(databaseSecretName)...)
 	ctr.Env = append(
 		ctr.Env,
@@ -104,58 +100,32 @@ func (r *Reconciler) reconcileCodeInsightsStatefulSet(ctx context.Context, sg *c
 			},
 		},
 	})
-	initCtr.SecurityContext = &corev1.SecurityContext{
-		RunAsUser:                pointers.Ptr[int64](70),
-		RunAsGroup:               pointers.Ptr[int64](70),
-		AllowPrivilegeEscalation: pointers.Ptr(false),
-		ReadOnlyRootFilesystem:   pointers.Ptr(true),
-	}
 	initCtr.VolumeMounts = []corev1.VolumeMount{{Name: "disk", MountPath: "/var/lib/postgresql/data"}}
 	initCtr.Command = []string{"sh", "-c", "if [ -d /var/lib/postgresql/data/pgdata ]; then chmod 750 /var/lib/postgresql/data/pgdata; fi"}
 
-	pgExpCtrImage, err := config.GetDefaultImage(sg, "pgsql-exporter")
-	if err != nil {
-		return err
-	}
-
-	pgExpCtr := container.NewContainer("pgsql-exporter", cfg, config.ContainerConfig{
-		Image: pgExpCtrImage,
-		Resources: &corev1.ResourceRequirements{
-			Requests: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("10m"),
-				corev1.ResourceMemory: resource.MustParse("50Mi"),
-			},
-			Limits: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("10m"),
-				corev1.ResourceMemory: resource.MustParse("50Mi"),
-			},
-		},
-	})
-	pgExpCtr.Env = append(pgExpCtr.Env, container.EnvVarsPostgresExporter(databaseSecretName)...)
-	pgExpCtr.Env = append(pgExpCtr.Env, corev1.EnvVar{
-		Name: "PG_EXPORTER_EXTEND_QUERY_PATH", Value: "/config/code_insights_queries.yaml",
-	})
-
 	podVolumes := []corev1.Volume{
-		pod.NewVolumeFromPVC("disk", name),
-		pod.NewVolumeFromConfigMap("codeinsights-conf", "codeinsights-db-conf"),
+		pod.NewVolumeFromPVC("disk", sg.Spec.ObjectName(name)),
+		pod.NewVolumeFromConfigMap("codeinsights-conf", sg.Spec.ObjectName("codeinsights-db-conf")),
 		pod.NewVolumeEmptyDir("lockdir"),
 	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
 	podTemplate.Template.Spec.TerminationGracePeriodSeconds = pointers.Ptr[int64](120)
 	podTemplate.Template.Spec.InitContainers = []corev1.Container{initCtr}
-	podTemplate.Template.Spec.Containers = []corev1.Container{ctr, pgExpCtr}
-	podTemplate.Template.Spec.ServiceAccountName = name
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
 	podTemplate.Template.Spec.Volumes = podVolumes
-	podTemplate.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
-		FSGroup:             pointers.Ptr[int64](70),
-		RunAsUser:           pointers.Ptr[int64](70),
-		RunAsGroup:          pointers.Ptr[int64](70),
-		FSGroupChangePolicy: pointers.Ptr(corev1.FSGroupChangeOnRootMismatch),
+
+	if err := applyPostgresExporter(&podTemplate.Template, sg, cfg, cfg.Exporter, databaseSecretName, "/config/code_insights_queries.yaml", "50Mi"); err != nil {
+		return err
 	}
 
-	sset := statefulset.NewStatefulSet(name, sg.Namespace, sg.Spec.RequestedVersion)
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	sset := statefulset.NewStatefulSet(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	applyStatefulSetStrategy(&sset, sg.Spec.CodeInsights)
 	sset.Spec.Template = podTemplate.Template
 
 	return reconcileObject(ctx, r, sg.Spec.CodeInsights, &sset, &appsv1.StatefulSet{}, sg, owner)
@@ -163,7 +133,7 @@ func (r *Reconciler) reconcileCodeInsightsStatefulSet(ctx context.Context, sg *c
 
 func (r *Reconciler) reconcileCodeInsightsPersistentVolumeClaim(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	cfg := sg.Spec.CodeInsights
-	p, err := pvc.NewPersistentVolumeClaim("codeinsights-db", sg.Namespace, cfg)
+	p, err := pvc.NewPersistentVolumeClaim(sg.Spec.ObjectName("codeinsights-db"), sg.Namespace, cfg)
 	if err != nil {
 		return err
 	}
@@ -171,18 +141,18 @@ func (r *Reconciler) reconcileCodeInsightsPersistentVolumeClaim(ctx context.Cont
 }
 
 func (r *Reconciler) reconcileCodeInsightsConfigMap(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cm := configmap.NewConfigMap("codeinsights-db-conf", sg.Namespace)
+	cm := configmap.NewConfigMap(sg.Spec.ObjectName("codeinsights-db-conf"), sg.Namespace)
 	cm.Data = map[string]string{"postgresql.conf": string(config.CodeInsightsConfig)}
 
 	return reconcileObject(ctx, r, sg.Spec.CodeInsights, &cm, &corev1.ConfigMap{}, sg, owner)
 }
 
 func (r *Reconciler) reconcileCodeInsightsSecret(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	scrt := secret.NewSecret("codeinsights-db-auth", sg.Namespace, sg.Spec.RequestedVersion)
+	scrt := secret.NewSecret(sg.Spec.ObjectName("codeinsights-db-auth"), sg.Namespace, sg.Spec.RequestedVersion)
 
 	cn := sg.Spec.CodeInsights.DatabaseConnection
 	scrt.Data = map[string][]byte{
-		"host":     []byte(cn.Host),
+		"host":     []byte(sg.Spec.ServiceHost(cn.Host, "codeinsights-db")),
 		"port":     []byte(cn.Port),
 		"user":     []byte(cn.User),
 		"password": []byte(cn.Password),
@@ -194,15 +164,13 @@ func (r *Reconciler) reconcileCodeInsightsSecret(ctx context.Context, sg *config
 
 func (r *Reconciler) reconcileCodeInsightsService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	name := "codeinsights-db"
-	svc := service.NewService(name, sg.Namespace, sg.Spec.CodeInsights)
+	svc := service.NewService(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.CodeInsights)
 	svc.Spec.Ports = []corev1.ServicePort{{Name: name, TargetPort: intstr.FromString(name), Port: 5432}}
-	svc.Spec.Selector = map[string]string{"app": name}
+	svc.Spec.Selector = map[string]string{"app": sg.Spec.ObjectName(name)}
 
 	return reconcileObject(ctx, r, sg.Spec.CodeInsights, &svc, &corev1.Service{}, sg, owner)
 }
 
 func (r *Reconciler) reconcileCodeInsightsServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.CodeInsights
-	sa := serviceaccount.NewServiceAccount("codeinsights-db", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, sg.Spec.CodeInsights, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("codeinsights-db"), sg.Spec.CodeInsights)
 }
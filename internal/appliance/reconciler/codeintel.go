@@ -16,7 +16,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/secret"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/statefulset"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
@@ -31,15 +30,18 @@ func (r *Reconciler) reconcileCodeIntel(ctx context.Context, sg *config.Sourcegr
 	if err := r.reconcileCodeIntelConfigMap(ctx, sg, owner); err != nil {
 		return err
 	}
-	if err := r.reconcileCodeIntelSecret(ctx, sg, owner); err != nil {
-		return err
-	}
 	if err := r.reconcileCodeIntelService(ctx, sg, owner); err != nil {
 		return err
 	}
 	if err := r.reconcileCodeIntelServiceAccount(ctx, sg, owner); err != nil {
 		return err
 	}
+	if err := r.reconcileDatabaseBackups(ctx, sg, owner, "codeintel-db", sg.Spec.ObjectName("codeintel-db-auth"), sg.Spec.CodeIntel, sg.Spec.CodeIntel.Backup); err != nil {
+		return err
+	}
+	if err := r.reconcileConnectionPooler(ctx, sg, owner, "codeintel-db", sg.Spec.ObjectName("codeintel-db-auth"), sg.Spec.CodeIntel, sg.Spec.CodeIntel.ConnectionPooler); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -65,14 +67,8 @@ func (r *Reconciler) reconcileCodeIntelStatefulSet(ctx context.Context, sg *conf
 			},
 		},
 	})
-	ctr.SecurityContext = &corev1.SecurityContext{
-		RunAsUser:                pointers.Ptr[int64](999),
-		RunAsGroup:               pointers.Ptr[int64](999),
-		AllowPrivilegeEscalation: pointers.Ptr(false),
-		ReadOnlyRootFilesystem:   pointers.Ptr(true),
-	}
 
-	databaseSecretName := "codeintel-db-auth"
+	databaseSecretName := sg.Spec.ObjectName("codeintel-db-auth")
 	ctr.Env = append(ctr.Env, container.EnvVarsPostgres(databaseSecretName)...)
 	ctr.Ports = []corev1.ContainerPort{{Name: "pgsql", ContainerPort: 5432}}
 	ctr.LivenessProbe = &corev1.Probe{
@@ -123,75 +119,43 @@ func (r *Reconciler) reconcileCodeIntelStatefulSet(ctx context.Context, sg *conf
 			},
 		},
 	})
-	initCtr.SecurityContext = &corev1.SecurityContext{
-		RunAsUser:                pointers.Ptr[int64](999),
-		RunAsGroup:               pointers.Ptr[int64](999),
-		AllowPrivilegeEscalation: pointers.Ptr(false),
-		ReadOnlyRootFilesystem:   pointers.Ptr(true),
-	}
 	initCtr.VolumeMounts = []corev1.VolumeMount{{Name: "disk", MountPath: "/data"}}
 	initCtr.Command = []string{"sh", "-c", "if [ -d /data/pgdata-12 ]; then chmod 750 /data/pgdata-12; fi"}
 
-	pgExpCtrImage, err := config.GetDefaultImage(sg, "pgsql-exporter")
-	if err != nil {
-		return err
-	}
-
-	pgExpCtr := container.NewContainer("pgsql-exporter", cfg, config.ContainerConfig{
-		Image: pgExpCtrImage,
-		Resources: &corev1.ResourceRequirements{
-			Requests: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("10m"),
-				corev1.ResourceMemory: resource.MustParse("50M"),
-			},
-			Limits: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("10m"),
-				corev1.ResourceMemory: resource.MustParse("50M"),
-			},
-		},
-	})
-	pgExpCtr.SecurityContext = &corev1.SecurityContext{
-		RunAsUser:                pointers.Ptr[int64](999),
-		RunAsGroup:               pointers.Ptr[int64](999),
-		AllowPrivilegeEscalation: pointers.Ptr(false),
-		ReadOnlyRootFilesystem:   pointers.Ptr(true),
-	}
-	pgExpCtr.Env = append(pgExpCtr.Env, container.EnvVarsPostgresExporter(databaseSecretName)...)
-	pgExpCtr.Env = append(pgExpCtr.Env, corev1.EnvVar{
-		Name: "PG_EXPORTER_EXTEND_QUERY_PATH", Value: "/config/code_intel_queries.yaml",
-	})
-
 	podVolumes := []corev1.Volume{
 		pod.NewVolumeEmptyDir("lockdir"),
 		{Name: "disk", VolumeSource: corev1.VolumeSource{
 			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-				ClaimName: "codeintel-db",
+				ClaimName: sg.Spec.ObjectName("codeintel-db"),
 			},
 		}},
 		{Name: "pgsql-conf", VolumeSource: corev1.VolumeSource{
 			ConfigMap: &corev1.ConfigMapVolumeSource{
 				DefaultMode: pointers.Ptr[int32](0777),
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: "codeintel-db-conf",
+					Name: sg.Spec.ObjectName("codeintel-db-conf"),
 				},
 			},
 		}},
 	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
 	podTemplate.Template.Spec.TerminationGracePeriodSeconds = pointers.Ptr[int64](120)
 	podTemplate.Template.Spec.InitContainers = []corev1.Container{initCtr}
-	podTemplate.Template.Spec.Containers = []corev1.Container{ctr, pgExpCtr}
-	podTemplate.Template.Spec.ServiceAccountName = name
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
 	podTemplate.Template.Spec.Volumes = podVolumes
-	podTemplate.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
-		FSGroup:             pointers.Ptr[int64](999),
-		RunAsUser:           pointers.Ptr[int64](999),
-		RunAsGroup:          pointers.Ptr[int64](999),
-		FSGroupChangePolicy: pointers.Ptr(corev1.FSGroupChangeOnRootMismatch),
+
+	if err := applyPostgresExporter(&podTemplate.Template, sg, cfg, cfg.Exporter, databaseSecretName, "/config/code_intel_queries.yaml", "50M"); err != nil {
+		return err
 	}
 
-	sset := statefulset.NewStatefulSet(name, sg.Namespace, sg.Spec.RequestedVersion)
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	sset := statefulset.NewStatefulSet(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	applyStatefulSetStrategy(&sset, sg.Spec.CodeIntel)
 	sset.Spec.Template = podTemplate.Template
 
 	return reconcileObject(ctx, r, sg.Spec.CodeIntel, &sset, &appsv1.StatefulSet{}, sg, owner)
@@ -199,7 +163,7 @@ func (r *Reconciler) reconcileCodeIntelStatefulSet(ctx context.Context, sg *conf
 
 func (r *Reconciler) reconcileCodeIntelPersistentVolumeClaim(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	cfg := sg.Spec.CodeIntel
-	p, err := pvc.NewPersistentVolumeClaim("codeintel-db", sg.Namespace, cfg)
+	p, err := pvc.NewPersistentVolumeClaim(sg.Spec.ObjectName("codeintel-db"), sg.Namespace, cfg)
 	if err != nil {
 		return err
 	}
@@ -207,18 +171,18 @@ func (r *Reconciler) reconcileCodeIntelPersistentVolumeClaim(ctx context.Context
 }
 
 func (r *Reconciler) reconcileCodeIntelConfigMap(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cm := configmap.NewConfigMap("codeintel-db-conf", sg.Namespace)
+	cm := configmap.NewConfigMap(sg.Spec.ObjectName("codeintel-db-conf"), sg.Namespace)
 	cm.Data = map[string]string{"postgresql.conf": string(config.CodeIntelConfig)}
 
 	return reconcileObject(ctx, r, sg.Spec.CodeIntel, &cm, &corev1.ConfigMap{}, sg, owner)
 }
 
 func (r *Reconciler) reconcileCodeIntelSecret(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	scrt := secret.NewSecret("codeintel-db-auth", sg.Namespace, sg.Spec.RequestedVersion)
+	scrt := secret.NewSecret(sg.Spec.ObjectName("codeintel-db-auth"), sg.Namespace, sg.Spec.RequestedVersion)
 
 	cn := sg.Spec.CodeIntel.DatabaseConnection
 	scrt.Data = map[string][]byte{
-		"host":     []byte(cn.Host),
+		"host":     []byte(sg.Spec.ServiceHost(cn.Host, "codeintel-db")),
 		"port":     []byte(cn.Port),
 		"user":     []byte(cn.User),
 		"password": []byte(cn.Password),
@@ -229,15 +193,13 @@ func (r *Reconciler) reconcileCodeIntelSecret(ctx context.Context, sg *config.So
 }
 
 func (r *Reconciler) reconcileCodeIntelService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	svc := service.NewService("codeintel-db", sg.Namespace, sg.Spec.CodeIntel)
+	svc := service.NewService(sg.Spec.ObjectName("codeintel-db"), sg.Namespace, sg.Spec.CodeIntel)
 	svc.Spec.Ports = []corev1.ServicePort{{Name: "pgsql", TargetPort: intstr.FromString("pgsql"), Port: 5432}}
-	svc.Spec.Selector = map[string]string{"app": "codeintel-db"}
+	svc.Spec.Selector = map[string]string{"app": sg.Spec.ObjectName("codeintel-db")}
 
 	return reconcileObject(ctx, r, sg.Spec.CodeIntel, &svc, &corev1.Service{}, sg, owner)
 }
 
 func (r *Reconciler) reconcileCodeIntelServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.CodeIntel
-	sa := serviceaccount.NewServiceAccount("codeintel", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, sg.Spec.CodeIntel, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("codeintel"), sg.Spec.CodeIntel)
 }
@@ -0,0 +1,222 @@
+package reconciler
+
+import (
+	"context"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	connectionPoolerContainerName = "pgbouncer"
+	connectionPoolerPort          = 5432
+	connectionPoolerConfigDir     = "/etc/pgbouncer"
+
+	// connectionPoolerScript writes the config of PgBouncer from its env vars,
+	// since the password of the database is only in its auth Secret, and
+	// starts it. Quotes in the credentials are doubled, as the auth file
+	// requires. It must not contain "$(", which Kubernetes would expand as a
+	// reference to an env var.
+	connectionPoolerScript = `set -eu
+cat > /etc/pgbouncer/pgbouncer.ini <<EOF
+[databases]
+* = host=${PGHOST} port=${PGPORT}
+
+[pgbouncer]
+listen_addr = *
+listen_port = 5432
+unix_socket_dir =
+auth_type = md5
+auth_file = /etc/pgbouncer/userlist.txt
+pool_mode = ${POOL_MODE}
+default_pool_size = ${DEFAULT_POOL_SIZE}
+max_client_conn = ${MAX_CLIENT_CONN}
+ignore_startup_parameters = extra_float_digits
+EOF
+awk 'BEGIN {
+  user = ENVIRON["PGUSER"]; password = ENVIRON["PGPASSWORD"]
+  gsub(/"/, "\"\"", user); gsub(/"/, "\"\"", password)
+  printf "\"%s\" \"%s\"\n", user, password
+}' > /etc/pgbouncer/userlist.txt
+exec pgbouncer /etc/pgbouncer/pgbouncer.ini
+`
+)
+
+// connectionPoolerName returns the name of the objects of the connection
+// pooler of the database called name, before the name prefix is applied.
+func connectionPoolerName(name string) string { return name + "-pgbouncer" }
+
+// reconcileConnectionPooler manages the PgBouncer Deployment and Service in
+// front of the database called name, whose connection details are in
+// secretName. They are deleted when the pooler is disabled, which points the
+// services back at the database, since their env vars are rendered from the
+// same config.
+func (r *Reconciler) reconcileConnectionPooler(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name, secretName string, dbCfg config.StandardComponent, pooler *config.ConnectionPoolerSpec) error {
+	cfg := connectionPoolerConfig{StandardComponent: dbCfg, pooler: pooler}
+	if err := r.reconcileConnectionPoolerDeployment(ctx, sg, owner, name, secretName, cfg); err != nil {
+		return errors.Wrap(err, "reconciling connection pooler Deployment")
+	}
+	if err := r.reconcileConnectionPoolerService(ctx, sg, owner, name, cfg); err != nil {
+		return errors.Wrap(err, "reconciling connection pooler Service")
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileConnectionPoolerDeployment(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name, secretName string, cfg connectionPoolerConfig) error {
+	poolerName := connectionPoolerName(name)
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(poolerName), sg.Namespace, sg.Spec.RequestedVersion)
+	if cfg.IsDisabled() {
+		return reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner)
+	}
+
+	image, err := config.GetDefaultImage(sg, "pgbouncer")
+	if err != nil {
+		return err
+	}
+	ctr := container.NewContainer(connectionPoolerContainerName, cfg, config.ContainerConfig{
+		Image: image,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+		},
+	})
+	ctr.Command = []string{"sh", "-c", connectionPoolerScript}
+	ctr.Env = append(ctr.Env, container.EnvVarsPostgresClient("", secretName)...)
+	ctr.Env = append(ctr.Env,
+		corev1.EnvVar{Name: "POOL_MODE", Value: cfg.pooler.GetPoolMode()},
+		corev1.EnvVar{Name: "DEFAULT_POOL_SIZE", Value: strconv.Itoa(int(cfg.pooler.GetDefaultPoolSize()))},
+		corev1.EnvVar{Name: "MAX_CLIENT_CONN", Value: strconv.Itoa(int(cfg.pooler.GetMaxClientConnections()))},
+	)
+	ctr.Ports = []corev1.ContainerPort{{Name: "pgbouncer", ContainerPort: connectionPoolerPort}}
+	ctr.ReadinessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("pgbouncer")},
+		},
+		PeriodSeconds: 5,
+	}
+	ctr.LivenessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("pgbouncer")},
+		},
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       10,
+	}
+	ctr.VolumeMounts = []corev1.VolumeMount{{Name: "config", MountPath: connectionPoolerConfigDir}}
+
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(poolerName), cfg)
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
+	podTemplate.Template.Spec.Volumes = []corev1.Volume{pod.NewVolumeEmptyDir("config")}
+
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+	dep.Spec.Template = podTemplate.Template
+
+	return reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileConnectionPoolerService(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name string, cfg connectionPoolerConfig) error {
+	poolerName := sg.Spec.ObjectName(connectionPoolerName(name))
+	svc := service.NewService(poolerName, sg.Namespace, cfg)
+	svc.Spec.Ports = []corev1.ServicePort{
+		{Name: "pgbouncer", TargetPort: intstr.FromString("pgbouncer"), Port: connectionPoolerPort},
+	}
+	svc.Spec.Selector = map[string]string{"app": poolerName}
+
+	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
+}
+
+// connectionPoolerConfig wraps a database's config for the objects of its
+// connection pooler, which only exist while it is configured. Its pods run as
+// the database's, but without its env vars, sidecars and extra volumes, and
+// aren't scraped by Prometheus.
+type connectionPoolerConfig struct {
+	config.StandardComponent
+	pooler *config.ConnectionPoolerSpec
+}
+
+func (c connectionPoolerConfig) IsDisabled() bool {
+	return c.StandardComponent.IsDisabled() || !c.pooler.IsEnabled()
+}
+
+func (c connectionPoolerConfig) GetPrometheusPort() *int                    { return nil }
+func (c connectionPoolerConfig) GetEnv() map[string]string                  { return nil }
+func (c connectionPoolerConfig) GetEnvFrom() []config.SecretOrConfigMapRef  { return nil }
+func (c connectionPoolerConfig) GetSidecars() []corev1.Container            { return nil }
+func (c connectionPoolerConfig) GetExtraVolumes() []corev1.Volume           { return nil }
+func (c connectionPoolerConfig) GetExtraVolumeMounts() []corev1.VolumeMount { return nil }
+func (c connectionPoolerConfig) GetExtraInitContainers() []corev1.Container { return nil }
+
+// connectionPooler returns the config of the connection pooler of db.
+func (db gatedDatabase) connectionPooler(sg *config.Sourcegraph) connectionPoolerConfig {
+	switch db.name {
+	case "codeintel-db":
+		return connectionPoolerConfig{StandardComponent: sg.Spec.CodeIntel, pooler: sg.Spec.CodeIntel.ConnectionPooler}
+	case "codeinsights-db":
+		return connectionPoolerConfig{StandardComponent: sg.Spec.CodeInsights, pooler: sg.Spec.CodeInsights.ConnectionPooler}
+	default:
+		return connectionPoolerConfig{StandardComponent: sg.Spec.PGSQL, pooler: sg.Spec.PGSQL.ConnectionPooler}
+	}
+}
+
+// enabledConnectionPoolers returns the names of the databases that have a
+// connection pooler.
+func enabledConnectionPoolers(sg *config.Sourcegraph) []string {
+	var names []string
+	for _, db := range []gatedDatabase{gatePgsql, gateCodeIntel, gateCodeInsights} {
+		if !db.connectionPooler(sg).IsDisabled() {
+			names = append(names, db.name)
+		}
+	}
+	return names
+}
+
+// endpointEnvVars returns the PGHOST and PGPORT env vars, with the prefix of
+// db, that services connect to db with: its connection pooler if it has one,
+// and the host and port of its auth Secret otherwise.
+func (db gatedDatabase) endpointEnvVars(sg *config.Sourcegraph) []corev1.EnvVar {
+	if db.connectionPooler(sg).IsDisabled() {
+		secretName := sg.Spec.ObjectName(db.secretName)
+		return []corev1.EnvVar{
+			container.NewEnvVarSecretKeyRef(db.envPrefix+"PGHOST", secretName, "host"),
+			container.NewEnvVarSecretKeyRef(db.envPrefix+"PGPORT", secretName, "port"),
+		}
+	}
+	return []corev1.EnvVar{
+		{Name: db.envPrefix + "PGHOST", Value: sg.Spec.ObjectName(connectionPoolerName(db.name))},
+		{Name: db.envPrefix + "PGPORT", Value: strconv.Itoa(connectionPoolerPort)},
+	}
+}
+
+// clientEnvVars returns the env vars that services connect to db with, e.g.
+// PGHOST, with the prefix of db.
+func (db gatedDatabase) clientEnvVars(sg *config.Sourcegraph) []corev1.EnvVar {
+	envVars := container.EnvVarsPostgresClient(db.envPrefix, sg.Spec.ObjectName(db.secretName))
+	endpoint := db.endpointEnvVars(sg)
+	for i, envVar := range envVars {
+		for _, e := range endpoint {
+			if envVar.Name == e.Name {
+				envVars[i] = e
+			}
+		}
+	}
+	return envVars
+}
@@ -0,0 +1,149 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConnectionPooler(t *testing.T) {
+	// The three databases, with a pooler in front of pgsql only, and every
+	// service that connects to them.
+	spec := func(pooler string) []byte {
+		return []byte(`spec:
+  requestedVersion: "5.3.9104"
+  pgsql:` + pooler + `
+  codeIntel: {}
+  codeInsights: {}
+  symbols:
+    rockskip:
+      enabled: true
+`)
+	}
+	const enabled = `
+    connectionPooler:
+      poolMode: transaction
+      defaultPoolSize: 30`
+
+	c := fake.NewClientBuilder().WithObjects(newSpecConfigMap(spec(enabled))).Build()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: record.NewFakeRecorder(1000)}
+	ctx := context.Background()
+	key := func(name string) client.ObjectKey {
+		return client.ObjectKey{Namespace: renderedSpec.Namespace, Name: name}
+	}
+	podSpec := func(t *testing.T, kind, name string) corev1.PodSpec {
+		t.Helper()
+		if kind == "StatefulSet" {
+			var sset appsv1.StatefulSet
+			require.NoError(t, c.Get(ctx, key(name), &sset))
+			return sset.Spec.Template.Spec
+		}
+		var dep appsv1.Deployment
+		require.NoError(t, c.Get(ctx, key(name), &dep))
+		return dep.Spec.Template.Spec
+	}
+	findContainer := func(t *testing.T, containers []corev1.Container, name string) corev1.Container {
+		t.Helper()
+		for _, ctr := range containers {
+			if ctr.Name == name {
+				return ctr
+			}
+		}
+		t.Fatalf("missing container %s", name)
+		return corev1.Container{}
+	}
+	findEnv := func(t *testing.T, ctr corev1.Container, name string) corev1.EnvVar {
+		t.Helper()
+		for _, env := range ctr.Env {
+			if env.Name == name {
+				return env
+			}
+		}
+		t.Fatalf("missing env var %s in container %s", name, ctr.Name)
+		return corev1.EnvVar{}
+	}
+	// requireHost asserts that the PGHOST and PGPORT env vars with prefix
+	// point at host, or at the auth Secret of the database if host is empty.
+	requireHost := func(t *testing.T, ctr corev1.Container, prefix, secretName, host string) {
+		t.Helper()
+		pgHost, pgPort := findEnv(t, ctr, prefix+"PGHOST"), findEnv(t, ctr, prefix+"PGPORT")
+		if host == "" {
+			require.NotNil(t, pgHost.ValueFrom, "%s: %sPGHOST", ctr.Name, prefix)
+			require.Equal(t, secretName, pgHost.ValueFrom.SecretKeyRef.Name)
+			require.Equal(t, "host", pgHost.ValueFrom.SecretKeyRef.Key)
+			require.NotNil(t, pgPort.ValueFrom, "%s: %sPGPORT", ctr.Name, prefix)
+			require.Equal(t, secretName, pgPort.ValueFrom.SecretKeyRef.Name)
+			return
+		}
+		require.Nil(t, pgHost.ValueFrom, "%s: %sPGHOST", ctr.Name, prefix)
+		require.Equal(t, host, pgHost.Value)
+		require.Equal(t, "5432", pgPort.Value)
+	}
+	// requireConsumers asserts the env wiring of every service that connects
+	// to a database, given the host each database is reached at.
+	requireConsumers := func(t *testing.T, pgsqlHost string) {
+		t.Helper()
+		frontend := podSpec(t, "Deployment", "sourcegraph-frontend")
+		ctr := findContainer(t, frontend.Containers, "frontend")
+		requireHost(t, ctr, "", "pgsql-auth", pgsqlHost)
+		requireHost(t, ctr, "CODEINTEL_", "codeintel-db-auth", "")
+		requireHost(t, ctr, "CODEINSIGHTS_", "codeinsights-db-auth", "")
+		// The credentials still come from the auth Secret.
+		require.Equal(t, "pgsql-auth", findEnv(t, ctr, "PGPASSWORD").ValueFrom.SecretKeyRef.Name)
+
+		gate := findContainer(t, frontend.InitContainers, migrationGateContainerName)
+		requireHost(t, gate, "", "pgsql-auth", pgsqlHost)
+		requireHost(t, gate, "CODEINTEL_", "codeintel-db-auth", "")
+		requireHost(t, gate, "CODEINSIGHTS_", "codeinsights-db-auth", "")
+
+		for _, name := range []string{"worker", "precise-code-intel-worker"} {
+			gate := findContainer(t, podSpec(t, "Deployment", name).InitContainers, migrationGateContainerName)
+			requireHost(t, gate, "", "pgsql-auth", pgsqlHost)
+			requireHost(t, gate, "CODEINTEL_", "codeintel-db-auth", "")
+		}
+
+		symbols := findContainer(t, podSpec(t, "StatefulSet", "symbols").Containers, "symbols")
+		requireHost(t, symbols, "CODEINTEL_", "codeintel-db-auth", "")
+	}
+
+	_, _ = reconcileSpecConfigMap(t, r)
+
+	t.Run("enabled", func(t *testing.T) {
+		requireConsumers(t, "pgsql-pgbouncer")
+
+		pooler := findContainer(t, podSpec(t, "Deployment", "pgsql-pgbouncer").Containers, "pgbouncer")
+		require.Equal(t, "transaction", findEnv(t, pooler, "POOL_MODE").Value)
+		require.Equal(t, "30", findEnv(t, pooler, "DEFAULT_POOL_SIZE").Value)
+		require.Equal(t, "1000", findEnv(t, pooler, "MAX_CLIENT_CONN").Value)
+		// The pooler itself connects to the database directly.
+		requireHost(t, pooler, "", "pgsql-auth", "")
+
+		var svc corev1.Service
+		require.NoError(t, c.Get(ctx, key("pgsql-pgbouncer"), &svc))
+		require.Equal(t, "pgsql-pgbouncer", svc.Spec.Selector["app"])
+
+		for _, name := range []string{"codeintel-db-pgbouncer", "codeinsights-db-pgbouncer"} {
+			require.True(t, kerrors.IsNotFound(c.Get(ctx, key(name), &appsv1.Deployment{})), name)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		cm.Data["spec"] = string(spec(""))
+		require.NoError(t, c.Update(ctx, &cm))
+		_, _ = reconcileSpecConfigMap(t, r)
+
+		requireConsumers(t, "")
+		require.True(t, kerrors.IsNotFound(c.Get(ctx, key("pgsql-pgbouncer"), &appsv1.Deployment{})))
+		require.True(t, kerrors.IsNotFound(c.Get(ctx, key("pgsql-pgbouncer"), &corev1.Service{})))
+	})
+}
@@ -0,0 +1,215 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// holdsData reports whether obj is of a kind that the data retention policy
+// applies to: PersistentVolumeClaims, and Secrets, which hold the generated
+// credentials that the data can only be read with.
+func holdsData(obj client.Object) bool {
+	switch obj.(type) {
+	case *corev1.PersistentVolumeClaim, *corev1.Secret:
+		return true
+	}
+	return false
+}
+
+// reconcileDataRetentionFinalizer sets FinalizerDataRetention on the appliance
+// ConfigMap while policy is Retain, so that its deletion waits for
+// reconcileDeletion, and removes it otherwise.
+func (r *Reconciler) reconcileDataRetentionFinalizer(ctx context.Context, applianceSpec *corev1.ConfigMap, policy config.DataRetentionPolicy) error {
+	var changed bool
+	if policy == config.DataRetentionPolicyRetain {
+		changed = controllerutil.AddFinalizer(applianceSpec, config.FinalizerDataRetention)
+	} else {
+		changed = controllerutil.RemoveFinalizer(applianceSpec, config.FinalizerDataRetention)
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.Client.Update(ctx, applianceSpec); err != nil {
+		return errors.Wrap(err, "updating data retention finalizer")
+	}
+	return nil
+}
+
+// reconcileDeletion lets the appliance ConfigMap, which is being deleted, go
+// by removing FinalizerDataRetention, unless the data retention policy of its
+// spec is Retain and the deletion hasn't been confirmed with the
+// AnnotationKeyConfirmDeletion annotation. The data that is kept is labeled,
+// so that a later install in the same namespace adopts it. It returns whether
+// the deletion is blocked.
+//
+// A spec that can't be decoded keeps the data, since that is the default.
+func (r *Reconciler) reconcileDeletion(ctx context.Context, applianceSpec *corev1.ConfigMap) (blocked bool, _ error) {
+	if !controllerutil.ContainsFinalizer(applianceSpec, config.FinalizerDataRetention) {
+		return false, nil
+	}
+	policy := config.DataRetentionPolicyRetain
+	if sourcegraph, _, err := config.DecodeConfigYAML([]byte(applianceSpec.Data["spec"]), config.DecodeOptions{}); err == nil {
+		policy = sourcegraph.Spec.DataRetentionPolicy.OrDefault()
+	}
+
+	if policy == config.DataRetentionPolicyRetain {
+		if _, confirmed := applianceSpec.Annotations[config.AnnotationKeyConfirmDeletion]; !confirmed {
+			eventsFrom(ctx).eventf(EventReasonDeletionBlocked,
+				"Deletion is blocked, since the data retention policy is Retain. Set the %s annotation to delete every service but keep their data, or set dataRetentionPolicy to Delete to delete their data too.",
+				config.AnnotationKeyConfirmDeletion)
+			return true, nil
+		}
+		if err := r.retainData(ctx, applianceSpec); err != nil {
+			return false, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(applianceSpec, config.FinalizerDataRetention)
+	if err := r.Client.Update(ctx, applianceSpec); err != nil {
+		return false, errors.Wrap(err, "removing data retention finalizer")
+	}
+	return false, nil
+}
+
+// retainData labels every PersistentVolumeClaim and Secret of Sourcegraph in
+// the namespace of the appliance ConfigMap owner as retained from it,
+// including the claims of StatefulSets, which the appliance doesn't create
+// itself.
+func (r *Reconciler) retainData(ctx context.Context, owner client.Object) error {
+	var claims corev1.PersistentVolumeClaimList
+	var secrets corev1.SecretList
+	for _, list := range []client.ObjectList{&claims, &secrets} {
+		if err := r.Client.List(ctx, list, client.InNamespace(owner.GetNamespace()), client.MatchingLabels{"deploy": "sourcegraph"}); err != nil {
+			return errors.Wrap(err, "listing data to retain")
+		}
+	}
+	objs := make([]client.Object, 0, len(claims.Items)+len(secrets.Items))
+	for i := range claims.Items {
+		objs = append(objs, &claims.Items[i])
+	}
+	for i := range secrets.Items {
+		objs = append(objs, &secrets.Items[i])
+	}
+	for _, obj := range objs {
+		if err := r.markRetained(ctx, obj, owner); err != nil {
+			return err
+		}
+	}
+	eventsFrom(ctx).eventf(EventReasonRetained,
+		"Kept %d PersistentVolumeClaims and %d Secrets, which a later install in this namespace adopts.", len(claims.Items), len(secrets.Items))
+	return nil
+}
+
+// retainObject keeps obj, which the spec no longer requires, rather than
+// deleting it, and labels it as retained from owner.
+func (r *Reconciler) retainObject(ctx context.Context, obj, owner client.Object) error {
+	existing := obj.DeepCopyObject().(client.Object)
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if _, retained := existing.GetLabels()[config.LabelKeyRetainedFrom]; retained {
+		return nil
+	}
+	if err := r.markRetained(ctx, existing, owner); err != nil {
+		return err
+	}
+	eventsFrom(ctx).eventf(EventReasonRetained,
+		"Kept %s %s, which the spec no longer requires, since the data retention policy is Retain.", r.objectKind(existing), existing.GetName())
+	return nil
+}
+
+// markRetained labels obj as retained from owner, unless it already is, and
+// removes the owner reference to owner from it, so that deleting owner
+// doesn't delete obj.
+func (r *Reconciler) markRetained(ctx context.Context, obj, owner client.Object) error {
+	if _, retained := obj.GetLabels()[config.LabelKeyRetainedFrom]; retained {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[config.LabelKeyRetainedFrom] = owner.GetName()
+	obj.SetLabels(labels)
+	var refs []metav1.OwnerReference
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID != owner.GetUID() {
+			refs = append(refs, ref)
+		}
+	}
+	obj.SetOwnerReferences(refs)
+	if err := r.Client.Patch(ctx, obj, patch); err != nil {
+		return errors.Wrapf(err, "labeling %s %s as retained", r.objectKind(obj), obj.GetName())
+	}
+	return nil
+}
+
+// adoptObject removes the LabelKeyRetainedFrom label from existing, which the
+// spec requires again, e.g. after a reinstall.
+func (r *Reconciler) adoptObject(ctx context.Context, existing client.Object) error {
+	from, retained := existing.GetLabels()[config.LabelKeyRetainedFrom]
+	if !retained {
+		return nil
+	}
+	patch := client.MergeFrom(existing.DeepCopyObject().(client.Object))
+	labels := existing.GetLabels()
+	delete(labels, config.LabelKeyRetainedFrom)
+	existing.SetLabels(labels)
+	if err := r.Client.Patch(ctx, existing, patch); err != nil {
+		return errors.Wrapf(err, "adopting retained %s %s", r.objectKind(existing), existing.GetName())
+	}
+	eventsFrom(ctx).eventf(EventReasonAdopted, "Adopted %s %s, which was retained from %s.", r.objectKind(existing), existing.GetName(), from)
+	return nil
+}
+
+// reconcileStatefulSetClaims adopts the retained PersistentVolumeClaims that
+// the StatefulSet sset creates from its claim templates, or, if sset is
+// disabled and they are to be retained, labels them as retained from owner.
+// The appliance doesn't reconcile these claims itself, so this is the only
+// place to do it.
+func (r *Reconciler) reconcileStatefulSetClaims(ctx context.Context, sset *appsv1.StatefulSet, disabled bool, owner client.Object) error {
+	if len(sset.Spec.VolumeClaimTemplates) == 0 {
+		return nil
+	}
+	var claims corev1.PersistentVolumeClaimList
+	if err := r.Client.List(ctx, &claims, client.InNamespace(sset.Namespace), client.MatchingLabels(sset.Spec.Selector.MatchLabels)); err != nil {
+		return errors.Wrapf(err, "listing claims of StatefulSet %s", sset.Name)
+	}
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if !isStatefulSetClaim(sset, claim.Name) {
+			continue
+		}
+		var err error
+		if disabled {
+			err = r.retainObject(ctx, claim, owner)
+		} else {
+			err = r.adoptObject(ctx, claim)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isStatefulSetClaim reports whether name is the name of a claim that sset
+// creates from one of its claim templates, for one of its pods.
+func isStatefulSetClaim(sset *appsv1.StatefulSet, name string) bool {
+	for _, template := range sset.Spec.VolumeClaimTemplates {
+		if strings.HasPrefix(name, template.Name+"-"+sset.Name+"-") {
+			return true
+		}
+	}
+	return false
+}
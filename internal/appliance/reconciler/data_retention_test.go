@@ -0,0 +1,182 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+func TestDataRetention(t *testing.T) {
+	ctx := context.Background()
+	pgsql := string(readSpecFixture(t, "pgsql/default"))
+
+	// install creates the appliance ConfigMap with spec and uid, and
+	// reconciles it.
+	install := func(t *testing.T, c client.Client, r *Reconciler, spec string, uid types.UID) {
+		t.Helper()
+		cm := newSpecConfigMap([]byte(spec))
+		cm.UID = uid
+		require.NoError(t, c.Create(ctx, cm))
+		reconcileSpecConfigMap(t, r)
+	}
+	// dataOf returns the PersistentVolumeClaim and the Secret of pgsql.
+	dataOf := func(t *testing.T, c client.Client) []client.Object {
+		t.Helper()
+		objs := []client.Object{&corev1.PersistentVolumeClaim{}, &corev1.Secret{}}
+		for i, name := range []string{"pgsql", "pgsql-auth"} {
+			require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: renderedSpec.Namespace, Name: name}, objs[i]))
+		}
+		return objs
+	}
+	events := func(recorder *record.FakeRecorder, reason EventReason) []string {
+		var events []string
+		for {
+			select {
+			case event := <-recorder.Events:
+				if strings.Contains(event, " "+string(reason)+" ") {
+					events = append(events, event)
+				}
+			default:
+				return events
+			}
+		}
+	}
+	newReconciler := func() (client.Client, *Reconciler, *record.FakeRecorder) {
+		c := fake.NewClientBuilder().Build()
+		recorder := record.NewFakeRecorder(1000)
+		return c, &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: recorder}, recorder
+	}
+
+	t.Run("delete with Retain", func(t *testing.T) {
+		c, r, recorder := newReconciler()
+		install(t, c, r, pgsql, "first-install")
+
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		require.True(t, controllerutil.ContainsFinalizer(&cm, config.FinalizerDataRetention))
+		for _, obj := range dataOf(t, c) {
+			require.Empty(t, obj.GetOwnerReferences(), obj.GetName())
+		}
+		var sset appsv1.StatefulSet
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: renderedSpec.Namespace, Name: "pgsql"}, &sset))
+		require.True(t, metav1.IsControlledBy(&sset, &cm))
+
+		// Deleting the ConfigMap is blocked until it is confirmed.
+		require.NoError(t, c.Delete(ctx, &cm))
+		reconcileSpecConfigMap(t, r)
+		require.Len(t, events(recorder, EventReasonDeletionBlocked), 1)
+
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		cm.Annotations[config.AnnotationKeyConfirmDeletion] = "true"
+		require.NoError(t, c.Update(ctx, &cm))
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: renderedSpec})
+		require.NoError(t, err)
+		require.True(t, kerrors.IsNotFound(c.Get(ctx, renderedSpec, &cm)))
+
+		// The data survives, labeled for a later install to adopt.
+		for _, obj := range dataOf(t, c) {
+			require.Equal(t, "sg", obj.GetLabels()[config.LabelKeyRetainedFrom], obj.GetName())
+			require.Empty(t, obj.GetOwnerReferences(), obj.GetName())
+		}
+
+		t.Run("reinstall adopts it", func(t *testing.T) {
+			install(t, c, r, pgsql, "second-install")
+			for _, obj := range dataOf(t, c) {
+				require.NotContains(t, obj.GetLabels(), config.LabelKeyRetainedFrom, obj.GetName())
+			}
+			require.ElementsMatch(t, []string{
+				"Normal Adopted Adopted PersistentVolumeClaim pgsql, which was retained from sg.",
+				"Normal Adopted Adopted Secret pgsql-auth, which was retained from sg.",
+			}, events(recorder, EventReasonAdopted))
+		})
+	})
+
+	t.Run("delete with Delete", func(t *testing.T) {
+		c, r, _ := newReconciler()
+		spec := strings.Replace(pgsql, "spec:\n", "spec:\n  dataRetentionPolicy: Delete\n", 1)
+		install(t, c, r, spec, "first-install")
+
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		require.Empty(t, cm.Finalizers)
+
+		// The data is owned by the ConfigMap, so that the garbage collector
+		// deletes it along with it.
+		for _, obj := range dataOf(t, c) {
+			require.True(t, metav1.IsControlledBy(obj, &cm), obj.GetName())
+		}
+		require.NoError(t, c.Delete(ctx, &cm))
+		require.True(t, kerrors.IsNotFound(c.Get(ctx, renderedSpec, &cm)))
+	})
+
+	t.Run("disable with Retain", func(t *testing.T) {
+		c, r, recorder := newReconciler()
+		install(t, c, r, pgsql, "first-install")
+
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		cm.Data["spec"] = strings.Replace(pgsql, "pgsql: {}", "pgsql:\n    disabled: true", 1)
+		require.NoError(t, c.Update(ctx, &cm))
+		reconcileSpecConfigMap(t, r)
+
+		var sset appsv1.StatefulSet
+		require.True(t, kerrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: renderedSpec.Namespace, Name: "pgsql"}, &sset)))
+		for _, obj := range dataOf(t, c) {
+			require.Equal(t, "sg", obj.GetLabels()[config.LabelKeyRetainedFrom], obj.GetName())
+		}
+		require.Len(t, events(recorder, EventReasonRetained), 2)
+
+		// Enabling it again adopts the data.
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		cm.Data["spec"] = pgsql
+		require.NoError(t, c.Update(ctx, &cm))
+		reconcileSpecConfigMap(t, r)
+		for _, obj := range dataOf(t, c) {
+			require.NotContains(t, obj.GetLabels(), config.LabelKeyRetainedFrom, obj.GetName())
+		}
+	})
+}
+
+func TestDataRetentionPolicyOfStatefulSetClaims(t *testing.T) {
+	for _, tc := range []struct {
+		policy string
+		want   *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy
+	}{
+		{policy: "Retain"},
+		{policy: "Delete", want: &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+			WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+			WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+		}},
+	} {
+		t.Run(tc.policy, func(t *testing.T) {
+			spec := strings.Replace(string(readSpecFixture(t, "gitserver/default")), "spec:\n", "spec:\n  dataRetentionPolicy: "+tc.policy+"\n", 1)
+			objs, err := Render(context.Background(), renderedSpec, []byte(spec))
+			require.NoError(t, err)
+			var gitserver appsv1.StatefulSet
+			for _, obj := range objs {
+				if u := obj.(*unstructured.Unstructured); u.GetKind() == "StatefulSet" && u.GetName() == "gitserver" {
+					require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &gitserver))
+				}
+			}
+			require.Equal(t, "gitserver", gitserver.Name)
+			require.Equal(t, tc.want, gitserver.Spec.PersistentVolumeClaimRetentionPolicy)
+		})
+	}
+}
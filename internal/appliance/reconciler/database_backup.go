@@ -0,0 +1,254 @@
+package reconciler
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/cronjob"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/job"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// backupScript dumps a database into /backups, and then deletes all but the
+// newest BACKUP_RETENTION backups. Backups are named after the time they were
+// taken, so that they sort in that order.
+const backupScript = `set -eu
+backup="${BACKUP_PREFIX}-$(date -u +%Y%m%dT%H%M%SZ).dump"
+pg_dump --format=custom --no-owner --file="/backups/${backup}.partial"
+mv "/backups/${backup}.partial" "/backups/${backup}"
+echo "Wrote backup ${backup}"
+ls -1 /backups/"${BACKUP_PREFIX}"-*.dump | sort -r | tail -n +$((BACKUP_RETENTION + 1)) | xargs -r rm -f
+`
+
+// restoreScript replaces the contents of a database with those of the backup
+// called BACKUP_NAME.
+const restoreScript = `set -eu
+pg_restore --clean --if-exists --no-owner --exit-on-error --dbname="${PGDATABASE}" "/backups/${BACKUP_NAME}"
+echo "Restored backup ${BACKUP_NAME}"
+`
+
+// reconcileDatabaseBackups manages the backups of the database called name,
+// whose image is the default image of the same name, and whose connection
+// details are in secretName. The objects are named after the database with
+// the NamePrefix applied.
+func (r *Reconciler) reconcileDatabaseBackups(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name, secretName string, dbCfg config.StandardComponent, backup *config.DatabaseBackupSpec) error {
+	cfg := databaseBackupConfig{StandardComponent: dbCfg, backup: backup}
+	if err := r.reconcileDatabaseBackupPersistentVolumeClaim(ctx, sg, owner, name, cfg); err != nil {
+		return errors.Wrap(err, "reconciling backup PersistentVolumeClaim")
+	}
+	if err := r.reconcileDatabaseBackupCronJob(ctx, sg, owner, name, secretName, cfg, dbCfg); err != nil {
+		return errors.Wrap(err, "reconciling backup CronJob")
+	}
+	if err := r.reconcileDatabaseRestoreJob(ctx, sg, owner, name, secretName, cfg); err != nil {
+		return errors.Wrap(err, "reconciling restore Job")
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileDatabaseBackupPersistentVolumeClaim(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name string, cfg databaseBackupConfig) error {
+	// Backups outlive the config that created them, so that they can still be
+	// restored from. Admins delete the PVC by hand once they don't need them.
+	if cfg.IsDisabled() {
+		return nil
+	}
+	p, err := pvc.NewPersistentVolumeClaim(sg.Spec.ObjectName(name+"-backups"), sg.Namespace, cfg)
+	if err != nil {
+		return err
+	}
+	return reconcileObject(ctx, r, cfg, &p, &corev1.PersistentVolumeClaim{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileDatabaseBackupCronJob(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name, secretName string, cfg databaseBackupConfig, dbCfg config.StandardComponent) error {
+	cj := cronjob.NewCronJob(sg.Spec.ObjectName(name+"-backup"), sg.Namespace, sg.Spec.RequestedVersion)
+	if !cfg.IsDisabled() {
+		template, err := r.databaseBackupPodTemplate(sg, owner, name+"-backup", name, secretName, cfg, backupScript, []corev1.EnvVar{
+			{Name: "BACKUP_PREFIX", Value: name},
+			{Name: "BACKUP_RETENTION", Value: strconv.Itoa(int(cfg.backup.GetRetention()))},
+		})
+		if err != nil {
+			return err
+		}
+		cj.Spec.Schedule = cfg.backup.GetSchedule()
+		// The database is down while it's scaled down for maintenance, so
+		// every backup would fail.
+		cj.Spec.Suspend = pointers.Ptr(sg.Spec.MaintenanceMode.ScaledDown(dbCfg))
+		cj.Spec.JobTemplate.Spec.BackoffLimit = pointers.Ptr[int32](0)
+		cj.Spec.JobTemplate.Spec.Template = template
+	}
+	if err := reconcileObject(ctx, r, cfg, &cj, &batchv1.CronJob{}, sg, owner); err != nil {
+		return err
+	}
+	return r.recordBackupStatus(ctx, sg, owner, name, cfg)
+}
+
+// recordBackupStatus lists the database in an annotation on the owner while
+// its last scheduled backup has failed, and records a warning event when that
+// starts.
+func (r *Reconciler) recordBackupStatus(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name string, cfg databaseBackupConfig) error {
+	failed := false
+	if !cfg.IsDisabled() {
+		var cj batchv1.CronJob
+		err := r.Get(ctx, types.NamespacedName{Namespace: sg.Namespace, Name: sg.Spec.ObjectName(name + "-backup")}, &cj)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrap(err, "getting backup status")
+		}
+		failed = err == nil && lastBackupFailed(cj.Status)
+	}
+
+	annotations := owner.GetAnnotations()
+	var databases []string
+	if value := annotations[config.AnnotationKeyFailedBackups]; value != "" {
+		databases = strings.Split(value, ",")
+	}
+	wasFailed := slices.Contains(databases, name)
+	switch {
+	case failed && !wasFailed:
+		r.Recorder.Eventf(owner, corev1.EventTypeWarning, "BackupFailed",
+			"The last backup of %s failed, see the logs of the %s Job.", name, sg.Spec.ObjectName(name+"-backup"))
+		databases = append(databases, name)
+		slices.Sort(databases)
+	case !failed && wasFailed:
+		databases = slices.DeleteFunc(databases, func(db string) bool { return db == name })
+	default:
+		return nil
+	}
+
+	if len(databases) == 0 {
+		delete(annotations, config.AnnotationKeyFailedBackups)
+	} else {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[config.AnnotationKeyFailedBackups] = strings.Join(databases, ",")
+	}
+	owner.SetAnnotations(annotations)
+	return nil
+}
+
+// lastBackupFailed reports whether the last Job that a CronJob scheduled has
+// finished without succeeding.
+func lastBackupFailed(status batchv1.CronJobStatus) bool {
+	if status.LastScheduleTime == nil || len(status.Active) > 0 {
+		return false
+	}
+	return status.LastSuccessfulTime == nil || status.LastSuccessfulTime.Before(status.LastScheduleTime)
+}
+
+// reconcileDatabaseRestoreJob runs a one-off Job that restores a database from
+// the backup named in RestoreFrom. Jobs can't be updated, and rerunning one
+// would discard everything written to the database since, so the Job is only
+// replaced when another backup is named.
+func (r *Reconciler) reconcileDatabaseRestoreJob(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name, secretName string, cfg databaseBackupConfig) error {
+	restoreJob := job.NewJob(sg.Spec.ObjectName(name+"-restore"), sg.Namespace, sg.Spec.RequestedVersion)
+	var restoreFrom string
+	if !cfg.IsDisabled() {
+		restoreFrom = cfg.backup.RestoreFrom
+	}
+
+	var existing batchv1.Job
+	err := r.Get(ctx, client.ObjectKeyFromObject(&restoreJob), &existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "getting existing restore Job")
+	}
+	if err == nil {
+		if restoreFrom != "" && existing.GetAnnotations()[config.AnnotationKeyRestoreFrom] == restoreFrom {
+			return nil
+		}
+		if err := r.Delete(ctx, &existing, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrap(err, "deleting previous restore Job")
+		}
+	}
+	if restoreFrom == "" {
+		return nil
+	}
+
+	template, err := r.databaseBackupPodTemplate(sg, owner, name+"-restore", name, secretName, cfg, restoreScript, []corev1.EnvVar{
+		{Name: "BACKUP_NAME", Value: restoreFrom},
+	})
+	if err != nil {
+		return err
+	}
+	restoreJob.Annotations = map[string]string{config.AnnotationKeyRestoreFrom: restoreFrom}
+	restoreJob.Spec.Template = template
+	return reconcileObject(ctx, r, cfg, &restoreJob, &batchv1.Job{}, sg, owner)
+}
+
+// databaseBackupPodTemplate returns the template of pods that run script in
+// the image of the database called name, connected to the database and with
+// its backups mounted at /backups.
+func (r *Reconciler) databaseBackupPodTemplate(sg *config.Sourcegraph, owner client.Object, podName, name, secretName string, cfg databaseBackupConfig, script string, env []corev1.EnvVar) (corev1.PodTemplateSpec, error) {
+	image, err := config.GetDefaultImage(sg, name)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	ctr := container.NewContainer(podName, cfg, config.ContainerConfig{
+		Image: image,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+	})
+	ctr.Command = []string{"sh", "-c", script}
+	ctr.Env = append(ctr.Env, container.EnvVarsPostgresClient("", secretName)...)
+	ctr.Env = append(ctr.Env, env...)
+	ctr.VolumeMounts = []corev1.VolumeMount{{Name: "backups", MountPath: "/backups"}}
+
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(podName), cfg)
+	podTemplate.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate.Template.Spec.Volumes = []corev1.Volume{pod.NewVolumeFromPVC("backups", sg.Spec.ObjectName(name+"-backups"))}
+
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	return podTemplate.Template, nil
+}
+
+// databaseBackupConfig wraps a database's config for the objects that back it
+// up, which only exist while backups are configured. Those pods run as the
+// database's, but without its env vars, sidecars and extra volumes.
+type databaseBackupConfig struct {
+	config.StandardComponent
+	backup *config.DatabaseBackupSpec
+}
+
+func (c databaseBackupConfig) IsDisabled() bool {
+	return c.StandardComponent.IsDisabled() || c.backup == nil
+}
+
+func (c databaseBackupConfig) GetPersistentVolumeConfig() config.PersistentVolumeConfig {
+	volume := c.backup.PersistentVolumeConfig
+	if volume.StorageSize == "" {
+		volume.StorageSize = c.StandardComponent.GetPersistentVolumeConfig().StorageSize
+	}
+	return volume
+}
+
+func (c databaseBackupConfig) GetEnv() map[string]string                  { return nil }
+func (c databaseBackupConfig) GetEnvFrom() []config.SecretOrConfigMapRef  { return nil }
+func (c databaseBackupConfig) GetSidecars() []corev1.Container            { return nil }
+func (c databaseBackupConfig) GetExtraVolumes() []corev1.Volume           { return nil }
+func (c databaseBackupConfig) GetExtraVolumeMounts() []corev1.VolumeMount { return nil }
+func (c databaseBackupConfig) GetExtraInitContainers() []corev1.Container { return nil }
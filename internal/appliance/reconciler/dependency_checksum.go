@@ -0,0 +1,220 @@
+package reconciler
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// reconcileCredentials reconciles the Secrets holding the credentials and
+// endpoints of the databases, and the executors' access token, which other
+// services consume. It runs before
+// the services, so that their dependency checksums account for the
+// credentials as they are after this reconcile.
+func (r *Reconciler) reconcileCredentials(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	if err := r.reconcilePGSQLSecret(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling pgsql Secret")
+	}
+	if err := r.reconcileCodeIntelSecret(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling codeintel-db Secret")
+	}
+	if err := r.reconcileCodeInsightsSecret(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling codeinsights-db Secret")
+	}
+	if err := r.reconcileRedisSecret(ctx, sg, owner, "cache", sg.Spec.RedisCache); err != nil {
+		return errors.Wrap(err, "reconciling redis-cache Secret")
+	}
+	if err := r.reconcileRedisSecret(ctx, sg, owner, "store", sg.Spec.RedisStore); err != nil {
+		return errors.Wrap(err, "reconciling redis-store Secret")
+	}
+	if err := r.reconcileExecutorSecret(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling executor-auth Secret")
+	}
+	return nil
+}
+
+// podTemplateOf returns the pod template of a Deployment, StatefulSet, or
+// DaemonSet, or nil for other kinds. Jobs are left out, since their pod
+// templates are immutable.
+func podTemplateOf(obj client.Object) *corev1.PodTemplateSpec {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return &o.Spec.Template
+	case *appsv1.StatefulSet:
+		return &o.Spec.Template
+	case *appsv1.DaemonSet:
+		return &o.Spec.Template
+	}
+	return nil
+}
+
+// dependencyRef is a Secret or ConfigMap that a pod template references.
+type dependencyRef struct {
+	kind string
+	name string
+}
+
+// podTemplateDependencies returns the Secrets and ConfigMaps that the
+// containers of a pod template read env vars from, or that its volumes mount,
+// sorted and without duplicates.
+func podTemplateDependencies(template *corev1.PodTemplateSpec) []dependencyRef {
+	var refs []dependencyRef
+	for _, ctr := range slices.Concat(template.Spec.InitContainers, template.Spec.Containers) {
+		for _, env := range ctr.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				refs = append(refs, dependencyRef{kind: "Secret", name: ref.Name})
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				refs = append(refs, dependencyRef{kind: "ConfigMap", name: ref.Name})
+			}
+		}
+		for _, envFrom := range ctr.EnvFrom {
+			if ref := envFrom.SecretRef; ref != nil {
+				refs = append(refs, dependencyRef{kind: "Secret", name: ref.Name})
+			}
+			if ref := envFrom.ConfigMapRef; ref != nil {
+				refs = append(refs, dependencyRef{kind: "ConfigMap", name: ref.Name})
+			}
+		}
+	}
+	for _, vol := range template.Spec.Volumes {
+		if vol.Secret != nil {
+			refs = append(refs, dependencyRef{kind: "Secret", name: vol.Secret.SecretName})
+		}
+		if vol.ConfigMap != nil {
+			refs = append(refs, dependencyRef{kind: "ConfigMap", name: vol.ConfigMap.Name})
+		}
+		if vol.Projected == nil {
+			continue
+		}
+		for _, src := range vol.Projected.Sources {
+			if src.Secret != nil {
+				refs = append(refs, dependencyRef{kind: "Secret", name: src.Secret.Name})
+			}
+			if src.ConfigMap != nil {
+				refs = append(refs, dependencyRef{kind: "ConfigMap", name: src.ConfigMap.Name})
+			}
+		}
+	}
+
+	slices.SortFunc(refs, func(a, b dependencyRef) int {
+		return cmp.Or(strings.Compare(a.kind, b.kind), strings.Compare(a.name, b.name))
+	})
+	return slices.Compact(refs)
+}
+
+// applyDependencyChecksum sets the dependency checksum annotation of a pod
+// template, so that its pods are rolled whenever a Secret or ConfigMap that
+// they consume changes, or the rotateNow annotation of the spec ConfigMap is
+// set to a new value. Only the pods that consume a rotated Secret are rolled.
+//
+// ConfigMaps that the appliance generates from the spec are left out: they
+// only change along with the spec, which rolls the pods anyway, and those
+// whose content the pods don't pick up by themselves already have a checksum
+// of their own. Secrets and ConfigMaps that don't exist yet are left out too,
+// since pods can't start without them.
+func (r *Reconciler) applyDependencyChecksum(ctx context.Context, sg *config.Sourcegraph, owner client.Object, template *corev1.PodTemplateSpec) error {
+	checksum, err := r.dependencyChecksum(ctx, sg, owner, template)
+	if err != nil {
+		return err
+	}
+	if checksum == "" {
+		return nil
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[config.AnnotationKeyDependencyChecksum] = checksum
+	return nil
+}
+
+// dependencyChecksum returns the checksum of the content of the Secrets and
+// ConfigMaps that a pod template depends on, and of the rotateNow annotation,
+// or the empty string if there is nothing to checksum. Keys are sorted, so
+// that the checksum is stable.
+func (r *Reconciler) dependencyChecksum(ctx context.Context, sg *config.Sourcegraph, owner client.Object, template *corev1.PodTemplateSpec) (string, error) {
+	hash := sha256.New()
+	empty := true
+	if rotateNow, ok := sg.Annotations[config.AnnotationKeyRotateNow]; ok {
+		fmt.Fprintf(hash, "rotateNow=%s\n", rotateNow)
+		empty = false
+	}
+
+	for _, ref := range podTemplateDependencies(template) {
+		var data map[string][]byte
+		switch ref.kind {
+		case "Secret":
+			var secret corev1.Secret
+			found, err := r.getDependency(ctx, ref.name, sg.Namespace, &secret)
+			if err != nil {
+				return "", err
+			}
+			if !found {
+				continue
+			}
+			data = secret.Data
+		case "ConfigMap":
+			var cm corev1.ConfigMap
+			found, err := r.getDependency(ctx, ref.name, sg.Namespace, &cm)
+			if err != nil {
+				return "", err
+			}
+			if !found {
+				continue
+			}
+			if metav1.IsControlledBy(&cm, owner) {
+				continue
+			}
+			data = make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+			for key, value := range cm.Data {
+				data[key] = []byte(value)
+			}
+			for key, value := range cm.BinaryData {
+				data[key] = value
+			}
+		}
+
+		fmt.Fprintf(hash, "%s/%s\n", ref.kind, ref.name)
+		keys := maps.Keys(data)
+		slices.Sort(keys)
+		for _, key := range keys {
+			valueHash := sha256.Sum256(data[key])
+			fmt.Fprintf(hash, "%s=%s\n", key, hex.EncodeToString(valueHash[:]))
+		}
+		empty = false
+	}
+
+	if empty {
+		return "", nil
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// getDependency gets a Secret or ConfigMap that a pod template depends on,
+// returning whether it exists.
+func (r *Reconciler) getDependency(ctx context.Context, name, namespace string, obj client.Object) (bool, error) {
+	if err := r.GetObject(ctx, name, namespace, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "getting %s", name)
+	}
+	return true, nil
+}
@@ -0,0 +1,125 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+func TestDependencyChecksum(t *testing.T) {
+	ctx := context.Background()
+	spec := []byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  worker:
+    envFrom:
+      - name: SMTP_PASSWORD
+        secretName: smtp
+        key: password
+`)
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, kind := range managedKinds {
+		scope := meta.RESTScopeRoot
+		if kind.namespaced {
+			scope = meta.RESTScopeNamespace
+		}
+		mapper.Add(kind.gvk, scope)
+	}
+	smtp := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "smtp", Namespace: renderedSpec.Namespace},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+	c := fake.NewClientBuilder().
+		WithRESTMapper(mapper).
+		WithObjects(newSpecConfigMap(spec), smtp).
+		Build()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}}
+
+	reconcileSpecConfigMap(t, r)
+	checksums := podTemplateChecksums(t, c)
+	require.NotEmpty(t, checksums["worker"])
+	require.NotEmpty(t, checksums["sourcegraph-frontend"])
+
+	// requireRolled reconciles the spec ConfigMap again, and checks that the
+	// pod templates of exactly the given workloads changed.
+	requireRolled := func(t *testing.T, want ...string) {
+		t.Helper()
+		reconcileSpecConfigMap(t, r)
+		next := podTemplateChecksums(t, c)
+		var rolled []string
+		for name, checksum := range next {
+			if checksum != checksums[name] {
+				rolled = append(rolled, name)
+			}
+		}
+		require.ElementsMatch(t, want, rolled)
+		checksums = next
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		requireRolled(t)
+	})
+
+	t.Run("rotated Secret", func(t *testing.T) {
+		var secret corev1.Secret
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(smtp), &secret))
+		secret.Data["password"] = []byte("correct horse battery staple")
+		require.NoError(t, c.Update(ctx, &secret))
+		requireRolled(t, "worker")
+	})
+
+	t.Run("rotated database credentials", func(t *testing.T) {
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		cm.Data["spec"] = string(spec) + `
+  codeInsights:
+    database:
+      password: rotated
+`
+		require.NoError(t, c.Update(ctx, &cm))
+		requireRolled(t, "codeinsights-db", "sourcegraph-frontend", "worker")
+	})
+
+	t.Run("rotate now", func(t *testing.T) {
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		metav1.SetMetaDataAnnotation(&cm.ObjectMeta, config.AnnotationKeyRotateNow, "2024-04-19T00:00:00Z")
+		require.NoError(t, c.Update(ctx, &cm))
+
+		var all []string
+		for name := range checksums {
+			all = append(all, name)
+		}
+		requireRolled(t, all...)
+	})
+}
+
+// podTemplateChecksums returns the dependency checksums of the pod templates
+// of the Deployments and StatefulSets in the rendered namespace, by name.
+func podTemplateChecksums(t *testing.T, c client.Client) map[string]string {
+	t.Helper()
+	ctx := context.Background()
+	checksums := map[string]string{}
+	var deps appsv1.DeploymentList
+	require.NoError(t, c.List(ctx, &deps, client.InNamespace(renderedSpec.Namespace)))
+	for _, dep := range deps.Items {
+		checksums[dep.Name] = dep.Spec.Template.Annotations[config.AnnotationKeyDependencyChecksum]
+	}
+	var ssets appsv1.StatefulSetList
+	require.NoError(t, c.List(ctx, &ssets, client.InNamespace(renderedSpec.Namespace)))
+	for _, sset := range ssets.Items {
+		checksums[sset.Name] = sset.Spec.Template.Annotations[config.AnnotationKeyDependencyChecksum]
+	}
+	return checksums
+}
@@ -0,0 +1,376 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// objectDrift lists the fields of an object that were changed by hand.
+type objectDrift struct {
+	kind   string
+	name   string
+	fields []string
+}
+
+func (d objectDrift) String() string {
+	return fmt.Sprintf("%s %s: %s", d.kind, d.name, strings.Join(d.fields, ", "))
+}
+
+// driftRecorder collects the drift found while reconciling a service.
+type driftRecorder struct {
+	drifts []objectDrift
+}
+
+type driftRecorderKey struct{}
+
+// withDriftRecorder returns a context in which the drift found by
+// createOrUpdateObject is collected by the returned recorder.
+func withDriftRecorder(ctx context.Context) (context.Context, *driftRecorder) {
+	recorder := &driftRecorder{}
+	return context.WithValue(ctx, driftRecorderKey{}, recorder), recorder
+}
+
+// recordDrift adds drift to the recorder of ctx, if it has one.
+func recordDrift(ctx context.Context, drift objectDrift) {
+	if recorder, ok := ctx.Value(driftRecorderKey{}).(*driftRecorder); ok {
+		recorder.drifts = append(recorder.drifts, drift)
+	}
+}
+
+// reportDrift sets the InSync condition of a service, and records an event
+// for drift that was reverted or is reported.
+func reportDrift(svc *config.ServiceStatus, events eventRecorder, policy config.DriftPolicy, drifts []objectDrift) {
+	if len(drifts) == 0 {
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:   config.ConditionInSync,
+			Status: metav1.ConditionTrue,
+			Reason: config.ReasonNoDrift,
+		})
+		return
+	}
+
+	messages := make([]string, 0, len(drifts))
+	for _, drift := range drifts {
+		messages = append(messages, drift.String())
+	}
+	condition := metav1.Condition{
+		Type:    config.ConditionInSync,
+		Message: "Changed by hand: " + strings.Join(messages, "; "),
+	}
+	switch policy {
+	case config.DriftPolicyIgnore:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = config.ReasonDriftIgnored
+	case config.DriftPolicyReport:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = config.ReasonDriftDetected
+		for _, message := range messages {
+			events.eventf(EventReasonDriftDetected, "Changed by hand: %s", message)
+		}
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = config.ReasonDriftReverted
+		for _, message := range messages {
+			events.eventf(EventReasonDriftReverted, "Reverted changes made by hand: %s", message)
+		}
+	}
+	meta.SetStatusCondition(&svc.Conditions, condition)
+}
+
+// objectFields returns the fields of obj as generic JSON values, so that
+// objects of any kind can be compared and patched alike.
+func objectFields(obj client.Object) (map[string]any, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// desiredFields returns the fields that the appliance sets on obj. Fields that
+// the API server sets, and empty ones, are left out, since they would
+// otherwise be mistaken for drift, and so are the kind and API version, which
+// typed clients don't return.
+func desiredFields(obj client.Object) (map[string]any, error) {
+	fields, err := objectFields(obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range [][]string{
+		{"apiVersion"},
+		{"kind"},
+		{"metadata", "annotations", config.AnnotationKeyLastApplied},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "generation"},
+		{"metadata", "managedFields"},
+		{"metadata", "resourceVersion"},
+		{"metadata", "uid"},
+		{"status"},
+	} {
+		unstructured.RemoveNestedField(fields, field...)
+	}
+	pruneEmpty(fields)
+	return fields, nil
+}
+
+// pruneEmpty recursively removes the nulls, and the strings, maps, and lists
+// that are empty, from fields.
+func pruneEmpty(fields map[string]any) {
+	for key, value := range fields {
+		switch value := value.(type) {
+		case nil:
+			delete(fields, key)
+		case string:
+			if value == "" {
+				delete(fields, key)
+			}
+		case map[string]any:
+			pruneEmpty(value)
+			if len(value) == 0 {
+				delete(fields, key)
+			}
+		case []any:
+			for _, item := range value {
+				if item, ok := item.(map[string]any); ok {
+					pruneEmpty(item)
+				}
+			}
+			if len(value) == 0 {
+				delete(fields, key)
+			}
+		}
+	}
+}
+
+// hashValues returns a copy of the fields of obj, in which the values of a
+// Secret or ConfigMap are replaced with their hashes, so that they can be
+// compared without being recorded in AnnotationKeyLastApplied.
+func hashValues(obj client.Object, fields map[string]any) (map[string]any, error) {
+	switch obj.(type) {
+	case *corev1.Secret, *corev1.ConfigMap:
+	default:
+		return fields, nil
+	}
+
+	hashed := runtime.DeepCopyJSON(fields)
+	for _, key := range []string{"data", "binaryData", "stringData"} {
+		values, ok := hashed[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		for name, value := range values {
+			hash, err := configHash(value)
+			if err != nil {
+				return nil, err
+			}
+			values[name] = "sha256:" + hash
+		}
+	}
+	return hashed, nil
+}
+
+// foldSecretStringData moves the StringData of a Secret into its Data, as the
+// API server does, so that it can be compared to the live Secret.
+func foldSecretStringData(obj client.Object) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || len(secret.StringData) == 0 {
+		return
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for key, value := range secret.StringData {
+		secret.Data[key] = []byte(value)
+	}
+	secret.StringData = nil
+}
+
+// driftedFields returns the paths of the fields set in want whose value in
+// live differs. Fields that only live sets aren't drift: they are set by the
+// API server, or by someone else. Items of lists of objects are matched by
+// their key, e.g. containers by name, so that items added by someone else
+// aren't drift either.
+func driftedFields(path string, want, live any) []string {
+	switch want := want.(type) {
+	case map[string]any:
+		live, ok := live.(map[string]any)
+		if !ok {
+			return []string{path}
+		}
+		keys := make([]string, 0, len(want))
+		for key := range want {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var fields []string
+		for _, key := range keys {
+			fields = append(fields, driftedFields(fieldPath(path, key), want[key], live[key])...)
+		}
+		return fields
+
+	case []any:
+		live, ok := live.([]any)
+		if !ok {
+			return []string{path}
+		}
+		var fields []string
+		if key := listKey(want); key != "" {
+			for _, item := range want {
+				id := item.(map[string]any)[key]
+				itemPath := fmt.Sprintf("%s[%s=%v]", path, key, id)
+				liveItem := findListItem(live, key, id)
+				if liveItem == nil {
+					fields = append(fields, itemPath)
+					continue
+				}
+				fields = append(fields, driftedFields(itemPath, item, liveItem)...)
+			}
+			return fields
+		}
+		if len(want) != len(live) {
+			return []string{path}
+		}
+		for i := range want {
+			fields = append(fields, driftedFields(fmt.Sprintf("%s[%d]", path, i), want[i], live[i])...)
+		}
+		return fields
+
+	default:
+		if !reflect.DeepEqual(want, live) {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+func fieldPath(path, key string) string {
+	if strings.ContainsAny(key, "./") {
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// listKeys are the fields that identify the items of lists of objects in the
+// kinds that the appliance manages, in order of preference.
+var listKeys = []string{"name", "mountPath", "containerPort", "port", "topologyKey", "ip"}
+
+// listKey returns the field that identifies each item of list, or "" if list
+// has to be compared item by item.
+func listKey(list []any) string {
+	for _, key := range listKeys {
+		seen := map[any]struct{}{}
+		for _, item := range list {
+			item, ok := item.(map[string]any)
+			if !ok {
+				return ""
+			}
+			id, ok := item[key]
+			if !ok {
+				break
+			}
+			if _, ok := id.(string); !ok {
+				if _, ok := id.(float64); !ok {
+					break
+				}
+			}
+			seen[id] = struct{}{}
+		}
+		if len(seen) == len(list) {
+			return key
+		}
+	}
+	return ""
+}
+
+func findListItem(list []any, key string, id any) map[string]any {
+	for _, item := range list {
+		if item, ok := item.(map[string]any); ok && item[key] == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// mergePatch returns a patch that sets the fields of modified on current, and
+// removes the fields of original that modified lacks. Fields that only
+// current sets are kept. Built-in kinds get a strategic merge patch, so that
+// the items of lists such as containers are merged by key, and other kinds a
+// JSON merge patch. The patch is nil if there is nothing to change.
+//
+// Strategic merge patches merge owner references by their uid, which
+// references to owners that no API server stored lack, e.g. those created by
+// the fake client of the tests. Objects with such references get a JSON merge
+// patch as well.
+func mergePatch(obj client.Object, original, modified, current map[string]any) (client.Patch, error) {
+	var docs [3][]byte
+	for i, fields := range []map[string]any{original, modified, current} {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = data
+	}
+
+	var patchType types.PatchType
+	var patch []byte
+	if _, ok := obj.(runtime.Unstructured); ok || !ownerReferencesHaveUIDs(original, modified, current) {
+		var err error
+		patchType = types.MergePatchType
+		patch, err = jsonmergepatch.CreateThreeWayJSONMergePatch(docs[0], docs[1], docs[2])
+		if err != nil {
+			return nil, errors.Wrap(err, "creating merge patch")
+		}
+	} else {
+		lookup, err := strategicpatch.NewPatchMetaFromStruct(obj)
+		if err != nil {
+			return nil, err
+		}
+		patchType = types.StrategicMergePatchType
+		patch, err = strategicpatch.CreateThreeWayMergePatch(docs[0], docs[1], docs[2], lookup, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating strategic merge patch")
+		}
+	}
+	if string(patch) == "{}" {
+		return nil, nil
+	}
+	return client.RawPatch(patchType, patch), nil
+}
+
+// ownerReferencesHaveUIDs returns true if all owner references of the objects
+// have a uid.
+func ownerReferencesHaveUIDs(objs ...map[string]any) bool {
+	for _, obj := range objs {
+		refs, _, _ := unstructured.NestedSlice(obj, "metadata", "ownerReferences")
+		for _, ref := range refs {
+			if ref, ok := ref.(map[string]any); !ok || ref["uid"] == nil || ref["uid"] == "" {
+				return false
+			}
+		}
+	}
+	return true
+}
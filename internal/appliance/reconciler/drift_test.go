@@ -0,0 +1,202 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+func TestDriftPolicy(t *testing.T) {
+	spec := func(policy config.DriftPolicy, extra string) []byte {
+		return []byte(strings.Replace(string(readSpecFixture(t, "repo-updater/default")),
+			`requestedVersion: "5.3.9104"`,
+			`requestedVersion: "5.3.9104"`+"\n  driftPolicy: "+string(policy)+extra, 1))
+	}
+	getDeployment := func(t *testing.T, c client.Client) *appsv1.Deployment {
+		t.Helper()
+		var dep appsv1.Deployment
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: renderedSpec.Namespace, Name: "repo-updater"}, &dep))
+		return &dep
+	}
+	requireInSync := func(t *testing.T, cm corev1.ConfigMap, status metav1.ConditionStatus, reason, message string) {
+		t.Helper()
+		sgStatus := statusFromAnnotations(cm.Annotations)
+		repoUpdater := sgStatus.Service("repo-updater")
+		requireCondition(t, repoUpdater.Conditions, config.ConditionInSync, status, message)
+		require.Equal(t, reason, meta.FindStatusCondition(repoUpdater.Conditions, config.ConditionInSync).Reason)
+	}
+	events := func(recorder *record.FakeRecorder) []string {
+		var events []string
+		for {
+			select {
+			case event := <-recorder.Events:
+				if strings.Contains(event, "Drift") {
+					events = append(events, event)
+				}
+			default:
+				return events
+			}
+		}
+	}
+
+	const drift = "Changed by hand: Deployment repo-updater: spec.minReadySeconds"
+
+	for _, tc := range []struct {
+		policy              config.DriftPolicy
+		wantMinReadySeconds int32
+		wantStatus          metav1.ConditionStatus
+		wantReason          string
+		wantEvents          []string
+	}{
+		{
+			policy:              config.DriftPolicyRevert,
+			wantMinReadySeconds: 10,
+			wantStatus:          metav1.ConditionTrue,
+			wantReason:          config.ReasonDriftReverted,
+			wantEvents:          []string{"Normal DriftReverted Reverted changes made by hand: Deployment repo-updater: spec.minReadySeconds"},
+		},
+		{
+			policy:              config.DriftPolicyIgnore,
+			wantMinReadySeconds: 30,
+			wantStatus:          metav1.ConditionTrue,
+			wantReason:          config.ReasonDriftIgnored,
+		},
+		{
+			policy:              config.DriftPolicyReport,
+			wantMinReadySeconds: 30,
+			wantStatus:          metav1.ConditionFalse,
+			wantReason:          config.ReasonDriftDetected,
+			wantEvents:          []string{"Warning DriftDetected " + drift},
+		},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(newSpecConfigMap(spec(tc.policy, ""))).Build()
+			recorder := record.NewFakeRecorder(100)
+			r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: recorder}
+
+			_, cm := reconcileSpecConfigMap(t, r)
+			requireInSync(t, cm, metav1.ConditionTrue, config.ReasonNoDrift, "")
+			_, cm = reconcileSpecConfigMap(t, r)
+			requireInSync(t, cm, metav1.ConditionTrue, config.ReasonNoDrift, "")
+			require.Empty(t, events(recorder))
+
+			// minReadySeconds is set by the appliance, but the label isn't,
+			// so only minReadySeconds drifts.
+			dep := getDeployment(t, c)
+			dep.Spec.MinReadySeconds = 30
+			dep.Labels["team"] = "source"
+			require.NoError(t, c.Update(context.Background(), dep))
+
+			_, cm = reconcileSpecConfigMap(t, r)
+			requireInSync(t, cm, tc.wantStatus, tc.wantReason, drift)
+			require.Equal(t, tc.wantEvents, events(recorder))
+			dep = getDeployment(t, c)
+			require.Equal(t, tc.wantMinReadySeconds, dep.Spec.MinReadySeconds)
+			require.Equal(t, "source", dep.Labels["team"], "fields the appliance doesn't set are kept")
+
+			// Reverted drift is gone, while drift that is kept is still
+			// listed.
+			_, cm = reconcileSpecConfigMap(t, r)
+			if tc.policy == config.DriftPolicyRevert {
+				requireInSync(t, cm, metav1.ConditionTrue, config.ReasonNoDrift, "")
+			} else {
+				requireInSync(t, cm, tc.wantStatus, tc.wantReason, drift)
+			}
+
+			// Changing the spec applies the change, but only reverts drift
+			// with the Revert policy.
+			var specCM corev1.ConfigMap
+			require.NoError(t, c.Get(context.Background(), renderedSpec, &specCM))
+			specCM.Data["spec"] = string(spec(tc.policy, "\n  labels:\n    env: prod"))
+			require.NoError(t, c.Update(context.Background(), &specCM))
+			_, _ = reconcileSpecConfigMap(t, r)
+			dep = getDeployment(t, c)
+			require.Equal(t, "prod", dep.Labels["env"])
+			require.Equal(t, "source", dep.Labels["team"])
+			require.Equal(t, tc.wantMinReadySeconds, dep.Spec.MinReadySeconds)
+		})
+	}
+}
+
+func TestDriftedFields(t *testing.T) {
+	want := map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{"app.kubernetes.io/name": "repo-updater"},
+		},
+		"spec": map[string]any{
+			"replicas": float64(1),
+			"containers": []any{
+				map[string]any{"name": "repo-updater", "image": "repo-updater:5.3.9104", "args": []any{"-v"}},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		live map[string]any
+		want []string
+	}{
+		{
+			name: "in sync, with fields set by others",
+			live: map[string]any{
+				"metadata": map[string]any{
+					"labels":          map[string]any{"app.kubernetes.io/name": "repo-updater", "team": "source"},
+					"resourceVersion": "3",
+				},
+				"spec": map[string]any{
+					"replicas": float64(1),
+					"containers": []any{
+						map[string]any{"name": "sidecar", "image": "sidecar"},
+						map[string]any{"name": "repo-updater", "image": "repo-updater:5.3.9104", "args": []any{"-v"}, "imagePullPolicy": "IfNotPresent"},
+					},
+				},
+			},
+		},
+		{
+			name: "drifted",
+			live: map[string]any{
+				"metadata": map[string]any{
+					"labels": map[string]any{},
+				},
+				"spec": map[string]any{
+					"replicas": float64(5),
+					"containers": []any{
+						map[string]any{"name": "repo-updater", "image": "repo-updater:custom", "args": []any{"-v", "-x"}},
+					},
+				},
+			},
+			want: []string{
+				"metadata.labels[app.kubernetes.io/name]",
+				"spec.containers[name=repo-updater].args",
+				"spec.containers[name=repo-updater].image",
+				"spec.replicas",
+			},
+		},
+		{
+			name: "container removed",
+			live: map[string]any{
+				"metadata": map[string]any{
+					"labels": map[string]any{"app.kubernetes.io/name": "repo-updater"},
+				},
+				"spec": map[string]any{"replicas": float64(1)},
+			},
+			want: []string{"spec.containers"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, driftedFields("", want, tc.live))
+		})
+	}
+}
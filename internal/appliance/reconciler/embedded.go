@@ -0,0 +1,67 @@
+package reconciler
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ReconcileSpec stores spec in the appliance ConfigMap name, creating it if it
+// doesn't exist, and reconciles it once with c, the same way the controller
+// does. Nothing is cached, no leader is elected, and nothing is requeued:
+// reconciling again, e.g. after Result.RequeueAfter, is up to the caller.
+// Events are discarded.
+//
+// This lets Sourcegraph be deployed from tests and tools without running a
+// manager. An invalid spec is returned as an error, rather than only being
+// recorded in the status.
+func ReconcileSpec(ctx context.Context, c client.Client, name types.NamespacedName, spec []byte) (Result, error) {
+	var cm corev1.ConfigMap
+	err := c.Get(ctx, name, &cm)
+	switch {
+	case apierrors.IsNotFound(err):
+		cm = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name.Name,
+				Namespace:   name.Namespace,
+				Labels:      map[string]string{"deploy": "sourcegraph"},
+				Annotations: map[string]string{config.AnnotationKeyManaged: "true"},
+			},
+			Data: map[string]string{"spec": string(spec)},
+		}
+		if err := c.Create(ctx, &cm); err != nil {
+			return Result{}, errors.Wrap(err, "creating appliance ConfigMap")
+		}
+	case err != nil:
+		return Result{}, errors.Wrap(err, "getting appliance ConfigMap")
+	case cm.GetAnnotations()[config.AnnotationKeyManaged] != "true":
+		return Result{}, errors.Newf("ConfigMap %s is not managed by the appliance, it lacks the %s annotation", name, config.AnnotationKeyManaged)
+	case cm.Data["spec"] != string(spec):
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data["spec"] = string(spec)
+		if err := c.Update(ctx, &cm); err != nil {
+			return Result{}, errors.Wrap(err, "updating appliance ConfigMap")
+		}
+	}
+
+	r := &Reconciler{Client: c, Scheme: c.Scheme(), Recorder: &record.FakeRecorder{}}
+	result, err := r.reconcile(ctx, name)
+	if err != nil {
+		return result, err
+	}
+	if ready := meta.FindStatusCondition(result.Status.Conditions, config.ConditionReady); ready != nil && ready.Reason == config.ReasonInvalidSpec {
+		return result, errors.Newf("invalid spec: %s", ready.Message)
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// EventReason is the reason of an Event that the appliance records on the
+// appliance ConfigMap.
+type EventReason string
+
+const (
+	// EventReasonReconcileFinished is recorded at the end of every reconcile.
+	EventReasonReconcileFinished EventReason = "ReconcileFinished"
+
+	// EventReasonInvalidSpec is recorded when the spec is rejected, listing
+	// every problem with it.
+	EventReasonInvalidSpec EventReason = "InvalidSpec"
+	// EventReasonSpecWarning and EventReasonSpecFieldWarning are recorded for
+	// every warning about a valid spec, and about its fields.
+	EventReasonSpecWarning      EventReason = "SpecWarning"
+	EventReasonSpecFieldWarning EventReason = "SpecFieldWarning"
+	// EventReasonImageVersionFallback is recorded when the default images of
+	// an earlier version are used, because the requested one has none.
+	EventReasonImageVersionFallback EventReason = "ImageVersionFallback"
+
+	// EventReasonVersionChangeDetected is recorded when another version than
+	// the current one is first requested.
+	EventReasonVersionChangeDetected EventReason = "VersionChangeDetected"
+	// EventReasonVersionReconciled is recorded when every service has been
+	// reconciled to a version that they didn't run before.
+	EventReasonVersionReconciled EventReason = "VersionReconciled"
+	// EventReasonUpgradePhase is recorded when a phase of an upgrade has
+	// rolled out.
+	EventReasonUpgradePhase EventReason = "UpgradePhase"
+	// EventReasonOperatorUpdated is recorded when the appliance moves its own
+	// Deployment to its image for the requested version, and
+	// EventReasonOperatorUpdateBlocked when it doesn't, since that image is
+	// older than the current version.
+	EventReasonOperatorUpdated       EventReason = "OperatorUpdated"
+	EventReasonOperatorUpdateBlocked EventReason = "OperatorUpdateBlocked"
+	// EventReasonMaintenanceMode is recorded when Sourcegraph is scaled down
+	// for maintenance, and when it is scaled back up.
+	EventReasonMaintenanceMode EventReason = "MaintenanceMode"
+
+	// EventReasonCreated is recorded for every object that the appliance
+	// creates.
+	EventReasonCreated EventReason = "Created"
+	// EventReasonDeleted is recorded for every object that the appliance
+	// deletes, e.g. because its service was disabled.
+	EventReasonDeleted EventReason = "Deleted"
+	// EventReasonVolumeExpansion is recorded when the storage requested by a
+	// PersistentVolumeClaim is raised.
+	EventReasonVolumeExpansion EventReason = "VolumeExpansionRequested"
+	// EventReasonRetained is recorded when data that the spec no longer
+	// requires is kept, because of the data retention policy, and
+	// EventReasonAdopted when it is required again.
+	EventReasonRetained EventReason = "Retained"
+	EventReasonAdopted  EventReason = "Adopted"
+	// EventReasonDeletionBlocked is recorded when the appliance ConfigMap is
+	// deleted without confirmation while the data retention policy is Retain.
+	EventReasonDeletionBlocked EventReason = "DeletionBlocked"
+
+	// EventReasonDriftReverted and EventReasonDriftDetected are recorded for
+	// changes made by hand, depending on the drift policy.
+	EventReasonDriftReverted EventReason = "DriftReverted"
+	EventReasonDriftDetected EventReason = "DriftDetected"
+
+	// EventReasonChangePending is recorded for changes to fields that can't
+	// be updated, which the appliance doesn't apply, and EventReasonRecreated
+	// when it deletes a StatefulSet to apply them, since
+	// AllowStatefulSetRecreate is set.
+	EventReasonChangePending EventReason = "ChangePending"
+	EventReasonRecreated     EventReason = "Recreated"
+)
+
+// eventType returns the type of the Events of a reason: Warning for those
+// that need attention, Normal for the others.
+func (reason EventReason) eventType() string {
+	switch reason {
+	case EventReasonInvalidSpec,
+		EventReasonSpecWarning,
+		EventReasonSpecFieldWarning,
+		EventReasonImageVersionFallback,
+		EventReasonOperatorUpdateBlocked,
+		EventReasonDeletionBlocked,
+		EventReasonDriftDetected,
+		EventReasonChangePending:
+		return corev1.EventTypeWarning
+	}
+	return corev1.EventTypeNormal
+}
+
+// eventRecorder records the Events of a reconcile on the appliance ConfigMap
+// being reconciled. The zero value drops them, e.g. when rendering.
+type eventRecorder struct {
+	recorder record.EventRecorder
+	target   runtime.Object
+}
+
+// eventf records an Event with the type of its reason.
+func (e eventRecorder) eventf(reason EventReason, messageFmt string, args ...any) {
+	if e.recorder == nil || e.target == nil {
+		return
+	}
+	e.recorder.Event(e.target, reason.eventType(), string(reason), fmt.Sprintf(messageFmt, args...))
+}
+
+type eventRecorderKey struct{}
+
+// withEventRecorder returns a context in which the Events that code deep in
+// the reconcile records, e.g. createOrUpdateObject, are recorded on target.
+func (r *Reconciler) withEventRecorder(ctx context.Context, target runtime.Object) (context.Context, eventRecorder) {
+	events := eventRecorder{recorder: r.Recorder, target: target}
+	return context.WithValue(ctx, eventRecorderKey{}, events), events
+}
+
+// eventsFrom returns the event recorder of ctx, or one that drops Events if
+// it has none.
+func eventsFrom(ctx context.Context) eventRecorder {
+	events, _ := ctx.Value(eventRecorderKey{}).(eventRecorder)
+	return events
+}
+
+// objectKind returns the kind of obj, for the messages of Events.
+func (r *Reconciler) objectKind(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return fmt.Sprintf("%T", obj)
+	}
+	return gvk.Kind
+}
@@ -0,0 +1,112 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileEvents(t *testing.T) {
+	ctx := context.Background()
+	spec := string(readSpecFixture(t, "repo-updater/default"))
+	c := fake.NewClientBuilder().WithObjects(newSpecConfigMap([]byte(spec))).Build()
+	recorder := record.NewFakeRecorder(1000)
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: recorder}
+
+	// events returns the Events recorded since it was last called, except
+	// for those that every reconcile may record.
+	events := func() []string {
+		var events []string
+		for {
+			select {
+			case event := <-recorder.Events:
+				if !strings.Contains(event, string(EventReasonReconcileFinished)) && !strings.Contains(event, string(EventReasonSpecWarning)) {
+					events = append(events, event)
+				}
+			default:
+				return events
+			}
+		}
+	}
+	updateSpec := func(t *testing.T, spec string) {
+		t.Helper()
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		cm.Data["spec"] = spec
+		require.NoError(t, c.Update(ctx, &cm))
+	}
+
+	t.Run("create", func(t *testing.T) {
+		reconcileSpecConfigMap(t, r)
+		require.Subset(t, events(), []string{
+			"Normal Created Created Deployment repo-updater.",
+			"Normal Created Created Service repo-updater.",
+			"Normal Created Created ServiceAccount repo-updater.",
+			"Normal VersionReconciled Every service has been reconciled to version 5.3.9104.",
+		})
+
+		// Reconciling again changes nothing.
+		reconcileSpecConfigMap(t, r)
+		require.Empty(t, events())
+	})
+
+	t.Run("update version", func(t *testing.T) {
+		spec = strings.Replace(spec, `requestedVersion: "5.3.9104"`, `requestedVersion: "5.3.9105"`+"\n  imageVersionFallback: true", 1)
+		updateSpec(t, spec)
+		reconcileSpecConfigMap(t, r)
+		require.Equal(t, []string{
+			"Normal VersionChangeDetected Version 5.3.9105 was requested, rolling it out over version 5.3.9104.",
+			"Warning ImageVersionFallback No default images for version 5.3.9105, using those of version 5.3.9104.",
+			"Normal VersionReconciled Every service has been reconciled to version 5.3.9105, from version 5.3.9104.",
+		}, events())
+
+		// The change is only detected once.
+		reconcileSpecConfigMap(t, r)
+		require.Equal(t, []string{
+			"Warning ImageVersionFallback No default images for version 5.3.9105, using those of version 5.3.9104.",
+		}, events())
+	})
+
+	t.Run("disable service", func(t *testing.T) {
+		updateSpec(t, strings.Replace(spec, "repoUpdater: {}", "repoUpdater:\n    disabled: true", 1))
+		reconcileSpecConfigMap(t, r)
+		require.Subset(t, events(), []string{
+			"Warning ImageVersionFallback No default images for version 5.3.9105, using those of version 5.3.9104.",
+			"Normal Deleted Deleted Deployment repo-updater, which the spec no longer requires.",
+			"Normal Deleted Deleted Service repo-updater, which the spec no longer requires.",
+			"Normal Deleted Deleted ServiceAccount repo-updater, which the spec no longer requires.",
+		})
+	})
+}
+
+func TestRecordVolumeExpansion(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{Recorder: recorder}
+	ctx, _ := r.withEventRecorder(context.Background(), &corev1.ConfigMap{})
+	claim := func(size string) client.Object {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "gitserver"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+
+	recordVolumeExpansion(ctx, claim("200Gi"), claim("200Gi"))
+	recordVolumeExpansion(ctx, claim("100Gi"), claim("200Gi"))
+	require.Empty(t, recorder.Events)
+
+	recordVolumeExpansion(ctx, claim("400Gi"), claim("200Gi"))
+	require.Equal(t, "Normal VolumeExpansionRequested Requested the expansion of PersistentVolumeClaim gitserver from 200Gi to 400Gi.", <-recorder.Events)
+}
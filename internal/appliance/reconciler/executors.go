@@ -0,0 +1,343 @@
+package reconciler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/role"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/rolebinding"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/secret"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// executorAccessTokenKey is the key of the token in the generated
+// executor-auth Secret.
+const executorAccessTokenKey = "token"
+
+func (r *Reconciler) reconcileExecutors(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	if err := r.reconcileExecutorPVC(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling PVC")
+	}
+	if err := r.reconcileExecutorDeployment(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Deployment")
+	}
+	if err := r.reconcilePodDisruptionBudget(ctx, sg, owner, sg.Spec.ObjectName("executor"), sg.Spec.Executors, sg.Spec.Executors.Replicas); err != nil {
+		return errors.Wrap(err, "reconciling PodDisruptionBudget")
+	}
+	if err := r.reconcileExecutorRole(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Role")
+	}
+	if err := r.reconcileExecutorRoleBinding(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling RoleBinding")
+	}
+	if err := r.reconcileExecutorServiceAccount(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling ServiceAccount")
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileExecutorDeployment(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := "executor"
+	cfg := sg.Spec.Executors
+
+	defaultImage, err := config.GetDefaultImage(sg, "executor")
+	if err != nil {
+		return err
+	}
+	stepImage, err := config.GetDefaultImage(sg, "batcheshelper")
+	if err != nil {
+		return err
+	}
+	ctr := container.NewContainer(name, cfg, config.ContainerConfig{
+		Image: defaultImage,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("500M"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1G"),
+			},
+		},
+	})
+
+	jobs := cfg.Kubernetes
+	jobNamespace := executorJobNamespace(sg)
+	ctr.Env = append(
+		ctr.Env,
+		corev1.EnvVar{Name: "EXECUTOR_FRONTEND_URL", Value: executorFrontendURL(sg)},
+		corev1.EnvVar{Name: "EXECUTOR_FRONTEND_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: executorAccessTokenRef(sg)}},
+		corev1.EnvVar{Name: "EXECUTOR_QUEUE_NAMES", Value: strings.Join(cfg.Queues, ",")},
+		corev1.EnvVar{Name: "EXECUTOR_MAXIMUM_NUM_JOBS", Value: strconv.Itoa(int(pointers.Deref(jobs.MaxConcurrentJobs, 1)))},
+		corev1.EnvVar{Name: "EXECUTOR_USE_FIRECRACKER", Value: "false"},
+		corev1.EnvVar{Name: "EXECUTOR_KUBERNETES_NAMESPACE", Value: jobNamespace},
+		corev1.EnvVar{Name: "KUBERNETES_SINGLE_JOB_STEP_IMAGE", Value: cfg.ContainerConfig["batcheshelper"].ImageFor(stepImage)},
+	)
+	ctr.Env = append(ctr.Env, executorJobResourceEnvVars(jobs.JobResources)...)
+	cacheOnPVC := !(executorCacheConfig{cfg, jobNamespace != sg.Namespace}).IsDisabled()
+	if cacheOnPVC {
+		// Jobs mount the executors' cache volume, so they must run on the
+		// executor's node.
+		ctr.Env = append(
+			ctr.Env,
+			corev1.EnvVar{Name: "EXECUTOR_KUBERNETES_PERSISTENCE_VOLUME_NAME", Value: sg.Spec.ObjectName(name)},
+			container.NewEnvVarFieldRef("EXECUTOR_KUBERNETES_NODE_NAME", "spec.nodeName"),
+		)
+	} else {
+		ctr.Env = append(
+			ctr.Env,
+			corev1.EnvVar{Name: "KUBERNETES_SINGLE_JOB_POD", Value: "true"},
+			corev1.EnvVar{Name: "KUBERNETES_JOB_VOLUME_TYPE", Value: "emptyDir"},
+		)
+	}
+	ctr.Env = append(ctr.Env, otelEnvVars(sg)...)
+	ctr.VolumeMounts = []corev1.VolumeMount{
+		{Name: "data", MountPath: "/data"},
+	}
+
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
+	if cacheOnPVC {
+		podTemplate.Template.Spec.Volumes = []corev1.Volume{pod.NewVolumeFromPVC("data", sg.Spec.ObjectName(name))}
+		// The cache volume is ReadWriteOnce, so every replica must run on
+		// the same node, unless the affinity is configured otherwise.
+		if cfg.Replicas > 1 && podTemplate.Template.Spec.Affinity == nil {
+			podTemplate.Template.Spec.Affinity = &corev1.Affinity{
+				PodAffinity: &corev1.PodAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"app": sg.Spec.ObjectName(name)},
+						},
+						TopologyKey: corev1.LabelHostname,
+					}},
+				},
+			}
+		}
+	} else {
+		podTemplate.Template.Spec.Volumes = []corev1.Volume{pod.NewVolumeEmptyDir("data")}
+	}
+
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	dep.Spec.Replicas = pointers.Ptr(cfg.Replicas)
+	if err := applyDeploymentStrategy(&dep, cfg); err != nil {
+		return err
+	}
+	dep.Spec.Template = podTemplate.Template
+
+	return reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner)
+}
+
+// executorJobResourceEnvVars returns the env vars that set the resources of
+// the executors' Jobs, leaving the executors' own defaults for those unset.
+func executorJobResourceEnvVars(resources *corev1.ResourceRequirements) []corev1.EnvVar {
+	if resources == nil {
+		return nil
+	}
+	var envVars []corev1.EnvVar
+	for _, r := range []struct {
+		name string
+		list corev1.ResourceList
+		key  corev1.ResourceName
+	}{
+		{"EXECUTOR_KUBERNETES_RESOURCE_REQUEST_CPU", resources.Requests, corev1.ResourceCPU},
+		{"EXECUTOR_KUBERNETES_RESOURCE_REQUEST_MEMORY", resources.Requests, corev1.ResourceMemory},
+		{"EXECUTOR_KUBERNETES_RESOURCE_LIMIT_CPU", resources.Limits, corev1.ResourceCPU},
+		{"EXECUTOR_KUBERNETES_RESOURCE_LIMIT_MEMORY", resources.Limits, corev1.ResourceMemory},
+	} {
+		if quantity, ok := r.list[r.key]; ok {
+			envVars = append(envVars, corev1.EnvVar{Name: r.name, Value: quantity.String()})
+		}
+	}
+	return envVars
+}
+
+func (r *Reconciler) reconcileExecutorPVC(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := executorCacheConfig{sg.Spec.Executors, executorJobNamespace(sg) != sg.Namespace}
+
+	if cfg.IsDisabled() {
+		p := corev1.PersistentVolumeClaim{}
+		p.Name, p.Namespace = sg.Spec.ObjectName("executor"), sg.Namespace
+		return reconcileObject(ctx, r, cfg, &p, &corev1.PersistentVolumeClaim{}, sg, owner)
+	}
+
+	p, err := pvc.NewPersistentVolumeClaim(sg.Spec.ObjectName("executor"), sg.Namespace, cfg)
+	if err != nil {
+		return err
+	}
+
+	return reconcileObject(ctx, r, cfg, &p, &corev1.PersistentVolumeClaim{}, sg, owner)
+}
+
+// reconcileExecutorRole grants the executors what they need to run their
+// Jobs. It only applies to Jobs in the namespace of Sourcegraph; those in
+// another namespace need the same permissions to be granted there.
+func (r *Reconciler) reconcileExecutorRole(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := sg.Spec.Executors
+
+	role := role.NewRole(sg.Spec.ObjectName("executor"), sg.Namespace)
+	role.Rules = []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"batch"},
+			Resources: []string{"jobs"},
+			Verbs:     []string{"create", "delete"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods", "pods/log"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		// Jobs that run in a single pod pass their secrets in a Secret.
+		{
+			APIGroups: []string{""},
+			Resources: []string{"secrets"},
+			Verbs:     []string{"create", "delete"},
+		},
+	}
+
+	return reconcileObject(ctx, r, cfg, &role, &rbacv1.Role{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileExecutorRoleBinding(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := sg.Spec.ObjectName("executor")
+	binding := rolebinding.NewRoleBinding(name, sg.Namespace)
+	binding.RoleRef = rbacv1.RoleRef{
+		Kind: "Role",
+		Name: name,
+	}
+	binding.Subjects = []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      name,
+			Namespace: sg.Namespace,
+		},
+	}
+
+	return reconcileObject(ctx, r, sg.Spec.Executors, &binding, &rbacv1.RoleBinding{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileExecutorServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("executor"), sg.Spec.Executors)
+}
+
+// reconcileExecutorSecret reconciles the Secret holding the token that the
+// executors and the frontend share, unless the spec references one of its
+// own. The token is generated once, and kept by later reconciles.
+func (r *Reconciler) reconcileExecutorSecret(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := executorSecretConfig{sg.Spec.Executors}
+	scrt := secret.NewSecret(sg.Spec.ObjectName("executor-auth"), sg.Namespace, sg.Spec.RequestedVersion)
+	if cfg.IsDisabled() {
+		return reconcileObject(ctx, r, cfg, &scrt, &corev1.Secret{}, sg, owner)
+	}
+
+	var existing corev1.Secret
+	found, err := r.getDependency(ctx, scrt.Name, sg.Namespace, &existing)
+	if err != nil {
+		return err
+	}
+	token := existing.Data[executorAccessTokenKey]
+	if !found || len(token) == 0 {
+		generated, err := generateExecutorAccessToken()
+		if err != nil {
+			return err
+		}
+		token = []byte(generated)
+	}
+	scrt.Data = map[string][]byte{executorAccessTokenKey: token}
+
+	return reconcileObject(ctx, r, cfg, &scrt, &corev1.Secret{}, sg, owner)
+}
+
+func generateExecutorAccessToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "generating executor access token")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// executorAccessTokenRef returns the key of the Secret holding the token
+// that the executors and the frontend share.
+func executorAccessTokenRef(sg *config.Sourcegraph) *corev1.SecretKeySelector {
+	if ref := sg.Spec.Executors.AccessTokenSecretRef; ref != nil {
+		return ref.DeepCopy()
+	}
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: sg.Spec.ObjectName("executor-auth")},
+		Key:                  executorAccessTokenKey,
+	}
+}
+
+// executorJobNamespace returns the namespace that the executors run their
+// Jobs in.
+func executorJobNamespace(sg *config.Sourcegraph) string {
+	if ns := sg.Spec.Executors.Kubernetes.Namespace; ns != "" {
+		return ns
+	}
+	return sg.Namespace
+}
+
+// executorFrontendURL returns the URL of the frontend that the executors, and
+// the pods of their Jobs, reach it at. It is fully qualified when the Jobs run
+// in another namespace.
+func executorFrontendURL(sg *config.Sourcegraph) string {
+	host := sg.Spec.ObjectName("sourcegraph-frontend")
+	if executorJobNamespace(sg) != sg.Namespace {
+		host += "." + sg.Namespace + ".svc"
+	}
+	return serviceScheme(sg, sg.Spec.Frontend) + "://" + host + ":30080"
+}
+
+// executorCacheConfig wraps an ExecutorsSpec for the cache volume that the
+// executors share with their Jobs, which can't be mounted by Jobs in another
+// namespace.
+type executorCacheConfig struct {
+	config.ExecutorsSpec
+	jobsInOtherNamespace bool
+}
+
+func (c executorCacheConfig) IsDisabled() bool {
+	return c.Disabled || c.jobsInOtherNamespace || c.PersistentVolumeConfig.StorageSize == ""
+}
+
+// executorSecretConfig wraps an ExecutorsSpec for the generated executor-auth
+// Secret, which isn't needed when the spec references a Secret of its own.
+type executorSecretConfig struct {
+	config.ExecutorsSpec
+}
+
+func (c executorSecretConfig) IsDisabled() bool {
+	return c.Disabled || c.AccessTokenSecretRef != nil
+}
+
+// executorsFrontendEnvVars returns the env vars that give the frontend the
+// token that the executors authenticate with, if they are enabled.
+func executorsFrontendEnvVars(sg *config.Sourcegraph) []corev1.EnvVar {
+	if sg.Spec.Executors.IsDisabled() {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{Name: "EXECUTORS_ACCESS_TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: executorAccessTokenRef(sg)}},
+	}
+}
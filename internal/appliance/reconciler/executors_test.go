@@ -0,0 +1,127 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestExecutors(t *testing.T) {
+	// render returns the rendered Deployments and Secrets by name, and the
+	// kinds of the other objects by name.
+	render := func(t *testing.T, spec string) (map[string]appsv1.Deployment, map[string]corev1.Secret, map[string][]string) {
+		t.Helper()
+		objs, err := Render(context.Background(), renderedSpec, []byte(spec))
+		require.NoError(t, err)
+		deps, secrets, kinds := map[string]appsv1.Deployment{}, map[string]corev1.Secret{}, map[string][]string{}
+		for _, obj := range objs {
+			u := obj.(*unstructured.Unstructured)
+			kinds[u.GetName()] = append(kinds[u.GetName()], u.GetKind())
+			switch u.GetKind() {
+			case "Deployment":
+				var dep appsv1.Deployment
+				require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &dep))
+				deps[u.GetName()] = dep
+			case "Secret":
+				var secret corev1.Secret
+				require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &secret))
+				secrets[u.GetName()] = secret
+			}
+		}
+		return deps, secrets, kinds
+	}
+	envOf := func(t *testing.T, dep appsv1.Deployment) map[string]corev1.EnvVar {
+		t.Helper()
+		require.NotEmpty(t, dep.Spec.Template.Spec.Containers)
+		env := map[string]corev1.EnvVar{}
+		for _, e := range dep.Spec.Template.Spec.Containers[0].Env {
+			env[e.Name] = e
+		}
+		return env
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		deps, secrets, kinds := render(t, `
+spec:
+  requestedVersion: "5.3.9104"
+`)
+		require.NotContains(t, deps, "executor")
+		require.NotContains(t, secrets, "executor-auth")
+		require.NotContains(t, kinds, "executor")
+		require.NotContains(t, envOf(t, deps["sourcegraph-frontend"]), "EXECUTORS_ACCESS_TOKEN")
+	})
+
+	t.Run("batches and codeintel", func(t *testing.T) {
+		deps, secrets, kinds := render(t, `
+spec:
+  requestedVersion: "5.3.9104"
+  executors:
+    disabled: false
+    kubernetes:
+      maxConcurrentJobs: 4
+      jobResources:
+        limits:
+          memory: 4Gi
+`)
+		require.ElementsMatch(t, []string{"Deployment", "PersistentVolumeClaim", "Role", "RoleBinding", "ServiceAccount"}, kinds["executor"])
+
+		secret, ok := secrets["executor-auth"]
+		require.True(t, ok)
+		require.Len(t, secret.Data["token"], 64)
+
+		dep := deps["executor"]
+		require.Equal(t, "executor", dep.Spec.Template.Spec.ServiceAccountName)
+		require.Equal(t, "executor", dep.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		env := envOf(t, dep)
+		require.Equal(t, "batches,codeintel", env["EXECUTOR_QUEUE_NAMES"].Value)
+		require.Equal(t, "http://sourcegraph-frontend:30080", env["EXECUTOR_FRONTEND_URL"].Value)
+		require.Equal(t, "4", env["EXECUTOR_MAXIMUM_NUM_JOBS"].Value)
+		require.Equal(t, "4Gi", env["EXECUTOR_KUBERNETES_RESOURCE_LIMIT_MEMORY"].Value)
+		require.NotContains(t, env, "EXECUTOR_KUBERNETES_RESOURCE_REQUEST_MEMORY")
+		require.Equal(t, renderedSpec.Namespace, env["EXECUTOR_KUBERNETES_NAMESPACE"].Value)
+		require.Equal(t, "executor", env["EXECUTOR_KUBERNETES_PERSISTENCE_VOLUME_NAME"].Value)
+		require.Equal(t, "spec.nodeName", env["EXECUTOR_KUBERNETES_NODE_NAME"].ValueFrom.FieldRef.FieldPath)
+		require.Equal(t, "index.docker.io/sourcegraph/batcheshelper:5.3.2", env["KUBERNETES_SINGLE_JOB_STEP_IMAGE"].Value)
+
+		// The frontend accepts the token that the executors authenticate
+		// with.
+		password := env["EXECUTOR_FRONTEND_PASSWORD"].ValueFrom.SecretKeyRef
+		require.Equal(t, "executor-auth", password.Name)
+		require.Equal(t, "token", password.Key)
+		require.Equal(t, password, envOf(t, deps["sourcegraph-frontend"])["EXECUTORS_ACCESS_TOKEN"].ValueFrom.SecretKeyRef)
+	})
+
+	t.Run("referenced token and jobs in another namespace", func(t *testing.T) {
+		deps, secrets, kinds := render(t, `
+spec:
+  requestedVersion: "5.3.9104"
+  executors:
+    disabled: false
+    queues: [batches]
+    accessTokenSecretRef:
+      name: executors
+      key: password
+    kubernetes:
+      namespace: sourcegraph-jobs
+`)
+		require.NotContains(t, secrets, "executor-auth")
+		require.NotContains(t, kinds["executor"], "PersistentVolumeClaim")
+
+		env := envOf(t, deps["executor"])
+		require.Equal(t, "batches", env["EXECUTOR_QUEUE_NAMES"].Value)
+		require.Equal(t, "sourcegraph-jobs", env["EXECUTOR_KUBERNETES_NAMESPACE"].Value)
+		require.Equal(t, "http://sourcegraph-frontend."+renderedSpec.Namespace+".svc:30080", env["EXECUTOR_FRONTEND_URL"].Value)
+		require.Equal(t, "true", env["KUBERNETES_SINGLE_JOB_POD"].Value)
+		require.NotContains(t, env, "EXECUTOR_KUBERNETES_PERSISTENCE_VOLUME_NAME")
+
+		password := env["EXECUTOR_FRONTEND_PASSWORD"].ValueFrom.SecretKeyRef
+		require.Equal(t, "executors", password.Name)
+		require.Equal(t, "password", password.Key)
+		require.Equal(t, password, envOf(t, deps["sourcegraph-frontend"])["EXECUTORS_ACCESS_TOKEN"].ValueFrom.SecretKeyRef)
+	})
+}
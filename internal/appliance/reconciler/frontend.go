@@ -0,0 +1,371 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/ingress"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/role"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/rolebinding"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func (r *Reconciler) reconcileFrontend(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	// The frontend isn't rolled out with an invalid site configuration.
+	if err := r.reconcileSiteConfigSecret(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling site config Secret")
+	}
+	if err := r.reconcileFrontendDeployment(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Deployment")
+	}
+	if err := r.reconcilePodDisruptionBudget(ctx, sg, owner, sg.Spec.ObjectName("sourcegraph-frontend"), sg.Spec.Frontend, sg.Spec.Frontend.Replicas); err != nil {
+		return errors.Wrap(err, "reconciling PodDisruptionBudget")
+	}
+	if err := r.reconcileFrontendService(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Service")
+	}
+	if err := r.reconcileFrontendInternalService(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling internal Service")
+	}
+	if err := r.reconcileFrontendServiceAccount(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling ServiceAccount")
+	}
+	if err := r.reconcileFrontendRole(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Role")
+	}
+	if err := r.reconcileFrontendRoleBinding(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling RoleBinding")
+	}
+	if err := r.reconcileFrontendIngress(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Ingress")
+	}
+	if err := r.reconcileFrontendRoute(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Route")
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileFrontendDeployment(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := sg.Spec.ObjectName("sourcegraph-frontend")
+	cfg := sg.Spec.Frontend
+
+	defaultImage, err := config.GetDefaultImage(sg, "frontend")
+	if err != nil {
+		return err
+	}
+	ctr := container.NewContainer("frontend", cfg, config.ContainerConfig{
+		Image: defaultImage,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("2G"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4G"),
+			},
+		},
+	})
+	ctr.Args = []string{"serve"}
+
+	ctr.Env = append(ctr.Env, gatePgsql.clientEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, gateCodeIntel.clientEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, gateCodeInsights.clientEnvVars(sg)...)
+	ctr.Env = append(
+		ctr.Env,
+		corev1.EnvVar{Name: "SRC_GIT_SERVERS", Value: frontendGitServers(sg)},
+		container.NewEnvVarFieldRef("POD_NAME", "metadata.name"),
+		corev1.EnvVar{Name: "CACHE_DIR", Value: "/mnt/cache/$(POD_NAME)"},
+		corev1.EnvVar{Name: "PROMETHEUS_URL", Value: serviceScheme(sg, sg.Spec.Prometheus) + "://" + sg.Spec.ObjectName("prometheus") + ":30090"},
+	)
+	ctr.Env = append(ctr.Env, redisEnvVars(sg)...)
+	ctr.Env = addPreciseCodeIntelBlobstoreVars(ctr.Env, sg)
+	ctr.Env = append(ctr.Env, executorsFrontendEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, otelEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, serviceEndpointEnvVars(sg)...)
+
+	ctr.Ports = []corev1.ContainerPort{
+		{Name: "http", ContainerPort: 3080},
+		{Name: "http-internal", ContainerPort: 3090},
+		{Name: "debug", ContainerPort: 6060},
+	}
+	ctr.LivenessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromString("debug"),
+			},
+		},
+		InitialDelaySeconds: 300,
+		TimeoutSeconds:      5,
+	}
+	ctr.ReadinessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/ready",
+				Port: intstr.FromString("debug"),
+			},
+		},
+		PeriodSeconds:  5,
+		TimeoutSeconds: 5,
+	}
+	ctr.VolumeMounts = []corev1.VolumeMount{
+		{Name: "cache-ssd", MountPath: "/mnt/cache"},
+	}
+
+	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate.Template.Spec.ServiceAccountName = name
+	podTemplate.Template.Spec.Volumes = []corev1.Volume{
+		pod.NewVolumeEmptyDir("cache-ssd"),
+	}
+	applySiteConfig(&podTemplate.Template, &podTemplate.Template.Spec.Containers[0], sg)
+
+	if err := applyMigrationGate(&podTemplate.Template, sg, cfg, gatePgsql, gateCodeIntel, gateCodeInsights); err != nil {
+		return err
+	}
+	applyTopologySpread(&podTemplate.Template, cfg, cfg.Replicas)
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	dep := deployment.NewDeployment(name, sg.Namespace, sg.Spec.RequestedVersion)
+	dep.Spec.Replicas = pointers.Ptr(cfg.Replicas)
+	if err := applyDeploymentStrategy(&dep, cfg); err != nil {
+		return err
+	}
+	dep.Spec.Template = podTemplate.Template
+
+	return reconcileObject(ctx, r, frontendDeploymentConfig{cfg, sg.Spec.GitServer.Replicas}, &dep, &appsv1.Deployment{}, sg, owner)
+}
+
+// frontendDeploymentConfig wraps a FrontendSpec for the frontend Deployment,
+// which is updated when the number of gitserver replicas changes, since
+// SRC_GIT_SERVERS lists them.
+type frontendDeploymentConfig struct {
+	config.FrontendSpec
+	GitServerReplicas int32 `json:"gitServerReplicas"`
+}
+
+// frontendGitServers returns the space-separated list of gitserver addresses
+// that the frontend shards repositories across. The number of replicas is the
+// one settled by resolveGitServerReplicas, so that it matches the StatefulSet
+// even while a reduction is blocked.
+func frontendGitServers(sg *config.Sourcegraph) string {
+	name := sg.Spec.ObjectName("gitserver")
+	addrs := make([]string, 0, sg.Spec.GitServer.Replicas)
+	for i := int32(0); i < sg.Spec.GitServer.Replicas; i++ {
+		addrs = append(addrs, fmt.Sprintf("%s-%d.%s:3178", name, i, name))
+	}
+	return strings.Join(addrs, " ")
+}
+
+func (r *Reconciler) reconcileFrontendService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := sg.Spec.ObjectName("sourcegraph-frontend")
+	cfg := sg.Spec.Frontend
+
+	svc := service.NewService(name, sg.Namespace, cfg)
+	svc.Spec.Ports = []corev1.ServicePort{
+		{Name: "http", Port: 30080, TargetPort: intstr.FromString("http")},
+	}
+	svc.Spec.Selector = map[string]string{
+		"app": name,
+	}
+
+	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileFrontendInternalService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := sg.Spec.Frontend
+
+	svc := service.NewService(sg.Spec.ObjectName("sourcegraph-frontend-internal"), sg.Namespace, nil)
+	svc.Spec.Ports = []corev1.ServicePort{
+		{Name: "http-internal", Port: 80, TargetPort: intstr.FromString("http-internal")},
+	}
+	svc.Spec.Selector = map[string]string{
+		"app": sg.Spec.ObjectName("sourcegraph-frontend"),
+	}
+
+	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileFrontendServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("sourcegraph-frontend"), sg.Spec.Frontend)
+}
+
+func (r *Reconciler) reconcileFrontendRole(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := sg.Spec.Frontend
+
+	// The frontend discovers other services by watching their endpoints.
+	role := role.NewRole(sg.Spec.ObjectName("sourcegraph-frontend"), sg.Namespace)
+	role.Rules = []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"endpoints", "pods", "services"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+	}
+
+	return reconcileObject(ctx, r, cfg, &role, &rbacv1.Role{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileFrontendRoleBinding(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := sg.Spec.ObjectName("sourcegraph-frontend")
+	binding := rolebinding.NewRoleBinding(name, sg.Namespace)
+	binding.RoleRef = rbacv1.RoleRef{
+		Kind: "Role",
+		Name: name,
+	}
+	binding.Subjects = []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      name,
+			Namespace: sg.Namespace,
+		},
+	}
+
+	return reconcileObject(ctx, r, sg.Spec.Frontend, &binding, &rbacv1.RoleBinding{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileFrontendIngress(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := sg.Spec.ObjectName("sourcegraph-frontend")
+	cfg := frontendIngressConfig{FrontendSpec: sg.Spec.Frontend, kind: config.IngressKindIngress}
+	if cfg.IsDisabled() {
+		ing := ingress.NewIngress(name, sg.Namespace)
+		return reconcileObject(ctx, r, cfg, &ing, &networkingv1.Ingress{}, sg, owner)
+	}
+	ingressCfg := *cfg.Ingress
+	if err := ingressCfg.Validate(); err != nil {
+		return errors.Wrap(err, "validating ingress config")
+	}
+
+	ing := ingress.NewIngress(name, sg.Namespace)
+	ing.SetAnnotations(maps.Clone(ingressCfg.Annotations))
+	if ingressCfg.IngressClassName != "" {
+		ing.Spec.IngressClassName = pointers.Ptr(ingressCfg.IngressClassName)
+	}
+	ing.Spec.Rules = []networkingv1.IngressRule{
+		{
+			Host: ingressCfg.Host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{
+						{
+							Path:     "/",
+							PathType: pointers.Ptr(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: name,
+									Port: networkingv1.ServiceBackendPort{Number: 30080},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if ingressCfg.TLSSecret != "" {
+		ing.Spec.TLS = []networkingv1.IngressTLS{
+			{
+				Hosts:      []string{ingressCfg.Host},
+				SecretName: ingressCfg.TLSSecret,
+			},
+		}
+	}
+
+	return reconcileObject(ctx, r, cfg, &ing, &networkingv1.Ingress{}, sg, owner)
+}
+
+var routeGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+// reconcileFrontendRoute manages an OpenShift Route. Routes are not part of
+// the core API, so they are handled as unstructured objects, and clusters
+// without the Route API are tolerated as long as no Route is requested.
+func (r *Reconciler) reconcileFrontendRoute(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := sg.Spec.ObjectName("sourcegraph-frontend")
+	cfg := frontendIngressConfig{FrontendSpec: sg.Spec.Frontend, kind: config.IngressKindRoute}
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(routeGVK)
+	route.SetName(name)
+	route.SetNamespace(sg.Namespace)
+	route.SetLabels(map[string]string{"deploy": "sourcegraph"})
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(routeGVK)
+
+	if _, err := r.Client.RESTMapper().RESTMapping(routeGVK.GroupKind(), routeGVK.Version); err != nil {
+		if !meta.IsNoMatchError(err) {
+			return err
+		}
+		if cfg.IsDisabled() {
+			// Nothing can have been created on a cluster without Routes.
+			return nil
+		}
+		return errors.New("ingress.kind is Route, but the cluster does not serve the route.openshift.io/v1 API")
+	}
+	if cfg.IsDisabled() {
+		return reconcileObject(ctx, r, cfg, route, existing, sg, owner)
+	}
+	ingressCfg := *cfg.Ingress
+	if err := ingressCfg.Validate(); err != nil {
+		return errors.Wrap(err, "validating ingress config")
+	}
+
+	route.SetAnnotations(maps.Clone(ingressCfg.Annotations))
+	spec := map[string]any{
+		"to": map[string]any{
+			"kind": "Service",
+			"name": name,
+		},
+		"port": map[string]any{
+			"targetPort": "http",
+		},
+	}
+	if ingressCfg.Host != "" {
+		spec["host"] = ingressCfg.Host
+	}
+	if ingressCfg.TLSSecret != "" {
+		// Routes can't reference a TLS secret, so TLS is terminated at the
+		// router with its default certificate.
+		spec["tls"] = map[string]any{
+			"termination":                   "edge",
+			"insecureEdgeTerminationPolicy": "Redirect",
+		}
+	}
+	route.Object["spec"] = spec
+
+	return reconcileObject(ctx, r, cfg, route, existing, sg, owner)
+}
+
+// frontendIngressConfig wraps a FrontendSpec for the object that exposes the
+// frontend outside the cluster, which is only created when an ingress of the
+// matching kind is configured.
+type frontendIngressConfig struct {
+	config.FrontendSpec
+	kind config.IngressKind
+}
+
+func (c frontendIngressConfig) IsDisabled() bool {
+	return c.Disabled || c.Ingress == nil || c.Ingress.Disabled || c.Ingress.GetKind() != c.kind
+}
@@ -0,0 +1,28 @@
+package reconciler
+
+func (suite *ApplianceTestSuite) TestDeployFrontend() {
+	for _, tc := range []struct {
+		name string
+	}{
+		{name: "frontend/default"},
+		{name: "frontend/with-ingress"},
+		{name: "frontend/with-sidecar"},
+	} {
+		suite.Run(tc.name, func() {
+			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
+			suite.makeGoldenAssertions(namespace, tc.name)
+		})
+	}
+}
+
+func (suite *ApplianceTestSuite) TestFrontendIngressDeletedWhenRemoved() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("frontend/with-ingress")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "frontend/default")
+	suite.makeGoldenAssertions(namespace, "frontend/subsequent-ingress-removal")
+}
+
+func (suite *ApplianceTestSuite) TestFrontendSidecarDeletedWhenRemoved() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("frontend/with-sidecar")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "frontend/default")
+	suite.makeGoldenAssertions(namespace, "frontend/subsequent-sidecar-removal")
+}
@@ -2,13 +2,18 @@ package reconciler
 
 import (
 	"context"
+	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 
 	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
@@ -16,7 +21,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/statefulset"
 )
 
@@ -24,6 +28,9 @@ func (r *Reconciler) reconcileGitServer(ctx context.Context, sg *config.Sourcegr
 	if err := r.reconcileGitServerStatefulSet(ctx, sg, owner); err != nil {
 		return err
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, sg, owner, sg.Spec.ObjectName("gitserver"), sg.Spec.GitServer, sg.Spec.GitServer.Replicas); err != nil {
+		return err
+	}
 	if err := r.reconcileGitServerService(ctx, sg, owner); err != nil {
 		return err
 	}
@@ -55,8 +62,9 @@ func (r *Reconciler) reconcileGitServerStatefulSet(ctx context.Context, sg *conf
 		},
 	})
 
-	ctr.Env = append(ctr.Env, container.EnvVarsRedis()...)
-	ctr.Env = append(ctr.Env, container.EnvVarsOtel()...)
+	ctr.Env = append(ctr.Env, redisEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, otelEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, serviceEndpointEnvVars(sg)...)
 
 	ctr.Ports = []corev1.ContainerPort{
 		{Name: "rpc", ContainerPort: 3178},
@@ -96,9 +104,9 @@ func (r *Reconciler) reconcileGitServerStatefulSet(ctx context.Context, sg *conf
 		})
 	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
 	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
-	podTemplate.Template.Spec.ServiceAccountName = name
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
 	podTemplate.Template.Spec.Volumes = podVolumes
 
 	pvc, err := pvc.NewPersistentVolumeClaim("repos", sg.Namespace, sg.Spec.GitServer)
@@ -106,7 +114,14 @@ func (r *Reconciler) reconcileGitServerStatefulSet(ctx context.Context, sg *conf
 		return err
 	}
 
-	sset := statefulset.NewStatefulSet(name, sg.Namespace, sg.Spec.RequestedVersion)
+	applyTopologySpread(&podTemplate.Template, cfg, cfg.Replicas)
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	sset := statefulset.NewStatefulSet(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	sset.Spec.Replicas = pointers.Ptr(cfg.Replicas)
+	applyStatefulSetStrategy(&sset, cfg)
 	sset.Spec.Template = podTemplate.Template
 	sset.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{pvc}
 
@@ -114,12 +129,12 @@ func (r *Reconciler) reconcileGitServerStatefulSet(ctx context.Context, sg *conf
 }
 
 func (r *Reconciler) reconcileGitServerService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	svc := service.NewService("gitserver", sg.Namespace, sg.Spec.GitServer)
+	svc := service.NewService(sg.Spec.ObjectName("gitserver"), sg.Namespace, sg.Spec.GitServer)
 	svc.Spec.Ports = []corev1.ServicePort{
 		{Name: "unused", TargetPort: intstr.FromInt32(10811), Port: 10811},
 	}
 	svc.Spec.Selector = map[string]string{
-		"app":  "gitserver",
+		"app":  sg.Spec.ObjectName("gitserver"),
 		"type": "gitserver",
 	}
 
@@ -127,7 +142,93 @@ func (r *Reconciler) reconcileGitServerService(ctx context.Context, sg *config.S
 }
 
 func (r *Reconciler) reconcileGitServerServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.GitServer
-	sa := serviceaccount.NewServiceAccount("gitserver", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, sg.Spec.GitServer, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("gitserver"), sg.Spec.GitServer)
+}
+
+// gitServerScaling describes how the number of gitserver replicas changes in a
+// reconcile.
+type gitServerScaling struct {
+	// enabled is whether gitserver is enabled.
+	enabled bool
+
+	// from is the number of replicas the StatefulSet currently has, and to the
+	// number it is reconciled to. They are the same if the StatefulSet doesn't
+	// exist yet, since there are no repositories to redistribute.
+	from, to int32
+
+	// blocked is whether a reduction of the replicas was requested, but not
+	// allowed by GitServerSpec.AllowScaleDown.
+	blocked bool
+}
+
+// resolveGitServerReplicas compares the requested number of gitserver replicas
+// with that of the existing StatefulSet. A reduction that isn't allowed is
+// undone by resetting sg.Spec.GitServer.Replicas to the current number, which
+// must happen before any service is reconciled, so that SRC_GIT_SERVERS of
+// all consumers agrees with the replicas gitserver actually runs.
+func (r *Reconciler) resolveGitServerReplicas(ctx context.Context, sg *config.Sourcegraph) (gitServerScaling, error) {
+	cfg := &sg.Spec.GitServer
+	if cfg.IsDisabled() {
+		return gitServerScaling{}, nil
+	}
+
+	var sset appsv1.StatefulSet
+	if err := r.Get(ctx, types.NamespacedName{Namespace: sg.Namespace, Name: sg.Spec.ObjectName("gitserver")}, &sset); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return gitServerScaling{}, errors.Wrap(err, "getting gitserver StatefulSet")
+		}
+		return gitServerScaling{enabled: true, from: cfg.Replicas, to: cfg.Replicas}, nil
+	}
+
+	// A StatefulSet scaled down for maintenance records how many replicas it
+	// is restored to.
+	current, ok := replicasBeforeMaintenance(&sset)
+	if !ok {
+		current = pointers.Deref(sset.Spec.Replicas, 1)
+	}
+
+	scaling := gitServerScaling{enabled: true, from: current, to: cfg.Replicas}
+	if cfg.Replicas < current && !cfg.AllowScaleDown {
+		scaling.blocked = true
+		scaling.to = current
+		cfg.Replicas = current
+	}
+	return scaling, nil
+}
+
+// setGitServerShardsCondition sets the ShardsStable condition of gitserver.
+// Once the StatefulSet has been scaled, the condition stays false until the
+// service is available again.
+func setGitServerShardsCondition(svc *config.ServiceStatus, scaling gitServerScaling, requested int32) {
+	if !scaling.enabled {
+		meta.RemoveStatusCondition(&svc.Conditions, config.ConditionShardsStable)
+		return
+	}
+
+	switch {
+	case scaling.blocked:
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:    config.ConditionShardsStable,
+			Status:  metav1.ConditionFalse,
+			Reason:  config.ReasonScaleDownBlocked,
+			Message: fmt.Sprintf("Not scaling gitserver down from %d to %d replicas, since spec.gitServer.allowScaleDown is not set. Repositories on the removed replicas' volumes would be left behind.", scaling.from, requested),
+		})
+	case scaling.from != scaling.to:
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:    config.ConditionShardsStable,
+			Status:  metav1.ConditionFalse,
+			Reason:  config.ReasonReplicasChanged,
+			Message: fmt.Sprintf("Scaling gitserver from %d to %d replicas. Repositories are redistributed across the replicas, and may be missing until they have been cloned again.", scaling.from, scaling.to),
+		})
+	default:
+		previous := meta.FindStatusCondition(svc.Conditions, config.ConditionShardsStable)
+		if previous != nil && previous.Reason == config.ReasonReplicasChanged && !meta.IsStatusConditionTrue(svc.Conditions, config.ConditionAvailable) {
+			return
+		}
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:   config.ConditionShardsStable,
+			Status: metav1.ConditionTrue,
+			Reason: config.ReasonShardsUnchanged,
+		})
+	}
 }
@@ -1,10 +1,32 @@
 package reconciler
 
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
 func (suite *ApplianceTestSuite) TestDeployGitServer() {
 	for _, tc := range []struct {
 		name string
 	}{
 		{name: "gitserver/default"},
+		{name: "gitserver/with-managed-priority-classes"},
+		{name: "gitserver/with-probe-overrides"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -12,3 +34,162 @@ func (suite *ApplianceTestSuite) TestDeployGitServer() {
 		})
 	}
 }
+
+func (suite *ApplianceTestSuite) TestPriorityClassesDeletedWhenUnmanaged() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("gitserver/with-managed-priority-classes")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "gitserver/default")
+	suite.makeGoldenAssertions(namespace, "gitserver/subsequent-unmanaged-priority-classes")
+}
+
+func TestGitServerScaling(t *testing.T) {
+	spec := func(gitServer string) []byte {
+		s := strings.Replace(string(readSpecFixture(t, "gitserver/default")), `
+  frontend:
+    disabled: true
+`, "\n  frontend: {}\n", 1)
+		return []byte(strings.Replace(s, "gitServer: {}", "gitServer:\n"+gitServer, 1))
+	}
+	setSpec := func(t *testing.T, c client.Client, gitServer string) {
+		t.Helper()
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(context.Background(), renderedSpec, &cm))
+		cm.Data["spec"] = string(spec(gitServer))
+		require.NoError(t, c.Update(context.Background(), &cm))
+	}
+	requireReplicas := func(t *testing.T, c client.Client, replicas int32, gitServers string) {
+		t.Helper()
+		var sset appsv1.StatefulSet
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: renderedSpec.Namespace, Name: "gitserver"}, &sset))
+		require.Equal(t, replicas, *sset.Spec.Replicas)
+
+		var dep appsv1.Deployment
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: renderedSpec.Namespace, Name: "sourcegraph-frontend"}, &dep))
+		for _, ctr := range append(dep.Spec.Template.Spec.InitContainers, dep.Spec.Template.Spec.Containers...) {
+			for _, env := range ctr.Env {
+				if env.Name == "SRC_GIT_SERVERS" {
+					require.Equal(t, gitServers, env.Value, ctr.Name)
+				}
+			}
+		}
+	}
+	setup := func(t *testing.T) (client.Client, *Reconciler) {
+		c := fake.NewClientBuilder().WithObjects(newSpecConfigMap(spec("    replicas: 2\n"))).Build()
+		r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}}
+		_, cm := reconcileSpecConfigMap(t, r)
+
+		// A new StatefulSet has no repositories to redistribute.
+		requireReplicas(t, c, 2, "gitserver-0.gitserver:3178 gitserver-1.gitserver:3178")
+		requireCondition(t, gitServerStatus(cm).Conditions, config.ConditionShardsStable, metav1.ConditionTrue, "")
+		return c, r
+	}
+
+	t.Run("scale up", func(t *testing.T) {
+		c, r := setup(t)
+		setSpec(t, c, "    replicas: 3\n")
+		_, cm := reconcileSpecConfigMap(t, r)
+		requireReplicas(t, c, 3, "gitserver-0.gitserver:3178 gitserver-1.gitserver:3178 gitserver-2.gitserver:3178")
+		gitserver := gitServerStatus(cm)
+		requireCondition(t, gitserver.Conditions, config.ConditionShardsStable, metav1.ConditionFalse,
+			"Scaling gitserver from 2 to 3 replicas. Repositories are redistributed across the replicas, and may be missing until they have been cloned again.")
+
+		// The warning stays until gitserver is available again.
+		_, cm = reconcileSpecConfigMap(t, r)
+		gitserver = gitServerStatus(cm)
+		require.Equal(t, config.ReasonReplicasChanged, meta.FindStatusCondition(gitserver.Conditions, config.ConditionShardsStable).Reason)
+
+		var sset appsv1.StatefulSet
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: renderedSpec.Namespace, Name: "gitserver"}, &sset))
+		sset.Status = appsv1.StatefulSetStatus{ObservedGeneration: sset.Generation, Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3, AvailableReplicas: 3, CurrentRevision: "r", UpdateRevision: "r"}
+		require.NoError(t, c.Status().Update(context.Background(), &sset))
+		_, cm = reconcileSpecConfigMap(t, r)
+		gitserver = gitServerStatus(cm)
+		requireCondition(t, gitserver.Conditions, config.ConditionAvailable, metav1.ConditionTrue, "")
+		requireCondition(t, gitserver.Conditions, config.ConditionShardsStable, metav1.ConditionTrue, "")
+	})
+
+	t.Run("blocked scale down", func(t *testing.T) {
+		c, r := setup(t)
+		setSpec(t, c, "    replicas: 1\n")
+		_, cm := reconcileSpecConfigMap(t, r)
+		requireReplicas(t, c, 2, "gitserver-0.gitserver:3178 gitserver-1.gitserver:3178")
+		requireCondition(t, gitServerStatus(cm).Conditions, config.ConditionShardsStable, metav1.ConditionFalse,
+			"Not scaling gitserver down from 2 to 1 replicas, since spec.gitServer.allowScaleDown is not set. Repositories on the removed replicas' volumes would be left behind.")
+	})
+
+	t.Run("allowed scale down", func(t *testing.T) {
+		c, r := setup(t)
+		setSpec(t, c, "    replicas: 1\n    allowScaleDown: true\n")
+		_, cm := reconcileSpecConfigMap(t, r)
+		requireReplicas(t, c, 1, "gitserver-0.gitserver:3178")
+		requireCondition(t, gitServerStatus(cm).Conditions, config.ConditionShardsStable, metav1.ConditionFalse,
+			"Scaling gitserver from 2 to 1 replicas. Repositories are redistributed across the replicas, and may be missing until they have been cloned again.")
+	})
+}
+
+func gitServerStatus(cm corev1.ConfigMap) *config.ServiceStatus {
+	status := statusFromAnnotations(cm.Annotations)
+	return status.Service("gitserver")
+}
+
+func TestProbeOverrides(t *testing.T) {
+	probes := func(t *testing.T, spec string) map[string]corev1.Container {
+		t.Helper()
+		objs, err := Render(context.Background(), renderedSpec, []byte(spec))
+		require.NoError(t, err)
+		ctrs := map[string]corev1.Container{}
+		for _, obj := range objs {
+			u := obj.(*unstructured.Unstructured)
+			var template corev1.PodTemplateSpec
+			switch u.GetKind() {
+			case "Deployment":
+				var dep appsv1.Deployment
+				require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &dep))
+				template = dep.Spec.Template
+			case "StatefulSet":
+				var sset appsv1.StatefulSet
+				require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &sset))
+				template = sset.Spec.Template
+			default:
+				continue
+			}
+			for _, ctr := range template.Spec.Containers {
+				ctrs[u.GetName()+"/"+ctr.Name] = corev1.Container{
+					LivenessProbe:  ctr.LivenessProbe,
+					ReadinessProbe: ctr.ReadinessProbe,
+					StartupProbe:   ctr.StartupProbe,
+				}
+			}
+		}
+		return ctrs
+	}
+
+	stock := probes(t, `
+spec:
+  requestedVersion: "5.3.9104"
+`)
+	overridden := probes(t, `
+spec:
+  requestedVersion: "5.3.9104"
+  gitServer:
+    startupProbe:
+      periodSeconds: 10
+      failureThreshold: 60
+`)
+
+	// Gitserver gets ten minutes to start up, checked with its liveness
+	// probe's handler, while its other probes are untouched.
+	gitserver := overridden["gitserver/gitserver"]
+	require.Equal(t, &corev1.Probe{
+		ProbeHandler:     stock["gitserver/gitserver"].LivenessProbe.ProbeHandler,
+		PeriodSeconds:    10,
+		FailureThreshold: 60,
+	}, gitserver.StartupProbe)
+	require.Equal(t, stock["gitserver/gitserver"].LivenessProbe, gitserver.LivenessProbe)
+	require.Equal(t, stock["gitserver/gitserver"].ReadinessProbe, gitserver.ReadinessProbe)
+
+	// Every other service keeps its stock probes.
+	delete(stock, "gitserver/gitserver")
+	delete(overridden, "gitserver/gitserver")
+	require.NotEmpty(t, stock)
+	require.Equal(t, stock, overridden)
+}
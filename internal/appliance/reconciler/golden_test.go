@@ -4,14 +4,18 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	k8syaml "sigs.k8s.io/yaml"
 
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
 	applianceyaml "github.com/sourcegraph/sourcegraph/internal/appliance/yaml"
 	"github.com/sourcegraph/sourcegraph/internal/slices"
 )
@@ -30,10 +34,14 @@ type goldenFile struct {
 }
 
 func (suite *ApplianceTestSuite) makeGoldenAssertions(namespace, goldenFileName string) {
-	require := suite.Require()
+	assertGoldenFile(suite.T(), goldenFileName, suite.gatherResources(namespace))
+}
+
+func assertGoldenFile(t *testing.T, goldenFileName string, resources []client.Object) {
+	require := require.New(t)
 
 	goldenFilePath := filepath.Join("testdata", "golden-fixtures", goldenFileName+".yaml")
-	obtainedResources := goldenFile{Resources: suite.gatherResources(namespace)}
+	obtainedResources := goldenFile{Resources: resources}
 	obtainedBytes, err := k8syaml.Marshal(obtainedResources)
 	require.NoError(err)
 	obtainedBytes, err = applianceyaml.ConvertYAMLStringsToMultilineLiterals(obtainedBytes)
@@ -70,6 +78,7 @@ func (suite *ApplianceTestSuite) gatherResources(namespace string) []client.Obje
 	suite.Require().NoError(err)
 	for _, obj := range deps.Items {
 		obj := obj // see exportloopref
+		normalizePriorityClassName(&obj.Spec.Template)
 		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
 		normalizeObj(&obj)
 		objs = append(objs, &obj)
@@ -78,6 +87,7 @@ func (suite *ApplianceTestSuite) gatherResources(namespace string) []client.Obje
 	suite.Require().NoError(err)
 	for _, obj := range daemonsets.Items {
 		obj := obj
+		normalizePriorityClassName(&obj.Spec.Template)
 		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"})
 		normalizeObj(&obj)
 		objs = append(objs, &obj)
@@ -89,11 +99,51 @@ func (suite *ApplianceTestSuite) gatherResources(namespace string) []client.Obje
 		for i := range obj.Spec.VolumeClaimTemplates {
 			obj.Spec.VolumeClaimTemplates[i].Namespace = normalizedString
 		}
+		normalizePriorityClassName(&obj.Spec.Template)
 		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"})
 		normalizeObj(&obj)
 		objs = append(objs, &obj)
 	}
 
+	hpas, err := suite.k8sClient.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(suite.ctx, metav1.ListOptions{})
+	suite.Require().NoError(err)
+	for _, obj := range hpas.Items {
+		obj := obj
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"})
+		normalizeObj(&obj)
+		objs = append(objs, &obj)
+	}
+	cronJobs, err := suite.k8sClient.BatchV1().CronJobs(namespace).List(suite.ctx, metav1.ListOptions{})
+	suite.Require().NoError(err)
+	for _, obj := range cronJobs.Items {
+		obj := obj
+		normalizePriorityClassName(&obj.Spec.JobTemplate.Spec.Template)
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"})
+		normalizeObj(&obj)
+		objs = append(objs, &obj)
+	}
+	jobs, err := suite.k8sClient.BatchV1().Jobs(namespace).List(suite.ctx, metav1.ListOptions{})
+	suite.Require().NoError(err)
+	for _, obj := range jobs.Items {
+		obj := obj
+		// The API server labels Jobs' pods with the Job's UID.
+		labelSets := []map[string]string{obj.Spec.Template.Labels}
+		if obj.Spec.Selector != nil {
+			labelSets = append(labelSets, obj.Spec.Selector.MatchLabels)
+		}
+		for _, labels := range labelSets {
+			for _, key := range []string{"batch.kubernetes.io/controller-uid", "controller-uid"} {
+				if _, ok := labels[key]; ok {
+					labels[key] = normalizedString
+				}
+			}
+		}
+		normalizePriorityClassName(&obj.Spec.Template)
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"})
+		normalizeObj(&obj)
+		objs = append(objs, &obj)
+	}
+
 	// Cluster-scoped resources have to be qualified by something other than
 	// metadata.namespace.
 	clusterRoles, err := suite.k8sClient.RbacV1().ClusterRoles().List(suite.ctx, metav1.ListOptions{
@@ -126,6 +176,19 @@ func (suite *ApplianceTestSuite) gatherResources(namespace string) []client.Obje
 		objs = append(objs, &obj)
 	}
 
+	priorityClasses, err := suite.k8sClient.SchedulingV1().PriorityClasses().List(suite.ctx, metav1.ListOptions{
+		LabelSelector: "for-namespace=" + namespace,
+	})
+	suite.Require().NoError(err)
+	for _, obj := range priorityClasses.Items {
+		obj := obj
+		obj.SetName(namespaceRegexp.ReplaceAllString(obj.Name, normalizedString))
+		obj.Labels["for-namespace"] = normalizedString
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"})
+		normalizeObj(&obj)
+		objs = append(objs, &obj)
+	}
+
 	cmaps, err := suite.k8sClient.CoreV1().ConfigMaps(namespace).List(suite.ctx, metav1.ListOptions{})
 	suite.Require().NoError(err)
 	for _, obj := range cmaps.Items {
@@ -137,6 +200,15 @@ func (suite *ApplianceTestSuite) gatherResources(namespace string) []client.Obje
 			obj.Data[file] = namespaceRegexp.ReplaceAllString(content, normalizedString)
 		}
 
+		// envtest doesn't run the controllers that roll out workloads, and
+		// the status holds transition times, so it is covered by
+		// status_test.go instead.
+		delete(obj.Annotations, config.AnnotationKeyStatus)
+		delete(obj.Annotations, config.AnnotationKeyReady)
+		// The resolved config repeats the spec, and is covered by
+		// resolved_config_test.go.
+		delete(obj.Annotations, config.AnnotationKeyResolvedConfig)
+
 		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"})
 		normalizeObj(&obj)
 		objs = append(objs, &obj)
@@ -160,6 +232,14 @@ func (suite *ApplianceTestSuite) gatherResources(namespace string) []client.Obje
 		normalizeObj(&obj)
 		objs = append(objs, &obj)
 	}
+	pdbs, err := suite.k8sClient.PolicyV1().PodDisruptionBudgets(namespace).List(suite.ctx, metav1.ListOptions{})
+	suite.Require().NoError(err)
+	for _, obj := range pdbs.Items {
+		obj := obj
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"})
+		normalizeObj(&obj)
+		objs = append(objs, &obj)
+	}
 	roles, err := suite.k8sClient.RbacV1().Roles(namespace).List(suite.ctx, metav1.ListOptions{})
 	suite.Require().NoError(err)
 	for _, obj := range roles.Items {
@@ -210,6 +290,37 @@ func (suite *ApplianceTestSuite) gatherResources(namespace string) []client.Obje
 		normalizeObj(&obj)
 		objs = append(objs, &obj)
 	}
+	ingresses, err := suite.k8sClient.NetworkingV1().Ingresses(namespace).List(suite.ctx, metav1.ListOptions{})
+	suite.Require().NoError(err)
+	for _, obj := range ingresses.Items {
+		obj := obj
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"})
+		normalizeObj(&obj)
+		objs = append(objs, &obj)
+	}
+	networkPolicies, err := suite.k8sClient.NetworkingV1().NetworkPolicies(namespace).List(suite.ctx, metav1.ListOptions{})
+	suite.Require().NoError(err)
+	for _, obj := range networkPolicies.Items {
+		obj := obj
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"})
+		normalizeObj(&obj)
+		objs = append(objs, &obj)
+	}
+
+	// Objects of third-party CRDs aren't owned in SetupWithManager(), since
+	// the cluster might not serve them.
+	for _, gvr := range []schema.GroupVersionResource{
+		{Group: "monitoring.coreos.com", Version: "v1", Resource: "podmonitors"},
+		{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"},
+	} {
+		list, err := suite.dynamicClient.Resource(gvr).Namespace(namespace).List(suite.ctx, metav1.ListOptions{})
+		suite.Require().NoError(err)
+		for _, obj := range list.Items {
+			obj := obj
+			normalizeObj(&obj)
+			objs = append(objs, &obj)
+		}
+	}
 
 	return objs
 }
@@ -221,6 +332,12 @@ func normalizeObj(obj client.Object) {
 	obj.SetNamespace(normalizedString)
 	obj.SetResourceVersion(normalizedString)
 
+	// The annotation recording what was applied repeats the object itself,
+	// including its namespace.
+	annotations := obj.GetAnnotations()
+	delete(annotations, config.AnnotationKeyLastApplied)
+	obj.SetAnnotations(annotations)
+
 	ownerRefs := obj.GetOwnerReferences()
 	normalizedOwnerRefs := make([]metav1.OwnerReference, len(ownerRefs))
 	for i, ownerRef := range ownerRefs {
@@ -229,3 +346,9 @@ func normalizeObj(obj client.Object) {
 	}
 	obj.SetOwnerReferences(normalizedOwnerRefs)
 }
+
+// Managed PriorityClasses are cluster-scoped, so their names include the
+// namespace.
+func normalizePriorityClassName(template *corev1.PodTemplateSpec) {
+	template.Spec.PriorityClassName = namespaceRegexp.ReplaceAllString(template.Spec.PriorityClassName, normalizedString)
+}
@@ -0,0 +1,139 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/configmap"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func (r *Reconciler) reconcileGrafana(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	if err := r.reconcileGrafanaConfigMap(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling ConfigMap")
+	}
+	if err := r.reconcileGrafanaPVC(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling PVC")
+	}
+	if err := r.reconcileGrafanaDeployment(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Deployment")
+	}
+	if err := r.reconcileGrafanaService(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Service")
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileGrafanaDeployment(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := "grafana"
+	cfg := sg.Spec.Grafana
+
+	defaultImage, err := config.GetDefaultImage(sg, name)
+	if err != nil {
+		return err
+	}
+	ctr := container.NewContainer(name, cfg, config.ContainerConfig{
+		Image: defaultImage,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		},
+	})
+
+	ctr.Ports = []corev1.ContainerPort{
+		{Name: "http", ContainerPort: 3370},
+	}
+	ctr.VolumeMounts = []corev1.VolumeMount{
+		{Name: "data", MountPath: "/var/lib/grafana"},
+		{Name: "config", MountPath: "/sg_config_grafana/provisioning/datasources"},
+	}
+
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
+	podTemplate.Template.Spec.Volumes = []corev1.Volume{
+		pod.NewVolumeFromPVC("data", sg.Spec.ObjectName(name)),
+		pod.NewVolumeFromConfigMap("config", sg.Spec.ObjectName(name)),
+	}
+
+	// The grafana image provisions any dashboards found in this directory in
+	// addition to the ones it ships with.
+	if cfg.ExistingConfigMap != "" {
+		ctr.VolumeMounts = append(ctr.VolumeMounts, corev1.VolumeMount{
+			Name: "dashboards", MountPath: "/sg_grafana_additional_dashboards",
+		})
+		podTemplate.Template.Spec.Volumes = append(
+			podTemplate.Template.Spec.Volumes,
+			pod.NewVolumeFromConfigMap("dashboards", cfg.ExistingConfigMap),
+		)
+	}
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	if err := applyDeploymentStrategy(&dep, cfg); err != nil {
+		return err
+	}
+	dep.Spec.Template = podTemplate.Template
+
+	return reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileGrafanaConfigMap(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := sg.Spec.Grafana
+
+	// The bundled datasource points at the prometheus Service, which is
+	// prefixed like every other object.
+	datasources := strings.Replace(string(config.GrafanaDatasourcesConfig),
+		"//prometheus:", "//"+sg.Spec.ObjectName("prometheus")+":", 1)
+
+	cm := configmap.NewConfigMap(sg.Spec.ObjectName("grafana"), sg.Namespace)
+	cm.Data = map[string]string{
+		"datasources.yml": datasources,
+	}
+
+	return reconcileObject(ctx, r, cfg, &cm, &corev1.ConfigMap{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileGrafanaPVC(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := sg.Spec.Grafana
+	p, err := pvc.NewPersistentVolumeClaim(sg.Spec.ObjectName("grafana"), sg.Namespace, cfg)
+	if err != nil {
+		return err
+	}
+	return reconcileObject(ctx, r, cfg, &p, &corev1.PersistentVolumeClaim{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileGrafanaService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := sg.Spec.ObjectName("grafana")
+	cfg := sg.Spec.Grafana
+
+	svc := service.NewService(name, sg.Namespace, cfg)
+	svc.Spec.Ports = []corev1.ServicePort{
+		{Name: "http", Port: 30070, TargetPort: intstr.FromString("http")},
+	}
+	svc.Spec.Selector = map[string]string{
+		"app": name,
+	}
+
+	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
+}
@@ -0,0 +1,21 @@
+package reconciler
+
+func (suite *ApplianceTestSuite) TestDeployGrafana() {
+	for _, tc := range []struct {
+		name string
+	}{
+		{name: "grafana/default"},
+		{name: "grafana/with-existing-configmap"},
+	} {
+		suite.Run(tc.name, func() {
+			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
+			suite.makeGoldenAssertions(namespace, tc.name)
+		})
+	}
+}
+
+func (suite *ApplianceTestSuite) TestGrafanaResourcesDeletedWhenDisabled() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("grafana/default")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "standard/everything-disabled")
+	suite.makeGoldenAssertions(namespace, "grafana/subsequent-disable")
+}
@@ -0,0 +1,98 @@
+package reconciler
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+// ReconcileResult is the outcome of the last reconcile of an appliance
+// ConfigMap.
+type ReconcileResult struct {
+	// ConfigMap is the appliance ConfigMap that was reconciled.
+	ConfigMap types.NamespacedName
+
+	// Time is when the reconcile finished.
+	Time time.Time
+
+	// Err is the error that the reconcile failed with, if any.
+	Err error
+
+	// Status is the status that the reconcile recorded on the ConfigMap. It
+	// is empty if the reconcile failed before getting that far.
+	Status config.SourcegraphStatus
+}
+
+// ReconcileTracker records the result of the last reconcile of every
+// appliance ConfigMap, for the health endpoints of the appliance. The methods
+// of a nil tracker do nothing.
+type ReconcileTracker struct {
+	mu      sync.Mutex
+	results map[types.NamespacedName]ReconcileResult
+	// pending holds the status recorded by reconciles that are in progress.
+	pending map[types.NamespacedName]config.SourcegraphStatus
+}
+
+// NewReconcileTracker returns a tracker that hasn't seen a reconcile yet.
+func NewReconcileTracker() *ReconcileTracker {
+	return &ReconcileTracker{
+		results: map[types.NamespacedName]ReconcileResult{},
+		pending: map[types.NamespacedName]config.SourcegraphStatus{},
+	}
+}
+
+// Results returns the result of the last reconcile of every ConfigMap, ordered
+// by namespace and name.
+func (t *ReconcileTracker) Results() []ReconcileResult {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	results := make([]ReconcileResult, 0, len(t.results))
+	for _, result := range t.results {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ConfigMap.String() < results[j].ConfigMap.String()
+	})
+	return results
+}
+
+// setStatus records the status that the reconcile of cm in progress recorded
+// on it.
+func (t *ReconcileTracker) setStatus(cm types.NamespacedName, status config.SourcegraphStatus) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[cm] = status
+}
+
+// finish records the end of a reconcile of cm.
+func (t *ReconcileTracker) finish(cm types.NamespacedName, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.results[cm] = ReconcileResult{ConfigMap: cm, Time: time.Now(), Err: err, Status: t.pending[cm]}
+	delete(t.pending, cm)
+}
+
+// forget drops the result of cm, e.g. once it is deleted.
+func (t *ReconcileTracker) forget(cm types.NamespacedName) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.results, cm)
+	delete(t.pending, cm)
+}
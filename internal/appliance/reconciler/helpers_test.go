@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -39,7 +40,8 @@ type ApplianceTestSuite struct {
 	testEnv     *envtest.Environment
 	ctrlMgrDone chan struct{}
 
-	k8sClient *kubernetes.Clientset
+	k8sClient     *kubernetes.Clientset
+	dynamicClient *dynamic.DynamicClient
 }
 
 func TestApplianceTestSuite(t *testing.T) {
@@ -65,6 +67,10 @@ func (suite *ApplianceTestSuite) setupEnvtest() {
 	suite.testEnv = &envtest.Environment{
 		AttachControlPlaneOutput: true,
 		BinaryAssetsDirectory:    suite.kubebuilderAssetPath(),
+
+		// Stand-ins for third-party CRDs that the appliance can manage objects
+		// of, when the cluster serves them.
+		CRDDirectoryPaths: []string{filepath.Join("testdata", "crds")},
 	}
 	apiServerCfg := suite.testEnv.ControlPlane.GetAPIServer()
 	apiServerCfg.Configure().Set("bind-address", "127.0.0.1")
@@ -87,6 +93,8 @@ func (suite *ApplianceTestSuite) setupEnvtest() {
 	require.NoError(t, err)
 	suite.k8sClient, err = kubernetes.NewForConfig(cfg)
 	require.NoError(t, err)
+	suite.dynamicClient, err = dynamic.NewForConfig(cfg)
+	require.NoError(t, err)
 
 	reconciler := &Reconciler{
 		Client:   ctrlMgr.GetClient(),
@@ -0,0 +1,93 @@
+package reconciler
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/hpa"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// reconcileHorizontalPodAutoscaler manages the HorizontalPodAutoscaler for the
+// apps/v1 object of the given kind called name, which only exists while
+// autoscaling is enabled.
+func (r *Reconciler) reconcileHorizontalPodAutoscaler(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name, kind string, cfg config.StandardComponent, autoscaling *config.AutoscalingConfig) error {
+	scaler := hpa.NewHorizontalPodAutoscaler(name, sg.Namespace, kind)
+	if autoscaling.IsEnabled() {
+		scaler.Spec.MinReplicas = autoscaling.MinReplicas
+		scaler.Spec.MaxReplicas = autoscaling.MaxReplicas
+		if autoscaling.TargetCPUUtilizationPercentage == nil && autoscaling.TargetMemoryUtilizationPercentage == nil {
+			scaler.Spec.Metrics = append(scaler.Spec.Metrics, utilizationMetric(corev1.ResourceCPU, 80))
+		}
+		if target := autoscaling.TargetCPUUtilizationPercentage; target != nil {
+			scaler.Spec.Metrics = append(scaler.Spec.Metrics, utilizationMetric(corev1.ResourceCPU, *target))
+		}
+		if target := autoscaling.TargetMemoryUtilizationPercentage; target != nil {
+			scaler.Spec.Metrics = append(scaler.Spec.Metrics, utilizationMetric(corev1.ResourceMemory, *target))
+		}
+	}
+
+	hpaCfg := horizontalPodAutoscalerConfig{StandardComponent: cfg, autoscaling: autoscaling}
+	return reconcileObject(ctx, r, hpaCfg, &scaler, &autoscalingv2.HorizontalPodAutoscaler{}, sg, owner)
+}
+
+func utilizationMetric(resource corev1.ResourceName, percentage int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: resource,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: pointers.Ptr(percentage),
+			},
+		},
+	}
+}
+
+// horizontalPodAutoscalerConfig wraps a service's config for its
+// HorizontalPodAutoscaler.
+type horizontalPodAutoscalerConfig struct {
+	config.StandardComponent
+	autoscaling *config.AutoscalingConfig
+}
+
+func (c horizontalPodAutoscalerConfig) IsDisabled() bool {
+	return c.StandardComponent.IsDisabled() || !c.autoscaling.IsEnabled()
+}
+
+// replicasFor returns the replicas to set on the Deployment or StatefulSet obj,
+// where objKind is an empty object of the same type. While autoscaling is
+// enabled, the HorizontalPodAutoscaler manages the number of replicas, so the
+// current number is kept when obj is updated, or the number from before it was
+// scaled down for maintenance.
+func (r *Reconciler) replicasFor(ctx context.Context, obj, objKind client.Object, replicas int32, autoscaling *config.AutoscalingConfig) (*int32, error) {
+	if !autoscaling.IsEnabled() {
+		return pointers.Ptr(replicas), nil
+	}
+
+	err := r.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, objKind)
+	if kerrors.IsNotFound(err) {
+		return pointers.Ptr(pointers.Deref(autoscaling.MinReplicas, 1)), nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "getting current replicas")
+	}
+	if replicas, ok := replicasBeforeMaintenance(objKind); ok {
+		return pointers.Ptr(replicas), nil
+	}
+
+	switch existing := objKind.(type) {
+	case *appsv1.Deployment:
+		return existing.Spec.Replicas, nil
+	case *appsv1.StatefulSet:
+		return existing.Spec.Replicas, nil
+	}
+	return nil, errors.Newf("unsupported kind %T", objKind)
+}
@@ -0,0 +1,125 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// pendingChange is a change to an object that the API server rejected, since
+// it changes fields that can't be updated once the object exists, e.g. the
+// storage class of a PersistentVolumeClaim, or the volume claim templates of
+// a StatefulSet.
+type pendingChange struct {
+	kind string
+	name string
+}
+
+func (c pendingChange) String() string {
+	if c.kind == "StatefulSet" {
+		return fmt.Sprintf("StatefulSet %s changes fields that can't be updated, set allowStatefulSetRecreate to recreate it", c.name)
+	}
+	return fmt.Sprintf("%s %s changes fields that can't be updated, and has to be replaced by hand", c.kind, c.name)
+}
+
+// pendingChangeRecorder collects the pending changes found while reconciling
+// a service.
+type pendingChangeRecorder struct {
+	changes []pendingChange
+}
+
+type pendingChangeRecorderKey struct{}
+
+// withPendingChangeRecorder returns a context in which the pending changes
+// found by reconcileObject are collected by the returned recorder.
+func withPendingChangeRecorder(ctx context.Context) (context.Context, *pendingChangeRecorder) {
+	recorder := &pendingChangeRecorder{}
+	return context.WithValue(ctx, pendingChangeRecorderKey{}, recorder), recorder
+}
+
+// recordPendingChange adds change to the recorder of ctx, if it has one.
+func recordPendingChange(ctx context.Context, change pendingChange) {
+	if recorder, ok := ctx.Value(pendingChangeRecorderKey{}).(*pendingChangeRecorder); ok {
+		recorder.changes = append(recorder.changes, change)
+	}
+}
+
+// reportPendingChanges sets the ChangesApplied condition of a service while
+// it has pending changes, and records an event for each of them.
+func reportPendingChanges(svc *config.ServiceStatus, events eventRecorder, changes []pendingChange) {
+	if len(changes) == 0 {
+		meta.RemoveStatusCondition(&svc.Conditions, config.ConditionChangesApplied)
+		return
+	}
+
+	messages := make([]string, 0, len(changes))
+	for _, change := range changes {
+		messages = append(messages, change.String())
+		events.eventf(EventReasonChangePending, "%s.", change)
+	}
+	meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+		Type:    config.ConditionChangesApplied,
+		Status:  metav1.ConditionFalse,
+		Reason:  config.ReasonImmutableFieldsChanged,
+		Message: "Changes pending: " + strings.Join(messages, "; "),
+	})
+}
+
+// isImmutableFieldError reports whether err is the API server rejecting an
+// update of fields that can't be changed once an object exists.
+func isImmutableFieldError(err error) bool {
+	if !kerrors.IsInvalid(err) {
+		return false
+	}
+	var statusErr *kerrors.StatusError
+	if !errors.As(err, &statusErr) || statusErr.ErrStatus.Details == nil {
+		return false
+	}
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		if cause.Type == metav1.CauseTypeForbidden {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteStatefulSetForRecreate deletes the StatefulSet that sset replaces, so
+// that it can be created again with fields that can't be updated. Its pods
+// and PersistentVolumeClaims are orphaned rather than deleted, and adopted by
+// the new StatefulSet, which rolls the pods as usual. Existing claims keep
+// their volumes, since claim templates only apply to claims created later.
+//
+// It returns an error until the StatefulSet is gone, which takes until the
+// garbage collector orphaned its pods.
+func (r *Reconciler) deleteStatefulSetForRecreate(ctx context.Context, sset *appsv1.StatefulSet) error {
+	var existing appsv1.StatefulSet
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(sset), &existing); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if existing.DeletionTimestamp == nil {
+		if err := r.Client.Delete(ctx, &existing, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "deleting StatefulSet %s", sset.Name)
+		}
+		eventsFrom(ctx).eventf(EventReasonRecreated, "Deleted StatefulSet %s, keeping its pods and PersistentVolumeClaims, to recreate it with fields that can't be updated.", sset.Name)
+	}
+
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(sset), &existing); !kerrors.IsNotFound(err) {
+		if err != nil {
+			return err
+		}
+		return errors.Newf("waiting for StatefulSet %s to be deleted before recreating it", sset.Name)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+func TestImmutableFieldChanges(t *testing.T) {
+	// spec adds extra to the top level of the spec of fixture.
+	spec := func(fixture, extra string) []byte {
+		return []byte(strings.Replace(string(readSpecFixture(t, fixture)),
+			`requestedVersion: "5.3.9104"`,
+			`requestedVersion: "5.3.9104"`+extra, 1))
+	}
+	// The labels of the spec apply to every object, so changing them
+	// patches the StatefulSets and PersistentVolumeClaims, which the API
+	// server rejects like a change of their volume claim templates or
+	// storage class.
+	const changed = "\n  labels:\n    env: prod"
+
+	type recordingClient struct {
+		client.Client
+		recorder *record.FakeRecorder
+		// deletes are the options of the deletes of StatefulSets that
+		// existed.
+		deletes []client.DeleteOptions
+	}
+	newClient := func(t *testing.T, spec []byte) (*Reconciler, *recordingClient) {
+		c := &recordingClient{recorder: record.NewFakeRecorder(100)}
+		c.Client = fake.NewClientBuilder().
+			WithObjects(newSpecConfigMap(spec)).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					var gk schema.GroupKind
+					switch obj.(type) {
+					case *appsv1.StatefulSet:
+						gk = schema.GroupKind{Group: "apps", Kind: "StatefulSet"}
+					case *corev1.PersistentVolumeClaim:
+						gk = schema.GroupKind{Kind: "PersistentVolumeClaim"}
+					default:
+						return cl.Patch(ctx, obj, patch, opts...)
+					}
+					return kerrors.NewInvalid(gk, obj.GetName(), field.ErrorList{
+						field.Forbidden(field.NewPath("spec"), "updates to these fields are forbidden"),
+					})
+				},
+				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					if err := cl.Delete(ctx, obj, opts...); err != nil {
+						return err
+					}
+					if _, ok := obj.(*appsv1.StatefulSet); ok {
+						var o client.DeleteOptions
+						o.ApplyOptions(opts)
+						c.deletes = append(c.deletes, o)
+					}
+					return nil
+				},
+			}).
+			Build()
+		return &Reconciler{Client: c.Client, Scheme: scheme.Scheme, Recorder: c.recorder}, c
+	}
+	updateSpec := func(t *testing.T, c client.Client, spec []byte) {
+		t.Helper()
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(context.Background(), renderedSpec, &cm))
+		cm.Data["spec"] = string(spec)
+		require.NoError(t, c.Update(context.Background(), &cm))
+	}
+	events := func(recorder *record.FakeRecorder) []string {
+		var events []string
+		for {
+			select {
+			case event := <-recorder.Events:
+				if strings.Contains(event, "ChangePending") || strings.Contains(event, "Recreated") {
+					events = append(events, event)
+				}
+			default:
+				return events
+			}
+		}
+	}
+	get := func(t *testing.T, c client.Client, name string, obj client.Object) {
+		t.Helper()
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: renderedSpec.Namespace, Name: name}, obj))
+	}
+
+	t.Run("reported only", func(t *testing.T) {
+		r, c := newClient(t, spec("pgsql/default", ""))
+		_, _ = reconcileSpecConfigMap(t, r)
+		var before appsv1.StatefulSet
+		get(t, c, "pgsql", &before)
+		_ = events(c.recorder)
+
+		updateSpec(t, c, spec("pgsql/default", changed))
+		_, cm := reconcileSpecConfigMap(t, r)
+		status := statusFromAnnotations(cm.Annotations)
+		pgsql := status.Service("pgsql")
+		requireCondition(t, pgsql.Conditions, config.ConditionReconciled, metav1.ConditionTrue, "")
+		requireCondition(t, pgsql.Conditions, config.ConditionChangesApplied, metav1.ConditionFalse,
+			"Changes pending: StatefulSet pgsql changes fields that can't be updated, set allowStatefulSetRecreate to recreate it; "+
+				"PersistentVolumeClaim pgsql changes fields that can't be updated, and has to be replaced by hand")
+		require.Equal(t, []string{
+			"Warning ChangePending StatefulSet pgsql changes fields that can't be updated, set allowStatefulSetRecreate to recreate it.",
+			"Warning ChangePending PersistentVolumeClaim pgsql changes fields that can't be updated, and has to be replaced by hand.",
+		}, events(c.recorder))
+
+		// The old StatefulSet keeps running.
+		require.Empty(t, c.deletes)
+		var after appsv1.StatefulSet
+		get(t, c, "pgsql", &after)
+		require.Equal(t, before.ResourceVersion, after.ResourceVersion)
+		require.NotContains(t, after.Labels, "env")
+
+		// Reverting the change clears the condition.
+		updateSpec(t, c, spec("pgsql/default", ""))
+		_, cm = reconcileSpecConfigMap(t, r)
+		status = statusFromAnnotations(cm.Annotations)
+		require.Nil(t, meta.FindStatusCondition(status.Service("pgsql").Conditions, config.ConditionChangesApplied))
+	})
+
+	t.Run("recreate", func(t *testing.T) {
+		allowed := "\n  allowStatefulSetRecreate: true"
+		r, c := newClient(t, spec("pgsql/default", allowed))
+		_, _ = reconcileSpecConfigMap(t, r)
+		var pvcBefore corev1.PersistentVolumeClaim
+		get(t, c, "pgsql", &pvcBefore)
+		_ = events(c.recorder)
+
+		updateSpec(t, c, spec("pgsql/default", allowed+changed))
+		_, cm := reconcileSpecConfigMap(t, r)
+
+		// The StatefulSet is deleted without its pods and claims, and
+		// recreated with the change.
+		require.Len(t, c.deletes, 1)
+		require.NotNil(t, c.deletes[0].PropagationPolicy)
+		require.Equal(t, metav1.DeletePropagationOrphan, *c.deletes[0].PropagationPolicy)
+		var sset appsv1.StatefulSet
+		get(t, c, "pgsql", &sset)
+		require.Equal(t, "prod", sset.Labels["env"])
+
+		// The PersistentVolumeClaim can't be recreated without losing its
+		// data, so its change is still only reported.
+		var pvc corev1.PersistentVolumeClaim
+		get(t, c, "pgsql", &pvc)
+		require.Equal(t, pvcBefore.ResourceVersion, pvc.ResourceVersion)
+		status := statusFromAnnotations(cm.Annotations)
+		requireCondition(t, status.Service("pgsql").Conditions, config.ConditionChangesApplied, metav1.ConditionFalse,
+			"Changes pending: PersistentVolumeClaim pgsql changes fields that can't be updated, and has to be replaced by hand")
+		require.Equal(t, []string{
+			"Normal Recreated Deleted StatefulSet pgsql, keeping its pods and PersistentVolumeClaims, to recreate it with fields that can't be updated.",
+			"Warning ChangePending PersistentVolumeClaim pgsql changes fields that can't be updated, and has to be replaced by hand.",
+		}, events(c.recorder))
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		// Redis runs as a Deployment, so only its claims have changes that
+		// can't be applied, whether recreating StatefulSets is allowed or not.
+		allowed := "\n  allowStatefulSetRecreate: true"
+		r, c := newClient(t, spec("redis/default", allowed))
+		_, _ = reconcileSpecConfigMap(t, r)
+
+		updateSpec(t, c, spec("redis/default", allowed+changed))
+		_, cm := reconcileSpecConfigMap(t, r)
+		require.Empty(t, c.deletes)
+		status := statusFromAnnotations(cm.Annotations)
+		requireCondition(t, status.Service("redis").Conditions, config.ConditionChangesApplied, metav1.ConditionFalse,
+			"Changes pending: PersistentVolumeClaim redis-cache changes fields that can't be updated, and has to be replaced by hand; "+
+				"PersistentVolumeClaim redis-store changes fields that can't be updated, and has to be replaced by hand")
+		for _, name := range []string{"redis-cache", "redis-store"} {
+			var dep appsv1.Deployment
+			get(t, c, name, &dep)
+			require.Equal(t, "prod", dep.Labels["env"])
+		}
+	})
+}
+
+func TestReadyWithPendingChanges(t *testing.T) {
+	status := config.SourcegraphStatus{Services: []config.ServiceStatus{{
+		Name: "pgsql",
+		Conditions: []metav1.Condition{
+			{Type: config.ConditionReconciled, Status: metav1.ConditionTrue},
+			{Type: config.ConditionAvailable, Status: metav1.ConditionTrue},
+		},
+	}}}
+	require.True(t, setReadyCondition(&status))
+
+	reportPendingChanges(&status.Services[0], eventRecorder{}, []pendingChange{{kind: "StatefulSet", name: "pgsql"}})
+	require.False(t, setReadyCondition(&status))
+	requireCondition(t, status.Conditions, config.ConditionReady, metav1.ConditionFalse, "Services not ready: pgsql")
+}
+
+func TestIsImmutableFieldError(t *testing.T) {
+	gk := schema.GroupKind{Group: "apps", Kind: "StatefulSet"}
+	require.True(t, isImmutableFieldError(kerrors.NewInvalid(gk, "pgsql", field.ErrorList{
+		field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other than 'replicas' are forbidden"),
+	})))
+	require.False(t, isImmutableFieldError(kerrors.NewInvalid(gk, "pgsql", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "replicas"), -1, "must be greater than or equal to 0"),
+	})))
+	require.False(t, isImmutableFieldError(kerrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "statefulsets"}, "pgsql", nil)))
+	require.False(t, isImmutableFieldError(nil))
+}
@@ -0,0 +1,160 @@
+package reconciler
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/statefulset"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func (r *Reconciler) reconcileIndexedSearch(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	if err := r.reconcileIndexedSearchStatefulSet(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling StatefulSet")
+	}
+	if err := r.reconcilePodDisruptionBudget(ctx, sg, owner, sg.Spec.ObjectName("indexed-search"), sg.Spec.IndexedSearch, sg.Spec.IndexedSearch.Replicas); err != nil {
+		return errors.Wrap(err, "reconciling PodDisruptionBudget")
+	}
+	if err := r.reconcileIndexedSearchService(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Service")
+	}
+	if err := r.reconcileIndexedSearchIndexerService(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling indexer Service")
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileIndexedSearchStatefulSet(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := "indexed-search"
+	cfg := sg.Spec.IndexedSearch
+
+	webserverImage, err := config.GetDefaultImage(sg, "indexed-search")
+	if err != nil {
+		return err
+	}
+	webserverCtr := container.NewContainer("zoekt-webserver", cfg, config.ContainerConfig{
+		Image: webserverImage,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("2G"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4G"),
+			},
+		},
+	})
+	webserverCtr.Env = append(webserverCtr.Env, otelEnvVars(sg)...)
+	webserverCtr.Env = append(webserverCtr.Env, serviceEndpointEnvVars(sg)...)
+	webserverCtr.Ports = []corev1.ContainerPort{
+		{Name: "http", ContainerPort: 6070},
+	}
+	webserverCtr.ReadinessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromString("http"),
+			},
+		},
+		PeriodSeconds:  5,
+		TimeoutSeconds: 5,
+	}
+	webserverCtr.VolumeMounts = []corev1.VolumeMount{
+		{Name: "data", MountPath: "/data"},
+	}
+
+	indexserverImage, err := config.GetDefaultImage(sg, "indexed-search-indexer")
+	if err != nil {
+		return err
+	}
+	indexserverCtr := container.NewContainer("zoekt-indexserver", cfg, config.ContainerConfig{
+		Image: indexserverImage,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8G"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("16G"),
+			},
+		},
+	})
+	indexserverCtr.Env = append(indexserverCtr.Env, otelEnvVars(sg)...)
+	indexserverCtr.Env = append(indexserverCtr.Env, serviceEndpointEnvVars(sg)...)
+	indexserverCtr.Ports = []corev1.ContainerPort{
+		{Name: "index-http", ContainerPort: 6072},
+	}
+	indexserverCtr.VolumeMounts = []corev1.VolumeMount{
+		{Name: "data", MountPath: "/data"},
+	}
+
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
+	podTemplate.Template.Spec.Containers = []corev1.Container{webserverCtr, indexserverCtr}
+
+	pvc, err := pvc.NewPersistentVolumeClaim("data", sg.Namespace, cfg)
+	if err != nil {
+		return err
+	}
+
+	applyTopologySpread(&podTemplate.Template, cfg, cfg.Replicas)
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	sset := statefulset.NewStatefulSet(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	sset.Spec.Replicas = pointers.Ptr(cfg.Replicas)
+	applyStatefulSetStrategy(&sset, cfg)
+	sset.Spec.Template = podTemplate.Template
+	sset.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{pvc}
+
+	return reconcileObject(ctx, r, cfg, &sset, &appsv1.StatefulSet{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileIndexedSearchService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := sg.Spec.ObjectName("indexed-search")
+	cfg := sg.Spec.IndexedSearch
+
+	// Headless, so that the frontend can address each replica individually.
+	svc := service.NewService(name, sg.Namespace, cfg)
+	svc.Spec.ClusterIP = corev1.ClusterIPNone
+	svc.Spec.Ports = []corev1.ServicePort{
+		{Name: "http", Port: 6070, TargetPort: intstr.FromString("http")},
+	}
+	svc.Spec.Selector = map[string]string{
+		"app": name,
+	}
+
+	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileIndexedSearchIndexerService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := sg.Spec.IndexedSearch
+
+	svc := service.NewService(sg.Spec.ObjectName("indexed-search-indexer"), sg.Namespace, nil)
+	svc.Spec.ClusterIP = corev1.ClusterIPNone
+	svc.Spec.Ports = []corev1.ServicePort{
+		{Name: "index-http", Port: 6072, TargetPort: intstr.FromString("index-http")},
+	}
+	svc.Spec.Selector = map[string]string{
+		"app": sg.Spec.ObjectName("indexed-search"),
+	}
+	svc.SetAnnotations(map[string]string{
+		"prometheus.io/port":            "6072",
+		"sourcegraph.prometheus/scrape": "true",
+	})
+
+	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
+}
@@ -0,0 +1,58 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// The API server of envtest only supports IPv4, so Services using IPv6 are
+// checked against a fake client instead of golden files.
+func TestReconcileIPFamilies(t *testing.T) {
+	spec := strings.Replace(string(readSpecFixture(t, "otel-collector/default")), `
+  worker:
+    disabled: true
+`, `
+  worker:
+    ipFamilyPolicy: SingleStack
+    ipFamilies: [IPv4]
+`, 1)
+	spec += "  ipFamilyPolicy: PreferDualStack\n  ipFamilies: [IPv6, IPv4]\n"
+	c := fake.NewClientBuilder().WithObjects(newSpecConfigMap([]byte(spec))).Build()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}}
+	reconcileSpecConfigMap(t, r)
+
+	getService := func(name string) corev1.Service {
+		var svc corev1.Service
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: renderedSpec.Namespace, Name: name}, &svc))
+		return svc
+	}
+
+	otel := getService("otel-collector")
+	require.Equal(t, pointers.Ptr(corev1.IPFamilyPolicyPreferDualStack), otel.Spec.IPFamilyPolicy)
+	require.Equal(t, []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}, otel.Spec.IPFamilies)
+
+	// Services without a config of their own use the one of the service they
+	// belong to.
+	for _, name := range []string{"worker", "worker-executors"} {
+		worker := getService(name)
+		require.Equal(t, pointers.Ptr(corev1.IPFamilyPolicySingleStack), worker.Spec.IPFamilyPolicy, name)
+		require.Equal(t, []corev1.IPFamily{corev1.IPv4Protocol}, worker.Spec.IPFamilies, name)
+	}
+
+	// The collector binds explicitly to its listen address, which must be
+	// IPv6 for the Service to reach it.
+	var cm corev1.ConfigMap
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: renderedSpec.Namespace, Name: "otel-collector"}, &cm))
+	require.Contains(t, cm.Data["config.yaml"], "endpoint: [::]:4317")
+	require.NotContains(t, cm.Data["config.yaml"], "0.0.0.0")
+}
@@ -6,11 +6,18 @@ import (
 	"encoding/hex"
 	"encoding/json"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
@@ -27,19 +34,116 @@ func reconcileObject[T client.Object](
 	obj, objKind T,
 	sg *config.Sourcegraph, owner client.Object,
 ) error {
+	retention := sg.Spec.DataRetentionPolicy.OrDefault()
+	if sset, ok := any(obj).(*appsv1.StatefulSet); ok && retention == config.DataRetentionPolicyRetain {
+		if err := r.reconcileStatefulSetClaims(ctx, sset, cfg.IsDisabled(), owner); err != nil {
+			return err
+		}
+	}
 	if cfg.IsDisabled() {
+		if retention == config.DataRetentionPolicyRetain && holdsData(obj) {
+			return r.retainObject(ctx, obj, owner)
+		}
 		return r.ensureObjectDeleted(ctx, obj)
 	}
+	applyCustomMetadata(obj, sg.Spec.LabelsFor(cfg), sg.Spec.AnnotationsFor(cfg))
+	if svc, ok := any(obj).(*corev1.Service); ok {
+		policy, families := sg.Spec.IPFamiliesFor(cfg)
+		applyIPFamilies(svc, policy, families)
+	}
+	if sset, ok := any(obj).(*appsv1.StatefulSet); ok && retention == config.DataRetentionPolicyDelete {
+		sset.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+			WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+			WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+		}
+	}
+	scaledDown := sg.Spec.MaintenanceMode.ScaledDown(cfg)
+	if scaledDown {
+		if err := r.scaleDownForMaintenance(ctx, obj, objKind); err != nil {
+			return err
+		}
+	}
+	if template := podTemplateOf(obj); template != nil {
+		if err := r.applyDependencyChecksum(ctx, sg, owner, template); err != nil {
+			return err
+		}
+	}
 
+	// Objects also depend on some spec-wide settings: which services serve
+	// TLS determines the trust bundle and URL schemes of every pod, and global
+	// image pull secrets, priority classes, trusted CAs, and proxies are
+	// applied to every pod. These are omitted when empty so that deployments
+	// not using them keep their existing hashes. Spec-wide labels, annotations, and IP families are
+	// included so that removing one from the spec removes it from the object
+	// too, and whether the service is scaled down for maintenance so that it
+	// is scaled back up afterwards. The data retention policy is only
+	// included when it isn't the default, for the same reason. Which
+	// databases have a connection pooler decides where services connect to
+	// them.
 	updateIfChanged := struct {
-		Cfg     config.Disableable
-		Version string
+		Cfg                   config.Disableable
+		Version               string
+		TLS                   map[string]string         `json:",omitempty"`
+		ImagePullSecrets      []string                  `json:",omitempty"`
+		PriorityClassName     string                    `json:",omitempty"`
+		ManagePriorityClasses bool                      `json:",omitempty"`
+		Labels                map[string]string         `json:",omitempty"`
+		Annotations           map[string]string         `json:",omitempty"`
+		ScaledDown            bool                      `json:",omitempty"`
+		IPFamilyPolicy        string                    `json:",omitempty"`
+		IPFamilies            []corev1.IPFamily         `json:",omitempty"`
+		TrustedCACerts        string                    `json:",omitempty"`
+		HTTPProxy             string                    `json:",omitempty"`
+		HTTPSProxy            string                    `json:",omitempty"`
+		NoProxy               []string                  `json:",omitempty"`
+		MigrationGate         *config.MigrationGateSpec `json:",omitempty"`
+		ConnectionPoolers     []string                  `json:",omitempty"`
+		DataRetentionPolicy   string                    `json:",omitempty"`
 	}{
-		Cfg:     cfg,
-		Version: sg.Spec.RequestedVersion,
+		Cfg:                   cfg,
+		Version:               sg.Spec.RequestedVersion,
+		TLS:                   sg.Spec.TLSSecretsByService(),
+		ImagePullSecrets:      sg.Spec.ImagePullSecrets,
+		PriorityClassName:     sg.Spec.PriorityClassName,
+		ManagePriorityClasses: sg.Spec.ManagePriorityClasses,
+		Labels:                sg.Spec.Labels,
+		Annotations:           sg.Spec.Annotations,
+		ScaledDown:            scaledDown,
+		IPFamilyPolicy:        string(sg.Spec.IPFamilyPolicy),
+		IPFamilies:            sg.Spec.IPFamilies,
+		HTTPProxy:             sg.Spec.HTTPProxy,
+		HTTPSProxy:            sg.Spec.HTTPSProxy,
+		NoProxy:               sg.Spec.NoProxy,
+		ConnectionPoolers:     enabledConnectionPoolers(sg),
+	}
+	if ref := sg.Spec.TrustedCACertsConfigMapRef; ref != nil {
+		updateIfChanged.TrustedCACerts = ref.Name
+	}
+	if gate := sg.Spec.MigrationGate; gate != (config.MigrationGateSpec{}) {
+		updateIfChanged.MigrationGate = &gate
+	}
+	if retention != config.DataRetentionPolicyRetain {
+		updateIfChanged.DataRetentionPolicy = string(retention)
 	}
 
-	return createOrUpdateObject(ctx, r, updateIfChanged, owner, obj, objKind)
+	err := createOrUpdateObject(ctx, r, updateIfChanged, sg.Spec.DriftPolicy.OrDefault(), retention, owner, obj, objKind)
+	if !isImmutableFieldError(err) {
+		return err
+	}
+
+	// The existing object keeps running as it is, rather than the reconcile
+	// failing until the spec is reverted. Only StatefulSets can be replaced
+	// without losing data.
+	sset, ok := any(obj).(*appsv1.StatefulSet)
+	if !ok || !sg.Spec.AllowStatefulSetRecreate {
+		recordPendingChange(ctx, pendingChange{kind: r.objectKind(obj), name: obj.GetName()})
+		return nil
+	}
+	if err := r.deleteStatefulSetForRecreate(ctx, sset); err != nil {
+		return err
+	}
+	sset.SetResourceVersion("")
+	return createOrUpdateObject(ctx, r, updateIfChanged, sg.Spec.DriftPolicy.OrDefault(), retention, owner, obj, objKind)
 }
 
 // Upsert a Kubernetes object.
@@ -56,11 +160,20 @@ func reconcileObject[T client.Object](
 // determine whether an existing in-cluster object is out of date and needs to
 // be replaced.
 //
+// The objects that retention applies to, i.e. PersistentVolumeClaims and
+// Secrets, are only owned by owner, and so garbage-collected along with it, if
+// retention is Delete.
+//
+// What obj sets is recorded in an annotation too, so that changes made by hand
+// to those fields, i.e. drift, can be found and handled according to policy,
+// and fields that obj doesn't set are left alone. Existing objects are
+// patched rather than replaced, for the same reason.
+//
 // Takes the reconciler as a parameter rather than being a method on it due to
 // limitations of Go generics.
 func createOrUpdateObject[R client.Object](
-	ctx context.Context, r *Reconciler, updateIfChanged any,
-	owner client.Object, obj, objKind R,
+	ctx context.Context, r *Reconciler, updateIfChanged any, policy config.DriftPolicy,
+	retention config.DataRetentionPolicy, owner client.Object, obj, objKind R,
 ) error {
 	logger := log.FromContext(ctx).WithValues("kind", obj.GetObjectKind().GroupVersionKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
 	namespacedName := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
@@ -80,13 +193,30 @@ func createOrUpdateObject[R client.Object](
 	// SetControllerReference on cluster-scoped resources gives the following
 	// error: "cluster-scoped resource must not have a namespace-scoped owner".
 	// non-namespaced resources will therefore not be garbage-collected when the
-	// ConfigMap is deleted.
-	if !isNamespaced(obj) {
+	// ConfigMap is deleted. Neither are the objects that hold data, unless the
+	// data retention policy allows it.
+	if !isNamespaced(obj) && (retention == config.DataRetentionPolicyDelete || !holdsData(obj)) {
 		if err := ctrl.SetControllerReference(owner, obj, r.Scheme); err != nil {
 			return errors.Newf("setting controller reference: %w", err)
 		}
 	}
 
+	foldSecretStringData(obj)
+	desired, err := desiredFields(obj)
+	if err != nil {
+		return errors.Wrap(err, "encoding desired object")
+	}
+	applied, err := hashValues(obj, desired)
+	if err != nil {
+		return err
+	}
+	appliedJSON, err := json.Marshal(applied)
+	if err != nil {
+		return err
+	}
+	annotations[config.AnnotationKeyLastApplied] = string(appliedJSON)
+	obj.SetAnnotations(annotations)
+
 	existingRes := objKind
 	if err := r.Client.Get(ctx, namespacedName, existingRes); err != nil {
 		if kerrors.IsNotFound(err) {
@@ -95,15 +225,43 @@ func createOrUpdateObject[R client.Object](
 				logger.Error(err, "error creating object")
 				return err
 			}
+			eventsFrom(ctx).eventf(EventReasonCreated, "Created %s %s.", r.objectKind(obj), obj.GetName())
 			return nil
 		}
 
 		logger.Error(err, "unexpected error getting object")
 		return err
 	}
+	if err := r.adoptObject(ctx, existingRes); err != nil {
+		return err
+	}
+
+	changed := cfgHash != existingRes.GetAnnotations()[config.AnnotationKeyConfigHash]
+	// Objects that hold data were owned whatever the data retention policy
+	// before it existed, so whether they are owned is compared too.
+	changed = changed || metav1.IsControlledBy(obj, owner) != metav1.IsControlledBy(existingRes, owner)
+	// Rotating a Secret that pods consume changes their dependency checksum,
+	// but not the config hash.
+	if template := podTemplateOf(obj); template != nil {
+		key := config.AnnotationKeyDependencyChecksum
+		changed = changed || template.Annotations[key] != podTemplateOf(existingRes).Annotations[key]
+	}
 
-	if cfgHash != existingRes.GetAnnotations()[config.AnnotationKeyConfigHash] {
+	// Objects created before what was applied to them was recorded lack the
+	// annotation. If they are up to date, what was applied is what would be
+	// applied now. Otherwise, which fields to remove is unknown, so they are
+	// replaced as a whole.
+	lastApplied := applied
+	if recorded, ok := existingRes.GetAnnotations()[config.AnnotationKeyLastApplied]; ok {
+		lastApplied = nil
+		if err := json.Unmarshal([]byte(recorded), &lastApplied); err != nil {
+			return errors.Wrapf(err, "decoding %s annotation", config.AnnotationKeyLastApplied)
+		}
+	} else if changed {
 		logger.Info("Found existing object with spec that does not match desired state. Clobbering it.")
+		// Some kinds, e.g. PodDisruptionBudgets, don't allow updates that
+		// aren't conditional on the resource version.
+		obj.SetResourceVersion(existingRes.GetResourceVersion())
 		if err := r.Client.Update(ctx, obj); err != nil {
 			logger.Error(err, "error updating object")
 			return err
@@ -111,10 +269,123 @@ func createOrUpdateObject[R client.Object](
 		return nil
 	}
 
-	logger.Info("Found existing object with spec that matches the desired state. Will do nothing.")
+	live, err := objectFields(existingRes)
+	if err != nil {
+		return errors.Wrap(err, "encoding existing object")
+	}
+	hashedLive, err := hashValues(obj, live)
+	if err != nil {
+		return err
+	}
+	if drifted := driftedFields("", lastApplied, hashedLive); len(drifted) > 0 {
+		logger.Info("Found fields of existing object that were changed by hand.", "fields", drifted, "policy", policy)
+		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+		if err != nil {
+			return err
+		}
+		recordDrift(ctx, objectDrift{kind: gvk.Kind, name: obj.GetName(), fields: drifted})
+		if policy == config.DriftPolicyRevert {
+			changed = true
+		} else if changed {
+			// Only what the appliance changed is applied, keeping the
+			// drift of other fields.
+			live = lastApplied
+		}
+	}
+	if !changed {
+		logger.Info("Found existing object with spec that matches the desired state. Will do nothing.")
+		return nil
+	}
+
+	modified := runtime.DeepCopyJSON(desired)
+	if err := unstructured.SetNestedField(modified, string(appliedJSON), "metadata", "annotations", config.AnnotationKeyLastApplied); err != nil {
+		return err
+	}
+	patch, err := mergePatch(obj, lastApplied, modified, live)
+	if err != nil {
+		return err
+	}
+	if patch == nil {
+		return nil
+	}
+	logger.Info("Found existing object with spec that does not match desired state. Patching it.")
+	if err := r.Client.Patch(ctx, obj, patch); err != nil {
+		logger.Error(err, "error patching object")
+		return err
+	}
+	recordVolumeExpansion(ctx, obj, existingRes)
 	return nil
 }
 
+// recordVolumeExpansion records an Event if obj is a PersistentVolumeClaim
+// that requests more storage than existing, which makes the cluster expand
+// its volume, if its storage class allows it.
+func recordVolumeExpansion(ctx context.Context, obj, existing client.Object) {
+	claim, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return
+	}
+	existingClaim := existing.(*corev1.PersistentVolumeClaim)
+	requested := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	previous := existingClaim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if requested.Cmp(previous) <= 0 {
+		return
+	}
+	eventsFrom(ctx).eventf(EventReasonVolumeExpansion, "Requested the expansion of PersistentVolumeClaim %s from %s to %s.", claim.Name, previous.String(), requested.String())
+}
+
+// applyIPFamilies sets the IP family policy and IP families of a Service,
+// unless they are empty, in which case the cluster chooses.
+func applyIPFamilies(svc *corev1.Service, policy corev1.IPFamilyPolicy, families []corev1.IPFamily) {
+	if policy != "" {
+		svc.Spec.IPFamilyPolicy = &policy
+	}
+	if len(families) > 0 {
+		svc.Spec.IPFamilies = families
+	}
+}
+
+// applyCustomMetadata adds user-supplied labels and annotations to an object,
+// and to the pod template of workloads. Labels and annotations already set by
+// the appliance are kept on conflict.
+func applyCustomMetadata(obj client.Object, labels, annotations map[string]string) {
+	if len(labels) == 0 && len(annotations) == 0 {
+		return
+	}
+	obj.SetLabels(mergeUnmanaged(obj.GetLabels(), labels))
+	obj.SetAnnotations(mergeUnmanaged(obj.GetAnnotations(), annotations))
+
+	var template *metav1.ObjectMeta
+	switch o := any(obj).(type) {
+	case *appsv1.Deployment:
+		template = &o.Spec.Template.ObjectMeta
+	case *appsv1.StatefulSet:
+		template = &o.Spec.Template.ObjectMeta
+	case *appsv1.DaemonSet:
+		template = &o.Spec.Template.ObjectMeta
+	}
+	if template != nil {
+		template.Labels = mergeUnmanaged(template.Labels, labels)
+		template.Annotations = mergeUnmanaged(template.Annotations, annotations)
+	}
+}
+
+// mergeUnmanaged returns a copy of managed with the entries of custom added,
+// except those whose keys are already in managed.
+func mergeUnmanaged(managed, custom map[string]string) map[string]string {
+	if len(custom) == 0 {
+		return managed
+	}
+	merged := make(map[string]string, len(managed)+len(custom))
+	for k, v := range custom {
+		merged[k] = v
+	}
+	for k, v := range managed {
+		merged[k] = v
+	}
+	return merged
+}
+
 func isNamespaced(obj client.Object) bool {
 	if _, ok := obj.(*rbacv1.ClusterRole); ok {
 		return true
@@ -122,6 +393,9 @@ func isNamespaced(obj client.Object) bool {
 	if _, ok := obj.(*rbacv1.ClusterRoleBinding); ok {
 		return true
 	}
+	if _, ok := obj.(*schedulingv1.PriorityClass); ok {
+		return true
+	}
 	return false
 }
 
@@ -135,6 +409,7 @@ func (r *Reconciler) ensureObjectDeleted(ctx context.Context, obj client.Object)
 		logger.Error(err, "unexpected error deleting resource")
 		return err
 	}
+	eventsFrom(ctx).eventf(EventReasonDeleted, "Deleted %s %s, which the spec no longer requires.", r.objectKind(obj), obj.GetName())
 	return nil
 }
 
@@ -0,0 +1,80 @@
+package reconciler
+
+import (
+	"context"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// scaleDownForMaintenance sets the replicas of a Deployment or StatefulSet to
+// zero, and records how many to restore once maintenance mode is over in an
+// annotation. objKind is an empty object of the same type, used to look up
+// the current number of replicas. Other kinds of objects are left as they are.
+//
+// The number recorded when maintenance mode is first enabled is kept for as
+// long as it stays enabled, so that it isn't overwritten with zero. A
+// HorizontalPodAutoscaler doesn't scale a workload that has zero replicas.
+func (r *Reconciler) scaleDownForMaintenance(ctx context.Context, obj, objKind client.Object) error {
+	var replicas **int32
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		replicas = &o.Spec.Replicas
+	case *appsv1.StatefulSet:
+		replicas = &o.Spec.Replicas
+	default:
+		return nil
+	}
+
+	before := pointers.Deref(*replicas, 1)
+	err := r.Get(ctx, client.ObjectKeyFromObject(obj), objKind)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "getting current replicas")
+	}
+	if err == nil {
+		if recorded, ok := replicasBeforeMaintenance(objKind); ok {
+			before = recorded
+		} else if current := workloadReplicas(objKind); current != nil {
+			before = *current
+		}
+	}
+
+	*replicas = pointers.Ptr(int32(0))
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[config.AnnotationKeyReplicasBeforeMaintenance] = strconv.Itoa(int(before))
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// replicasBeforeMaintenance returns the number of replicas recorded on a
+// workload when it was scaled down for maintenance.
+func replicasBeforeMaintenance(obj client.Object) (int32, bool) {
+	value, ok := obj.GetAnnotations()[config.AnnotationKeyReplicasBeforeMaintenance]
+	if !ok {
+		return 0, false
+	}
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || replicas < 0 {
+		return 0, false
+	}
+	return int32(replicas), true
+}
+
+func workloadReplicas(obj client.Object) *int32 {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return o.Spec.Replicas
+	case *appsv1.StatefulSet:
+		return o.Spec.Replicas
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package reconciler
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// gatedDatabase is a database that a service connects to, and waits for
+// before it starts.
+type gatedDatabase struct {
+	// name is the name of the database's objects, e.g. codeintel-db, before
+	// the name prefix is applied.
+	name string
+	// envPrefix is the prefix of the PGHOST and PGPORT env vars, e.g.
+	// "CODEINTEL_".
+	envPrefix string
+	// secretName is the name of the database's auth Secret, before the name
+	// prefix is applied.
+	secretName string
+}
+
+var (
+	gatePgsql        = gatedDatabase{name: "pgsql", envPrefix: "", secretName: "pgsql-auth"}
+	gateCodeIntel    = gatedDatabase{name: "codeintel-db", envPrefix: "CODEINTEL_", secretName: "codeintel-db-auth"}
+	gateCodeInsights = gatedDatabase{name: "codeinsights-db", envPrefix: "CODEINSIGHTS_", secretName: "codeinsights-db-auth"}
+)
+
+const (
+	migrationGateContainerName = "wait-for-databases"
+	schemaVersionVolumeName    = "schema-version"
+	schemaVersionMountPath     = "/schema-version"
+
+	// waitForDatabasesScript waits until each host:port in DATABASES accepts
+	// connections. It must not contain "$(", which Kubernetes would expand as
+	// a reference to an env var.
+	waitForDatabasesScript = `for db in $DATABASES; do
+  until nc -z -w 2 "${db%:*}" "${db##*:}"; do
+    echo "waiting for database $db"
+    sleep 2
+  done
+done
+`
+	// waitForSchemaVersionScript waits until the migrator has recorded the
+	// expected schema version.
+	waitForSchemaVersionScript = `until grep -qx "$EXPECTED_SCHEMA_VERSION" "$SCHEMA_VERSION_FILE" 2>/dev/null; do
+  echo "waiting for the schema to be migrated to $EXPECTED_SCHEMA_VERSION"
+  sleep 5
+done
+`
+)
+
+// applyMigrationGate adds an init container to the pod template that waits
+// until the given databases accept connections, and, if a schema version
+// ConfigMap is configured, until the migrator has migrated their schemas to
+// the requested version. It does nothing if the gate is disabled.
+func applyMigrationGate(template *corev1.PodTemplateSpec, sg *config.Sourcegraph, cfg config.StandardComponent, databases ...gatedDatabase) error {
+	gate := sg.Spec.MigrationGate
+	if gate.Disabled {
+		return nil
+	}
+
+	image, err := config.GetDefaultImage(sg, "alpine")
+	if err != nil {
+		return err
+	}
+	ctr := container.NewContainer(migrationGateContainerName, cfg, config.ContainerConfig{
+		Image: image,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("50M"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("50M"),
+			},
+		},
+	})
+
+	endpoints := make([]string, 0, len(databases))
+	for _, db := range databases {
+		// The service connects through the database's connection pooler, if
+		// it has one, so that's what it waits for.
+		ctr.Env = append(ctr.Env, db.endpointEnvVars(sg)...)
+		endpoints = append(endpoints, "$("+db.envPrefix+"PGHOST):$("+db.envPrefix+"PGPORT)")
+	}
+	ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "DATABASES", Value: strings.Join(endpoints, " ")})
+
+	script := waitForDatabasesScript
+	if gate.SchemaVersionConfigMap != "" {
+		script += waitForSchemaVersionScript
+		ctr.Env = append(ctr.Env,
+			corev1.EnvVar{Name: "EXPECTED_SCHEMA_VERSION", Value: sg.Spec.RequestedVersion},
+			corev1.EnvVar{Name: "SCHEMA_VERSION_FILE", Value: schemaVersionMountPath + "/version"},
+		)
+		ctr.VolumeMounts = append(ctr.VolumeMounts, corev1.VolumeMount{
+			Name:      schemaVersionVolumeName,
+			MountPath: schemaVersionMountPath,
+			ReadOnly:  true,
+		})
+		// The ConfigMap is optional, as the migrator may not have created it
+		// yet, in which case the init container keeps waiting.
+		vol := pod.NewVolumeFromConfigMap(schemaVersionVolumeName, gate.SchemaVersionConfigMap)
+		vol.ConfigMap.Optional = pointers.Ptr(true)
+		template.Spec.Volumes = append(template.Spec.Volumes, vol)
+	}
+	ctr.Command = []string{"sh", "-c", script}
+
+	template.Spec.InitContainers = append(template.Spec.InitContainers, ctr)
+	return nil
+}
@@ -0,0 +1,163 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var (
+	serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	podMonitorGVK     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+)
+
+// monitoredService describes how to scrape a service's PrometheusPort with a
+// ServiceMonitor, or a PodMonitor for services without a Service.
+type monitoredService struct {
+	name string
+	cfg  config.StandardComponent
+
+	// port is the name of a port of the service's Service, or of its pods'
+	// containers for PodMonitors. It selects one target per pod, whose address
+	// is then rewritten to the PrometheusPort, like the bundled Prometheus does
+	// with the prometheus.io/port annotation.
+	port       string
+	podMonitor bool
+}
+
+func monitoredServices(sg *config.Sourcegraph) []monitoredService {
+	return []monitoredService{
+		{name: "blobstore", cfg: sg.Spec.Blobstore, port: "blobstore"},
+		{name: "cadvisor", cfg: sg.Spec.Cadvisor, port: "http", podMonitor: true},
+		{name: "codeinsights-db", cfg: sg.Spec.CodeInsights, port: "codeinsights-db"},
+		{name: "codeintel-db", cfg: sg.Spec.CodeIntel, port: "pgsql"},
+		{name: "gitserver", cfg: sg.Spec.GitServer, port: "unused"},
+		{name: "grafana", cfg: sg.Spec.Grafana, port: "http"},
+		{name: "indexed-search", cfg: sg.Spec.IndexedSearch, port: "http"},
+		{name: "otel-collector", cfg: sg.Spec.OtelCollector, port: "metrics"},
+		{name: "pgsql", cfg: sg.Spec.PGSQL, port: "pgsql"},
+		{name: "precise-code-intel-worker", cfg: sg.Spec.PreciseCodeIntel, port: "http"},
+		{name: "redis-cache", cfg: sg.Spec.RedisCache, port: "redis"},
+		{name: "redis-store", cfg: sg.Spec.RedisStore, port: "redis"},
+		{name: "repo-updater", cfg: sg.Spec.RepoUpdater, port: "http"},
+		{name: "searcher", cfg: sg.Spec.Searcher, port: "http"},
+		{name: "sourcegraph-frontend", cfg: sg.Spec.Frontend, port: "http"},
+		{name: "symbols", cfg: sg.Spec.Symbols, port: "http"},
+		{name: "syntect-server", cfg: sg.Spec.SyntectServer, port: "http"},
+		{name: "worker", cfg: sg.Spec.Worker, port: "http"},
+	}
+}
+
+// reconcileMonitors manages the ServiceMonitors and PodMonitors used in the
+// serviceMonitor monitoring mode. They are defined by the Prometheus Operator's
+// CRDs, so they are handled as unstructured objects, and clusters without the
+// CRDs are tolerated as long as no monitors are requested.
+func (r *Reconciler) reconcileMonitors(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	if err := sg.Spec.Monitoring.Validate(); err != nil {
+		return err
+	}
+	for _, svc := range monitoredServices(sg) {
+		if err := r.reconcileMonitor(ctx, sg, owner, svc); err != nil {
+			return errors.Wrapf(err, "reconciling %s monitor", svc.name)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileMonitor(ctx context.Context, sg *config.Sourcegraph, owner client.Object, svc monitoredService) error {
+	cfg := monitorConfig{StandardComponent: svc.cfg, Monitoring: sg.Spec.Monitoring}
+	gvk := serviceMonitorGVK
+	if svc.podMonitor {
+		gvk = podMonitorGVK
+	}
+
+	labels := map[string]string{"deploy": "sourcegraph"}
+	maps.Copy(labels, sg.Spec.Monitoring.Labels)
+	monitor := &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(gvk)
+	monitor.SetName(sg.Spec.ObjectName(svc.name))
+	monitor.SetNamespace(sg.Namespace)
+	monitor.SetLabels(labels)
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(gvk)
+
+	if _, err := r.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if !meta.IsNoMatchError(err) {
+			return err
+		}
+		if cfg.IsDisabled() {
+			// Nothing can have been created on a cluster without the CRD.
+			return nil
+		}
+		return errors.Newf("monitoring.mode is serviceMonitor, but the cluster does not serve the %s API", gvk.GroupVersion())
+	}
+	if cfg.IsDisabled() {
+		return reconcileObject(ctx, r, cfg, monitor, existing, sg, owner)
+	}
+
+	// Mirror the relabeling of the bundled Prometheus, so that dashboards and
+	// alerts work the same way.
+	endpoint := map[string]any{
+		"port": svc.port,
+		"relabelings": []any{
+			map[string]any{
+				"sourceLabels": []any{"__address__"},
+				"regex":        `(.+)(?::\d+)`,
+				"replacement":  fmt.Sprintf("$1:%d", *svc.cfg.GetPrometheusPort()),
+				"targetLabel":  "__address__",
+				"action":       "replace",
+			},
+			map[string]any{
+				"sourceLabels": []any{"__meta_kubernetes_namespace"},
+				"targetLabel":  "ns",
+				"action":       "replace",
+			},
+			map[string]any{
+				"sourceLabels": []any{"__meta_kubernetes_pod_name"},
+				"targetLabel":  "instance",
+				"action":       "replace",
+			},
+			map[string]any{
+				"sourceLabels": []any{"__meta_kubernetes_pod_node_name"},
+				"targetLabel":  "nodename",
+				"action":       "replace",
+			},
+		},
+	}
+	endpointsKey := "endpoints"
+	if svc.podMonitor {
+		endpointsKey = "podMetricsEndpoints"
+	}
+	monitor.Object["spec"] = map[string]any{
+		"jobLabel": "app",
+		"selector": map[string]any{
+			"matchLabels": map[string]any{"app": sg.Spec.ObjectName(svc.name)},
+		},
+		endpointsKey: []any{endpoint},
+	}
+
+	return reconcileObject(ctx, r, cfg, monitor, existing, sg, owner)
+}
+
+// monitorConfig wraps a service's config for its ServiceMonitor or PodMonitor,
+// which only exists in the serviceMonitor monitoring mode, and for services
+// with a PrometheusPort.
+type monitorConfig struct {
+	config.StandardComponent
+	Monitoring config.MonitoringSpec
+}
+
+func (c monitorConfig) IsDisabled() bool {
+	return c.StandardComponent.IsDisabled() ||
+		c.GetPrometheusPort() == nil ||
+		c.Monitoring.GetMode() != config.MonitoringModeServiceMonitor
+}
@@ -0,0 +1,41 @@
+package reconciler
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// applyNamePrefix points the default container of a pod template that is named
+// after its prefixed app label back at the container, which keeps the name of
+// the service.
+func applyNamePrefix(template *corev1.PodTemplateSpec, sg *config.Sourcegraph) {
+	if sg.Spec.NamePrefix == "" {
+		return
+	}
+	if ctr, ok := template.Annotations[defaultContainerAnnotation]; ok {
+		template.Annotations[defaultContainerAnnotation] = strings.TrimPrefix(ctr, sg.Spec.NamePrefix+"-")
+	}
+}
+
+// serviceEndpointEnvVars returns the env vars that point a Sourcegraph service
+// at the services it discovers by name, for deployments with a NamePrefix.
+// Without one, the services' defaults already match our names.
+func serviceEndpointEnvVars(sg *config.Sourcegraph) []corev1.EnvVar {
+	if sg.Spec.NamePrefix == "" {
+		return nil
+	}
+	name := sg.Spec.ObjectName
+	return []corev1.EnvVar{
+		{Name: "SRC_FRONTEND_INTERNAL", Value: name("sourcegraph-frontend-internal")},
+		{Name: "REPO_UPDATER_URL", Value: serviceScheme(sg, sg.Spec.RepoUpdater) + "://" + name("repo-updater") + ":3182"},
+		{Name: "SEARCHER_URL", Value: "k8s+http://" + name("searcher") + ":3181"},
+		{Name: "SYMBOLS_URL", Value: serviceScheme(sg, sg.Spec.Symbols) + "://" + name("symbols") + ":3184"},
+		{Name: "INDEXED_SEARCH_SERVERS", Value: "k8s+rpc://" + name("indexed-search") + ":6070?kind=sts"},
+		{Name: "SRC_SYNTECT_SERVER", Value: serviceScheme(sg, sg.Spec.SyntectServer) + "://" + name("syntect-server") + ":9238"},
+	}
+}
@@ -0,0 +1,264 @@
+package reconciler
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/networkpolicy"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// networkPolicyService describes which pods may connect to a service in strict
+// mode, besides Prometheus scraping its PrometheusPort.
+type networkPolicyService struct {
+	name string
+	cfg  config.StandardComponent
+
+	// clients are the app labels of the services that connect to this one,
+	// without the NamePrefix.
+	clients []string
+
+	// fromAnyService allows every Sourcegraph pod to connect, for services
+	// that nearly everything uses, e.g. redis.
+	fromAnyService bool
+
+	// backupClients are the app labels of the pods that back up and restore
+	// this database, while backups are configured.
+	backupClients []string
+
+	// poolerClients are the app labels of the connection pooler in front of
+	// this database, while it is configured.
+	poolerClients []string
+}
+
+// networkPolicyServices is the graph of which services talk to each other.
+// When a service starts talking to another, it must be added here, or its
+// connections will be dropped in strict mode.
+func networkPolicyServices(sg *config.Sourcegraph) []networkPolicyService {
+	// The connection pooler of a database accepts connections from the same
+	// services as the database.
+	codeInsightsClients := []string{"sourcegraph-frontend", "worker"}
+	codeIntelClients := []string{"sourcegraph-frontend", "precise-code-intel-worker", "worker"}
+	pgsqlClients := []string{"sourcegraph-frontend", "precise-code-intel-worker", "repo-updater", "worker"}
+
+	return []networkPolicyService{
+		{name: "blobstore", cfg: sg.Spec.Blobstore, clients: []string{"sourcegraph-frontend", "precise-code-intel-worker", "worker"}},
+		{name: "cadvisor", cfg: sg.Spec.Cadvisor},
+		{name: "codeinsights-db", cfg: sg.Spec.CodeInsights, clients: codeInsightsClients, backupClients: databaseBackupClients("codeinsights-db", sg.Spec.CodeInsights.Backup), poolerClients: connectionPoolerClients("codeinsights-db", sg.Spec.CodeInsights.ConnectionPooler)},
+		{name: "codeinsights-db-pgbouncer", cfg: gateCodeInsights.connectionPooler(sg), clients: codeInsightsClients},
+		{name: "executor", cfg: sg.Spec.Executors},
+		{name: "codeintel-db", cfg: sg.Spec.CodeIntel, clients: codeIntelClients, backupClients: databaseBackupClients("codeintel-db", sg.Spec.CodeIntel.Backup), poolerClients: connectionPoolerClients("codeintel-db", sg.Spec.CodeIntel.ConnectionPooler)},
+		{name: "codeintel-db-pgbouncer", cfg: gateCodeIntel.connectionPooler(sg), clients: codeIntelClients},
+		{name: "gitserver", cfg: sg.Spec.GitServer, clients: []string{"sourcegraph-frontend", "repo-updater", "searcher", "symbols", "worker"}},
+		{name: "grafana", cfg: sg.Spec.Grafana, clients: []string{"sourcegraph-frontend"}},
+		{name: "indexed-search", cfg: sg.Spec.IndexedSearch, clients: []string{"sourcegraph-frontend"}},
+		{name: "otel-collector", cfg: sg.Spec.OtelCollector, fromAnyService: true},
+		{name: "pgsql", cfg: sg.Spec.PGSQL, clients: pgsqlClients, backupClients: databaseBackupClients("pgsql", sg.Spec.PGSQL.Backup), poolerClients: connectionPoolerClients("pgsql", sg.Spec.PGSQL.ConnectionPooler)},
+		{name: "pgsql-pgbouncer", cfg: gatePgsql.connectionPooler(sg), clients: pgsqlClients},
+		{name: "precise-code-intel-worker", cfg: sg.Spec.PreciseCodeIntel},
+		{name: "prometheus", cfg: bundledPrometheus(sg), clients: []string{"sourcegraph-frontend", "grafana"}},
+		{name: "redis-cache", cfg: sg.Spec.RedisCache, fromAnyService: true},
+		{name: "redis-store", cfg: sg.Spec.RedisStore, fromAnyService: true},
+		{name: "repo-updater", cfg: sg.Spec.RepoUpdater, clients: []string{"sourcegraph-frontend", "worker"}},
+		{name: "searcher", cfg: sg.Spec.Searcher, clients: []string{"sourcegraph-frontend"}},
+		{name: "sourcegraph-frontend", cfg: sg.Spec.Frontend, fromAnyService: true},
+		{name: "symbols", cfg: sg.Spec.Symbols, clients: []string{"sourcegraph-frontend"}},
+		{name: "syntect-server", cfg: sg.Spec.SyntectServer, clients: []string{"sourcegraph-frontend"}},
+		{name: "worker", cfg: sg.Spec.Worker},
+	}
+}
+
+// reconcileNetworkPolicies manages the NetworkPolicies of the configured mode,
+// and deletes those of every other mode.
+func (r *Reconciler) reconcileNetworkPolicies(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	policies := sg.Spec.NetworkPolicies
+	if err := policies.Validate(); err != nil {
+		return err
+	}
+	mode := policies.GetMode()
+	services := networkPolicyServices(sg)
+
+	// The frontend must be reachable by ingress controllers, which usually
+	// run in another namespace, in every mode.
+	public := networkpolicy.NewNetworkPolicy(sg.Spec.ObjectName("sourcegraph-frontend-public"), sg.Namespace)
+	public.Spec.PodSelector.MatchLabels["app"] = sg.Spec.ObjectName("sourcegraph-frontend")
+	public.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{
+		Ports: []networkingv1.NetworkPolicyPort{{
+			Protocol: pointers.Ptr(corev1.ProtocolTCP),
+			Port:     pointers.Ptr(intstr.FromString("http")),
+		}},
+	}}
+	publicCfg := networkPolicyConfig{
+		NetworkPolicies: policies,
+		disabled:        mode == config.NetworkPolicyModeNone || sg.Spec.Frontend.IsDisabled(),
+	}
+	if err := reconcileObject(ctx, r, publicCfg, &public, &networkingv1.NetworkPolicy{}, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling frontend NetworkPolicy")
+	}
+
+	// In namespace-isolated mode, Sourcegraph pods accept traffic from any pod
+	// in the namespace, and from the extra peers on any PrometheusPort.
+	var prometheusPorts []int32
+	for _, svc := range services {
+		if port := svc.cfg.GetPrometheusPort(); port != nil && !svc.cfg.IsDisabled() {
+			prometheusPorts = append(prometheusPorts, int32(*port))
+		}
+	}
+	prometheusPorts = uniqueSorted(prometheusPorts)
+	isolation := networkpolicy.NewNetworkPolicy(sg.Spec.ObjectName("sourcegraph-namespace-isolation"), sg.Namespace)
+	isolation.Spec.PodSelector = sourcegraphPodSelector()
+	isolation.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{
+		From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+	}}
+	isolation.Spec.Ingress = appendMonitoringRule(isolation.Spec.Ingress, policies, prometheusPorts)
+	isolationCfg := networkPolicyConfig{
+		NetworkPolicies: policies,
+		PrometheusPorts: prometheusPorts,
+		disabled:        mode != config.NetworkPolicyModeNamespaceIsolated,
+	}
+	if err := reconcileObject(ctx, r, isolationCfg, &isolation, &networkingv1.NetworkPolicy{}, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling namespace isolation NetworkPolicy")
+	}
+
+	// In strict mode, Sourcegraph pods deny all traffic that the per-service
+	// policies below don't allow, including for services added after the
+	// graph was last updated.
+	deny := networkpolicy.NewNetworkPolicy(sg.Spec.ObjectName("sourcegraph-default-deny"), sg.Namespace)
+	deny.Spec.PodSelector = sourcegraphPodSelector()
+	denyCfg := networkPolicyConfig{
+		NetworkPolicies: policies,
+		disabled:        mode != config.NetworkPolicyModeStrict,
+	}
+	if err := reconcileObject(ctx, r, denyCfg, &deny, &networkingv1.NetworkPolicy{}, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling default deny NetworkPolicy")
+	}
+
+	for _, svc := range services {
+		if err := r.reconcileServiceNetworkPolicy(ctx, sg, owner, svc); err != nil {
+			return errors.Wrapf(err, "reconciling %s NetworkPolicy", svc.name)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileServiceNetworkPolicy(ctx context.Context, sg *config.Sourcegraph, owner client.Object, svc networkPolicyService) error {
+	policies := sg.Spec.NetworkPolicies
+	policy := networkpolicy.NewNetworkPolicy(sg.Spec.ObjectName(svc.name), sg.Namespace)
+
+	var clients []networkingv1.NetworkPolicyPeer
+	if svc.fromAnyService {
+		clients = append(clients, networkingv1.NetworkPolicyPeer{PodSelector: pointers.Ptr(sourcegraphPodSelector())})
+	}
+	for _, app := range append(append(svc.clients, svc.backupClients...), svc.poolerClients...) {
+		clients = append(clients, networkingv1.NetworkPolicyPeer{PodSelector: appPodSelector(sg, app)})
+	}
+	if len(clients) > 0 {
+		policy.Spec.Ingress = append(policy.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{From: clients})
+	}
+
+	var prometheusPorts []int32
+	if port := svc.cfg.GetPrometheusPort(); port != nil {
+		prometheusPorts = []int32{int32(*port)}
+		policy.Spec.Ingress = append(policy.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			Ports: tcpPorts(prometheusPorts),
+			From:  []networkingv1.NetworkPolicyPeer{{PodSelector: appPodSelector(sg, "prometheus")}},
+		})
+		policy.Spec.Ingress = appendMonitoringRule(policy.Spec.Ingress, policies, prometheusPorts)
+	}
+
+	cfg := networkPolicyConfig{
+		NetworkPolicies: policies,
+		PrometheusPorts: prometheusPorts,
+		BackupClients:   svc.backupClients,
+		PoolerClients:   svc.poolerClients,
+		disabled:        policies.GetMode() != config.NetworkPolicyModeStrict || svc.cfg.IsDisabled(),
+	}
+	return reconcileObject(ctx, r, cfg, &policy, &networkingv1.NetworkPolicy{}, sg, owner)
+}
+
+// appendMonitoringRule allows the extra CIDRs and namespaces to connect to the
+// given PrometheusPorts, if any are configured.
+func appendMonitoringRule(rules []networkingv1.NetworkPolicyIngressRule, policies config.NetworkPoliciesSpec, ports []int32) []networkingv1.NetworkPolicyIngressRule {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, cidr := range policies.ExtraAllowedCIDRs {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+	for _, namespace := range policies.ExtraAllowedNamespaces {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{corev1.LabelMetadataName: namespace},
+			},
+		})
+	}
+	if len(peers) == 0 || len(ports) == 0 {
+		return rules
+	}
+	return append(rules, networkingv1.NetworkPolicyIngressRule{
+		Ports: tcpPorts(ports),
+		From:  peers,
+	})
+}
+
+func databaseBackupClients(name string, backup *config.DatabaseBackupSpec) []string {
+	if backup == nil {
+		return nil
+	}
+	return []string{name + "-backup", name + "-restore"}
+}
+
+func connectionPoolerClients(name string, pooler *config.ConnectionPoolerSpec) []string {
+	if !pooler.IsEnabled() {
+		return nil
+	}
+	return []string{connectionPoolerName(name)}
+}
+
+func sourcegraphPodSelector() metav1.LabelSelector {
+	return metav1.LabelSelector{MatchLabels: map[string]string{"deploy": "sourcegraph"}}
+}
+
+func appPodSelector(sg *config.Sourcegraph, app string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{MatchLabels: map[string]string{"app": sg.Spec.ObjectName(app)}}
+}
+
+func tcpPorts(ports []int32) []networkingv1.NetworkPolicyPort {
+	policyPorts := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{
+			Protocol: pointers.Ptr(corev1.ProtocolTCP),
+			Port:     pointers.Ptr(intstr.FromInt32(port)),
+		})
+	}
+	return policyPorts
+}
+
+func uniqueSorted(ports []int32) []int32 {
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	var unique []int32
+	for i, port := range ports {
+		if i == 0 || port != ports[i-1] {
+			unique = append(unique, port)
+		}
+	}
+	return unique
+}
+
+// networkPolicyConfig holds everything a NetworkPolicy is built from, so that
+// it's updated when any of it changes.
+type networkPolicyConfig struct {
+	NetworkPolicies config.NetworkPoliciesSpec
+	PrometheusPorts []int32  `json:",omitempty"`
+	BackupClients   []string `json:",omitempty"`
+	PoolerClients   []string `json:",omitempty"`
+	disabled        bool
+}
+
+func (c networkPolicyConfig) IsDisabled() bool { return c.disabled }
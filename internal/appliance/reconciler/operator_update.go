@@ -0,0 +1,82 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// operatorContainerName is the name of the container of the appliance in its
+// Deployment.
+const operatorContainerName = "appliance"
+
+// reconcileOperatorUpdate moves the Deployment of the appliance to its image
+// for the requested version, if the spec opts into automatic updates. It
+// returns the version that the appliance is moving to, while this appliance
+// runs another one, in which case the caller must not roll out the requested
+// version: the new appliance takes over once its Deployment rolls out. It
+// returns an empty version if the appliance runs its image for the requested
+// version, or isn't updated: with the Manual policy, without an
+// OperatorDeployment, or if the image belongs to a version older than the
+// current one, which is recorded as a warning.
+func (r *Reconciler) reconcileOperatorUpdate(ctx context.Context, sg *config.Sourcegraph) (string, error) {
+	if sg.Spec.OperatorUpdatePolicy.OrDefault() != config.OperatorUpdatePolicyAutomatic || r.OperatorDeployment.Name == "" {
+		return "", nil
+	}
+	// The spec is valid, so the image resolves.
+	version, image, err := config.GetOperatorImage(sg)
+	if err != nil {
+		return "", err
+	}
+
+	var dep appsv1.Deployment
+	if err := r.Get(ctx, r.OperatorDeployment, &dep); err != nil {
+		return "", errors.Wrapf(err, "getting the Deployment of the appliance %s", r.OperatorDeployment)
+	}
+	var ctr *corev1.Container
+	for i := range dep.Spec.Template.Spec.Containers {
+		if dep.Spec.Template.Spec.Containers[i].Name == operatorContainerName {
+			ctr = &dep.Spec.Template.Spec.Containers[i]
+		}
+	}
+	if ctr == nil {
+		return "", errors.Newf("the Deployment of the appliance %s has no %s container", r.OperatorDeployment, operatorContainerName)
+	}
+	if ctr.Image == image {
+		if r.OperatorVersion != "" && r.OperatorVersion != version {
+			// The Deployment is still replacing this appliance.
+			return version, nil
+		}
+		return "", nil
+	}
+
+	events := eventsFrom(ctx)
+	if err := config.ValidateOperatorUpdate(sg.Status.CurrentVersion, version); err != nil {
+		events.eventf(EventReasonOperatorUpdateBlocked, "%s", err)
+		return "", nil
+	}
+	ctr.Image = image
+	if err := r.Update(ctx, &dep); err != nil {
+		return "", errors.Wrapf(err, "updating the Deployment of the appliance %s", r.OperatorDeployment)
+	}
+	events.eventf(EventReasonOperatorUpdated, "Updating the appliance to version %s, which rolls out version %s.", version, sg.Spec.RequestedVersion)
+	return version, nil
+}
+
+// setOperatorUpdateCondition records that the appliance is moving to version,
+// and waits for it before rolling out the requested version.
+func setOperatorUpdateCondition(status *config.SourcegraphStatus, version string) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    config.ConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  config.ReasonOperatorUpdateInProgress,
+		Message: fmt.Sprintf("Updating the appliance to version %s before rolling out version %s", version, status.RequestedVersion),
+	})
+}
@@ -0,0 +1,126 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+func TestOperatorUpdate(t *testing.T) {
+	ctx := context.Background()
+	const oldImage = "index.docker.io/sourcegraph/appliance:5.3.0"
+	const newImage = "index.docker.io/sourcegraph/appliance:5.3.9104"
+	operatorDeployment := types.NamespacedName{Namespace: "appliance", Name: "sourcegraph-appliance"}
+	repoUpdater := client.ObjectKey{Namespace: renderedSpec.Namespace, Name: "repo-updater"}
+
+	// newReconciler returns a reconciler of version 5.3.0 of the appliance,
+	// whose Deployment runs image, for spec.
+	newReconciler := func(t *testing.T, spec, image string) (client.Client, *Reconciler, *record.FakeRecorder) {
+		t.Helper()
+		dep := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorDeployment.Namespace, Name: operatorDeployment.Name},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "appliance", Image: image}},
+					},
+				},
+			},
+		}
+		c := fake.NewClientBuilder().WithObjects(dep, newSpecConfigMap([]byte(spec))).Build()
+		recorder := record.NewFakeRecorder(1000)
+		return c, &Reconciler{
+			Client:             c,
+			Scheme:             scheme.Scheme,
+			Recorder:           recorder,
+			OperatorVersion:    "5.3.0",
+			OperatorDeployment: operatorDeployment,
+		}, recorder
+	}
+	operatorImage := func(t *testing.T, c client.Client) string {
+		t.Helper()
+		var dep appsv1.Deployment
+		require.NoError(t, c.Get(ctx, operatorDeployment, &dep))
+		return dep.Spec.Template.Spec.Containers[0].Image
+	}
+	// events returns the Events recorded since it was last called.
+	events := func(recorder *record.FakeRecorder) string {
+		var events []string
+		for {
+			select {
+			case event := <-recorder.Events:
+				events = append(events, event)
+			default:
+				return strings.Join(events, "\n")
+			}
+		}
+	}
+	statusOf := func(cm corev1.ConfigMap) config.SourcegraphStatus {
+		return statusFromAnnotations(cm.GetAnnotations())
+	}
+	repoUpdaterSpec := string(readSpecFixture(t, "repo-updater/default"))
+	automatic := strings.Replace(repoUpdaterSpec, "spec:\n", "spec:\n  operatorUpdatePolicy: Automatic\n", 1)
+
+	t.Run("automatic", func(t *testing.T) {
+		c, r, recorder := newReconciler(t, automatic, oldImage)
+
+		// The appliance moves to the image of the requested version first,
+		// and leaves the rollout to it.
+		result, cm := reconcileSpecConfigMap(t, r)
+		require.Equal(t, rolloutPollInterval, result.RequeueAfter)
+		require.Equal(t, newImage, operatorImage(t, c))
+		require.True(t, kerrors.IsNotFound(c.Get(ctx, repoUpdater, &appsv1.Deployment{})))
+		status := statusOf(cm)
+		require.Equal(t, "5.3.0", status.OperatorVersion)
+		require.Equal(t, config.ReasonOperatorUpdateInProgress, meta.FindStatusCondition(status.Conditions, config.ConditionReady).Reason)
+		require.Contains(t, events(recorder), "Normal OperatorUpdated Updating the appliance to version 5.3.9104, which rolls out version 5.3.9104.")
+
+		// The old appliance keeps waiting until it is replaced.
+		reconcileSpecConfigMap(t, r)
+		require.True(t, kerrors.IsNotFound(c.Get(ctx, repoUpdater, &appsv1.Deployment{})))
+
+		// The new appliance rolls out the requested version.
+		r.OperatorVersion = "5.3.9104"
+		_, cm = reconcileSpecConfigMap(t, r)
+		require.NoError(t, c.Get(ctx, repoUpdater, &appsv1.Deployment{}))
+		require.Equal(t, "5.3.9104", statusOf(cm).OperatorVersion)
+		require.Equal(t, "5.3.9104", statusOf(cm).CurrentVersion)
+	})
+
+	t.Run("manual", func(t *testing.T) {
+		c, r, _ := newReconciler(t, repoUpdaterSpec, oldImage)
+
+		_, cm := reconcileSpecConfigMap(t, r)
+		require.Equal(t, oldImage, operatorImage(t, c))
+		require.NoError(t, c.Get(ctx, repoUpdater, &appsv1.Deployment{}))
+		require.Equal(t, "5.3.0", statusOf(cm).OperatorVersion)
+	})
+
+	t.Run("no downgrade", func(t *testing.T) {
+		c, r, recorder := newReconciler(t, automatic, oldImage)
+		ctx, _ := r.withEventRecorder(ctx, newSpecConfigMap(nil))
+
+		sg, _, err := config.DecodeConfigYAML([]byte(automatic), config.DecodeOptions{})
+		require.NoError(t, err)
+		sg.Status.CurrentVersion = "5.4.0"
+		version, err := r.reconcileOperatorUpdate(ctx, &sg)
+		require.NoError(t, err)
+		require.Empty(t, version)
+		require.Equal(t, oldImage, operatorImage(t, c))
+		require.Contains(t, events(recorder), "Warning OperatorUpdateBlocked not moving the appliance to version 5.3.9104, since Sourcegraph runs version 5.4.0")
+	})
+}
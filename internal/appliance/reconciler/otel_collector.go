@@ -0,0 +1,224 @@
+package reconciler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/configmap"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// otelEnvVars returns the env vars that point a trace-emitting service at the
+// right OpenTelemetry endpoint: the bundled collector if it is enabled, or a
+// node-local agent otherwise.
+func otelEnvVars(sg *config.Sourcegraph) []corev1.EnvVar {
+	if sg.Spec.OtelCollector.IsDisabled() {
+		return container.EnvVarsOtel()
+	}
+	return []corev1.EnvVar{
+		{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: serviceScheme(sg, sg.Spec.OtelCollector) + "://" + sg.Spec.ObjectName("otel-collector") + ":4317"},
+	}
+}
+
+func (r *Reconciler) reconcileOtelCollector(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	if err := sg.Spec.OtelCollector.Validate(); err != nil {
+		return errors.Wrap(err, "validating otel collector config")
+	}
+
+	headers, err := r.otelCollectorExporterHeaders(ctx, sg)
+	if err != nil {
+		return err
+	}
+
+	if err := r.reconcileOtelCollectorConfigMap(ctx, sg, headers, owner); err != nil {
+		return errors.Wrap(err, "reconciling ConfigMap")
+	}
+	if err := r.reconcileOtelCollectorDeployment(ctx, sg, headers, owner); err != nil {
+		return errors.Wrap(err, "reconciling Deployment")
+	}
+	if err := r.reconcileOtelCollectorService(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Service")
+	}
+	return nil
+}
+
+// otelCollectorHeader is an exporter header whose value is read from an env
+// var, so that secret values don't end up in the collector ConfigMap.
+type otelCollectorHeader struct {
+	Name   string
+	EnvVar string
+	Secret string
+}
+
+func (r *Reconciler) otelCollectorExporterHeaders(ctx context.Context, sg *config.Sourcegraph) ([]otelCollectorHeader, error) {
+	cfg := sg.Spec.OtelCollector
+	ref := cfg.ExporterHeadersSecretRef
+	if cfg.IsDisabled() || ref == nil {
+		return nil, nil
+	}
+
+	var headersSecret corev1.Secret
+	if err := r.GetObject(ctx, ref.Name, sg.Namespace, &headersSecret); err != nil {
+		return nil, errors.Wrapf(err, "getting otel collector headers secret %q", ref.Name)
+	}
+
+	names := make([]string, 0, len(headersSecret.Data))
+	for name := range headersSecret.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := make([]otelCollectorHeader, 0, len(names))
+	for i, name := range names {
+		headers = append(headers, otelCollectorHeader{
+			Name:   name,
+			EnvVar: fmt.Sprintf("OTEL_EXPORTER_HEADER_%d", i),
+			Secret: ref.Name,
+		})
+	}
+	return headers, nil
+}
+
+func (r *Reconciler) reconcileOtelCollectorDeployment(ctx context.Context, sg *config.Sourcegraph, headers []otelCollectorHeader, owner client.Object) error {
+	name := "otel-collector"
+	cfg := sg.Spec.OtelCollector
+
+	defaultImage, err := config.GetDefaultImage(sg, name)
+	if err != nil {
+		return err
+	}
+	ctr := container.NewContainer(name, cfg, config.ContainerConfig{
+		Image: defaultImage,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1G"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("3"),
+				corev1.ResourceMemory: resource.MustParse("3G"),
+			},
+		},
+	})
+	ctr.Args = []string{"--config", "/etc/otel-collector/config.yaml"}
+	for _, header := range headers {
+		ctr.Env = append(ctr.Env, container.NewEnvVarSecretKeyRef(header.EnvVar, header.Secret, header.Name))
+	}
+
+	ctr.Ports = []corev1.ContainerPort{
+		{Name: "otlp-grpc", ContainerPort: 4317},
+		{Name: "otlp-http", ContainerPort: 4318},
+		{Name: "metrics", ContainerPort: 8888},
+		{Name: "health", ContainerPort: 13133},
+	}
+	ctr.ReadinessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/",
+				Port: intstr.FromString("health"),
+			},
+		},
+		PeriodSeconds:  5,
+		TimeoutSeconds: 5,
+	}
+	ctr.LivenessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/",
+				Port: intstr.FromString("health"),
+			},
+		},
+		TimeoutSeconds: 5,
+	}
+	ctr.VolumeMounts = []corev1.VolumeMount{
+		{Name: "config", MountPath: "/etc/otel-collector"},
+	}
+
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate.Template.Spec.Volumes = []corev1.Volume{
+		pod.NewVolumeFromConfigMap("config", sg.Spec.ObjectName(name)),
+	}
+
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	if err := applyDeploymentStrategy(&dep, cfg); err != nil {
+		return err
+	}
+	dep.Spec.Template = podTemplate.Template
+
+	return reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileOtelCollectorConfigMap(ctx context.Context, sg *config.Sourcegraph, headers []otelCollectorHeader, owner client.Object) error {
+	cfg := sg.Spec.OtelCollector
+
+	collectorConfig := cfg.CustomConfigYAML
+	if collectorConfig == "" {
+		tmpl, err := template.New("otel-collector-config").Parse(string(config.OtelCollectorConfigTemplate))
+		if err != nil {
+			return errors.Wrap(err, "parsing default otel collector config template")
+		}
+		// The collector binds explicitly to the host it listens on, so it
+		// must be told to use IPv6 if the Service does.
+		listenHost := sg.Spec.ListenHostFor(cfg)
+		if strings.Contains(listenHost, ":") {
+			listenHost = "[" + listenHost + "]"
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, struct {
+			ListenHost       string
+			ExporterEndpoint string
+			Headers          []otelCollectorHeader
+		}{
+			ListenHost:       listenHost,
+			ExporterEndpoint: cfg.ExporterEndpoint,
+			Headers:          headers,
+		}); err != nil {
+			return errors.Wrap(err, "rendering default otel collector config template")
+		}
+		collectorConfig = buf.String()
+	}
+
+	cm := configmap.NewConfigMap(sg.Spec.ObjectName("otel-collector"), sg.Namespace)
+	cm.Data = map[string]string{
+		"config.yaml": collectorConfig,
+	}
+
+	return reconcileObject(ctx, r, cfg, &cm, &corev1.ConfigMap{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileOtelCollectorService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := sg.Spec.ObjectName("otel-collector")
+	cfg := sg.Spec.OtelCollector
+
+	svc := service.NewService(name, sg.Namespace, cfg)
+	svc.Spec.Ports = []corev1.ServicePort{
+		{Name: "otlp-grpc", Port: 4317, TargetPort: intstr.FromString("otlp-grpc")},
+		{Name: "otlp-http", Port: 4318, TargetPort: intstr.FromString("otlp-http")},
+		{Name: "metrics", Port: 8888, TargetPort: intstr.FromString("metrics")},
+	}
+	svc.Spec.Selector = map[string]string{
+		"app": name,
+	}
+
+	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
+}
@@ -0,0 +1,19 @@
+package reconciler
+
+func (suite *ApplianceTestSuite) TestDeployOtelCollector() {
+	for _, tc := range []struct {
+		name string
+	}{
+		{name: "otel-collector/default"},
+		{name: "otel-collector/with-custom-config"},
+		{name: "otel-collector/with-exporter-endpoint"},
+
+		// Services that emit traces should send them to the collector.
+		{name: "otel-collector/with-worker"},
+	} {
+		suite.Run(tc.name, func() {
+			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
+			suite.makeGoldenAssertions(namespace, tc.name)
+		})
+	}
+}
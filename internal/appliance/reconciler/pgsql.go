@@ -16,7 +16,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/secret"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/statefulset"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
@@ -31,15 +30,18 @@ func (r *Reconciler) reconcilePGSQL(ctx context.Context, sg *config.Sourcegraph,
 	if err := r.reconcilePGSQLConfigMap(ctx, sg, owner); err != nil {
 		return err
 	}
-	if err := r.reconcilePGSQLSecret(ctx, sg, owner); err != nil {
-		return err
-	}
 	if err := r.reconcilePGSQLService(ctx, sg, owner); err != nil {
 		return err
 	}
 	if err := r.reconcilePGSQLServiceAccount(ctx, sg, owner); err != nil {
 		return err
 	}
+	if err := r.reconcileDatabaseBackups(ctx, sg, owner, "pgsql", sg.Spec.ObjectName("pgsql-auth"), sg.Spec.PGSQL, sg.Spec.PGSQL.Backup); err != nil {
+		return err
+	}
+	if err := r.reconcileConnectionPooler(ctx, sg, owner, "pgsql", sg.Spec.ObjectName("pgsql-auth"), sg.Spec.PGSQL, sg.Spec.PGSQL.ConnectionPooler); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -65,14 +67,8 @@ func (r *Reconciler) reconcilePGSQLStatefulSet(ctx context.Context, sg *config.S
 			},
 		},
 	})
-	ctr.SecurityContext = &corev1.SecurityContext{
-		RunAsUser:                pointers.Ptr[int64](999),
-		RunAsGroup:               pointers.Ptr[int64](999),
-		AllowPrivilegeEscalation: pointers.Ptr(false),
-		ReadOnlyRootFilesystem:   pointers.Ptr(true),
-	}
 
-	databaseSecretName := "pgsql-auth"
+	databaseSecretName := sg.Spec.ObjectName("pgsql-auth")
 	ctr.Env = append(ctr.Env, container.EnvVarsPostgres(databaseSecretName)...)
 	ctr.Ports = []corev1.ContainerPort{{Name: name, ContainerPort: 5432}}
 	ctr.LivenessProbe = &corev1.Probe{
@@ -124,44 +120,9 @@ func (r *Reconciler) reconcilePGSQLStatefulSet(ctx context.Context, sg *config.S
 			},
 		},
 	})
-	initCtr.SecurityContext = &corev1.SecurityContext{
-		RunAsUser:                pointers.Ptr[int64](999),
-		RunAsGroup:               pointers.Ptr[int64](999),
-		AllowPrivilegeEscalation: pointers.Ptr(false),
-		ReadOnlyRootFilesystem:   pointers.Ptr(true),
-	}
 	initCtr.VolumeMounts = []corev1.VolumeMount{{Name: "disk", MountPath: "/data"}}
 	initCtr.Command = []string{"sh", "-c", "if [ -d /data/pgdata-12 ]; then chmod 750 /data/pgdata-12; fi"}
 
-	pgExpCtrImage, err := config.GetDefaultImage(sg, "pgsql-exporter")
-	if err != nil {
-		return err
-	}
-
-	pgExpCtr := container.NewContainer("pgsql-exporter", cfg, config.ContainerConfig{
-		Image: pgExpCtrImage,
-		Resources: &corev1.ResourceRequirements{
-			Requests: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("10m"),
-				corev1.ResourceMemory: resource.MustParse("50M"),
-			},
-			Limits: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("10m"),
-				corev1.ResourceMemory: resource.MustParse("50M"),
-			},
-		},
-	})
-	pgExpCtr.SecurityContext = &corev1.SecurityContext{
-		RunAsUser:                pointers.Ptr[int64](999),
-		RunAsGroup:               pointers.Ptr[int64](999),
-		AllowPrivilegeEscalation: pointers.Ptr(false),
-		ReadOnlyRootFilesystem:   pointers.Ptr(true),
-	}
-	pgExpCtr.Env = append(pgExpCtr.Env, container.EnvVarsPostgresExporter(databaseSecretName)...)
-	pgExpCtr.Env = append(pgExpCtr.Env, corev1.EnvVar{
-		Name: "PG_EXPORTER_EXTEND_QUERY_PATH", Value: "/config/queries.yaml",
-	})
-
 	podVolumes := []corev1.Volume{
 		pod.NewVolumeEmptyDir("lockdir"),
 		{Name: "dshm", VolumeSource: corev1.VolumeSource{
@@ -172,33 +133,36 @@ func (r *Reconciler) reconcilePGSQLStatefulSet(ctx context.Context, sg *config.S
 		}},
 		{Name: "disk", VolumeSource: corev1.VolumeSource{
 			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-				ClaimName: "pgsql",
+				ClaimName: sg.Spec.ObjectName("pgsql"),
 			},
 		}},
 		{Name: "pgsql-conf", VolumeSource: corev1.VolumeSource{
 			ConfigMap: &corev1.ConfigMapVolumeSource{
 				DefaultMode: pointers.Ptr[int32](0777),
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: "pgsql-conf",
+					Name: sg.Spec.ObjectName("pgsql-conf"),
 				},
 			},
 		}},
 	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
 	podTemplate.Template.Spec.TerminationGracePeriodSeconds = pointers.Ptr[int64](120)
 	podTemplate.Template.Spec.InitContainers = []corev1.Container{initCtr}
-	podTemplate.Template.Spec.Containers = []corev1.Container{ctr, pgExpCtr}
-	podTemplate.Template.Spec.ServiceAccountName = name
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
 	podTemplate.Template.Spec.Volumes = podVolumes
-	podTemplate.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
-		FSGroup:             pointers.Ptr[int64](999),
-		RunAsUser:           pointers.Ptr[int64](999),
-		RunAsGroup:          pointers.Ptr[int64](999),
-		FSGroupChangePolicy: pointers.Ptr(corev1.FSGroupChangeOnRootMismatch),
+
+	if err := applyPostgresExporter(&podTemplate.Template, sg, cfg, cfg.Exporter, databaseSecretName, "/config/queries.yaml", "50M"); err != nil {
+		return err
 	}
 
-	sset := statefulset.NewStatefulSet(name, sg.Namespace, sg.Spec.RequestedVersion)
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	sset := statefulset.NewStatefulSet(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	applyStatefulSetStrategy(&sset, sg.Spec.PGSQL)
 	sset.Spec.Template = podTemplate.Template
 
 	return reconcileObject(ctx, r, sg.Spec.PGSQL, &sset, &appsv1.StatefulSet{}, sg, owner)
@@ -206,7 +170,7 @@ func (r *Reconciler) reconcilePGSQLStatefulSet(ctx context.Context, sg *config.S
 
 func (r *Reconciler) reconcilePGSQLPersistentVolumeClaim(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	cfg := sg.Spec.PGSQL
-	p, err := pvc.NewPersistentVolumeClaim("pgsql", sg.Namespace, cfg)
+	p, err := pvc.NewPersistentVolumeClaim(sg.Spec.ObjectName("pgsql"), sg.Namespace, cfg)
 	if err != nil {
 		return err
 	}
@@ -214,18 +178,18 @@ func (r *Reconciler) reconcilePGSQLPersistentVolumeClaim(ctx context.Context, sg
 }
 
 func (r *Reconciler) reconcilePGSQLConfigMap(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cm := configmap.NewConfigMap("pgsql-conf", sg.Namespace)
+	cm := configmap.NewConfigMap(sg.Spec.ObjectName("pgsql-conf"), sg.Namespace)
 	cm.Data = map[string]string{"postgresql.conf": string(config.PgsqlConfig)}
 
 	return reconcileObject(ctx, r, sg.Spec.PGSQL, &cm, &corev1.ConfigMap{}, sg, owner)
 }
 
 func (r *Reconciler) reconcilePGSQLSecret(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	scrt := secret.NewSecret("pgsql-auth", sg.Namespace, sg.Spec.RequestedVersion)
+	scrt := secret.NewSecret(sg.Spec.ObjectName("pgsql-auth"), sg.Namespace, sg.Spec.RequestedVersion)
 
 	cn := sg.Spec.PGSQL.DatabaseConnection
 	scrt.Data = map[string][]byte{
-		"host":     []byte(cn.Host),
+		"host":     []byte(sg.Spec.ServiceHost(cn.Host, "pgsql")),
 		"port":     []byte(cn.Port),
 		"user":     []byte(cn.User),
 		"password": []byte(cn.Password),
@@ -236,17 +200,15 @@ func (r *Reconciler) reconcilePGSQLSecret(ctx context.Context, sg *config.Source
 }
 
 func (r *Reconciler) reconcilePGSQLService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	svc := service.NewService("pgsql", sg.Namespace, sg.Spec.PGSQL)
+	svc := service.NewService(sg.Spec.ObjectName("pgsql"), sg.Namespace, sg.Spec.PGSQL)
 	svc.Spec.Ports = []corev1.ServicePort{
 		{Name: "pgsql", TargetPort: intstr.FromString("pgsql"), Port: 5432},
 	}
-	svc.Spec.Selector = map[string]string{"app": "pgsql"}
+	svc.Spec.Selector = map[string]string{"app": sg.Spec.ObjectName("pgsql")}
 
 	return reconcileObject(ctx, r, sg.Spec.PGSQL, &svc, &corev1.Service{}, sg, owner)
 }
 
 func (r *Reconciler) reconcilePGSQLServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.PGSQL
-	sa := serviceaccount.NewServiceAccount("pgsql", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, sg.Spec.PGSQL, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("pgsql"), sg.Spec.PGSQL)
 }
@@ -5,6 +5,9 @@ func (suite *ApplianceTestSuite) TestDeployPGSQL() {
 		name string
 	}{
 		{name: "pgsql/default"},
+		{name: "pgsql/with-backups"},
+		{name: "pgsql/with-custom-exporter"},
+		{name: "pgsql/with-exporter-disabled"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -12,3 +15,9 @@ func (suite *ApplianceTestSuite) TestDeployPGSQL() {
 		})
 	}
 }
+
+func (suite *ApplianceTestSuite) TestPGSQLExporterDeletedWhenDisabled() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("pgsql/default")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "pgsql/with-exporter-disabled")
+	suite.makeGoldenAssertions(namespace, "pgsql/subsequent-exporter-disable")
+}
@@ -0,0 +1,54 @@
+package reconciler
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pdb"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// reconcilePodDisruptionBudget manages the PodDisruptionBudget for the pods of
+// the Deployment or StatefulSet called name, which runs the given number of
+// replicas.
+func (r *Reconciler) reconcilePodDisruptionBudget(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name string, cfg config.StandardComponent, replicas int32) error {
+	pdbCfg := podDisruptionBudgetConfig{StandardComponent: cfg, replicas: replicas}
+	budget := pdb.NewPodDisruptionBudget(name, sg.Namespace)
+	budget.Spec.MaxUnavailable = pointers.Ptr(intstr.FromInt32(1))
+	if budgetCfg := cfg.GetDisruptionBudget(); budgetCfg != nil && !pdbCfg.IsDisabled() {
+		if err := budgetCfg.Validate(); err != nil {
+			return err
+		}
+		if budgetCfg.MinAvailable != nil {
+			budget.Spec.MinAvailable = budgetCfg.MinAvailable
+			budget.Spec.MaxUnavailable = nil
+		} else if budgetCfg.MaxUnavailable != nil {
+			budget.Spec.MaxUnavailable = budgetCfg.MaxUnavailable
+		}
+	}
+
+	return reconcileObject(ctx, r, pdbCfg, &budget, &policyv1.PodDisruptionBudget{}, sg, owner)
+}
+
+// podDisruptionBudgetConfig wraps a service's config for its
+// PodDisruptionBudget. By default, one only exists while the service runs more
+// than one replica, since a budget for a single pod would block node drains
+// entirely.
+type podDisruptionBudgetConfig struct {
+	config.StandardComponent
+	replicas int32
+}
+
+func (c podDisruptionBudgetConfig) IsDisabled() bool {
+	if c.StandardComponent.IsDisabled() {
+		return true
+	}
+	if budgetCfg := c.GetDisruptionBudget(); budgetCfg != nil {
+		return budgetCfg.Disabled
+	}
+	return c.replicas <= 1
+}
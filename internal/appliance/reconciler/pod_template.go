@@ -0,0 +1,169 @@
+package reconciler
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// applyGlobalPodConfig applies settings that depend on the wider Sourcegraph
+// spec rather than only on the service's own config, or that override what
+// the reconciler set up, and so can't be set by pod.NewPodTemplate. Call it
+// once a pod template's containers, volumes and ServiceAccount are in place.
+func (r *Reconciler) applyGlobalPodConfig(template *corev1.PodTemplateSpec, sg *config.Sourcegraph, cfg config.StandardComponent, owner client.Object) error {
+	template.Spec.ImagePullSecrets = sg.Spec.ImagePullSecretsFor(cfg)
+	if name := cfg.GetServiceAccountConfig().Name; name != "" {
+		template.Spec.ServiceAccountName = name
+	}
+	template.Spec.PriorityClassName = sg.Spec.PriorityClassNameFor(cfg, sg.Namespace)
+	applyNamePrefix(template, sg)
+	if err := r.applyEnv(template, cfg, owner); err != nil {
+		return err
+	}
+	applySecurityContexts(template, cfg)
+	applyProbes(template, cfg)
+	applyTLS(template, sg, cfg)
+	applyProxy(template, sg, cfg)
+
+	// Sidecars are added last, so that they are left exactly as configured.
+	return applySidecars(template, cfg)
+}
+
+// applyEnv adds the service's configured env vars to the main container, which
+// is the first one. Env vars that are already set take precedence, and a
+// warning event is recorded for each configured one that is ignored.
+func (r *Reconciler) applyEnv(template *corev1.PodTemplateSpec, cfg config.StandardComponent, owner client.Object) error {
+	if cfg.IsDisabled() {
+		return nil
+	}
+	for _, ref := range cfg.GetEnvFrom() {
+		if err := ref.Validate(); err != nil {
+			return errors.Wrap(err, "validating envFrom")
+		}
+	}
+	if len(template.Spec.Containers) == 0 {
+		return nil
+	}
+
+	ctr := &template.Spec.Containers[0]
+	existing := make(map[string]bool, len(ctr.Env))
+	for _, env := range ctr.Env {
+		existing[env.Name] = true
+	}
+	configured := make(map[string]bool)
+	for _, env := range container.EnvVarsFromConfig(cfg) {
+		if configured[env.Name] {
+			return errors.Newf("env var %s is configured more than once in env and envFrom", env.Name)
+		}
+		configured[env.Name] = true
+
+		if existing[env.Name] {
+			r.Recorder.Eventf(owner, corev1.EventTypeWarning, "EnvVarIgnored",
+				"Env var %s is managed by the appliance on container %s, ignoring the configured value.", env.Name, ctr.Name)
+			continue
+		}
+		ctr.Env = append(ctr.Env, env)
+	}
+	return nil
+}
+
+// applyProbes applies the service's probe overrides to the main container,
+// which is the first one. A startup probe override adds a startup probe with
+// the handler of the liveness probe if the container has none, and is ignored
+// if it has neither.
+func applyProbes(template *corev1.PodTemplateSpec, cfg config.StandardComponent) {
+	if len(template.Spec.Containers) == 0 {
+		return
+	}
+	ctr := &template.Spec.Containers[0]
+	overrideProbe(ctr.LivenessProbe, cfg.GetLivenessProbe())
+	overrideProbe(ctr.ReadinessProbe, cfg.GetReadinessProbe())
+	if startup := cfg.GetStartupProbe(); startup != nil {
+		if ctr.StartupProbe == nil && ctr.LivenessProbe != nil {
+			ctr.StartupProbe = &corev1.Probe{ProbeHandler: *ctr.LivenessProbe.ProbeHandler.DeepCopy()}
+		}
+		overrideProbe(ctr.StartupProbe, startup)
+	}
+}
+
+// overrideProbe sets the fields of probe that override sets, if the probe
+// exists.
+func overrideProbe(probe *corev1.Probe, override *config.ProbeConfig) {
+	if probe == nil || override == nil {
+		return
+	}
+	if override.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *override.InitialDelaySeconds
+	}
+	if override.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *override.TimeoutSeconds
+	}
+	if override.PeriodSeconds != nil {
+		probe.PeriodSeconds = *override.PeriodSeconds
+	}
+	if override.SuccessThreshold != nil {
+		probe.SuccessThreshold = *override.SuccessThreshold
+	}
+	if override.FailureThreshold != nil {
+		probe.FailureThreshold = *override.FailureThreshold
+	}
+}
+
+// applySecurityContexts replaces the security contexts that the reconciler and
+// pod.NewPodTemplate set up with the service's configured ones, if any.
+func applySecurityContexts(template *corev1.PodTemplateSpec, cfg config.StandardComponent) {
+	if sc := cfg.GetPodSecurityContext(); sc != nil {
+		template.Spec.SecurityContext = sc
+	}
+	if sc := cfg.GetContainerSecurityContext(); sc != nil {
+		for i := range template.Spec.InitContainers {
+			template.Spec.InitContainers[i].SecurityContext = sc.DeepCopy()
+		}
+		for i := range template.Spec.Containers {
+			template.Spec.Containers[i].SecurityContext = sc.DeepCopy()
+		}
+	}
+}
+
+// applySidecars adds the service's sidecars, extra init containers and extra
+// volumes to the pod template, and mounts the extra volumes into the main
+// container. Extra init containers run after the managed ones.
+func applySidecars(template *corev1.PodTemplateSpec, cfg config.StandardComponent) error {
+	if cfg.IsDisabled() || len(template.Spec.Containers) == 0 {
+		return nil
+	}
+
+	for _, vol := range cfg.GetExtraVolumes() {
+		if slices.ContainsFunc(template.Spec.Volumes, func(v corev1.Volume) bool { return v.Name == vol.Name }) {
+			return errors.Newf("extra volume %q has the same name as a volume managed by the appliance", vol.Name)
+		}
+		template.Spec.Volumes = append(template.Spec.Volumes, *vol.DeepCopy())
+	}
+
+	ctr := &template.Spec.Containers[0]
+	for _, mount := range cfg.GetExtraVolumeMounts() {
+		ctr.VolumeMounts = append(ctr.VolumeMounts, *mount.DeepCopy())
+	}
+
+	for _, initCtr := range cfg.GetExtraInitContainers() {
+		sameName := func(c corev1.Container) bool { return c.Name == initCtr.Name }
+		if slices.ContainsFunc(template.Spec.InitContainers, sameName) || slices.ContainsFunc(template.Spec.Containers, sameName) {
+			return errors.Newf("extra init container %q has the same name as a container managed by the appliance", initCtr.Name)
+		}
+		template.Spec.InitContainers = append(template.Spec.InitContainers, *initCtr.DeepCopy())
+	}
+
+	for _, sidecar := range cfg.GetSidecars() {
+		sameName := func(c corev1.Container) bool { return c.Name == sidecar.Name }
+		if slices.ContainsFunc(template.Spec.InitContainers, sameName) || slices.ContainsFunc(template.Spec.Containers, sameName) {
+			return errors.Newf("sidecar %q has the same name as a container managed by the appliance", sidecar.Name)
+		}
+		template.Spec.Containers = append(template.Spec.Containers, *sidecar.DeepCopy())
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package reconciler
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+)
+
+// customQueriesDir is where the ConfigMap of an exporter's custom queries is
+// mounted.
+const customQueriesDir = "/custom-queries"
+
+// applyPostgresExporter adds the pgsql-exporter sidecar to a database's pod
+// template, unless it is disabled. The exporter connects with the database's
+// connection details in databaseSecretName unless it has a DSN of its own, and
+// runs the queries bundled with it at queriesPath unless it has custom ones.
+// memory is its default memory request and limit.
+func applyPostgresExporter(template *corev1.PodTemplateSpec, sg *config.Sourcegraph, cfg config.StandardComponent, exporter *config.PostgresExporterConfig, databaseSecretName, queriesPath, memory string) error {
+	if exporter.IsDisabled() {
+		return nil
+	}
+
+	image, err := config.GetDefaultImage(sg, "pgsql-exporter")
+	if err != nil {
+		return err
+	}
+
+	ctr := container.NewContainer("pgsql-exporter", cfg, config.ContainerConfig{
+		Image: image,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	})
+
+	if exporter != nil && exporter.DSNSecretRef != nil {
+		ctr.Env = append(ctr.Env, container.NewEnvVarSecretKeyRef("DATA_SOURCE_NAME", exporter.DSNSecretRef.Name, exporter.DSNSecretRef.Key))
+	} else {
+		ctr.Env = append(ctr.Env, container.EnvVarsPostgresExporter(databaseSecretName)...)
+	}
+
+	if exporter != nil && exporter.CustomQueriesConfigMapRef != nil {
+		queriesPath = customQueriesDir + "/queries.yaml"
+		ctr.VolumeMounts = append(ctr.VolumeMounts, corev1.VolumeMount{Name: "exporter-queries", MountPath: customQueriesDir})
+		template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+			Name: "exporter-queries",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: exporter.CustomQueriesConfigMapRef.LocalObjectReference,
+					Items:                []corev1.KeyToPath{{Key: exporter.CustomQueriesConfigMapRef.Key, Path: "queries.yaml"}},
+				},
+			},
+		})
+	}
+	ctr.Env = append(ctr.Env,
+		corev1.EnvVar{Name: "PG_EXPORTER_EXTEND_QUERY_PATH", Value: queriesPath},
+		corev1.EnvVar{Name: "PG_EXPORTER_WEB_LISTEN_ADDRESS", Value: fmt.Sprintf(":%d", *exporter.GetPrometheusPort())},
+	)
+
+	template.Spec.Containers = append(template.Spec.Containers, ctr)
+	return nil
+}
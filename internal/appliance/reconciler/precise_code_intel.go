@@ -7,6 +7,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -14,16 +15,21 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
-	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 func (r *Reconciler) reconcilePreciseCodeIntel(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	if err := r.reconcilePreciseCodeIntelDeployment(ctx, sg, owner); err != nil {
 		return errors.Wrap(err, "reconciling Deployment")
 	}
+	if err := r.reconcileHorizontalPodAutoscaler(ctx, sg, owner, sg.Spec.ObjectName("precise-code-intel-worker"), "Deployment", sg.Spec.PreciseCodeIntel, sg.Spec.PreciseCodeIntel.Autoscaling); err != nil {
+		return errors.Wrap(err, "reconciling HorizontalPodAutoscaler")
+	}
+	if err := r.reconcilePodDisruptionBudget(ctx, sg, owner, sg.Spec.ObjectName("precise-code-intel-worker"), sg.Spec.PreciseCodeIntel, sg.Spec.PreciseCodeIntel.Autoscaling.MaxReplicasOr(sg.Spec.PreciseCodeIntel.Replicas)); err != nil {
+		return errors.Wrap(err, "reconciling PodDisruptionBudget")
+	}
 	if err := r.reconcilePreciseCodeIntelService(ctx, sg, owner); err != nil {
 		return errors.Wrap(err, "reconciling Service")
 	}
@@ -65,10 +71,21 @@ func (r *Reconciler) reconcilePreciseCodeIntelDeployment(ctx context.Context, sg
 
 		container.NewEnvVarFieldRef("POD_NAME", "metadata.name"),
 	)
+	if cfg.UploadPollInterval != "" {
+		ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "PRECISE_CODE_INTEL_WORKER_POLL_INTERVAL", Value: cfg.UploadPollInterval})
+	}
+	if cfg.MaxUploadSize != "" {
+		maxUploadSize, err := resource.ParseQuantity(cfg.MaxUploadSize)
+		if err != nil {
+			return errors.Wrap(err, "parsing max upload size")
+		}
+		ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "PRECISE_CODE_INTEL_WORKER_BUDGET", Value: fmt.Sprintf("%d", maxUploadSize.Value())})
+	}
 
 	ctr.Env = addPreciseCodeIntelBlobstoreVars(ctr.Env, sg)
 
-	ctr.Env = append(ctr.Env, container.EnvVarsOtel()...)
+	ctr.Env = append(ctr.Env, otelEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, serviceEndpointEnvVars(sg)...)
 
 	ctr.Ports = []corev1.ContainerPort{
 		{Name: "http", ContainerPort: 3188},
@@ -98,54 +115,138 @@ func (r *Reconciler) reconcilePreciseCodeIntelDeployment(ctx context.Context, sg
 		{Name: "tmpdir", MountPath: "/tmp"},
 	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
 	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
-	podTemplate.Template.Spec.Volumes = []corev1.Volume{
-		pod.NewVolumeEmptyDir("tmpdir"),
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
+	tmpdir, err := preciseCodeIntelTempDir(sg.Namespace, cfg.TempDir)
+	if err != nil {
+		return err
+	}
+	podTemplate.Template.Spec.Volumes = []corev1.Volume{tmpdir}
+
+	if err := applyMigrationGate(&podTemplate.Template, sg, cfg, gatePgsql, gateCodeIntel); err != nil {
+		return err
+	}
+	applyTopologySpread(&podTemplate.Template, cfg, cfg.Autoscaling.MaxReplicasOr(cfg.Replicas))
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
 	}
 
-	dep := deployment.NewDeployment(name, sg.Namespace, sg.Spec.RequestedVersion)
-	dep.Spec.Replicas = pointers.Ptr(cfg.Replicas)
-	dep.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{
-		MaxSurge:       pointers.Ptr(intstr.FromInt(1)),
-		MaxUnavailable: pointers.Ptr(intstr.FromInt(1)),
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	dep.Spec.Replicas, err = r.replicasFor(ctx, &dep, &appsv1.Deployment{}, cfg.Replicas, cfg.Autoscaling)
+	if err != nil {
+		return err
+	}
+	if err := applyDeploymentStrategy(&dep, cfg); err != nil {
+		return err
 	}
 	dep.Spec.Template = podTemplate.Template
 
 	return reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner)
 }
 
+// preciseCodeIntelTempDir returns the volume that the worker processes uploads
+// in: an emptyDir, optionally limited in size, or a generic ephemeral volume.
+func preciseCodeIntelTempDir(namespace string, cfg *config.TempDirConfig) (corev1.Volume, error) {
+	vol := pod.NewVolumeEmptyDir("tmpdir")
+	if cfg == nil {
+		return vol, nil
+	}
+	if pv := cfg.PersistentVolume; pv != nil {
+		claim, err := pvc.NewPersistentVolumeClaim("tmpdir", namespace, config.StandardConfig{PersistentVolumeConfig: *pv})
+		if err != nil {
+			return corev1.Volume{}, err
+		}
+		vol.VolumeSource = corev1.VolumeSource{
+			Ephemeral: &corev1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+					ObjectMeta: metav1.ObjectMeta{Labels: claim.Labels},
+					Spec:       claim.Spec,
+				},
+			},
+		}
+		return vol, nil
+	}
+	if cfg.SizeLimit != "" {
+		sizeLimit, err := resource.ParseQuantity(cfg.SizeLimit)
+		if err != nil {
+			return corev1.Volume{}, errors.Wrap(err, "parsing temp dir size limit")
+		}
+		vol.EmptyDir.SizeLimit = &sizeLimit
+	}
+	return vol, nil
+}
+
 func (r *Reconciler) reconcilePreciseCodeIntelService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	name := "precise-code-intel-worker"
 	cfg := sg.Spec.PreciseCodeIntel
 
-	svc := service.NewService(name, sg.Namespace, cfg)
+	svc := service.NewService(sg.Spec.ObjectName(name), sg.Namespace, cfg)
 	svc.Spec.Ports = []corev1.ServicePort{
 		{Name: "http", Port: 3188, TargetPort: intstr.FromString("http")},
 		{Name: "debug", Port: 6060, TargetPort: intstr.FromString("debug")},
 	}
 	svc.Spec.Selector = map[string]string{
-		"app": "precise-code-intel-worker",
+		"app": sg.Spec.ObjectName("precise-code-intel-worker"),
 	}
 
 	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
 }
 
 func (r *Reconciler) reconcilePreciseCodeIntelServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.PreciseCodeIntel
-	sa := serviceaccount.NewServiceAccount("precise-code-intel-worker", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, cfg, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("precise-code-intel-worker"), sg.Spec.PreciseCodeIntel)
 }
 
 func addPreciseCodeIntelBlobstoreVars(env []corev1.EnvVar, sg *config.Sourcegraph) []corev1.EnvVar {
+	if ext := sg.Spec.Blobstore.ExternalStorage; ext != nil {
+		return append(env, preciseCodeIntelExternalStorageVars(ext)...)
+	}
+
 	// Only set these when the internal blobstore is enabled. Otherwise, callers
 	// can supply env vars for external blobstores via ContainerConfig.
 	if !sg.Spec.Blobstore.Disabled {
 		env = append(
 			env,
 			corev1.EnvVar{Name: "PRECISE_CODE_INTEL_UPLOAD_BACKEND", Value: "blobstore"},
-			corev1.EnvVar{Name: "PRECISE_CODE_INTEL_UPLOAD_AWS_ENDPOINT", Value: "http://blobstore:9000"},
+			corev1.EnvVar{Name: "PRECISE_CODE_INTEL_UPLOAD_AWS_ENDPOINT", Value: serviceScheme(sg, sg.Spec.Blobstore) + "://" + sg.Spec.ObjectName("blobstore") + ":9000"},
 		)
 	}
 	return env
 }
+
+func preciseCodeIntelExternalStorageVars(ext *config.ExternalStorageSpec) []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "PRECISE_CODE_INTEL_UPLOAD_BACKEND", Value: string(ext.Backend)},
+		{Name: "PRECISE_CODE_INTEL_UPLOAD_BUCKET", Value: ext.Bucket},
+	}
+
+	switch ext.Backend {
+	case config.ExternalStorageBackendS3:
+		if ext.Region != "" {
+			env = append(env, corev1.EnvVar{Name: "PRECISE_CODE_INTEL_UPLOAD_AWS_REGION", Value: ext.Region})
+		}
+		if ext.Endpoint != "" {
+			env = append(env, corev1.EnvVar{Name: "PRECISE_CODE_INTEL_UPLOAD_AWS_ENDPOINT", Value: ext.Endpoint})
+		}
+		if ext.CredentialsSecret != "" {
+			env = append(
+				env,
+				container.NewEnvVarSecretKeyRef("PRECISE_CODE_INTEL_UPLOAD_AWS_ACCESS_KEY_ID", ext.CredentialsSecret, "accessKeyID"),
+				container.NewEnvVarSecretKeyRef("PRECISE_CODE_INTEL_UPLOAD_AWS_SECRET_ACCESS_KEY", ext.CredentialsSecret, "secretAccessKey"),
+			)
+		} else {
+			// Without static credentials, fall back on the credentials of the
+			// pod's ServiceAccount, e.g. via IRSA.
+			env = append(env, corev1.EnvVar{Name: "PRECISE_CODE_INTEL_UPLOAD_AWS_USE_EC2_ROLE_CREDENTIALS", Value: "true"})
+		}
+	case config.ExternalStorageBackendGCS:
+		if ext.ProjectID != "" {
+			env = append(env, corev1.EnvVar{Name: "PRECISE_CODE_INTEL_UPLOAD_GCP_PROJECT_ID", Value: ext.ProjectID})
+		}
+		if ext.CredentialsSecret != "" {
+			env = append(env, container.NewEnvVarSecretKeyRef("PRECISE_CODE_INTEL_UPLOAD_GOOGLE_APPLICATION_CREDENTIALS_FILE_CONTENT", ext.CredentialsSecret, "credentials.json"))
+		}
+	}
+
+	return env
+}
@@ -8,6 +8,9 @@ func (suite *ApplianceTestSuite) TestDeployPreciseCodeIntel() {
 		{name: "precise-code-intel/with-blobstore"},
 		{name: "precise-code-intel/with-num-workers"},
 		{name: "precise-code-intel/with-replicas"},
+		{name: "precise-code-intel/with-service-account-annotations"},
+		{name: "precise-code-intel/with-temp-volume"},
+		{name: "precise-code-intel/with-worker-tuning"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -0,0 +1,44 @@
+package reconciler
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/priorityclass"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// reconcilePriorityClasses manages the PriorityClasses that services use when
+// ManagePriorityClasses is set, and deletes them when it isn't.
+func (r *Reconciler) reconcilePriorityClasses(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := priorityClassConfig{Managed: sg.Spec.ManagePriorityClasses}
+
+	// Critical pods may preempt other pods to be scheduled, e.g. after a node
+	// failure. Standard pods are only scheduled ahead of lower-priority pods,
+	// since a Sourcegraph deployment shouldn't evict other workloads just to
+	// scale out.
+	critical := priorityclass.NewPriorityClass(config.ManagedPriorityClassName(sg.Namespace, sg.Spec.ObjectName(config.PriorityClassCritical)), sg.Namespace, 1000000)
+	critical.Description = "Sourcegraph services that store data, such as gitserver and the databases."
+	if err := reconcileObject(ctx, r, cfg, &critical, &schedulingv1.PriorityClass{}, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling critical PriorityClass")
+	}
+
+	standard := priorityclass.NewPriorityClass(config.ManagedPriorityClassName(sg.Namespace, sg.Spec.ObjectName(config.PriorityClassStandard)), sg.Namespace, 100000)
+	standard.Description = "Sourcegraph services that don't store data."
+	standard.PreemptionPolicy = pointers.Ptr(corev1.PreemptNever)
+	if err := reconcileObject(ctx, r, cfg, &standard, &schedulingv1.PriorityClass{}, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling standard PriorityClass")
+	}
+	return nil
+}
+
+type priorityClassConfig struct {
+	Managed bool
+}
+
+func (c priorityClassConfig) IsDisabled() bool { return !c.Managed }
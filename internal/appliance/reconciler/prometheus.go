@@ -3,7 +3,11 @@ package reconciler
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"text/template"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -22,7 +26,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/role"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/rolebinding"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -46,7 +49,7 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, sg *config.Sourceg
 		return errors.Wrap(err, "reconciling PVC")
 	}
 
-	if sg.Spec.Prometheus.Privileged {
+	if bundledPrometheus(sg).Privileged {
 		if err := r.reconcilePrometheusClusterRoleBinding(ctx, sg, owner); err != nil {
 			return errors.Wrap(err, "reconciling ClusterRoleBinding")
 		}
@@ -60,7 +63,7 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, sg *config.Sourceg
 
 func (r *Reconciler) reconcilePrometheusDeployment(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	name := "prometheus"
-	cfg := sg.Spec.Prometheus
+	cfg := bundledPrometheus(sg)
 
 	defaultImage, err := config.GetDefaultImage(sg, name)
 	if err != nil {
@@ -97,23 +100,51 @@ func (r *Reconciler) reconcilePrometheusDeployment(ctx context.Context, sg *conf
 		{Name: "data", MountPath: "/prometheus"},
 		{Name: "config", MountPath: "/sg_prometheus_add_ons"},
 	}
+	flags, err := prometheusRetentionFlags(cfg.PrometheusSpec)
+	if err != nil {
+		return err
+	}
+	if len(flags) > 0 {
+		ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "PROMETHEUS_ADDITIONAL_FLAGS", Value: strings.Join(flags, " ")})
+	}
+	if !cfg.BundledAlertmanager() {
+		ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "DISABLE_ALERTMANAGER", Value: "true"})
+	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
-	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
 
-	cfgMapName := name
+	cfgMapName := sg.Spec.ObjectName(name)
 	if cfg.ExistingConfigMap != "" {
 		cfgMapName = cfg.ExistingConfigMap
 	}
 	podTemplate.Template.Spec.Volumes = []corev1.Volume{
-		pod.NewVolumeFromPVC("data", name),
+		pod.NewVolumeFromPVC("data", sg.Spec.ObjectName(name)),
 		pod.NewVolumeFromConfigMap("config", cfgMapName),
 	}
-	podTemplate.Template.Spec.ServiceAccountName = name
+	for _, rw := range cfg.RemoteWrite {
+		if rw.SecretName() == "" {
+			continue
+		}
+		volName := "remote-write-" + rw.Name
+		podTemplate.Template.Spec.Volumes = append(podTemplate.Template.Spec.Volumes, pod.NewVolumeFromSecret(volName, rw.SecretName()))
+		ctr.VolumeMounts = append(ctr.VolumeMounts, corev1.VolumeMount{Name: volName, MountPath: rw.SecretPath(), ReadOnly: true})
+	}
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+
+	// Prometheus only reads its config on startup, so roll its pod when its
+	// external labels, remote-write endpoints, or alerting change.
+	if checksum, ok := prometheusConfigChecksum(cfg.PrometheusSpec); ok {
+		podTemplate.Template.Annotations[config.AnnotationKeyConfigChecksum] = checksum
+	}
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
 
-	dep := deployment.NewDeployment(name, sg.Namespace, sg.Spec.RequestedVersion)
-	dep.Spec.Strategy = appsv1.DeploymentStrategy{
-		Type: appsv1.RecreateDeploymentStrategyType,
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	if err := applyDeploymentStrategy(&dep, cfg); err != nil {
+		return err
 	}
 	dep.Spec.Template = podTemplate.Template
 
@@ -121,28 +152,26 @@ func (r *Reconciler) reconcilePrometheusDeployment(ctx context.Context, sg *conf
 }
 
 func (r *Reconciler) reconcilePrometheusService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	name := "prometheus"
-	cfg := sg.Spec.Prometheus
+	name := sg.Spec.ObjectName("prometheus")
+	cfg := bundledPrometheus(sg)
 
 	svc := service.NewService(name, sg.Namespace, cfg)
 	svc.Spec.Ports = []corev1.ServicePort{
 		{Name: "http", Port: 30090, TargetPort: intstr.FromString("http")},
 	}
 	svc.Spec.Selector = map[string]string{
-		"app": "syntect-server",
+		"app": sg.Spec.ObjectName("syntect-server"),
 	}
 
 	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
 }
 
 func (r *Reconciler) reconcilePrometheusServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.Prometheus
-	sa := serviceaccount.NewServiceAccount("prometheus", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, cfg, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("prometheus"), bundledPrometheus(sg))
 }
 
 func (r *Reconciler) reconcilePrometheusConfigMap(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.Prometheus
+	cfg := bundledPrometheus(sg)
 	if cfg.ExistingConfigMap != "" {
 		return nil
 	}
@@ -156,19 +185,27 @@ func (r *Reconciler) reconcilePrometheusConfigMap(ctx context.Context, sg *confi
 		return errors.Wrap(err, "rendering default prometheus config template")
 	}
 
-	name := "prometheus"
-	cm := configmap.NewConfigMap(name, sg.Namespace)
+	cm := configmap.NewConfigMap(sg.Spec.ObjectName("prometheus"), sg.Namespace)
 	cm.Data = map[string]string{
 		"prometheus.yml":  defaultConfig.String(),
 		"extra_rules.yml": "",
 	}
+	// The rules of alerts whose thresholds are overridden are picked up from
+	// the ConfigMap like extra_rules.yml.
+	alertRules, err := cfg.Alerting.AlertRules()
+	if err != nil {
+		return errors.Wrap(err, "rendering alert rules")
+	}
+	if alertRules != nil {
+		cm.Data["appliance_alert_rules.yml"] = string(alertRules)
+	}
 
 	return reconcileObject(ctx, r, cfg, &cm, &corev1.ConfigMap{}, sg, owner)
 }
 
 func (r *Reconciler) reconcilePrometheusRole(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	name := "prometheus"
-	cfg := sg.Spec.Prometheus
+	name := sg.Spec.ObjectName("prometheus")
+	cfg := bundledPrometheus(sg)
 
 	resources := []string{
 		"endpoints",
@@ -204,7 +241,7 @@ func (r *Reconciler) reconcilePrometheusRole(ctx context.Context, sg *config.Sou
 
 		// Make resource name sg-specific since this is a non-namespaced
 		// (cluster-scoped) object
-		name := fmt.Sprintf("%s-%s", sg.Namespace, "prometheus")
+		name := fmt.Sprintf("%s-%s", sg.Namespace, name)
 		role := role.NewClusterRole(name, sg.Namespace)
 		role.Rules = rules
 		return reconcileObject(ctx, r, cfg, &role, &rbacv1.ClusterRole{}, sg, owner)
@@ -216,7 +253,7 @@ func (r *Reconciler) reconcilePrometheusRole(ctx context.Context, sg *config.Sou
 }
 
 func (r *Reconciler) reconcilePrometheusRoleBinding(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	name := "prometheus"
+	name := sg.Spec.ObjectName("prometheus")
 	binding := rolebinding.NewRoleBinding(name, sg.Namespace)
 	binding.RoleRef = rbacv1.RoleRef{
 		Kind: "Role",
@@ -225,17 +262,17 @@ func (r *Reconciler) reconcilePrometheusRoleBinding(ctx context.Context, sg *con
 	binding.Subjects = []rbacv1.Subject{
 		{
 			Kind:      "ServiceAccount",
-			Name:      "prometheus",
+			Name:      name,
 			Namespace: sg.Namespace,
 		},
 	}
-	return reconcileObject(ctx, r, sg.Spec.Prometheus, &binding, &rbacv1.RoleBinding{}, sg, owner)
+	return reconcileObject(ctx, r, bundledPrometheus(sg), &binding, &rbacv1.RoleBinding{}, sg, owner)
 }
 
 func (r *Reconciler) reconcilePrometheusClusterRoleBinding(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	// Make resource name sg-specific since this is a non-namespaced
 	// (cluster-scoped) object
-	name := fmt.Sprintf("%s-%s", sg.Namespace, "prometheus")
+	name := fmt.Sprintf("%s-%s", sg.Namespace, sg.Spec.ObjectName("prometheus"))
 	binding := rolebinding.NewClusterRoleBinding(name, sg.Namespace)
 	binding.RoleRef = rbacv1.RoleRef{
 		Kind: "ClusterRole",
@@ -244,19 +281,73 @@ func (r *Reconciler) reconcilePrometheusClusterRoleBinding(ctx context.Context,
 	binding.Subjects = []rbacv1.Subject{
 		{
 			Kind:      "ServiceAccount",
-			Name:      "prometheus",
+			Name:      sg.Spec.ObjectName("prometheus"),
 			Namespace: sg.Namespace,
 		},
 	}
-	return reconcileObject(ctx, r, sg.Spec.Prometheus, &binding, &rbacv1.ClusterRoleBinding{}, sg, owner)
+	return reconcileObject(ctx, r, bundledPrometheus(sg), &binding, &rbacv1.ClusterRoleBinding{}, sg, owner)
 }
 
 func (r *Reconciler) reconcilePrometheusPVC(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	name := "prometheus"
-	cfg := sg.Spec.Prometheus
+	name := sg.Spec.ObjectName("prometheus")
+	cfg := bundledPrometheus(sg)
 	pvc, err := pvc.NewPersistentVolumeClaim(name, sg.Namespace, cfg)
 	if err != nil {
 		return err
 	}
 	return reconcileObject(ctx, r, cfg, &pvc, &corev1.PersistentVolumeClaim{}, sg, owner)
 }
+
+// bundledPrometheusConfig wraps a PrometheusSpec for the resources that make up
+// the bundled Prometheus, which must not exist when an external Prometheus
+// Operator scrapes Sourcegraph instead.
+type bundledPrometheusConfig struct {
+	config.PrometheusSpec
+	mode config.MonitoringMode
+}
+
+func bundledPrometheus(sg *config.Sourcegraph) bundledPrometheusConfig {
+	return bundledPrometheusConfig{PrometheusSpec: sg.Spec.Prometheus, mode: sg.Spec.Monitoring.GetMode()}
+}
+
+func (c bundledPrometheusConfig) IsDisabled() bool {
+	return c.Disabled || c.mode != config.MonitoringModeBundled
+}
+
+// prometheusRetentionFlags returns the Prometheus flags for the configured
+// retention settings.
+func prometheusRetentionFlags(cfg config.PrometheusSpec) ([]string, error) {
+	var flags []string
+	if cfg.RetentionTime != "" {
+		flags = append(flags, "--storage.tsdb.retention.time="+cfg.RetentionTime)
+	}
+	if cfg.RetentionSize != "" {
+		size, err := resource.ParseQuantity(cfg.RetentionSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing retention size")
+		}
+		flags = append(flags, fmt.Sprintf("--storage.tsdb.retention.size=%dB", size.Value()))
+	}
+	return flags, nil
+}
+
+// prometheusConfigChecksum returns a checksum of the external labels,
+// remote-write endpoints, and alerting settings that are rendered into the
+// generated Prometheus config, if any are set. It isn't a checksum of the
+// whole config, which depends on the namespace, so that it doesn't differ
+// between namespaces.
+func prometheusConfigChecksum(cfg config.PrometheusSpec) (string, bool) {
+	if cfg.ExistingConfigMap != "" || (len(cfg.ExternalLabels) == 0 && len(cfg.RemoteWrite) == 0 && cfg.Alerting == nil) {
+		return "", false
+	}
+	settings, err := json.Marshal(struct {
+		ExternalLabels map[string]string
+		RemoteWrite    []config.PrometheusRemoteWriteSpec
+		Alerting       *config.PrometheusAlertingSpec `json:",omitempty"`
+	}{cfg.ExternalLabels, cfg.RemoteWrite, cfg.Alerting})
+	if err != nil {
+		return "", false
+	}
+	checksum := sha256.Sum256(settings)
+	return hex.EncodeToString(checksum[:]), true
+}
@@ -1,5 +1,16 @@
 package reconciler
 
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
 func (suite *ApplianceTestSuite) TestDeployPrometheus() {
 	for _, tc := range []struct {
 		name string
@@ -7,6 +18,8 @@ func (suite *ApplianceTestSuite) TestDeployPrometheus() {
 		{name: "prometheus/default"},
 		{name: "prometheus/privileged"},
 		{name: "prometheus/with-existing-configmap"},
+		{name: "prometheus/with-service-monitors"},
+		{name: "prometheus/with-remote-write"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -20,3 +33,65 @@ func (suite *ApplianceTestSuite) TestNonNamespacedResourcesRemainWhenDisabled()
 	suite.updateConfigMapAndAwaitReconciliation(namespace, "standard/everything-disabled")
 	suite.makeGoldenAssertions(namespace, "prometheus/subsequent-disable")
 }
+
+func (suite *ApplianceTestSuite) TestServiceMonitorsDeletedWhenBundled() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("prometheus/with-service-monitors")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "prometheus/bundled-with-syntect")
+	suite.makeGoldenAssertions(namespace, "prometheus/subsequent-bundled")
+}
+
+func (suite *ApplianceTestSuite) TestBundledPrometheusDeletedWhenServiceMonitors() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("prometheus/bundled-with-syntect")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "prometheus/with-service-monitors")
+	suite.makeGoldenAssertions(namespace, "prometheus/subsequent-service-monitors")
+}
+
+func TestPrometheusAlerting(t *testing.T) {
+	objs, err := Render(context.Background(), renderedSpec, []byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  prometheus:
+    alerting:
+      thresholds:
+        warning_gitserver_disk_space_remaining: 25
+      silenced:
+        - warning_frontend_mean_blocked_seconds_per_conn_request
+      alertmanagers:
+        - url: https://alertmanager.example.com/sourcegraph
+      disableBundledAlertmanager: true
+`))
+	require.NoError(t, err)
+
+	var cm corev1.ConfigMap
+	var dep appsv1.Deployment
+	for _, obj := range objs {
+		u := obj.(*unstructured.Unstructured)
+		switch {
+		case u.GetKind() == "ConfigMap" && u.GetName() == "prometheus":
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &cm))
+		case u.GetKind() == "Deployment" && u.GetName() == "prometheus":
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &dep))
+		}
+	}
+
+	// The overridden threshold replaces the shipped one.
+	require.Contains(t, cm.Data["appliance_alert_rules.yml"], "* 100) < 25)")
+	require.Contains(t, cm.Data["prometheus.yml"], `
+  alert_relabel_configs:
+    # Alerts whose thresholds are overridden by the appliance's rules
+    - source_labels: [alertname, alert_type]
+      regex: "(warning_gitserver_disk_space_remaining);builtin"
+      action: drop
+    # Silenced alerts
+    - source_labels: [alertname]
+      regex: "(warning_frontend_mean_blocked_seconds_per_conn_request)"
+      action: drop
+  alertmanagers:
+    - scheme: https
+      static_configs:
+        - targets: ["alertmanager.example.com"]
+      path_prefix: "/sourcegraph"
+`)
+	require.NotContains(t, cm.Data["prometheus.yml"], "127.0.0.1:9093")
+	require.Contains(t, dep.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "DISABLE_ALERTMANAGER", Value: "true"})
+}
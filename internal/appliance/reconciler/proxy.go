@@ -0,0 +1,77 @@
+package reconciler
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+// applianceServiceNames are the names of the Services that the appliance may
+// create. Pods reach them directly rather than through a proxy, even if
+// they're disabled, so that NO_PROXY doesn't change as services come and go.
+var applianceServiceNames = []string{
+	"blobstore",
+	"codeinsights-db",
+	"codeintel-db",
+	"gitserver",
+	"grafana",
+	"indexed-search",
+	"indexed-search-indexer",
+	"otel-collector",
+	"pgsql",
+	"precise-code-intel-worker",
+	"prometheus",
+	"redis-cache",
+	"redis-store",
+	"repo-updater",
+	"searcher",
+	"sourcegraph-frontend",
+	"sourcegraph-frontend-internal",
+	"symbols",
+	"syntect-server",
+	"worker",
+	"worker-executors",
+}
+
+// noProxy returns the value of NO_PROXY: loopback addresses, the suffixes of
+// fully qualified cluster-internal names, the short names of the appliance's
+// Services, which also match their subdomains, e.g. gitserver-0.gitserver,
+// and finally the spec's own entries.
+func noProxy(sg *config.Sourcegraph) string {
+	hosts := []string{"localhost", "127.0.0.1", "::1", ".svc", ".cluster.local"}
+	for _, name := range applianceServiceNames {
+		hosts = append(hosts, sg.Spec.ObjectName(name))
+	}
+	hosts = append(hosts, sg.Spec.NoProxy...)
+	return strings.Join(hosts, ",")
+}
+
+// applyProxy points every container in template at the spec's proxies, unless
+// the service opts out. Both the upper and lower case env vars are set, as
+// tools such as curl and git only read the lower case ones.
+func applyProxy(template *corev1.PodTemplateSpec, sg *config.Sourcegraph, cfg config.StandardComponent) {
+	if !sg.Spec.UsesProxy(cfg) {
+		return
+	}
+
+	var env []corev1.EnvVar
+	for _, v := range []struct{ name, value string }{
+		{"HTTP_PROXY", sg.Spec.HTTPProxy},
+		{"HTTPS_PROXY", sg.Spec.HTTPSProxy},
+		{"NO_PROXY", noProxy(sg)},
+	} {
+		if v.value == "" {
+			continue
+		}
+		env = append(env,
+			corev1.EnvVar{Name: v.name, Value: v.value},
+			corev1.EnvVar{Name: strings.ToLower(v.name), Value: v.value},
+		)
+	}
+	for i := range template.Spec.Containers {
+		ctr := &template.Spec.Containers[i]
+		ctr.Env = append(ctr.Env, env...)
+	}
+}
@@ -0,0 +1,127 @@
+package reconciler
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestProxy(t *testing.T) {
+	objs, err := Render(context.Background(), renderedSpec, []byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  httpProxy: http://proxy.example.com:3128
+  httpsProxy: http://proxy.example.com:3129
+  noProxy: [.corp.example.com]
+  trustedCACertsConfigMapRef:
+    name: corporate-ca
+  cadvisor:
+    disabled: false
+  grafana:
+    disabled: false
+  otelCollector:
+    disabled: false
+  gitServer:
+    disableProxy: true
+  pgsql:
+    backup: {}
+`))
+	require.NoError(t, err)
+
+	var services []string
+	templates := map[string]corev1.PodTemplateSpec{}
+	for _, obj := range objs {
+		u := obj.(*unstructured.Unstructured)
+		key := u.GetKind() + " " + u.GetName()
+		switch u.GetKind() {
+		case "Service":
+			services = append(services, u.GetName())
+		case "Deployment":
+			var dep appsv1.Deployment
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &dep))
+			templates[key] = dep.Spec.Template
+		case "StatefulSet":
+			var sset appsv1.StatefulSet
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &sset))
+			templates[key] = sset.Spec.Template
+		case "DaemonSet":
+			var ds appsv1.DaemonSet
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &ds))
+			templates[key] = ds.Spec.Template
+		case "CronJob":
+			var cj batchv1.CronJob
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &cj))
+			templates[key] = cj.Spec.JobTemplate.Spec.Template
+		}
+	}
+	require.NotEmpty(t, services)
+	require.Contains(t, templates, "CronJob pgsql-backup")
+
+	for key, template := range templates {
+		i := slices.IndexFunc(template.Spec.Volumes, func(v corev1.Volume) bool { return v.Name == "tls-ca" })
+		require.NotEqual(t, -1, i, "%s has no tls-ca volume", key)
+		require.Equal(t, []corev1.VolumeProjection{{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "corporate-ca"},
+			},
+		}}, template.Spec.Volumes[i].Projected.Sources, key)
+
+		for _, ctr := range template.Spec.Containers {
+			env := map[string]string{}
+			for _, e := range ctr.Env {
+				env[e.Name] = e.Value
+			}
+			require.Equal(t, tlsCertDirs, env["SSL_CERT_DIR"], "%s container %s", key, ctr.Name)
+
+			if key == "StatefulSet gitserver" {
+				require.NotContains(t, env, "HTTP_PROXY", "%s container %s", key, ctr.Name)
+				require.NotContains(t, env, "NO_PROXY", "%s container %s", key, ctr.Name)
+				continue
+			}
+			require.Equal(t, "http://proxy.example.com:3128", env["HTTP_PROXY"], "%s container %s", key, ctr.Name)
+			require.Equal(t, "http://proxy.example.com:3128", env["http_proxy"], "%s container %s", key, ctr.Name)
+			require.Equal(t, "http://proxy.example.com:3129", env["HTTPS_PROXY"], "%s container %s", key, ctr.Name)
+			require.Equal(t, env["NO_PROXY"], env["no_proxy"], "%s container %s", key, ctr.Name)
+
+			// Every Service that the appliance generates must be reached
+			// directly, by its short and fully qualified names alike.
+			noProxy := strings.Split(env["NO_PROXY"], ",")
+			for _, svc := range services {
+				require.Contains(t, noProxy, svc, "%s container %s", key, ctr.Name)
+			}
+			require.Contains(t, noProxy, ".svc", "%s container %s", key, ctr.Name)
+			require.Contains(t, noProxy, ".corp.example.com", "%s container %s", key, ctr.Name)
+		}
+	}
+}
+
+func TestNoProxyListsEveryGeneratedService(t *testing.T) {
+	// Enabling every service that can be disabled generates every Service.
+	objs, err := Render(context.Background(), renderedSpec, []byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  cadvisor:
+    disabled: false
+  grafana:
+    disabled: false
+  otelCollector:
+    disabled: false
+`))
+	require.NoError(t, err)
+
+	var services []string
+	for _, obj := range objs {
+		if obj.GetObjectKind().GroupVersionKind().Kind == "Service" {
+			services = append(services, obj.GetName())
+		}
+	}
+	require.ElementsMatch(t, applianceServiceNames, services)
+}
@@ -2,10 +2,18 @@ package reconciler
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -14,7 +22,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/yaml"
 
 	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -26,20 +33,74 @@ type Reconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// StrictSpecDecoding makes specs with unknown fields invalid, instead of
+	// only warning about them.
+	StrictSpecDecoding bool
+
+	// HTTPClient fetches metrics that services report in the status, such as
+	// the usage of the blobstore volume. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Tracker records the result of every reconcile for the health endpoints
+	// of the appliance, if set.
+	Tracker *ReconcileTracker
+
+	// ResyncInterval is how long after a reconcile that needs no follow-up a
+	// ConfigMap is reconciled again, if set. Besides reverting changes made by
+	// hand to the objects of the appliance, this tells the health endpoints
+	// that the reconcile loop is still running.
+	ResyncInterval time.Duration
+
+	// OperatorVersion is the version of the appliance itself, recorded in the
+	// status. OperatorDeployment is the Deployment that runs it, which it
+	// moves to its image for the requested version if the spec sets the
+	// Automatic OperatorUpdatePolicy. Without it, the appliance doesn't
+	// update itself.
+	OperatorVersion    string
+	OperatorDeployment types.NamespacedName
+}
+
+// errSpecNotFound is returned by reconcile if the appliance ConfigMap doesn't
+// exist, or is being deleted and nothing blocks it.
+var errSpecNotFound = errors.New("appliance ConfigMap not found")
+
+// Result is the outcome of a single reconcile of an appliance ConfigMap.
+type Result struct {
+	// Status is the status of Sourcegraph that the reconcile recorded on the
+	// ConfigMap.
+	Status config.SourcegraphStatus
+
+	// RequeueAfter is how long until the ConfigMap should be reconciled
+	// again, e.g. to check on a rollout, or zero if it needn't be.
+	RequeueAfter time.Duration
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	result, err := r.reconcile(ctx, req.NamespacedName)
+	if errors.Is(err, errSpecNotFound) {
+		// Object not found, maybe deleted.
+		r.Tracker.forget(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+	r.Tracker.finish(req.NamespacedName, err)
+	return ctrl.Result{RequeueAfter: result.RequeueAfter}, err
+}
+
+// reconcile makes a single pass over the appliance ConfigMap name. It is
+// shared by the controller and ReconcileSpec, so that Sourcegraph is deployed
+// the same way with or without a manager.
+func (r *Reconciler) reconcile(ctx context.Context, name types.NamespacedName) (Result, error) {
 	reqLog := log.FromContext(ctx)
 	reqLog.Info("reconciling sourcegraph appliance")
 
 	var applianceSpec corev1.ConfigMap
-	err := r.Get(ctx, req.NamespacedName, &applianceSpec)
+	err := r.Get(ctx, name, &applianceSpec)
 	if apierrors.IsNotFound(err) {
-		// Object not found, maybe deleted.
-		return ctrl.Result{}, nil
+		return Result{}, errSpecNotFound
 	} else if err != nil {
 		reqLog.Error(err, "failed to fetch sourcegraph appliance spec")
-		return ctrl.Result{}, err
+		return Result{}, err
 	}
 
 	// Emit a ReconcileFinished event at the end. Currently, this is only used
@@ -47,17 +108,37 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// assertions on the state of the cluster at the time this event is emitted.
 	// Perhaps this should be feature-flagged so that it is only emitted during
 	// tests, if it isn't useful elsewhere.
-	defer r.Recorder.Event(&applianceSpec, "Normal", "ReconcileFinished", "Reconcile finished.")
+	ctx, events := r.withEventRecorder(ctx, &applianceSpec)
+	defer events.eventf(EventReasonReconcileFinished, "Reconcile finished.")
+
+	// Deleting the ConfigMap deletes every object that it owns. Whether that
+	// includes the data of Sourcegraph is up to its data retention policy.
+	if !applianceSpec.DeletionTimestamp.IsZero() {
+		blocked, err := r.reconcileDeletion(ctx, &applianceSpec)
+		if err != nil {
+			return Result{}, err
+		}
+		if !blocked {
+			return Result{}, errSpecNotFound
+		}
+		return Result{RequeueAfter: r.ResyncInterval}, nil
+	}
 
 	// TODO place holder code until we get the configmap spec'd out and working'
 	data, ok := applianceSpec.Data["spec"]
 	if !ok {
-		return ctrl.Result{}, errors.New("failed to get sourcegraph spec from configmap")
+		return Result{}, errors.New("failed to get sourcegraph spec from configmap")
 	}
 
-	sourcegraph := config.NewDefaultConfig()
-	if err := yaml.Unmarshal([]byte(data), &sourcegraph); err != nil {
-		return reconcile.Result{}, err
+	// In strict mode, unknown fields make the spec invalid, which is reported
+	// below along with any other problems with it.
+	sourcegraph, fieldWarnings, decodeErr := config.DecodeConfigYAML([]byte(data), config.DecodeOptions{Strict: r.StrictSpecDecoding})
+	var unknownFieldsErr *config.UnknownFieldsError
+	if decodeErr != nil && !errors.As(decodeErr, &unknownFieldsErr) {
+		return Result{}, decodeErr
+	}
+	for _, w := range fieldWarnings {
+		events.eventf(EventReasonSpecFieldWarning, "%s", w)
 	}
 
 	// config.Sourcegraph is a kubebuilder-scaffolded custom type, but we do not
@@ -65,60 +146,276 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// based on the actual object being reconciled, so that more deeply-nested
 	// code can treat it like a CRD.
 	sourcegraph.Namespace = applianceSpec.GetNamespace()
+	// Setting the rotateNow annotation on the ConfigMap restarts every
+	// service, through the dependency checksums of their pod templates.
+	if rotateNow, ok := applianceSpec.GetAnnotations()[config.AnnotationKeyRotateNow]; ok {
+		metav1.SetMetaDataAnnotation(&sourcegraph.ObjectMeta, config.AnnotationKeyRotateNow, rotateNow)
+	}
 
-	// Similarly, we simulate a CRD status using an annotation. ConfigMaps don't
+	// Similarly, we simulate a CRD status using annotations. ConfigMaps don't
 	// have Statuses, so we must use annotations to drive this.
 	// This can be empty string.
 	sourcegraph.Status.CurrentVersion = applianceSpec.GetAnnotations()[config.AnnotationKeyCurrentVersion]
+	previousStatus := statusFromAnnotations(applianceSpec.GetAnnotations())
 
-	// Reconcile services here
-	if err := r.reconcileBlobstore(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile blobstore: %w", err)
-	}
-	if err := r.reconcileRepoUpdater(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile repo updater: %w", err)
+	// Reject an invalid spec, or an unsupported change of version from the
+	// last one that was reconciled successfully, before touching anything,
+	// rather than failing halfway through and leaving a partially-updated
+	// deployment. Retrying can't help, so the errors are surfaced in the
+	// status annotation, and the next edit of the spec triggers another
+	// reconcile.
+	if err := errors.Append(decodeErr, sourcegraph.Validate(), sourcegraph.ValidateUpgrade()); err != nil {
+		reqLog.Error(err, "invalid sourcegraph appliance spec")
+		events.eventf(EventReasonInvalidSpec, "%s", err)
+		applianceSpec.Annotations[config.AnnotationKeyValidationErrors] = err.Error()
+		status := previousStatus
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:    config.ConditionReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  config.ReasonInvalidSpec,
+			Message: err.Error(),
+		})
+		setSpecFieldsCondition(&status, fieldWarnings)
+		if err := setStatusAnnotations(&applianceSpec, status); err != nil {
+			return Result{}, err
+		}
+		r.Tracker.setStatus(name, status)
+		if err := r.Client.Update(ctx, &applianceSpec); err != nil {
+			return Result{}, errors.Newf("failed to update validation errors annotation: %w", err)
+		}
+		return Result{Status: status, RequeueAfter: r.ResyncInterval}, nil
 	}
-	if err := r.reconcileSymbols(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile symbols service: %w", err)
+	delete(applianceSpec.Annotations, config.AnnotationKeyValidationErrors)
+	for _, w := range sourcegraph.Warnings() {
+		events.eventf(EventReasonSpecWarning, "%s", w)
 	}
-	if err := r.reconcileGitServer(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile gitserver: %w", err)
+
+	if current, requested := sourcegraph.Status.CurrentVersion, sourcegraph.Spec.RequestedVersion; current != "" && current != requested && previousStatus.RequestedVersion != requested {
+		events.eventf(EventReasonVersionChangeDetected, "Version %s was requested, rolling it out over version %s.", requested, current)
 	}
-	if err := r.reconcileRedis(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile redis: %w", err)
+
+	// The spec is valid, so the image version resolves.
+	imageVersion, _ := config.ResolveImageVersion(&sourcegraph)
+	if imageVersion != sourcegraph.Spec.RequestedVersion {
+		events.eventf(EventReasonImageVersionFallback,
+			"No default images for version %s, using those of version %s.", sourcegraph.Spec.RequestedVersion, imageVersion)
+		applianceSpec.Annotations[config.AnnotationKeyImageVersion] = imageVersion
+	} else {
+		delete(applianceSpec.Annotations, config.AnnotationKeyImageVersion)
 	}
-	if err := r.reconcilePGSQL(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile pgsql: %w", err)
+
+	// With automatic updates, the appliance of the requested version rolls
+	// it out, so that both are upgraded in lockstep. Until it replaced this
+	// one, nothing else is reconciled.
+	operatorVersion, err := r.reconcileOperatorUpdate(ctx, &sourcegraph)
+	if err != nil {
+		return Result{}, errors.Newf("failed to update the appliance: %w", err)
 	}
-	if err := r.reconcileSyntect(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile syntect: %w", err)
+	if operatorVersion != "" {
+		status := previousStatus
+		status.RequestedVersion = sourcegraph.Spec.RequestedVersion
+		status.OperatorVersion = r.OperatorVersion
+		setOperatorUpdateCondition(&status, operatorVersion)
+		setSpecFieldsCondition(&status, fieldWarnings)
+		if err := setStatusAnnotations(&applianceSpec, status); err != nil {
+			return Result{}, err
+		}
+		r.Tracker.setStatus(name, status)
+		if err := r.Client.Update(ctx, &applianceSpec); err != nil {
+			return Result{}, errors.Newf("failed to update status annotation: %w", err)
+		}
+		return Result{Status: status, RequeueAfter: rolloutPollInterval}, nil
 	}
-	if err := r.reconcilePreciseCodeIntel(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile precise code intel: %w", err)
+
+	_, wasInMaintenance := applianceSpec.Annotations[config.AnnotationKeyMaintenanceMode]
+	if sourcegraph.Spec.MaintenanceMode.Enabled {
+		if !wasInMaintenance {
+			events.eventf(EventReasonMaintenanceMode, "Scaling Sourcegraph down for maintenance.")
+		}
+		applianceSpec.Annotations[config.AnnotationKeyMaintenanceMode] = "true"
+	} else if wasInMaintenance {
+		events.eventf(EventReasonMaintenanceMode, "Maintenance mode is over, scaling Sourcegraph back up.")
+		delete(applianceSpec.Annotations, config.AnnotationKeyMaintenanceMode)
 	}
-	if err := r.reconcileCodeInsights(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile code insights DB: %w", err)
+
+	// The data retention policy must apply before any data is created.
+	if err := r.reconcileDataRetentionFinalizer(ctx, &applianceSpec, sourcegraph.Spec.DataRetentionPolicy.OrDefault()); err != nil {
+		return Result{}, err
 	}
-	if err := r.reconcileCodeIntel(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile code intel DB: %w", err)
+
+	// PriorityClasses must exist before any pods that use them are created.
+	if err := r.reconcilePriorityClasses(ctx, &sourcegraph, &applianceSpec); err != nil {
+		return Result{}, errors.Newf("failed to reconcile priority classes: %w", err)
 	}
-	if err := r.reconcilePrometheus(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile prometheus: %w", err)
+
+	// Settle the number of gitserver replicas before any service is
+	// reconciled, since consumers of gitserver address its replicas.
+	requestedGitServers := sourcegraph.Spec.GitServer.Replicas
+	gitServerScaling, err := r.resolveGitServerReplicas(ctx, &sourcegraph)
+	if err != nil {
+		return Result{}, err
 	}
-	if err := r.reconcileCadvisor(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile cadvisor: %w", err)
+
+	// An upgrade that rolls out in phases rolls out one phase at a time, with
+	// the images that GetDefaultImage resolves for it.
+	sourcegraph.Status.Upgrade = upgradeInProgress(previousStatus.Upgrade, &sourcegraph)
+
+	// Reconcile services here. A service that fails to reconcile doesn't stop
+	// the others from being reconciled, so that the status shows every
+	// service that is blocking the rollout.
+	status := config.SourcegraphStatus{
+		CurrentVersion:   previousStatus.CurrentVersion,
+		RequestedVersion: sourcegraph.Spec.RequestedVersion,
+		Conditions:       previousStatus.Conditions,
+		OperatorVersion:  r.OperatorVersion,
 	}
-	if err := r.reconcileWorker(ctx, &sourcegraph, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to reconcile worker: %w", err)
+	setSpecFieldsCondition(&status, fieldWarnings)
+	var errs error
+	nodes, nodesErr := r.listNodesForSchedulingCheck(ctx, &sourcegraph)
+	for _, step := range r.reconcileSteps() {
+		svc := status.Service(step.name)
+		svc.Conditions = previousStatus.Service(step.name).Conditions
+
+		stepCtx, drift := withDriftRecorder(ctx)
+		stepCtx, pending := withPendingChangeRecorder(stepCtx)
+		err := step.reconcile(stepCtx, &sourcegraph, &applianceSpec)
+		if err != nil {
+			err = errors.Newf("failed to reconcile %s: %w", step.description, err)
+			errs = errors.Append(errs, err)
+		}
+		setReconciledCondition(svc, err)
+		reportDrift(svc, events, sourcegraph.Spec.DriftPolicy.OrDefault(), drift.drifts)
+		reportPendingChanges(svc, events, pending.changes)
+		workloads := prefixedWorkloads(sourcegraph.Spec, step.workloads)
+		if err := r.setAvailableCondition(ctx, svc, sourcegraph.Namespace, workloads); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "getting status of %s", step.description))
+		}
+		if err := r.setSchedulableCondition(ctx, svc, sourcegraph.Namespace, workloads, nodes, nodesErr); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "checking nodes of %s", step.description))
+		}
+		if step.name == "gitserver" {
+			setGitServerShardsCondition(svc, gitServerScaling, requestedGitServers)
+		}
+		if step.name == "blobstore" {
+			r.setBlobstoreDiskSpaceCondition(ctx, svc, &sourcegraph)
+		}
+		if step.name == "frontend" {
+			setSiteConfigCondition(svc, &sourcegraph, err)
+		}
 	}
 
-	// Set the current version annotation in case migration logic depends on it.
-	applianceSpec.Annotations[config.AnnotationKeyCurrentVersion] = sourcegraph.Spec.RequestedVersion
+	// Set the current version annotation in case migration logic depends on
+	// it, but only once every service has been reconciled to it, after every
+	// phase of the upgrade.
+	ready := setReadyCondition(&status)
+	if errs != nil {
+		status.Upgrade = sourcegraph.Status.Upgrade
+	} else if upgrade := sourcegraph.Status.Upgrade; advanceUpgrade(&status, upgrade, sourcegraph.UpgradePhases(), ready) {
+		if status.Upgrade.Phase != upgrade.Phase {
+			events.eventf(EventReasonUpgradePhase, "Phase %d of the upgrade to %s has rolled out.", upgrade.Phase+1, upgrade.Version)
+		}
+		ready = false
+	} else {
+		if previous := sourcegraph.Status.CurrentVersion; previous != sourcegraph.Spec.RequestedVersion {
+			if previous == "" {
+				events.eventf(EventReasonVersionReconciled, "Every service has been reconciled to version %s.", sourcegraph.Spec.RequestedVersion)
+			} else {
+				events.eventf(EventReasonVersionReconciled, "Every service has been reconciled to version %s, from version %s.", sourcegraph.Spec.RequestedVersion, previous)
+			}
+		}
+		applianceSpec.Annotations[config.AnnotationKeyCurrentVersion] = sourcegraph.Spec.RequestedVersion
+		status.CurrentVersion = sourcegraph.Spec.RequestedVersion
+		status.Upgrade = nil
+	}
+	// The resolved config is only recorded once the spec was reconciled
+	// without errors, so that it describes what was deployed.
+	if errs == nil {
+		resolved, err := config.MarshalResolvedYAML(&sourcegraph)
+		if err != nil {
+			return Result{}, errors.Wrap(err, "resolving config")
+		}
+		applianceSpec.Annotations[config.AnnotationKeyResolvedConfig] = string(resolved)
+	}
+	if err := setStatusAnnotations(&applianceSpec, status); err != nil {
+		return Result{}, err
+	}
+	r.Tracker.setStatus(name, status)
 	if err := r.Client.Update(ctx, &applianceSpec); err != nil {
-		return ctrl.Result{}, errors.Newf("failed to update current version annotation: %w", err)
+		return Result{}, errors.Newf("failed to update current version annotation: %w", err)
+	}
+	if errs != nil {
+		return Result{Status: status}, errs
+	}
+	if !ready {
+		return Result{Status: status, RequeueAfter: rolloutPollInterval}, nil
+	}
+	if sourcegraph.Spec.Blobstore.HasRetention() && (r.ResyncInterval == 0 || diskUsagePollInterval < r.ResyncInterval) {
+		return Result{Status: status, RequeueAfter: diskUsagePollInterval}, nil
 	}
+	return Result{Status: status, RequeueAfter: r.ResyncInterval}, nil
+}
+
+// reconcileStep reconciles a service, or another group of objects, whose
+// status is reported under name.
+type reconcileStep struct {
+	name        string
+	description string
+	reconcile   func(context.Context, *config.Sourcegraph, client.Object) error
 
-	return ctrl.Result{}, nil
+	// workloads are the Deployments, StatefulSets, and DaemonSets that run
+	// the service, if any, and determine whether it is available. They are
+	// named as in a deployment without a NamePrefix.
+	workloads []workload
+}
+
+// reconcileSteps returns the steps of a reconcile, in order.
+func (r *Reconciler) reconcileSteps() []reconcileStep {
+	return []reconcileStep{
+		// The credentials that services consume are reconciled first, so
+		// that the dependency checksums of the services are right from the
+		// first reconcile.
+		{name: "credentials", description: "credentials", reconcile: r.reconcileCredentials},
+		{name: "blobstore", description: "blobstore", reconcile: r.reconcileBlobstore,
+			workloads: []workload{deploymentWorkload("blobstore")}},
+		{name: "repo-updater", description: "repo updater", reconcile: r.reconcileRepoUpdater,
+			workloads: []workload{deploymentWorkload("repo-updater")}},
+		{name: "symbols", description: "symbols service", reconcile: r.reconcileSymbols,
+			workloads: []workload{statefulSetWorkload("symbols")}},
+		{name: "gitserver", description: "gitserver", reconcile: r.reconcileGitServer,
+			workloads: []workload{statefulSetWorkload("gitserver")}},
+		{name: "redis", description: "redis", reconcile: r.reconcileRedis,
+			workloads: []workload{deploymentWorkload("redis-cache"), deploymentWorkload("redis-store")}},
+		{name: "pgsql", description: "pgsql", reconcile: r.reconcilePGSQL,
+			workloads: []workload{statefulSetWorkload("pgsql"), deploymentWorkload("pgsql-pgbouncer")}},
+		{name: "syntect-server", description: "syntect", reconcile: r.reconcileSyntect,
+			workloads: []workload{deploymentWorkload("syntect-server")}},
+		{name: "precise-code-intel", description: "precise code intel", reconcile: r.reconcilePreciseCodeIntel,
+			workloads: []workload{deploymentWorkload("precise-code-intel-worker")}},
+		{name: "codeinsights-db", description: "code insights DB", reconcile: r.reconcileCodeInsights,
+			workloads: []workload{statefulSetWorkload("codeinsights-db"), deploymentWorkload("codeinsights-db-pgbouncer")}},
+		{name: "codeintel-db", description: "code intel DB", reconcile: r.reconcileCodeIntel,
+			workloads: []workload{statefulSetWorkload("codeintel-db"), deploymentWorkload("codeintel-db-pgbouncer")}},
+		{name: "prometheus", description: "prometheus", reconcile: r.reconcilePrometheus,
+			workloads: []workload{deploymentWorkload("prometheus")}},
+		{name: "monitors", description: "monitors", reconcile: r.reconcileMonitors},
+		{name: "cadvisor", description: "cadvisor", reconcile: r.reconcileCadvisor,
+			workloads: []workload{daemonSetWorkload("cadvisor")}},
+		{name: "worker", description: "worker", reconcile: r.reconcileWorker,
+			workloads: []workload{deploymentWorkload("worker")}},
+		{name: "executors", description: "executors", reconcile: r.reconcileExecutors,
+			workloads: []workload{deploymentWorkload("executor")}},
+		{name: "frontend", description: "frontend", reconcile: r.reconcileFrontend,
+			workloads: []workload{deploymentWorkload("sourcegraph-frontend")}},
+		{name: "searcher", description: "searcher", reconcile: r.reconcileSearcher,
+			workloads: []workload{deploymentWorkload("searcher")}},
+		{name: "indexed-search", description: "indexed search", reconcile: r.reconcileIndexedSearch,
+			workloads: []workload{statefulSetWorkload("indexed-search")}},
+		{name: "grafana", description: "grafana", reconcile: r.reconcileGrafana,
+			workloads: []workload{deploymentWorkload("grafana")}},
+		{name: "otel-collector", description: "otel collector", reconcile: r.reconcileOtelCollector,
+			workloads: []workload{deploymentWorkload("otel-collector")}},
+		{name: "network-policies", description: "network policies", reconcile: r.reconcileNetworkPolicies},
+	}
 }
 
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -127,19 +424,26 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	})
 
 	// When updating this list of owned resources, please update the
-	// corresponding code in gatherResources() in golden_test.go.
+	// corresponding code in gatherResources() in golden_test.go, and
+	// managedKinds in render.go.
 	return ctrl.NewControllerManagedBy(mgr).
 		WithEventFilter(applianceAnnotationPredicate).
 		For(&corev1.ConfigMap{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.DaemonSet{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&batchv1.CronJob{}).
+		Owns(&batchv1.Job{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&corev1.Pod{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
+		Owns(&networkingv1.Ingress{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Complete(r)
 }
 
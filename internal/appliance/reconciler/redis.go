@@ -1,7 +1,15 @@
 package reconciler
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -10,6 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/configmap"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
@@ -17,7 +26,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/secret"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
-	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 func (r *Reconciler) reconcileRedis(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
@@ -31,22 +39,90 @@ func (r *Reconciler) reconcileRedis(ctx context.Context, sg *config.Sourcegraph,
 }
 
 func (r *Reconciler) reconcileRedisInstance(ctx context.Context, sg *config.Sourcegraph, owner client.Object, kind string, cfg config.RedisSpec) error {
-	if err := r.reconcileRedisDeployment(ctx, sg, owner, kind, cfg); err != nil {
+	// The bundled redis resources are treated as disabled when an external
+	// redis is configured. The Secret, which is how consumers discover the
+	// redis endpoint, is still created, by reconcileCredentials.
+	bundledCfg := bundledRedisConfig{RedisSpec: cfg}
+
+	redisConf, err := redisConfig(kind, cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := r.reconcileRedisConfigMap(ctx, sg, owner, kind, bundledCfg, redisConf); err != nil {
+		return errors.Wrap(err, "reconciling ConfigMap")
+	}
+	if err := r.reconcileRedisDeployment(ctx, sg, owner, kind, bundledCfg, redisConf); err != nil {
 		return errors.Wrap(err, "reconciling Deployment")
 	}
-	if err := r.reconcileRedisPVC(ctx, sg, owner, kind, cfg); err != nil {
+	if err := r.reconcileRedisPVC(ctx, sg, owner, kind, bundledCfg); err != nil {
 		return errors.Wrap(err, "reconciling PersistentVolumeClaim")
 	}
-	if err := r.reconcileRedisService(ctx, sg, owner, kind, cfg); err != nil {
+	if err := r.reconcileRedisService(ctx, sg, owner, kind, bundledCfg); err != nil {
 		return errors.Wrap(err, "reconciling Service")
 	}
-	if err := r.reconcileRedisSecret(ctx, sg, owner, kind, cfg); err != nil {
-		return errors.Wrap(err, "reconciling Secret")
-	}
 	return nil
 }
 
-func (r *Reconciler) reconcileRedisDeployment(ctx context.Context, sg *config.Sourcegraph, owner client.Object, kind string, cfg config.RedisSpec) error {
+// redisConfig renders the redis.conf of the bundled redis of the given kind.
+// The store persists every write, while the cache only takes snapshots.
+func redisConfig(kind string, cfg config.RedisSpec) (string, error) {
+	tmpl, err := template.New("redis-config").Parse(string(config.RedisConfigTemplate))
+	if err != nil {
+		return "", errors.Wrap(err, "parsing redis config template")
+	}
+
+	var maxMemory string
+	if cfg.MaxMemory != "" {
+		quantity, err := resource.ParseQuantity(cfg.MaxMemory)
+		if err != nil {
+			return "", errors.Wrap(err, "parsing maxMemory")
+		}
+		maxMemory = redisMemory(quantity.Value())
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct {
+		MaxMemory       string
+		MaxMemoryPolicy string
+		AppendOnly      bool
+		CustomConfig    string
+	}{
+		MaxMemory:       maxMemory,
+		MaxMemoryPolicy: cfg.MaxMemoryPolicy,
+		AppendOnly:      kind == "store",
+		CustomConfig:    strings.TrimSpace(cfg.CustomConfig),
+	}); err != nil {
+		return "", errors.Wrap(err, "rendering redis config template")
+	}
+	return buf.String(), nil
+}
+
+// redisMemory formats a number of bytes in the largest of redis' units that
+// it is a multiple of, which are powers of 1024.
+func redisMemory(bytes int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{
+		{"gb", 1 << 30},
+		{"mb", 1 << 20},
+		{"kb", 1 << 10},
+	} {
+		if bytes%unit.size == 0 {
+			return fmt.Sprintf("%d%s", bytes/unit.size, unit.suffix)
+		}
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+func (r *Reconciler) reconcileRedisConfigMap(ctx context.Context, sg *config.Sourcegraph, owner client.Object, kind string, cfg bundledRedisConfig, redisConf string) error {
+	cm := configmap.NewConfigMap(sg.Spec.ObjectName("redis-"+kind+"-conf"), sg.Namespace)
+	cm.Data = map[string]string{"redis.conf": redisConf}
+	return reconcileObject(ctx, r, cfg, &cm, &corev1.ConfigMap{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileRedisDeployment(ctx context.Context, sg *config.Sourcegraph, owner client.Object, kind string, cfg bundledRedisConfig, redisConf string) error {
 	name := "redis-" + kind
 
 	defaultImage, err := config.GetDefaultImage(sg, name)
@@ -105,9 +181,8 @@ fi
 	}
 	ctr.VolumeMounts = []corev1.VolumeMount{
 		{Name: "redis-data", MountPath: "/redis-data"},
+		{Name: "redis-conf", MountPath: "/etc/redis"},
 	}
-	ctr.SecurityContext.RunAsUser = pointers.Ptr(int64(999))
-	ctr.SecurityContext.RunAsGroup = pointers.Ptr(int64(1000))
 
 	exporterImage, err := config.GetDefaultImage(sg, "redis-exporter")
 	if err != nil {
@@ -129,25 +204,31 @@ fi
 	exporterCtr.Ports = []corev1.ContainerPort{
 		{Name: "redisexp", ContainerPort: 9121},
 	}
-	exporterCtr.SecurityContext.RunAsUser = pointers.Ptr(int64(999))
-	exporterCtr.SecurityContext.RunAsGroup = pointers.Ptr(int64(1000))
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
 	podTemplate.Template.Spec.Containers = []corev1.Container{ctr, exporterCtr}
 	podTemplate.Template.Spec.Volumes = []corev1.Volume{
-		pod.NewVolumeFromPVC("redis-data", name),
+		pod.NewVolumeFromPVC("redis-data", sg.Spec.ObjectName(name)),
+		pod.NewVolumeFromConfigMap("redis-conf", sg.Spec.ObjectName(name+"-conf")),
+	}
+	checksum := sha256.Sum256([]byte(redisConf))
+	podTemplate.Template.Annotations[config.AnnotationKeyConfigChecksum] = hex.EncodeToString(checksum[:])
+
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
 	}
-	podTemplate.Template.Spec.SecurityContext.FSGroup = pointers.Ptr(int64(1000))
 
-	dep := deployment.NewDeployment(name, sg.Namespace, sg.Spec.RequestedVersion)
-	dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	if err := applyDeploymentStrategy(&dep, cfg); err != nil {
+		return err
+	}
 	dep.Spec.Template = podTemplate.Template
 
 	return reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner)
 }
 
-func (r *Reconciler) reconcileRedisService(ctx context.Context, sg *config.Sourcegraph, owner client.Object, kind string, cfg config.RedisSpec) error {
-	name := "redis-" + kind
+func (r *Reconciler) reconcileRedisService(ctx context.Context, sg *config.Sourcegraph, owner client.Object, kind string, cfg bundledRedisConfig) error {
+	name := sg.Spec.ObjectName("redis-" + kind)
 	svc := service.NewService(name, sg.Namespace, cfg)
 	svc.Spec.Ports = []corev1.ServicePort{
 		{Name: "redis", Port: 6379, TargetPort: intstr.FromString("redis")},
@@ -158,8 +239,8 @@ func (r *Reconciler) reconcileRedisService(ctx context.Context, sg *config.Sourc
 	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
 }
 
-func (r *Reconciler) reconcileRedisPVC(ctx context.Context, sg *config.Sourcegraph, owner client.Object, kind string, cfg config.RedisSpec) error {
-	name := "redis-" + kind
+func (r *Reconciler) reconcileRedisPVC(ctx context.Context, sg *config.Sourcegraph, owner client.Object, kind string, cfg bundledRedisConfig) error {
+	name := sg.Spec.ObjectName("redis-" + kind)
 	pvc, err := pvc.NewPersistentVolumeClaim(name, sg.Namespace, cfg)
 	if err != nil {
 		return err
@@ -167,11 +248,71 @@ func (r *Reconciler) reconcileRedisPVC(ctx context.Context, sg *config.Sourcegra
 	return reconcileObject(ctx, r, cfg, &pvc, &corev1.PersistentVolumeClaim{}, sg, owner)
 }
 
+// reconcileRedisSecret manages the Secret that holds the endpoint of a redis
+// instance. It exists both for bundled and external redis: consumers don't
+// know which one they talk to, since redisEnvVars reads REDIS_CACHE_ENDPOINT
+// and REDIS_STORE_ENDPOINT from the "endpoint" key of these Secrets. Pointing
+// the Secret at an external endpoint is therefore all it takes to rewire them.
 func (r *Reconciler) reconcileRedisSecret(ctx context.Context, sg *config.Sourcegraph, owner client.Object, kind string, cfg config.RedisSpec) error {
-	name := "redis-" + kind
+	name := sg.Spec.ObjectName("redis-" + kind)
+
+	endpoint := name + ":6379"
+	if cfg.IsExternal() {
+		var err error
+		endpoint, err = r.externalRedisEndpoint(ctx, sg, cfg.External)
+		if err != nil {
+			return err
+		}
+	}
+
 	secret := secret.NewSecret(name, sg.Namespace, sg.Spec.RequestedVersion)
 	secret.StringData = map[string]string{
-		"endpoint": name + ":6379",
+		"endpoint": endpoint,
 	}
 	return reconcileObject(ctx, r, cfg, &secret, &corev1.Secret{}, sg, owner)
 }
+
+// redisEnvVars returns the env vars that point a service at the redis
+// endpoints, which are read from the Secrets that reconcileRedisSecret manages.
+func redisEnvVars(sg *config.Sourcegraph) []corev1.EnvVar {
+	return container.EnvVarsRedis(sg.Spec.ObjectName("redis-cache"), sg.Spec.ObjectName("redis-store"))
+}
+
+// externalRedisEndpoint builds the endpoint that consumers use to connect to
+// an external redis instance. If an auth secret is referenced, the password is
+// read from it and embedded in the endpoint URL.
+func (r *Reconciler) externalRedisEndpoint(ctx context.Context, sg *config.Sourcegraph, ext *config.ExternalRedisSpec) (string, error) {
+	if ext.Endpoint == "" {
+		return "", errors.New("external redis endpoint must be set")
+	}
+
+	endpoint := url.URL{Scheme: "redis", Host: ext.Endpoint}
+	if ext.TLS {
+		endpoint.Scheme = "rediss"
+	}
+
+	if ref := ext.AuthSecretRef; ref != nil {
+		var authSecret corev1.Secret
+		if err := r.GetObject(ctx, ref.Name, sg.Namespace, &authSecret); err != nil {
+			return "", errors.Wrapf(err, "getting redis auth secret %q", ref.Name)
+		}
+		password, ok := authSecret.Data[ref.Key]
+		if !ok {
+			return "", errors.Newf("redis auth secret %q has no key %q", ref.Name, ref.Key)
+		}
+		endpoint.User = url.UserPassword("", string(password))
+	}
+
+	return endpoint.String(), nil
+}
+
+// bundledRedisConfig wraps a RedisSpec for the resources that make up the
+// in-cluster redis deployment, which must not exist when an external redis is
+// configured.
+type bundledRedisConfig struct {
+	config.RedisSpec
+}
+
+func (c bundledRedisConfig) IsDisabled() bool {
+	return c.Disabled || c.IsExternal()
+}
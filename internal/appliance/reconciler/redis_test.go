@@ -1,10 +1,21 @@
 package reconciler
 
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
 func (suite *ApplianceTestSuite) TestDeployRedis() {
 	for _, tc := range []struct {
 		name string
 	}{
 		{name: "redis/default"},
+		{name: "redis/with-external-cache"},
+		{name: "redis/with-tuning"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -12,3 +23,56 @@ func (suite *ApplianceTestSuite) TestDeployRedis() {
 		})
 	}
 }
+
+func (suite *ApplianceTestSuite) TestRedisRolledWhenTuned() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("redis/default")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "redis/with-tuning")
+	suite.makeGoldenAssertions(namespace, "redis/subsequent-tuning")
+}
+
+func TestRenderRedisExternalCache(t *testing.T) {
+	objs, err := Render(context.Background(), renderedSpec, readSpecFixture(t, "redis/with-external-cache"))
+	require.NoError(t, err)
+
+	var workloads []string
+	endpoints := map[string]string{}
+	for _, obj := range objs {
+		u := obj.(*unstructured.Unstructured)
+		switch u.GetKind() {
+		case "Deployment", "StatefulSet", "PersistentVolumeClaim":
+			if u.GetName() == "redis-cache" || u.GetName() == "redis-store" {
+				workloads = append(workloads, u.GetKind()+"/"+u.GetName())
+			}
+		case "Secret":
+			encoded, found, err := unstructured.NestedString(u.Object, "data", "endpoint")
+			require.NoError(t, err)
+			if found {
+				endpoint, err := base64.StdEncoding.DecodeString(encoded)
+				require.NoError(t, err)
+				endpoints[u.GetName()] = string(endpoint)
+			}
+		}
+	}
+
+	// Only the bundled store runs in the cluster, the consumers reach the
+	// external cache through its Secret.
+	require.ElementsMatch(t, []string{"Deployment/redis-store", "PersistentVolumeClaim/redis-store"}, workloads)
+	require.Equal(t, map[string]string{
+		"redis-cache": "rediss://my-redis-cache.example.com:6379",
+		"redis-store": "redis-store:6379",
+	}, endpoints)
+}
+
+func TestRedisMemory(t *testing.T) {
+	for _, tc := range []struct {
+		bytes int64
+		want  string
+	}{
+		{bytes: 6 << 30, want: "6gb"},
+		{bytes: 1536 << 20, want: "1536mb"},
+		{bytes: 100 << 10, want: "100kb"},
+		{bytes: 1000, want: "1000"},
+	} {
+		require.Equal(t, tc.want, redisMemory(tc.bytes))
+	}
+}
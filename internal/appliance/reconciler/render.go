@@ -0,0 +1,339 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// managedKind is a kind of object that the appliance manages. Cluster-scoped
+// objects are labeled with the namespace of the deployment they belong to.
+type managedKind struct {
+	gvk        schema.GroupVersionKind
+	namespaced bool
+}
+
+// managedKinds are the kinds of objects that the appliance manages, in the
+// order that Render and Diff return them. When new owned types are declared in
+// SetupWithManager(), they must be added here too.
+var managedKinds = []managedKind{
+	{gvk: appsv1.SchemeGroupVersion.WithKind("Deployment"), namespaced: true},
+	{gvk: appsv1.SchemeGroupVersion.WithKind("DaemonSet"), namespaced: true},
+	{gvk: appsv1.SchemeGroupVersion.WithKind("StatefulSet"), namespaced: true},
+	{gvk: autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"), namespaced: true},
+	{gvk: batchv1.SchemeGroupVersion.WithKind("CronJob"), namespaced: true},
+	{gvk: batchv1.SchemeGroupVersion.WithKind("Job"), namespaced: true},
+	{gvk: rbacv1.SchemeGroupVersion.WithKind("ClusterRole")},
+	{gvk: rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding")},
+	{gvk: schedulingv1.SchemeGroupVersion.WithKind("PriorityClass")},
+	{gvk: corev1.SchemeGroupVersion.WithKind("ConfigMap"), namespaced: true},
+	{gvk: corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"), namespaced: true},
+	{gvk: corev1.SchemeGroupVersion.WithKind("Pod"), namespaced: true},
+	{gvk: policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"), namespaced: true},
+	{gvk: rbacv1.SchemeGroupVersion.WithKind("Role"), namespaced: true},
+	{gvk: rbacv1.SchemeGroupVersion.WithKind("RoleBinding"), namespaced: true},
+	{gvk: corev1.SchemeGroupVersion.WithKind("Secret"), namespaced: true},
+	{gvk: corev1.SchemeGroupVersion.WithKind("ServiceAccount"), namespaced: true},
+	{gvk: corev1.SchemeGroupVersion.WithKind("Service"), namespaced: true},
+	{gvk: networkingv1.SchemeGroupVersion.WithKind("Ingress"), namespaced: true},
+	{gvk: networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"), namespaced: true},
+	{gvk: podMonitorGVK, namespaced: true},
+	{gvk: serviceMonitorGVK, namespaced: true},
+	{gvk: routeGVK, namespaced: true},
+}
+
+// ChangeAction is what a reconcile does to an object.
+type ChangeAction string
+
+const (
+	ChangeCreate ChangeAction = "create"
+	ChangeUpdate ChangeAction = "update"
+	ChangeDelete ChangeAction = "delete"
+)
+
+// Change is an object that a reconcile would create, update, or delete.
+type Change struct {
+	Action    ChangeAction
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (c Change) String() string {
+	if c.Namespace == "" {
+		return fmt.Sprintf("%s %s %s", c.Action, c.Kind, c.Name)
+	}
+	return fmt.Sprintf("%s %s %s/%s", c.Action, c.Kind, c.Namespace, c.Name)
+}
+
+// Render returns the objects that the appliance would create for spec, were it
+// stored in the appliance ConfigMap applianceSpec of an empty namespace. The
+// objects are sorted by kind and name, and server-generated fields such as
+// UIDs, resource versions, and statuses are left out, so that the output is
+// the same every time.
+//
+// Nothing is read from or written to a cluster. Instead, the cluster is
+// assumed to serve the Prometheus Operator and OpenShift APIs, so that every
+// object that the spec asks for is rendered.
+func Render(ctx context.Context, applianceSpec types.NamespacedName, spec []byte) ([]client.Object, error) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, kind := range managedKinds {
+		scope := meta.RESTScopeRoot
+		if kind.namespaced {
+			scope = meta.RESTScopeNamespace
+		}
+		mapper.Add(kind.gvk, scope)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        applianceSpec.Name,
+			Namespace:   applianceSpec.Namespace,
+			Annotations: map[string]string{config.AnnotationKeyManaged: "true"},
+		},
+		Data: map[string]string{"spec": string(spec)},
+	}
+	_, after, err := simulateReconcile(ctx, cm, nil, mapper)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := make([]client.Object, 0, len(after))
+	for _, key := range sortedObjectKeys(after) {
+		if key == configMapKey(applianceSpec) {
+			continue
+		}
+		obj := after[key]
+		normalizeRenderedObject(obj)
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// Diff returns the changes that the next reconcile of the appliance ConfigMap
+// applianceSpec would make to the live cluster, if its spec were replaced with
+// spec. If spec is nil, the ConfigMap's current spec is used, e.g. to find
+// objects that were deleted by hand. The cluster is only read from.
+func Diff(ctx context.Context, live client.Client, applianceSpec types.NamespacedName, spec []byte) ([]Change, error) {
+	var cm corev1.ConfigMap
+	if err := live.Get(ctx, applianceSpec, &cm); err != nil {
+		return nil, errors.Wrap(err, "getting appliance ConfigMap")
+	}
+	if cm.GetAnnotations()[config.AnnotationKeyManaged] != "true" {
+		return nil, errors.Newf("ConfigMap %s is not managed by the appliance, it lacks the %s annotation", applianceSpec, config.AnnotationKeyManaged)
+	}
+	if spec != nil {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data["spec"] = string(spec)
+	}
+
+	existing, err := listManagedObjects(ctx, live, applianceSpec.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing live objects")
+	}
+	existingObjs := make([]client.Object, 0, len(existing))
+	for key, obj := range existing {
+		if key != configMapKey(applianceSpec) {
+			existingObjs = append(existingObjs, obj)
+		}
+	}
+
+	before, after, err := simulateReconcile(ctx, &cm, existingObjs, live.RESTMapper())
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, key := range sortedObjectKeys(before, after) {
+		if key == configMapKey(applianceSpec) {
+			continue
+		}
+		change := Change{Kind: key.gvk.Kind, Namespace: key.Namespace, Name: key.Name}
+		b, existed := before[key]
+		a, exists := after[key]
+		switch {
+		case !existed:
+			change.Action = ChangeCreate
+		case !exists:
+			change.Action = ChangeDelete
+		case b.GetResourceVersion() != a.GetResourceVersion():
+			change.Action = ChangeUpdate
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// simulateReconcile reconciles the appliance ConfigMap cm against an in-memory
+// cluster that holds cm and existing, and returns the managed objects in that
+// cluster before and after. mapper determines which optional APIs the
+// in-memory cluster serves.
+func simulateReconcile(ctx context.Context, cm *corev1.ConfigMap, existing []client.Object, mapper meta.RESTMapper) (before, after map[objectKey]*unstructured.Unstructured, err error) {
+	// The fake client registers the kinds of unstructured objects in its
+	// scheme, so it gets one of its own.
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, nil, err
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(mapper).
+		WithObjects(append([]client.Object{cm.DeepCopy()}, existing...)...).
+		Build()
+
+	before, err = listManagedObjects(ctx, c, cm.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := &Reconciler{Client: c, Scheme: scheme, Recorder: &record.FakeRecorder{}, HTTPClient: &http.Client{Transport: offlineTransport{}}}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cm)}); err != nil {
+		return nil, nil, err
+	}
+
+	// An invalid spec isn't an error of the reconcile, but is surfaced in an
+	// annotation instead.
+	var reconciled corev1.ConfigMap
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), &reconciled); err != nil {
+		return nil, nil, err
+	}
+	if validationErrors := reconciled.GetAnnotations()[config.AnnotationKeyValidationErrors]; validationErrors != "" {
+		return nil, nil, errors.Newf("invalid spec: %s", validationErrors)
+	}
+
+	after, err = listManagedObjects(ctx, c, cm.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+// objectKey identifies an object of any kind.
+type objectKey struct {
+	gvk schema.GroupVersionKind
+	types.NamespacedName
+}
+
+func configMapKey(name types.NamespacedName) objectKey {
+	return objectKey{gvk: corev1.SchemeGroupVersion.WithKind("ConfigMap"), NamespacedName: name}
+}
+
+// listManagedObjects lists the objects of the kinds that the appliance manages
+// in namespace, and the cluster-scoped ones that belong to it. Kinds that the
+// cluster doesn't serve are skipped.
+func listManagedObjects(ctx context.Context, c client.Client, namespace string) (map[objectKey]*unstructured.Unstructured, error) {
+	objs := map[objectKey]*unstructured.Unstructured{}
+	for _, kind := range managedKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(kind.gvk.GroupVersion().WithKind(kind.gvk.Kind + "List"))
+		opt := client.ListOption(client.InNamespace(namespace))
+		if !kind.namespaced {
+			opt = client.MatchingLabels{"for-namespace": namespace}
+		}
+		if err := c.List(ctx, list, opt); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "listing %s", kind.gvk.Kind)
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			obj.SetGroupVersionKind(kind.gvk)
+			objs[objectKey{gvk: kind.gvk, NamespacedName: client.ObjectKeyFromObject(obj)}] = obj
+		}
+	}
+	return objs, nil
+}
+
+// sortedObjectKeys returns the keys of all objs, ordered like managedKinds,
+// and then by namespace and name.
+func sortedObjectKeys(objs ...map[objectKey]*unstructured.Unstructured) []objectKey {
+	kindOrder := make(map[schema.GroupVersionKind]int, len(managedKinds))
+	for i, kind := range managedKinds {
+		kindOrder[kind.gvk] = i
+	}
+	seen := map[objectKey]struct{}{}
+	var keys []objectKey
+	for _, m := range objs {
+		for key := range m {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if oi, oj := kindOrder[keys[i].gvk], kindOrder[keys[j].gvk]; oi != oj {
+			return oi < oj
+		}
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	return keys
+}
+
+// normalizeRenderedObject removes the fields that the API server, rather than
+// the appliance, sets.
+func normalizeRenderedObject(obj *unstructured.Unstructured) {
+	for _, field := range [][]string{
+		{"metadata", "creationTimestamp"},
+		{"metadata", "generation"},
+		{"metadata", "managedFields"},
+		{"metadata", "resourceVersion"},
+		{"metadata", "uid"},
+		{"status"},
+	} {
+		unstructured.RemoveNestedField(obj.Object, field...)
+	}
+	// The annotation recording what was applied repeats the object itself.
+	unstructured.RemoveNestedField(obj.Object, "metadata", "annotations", config.AnnotationKeyLastApplied)
+
+	ownerRefs, found, _ := unstructured.NestedSlice(obj.Object, "metadata", "ownerReferences")
+	if !found {
+		return
+	}
+	for _, ref := range ownerRefs {
+		if ref, ok := ref.(map[string]any); ok {
+			delete(ref, "uid")
+		}
+	}
+	_ = unstructured.SetNestedSlice(obj.Object, ownerRefs, "metadata", "ownerReferences")
+}
+
+// offlineTransport fails every request, since services can't be reached while
+// rendering.
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("services can't be reached while rendering")
+}
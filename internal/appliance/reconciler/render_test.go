@@ -0,0 +1,109 @@
+package reconciler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var renderedSpec = types.NamespacedName{Namespace: "sourcegraph", Name: "sg"}
+
+func TestRender(t *testing.T) {
+	for _, name := range []string{
+		"render/default",
+	} {
+		t.Run(name, func(t *testing.T) {
+			objs, err := Render(context.Background(), renderedSpec, readSpecFixture(t, name))
+			require.NoError(t, err)
+			assertGoldenFile(t, name, objs)
+		})
+	}
+}
+
+func TestRenderInvalidSpec(t *testing.T) {
+	_, err := Render(context.Background(), renderedSpec, []byte("spec:\n  requestedVersion: 0.0.1\n"))
+	require.ErrorContains(t, err, "invalid spec")
+}
+
+func TestRenderNamePrefix(t *testing.T) {
+	ctx := context.Background()
+	names := map[string]string{}
+	for _, prefix := range []string{"blue", "green"} {
+		objs, err := Render(ctx, renderedSpec, []byte("spec:\n  requestedVersion: \"5.3.9104\"\n  namePrefix: "+prefix+"\n"))
+		require.NoError(t, err)
+		for _, obj := range objs {
+			key := obj.GetObjectKind().GroupVersionKind().String() + " " + obj.GetName()
+			other, ok := names[key]
+			require.False(t, ok, "%s is rendered for both %s and %s", key, other, prefix)
+			names[key] = prefix
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+	spec := readSpecFixture(t, "render/default")
+	rendered, err := Render(ctx, renderedSpec, spec)
+	require.NoError(t, err)
+
+	// The rendered objects stand in for a cluster that was reconciled from the
+	// default spec.
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, kind := range managedKinds {
+		scope := meta.RESTScopeRoot
+		if kind.namespaced {
+			scope = meta.RESTScopeNamespace
+		}
+		mapper.Add(kind.gvk, scope)
+	}
+	live := fake.NewClientBuilder().
+		WithRESTMapper(mapper).
+		WithObjects(append(rendered, newSpecConfigMap(spec))...).
+		Build()
+
+	t.Run("unchanged spec", func(t *testing.T) {
+		changes, err := Diff(ctx, live, renderedSpec, nil)
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+
+	t.Run("changed spec", func(t *testing.T) {
+		changes, err := Diff(ctx, live, renderedSpec, []byte(`spec:
+  requestedVersion: "5.3.9104"
+  pgsql:
+    persistentVolumeConfig:
+      storageSize: 500Gi
+  repoUpdater:
+    disabled: true
+`))
+		require.NoError(t, err)
+		require.Contains(t, changes, Change{Action: ChangeUpdate, Kind: "PersistentVolumeClaim", Namespace: "sourcegraph", Name: "pgsql"})
+		require.Contains(t, changes, Change{Action: ChangeDelete, Kind: "Deployment", Namespace: "sourcegraph", Name: "repo-updater"})
+		for _, change := range changes {
+			require.NotEqual(t, ChangeCreate, change.Action, change.String())
+		}
+	})
+
+	t.Run("unmanaged ConfigMap", func(t *testing.T) {
+		unmanaged := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: renderedSpec.Name, Namespace: renderedSpec.Namespace},
+		}).Build()
+		_, err := Diff(ctx, unmanaged, renderedSpec, spec)
+		require.ErrorContains(t, err, "not managed by the appliance")
+	})
+}
+
+func readSpecFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	spec, err := os.ReadFile(filepath.Join("testdata", "sg", name+".yaml"))
+	require.NoError(t, err)
+	return spec
+}
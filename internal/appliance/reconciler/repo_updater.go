@@ -2,6 +2,7 @@ package reconciler
 
 import (
 	"context"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -14,7 +15,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 )
 
 func (r *Reconciler) reconcileRepoUpdater(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
@@ -31,12 +31,12 @@ func (r *Reconciler) reconcileRepoUpdater(ctx context.Context, sg *config.Source
 }
 
 func (r *Reconciler) reconcileRepoUpdaterService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	svc := service.NewService("repo-updater", sg.Namespace, sg.Spec.RepoUpdater)
+	svc := service.NewService(sg.Spec.ObjectName("repo-updater"), sg.Namespace, sg.Spec.RepoUpdater)
 	svc.Spec.Ports = []corev1.ServicePort{
 		{Name: "http", TargetPort: intstr.FromString("http"), Port: 3182},
 	}
 	svc.Spec.Selector = map[string]string{
-		"app": "repo-updater",
+		"app": sg.Spec.ObjectName("repo-updater"),
 	}
 
 	return reconcileObject(ctx, r, sg.Spec.RepoUpdater, &svc, &corev1.Service{}, sg, owner)
@@ -64,8 +64,10 @@ func (r *Reconciler) reconcileRepoUpdaterDeployment(ctx context.Context, sg *con
 		},
 	})
 
-	ctr.Env = append(ctr.Env, container.EnvVarsRedis()...)
-	ctr.Env = append(ctr.Env, container.EnvVarsOtel()...)
+	ctr.Env = append(ctr.Env, redisEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, repoUpdaterSyncEnvVars(cfg)...)
+	ctr.Env = append(ctr.Env, otelEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, serviceEndpointEnvVars(sg)...)
 
 	ctr.Ports = []corev1.ContainerPort{
 		{Name: "http", ContainerPort: 3182},
@@ -97,18 +99,45 @@ func (r *Reconciler) reconcileRepoUpdaterDeployment(ctx context.Context, sg *con
 		TimeoutSeconds:   5,
 	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
 	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
 
-	dep := deployment.NewDeployment(name, sg.Namespace, sg.Spec.RequestedVersion)
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	if err := applyDeploymentStrategy(&dep, sg.Spec.RepoUpdater); err != nil {
+		return err
+	}
 	dep.Spec.Template = podTemplate.Template
-	dep.Spec.Template.Spec.ServiceAccountName = name
 
 	return reconcileObject(ctx, r, sg.Spec.RepoUpdater, &dep, &appsv1.Deployment{}, sg, owner)
 }
 
 func (r *Reconciler) reconcileRepoUpdaterServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.RepoUpdater
-	sa := serviceaccount.NewServiceAccount("repo-updater", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, sg.Spec.RepoUpdater, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("repo-updater"), sg.Spec.RepoUpdater)
+}
+
+// repoUpdaterSyncEnvVars returns the env vars that tune repository syncing, for
+// the settings that are configured.
+func repoUpdaterSyncEnvVars(cfg config.RepoUpdaterSpec) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if cfg.SyncInterval != "" {
+		env = append(env, corev1.EnvVar{Name: "SRC_REPOS_SYNC_INTERVAL", Value: cfg.SyncInterval})
+	}
+	if n := cfg.MaxConcurrentSyncers; n != nil {
+		env = append(env, corev1.EnvVar{Name: "SRC_REPOS_SYNC_MAX_CONCURRENT_SYNCERS", Value: strconv.Itoa(int(*n))})
+	}
+	if n := cfg.MaxConcurrentRequests; n != nil {
+		env = append(env, corev1.EnvVar{Name: "SRC_CODE_HOST_MAX_CONCURRENT_REQUESTS", Value: strconv.Itoa(int(*n))})
+	}
+	if limit := cfg.DefaultRateLimit; limit != nil {
+		env = append(env, corev1.EnvVar{Name: "SRC_CODE_HOST_RATE_LIMIT_REQUESTS_PER_HOUR", Value: strconv.Itoa(int(limit.RequestsPerHour))})
+		if limit.Burst != nil {
+			env = append(env, corev1.EnvVar{Name: "SRC_CODE_HOST_RATE_LIMIT_BURST", Value: strconv.Itoa(int(*limit.Burst))})
+		}
+	}
+	return env
 }
@@ -5,6 +5,8 @@ func (suite *ApplianceTestSuite) TestDeployRepoUpdater() {
 		name string
 	}{
 		{name: "repo-updater/default"},
+		{name: "repo-updater/with-proxy"},
+		{name: "repo-updater/with-sync-tuning"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -0,0 +1,102 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+func TestResolvedConfig(t *testing.T) {
+	spec := func(extra string) []byte {
+		return []byte(`spec:
+  requestedVersion: "5.3.9104"
+  pgsql:
+    database:
+      password: pgsql-secret
+` + extra)
+	}
+	c := fake.NewClientBuilder().WithObjects(newSpecConfigMap(spec(""))).Build()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: record.NewFakeRecorder(1000)}
+	ctx := context.Background()
+
+	_, cm := reconcileSpecConfigMap(t, r)
+	before := cm.Annotations[config.AnnotationKeyResolvedConfig]
+	require.NotEmpty(t, before)
+	require.NotContains(t, before, "pgsql-secret")
+	require.Contains(t, before, config.RedactedValue)
+
+	// Reconciling the same spec again doesn't change it.
+	_, cm = reconcileSpecConfigMap(t, r)
+	require.Equal(t, before, cm.Annotations[config.AnnotationKeyResolvedConfig])
+
+	// Overriding the image of the frontend changes its container config and
+	// its resolved image, and nothing else.
+	cm.Data["spec"] = string(spec(`  frontend:
+    containerConfig:
+      frontend:
+        image: frontend:custom
+`))
+	require.NoError(t, c.Update(ctx, &cm))
+	_, cm = reconcileSpecConfigMap(t, r)
+	after := cm.Annotations[config.AnnotationKeyResolvedConfig]
+
+	sg := config.NewDefaultConfig()
+	sg.Spec.RequestedVersion = "5.3.9104"
+	defaultImage, err := config.GetDefaultImage(&sg, "frontend")
+	require.NoError(t, err)
+	require.Equal(t, map[string][2]any{
+		"spec.frontend.containerConfig.frontend.image": {nil, "frontend:custom"},
+		"images.frontend/frontend":                     {defaultImage, "index.docker.io/sourcegraph/frontend:custom"},
+	}, resolvedConfigDelta(t, before, after))
+}
+
+// resolvedConfigDelta returns the leaves of two resolved config documents that
+// differ, keyed by their path, with their values before and after.
+func resolvedConfigDelta(t *testing.T, before, after string) map[string][2]any {
+	t.Helper()
+	flatten := func(doc string) map[string]any {
+		var value any
+		require.NoError(t, yaml.Unmarshal([]byte(doc), &value))
+		leaves := map[string]any{}
+		var walk func(path []string, value any)
+		walk = func(path []string, value any) {
+			switch v := value.(type) {
+			case map[string]any:
+				for key, child := range v {
+					walk(append(path, key), child)
+				}
+			case []any:
+				for i, child := range v {
+					walk(append(path, fmt.Sprint(i)), child)
+				}
+			default:
+				leaves[strings.Join(path, ".")] = v
+			}
+		}
+		walk(nil, value)
+		return leaves
+	}
+
+	beforeLeaves, afterLeaves := flatten(before), flatten(after)
+	delta := map[string][2]any{}
+	for path, value := range beforeLeaves {
+		if other, ok := afterLeaves[path]; !ok || fmt.Sprint(other) != fmt.Sprint(value) {
+			delta[path] = [2]any{value, afterLeaves[path]}
+		}
+	}
+	for path, value := range afterLeaves {
+		if _, ok := beforeLeaves[path]; !ok {
+			delta[path] = [2]any{nil, value}
+		}
+	}
+	return delta
+}
@@ -0,0 +1,60 @@
+package reconciler
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// applyDeploymentStrategy sets the rollout strategy of a service's Deployment.
+// Without a configured strategy, the Kubernetes default applies: a rolling
+// update with 25% surge and 25% unavailable pods.
+func applyDeploymentStrategy(dep *appsv1.Deployment, cfg config.StandardComponent) error {
+	strategy := cfg.GetStrategy()
+	if strategy == nil {
+		return nil
+	}
+	switch strategy.Type {
+	case config.RecreateStrategy:
+		dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	case config.OnDeleteStrategy:
+		return errors.Newf("strategy.type: %s is only supported by StatefulSets, and %s is a Deployment", strategy.Type, dep.Name)
+	default:
+		dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType}
+		// Unset parameters keep the Kubernetes default of 25%.
+		if strategy.MaxSurge != nil || strategy.MaxUnavailable != nil {
+			dep.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{}
+			if strategy.MaxSurge != nil {
+				dep.Spec.Strategy.RollingUpdate.MaxSurge = pointers.Ptr(*strategy.MaxSurge)
+			}
+			if strategy.MaxUnavailable != nil {
+				dep.Spec.Strategy.RollingUpdate.MaxUnavailable = pointers.Ptr(*strategy.MaxUnavailable)
+			}
+		}
+	}
+	return nil
+}
+
+// applyStatefulSetStrategy sets the update strategy of a service's
+// StatefulSet. StatefulSets terminate each pod before starting its
+// replacement, so a Recreate strategy is a rolling update. A partition keeps
+// the pods with a lower ordinal on the previous version, e.g. to roll a new
+// version out to a single gitserver shard first.
+func applyStatefulSetStrategy(sset *appsv1.StatefulSet, cfg config.StandardComponent) {
+	strategy := cfg.GetStrategy()
+	if strategy == nil {
+		return
+	}
+	if strategy.Type == config.OnDeleteStrategy {
+		sset.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+		return
+	}
+	sset.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType}
+	if strategy.Partition != nil {
+		sset.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: pointers.Ptr(*strategy.Partition),
+		}
+	}
+}
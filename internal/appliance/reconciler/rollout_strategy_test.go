@@ -0,0 +1,133 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func TestRolloutStrategy(t *testing.T) {
+	render := func(t *testing.T, spec string) (map[string]appsv1.Deployment, map[string]appsv1.StatefulSet) {
+		t.Helper()
+		objs, err := Render(context.Background(), renderedSpec, []byte(spec))
+		require.NoError(t, err)
+		deps, ssets := map[string]appsv1.Deployment{}, map[string]appsv1.StatefulSet{}
+		for _, obj := range objs {
+			u := obj.(*unstructured.Unstructured)
+			switch u.GetKind() {
+			case "Deployment":
+				var dep appsv1.Deployment
+				require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &dep))
+				deps[u.GetName()] = dep
+			case "StatefulSet":
+				var sset appsv1.StatefulSet
+				require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &sset))
+				ssets[u.GetName()] = sset
+			}
+		}
+		return deps, ssets
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		deps, ssets := render(t, `
+spec:
+  requestedVersion: "5.3.9104"
+`)
+		require.Equal(t, appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxSurge:       pointers.Ptr(intstr.FromInt32(2)),
+				MaxUnavailable: pointers.Ptr(intstr.FromInt32(0)),
+			},
+		}, deps["sourcegraph-frontend"].Spec.Strategy)
+		require.Equal(t, appsv1.RecreateDeploymentStrategyType, deps["redis-store"].Spec.Strategy.Type)
+		require.Equal(t, appsv1.DeploymentStrategy{}, deps["repo-updater"].Spec.Strategy)
+		require.Equal(t, appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType}, ssets["gitserver"].Spec.UpdateStrategy)
+	})
+
+	t.Run("gitserver partition", func(t *testing.T) {
+		_, ssets := render(t, `
+spec:
+  requestedVersion: "5.3.9104"
+  gitServer:
+    replicas: 2
+    strategy:
+      partition: 1
+`)
+		sset := ssets["gitserver"]
+		require.Equal(t, appsv1.RollingUpdateStatefulSetStrategyType, sset.Spec.UpdateStrategy.Type)
+		require.NotNil(t, sset.Spec.UpdateStrategy.RollingUpdate)
+
+		// The StatefulSet controller only updates the pods whose ordinal is
+		// at least the partition.
+		partition := pointers.Deref(sset.Spec.UpdateStrategy.RollingUpdate.Partition, 0)
+		var updated []int32
+		for ordinal := int32(0); ordinal < pointers.Deref(sset.Spec.Replicas, 1); ordinal++ {
+			if ordinal >= partition {
+				updated = append(updated, ordinal)
+			}
+		}
+		require.Equal(t, []int32{1}, updated)
+
+		// The rollout is complete once the pods above the partition are.
+		sset.Status = appsv1.StatefulSetStatus{Replicas: 2, UpdatedReplicas: 1, ReadyReplicas: 2}
+		require.Empty(t, statefulSetRolloutProblem(&sset))
+		sset.Status.UpdatedReplicas = 0
+		require.Equal(t, "0 of 1 replicas are updated", statefulSetRolloutProblem(&sset))
+	})
+
+	t.Run("recreate", func(t *testing.T) {
+		deps, ssets := render(t, `
+spec:
+  requestedVersion: "5.3.9104"
+  pgsql:
+    strategy:
+      type: Recreate
+  frontend:
+    strategy:
+      type: Recreate
+`)
+		// StatefulSets already terminate each pod before replacing it.
+		require.Equal(t, appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType}, ssets["pgsql"].Spec.UpdateStrategy)
+		// The surge of the frontend's default strategy doesn't apply.
+		require.Equal(t, appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}, deps["sourcegraph-frontend"].Spec.Strategy)
+	})
+
+	t.Run("rolling update parameters", func(t *testing.T) {
+		deps, _ := render(t, `
+spec:
+  requestedVersion: "5.3.9104"
+  frontend:
+    strategy:
+      maxSurge: 25%
+  repoUpdater:
+    strategy:
+      maxUnavailable: 0
+`)
+		require.Equal(t, &appsv1.RollingUpdateDeployment{
+			MaxSurge:       pointers.Ptr(intstr.FromString("25%")),
+			MaxUnavailable: pointers.Ptr(intstr.FromInt32(0)),
+		}, deps["sourcegraph-frontend"].Spec.Strategy.RollingUpdate)
+		require.Equal(t, &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: pointers.Ptr(intstr.FromInt32(0)),
+		}, deps["repo-updater"].Spec.Strategy.RollingUpdate)
+	})
+
+	t.Run("OnDelete is only supported by StatefulSets", func(t *testing.T) {
+		_, err := Render(context.Background(), renderedSpec, []byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  frontend:
+    strategy:
+      type: OnDelete
+`))
+		require.ErrorContains(t, err, "only supported by StatefulSets")
+	})
+}
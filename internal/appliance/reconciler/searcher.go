@@ -0,0 +1,182 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/container"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func (r *Reconciler) reconcileSearcher(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	if err := r.reconcileSearcherPVC(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling PVC")
+	}
+	if err := r.reconcileSearcherDeployment(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Deployment")
+	}
+	if err := r.reconcileHorizontalPodAutoscaler(ctx, sg, owner, sg.Spec.ObjectName("searcher"), "Deployment", sg.Spec.Searcher, sg.Spec.Searcher.Autoscaling); err != nil {
+		return errors.Wrap(err, "reconciling HorizontalPodAutoscaler")
+	}
+	if err := r.reconcilePodDisruptionBudget(ctx, sg, owner, sg.Spec.ObjectName("searcher"), sg.Spec.Searcher, sg.Spec.Searcher.Autoscaling.MaxReplicasOr(sg.Spec.Searcher.Replicas)); err != nil {
+		return errors.Wrap(err, "reconciling PodDisruptionBudget")
+	}
+	if err := r.reconcileSearcherService(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling Service")
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileSearcherDeployment(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := "searcher"
+	cfg := sg.Spec.Searcher
+	cacheOnPVC := !(searcherCacheConfig{cfg}).IsDisabled()
+
+	defaultImage, err := config.GetDefaultImage(sg, name)
+	if err != nil {
+		return err
+	}
+	ctr := container.NewContainer(name, cfg, config.ContainerConfig{
+		Image: defaultImage,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("500M"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("2G"),
+			},
+		},
+	})
+
+	ctr.Env = append(ctr.Env, redisEnvVars(sg)...)
+	if cacheOnPVC {
+		storageSize, err := resource.ParseQuantity(cfg.GetPersistentVolumeConfig().StorageSize)
+		if err != nil {
+			return errors.Wrap(err, "parsing storage size")
+		}
+
+		// Cache size is 90% of available attached storage
+		cacheSize := float64(storageSize.Value()) * 0.9
+		cacheSizeMB := int(math.Floor(cacheSize / 1024 / 1024))
+		ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "SEARCHER_CACHE_SIZE_MB", Value: fmt.Sprintf("%d", cacheSizeMB)})
+	}
+	ctr.Env = append(
+		ctr.Env,
+		container.NewEnvVarFieldRef("POD_NAME", "metadata.name"),
+		corev1.EnvVar{Name: "CACHE_DIR", Value: "/mnt/cache/$(POD_NAME)"},
+	)
+	ctr.Env = append(ctr.Env, otelEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, serviceEndpointEnvVars(sg)...)
+
+	ctr.Ports = []corev1.ContainerPort{
+		{Name: "http", ContainerPort: 3181},
+		{Name: "debug", ContainerPort: 6060},
+	}
+	ctr.LivenessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromString("debug"),
+			},
+		},
+		InitialDelaySeconds: 60,
+		TimeoutSeconds:      5,
+	}
+	ctr.ReadinessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromString("http"),
+			},
+		},
+		PeriodSeconds:  5,
+		TimeoutSeconds: 5,
+	}
+	ctr.VolumeMounts = []corev1.VolumeMount{
+		{Name: "cache", MountPath: "/mnt/cache"},
+	}
+
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
+	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	if cacheOnPVC {
+		podTemplate.Template.Spec.Volumes = []corev1.Volume{pod.NewVolumeFromPVC("cache", sg.Spec.ObjectName(name))}
+	} else {
+		podTemplate.Template.Spec.Volumes = []corev1.Volume{pod.NewVolumeEmptyDir("cache")}
+	}
+
+	applyTopologySpread(&podTemplate.Template, cfg, cfg.Autoscaling.MaxReplicasOr(cfg.Replicas))
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	dep.Spec.Replicas, err = r.replicasFor(ctx, &dep, &appsv1.Deployment{}, cfg.Replicas, cfg.Autoscaling)
+	if err != nil {
+		return err
+	}
+	if err := applyDeploymentStrategy(&dep, cfg); err != nil {
+		return err
+	}
+	dep.Spec.Template = podTemplate.Template
+
+	return reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileSearcherPVC(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := searcherCacheConfig{sg.Spec.Searcher}
+
+	if cfg.IsDisabled() {
+		// There is no storage size to parse, and reconcileObject only needs
+		// the name and namespace to delete any existing PVC.
+		p := corev1.PersistentVolumeClaim{}
+		p.Name, p.Namespace = sg.Spec.ObjectName("searcher"), sg.Namespace
+		return reconcileObject(ctx, r, cfg, &p, &corev1.PersistentVolumeClaim{}, sg, owner)
+	}
+
+	p, err := pvc.NewPersistentVolumeClaim(sg.Spec.ObjectName("searcher"), sg.Namespace, cfg)
+	if err != nil {
+		return err
+	}
+
+	return reconcileObject(ctx, r, cfg, &p, &corev1.PersistentVolumeClaim{}, sg, owner)
+}
+
+func (r *Reconciler) reconcileSearcherService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	name := "searcher"
+	cfg := sg.Spec.Searcher
+
+	svc := service.NewService(sg.Spec.ObjectName(name), sg.Namespace, cfg)
+	svc.Spec.Ports = []corev1.ServicePort{
+		{Name: "http", Port: 3181, TargetPort: intstr.FromString("http")},
+		{Name: "debug", Port: 6060, TargetPort: intstr.FromString("debug")},
+	}
+	svc.Spec.Selector = map[string]string{
+		"app": sg.Spec.ObjectName(name),
+	}
+
+	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
+}
+
+// searcherCacheConfig wraps a SearcherSpec for the searcher cache PVC, which
+// is replaced by an emptyDir when no storage size is configured.
+type searcherCacheConfig struct {
+	config.SearcherSpec
+}
+
+func (c searcherCacheConfig) IsDisabled() bool {
+	return c.Disabled || c.PersistentVolumeConfig.StorageSize == ""
+}
@@ -0,0 +1,20 @@
+package reconciler
+
+func (suite *ApplianceTestSuite) TestDeploySearcher() {
+	for _, tc := range []struct {
+		name string
+	}{
+		{name: "searcher/default"},
+		{name: "searcher/disabled"},
+
+		// This service does some logic on the storage quantity, so we can't
+		// just rely on the standard config test for storage amounts/classes.
+		{name: "searcher/with-storage"},
+		{name: "searcher/without-storage"},
+	} {
+		suite.Run(tc.name, func() {
+			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
+			suite.makeGoldenAssertions(namespace, tc.name)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package reconciler
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
+)
+
+// reconcileServiceAccount manages the ServiceAccount called name that a
+// service's pods run as by default. If the service is configured to run as an
+// existing ServiceAccount instead, the managed one is deleted.
+func (r *Reconciler) reconcileServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object, name string, cfg config.StandardComponent) error {
+	if err := cfg.GetServiceAccountConfig().Validate(); err != nil {
+		return err
+	}
+
+	sa := serviceaccount.NewServiceAccount(name, sg.Namespace, cfg)
+	if existing := cfg.GetServiceAccountConfig().Name; existing != "" && !cfg.IsDisabled() {
+		if existing == name {
+			// The admin has taken over the ServiceAccount, so leave it be.
+			return nil
+		}
+		return r.ensureObjectDeleted(ctx, &sa)
+	}
+	return reconcileObject(ctx, r, cfg, &sa, &corev1.ServiceAccount{}, sg, owner)
+}
@@ -0,0 +1,179 @@
+package reconciler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/secret"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	// siteConfigKey is the key of the generated site config Secret that the
+	// frontend reads the site configuration from.
+	siteConfigKey = "site.json"
+
+	siteConfigMountPath = "/etc/sourcegraph/site-config"
+)
+
+// siteConfigError is returned when the site configuration of the spec can't
+// be read or doesn't match the site configuration schema. It is reported in
+// the SiteConfigValid condition of the frontend.
+type siteConfigError struct {
+	problems []string
+}
+
+func (e *siteConfigError) Error() string {
+	return "invalid site configuration: " + strings.Join(e.problems, "; ")
+}
+
+// reconcileSiteConfigSecret copies the site configuration of the spec into a
+// generated Secret, which the frontend mounts, once it has been validated.
+// An invalid site configuration leaves the Secret as it is, and fails the
+// reconcile of the frontend, so that it keeps running with the last valid
+// one. The frontend is rolled when the Secret changes, by its dependency
+// checksum.
+func (r *Reconciler) reconcileSiteConfigSecret(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	cfg := siteConfigSecretConfig{FrontendSpec: sg.Spec.Frontend, siteConfig: sg.Spec.SiteConfig}
+	scrt := secret.NewSecret(sg.Spec.ObjectName("sourcegraph-frontend-site-config"), sg.Namespace, sg.Spec.RequestedVersion)
+	if cfg.IsDisabled() {
+		return reconcileObject(ctx, r, cfg, &scrt, &corev1.Secret{}, sg, owner)
+	}
+
+	siteConfig, err := r.readSiteConfig(ctx, sg)
+	if err != nil {
+		return err
+	}
+	if problems := validateSiteConfig(siteConfig); len(problems) > 0 {
+		return &siteConfigError{problems: problems}
+	}
+	scrt.Data = map[string][]byte{siteConfigKey: []byte(siteConfig)}
+	checksum := sha256.Sum256([]byte(siteConfig))
+	cfg.SiteConfigChecksum = hex.EncodeToString(checksum[:])
+
+	return reconcileObject(ctx, r, cfg, &scrt, &corev1.Secret{}, sg, owner)
+}
+
+// readSiteConfig returns the site configuration of the spec, reading it from
+// the Secret that the spec references if it isn't inline.
+func (r *Reconciler) readSiteConfig(ctx context.Context, sg *config.Sourcegraph) (string, error) {
+	ref := sg.Spec.SiteConfig.SecretRef
+	if ref == nil {
+		return sg.Spec.SiteConfig.JSON, nil
+	}
+	var scrt corev1.Secret
+	found, err := r.getDependency(ctx, ref.Name, sg.Namespace, &scrt)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", &siteConfigError{problems: []string{fmt.Sprintf("Secret %s not found", ref.Name)}}
+	}
+	siteConfig, ok := scrt.Data[ref.Key]
+	if !ok {
+		return "", &siteConfigError{problems: []string{fmt.Sprintf("Secret %s has no key %s", ref.Name, ref.Key)}}
+	}
+	return string(siteConfig), nil
+}
+
+// validateSiteConfig checks a site configuration, which may have comments and
+// trailing commas, against the site configuration schema, returning the
+// problems that the frontend would report for it.
+func validateSiteConfig(siteConfig string) []string {
+	input, err := jsonc.Parse(siteConfig)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(schema.SiteSchemaJSON))
+	if err != nil {
+		return []string{errors.Wrap(err, "loading the site configuration schema").Error()}
+	}
+	res, err := s.Validate(gojsonschema.NewBytesLoader(input))
+	if err != nil {
+		return []string{err.Error()}
+	}
+	problems := make([]string, 0, len(res.Errors()))
+	for _, e := range res.Errors() {
+		field := e.Field()
+		if c := e.Context(); c != nil {
+			field = strings.TrimPrefix(c.String("."), "(root).")
+		}
+		problems = append(problems, fmt.Sprintf("%s: %s", field, e.Description()))
+	}
+	return problems
+}
+
+// siteConfigSecretConfig wraps a FrontendSpec for the generated site config
+// Secret, which is only needed if the spec has a site configuration. The
+// checksum of the site configuration, which may come from a Secret rather
+// than the spec, makes the Secret be updated whenever it changes.
+type siteConfigSecretConfig struct {
+	config.FrontendSpec
+	SiteConfigChecksum string `json:",omitempty"`
+	siteConfig         *config.SiteConfigSpec
+}
+
+func (c siteConfigSecretConfig) IsDisabled() bool {
+	return c.Disabled || c.siteConfig == nil
+}
+
+// applySiteConfig makes the frontend read the site configuration from the
+// generated site config Secret, if the spec has one. The site configuration
+// can't be edited in the UI then, since the edits would be lost when the
+// frontend restarts.
+func applySiteConfig(template *corev1.PodTemplateSpec, ctr *corev1.Container, sg *config.Sourcegraph) {
+	if sg.Spec.SiteConfig == nil {
+		return
+	}
+	ctr.Env = append(ctr.Env,
+		corev1.EnvVar{Name: "SITE_CONFIG_FILE", Value: siteConfigMountPath + "/" + siteConfigKey},
+		corev1.EnvVar{Name: "SITE_CONFIG_ALLOW_EDITS", Value: "false"},
+	)
+	ctr.VolumeMounts = append(ctr.VolumeMounts, corev1.VolumeMount{
+		Name:      "site-config",
+		MountPath: siteConfigMountPath,
+		ReadOnly:  true,
+	})
+	template.Spec.Volumes = append(template.Spec.Volumes, pod.NewVolumeFromSecret("site-config", sg.Spec.ObjectName("sourcegraph-frontend-site-config")))
+}
+
+// setSiteConfigCondition sets the SiteConfigValid condition of the frontend
+// from the error that reconciling it returned, if the spec has a site
+// configuration. Other errors, e.g. from the API server, leave the condition
+// as it is.
+func setSiteConfigCondition(svc *config.ServiceStatus, sg *config.Sourcegraph, err error) {
+	if sg.Spec.SiteConfig == nil || sg.Spec.Frontend.Disabled {
+		meta.RemoveStatusCondition(&svc.Conditions, config.ConditionSiteConfigValid)
+		return
+	}
+
+	var siteConfigErr *siteConfigError
+	switch {
+	case errors.As(err, &siteConfigErr):
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:    config.ConditionSiteConfigValid,
+			Status:  metav1.ConditionFalse,
+			Reason:  config.ReasonSiteConfigInvalid,
+			Message: fmt.Sprintf("Not rolling out the frontend, since its site configuration is invalid: %s", strings.Join(siteConfigErr.problems, "; ")),
+		})
+	case err == nil:
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:   config.ConditionSiteConfigValid,
+			Status: metav1.ConditionTrue,
+			Reason: config.ReasonSiteConfigValid,
+		})
+	}
+}
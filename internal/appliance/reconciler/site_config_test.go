@@ -0,0 +1,155 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+func TestSiteConfig(t *testing.T) {
+	ctx := context.Background()
+	frontend := client.ObjectKey{Namespace: renderedSpec.Namespace, Name: "sourcegraph-frontend"}
+	generated := client.ObjectKey{Namespace: renderedSpec.Namespace, Name: "sourcegraph-frontend-site-config"}
+	frontendSpec := string(readSpecFixture(t, "frontend/default"))
+
+	// withSiteConfig returns the frontend spec with the given siteConfig
+	// block, indented by 4 spaces.
+	withSiteConfig := func(siteConfig string) string {
+		return strings.Replace(frontendSpec, "spec:\n", "spec:\n  siteConfig:\n"+siteConfig, 1)
+	}
+	inline := func(json string) string {
+		return withSiteConfig("    json: |-\n      " + strings.ReplaceAll(json, "\n", "\n      ") + "\n")
+	}
+	newReconciler := func(t *testing.T, spec string, objs ...client.Object) (client.Client, *Reconciler) {
+		t.Helper()
+		mapper := meta.NewDefaultRESTMapper(nil)
+		for _, kind := range managedKinds {
+			scope := meta.RESTScopeRoot
+			if kind.namespaced {
+				scope = meta.RESTScopeNamespace
+			}
+			mapper.Add(kind.gvk, scope)
+		}
+		c := fake.NewClientBuilder().
+			WithRESTMapper(mapper).
+			WithObjects(append(objs, newSpecConfigMap([]byte(spec)))...).
+			Build()
+		return c, &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: record.NewFakeRecorder(1000)}
+	}
+	updateSpec := func(t *testing.T, c client.Client, spec string) {
+		t.Helper()
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		cm.Data["spec"] = spec
+		require.NoError(t, c.Update(ctx, &cm))
+	}
+	siteConfigOf := func(t *testing.T, c client.Client) string {
+		t.Helper()
+		var scrt corev1.Secret
+		require.NoError(t, c.Get(ctx, generated, &scrt))
+		return string(scrt.Data[siteConfigKey])
+	}
+	frontendDeployment := func(t *testing.T, c client.Client) appsv1.Deployment {
+		t.Helper()
+		var dep appsv1.Deployment
+		require.NoError(t, c.Get(ctx, frontend, &dep))
+		return dep
+	}
+	frontendConditions := func(cm corev1.ConfigMap) []metav1.Condition {
+		status := statusFromAnnotations(cm.GetAnnotations())
+		return status.Service("frontend").Conditions
+	}
+
+	const siteConfig = `{
+  // The URL that users reach Sourcegraph at.
+  "externalURL": "https://sourcegraph.example.com",
+}`
+
+	t.Run("inline", func(t *testing.T) {
+		c, r := newReconciler(t, inline(siteConfig))
+
+		_, cm := reconcileSpecConfigMap(t, r)
+		require.Equal(t, siteConfig, siteConfigOf(t, c))
+		requireCondition(t, frontendConditions(cm), config.ConditionSiteConfigValid, metav1.ConditionTrue, "")
+
+		dep := frontendDeployment(t, c)
+		ctr := dep.Spec.Template.Spec.Containers[0]
+		require.Contains(t, ctr.Env, corev1.EnvVar{Name: "SITE_CONFIG_FILE", Value: "/etc/sourcegraph/site-config/site.json"})
+		require.Contains(t, ctr.Env, corev1.EnvVar{Name: "SITE_CONFIG_ALLOW_EDITS", Value: "false"})
+		require.Contains(t, ctr.VolumeMounts, corev1.VolumeMount{Name: "site-config", MountPath: "/etc/sourcegraph/site-config", ReadOnly: true})
+		checksum := dep.Spec.Template.Annotations[config.AnnotationKeyDependencyChecksum]
+		require.NotEmpty(t, checksum)
+
+		// Changing the site configuration rolls the frontend.
+		updateSpec(t, c, inline(`{"externalURL": "https://sourcegraph.example.org"}`))
+		reconcileSpecConfigMap(t, r)
+		require.NotEqual(t, checksum, frontendDeployment(t, c).Spec.Template.Annotations[config.AnnotationKeyDependencyChecksum])
+
+		// Removing it lets the frontend read the site configuration from
+		// the database again.
+		updateSpec(t, c, frontendSpec)
+		_, cm = reconcileSpecConfigMap(t, r)
+		require.Nil(t, meta.FindStatusCondition(frontendConditions(cm), config.ConditionSiteConfigValid))
+		require.NotContains(t, frontendDeployment(t, c).Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "SITE_CONFIG_ALLOW_EDITS", Value: "false"})
+	})
+
+	t.Run("Secret reference", func(t *testing.T) {
+		userSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "site-config", Namespace: renderedSpec.Namespace},
+			Data:       map[string][]byte{"config": []byte(siteConfig)},
+		}
+		c, r := newReconciler(t, withSiteConfig("    secretRef:\n      name: site-config\n      key: config\n"), userSecret)
+
+		_, cm := reconcileSpecConfigMap(t, r)
+		require.Equal(t, siteConfig, siteConfigOf(t, c))
+		requireCondition(t, frontendConditions(cm), config.ConditionSiteConfigValid, metav1.ConditionTrue, "")
+		checksum := frontendDeployment(t, c).Spec.Template.Annotations[config.AnnotationKeyDependencyChecksum]
+
+		// Changing the Secret rolls the frontend.
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(userSecret), userSecret))
+		userSecret.Data["config"] = []byte(`{"externalURL": "https://sourcegraph.example.org"}`)
+		require.NoError(t, c.Update(ctx, userSecret))
+		reconcileSpecConfigMap(t, r)
+		require.Equal(t, `{"externalURL": "https://sourcegraph.example.org"}`, siteConfigOf(t, c))
+		require.NotEqual(t, checksum, frontendDeployment(t, c).Spec.Template.Annotations[config.AnnotationKeyDependencyChecksum])
+
+		// A missing key is reported like an invalid site configuration.
+		delete(userSecret.Data, "config")
+		require.NoError(t, c.Update(ctx, userSecret))
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: renderedSpec})
+		require.Error(t, err)
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		requireCondition(t, frontendConditions(cm), config.ConditionSiteConfigValid, metav1.ConditionFalse,
+			"Not rolling out the frontend, since its site configuration is invalid: Secret site-config has no key config")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c, r := newReconciler(t, inline(siteConfig))
+		reconcileSpecConfigMap(t, r)
+		dep := frontendDeployment(t, c)
+
+		// The frontend keeps the last valid site configuration.
+		updateSpec(t, c, inline(`{"externalURL": 42}`))
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: renderedSpec})
+		require.ErrorContains(t, err, "invalid site configuration: externalURL: Invalid type. Expected: string, given: integer")
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(ctx, renderedSpec, &cm))
+		requireCondition(t, frontendConditions(cm), config.ConditionSiteConfigValid, metav1.ConditionFalse,
+			"Not rolling out the frontend, since its site configuration is invalid: externalURL: Invalid type. Expected: string, given: integer")
+		require.Equal(t, siteConfig, siteConfigOf(t, c))
+		require.Equal(t, dep.Spec.Template, frontendDeployment(t, c).Spec.Template)
+	})
+}
@@ -1,5 +1,15 @@
 package reconciler
 
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
 // Use this file to test features available in StandardConfig (see
 // development.md and config subpackage).
 
@@ -8,14 +18,20 @@ func (suite *ApplianceTestSuite) TestStandardFeatures() {
 		name string
 	}{
 		{name: "standard/blobstore-with-named-storage-class"},
+		{name: "standard/blobstore-with-tls"},
+		{name: "standard/frontend-with-strict-network-policies"},
 		{name: "standard/precise-code-intel-with-env-vars"},
 		{name: "standard/redis-with-multiple-custom-images"},
 		{name: "standard/redis-with-storage"},
+		{name: "standard/repo-updater-in-maintenance-mode"},
+		{name: "standard/repo-updater-with-custom-metadata"},
 		{name: "standard/repo-updater-with-no-resources"},
 		{name: "standard/repo-updater-with-pod-template-config"},
 		{name: "standard/repo-updater-with-resources"},
 		{name: "standard/repo-updater-with-sa-annotations"},
+		{name: "standard/repo-updater-with-security-context"},
 		{name: "standard/symbols-with-custom-image"},
+		{name: "standard/syntect-with-namespace-isolated-network-policies"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -32,3 +48,128 @@ func (suite *ApplianceTestSuite) TestResourcesDeletedWhenDisabled() {
 	suite.updateConfigMapAndAwaitReconciliation(namespace, "standard/everything-disabled")
 	suite.makeGoldenAssertions(namespace, "standard/blobstore-subsequent-disable")
 }
+
+func (suite *ApplianceTestSuite) TestNetworkPoliciesDeletedWhenDisabled() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("standard/frontend-with-strict-network-policies")
+
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "frontend/default")
+	suite.makeGoldenAssertions(namespace, "standard/frontend-network-policies-subsequent-disable")
+}
+
+func (suite *ApplianceTestSuite) TestCustomMetadataRemovedWhenUnset() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("standard/repo-updater-with-custom-metadata")
+
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "standard/repo-updater-with-sa-annotations")
+	suite.makeGoldenAssertions(namespace, "standard/repo-updater-custom-metadata-subsequent-removal")
+}
+
+// Replicas are restored when maintenance mode is disabled, and recorded again
+// when it is re-enabled.
+func (suite *ApplianceTestSuite) TestMaintenanceModeRestoresReplicas() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("standard/repo-updater-in-maintenance-mode")
+
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "repo-updater/default")
+	suite.makeGoldenAssertions(namespace, "standard/repo-updater-maintenance-mode-subsequent-disable")
+
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "standard/repo-updater-in-maintenance-mode")
+	suite.makeGoldenAssertions(namespace, "standard/repo-updater-in-maintenance-mode-reenabled")
+}
+
+// Every service should be deployable into a namespace that enforces the
+// "restricted" Pod Security Standard, except for cadvisor, which reads from the
+// host.
+func (suite *ApplianceTestSuite) TestDefaultsSatisfyRestrictedPodSecurity() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("standard/everything-enabled")
+
+	templates := map[string]corev1.PodTemplateSpec{}
+	deps, err := suite.k8sClient.AppsV1().Deployments(namespace).List(suite.ctx, metav1.ListOptions{})
+	suite.Require().NoError(err)
+	for _, obj := range deps.Items {
+		templates[obj.Name] = obj.Spec.Template
+	}
+	ssets, err := suite.k8sClient.AppsV1().StatefulSets(namespace).List(suite.ctx, metav1.ListOptions{})
+	suite.Require().NoError(err)
+	for _, obj := range ssets.Items {
+		templates[obj.Name] = obj.Spec.Template
+	}
+	daemonsets, err := suite.k8sClient.AppsV1().DaemonSets(namespace).List(suite.ctx, metav1.ListOptions{})
+	suite.Require().NoError(err)
+	for _, obj := range daemonsets.Items {
+		templates[obj.Name] = obj.Spec.Template
+	}
+	suite.Require().Contains(templates, "cadvisor")
+
+	for name, template := range templates {
+		violations := restrictedPodSecurityViolations(template.Spec)
+		if name == "cadvisor" {
+			suite.NotEmpty(violations, name)
+			continue
+		}
+		suite.Empty(violations, name)
+	}
+}
+
+// restrictedPodSecurityViolations reports the ways in which a pod spec fails
+// the "restricted" Pod Security Standard, as enforced by the PodSecurity
+// admission controller.
+// https://kubernetes.io/docs/concepts/security/pod-security-standards/#restricted
+func restrictedPodSecurityViolations(spec corev1.PodSpec) []string {
+	var violations []string
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		violations = append(violations, "uses host namespaces")
+	}
+	for _, vol := range spec.Volumes {
+		if vol.HostPath != nil {
+			violations = append(violations, fmt.Sprintf("volume %q is a hostPath", vol.Name))
+		}
+	}
+
+	podSC := spec.SecurityContext
+	if podSC == nil {
+		podSC = &corev1.PodSecurityContext{}
+	}
+	if pointers.Deref(podSC.RunAsUser, -1) == 0 {
+		violations = append(violations, "pod runs as root")
+	}
+
+	for _, ctr := range append(slices.Clone(spec.InitContainers), spec.Containers...) {
+		sc := ctr.SecurityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		if pointers.DerefZero(sc.Privileged) {
+			violations = append(violations, fmt.Sprintf("container %q is privileged", ctr.Name))
+		}
+		if pointers.Deref(sc.AllowPrivilegeEscalation, true) {
+			violations = append(violations, fmt.Sprintf("container %q allows privilege escalation", ctr.Name))
+		}
+		if !pointers.Deref(sc.RunAsNonRoot, pointers.DerefZero(podSC.RunAsNonRoot)) {
+			violations = append(violations, fmt.Sprintf("container %q does not set runAsNonRoot", ctr.Name))
+		}
+		if pointers.Deref(sc.RunAsUser, -1) == 0 {
+			violations = append(violations, fmt.Sprintf("container %q runs as root", ctr.Name))
+		}
+
+		seccomp := sc.SeccompProfile
+		if seccomp == nil {
+			seccomp = podSC.SeccompProfile
+		}
+		if seccomp == nil || (seccomp.Type != corev1.SeccompProfileTypeRuntimeDefault && seccomp.Type != corev1.SeccompProfileTypeLocalhost) {
+			violations = append(violations, fmt.Sprintf("container %q has no seccomp profile", ctr.Name))
+		}
+
+		caps := sc.Capabilities
+		if caps == nil {
+			caps = &corev1.Capabilities{}
+		}
+		if !slices.Contains(caps.Drop, "ALL") {
+			violations = append(violations, fmt.Sprintf("container %q does not drop ALL capabilities", ctr.Name))
+		}
+		for _, c := range caps.Add {
+			if c != "NET_BIND_SERVICE" {
+				violations = append(violations, fmt.Sprintf("container %q adds capability %s", ctr.Name, c))
+			}
+		}
+	}
+	return violations
+}
@@ -0,0 +1,306 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// rolloutPollInterval is how often the appliance checks on services that are
+// still rolling out. Their workloads don't trigger reconciles themselves, as
+// they lack the managed annotation.
+const rolloutPollInterval = 15 * time.Second
+
+// workload is a Deployment, StatefulSet, or DaemonSet that runs a service.
+type workload struct {
+	kind string
+	name string
+}
+
+func deploymentWorkload(name string) workload  { return workload{kind: "Deployment", name: name} }
+func statefulSetWorkload(name string) workload { return workload{kind: "StatefulSet", name: name} }
+func daemonSetWorkload(name string) workload   { return workload{kind: "DaemonSet", name: name} }
+
+// prefixedWorkloads returns workloads with the NamePrefix of spec applied to
+// their names.
+func prefixedWorkloads(spec config.SourcegraphSpec, workloads []workload) []workload {
+	prefixed := make([]workload, 0, len(workloads))
+	for _, w := range workloads {
+		prefixed = append(prefixed, workload{kind: w.kind, name: spec.ObjectName(w.name)})
+	}
+	return prefixed
+}
+
+// statusFromAnnotations returns the status recorded on the spec ConfigMap by
+// the last reconcile. A status that can't be parsed, e.g. because it was
+// edited by hand, is discarded, as it is rebuilt anyway.
+func statusFromAnnotations(annotations map[string]string) config.SourcegraphStatus {
+	var status config.SourcegraphStatus
+	if data, ok := annotations[config.AnnotationKeyStatus]; ok {
+		if err := json.Unmarshal([]byte(data), &status); err != nil {
+			return config.SourcegraphStatus{}
+		}
+	}
+	return status
+}
+
+// setStatusAnnotations records status on the spec ConfigMap.
+func setStatusAnnotations(cm *corev1.ConfigMap, status config.SourcegraphStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return errors.Wrap(err, "marshalling status")
+	}
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[config.AnnotationKeyStatus] = string(data)
+	ready := metav1.ConditionFalse
+	if meta.IsStatusConditionTrue(status.Conditions, config.ConditionReady) {
+		ready = metav1.ConditionTrue
+	}
+	cm.Annotations[config.AnnotationKeyReady] = string(ready)
+	return nil
+}
+
+// setReadyCondition sets the Ready condition from the conditions of the
+// services, and reports whether it is true.
+func setReadyCondition(status *config.SourcegraphStatus) bool {
+	var notReady []string
+	for _, svc := range status.Services {
+		available := meta.FindStatusCondition(svc.Conditions, config.ConditionAvailable)
+		if !meta.IsStatusConditionTrue(svc.Conditions, config.ConditionReconciled) ||
+			(available != nil && available.Status != metav1.ConditionTrue) ||
+			meta.IsStatusConditionFalse(svc.Conditions, config.ConditionChangesApplied) {
+			notReady = append(notReady, svc.Name)
+		}
+	}
+	if len(notReady) > 0 {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:    config.ConditionReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  config.ReasonServicesNotReady,
+			Message: "Services not ready: " + strings.Join(notReady, ", "),
+		})
+		return false
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:   config.ConditionReady,
+		Status: metav1.ConditionTrue,
+		Reason: config.ReasonServicesReady,
+	})
+	return true
+}
+
+// setSpecFieldsCondition records the warnings about the fields of the spec.
+func setSpecFieldsCondition(status *config.SourcegraphStatus, warnings []config.FieldWarning) {
+	if len(warnings) == 0 {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:   config.ConditionSpecFieldsRecognized,
+			Status: metav1.ConditionTrue,
+			Reason: config.ReasonFieldsRecognized,
+		})
+		return
+	}
+	reason := config.ReasonDeprecatedFields
+	messages := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		if !w.Deprecated {
+			reason = config.ReasonUnknownFields
+		}
+		messages = append(messages, w.String())
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    config.ConditionSpecFieldsRecognized,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: strings.Join(messages, "; "),
+	})
+}
+
+// setReconciledCondition records the outcome of reconciling a service.
+func setReconciledCondition(svc *config.ServiceStatus, err error) {
+	if err != nil {
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:    config.ConditionReconciled,
+			Status:  metav1.ConditionFalse,
+			Reason:  config.ReasonReconcileFailed,
+			Message: err.Error(),
+		})
+		return
+	}
+	meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+		Type:   config.ConditionReconciled,
+		Status: metav1.ConditionTrue,
+		Reason: config.ReasonReconcileSucceeded,
+	})
+}
+
+// setAvailableCondition records whether the workloads of a service have rolled
+// out. Workloads that don't exist, e.g. because the service is disabled, are
+// ignored, and the condition is removed if none exist.
+func (r *Reconciler) setAvailableCondition(ctx context.Context, svc *config.ServiceStatus, namespace string, workloads []workload) error {
+	var found bool
+	var problems []string
+	for _, w := range workloads {
+		exists, wProblems, err := r.workloadRolloutProblems(ctx, namespace, w)
+		if err != nil {
+			return errors.Wrapf(err, "getting %s %s", w.kind, w.name)
+		}
+		found = found || exists
+		problems = append(problems, wProblems...)
+	}
+
+	switch {
+	case !found:
+		meta.RemoveStatusCondition(&svc.Conditions, config.ConditionAvailable)
+	case len(problems) > 0:
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:    config.ConditionAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  config.ReasonRolloutInProgress,
+			Message: strings.Join(problems, "; "),
+		})
+	default:
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:   config.ConditionAvailable,
+			Status: metav1.ConditionTrue,
+			Reason: config.ReasonRolloutComplete,
+		})
+	}
+	return nil
+}
+
+// workloadRolloutProblems returns why a workload hasn't rolled out yet,
+// including any of its PersistentVolumeClaims that aren't bound, which keep
+// its pods from being scheduled.
+func (r *Reconciler) workloadRolloutProblems(ctx context.Context, namespace string, w workload) (exists bool, problems []string, err error) {
+	key := types.NamespacedName{Namespace: namespace, Name: w.name}
+	var problem string
+	var template corev1.PodTemplateSpec
+	var claims []string
+	switch w.kind {
+	case "Deployment":
+		var dep appsv1.Deployment
+		if err := r.Get(ctx, key, &dep); err != nil {
+			return false, nil, client.IgnoreNotFound(err)
+		}
+		problem = deploymentRolloutProblem(&dep)
+		template = dep.Spec.Template
+	case "StatefulSet":
+		var sset appsv1.StatefulSet
+		if err := r.Get(ctx, key, &sset); err != nil {
+			return false, nil, client.IgnoreNotFound(err)
+		}
+		problem = statefulSetRolloutProblem(&sset)
+		template = sset.Spec.Template
+		for _, claim := range sset.Spec.VolumeClaimTemplates {
+			for i := int32(0); i < pointers.Deref(sset.Spec.Replicas, 1); i++ {
+				claims = append(claims, fmt.Sprintf("%s-%s-%d", claim.Name, sset.Name, i))
+			}
+		}
+	case "DaemonSet":
+		var ds appsv1.DaemonSet
+		if err := r.Get(ctx, key, &ds); err != nil {
+			return false, nil, client.IgnoreNotFound(err)
+		}
+		problem = daemonSetRolloutProblem(&ds)
+		template = ds.Spec.Template
+	default:
+		return false, nil, errors.Newf("unknown workload kind %q", w.kind)
+	}
+	if problem == "" {
+		return true, nil, nil
+	}
+	problems = append(problems, fmt.Sprintf("%s %s: %s", w.kind, w.name, problem))
+
+	for _, vol := range template.Spec.Volumes {
+		if vol.PersistentVolumeClaim != nil {
+			claims = append(claims, vol.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	for _, claim := range claims {
+		var pvc corev1.PersistentVolumeClaim
+		if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: claim}, &pvc); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return true, nil, err
+		}
+		if pvc.Status.Phase != corev1.ClaimBound {
+			phase := pvc.Status.Phase
+			if phase == "" {
+				phase = corev1.ClaimPending
+			}
+			problems = append(problems, fmt.Sprintf("PersistentVolumeClaim %s is %s", claim, phase))
+		}
+	}
+	return true, problems, nil
+}
+
+// deploymentRolloutProblem returns why a Deployment hasn't rolled out, or ""
+// if it has. It mirrors kubectl rollout status.
+func deploymentRolloutProblem(dep *appsv1.Deployment) string {
+	replicas := pointers.Deref(dep.Spec.Replicas, 1)
+	status := dep.Status
+	switch {
+	case status.ObservedGeneration < dep.Generation:
+		return "waiting for the latest spec to be observed"
+	case status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas are updated", status.UpdatedReplicas, replicas)
+	case status.Replicas > status.UpdatedReplicas:
+		return fmt.Sprintf("%d old replicas are pending termination", status.Replicas-status.UpdatedReplicas)
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		return fmt.Sprintf("%d of %d updated replicas are available", status.AvailableReplicas, status.UpdatedReplicas)
+	}
+	return ""
+}
+
+// statefulSetRolloutProblem returns why a StatefulSet hasn't rolled out, or ""
+// if it has. With a partition, only the pods with an ordinal at or above it
+// are expected to be updated.
+func statefulSetRolloutProblem(sset *appsv1.StatefulSet) string {
+	replicas := pointers.Deref(sset.Spec.Replicas, 1)
+	toUpdate := replicas
+	if rollingUpdate := sset.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil {
+		toUpdate = max(replicas-pointers.Deref(rollingUpdate.Partition, 0), 0)
+	}
+	status := sset.Status
+	switch {
+	case status.ObservedGeneration < sset.Generation:
+		return "waiting for the latest spec to be observed"
+	case sset.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType && status.UpdatedReplicas < toUpdate:
+		return fmt.Sprintf("%d of %d replicas are updated", status.UpdatedReplicas, toUpdate)
+	case status.ReadyReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas are ready", status.ReadyReplicas, replicas)
+	}
+	return ""
+}
+
+// daemonSetRolloutProblem returns why a DaemonSet hasn't rolled out, or "" if
+// it has.
+func daemonSetRolloutProblem(ds *appsv1.DaemonSet) string {
+	status := ds.Status
+	switch {
+	case status.ObservedGeneration < ds.Generation:
+		return "waiting for the latest spec to be observed"
+	case status.UpdatedNumberScheduled < status.DesiredNumberScheduled:
+		return fmt.Sprintf("%d of %d pods are updated", status.UpdatedNumberScheduled, status.DesiredNumberScheduled)
+	case status.NumberAvailable < status.DesiredNumberScheduled:
+		return fmt.Sprintf("%d of %d pods are available", status.NumberAvailable, status.DesiredNumberScheduled)
+	}
+	return ""
+}
@@ -0,0 +1,295 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func TestReconcileStatus(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(newSpecConfigMap(readSpecFixture(t, "repo-updater/default"))).Build()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}}
+
+	// The Deployment has been created, but not rolled out yet.
+	result, cm := reconcileSpecConfigMap(t, r)
+	require.Equal(t, rolloutPollInterval, result.RequeueAfter)
+	require.Equal(t, "False", cm.Annotations[config.AnnotationKeyReady])
+	status := statusFromAnnotations(cm.Annotations)
+	require.Equal(t, "5.3.9104", status.CurrentVersion)
+	requireCondition(t, status.Conditions, config.ConditionReady, metav1.ConditionFalse, "Services not ready: repo-updater")
+	repoUpdater := status.Service("repo-updater")
+	requireCondition(t, repoUpdater.Conditions, config.ConditionReconciled, metav1.ConditionTrue, "")
+	requireCondition(t, repoUpdater.Conditions, config.ConditionAvailable, metav1.ConditionFalse, "Deployment repo-updater: 0 of 1 replicas are updated")
+	reconciledAt := meta.FindStatusCondition(repoUpdater.Conditions, config.ConditionReconciled).LastTransitionTime
+
+	// Disabled services are reconciled, but have no workloads.
+	blobstore := status.Service("blobstore")
+	requireCondition(t, blobstore.Conditions, config.ConditionReconciled, metav1.ConditionTrue, "")
+	require.Nil(t, meta.FindStatusCondition(blobstore.Conditions, config.ConditionAvailable))
+
+	setDeploymentStatus(t, c, "repo-updater", appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1})
+	result, cm = reconcileSpecConfigMap(t, r)
+	require.Zero(t, result.RequeueAfter)
+	require.Equal(t, "True", cm.Annotations[config.AnnotationKeyReady])
+	status = statusFromAnnotations(cm.Annotations)
+	requireCondition(t, status.Conditions, config.ConditionReady, metav1.ConditionTrue, "")
+	repoUpdater = status.Service("repo-updater")
+	requireCondition(t, repoUpdater.Conditions, config.ConditionAvailable, metav1.ConditionTrue, "")
+	require.Equal(t, reconciledAt, meta.FindStatusCondition(repoUpdater.Conditions, config.ConditionReconciled).LastTransitionTime)
+
+	// Reconciling an unchanged deployment leaves the status as it was, so that
+	// the update of the ConfigMap doesn't trigger yet another reconcile.
+	_, unchanged := reconcileSpecConfigMap(t, r)
+	require.Equal(t, cm.Annotations[config.AnnotationKeyStatus], unchanged.Annotations[config.AnnotationKeyStatus])
+
+	// A pod of the service stops being available.
+	setDeploymentStatus(t, c, "repo-updater", appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 0})
+	result, cm = reconcileSpecConfigMap(t, r)
+	require.Equal(t, rolloutPollInterval, result.RequeueAfter)
+	require.Equal(t, "False", cm.Annotations[config.AnnotationKeyReady])
+	status = statusFromAnnotations(cm.Annotations)
+	requireCondition(t, status.Conditions, config.ConditionReady, metav1.ConditionFalse, "Services not ready: repo-updater")
+	requireCondition(t, status.Service("repo-updater").Conditions, config.ConditionAvailable, metav1.ConditionFalse, "Deployment repo-updater: 0 of 1 updated replicas are available")
+}
+
+func TestReconcileStatusFailedService(t *testing.T) {
+	c := fake.NewClientBuilder().
+		WithObjects(newSpecConfigMap(readSpecFixture(t, "repo-updater/default"))).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if _, ok := obj.(*corev1.Service); ok && obj.GetName() == "repo-updater" {
+					return errors.New("quota exceeded")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(newSpecConfigMap(nil))})
+	require.ErrorContains(t, err, "failed to reconcile repo updater")
+
+	// The status is recorded nonetheless, and the other services are
+	// reconciled.
+	var cm corev1.ConfigMap
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(newSpecConfigMap(nil)), &cm))
+	require.Equal(t, "False", cm.Annotations[config.AnnotationKeyReady])
+	require.NotContains(t, cm.Annotations, config.AnnotationKeyCurrentVersion)
+	status := statusFromAnnotations(cm.Annotations)
+	require.Empty(t, status.CurrentVersion)
+	requireCondition(t, status.Conditions, config.ConditionReady, metav1.ConditionFalse, "Services not ready: repo-updater")
+	reconciled := meta.FindStatusCondition(status.Service("repo-updater").Conditions, config.ConditionReconciled)
+	require.Equal(t, metav1.ConditionFalse, reconciled.Status)
+	require.Equal(t, config.ReasonReconcileFailed, reconciled.Reason)
+	require.Contains(t, reconciled.Message, "quota exceeded")
+	requireCondition(t, status.Service("network-policies").Conditions, config.ConditionReconciled, metav1.ConditionTrue, "")
+}
+
+func TestReconcileStatusInvalidSpec(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(newSpecConfigMap([]byte("spec:\n  requestedVersion: 0.0.1\n"))).Build()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}}
+
+	_, cm := reconcileSpecConfigMap(t, r)
+	require.Equal(t, "False", cm.Annotations[config.AnnotationKeyReady])
+	ready := meta.FindStatusCondition(statusFromAnnotations(cm.Annotations).Conditions, config.ConditionReady)
+	require.Equal(t, config.ReasonInvalidSpec, ready.Reason)
+	require.Equal(t, cm.Annotations[config.AnnotationKeyValidationErrors], ready.Message)
+}
+
+func TestReconcileStatusSpecFields(t *testing.T) {
+	spec := []byte("spec:\n  requestedVersion: \"5.3.9104\"\n  frontend:\n    replcias: 3\n")
+
+	c := fake.NewClientBuilder().WithObjects(newSpecConfigMap(spec)).Build()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}}
+	_, cm := reconcileSpecConfigMap(t, r)
+	status := statusFromAnnotations(cm.Annotations)
+	requireCondition(t, status.Conditions, config.ConditionSpecFieldsRecognized, metav1.ConditionFalse,
+		"spec.frontend.replcias: unknown field")
+	require.Empty(t, cm.Annotations[config.AnnotationKeyValidationErrors])
+
+	// In strict mode, the unknown field makes the spec invalid.
+	c = fake.NewClientBuilder().WithObjects(newSpecConfigMap(spec)).Build()
+	r = &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}, StrictSpecDecoding: true}
+	_, cm = reconcileSpecConfigMap(t, r)
+	status = statusFromAnnotations(cm.Annotations)
+	requireCondition(t, status.Conditions, config.ConditionSpecFieldsRecognized, metav1.ConditionFalse,
+		"spec.frontend.replcias: unknown field")
+	require.Equal(t, "unknown fields: spec.frontend.replcias", cm.Annotations[config.AnnotationKeyValidationErrors])
+	require.Equal(t, config.ReasonInvalidSpec, meta.FindStatusCondition(status.Conditions, config.ConditionReady).Reason)
+}
+
+func TestWorkloadRolloutProblems(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "gitserver", Namespace: renderedSpec.Namespace, Generation: 2}
+	c := fake.NewClientBuilder().WithObjects(
+		&appsv1.StatefulSet{
+			ObjectMeta: meta,
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: pointers.Ptr[int32](1),
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					{ObjectMeta: metav1.ObjectMeta{Name: "repos"}},
+				},
+			},
+			Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdatedReplicas: 1},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "repos-gitserver-0", Namespace: renderedSpec.Namespace},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		},
+	).Build()
+	r := &Reconciler{Client: c}
+
+	exists, problems, err := r.workloadRolloutProblems(context.Background(), renderedSpec.Namespace, statefulSetWorkload("gitserver"))
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, []string{
+		"StatefulSet gitserver: 0 of 1 replicas are ready",
+		"PersistentVolumeClaim repos-gitserver-0 is Pending",
+	}, problems)
+
+	exists, problems, err = r.workloadRolloutProblems(context.Background(), renderedSpec.Namespace, statefulSetWorkload("pgsql"))
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.Empty(t, problems)
+}
+
+func TestDeploymentRolloutProblem(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		generation int64
+		replicas   int32
+		status     appsv1.DeploymentStatus
+		want       string
+	}{
+		{
+			name:       "rolled out",
+			generation: 3,
+			replicas:   2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+		},
+		{
+			name:       "new spec not observed",
+			generation: 3,
+			replicas:   2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			want:       "waiting for the latest spec to be observed",
+		},
+		{
+			name:     "updating",
+			replicas: 2,
+			status:   appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 2},
+			want:     "1 of 2 replicas are updated",
+		},
+		{
+			name:     "old replicas terminating",
+			replicas: 2,
+			status:   appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2},
+			want:     "1 old replicas are pending termination",
+		},
+		{
+			name:     "crash looping",
+			replicas: 2,
+			status:   appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1},
+			want:     "1 of 2 updated replicas are available",
+		},
+		{
+			name:     "scaled to zero",
+			replicas: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dep := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: tc.generation},
+				Spec:       appsv1.DeploymentSpec{Replicas: pointers.Ptr(tc.replicas)},
+				Status:     tc.status,
+			}
+			require.Equal(t, tc.want, deploymentRolloutProblem(dep))
+		})
+	}
+}
+
+// newSpecConfigMap returns the appliance ConfigMap called renderedSpec, which
+// holds spec.
+func TestReconcileTracker(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(newSpecConfigMap(readSpecFixture(t, "repo-updater/default"))).Build()
+	tracker := NewReconcileTracker()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}, Tracker: tracker, ResyncInterval: 5 * time.Minute}
+
+	before := time.Now()
+	reconcileSpecConfigMap(t, r)
+	setDeploymentStatus(t, c, "repo-updater", appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1})
+
+	// Once every service is ready, the ConfigMap is reconciled again after
+	// the resync interval.
+	result, _ := reconcileSpecConfigMap(t, r)
+	require.Equal(t, 5*time.Minute, result.RequeueAfter)
+
+	results := tracker.Results()
+	require.Len(t, results, 1)
+	require.Equal(t, renderedSpec, results[0].ConfigMap)
+	require.NoError(t, results[0].Err)
+	require.False(t, results[0].Time.Before(before))
+	requireCondition(t, results[0].Status.Conditions, config.ConditionReady, metav1.ConditionTrue, "")
+
+	// A deleted ConfigMap is forgotten, once its deletion is confirmed.
+	var cm corev1.ConfigMap
+	require.NoError(t, c.Get(context.Background(), renderedSpec, &cm))
+	cm.Annotations[config.AnnotationKeyConfirmDeletion] = "true"
+	require.NoError(t, c.Update(context.Background(), &cm))
+	require.NoError(t, c.Delete(context.Background(), &cm))
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: renderedSpec})
+	require.NoError(t, err)
+	require.Empty(t, tracker.Results())
+}
+
+func newSpecConfigMap(spec []byte) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        renderedSpec.Name,
+			Namespace:   renderedSpec.Namespace,
+			Annotations: map[string]string{config.AnnotationKeyManaged: "true"},
+		},
+		Data: map[string]string{"spec": string(spec)},
+	}
+}
+
+func reconcileSpecConfigMap(t *testing.T, r *Reconciler) (ctrl.Result, corev1.ConfigMap) {
+	t.Helper()
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: renderedSpec})
+	require.NoError(t, err)
+	var cm corev1.ConfigMap
+	require.NoError(t, r.Get(context.Background(), renderedSpec, &cm))
+	return result, cm
+}
+
+func setDeploymentStatus(t *testing.T, c client.Client, name string, status appsv1.DeploymentStatus) {
+	t.Helper()
+	var dep appsv1.Deployment
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: renderedSpec.Namespace, Name: name}, &dep))
+	status.ObservedGeneration = dep.Generation
+	dep.Status = status
+	require.NoError(t, c.Status().Update(context.Background(), &dep))
+}
+
+func requireCondition(t *testing.T, conditions []metav1.Condition, conditionType string, status metav1.ConditionStatus, message string) {
+	t.Helper()
+	condition := meta.FindStatusCondition(conditions, conditionType)
+	require.NotNil(t, condition, "missing condition %s", conditionType)
+	require.Equal(t, status, condition.Status)
+	require.Equal(t, message, condition.Message)
+}
@@ -3,7 +3,6 @@ package reconciler
 import (
 	"context"
 	"fmt"
-	"math"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -16,15 +15,16 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pvc"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/statefulset"
-	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func (r *Reconciler) reconcileSymbols(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	if err := r.reconcileSymbolsStatefulSet(ctx, sg, owner); err != nil {
 		return err
 	}
+	if err := r.reconcileHorizontalPodAutoscaler(ctx, sg, owner, sg.Spec.ObjectName("symbols"), "StatefulSet", sg.Spec.Symbols, sg.Spec.Symbols.Autoscaling); err != nil {
+		return err
+	}
 	if err := r.reconcileSymbolsService(ctx, sg, owner); err != nil {
 		return err
 	}
@@ -56,16 +56,12 @@ func (r *Reconciler) reconcileSymbolsStatefulSet(ctx context.Context, sg *config
 		},
 	})
 
-	storageSize, err := resource.ParseQuantity(cfg.GetPersistentVolumeConfig().StorageSize)
+	cacheSizeMB, err := cfg.GetCacheSizeMB()
 	if err != nil {
-		return errors.Wrap(err, "parsing storage size")
+		return err
 	}
 
-	// Cache size is 90% of available attached storage
-	cacheSize := float64(storageSize.Value()) * 0.9
-	cacheSizeMB := int(math.Floor(cacheSize / 1024 / 1024))
-
-	ctr.Env = append(ctr.Env, container.EnvVarsRedis()...)
+	ctr.Env = append(ctr.Env, redisEnvVars(sg)...)
 	ctr.Env = append(
 		ctr.Env,
 		corev1.EnvVar{Name: "SYMBOLS_CACHE_SIZE_MB", Value: fmt.Sprintf("%d", cacheSizeMB)},
@@ -75,7 +71,19 @@ func (r *Reconciler) reconcileSymbolsStatefulSet(ctx context.Context, sg *config
 
 		corev1.EnvVar{Name: "TMPDIR", Value: "/mnt/tmp"},
 	)
-	ctr.Env = append(ctr.Env, container.EnvVarsOtel()...)
+	if rockskip := cfg.Rockskip; rockskip != nil && rockskip.Enabled {
+		ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "USE_ROCKSKIP", Value: "true"})
+		if rockskip.MinRepoSizeMB != nil {
+			ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "ROCKSKIP_MIN_REPO_SIZE_MB", Value: fmt.Sprintf("%d", *rockskip.MinRepoSizeMB)})
+		}
+		if rockskip.MaxConcurrentlyIndexing != nil {
+			ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "MAX_CONCURRENTLY_INDEXING", Value: fmt.Sprintf("%d", *rockskip.MaxConcurrentlyIndexing)})
+		}
+		// Rockskip stores its indexes in the codeintel database.
+		ctr.Env = append(ctr.Env, gateCodeIntel.clientEnvVars(sg)...)
+	}
+	ctr.Env = append(ctr.Env, otelEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, serviceEndpointEnvVars(sg)...)
 
 	ctr.Ports = []corev1.ContainerPort{
 		{Name: "http", ContainerPort: 3184},
@@ -108,9 +116,9 @@ func (r *Reconciler) reconcileSymbolsStatefulSet(ctx context.Context, sg *config
 		{Name: "tmp", MountPath: "/mnt/tmp"},
 	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
 	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
-	podTemplate.Template.Spec.ServiceAccountName = name
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
 	podTemplate.Template.Spec.Volumes = []corev1.Volume{
 		{Name: "cache"},
 		pod.NewVolumeEmptyDir("tmp"),
@@ -121,7 +129,17 @@ func (r *Reconciler) reconcileSymbolsStatefulSet(ctx context.Context, sg *config
 		return err
 	}
 
-	sset := statefulset.NewStatefulSet(name, sg.Namespace, sg.Spec.RequestedVersion)
+	applyTopologySpread(&podTemplate.Template, cfg, cfg.Autoscaling.MaxReplicasOr(cfg.Replicas))
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	sset := statefulset.NewStatefulSet(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	sset.Spec.Replicas, err = r.replicasFor(ctx, &sset, &appsv1.StatefulSet{}, cfg.Replicas, cfg.Autoscaling)
+	if err != nil {
+		return err
+	}
+	applyStatefulSetStrategy(&sset, cfg)
 	sset.Spec.Template = podTemplate.Template
 	sset.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{pvc}
 
@@ -129,19 +147,17 @@ func (r *Reconciler) reconcileSymbolsStatefulSet(ctx context.Context, sg *config
 }
 
 func (r *Reconciler) reconcileSymbolsService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	svc := service.NewService("symbols", sg.Namespace, sg.Spec.RepoUpdater)
+	svc := service.NewService(sg.Spec.ObjectName("symbols"), sg.Namespace, sg.Spec.RepoUpdater)
 	svc.Spec.Ports = []corev1.ServicePort{
 		{Name: "http", TargetPort: intstr.FromString("http"), Port: 3184},
 		{Name: "debug", TargetPort: intstr.FromString("debug"), Port: 6060},
 	}
 	svc.Spec.Selector = map[string]string{
-		"app": "symbols",
+		"app": sg.Spec.ObjectName("symbols"),
 	}
 	return reconcileObject(ctx, r, sg.Spec.Symbols, &svc, &corev1.Service{}, sg, owner)
 }
 
 func (r *Reconciler) reconcileSymbolsServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.Symbols
-	sa := serviceaccount.NewServiceAccount("symbols", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, sg.Spec.Symbols, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("symbols"), sg.Spec.Symbols)
 }
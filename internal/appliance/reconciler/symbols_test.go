@@ -9,6 +9,7 @@ func (suite *ApplianceTestSuite) TestDeploySymbols() {
 		// This service does some logic on the storage quantity, so we can't
 		// just rely on the standard config test for storage amounts/classes.
 		{name: "symbols/with-storage"},
+		{name: "symbols/with-rockskip"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -2,6 +2,8 @@ package reconciler
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -14,15 +16,19 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
-	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 func (r *Reconciler) reconcileSyntect(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	if err := r.reconcileSyntectDeployment(ctx, sg, owner); err != nil {
 		return errors.Wrap(err, "reconciling Deployment")
 	}
+	if err := r.reconcileHorizontalPodAutoscaler(ctx, sg, owner, sg.Spec.ObjectName("syntect-server"), "Deployment", sg.Spec.SyntectServer, sg.Spec.SyntectServer.Autoscaling); err != nil {
+		return errors.Wrap(err, "reconciling HorizontalPodAutoscaler")
+	}
+	if err := r.reconcilePodDisruptionBudget(ctx, sg, owner, sg.Spec.ObjectName("syntect-server"), sg.Spec.SyntectServer, sg.Spec.SyntectServer.Autoscaling.MaxReplicasOr(sg.Spec.SyntectServer.Replicas)); err != nil {
+		return errors.Wrap(err, "reconciling PodDisruptionBudget")
+	}
 	if err := r.reconcileSyntectService(ctx, sg, owner); err != nil {
 		return errors.Wrap(err, "reconciling Service")
 	}
@@ -53,6 +59,11 @@ func (r *Reconciler) reconcileSyntectDeployment(ctx context.Context, sg *config.
 			},
 		},
 	})
+	tuningEnv, err := syntectTuningEnvVars(cfg)
+	if err != nil {
+		return err
+	}
+	ctr.Env = append(ctr.Env, tuningEnv...)
 	ctr.Ports = []corev1.ContainerPort{
 		{Name: "http", ContainerPort: 9238},
 	}
@@ -74,15 +85,22 @@ func (r *Reconciler) reconcileSyntectDeployment(ctx context.Context, sg *config.
 		},
 	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
 	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
-	podTemplate.Template.Spec.ServiceAccountName = name
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName(name)
 
-	dep := deployment.NewDeployment(name, sg.Namespace, sg.Spec.RequestedVersion)
-	dep.Spec.Replicas = pointers.Ptr(cfg.Replicas)
-	dep.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{
-		MaxSurge:       pointers.Ptr(intstr.FromInt(1)),
-		MaxUnavailable: pointers.Ptr(intstr.FromInt(0)),
+	applyTopologySpread(&podTemplate.Template, cfg, cfg.Autoscaling.MaxReplicasOr(cfg.Replicas))
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return err
+	}
+
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
+	dep.Spec.Replicas, err = r.replicasFor(ctx, &dep, &appsv1.Deployment{}, cfg.Replicas, cfg.Autoscaling)
+	if err != nil {
+		return err
+	}
+	if err := applyDeploymentStrategy(&dep, cfg); err != nil {
+		return err
 	}
 	dep.Spec.Template = podTemplate.Template
 
@@ -93,19 +111,38 @@ func (r *Reconciler) reconcileSyntectService(ctx context.Context, sg *config.Sou
 	name := "syntect-server"
 	cfg := sg.Spec.SyntectServer
 
-	svc := service.NewService(name, sg.Namespace, cfg)
+	svc := service.NewService(sg.Spec.ObjectName(name), sg.Namespace, cfg)
 	svc.Spec.Ports = []corev1.ServicePort{
 		{Name: "http", Port: 9238, TargetPort: intstr.FromString("http")},
 	}
 	svc.Spec.Selector = map[string]string{
-		"app": "syntect-server",
+		"app": sg.Spec.ObjectName("syntect-server"),
 	}
 
 	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
 }
 
 func (r *Reconciler) reconcileSyntectServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.SyntectServer
-	sa := serviceaccount.NewServiceAccount("syntect-server", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, cfg, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("syntect-server"), sg.Spec.SyntectServer)
+}
+
+// syntectTuningEnvVars returns the env vars that override the defaults of the
+// syntect-server image, for the settings that are configured.
+func syntectTuningEnvVars(cfg config.SyntectServerSpec) ([]corev1.EnvVar, error) {
+	var env []corev1.EnvVar
+	if n := cfg.NumWorkers; n != nil {
+		env = append(env, corev1.EnvVar{Name: "WORKERS", Value: strconv.Itoa(int(*n))})
+	}
+	if cfg.RequestTimeout != "" {
+		env = append(env, corev1.EnvVar{Name: "TIMEOUT", Value: cfg.RequestTimeout})
+	}
+	if cfg.MaxFileSize != "" {
+		maxFileSize, err := resource.ParseQuantity(cfg.MaxFileSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing max file size")
+		}
+		// Files are sent to syntect-server in JSON request bodies.
+		env = append(env, corev1.EnvVar{Name: "ROCKET_LIMITS", Value: fmt.Sprintf("{json=%d}", maxFileSize.Value())})
+	}
+	return env, nil
 }
@@ -6,6 +6,8 @@ func (suite *ApplianceTestSuite) TestDeploySyntect() {
 	}{
 		{name: "syntect/default"},
 		{name: "syntect/with-replicas"},
+		{name: "syntect/with-autoscaling"},
+		{name: "syntect/with-worker-tuning"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -13,3 +15,15 @@ func (suite *ApplianceTestSuite) TestDeploySyntect() {
 		})
 	}
 }
+
+func (suite *ApplianceTestSuite) TestSyntectAutoscalingTuned() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("syntect/with-autoscaling")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "syntect/with-tuned-autoscaling")
+	suite.makeGoldenAssertions(namespace, "syntect/subsequent-tuned-autoscaling")
+}
+
+func (suite *ApplianceTestSuite) TestSyntectAutoscalerDeletedWhenDisabled() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("syntect/with-autoscaling")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "syntect/default")
+	suite.makeGoldenAssertions(namespace, "syntect/subsequent-autoscaling-disabled")
+}
@@ -0,0 +1,101 @@
+package reconciler
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+const (
+	tlsMountPath   = "/etc/sourcegraph/tls"
+	tlsCAMountPath = "/etc/sourcegraph/tls-ca"
+
+	// Go and OpenSSL both read every certificate in these directories, so
+	// listing the system directory first adds our CAs to the system trust
+	// bundle rather than replacing it.
+	tlsCertDirs = "/etc/ssl/certs:" + tlsCAMountPath
+)
+
+// serviceScheme returns the URL scheme that consumers should use to reach the
+// service configured by cfg.
+func serviceScheme(sg *config.Sourcegraph, cfg config.StandardComponent) string {
+	if sg.Spec.TLSSecretName(cfg) != "" {
+		return "https"
+	}
+	return "http"
+}
+
+// applyTLS mounts the certificate configured for cfg, if any, into every
+// container in template and points the services at it. If any service in the
+// spec serves HTTPS, it also adds the CAs of all certificates in use to the
+// containers' trust bundle so that they can reach those services. The spec's
+// trusted CA certificates are always added to the trust bundle.
+func applyTLS(template *corev1.PodTemplateSpec, sg *config.Sourcegraph, cfg config.StandardComponent) {
+	secretNames := sg.Spec.TLSSecretNames()
+	trustedCACerts := sg.Spec.TrustedCACertsConfigMapRef
+	if len(secretNames) == 0 && trustedCACerts == nil {
+		return
+	}
+
+	caSources := make([]corev1.VolumeProjection, 0, len(secretNames)+1)
+	if trustedCACerts != nil {
+		caSources = append(caSources, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: *trustedCACerts},
+		})
+	}
+	for _, name := range secretNames {
+		caSources = append(caSources, corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Items: []corev1.KeyToPath{
+					{Key: "ca.crt", Path: name + ".crt"},
+				},
+				// Certificates signed by a public CA don't need to ship one.
+				Optional: pointers.Ptr(true),
+			},
+		})
+	}
+	template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+		Name: "tls-ca",
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{Sources: caSources},
+		},
+	})
+
+	secretName := sg.Spec.TLSSecretName(cfg)
+	if secretName != "" {
+		template.Spec.Volumes = append(template.Spec.Volumes, pod.NewVolumeFromSecret("tls", secretName))
+	}
+
+	for i := range template.Spec.Containers {
+		ctr := &template.Spec.Containers[i]
+		ctr.VolumeMounts = append(ctr.VolumeMounts, corev1.VolumeMount{
+			Name: "tls-ca", MountPath: tlsCAMountPath, ReadOnly: true,
+		})
+		ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: tlsCertDirs})
+
+		if secretName == "" {
+			continue
+		}
+		ctr.VolumeMounts = append(ctr.VolumeMounts, corev1.VolumeMount{
+			Name: "tls", MountPath: tlsMountPath, ReadOnly: true,
+		})
+		ctr.Env = append(
+			ctr.Env,
+			corev1.EnvVar{Name: "SRC_TLS_CERT_FILE", Value: path.Join(tlsMountPath, "tls.crt")},
+			corev1.EnvVar{Name: "SRC_TLS_KEY_FILE", Value: path.Join(tlsMountPath, "tls.key")},
+		)
+
+		// Only the main listener serves HTTPS, so only probes against it
+		// need to switch scheme.
+		for _, probe := range []*corev1.Probe{ctr.LivenessProbe, ctr.ReadinessProbe, ctr.StartupProbe} {
+			if probe != nil && probe.HTTPGet != nil && probe.HTTPGet.Port.StrVal == "http" {
+				probe.HTTPGet.Scheme = corev1.URISchemeHTTPS
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// applyTopologySpread sets the topology spread constraints of a service's pod
+// template, which must already carry its app label. Unless configured
+// otherwise, a service that may run more than one replica is spread across nodes
+// where possible, so that losing a node doesn't take out all of its replicas.
+func applyTopologySpread(template *corev1.PodTemplateSpec, cfg config.StandardComponent, replicas int32) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": template.Labels["app"]}}
+
+	constraints := cfg.GetTopologySpreadConstraints()
+	if constraints == nil {
+		if replicas <= 1 {
+			return
+		}
+		template.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelHostname,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     selector,
+		}}
+		return
+	}
+
+	template.Spec.TopologySpreadConstraints = nil
+	for _, c := range constraints {
+		c = *c.DeepCopy()
+		if c.LabelSelector == nil {
+			c.LabelSelector = selector.DeepCopy()
+		}
+		template.Spec.TopologySpreadConstraints = append(template.Spec.TopologySpreadConstraints, c)
+	}
+}
+
+// listNodesForSchedulingCheck returns the cluster's nodes if
+// SourcegraphSpec.CheckSchedulableNodes is set, and nil otherwise.
+func (r *Reconciler) listNodesForSchedulingCheck(ctx context.Context, sg *config.Sourcegraph) (*corev1.NodeList, error) {
+	if !sg.Spec.CheckSchedulableNodes {
+		return nil, nil
+	}
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		return &nodes, errors.Wrap(err, "listing nodes")
+	}
+	return &nodes, nil
+}
+
+// setSchedulableCondition records whether the workloads of a service that run
+// more than one replica have a node to schedule each replica on. nodes is nil
+// if the check is disabled, and nodesErr is why they couldn't be listed. The
+// condition is removed if the check is disabled or there is nothing to check.
+func (r *Reconciler) setSchedulableCondition(ctx context.Context, svc *config.ServiceStatus, namespace string, workloads []workload, nodes *corev1.NodeList, nodesErr error) error {
+	if nodes == nil {
+		meta.RemoveStatusCondition(&svc.Conditions, config.ConditionSchedulable)
+		return nil
+	}
+
+	var checked bool
+	var problems []string
+	for _, w := range workloads {
+		var replicas int32
+		var template corev1.PodTemplateSpec
+		key := types.NamespacedName{Namespace: namespace, Name: w.name}
+		switch w.kind {
+		case "Deployment":
+			var dep appsv1.Deployment
+			if err := r.Get(ctx, key, &dep); err != nil {
+				if err := client.IgnoreNotFound(err); err != nil {
+					return errors.Wrapf(err, "getting %s %s", w.kind, w.name)
+				}
+				continue
+			}
+			replicas, template = pointers.Deref(dep.Spec.Replicas, 1), dep.Spec.Template
+		case "StatefulSet":
+			var sset appsv1.StatefulSet
+			if err := r.Get(ctx, key, &sset); err != nil {
+				if err := client.IgnoreNotFound(err); err != nil {
+					return errors.Wrapf(err, "getting %s %s", w.kind, w.name)
+				}
+				continue
+			}
+			replicas, template = pointers.Deref(sset.Spec.Replicas, 1), sset.Spec.Template
+		default:
+			// DaemonSets run one pod per node by definition.
+			continue
+		}
+		if replicas <= 1 {
+			continue
+		}
+
+		checked = true
+		if nodesErr != nil {
+			continue
+		}
+		if schedulable := countSchedulableNodes(nodes.Items, &template.Spec); replicas > schedulable {
+			problems = append(problems, fmt.Sprintf("%s %s: %d replicas requested, but only %d nodes are schedulable", w.kind, w.name, replicas, schedulable))
+		}
+	}
+
+	switch {
+	case !checked:
+		meta.RemoveStatusCondition(&svc.Conditions, config.ConditionSchedulable)
+	case nodesErr != nil:
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:    config.ConditionSchedulable,
+			Status:  metav1.ConditionUnknown,
+			Reason:  config.ReasonNodesUnknown,
+			Message: nodesErr.Error(),
+		})
+	case len(problems) > 0:
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:    config.ConditionSchedulable,
+			Status:  metav1.ConditionFalse,
+			Reason:  config.ReasonInsufficientNodes,
+			Message: strings.Join(problems, "; "),
+		})
+	default:
+		meta.SetStatusCondition(&svc.Conditions, metav1.Condition{
+			Type:   config.ConditionSchedulable,
+			Status: metav1.ConditionTrue,
+			Reason: config.ReasonEnoughNodes,
+		})
+	}
+	return nil
+}
+
+// countSchedulableNodes returns how many nodes pods with spec could be
+// scheduled on: nodes that are ready, not cordoned, match the node selector,
+// and have no taints that the pods don't tolerate. Node affinity and free
+// resources aren't taken into account.
+func countSchedulableNodes(nodes []corev1.Node, spec *corev1.PodSpec) int32 {
+	selector := labels.SelectorFromSet(spec.NodeSelector)
+	var count int32
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !nodeReady(&node) || !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if !toleratesTaints(spec.Tolerations, node.Spec.Taints) {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// toleratesTaints reports whether tolerations tolerate every taint that keeps
+// pods from being scheduled.
+func toleratesTaints(tolerations []corev1.Toleration, taints []corev1.Taint) bool {
+	for _, taint := range taints {
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range tolerations {
+			if toleration.ToleratesTaint(&taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,185 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestTopologySpread(t *testing.T) {
+	objs, err := Render(context.Background(), renderedSpec, []byte(`
+spec:
+  requestedVersion: "5.3.9104"
+  frontend:
+    topologySpreadConstraints: []
+  gitServer:
+    topologySpreadConstraints:
+      - maxSkew: 1
+        topologyKey: topology.kubernetes.io/zone
+        whenUnsatisfiable: DoNotSchedule
+  worker:
+    replicas: 3
+`))
+	require.NoError(t, err)
+
+	constraints := map[string][]corev1.TopologySpreadConstraint{}
+	for _, obj := range objs {
+		u := obj.(*unstructured.Unstructured)
+		switch u.GetKind() {
+		case "Deployment":
+			var dep appsv1.Deployment
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &dep))
+			constraints[u.GetName()] = dep.Spec.Template.Spec.TopologySpreadConstraints
+		case "StatefulSet":
+			var sset appsv1.StatefulSet
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &sset))
+			constraints[u.GetName()] = sset.Spec.Template.Spec.TopologySpreadConstraints
+		}
+	}
+
+	// Services with more than one replica are spread across nodes by
+	// default.
+	require.Equal(t, []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       corev1.LabelHostname,
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "worker"}},
+	}}, constraints["worker"])
+	require.Len(t, constraints["precise-code-intel-worker"], 1)
+
+	// Configured constraints replace the default, and select the service's
+	// pods unless they say otherwise.
+	require.Equal(t, []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "gitserver"}},
+	}}, constraints["gitserver"])
+	require.Empty(t, constraints["sourcegraph-frontend"])
+
+	// Services with a single replica aren't spread.
+	require.Contains(t, constraints, "repo-updater")
+	require.Empty(t, constraints["repo-updater"])
+}
+
+func TestReconcileSchedulableCondition(t *testing.T) {
+	spec := append(readSpecFixture(t, "frontend/default"), []byte("\n  checkSchedulableNodes: true\n")...)
+	c := fake.NewClientBuilder().WithObjects(
+		newSpecConfigMap(spec),
+		newNode("ready"),
+		newNode("cordoned", func(n *corev1.Node) { n.Spec.Unschedulable = true }),
+		newNode("tainted", func(n *corev1.Node) {
+			n.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+		}),
+		newNode("not-ready", func(n *corev1.Node) { n.Status.Conditions[0].Status = corev1.ConditionFalse }),
+	).Build()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}}
+
+	_, cm := reconcileSpecConfigMap(t, r)
+	status := statusFromAnnotations(cm.Annotations)
+	requireCondition(t, status.Service("frontend").Conditions, config.ConditionSchedulable, metav1.ConditionFalse,
+		"Deployment sourcegraph-frontend: 2 replicas requested, but only 1 nodes are schedulable")
+	// The warning doesn't hold up the rollout.
+	requireCondition(t, status.Conditions, config.ConditionReady, metav1.ConditionFalse, "Services not ready: frontend")
+
+	// Services with a single replica aren't checked.
+	require.Nil(t, meta.FindStatusCondition(status.Service("repo-updater").Conditions, config.ConditionSchedulable))
+
+	require.NoError(t, c.Create(context.Background(), newNode("added")))
+	_, cm = reconcileSpecConfigMap(t, r)
+	status = statusFromAnnotations(cm.Annotations)
+	requireCondition(t, status.Service("frontend").Conditions, config.ConditionSchedulable, metav1.ConditionTrue, "")
+}
+
+func TestReconcileSchedulableConditionNodesForbidden(t *testing.T) {
+	spec := append(readSpecFixture(t, "frontend/default"), []byte("\n  checkSchedulableNodes: true\n")...)
+	c := fake.NewClientBuilder().
+		WithObjects(newSpecConfigMap(spec)).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*corev1.NodeList); ok {
+					return errors.New("nodes is forbidden")
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).
+		Build()
+	r := &Reconciler{Client: c, Scheme: scheme.Scheme, Recorder: &record.FakeRecorder{}}
+
+	// Nodes that can't be listed don't fail the reconcile.
+	_, cm := reconcileSpecConfigMap(t, r)
+	status := statusFromAnnotations(cm.Annotations)
+	requireCondition(t, status.Service("frontend").Conditions, config.ConditionSchedulable, metav1.ConditionUnknown,
+		"listing nodes: nodes is forbidden")
+}
+
+func TestCountSchedulableNodes(t *testing.T) {
+	nodes := []corev1.Node{
+		*newNode("general"),
+		*newNode("search", func(n *corev1.Node) { n.Labels["pool"] = "search" }),
+		*newNode("search-tainted", func(n *corev1.Node) {
+			n.Labels["pool"] = "search"
+			n.Spec.Taints = []corev1.Taint{{Key: "pool", Value: "search", Effect: corev1.TaintEffectNoSchedule}}
+		}),
+		*newNode("preferred", func(n *corev1.Node) {
+			n.Spec.Taints = []corev1.Taint{{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}}
+		}),
+	}
+	for _, tc := range []struct {
+		name string
+		spec corev1.PodSpec
+		want int32
+	}{
+		{name: "no constraints", want: 3},
+		{
+			name: "node selector",
+			spec: corev1.PodSpec{NodeSelector: map[string]string{"pool": "search"}},
+			want: 1,
+		},
+		{
+			name: "node selector and toleration",
+			spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"pool": "search"},
+				Tolerations:  []corev1.Toleration{{Key: "pool", Operator: corev1.TolerationOpEqual, Value: "search", Effect: corev1.TaintEffectNoSchedule}},
+			},
+			want: 2,
+		},
+		{
+			name: "toleration of everything",
+			spec: corev1.PodSpec{Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}}},
+			want: 4,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, countSchedulableNodes(nodes, &tc.spec))
+		})
+	}
+}
+
+func newNode(name string, mutate ...func(*corev1.Node)) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelHostname: name}},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	for _, m := range mutate {
+		m(node)
+	}
+	return node
+}
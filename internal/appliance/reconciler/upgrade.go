@@ -0,0 +1,57 @@
+package reconciler
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+// upgradeInProgress returns the progress of the upgrade to the requested
+// version, given the one recorded by the last reconcile. An upgrade that rolls
+// out in phases starts at its first phase, and starts over if another version
+// is requested halfway through. It is nil if every component is rolled out at
+// once.
+func upgradeInProgress(previous *config.UpgradeStatus, sg *config.Sourcegraph) *config.UpgradeStatus {
+	if len(sg.UpgradePhases()) == 0 {
+		return nil
+	}
+	if previous == nil || previous.Version != sg.Spec.RequestedVersion {
+		return &config.UpgradeStatus{Version: sg.Spec.RequestedVersion}
+	}
+	upgrade := *previous
+	return &upgrade
+}
+
+// advanceUpgrade records upgrade, whose current phase has been reconciled
+// without errors, in status, unless it has no phases left to roll out. Each
+// phase starts once every service is ready after the previous one, so that
+// e.g. the databases are upgraded before the services that use them. It
+// returns whether the upgrade is still rolling out its phases, in which case
+// the Ready condition is false. Once they have all rolled out, every
+// component is rolled out to the requested version, like an upgrade without
+// phases.
+func advanceUpgrade(status *config.SourcegraphStatus, upgrade *config.UpgradeStatus, phases []config.UpgradePhase, ready bool) bool {
+	if upgrade == nil || upgrade.Phase >= len(phases) {
+		return false
+	}
+	next := *upgrade
+	if ready {
+		next.Phase++
+	}
+	status.Upgrade = &next
+
+	message := fmt.Sprintf("Upgrading to %s: all phases have rolled out, rolling out the remaining components", next.Version)
+	if next.Phase < len(phases) {
+		message = fmt.Sprintf("Upgrading to %s: rolling out phase %d of %d, %s", next.Version, next.Phase+1, len(phases), phases[next.Phase].Name)
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    config.ConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  config.ReasonUpgradeInProgress,
+		Message: message,
+	})
+	return true
+}
@@ -0,0 +1,45 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+func TestAdvanceUpgrade(t *testing.T) {
+	phases := []config.UpgradePhase{
+		{Name: "databases", Components: map[string]string{"pgsql": "5.4.0"}},
+		{Name: "data migrations", Components: map[string]string{"worker": "5.4.0-transitional"}},
+	}
+	upgrade := &config.UpgradeStatus{Version: "5.4.0"}
+
+	// Each phase waits for the services to be ready after the previous one.
+	for _, step := range []struct {
+		ready       bool
+		wantPhase   int
+		wantMessage string
+	}{
+		{ready: false, wantPhase: 0, wantMessage: "Upgrading to 5.4.0: rolling out phase 1 of 2, databases"},
+		{ready: true, wantPhase: 1, wantMessage: "Upgrading to 5.4.0: rolling out phase 2 of 2, data migrations"},
+		{ready: false, wantPhase: 1, wantMessage: "Upgrading to 5.4.0: rolling out phase 2 of 2, data migrations"},
+		{ready: true, wantPhase: 2, wantMessage: "Upgrading to 5.4.0: all phases have rolled out, rolling out the remaining components"},
+	} {
+		var status config.SourcegraphStatus
+		require.True(t, advanceUpgrade(&status, upgrade, phases, step.ready))
+		require.Equal(t, &config.UpgradeStatus{Version: "5.4.0", Phase: step.wantPhase}, status.Upgrade)
+		requireCondition(t, status.Conditions, config.ConditionReady, metav1.ConditionFalse, step.wantMessage)
+		require.Equal(t, config.ReasonUpgradeInProgress, meta.FindStatusCondition(status.Conditions, config.ConditionReady).Reason)
+		upgrade = status.Upgrade
+	}
+
+	// Once every phase has rolled out, the upgrade completes like one without
+	// phases.
+	var status config.SourcegraphStatus
+	require.False(t, advanceUpgrade(&status, upgrade, phases, true))
+	require.Nil(t, status.Upgrade)
+	require.False(t, advanceUpgrade(&status, nil, nil, true))
+}
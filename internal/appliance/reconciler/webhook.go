@@ -0,0 +1,126 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+// The paths that the admission webhooks of the spec are served at.
+const (
+	SpecDefaultingWebhookPath = "/mutate-appliance-spec"
+	SpecValidatingWebhookPath = "/validate-appliance-spec"
+)
+
+// SetupWebhooksWithManager registers the admission webhooks of the spec
+// ConfigMap with the webhook server of mgr. They only take effect once a
+// MutatingWebhookConfiguration and a ValidatingWebhookConfiguration send the
+// ConfigMaps of the appliance to SpecDefaultingWebhookPath and
+// SpecValidatingWebhookPath.
+func (r *Reconciler) SetupWebhooksWithManager(mgr ctrl.Manager) {
+	decoder := admission.NewDecoder(mgr.GetScheme())
+	server := mgr.GetWebhookServer()
+	server.Register(SpecDefaultingWebhookPath, &webhook.Admission{Handler: &specDefaulter{decoder: decoder}})
+	server.Register(SpecValidatingWebhookPath, &webhook.Admission{Handler: &specValidator{decoder: decoder, strict: r.StrictSpecDecoding}})
+}
+
+// specDefaulter converts specs to the latest API version on admission, see
+// config.DefaultSpecYAML. Specs in an unknown version are rejected.
+type specDefaulter struct {
+	decoder *admission.Decoder
+}
+
+func (d *specDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cm, data, ok, resp := decodeSpecConfigMap(d.decoder, req)
+	if !ok {
+		return resp
+	}
+
+	defaulted, err := config.DefaultSpecYAML([]byte(data))
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+	if string(defaulted) == data {
+		return admission.Allowed("")
+	}
+
+	cm.Data["spec"] = string(defaulted)
+	marshaled, err := json.Marshal(cm)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// specValidator rejects specs that the reconciler would report as invalid,
+// before they are stored: specs that don't decode, and specs that fail
+// Validate once merged with the defaults of their size. Unknown and deprecated
+// fields are returned as warnings, unless strict makes unknown fields invalid.
+type specValidator struct {
+	decoder *admission.Decoder
+	strict  bool
+}
+
+func (v *specValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	_, data, ok, resp := decodeSpecConfigMap(v.decoder, req)
+	if !ok {
+		return resp
+	}
+
+	sg, fieldWarnings, err := config.DecodeConfigYAML([]byte(data), config.DecodeOptions{Strict: v.strict})
+	var warnings []string
+	for _, w := range fieldWarnings {
+		warnings = append(warnings, "spec: "+w.String())
+	}
+	if err != nil {
+		return admission.Denied("invalid spec: " + err.Error()).WithWarnings(warnings...)
+	}
+	merged, err := config.MergeDocumentWithDefaults(sg, []byte(data))
+	if err != nil {
+		return admission.Denied("invalid spec: " + err.Error()).WithWarnings(warnings...)
+	}
+	if err := merged.Validate(); err != nil {
+		return admission.Denied("invalid spec: " + err.Error()).WithWarnings(warnings...)
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// decodeSpecConfigMap returns the ConfigMap of req and its spec. It returns
+// false with the response to send if there is nothing to check: the ConfigMap
+// isn't managed by the appliance, has no spec, is being deleted, or only its
+// annotations changed, e.g. when the reconciler records the status. The latter
+// keeps a spec that became invalid, e.g. after an upgrade of the appliance,
+// from blocking the reconciler.
+func decodeSpecConfigMap(decoder *admission.Decoder, req admission.Request) (*corev1.ConfigMap, string, bool, admission.Response) {
+	if req.Operation == admissionv1.Delete {
+		return nil, "", false, admission.Allowed("")
+	}
+
+	var cm corev1.ConfigMap
+	if err := decoder.Decode(req, &cm); err != nil {
+		return nil, "", false, admission.Errored(http.StatusBadRequest, err)
+	}
+	data, ok := cm.Data["spec"]
+	if !ok || cm.Annotations[config.AnnotationKeyManaged] != "true" {
+		return nil, "", false, admission.Allowed("")
+	}
+
+	if req.Operation == admissionv1.Update {
+		var old corev1.ConfigMap
+		if err := decoder.DecodeRaw(req.OldObject, &old); err != nil {
+			return nil, "", false, admission.Errored(http.StatusBadRequest, err)
+		}
+		if oldData, ok := old.Data["spec"]; ok && oldData == data {
+			return nil, "", false, admission.Allowed("")
+		}
+	}
+	return &cm, data, true, admission.Response{}
+}
@@ -0,0 +1,99 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sourcegraph/sourcegraph/internal/appliance/config"
+)
+
+func TestSpecWebhooks(t *testing.T) {
+	decoder := admission.NewDecoder(scheme.Scheme)
+	defaulter := &specDefaulter{decoder: decoder}
+	validator := &specValidator{decoder: decoder}
+	ctx := context.Background()
+
+	request := func(t *testing.T, op admissionv1.Operation, cm, old *corev1.ConfigMap) admission.Request {
+		t.Helper()
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: op}}
+		raw, err := json.Marshal(cm)
+		require.NoError(t, err)
+		req.Object = runtime.RawExtension{Raw: raw}
+		if old != nil {
+			raw, err := json.Marshal(old)
+			require.NoError(t, err)
+			req.OldObject = runtime.RawExtension{Raw: raw}
+		}
+		return req
+	}
+
+	const v1alpha1 = "spec:\n  requestedVersion: \"5.3.9104\"\n  frontend:\n    containerConfig:\n      frontend:\n        image: frontend:custom\n"
+
+	t.Run("defaulting converts to the latest version", func(t *testing.T) {
+		resp := defaulter.Handle(ctx, request(t, admissionv1.Create, newSpecConfigMap([]byte(v1alpha1)), nil))
+		require.True(t, resp.Allowed)
+		require.Len(t, resp.Patches, 1)
+		require.Equal(t, "/data/spec", resp.Patches[0].Path)
+
+		var doc map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(resp.Patches[0].Value.(string)), &doc))
+		require.Equal(t, config.LatestAPIVersion, doc["apiVersion"])
+		require.Contains(t, doc["spec"].(map[string]any)["frontend"], "containers")
+
+		// Specs in the latest version are left alone.
+		converted, err := config.DefaultSpecYAML([]byte(v1alpha1))
+		require.NoError(t, err)
+		resp = defaulter.Handle(ctx, request(t, admissionv1.Create, newSpecConfigMap(converted), nil))
+		require.True(t, resp.Allowed)
+		require.Empty(t, resp.Patches)
+	})
+
+	t.Run("unknown versions are rejected", func(t *testing.T) {
+		cm := newSpecConfigMap([]byte("apiVersion: appliance.sourcegraph.com/v9\nspec: {}\n"))
+		for _, resp := range []admission.Response{
+			defaulter.Handle(ctx, request(t, admissionv1.Create, cm, nil)),
+			validator.Handle(ctx, request(t, admissionv1.Create, cm, nil)),
+		} {
+			require.False(t, resp.Allowed)
+			require.Contains(t, resp.Result.Message, `unsupported apiVersion "appliance.sourcegraph.com/v9"`)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		resp := validator.Handle(ctx, request(t, admissionv1.Create, newSpecConfigMap([]byte(v1alpha1+"  frontend2: {}\n")), nil))
+		require.True(t, resp.Allowed)
+		require.Equal(t, []string{"spec: spec.frontend2: unknown field"}, resp.Warnings)
+
+		strict := &specValidator{decoder: decoder, strict: true}
+		resp = strict.Handle(ctx, request(t, admissionv1.Create, newSpecConfigMap([]byte(v1alpha1+"  frontend2: {}\n")), nil))
+		require.False(t, resp.Allowed)
+
+		invalid := newSpecConfigMap([]byte("spec:\n  requestedVersion: 0.0.1\n"))
+		resp = validator.Handle(ctx, request(t, admissionv1.Create, invalid, nil))
+		require.False(t, resp.Allowed)
+		require.Contains(t, resp.Result.Message, "invalid spec: ")
+
+		// Changes to the annotations of a spec that became invalid, e.g. its
+		// status, are let through.
+		updated := invalid.DeepCopy()
+		updated.Annotations[config.AnnotationKeyValidationErrors] = "invalid"
+		resp = validator.Handle(ctx, request(t, admissionv1.Update, updated, invalid))
+		require.True(t, resp.Allowed)
+	})
+
+	t.Run("other ConfigMaps are ignored", func(t *testing.T) {
+		cm := newSpecConfigMap([]byte("apiVersion: appliance.sourcegraph.com/v9\n"))
+		cm.Annotations = nil
+		require.True(t, defaulter.Handle(ctx, request(t, admissionv1.Create, cm, nil)).Allowed)
+		require.True(t, validator.Handle(ctx, request(t, admissionv1.Create, cm, nil)).Allowed)
+	})
+}
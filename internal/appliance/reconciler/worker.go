@@ -2,7 +2,10 @@ package reconciler
 
 import (
 	"context"
+	"slices"
+	"strings"
 
+	"golang.org/x/exp/maps"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -14,7 +17,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/deployment"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/pod"
 	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/service"
-	"github.com/sourcegraph/sourcegraph/internal/k8s/resource/serviceaccount"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
@@ -23,6 +25,12 @@ func (r *Reconciler) reconcileWorker(ctx context.Context, sg *config.Sourcegraph
 	if err := r.reconcileWorkerDeployment(ctx, sg, owner); err != nil {
 		return errors.Wrap(err, "reconciling Deployment")
 	}
+	if err := r.reconcileExtraWorkerDeployments(ctx, sg, owner); err != nil {
+		return errors.Wrap(err, "reconciling extra worker Deployments")
+	}
+	if err := r.reconcilePodDisruptionBudget(ctx, sg, owner, sg.Spec.ObjectName("worker"), sg.Spec.Worker, sg.Spec.Worker.Replicas); err != nil {
+		return errors.Wrap(err, "reconciling PodDisruptionBudget")
+	}
 	if err := r.reconcileWorkerService(ctx, sg, owner); err != nil {
 		return errors.Wrap(err, "reconciling Service")
 	}
@@ -36,14 +44,62 @@ func (r *Reconciler) reconcileWorker(ctx context.Context, sg *config.Sourcegraph
 }
 
 func (r *Reconciler) reconcileWorkerDeployment(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	name := "worker"
 	cfg := sg.Spec.Worker
-
-	defaultImage, err := config.GetDefaultImage(sg, name)
+	dep, err := r.workerDeployment(sg, "worker", cfg, owner)
 	if err != nil {
 		return err
 	}
-	ctr := container.NewContainer(name, cfg, config.ContainerConfig{
+	return reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner)
+}
+
+// reconcileExtraWorkerDeployments reconciles the Deployments of
+// WorkerSpec.ExtraWorkers, and deletes those of extra workers that have been
+// removed from the spec.
+func (r *Reconciler) reconcileExtraWorkerDeployments(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
+	keys := maps.Keys(sg.Spec.Worker.ExtraWorkers)
+	slices.Sort(keys)
+	for _, key := range keys {
+		cfg := sg.Spec.Worker.ExtraWorker(key)
+		dep, err := r.workerDeployment(sg, "worker-"+key, cfg, owner)
+		if err != nil {
+			return errors.Wrapf(err, "extra worker %s", key)
+		}
+		dep.Labels[config.LabelKeyExtraWorker] = key
+		if err := reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner); err != nil {
+			return errors.Wrapf(err, "extra worker %s", key)
+		}
+	}
+
+	var existing appsv1.DeploymentList
+	if err := r.List(ctx, &existing, client.InNamespace(sg.Namespace), client.HasLabels{config.LabelKeyExtraWorker}); err != nil {
+		return errors.Wrap(err, "listing extra worker Deployments")
+	}
+	for i := range existing.Items {
+		dep := &existing.Items[i]
+		key := dep.Labels[config.LabelKeyExtraWorker]
+		if _, ok := sg.Spec.Worker.ExtraWorkers[key]; ok {
+			continue
+		}
+		// The extra workers of another deployment in the namespace, with
+		// another NamePrefix, are left alone.
+		if dep.Name != sg.Spec.ObjectName("worker-"+key) {
+			continue
+		}
+		if err := r.ensureObjectDeleted(ctx, dep); err != nil {
+			return errors.Wrapf(err, "deleting extra worker Deployment %s", dep.Name)
+		}
+	}
+	return nil
+}
+
+// workerDeployment returns the Deployment of the worker, or of an extra worker,
+// called name.
+func (r *Reconciler) workerDeployment(sg *config.Sourcegraph, name string, cfg config.WorkerSpec, owner client.Object) (appsv1.Deployment, error) {
+	defaultImage, err := config.GetDefaultImage(sg, "worker")
+	if err != nil {
+		return appsv1.Deployment{}, err
+	}
+	ctr := container.NewContainer("worker", cfg, config.ContainerConfig{
 		Image: defaultImage,
 		Resources: &corev1.ResourceRequirements{
 			Requests: corev1.ResourceList{
@@ -57,20 +113,27 @@ func (r *Reconciler) reconcileWorkerDeployment(ctx context.Context, sg *config.S
 		},
 	})
 
-	ctr.Env = append(ctr.Env, container.EnvVarsRedis()...)
+	ctr.Env = append(ctr.Env, redisEnvVars(sg)...)
 	ctr.Env = addPreciseCodeIntelBlobstoreVars(ctr.Env, sg)
-	if !sg.Spec.Embeddings.Disabled && !sg.Spec.Blobstore.Disabled {
+	if !sg.Spec.Embeddings.Disabled && !sg.Spec.Blobstore.Disabled && !sg.Spec.Blobstore.IsExternal() {
 		ctr.Env = append(
 			ctr.Env,
 			corev1.EnvVar{Name: "EMBEDDINGS_UPLOAD_BACKEND", Value: "blobstore"},
-			corev1.EnvVar{Name: "EMBEDDINGS_UPLOAD_AWS_ENDPOINT", Value: "http://blobstore:9000"},
+			corev1.EnvVar{Name: "EMBEDDINGS_UPLOAD_AWS_ENDPOINT", Value: serviceScheme(sg, sg.Spec.Blobstore) + "://" + sg.Spec.ObjectName("blobstore") + ":9000"},
 		)
 	}
 	ctr.Env = append(
 		ctr.Env,
 		container.NewEnvVarFieldRef("POD_NAME", "metadata.name"),
 	)
-	ctr.Env = append(ctr.Env, container.EnvVarsOtel()...)
+	if len(cfg.JobAllowlist) > 0 {
+		ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "WORKER_JOB_ALLOWLIST", Value: strings.Join(cfg.JobAllowlist, ",")})
+	}
+	if len(cfg.JobDenylist) > 0 {
+		ctr.Env = append(ctr.Env, corev1.EnvVar{Name: "WORKER_JOB_BLOCKLIST", Value: strings.Join(cfg.JobDenylist, ",")})
+	}
+	ctr.Env = append(ctr.Env, otelEnvVars(sg)...)
+	ctr.Env = append(ctr.Env, serviceEndpointEnvVars(sg)...)
 
 	ctr.Ports = []corev1.ContainerPort{
 		{Name: "http", ContainerPort: 3189},
@@ -98,31 +161,40 @@ func (r *Reconciler) reconcileWorkerDeployment(ctx context.Context, sg *config.S
 		TimeoutSeconds: 5,
 	}
 
-	podTemplate := pod.NewPodTemplate(name, cfg)
+	podTemplate := pod.NewPodTemplate(sg.Spec.ObjectName(name), cfg)
+	// Extra workers run the same container as the worker.
+	podTemplate.Template.Annotations["kubectl.kubernetes.io/default-container"] = ctr.Name
 	podTemplate.Template.Spec.Containers = []corev1.Container{ctr}
+	podTemplate.Template.Spec.ServiceAccountName = sg.Spec.ObjectName("worker")
 
-	dep := deployment.NewDeployment(name, sg.Namespace, sg.Spec.RequestedVersion)
+	if err := applyMigrationGate(&podTemplate.Template, sg, cfg, gatePgsql, gateCodeIntel, gateCodeInsights); err != nil {
+		return appsv1.Deployment{}, err
+	}
+	applyTopologySpread(&podTemplate.Template, cfg, cfg.Replicas)
+	if err := r.applyGlobalPodConfig(&podTemplate.Template, sg, cfg, owner); err != nil {
+		return appsv1.Deployment{}, err
+	}
+
+	dep := deployment.NewDeployment(sg.Spec.ObjectName(name), sg.Namespace, sg.Spec.RequestedVersion)
 	dep.Spec.Replicas = pointers.Ptr(cfg.Replicas)
-	dep.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{
-		MaxSurge:       pointers.Ptr(intstr.FromInt(1)),
-		MaxUnavailable: pointers.Ptr(intstr.FromInt(1)),
+	if err := applyDeploymentStrategy(&dep, cfg); err != nil {
+		return appsv1.Deployment{}, err
 	}
 	dep.Spec.Template = podTemplate.Template
-
-	return reconcileObject(ctx, r, cfg, &dep, &appsv1.Deployment{}, sg, owner)
+	return dep, nil
 }
 
 func (r *Reconciler) reconcileWorkerService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	name := "worker"
 	cfg := sg.Spec.Worker
 
-	svc := service.NewService(name, sg.Namespace, cfg)
+	svc := service.NewService(sg.Spec.ObjectName(name), sg.Namespace, cfg)
 	svc.Spec.Ports = []corev1.ServicePort{
 		{Name: "http", Port: 3189, TargetPort: intstr.FromString("http")},
 		{Name: "debug", Port: 6060, TargetPort: intstr.FromString("debug")},
 	}
 	svc.Spec.Selector = map[string]string{
-		"app": name,
+		"app": sg.Spec.ObjectName(name),
 	}
 
 	return reconcileObject(ctx, r, cfg, &svc, &corev1.Service{}, sg, owner)
@@ -131,12 +203,12 @@ func (r *Reconciler) reconcileWorkerService(ctx context.Context, sg *config.Sour
 func (r *Reconciler) reconcileWorkerExecutorsService(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
 	cfg := sg.Spec.Worker
 
-	svc := service.NewService("worker-executors", sg.Namespace, nil)
+	svc := service.NewService(sg.Spec.ObjectName("worker-executors"), sg.Namespace, nil)
 	svc.Spec.Ports = []corev1.ServicePort{
 		{Name: "prom", Port: 6996, TargetPort: intstr.FromString("prom")},
 	}
 	svc.Spec.Selector = map[string]string{
-		"app": "worker",
+		"app": sg.Spec.ObjectName("worker"),
 	}
 	svc.SetAnnotations(map[string]string{
 		"prometheus.io/port":            "6996",
@@ -147,7 +219,5 @@ func (r *Reconciler) reconcileWorkerExecutorsService(ctx context.Context, sg *co
 }
 
 func (r *Reconciler) reconcileWorkerServiceAccount(ctx context.Context, sg *config.Sourcegraph, owner client.Object) error {
-	cfg := sg.Spec.Worker
-	sa := serviceaccount.NewServiceAccount("worker", sg.Namespace, cfg)
-	return reconcileObject(ctx, r, cfg, &sa, &corev1.ServiceAccount{}, sg, owner)
+	return r.reconcileServiceAccount(ctx, sg, owner, sg.Spec.ObjectName("worker"), sg.Spec.Worker)
 }
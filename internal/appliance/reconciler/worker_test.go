@@ -5,9 +5,18 @@ func (suite *ApplianceTestSuite) TestDeployWorker() {
 		name string
 	}{
 		{name: "worker/default"},
+		{name: "worker/with-env"},
 		{name: "worker/with-blobstore"},
 		{name: "worker/with-blobstore-and-embeddings"},
+		{name: "worker/with-disruption-budget"},
+		{name: "worker/with-existing-service-account"},
+		{name: "worker/with-extra-workers"},
+		{name: "worker/with-external-storage"},
+		{name: "worker/with-extra-init-containers"},
+		{name: "worker/with-migration-gate-disabled"},
 		{name: "worker/with-replicas"},
+		{name: "worker/with-schema-version-gate"},
+		{name: "worker/with-service-account-annotations"},
 	} {
 		suite.Run(tc.name, func() {
 			namespace := suite.createConfigMapAndAwaitReconciliation(tc.name)
@@ -15,3 +24,21 @@ func (suite *ApplianceTestSuite) TestDeployWorker() {
 		})
 	}
 }
+
+func (suite *ApplianceTestSuite) TestWorkerManagedServiceAccountDeletedWhenExistingConfigured() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("worker/with-service-account-annotations")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "worker/with-existing-service-account")
+	suite.makeGoldenAssertions(namespace, "worker/subsequent-existing-service-account")
+}
+
+func (suite *ApplianceTestSuite) TestWorkerPodDisruptionBudgetDeletedWhenScaledDown() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("worker/with-replicas")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "worker/default")
+	suite.makeGoldenAssertions(namespace, "worker/subsequent-scale-down")
+}
+
+func (suite *ApplianceTestSuite) TestExtraWorkersDeletedWhenRemoved() {
+	namespace := suite.createConfigMapAndAwaitReconciliation("worker/with-extra-workers")
+	suite.updateConfigMapAndAwaitReconciliation(namespace, "worker/default")
+	suite.makeGoldenAssertions(namespace, "worker/subsequent-extra-workers-removed")
+}
@@ -107,6 +107,7 @@ const (
 	GitserverAccess = iota
 	InternalTraffic
 	GraphQL
+	RedactSearchJobQueries
 )
 
 // IsEnabled returns the value of the respective setting from the site config (if set).
@@ -121,6 +122,8 @@ func IsEnabled(cfg schema.SiteConfiguration, setting AuditLogSetting) bool {
 			return auditCfg.InternalTraffic
 		case GraphQL:
 			return auditCfg.GraphQL
+		case RedactSearchJobQueries:
+			return auditCfg.RedactSearchJobQueries
 		}
 	}
 	// all settings now currently default to 'false', but that's a coincidence, not intention
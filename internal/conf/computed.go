@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/confdefaults"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/conf/deploy"
+	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/hashutil"
 	"github.com/sourcegraph/sourcegraph/internal/license"
 	srccli "github.com/sourcegraph/sourcegraph/internal/src-cli"
@@ -44,8 +45,22 @@ func defaultConfigForDeployment() conftypes.RawUnified {
 	}
 }
 
+// executorsAccessTokenFromEnv is the access token of executors deployments
+// that share it through the environment rather than the site configuration,
+// such as the appliance, which generates it.
+var executorsAccessTokenFromEnv = env.Get("EXECUTORS_ACCESS_TOKEN", "", "The access token that executors authenticate with, unless executors.accessToken is set in the site configuration.")
+
 func ExecutorsAccessToken() string {
-	return Get().ExecutorsAccessToken
+	return ExecutorsAccessTokenOf(Get().SiteConfiguration)
+}
+
+// ExecutorsAccessTokenOf returns the executors access token of the given site
+// configuration, or the one from EXECUTORS_ACCESS_TOKEN if it sets none.
+func ExecutorsAccessTokenOf(c schema.SiteConfiguration) string {
+	if c.ExecutorsAccessToken != "" {
+		return c.ExecutorsAccessToken
+	}
+	return executorsAccessTokenFromEnv
 }
 
 type AccessTokenAllow string
@@ -279,7 +294,7 @@ func CodyPermissionsEnabled() bool {
 }
 
 func ExecutorsEnabled() bool {
-	return Get().ExecutorsAccessToken != ""
+	return ExecutorsAccessToken() != ""
 }
 
 func ExecutorsFrontendURL() string {
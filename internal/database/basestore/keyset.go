@@ -0,0 +1,158 @@
+package basestore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Keyset builds the SQL of a keyset pagination, which continues after the
+// last row of the previous page rather than skipping its rows with OFFSET.
+// A page then costs the same wherever it is, and rows inserted or deleted
+// while paginating don't shift the rows of the next pages.
+//
+//	keyset := basestore.Keyset{Columns: []string{"created_at", "id"}}
+//	q := sqlf.Sprintf("SELECT ... WHERE %s ORDER BY %s LIMIT %s", keyset.After(cursor), keyset.OrderBy(), limit)
+//
+// The columns must not be NULL, and must identify a row together, e.g. by
+// ending with the primary key: rows with the same values would be skipped.
+type Keyset struct {
+	// Columns are the columns to order by, e.g. "id" or "jobs.created_at".
+	// They are trusted SQL.
+	Columns []string
+	// Descending orders the rows by descending values of the columns.
+	Descending bool
+}
+
+// After returns the condition matching the rows after the row whose values
+// of the columns are cursor. If cursor is empty, it matches every row.
+func (k Keyset) After(cursor []any) *sqlf.Query {
+	return k.compare(cursor, k.Descending)
+}
+
+// Before returns the condition matching the rows before the row whose values
+// of the columns are cursor. If cursor is empty, it matches every row.
+func (k Keyset) Before(cursor []any) *sqlf.Query {
+	return k.compare(cursor, !k.Descending)
+}
+
+func (k Keyset) compare(cursor []any, less bool) *sqlf.Query {
+	if len(cursor) == 0 {
+		return sqlf.Sprintf("TRUE")
+	}
+
+	operator := ">"
+	if less {
+		operator = "<"
+	}
+	values := make([]*sqlf.Query, 0, len(cursor))
+	for _, value := range cursor {
+		values = append(values, sqlf.Sprintf("%s", value))
+	}
+	return sqlf.Sprintf("("+strings.Join(k.Columns, ", ")+") "+operator+" (%s)", sqlf.Join(values, ", "))
+}
+
+// OrderBy returns the terms of the ORDER BY clause of the keyset.
+func (k Keyset) OrderBy() *sqlf.Query {
+	direction := " ASC"
+	if k.Descending {
+		direction = " DESC"
+	}
+	terms := make([]string, 0, len(k.Columns))
+	for _, column := range k.Columns {
+		terms = append(terms, column+direction)
+	}
+	return sqlf.Sprintf(strings.Join(terms, ", "))
+}
+
+// Reverse returns the keyset ordering the rows the other way, e.g. to read
+// the last rows before a cursor. The rows of such a page have to be reversed
+// again by the caller.
+func (k Keyset) Reverse() Keyset {
+	return Keyset{Columns: k.Columns, Descending: !k.Descending}
+}
+
+// EncodeCursor encodes the values of the columns of a keyset for the last row
+// of a page into an opaque cursor, which DecodeCursor decodes.
+func EncodeCursor(values ...any) (string, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor decodes a cursor of EncodeCursor into targets, which are
+// pointers to the values passed to EncodeCursor. It returns the decoded
+// values, ready to be passed to Keyset.After.
+func DecodeCursor(cursor string, targets ...any) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cursor")
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, errors.Wrap(err, "invalid cursor")
+	}
+	if len(raw) != len(targets) {
+		return nil, errors.Newf("invalid cursor: got %d values, want %d", len(raw), len(targets))
+	}
+
+	values := make([]any, 0, len(targets))
+	for i, target := range targets {
+		if err := json.Unmarshal(raw[i], target); err != nil {
+			return nil, errors.Wrapf(err, "invalid cursor value %d", i)
+		}
+		values = append(values, dereference(target))
+	}
+	return values, nil
+}
+
+// ScanBatches streams the rows of a query to scan, reading them batchSize
+// rows at a time with a keyset pagination, so that neither the rows nor a
+// transaction are held until every row is read. newQuery returns the query
+// for the rows matching a condition, without ORDER BY or LIMIT. scan is
+// called for every row, and returns the values of the columns of the keyset
+// for the row, which continue the pagination.
+func ScanBatches(
+	ctx context.Context,
+	store *Store,
+	keyset Keyset,
+	batchSize int,
+	newQuery func(cond *sqlf.Query) *sqlf.Query,
+	scan func(dbutil.Scanner) (cursor []any, _ error),
+) error {
+	if batchSize <= 0 {
+		return errors.Newf("invalid batch size %d", batchSize)
+	}
+
+	var cursor []any
+	for {
+		q := sqlf.Sprintf("%s ORDER BY %s LIMIT %s", newQuery(keyset.After(cursor)), keyset.OrderBy(), batchSize)
+
+		n := 0
+		err := NewCallbackScanner(func(s dbutil.Scanner) (_ bool, err error) {
+			n++
+			cursor, err = scan(s)
+			return err == nil, err
+		})(store.Query(ctx, q))
+		if err != nil {
+			return err
+		}
+		if n < batchSize {
+			return nil
+		}
+	}
+}
+
+// dereference returns the value target points to.
+func dereference(target any) any {
+	return reflect.ValueOf(target).Elem().Interface()
+}
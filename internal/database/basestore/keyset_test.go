@@ -0,0 +1,176 @@
+package basestore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+func TestKeyset(t *testing.T) {
+	keyset := Keyset{Columns: []string{"created_at", "id"}}
+	cursor := []any{"2024-01-01", 42}
+
+	for _, tc := range []struct {
+		name     string
+		query    *sqlf.Query
+		wantSQL  string
+		wantArgs []any
+	}{
+		{name: "after", query: keyset.After(cursor), wantSQL: "(created_at, id) > ($1, $2)", wantArgs: cursor},
+		{name: "before", query: keyset.Before(cursor), wantSQL: "(created_at, id) < ($1, $2)", wantArgs: cursor},
+		{name: "after descending", query: keyset.Reverse().After(cursor), wantSQL: "(created_at, id) < ($1, $2)", wantArgs: cursor},
+		{name: "before descending", query: keyset.Reverse().Before(cursor), wantSQL: "(created_at, id) > ($1, $2)", wantArgs: cursor},
+		{name: "no cursor", query: keyset.After(nil), wantSQL: "TRUE"},
+		{name: "order", query: keyset.OrderBy(), wantSQL: "created_at ASC, id ASC"},
+		{name: "order descending", query: keyset.Reverse().OrderBy(), wantSQL: "created_at DESC, id DESC"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.wantSQL, tc.query.Query(sqlf.PostgresBindVar))
+			require.Equal(t, tc.wantArgs, tc.query.Args())
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	cursor, err := EncodeCursor(createdAt, int64(42))
+	require.NoError(t, err)
+
+	var decodedCreatedAt time.Time
+	var decodedID int64
+	values, err := DecodeCursor(cursor, &decodedCreatedAt, &decodedID)
+	require.NoError(t, err)
+	require.Equal(t, []any{createdAt, int64(42)}, values)
+
+	_, err = DecodeCursor(cursor, &decodedID)
+	require.Error(t, err, "wrong number of values")
+	_, err = DecodeCursor(cursor, &decodedID, &decodedCreatedAt)
+	require.Error(t, err, "wrong types")
+	_, err = DecodeCursor("not a cursor", &decodedCreatedAt, &decodedID)
+	require.Error(t, err, "invalid encoding")
+}
+
+func TestScanBatches(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := dbtest.NewRawDB(logger, t)
+	setupStoreTest(t, db)
+	store := testStore(t, db)
+	ctx := context.Background()
+
+	insert := func(id, value int) {
+		t.Helper()
+		require.NoError(t, store.Exec(ctx, sqlf.Sprintf(`INSERT INTO store_counts_test VALUES (%s, %s)`, id, value)))
+	}
+	for id := 1; id <= 10; id++ {
+		insert(id, id%3)
+	}
+
+	// scan returns the IDs of the rows in the order of (value, id), and
+	// calls onRow after every row.
+	scan := func(batchSize int, onRow func(id int)) []int {
+		t.Helper()
+		var ids []int
+		err := ScanBatches(
+			ctx,
+			store,
+			Keyset{Columns: []string{"value", "id"}},
+			batchSize,
+			func(cond *sqlf.Query) *sqlf.Query {
+				return sqlf.Sprintf("SELECT id, value FROM store_counts_test WHERE %s", cond)
+			},
+			func(s dbutil.Scanner) ([]any, error) {
+				var id, value int
+				if err := s.Scan(&id, &value); err != nil {
+					return nil, err
+				}
+				ids = append(ids, id)
+				onRow(id)
+				return []any{value, id}, nil
+			},
+		)
+		require.NoError(t, err)
+		return ids
+	}
+
+	want := []int{3, 6, 9, 1, 4, 7, 10, 2, 5, 8}
+	for _, batchSize := range []int{1, 3, 10, 11} {
+		require.Equal(t, want, scan(batchSize, func(int) {}), "batch size %d", batchSize)
+	}
+
+	t.Run("rows inserted mid-pagination", func(t *testing.T) {
+		// A row inserted before the cursor isn't seen, and a row inserted
+		// after it is, but neither shifts the rows of the next batches, so
+		// no row is seen twice or skipped.
+		got := scan(3, func(id int) {
+			if id == 1 {
+				insert(11, 0) // before the cursor (1, 1)
+				insert(12, 2) // after the cursor
+			}
+		})
+		require.Equal(t, []int{3, 6, 9, 1, 4, 7, 10, 2, 5, 8, 12}, got)
+	})
+}
+
+// BenchmarkPagination compares reading a page deep into a table of 1M rows
+// with OFFSET and with a keyset, which reads the page from the index rather
+// than skipping the rows before it.
+func BenchmarkPagination(b *testing.B) {
+	logger := logtest.Scoped(b)
+	db := dbtest.NewRawDB(logger, b)
+	store := testStore(b, db)
+	ctx := context.Background()
+
+	const rows, pageSize = 1_000_000, 100
+	for _, q := range []*sqlf.Query{
+		sqlf.Sprintf(`CREATE TABLE keyset_bench (id integer PRIMARY KEY, created_at timestamptz NOT NULL)`),
+		sqlf.Sprintf(`INSERT INTO keyset_bench SELECT i, now() - i * interval '1 second' FROM generate_series(1, %s) i`, rows),
+		sqlf.Sprintf(`CREATE INDEX keyset_bench_created_at_id ON keyset_bench (created_at, id)`),
+		sqlf.Sprintf(`ANALYZE keyset_bench`),
+	} {
+		require.NoError(b, store.Exec(ctx, q))
+	}
+
+	keyset := Keyset{Columns: []string{"created_at", "id"}}
+	for _, offset := range []int{0, rows / 2, rows - pageSize} {
+		var cursor []any
+		if offset > 0 {
+			var createdAt time.Time
+			var id int
+			q := sqlf.Sprintf("SELECT created_at, id FROM keyset_bench ORDER BY %s OFFSET %s LIMIT 1", keyset.OrderBy(), offset-1)
+			require.NoError(b, store.QueryRow(ctx, q).Scan(&createdAt, &id))
+			cursor = []any{createdAt, id}
+		}
+
+		b.Run(fmt.Sprintf("offset=%d", offset), func(b *testing.B) {
+			b.Run("offset", func(b *testing.B) {
+				q := sqlf.Sprintf("SELECT id FROM keyset_bench ORDER BY %s OFFSET %s LIMIT %s", keyset.OrderBy(), offset, pageSize)
+				benchmarkPage(b, store, q, pageSize)
+			})
+			b.Run("keyset", func(b *testing.B) {
+				q := sqlf.Sprintf("SELECT id FROM keyset_bench WHERE %s ORDER BY %s LIMIT %s", keyset.After(cursor), keyset.OrderBy(), pageSize)
+				benchmarkPage(b, store, q, pageSize)
+			})
+		})
+	}
+}
+
+func benchmarkPage(b *testing.B, store *Store, q *sqlf.Query, pageSize int) {
+	b.Helper()
+	for range b.N {
+		ids, err := ScanInts(store.Query(context.Background(), q))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(ids) != pageSize {
+			b.Fatalf("got %d rows, want %d", len(ids), pageSize)
+		}
+	}
+}
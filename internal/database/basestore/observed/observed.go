@@ -0,0 +1,121 @@
+// Package observed instruments the stores built on basestore: it creates the
+// observation operations of their methods, which share RED metrics labelled
+// by method, and logs the queries which take longer than a threshold.
+//
+//	ops := observed.NewOperations(observationCtx, "searchjobs_store")
+//	op := ops.Operation("GetSearchJob")
+//
+//	handle := observed.LogSlowQueries(db.Handle(), observationCtx.Logger, time.Second)
+//	s := basestore.NewWithHandle(handle)
+package observed
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// Operations creates the operations of the methods of a store.
+type Operations struct {
+	observationCtx *observation.Context
+	name           string
+	metrics        *metrics.REDMetrics
+}
+
+// NewOperations returns the operations of the store called name, which is
+// snake_cased, like "searchjobs_store". The duration, count and errors of the
+// operations are exported as src_<name>_duration_seconds, src_<name>_total
+// and src_<name>_errors_total, labelled by op. The metrics are registered
+// once per registerer, so every store created with the same observation
+// context shares them.
+func NewOperations(observationCtx *observation.Context, name string) *Operations {
+	return &Operations{
+		observationCtx: observationCtx,
+		name:           name,
+		metrics: metrics.NewREDMetrics(
+			observationCtx.Registerer,
+			name,
+			metrics.WithLabels("op"),
+			metrics.WithCountHelp("Total number of method invocations."),
+		),
+	}
+}
+
+// Operation returns the operation of the method of the store, which is named
+// like the method, e.g. "ListJobs". Its spans and logs are named
+// "<name>.<method>" with the underscores of the name replaced by dots, e.g.
+// "searchjobs.store.ListJobs".
+func (o *Operations) Operation(method string) *observation.Operation {
+	return o.observationCtx.Operation(observation.Op{
+		Name:              strings.ReplaceAll(o.name, "_", ".") + "." + method,
+		MetricLabelValues: []string{method},
+		Metrics:           o.metrics,
+	})
+}
+
+// LogSlowQueries returns a handle which executes its queries on handle, and
+// logs a warning with the query, once whitespace is collapsed, if the
+// database takes longer than threshold to respond. The time spent reading the
+// rows afterwards is not counted. The arguments of the query are not logged,
+// since they may contain user data. The queries of the transactions opened
+// on the handle are logged too. A threshold of 0 disables the logging.
+func LogSlowQueries(handle basestore.TransactableHandle, logger log.Logger, threshold time.Duration) basestore.TransactableHandle {
+	if threshold <= 0 {
+		return handle
+	}
+	return &slowQueryHandle{
+		TransactableHandle: handle,
+		logger:             logger.Scoped("slowquery"),
+		threshold:          threshold,
+	}
+}
+
+type slowQueryHandle struct {
+	basestore.TransactableHandle
+	logger    log.Logger
+	threshold time.Duration
+}
+
+func (h *slowQueryHandle) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	defer h.observe(ctx, time.Now(), query, args)
+	return h.TransactableHandle.QueryContext(ctx, query, args...)
+}
+
+func (h *slowQueryHandle) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	defer h.observe(ctx, time.Now(), query, args)
+	return h.TransactableHandle.QueryRowContext(ctx, query, args...)
+}
+
+func (h *slowQueryHandle) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	defer h.observe(ctx, time.Now(), query, args)
+	return h.TransactableHandle.ExecContext(ctx, query, args...)
+}
+
+func (h *slowQueryHandle) Transact(ctx context.Context) (basestore.TransactableHandle, error) {
+	tx, err := h.TransactableHandle.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &slowQueryHandle{TransactableHandle: tx, logger: h.logger, threshold: h.threshold}, nil
+}
+
+func (h *slowQueryHandle) observe(ctx context.Context, start time.Time, query string, args []any) {
+	elapsed := time.Since(start)
+	if elapsed < h.threshold {
+		return
+	}
+	trace.Logger(ctx, h.logger).Warn("slow query",
+		log.String("query", strings.Join(strings.Fields(query), " ")),
+		log.Int("args", len(args)),
+		log.Duration("duration", elapsed),
+		log.Duration("threshold", h.threshold),
+	)
+}
@@ -0,0 +1,111 @@
+package observed
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sourcegraph/log"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestOperations(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	observationCtx := observation.TestContextTB(t)
+	observationCtx.Registerer = registry
+
+	ops := NewOperations(observationCtx, "test_store")
+	// A second store created with the same context shares the metrics.
+	other := NewOperations(observationCtx, "test_store")
+
+	observe := func(op *observation.Operation, err error) {
+		_, _, endObservation := op.With(context.Background(), &err, observation.Args{})
+		endObservation(1, observation.Args{})
+	}
+	observe(ops.Operation("Get"), nil)
+	observe(other.Operation("Get"), nil)
+	observe(ops.Operation("List"), nil)
+	observe(ops.Operation("List"), errors.New("failed"))
+
+	require.Equal(t, 2, testutil.CollectAndCount(registry, "src_test_store_duration_seconds"), "one histogram per operation")
+	require.Equal(t, float64(2), testutil.ToFloat64(ops.metrics.Count.WithLabelValues("Get")))
+	require.Equal(t, float64(1), testutil.ToFloat64(ops.metrics.Count.WithLabelValues("List")))
+	require.Equal(t, float64(1), testutil.ToFloat64(ops.metrics.Errors.WithLabelValues("List")))
+}
+
+func TestLogSlowQueries(t *testing.T) {
+	ctx := context.Background()
+
+	newStore := func(t *testing.T, threshold time.Duration) (*basestore.Store, func() logtest.CapturedLogs) {
+		logger, exportLogs := logtest.Captured(t)
+		return basestore.NewWithHandle(LogSlowQueries(&fakeHandle{delay: time.Millisecond}, logger, threshold)), exportLogs
+	}
+
+	t.Run("slow queries", func(t *testing.T) {
+		s, exportLogs := newStore(t, time.Nanosecond)
+
+		_ = s.Exec(ctx, sqlf.Sprintf("DELETE FROM users\n\tWHERE id = %s", 1))
+		err := s.WithTransact(ctx, func(tx *basestore.Store) error {
+			_, _ = tx.Query(ctx, sqlf.Sprintf("SELECT id FROM users"))
+			return nil
+		})
+		require.NoError(t, err)
+
+		logs := exportLogs().Filter(func(l logtest.CapturedLog) bool { return l.Message == "slow query" })
+		require.Len(t, logs, 2)
+		require.Equal(t, log.LevelWarn, logs[0].Level)
+		require.Equal(t, "DELETE FROM users WHERE id = $1", logs[0].Fields["query"])
+		require.Equal(t, "SELECT id FROM users", logs[1].Fields["query"], "queries of transactions are logged")
+	})
+
+	t.Run("fast queries", func(t *testing.T) {
+		s, exportLogs := newStore(t, time.Hour)
+
+		_ = s.Exec(ctx, sqlf.Sprintf("DELETE FROM users WHERE id = %s", 1))
+		require.Empty(t, exportLogs())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		handle := &fakeHandle{}
+		require.Same(t, handle, LogSlowQueries(handle, logtest.Scoped(t), 0))
+	})
+}
+
+// fakeHandle is a handle which executes no queries, but takes delay to do
+// so.
+type fakeHandle struct {
+	delay         time.Duration
+	inTransaction bool
+}
+
+func (h *fakeHandle) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	time.Sleep(h.delay)
+	return nil, nil
+}
+
+func (h *fakeHandle) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	time.Sleep(h.delay)
+	return nil
+}
+
+func (h *fakeHandle) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	time.Sleep(h.delay)
+	return nil, nil
+}
+
+func (h *fakeHandle) InTransaction() bool { return h.inTransaction }
+
+func (h *fakeHandle) Transact(context.Context) (basestore.TransactableHandle, error) {
+	return &fakeHandle{delay: h.delay, inTransaction: true}, nil
+}
+
+func (h *fakeHandle) Done(err error) error { return err }
@@ -0,0 +1,204 @@
+// Package querytest records the SQL queries executed by stores built on
+// basestore, so that tests can compare them to golden files. This makes the
+// changes to the queries of a store reviewable as diffs of its golden files,
+// including the changes tests don't observe, like a dropped condition.
+//
+//	rec := querytest.NewRecorder()
+//	s := store.New(database.NewDBWith(logger, rec.Store(db)), observation.TestContextTB(t))
+//	...
+//	rec.AssertGolden(t, "testdata/golden/"+t.Name(), update(t.Name()))
+package querytest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/regexp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+)
+
+// Method is the method of the database handle which executed a query.
+type Method string
+
+const (
+	MethodQuery    Method = "Query"
+	MethodQueryRow Method = "QueryRow"
+	MethodExec     Method = "Exec"
+)
+
+// Query is a query executed on a recording handle.
+type Query struct {
+	Method Method
+	SQL    string
+	Args   []any
+}
+
+// Recorder records the queries executed on the handles it wraps. It is safe
+// for concurrent use, so it also records the queries of handlers which run in
+// goroutines.
+type Recorder struct {
+	mu      sync.Mutex
+	queries []Query
+}
+
+// NewRecorder returns a recorder which hasn't recorded any query yet.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Store returns a store which executes its queries on the handle of other,
+// and records them. The queries executed in transactions opened on the store
+// are recorded too.
+func (r *Recorder) Store(other basestore.ShareableStore) *basestore.Store {
+	return basestore.NewWithHandle(r.Wrap(other.Handle()))
+}
+
+// Wrap returns a handle which executes its queries on handle, and records
+// them.
+func (r *Recorder) Wrap(handle basestore.TransactableHandle) basestore.TransactableHandle {
+	return &recordingHandle{TransactableHandle: handle, recorder: r}
+}
+
+// Queries returns the queries recorded so far, in the order they were
+// executed.
+func (r *Recorder) Queries() []Query {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Query(nil), r.queries...)
+}
+
+// Reset forgets the queries recorded so far, for example the ones which set
+// up the data of a test.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = nil
+}
+
+func (r *Recorder) record(method Method, query string, args []any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, Query{Method: method, SQL: query, Args: args})
+}
+
+// AssertGolden compares the queries recorded so far to the golden file at
+// path, after normalizing them with Normalize. If update is true, the golden
+// file is written instead.
+func (r *Recorder) AssertGolden(t testing.TB, path string, update bool) {
+	t.Helper()
+	testutil.AssertGolden(t, path, update, Format(r.Queries()))
+}
+
+// Format formats queries for a golden file. The queries are normalized, and
+// their arguments are listed below them.
+func Format(queries []Query) string {
+	var b strings.Builder
+	for i, q := range queries {
+		sql, args := Normalize(q.SQL, q.Args)
+		fmt.Fprintf(&b, "-- %d: %s\n%s\n", i+1, q.Method, sql)
+		for j, arg := range args {
+			fmt.Fprintf(&b, "-- $%d = %s\n", j+1, formatArg(arg))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+var (
+	spaces    = regexp.MustCompile(`[ \t]+`)
+	bindVar   = regexp.MustCompile(`\$(\d+)`)
+	emptyLine = regexp.MustCompile(`\n{2,}`)
+)
+
+// Normalize returns query and its arguments in a form which doesn't depend
+// on how the query was written:
+//
+//   - Lines are trimmed, empty lines are dropped, and runs of spaces and tabs
+//     are collapsed to one space. Line breaks are kept, so that the diffs of
+//     golden files stay readable.
+//   - The bind variables are renumbered in the order they appear in, and the
+//     arguments are reordered to match. Arguments which aren't referenced are
+//     kept last.
+func Normalize(query string, args []any) (string, []any) {
+	lines := strings.Split(query, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spaces.ReplaceAllString(line, " "))
+	}
+	query = strings.Trim(emptyLine.ReplaceAllString(strings.Join(lines, "\n"), "\n"), "\n")
+
+	renumbered := map[int]int{}
+	var normalizedArgs []any
+	query = bindVar.ReplaceAllStringFunc(query, func(v string) string {
+		n, _ := strconv.Atoi(v[1:])
+		if n < 1 || n > len(args) {
+			return v
+		}
+		if _, ok := renumbered[n]; !ok {
+			normalizedArgs = append(normalizedArgs, args[n-1])
+			renumbered[n] = len(normalizedArgs)
+		}
+		return "$" + strconv.Itoa(renumbered[n])
+	})
+	for i, arg := range args {
+		if _, ok := renumbered[i+1]; !ok {
+			normalizedArgs = append(normalizedArgs, arg)
+		}
+	}
+	return query, normalizedArgs
+}
+
+// formatArg formats an argument as the value sent to the database. Times are
+// replaced by their type, since they change between runs.
+func formatArg(arg any) string {
+	if valuer, ok := arg.(driver.Valuer); ok {
+		if v, err := valuer.Value(); err == nil {
+			arg = v
+		}
+	}
+	if _, ok := arg.(time.Time); ok {
+		return "time.Time"
+	}
+	if b, ok := arg.([]byte); ok {
+		return strconv.Quote(string(b))
+	}
+	return fmt.Sprintf("%#v", arg)
+}
+
+// recordingHandle records the queries executed on it, and on the
+// transactions opened on it.
+type recordingHandle struct {
+	basestore.TransactableHandle
+	recorder *Recorder
+}
+
+func (h *recordingHandle) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	h.recorder.record(MethodQuery, query, args)
+	return h.TransactableHandle.QueryContext(ctx, query, args...)
+}
+
+func (h *recordingHandle) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	h.recorder.record(MethodQueryRow, query, args)
+	return h.TransactableHandle.QueryRowContext(ctx, query, args...)
+}
+
+func (h *recordingHandle) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	h.recorder.record(MethodExec, query, args)
+	return h.TransactableHandle.ExecContext(ctx, query, args...)
+}
+
+func (h *recordingHandle) Transact(ctx context.Context) (basestore.TransactableHandle, error) {
+	tx, err := h.TransactableHandle.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.recorder.Wrap(tx), nil
+}
@@ -0,0 +1,136 @@
+package querytest
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/regexp"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+var updateRegex = flag.String("update", "", "Update testdata of tests matching the given regex")
+
+func update(name string) bool {
+	if updateRegex == nil || *updateRegex == "" {
+		return false
+	}
+	return regexp.MustCompile(*updateRegex).MatchString(name)
+}
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		query    string
+		args     []any
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "whitespace",
+			query:   "\n\tSELECT  id,\tname\n\n    FROM users  \n\tWHERE id = $1\n",
+			args:    []any{1},
+			wantSQL: "SELECT id, name\nFROM users\nWHERE id = $1",
+			wantArgs: []any{
+				1,
+			},
+		},
+		{
+			name:     "parameter numbering",
+			query:    "SELECT $2, $1, $2, $10",
+			args:     []any{"a", "b"},
+			wantSQL:  "SELECT $1, $2, $1, $10",
+			wantArgs: []any{"b", "a"},
+		},
+		{
+			name:     "unreferenced arguments",
+			query:    "SELECT $2",
+			args:     []any{"a", "b", "c"},
+			wantSQL:  "SELECT $1",
+			wantArgs: []any{"b", "a", "c"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, args := Normalize(tc.query, tc.args)
+			require.Equal(t, tc.wantSQL, sql)
+			require.Equal(t, tc.wantArgs, args)
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+
+	rec := NewRecorder()
+	s := rec.Store(basestore.NewWithHandle(&fakeHandle{}))
+
+	_ = s.Exec(ctx, sqlf.Sprintf("DELETE FROM users WHERE id = %s", 1))
+	rec.Reset()
+
+	_, _ = s.Query(ctx, sqlf.Sprintf(`
+		SELECT id
+		FROM users
+		WHERE %s
+	`, sqlf.Join([]*sqlf.Query{
+		sqlf.Sprintf("username = %s", "alice"),
+		sqlf.Sprintf("id = ANY(%s)", pq.Array([]int32{1, 2})),
+		sqlf.Sprintf("created_at < %s", time.Now()),
+	}, "\n AND ")))
+
+	err := s.WithTransact(ctx, func(tx *basestore.Store) error {
+		_ = tx.QueryRow(ctx, sqlf.Sprintf("SELECT site_admin FROM users WHERE id = %s", 1))
+		return tx.Exec(ctx, sqlf.Sprintf("UPDATE users SET site_admin = %s WHERE id = %s", true, 1))
+	})
+	require.NoError(t, err)
+
+	rec.AssertGolden(t, "testdata/golden/"+t.Name(), update(t.Name()))
+
+	t.Run("concurrent use", func(t *testing.T) {
+		rec := NewRecorder()
+		s := rec.Store(basestore.NewWithHandle(&fakeHandle{}))
+
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = s.Exec(ctx, sqlf.Sprintf("DELETE FROM users WHERE id = %s", i))
+			}()
+		}
+		wg.Wait()
+
+		require.Len(t, rec.Queries(), 10)
+	})
+}
+
+// fakeHandle is a handle which executes no queries.
+type fakeHandle struct {
+	inTransaction bool
+}
+
+func (h *fakeHandle) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (h *fakeHandle) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	return nil
+}
+
+func (h *fakeHandle) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, nil
+}
+
+func (h *fakeHandle) InTransaction() bool { return h.inTransaction }
+
+func (h *fakeHandle) Transact(context.Context) (basestore.TransactableHandle, error) {
+	return &fakeHandle{inTransaction: true}, nil
+}
+
+func (h *fakeHandle) Done(err error) error { return err }
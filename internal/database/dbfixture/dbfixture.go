@@ -0,0 +1,263 @@
+// Package dbfixture creates the records tests need in a database returned by
+// dbtest. Every fixture has defaults for the fields a test doesn't care about,
+// and takes options for the ones it does:
+//
+//	alice := dbfixture.User(t, db, dbfixture.WithUsername("alice"))
+//	repo := dbfixture.Repo(t, db, dbfixture.WithRepoOwner(alice))
+//
+// The records are created with the stores of the database package, so that
+// they are consistent with the records the application creates. The created
+// records are returned as read back from the database.
+package dbfixture
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// sequence makes the default names unique within a test binary.
+var sequence atomic.Int64
+
+func next() int64 { return sequence.Add(1) }
+
+// ctx is the context of the fixtures. The internal actor can read every
+// record, private repos included.
+func ctx() context.Context { return actor.WithInternalActor(context.Background()) }
+
+// UserOption overrides a default of User.
+type UserOption func(*userOptions)
+
+type userOptions struct {
+	user      database.NewUser
+	siteAdmin bool
+}
+
+// WithUsername sets the username, which defaults to a unique "user-N".
+func WithUsername(username string) UserOption {
+	return func(o *userOptions) { o.user.Username = username }
+}
+
+// WithDisplayName sets the display name, which defaults to empty.
+func WithDisplayName(displayName string) UserOption {
+	return func(o *userOptions) { o.user.DisplayName = displayName }
+}
+
+// WithSiteAdmin makes the user a site admin. Users aren't site admins by
+// default, not even the first user created.
+func WithSiteAdmin() UserOption {
+	return func(o *userOptions) { o.siteAdmin = true }
+}
+
+// User creates a user.
+func User(t testing.TB, db database.DB, opts ...UserOption) *types.User {
+	t.Helper()
+
+	o := userOptions{user: database.NewUser{Username: fmt.Sprintf("user-%d", next())}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	user, err := db.Users().Create(ctx(), o.user)
+	if err != nil {
+		t.Fatalf("creating user %q: %s", o.user.Username, err)
+	}
+	// The store makes the first user created a site admin.
+	if err := db.Users().SetIsSiteAdmin(ctx(), user.ID, o.siteAdmin); err != nil {
+		t.Fatalf("setting site admin of user %q: %s", o.user.Username, err)
+	}
+
+	user, err = db.Users().GetByID(ctx(), user.ID)
+	if err != nil {
+		t.Fatalf("reading user %q: %s", o.user.Username, err)
+	}
+	return user
+}
+
+// OrgOption overrides a default of Org.
+type OrgOption func(*orgOptions)
+
+type orgOptions struct {
+	name    string
+	members []*types.User
+}
+
+// WithOrgName sets the name of the org, which defaults to a unique "org-N".
+func WithOrgName(name string) OrgOption {
+	return func(o *orgOptions) { o.name = name }
+}
+
+// WithOrgMembers adds users to the org, which has no members by default.
+func WithOrgMembers(users ...*types.User) OrgOption {
+	return func(o *orgOptions) { o.members = append(o.members, users...) }
+}
+
+// Org creates an org.
+func Org(t testing.TB, db database.DB, opts ...OrgOption) *types.Org {
+	t.Helper()
+
+	o := orgOptions{name: fmt.Sprintf("org-%d", next())}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	org, err := db.Orgs().Create(ctx(), o.name, nil)
+	if err != nil {
+		t.Fatalf("creating org %q: %s", o.name, err)
+	}
+	for _, member := range o.members {
+		if _, err := db.OrgMembers().Create(ctx(), org.ID, member.ID); err != nil {
+			t.Fatalf("adding user %q to org %q: %s", member.Username, o.name, err)
+		}
+	}
+	return org
+}
+
+// ExternalServiceOption overrides a default of ExternalService.
+type ExternalServiceOption func(*types.ExternalService)
+
+// WithExternalServiceCreator sets the user who created the external service,
+// which defaults to none.
+func WithExternalServiceCreator(user *types.User) ExternalServiceOption {
+	return func(es *types.ExternalService) { es.CreatorID = &user.ID }
+}
+
+// WithExternalServiceConfig sets the kind and the config of the external
+// service, which default to a GitHub connection which syncs no repos.
+func WithExternalServiceConfig(kind, config string) ExternalServiceOption {
+	return func(es *types.ExternalService) {
+		es.Kind = kind
+		es.Config = extsvc.NewUnencryptedConfig(config)
+	}
+}
+
+// ExternalService creates an external service, also called code host
+// connection.
+func ExternalService(t testing.TB, db database.DB, opts ...ExternalServiceOption) *types.ExternalService {
+	t.Helper()
+
+	n := next()
+	es := &types.ExternalService{
+		Kind:        extsvc.KindGitHub,
+		DisplayName: fmt.Sprintf("external-service-%d", n),
+		Config:      extsvc.NewUnencryptedConfig(fmt.Sprintf(`{"url": "https://github-%d.example.com", "repositoryQuery": ["none"], "token": "abc"}`, n)),
+	}
+	for _, opt := range opts {
+		opt(es)
+	}
+
+	confGet := func() *conf.Unified { return &conf.Unified{} }
+	if err := db.ExternalServices().Create(ctx(), confGet, es); err != nil {
+		t.Fatalf("creating external service %q: %s", es.DisplayName, err)
+	}
+	return es
+}
+
+// RepoOption overrides a default of Repo.
+type RepoOption func(*repoOptions)
+
+type repoOptions struct {
+	repo             types.Repo
+	owners           []*types.User
+	externalServices []*types.ExternalService
+}
+
+// WithRepoName sets the name of the repo, which defaults to a unique
+// "github.com/sourcegraph/repo-N".
+func WithRepoName(name api.RepoName) RepoOption {
+	return func(o *repoOptions) { o.repo.Name = name }
+}
+
+// WithRepoID sets the ID of the repo, for tests which refer to it by a
+// literal. By default, the database assigns the ID.
+func WithRepoID(id api.RepoID) RepoOption {
+	return func(o *repoOptions) { o.repo.ID = id }
+}
+
+// WithRepoOwner makes the repo private, and grants user access to it. Repos
+// are public by default.
+func WithRepoOwner(user *types.User) RepoOption {
+	return func(o *repoOptions) {
+		o.repo.Private = true
+		o.owners = append(o.owners, user)
+	}
+}
+
+// WithRepoExternalService makes es a source of the repo. Repos have no
+// sources by default.
+func WithRepoExternalService(es *types.ExternalService) RepoOption {
+	return func(o *repoOptions) { o.externalServices = append(o.externalServices, es) }
+}
+
+// Repo creates a repo.
+func Repo(t testing.TB, db database.DB, opts ...RepoOption) *types.Repo {
+	t.Helper()
+
+	o := repoOptions{repo: types.Repo{Name: api.RepoName(fmt.Sprintf("github.com/sourcegraph/repo-%d", next()))}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	repo := &o.repo
+
+	for _, es := range o.externalServices {
+		if repo.Sources == nil {
+			repo.Sources = map[string]*types.SourceInfo{}
+		}
+		repo.Sources[es.URN()] = &types.SourceInfo{
+			ID:       es.URN(),
+			CloneURL: fmt.Sprintf("https://%s.git", repo.Name),
+		}
+	}
+
+	if repo.ID == 0 {
+		if err := db.Repos().Create(ctx(), repo); err != nil {
+			t.Fatalf("creating repo %q: %s", repo.Name, err)
+		}
+	} else {
+		createRepoWithID(t, db, repo)
+	}
+
+	if len(o.owners) > 0 {
+		userIDs := make([]authz.UserIDWithExternalAccountID, 0, len(o.owners))
+		for _, owner := range o.owners {
+			userIDs = append(userIDs, authz.UserIDWithExternalAccountID{UserID: owner.ID})
+		}
+		if _, err := db.Perms().SetRepoPerms(ctx(), int32(repo.ID), userIDs, authz.SourceAPI); err != nil {
+			t.Fatalf("granting access to repo %q: %s", repo.Name, err)
+		}
+	}
+
+	repo, err := db.Repos().Get(ctx(), repo.ID)
+	if err != nil {
+		t.Fatalf("reading repo %q: %s", o.repo.Name, err)
+	}
+	return repo
+}
+
+// createRepoWithID falls back to SQL since the store can't create a repo
+// with a given ID.
+func createRepoWithID(t testing.TB, db database.DB, repo *types.Repo) {
+	t.Helper()
+
+	if len(repo.Sources) > 0 {
+		t.Fatalf("repo %q: WithRepoID can't be combined with WithRepoExternalService", repo.Name)
+	}
+
+	err := db.QueryRowContext(ctx(), `INSERT INTO repo (id, name, private) VALUES ($1, $2, $3) RETURNING id`, repo.ID, repo.Name, repo.Private).Scan(&repo.ID)
+	if err != nil {
+		t.Fatalf("creating repo %q: %s", repo.Name, err)
+	}
+	// Keep the sequence ahead of the IDs, for the repos the store creates.
+	if _, err := db.ExecContext(ctx(), `SELECT setval('repo_id_seq', GREATEST($1, (SELECT last_value FROM repo_id_seq)))`, repo.ID); err != nil {
+		t.Fatalf("advancing the repo IDs: %s", err)
+	}
+}
@@ -0,0 +1,53 @@
+package dbfixture_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbfixture"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestFixtures(t *testing.T) {
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+
+	// The first user isn't a site admin, unlike with the store.
+	user := dbfixture.User(t, db)
+	require.NotZero(t, user.ID)
+	require.False(t, user.SiteAdmin)
+	admin := dbfixture.User(t, db, dbfixture.WithUsername("admin"), dbfixture.WithSiteAdmin())
+	require.Equal(t, "admin", admin.Username)
+	require.True(t, admin.SiteAdmin)
+
+	org := dbfixture.Org(t, db, dbfixture.WithOrgMembers(user))
+	members, err := db.OrgMembers().GetByOrgID(actor.WithInternalActor(ctx), org.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, user.ID, members[0].UserID)
+
+	es := dbfixture.ExternalService(t, db, dbfixture.WithExternalServiceCreator(admin))
+	require.NotZero(t, es.ID)
+
+	// Private repos are only visible to their owners.
+	repo := dbfixture.Repo(t, db, dbfixture.WithRepoOwner(user), dbfixture.WithRepoExternalService(es))
+	require.True(t, repo.Private)
+	require.Contains(t, repo.Sources, es.URN())
+	got, err := db.Repos().Get(actor.WithActor(ctx, actor.FromUser(user.ID)), repo.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.Name, got.Name)
+	other := dbfixture.User(t, db)
+	_, err = db.Repos().Get(actor.WithActor(ctx, actor.FromUser(other.ID)), repo.ID)
+	require.Error(t, err)
+
+	// The repos created with and without an ID don't collide.
+	withID := dbfixture.Repo(t, db, dbfixture.WithRepoID(100), dbfixture.WithRepoName("repoa"))
+	require.EqualValues(t, 100, withID.ID)
+	require.Greater(t, dbfixture.Repo(t, db).ID, withID.ID)
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"database/sql"
 	"sync"
+	"testing"
 	"time"
 
 	uuid "github.com/google/uuid"
@@ -174,6 +175,84 @@ func NewStrictMockAccessRequestStore() *MockAccessRequestStore {
 	}
 }
 
+// NewStrictMockAccessRequestStoreWithT creates a new mock of the
+// AccessRequestStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockAccessRequestStoreWithT(t testing.TB) *MockAccessRequestStore {
+	return &MockAccessRequestStore{
+		CountFunc: &AccessRequestStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 *database.AccessRequestsFilterArgs) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockAccessRequestStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &AccessRequestStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.AccessRequest) (r0 *types.AccessRequest, r1 error) {
+				t.Errorf("unexpected call of MockAccessRequestStore.Create(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &AccessRequestStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockAccessRequestStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetByEmailFunc: &AccessRequestStoreGetByEmailFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *types.AccessRequest, r1 error) {
+				t.Errorf("unexpected call of MockAccessRequestStore.GetByEmail(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &AccessRequestStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *types.AccessRequest, r1 error) {
+				t.Errorf("unexpected call of MockAccessRequestStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &AccessRequestStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockAccessRequestStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &AccessRequestStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 *database.AccessRequestsFilterArgs, v2 *database.PaginationArgs) (r0 []*types.AccessRequest, r1 error) {
+				t.Errorf("unexpected call of MockAccessRequestStore.List(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		UpdateFunc: &AccessRequestStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.AccessRequest) (r0 *types.AccessRequest, r1 error) {
+				t.Errorf("unexpected call of MockAccessRequestStore.Update(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithTransactFunc: &AccessRequestStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.AccessRequestStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockAccessRequestStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockAccessRequestStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockAccessRequestStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockAccessRequestStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockAccessRequestStore.Create")
+	m.DoneFunc.assertExpectations(t, "MockAccessRequestStore.Done")
+	m.GetByEmailFunc.assertExpectations(t, "MockAccessRequestStore.GetByEmail")
+	m.GetByIDFunc.assertExpectations(t, "MockAccessRequestStore.GetByID")
+	m.HandleFunc.assertExpectations(t, "MockAccessRequestStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockAccessRequestStore.List")
+	m.UpdateFunc.assertExpectations(t, "MockAccessRequestStore.Update")
+	m.WithTransactFunc.assertExpectations(t, "MockAccessRequestStore.WithTransact")
+}
+
 // NewMockAccessRequestStoreFrom creates a new mock of the
 // MockAccessRequestStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -288,6 +367,18 @@ func (f *AccessRequestStoreCountFunc) History() []AccessRequestStoreCountFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessRequestStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessRequestStoreCountFuncCall is an object that describes an invocation
 // of method Count on an instance of MockAccessRequestStore.
 type AccessRequestStoreCountFuncCall struct {
@@ -396,6 +487,18 @@ func (f *AccessRequestStoreCreateFunc) History() []AccessRequestStoreCreateFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessRequestStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessRequestStoreCreateFuncCall is an object that describes an
 // invocation of method Create on an instance of MockAccessRequestStore.
 type AccessRequestStoreCreateFuncCall struct {
@@ -504,6 +607,18 @@ func (f *AccessRequestStoreDoneFunc) History() []AccessRequestStoreDoneFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessRequestStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessRequestStoreDoneFuncCall is an object that describes an invocation
 // of method Done on an instance of MockAccessRequestStore.
 type AccessRequestStoreDoneFuncCall struct {
@@ -607,6 +722,18 @@ func (f *AccessRequestStoreGetByEmailFunc) History() []AccessRequestStoreGetByEm
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessRequestStoreGetByEmailFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessRequestStoreGetByEmailFuncCall is an object that describes an
 // invocation of method GetByEmail on an instance of MockAccessRequestStore.
 type AccessRequestStoreGetByEmailFuncCall struct {
@@ -715,6 +842,18 @@ func (f *AccessRequestStoreGetByIDFunc) History() []AccessRequestStoreGetByIDFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessRequestStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessRequestStoreGetByIDFuncCall is an object that describes an
 // invocation of method GetByID on an instance of MockAccessRequestStore.
 type AccessRequestStoreGetByIDFuncCall struct {
@@ -823,6 +962,18 @@ func (f *AccessRequestStoreHandleFunc) History() []AccessRequestStoreHandleFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessRequestStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessRequestStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockAccessRequestStore.
 type AccessRequestStoreHandleFuncCall struct {
@@ -922,6 +1073,18 @@ func (f *AccessRequestStoreListFunc) History() []AccessRequestStoreListFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessRequestStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessRequestStoreListFuncCall is an object that describes an invocation
 // of method List on an instance of MockAccessRequestStore.
 type AccessRequestStoreListFuncCall struct {
@@ -1033,6 +1196,18 @@ func (f *AccessRequestStoreUpdateFunc) History() []AccessRequestStoreUpdateFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessRequestStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessRequestStoreUpdateFuncCall is an object that describes an
 // invocation of method Update on an instance of MockAccessRequestStore.
 type AccessRequestStoreUpdateFuncCall struct {
@@ -1142,6 +1317,18 @@ func (f *AccessRequestStoreWithTransactFunc) History() []AccessRequestStoreWithT
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessRequestStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessRequestStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of
 // MockAccessRequestStore.
@@ -1373,6 +1560,119 @@ func NewStrictMockAccessTokenStore() *MockAccessTokenStore {
 	}
 }
 
+// NewStrictMockAccessTokenStoreWithT creates a new mock of the
+// AccessTokenStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockAccessTokenStoreWithT(t testing.TB) *MockAccessTokenStore {
+	return &MockAccessTokenStore{
+		CountFunc: &AccessTokenStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.AccessTokensListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &AccessTokenStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 []string, v3 string, v4 int32, v5 time.Time) (r0 int64, r1 string, r2 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.Create(%v, %v, %v, %v, %v, %v)", v0, v1, v2, v3, v4, v5)
+				return
+			},
+		},
+		CreateInternalFunc: &AccessTokenStoreCreateInternalFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 []string, v3 string, v4 int32) (r0 int64, r1 string, r2 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.CreateInternal(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		DeleteByIDFunc: &AccessTokenStoreDeleteByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.DeleteByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteByTokenFunc: &AccessTokenStoreDeleteByTokenFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.DeleteByToken(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &AccessTokenStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *database.AccessToken, r1 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByTokenFunc: &AccessTokenStoreGetByTokenFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *database.AccessToken, r1 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.GetByToken(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetOrCreateInternalTokenFunc: &AccessTokenStoreGetOrCreateInternalTokenFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 []string) (r0 []byte, r1 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.GetOrCreateInternalToken(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		HandleFunc: &AccessTokenStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockAccessTokenStore.Handle()")
+				return
+			},
+		},
+		HardDeleteByIDFunc: &AccessTokenStoreHardDeleteByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.HardDeleteByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListFunc: &AccessTokenStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.AccessTokensListOptions) (r0 []*database.AccessToken, r1 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		LookupFunc: &AccessTokenStoreLookupFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 database.TokenLookupOpts) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.Lookup(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		WithFunc: &AccessTokenStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.AccessTokenStore) {
+				t.Errorf("unexpected call of MockAccessTokenStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &AccessTokenStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.AccessTokenStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockAccessTokenStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockAccessTokenStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockAccessTokenStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockAccessTokenStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockAccessTokenStore.Create")
+	m.CreateInternalFunc.assertExpectations(t, "MockAccessTokenStore.CreateInternal")
+	m.DeleteByIDFunc.assertExpectations(t, "MockAccessTokenStore.DeleteByID")
+	m.DeleteByTokenFunc.assertExpectations(t, "MockAccessTokenStore.DeleteByToken")
+	m.GetByIDFunc.assertExpectations(t, "MockAccessTokenStore.GetByID")
+	m.GetByTokenFunc.assertExpectations(t, "MockAccessTokenStore.GetByToken")
+	m.GetOrCreateInternalTokenFunc.assertExpectations(t, "MockAccessTokenStore.GetOrCreateInternalToken")
+	m.HandleFunc.assertExpectations(t, "MockAccessTokenStore.Handle")
+	m.HardDeleteByIDFunc.assertExpectations(t, "MockAccessTokenStore.HardDeleteByID")
+	m.ListFunc.assertExpectations(t, "MockAccessTokenStore.List")
+	m.LookupFunc.assertExpectations(t, "MockAccessTokenStore.Lookup")
+	m.WithFunc.assertExpectations(t, "MockAccessTokenStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockAccessTokenStore.WithTransact")
+}
+
 // NewMockAccessTokenStoreFrom creates a new mock of the
 // MockAccessTokenStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -1502,6 +1802,18 @@ func (f *AccessTokenStoreCountFunc) History() []AccessTokenStoreCountFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreCountFuncCall is an object that describes an invocation
 // of method Count on an instance of MockAccessTokenStore.
 type AccessTokenStoreCountFuncCall struct {
@@ -1610,6 +1922,18 @@ func (f *AccessTokenStoreCreateFunc) History() []AccessTokenStoreCreateFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreCreateFuncCall is an object that describes an invocation
 // of method Create on an instance of MockAccessTokenStore.
 type AccessTokenStoreCreateFuncCall struct {
@@ -1734,6 +2058,18 @@ func (f *AccessTokenStoreCreateInternalFunc) History() []AccessTokenStoreCreateI
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreCreateInternalFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreCreateInternalFuncCall is an object that describes an
 // invocation of method CreateInternal on an instance of
 // MockAccessTokenStore.
@@ -1855,6 +2191,18 @@ func (f *AccessTokenStoreDeleteByIDFunc) History() []AccessTokenStoreDeleteByIDF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreDeleteByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreDeleteByIDFuncCall is an object that describes an
 // invocation of method DeleteByID on an instance of MockAccessTokenStore.
 type AccessTokenStoreDeleteByIDFuncCall struct {
@@ -1961,6 +2309,18 @@ func (f *AccessTokenStoreDeleteByTokenFunc) History() []AccessTokenStoreDeleteBy
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreDeleteByTokenFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreDeleteByTokenFuncCall is an object that describes an
 // invocation of method DeleteByToken on an instance of
 // MockAccessTokenStore.
@@ -2067,6 +2427,18 @@ func (f *AccessTokenStoreGetByIDFunc) History() []AccessTokenStoreGetByIDFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreGetByIDFuncCall is an object that describes an invocation
 // of method GetByID on an instance of MockAccessTokenStore.
 type AccessTokenStoreGetByIDFuncCall struct {
@@ -2175,6 +2547,18 @@ func (f *AccessTokenStoreGetByTokenFunc) History() []AccessTokenStoreGetByTokenF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreGetByTokenFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreGetByTokenFuncCall is an object that describes an
 // invocation of method GetByToken on an instance of MockAccessTokenStore.
 type AccessTokenStoreGetByTokenFuncCall struct {
@@ -2286,6 +2670,18 @@ func (f *AccessTokenStoreGetOrCreateInternalTokenFunc) History() []AccessTokenSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreGetOrCreateInternalTokenFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreGetOrCreateInternalTokenFuncCall is an object that
 // describes an invocation of method GetOrCreateInternalToken on an instance
 // of MockAccessTokenStore.
@@ -2398,6 +2794,18 @@ func (f *AccessTokenStoreHandleFunc) History() []AccessTokenStoreHandleFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockAccessTokenStore.
 type AccessTokenStoreHandleFuncCall struct {
@@ -2498,6 +2906,18 @@ func (f *AccessTokenStoreHardDeleteByIDFunc) History() []AccessTokenStoreHardDel
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreHardDeleteByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreHardDeleteByIDFuncCall is an object that describes an
 // invocation of method HardDeleteByID on an instance of
 // MockAccessTokenStore.
@@ -2604,6 +3024,18 @@ func (f *AccessTokenStoreListFunc) History() []AccessTokenStoreListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreListFuncCall is an object that describes an invocation of
 // method List on an instance of MockAccessTokenStore.
 type AccessTokenStoreListFuncCall struct {
@@ -2712,6 +3144,18 @@ func (f *AccessTokenStoreLookupFunc) History() []AccessTokenStoreLookupFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreLookupFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreLookupFuncCall is an object that describes an invocation
 // of method Lookup on an instance of MockAccessTokenStore.
 type AccessTokenStoreLookupFuncCall struct {
@@ -2823,6 +3267,18 @@ func (f *AccessTokenStoreWithFunc) History() []AccessTokenStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockAccessTokenStore.
 type AccessTokenStoreWithFuncCall struct {
@@ -2926,6 +3382,18 @@ func (f *AccessTokenStoreWithTransactFunc) History() []AccessTokenStoreWithTrans
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AccessTokenStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AccessTokenStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockAccessTokenStore.
 type AccessTokenStoreWithTransactFuncCall struct {
@@ -3028,6 +3496,49 @@ func NewStrictMockAssignedOwnersStore() *MockAssignedOwnersStore {
 	}
 }
 
+// NewStrictMockAssignedOwnersStoreWithT creates a new mock of the
+// AssignedOwnersStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockAssignedOwnersStoreWithT(t testing.TB) *MockAssignedOwnersStore {
+	return &MockAssignedOwnersStore{
+		CountAssignedOwnersFunc: &AssignedOwnersStoreCountAssignedOwnersFunc{
+			defaultHook: func(v0 context.Context) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockAssignedOwnersStore.CountAssignedOwners(%v)", v0)
+				return
+			},
+		},
+		DeleteOwnerFunc: &AssignedOwnersStoreDeleteOwnerFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 api.RepoID, v3 string) (r0 error) {
+				t.Errorf("unexpected call of MockAssignedOwnersStore.DeleteOwner(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		InsertFunc: &AssignedOwnersStoreInsertFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 api.RepoID, v3 string, v4 int32) (r0 error) {
+				t.Errorf("unexpected call of MockAssignedOwnersStore.Insert(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		ListAssignedOwnersForRepoFunc: &AssignedOwnersStoreListAssignedOwnersForRepoFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 []*database.AssignedOwnerSummary, r1 error) {
+				t.Errorf("unexpected call of MockAssignedOwnersStore.ListAssignedOwnersForRepo(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockAssignedOwnersStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockAssignedOwnersStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountAssignedOwnersFunc.assertExpectations(t, "MockAssignedOwnersStore.CountAssignedOwners")
+	m.DeleteOwnerFunc.assertExpectations(t, "MockAssignedOwnersStore.DeleteOwner")
+	m.InsertFunc.assertExpectations(t, "MockAssignedOwnersStore.Insert")
+	m.ListAssignedOwnersForRepoFunc.assertExpectations(t, "MockAssignedOwnersStore.ListAssignedOwnersForRepo")
+}
+
 // NewMockAssignedOwnersStoreFrom creates a new mock of the
 // MockAssignedOwnersStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -3130,6 +3641,18 @@ func (f *AssignedOwnersStoreCountAssignedOwnersFunc) History() []AssignedOwnersS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AssignedOwnersStoreCountAssignedOwnersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AssignedOwnersStoreCountAssignedOwnersFuncCall is an object that
 // describes an invocation of method CountAssignedOwners on an instance of
 // MockAssignedOwnersStore.
@@ -3237,6 +3760,18 @@ func (f *AssignedOwnersStoreDeleteOwnerFunc) History() []AssignedOwnersStoreDele
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AssignedOwnersStoreDeleteOwnerFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AssignedOwnersStoreDeleteOwnerFuncCall is an object that describes an
 // invocation of method DeleteOwner on an instance of
 // MockAssignedOwnersStore.
@@ -3349,6 +3884,18 @@ func (f *AssignedOwnersStoreInsertFunc) History() []AssignedOwnersStoreInsertFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AssignedOwnersStoreInsertFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AssignedOwnersStoreInsertFuncCall is an object that describes an
 // invocation of method Insert on an instance of MockAssignedOwnersStore.
 type AssignedOwnersStoreInsertFuncCall struct {
@@ -3466,6 +4013,18 @@ func (f *AssignedOwnersStoreListAssignedOwnersForRepoFunc) History() []AssignedO
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AssignedOwnersStoreListAssignedOwnersForRepoFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AssignedOwnersStoreListAssignedOwnersForRepoFuncCall is an object that
 // describes an invocation of method ListAssignedOwnersForRepo on an
 // instance of MockAssignedOwnersStore.
@@ -3558,6 +4117,42 @@ func NewStrictMockAssignedTeamsStore() *MockAssignedTeamsStore {
 	}
 }
 
+// NewStrictMockAssignedTeamsStoreWithT creates a new mock of the
+// AssignedTeamsStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockAssignedTeamsStoreWithT(t testing.TB) *MockAssignedTeamsStore {
+	return &MockAssignedTeamsStore{
+		DeleteOwnerTeamFunc: &AssignedTeamsStoreDeleteOwnerTeamFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 api.RepoID, v3 string) (r0 error) {
+				t.Errorf("unexpected call of MockAssignedTeamsStore.DeleteOwnerTeam(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		InsertFunc: &AssignedTeamsStoreInsertFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 api.RepoID, v3 string, v4 int32) (r0 error) {
+				t.Errorf("unexpected call of MockAssignedTeamsStore.Insert(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		ListAssignedTeamsForRepoFunc: &AssignedTeamsStoreListAssignedTeamsForRepoFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 []*database.AssignedTeamSummary, r1 error) {
+				t.Errorf("unexpected call of MockAssignedTeamsStore.ListAssignedTeamsForRepo(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockAssignedTeamsStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockAssignedTeamsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.DeleteOwnerTeamFunc.assertExpectations(t, "MockAssignedTeamsStore.DeleteOwnerTeam")
+	m.InsertFunc.assertExpectations(t, "MockAssignedTeamsStore.Insert")
+	m.ListAssignedTeamsForRepoFunc.assertExpectations(t, "MockAssignedTeamsStore.ListAssignedTeamsForRepo")
+}
+
 // NewMockAssignedTeamsStoreFrom creates a new mock of the
 // MockAssignedTeamsStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -3656,6 +4251,18 @@ func (f *AssignedTeamsStoreDeleteOwnerTeamFunc) History() []AssignedTeamsStoreDe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AssignedTeamsStoreDeleteOwnerTeamFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AssignedTeamsStoreDeleteOwnerTeamFuncCall is an object that describes an
 // invocation of method DeleteOwnerTeam on an instance of
 // MockAssignedTeamsStore.
@@ -3768,6 +4375,18 @@ func (f *AssignedTeamsStoreInsertFunc) History() []AssignedTeamsStoreInsertFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AssignedTeamsStoreInsertFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AssignedTeamsStoreInsertFuncCall is an object that describes an
 // invocation of method Insert on an instance of MockAssignedTeamsStore.
 type AssignedTeamsStoreInsertFuncCall struct {
@@ -3885,6 +4504,18 @@ func (f *AssignedTeamsStoreListAssignedTeamsForRepoFunc) History() []AssignedTea
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AssignedTeamsStoreListAssignedTeamsForRepoFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AssignedTeamsStoreListAssignedTeamsForRepoFuncCall is an object that
 // describes an invocation of method ListAssignedTeamsForRepo on an instance
 // of MockAssignedTeamsStore.
@@ -3988,6 +4619,48 @@ func NewStrictMockAuthzStore() *MockAuthzStore {
 	}
 }
 
+// NewStrictMockAuthzStoreWithT creates a new mock of the AuthzStore interface.
+// All methods fail t with their arguments and return zero values for all
+// results, unless overwritten.
+func NewStrictMockAuthzStoreWithT(t testing.TB) *MockAuthzStore {
+	return &MockAuthzStore{
+		AuthorizedReposFunc: &AuthzStoreAuthorizedReposFunc{
+			defaultHook: func(v0 context.Context, v1 *database.AuthorizedReposArgs) (r0 []*types.Repo, r1 error) {
+				t.Errorf("unexpected call of MockAuthzStore.AuthorizedRepos(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GrantPendingPermissionsFunc: &AuthzStoreGrantPendingPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 *database.GrantPendingPermissionsArgs) (r0 error) {
+				t.Errorf("unexpected call of MockAuthzStore.GrantPendingPermissions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RevokeUserPermissionsFunc: &AuthzStoreRevokeUserPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 *database.RevokeUserPermissionsArgs) (r0 error) {
+				t.Errorf("unexpected call of MockAuthzStore.RevokeUserPermissions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RevokeUserPermissionsListFunc: &AuthzStoreRevokeUserPermissionsListFunc{
+			defaultHook: func(v0 context.Context, v1 []*database.RevokeUserPermissionsArgs) (r0 error) {
+				t.Errorf("unexpected call of MockAuthzStore.RevokeUserPermissionsList(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockAuthzStore instance with PushHook or PushReturn which was not called.
+func (m *MockAuthzStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.AuthorizedReposFunc.assertExpectations(t, "MockAuthzStore.AuthorizedRepos")
+	m.GrantPendingPermissionsFunc.assertExpectations(t, "MockAuthzStore.GrantPendingPermissions")
+	m.RevokeUserPermissionsFunc.assertExpectations(t, "MockAuthzStore.RevokeUserPermissions")
+	m.RevokeUserPermissionsListFunc.assertExpectations(t, "MockAuthzStore.RevokeUserPermissionsList")
+}
+
 // NewMockAuthzStoreFrom creates a new mock of the MockAuthzStore interface.
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockAuthzStoreFrom(i database.AuthzStore) *MockAuthzStore {
@@ -4086,6 +4759,18 @@ func (f *AuthzStoreAuthorizedReposFunc) History() []AuthzStoreAuthorizedReposFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AuthzStoreAuthorizedReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AuthzStoreAuthorizedReposFuncCall is an object that describes an
 // invocation of method AuthorizedRepos on an instance of MockAuthzStore.
 type AuthzStoreAuthorizedReposFuncCall struct {
@@ -4196,6 +4881,18 @@ func (f *AuthzStoreGrantPendingPermissionsFunc) History() []AuthzStoreGrantPendi
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AuthzStoreGrantPendingPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AuthzStoreGrantPendingPermissionsFuncCall is an object that describes an
 // invocation of method GrantPendingPermissions on an instance of
 // MockAuthzStore.
@@ -4304,6 +5001,18 @@ func (f *AuthzStoreRevokeUserPermissionsFunc) History() []AuthzStoreRevokeUserPe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AuthzStoreRevokeUserPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AuthzStoreRevokeUserPermissionsFuncCall is an object that describes an
 // invocation of method RevokeUserPermissions on an instance of
 // MockAuthzStore.
@@ -4412,6 +5121,18 @@ func (f *AuthzStoreRevokeUserPermissionsListFunc) History() []AuthzStoreRevokeUs
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *AuthzStoreRevokeUserPermissionsListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // AuthzStoreRevokeUserPermissionsListFuncCall is an object that describes
 // an invocation of method RevokeUserPermissionsList on an instance of
 // MockAuthzStore.
@@ -4527,6 +5248,56 @@ func NewStrictMockBitbucketProjectPermissionsStore() *MockBitbucketProjectPermis
 	}
 }
 
+// NewStrictMockBitbucketProjectPermissionsStoreWithT creates a new mock of the
+// BitbucketProjectPermissionsStore interface. All methods fail t with their
+// arguments and return zero values for all results, unless overwritten.
+func NewStrictMockBitbucketProjectPermissionsStoreWithT(t testing.TB) *MockBitbucketProjectPermissionsStore {
+	return &MockBitbucketProjectPermissionsStore{
+		EnqueueFunc: &BitbucketProjectPermissionsStoreEnqueueFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 int64, v3 []types.UserPermission, v4 bool) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockBitbucketProjectPermissionsStore.Enqueue(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		HandleFunc: &BitbucketProjectPermissionsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockBitbucketProjectPermissionsStore.Handle()")
+				return
+			},
+		},
+		ListJobsFunc: &BitbucketProjectPermissionsStoreListJobsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListJobsOptions) (r0 []*types.BitbucketProjectPermissionJob, r1 error) {
+				t.Errorf("unexpected call of MockBitbucketProjectPermissionsStore.ListJobs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &BitbucketProjectPermissionsStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.BitbucketProjectPermissionsStore) {
+				t.Errorf("unexpected call of MockBitbucketProjectPermissionsStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &BitbucketProjectPermissionsStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.BitbucketProjectPermissionsStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockBitbucketProjectPermissionsStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockBitbucketProjectPermissionsStore instance with PushHook or PushReturn
+// which was not called.
+func (m *MockBitbucketProjectPermissionsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.EnqueueFunc.assertExpectations(t, "MockBitbucketProjectPermissionsStore.Enqueue")
+	m.HandleFunc.assertExpectations(t, "MockBitbucketProjectPermissionsStore.Handle")
+	m.ListJobsFunc.assertExpectations(t, "MockBitbucketProjectPermissionsStore.ListJobs")
+	m.WithFunc.assertExpectations(t, "MockBitbucketProjectPermissionsStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockBitbucketProjectPermissionsStore.WithTransact")
+}
+
 // NewMockBitbucketProjectPermissionsStoreFrom creates a new mock of the
 // MockBitbucketProjectPermissionsStore interface. All methods delegate to
 // the given implementation, unless overwritten.
@@ -4632,6 +5403,18 @@ func (f *BitbucketProjectPermissionsStoreEnqueueFunc) History() []BitbucketProje
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *BitbucketProjectPermissionsStoreEnqueueFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // BitbucketProjectPermissionsStoreEnqueueFuncCall is an object that
 // describes an invocation of method Enqueue on an instance of
 // MockBitbucketProjectPermissionsStore.
@@ -4753,6 +5536,18 @@ func (f *BitbucketProjectPermissionsStoreHandleFunc) History() []BitbucketProjec
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *BitbucketProjectPermissionsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // BitbucketProjectPermissionsStoreHandleFuncCall is an object that
 // describes an invocation of method Handle on an instance of
 // MockBitbucketProjectPermissionsStore.
@@ -4856,6 +5651,18 @@ func (f *BitbucketProjectPermissionsStoreListJobsFunc) History() []BitbucketProj
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *BitbucketProjectPermissionsStoreListJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // BitbucketProjectPermissionsStoreListJobsFuncCall is an object that
 // describes an invocation of method ListJobs on an instance of
 // MockBitbucketProjectPermissionsStore.
@@ -4968,6 +5775,18 @@ func (f *BitbucketProjectPermissionsStoreWithFunc) History() []BitbucketProjectP
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *BitbucketProjectPermissionsStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // BitbucketProjectPermissionsStoreWithFuncCall is an object that describes
 // an invocation of method With on an instance of
 // MockBitbucketProjectPermissionsStore.
@@ -5074,6 +5893,18 @@ func (f *BitbucketProjectPermissionsStoreWithTransactFunc) History() []Bitbucket
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *BitbucketProjectPermissionsStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // BitbucketProjectPermissionsStoreWithTransactFuncCall is an object that
 // describes an invocation of method WithTransact on an instance of
 // MockBitbucketProjectPermissionsStore.
@@ -5251,6 +6082,90 @@ func NewStrictMockCodeHostStore() *MockCodeHostStore {
 	}
 }
 
+// NewStrictMockCodeHostStoreWithT creates a new mock of the CodeHostStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockCodeHostStoreWithT(t testing.TB) *MockCodeHostStore {
+	return &MockCodeHostStore{
+		CountFunc: &CodeHostStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListCodeHostsOpts) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockCodeHostStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &CodeHostStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.CodeHost) (r0 error) {
+				t.Errorf("unexpected call of MockCodeHostStore.Create(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteFunc: &CodeHostStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockCodeHostStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &CodeHostStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *types.CodeHost, r1 error) {
+				t.Errorf("unexpected call of MockCodeHostStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByURLFunc: &CodeHostStoreGetByURLFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *types.CodeHost, r1 error) {
+				t.Errorf("unexpected call of MockCodeHostStore.GetByURL(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &CodeHostStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockCodeHostStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &CodeHostStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListCodeHostsOpts) (r0 []*types.CodeHost, r1 int32, r2 error) {
+				t.Errorf("unexpected call of MockCodeHostStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateFunc: &CodeHostStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.CodeHost) (r0 error) {
+				t.Errorf("unexpected call of MockCodeHostStore.Update(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &CodeHostStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.CodeHostStore) {
+				t.Errorf("unexpected call of MockCodeHostStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &CodeHostStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.CodeHostStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockCodeHostStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockCodeHostStore instance with PushHook or PushReturn which was not called.
+func (m *MockCodeHostStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockCodeHostStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockCodeHostStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockCodeHostStore.Delete")
+	m.GetByIDFunc.assertExpectations(t, "MockCodeHostStore.GetByID")
+	m.GetByURLFunc.assertExpectations(t, "MockCodeHostStore.GetByURL")
+	m.HandleFunc.assertExpectations(t, "MockCodeHostStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockCodeHostStore.List")
+	m.UpdateFunc.assertExpectations(t, "MockCodeHostStore.Update")
+	m.WithFunc.assertExpectations(t, "MockCodeHostStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockCodeHostStore.WithTransact")
+}
+
 // NewMockCodeHostStoreFrom creates a new mock of the MockCodeHostStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -5367,6 +6282,18 @@ func (f *CodeHostStoreCountFunc) History() []CodeHostStoreCountFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeHostStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeHostStoreCountFuncCall is an object that describes an invocation of
 // method Count on an instance of MockCodeHostStore.
 type CodeHostStoreCountFuncCall struct {
@@ -5474,6 +6401,18 @@ func (f *CodeHostStoreCreateFunc) History() []CodeHostStoreCreateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeHostStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeHostStoreCreateFuncCall is an object that describes an invocation of
 // method Create on an instance of MockCodeHostStore.
 type CodeHostStoreCreateFuncCall struct {
@@ -5578,6 +6517,18 @@ func (f *CodeHostStoreDeleteFunc) History() []CodeHostStoreDeleteFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeHostStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeHostStoreDeleteFuncCall is an object that describes an invocation of
 // method Delete on an instance of MockCodeHostStore.
 type CodeHostStoreDeleteFuncCall struct {
@@ -5683,6 +6634,18 @@ func (f *CodeHostStoreGetByIDFunc) History() []CodeHostStoreGetByIDFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeHostStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeHostStoreGetByIDFuncCall is an object that describes an invocation of
 // method GetByID on an instance of MockCodeHostStore.
 type CodeHostStoreGetByIDFuncCall struct {
@@ -5791,6 +6754,18 @@ func (f *CodeHostStoreGetByURLFunc) History() []CodeHostStoreGetByURLFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeHostStoreGetByURLFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeHostStoreGetByURLFuncCall is an object that describes an invocation
 // of method GetByURL on an instance of MockCodeHostStore.
 type CodeHostStoreGetByURLFuncCall struct {
@@ -5898,6 +6873,18 @@ func (f *CodeHostStoreHandleFunc) History() []CodeHostStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeHostStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeHostStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockCodeHostStore.
 type CodeHostStoreHandleFuncCall struct {
@@ -5996,6 +6983,18 @@ func (f *CodeHostStoreListFunc) History() []CodeHostStoreListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeHostStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeHostStoreListFuncCall is an object that describes an invocation of
 // method List on an instance of MockCodeHostStore.
 type CodeHostStoreListFuncCall struct {
@@ -6106,6 +7105,18 @@ func (f *CodeHostStoreUpdateFunc) History() []CodeHostStoreUpdateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeHostStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeHostStoreUpdateFuncCall is an object that describes an invocation of
 // method Update on an instance of MockCodeHostStore.
 type CodeHostStoreUpdateFuncCall struct {
@@ -6210,6 +7221,18 @@ func (f *CodeHostStoreWithFunc) History() []CodeHostStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeHostStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeHostStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockCodeHostStore.
 type CodeHostStoreWithFuncCall struct {
@@ -6312,6 +7335,18 @@ func (f *CodeHostStoreWithTransactFunc) History() []CodeHostStoreWithTransactFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeHostStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeHostStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockCodeHostStore.
 type CodeHostStoreWithTransactFuncCall struct {
@@ -7067,6 +8102,399 @@ func NewStrictMockCodeMonitorStore() *MockCodeMonitorStore {
 	}
 }
 
+// NewStrictMockCodeMonitorStoreWithT creates a new mock of the
+// CodeMonitorStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockCodeMonitorStoreWithT(t testing.TB) *MockCodeMonitorStore {
+	return &MockCodeMonitorStore{
+		ClockFunc: &CodeMonitorStoreClockFunc{
+			defaultHook: func() (r0 func() time.Time) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.Clock()")
+				return
+			},
+		},
+		CountActionJobsFunc: &CodeMonitorStoreCountActionJobsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListActionJobsOpts) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CountActionJobs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountMonitorsFunc: &CodeMonitorStoreCountMonitorsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListMonitorsOpts) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CountMonitors(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountQueryTriggerJobsFunc: &CodeMonitorStoreCountQueryTriggerJobsFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CountQueryTriggerJobs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountRecipientsFunc: &CodeMonitorStoreCountRecipientsFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CountRecipients(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountSlackWebhookActionsFunc: &CodeMonitorStoreCountSlackWebhookActionsFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CountSlackWebhookActions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountWebhookActionsFunc: &CodeMonitorStoreCountWebhookActionsFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CountWebhookActions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateEmailActionFunc: &CodeMonitorStoreCreateEmailActionFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 *database.EmailActionArgs) (r0 *database.EmailAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CreateEmailAction(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreateMonitorFunc: &CodeMonitorStoreCreateMonitorFunc{
+			defaultHook: func(v0 context.Context, v1 database.MonitorArgs) (r0 *database.Monitor, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CreateMonitor(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateQueryTriggerFunc: &CodeMonitorStoreCreateQueryTriggerFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 string) (r0 *database.QueryTrigger, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CreateQueryTrigger(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreateRecipientFunc: &CodeMonitorStoreCreateRecipientFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 *int32, v3 *int32) (r0 *database.Recipient, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CreateRecipient(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		CreateSlackWebhookActionFunc: &CodeMonitorStoreCreateSlackWebhookActionFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 bool, v3 bool, v4 string) (r0 *database.SlackWebhookAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CreateSlackWebhookAction(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		CreateWebhookActionFunc: &CodeMonitorStoreCreateWebhookActionFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 bool, v3 bool, v4 string) (r0 *database.WebhookAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.CreateWebhookAction(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		DeleteEmailActionsFunc: &CodeMonitorStoreDeleteEmailActionsFunc{
+			defaultHook: func(v0 context.Context, v1 []int64, v2 int64) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.DeleteEmailActions(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DeleteMonitorFunc: &CodeMonitorStoreDeleteMonitorFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.DeleteMonitor(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteOldTriggerJobsFunc: &CodeMonitorStoreDeleteOldTriggerJobsFunc{
+			defaultHook: func(v0 context.Context, v1 int) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.DeleteOldTriggerJobs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteRecipientsFunc: &CodeMonitorStoreDeleteRecipientsFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.DeleteRecipients(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteSlackWebhookActionsFunc: &CodeMonitorStoreDeleteSlackWebhookActionsFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 ...int64) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.DeleteSlackWebhookActions(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DeleteWebhookActionsFunc: &CodeMonitorStoreDeleteWebhookActionsFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 ...int64) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.DeleteWebhookActions(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DoneFunc: &CodeMonitorStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.Done(%v)", v0)
+				return
+			},
+		},
+		EnqueueActionJobsForMonitorFunc: &CodeMonitorStoreEnqueueActionJobsForMonitorFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 int32) (r0 []*database.ActionJob, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.EnqueueActionJobsForMonitor(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		EnqueueQueryTriggerJobsFunc: &CodeMonitorStoreEnqueueQueryTriggerJobsFunc{
+			defaultHook: func(v0 context.Context) (r0 []*database.TriggerJob, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.EnqueueQueryTriggerJobs(%v)", v0)
+				return
+			},
+		},
+		ExecFunc: &CodeMonitorStoreExecFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.Exec(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetActionJobFunc: &CodeMonitorStoreGetActionJobFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *database.ActionJob, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.GetActionJob(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetActionJobMetadataFunc: &CodeMonitorStoreGetActionJobMetadataFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *database.ActionJobMetadata, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.GetActionJobMetadata(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetEmailActionFunc: &CodeMonitorStoreGetEmailActionFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *database.EmailAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.GetEmailAction(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetLastSearchedFunc: &CodeMonitorStoreGetLastSearchedFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 api.RepoID) (r0 []string, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.GetLastSearched(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		GetMonitorFunc: &CodeMonitorStoreGetMonitorFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *database.Monitor, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.GetMonitor(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetQueryTriggerForJobFunc: &CodeMonitorStoreGetQueryTriggerForJobFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *database.QueryTrigger, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.GetQueryTriggerForJob(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetQueryTriggerForMonitorFunc: &CodeMonitorStoreGetQueryTriggerForMonitorFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *database.QueryTrigger, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.GetQueryTriggerForMonitor(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetSlackWebhookActionFunc: &CodeMonitorStoreGetSlackWebhookActionFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *database.SlackWebhookAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.GetSlackWebhookAction(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetWebhookActionFunc: &CodeMonitorStoreGetWebhookActionFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *database.WebhookAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.GetWebhookAction(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &CodeMonitorStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.Handle()")
+				return
+			},
+		},
+		HasAnyLastSearchedFunc: &CodeMonitorStoreHasAnyLastSearchedFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.HasAnyLastSearched(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListActionJobsFunc: &CodeMonitorStoreListActionJobsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListActionJobsOpts) (r0 []*database.ActionJob, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.ListActionJobs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListEmailActionsFunc: &CodeMonitorStoreListEmailActionsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListActionsOpts) (r0 []*database.EmailAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.ListEmailActions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListMonitorsFunc: &CodeMonitorStoreListMonitorsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListMonitorsOpts) (r0 []*database.Monitor, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.ListMonitors(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListQueryTriggerJobsFunc: &CodeMonitorStoreListQueryTriggerJobsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListTriggerJobsOpts) (r0 []*database.TriggerJob, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.ListQueryTriggerJobs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListRecipientsFunc: &CodeMonitorStoreListRecipientsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListRecipientsOpts) (r0 []*database.Recipient, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.ListRecipients(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListSlackWebhookActionsFunc: &CodeMonitorStoreListSlackWebhookActionsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListActionsOpts) (r0 []*database.SlackWebhookAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.ListSlackWebhookActions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListWebhookActionsFunc: &CodeMonitorStoreListWebhookActionsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListActionsOpts) (r0 []*database.WebhookAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.ListWebhookActions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		NowFunc: &CodeMonitorStoreNowFunc{
+			defaultHook: func() (r0 time.Time) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.Now()")
+				return
+			},
+		},
+		ResetQueryTriggerTimestampsFunc: &CodeMonitorStoreResetQueryTriggerTimestampsFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.ResetQueryTriggerTimestamps(%v, %v)", v0, v1)
+				return
+			},
+		},
+		SetQueryTriggerNextRunFunc: &CodeMonitorStoreSetQueryTriggerNextRunFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 time.Time, v3 time.Time) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.SetQueryTriggerNextRun(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		TransactFunc: &CodeMonitorStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.CodeMonitorStore, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.Transact(%v)", v0)
+				return
+			},
+		},
+		UpdateEmailActionFunc: &CodeMonitorStoreUpdateEmailActionFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 *database.EmailActionArgs) (r0 *database.EmailAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.UpdateEmailAction(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		UpdateMonitorFunc: &CodeMonitorStoreUpdateMonitorFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 database.MonitorArgs) (r0 *database.Monitor, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.UpdateMonitor(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		UpdateMonitorEnabledFunc: &CodeMonitorStoreUpdateMonitorEnabledFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 bool) (r0 *database.Monitor, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.UpdateMonitorEnabled(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		UpdateQueryTriggerFunc: &CodeMonitorStoreUpdateQueryTriggerFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 string) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.UpdateQueryTrigger(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		UpdateSlackWebhookActionFunc: &CodeMonitorStoreUpdateSlackWebhookActionFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 bool, v3 bool, v4 string) (r0 *database.SlackWebhookAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.UpdateSlackWebhookAction(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		UpdateTriggerJobWithLogsFunc: &CodeMonitorStoreUpdateTriggerJobWithLogsFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 database.TriggerJobLogs) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.UpdateTriggerJobWithLogs(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		UpdateTriggerJobWithResultsFunc: &CodeMonitorStoreUpdateTriggerJobWithResultsFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string, v3 []*result.CommitMatch) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.UpdateTriggerJobWithResults(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		UpdateWebhookActionFunc: &CodeMonitorStoreUpdateWebhookActionFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 bool, v3 bool, v4 string) (r0 *database.WebhookAction, r1 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.UpdateWebhookAction(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		UpsertLastSearchedFunc: &CodeMonitorStoreUpsertLastSearchedFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 api.RepoID, v3 []string) (r0 error) {
+				t.Errorf("unexpected call of MockCodeMonitorStore.UpsertLastSearched(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockCodeMonitorStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockCodeMonitorStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.ClockFunc.assertExpectations(t, "MockCodeMonitorStore.Clock")
+	m.CountActionJobsFunc.assertExpectations(t, "MockCodeMonitorStore.CountActionJobs")
+	m.CountMonitorsFunc.assertExpectations(t, "MockCodeMonitorStore.CountMonitors")
+	m.CountQueryTriggerJobsFunc.assertExpectations(t, "MockCodeMonitorStore.CountQueryTriggerJobs")
+	m.CountRecipientsFunc.assertExpectations(t, "MockCodeMonitorStore.CountRecipients")
+	m.CountSlackWebhookActionsFunc.assertExpectations(t, "MockCodeMonitorStore.CountSlackWebhookActions")
+	m.CountWebhookActionsFunc.assertExpectations(t, "MockCodeMonitorStore.CountWebhookActions")
+	m.CreateEmailActionFunc.assertExpectations(t, "MockCodeMonitorStore.CreateEmailAction")
+	m.CreateMonitorFunc.assertExpectations(t, "MockCodeMonitorStore.CreateMonitor")
+	m.CreateQueryTriggerFunc.assertExpectations(t, "MockCodeMonitorStore.CreateQueryTrigger")
+	m.CreateRecipientFunc.assertExpectations(t, "MockCodeMonitorStore.CreateRecipient")
+	m.CreateSlackWebhookActionFunc.assertExpectations(t, "MockCodeMonitorStore.CreateSlackWebhookAction")
+	m.CreateWebhookActionFunc.assertExpectations(t, "MockCodeMonitorStore.CreateWebhookAction")
+	m.DeleteEmailActionsFunc.assertExpectations(t, "MockCodeMonitorStore.DeleteEmailActions")
+	m.DeleteMonitorFunc.assertExpectations(t, "MockCodeMonitorStore.DeleteMonitor")
+	m.DeleteOldTriggerJobsFunc.assertExpectations(t, "MockCodeMonitorStore.DeleteOldTriggerJobs")
+	m.DeleteRecipientsFunc.assertExpectations(t, "MockCodeMonitorStore.DeleteRecipients")
+	m.DeleteSlackWebhookActionsFunc.assertExpectations(t, "MockCodeMonitorStore.DeleteSlackWebhookActions")
+	m.DeleteWebhookActionsFunc.assertExpectations(t, "MockCodeMonitorStore.DeleteWebhookActions")
+	m.DoneFunc.assertExpectations(t, "MockCodeMonitorStore.Done")
+	m.EnqueueActionJobsForMonitorFunc.assertExpectations(t, "MockCodeMonitorStore.EnqueueActionJobsForMonitor")
+	m.EnqueueQueryTriggerJobsFunc.assertExpectations(t, "MockCodeMonitorStore.EnqueueQueryTriggerJobs")
+	m.ExecFunc.assertExpectations(t, "MockCodeMonitorStore.Exec")
+	m.GetActionJobFunc.assertExpectations(t, "MockCodeMonitorStore.GetActionJob")
+	m.GetActionJobMetadataFunc.assertExpectations(t, "MockCodeMonitorStore.GetActionJobMetadata")
+	m.GetEmailActionFunc.assertExpectations(t, "MockCodeMonitorStore.GetEmailAction")
+	m.GetLastSearchedFunc.assertExpectations(t, "MockCodeMonitorStore.GetLastSearched")
+	m.GetMonitorFunc.assertExpectations(t, "MockCodeMonitorStore.GetMonitor")
+	m.GetQueryTriggerForJobFunc.assertExpectations(t, "MockCodeMonitorStore.GetQueryTriggerForJob")
+	m.GetQueryTriggerForMonitorFunc.assertExpectations(t, "MockCodeMonitorStore.GetQueryTriggerForMonitor")
+	m.GetSlackWebhookActionFunc.assertExpectations(t, "MockCodeMonitorStore.GetSlackWebhookAction")
+	m.GetWebhookActionFunc.assertExpectations(t, "MockCodeMonitorStore.GetWebhookAction")
+	m.HandleFunc.assertExpectations(t, "MockCodeMonitorStore.Handle")
+	m.HasAnyLastSearchedFunc.assertExpectations(t, "MockCodeMonitorStore.HasAnyLastSearched")
+	m.ListActionJobsFunc.assertExpectations(t, "MockCodeMonitorStore.ListActionJobs")
+	m.ListEmailActionsFunc.assertExpectations(t, "MockCodeMonitorStore.ListEmailActions")
+	m.ListMonitorsFunc.assertExpectations(t, "MockCodeMonitorStore.ListMonitors")
+	m.ListQueryTriggerJobsFunc.assertExpectations(t, "MockCodeMonitorStore.ListQueryTriggerJobs")
+	m.ListRecipientsFunc.assertExpectations(t, "MockCodeMonitorStore.ListRecipients")
+	m.ListSlackWebhookActionsFunc.assertExpectations(t, "MockCodeMonitorStore.ListSlackWebhookActions")
+	m.ListWebhookActionsFunc.assertExpectations(t, "MockCodeMonitorStore.ListWebhookActions")
+	m.NowFunc.assertExpectations(t, "MockCodeMonitorStore.Now")
+	m.ResetQueryTriggerTimestampsFunc.assertExpectations(t, "MockCodeMonitorStore.ResetQueryTriggerTimestamps")
+	m.SetQueryTriggerNextRunFunc.assertExpectations(t, "MockCodeMonitorStore.SetQueryTriggerNextRun")
+	m.TransactFunc.assertExpectations(t, "MockCodeMonitorStore.Transact")
+	m.UpdateEmailActionFunc.assertExpectations(t, "MockCodeMonitorStore.UpdateEmailAction")
+	m.UpdateMonitorFunc.assertExpectations(t, "MockCodeMonitorStore.UpdateMonitor")
+	m.UpdateMonitorEnabledFunc.assertExpectations(t, "MockCodeMonitorStore.UpdateMonitorEnabled")
+	m.UpdateQueryTriggerFunc.assertExpectations(t, "MockCodeMonitorStore.UpdateQueryTrigger")
+	m.UpdateSlackWebhookActionFunc.assertExpectations(t, "MockCodeMonitorStore.UpdateSlackWebhookAction")
+	m.UpdateTriggerJobWithLogsFunc.assertExpectations(t, "MockCodeMonitorStore.UpdateTriggerJobWithLogs")
+	m.UpdateTriggerJobWithResultsFunc.assertExpectations(t, "MockCodeMonitorStore.UpdateTriggerJobWithResults")
+	m.UpdateWebhookActionFunc.assertExpectations(t, "MockCodeMonitorStore.UpdateWebhookAction")
+	m.UpsertLastSearchedFunc.assertExpectations(t, "MockCodeMonitorStore.UpsertLastSearched")
+}
+
 // NewMockCodeMonitorStoreFrom creates a new mock of the
 // MockCodeMonitorStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -7316,6 +8744,18 @@ func (f *CodeMonitorStoreClockFunc) History() []CodeMonitorStoreClockFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreClockFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreClockFuncCall is an object that describes an invocation
 // of method Clock on an instance of MockCodeMonitorStore.
 type CodeMonitorStoreClockFuncCall struct {
@@ -7417,6 +8857,18 @@ func (f *CodeMonitorStoreCountActionJobsFunc) History() []CodeMonitorStoreCountA
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCountActionJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCountActionJobsFuncCall is an object that describes an
 // invocation of method CountActionJobs on an instance of
 // MockCodeMonitorStore.
@@ -7527,6 +8979,18 @@ func (f *CodeMonitorStoreCountMonitorsFunc) History() []CodeMonitorStoreCountMon
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCountMonitorsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCountMonitorsFuncCall is an object that describes an
 // invocation of method CountMonitors on an instance of
 // MockCodeMonitorStore.
@@ -7639,6 +9103,18 @@ func (f *CodeMonitorStoreCountQueryTriggerJobsFunc) History() []CodeMonitorStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCountQueryTriggerJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCountQueryTriggerJobsFuncCall is an object that describes
 // an invocation of method CountQueryTriggerJobs on an instance of
 // MockCodeMonitorStore.
@@ -7750,6 +9226,18 @@ func (f *CodeMonitorStoreCountRecipientsFunc) History() []CodeMonitorStoreCountR
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCountRecipientsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCountRecipientsFuncCall is an object that describes an
 // invocation of method CountRecipients on an instance of
 // MockCodeMonitorStore.
@@ -7862,6 +9350,18 @@ func (f *CodeMonitorStoreCountSlackWebhookActionsFunc) History() []CodeMonitorSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCountSlackWebhookActionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCountSlackWebhookActionsFuncCall is an object that
 // describes an invocation of method CountSlackWebhookActions on an instance
 // of MockCodeMonitorStore.
@@ -7973,6 +9473,18 @@ func (f *CodeMonitorStoreCountWebhookActionsFunc) History() []CodeMonitorStoreCo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCountWebhookActionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCountWebhookActionsFuncCall is an object that describes
 // an invocation of method CountWebhookActions on an instance of
 // MockCodeMonitorStore.
@@ -8084,6 +9596,18 @@ func (f *CodeMonitorStoreCreateEmailActionFunc) History() []CodeMonitorStoreCrea
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCreateEmailActionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCreateEmailActionFuncCall is an object that describes an
 // invocation of method CreateEmailAction on an instance of
 // MockCodeMonitorStore.
@@ -8197,6 +9721,18 @@ func (f *CodeMonitorStoreCreateMonitorFunc) History() []CodeMonitorStoreCreateMo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCreateMonitorFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCreateMonitorFuncCall is an object that describes an
 // invocation of method CreateMonitor on an instance of
 // MockCodeMonitorStore.
@@ -8308,6 +9844,18 @@ func (f *CodeMonitorStoreCreateQueryTriggerFunc) History() []CodeMonitorStoreCre
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCreateQueryTriggerFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCreateQueryTriggerFuncCall is an object that describes an
 // invocation of method CreateQueryTrigger on an instance of
 // MockCodeMonitorStore.
@@ -8422,6 +9970,18 @@ func (f *CodeMonitorStoreCreateRecipientFunc) History() []CodeMonitorStoreCreate
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCreateRecipientFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCreateRecipientFuncCall is an object that describes an
 // invocation of method CreateRecipient on an instance of
 // MockCodeMonitorStore.
@@ -8540,6 +10100,18 @@ func (f *CodeMonitorStoreCreateSlackWebhookActionFunc) History() []CodeMonitorSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCreateSlackWebhookActionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCreateSlackWebhookActionFuncCall is an object that
 // describes an invocation of method CreateSlackWebhookAction on an instance
 // of MockCodeMonitorStore.
@@ -8660,6 +10232,18 @@ func (f *CodeMonitorStoreCreateWebhookActionFunc) History() []CodeMonitorStoreCr
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreCreateWebhookActionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreCreateWebhookActionFuncCall is an object that describes
 // an invocation of method CreateWebhookAction on an instance of
 // MockCodeMonitorStore.
@@ -8780,6 +10364,18 @@ func (f *CodeMonitorStoreDeleteEmailActionsFunc) History() []CodeMonitorStoreDel
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreDeleteEmailActionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreDeleteEmailActionsFuncCall is an object that describes an
 // invocation of method DeleteEmailActions on an instance of
 // MockCodeMonitorStore.
@@ -8890,6 +10486,18 @@ func (f *CodeMonitorStoreDeleteMonitorFunc) History() []CodeMonitorStoreDeleteMo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreDeleteMonitorFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreDeleteMonitorFuncCall is an object that describes an
 // invocation of method DeleteMonitor on an instance of
 // MockCodeMonitorStore.
@@ -8999,6 +10607,18 @@ func (f *CodeMonitorStoreDeleteOldTriggerJobsFunc) History() []CodeMonitorStoreD
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreDeleteOldTriggerJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreDeleteOldTriggerJobsFuncCall is an object that describes
 // an invocation of method DeleteOldTriggerJobs on an instance of
 // MockCodeMonitorStore.
@@ -9107,6 +10727,18 @@ func (f *CodeMonitorStoreDeleteRecipientsFunc) History() []CodeMonitorStoreDelet
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreDeleteRecipientsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreDeleteRecipientsFuncCall is an object that describes an
 // invocation of method DeleteRecipients on an instance of
 // MockCodeMonitorStore.
@@ -9216,6 +10848,18 @@ func (f *CodeMonitorStoreDeleteSlackWebhookActionsFunc) History() []CodeMonitorS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreDeleteSlackWebhookActionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreDeleteSlackWebhookActionsFuncCall is an object that
 // describes an invocation of method DeleteSlackWebhookActions on an
 // instance of MockCodeMonitorStore.
@@ -9335,6 +10979,18 @@ func (f *CodeMonitorStoreDeleteWebhookActionsFunc) History() []CodeMonitorStoreD
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreDeleteWebhookActionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreDeleteWebhookActionsFuncCall is an object that describes
 // an invocation of method DeleteWebhookActions on an instance of
 // MockCodeMonitorStore.
@@ -9451,6 +11107,18 @@ func (f *CodeMonitorStoreDoneFunc) History() []CodeMonitorStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreDoneFuncCall is an object that describes an invocation of
 // method Done on an instance of MockCodeMonitorStore.
 type CodeMonitorStoreDoneFuncCall struct {
@@ -9556,6 +11224,18 @@ func (f *CodeMonitorStoreEnqueueActionJobsForMonitorFunc) History() []CodeMonito
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreEnqueueActionJobsForMonitorFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreEnqueueActionJobsForMonitorFuncCall is an object that
 // describes an invocation of method EnqueueActionJobsForMonitor on an
 // instance of MockCodeMonitorStore.
@@ -9671,6 +11351,18 @@ func (f *CodeMonitorStoreEnqueueQueryTriggerJobsFunc) History() []CodeMonitorSto
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreEnqueueQueryTriggerJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreEnqueueQueryTriggerJobsFuncCall is an object that
 // describes an invocation of method EnqueueQueryTriggerJobs on an instance
 // of MockCodeMonitorStore.
@@ -9777,6 +11469,18 @@ func (f *CodeMonitorStoreExecFunc) History() []CodeMonitorStoreExecFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreExecFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreExecFuncCall is an object that describes an invocation of
 // method Exec on an instance of MockCodeMonitorStore.
 type CodeMonitorStoreExecFuncCall struct {
@@ -9883,6 +11587,18 @@ func (f *CodeMonitorStoreGetActionJobFunc) History() []CodeMonitorStoreGetAction
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreGetActionJobFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreGetActionJobFuncCall is an object that describes an
 // invocation of method GetActionJob on an instance of MockCodeMonitorStore.
 type CodeMonitorStoreGetActionJobFuncCall struct {
@@ -9994,6 +11710,18 @@ func (f *CodeMonitorStoreGetActionJobMetadataFunc) History() []CodeMonitorStoreG
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreGetActionJobMetadataFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreGetActionJobMetadataFuncCall is an object that describes
 // an invocation of method GetActionJobMetadata on an instance of
 // MockCodeMonitorStore.
@@ -10104,6 +11832,18 @@ func (f *CodeMonitorStoreGetEmailActionFunc) History() []CodeMonitorStoreGetEmai
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreGetEmailActionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreGetEmailActionFuncCall is an object that describes an
 // invocation of method GetEmailAction on an instance of
 // MockCodeMonitorStore.
@@ -10215,6 +11955,18 @@ func (f *CodeMonitorStoreGetLastSearchedFunc) History() []CodeMonitorStoreGetLas
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreGetLastSearchedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreGetLastSearchedFuncCall is an object that describes an
 // invocation of method GetLastSearched on an instance of
 // MockCodeMonitorStore.
@@ -10327,6 +12079,18 @@ func (f *CodeMonitorStoreGetMonitorFunc) History() []CodeMonitorStoreGetMonitorF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreGetMonitorFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreGetMonitorFuncCall is an object that describes an
 // invocation of method GetMonitor on an instance of MockCodeMonitorStore.
 type CodeMonitorStoreGetMonitorFuncCall struct {
@@ -10438,6 +12202,18 @@ func (f *CodeMonitorStoreGetQueryTriggerForJobFunc) History() []CodeMonitorStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreGetQueryTriggerForJobFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreGetQueryTriggerForJobFuncCall is an object that describes
 // an invocation of method GetQueryTriggerForJob on an instance of
 // MockCodeMonitorStore.
@@ -10550,6 +12326,18 @@ func (f *CodeMonitorStoreGetQueryTriggerForMonitorFunc) History() []CodeMonitorS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreGetQueryTriggerForMonitorFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreGetQueryTriggerForMonitorFuncCall is an object that
 // describes an invocation of method GetQueryTriggerForMonitor on an
 // instance of MockCodeMonitorStore.
@@ -10662,6 +12450,18 @@ func (f *CodeMonitorStoreGetSlackWebhookActionFunc) History() []CodeMonitorStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreGetSlackWebhookActionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreGetSlackWebhookActionFuncCall is an object that describes
 // an invocation of method GetSlackWebhookAction on an instance of
 // MockCodeMonitorStore.
@@ -10773,6 +12573,18 @@ func (f *CodeMonitorStoreGetWebhookActionFunc) History() []CodeMonitorStoreGetWe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreGetWebhookActionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreGetWebhookActionFuncCall is an object that describes an
 // invocation of method GetWebhookAction on an instance of
 // MockCodeMonitorStore.
@@ -10882,6 +12694,18 @@ func (f *CodeMonitorStoreHandleFunc) History() []CodeMonitorStoreHandleFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockCodeMonitorStore.
 type CodeMonitorStoreHandleFuncCall struct {
@@ -10983,6 +12807,18 @@ func (f *CodeMonitorStoreHasAnyLastSearchedFunc) History() []CodeMonitorStoreHas
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreHasAnyLastSearchedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreHasAnyLastSearchedFuncCall is an object that describes an
 // invocation of method HasAnyLastSearched on an instance of
 // MockCodeMonitorStore.
@@ -11093,6 +12929,18 @@ func (f *CodeMonitorStoreListActionJobsFunc) History() []CodeMonitorStoreListAct
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreListActionJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreListActionJobsFuncCall is an object that describes an
 // invocation of method ListActionJobs on an instance of
 // MockCodeMonitorStore.
@@ -11204,6 +13052,18 @@ func (f *CodeMonitorStoreListEmailActionsFunc) History() []CodeMonitorStoreListE
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreListEmailActionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreListEmailActionsFuncCall is an object that describes an
 // invocation of method ListEmailActions on an instance of
 // MockCodeMonitorStore.
@@ -11314,6 +13174,18 @@ func (f *CodeMonitorStoreListMonitorsFunc) History() []CodeMonitorStoreListMonit
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreListMonitorsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreListMonitorsFuncCall is an object that describes an
 // invocation of method ListMonitors on an instance of MockCodeMonitorStore.
 type CodeMonitorStoreListMonitorsFuncCall struct {
@@ -11425,6 +13297,18 @@ func (f *CodeMonitorStoreListQueryTriggerJobsFunc) History() []CodeMonitorStoreL
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreListQueryTriggerJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreListQueryTriggerJobsFuncCall is an object that describes
 // an invocation of method ListQueryTriggerJobs on an instance of
 // MockCodeMonitorStore.
@@ -11535,6 +13419,18 @@ func (f *CodeMonitorStoreListRecipientsFunc) History() []CodeMonitorStoreListRec
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreListRecipientsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreListRecipientsFuncCall is an object that describes an
 // invocation of method ListRecipients on an instance of
 // MockCodeMonitorStore.
@@ -11647,6 +13543,18 @@ func (f *CodeMonitorStoreListSlackWebhookActionsFunc) History() []CodeMonitorSto
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreListSlackWebhookActionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreListSlackWebhookActionsFuncCall is an object that
 // describes an invocation of method ListSlackWebhookActions on an instance
 // of MockCodeMonitorStore.
@@ -11758,6 +13666,18 @@ func (f *CodeMonitorStoreListWebhookActionsFunc) History() []CodeMonitorStoreLis
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreListWebhookActionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreListWebhookActionsFuncCall is an object that describes an
 // invocation of method ListWebhookActions on an instance of
 // MockCodeMonitorStore.
@@ -11867,6 +13787,18 @@ func (f *CodeMonitorStoreNowFunc) History() []CodeMonitorStoreNowFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreNowFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreNowFuncCall is an object that describes an invocation of
 // method Now on an instance of MockCodeMonitorStore.
 type CodeMonitorStoreNowFuncCall struct {
@@ -11969,6 +13901,18 @@ func (f *CodeMonitorStoreResetQueryTriggerTimestampsFunc) History() []CodeMonito
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreResetQueryTriggerTimestampsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreResetQueryTriggerTimestampsFuncCall is an object that
 // describes an invocation of method ResetQueryTriggerTimestamps on an
 // instance of MockCodeMonitorStore.
@@ -12078,6 +14022,18 @@ func (f *CodeMonitorStoreSetQueryTriggerNextRunFunc) History() []CodeMonitorStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreSetQueryTriggerNextRunFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreSetQueryTriggerNextRunFuncCall is an object that
 // describes an invocation of method SetQueryTriggerNextRun on an instance
 // of MockCodeMonitorStore.
@@ -12190,6 +14146,18 @@ func (f *CodeMonitorStoreTransactFunc) History() []CodeMonitorStoreTransactFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreTransactFuncCall is an object that describes an
 // invocation of method Transact on an instance of MockCodeMonitorStore.
 type CodeMonitorStoreTransactFuncCall struct {
@@ -12297,6 +14265,18 @@ func (f *CodeMonitorStoreUpdateEmailActionFunc) History() []CodeMonitorStoreUpda
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreUpdateEmailActionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreUpdateEmailActionFuncCall is an object that describes an
 // invocation of method UpdateEmailAction on an instance of
 // MockCodeMonitorStore.
@@ -12410,6 +14390,18 @@ func (f *CodeMonitorStoreUpdateMonitorFunc) History() []CodeMonitorStoreUpdateMo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreUpdateMonitorFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreUpdateMonitorFuncCall is an object that describes an
 // invocation of method UpdateMonitor on an instance of
 // MockCodeMonitorStore.
@@ -12525,6 +14517,18 @@ func (f *CodeMonitorStoreUpdateMonitorEnabledFunc) History() []CodeMonitorStoreU
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreUpdateMonitorEnabledFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreUpdateMonitorEnabledFuncCall is an object that describes
 // an invocation of method UpdateMonitorEnabled on an instance of
 // MockCodeMonitorStore.
@@ -12639,6 +14643,18 @@ func (f *CodeMonitorStoreUpdateQueryTriggerFunc) History() []CodeMonitorStoreUpd
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreUpdateQueryTriggerFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreUpdateQueryTriggerFuncCall is an object that describes an
 // invocation of method UpdateQueryTrigger on an instance of
 // MockCodeMonitorStore.
@@ -12751,6 +14767,18 @@ func (f *CodeMonitorStoreUpdateSlackWebhookActionFunc) History() []CodeMonitorSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreUpdateSlackWebhookActionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreUpdateSlackWebhookActionFuncCall is an object that
 // describes an invocation of method UpdateSlackWebhookAction on an instance
 // of MockCodeMonitorStore.
@@ -12872,6 +14900,18 @@ func (f *CodeMonitorStoreUpdateTriggerJobWithLogsFunc) History() []CodeMonitorSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreUpdateTriggerJobWithLogsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreUpdateTriggerJobWithLogsFuncCall is an object that
 // describes an invocation of method UpdateTriggerJobWithLogs on an instance
 // of MockCodeMonitorStore.
@@ -12984,6 +15024,18 @@ func (f *CodeMonitorStoreUpdateTriggerJobWithResultsFunc) History() []CodeMonito
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreUpdateTriggerJobWithResultsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreUpdateTriggerJobWithResultsFuncCall is an object that
 // describes an invocation of method UpdateTriggerJobWithResults on an
 // instance of MockCodeMonitorStore.
@@ -13098,6 +15150,18 @@ func (f *CodeMonitorStoreUpdateWebhookActionFunc) History() []CodeMonitorStoreUp
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreUpdateWebhookActionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreUpdateWebhookActionFuncCall is an object that describes
 // an invocation of method UpdateWebhookAction on an instance of
 // MockCodeMonitorStore.
@@ -13218,6 +15282,18 @@ func (f *CodeMonitorStoreUpsertLastSearchedFunc) History() []CodeMonitorStoreUps
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeMonitorStoreUpsertLastSearchedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeMonitorStoreUpsertLastSearchedFuncCall is an object that describes an
 // invocation of method UpsertLastSearched on an instance of
 // MockCodeMonitorStore.
@@ -13377,6 +15453,77 @@ func NewStrictMockCodeownersStore() *MockCodeownersStore {
 	}
 }
 
+// NewStrictMockCodeownersStoreWithT creates a new mock of the CodeownersStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockCodeownersStoreWithT(t testing.TB) *MockCodeownersStore {
+	return &MockCodeownersStore{
+		CountCodeownersFilesFunc: &CodeownersStoreCountCodeownersFilesFunc{
+			defaultHook: func(v0 context.Context) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockCodeownersStore.CountCodeownersFiles(%v)", v0)
+				return
+			},
+		},
+		CreateCodeownersFileFunc: &CodeownersStoreCreateCodeownersFileFunc{
+			defaultHook: func(v0 context.Context, v1 *types1.CodeownersFile) (r0 error) {
+				t.Errorf("unexpected call of MockCodeownersStore.CreateCodeownersFile(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteCodeownersForReposFunc: &CodeownersStoreDeleteCodeownersForReposFunc{
+			defaultHook: func(v0 context.Context, v1 ...api.RepoID) (r0 error) {
+				t.Errorf("unexpected call of MockCodeownersStore.DeleteCodeownersForRepos(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &CodeownersStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockCodeownersStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetCodeownersForRepoFunc: &CodeownersStoreGetCodeownersForRepoFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 *types1.CodeownersFile, r1 error) {
+				t.Errorf("unexpected call of MockCodeownersStore.GetCodeownersForRepo(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &CodeownersStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockCodeownersStore.Handle()")
+				return
+			},
+		},
+		ListCodeownersFunc: &CodeownersStoreListCodeownersFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListCodeownersOpts) (r0 []*types1.CodeownersFile, r1 int32, r2 error) {
+				t.Errorf("unexpected call of MockCodeownersStore.ListCodeowners(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateCodeownersFileFunc: &CodeownersStoreUpdateCodeownersFileFunc{
+			defaultHook: func(v0 context.Context, v1 *types1.CodeownersFile) (r0 error) {
+				t.Errorf("unexpected call of MockCodeownersStore.UpdateCodeownersFile(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockCodeownersStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockCodeownersStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountCodeownersFilesFunc.assertExpectations(t, "MockCodeownersStore.CountCodeownersFiles")
+	m.CreateCodeownersFileFunc.assertExpectations(t, "MockCodeownersStore.CreateCodeownersFile")
+	m.DeleteCodeownersForReposFunc.assertExpectations(t, "MockCodeownersStore.DeleteCodeownersForRepos")
+	m.DoneFunc.assertExpectations(t, "MockCodeownersStore.Done")
+	m.GetCodeownersForRepoFunc.assertExpectations(t, "MockCodeownersStore.GetCodeownersForRepo")
+	m.HandleFunc.assertExpectations(t, "MockCodeownersStore.Handle")
+	m.ListCodeownersFunc.assertExpectations(t, "MockCodeownersStore.ListCodeowners")
+	m.UpdateCodeownersFileFunc.assertExpectations(t, "MockCodeownersStore.UpdateCodeownersFile")
+}
+
 // NewMockCodeownersStoreFrom creates a new mock of the MockCodeownersStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -13490,6 +15637,18 @@ func (f *CodeownersStoreCountCodeownersFilesFunc) History() []CodeownersStoreCou
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeownersStoreCountCodeownersFilesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeownersStoreCountCodeownersFilesFuncCall is an object that describes
 // an invocation of method CountCodeownersFiles on an instance of
 // MockCodeownersStore.
@@ -13598,6 +15757,18 @@ func (f *CodeownersStoreCreateCodeownersFileFunc) History() []CodeownersStoreCre
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeownersStoreCreateCodeownersFileFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeownersStoreCreateCodeownersFileFuncCall is an object that describes
 // an invocation of method CreateCodeownersFile on an instance of
 // MockCodeownersStore.
@@ -13707,6 +15878,18 @@ func (f *CodeownersStoreDeleteCodeownersForReposFunc) History() []CodeownersStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeownersStoreDeleteCodeownersForReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeownersStoreDeleteCodeownersForReposFuncCall is an object that
 // describes an invocation of method DeleteCodeownersForRepos on an instance
 // of MockCodeownersStore.
@@ -13820,6 +16003,18 @@ func (f *CodeownersStoreDoneFunc) History() []CodeownersStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeownersStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeownersStoreDoneFuncCall is an object that describes an invocation of
 // method Done on an instance of MockCodeownersStore.
 type CodeownersStoreDoneFuncCall struct {
@@ -13924,6 +16119,18 @@ func (f *CodeownersStoreGetCodeownersForRepoFunc) History() []CodeownersStoreGet
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeownersStoreGetCodeownersForRepoFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeownersStoreGetCodeownersForRepoFuncCall is an object that describes
 // an invocation of method GetCodeownersForRepo on an instance of
 // MockCodeownersStore.
@@ -14033,6 +16240,18 @@ func (f *CodeownersStoreHandleFunc) History() []CodeownersStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeownersStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeownersStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockCodeownersStore.
 type CodeownersStoreHandleFuncCall struct {
@@ -14133,6 +16352,18 @@ func (f *CodeownersStoreListCodeownersFunc) History() []CodeownersStoreListCodeo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeownersStoreListCodeownersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeownersStoreListCodeownersFuncCall is an object that describes an
 // invocation of method ListCodeowners on an instance of
 // MockCodeownersStore.
@@ -14247,6 +16478,18 @@ func (f *CodeownersStoreUpdateCodeownersFileFunc) History() []CodeownersStoreUpd
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *CodeownersStoreUpdateCodeownersFileFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // CodeownersStoreUpdateCodeownersFileFuncCall is an object that describes
 // an invocation of method UpdateCodeownersFile on an instance of
 // MockCodeownersStore.
@@ -14385,6 +16628,69 @@ func NewStrictMockConfStore() *MockConfStore {
 	}
 }
 
+// NewStrictMockConfStoreWithT creates a new mock of the ConfStore interface.
+// All methods fail t with their arguments and return zero values for all
+// results, unless overwritten.
+func NewStrictMockConfStoreWithT(t testing.TB) *MockConfStore {
+	return &MockConfStore{
+		DoneFunc: &ConfStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockConfStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetSiteConfigCountFunc: &ConfStoreGetSiteConfigCountFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockConfStore.GetSiteConfigCount(%v)", v0)
+				return
+			},
+		},
+		HandleFunc: &ConfStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockConfStore.Handle()")
+				return
+			},
+		},
+		ListSiteConfigsFunc: &ConfStoreListSiteConfigsFunc{
+			defaultHook: func(v0 context.Context, v1 *database.PaginationArgs) (r0 []*database.SiteConfig, r1 error) {
+				t.Errorf("unexpected call of MockConfStore.ListSiteConfigs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		SiteCreateIfUpToDateFunc: &ConfStoreSiteCreateIfUpToDateFunc{
+			defaultHook: func(v0 context.Context, v1 *int32, v2 int32, v3 string, v4 bool) (r0 *database.SiteConfig, r1 error) {
+				t.Errorf("unexpected call of MockConfStore.SiteCreateIfUpToDate(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		SiteGetLatestFunc: &ConfStoreSiteGetLatestFunc{
+			defaultHook: func(v0 context.Context) (r0 *database.SiteConfig, r1 error) {
+				t.Errorf("unexpected call of MockConfStore.SiteGetLatest(%v)", v0)
+				return
+			},
+		},
+		TransactFunc: &ConfStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.ConfStore, r1 error) {
+				t.Errorf("unexpected call of MockConfStore.Transact(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockConfStore instance with PushHook or PushReturn which was not called.
+func (m *MockConfStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.DoneFunc.assertExpectations(t, "MockConfStore.Done")
+	m.GetSiteConfigCountFunc.assertExpectations(t, "MockConfStore.GetSiteConfigCount")
+	m.HandleFunc.assertExpectations(t, "MockConfStore.Handle")
+	m.ListSiteConfigsFunc.assertExpectations(t, "MockConfStore.ListSiteConfigs")
+	m.SiteCreateIfUpToDateFunc.assertExpectations(t, "MockConfStore.SiteCreateIfUpToDate")
+	m.SiteGetLatestFunc.assertExpectations(t, "MockConfStore.SiteGetLatest")
+	m.TransactFunc.assertExpectations(t, "MockConfStore.Transact")
+}
+
 // NewMockConfStoreFrom creates a new mock of the MockConfStore interface.
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockConfStoreFrom(i database.ConfStore) *MockConfStore {
@@ -14491,6 +16797,18 @@ func (f *ConfStoreDoneFunc) History() []ConfStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ConfStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ConfStoreDoneFuncCall is an object that describes an invocation of method
 // Done on an instance of MockConfStore.
 type ConfStoreDoneFuncCall struct {
@@ -14594,6 +16912,18 @@ func (f *ConfStoreGetSiteConfigCountFunc) History() []ConfStoreGetSiteConfigCoun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ConfStoreGetSiteConfigCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ConfStoreGetSiteConfigCountFuncCall is an object that describes an
 // invocation of method GetSiteConfigCount on an instance of MockConfStore.
 type ConfStoreGetSiteConfigCountFuncCall struct {
@@ -14698,6 +17028,18 @@ func (f *ConfStoreHandleFunc) History() []ConfStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ConfStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ConfStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockConfStore.
 type ConfStoreHandleFuncCall struct {
@@ -14797,6 +17139,18 @@ func (f *ConfStoreListSiteConfigsFunc) History() []ConfStoreListSiteConfigsFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ConfStoreListSiteConfigsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ConfStoreListSiteConfigsFuncCall is an object that describes an
 // invocation of method ListSiteConfigs on an instance of MockConfStore.
 type ConfStoreListSiteConfigsFuncCall struct {
@@ -14906,6 +17260,18 @@ func (f *ConfStoreSiteCreateIfUpToDateFunc) History() []ConfStoreSiteCreateIfUpT
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ConfStoreSiteCreateIfUpToDateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ConfStoreSiteCreateIfUpToDateFuncCall is an object that describes an
 // invocation of method SiteCreateIfUpToDate on an instance of
 // MockConfStore.
@@ -15024,6 +17390,18 @@ func (f *ConfStoreSiteGetLatestFunc) History() []ConfStoreSiteGetLatestFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ConfStoreSiteGetLatestFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ConfStoreSiteGetLatestFuncCall is an object that describes an invocation
 // of method SiteGetLatest on an instance of MockConfStore.
 type ConfStoreSiteGetLatestFuncCall struct {
@@ -15128,6 +17506,18 @@ func (f *ConfStoreTransactFunc) History() []ConfStoreTransactFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ConfStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ConfStoreTransactFuncCall is an object that describes an invocation of
 // method Transact on an instance of MockConfStore.
 type ConfStoreTransactFuncCall struct {
@@ -16022,6 +18412,475 @@ func NewStrictMockDB() *MockDB {
 	}
 }
 
+// NewStrictMockDBWithT creates a new mock of the DB interface. All methods
+// fail t with their arguments and return zero values for all results, unless
+// overwritten.
+func NewStrictMockDBWithT(t testing.TB) *MockDB {
+	return &MockDB{
+		AccessRequestsFunc: &DBAccessRequestsFunc{
+			defaultHook: func() (r0 database.AccessRequestStore) {
+				t.Errorf("unexpected call of MockDB.AccessRequests()")
+				return
+			},
+		},
+		AccessTokensFunc: &DBAccessTokensFunc{
+			defaultHook: func() (r0 database.AccessTokenStore) {
+				t.Errorf("unexpected call of MockDB.AccessTokens()")
+				return
+			},
+		},
+		AssignedOwnersFunc: &DBAssignedOwnersFunc{
+			defaultHook: func() (r0 database.AssignedOwnersStore) {
+				t.Errorf("unexpected call of MockDB.AssignedOwners()")
+				return
+			},
+		},
+		AssignedTeamsFunc: &DBAssignedTeamsFunc{
+			defaultHook: func() (r0 database.AssignedTeamsStore) {
+				t.Errorf("unexpected call of MockDB.AssignedTeams()")
+				return
+			},
+		},
+		AuthzFunc: &DBAuthzFunc{
+			defaultHook: func() (r0 database.AuthzStore) {
+				t.Errorf("unexpected call of MockDB.Authz()")
+				return
+			},
+		},
+		BitbucketProjectPermissionsFunc: &DBBitbucketProjectPermissionsFunc{
+			defaultHook: func() (r0 database.BitbucketProjectPermissionsStore) {
+				t.Errorf("unexpected call of MockDB.BitbucketProjectPermissions()")
+				return
+			},
+		},
+		CodeHostsFunc: &DBCodeHostsFunc{
+			defaultHook: func() (r0 database.CodeHostStore) {
+				t.Errorf("unexpected call of MockDB.CodeHosts()")
+				return
+			},
+		},
+		CodeMonitorsFunc: &DBCodeMonitorsFunc{
+			defaultHook: func() (r0 database.CodeMonitorStore) {
+				t.Errorf("unexpected call of MockDB.CodeMonitors()")
+				return
+			},
+		},
+		CodeownersFunc: &DBCodeownersFunc{
+			defaultHook: func() (r0 database.CodeownersStore) {
+				t.Errorf("unexpected call of MockDB.Codeowners()")
+				return
+			},
+		},
+		ConfFunc: &DBConfFunc{
+			defaultHook: func() (r0 database.ConfStore) {
+				t.Errorf("unexpected call of MockDB.Conf()")
+				return
+			},
+		},
+		EventLogsFunc: &DBEventLogsFunc{
+			defaultHook: func() (r0 database.EventLogStore) {
+				t.Errorf("unexpected call of MockDB.EventLogs()")
+				return
+			},
+		},
+		EventLogsScrapeStateFunc: &DBEventLogsScrapeStateFunc{
+			defaultHook: func() (r0 database.EventLogsScrapeStateStore) {
+				t.Errorf("unexpected call of MockDB.EventLogsScrapeState()")
+				return
+			},
+		},
+		ExecContextFunc: &DBExecContextFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 ...interface{}) (r0 sql.Result, r1 error) {
+				t.Errorf("unexpected call of MockDB.ExecContext(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		ExecutorSecretAccessLogsFunc: &DBExecutorSecretAccessLogsFunc{
+			defaultHook: func() (r0 database.ExecutorSecretAccessLogStore) {
+				t.Errorf("unexpected call of MockDB.ExecutorSecretAccessLogs()")
+				return
+			},
+		},
+		ExecutorSecretsFunc: &DBExecutorSecretsFunc{
+			defaultHook: func(v0 encryption.Key) (r0 database.ExecutorSecretStore) {
+				t.Errorf("unexpected call of MockDB.ExecutorSecrets(%v)", v0)
+				return
+			},
+		},
+		ExecutorsFunc: &DBExecutorsFunc{
+			defaultHook: func() (r0 database.ExecutorStore) {
+				t.Errorf("unexpected call of MockDB.Executors()")
+				return
+			},
+		},
+		ExternalServicesFunc: &DBExternalServicesFunc{
+			defaultHook: func() (r0 database.ExternalServiceStore) {
+				t.Errorf("unexpected call of MockDB.ExternalServices()")
+				return
+			},
+		},
+		FeatureFlagsFunc: &DBFeatureFlagsFunc{
+			defaultHook: func() (r0 database.FeatureFlagStore) {
+				t.Errorf("unexpected call of MockDB.FeatureFlags()")
+				return
+			},
+		},
+		GitHubAppsFunc: &DBGitHubAppsFunc{
+			defaultHook: func() (r0 store.GitHubAppsStore) {
+				t.Errorf("unexpected call of MockDB.GitHubApps()")
+				return
+			},
+		},
+		GitserverReposFunc: &DBGitserverReposFunc{
+			defaultHook: func() (r0 database.GitserverRepoStore) {
+				t.Errorf("unexpected call of MockDB.GitserverRepos()")
+				return
+			},
+		},
+		GlobalStateFunc: &DBGlobalStateFunc{
+			defaultHook: func() (r0 database.GlobalStateStore) {
+				t.Errorf("unexpected call of MockDB.GlobalState()")
+				return
+			},
+		},
+		HandleFunc: &DBHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockDB.Handle()")
+				return
+			},
+		},
+		NamespacePermissionsFunc: &DBNamespacePermissionsFunc{
+			defaultHook: func() (r0 database.NamespacePermissionStore) {
+				t.Errorf("unexpected call of MockDB.NamespacePermissions()")
+				return
+			},
+		},
+		NamespacesFunc: &DBNamespacesFunc{
+			defaultHook: func() (r0 database.NamespaceStore) {
+				t.Errorf("unexpected call of MockDB.Namespaces()")
+				return
+			},
+		},
+		OrgInvitationsFunc: &DBOrgInvitationsFunc{
+			defaultHook: func() (r0 database.OrgInvitationStore) {
+				t.Errorf("unexpected call of MockDB.OrgInvitations()")
+				return
+			},
+		},
+		OrgMembersFunc: &DBOrgMembersFunc{
+			defaultHook: func() (r0 database.OrgMemberStore) {
+				t.Errorf("unexpected call of MockDB.OrgMembers()")
+				return
+			},
+		},
+		OrgsFunc: &DBOrgsFunc{
+			defaultHook: func() (r0 database.OrgStore) {
+				t.Errorf("unexpected call of MockDB.Orgs()")
+				return
+			},
+		},
+		OutboundWebhookJobsFunc: &DBOutboundWebhookJobsFunc{
+			defaultHook: func(v0 encryption.Key) (r0 database.OutboundWebhookJobStore) {
+				t.Errorf("unexpected call of MockDB.OutboundWebhookJobs(%v)", v0)
+				return
+			},
+		},
+		OutboundWebhookLogsFunc: &DBOutboundWebhookLogsFunc{
+			defaultHook: func(v0 encryption.Key) (r0 database.OutboundWebhookLogStore) {
+				t.Errorf("unexpected call of MockDB.OutboundWebhookLogs(%v)", v0)
+				return
+			},
+		},
+		OutboundWebhooksFunc: &DBOutboundWebhooksFunc{
+			defaultHook: func(v0 encryption.Key) (r0 database.OutboundWebhookStore) {
+				t.Errorf("unexpected call of MockDB.OutboundWebhooks(%v)", v0)
+				return
+			},
+		},
+		OwnSignalConfigurationsFunc: &DBOwnSignalConfigurationsFunc{
+			defaultHook: func() (r0 database.SignalConfigurationStore) {
+				t.Errorf("unexpected call of MockDB.OwnSignalConfigurations()")
+				return
+			},
+		},
+		OwnershipStatsFunc: &DBOwnershipStatsFunc{
+			defaultHook: func() (r0 database.OwnershipStatsStore) {
+				t.Errorf("unexpected call of MockDB.OwnershipStats()")
+				return
+			},
+		},
+		PermissionSyncJobsFunc: &DBPermissionSyncJobsFunc{
+			defaultHook: func() (r0 database.PermissionSyncJobStore) {
+				t.Errorf("unexpected call of MockDB.PermissionSyncJobs()")
+				return
+			},
+		},
+		PermissionsFunc: &DBPermissionsFunc{
+			defaultHook: func() (r0 database.PermissionStore) {
+				t.Errorf("unexpected call of MockDB.Permissions()")
+				return
+			},
+		},
+		PermsFunc: &DBPermsFunc{
+			defaultHook: func() (r0 database.PermsStore) {
+				t.Errorf("unexpected call of MockDB.Perms()")
+				return
+			},
+		},
+		PhabricatorFunc: &DBPhabricatorFunc{
+			defaultHook: func() (r0 database.PhabricatorStore) {
+				t.Errorf("unexpected call of MockDB.Phabricator()")
+				return
+			},
+		},
+		QueryContextFunc: &DBQueryContextFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 ...interface{}) (r0 *sql.Rows, r1 error) {
+				t.Errorf("unexpected call of MockDB.QueryContext(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		QueryRowContextFunc: &DBQueryRowContextFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 ...interface{}) (r0 *sql.Row) {
+				t.Errorf("unexpected call of MockDB.QueryRowContext(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		RecentContributionSignalsFunc: &DBRecentContributionSignalsFunc{
+			defaultHook: func() (r0 database.RecentContributionSignalStore) {
+				t.Errorf("unexpected call of MockDB.RecentContributionSignals()")
+				return
+			},
+		},
+		RecentViewSignalFunc: &DBRecentViewSignalFunc{
+			defaultHook: func() (r0 database.RecentViewSignalStore) {
+				t.Errorf("unexpected call of MockDB.RecentViewSignal()")
+				return
+			},
+		},
+		RedisKeyValueFunc: &DBRedisKeyValueFunc{
+			defaultHook: func() (r0 database.RedisKeyValueStore) {
+				t.Errorf("unexpected call of MockDB.RedisKeyValue()")
+				return
+			},
+		},
+		RepoCommitsChangelistsFunc: &DBRepoCommitsChangelistsFunc{
+			defaultHook: func() (r0 database.RepoCommitsChangelistsStore) {
+				t.Errorf("unexpected call of MockDB.RepoCommitsChangelists()")
+				return
+			},
+		},
+		RepoKVPsFunc: &DBRepoKVPsFunc{
+			defaultHook: func() (r0 database.RepoKVPStore) {
+				t.Errorf("unexpected call of MockDB.RepoKVPs()")
+				return
+			},
+		},
+		RepoPathsFunc: &DBRepoPathsFunc{
+			defaultHook: func() (r0 database.RepoPathStore) {
+				t.Errorf("unexpected call of MockDB.RepoPaths()")
+				return
+			},
+		},
+		RepoStatisticsFunc: &DBRepoStatisticsFunc{
+			defaultHook: func() (r0 database.RepoStatisticsStore) {
+				t.Errorf("unexpected call of MockDB.RepoStatistics()")
+				return
+			},
+		},
+		ReposFunc: &DBReposFunc{
+			defaultHook: func() (r0 database.RepoStore) {
+				t.Errorf("unexpected call of MockDB.Repos()")
+				return
+			},
+		},
+		RolePermissionsFunc: &DBRolePermissionsFunc{
+			defaultHook: func() (r0 database.RolePermissionStore) {
+				t.Errorf("unexpected call of MockDB.RolePermissions()")
+				return
+			},
+		},
+		RolesFunc: &DBRolesFunc{
+			defaultHook: func() (r0 database.RoleStore) {
+				t.Errorf("unexpected call of MockDB.Roles()")
+				return
+			},
+		},
+		SavedSearchesFunc: &DBSavedSearchesFunc{
+			defaultHook: func() (r0 database.SavedSearchStore) {
+				t.Errorf("unexpected call of MockDB.SavedSearches()")
+				return
+			},
+		},
+		SearchContextsFunc: &DBSearchContextsFunc{
+			defaultHook: func() (r0 database.SearchContextsStore) {
+				t.Errorf("unexpected call of MockDB.SearchContexts()")
+				return
+			},
+		},
+		SecurityEventLogsFunc: &DBSecurityEventLogsFunc{
+			defaultHook: func() (r0 database.SecurityEventLogsStore) {
+				t.Errorf("unexpected call of MockDB.SecurityEventLogs()")
+				return
+			},
+		},
+		SettingsFunc: &DBSettingsFunc{
+			defaultHook: func() (r0 database.SettingsStore) {
+				t.Errorf("unexpected call of MockDB.Settings()")
+				return
+			},
+		},
+		SubRepoPermsFunc: &DBSubRepoPermsFunc{
+			defaultHook: func() (r0 database.SubRepoPermsStore) {
+				t.Errorf("unexpected call of MockDB.SubRepoPerms()")
+				return
+			},
+		},
+		TeamsFunc: &DBTeamsFunc{
+			defaultHook: func() (r0 database.TeamStore) {
+				t.Errorf("unexpected call of MockDB.Teams()")
+				return
+			},
+		},
+		TelemetryEventsExportQueueFunc: &DBTelemetryEventsExportQueueFunc{
+			defaultHook: func() (r0 database.TelemetryEventsExportQueueStore) {
+				t.Errorf("unexpected call of MockDB.TelemetryEventsExportQueue()")
+				return
+			},
+		},
+		TemporarySettingsFunc: &DBTemporarySettingsFunc{
+			defaultHook: func() (r0 database.TemporarySettingsStore) {
+				t.Errorf("unexpected call of MockDB.TemporarySettings()")
+				return
+			},
+		},
+		UserCredentialsFunc: &DBUserCredentialsFunc{
+			defaultHook: func(v0 encryption.Key) (r0 database.UserCredentialsStore) {
+				t.Errorf("unexpected call of MockDB.UserCredentials(%v)", v0)
+				return
+			},
+		},
+		UserEmailsFunc: &DBUserEmailsFunc{
+			defaultHook: func() (r0 database.UserEmailsStore) {
+				t.Errorf("unexpected call of MockDB.UserEmails()")
+				return
+			},
+		},
+		UserExternalAccountsFunc: &DBUserExternalAccountsFunc{
+			defaultHook: func() (r0 database.UserExternalAccountsStore) {
+				t.Errorf("unexpected call of MockDB.UserExternalAccounts()")
+				return
+			},
+		},
+		UserRolesFunc: &DBUserRolesFunc{
+			defaultHook: func() (r0 database.UserRoleStore) {
+				t.Errorf("unexpected call of MockDB.UserRoles()")
+				return
+			},
+		},
+		UsersFunc: &DBUsersFunc{
+			defaultHook: func() (r0 database.UserStore) {
+				t.Errorf("unexpected call of MockDB.Users()")
+				return
+			},
+		},
+		WebhookLogsFunc: &DBWebhookLogsFunc{
+			defaultHook: func(v0 encryption.Key) (r0 database.WebhookLogStore) {
+				t.Errorf("unexpected call of MockDB.WebhookLogs(%v)", v0)
+				return
+			},
+		},
+		WebhooksFunc: &DBWebhooksFunc{
+			defaultHook: func(v0 encryption.Key) (r0 database.WebhookStore) {
+				t.Errorf("unexpected call of MockDB.Webhooks(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &DBWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(tx database.DB) error) (r0 error) {
+				t.Errorf("unexpected call of MockDB.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ZoektReposFunc: &DBZoektReposFunc{
+			defaultHook: func() (r0 database.ZoektReposStore) {
+				t.Errorf("unexpected call of MockDB.ZoektRepos()")
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockDB instance with PushHook or PushReturn which was not called.
+func (m *MockDB) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.AccessRequestsFunc.assertExpectations(t, "MockDB.AccessRequests")
+	m.AccessTokensFunc.assertExpectations(t, "MockDB.AccessTokens")
+	m.AssignedOwnersFunc.assertExpectations(t, "MockDB.AssignedOwners")
+	m.AssignedTeamsFunc.assertExpectations(t, "MockDB.AssignedTeams")
+	m.AuthzFunc.assertExpectations(t, "MockDB.Authz")
+	m.BitbucketProjectPermissionsFunc.assertExpectations(t, "MockDB.BitbucketProjectPermissions")
+	m.CodeHostsFunc.assertExpectations(t, "MockDB.CodeHosts")
+	m.CodeMonitorsFunc.assertExpectations(t, "MockDB.CodeMonitors")
+	m.CodeownersFunc.assertExpectations(t, "MockDB.Codeowners")
+	m.ConfFunc.assertExpectations(t, "MockDB.Conf")
+	m.EventLogsFunc.assertExpectations(t, "MockDB.EventLogs")
+	m.EventLogsScrapeStateFunc.assertExpectations(t, "MockDB.EventLogsScrapeState")
+	m.ExecContextFunc.assertExpectations(t, "MockDB.ExecContext")
+	m.ExecutorSecretAccessLogsFunc.assertExpectations(t, "MockDB.ExecutorSecretAccessLogs")
+	m.ExecutorSecretsFunc.assertExpectations(t, "MockDB.ExecutorSecrets")
+	m.ExecutorsFunc.assertExpectations(t, "MockDB.Executors")
+	m.ExternalServicesFunc.assertExpectations(t, "MockDB.ExternalServices")
+	m.FeatureFlagsFunc.assertExpectations(t, "MockDB.FeatureFlags")
+	m.GitHubAppsFunc.assertExpectations(t, "MockDB.GitHubApps")
+	m.GitserverReposFunc.assertExpectations(t, "MockDB.GitserverRepos")
+	m.GlobalStateFunc.assertExpectations(t, "MockDB.GlobalState")
+	m.HandleFunc.assertExpectations(t, "MockDB.Handle")
+	m.NamespacePermissionsFunc.assertExpectations(t, "MockDB.NamespacePermissions")
+	m.NamespacesFunc.assertExpectations(t, "MockDB.Namespaces")
+	m.OrgInvitationsFunc.assertExpectations(t, "MockDB.OrgInvitations")
+	m.OrgMembersFunc.assertExpectations(t, "MockDB.OrgMembers")
+	m.OrgsFunc.assertExpectations(t, "MockDB.Orgs")
+	m.OutboundWebhookJobsFunc.assertExpectations(t, "MockDB.OutboundWebhookJobs")
+	m.OutboundWebhookLogsFunc.assertExpectations(t, "MockDB.OutboundWebhookLogs")
+	m.OutboundWebhooksFunc.assertExpectations(t, "MockDB.OutboundWebhooks")
+	m.OwnSignalConfigurationsFunc.assertExpectations(t, "MockDB.OwnSignalConfigurations")
+	m.OwnershipStatsFunc.assertExpectations(t, "MockDB.OwnershipStats")
+	m.PermissionSyncJobsFunc.assertExpectations(t, "MockDB.PermissionSyncJobs")
+	m.PermissionsFunc.assertExpectations(t, "MockDB.Permissions")
+	m.PermsFunc.assertExpectations(t, "MockDB.Perms")
+	m.PhabricatorFunc.assertExpectations(t, "MockDB.Phabricator")
+	m.QueryContextFunc.assertExpectations(t, "MockDB.QueryContext")
+	m.QueryRowContextFunc.assertExpectations(t, "MockDB.QueryRowContext")
+	m.RecentContributionSignalsFunc.assertExpectations(t, "MockDB.RecentContributionSignals")
+	m.RecentViewSignalFunc.assertExpectations(t, "MockDB.RecentViewSignal")
+	m.RedisKeyValueFunc.assertExpectations(t, "MockDB.RedisKeyValue")
+	m.RepoCommitsChangelistsFunc.assertExpectations(t, "MockDB.RepoCommitsChangelists")
+	m.RepoKVPsFunc.assertExpectations(t, "MockDB.RepoKVPs")
+	m.RepoPathsFunc.assertExpectations(t, "MockDB.RepoPaths")
+	m.RepoStatisticsFunc.assertExpectations(t, "MockDB.RepoStatistics")
+	m.ReposFunc.assertExpectations(t, "MockDB.Repos")
+	m.RolePermissionsFunc.assertExpectations(t, "MockDB.RolePermissions")
+	m.RolesFunc.assertExpectations(t, "MockDB.Roles")
+	m.SavedSearchesFunc.assertExpectations(t, "MockDB.SavedSearches")
+	m.SearchContextsFunc.assertExpectations(t, "MockDB.SearchContexts")
+	m.SecurityEventLogsFunc.assertExpectations(t, "MockDB.SecurityEventLogs")
+	m.SettingsFunc.assertExpectations(t, "MockDB.Settings")
+	m.SubRepoPermsFunc.assertExpectations(t, "MockDB.SubRepoPerms")
+	m.TeamsFunc.assertExpectations(t, "MockDB.Teams")
+	m.TelemetryEventsExportQueueFunc.assertExpectations(t, "MockDB.TelemetryEventsExportQueue")
+	m.TemporarySettingsFunc.assertExpectations(t, "MockDB.TemporarySettings")
+	m.UserCredentialsFunc.assertExpectations(t, "MockDB.UserCredentials")
+	m.UserEmailsFunc.assertExpectations(t, "MockDB.UserEmails")
+	m.UserExternalAccountsFunc.assertExpectations(t, "MockDB.UserExternalAccounts")
+	m.UserRolesFunc.assertExpectations(t, "MockDB.UserRoles")
+	m.UsersFunc.assertExpectations(t, "MockDB.Users")
+	m.WebhookLogsFunc.assertExpectations(t, "MockDB.WebhookLogs")
+	m.WebhooksFunc.assertExpectations(t, "MockDB.Webhooks")
+	m.WithTransactFunc.assertExpectations(t, "MockDB.WithTransact")
+	m.ZoektReposFunc.assertExpectations(t, "MockDB.ZoektRepos")
+}
+
 // NewMockDBFrom creates a new mock of the MockDB interface. All methods
 // delegate to the given implementation, unless overwritten.
 func NewMockDBFrom(i database.DB) *MockDB {
@@ -16303,6 +19162,18 @@ func (f *DBAccessRequestsFunc) History() []DBAccessRequestsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBAccessRequestsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBAccessRequestsFuncCall is an object that describes an invocation of
 // method AccessRequests on an instance of MockDB.
 type DBAccessRequestsFuncCall struct {
@@ -16401,6 +19272,18 @@ func (f *DBAccessTokensFunc) History() []DBAccessTokensFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBAccessTokensFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBAccessTokensFuncCall is an object that describes an invocation of
 // method AccessTokens on an instance of MockDB.
 type DBAccessTokensFuncCall struct {
@@ -16500,6 +19383,18 @@ func (f *DBAssignedOwnersFunc) History() []DBAssignedOwnersFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBAssignedOwnersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBAssignedOwnersFuncCall is an object that describes an invocation of
 // method AssignedOwners on an instance of MockDB.
 type DBAssignedOwnersFuncCall struct {
@@ -16598,6 +19493,18 @@ func (f *DBAssignedTeamsFunc) History() []DBAssignedTeamsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBAssignedTeamsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBAssignedTeamsFuncCall is an object that describes an invocation of
 // method AssignedTeams on an instance of MockDB.
 type DBAssignedTeamsFuncCall struct {
@@ -16696,6 +19603,18 @@ func (f *DBAuthzFunc) History() []DBAuthzFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBAuthzFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBAuthzFuncCall is an object that describes an invocation of method Authz
 // on an instance of MockDB.
 type DBAuthzFuncCall struct {
@@ -16796,6 +19715,18 @@ func (f *DBBitbucketProjectPermissionsFunc) History() []DBBitbucketProjectPermis
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBBitbucketProjectPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBBitbucketProjectPermissionsFuncCall is an object that describes an
 // invocation of method BitbucketProjectPermissions on an instance of
 // MockDB.
@@ -16895,6 +19826,18 @@ func (f *DBCodeHostsFunc) History() []DBCodeHostsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBCodeHostsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBCodeHostsFuncCall is an object that describes an invocation of method
 // CodeHosts on an instance of MockDB.
 type DBCodeHostsFuncCall struct {
@@ -16993,6 +19936,18 @@ func (f *DBCodeMonitorsFunc) History() []DBCodeMonitorsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBCodeMonitorsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBCodeMonitorsFuncCall is an object that describes an invocation of
 // method CodeMonitors on an instance of MockDB.
 type DBCodeMonitorsFuncCall struct {
@@ -17091,6 +20046,18 @@ func (f *DBCodeownersFunc) History() []DBCodeownersFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBCodeownersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBCodeownersFuncCall is an object that describes an invocation of method
 // Codeowners on an instance of MockDB.
 type DBCodeownersFuncCall struct {
@@ -17189,6 +20156,18 @@ func (f *DBConfFunc) History() []DBConfFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBConfFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBConfFuncCall is an object that describes an invocation of method Conf
 // on an instance of MockDB.
 type DBConfFuncCall struct {
@@ -17287,6 +20266,18 @@ func (f *DBEventLogsFunc) History() []DBEventLogsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBEventLogsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBEventLogsFuncCall is an object that describes an invocation of method
 // EventLogs on an instance of MockDB.
 type DBEventLogsFuncCall struct {
@@ -17386,6 +20377,18 @@ func (f *DBEventLogsScrapeStateFunc) History() []DBEventLogsScrapeStateFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBEventLogsScrapeStateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBEventLogsScrapeStateFuncCall is an object that describes an invocation
 // of method EventLogsScrapeState on an instance of MockDB.
 type DBEventLogsScrapeStateFuncCall struct {
@@ -17484,6 +20487,18 @@ func (f *DBExecContextFunc) History() []DBExecContextFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBExecContextFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBExecContextFuncCall is an object that describes an invocation of method
 // ExecContext on an instance of MockDB.
 type DBExecContextFuncCall struct {
@@ -17602,6 +20617,18 @@ func (f *DBExecutorSecretAccessLogsFunc) History() []DBExecutorSecretAccessLogsF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBExecutorSecretAccessLogsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBExecutorSecretAccessLogsFuncCall is an object that describes an
 // invocation of method ExecutorSecretAccessLogs on an instance of MockDB.
 type DBExecutorSecretAccessLogsFuncCall struct {
@@ -17701,6 +20728,18 @@ func (f *DBExecutorSecretsFunc) History() []DBExecutorSecretsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBExecutorSecretsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBExecutorSecretsFuncCall is an object that describes an invocation of
 // method ExecutorSecrets on an instance of MockDB.
 type DBExecutorSecretsFuncCall struct {
@@ -17802,6 +20841,18 @@ func (f *DBExecutorsFunc) History() []DBExecutorsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBExecutorsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBExecutorsFuncCall is an object that describes an invocation of method
 // Executors on an instance of MockDB.
 type DBExecutorsFuncCall struct {
@@ -17901,6 +20952,18 @@ func (f *DBExternalServicesFunc) History() []DBExternalServicesFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBExternalServicesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBExternalServicesFuncCall is an object that describes an invocation of
 // method ExternalServices on an instance of MockDB.
 type DBExternalServicesFuncCall struct {
@@ -17999,6 +21062,18 @@ func (f *DBFeatureFlagsFunc) History() []DBFeatureFlagsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBFeatureFlagsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBFeatureFlagsFuncCall is an object that describes an invocation of
 // method FeatureFlags on an instance of MockDB.
 type DBFeatureFlagsFuncCall struct {
@@ -18097,6 +21172,18 @@ func (f *DBGitHubAppsFunc) History() []DBGitHubAppsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBGitHubAppsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBGitHubAppsFuncCall is an object that describes an invocation of method
 // GitHubApps on an instance of MockDB.
 type DBGitHubAppsFuncCall struct {
@@ -18196,6 +21283,18 @@ func (f *DBGitserverReposFunc) History() []DBGitserverReposFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBGitserverReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBGitserverReposFuncCall is an object that describes an invocation of
 // method GitserverRepos on an instance of MockDB.
 type DBGitserverReposFuncCall struct {
@@ -18294,6 +21393,18 @@ func (f *DBGlobalStateFunc) History() []DBGlobalStateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBGlobalStateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBGlobalStateFuncCall is an object that describes an invocation of method
 // GlobalState on an instance of MockDB.
 type DBGlobalStateFuncCall struct {
@@ -18392,6 +21503,18 @@ func (f *DBHandleFunc) History() []DBHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBHandleFuncCall is an object that describes an invocation of method
 // Handle on an instance of MockDB.
 type DBHandleFuncCall struct {
@@ -18491,6 +21614,18 @@ func (f *DBNamespacePermissionsFunc) History() []DBNamespacePermissionsFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBNamespacePermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBNamespacePermissionsFuncCall is an object that describes an invocation
 // of method NamespacePermissions on an instance of MockDB.
 type DBNamespacePermissionsFuncCall struct {
@@ -18589,6 +21724,18 @@ func (f *DBNamespacesFunc) History() []DBNamespacesFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBNamespacesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBNamespacesFuncCall is an object that describes an invocation of method
 // Namespaces on an instance of MockDB.
 type DBNamespacesFuncCall struct {
@@ -18688,6 +21835,18 @@ func (f *DBOrgInvitationsFunc) History() []DBOrgInvitationsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBOrgInvitationsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBOrgInvitationsFuncCall is an object that describes an invocation of
 // method OrgInvitations on an instance of MockDB.
 type DBOrgInvitationsFuncCall struct {
@@ -18786,6 +21945,18 @@ func (f *DBOrgMembersFunc) History() []DBOrgMembersFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBOrgMembersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBOrgMembersFuncCall is an object that describes an invocation of method
 // OrgMembers on an instance of MockDB.
 type DBOrgMembersFuncCall struct {
@@ -18884,6 +22055,18 @@ func (f *DBOrgsFunc) History() []DBOrgsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBOrgsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBOrgsFuncCall is an object that describes an invocation of method Orgs
 // on an instance of MockDB.
 type DBOrgsFuncCall struct {
@@ -18983,6 +22166,18 @@ func (f *DBOutboundWebhookJobsFunc) History() []DBOutboundWebhookJobsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBOutboundWebhookJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBOutboundWebhookJobsFuncCall is an object that describes an invocation
 // of method OutboundWebhookJobs on an instance of MockDB.
 type DBOutboundWebhookJobsFuncCall struct {
@@ -19085,6 +22280,18 @@ func (f *DBOutboundWebhookLogsFunc) History() []DBOutboundWebhookLogsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBOutboundWebhookLogsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBOutboundWebhookLogsFuncCall is an object that describes an invocation
 // of method OutboundWebhookLogs on an instance of MockDB.
 type DBOutboundWebhookLogsFuncCall struct {
@@ -19187,6 +22394,18 @@ func (f *DBOutboundWebhooksFunc) History() []DBOutboundWebhooksFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBOutboundWebhooksFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBOutboundWebhooksFuncCall is an object that describes an invocation of
 // method OutboundWebhooks on an instance of MockDB.
 type DBOutboundWebhooksFuncCall struct {
@@ -19289,6 +22508,18 @@ func (f *DBOwnSignalConfigurationsFunc) History() []DBOwnSignalConfigurationsFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBOwnSignalConfigurationsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBOwnSignalConfigurationsFuncCall is an object that describes an
 // invocation of method OwnSignalConfigurations on an instance of MockDB.
 type DBOwnSignalConfigurationsFuncCall struct {
@@ -19388,6 +22619,18 @@ func (f *DBOwnershipStatsFunc) History() []DBOwnershipStatsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBOwnershipStatsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBOwnershipStatsFuncCall is an object that describes an invocation of
 // method OwnershipStats on an instance of MockDB.
 type DBOwnershipStatsFuncCall struct {
@@ -19487,6 +22730,18 @@ func (f *DBPermissionSyncJobsFunc) History() []DBPermissionSyncJobsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBPermissionSyncJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBPermissionSyncJobsFuncCall is an object that describes an invocation of
 // method PermissionSyncJobs on an instance of MockDB.
 type DBPermissionSyncJobsFuncCall struct {
@@ -19585,6 +22840,18 @@ func (f *DBPermissionsFunc) History() []DBPermissionsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBPermissionsFuncCall is an object that describes an invocation of method
 // Permissions on an instance of MockDB.
 type DBPermissionsFuncCall struct {
@@ -19683,6 +22950,18 @@ func (f *DBPermsFunc) History() []DBPermsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBPermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBPermsFuncCall is an object that describes an invocation of method Perms
 // on an instance of MockDB.
 type DBPermsFuncCall struct {
@@ -19781,6 +23060,18 @@ func (f *DBPhabricatorFunc) History() []DBPhabricatorFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBPhabricatorFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBPhabricatorFuncCall is an object that describes an invocation of method
 // Phabricator on an instance of MockDB.
 type DBPhabricatorFuncCall struct {
@@ -19879,6 +23170,18 @@ func (f *DBQueryContextFunc) History() []DBQueryContextFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBQueryContextFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBQueryContextFuncCall is an object that describes an invocation of
 // method QueryContext on an instance of MockDB.
 type DBQueryContextFuncCall struct {
@@ -19997,6 +23300,18 @@ func (f *DBQueryRowContextFunc) History() []DBQueryRowContextFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBQueryRowContextFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBQueryRowContextFuncCall is an object that describes an invocation of
 // method QueryRowContext on an instance of MockDB.
 type DBQueryRowContextFuncCall struct {
@@ -20113,6 +23428,18 @@ func (f *DBRecentContributionSignalsFunc) History() []DBRecentContributionSignal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBRecentContributionSignalsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBRecentContributionSignalsFuncCall is an object that describes an
 // invocation of method RecentContributionSignals on an instance of MockDB.
 type DBRecentContributionSignalsFuncCall struct {
@@ -20212,6 +23539,18 @@ func (f *DBRecentViewSignalFunc) History() []DBRecentViewSignalFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBRecentViewSignalFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBRecentViewSignalFuncCall is an object that describes an invocation of
 // method RecentViewSignal on an instance of MockDB.
 type DBRecentViewSignalFuncCall struct {
@@ -20310,6 +23649,18 @@ func (f *DBRedisKeyValueFunc) History() []DBRedisKeyValueFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBRedisKeyValueFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBRedisKeyValueFuncCall is an object that describes an invocation of
 // method RedisKeyValue on an instance of MockDB.
 type DBRedisKeyValueFuncCall struct {
@@ -20409,6 +23760,18 @@ func (f *DBRepoCommitsChangelistsFunc) History() []DBRepoCommitsChangelistsFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBRepoCommitsChangelistsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBRepoCommitsChangelistsFuncCall is an object that describes an
 // invocation of method RepoCommitsChangelists on an instance of MockDB.
 type DBRepoCommitsChangelistsFuncCall struct {
@@ -20507,6 +23870,18 @@ func (f *DBRepoKVPsFunc) History() []DBRepoKVPsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBRepoKVPsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBRepoKVPsFuncCall is an object that describes an invocation of method
 // RepoKVPs on an instance of MockDB.
 type DBRepoKVPsFuncCall struct {
@@ -20605,6 +23980,18 @@ func (f *DBRepoPathsFunc) History() []DBRepoPathsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBRepoPathsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBRepoPathsFuncCall is an object that describes an invocation of method
 // RepoPaths on an instance of MockDB.
 type DBRepoPathsFuncCall struct {
@@ -20704,6 +24091,18 @@ func (f *DBRepoStatisticsFunc) History() []DBRepoStatisticsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBRepoStatisticsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBRepoStatisticsFuncCall is an object that describes an invocation of
 // method RepoStatistics on an instance of MockDB.
 type DBRepoStatisticsFuncCall struct {
@@ -20802,6 +24201,18 @@ func (f *DBReposFunc) History() []DBReposFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBReposFuncCall is an object that describes an invocation of method Repos
 // on an instance of MockDB.
 type DBReposFuncCall struct {
@@ -20901,6 +24312,18 @@ func (f *DBRolePermissionsFunc) History() []DBRolePermissionsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBRolePermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBRolePermissionsFuncCall is an object that describes an invocation of
 // method RolePermissions on an instance of MockDB.
 type DBRolePermissionsFuncCall struct {
@@ -20999,6 +24422,18 @@ func (f *DBRolesFunc) History() []DBRolesFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBRolesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBRolesFuncCall is an object that describes an invocation of method Roles
 // on an instance of MockDB.
 type DBRolesFuncCall struct {
@@ -21097,6 +24532,18 @@ func (f *DBSavedSearchesFunc) History() []DBSavedSearchesFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBSavedSearchesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBSavedSearchesFuncCall is an object that describes an invocation of
 // method SavedSearches on an instance of MockDB.
 type DBSavedSearchesFuncCall struct {
@@ -21196,6 +24643,18 @@ func (f *DBSearchContextsFunc) History() []DBSearchContextsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBSearchContextsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBSearchContextsFuncCall is an object that describes an invocation of
 // method SearchContexts on an instance of MockDB.
 type DBSearchContextsFuncCall struct {
@@ -21295,6 +24754,18 @@ func (f *DBSecurityEventLogsFunc) History() []DBSecurityEventLogsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBSecurityEventLogsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBSecurityEventLogsFuncCall is an object that describes an invocation of
 // method SecurityEventLogs on an instance of MockDB.
 type DBSecurityEventLogsFuncCall struct {
@@ -21393,6 +24864,18 @@ func (f *DBSettingsFunc) History() []DBSettingsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBSettingsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBSettingsFuncCall is an object that describes an invocation of method
 // Settings on an instance of MockDB.
 type DBSettingsFuncCall struct {
@@ -21491,6 +24974,18 @@ func (f *DBSubRepoPermsFunc) History() []DBSubRepoPermsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBSubRepoPermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBSubRepoPermsFuncCall is an object that describes an invocation of
 // method SubRepoPerms on an instance of MockDB.
 type DBSubRepoPermsFuncCall struct {
@@ -21589,6 +25084,18 @@ func (f *DBTeamsFunc) History() []DBTeamsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBTeamsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBTeamsFuncCall is an object that describes an invocation of method Teams
 // on an instance of MockDB.
 type DBTeamsFuncCall struct {
@@ -21689,6 +25196,18 @@ func (f *DBTelemetryEventsExportQueueFunc) History() []DBTelemetryEventsExportQu
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBTelemetryEventsExportQueueFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBTelemetryEventsExportQueueFuncCall is an object that describes an
 // invocation of method TelemetryEventsExportQueue on an instance of MockDB.
 type DBTelemetryEventsExportQueueFuncCall struct {
@@ -21788,6 +25307,18 @@ func (f *DBTemporarySettingsFunc) History() []DBTemporarySettingsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBTemporarySettingsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBTemporarySettingsFuncCall is an object that describes an invocation of
 // method TemporarySettings on an instance of MockDB.
 type DBTemporarySettingsFuncCall struct {
@@ -21887,6 +25418,18 @@ func (f *DBUserCredentialsFunc) History() []DBUserCredentialsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBUserCredentialsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBUserCredentialsFuncCall is an object that describes an invocation of
 // method UserCredentials on an instance of MockDB.
 type DBUserCredentialsFuncCall struct {
@@ -21988,6 +25531,18 @@ func (f *DBUserEmailsFunc) History() []DBUserEmailsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBUserEmailsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBUserEmailsFuncCall is an object that describes an invocation of method
 // UserEmails on an instance of MockDB.
 type DBUserEmailsFuncCall struct {
@@ -22087,6 +25642,18 @@ func (f *DBUserExternalAccountsFunc) History() []DBUserExternalAccountsFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBUserExternalAccountsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBUserExternalAccountsFuncCall is an object that describes an invocation
 // of method UserExternalAccounts on an instance of MockDB.
 type DBUserExternalAccountsFuncCall struct {
@@ -22185,6 +25752,18 @@ func (f *DBUserRolesFunc) History() []DBUserRolesFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBUserRolesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBUserRolesFuncCall is an object that describes an invocation of method
 // UserRoles on an instance of MockDB.
 type DBUserRolesFuncCall struct {
@@ -22283,6 +25862,18 @@ func (f *DBUsersFunc) History() []DBUsersFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBUsersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBUsersFuncCall is an object that describes an invocation of method Users
 // on an instance of MockDB.
 type DBUsersFuncCall struct {
@@ -22381,6 +25972,18 @@ func (f *DBWebhookLogsFunc) History() []DBWebhookLogsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBWebhookLogsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBWebhookLogsFuncCall is an object that describes an invocation of method
 // WebhookLogs on an instance of MockDB.
 type DBWebhookLogsFuncCall struct {
@@ -22482,6 +26085,18 @@ func (f *DBWebhooksFunc) History() []DBWebhooksFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBWebhooksFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBWebhooksFuncCall is an object that describes an invocation of method
 // Webhooks on an instance of MockDB.
 type DBWebhooksFuncCall struct {
@@ -22583,6 +26198,18 @@ func (f *DBWithTransactFunc) History() []DBWithTransactFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBWithTransactFuncCall is an object that describes an invocation of
 // method WithTransact on an instance of MockDB.
 type DBWithTransactFuncCall struct {
@@ -22687,6 +26314,18 @@ func (f *DBZoektReposFunc) History() []DBZoektReposFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *DBZoektReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // DBZoektReposFuncCall is an object that describes an invocation of method
 // ZoektRepos on an instance of MockDB.
 type DBZoektReposFuncCall struct {
@@ -23265,6 +26904,300 @@ func NewStrictMockEventLogStore() *MockEventLogStore {
 	}
 }
 
+// NewStrictMockEventLogStoreWithT creates a new mock of the EventLogStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockEventLogStoreWithT(t testing.TB) *MockEventLogStore {
+	return &MockEventLogStore{
+		AggregatedCodeIntelEventsFunc: &EventLogStoreAggregatedCodeIntelEventsFunc{
+			defaultHook: func(v0 context.Context) (r0 []types.CodeIntelAggregatedEvent, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.AggregatedCodeIntelEvents(%v)", v0)
+				return
+			},
+		},
+		AggregatedCodeIntelInvestigationEventsFunc: &EventLogStoreAggregatedCodeIntelInvestigationEventsFunc{
+			defaultHook: func(v0 context.Context) (r0 []types.CodeIntelAggregatedInvestigationEvent, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.AggregatedCodeIntelInvestigationEvents(%v)", v0)
+				return
+			},
+		},
+		AggregatedCodyUsageFunc: &EventLogStoreAggregatedCodyUsageFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time) (r0 *types.CodyAggregatedUsage, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.AggregatedCodyUsage(%v, %v)", v0, v1)
+				return
+			},
+		},
+		AggregatedRepoMetadataEventsFunc: &EventLogStoreAggregatedRepoMetadataEventsFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time, v2 database.PeriodType) (r0 *types.RepoMetadataAggregatedEvents, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.AggregatedRepoMetadataEvents(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		AggregatedSearchEventsFunc: &EventLogStoreAggregatedSearchEventsFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time) (r0 []types.SearchAggregatedEvent, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.AggregatedSearchEvents(%v, %v)", v0, v1)
+				return
+			},
+		},
+		BulkInsertFunc: &EventLogStoreBulkInsertFunc{
+			defaultHook: func(v0 context.Context, v1 []*database.Event) (r0 error) {
+				t.Errorf("unexpected call of MockEventLogStore.BulkInsert(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CodeIntelligenceCrossRepositoryWAUsFunc: &EventLogStoreCodeIntelligenceCrossRepositoryWAUsFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CodeIntelligenceCrossRepositoryWAUs(%v)", v0)
+				return
+			},
+		},
+		CodeIntelligencePreciseCrossRepositoryWAUsFunc: &EventLogStoreCodeIntelligencePreciseCrossRepositoryWAUsFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CodeIntelligencePreciseCrossRepositoryWAUs(%v)", v0)
+				return
+			},
+		},
+		CodeIntelligencePreciseWAUsFunc: &EventLogStoreCodeIntelligencePreciseWAUsFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CodeIntelligencePreciseWAUs(%v)", v0)
+				return
+			},
+		},
+		CodeIntelligenceRepositoryCountsFunc: &EventLogStoreCodeIntelligenceRepositoryCountsFunc{
+			defaultHook: func(v0 context.Context) (r0 database.CodeIntelligenceRepositoryCounts, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CodeIntelligenceRepositoryCounts(%v)", v0)
+				return
+			},
+		},
+		CodeIntelligenceRepositoryCountsByLanguageFunc: &EventLogStoreCodeIntelligenceRepositoryCountsByLanguageFunc{
+			defaultHook: func(v0 context.Context) (r0 map[string]database.CodeIntelligenceRepositoryCountsForLanguage, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CodeIntelligenceRepositoryCountsByLanguage(%v)", v0)
+				return
+			},
+		},
+		CodeIntelligenceSearchBasedCrossRepositoryWAUsFunc: &EventLogStoreCodeIntelligenceSearchBasedCrossRepositoryWAUsFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CodeIntelligenceSearchBasedCrossRepositoryWAUs(%v)", v0)
+				return
+			},
+		},
+		CodeIntelligenceSearchBasedWAUsFunc: &EventLogStoreCodeIntelligenceSearchBasedWAUsFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CodeIntelligenceSearchBasedWAUs(%v)", v0)
+				return
+			},
+		},
+		CodeIntelligenceSettingsPageViewCountFunc: &EventLogStoreCodeIntelligenceSettingsPageViewCountFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CodeIntelligenceSettingsPageViewCount(%v)", v0)
+				return
+			},
+		},
+		CodeIntelligenceWAUsFunc: &EventLogStoreCodeIntelligenceWAUsFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CodeIntelligenceWAUs(%v)", v0)
+				return
+			},
+		},
+		CountBySQLFunc: &EventLogStoreCountBySQLFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CountBySQL(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountByUserIDFunc: &EventLogStoreCountByUserIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CountByUserID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountByUserIDAndEventNameFunc: &EventLogStoreCountByUserIDAndEventNameFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CountByUserIDAndEventName(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CountByUserIDAndEventNamePrefixFunc: &EventLogStoreCountByUserIDAndEventNamePrefixFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CountByUserIDAndEventNamePrefix(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CountByUserIDAndEventNamesFunc: &EventLogStoreCountByUserIDAndEventNamesFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 []string) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CountByUserIDAndEventNames(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CountUniqueUsersAllFunc: &EventLogStoreCountUniqueUsersAllFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time, v2 time.Time, v3 *database.CountUniqueUsersOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CountUniqueUsersAll(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		CountUniqueUsersByEventNameFunc: &EventLogStoreCountUniqueUsersByEventNameFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time, v2 time.Time, v3 string) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CountUniqueUsersByEventName(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		CountUniqueUsersByEventNamePrefixFunc: &EventLogStoreCountUniqueUsersByEventNamePrefixFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time, v2 time.Time, v3 string) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CountUniqueUsersByEventNamePrefix(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		CountUniqueUsersByEventNamesFunc: &EventLogStoreCountUniqueUsersByEventNamesFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time, v2 time.Time, v3 []string) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CountUniqueUsersByEventNames(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		CountUsersWithSettingFunc: &EventLogStoreCountUsersWithSettingFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 interface{}) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.CountUsersWithSetting(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		HandleFunc: &EventLogStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockEventLogStore.Handle()")
+				return
+			},
+		},
+		InsertFunc: &EventLogStoreInsertFunc{
+			defaultHook: func(v0 context.Context, v1 *database.Event) (r0 error) {
+				t.Errorf("unexpected call of MockEventLogStore.Insert(%v, %v)", v0, v1)
+				return
+			},
+		},
+		LatestPingFunc: &EventLogStoreLatestPingFunc{
+			defaultHook: func(v0 context.Context) (r0 *database.Event, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.LatestPing(%v)", v0)
+				return
+			},
+		},
+		ListAllFunc: &EventLogStoreListAllFunc{
+			defaultHook: func(v0 context.Context, v1 database.EventLogsListOptions) (r0 []*database.Event, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.ListAll(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListExportableEventsFunc: &EventLogStoreListExportableEventsFunc{
+			defaultHook: func(v0 context.Context, v1 int, v2 int) (r0 []*database.Event, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.ListExportableEvents(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		ListUniqueUsersAllFunc: &EventLogStoreListUniqueUsersAllFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time, v2 time.Time) (r0 []int32, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.ListUniqueUsersAll(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		MaxTimestampByUserIDFunc: &EventLogStoreMaxTimestampByUserIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *time.Time, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.MaxTimestampByUserID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		MaxTimestampByUserIDAndSourceFunc: &EventLogStoreMaxTimestampByUserIDAndSourceFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 *time.Time, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.MaxTimestampByUserIDAndSource(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		OwnershipFeatureActivityFunc: &EventLogStoreOwnershipFeatureActivityFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time, v2 ...string) (r0 map[string]*types.OwnershipUsageStatisticsActiveUsers, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.OwnershipFeatureActivity(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		RequestsByLanguageFunc: &EventLogStoreRequestsByLanguageFunc{
+			defaultHook: func(v0 context.Context) (r0 map[string]int, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.RequestsByLanguage(%v)", v0)
+				return
+			},
+		},
+		SiteUsageCurrentPeriodsFunc: &EventLogStoreSiteUsageCurrentPeriodsFunc{
+			defaultHook: func(v0 context.Context) (r0 types.SiteUsageSummary, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.SiteUsageCurrentPeriods(%v)", v0)
+				return
+			},
+		},
+		SiteUsageMultiplePeriodsFunc: &EventLogStoreSiteUsageMultiplePeriodsFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time, v2 int, v3 int, v4 int, v5 *database.CountUniqueUsersOptions) (r0 *types.SiteUsageStatistics, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.SiteUsageMultiplePeriods(%v, %v, %v, %v, %v, %v)", v0, v1, v2, v3, v4, v5)
+				return
+			},
+		},
+		UsersUsageCountsFunc: &EventLogStoreUsersUsageCountsFunc{
+			defaultHook: func(v0 context.Context) (r0 []types.UserUsageCounts, r1 error) {
+				t.Errorf("unexpected call of MockEventLogStore.UsersUsageCounts(%v)", v0)
+				return
+			},
+		},
+		WithFunc: &EventLogStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.EventLogStore) {
+				t.Errorf("unexpected call of MockEventLogStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &EventLogStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.EventLogStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockEventLogStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockEventLogStore instance with PushHook or PushReturn which was not called.
+func (m *MockEventLogStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.AggregatedCodeIntelEventsFunc.assertExpectations(t, "MockEventLogStore.AggregatedCodeIntelEvents")
+	m.AggregatedCodeIntelInvestigationEventsFunc.assertExpectations(t, "MockEventLogStore.AggregatedCodeIntelInvestigationEvents")
+	m.AggregatedCodyUsageFunc.assertExpectations(t, "MockEventLogStore.AggregatedCodyUsage")
+	m.AggregatedRepoMetadataEventsFunc.assertExpectations(t, "MockEventLogStore.AggregatedRepoMetadataEvents")
+	m.AggregatedSearchEventsFunc.assertExpectations(t, "MockEventLogStore.AggregatedSearchEvents")
+	m.BulkInsertFunc.assertExpectations(t, "MockEventLogStore.BulkInsert")
+	m.CodeIntelligenceCrossRepositoryWAUsFunc.assertExpectations(t, "MockEventLogStore.CodeIntelligenceCrossRepositoryWAUs")
+	m.CodeIntelligencePreciseCrossRepositoryWAUsFunc.assertExpectations(t, "MockEventLogStore.CodeIntelligencePreciseCrossRepositoryWAUs")
+	m.CodeIntelligencePreciseWAUsFunc.assertExpectations(t, "MockEventLogStore.CodeIntelligencePreciseWAUs")
+	m.CodeIntelligenceRepositoryCountsFunc.assertExpectations(t, "MockEventLogStore.CodeIntelligenceRepositoryCounts")
+	m.CodeIntelligenceRepositoryCountsByLanguageFunc.assertExpectations(t, "MockEventLogStore.CodeIntelligenceRepositoryCountsByLanguage")
+	m.CodeIntelligenceSearchBasedCrossRepositoryWAUsFunc.assertExpectations(t, "MockEventLogStore.CodeIntelligenceSearchBasedCrossRepositoryWAUs")
+	m.CodeIntelligenceSearchBasedWAUsFunc.assertExpectations(t, "MockEventLogStore.CodeIntelligenceSearchBasedWAUs")
+	m.CodeIntelligenceSettingsPageViewCountFunc.assertExpectations(t, "MockEventLogStore.CodeIntelligenceSettingsPageViewCount")
+	m.CodeIntelligenceWAUsFunc.assertExpectations(t, "MockEventLogStore.CodeIntelligenceWAUs")
+	m.CountBySQLFunc.assertExpectations(t, "MockEventLogStore.CountBySQL")
+	m.CountByUserIDFunc.assertExpectations(t, "MockEventLogStore.CountByUserID")
+	m.CountByUserIDAndEventNameFunc.assertExpectations(t, "MockEventLogStore.CountByUserIDAndEventName")
+	m.CountByUserIDAndEventNamePrefixFunc.assertExpectations(t, "MockEventLogStore.CountByUserIDAndEventNamePrefix")
+	m.CountByUserIDAndEventNamesFunc.assertExpectations(t, "MockEventLogStore.CountByUserIDAndEventNames")
+	m.CountUniqueUsersAllFunc.assertExpectations(t, "MockEventLogStore.CountUniqueUsersAll")
+	m.CountUniqueUsersByEventNameFunc.assertExpectations(t, "MockEventLogStore.CountUniqueUsersByEventName")
+	m.CountUniqueUsersByEventNamePrefixFunc.assertExpectations(t, "MockEventLogStore.CountUniqueUsersByEventNamePrefix")
+	m.CountUniqueUsersByEventNamesFunc.assertExpectations(t, "MockEventLogStore.CountUniqueUsersByEventNames")
+	m.CountUsersWithSettingFunc.assertExpectations(t, "MockEventLogStore.CountUsersWithSetting")
+	m.HandleFunc.assertExpectations(t, "MockEventLogStore.Handle")
+	m.InsertFunc.assertExpectations(t, "MockEventLogStore.Insert")
+	m.LatestPingFunc.assertExpectations(t, "MockEventLogStore.LatestPing")
+	m.ListAllFunc.assertExpectations(t, "MockEventLogStore.ListAll")
+	m.ListExportableEventsFunc.assertExpectations(t, "MockEventLogStore.ListExportableEvents")
+	m.ListUniqueUsersAllFunc.assertExpectations(t, "MockEventLogStore.ListUniqueUsersAll")
+	m.MaxTimestampByUserIDFunc.assertExpectations(t, "MockEventLogStore.MaxTimestampByUserID")
+	m.MaxTimestampByUserIDAndSourceFunc.assertExpectations(t, "MockEventLogStore.MaxTimestampByUserIDAndSource")
+	m.OwnershipFeatureActivityFunc.assertExpectations(t, "MockEventLogStore.OwnershipFeatureActivity")
+	m.RequestsByLanguageFunc.assertExpectations(t, "MockEventLogStore.RequestsByLanguage")
+	m.SiteUsageCurrentPeriodsFunc.assertExpectations(t, "MockEventLogStore.SiteUsageCurrentPeriods")
+	m.SiteUsageMultiplePeriodsFunc.assertExpectations(t, "MockEventLogStore.SiteUsageMultiplePeriods")
+	m.UsersUsageCountsFunc.assertExpectations(t, "MockEventLogStore.UsersUsageCounts")
+	m.WithFunc.assertExpectations(t, "MockEventLogStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockEventLogStore.WithTransact")
+}
+
 // NewMockEventLogStoreFrom creates a new mock of the MockEventLogStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -23475,6 +27408,18 @@ func (f *EventLogStoreAggregatedCodeIntelEventsFunc) History() []EventLogStoreAg
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreAggregatedCodeIntelEventsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreAggregatedCodeIntelEventsFuncCall is an object that
 // describes an invocation of method AggregatedCodeIntelEvents on an
 // instance of MockEventLogStore.
@@ -23585,6 +27530,18 @@ func (f *EventLogStoreAggregatedCodeIntelInvestigationEventsFunc) History() []Ev
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreAggregatedCodeIntelInvestigationEventsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreAggregatedCodeIntelInvestigationEventsFuncCall is an object
 // that describes an invocation of method
 // AggregatedCodeIntelInvestigationEvents on an instance of
@@ -23694,6 +27651,18 @@ func (f *EventLogStoreAggregatedCodyUsageFunc) History() []EventLogStoreAggregat
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreAggregatedCodyUsageFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreAggregatedCodyUsageFuncCall is an object that describes an
 // invocation of method AggregatedCodyUsage on an instance of
 // MockEventLogStore.
@@ -23806,6 +27775,18 @@ func (f *EventLogStoreAggregatedRepoMetadataEventsFunc) History() []EventLogStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreAggregatedRepoMetadataEventsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreAggregatedRepoMetadataEventsFuncCall is an object that
 // describes an invocation of method AggregatedRepoMetadataEvents on an
 // instance of MockEventLogStore.
@@ -23920,6 +27901,18 @@ func (f *EventLogStoreAggregatedSearchEventsFunc) History() []EventLogStoreAggre
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreAggregatedSearchEventsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreAggregatedSearchEventsFuncCall is an object that describes
 // an invocation of method AggregatedSearchEvents on an instance of
 // MockEventLogStore.
@@ -24029,6 +28022,18 @@ func (f *EventLogStoreBulkInsertFunc) History() []EventLogStoreBulkInsertFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreBulkInsertFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreBulkInsertFuncCall is an object that describes an invocation
 // of method BulkInsert on an instance of MockEventLogStore.
 type EventLogStoreBulkInsertFuncCall struct {
@@ -24138,6 +28143,18 @@ func (f *EventLogStoreCodeIntelligenceCrossRepositoryWAUsFunc) History() []Event
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCodeIntelligenceCrossRepositoryWAUsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCodeIntelligenceCrossRepositoryWAUsFuncCall is an object
 // that describes an invocation of method
 // CodeIntelligenceCrossRepositoryWAUs on an instance of MockEventLogStore.
@@ -24248,6 +28265,18 @@ func (f *EventLogStoreCodeIntelligencePreciseCrossRepositoryWAUsFunc) History()
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCodeIntelligencePreciseCrossRepositoryWAUsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCodeIntelligencePreciseCrossRepositoryWAUsFuncCall is an
 // object that describes an invocation of method
 // CodeIntelligencePreciseCrossRepositoryWAUs on an instance of
@@ -24358,6 +28387,18 @@ func (f *EventLogStoreCodeIntelligencePreciseWAUsFunc) History() []EventLogStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCodeIntelligencePreciseWAUsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCodeIntelligencePreciseWAUsFuncCall is an object that
 // describes an invocation of method CodeIntelligencePreciseWAUs on an
 // instance of MockEventLogStore.
@@ -24467,6 +28508,18 @@ func (f *EventLogStoreCodeIntelligenceRepositoryCountsFunc) History() []EventLog
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCodeIntelligenceRepositoryCountsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCodeIntelligenceRepositoryCountsFuncCall is an object that
 // describes an invocation of method CodeIntelligenceRepositoryCounts on an
 // instance of MockEventLogStore.
@@ -24577,6 +28630,18 @@ func (f *EventLogStoreCodeIntelligenceRepositoryCountsByLanguageFunc) History()
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCodeIntelligenceRepositoryCountsByLanguageFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCodeIntelligenceRepositoryCountsByLanguageFuncCall is an
 // object that describes an invocation of method
 // CodeIntelligenceRepositoryCountsByLanguage on an instance of
@@ -24688,6 +28753,18 @@ func (f *EventLogStoreCodeIntelligenceSearchBasedCrossRepositoryWAUsFunc) Histor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCodeIntelligenceSearchBasedCrossRepositoryWAUsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCodeIntelligenceSearchBasedCrossRepositoryWAUsFuncCall is an
 // object that describes an invocation of method
 // CodeIntelligenceSearchBasedCrossRepositoryWAUs on an instance of
@@ -24798,6 +28875,18 @@ func (f *EventLogStoreCodeIntelligenceSearchBasedWAUsFunc) History() []EventLogS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCodeIntelligenceSearchBasedWAUsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCodeIntelligenceSearchBasedWAUsFuncCall is an object that
 // describes an invocation of method CodeIntelligenceSearchBasedWAUs on an
 // instance of MockEventLogStore.
@@ -24908,6 +28997,18 @@ func (f *EventLogStoreCodeIntelligenceSettingsPageViewCountFunc) History() []Eve
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCodeIntelligenceSettingsPageViewCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCodeIntelligenceSettingsPageViewCountFuncCall is an object
 // that describes an invocation of method
 // CodeIntelligenceSettingsPageViewCount on an instance of
@@ -25017,6 +29118,18 @@ func (f *EventLogStoreCodeIntelligenceWAUsFunc) History() []EventLogStoreCodeInt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCodeIntelligenceWAUsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCodeIntelligenceWAUsFuncCall is an object that describes an
 // invocation of method CodeIntelligenceWAUs on an instance of
 // MockEventLogStore.
@@ -25123,6 +29236,18 @@ func (f *EventLogStoreCountBySQLFunc) History() []EventLogStoreCountBySQLFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCountBySQLFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCountBySQLFuncCall is an object that describes an invocation
 // of method CountBySQL on an instance of MockEventLogStore.
 type EventLogStoreCountBySQLFuncCall struct {
@@ -25231,6 +29356,18 @@ func (f *EventLogStoreCountByUserIDFunc) History() []EventLogStoreCountByUserIDF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCountByUserIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCountByUserIDFuncCall is an object that describes an
 // invocation of method CountByUserID on an instance of MockEventLogStore.
 type EventLogStoreCountByUserIDFuncCall struct {
@@ -25342,6 +29479,18 @@ func (f *EventLogStoreCountByUserIDAndEventNameFunc) History() []EventLogStoreCo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCountByUserIDAndEventNameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCountByUserIDAndEventNameFuncCall is an object that
 // describes an invocation of method CountByUserIDAndEventName on an
 // instance of MockEventLogStore.
@@ -25457,6 +29606,18 @@ func (f *EventLogStoreCountByUserIDAndEventNamePrefixFunc) History() []EventLogS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCountByUserIDAndEventNamePrefixFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCountByUserIDAndEventNamePrefixFuncCall is an object that
 // describes an invocation of method CountByUserIDAndEventNamePrefix on an
 // instance of MockEventLogStore.
@@ -25572,6 +29733,18 @@ func (f *EventLogStoreCountByUserIDAndEventNamesFunc) History() []EventLogStoreC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCountByUserIDAndEventNamesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCountByUserIDAndEventNamesFuncCall is an object that
 // describes an invocation of method CountByUserIDAndEventNames on an
 // instance of MockEventLogStore.
@@ -25686,6 +29859,18 @@ func (f *EventLogStoreCountUniqueUsersAllFunc) History() []EventLogStoreCountUni
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCountUniqueUsersAllFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCountUniqueUsersAllFuncCall is an object that describes an
 // invocation of method CountUniqueUsersAll on an instance of
 // MockEventLogStore.
@@ -25804,6 +29989,18 @@ func (f *EventLogStoreCountUniqueUsersByEventNameFunc) History() []EventLogStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCountUniqueUsersByEventNameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCountUniqueUsersByEventNameFuncCall is an object that
 // describes an invocation of method CountUniqueUsersByEventName on an
 // instance of MockEventLogStore.
@@ -25922,6 +30119,18 @@ func (f *EventLogStoreCountUniqueUsersByEventNamePrefixFunc) History() []EventLo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCountUniqueUsersByEventNamePrefixFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCountUniqueUsersByEventNamePrefixFuncCall is an object that
 // describes an invocation of method CountUniqueUsersByEventNamePrefix on an
 // instance of MockEventLogStore.
@@ -26040,6 +30249,18 @@ func (f *EventLogStoreCountUniqueUsersByEventNamesFunc) History() []EventLogStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCountUniqueUsersByEventNamesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCountUniqueUsersByEventNamesFuncCall is an object that
 // describes an invocation of method CountUniqueUsersByEventNames on an
 // instance of MockEventLogStore.
@@ -26157,6 +30378,18 @@ func (f *EventLogStoreCountUsersWithSettingFunc) History() []EventLogStoreCountU
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreCountUsersWithSettingFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreCountUsersWithSettingFuncCall is an object that describes an
 // invocation of method CountUsersWithSetting on an instance of
 // MockEventLogStore.
@@ -26268,6 +30501,18 @@ func (f *EventLogStoreHandleFunc) History() []EventLogStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockEventLogStore.
 type EventLogStoreHandleFuncCall struct {
@@ -26366,6 +30611,18 @@ func (f *EventLogStoreInsertFunc) History() []EventLogStoreInsertFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreInsertFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreInsertFuncCall is an object that describes an invocation of
 // method Insert on an instance of MockEventLogStore.
 type EventLogStoreInsertFuncCall struct {
@@ -26471,6 +30728,18 @@ func (f *EventLogStoreLatestPingFunc) History() []EventLogStoreLatestPingFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreLatestPingFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreLatestPingFuncCall is an object that describes an invocation
 // of method LatestPing on an instance of MockEventLogStore.
 type EventLogStoreLatestPingFuncCall struct {
@@ -26576,6 +30845,18 @@ func (f *EventLogStoreListAllFunc) History() []EventLogStoreListAllFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreListAllFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreListAllFuncCall is an object that describes an invocation of
 // method ListAll on an instance of MockEventLogStore.
 type EventLogStoreListAllFuncCall struct {
@@ -26686,6 +30967,18 @@ func (f *EventLogStoreListExportableEventsFunc) History() []EventLogStoreListExp
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreListExportableEventsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreListExportableEventsFuncCall is an object that describes an
 // invocation of method ListExportableEvents on an instance of
 // MockEventLogStore.
@@ -26800,6 +31093,18 @@ func (f *EventLogStoreListUniqueUsersAllFunc) History() []EventLogStoreListUniqu
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreListUniqueUsersAllFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreListUniqueUsersAllFuncCall is an object that describes an
 // invocation of method ListUniqueUsersAll on an instance of
 // MockEventLogStore.
@@ -26914,6 +31219,18 @@ func (f *EventLogStoreMaxTimestampByUserIDFunc) History() []EventLogStoreMaxTime
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreMaxTimestampByUserIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreMaxTimestampByUserIDFuncCall is an object that describes an
 // invocation of method MaxTimestampByUserID on an instance of
 // MockEventLogStore.
@@ -27026,6 +31343,18 @@ func (f *EventLogStoreMaxTimestampByUserIDAndSourceFunc) History() []EventLogSto
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreMaxTimestampByUserIDAndSourceFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreMaxTimestampByUserIDAndSourceFuncCall is an object that
 // describes an invocation of method MaxTimestampByUserIDAndSource on an
 // instance of MockEventLogStore.
@@ -27141,6 +31470,18 @@ func (f *EventLogStoreOwnershipFeatureActivityFunc) History() []EventLogStoreOwn
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreOwnershipFeatureActivityFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreOwnershipFeatureActivityFuncCall is an object that describes
 // an invocation of method OwnershipFeatureActivity on an instance of
 // MockEventLogStore.
@@ -27262,6 +31603,18 @@ func (f *EventLogStoreRequestsByLanguageFunc) History() []EventLogStoreRequestsB
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreRequestsByLanguageFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreRequestsByLanguageFuncCall is an object that describes an
 // invocation of method RequestsByLanguage on an instance of
 // MockEventLogStore.
@@ -27371,6 +31724,18 @@ func (f *EventLogStoreSiteUsageCurrentPeriodsFunc) History() []EventLogStoreSite
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreSiteUsageCurrentPeriodsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreSiteUsageCurrentPeriodsFuncCall is an object that describes
 // an invocation of method SiteUsageCurrentPeriods on an instance of
 // MockEventLogStore.
@@ -27480,6 +31845,18 @@ func (f *EventLogStoreSiteUsageMultiplePeriodsFunc) History() []EventLogStoreSit
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreSiteUsageMultiplePeriodsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreSiteUsageMultiplePeriodsFuncCall is an object that describes
 // an invocation of method SiteUsageMultiplePeriods on an instance of
 // MockEventLogStore.
@@ -27602,6 +31979,18 @@ func (f *EventLogStoreUsersUsageCountsFunc) History() []EventLogStoreUsersUsageC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreUsersUsageCountsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreUsersUsageCountsFuncCall is an object that describes an
 // invocation of method UsersUsageCounts on an instance of
 // MockEventLogStore.
@@ -27707,6 +32096,18 @@ func (f *EventLogStoreWithFunc) History() []EventLogStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockEventLogStore.
 type EventLogStoreWithFuncCall struct {
@@ -27809,6 +32210,18 @@ func (f *EventLogStoreWithTransactFunc) History() []EventLogStoreWithTransactFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *EventLogStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // EventLogStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockEventLogStore.
 type EventLogStoreWithTransactFuncCall struct {
@@ -27949,6 +32362,70 @@ func NewStrictMockExecutorSecretAccessLogStore() *MockExecutorSecretAccessLogSto
 	}
 }
 
+// NewStrictMockExecutorSecretAccessLogStoreWithT creates a new mock of the
+// ExecutorSecretAccessLogStore interface. All methods fail t with their
+// arguments and return zero values for all results, unless overwritten.
+func NewStrictMockExecutorSecretAccessLogStoreWithT(t testing.TB) *MockExecutorSecretAccessLogStore {
+	return &MockExecutorSecretAccessLogStore{
+		CountFunc: &ExecutorSecretAccessLogStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExecutorSecretAccessLogsListOpts) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockExecutorSecretAccessLogStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &ExecutorSecretAccessLogStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 *database.ExecutorSecretAccessLog) (r0 error) {
+				t.Errorf("unexpected call of MockExecutorSecretAccessLogStore.Create(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &ExecutorSecretAccessLogStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *database.ExecutorSecretAccessLog, r1 error) {
+				t.Errorf("unexpected call of MockExecutorSecretAccessLogStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &ExecutorSecretAccessLogStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockExecutorSecretAccessLogStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &ExecutorSecretAccessLogStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExecutorSecretAccessLogsListOpts) (r0 []*database.ExecutorSecretAccessLog, r1 int, r2 error) {
+				t.Errorf("unexpected call of MockExecutorSecretAccessLogStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &ExecutorSecretAccessLogStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.ExecutorSecretAccessLogStore) {
+				t.Errorf("unexpected call of MockExecutorSecretAccessLogStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &ExecutorSecretAccessLogStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.ExecutorSecretAccessLogStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockExecutorSecretAccessLogStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockExecutorSecretAccessLogStore instance with PushHook or PushReturn which
+// was not called.
+func (m *MockExecutorSecretAccessLogStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockExecutorSecretAccessLogStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockExecutorSecretAccessLogStore.Create")
+	m.GetByIDFunc.assertExpectations(t, "MockExecutorSecretAccessLogStore.GetByID")
+	m.HandleFunc.assertExpectations(t, "MockExecutorSecretAccessLogStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockExecutorSecretAccessLogStore.List")
+	m.WithFunc.assertExpectations(t, "MockExecutorSecretAccessLogStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockExecutorSecretAccessLogStore.WithTransact")
+}
+
 // NewMockExecutorSecretAccessLogStoreFrom creates a new mock of the
 // MockExecutorSecretAccessLogStore interface. All methods delegate to the
 // given implementation, unless overwritten.
@@ -28059,6 +32536,18 @@ func (f *ExecutorSecretAccessLogStoreCountFunc) History() []ExecutorSecretAccess
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretAccessLogStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretAccessLogStoreCountFuncCall is an object that describes an
 // invocation of method Count on an instance of
 // MockExecutorSecretAccessLogStore.
@@ -28170,6 +32659,18 @@ func (f *ExecutorSecretAccessLogStoreCreateFunc) History() []ExecutorSecretAcces
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretAccessLogStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretAccessLogStoreCreateFuncCall is an object that describes an
 // invocation of method Create on an instance of
 // MockExecutorSecretAccessLogStore.
@@ -28278,6 +32779,18 @@ func (f *ExecutorSecretAccessLogStoreGetByIDFunc) History() []ExecutorSecretAcce
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretAccessLogStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretAccessLogStoreGetByIDFuncCall is an object that describes
 // an invocation of method GetByID on an instance of
 // MockExecutorSecretAccessLogStore.
@@ -28389,6 +32902,18 @@ func (f *ExecutorSecretAccessLogStoreHandleFunc) History() []ExecutorSecretAcces
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretAccessLogStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretAccessLogStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of
 // MockExecutorSecretAccessLogStore.
@@ -28491,6 +33016,18 @@ func (f *ExecutorSecretAccessLogStoreListFunc) History() []ExecutorSecretAccessL
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretAccessLogStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretAccessLogStoreListFuncCall is an object that describes an
 // invocation of method List on an instance of
 // MockExecutorSecretAccessLogStore.
@@ -28605,6 +33142,18 @@ func (f *ExecutorSecretAccessLogStoreWithFunc) History() []ExecutorSecretAccessL
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretAccessLogStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretAccessLogStoreWithFuncCall is an object that describes an
 // invocation of method With on an instance of
 // MockExecutorSecretAccessLogStore.
@@ -28711,6 +33260,18 @@ func (f *ExecutorSecretAccessLogStoreWithTransactFunc) History() []ExecutorSecre
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretAccessLogStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretAccessLogStoreWithTransactFuncCall is an object that
 // describes an invocation of method WithTransact on an instance of
 // MockExecutorSecretAccessLogStore.
@@ -28904,6 +33465,98 @@ func NewStrictMockExecutorSecretStore() *MockExecutorSecretStore {
 	}
 }
 
+// NewStrictMockExecutorSecretStoreWithT creates a new mock of the
+// ExecutorSecretStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockExecutorSecretStoreWithT(t testing.TB) *MockExecutorSecretStore {
+	return &MockExecutorSecretStore{
+		CountFunc: &ExecutorSecretStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExecutorSecretScope, v2 database.ExecutorSecretsListOpts) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.Count(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreateFunc: &ExecutorSecretStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExecutorSecretScope, v2 *database.ExecutorSecret, v3 string) (r0 error) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.Create(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		DeleteFunc: &ExecutorSecretStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExecutorSecretScope, v2 int64) (r0 error) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.Delete(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DoneFunc: &ExecutorSecretStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.Done(%v)", v0)
+				return
+			},
+		},
+		ExecResultFunc: &ExecutorSecretStoreExecResultFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 sql.Result, r1 error) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.ExecResult(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &ExecutorSecretStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExecutorSecretScope, v2 int64) (r0 *database.ExecutorSecret, r1 error) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.GetByID(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		HandleFunc: &ExecutorSecretStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &ExecutorSecretStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExecutorSecretScope, v2 database.ExecutorSecretsListOpts) (r0 []*database.ExecutorSecret, r1 int, r2 error) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.List(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		UpdateFunc: &ExecutorSecretStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExecutorSecretScope, v2 *database.ExecutorSecret, v3 string) (r0 error) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.Update(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		WithFunc: &ExecutorSecretStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.ExecutorSecretStore) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &ExecutorSecretStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.ExecutorSecretStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockExecutorSecretStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockExecutorSecretStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockExecutorSecretStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockExecutorSecretStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockExecutorSecretStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockExecutorSecretStore.Delete")
+	m.DoneFunc.assertExpectations(t, "MockExecutorSecretStore.Done")
+	m.ExecResultFunc.assertExpectations(t, "MockExecutorSecretStore.ExecResult")
+	m.GetByIDFunc.assertExpectations(t, "MockExecutorSecretStore.GetByID")
+	m.HandleFunc.assertExpectations(t, "MockExecutorSecretStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockExecutorSecretStore.List")
+	m.UpdateFunc.assertExpectations(t, "MockExecutorSecretStore.Update")
+	m.WithFunc.assertExpectations(t, "MockExecutorSecretStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockExecutorSecretStore.WithTransact")
+}
+
 // NewMockExecutorSecretStoreFrom creates a new mock of the
 // MockExecutorSecretStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -29024,6 +33677,18 @@ func (f *ExecutorSecretStoreCountFunc) History() []ExecutorSecretStoreCountFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreCountFuncCall is an object that describes an
 // invocation of method Count on an instance of MockExecutorSecretStore.
 type ExecutorSecretStoreCountFuncCall struct {
@@ -29135,6 +33800,18 @@ func (f *ExecutorSecretStoreCreateFunc) History() []ExecutorSecretStoreCreateFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreCreateFuncCall is an object that describes an
 // invocation of method Create on an instance of MockExecutorSecretStore.
 type ExecutorSecretStoreCreateFuncCall struct {
@@ -29246,6 +33923,18 @@ func (f *ExecutorSecretStoreDeleteFunc) History() []ExecutorSecretStoreDeleteFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreDeleteFuncCall is an object that describes an
 // invocation of method Delete on an instance of MockExecutorSecretStore.
 type ExecutorSecretStoreDeleteFuncCall struct {
@@ -29354,6 +34043,18 @@ func (f *ExecutorSecretStoreDoneFunc) History() []ExecutorSecretStoreDoneFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreDoneFuncCall is an object that describes an invocation
 // of method Done on an instance of MockExecutorSecretStore.
 type ExecutorSecretStoreDoneFuncCall struct {
@@ -29457,6 +34158,18 @@ func (f *ExecutorSecretStoreExecResultFunc) History() []ExecutorSecretStoreExecR
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreExecResultFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreExecResultFuncCall is an object that describes an
 // invocation of method ExecResult on an instance of
 // MockExecutorSecretStore.
@@ -29566,6 +34279,18 @@ func (f *ExecutorSecretStoreGetByIDFunc) History() []ExecutorSecretStoreGetByIDF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreGetByIDFuncCall is an object that describes an
 // invocation of method GetByID on an instance of MockExecutorSecretStore.
 type ExecutorSecretStoreGetByIDFuncCall struct {
@@ -29677,6 +34402,18 @@ func (f *ExecutorSecretStoreHandleFunc) History() []ExecutorSecretStoreHandleFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockExecutorSecretStore.
 type ExecutorSecretStoreHandleFuncCall struct {
@@ -29776,6 +34513,18 @@ func (f *ExecutorSecretStoreListFunc) History() []ExecutorSecretStoreListFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreListFuncCall is an object that describes an invocation
 // of method List on an instance of MockExecutorSecretStore.
 type ExecutorSecretStoreListFuncCall struct {
@@ -29890,6 +34639,18 @@ func (f *ExecutorSecretStoreUpdateFunc) History() []ExecutorSecretStoreUpdateFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreUpdateFuncCall is an object that describes an
 // invocation of method Update on an instance of MockExecutorSecretStore.
 type ExecutorSecretStoreUpdateFuncCall struct {
@@ -30001,6 +34762,18 @@ func (f *ExecutorSecretStoreWithFunc) History() []ExecutorSecretStoreWithFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreWithFuncCall is an object that describes an invocation
 // of method With on an instance of MockExecutorSecretStore.
 type ExecutorSecretStoreWithFuncCall struct {
@@ -30105,6 +34878,18 @@ func (f *ExecutorSecretStoreWithTransactFunc) History() []ExecutorSecretStoreWit
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorSecretStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorSecretStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of
 // MockExecutorSecretStore.
@@ -30282,6 +35067,90 @@ func NewStrictMockExecutorStore() *MockExecutorStore {
 	}
 }
 
+// NewStrictMockExecutorStoreWithT creates a new mock of the ExecutorStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockExecutorStoreWithT(t testing.TB) *MockExecutorStore {
+	return &MockExecutorStore{
+		CountFunc: &ExecutorStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExecutorStoreListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockExecutorStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteInactiveHeartbeatsFunc: &ExecutorStoreDeleteInactiveHeartbeatsFunc{
+			defaultHook: func(v0 context.Context, v1 time.Duration) (r0 error) {
+				t.Errorf("unexpected call of MockExecutorStore.DeleteInactiveHeartbeats(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByHostnameFunc: &ExecutorStoreGetByHostnameFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 types.Executor, r1 bool, r2 error) {
+				t.Errorf("unexpected call of MockExecutorStore.GetByHostname(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &ExecutorStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int) (r0 types.Executor, r1 bool, r2 error) {
+				t.Errorf("unexpected call of MockExecutorStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &ExecutorStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockExecutorStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &ExecutorStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExecutorStoreListOptions) (r0 []types.Executor, r1 error) {
+				t.Errorf("unexpected call of MockExecutorStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		QueryFunc: &ExecutorStoreQueryFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 *sql.Rows, r1 error) {
+				t.Errorf("unexpected call of MockExecutorStore.Query(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpsertHeartbeatFunc: &ExecutorStoreUpsertHeartbeatFunc{
+			defaultHook: func(v0 context.Context, v1 types.Executor) (r0 error) {
+				t.Errorf("unexpected call of MockExecutorStore.UpsertHeartbeat(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &ExecutorStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.ExecutorStore) {
+				t.Errorf("unexpected call of MockExecutorStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &ExecutorStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.ExecutorStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockExecutorStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockExecutorStore instance with PushHook or PushReturn which was not called.
+func (m *MockExecutorStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockExecutorStore.Count")
+	m.DeleteInactiveHeartbeatsFunc.assertExpectations(t, "MockExecutorStore.DeleteInactiveHeartbeats")
+	m.GetByHostnameFunc.assertExpectations(t, "MockExecutorStore.GetByHostname")
+	m.GetByIDFunc.assertExpectations(t, "MockExecutorStore.GetByID")
+	m.HandleFunc.assertExpectations(t, "MockExecutorStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockExecutorStore.List")
+	m.QueryFunc.assertExpectations(t, "MockExecutorStore.Query")
+	m.UpsertHeartbeatFunc.assertExpectations(t, "MockExecutorStore.UpsertHeartbeat")
+	m.WithFunc.assertExpectations(t, "MockExecutorStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockExecutorStore.WithTransact")
+}
+
 // NewMockExecutorStoreFrom creates a new mock of the MockExecutorStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -30398,6 +35267,18 @@ func (f *ExecutorStoreCountFunc) History() []ExecutorStoreCountFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorStoreCountFuncCall is an object that describes an invocation of
 // method Count on an instance of MockExecutorStore.
 type ExecutorStoreCountFuncCall struct {
@@ -30509,6 +35390,18 @@ func (f *ExecutorStoreDeleteInactiveHeartbeatsFunc) History() []ExecutorStoreDel
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorStoreDeleteInactiveHeartbeatsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorStoreDeleteInactiveHeartbeatsFuncCall is an object that describes
 // an invocation of method DeleteInactiveHeartbeats on an instance of
 // MockExecutorStore.
@@ -30615,6 +35508,18 @@ func (f *ExecutorStoreGetByHostnameFunc) History() []ExecutorStoreGetByHostnameF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorStoreGetByHostnameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorStoreGetByHostnameFuncCall is an object that describes an
 // invocation of method GetByHostname on an instance of MockExecutorStore.
 type ExecutorStoreGetByHostnameFuncCall struct {
@@ -30726,6 +35631,18 @@ func (f *ExecutorStoreGetByIDFunc) History() []ExecutorStoreGetByIDFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorStoreGetByIDFuncCall is an object that describes an invocation of
 // method GetByID on an instance of MockExecutorStore.
 type ExecutorStoreGetByIDFuncCall struct {
@@ -30836,6 +35753,18 @@ func (f *ExecutorStoreHandleFunc) History() []ExecutorStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockExecutorStore.
 type ExecutorStoreHandleFuncCall struct {
@@ -30934,6 +35863,18 @@ func (f *ExecutorStoreListFunc) History() []ExecutorStoreListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorStoreListFuncCall is an object that describes an invocation of
 // method List on an instance of MockExecutorStore.
 type ExecutorStoreListFuncCall struct {
@@ -31041,6 +35982,18 @@ func (f *ExecutorStoreQueryFunc) History() []ExecutorStoreQueryFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorStoreQueryFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorStoreQueryFuncCall is an object that describes an invocation of
 // method Query on an instance of MockExecutorStore.
 type ExecutorStoreQueryFuncCall struct {
@@ -31150,6 +36103,18 @@ func (f *ExecutorStoreUpsertHeartbeatFunc) History() []ExecutorStoreUpsertHeartb
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorStoreUpsertHeartbeatFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorStoreUpsertHeartbeatFuncCall is an object that describes an
 // invocation of method UpsertHeartbeat on an instance of MockExecutorStore.
 type ExecutorStoreUpsertHeartbeatFuncCall struct {
@@ -31254,6 +36219,18 @@ func (f *ExecutorStoreWithFunc) History() []ExecutorStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockExecutorStore.
 type ExecutorStoreWithFuncCall struct {
@@ -31356,6 +36333,18 @@ func (f *ExecutorStoreWithTransactFunc) History() []ExecutorStoreWithTransactFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExecutorStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExecutorStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockExecutorStore.
 type ExecutorStoreWithTransactFuncCall struct {
@@ -31717,6 +36706,189 @@ func NewStrictMockExternalServiceStore() *MockExternalServiceStore {
 	}
 }
 
+// NewStrictMockExternalServiceStoreWithT creates a new mock of the
+// ExternalServiceStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockExternalServiceStoreWithT(t testing.TB) *MockExternalServiceStore {
+	return &MockExternalServiceStore{
+		CancelSyncJobFunc: &ExternalServiceStoreCancelSyncJobFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExternalServicesCancelSyncJobOptions) (r0 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.CancelSyncJob(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CleanupSyncJobsFunc: &ExternalServiceStoreCleanupSyncJobsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExternalServicesCleanupSyncJobsOptions) (r0 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.CleanupSyncJobs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountFunc: &ExternalServiceStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExternalServicesListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountSyncJobsFunc: &ExternalServiceStoreCountSyncJobsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExternalServicesGetSyncJobsOptions) (r0 int64, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.CountSyncJobs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &ExternalServiceStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 func() *conf.Unified, v2 *types.ExternalService) (r0 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.Create(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DeleteFunc: &ExternalServiceStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DistinctKindsFunc: &ExternalServiceStoreDistinctKindsFunc{
+			defaultHook: func(v0 context.Context) (r0 []string, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.DistinctKinds(%v)", v0)
+				return
+			},
+		},
+		DoneFunc: &ExternalServiceStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetByIDFunc: &ExternalServiceStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *types.ExternalService, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetLastSyncErrorFunc: &ExternalServiceStoreGetLastSyncErrorFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 string, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.GetLastSyncError(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetLatestSyncErrorsFunc: &ExternalServiceStoreGetLatestSyncErrorsFunc{
+			defaultHook: func(v0 context.Context) (r0 []*database.SyncError, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.GetLatestSyncErrors(%v)", v0)
+				return
+			},
+		},
+		GetSyncJobByIDFunc: &ExternalServiceStoreGetSyncJobByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *types.ExternalServiceSyncJob, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.GetSyncJobByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetSyncJobsFunc: &ExternalServiceStoreGetSyncJobsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExternalServicesGetSyncJobsOptions) (r0 []*types.ExternalServiceSyncJob, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.GetSyncJobs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &ExternalServiceStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockExternalServiceStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &ExternalServiceStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExternalServicesListOptions) (r0 []*types.ExternalService, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListReposFunc: &ExternalServiceStoreListReposFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExternalServiceReposListOptions) (r0 []*types.ExternalServiceRepo, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.ListRepos(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RepoCountFunc: &ExternalServiceStoreRepoCountFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.RepoCount(%v, %v)", v0, v1)
+				return
+			},
+		},
+		SyncDueFunc: &ExternalServiceStoreSyncDueFunc{
+			defaultHook: func(v0 context.Context, v1 []int64, v2 time.Duration) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.SyncDue(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		TransactFunc: &ExternalServiceStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.ExternalServiceStore, r1 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.Transact(%v)", v0)
+				return
+			},
+		},
+		UpdateFunc: &ExternalServiceStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 []schema.AuthProviders, v2 int64, v3 *database.ExternalServiceUpdate) (r0 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.Update(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		UpdateSyncJobCountersFunc: &ExternalServiceStoreUpdateSyncJobCountersFunc{
+			defaultHook: func(v0 context.Context, v1 *types.ExternalServiceSyncJob) (r0 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.UpdateSyncJobCounters(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpsertFunc: &ExternalServiceStoreUpsertFunc{
+			defaultHook: func(v0 context.Context, v1 ...*types.ExternalService) (r0 error) {
+				t.Errorf("unexpected call of MockExternalServiceStore.Upsert(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &ExternalServiceStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.ExternalServiceStore) {
+				t.Errorf("unexpected call of MockExternalServiceStore.With(%v)", v0)
+				return
+			},
+		},
+		WithEncryptionKeyFunc: &ExternalServiceStoreWithEncryptionKeyFunc{
+			defaultHook: func(v0 encryption.Key) (r0 database.ExternalServiceStore) {
+				t.Errorf("unexpected call of MockExternalServiceStore.WithEncryptionKey(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockExternalServiceStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockExternalServiceStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CancelSyncJobFunc.assertExpectations(t, "MockExternalServiceStore.CancelSyncJob")
+	m.CleanupSyncJobsFunc.assertExpectations(t, "MockExternalServiceStore.CleanupSyncJobs")
+	m.CountFunc.assertExpectations(t, "MockExternalServiceStore.Count")
+	m.CountSyncJobsFunc.assertExpectations(t, "MockExternalServiceStore.CountSyncJobs")
+	m.CreateFunc.assertExpectations(t, "MockExternalServiceStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockExternalServiceStore.Delete")
+	m.DistinctKindsFunc.assertExpectations(t, "MockExternalServiceStore.DistinctKinds")
+	m.DoneFunc.assertExpectations(t, "MockExternalServiceStore.Done")
+	m.GetByIDFunc.assertExpectations(t, "MockExternalServiceStore.GetByID")
+	m.GetLastSyncErrorFunc.assertExpectations(t, "MockExternalServiceStore.GetLastSyncError")
+	m.GetLatestSyncErrorsFunc.assertExpectations(t, "MockExternalServiceStore.GetLatestSyncErrors")
+	m.GetSyncJobByIDFunc.assertExpectations(t, "MockExternalServiceStore.GetSyncJobByID")
+	m.GetSyncJobsFunc.assertExpectations(t, "MockExternalServiceStore.GetSyncJobs")
+	m.HandleFunc.assertExpectations(t, "MockExternalServiceStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockExternalServiceStore.List")
+	m.ListReposFunc.assertExpectations(t, "MockExternalServiceStore.ListRepos")
+	m.RepoCountFunc.assertExpectations(t, "MockExternalServiceStore.RepoCount")
+	m.SyncDueFunc.assertExpectations(t, "MockExternalServiceStore.SyncDue")
+	m.TransactFunc.assertExpectations(t, "MockExternalServiceStore.Transact")
+	m.UpdateFunc.assertExpectations(t, "MockExternalServiceStore.Update")
+	m.UpdateSyncJobCountersFunc.assertExpectations(t, "MockExternalServiceStore.UpdateSyncJobCounters")
+	m.UpsertFunc.assertExpectations(t, "MockExternalServiceStore.Upsert")
+	m.WithFunc.assertExpectations(t, "MockExternalServiceStore.With")
+	m.WithEncryptionKeyFunc.assertExpectations(t, "MockExternalServiceStore.WithEncryptionKey")
+}
+
 // NewMockExternalServiceStoreFrom creates a new mock of the
 // MockExternalServiceStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -31878,6 +37050,18 @@ func (f *ExternalServiceStoreCancelSyncJobFunc) History() []ExternalServiceStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreCancelSyncJobFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreCancelSyncJobFuncCall is an object that describes an
 // invocation of method CancelSyncJob on an instance of
 // MockExternalServiceStore.
@@ -31986,6 +37170,18 @@ func (f *ExternalServiceStoreCleanupSyncJobsFunc) History() []ExternalServiceSto
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreCleanupSyncJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreCleanupSyncJobsFuncCall is an object that describes
 // an invocation of method CleanupSyncJobs on an instance of
 // MockExternalServiceStore.
@@ -32092,6 +37288,18 @@ func (f *ExternalServiceStoreCountFunc) History() []ExternalServiceStoreCountFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreCountFuncCall is an object that describes an
 // invocation of method Count on an instance of MockExternalServiceStore.
 type ExternalServiceStoreCountFuncCall struct {
@@ -32202,6 +37410,18 @@ func (f *ExternalServiceStoreCountSyncJobsFunc) History() []ExternalServiceStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreCountSyncJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreCountSyncJobsFuncCall is an object that describes an
 // invocation of method CountSyncJobs on an instance of
 // MockExternalServiceStore.
@@ -32311,6 +37531,18 @@ func (f *ExternalServiceStoreCreateFunc) History() []ExternalServiceStoreCreateF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreCreateFuncCall is an object that describes an
 // invocation of method Create on an instance of MockExternalServiceStore.
 type ExternalServiceStoreCreateFuncCall struct {
@@ -32419,6 +37651,18 @@ func (f *ExternalServiceStoreDeleteFunc) History() []ExternalServiceStoreDeleteF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreDeleteFuncCall is an object that describes an
 // invocation of method Delete on an instance of MockExternalServiceStore.
 type ExternalServiceStoreDeleteFuncCall struct {
@@ -32526,6 +37770,18 @@ func (f *ExternalServiceStoreDistinctKindsFunc) History() []ExternalServiceStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreDistinctKindsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreDistinctKindsFuncCall is an object that describes an
 // invocation of method DistinctKinds on an instance of
 // MockExternalServiceStore.
@@ -32632,6 +37888,18 @@ func (f *ExternalServiceStoreDoneFunc) History() []ExternalServiceStoreDoneFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreDoneFuncCall is an object that describes an
 // invocation of method Done on an instance of MockExternalServiceStore.
 type ExternalServiceStoreDoneFuncCall struct {
@@ -32734,6 +38002,18 @@ func (f *ExternalServiceStoreGetByIDFunc) History() []ExternalServiceStoreGetByI
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreGetByIDFuncCall is an object that describes an
 // invocation of method GetByID on an instance of MockExternalServiceStore.
 type ExternalServiceStoreGetByIDFuncCall struct {
@@ -32845,6 +38125,18 @@ func (f *ExternalServiceStoreGetLastSyncErrorFunc) History() []ExternalServiceSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreGetLastSyncErrorFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreGetLastSyncErrorFuncCall is an object that describes
 // an invocation of method GetLastSyncError on an instance of
 // MockExternalServiceStore.
@@ -32957,6 +38249,18 @@ func (f *ExternalServiceStoreGetLatestSyncErrorsFunc) History() []ExternalServic
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreGetLatestSyncErrorsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreGetLatestSyncErrorsFuncCall is an object that
 // describes an invocation of method GetLatestSyncErrors on an instance of
 // MockExternalServiceStore.
@@ -33065,6 +38369,18 @@ func (f *ExternalServiceStoreGetSyncJobByIDFunc) History() []ExternalServiceStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreGetSyncJobByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreGetSyncJobByIDFuncCall is an object that describes an
 // invocation of method GetSyncJobByID on an instance of
 // MockExternalServiceStore.
@@ -33176,6 +38492,18 @@ func (f *ExternalServiceStoreGetSyncJobsFunc) History() []ExternalServiceStoreGe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreGetSyncJobsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreGetSyncJobsFuncCall is an object that describes an
 // invocation of method GetSyncJobs on an instance of
 // MockExternalServiceStore.
@@ -33285,6 +38613,18 @@ func (f *ExternalServiceStoreHandleFunc) History() []ExternalServiceStoreHandleF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockExternalServiceStore.
 type ExternalServiceStoreHandleFuncCall struct {
@@ -33384,6 +38724,18 @@ func (f *ExternalServiceStoreListFunc) History() []ExternalServiceStoreListFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreListFuncCall is an object that describes an
 // invocation of method List on an instance of MockExternalServiceStore.
 type ExternalServiceStoreListFuncCall struct {
@@ -33493,6 +38845,18 @@ func (f *ExternalServiceStoreListReposFunc) History() []ExternalServiceStoreList
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreListReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreListReposFuncCall is an object that describes an
 // invocation of method ListRepos on an instance of
 // MockExternalServiceStore.
@@ -33603,6 +38967,18 @@ func (f *ExternalServiceStoreRepoCountFunc) History() []ExternalServiceStoreRepo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreRepoCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreRepoCountFuncCall is an object that describes an
 // invocation of method RepoCount on an instance of
 // MockExternalServiceStore.
@@ -33712,6 +39088,18 @@ func (f *ExternalServiceStoreSyncDueFunc) History() []ExternalServiceStoreSyncDu
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreSyncDueFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreSyncDueFuncCall is an object that describes an
 // invocation of method SyncDue on an instance of MockExternalServiceStore.
 type ExternalServiceStoreSyncDueFuncCall struct {
@@ -33823,6 +39211,18 @@ func (f *ExternalServiceStoreTransactFunc) History() []ExternalServiceStoreTrans
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreTransactFuncCall is an object that describes an
 // invocation of method Transact on an instance of MockExternalServiceStore.
 type ExternalServiceStoreTransactFuncCall struct {
@@ -33928,6 +39328,18 @@ func (f *ExternalServiceStoreUpdateFunc) History() []ExternalServiceStoreUpdateF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreUpdateFuncCall is an object that describes an
 // invocation of method Update on an instance of MockExternalServiceStore.
 type ExternalServiceStoreUpdateFuncCall struct {
@@ -34042,6 +39454,18 @@ func (f *ExternalServiceStoreUpdateSyncJobCountersFunc) History() []ExternalServ
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreUpdateSyncJobCountersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreUpdateSyncJobCountersFuncCall is an object that
 // describes an invocation of method UpdateSyncJobCounters on an instance of
 // MockExternalServiceStore.
@@ -34148,6 +39572,18 @@ func (f *ExternalServiceStoreUpsertFunc) History() []ExternalServiceStoreUpsertF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreUpsertFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreUpsertFuncCall is an object that describes an
 // invocation of method Upsert on an instance of MockExternalServiceStore.
 type ExternalServiceStoreUpsertFuncCall struct {
@@ -34260,6 +39696,18 @@ func (f *ExternalServiceStoreWithFunc) History() []ExternalServiceStoreWithFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreWithFuncCall is an object that describes an
 // invocation of method With on an instance of MockExternalServiceStore.
 type ExternalServiceStoreWithFuncCall struct {
@@ -34365,6 +39813,18 @@ func (f *ExternalServiceStoreWithEncryptionKeyFunc) History() []ExternalServiceS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ExternalServiceStoreWithEncryptionKeyFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ExternalServiceStoreWithEncryptionKeyFuncCall is an object that describes
 // an invocation of method WithEncryptionKey on an instance of
 // MockExternalServiceStore.
@@ -34684,6 +40144,168 @@ func NewStrictMockFeatureFlagStore() *MockFeatureFlagStore {
 	}
 }
 
+// NewStrictMockFeatureFlagStoreWithT creates a new mock of the
+// FeatureFlagStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockFeatureFlagStoreWithT(t testing.TB) *MockFeatureFlagStore {
+	return &MockFeatureFlagStore{
+		CreateBoolFunc: &FeatureFlagStoreCreateBoolFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 bool) (r0 *featureflag.FeatureFlag, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.CreateBool(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreateFeatureFlagFunc: &FeatureFlagStoreCreateFeatureFlagFunc{
+			defaultHook: func(v0 context.Context, v1 *featureflag.FeatureFlag) (r0 *featureflag.FeatureFlag, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.CreateFeatureFlag(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateOverrideFunc: &FeatureFlagStoreCreateOverrideFunc{
+			defaultHook: func(v0 context.Context, v1 *featureflag.Override) (r0 *featureflag.Override, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.CreateOverride(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateRolloutFunc: &FeatureFlagStoreCreateRolloutFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 int32) (r0 *featureflag.FeatureFlag, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.CreateRollout(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DeleteFeatureFlagFunc: &FeatureFlagStoreDeleteFeatureFlagFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.DeleteFeatureFlag(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteOverrideFunc: &FeatureFlagStoreDeleteOverrideFunc{
+			defaultHook: func(v0 context.Context, v1 *int32, v2 *int32, v3 string) (r0 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.DeleteOverride(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		GetAnonymousUserFlagsFunc: &FeatureFlagStoreGetAnonymousUserFlagsFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 map[string]bool, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.GetAnonymousUserFlags(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetFeatureFlagFunc: &FeatureFlagStoreGetFeatureFlagFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *featureflag.FeatureFlag, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.GetFeatureFlag(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetFeatureFlagsFunc: &FeatureFlagStoreGetFeatureFlagsFunc{
+			defaultHook: func(v0 context.Context) (r0 []*featureflag.FeatureFlag, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.GetFeatureFlags(%v)", v0)
+				return
+			},
+		},
+		GetGlobalFeatureFlagsFunc: &FeatureFlagStoreGetGlobalFeatureFlagsFunc{
+			defaultHook: func(v0 context.Context) (r0 map[string]bool, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.GetGlobalFeatureFlags(%v)", v0)
+				return
+			},
+		},
+		GetOrgFeatureFlagFunc: &FeatureFlagStoreGetOrgFeatureFlagFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.GetOrgFeatureFlag(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		GetOrgOverrideForFlagFunc: &FeatureFlagStoreGetOrgOverrideForFlagFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 *featureflag.Override, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.GetOrgOverrideForFlag(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		GetOrgOverridesForUserFunc: &FeatureFlagStoreGetOrgOverridesForUserFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []*featureflag.Override, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.GetOrgOverridesForUser(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetOverridesForFlagFunc: &FeatureFlagStoreGetOverridesForFlagFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 []*featureflag.Override, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.GetOverridesForFlag(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetUserFlagsFunc: &FeatureFlagStoreGetUserFlagsFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 map[string]bool, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.GetUserFlags(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetUserOverridesFunc: &FeatureFlagStoreGetUserOverridesFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []*featureflag.Override, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.GetUserOverrides(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &FeatureFlagStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.Handle()")
+				return
+			},
+		},
+		UpdateFeatureFlagFunc: &FeatureFlagStoreUpdateFeatureFlagFunc{
+			defaultHook: func(v0 context.Context, v1 *featureflag.FeatureFlag) (r0 *featureflag.FeatureFlag, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.UpdateFeatureFlag(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateOverrideFunc: &FeatureFlagStoreUpdateOverrideFunc{
+			defaultHook: func(v0 context.Context, v1 *int32, v2 *int32, v3 string, v4 bool) (r0 *featureflag.Override, r1 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.UpdateOverride(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		WithFunc: &FeatureFlagStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.FeatureFlagStore) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &FeatureFlagStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.FeatureFlagStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockFeatureFlagStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockFeatureFlagStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockFeatureFlagStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CreateBoolFunc.assertExpectations(t, "MockFeatureFlagStore.CreateBool")
+	m.CreateFeatureFlagFunc.assertExpectations(t, "MockFeatureFlagStore.CreateFeatureFlag")
+	m.CreateOverrideFunc.assertExpectations(t, "MockFeatureFlagStore.CreateOverride")
+	m.CreateRolloutFunc.assertExpectations(t, "MockFeatureFlagStore.CreateRollout")
+	m.DeleteFeatureFlagFunc.assertExpectations(t, "MockFeatureFlagStore.DeleteFeatureFlag")
+	m.DeleteOverrideFunc.assertExpectations(t, "MockFeatureFlagStore.DeleteOverride")
+	m.GetAnonymousUserFlagsFunc.assertExpectations(t, "MockFeatureFlagStore.GetAnonymousUserFlags")
+	m.GetFeatureFlagFunc.assertExpectations(t, "MockFeatureFlagStore.GetFeatureFlag")
+	m.GetFeatureFlagsFunc.assertExpectations(t, "MockFeatureFlagStore.GetFeatureFlags")
+	m.GetGlobalFeatureFlagsFunc.assertExpectations(t, "MockFeatureFlagStore.GetGlobalFeatureFlags")
+	m.GetOrgFeatureFlagFunc.assertExpectations(t, "MockFeatureFlagStore.GetOrgFeatureFlag")
+	m.GetOrgOverrideForFlagFunc.assertExpectations(t, "MockFeatureFlagStore.GetOrgOverrideForFlag")
+	m.GetOrgOverridesForUserFunc.assertExpectations(t, "MockFeatureFlagStore.GetOrgOverridesForUser")
+	m.GetOverridesForFlagFunc.assertExpectations(t, "MockFeatureFlagStore.GetOverridesForFlag")
+	m.GetUserFlagsFunc.assertExpectations(t, "MockFeatureFlagStore.GetUserFlags")
+	m.GetUserOverridesFunc.assertExpectations(t, "MockFeatureFlagStore.GetUserOverrides")
+	m.HandleFunc.assertExpectations(t, "MockFeatureFlagStore.Handle")
+	m.UpdateFeatureFlagFunc.assertExpectations(t, "MockFeatureFlagStore.UpdateFeatureFlag")
+	m.UpdateOverrideFunc.assertExpectations(t, "MockFeatureFlagStore.UpdateOverride")
+	m.WithFunc.assertExpectations(t, "MockFeatureFlagStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockFeatureFlagStore.WithTransact")
+}
+
 // NewMockFeatureFlagStoreFrom creates a new mock of the
 // MockFeatureFlagStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -34834,6 +40456,18 @@ func (f *FeatureFlagStoreCreateBoolFunc) History() []FeatureFlagStoreCreateBoolF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreCreateBoolFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreCreateBoolFuncCall is an object that describes an
 // invocation of method CreateBool on an instance of MockFeatureFlagStore.
 type FeatureFlagStoreCreateBoolFuncCall struct {
@@ -34947,6 +40581,18 @@ func (f *FeatureFlagStoreCreateFeatureFlagFunc) History() []FeatureFlagStoreCrea
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreCreateFeatureFlagFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreCreateFeatureFlagFuncCall is an object that describes an
 // invocation of method CreateFeatureFlag on an instance of
 // MockFeatureFlagStore.
@@ -35057,6 +40703,18 @@ func (f *FeatureFlagStoreCreateOverrideFunc) History() []FeatureFlagStoreCreateO
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreCreateOverrideFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreCreateOverrideFuncCall is an object that describes an
 // invocation of method CreateOverride on an instance of
 // MockFeatureFlagStore.
@@ -35167,6 +40825,18 @@ func (f *FeatureFlagStoreCreateRolloutFunc) History() []FeatureFlagStoreCreateRo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreCreateRolloutFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreCreateRolloutFuncCall is an object that describes an
 // invocation of method CreateRollout on an instance of
 // MockFeatureFlagStore.
@@ -35281,6 +40951,18 @@ func (f *FeatureFlagStoreDeleteFeatureFlagFunc) History() []FeatureFlagStoreDele
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreDeleteFeatureFlagFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreDeleteFeatureFlagFuncCall is an object that describes an
 // invocation of method DeleteFeatureFlag on an instance of
 // MockFeatureFlagStore.
@@ -35388,6 +41070,18 @@ func (f *FeatureFlagStoreDeleteOverrideFunc) History() []FeatureFlagStoreDeleteO
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreDeleteOverrideFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreDeleteOverrideFuncCall is an object that describes an
 // invocation of method DeleteOverride on an instance of
 // MockFeatureFlagStore.
@@ -35503,6 +41197,18 @@ func (f *FeatureFlagStoreGetAnonymousUserFlagsFunc) History() []FeatureFlagStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreGetAnonymousUserFlagsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreGetAnonymousUserFlagsFuncCall is an object that describes
 // an invocation of method GetAnonymousUserFlags on an instance of
 // MockFeatureFlagStore.
@@ -35613,6 +41319,18 @@ func (f *FeatureFlagStoreGetFeatureFlagFunc) History() []FeatureFlagStoreGetFeat
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreGetFeatureFlagFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreGetFeatureFlagFuncCall is an object that describes an
 // invocation of method GetFeatureFlag on an instance of
 // MockFeatureFlagStore.
@@ -35724,6 +41442,18 @@ func (f *FeatureFlagStoreGetFeatureFlagsFunc) History() []FeatureFlagStoreGetFea
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreGetFeatureFlagsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreGetFeatureFlagsFuncCall is an object that describes an
 // invocation of method GetFeatureFlags on an instance of
 // MockFeatureFlagStore.
@@ -35833,6 +41563,18 @@ func (f *FeatureFlagStoreGetGlobalFeatureFlagsFunc) History() []FeatureFlagStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreGetGlobalFeatureFlagsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreGetGlobalFeatureFlagsFuncCall is an object that describes
 // an invocation of method GetGlobalFeatureFlags on an instance of
 // MockFeatureFlagStore.
@@ -35941,6 +41683,18 @@ func (f *FeatureFlagStoreGetOrgFeatureFlagFunc) History() []FeatureFlagStoreGetO
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreGetOrgFeatureFlagFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreGetOrgFeatureFlagFuncCall is an object that describes an
 // invocation of method GetOrgFeatureFlag on an instance of
 // MockFeatureFlagStore.
@@ -36056,6 +41810,18 @@ func (f *FeatureFlagStoreGetOrgOverrideForFlagFunc) History() []FeatureFlagStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreGetOrgOverrideForFlagFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreGetOrgOverrideForFlagFuncCall is an object that describes
 // an invocation of method GetOrgOverrideForFlag on an instance of
 // MockFeatureFlagStore.
@@ -36171,6 +41937,18 @@ func (f *FeatureFlagStoreGetOrgOverridesForUserFunc) History() []FeatureFlagStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreGetOrgOverridesForUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreGetOrgOverridesForUserFuncCall is an object that
 // describes an invocation of method GetOrgOverridesForUser on an instance
 // of MockFeatureFlagStore.
@@ -36282,6 +42060,18 @@ func (f *FeatureFlagStoreGetOverridesForFlagFunc) History() []FeatureFlagStoreGe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreGetOverridesForFlagFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreGetOverridesForFlagFuncCall is an object that describes
 // an invocation of method GetOverridesForFlag on an instance of
 // MockFeatureFlagStore.
@@ -36392,6 +42182,18 @@ func (f *FeatureFlagStoreGetUserFlagsFunc) History() []FeatureFlagStoreGetUserFl
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreGetUserFlagsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreGetUserFlagsFuncCall is an object that describes an
 // invocation of method GetUserFlags on an instance of MockFeatureFlagStore.
 type FeatureFlagStoreGetUserFlagsFuncCall struct {
@@ -36502,6 +42304,18 @@ func (f *FeatureFlagStoreGetUserOverridesFunc) History() []FeatureFlagStoreGetUs
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreGetUserOverridesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreGetUserOverridesFuncCall is an object that describes an
 // invocation of method GetUserOverrides on an instance of
 // MockFeatureFlagStore.
@@ -36611,6 +42425,18 @@ func (f *FeatureFlagStoreHandleFunc) History() []FeatureFlagStoreHandleFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockFeatureFlagStore.
 type FeatureFlagStoreHandleFuncCall struct {
@@ -36712,6 +42538,18 @@ func (f *FeatureFlagStoreUpdateFeatureFlagFunc) History() []FeatureFlagStoreUpda
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreUpdateFeatureFlagFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreUpdateFeatureFlagFuncCall is an object that describes an
 // invocation of method UpdateFeatureFlag on an instance of
 // MockFeatureFlagStore.
@@ -36822,6 +42660,18 @@ func (f *FeatureFlagStoreUpdateOverrideFunc) History() []FeatureFlagStoreUpdateO
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreUpdateOverrideFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreUpdateOverrideFuncCall is an object that describes an
 // invocation of method UpdateOverride on an instance of
 // MockFeatureFlagStore.
@@ -36940,6 +42790,18 @@ func (f *FeatureFlagStoreWithFunc) History() []FeatureFlagStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockFeatureFlagStore.
 type FeatureFlagStoreWithFuncCall struct {
@@ -37043,6 +42905,18 @@ func (f *FeatureFlagStoreWithTransactFunc) History() []FeatureFlagStoreWithTrans
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *FeatureFlagStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // FeatureFlagStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockFeatureFlagStore.
 type FeatureFlagStoreWithTransactFuncCall struct {
@@ -37341,6 +43215,154 @@ func NewStrictMockGitserverRepoStore() *MockGitserverRepoStore {
 	}
 }
 
+// NewStrictMockGitserverRepoStoreWithT creates a new mock of the
+// GitserverRepoStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockGitserverRepoStoreWithT(t testing.TB) *MockGitserverRepoStore {
+	return &MockGitserverRepoStore{
+		GetByIDFunc: &GitserverRepoStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 *types.GitserverRepo, r1 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByNameFunc: &GitserverRepoStoreGetByNameFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName) (r0 *types.GitserverRepo, r1 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.GetByName(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByNamesFunc: &GitserverRepoStoreGetByNamesFunc{
+			defaultHook: func(v0 context.Context, v1 ...api.RepoName) (r0 map[api.RepoName]*types.GitserverRepo, r1 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.GetByNames(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetGitserverGitDirSizeFunc: &GitserverRepoStoreGetGitserverGitDirSizeFunc{
+			defaultHook: func(v0 context.Context) (r0 int64, r1 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.GetGitserverGitDirSize(%v)", v0)
+				return
+			},
+		},
+		GetLastSyncOutputFunc: &GitserverRepoStoreGetLastSyncOutputFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName) (r0 string, r1 bool, r2 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.GetLastSyncOutput(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &GitserverRepoStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.Handle()")
+				return
+			},
+		},
+		IterateRepoGitserverStatusFunc: &GitserverRepoStoreIterateRepoGitserverStatusFunc{
+			defaultHook: func(v0 context.Context, v1 database.IterateRepoGitserverStatusOptions) (r0 []types.RepoGitserverStatus, r1 int, r2 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.IterateRepoGitserverStatus(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListPurgeableReposFunc: &GitserverRepoStoreListPurgeableReposFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListPurgableReposOptions) (r0 []api.RepoName, r1 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.ListPurgeableRepos(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListReposWithLastErrorFunc: &GitserverRepoStoreListReposWithLastErrorFunc{
+			defaultHook: func(v0 context.Context) (r0 []api.RepoName, r1 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.ListReposWithLastError(%v)", v0)
+				return
+			},
+		},
+		LogCorruptionFunc: &GitserverRepoStoreLogCorruptionFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName, v2 string, v3 string) (r0 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.LogCorruption(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		SetCloneStatusFunc: &GitserverRepoStoreSetCloneStatusFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName, v2 types.CloneStatus, v3 string) (r0 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.SetCloneStatus(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		SetLastErrorFunc: &GitserverRepoStoreSetLastErrorFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName, v2 string, v3 string) (r0 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.SetLastError(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		SetLastFetchedFunc: &GitserverRepoStoreSetLastFetchedFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName, v2 database.GitserverFetchData) (r0 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.SetLastFetched(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetLastOutputFunc: &GitserverRepoStoreSetLastOutputFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName, v2 string) (r0 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.SetLastOutput(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetRepoSizeFunc: &GitserverRepoStoreSetRepoSizeFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName, v2 int64, v3 string) (r0 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.SetRepoSize(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		TotalErroredCloudDefaultReposFunc: &GitserverRepoStoreTotalErroredCloudDefaultReposFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.TotalErroredCloudDefaultRepos(%v)", v0)
+				return
+			},
+		},
+		UpdateFunc: &GitserverRepoStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 ...*types.GitserverRepo) (r0 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.Update(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateRepoSizesFunc: &GitserverRepoStoreUpdateRepoSizesFunc{
+			defaultHook: func(v0 context.Context, v1 log.Logger, v2 string, v3 map[api.RepoName]int64) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.UpdateRepoSizes(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		WithFunc: &GitserverRepoStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.GitserverRepoStore) {
+				t.Errorf("unexpected call of MockGitserverRepoStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockGitserverRepoStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockGitserverRepoStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.GetByIDFunc.assertExpectations(t, "MockGitserverRepoStore.GetByID")
+	m.GetByNameFunc.assertExpectations(t, "MockGitserverRepoStore.GetByName")
+	m.GetByNamesFunc.assertExpectations(t, "MockGitserverRepoStore.GetByNames")
+	m.GetGitserverGitDirSizeFunc.assertExpectations(t, "MockGitserverRepoStore.GetGitserverGitDirSize")
+	m.GetLastSyncOutputFunc.assertExpectations(t, "MockGitserverRepoStore.GetLastSyncOutput")
+	m.HandleFunc.assertExpectations(t, "MockGitserverRepoStore.Handle")
+	m.IterateRepoGitserverStatusFunc.assertExpectations(t, "MockGitserverRepoStore.IterateRepoGitserverStatus")
+	m.ListPurgeableReposFunc.assertExpectations(t, "MockGitserverRepoStore.ListPurgeableRepos")
+	m.ListReposWithLastErrorFunc.assertExpectations(t, "MockGitserverRepoStore.ListReposWithLastError")
+	m.LogCorruptionFunc.assertExpectations(t, "MockGitserverRepoStore.LogCorruption")
+	m.SetCloneStatusFunc.assertExpectations(t, "MockGitserverRepoStore.SetCloneStatus")
+	m.SetLastErrorFunc.assertExpectations(t, "MockGitserverRepoStore.SetLastError")
+	m.SetLastFetchedFunc.assertExpectations(t, "MockGitserverRepoStore.SetLastFetched")
+	m.SetLastOutputFunc.assertExpectations(t, "MockGitserverRepoStore.SetLastOutput")
+	m.SetRepoSizeFunc.assertExpectations(t, "MockGitserverRepoStore.SetRepoSize")
+	m.TotalErroredCloudDefaultReposFunc.assertExpectations(t, "MockGitserverRepoStore.TotalErroredCloudDefaultRepos")
+	m.UpdateFunc.assertExpectations(t, "MockGitserverRepoStore.Update")
+	m.UpdateRepoSizesFunc.assertExpectations(t, "MockGitserverRepoStore.UpdateRepoSizes")
+	m.WithFunc.assertExpectations(t, "MockGitserverRepoStore.With")
+}
+
 // NewMockGitserverRepoStoreFrom creates a new mock of the
 // MockGitserverRepoStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -37485,6 +43507,18 @@ func (f *GitserverRepoStoreGetByIDFunc) History() []GitserverRepoStoreGetByIDFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreGetByIDFuncCall is an object that describes an
 // invocation of method GetByID on an instance of MockGitserverRepoStore.
 type GitserverRepoStoreGetByIDFuncCall struct {
@@ -37593,6 +43627,18 @@ func (f *GitserverRepoStoreGetByNameFunc) History() []GitserverRepoStoreGetByNam
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreGetByNameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreGetByNameFuncCall is an object that describes an
 // invocation of method GetByName on an instance of MockGitserverRepoStore.
 type GitserverRepoStoreGetByNameFuncCall struct {
@@ -37702,6 +43748,18 @@ func (f *GitserverRepoStoreGetByNamesFunc) History() []GitserverRepoStoreGetByNa
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreGetByNamesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreGetByNamesFuncCall is an object that describes an
 // invocation of method GetByNames on an instance of MockGitserverRepoStore.
 type GitserverRepoStoreGetByNamesFuncCall struct {
@@ -37820,6 +43878,18 @@ func (f *GitserverRepoStoreGetGitserverGitDirSizeFunc) History() []GitserverRepo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreGetGitserverGitDirSizeFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreGetGitserverGitDirSizeFuncCall is an object that
 // describes an invocation of method GetGitserverGitDirSize on an instance
 // of MockGitserverRepoStore.
@@ -37928,6 +43998,18 @@ func (f *GitserverRepoStoreGetLastSyncOutputFunc) History() []GitserverRepoStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreGetLastSyncOutputFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreGetLastSyncOutputFuncCall is an object that describes
 // an invocation of method GetLastSyncOutput on an instance of
 // MockGitserverRepoStore.
@@ -38040,6 +44122,18 @@ func (f *GitserverRepoStoreHandleFunc) History() []GitserverRepoStoreHandleFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockGitserverRepoStore.
 type GitserverRepoStoreHandleFuncCall struct {
@@ -38142,6 +44236,18 @@ func (f *GitserverRepoStoreIterateRepoGitserverStatusFunc) History() []Gitserver
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreIterateRepoGitserverStatusFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreIterateRepoGitserverStatusFuncCall is an object that
 // describes an invocation of method IterateRepoGitserverStatus on an
 // instance of MockGitserverRepoStore.
@@ -38257,6 +44363,18 @@ func (f *GitserverRepoStoreListPurgeableReposFunc) History() []GitserverRepoStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreListPurgeableReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreListPurgeableReposFuncCall is an object that describes
 // an invocation of method ListPurgeableRepos on an instance of
 // MockGitserverRepoStore.
@@ -38369,6 +44487,18 @@ func (f *GitserverRepoStoreListReposWithLastErrorFunc) History() []GitserverRepo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreListReposWithLastErrorFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreListReposWithLastErrorFuncCall is an object that
 // describes an invocation of method ListReposWithLastError on an instance
 // of MockGitserverRepoStore.
@@ -38477,6 +44607,18 @@ func (f *GitserverRepoStoreLogCorruptionFunc) History() []GitserverRepoStoreLogC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreLogCorruptionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreLogCorruptionFuncCall is an object that describes an
 // invocation of method LogCorruption on an instance of
 // MockGitserverRepoStore.
@@ -38591,6 +44733,18 @@ func (f *GitserverRepoStoreSetCloneStatusFunc) History() []GitserverRepoStoreSet
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreSetCloneStatusFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreSetCloneStatusFuncCall is an object that describes an
 // invocation of method SetCloneStatus on an instance of
 // MockGitserverRepoStore.
@@ -38704,6 +44858,18 @@ func (f *GitserverRepoStoreSetLastErrorFunc) History() []GitserverRepoStoreSetLa
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreSetLastErrorFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreSetLastErrorFuncCall is an object that describes an
 // invocation of method SetLastError on an instance of
 // MockGitserverRepoStore.
@@ -38818,6 +44984,18 @@ func (f *GitserverRepoStoreSetLastFetchedFunc) History() []GitserverRepoStoreSet
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreSetLastFetchedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreSetLastFetchedFuncCall is an object that describes an
 // invocation of method SetLastFetched on an instance of
 // MockGitserverRepoStore.
@@ -38929,6 +45107,18 @@ func (f *GitserverRepoStoreSetLastOutputFunc) History() []GitserverRepoStoreSetL
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreSetLastOutputFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreSetLastOutputFuncCall is an object that describes an
 // invocation of method SetLastOutput on an instance of
 // MockGitserverRepoStore.
@@ -39039,6 +45229,18 @@ func (f *GitserverRepoStoreSetRepoSizeFunc) History() []GitserverRepoStoreSetRep
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreSetRepoSizeFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreSetRepoSizeFuncCall is an object that describes an
 // invocation of method SetRepoSize on an instance of
 // MockGitserverRepoStore.
@@ -39154,6 +45356,18 @@ func (f *GitserverRepoStoreTotalErroredCloudDefaultReposFunc) History() []Gitser
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreTotalErroredCloudDefaultReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreTotalErroredCloudDefaultReposFuncCall is an object that
 // describes an invocation of method TotalErroredCloudDefaultRepos on an
 // instance of MockGitserverRepoStore.
@@ -39260,6 +45474,18 @@ func (f *GitserverRepoStoreUpdateFunc) History() []GitserverRepoStoreUpdateFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreUpdateFuncCall is an object that describes an
 // invocation of method Update on an instance of MockGitserverRepoStore.
 type GitserverRepoStoreUpdateFuncCall struct {
@@ -39374,6 +45600,18 @@ func (f *GitserverRepoStoreUpdateRepoSizesFunc) History() []GitserverRepoStoreUp
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreUpdateRepoSizesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreUpdateRepoSizesFuncCall is an object that describes an
 // invocation of method UpdateRepoSizes on an instance of
 // MockGitserverRepoStore.
@@ -39489,6 +45727,18 @@ func (f *GitserverRepoStoreWithFunc) History() []GitserverRepoStoreWithFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GitserverRepoStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GitserverRepoStoreWithFuncCall is an object that describes an invocation
 // of method With on an instance of MockGitserverRepoStore.
 type GitserverRepoStoreWithFuncCall struct {
@@ -39573,6 +45823,42 @@ func NewStrictMockGlobalStateStore() *MockGlobalStateStore {
 	}
 }
 
+// NewStrictMockGlobalStateStoreWithT creates a new mock of the
+// GlobalStateStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockGlobalStateStoreWithT(t testing.TB) *MockGlobalStateStore {
+	return &MockGlobalStateStore{
+		EnsureInitializedFunc: &GlobalStateStoreEnsureInitializedFunc{
+			defaultHook: func(v0 context.Context) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockGlobalStateStore.EnsureInitialized(%v)", v0)
+				return
+			},
+		},
+		GetFunc: &GlobalStateStoreGetFunc{
+			defaultHook: func(v0 context.Context) (r0 database.GlobalState, r1 error) {
+				t.Errorf("unexpected call of MockGlobalStateStore.Get(%v)", v0)
+				return
+			},
+		},
+		SiteInitializedFunc: &GlobalStateStoreSiteInitializedFunc{
+			defaultHook: func(v0 context.Context) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockGlobalStateStore.SiteInitialized(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockGlobalStateStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockGlobalStateStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.EnsureInitializedFunc.assertExpectations(t, "MockGlobalStateStore.EnsureInitialized")
+	m.GetFunc.assertExpectations(t, "MockGlobalStateStore.Get")
+	m.SiteInitializedFunc.assertExpectations(t, "MockGlobalStateStore.SiteInitialized")
+}
+
 // NewMockGlobalStateStoreFrom creates a new mock of the
 // MockGlobalStateStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -39671,6 +45957,18 @@ func (f *GlobalStateStoreEnsureInitializedFunc) History() []GlobalStateStoreEnsu
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GlobalStateStoreEnsureInitializedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GlobalStateStoreEnsureInitializedFuncCall is an object that describes an
 // invocation of method EnsureInitialized on an instance of
 // MockGlobalStateStore.
@@ -39777,6 +46075,18 @@ func (f *GlobalStateStoreGetFunc) History() []GlobalStateStoreGetFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GlobalStateStoreGetFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GlobalStateStoreGetFuncCall is an object that describes an invocation of
 // method Get on an instance of MockGlobalStateStore.
 type GlobalStateStoreGetFuncCall struct {
@@ -39884,6 +46194,18 @@ func (f *GlobalStateStoreSiteInitializedFunc) History() []GlobalStateStoreSiteIn
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *GlobalStateStoreSiteInitializedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // GlobalStateStoreSiteInitializedFuncCall is an object that describes an
 // invocation of method SiteInitialized on an instance of
 // MockGlobalStateStore.
@@ -39997,6 +46319,56 @@ func NewStrictMockNamespaceStore() *MockNamespaceStore {
 	}
 }
 
+// NewStrictMockNamespaceStoreWithT creates a new mock of the NamespaceStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockNamespaceStoreWithT(t testing.TB) *MockNamespaceStore {
+	return &MockNamespaceStore{
+		GetByIDFunc: &NamespaceStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int32) (r0 *database.Namespace, r1 error) {
+				t.Errorf("unexpected call of MockNamespaceStore.GetByID(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		GetByNameFunc: &NamespaceStoreGetByNameFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *database.Namespace, r1 error) {
+				t.Errorf("unexpected call of MockNamespaceStore.GetByName(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &NamespaceStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockNamespaceStore.Handle()")
+				return
+			},
+		},
+		WithFunc: &NamespaceStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.NamespaceStore) {
+				t.Errorf("unexpected call of MockNamespaceStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &NamespaceStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.NamespaceStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockNamespaceStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockNamespaceStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockNamespaceStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.GetByIDFunc.assertExpectations(t, "MockNamespaceStore.GetByID")
+	m.GetByNameFunc.assertExpectations(t, "MockNamespaceStore.GetByName")
+	m.HandleFunc.assertExpectations(t, "MockNamespaceStore.Handle")
+	m.WithFunc.assertExpectations(t, "MockNamespaceStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockNamespaceStore.WithTransact")
+}
+
 // NewMockNamespaceStoreFrom creates a new mock of the MockNamespaceStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -40099,6 +46471,18 @@ func (f *NamespaceStoreGetByIDFunc) History() []NamespaceStoreGetByIDFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *NamespaceStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // NamespaceStoreGetByIDFuncCall is an object that describes an invocation
 // of method GetByID on an instance of MockNamespaceStore.
 type NamespaceStoreGetByIDFuncCall struct {
@@ -40210,6 +46594,18 @@ func (f *NamespaceStoreGetByNameFunc) History() []NamespaceStoreGetByNameFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *NamespaceStoreGetByNameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // NamespaceStoreGetByNameFuncCall is an object that describes an invocation
 // of method GetByName on an instance of MockNamespaceStore.
 type NamespaceStoreGetByNameFuncCall struct {
@@ -40318,6 +46714,18 @@ func (f *NamespaceStoreHandleFunc) History() []NamespaceStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *NamespaceStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // NamespaceStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockNamespaceStore.
 type NamespaceStoreHandleFuncCall struct {
@@ -40417,6 +46825,18 @@ func (f *NamespaceStoreWithFunc) History() []NamespaceStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *NamespaceStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // NamespaceStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockNamespaceStore.
 type NamespaceStoreWithFuncCall struct {
@@ -40519,6 +46939,18 @@ func (f *NamespaceStoreWithTransactFunc) History() []NamespaceStoreWithTransactF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *NamespaceStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // NamespaceStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockNamespaceStore.
 type NamespaceStoreWithTransactFuncCall struct {
@@ -40750,6 +47182,119 @@ func NewStrictMockOrgInvitationStore() *MockOrgInvitationStore {
 	}
 }
 
+// NewStrictMockOrgInvitationStoreWithT creates a new mock of the
+// OrgInvitationStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockOrgInvitationStoreWithT(t testing.TB) *MockOrgInvitationStore {
+	return &MockOrgInvitationStore{
+		CountFunc: &OrgInvitationStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.OrgInvitationsListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &OrgInvitationStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int32, v3 int32, v4 string, v5 time.Time) (r0 *database.OrgInvitation, r1 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.Create(%v, %v, %v, %v, %v, %v)", v0, v1, v2, v3, v4, v5)
+				return
+			},
+		},
+		GetByIDFunc: &OrgInvitationStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *database.OrgInvitation, r1 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetPendingFunc: &OrgInvitationStoreGetPendingFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int32) (r0 *database.OrgInvitation, r1 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.GetPending(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		GetPendingByIDFunc: &OrgInvitationStoreGetPendingByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *database.OrgInvitation, r1 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.GetPendingByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetPendingByOrgIDFunc: &OrgInvitationStoreGetPendingByOrgIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []*database.OrgInvitation, r1 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.GetPendingByOrgID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &OrgInvitationStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &OrgInvitationStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.OrgInvitationsListOptions) (r0 []*database.OrgInvitation, r1 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RespondFunc: &OrgInvitationStoreRespondFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 int32, v3 bool) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.Respond(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		RevokeFunc: &OrgInvitationStoreRevokeFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.Revoke(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateEmailSentTimestampFunc: &OrgInvitationStoreUpdateEmailSentTimestampFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.UpdateEmailSentTimestamp(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateExpiryTimeFunc: &OrgInvitationStoreUpdateExpiryTimeFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 time.Time) (r0 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.UpdateExpiryTime(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		WithFunc: &OrgInvitationStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.OrgInvitationStore) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &OrgInvitationStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.OrgInvitationStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockOrgInvitationStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockOrgInvitationStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockOrgInvitationStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockOrgInvitationStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockOrgInvitationStore.Create")
+	m.GetByIDFunc.assertExpectations(t, "MockOrgInvitationStore.GetByID")
+	m.GetPendingFunc.assertExpectations(t, "MockOrgInvitationStore.GetPending")
+	m.GetPendingByIDFunc.assertExpectations(t, "MockOrgInvitationStore.GetPendingByID")
+	m.GetPendingByOrgIDFunc.assertExpectations(t, "MockOrgInvitationStore.GetPendingByOrgID")
+	m.HandleFunc.assertExpectations(t, "MockOrgInvitationStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockOrgInvitationStore.List")
+	m.RespondFunc.assertExpectations(t, "MockOrgInvitationStore.Respond")
+	m.RevokeFunc.assertExpectations(t, "MockOrgInvitationStore.Revoke")
+	m.UpdateEmailSentTimestampFunc.assertExpectations(t, "MockOrgInvitationStore.UpdateEmailSentTimestamp")
+	m.UpdateExpiryTimeFunc.assertExpectations(t, "MockOrgInvitationStore.UpdateExpiryTime")
+	m.WithFunc.assertExpectations(t, "MockOrgInvitationStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockOrgInvitationStore.WithTransact")
+}
+
 // NewMockOrgInvitationStoreFrom creates a new mock of the
 // MockOrgInvitationStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -40879,6 +47424,18 @@ func (f *OrgInvitationStoreCountFunc) History() []OrgInvitationStoreCountFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreCountFuncCall is an object that describes an invocation
 // of method Count on an instance of MockOrgInvitationStore.
 type OrgInvitationStoreCountFuncCall struct {
@@ -40987,6 +47544,18 @@ func (f *OrgInvitationStoreCreateFunc) History() []OrgInvitationStoreCreateFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreCreateFuncCall is an object that describes an
 // invocation of method Create on an instance of MockOrgInvitationStore.
 type OrgInvitationStoreCreateFuncCall struct {
@@ -41107,6 +47676,18 @@ func (f *OrgInvitationStoreGetByIDFunc) History() []OrgInvitationStoreGetByIDFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreGetByIDFuncCall is an object that describes an
 // invocation of method GetByID on an instance of MockOrgInvitationStore.
 type OrgInvitationStoreGetByIDFuncCall struct {
@@ -41216,6 +47797,18 @@ func (f *OrgInvitationStoreGetPendingFunc) History() []OrgInvitationStoreGetPend
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreGetPendingFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreGetPendingFuncCall is an object that describes an
 // invocation of method GetPending on an instance of MockOrgInvitationStore.
 type OrgInvitationStoreGetPendingFuncCall struct {
@@ -41329,6 +47922,18 @@ func (f *OrgInvitationStoreGetPendingByIDFunc) History() []OrgInvitationStoreGet
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreGetPendingByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreGetPendingByIDFuncCall is an object that describes an
 // invocation of method GetPendingByID on an instance of
 // MockOrgInvitationStore.
@@ -41440,6 +48045,18 @@ func (f *OrgInvitationStoreGetPendingByOrgIDFunc) History() []OrgInvitationStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreGetPendingByOrgIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreGetPendingByOrgIDFuncCall is an object that describes
 // an invocation of method GetPendingByOrgID on an instance of
 // MockOrgInvitationStore.
@@ -41549,6 +48166,18 @@ func (f *OrgInvitationStoreHandleFunc) History() []OrgInvitationStoreHandleFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockOrgInvitationStore.
 type OrgInvitationStoreHandleFuncCall struct {
@@ -41648,6 +48277,18 @@ func (f *OrgInvitationStoreListFunc) History() []OrgInvitationStoreListFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreListFuncCall is an object that describes an invocation
 // of method List on an instance of MockOrgInvitationStore.
 type OrgInvitationStoreListFuncCall struct {
@@ -41756,6 +48397,18 @@ func (f *OrgInvitationStoreRespondFunc) History() []OrgInvitationStoreRespondFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreRespondFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreRespondFuncCall is an object that describes an
 // invocation of method Respond on an instance of MockOrgInvitationStore.
 type OrgInvitationStoreRespondFuncCall struct {
@@ -41870,6 +48523,18 @@ func (f *OrgInvitationStoreRevokeFunc) History() []OrgInvitationStoreRevokeFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreRevokeFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreRevokeFuncCall is an object that describes an
 // invocation of method Revoke on an instance of MockOrgInvitationStore.
 type OrgInvitationStoreRevokeFuncCall struct {
@@ -41978,6 +48643,18 @@ func (f *OrgInvitationStoreUpdateEmailSentTimestampFunc) History() []OrgInvitati
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreUpdateEmailSentTimestampFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreUpdateEmailSentTimestampFuncCall is an object that
 // describes an invocation of method UpdateEmailSentTimestamp on an instance
 // of MockOrgInvitationStore.
@@ -42086,6 +48763,18 @@ func (f *OrgInvitationStoreUpdateExpiryTimeFunc) History() []OrgInvitationStoreU
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreUpdateExpiryTimeFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreUpdateExpiryTimeFuncCall is an object that describes an
 // invocation of method UpdateExpiryTime on an instance of
 // MockOrgInvitationStore.
@@ -42195,6 +48884,18 @@ func (f *OrgInvitationStoreWithFunc) History() []OrgInvitationStoreWithFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreWithFuncCall is an object that describes an invocation
 // of method With on an instance of MockOrgInvitationStore.
 type OrgInvitationStoreWithFuncCall struct {
@@ -42298,6 +48999,18 @@ func (f *OrgInvitationStoreWithTransactFunc) History() []OrgInvitationStoreWithT
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgInvitationStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgInvitationStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of
 // MockOrgInvitationStore.
@@ -42491,6 +49204,98 @@ func NewStrictMockOrgMemberStore() *MockOrgMemberStore {
 	}
 }
 
+// NewStrictMockOrgMemberStoreWithT creates a new mock of the OrgMemberStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockOrgMemberStoreWithT(t testing.TB) *MockOrgMemberStore {
+	return &MockOrgMemberStore{
+		AutocompleteMembersSearchFunc: &OrgMemberStoreAutocompleteMembersSearchFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 []*types.OrgMemberAutocompleteSearchItem, r1 error) {
+				t.Errorf("unexpected call of MockOrgMemberStore.AutocompleteMembersSearch(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreateFunc: &OrgMemberStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int32) (r0 *types.OrgMembership, r1 error) {
+				t.Errorf("unexpected call of MockOrgMemberStore.Create(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreateMembershipInOrgsForAllUsersFunc: &OrgMemberStoreCreateMembershipInOrgsForAllUsersFunc{
+			defaultHook: func(v0 context.Context, v1 []string) (r0 error) {
+				t.Errorf("unexpected call of MockOrgMemberStore.CreateMembershipInOrgsForAllUsers(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByOrgIDFunc: &OrgMemberStoreGetByOrgIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []*types.OrgMembership, r1 error) {
+				t.Errorf("unexpected call of MockOrgMemberStore.GetByOrgID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByOrgIDAndUserIDFunc: &OrgMemberStoreGetByOrgIDAndUserIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int32) (r0 *types.OrgMembership, r1 error) {
+				t.Errorf("unexpected call of MockOrgMemberStore.GetByOrgIDAndUserID(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		GetByUserIDFunc: &OrgMemberStoreGetByUserIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []*types.OrgMembership, r1 error) {
+				t.Errorf("unexpected call of MockOrgMemberStore.GetByUserID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &OrgMemberStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockOrgMemberStore.Handle()")
+				return
+			},
+		},
+		MemberCountFunc: &OrgMemberStoreMemberCountFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockOrgMemberStore.MemberCount(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RemoveFunc: &OrgMemberStoreRemoveFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int32) (r0 error) {
+				t.Errorf("unexpected call of MockOrgMemberStore.Remove(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		WithFunc: &OrgMemberStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.OrgMemberStore) {
+				t.Errorf("unexpected call of MockOrgMemberStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &OrgMemberStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.OrgMemberStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockOrgMemberStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockOrgMemberStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockOrgMemberStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.AutocompleteMembersSearchFunc.assertExpectations(t, "MockOrgMemberStore.AutocompleteMembersSearch")
+	m.CreateFunc.assertExpectations(t, "MockOrgMemberStore.Create")
+	m.CreateMembershipInOrgsForAllUsersFunc.assertExpectations(t, "MockOrgMemberStore.CreateMembershipInOrgsForAllUsers")
+	m.GetByOrgIDFunc.assertExpectations(t, "MockOrgMemberStore.GetByOrgID")
+	m.GetByOrgIDAndUserIDFunc.assertExpectations(t, "MockOrgMemberStore.GetByOrgIDAndUserID")
+	m.GetByUserIDFunc.assertExpectations(t, "MockOrgMemberStore.GetByUserID")
+	m.HandleFunc.assertExpectations(t, "MockOrgMemberStore.Handle")
+	m.MemberCountFunc.assertExpectations(t, "MockOrgMemberStore.MemberCount")
+	m.RemoveFunc.assertExpectations(t, "MockOrgMemberStore.Remove")
+	m.WithFunc.assertExpectations(t, "MockOrgMemberStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockOrgMemberStore.WithTransact")
+}
+
 // NewMockOrgMemberStoreFrom creates a new mock of the MockOrgMemberStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -42614,6 +49419,18 @@ func (f *OrgMemberStoreAutocompleteMembersSearchFunc) History() []OrgMemberStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreAutocompleteMembersSearchFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreAutocompleteMembersSearchFuncCall is an object that
 // describes an invocation of method AutocompleteMembersSearch on an
 // instance of MockOrgMemberStore.
@@ -42726,6 +49543,18 @@ func (f *OrgMemberStoreCreateFunc) History() []OrgMemberStoreCreateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreCreateFuncCall is an object that describes an invocation of
 // method Create on an instance of MockOrgMemberStore.
 type OrgMemberStoreCreateFuncCall struct {
@@ -42840,6 +49669,18 @@ func (f *OrgMemberStoreCreateMembershipInOrgsForAllUsersFunc) History() []OrgMem
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreCreateMembershipInOrgsForAllUsersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreCreateMembershipInOrgsForAllUsersFuncCall is an object that
 // describes an invocation of method CreateMembershipInOrgsForAllUsers on an
 // instance of MockOrgMemberStore.
@@ -42946,6 +49787,18 @@ func (f *OrgMemberStoreGetByOrgIDFunc) History() []OrgMemberStoreGetByOrgIDFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreGetByOrgIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreGetByOrgIDFuncCall is an object that describes an
 // invocation of method GetByOrgID on an instance of MockOrgMemberStore.
 type OrgMemberStoreGetByOrgIDFuncCall struct {
@@ -43056,6 +49909,18 @@ func (f *OrgMemberStoreGetByOrgIDAndUserIDFunc) History() []OrgMemberStoreGetByO
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreGetByOrgIDAndUserIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreGetByOrgIDAndUserIDFuncCall is an object that describes an
 // invocation of method GetByOrgIDAndUserID on an instance of
 // MockOrgMemberStore.
@@ -43168,6 +50033,18 @@ func (f *OrgMemberStoreGetByUserIDFunc) History() []OrgMemberStoreGetByUserIDFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreGetByUserIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreGetByUserIDFuncCall is an object that describes an
 // invocation of method GetByUserID on an instance of MockOrgMemberStore.
 type OrgMemberStoreGetByUserIDFuncCall struct {
@@ -43276,6 +50153,18 @@ func (f *OrgMemberStoreHandleFunc) History() []OrgMemberStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockOrgMemberStore.
 type OrgMemberStoreHandleFuncCall struct {
@@ -43375,6 +50264,18 @@ func (f *OrgMemberStoreMemberCountFunc) History() []OrgMemberStoreMemberCountFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreMemberCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreMemberCountFuncCall is an object that describes an
 // invocation of method MemberCount on an instance of MockOrgMemberStore.
 type OrgMemberStoreMemberCountFuncCall struct {
@@ -43483,6 +50384,18 @@ func (f *OrgMemberStoreRemoveFunc) History() []OrgMemberStoreRemoveFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreRemoveFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreRemoveFuncCall is an object that describes an invocation of
 // method Remove on an instance of MockOrgMemberStore.
 type OrgMemberStoreRemoveFuncCall struct {
@@ -43591,6 +50504,18 @@ func (f *OrgMemberStoreWithFunc) History() []OrgMemberStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockOrgMemberStore.
 type OrgMemberStoreWithFuncCall struct {
@@ -43693,6 +50618,18 @@ func (f *OrgMemberStoreWithTransactFunc) History() []OrgMemberStoreWithTransactF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgMemberStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgMemberStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockOrgMemberStore.
 type OrgMemberStoreWithTransactFuncCall struct {
@@ -43908,6 +50845,111 @@ func NewStrictMockOrgStore() *MockOrgStore {
 	}
 }
 
+// NewStrictMockOrgStoreWithT creates a new mock of the OrgStore interface. All
+// methods fail t with their arguments and return zero values for all results,
+// unless overwritten.
+func NewStrictMockOrgStoreWithT(t testing.TB) *MockOrgStore {
+	return &MockOrgStore{
+		CountFunc: &OrgStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.OrgsListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockOrgStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &OrgStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 *string) (r0 *types.Org, r1 error) {
+				t.Errorf("unexpected call of MockOrgStore.Create(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DeleteFunc: &OrgStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockOrgStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &OrgStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockOrgStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetByIDFunc: &OrgStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *types.Org, r1 error) {
+				t.Errorf("unexpected call of MockOrgStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByNameFunc: &OrgStoreGetByNameFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *types.Org, r1 error) {
+				t.Errorf("unexpected call of MockOrgStore.GetByName(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByUserIDFunc: &OrgStoreGetByUserIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []*types.Org, r1 error) {
+				t.Errorf("unexpected call of MockOrgStore.GetByUserID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &OrgStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockOrgStore.Handle()")
+				return
+			},
+		},
+		HardDeleteFunc: &OrgStoreHardDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockOrgStore.HardDelete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListFunc: &OrgStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 *database.OrgsListOptions) (r0 []*types.Org, r1 error) {
+				t.Errorf("unexpected call of MockOrgStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		TransactFunc: &OrgStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.OrgStore, r1 error) {
+				t.Errorf("unexpected call of MockOrgStore.Transact(%v)", v0)
+				return
+			},
+		},
+		UpdateFunc: &OrgStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 *string) (r0 *types.Org, r1 error) {
+				t.Errorf("unexpected call of MockOrgStore.Update(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		WithFunc: &OrgStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.OrgStore) {
+				t.Errorf("unexpected call of MockOrgStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockOrgStore instance with PushHook or PushReturn which was not called.
+func (m *MockOrgStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockOrgStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockOrgStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockOrgStore.Delete")
+	m.DoneFunc.assertExpectations(t, "MockOrgStore.Done")
+	m.GetByIDFunc.assertExpectations(t, "MockOrgStore.GetByID")
+	m.GetByNameFunc.assertExpectations(t, "MockOrgStore.GetByName")
+	m.GetByUserIDFunc.assertExpectations(t, "MockOrgStore.GetByUserID")
+	m.HandleFunc.assertExpectations(t, "MockOrgStore.Handle")
+	m.HardDeleteFunc.assertExpectations(t, "MockOrgStore.HardDelete")
+	m.ListFunc.assertExpectations(t, "MockOrgStore.List")
+	m.TransactFunc.assertExpectations(t, "MockOrgStore.Transact")
+	m.UpdateFunc.assertExpectations(t, "MockOrgStore.Update")
+	m.WithFunc.assertExpectations(t, "MockOrgStore.With")
+}
+
 // NewMockOrgStoreFrom creates a new mock of the MockOrgStore interface. All
 // methods delegate to the given implementation, unless overwritten.
 func NewMockOrgStoreFrom(i database.OrgStore) *MockOrgStore {
@@ -44032,6 +51074,18 @@ func (f *OrgStoreCountFunc) History() []OrgStoreCountFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreCountFuncCall is an object that describes an invocation of method
 // Count on an instance of MockOrgStore.
 type OrgStoreCountFuncCall struct {
@@ -44139,6 +51193,18 @@ func (f *OrgStoreCreateFunc) History() []OrgStoreCreateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreCreateFuncCall is an object that describes an invocation of
 // method Create on an instance of MockOrgStore.
 type OrgStoreCreateFuncCall struct {
@@ -44249,6 +51315,18 @@ func (f *OrgStoreDeleteFunc) History() []OrgStoreDeleteFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreDeleteFuncCall is an object that describes an invocation of
 // method Delete on an instance of MockOrgStore.
 type OrgStoreDeleteFuncCall struct {
@@ -44353,6 +51431,18 @@ func (f *OrgStoreDoneFunc) History() []OrgStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreDoneFuncCall is an object that describes an invocation of method
 // Done on an instance of MockOrgStore.
 type OrgStoreDoneFuncCall struct {
@@ -44454,6 +51544,18 @@ func (f *OrgStoreGetByIDFunc) History() []OrgStoreGetByIDFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreGetByIDFuncCall is an object that describes an invocation of
 // method GetByID on an instance of MockOrgStore.
 type OrgStoreGetByIDFuncCall struct {
@@ -44561,6 +51663,18 @@ func (f *OrgStoreGetByNameFunc) History() []OrgStoreGetByNameFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreGetByNameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreGetByNameFuncCall is an object that describes an invocation of
 // method GetByName on an instance of MockOrgStore.
 type OrgStoreGetByNameFuncCall struct {
@@ -44669,6 +51783,18 @@ func (f *OrgStoreGetByUserIDFunc) History() []OrgStoreGetByUserIDFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreGetByUserIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreGetByUserIDFuncCall is an object that describes an invocation of
 // method GetByUserID on an instance of MockOrgStore.
 type OrgStoreGetByUserIDFuncCall struct {
@@ -44776,6 +51902,18 @@ func (f *OrgStoreHandleFunc) History() []OrgStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockOrgStore.
 type OrgStoreHandleFuncCall struct {
@@ -44874,6 +52012,18 @@ func (f *OrgStoreHardDeleteFunc) History() []OrgStoreHardDeleteFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreHardDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreHardDeleteFuncCall is an object that describes an invocation of
 // method HardDelete on an instance of MockOrgStore.
 type OrgStoreHardDeleteFuncCall struct {
@@ -44978,6 +52128,18 @@ func (f *OrgStoreListFunc) History() []OrgStoreListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreListFuncCall is an object that describes an invocation of method
 // List on an instance of MockOrgStore.
 type OrgStoreListFuncCall struct {
@@ -45085,6 +52247,18 @@ func (f *OrgStoreTransactFunc) History() []OrgStoreTransactFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreTransactFuncCall is an object that describes an invocation of
 // method Transact on an instance of MockOrgStore.
 type OrgStoreTransactFuncCall struct {
@@ -45189,6 +52363,18 @@ func (f *OrgStoreUpdateFunc) History() []OrgStoreUpdateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreUpdateFuncCall is an object that describes an invocation of
 // method Update on an instance of MockOrgStore.
 type OrgStoreUpdateFuncCall struct {
@@ -45299,6 +52485,18 @@ func (f *OrgStoreWithFunc) History() []OrgStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OrgStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OrgStoreWithFuncCall is an object that describes an invocation of method
 // With on an instance of MockOrgStore.
 type OrgStoreWithFuncCall struct {
@@ -45462,6 +52660,84 @@ func NewStrictMockOutboundWebhookJobStore() *MockOutboundWebhookJobStore {
 	}
 }
 
+// NewStrictMockOutboundWebhookJobStoreWithT creates a new mock of the
+// OutboundWebhookJobStore interface. All methods fail t with their arguments
+// and return zero values for all results, unless overwritten.
+func NewStrictMockOutboundWebhookJobStoreWithT(t testing.TB) *MockOutboundWebhookJobStore {
+	return &MockOutboundWebhookJobStore{
+		CreateFunc: &OutboundWebhookJobStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 *string, v3 []byte) (r0 *types.OutboundWebhookJob, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookJobStore.Create(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		DeleteBeforeFunc: &OutboundWebhookJobStoreDeleteBeforeFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time) (r0 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookJobStore.DeleteBefore(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &OutboundWebhookJobStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookJobStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetByIDFunc: &OutboundWebhookJobStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *types.OutboundWebhookJob, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookJobStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetLastFunc: &OutboundWebhookJobStoreGetLastFunc{
+			defaultHook: func(v0 context.Context) (r0 *types.OutboundWebhookJob, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookJobStore.GetLast(%v)", v0)
+				return
+			},
+		},
+		HandleFunc: &OutboundWebhookJobStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockOutboundWebhookJobStore.Handle()")
+				return
+			},
+		},
+		QueryFunc: &OutboundWebhookJobStoreQueryFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 *sql.Rows, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookJobStore.Query(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &OutboundWebhookJobStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.OutboundWebhookJobStore) {
+				t.Errorf("unexpected call of MockOutboundWebhookJobStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &OutboundWebhookJobStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.OutboundWebhookJobStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookJobStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockOutboundWebhookJobStore instance with PushHook or PushReturn which was
+// not called.
+func (m *MockOutboundWebhookJobStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CreateFunc.assertExpectations(t, "MockOutboundWebhookJobStore.Create")
+	m.DeleteBeforeFunc.assertExpectations(t, "MockOutboundWebhookJobStore.DeleteBefore")
+	m.DoneFunc.assertExpectations(t, "MockOutboundWebhookJobStore.Done")
+	m.GetByIDFunc.assertExpectations(t, "MockOutboundWebhookJobStore.GetByID")
+	m.GetLastFunc.assertExpectations(t, "MockOutboundWebhookJobStore.GetLast")
+	m.HandleFunc.assertExpectations(t, "MockOutboundWebhookJobStore.Handle")
+	m.QueryFunc.assertExpectations(t, "MockOutboundWebhookJobStore.Query")
+	m.WithFunc.assertExpectations(t, "MockOutboundWebhookJobStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockOutboundWebhookJobStore.WithTransact")
+}
+
 // NewMockOutboundWebhookJobStoreFrom creates a new mock of the
 // MockOutboundWebhookJobStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -45576,6 +52852,18 @@ func (f *OutboundWebhookJobStoreCreateFunc) History() []OutboundWebhookJobStoreC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookJobStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookJobStoreCreateFuncCall is an object that describes an
 // invocation of method Create on an instance of
 // MockOutboundWebhookJobStore.
@@ -45693,6 +52981,18 @@ func (f *OutboundWebhookJobStoreDeleteBeforeFunc) History() []OutboundWebhookJob
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookJobStoreDeleteBeforeFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookJobStoreDeleteBeforeFuncCall is an object that describes
 // an invocation of method DeleteBefore on an instance of
 // MockOutboundWebhookJobStore.
@@ -45799,6 +53099,18 @@ func (f *OutboundWebhookJobStoreDoneFunc) History() []OutboundWebhookJobStoreDon
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookJobStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookJobStoreDoneFuncCall is an object that describes an
 // invocation of method Done on an instance of MockOutboundWebhookJobStore.
 type OutboundWebhookJobStoreDoneFuncCall struct {
@@ -45902,6 +53214,18 @@ func (f *OutboundWebhookJobStoreGetByIDFunc) History() []OutboundWebhookJobStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookJobStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookJobStoreGetByIDFuncCall is an object that describes an
 // invocation of method GetByID on an instance of
 // MockOutboundWebhookJobStore.
@@ -46012,6 +53336,18 @@ func (f *OutboundWebhookJobStoreGetLastFunc) History() []OutboundWebhookJobStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookJobStoreGetLastFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookJobStoreGetLastFuncCall is an object that describes an
 // invocation of method GetLast on an instance of
 // MockOutboundWebhookJobStore.
@@ -46118,6 +53454,18 @@ func (f *OutboundWebhookJobStoreHandleFunc) History() []OutboundWebhookJobStoreH
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookJobStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookJobStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of
 // MockOutboundWebhookJobStore.
@@ -46218,6 +53566,18 @@ func (f *OutboundWebhookJobStoreQueryFunc) History() []OutboundWebhookJobStoreQu
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookJobStoreQueryFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookJobStoreQueryFuncCall is an object that describes an
 // invocation of method Query on an instance of MockOutboundWebhookJobStore.
 type OutboundWebhookJobStoreQueryFuncCall struct {
@@ -46326,6 +53686,18 @@ func (f *OutboundWebhookJobStoreWithFunc) History() []OutboundWebhookJobStoreWit
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookJobStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookJobStoreWithFuncCall is an object that describes an
 // invocation of method With on an instance of MockOutboundWebhookJobStore.
 type OutboundWebhookJobStoreWithFuncCall struct {
@@ -46430,6 +53802,18 @@ func (f *OutboundWebhookJobStoreWithTransactFunc) History() []OutboundWebhookJob
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookJobStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookJobStoreWithTransactFuncCall is an object that describes
 // an invocation of method WithTransact on an instance of
 // MockOutboundWebhookJobStore.
@@ -46584,6 +53968,77 @@ func NewStrictMockOutboundWebhookLogStore() *MockOutboundWebhookLogStore {
 	}
 }
 
+// NewStrictMockOutboundWebhookLogStoreWithT creates a new mock of the
+// OutboundWebhookLogStore interface. All methods fail t with their arguments
+// and return zero values for all results, unless overwritten.
+func NewStrictMockOutboundWebhookLogStoreWithT(t testing.TB) *MockOutboundWebhookLogStore {
+	return &MockOutboundWebhookLogStore{
+		CountsForOutboundWebhookFunc: &OutboundWebhookLogStoreCountsForOutboundWebhookFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 int64, r1 int64, r2 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookLogStore.CountsForOutboundWebhook(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &OutboundWebhookLogStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.OutboundWebhookLog) (r0 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookLogStore.Create(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &OutboundWebhookLogStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookLogStore.Done(%v)", v0)
+				return
+			},
+		},
+		HandleFunc: &OutboundWebhookLogStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockOutboundWebhookLogStore.Handle()")
+				return
+			},
+		},
+		ListForOutboundWebhookFunc: &OutboundWebhookLogStoreListForOutboundWebhookFunc{
+			defaultHook: func(v0 context.Context, v1 database.OutboundWebhookLogListOpts) (r0 []*types.OutboundWebhookLog, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookLogStore.ListForOutboundWebhook(%v, %v)", v0, v1)
+				return
+			},
+		},
+		QueryFunc: &OutboundWebhookLogStoreQueryFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 *sql.Rows, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookLogStore.Query(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &OutboundWebhookLogStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.OutboundWebhookLogStore) {
+				t.Errorf("unexpected call of MockOutboundWebhookLogStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &OutboundWebhookLogStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.OutboundWebhookLogStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookLogStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockOutboundWebhookLogStore instance with PushHook or PushReturn which was
+// not called.
+func (m *MockOutboundWebhookLogStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountsForOutboundWebhookFunc.assertExpectations(t, "MockOutboundWebhookLogStore.CountsForOutboundWebhook")
+	m.CreateFunc.assertExpectations(t, "MockOutboundWebhookLogStore.Create")
+	m.DoneFunc.assertExpectations(t, "MockOutboundWebhookLogStore.Done")
+	m.HandleFunc.assertExpectations(t, "MockOutboundWebhookLogStore.Handle")
+	m.ListForOutboundWebhookFunc.assertExpectations(t, "MockOutboundWebhookLogStore.ListForOutboundWebhook")
+	m.QueryFunc.assertExpectations(t, "MockOutboundWebhookLogStore.Query")
+	m.WithFunc.assertExpectations(t, "MockOutboundWebhookLogStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockOutboundWebhookLogStore.WithTransact")
+}
+
 // NewMockOutboundWebhookLogStoreFrom creates a new mock of the
 // MockOutboundWebhookLogStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -46698,6 +54153,18 @@ func (f *OutboundWebhookLogStoreCountsForOutboundWebhookFunc) History() []Outbou
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookLogStoreCountsForOutboundWebhookFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookLogStoreCountsForOutboundWebhookFuncCall is an object that
 // describes an invocation of method CountsForOutboundWebhook on an instance
 // of MockOutboundWebhookLogStore.
@@ -46810,6 +54277,18 @@ func (f *OutboundWebhookLogStoreCreateFunc) History() []OutboundWebhookLogStoreC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookLogStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookLogStoreCreateFuncCall is an object that describes an
 // invocation of method Create on an instance of
 // MockOutboundWebhookLogStore.
@@ -46916,6 +54395,18 @@ func (f *OutboundWebhookLogStoreDoneFunc) History() []OutboundWebhookLogStoreDon
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookLogStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookLogStoreDoneFuncCall is an object that describes an
 // invocation of method Done on an instance of MockOutboundWebhookLogStore.
 type OutboundWebhookLogStoreDoneFuncCall struct {
@@ -47018,6 +54509,18 @@ func (f *OutboundWebhookLogStoreHandleFunc) History() []OutboundWebhookLogStoreH
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookLogStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookLogStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of
 // MockOutboundWebhookLogStore.
@@ -47121,6 +54624,18 @@ func (f *OutboundWebhookLogStoreListForOutboundWebhookFunc) History() []Outbound
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookLogStoreListForOutboundWebhookFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookLogStoreListForOutboundWebhookFuncCall is an object that
 // describes an invocation of method ListForOutboundWebhook on an instance
 // of MockOutboundWebhookLogStore.
@@ -47230,6 +54745,18 @@ func (f *OutboundWebhookLogStoreQueryFunc) History() []OutboundWebhookLogStoreQu
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookLogStoreQueryFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookLogStoreQueryFuncCall is an object that describes an
 // invocation of method Query on an instance of MockOutboundWebhookLogStore.
 type OutboundWebhookLogStoreQueryFuncCall struct {
@@ -47338,6 +54865,18 @@ func (f *OutboundWebhookLogStoreWithFunc) History() []OutboundWebhookLogStoreWit
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookLogStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookLogStoreWithFuncCall is an object that describes an
 // invocation of method With on an instance of MockOutboundWebhookLogStore.
 type OutboundWebhookLogStoreWithFuncCall struct {
@@ -47442,6 +54981,18 @@ func (f *OutboundWebhookLogStoreWithTransactFunc) History() []OutboundWebhookLog
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookLogStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookLogStoreWithTransactFuncCall is an object that describes
 // an invocation of method WithTransact on an instance of
 // MockOutboundWebhookLogStore.
@@ -47661,6 +55212,112 @@ func NewStrictMockOutboundWebhookStore() *MockOutboundWebhookStore {
 	}
 }
 
+// NewStrictMockOutboundWebhookStoreWithT creates a new mock of the
+// OutboundWebhookStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockOutboundWebhookStoreWithT(t testing.TB) *MockOutboundWebhookStore {
+	return &MockOutboundWebhookStore{
+		CountFunc: &OutboundWebhookStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.OutboundWebhookCountOpts) (r0 int64, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &OutboundWebhookStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.OutboundWebhook) (r0 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.Create(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteFunc: &OutboundWebhookStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &OutboundWebhookStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetByIDFunc: &OutboundWebhookStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *types.OutboundWebhook, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &OutboundWebhookStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &OutboundWebhookStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.OutboundWebhookListOpts) (r0 []*types.OutboundWebhook, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		QueryFunc: &OutboundWebhookStoreQueryFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 *sql.Rows, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.Query(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ToJobStoreFunc: &OutboundWebhookStoreToJobStoreFunc{
+			defaultHook: func() (r0 database.OutboundWebhookJobStore) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.ToJobStore()")
+				return
+			},
+		},
+		ToLogStoreFunc: &OutboundWebhookStoreToLogStoreFunc{
+			defaultHook: func() (r0 database.OutboundWebhookLogStore) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.ToLogStore()")
+				return
+			},
+		},
+		TransactFunc: &OutboundWebhookStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.OutboundWebhookStore, r1 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.Transact(%v)", v0)
+				return
+			},
+		},
+		UpdateFunc: &OutboundWebhookStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.OutboundWebhook) (r0 error) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.Update(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &OutboundWebhookStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.OutboundWebhookStore) {
+				t.Errorf("unexpected call of MockOutboundWebhookStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockOutboundWebhookStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockOutboundWebhookStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockOutboundWebhookStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockOutboundWebhookStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockOutboundWebhookStore.Delete")
+	m.DoneFunc.assertExpectations(t, "MockOutboundWebhookStore.Done")
+	m.GetByIDFunc.assertExpectations(t, "MockOutboundWebhookStore.GetByID")
+	m.HandleFunc.assertExpectations(t, "MockOutboundWebhookStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockOutboundWebhookStore.List")
+	m.QueryFunc.assertExpectations(t, "MockOutboundWebhookStore.Query")
+	m.ToJobStoreFunc.assertExpectations(t, "MockOutboundWebhookStore.ToJobStore")
+	m.ToLogStoreFunc.assertExpectations(t, "MockOutboundWebhookStore.ToLogStore")
+	m.TransactFunc.assertExpectations(t, "MockOutboundWebhookStore.Transact")
+	m.UpdateFunc.assertExpectations(t, "MockOutboundWebhookStore.Update")
+	m.WithFunc.assertExpectations(t, "MockOutboundWebhookStore.With")
+}
+
 // NewMockOutboundWebhookStoreFrom creates a new mock of the
 // MockOutboundWebhookStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -47787,6 +55444,18 @@ func (f *OutboundWebhookStoreCountFunc) History() []OutboundWebhookStoreCountFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreCountFuncCall is an object that describes an
 // invocation of method Count on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreCountFuncCall struct {
@@ -47895,6 +55564,18 @@ func (f *OutboundWebhookStoreCreateFunc) History() []OutboundWebhookStoreCreateF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreCreateFuncCall is an object that describes an
 // invocation of method Create on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreCreateFuncCall struct {
@@ -48000,6 +55681,18 @@ func (f *OutboundWebhookStoreDeleteFunc) History() []OutboundWebhookStoreDeleteF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreDeleteFuncCall is an object that describes an
 // invocation of method Delete on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreDeleteFuncCall struct {
@@ -48105,6 +55798,18 @@ func (f *OutboundWebhookStoreDoneFunc) History() []OutboundWebhookStoreDoneFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreDoneFuncCall is an object that describes an
 // invocation of method Done on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreDoneFuncCall struct {
@@ -48207,6 +55912,18 @@ func (f *OutboundWebhookStoreGetByIDFunc) History() []OutboundWebhookStoreGetByI
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreGetByIDFuncCall is an object that describes an
 // invocation of method GetByID on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreGetByIDFuncCall struct {
@@ -48315,6 +56032,18 @@ func (f *OutboundWebhookStoreHandleFunc) History() []OutboundWebhookStoreHandleF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreHandleFuncCall struct {
@@ -48414,6 +56143,18 @@ func (f *OutboundWebhookStoreListFunc) History() []OutboundWebhookStoreListFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreListFuncCall is an object that describes an
 // invocation of method List on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreListFuncCall struct {
@@ -48522,6 +56263,18 @@ func (f *OutboundWebhookStoreQueryFunc) History() []OutboundWebhookStoreQueryFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreQueryFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreQueryFuncCall is an object that describes an
 // invocation of method Query on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreQueryFuncCall struct {
@@ -48631,6 +56384,18 @@ func (f *OutboundWebhookStoreToJobStoreFunc) History() []OutboundWebhookStoreToJ
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreToJobStoreFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreToJobStoreFuncCall is an object that describes an
 // invocation of method ToJobStore on an instance of
 // MockOutboundWebhookStore.
@@ -48732,6 +56497,18 @@ func (f *OutboundWebhookStoreToLogStoreFunc) History() []OutboundWebhookStoreToL
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreToLogStoreFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreToLogStoreFuncCall is an object that describes an
 // invocation of method ToLogStore on an instance of
 // MockOutboundWebhookStore.
@@ -48832,6 +56609,18 @@ func (f *OutboundWebhookStoreTransactFunc) History() []OutboundWebhookStoreTrans
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreTransactFuncCall is an object that describes an
 // invocation of method Transact on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreTransactFuncCall struct {
@@ -48937,6 +56726,18 @@ func (f *OutboundWebhookStoreUpdateFunc) History() []OutboundWebhookStoreUpdateF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreUpdateFuncCall is an object that describes an
 // invocation of method Update on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreUpdateFuncCall struct {
@@ -49042,6 +56843,18 @@ func (f *OutboundWebhookStoreWithFunc) History() []OutboundWebhookStoreWithFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OutboundWebhookStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OutboundWebhookStoreWithFuncCall is an object that describes an
 // invocation of method With on an instance of MockOutboundWebhookStore.
 type OutboundWebhookStoreWithFuncCall struct {
@@ -49140,6 +56953,49 @@ func NewStrictMockOwnershipStatsStore() *MockOwnershipStatsStore {
 	}
 }
 
+// NewStrictMockOwnershipStatsStoreWithT creates a new mock of the
+// OwnershipStatsStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockOwnershipStatsStoreWithT(t testing.TB) *MockOwnershipStatsStore {
+	return &MockOwnershipStatsStore{
+		QueryAggregateCountsFunc: &OwnershipStatsStoreQueryAggregateCountsFunc{
+			defaultHook: func(v0 context.Context, v1 database.TreeLocationOpts) (r0 database.PathAggregateCounts, r1 error) {
+				t.Errorf("unexpected call of MockOwnershipStatsStore.QueryAggregateCounts(%v, %v)", v0, v1)
+				return
+			},
+		},
+		QueryIndividualCountsFunc: &OwnershipStatsStoreQueryIndividualCountsFunc{
+			defaultHook: func(v0 context.Context, v1 database.TreeLocationOpts, v2 *database.LimitOffset) (r0 []database.PathCodeownersCounts, r1 error) {
+				t.Errorf("unexpected call of MockOwnershipStatsStore.QueryIndividualCounts(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		UpdateAggregateCountsFunc: &OwnershipStatsStoreUpdateAggregateCountsFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID, v2 database.TreeAggregateStats, v3 time.Time) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockOwnershipStatsStore.UpdateAggregateCounts(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		UpdateIndividualCountsFunc: &OwnershipStatsStoreUpdateIndividualCountsFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID, v2 database.TreeCodeownersStats, v3 time.Time) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockOwnershipStatsStore.UpdateIndividualCounts(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockOwnershipStatsStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockOwnershipStatsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.QueryAggregateCountsFunc.assertExpectations(t, "MockOwnershipStatsStore.QueryAggregateCounts")
+	m.QueryIndividualCountsFunc.assertExpectations(t, "MockOwnershipStatsStore.QueryIndividualCounts")
+	m.UpdateAggregateCountsFunc.assertExpectations(t, "MockOwnershipStatsStore.UpdateAggregateCounts")
+	m.UpdateIndividualCountsFunc.assertExpectations(t, "MockOwnershipStatsStore.UpdateIndividualCounts")
+}
+
 // NewMockOwnershipStatsStoreFrom creates a new mock of the
 // MockOwnershipStatsStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -49242,6 +57098,18 @@ func (f *OwnershipStatsStoreQueryAggregateCountsFunc) History() []OwnershipStats
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OwnershipStatsStoreQueryAggregateCountsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OwnershipStatsStoreQueryAggregateCountsFuncCall is an object that
 // describes an invocation of method QueryAggregateCounts on an instance of
 // MockOwnershipStatsStore.
@@ -49354,6 +57222,18 @@ func (f *OwnershipStatsStoreQueryIndividualCountsFunc) History() []OwnershipStat
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OwnershipStatsStoreQueryIndividualCountsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OwnershipStatsStoreQueryIndividualCountsFuncCall is an object that
 // describes an invocation of method QueryIndividualCounts on an instance of
 // MockOwnershipStatsStore.
@@ -49469,6 +57349,18 @@ func (f *OwnershipStatsStoreUpdateAggregateCountsFunc) History() []OwnershipStat
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OwnershipStatsStoreUpdateAggregateCountsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OwnershipStatsStoreUpdateAggregateCountsFuncCall is an object that
 // describes an invocation of method UpdateAggregateCounts on an instance of
 // MockOwnershipStatsStore.
@@ -49587,6 +57479,18 @@ func (f *OwnershipStatsStoreUpdateIndividualCountsFunc) History() []OwnershipSta
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *OwnershipStatsStoreUpdateIndividualCountsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // OwnershipStatsStoreUpdateIndividualCountsFuncCall is an object that
 // describes an invocation of method UpdateIndividualCounts on an instance
 // of MockOwnershipStatsStore.
@@ -49775,6 +57679,91 @@ func NewStrictMockPermissionStore() *MockPermissionStore {
 	}
 }
 
+// NewStrictMockPermissionStoreWithT creates a new mock of the PermissionStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockPermissionStoreWithT(t testing.TB) *MockPermissionStore {
+	return &MockPermissionStore{
+		BulkCreateFunc: &PermissionStoreBulkCreateFunc{
+			defaultHook: func(v0 context.Context, v1 []database.CreatePermissionOpts) (r0 []*types.Permission, r1 error) {
+				t.Errorf("unexpected call of MockPermissionStore.BulkCreate(%v, %v)", v0, v1)
+				return
+			},
+		},
+		BulkDeleteFunc: &PermissionStoreBulkDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 []database.DeletePermissionOpts) (r0 error) {
+				t.Errorf("unexpected call of MockPermissionStore.BulkDelete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountFunc: &PermissionStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.PermissionListOpts) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockPermissionStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &PermissionStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 database.CreatePermissionOpts) (r0 *types.Permission, r1 error) {
+				t.Errorf("unexpected call of MockPermissionStore.Create(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteFunc: &PermissionStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 database.DeletePermissionOpts) (r0 error) {
+				t.Errorf("unexpected call of MockPermissionStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &PermissionStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 database.GetPermissionOpts) (r0 *types.Permission, r1 error) {
+				t.Errorf("unexpected call of MockPermissionStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetPermissionForUserFunc: &PermissionStoreGetPermissionForUserFunc{
+			defaultHook: func(v0 context.Context, v1 database.GetPermissionForUserOpts) (r0 *types.Permission, r1 error) {
+				t.Errorf("unexpected call of MockPermissionStore.GetPermissionForUser(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &PermissionStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockPermissionStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &PermissionStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.PermissionListOpts) (r0 []*types.Permission, r1 error) {
+				t.Errorf("unexpected call of MockPermissionStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithTransactFunc: &PermissionStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.PermissionStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockPermissionStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockPermissionStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockPermissionStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.BulkCreateFunc.assertExpectations(t, "MockPermissionStore.BulkCreate")
+	m.BulkDeleteFunc.assertExpectations(t, "MockPermissionStore.BulkDelete")
+	m.CountFunc.assertExpectations(t, "MockPermissionStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockPermissionStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockPermissionStore.Delete")
+	m.GetByIDFunc.assertExpectations(t, "MockPermissionStore.GetByID")
+	m.GetPermissionForUserFunc.assertExpectations(t, "MockPermissionStore.GetPermissionForUser")
+	m.HandleFunc.assertExpectations(t, "MockPermissionStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockPermissionStore.List")
+	m.WithTransactFunc.assertExpectations(t, "MockPermissionStore.WithTransact")
+}
+
 // NewMockPermissionStoreFrom creates a new mock of the MockPermissionStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -49892,6 +57881,18 @@ func (f *PermissionStoreBulkCreateFunc) History() []PermissionStoreBulkCreateFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionStoreBulkCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionStoreBulkCreateFuncCall is an object that describes an
 // invocation of method BulkCreate on an instance of MockPermissionStore.
 type PermissionStoreBulkCreateFuncCall struct {
@@ -50000,6 +58001,18 @@ func (f *PermissionStoreBulkDeleteFunc) History() []PermissionStoreBulkDeleteFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionStoreBulkDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionStoreBulkDeleteFuncCall is an object that describes an
 // invocation of method BulkDelete on an instance of MockPermissionStore.
 type PermissionStoreBulkDeleteFuncCall struct {
@@ -50105,6 +58118,18 @@ func (f *PermissionStoreCountFunc) History() []PermissionStoreCountFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionStoreCountFuncCall is an object that describes an invocation of
 // method Count on an instance of MockPermissionStore.
 type PermissionStoreCountFuncCall struct {
@@ -50213,6 +58238,18 @@ func (f *PermissionStoreCreateFunc) History() []PermissionStoreCreateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionStoreCreateFuncCall is an object that describes an invocation
 // of method Create on an instance of MockPermissionStore.
 type PermissionStoreCreateFuncCall struct {
@@ -50321,6 +58358,18 @@ func (f *PermissionStoreDeleteFunc) History() []PermissionStoreDeleteFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionStoreDeleteFuncCall is an object that describes an invocation
 // of method Delete on an instance of MockPermissionStore.
 type PermissionStoreDeleteFuncCall struct {
@@ -50426,6 +58475,18 @@ func (f *PermissionStoreGetByIDFunc) History() []PermissionStoreGetByIDFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionStoreGetByIDFuncCall is an object that describes an invocation
 // of method GetByID on an instance of MockPermissionStore.
 type PermissionStoreGetByIDFuncCall struct {
@@ -50536,6 +58597,18 @@ func (f *PermissionStoreGetPermissionForUserFunc) History() []PermissionStoreGet
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionStoreGetPermissionForUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionStoreGetPermissionForUserFuncCall is an object that describes
 // an invocation of method GetPermissionForUser on an instance of
 // MockPermissionStore.
@@ -50645,6 +58718,18 @@ func (f *PermissionStoreHandleFunc) History() []PermissionStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockPermissionStore.
 type PermissionStoreHandleFuncCall struct {
@@ -50744,6 +58829,18 @@ func (f *PermissionStoreListFunc) History() []PermissionStoreListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionStoreListFuncCall is an object that describes an invocation of
 // method List on an instance of MockPermissionStore.
 type PermissionStoreListFuncCall struct {
@@ -50853,6 +58950,18 @@ func (f *PermissionStoreWithTransactFunc) History() []PermissionStoreWithTransac
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockPermissionStore.
 type PermissionStoreWithTransactFuncCall struct {
@@ -51073,6 +59182,112 @@ func NewStrictMockPermissionSyncJobStore() *MockPermissionSyncJobStore {
 	}
 }
 
+// NewStrictMockPermissionSyncJobStoreWithT creates a new mock of the
+// PermissionSyncJobStore interface. All methods fail t with their arguments
+// and return zero values for all results, unless overwritten.
+func NewStrictMockPermissionSyncJobStoreWithT(t testing.TB) *MockPermissionSyncJobStore {
+	return &MockPermissionSyncJobStore{
+		CancelQueuedJobFunc: &PermissionSyncJobStoreCancelQueuedJobFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 int) (r0 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.CancelQueuedJob(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CountFunc: &PermissionSyncJobStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListPermissionSyncJobOpts) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountReposWithFailingSyncJobFunc: &PermissionSyncJobStoreCountReposWithFailingSyncJobFunc{
+			defaultHook: func(v0 context.Context) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.CountReposWithFailingSyncJob(%v)", v0)
+				return
+			},
+		},
+		CountUsersWithFailingSyncJobFunc: &PermissionSyncJobStoreCountUsersWithFailingSyncJobFunc{
+			defaultHook: func(v0 context.Context) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.CountUsersWithFailingSyncJob(%v)", v0)
+				return
+			},
+		},
+		CreateRepoSyncJobFunc: &PermissionSyncJobStoreCreateRepoSyncJobFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID, v2 database.PermissionSyncJobOpts) (r0 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.CreateRepoSyncJob(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreateUserSyncJobFunc: &PermissionSyncJobStoreCreateUserSyncJobFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 database.PermissionSyncJobOpts) (r0 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.CreateUserSyncJob(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DoneFunc: &PermissionSyncJobStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetLatestFinishedSyncJobFunc: &PermissionSyncJobStoreGetLatestFinishedSyncJobFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListPermissionSyncJobOpts) (r0 *database.PermissionSyncJob, r1 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.GetLatestFinishedSyncJob(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &PermissionSyncJobStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &PermissionSyncJobStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListPermissionSyncJobOpts) (r0 []*database.PermissionSyncJob, r1 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		SaveSyncResultFunc: &PermissionSyncJobStoreSaveSyncResultFunc{
+			defaultHook: func(v0 context.Context, v1 int, v2 bool, v3 *database.SetPermissionsResult, v4 database.CodeHostStatusesSet) (r0 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.SaveSyncResult(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		TransactFunc: &PermissionSyncJobStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.PermissionSyncJobStore, r1 error) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.Transact(%v)", v0)
+				return
+			},
+		},
+		WithFunc: &PermissionSyncJobStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.PermissionSyncJobStore) {
+				t.Errorf("unexpected call of MockPermissionSyncJobStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockPermissionSyncJobStore instance with PushHook or PushReturn which was
+// not called.
+func (m *MockPermissionSyncJobStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CancelQueuedJobFunc.assertExpectations(t, "MockPermissionSyncJobStore.CancelQueuedJob")
+	m.CountFunc.assertExpectations(t, "MockPermissionSyncJobStore.Count")
+	m.CountReposWithFailingSyncJobFunc.assertExpectations(t, "MockPermissionSyncJobStore.CountReposWithFailingSyncJob")
+	m.CountUsersWithFailingSyncJobFunc.assertExpectations(t, "MockPermissionSyncJobStore.CountUsersWithFailingSyncJob")
+	m.CreateRepoSyncJobFunc.assertExpectations(t, "MockPermissionSyncJobStore.CreateRepoSyncJob")
+	m.CreateUserSyncJobFunc.assertExpectations(t, "MockPermissionSyncJobStore.CreateUserSyncJob")
+	m.DoneFunc.assertExpectations(t, "MockPermissionSyncJobStore.Done")
+	m.GetLatestFinishedSyncJobFunc.assertExpectations(t, "MockPermissionSyncJobStore.GetLatestFinishedSyncJob")
+	m.HandleFunc.assertExpectations(t, "MockPermissionSyncJobStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockPermissionSyncJobStore.List")
+	m.SaveSyncResultFunc.assertExpectations(t, "MockPermissionSyncJobStore.SaveSyncResult")
+	m.TransactFunc.assertExpectations(t, "MockPermissionSyncJobStore.Transact")
+	m.WithFunc.assertExpectations(t, "MockPermissionSyncJobStore.With")
+}
+
 // NewMockPermissionSyncJobStoreFrom creates a new mock of the
 // MockPermissionSyncJobStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -51202,6 +59417,18 @@ func (f *PermissionSyncJobStoreCancelQueuedJobFunc) History() []PermissionSyncJo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreCancelQueuedJobFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreCancelQueuedJobFuncCall is an object that describes
 // an invocation of method CancelQueuedJob on an instance of
 // MockPermissionSyncJobStore.
@@ -51311,6 +59538,18 @@ func (f *PermissionSyncJobStoreCountFunc) History() []PermissionSyncJobStoreCoun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreCountFuncCall is an object that describes an
 // invocation of method Count on an instance of MockPermissionSyncJobStore.
 type PermissionSyncJobStoreCountFuncCall struct {
@@ -51423,6 +59662,18 @@ func (f *PermissionSyncJobStoreCountReposWithFailingSyncJobFunc) History() []Per
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreCountReposWithFailingSyncJobFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreCountReposWithFailingSyncJobFuncCall is an object
 // that describes an invocation of method CountReposWithFailingSyncJob on an
 // instance of MockPermissionSyncJobStore.
@@ -51533,6 +59784,18 @@ func (f *PermissionSyncJobStoreCountUsersWithFailingSyncJobFunc) History() []Per
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreCountUsersWithFailingSyncJobFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreCountUsersWithFailingSyncJobFuncCall is an object
 // that describes an invocation of method CountUsersWithFailingSyncJob on an
 // instance of MockPermissionSyncJobStore.
@@ -51642,6 +59905,18 @@ func (f *PermissionSyncJobStoreCreateRepoSyncJobFunc) History() []PermissionSync
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreCreateRepoSyncJobFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreCreateRepoSyncJobFuncCall is an object that
 // describes an invocation of method CreateRepoSyncJob on an instance of
 // MockPermissionSyncJobStore.
@@ -51754,6 +60029,18 @@ func (f *PermissionSyncJobStoreCreateUserSyncJobFunc) History() []PermissionSync
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreCreateUserSyncJobFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreCreateUserSyncJobFuncCall is an object that
 // describes an invocation of method CreateUserSyncJob on an instance of
 // MockPermissionSyncJobStore.
@@ -51863,6 +60150,18 @@ func (f *PermissionSyncJobStoreDoneFunc) History() []PermissionSyncJobStoreDoneF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreDoneFuncCall is an object that describes an
 // invocation of method Done on an instance of MockPermissionSyncJobStore.
 type PermissionSyncJobStoreDoneFuncCall struct {
@@ -51968,6 +60267,18 @@ func (f *PermissionSyncJobStoreGetLatestFinishedSyncJobFunc) History() []Permiss
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreGetLatestFinishedSyncJobFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreGetLatestFinishedSyncJobFuncCall is an object that
 // describes an invocation of method GetLatestFinishedSyncJob on an instance
 // of MockPermissionSyncJobStore.
@@ -52077,6 +60388,18 @@ func (f *PermissionSyncJobStoreHandleFunc) History() []PermissionSyncJobStoreHan
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockPermissionSyncJobStore.
 type PermissionSyncJobStoreHandleFuncCall struct {
@@ -52176,6 +60499,18 @@ func (f *PermissionSyncJobStoreListFunc) History() []PermissionSyncJobStoreListF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreListFuncCall is an object that describes an
 // invocation of method List on an instance of MockPermissionSyncJobStore.
 type PermissionSyncJobStoreListFuncCall struct {
@@ -52287,6 +60622,18 @@ func (f *PermissionSyncJobStoreSaveSyncResultFunc) History() []PermissionSyncJob
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreSaveSyncResultFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreSaveSyncResultFuncCall is an object that describes
 // an invocation of method SaveSyncResult on an instance of
 // MockPermissionSyncJobStore.
@@ -52403,6 +60750,18 @@ func (f *PermissionSyncJobStoreTransactFunc) History() []PermissionSyncJobStoreT
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreTransactFuncCall is an object that describes an
 // invocation of method Transact on an instance of
 // MockPermissionSyncJobStore.
@@ -52509,6 +60868,18 @@ func (f *PermissionSyncJobStoreWithFunc) History() []PermissionSyncJobStoreWithF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermissionSyncJobStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermissionSyncJobStoreWithFuncCall is an object that describes an
 // invocation of method With on an instance of MockPermissionSyncJobStore.
 type PermissionSyncJobStoreWithFuncCall struct {
@@ -52949,6 +61320,230 @@ func NewStrictMockPermsStore() *MockPermsStore {
 	}
 }
 
+// NewStrictMockPermsStoreWithT creates a new mock of the PermsStore interface.
+// All methods fail t with their arguments and return zero values for all
+// results, unless overwritten.
+func NewStrictMockPermsStoreWithT(t testing.TB) *MockPermsStore {
+	return &MockPermsStore{
+		CountReposWithNoPermsFunc: &PermsStoreCountReposWithNoPermsFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.CountReposWithNoPerms(%v)", v0)
+				return
+			},
+		},
+		CountReposWithStalePermsFunc: &PermsStoreCountReposWithStalePermsFunc{
+			defaultHook: func(v0 context.Context, v1 time.Duration) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.CountReposWithStalePerms(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountUsersWithNoPermsFunc: &PermsStoreCountUsersWithNoPermsFunc{
+			defaultHook: func(v0 context.Context) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.CountUsersWithNoPerms(%v)", v0)
+				return
+			},
+		},
+		CountUsersWithStalePermsFunc: &PermsStoreCountUsersWithStalePermsFunc{
+			defaultHook: func(v0 context.Context, v1 time.Duration) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.CountUsersWithStalePerms(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteAllUserPendingPermissionsFunc: &PermsStoreDeleteAllUserPendingPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 *extsvc.Accounts) (r0 error) {
+				t.Errorf("unexpected call of MockPermsStore.DeleteAllUserPendingPermissions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteAllUserPermissionsFunc: &PermsStoreDeleteAllUserPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockPermsStore.DeleteAllUserPermissions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &PermsStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockPermsStore.Done(%v)", v0)
+				return
+			},
+		},
+		FetchReposByExternalAccountFunc: &PermsStoreFetchReposByExternalAccountFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []api.RepoID, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.FetchReposByExternalAccount(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetUserIDsByExternalAccountsFunc: &PermsStoreGetUserIDsByExternalAccountsFunc{
+			defaultHook: func(v0 context.Context, v1 *extsvc.Accounts) (r0 map[string]authz.UserIDWithExternalAccountID, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.GetUserIDsByExternalAccounts(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GrantPendingPermissionsFunc: &PermsStoreGrantPendingPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 *authz.UserGrantPermissions) (r0 error) {
+				t.Errorf("unexpected call of MockPermsStore.GrantPendingPermissions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &PermsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockPermsStore.Handle()")
+				return
+			},
+		},
+		IsRepoUnrestrictedFunc: &PermsStoreIsRepoUnrestrictedFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.IsRepoUnrestricted(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListPendingUsersFunc: &PermsStoreListPendingUsersFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 string) (r0 []string, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.ListPendingUsers(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		ListRepoPermissionsFunc: &PermsStoreListRepoPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID, v2 *database.ListRepoPermissionsArgs) (r0 []*database.RepoPermission, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.ListRepoPermissions(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		ListUserPermissionsFunc: &PermsStoreListUserPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 *database.ListUserPermissionsArgs) (r0 []*database.UserPermission, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.ListUserPermissions(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		LoadRepoPermissionsFunc: &PermsStoreLoadRepoPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []authz.Permission, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.LoadRepoPermissions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		LoadUserPendingPermissionsFunc: &PermsStoreLoadUserPendingPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 *authz.UserPendingPermissions) (r0 error) {
+				t.Errorf("unexpected call of MockPermsStore.LoadUserPendingPermissions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		LoadUserPermissionsFunc: &PermsStoreLoadUserPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []authz.Permission, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.LoadUserPermissions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		MapUsersFunc: &PermsStoreMapUsersFunc{
+			defaultHook: func(v0 context.Context, v1 []string, v2 *schema.PermissionsUserMapping) (r0 map[string]int32, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.MapUsers(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		MetricsFunc: &PermsStoreMetricsFunc{
+			defaultHook: func(v0 context.Context, v1 time.Duration) (r0 *database.PermsMetrics, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.Metrics(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RepoIDsWithNoPermsFunc: &PermsStoreRepoIDsWithNoPermsFunc{
+			defaultHook: func(v0 context.Context) (r0 []api.RepoID, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.RepoIDsWithNoPerms(%v)", v0)
+				return
+			},
+		},
+		ReposIDsWithOldestPermsFunc: &PermsStoreReposIDsWithOldestPermsFunc{
+			defaultHook: func(v0 context.Context, v1 int, v2 time.Duration) (r0 map[api.RepoID]time.Time, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.ReposIDsWithOldestPerms(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetRepoPendingPermissionsFunc: &PermsStoreSetRepoPendingPermissionsFunc{
+			defaultHook: func(v0 context.Context, v1 *extsvc.Accounts, v2 *authz.RepoPermissions) (r0 error) {
+				t.Errorf("unexpected call of MockPermsStore.SetRepoPendingPermissions(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetRepoPermissionsUnrestrictedFunc: &PermsStoreSetRepoPermissionsUnrestrictedFunc{
+			defaultHook: func(v0 context.Context, v1 []int32, v2 bool) (r0 error) {
+				t.Errorf("unexpected call of MockPermsStore.SetRepoPermissionsUnrestricted(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetRepoPermsFunc: &PermsStoreSetRepoPermsFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 []authz.UserIDWithExternalAccountID, v3 authz.PermsSource) (r0 *database.SetPermissionsResult, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.SetRepoPerms(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		SetUserExternalAccountPermsFunc: &PermsStoreSetUserExternalAccountPermsFunc{
+			defaultHook: func(v0 context.Context, v1 authz.UserIDWithExternalAccountID, v2 []int32, v3 authz.PermsSource) (r0 *database.SetPermissionsResult, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.SetUserExternalAccountPerms(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		TransactFunc: &PermsStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.PermsStore, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.Transact(%v)", v0)
+				return
+			},
+		},
+		UserIDsWithNoPermsFunc: &PermsStoreUserIDsWithNoPermsFunc{
+			defaultHook: func(v0 context.Context) (r0 []int32, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.UserIDsWithNoPerms(%v)", v0)
+				return
+			},
+		},
+		UserIDsWithOldestPermsFunc: &PermsStoreUserIDsWithOldestPermsFunc{
+			defaultHook: func(v0 context.Context, v1 int, v2 time.Duration) (r0 map[int32]time.Time, r1 error) {
+				t.Errorf("unexpected call of MockPermsStore.UserIDsWithOldestPerms(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		WithFunc: &PermsStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.PermsStore) {
+				t.Errorf("unexpected call of MockPermsStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockPermsStore instance with PushHook or PushReturn which was not called.
+func (m *MockPermsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountReposWithNoPermsFunc.assertExpectations(t, "MockPermsStore.CountReposWithNoPerms")
+	m.CountReposWithStalePermsFunc.assertExpectations(t, "MockPermsStore.CountReposWithStalePerms")
+	m.CountUsersWithNoPermsFunc.assertExpectations(t, "MockPermsStore.CountUsersWithNoPerms")
+	m.CountUsersWithStalePermsFunc.assertExpectations(t, "MockPermsStore.CountUsersWithStalePerms")
+	m.DeleteAllUserPendingPermissionsFunc.assertExpectations(t, "MockPermsStore.DeleteAllUserPendingPermissions")
+	m.DeleteAllUserPermissionsFunc.assertExpectations(t, "MockPermsStore.DeleteAllUserPermissions")
+	m.DoneFunc.assertExpectations(t, "MockPermsStore.Done")
+	m.FetchReposByExternalAccountFunc.assertExpectations(t, "MockPermsStore.FetchReposByExternalAccount")
+	m.GetUserIDsByExternalAccountsFunc.assertExpectations(t, "MockPermsStore.GetUserIDsByExternalAccounts")
+	m.GrantPendingPermissionsFunc.assertExpectations(t, "MockPermsStore.GrantPendingPermissions")
+	m.HandleFunc.assertExpectations(t, "MockPermsStore.Handle")
+	m.IsRepoUnrestrictedFunc.assertExpectations(t, "MockPermsStore.IsRepoUnrestricted")
+	m.ListPendingUsersFunc.assertExpectations(t, "MockPermsStore.ListPendingUsers")
+	m.ListRepoPermissionsFunc.assertExpectations(t, "MockPermsStore.ListRepoPermissions")
+	m.ListUserPermissionsFunc.assertExpectations(t, "MockPermsStore.ListUserPermissions")
+	m.LoadRepoPermissionsFunc.assertExpectations(t, "MockPermsStore.LoadRepoPermissions")
+	m.LoadUserPendingPermissionsFunc.assertExpectations(t, "MockPermsStore.LoadUserPendingPermissions")
+	m.LoadUserPermissionsFunc.assertExpectations(t, "MockPermsStore.LoadUserPermissions")
+	m.MapUsersFunc.assertExpectations(t, "MockPermsStore.MapUsers")
+	m.MetricsFunc.assertExpectations(t, "MockPermsStore.Metrics")
+	m.RepoIDsWithNoPermsFunc.assertExpectations(t, "MockPermsStore.RepoIDsWithNoPerms")
+	m.ReposIDsWithOldestPermsFunc.assertExpectations(t, "MockPermsStore.ReposIDsWithOldestPerms")
+	m.SetRepoPendingPermissionsFunc.assertExpectations(t, "MockPermsStore.SetRepoPendingPermissions")
+	m.SetRepoPermissionsUnrestrictedFunc.assertExpectations(t, "MockPermsStore.SetRepoPermissionsUnrestricted")
+	m.SetRepoPermsFunc.assertExpectations(t, "MockPermsStore.SetRepoPerms")
+	m.SetUserExternalAccountPermsFunc.assertExpectations(t, "MockPermsStore.SetUserExternalAccountPerms")
+	m.TransactFunc.assertExpectations(t, "MockPermsStore.Transact")
+	m.UserIDsWithNoPermsFunc.assertExpectations(t, "MockPermsStore.UserIDsWithNoPerms")
+	m.UserIDsWithOldestPermsFunc.assertExpectations(t, "MockPermsStore.UserIDsWithOldestPerms")
+	m.WithFunc.assertExpectations(t, "MockPermsStore.With")
+}
+
 // NewMockPermsStoreFrom creates a new mock of the MockPermsStore interface.
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockPermsStoreFrom(i database.PermsStore) *MockPermsStore {
@@ -53127,6 +61722,18 @@ func (f *PermsStoreCountReposWithNoPermsFunc) History() []PermsStoreCountReposWi
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreCountReposWithNoPermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreCountReposWithNoPermsFuncCall is an object that describes an
 // invocation of method CountReposWithNoPerms on an instance of
 // MockPermsStore.
@@ -53235,6 +61842,18 @@ func (f *PermsStoreCountReposWithStalePermsFunc) History() []PermsStoreCountRepo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreCountReposWithStalePermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreCountReposWithStalePermsFuncCall is an object that describes an
 // invocation of method CountReposWithStalePerms on an instance of
 // MockPermsStore.
@@ -53346,6 +61965,18 @@ func (f *PermsStoreCountUsersWithNoPermsFunc) History() []PermsStoreCountUsersWi
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreCountUsersWithNoPermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreCountUsersWithNoPermsFuncCall is an object that describes an
 // invocation of method CountUsersWithNoPerms on an instance of
 // MockPermsStore.
@@ -53454,6 +62085,18 @@ func (f *PermsStoreCountUsersWithStalePermsFunc) History() []PermsStoreCountUser
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreCountUsersWithStalePermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreCountUsersWithStalePermsFuncCall is an object that describes an
 // invocation of method CountUsersWithStalePerms on an instance of
 // MockPermsStore.
@@ -53566,6 +62209,18 @@ func (f *PermsStoreDeleteAllUserPendingPermissionsFunc) History() []PermsStoreDe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreDeleteAllUserPendingPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreDeleteAllUserPendingPermissionsFuncCall is an object that
 // describes an invocation of method DeleteAllUserPendingPermissions on an
 // instance of MockPermsStore.
@@ -53674,6 +62329,18 @@ func (f *PermsStoreDeleteAllUserPermissionsFunc) History() []PermsStoreDeleteAll
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreDeleteAllUserPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreDeleteAllUserPermissionsFuncCall is an object that describes an
 // invocation of method DeleteAllUserPermissions on an instance of
 // MockPermsStore.
@@ -53779,6 +62446,18 @@ func (f *PermsStoreDoneFunc) History() []PermsStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreDoneFuncCall is an object that describes an invocation of
 // method Done on an instance of MockPermsStore.
 type PermsStoreDoneFuncCall struct {
@@ -53884,6 +62563,18 @@ func (f *PermsStoreFetchReposByExternalAccountFunc) History() []PermsStoreFetchR
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreFetchReposByExternalAccountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreFetchReposByExternalAccountFuncCall is an object that describes
 // an invocation of method FetchReposByExternalAccount on an instance of
 // MockPermsStore.
@@ -53996,6 +62687,18 @@ func (f *PermsStoreGetUserIDsByExternalAccountsFunc) History() []PermsStoreGetUs
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreGetUserIDsByExternalAccountsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreGetUserIDsByExternalAccountsFuncCall is an object that
 // describes an invocation of method GetUserIDsByExternalAccounts on an
 // instance of MockPermsStore.
@@ -54107,6 +62810,18 @@ func (f *PermsStoreGrantPendingPermissionsFunc) History() []PermsStoreGrantPendi
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreGrantPendingPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreGrantPendingPermissionsFuncCall is an object that describes an
 // invocation of method GrantPendingPermissions on an instance of
 // MockPermsStore.
@@ -54212,6 +62927,18 @@ func (f *PermsStoreHandleFunc) History() []PermsStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockPermsStore.
 type PermsStoreHandleFuncCall struct {
@@ -54312,6 +63039,18 @@ func (f *PermsStoreIsRepoUnrestrictedFunc) History() []PermsStoreIsRepoUnrestric
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreIsRepoUnrestrictedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreIsRepoUnrestrictedFuncCall is an object that describes an
 // invocation of method IsRepoUnrestricted on an instance of MockPermsStore.
 type PermsStoreIsRepoUnrestrictedFuncCall struct {
@@ -54420,6 +63159,18 @@ func (f *PermsStoreListPendingUsersFunc) History() []PermsStoreListPendingUsersF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreListPendingUsersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreListPendingUsersFuncCall is an object that describes an
 // invocation of method ListPendingUsers on an instance of MockPermsStore.
 type PermsStoreListPendingUsersFuncCall struct {
@@ -54532,6 +63283,18 @@ func (f *PermsStoreListRepoPermissionsFunc) History() []PermsStoreListRepoPermis
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreListRepoPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreListRepoPermissionsFuncCall is an object that describes an
 // invocation of method ListRepoPermissions on an instance of
 // MockPermsStore.
@@ -54645,6 +63408,18 @@ func (f *PermsStoreListUserPermissionsFunc) History() []PermsStoreListUserPermis
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreListUserPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreListUserPermissionsFuncCall is an object that describes an
 // invocation of method ListUserPermissions on an instance of
 // MockPermsStore.
@@ -54758,6 +63533,18 @@ func (f *PermsStoreLoadRepoPermissionsFunc) History() []PermsStoreLoadRepoPermis
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreLoadRepoPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreLoadRepoPermissionsFuncCall is an object that describes an
 // invocation of method LoadRepoPermissions on an instance of
 // MockPermsStore.
@@ -54870,6 +63657,18 @@ func (f *PermsStoreLoadUserPendingPermissionsFunc) History() []PermsStoreLoadUse
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreLoadUserPendingPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreLoadUserPendingPermissionsFuncCall is an object that describes
 // an invocation of method LoadUserPendingPermissions on an instance of
 // MockPermsStore.
@@ -54977,6 +63776,18 @@ func (f *PermsStoreLoadUserPermissionsFunc) History() []PermsStoreLoadUserPermis
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreLoadUserPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreLoadUserPermissionsFuncCall is an object that describes an
 // invocation of method LoadUserPermissions on an instance of
 // MockPermsStore.
@@ -55086,6 +63897,18 @@ func (f *PermsStoreMapUsersFunc) History() []PermsStoreMapUsersFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreMapUsersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreMapUsersFuncCall is an object that describes an invocation of
 // method MapUsers on an instance of MockPermsStore.
 type PermsStoreMapUsersFuncCall struct {
@@ -55197,6 +64020,18 @@ func (f *PermsStoreMetricsFunc) History() []PermsStoreMetricsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreMetricsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreMetricsFuncCall is an object that describes an invocation of
 // method Metrics on an instance of MockPermsStore.
 type PermsStoreMetricsFuncCall struct {
@@ -55306,6 +64141,18 @@ func (f *PermsStoreRepoIDsWithNoPermsFunc) History() []PermsStoreRepoIDsWithNoPe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreRepoIDsWithNoPermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreRepoIDsWithNoPermsFuncCall is an object that describes an
 // invocation of method RepoIDsWithNoPerms on an instance of MockPermsStore.
 type PermsStoreRepoIDsWithNoPermsFuncCall struct {
@@ -55413,6 +64260,18 @@ func (f *PermsStoreReposIDsWithOldestPermsFunc) History() []PermsStoreReposIDsWi
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreReposIDsWithOldestPermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreReposIDsWithOldestPermsFuncCall is an object that describes an
 // invocation of method ReposIDsWithOldestPerms on an instance of
 // MockPermsStore.
@@ -55527,6 +64386,18 @@ func (f *PermsStoreSetRepoPendingPermissionsFunc) History() []PermsStoreSetRepoP
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreSetRepoPendingPermissionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreSetRepoPendingPermissionsFuncCall is an object that describes
 // an invocation of method SetRepoPendingPermissions on an instance of
 // MockPermsStore.
@@ -55639,6 +64510,18 @@ func (f *PermsStoreSetRepoPermissionsUnrestrictedFunc) History() []PermsStoreSet
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreSetRepoPermissionsUnrestrictedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreSetRepoPermissionsUnrestrictedFuncCall is an object that
 // describes an invocation of method SetRepoPermissionsUnrestricted on an
 // instance of MockPermsStore.
@@ -55748,6 +64631,18 @@ func (f *PermsStoreSetRepoPermsFunc) History() []PermsStoreSetRepoPermsFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreSetRepoPermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreSetRepoPermsFuncCall is an object that describes an invocation
 // of method SetRepoPerms on an instance of MockPermsStore.
 type PermsStoreSetRepoPermsFuncCall struct {
@@ -55865,6 +64760,18 @@ func (f *PermsStoreSetUserExternalAccountPermsFunc) History() []PermsStoreSetUse
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreSetUserExternalAccountPermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreSetUserExternalAccountPermsFuncCall is an object that describes
 // an invocation of method SetUserExternalAccountPerms on an instance of
 // MockPermsStore.
@@ -55980,6 +64887,18 @@ func (f *PermsStoreTransactFunc) History() []PermsStoreTransactFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreTransactFuncCall is an object that describes an invocation of
 // method Transact on an instance of MockPermsStore.
 type PermsStoreTransactFuncCall struct {
@@ -56086,6 +65005,18 @@ func (f *PermsStoreUserIDsWithNoPermsFunc) History() []PermsStoreUserIDsWithNoPe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreUserIDsWithNoPermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreUserIDsWithNoPermsFuncCall is an object that describes an
 // invocation of method UserIDsWithNoPerms on an instance of MockPermsStore.
 type PermsStoreUserIDsWithNoPermsFuncCall struct {
@@ -56193,6 +65124,18 @@ func (f *PermsStoreUserIDsWithOldestPermsFunc) History() []PermsStoreUserIDsWith
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreUserIDsWithOldestPermsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreUserIDsWithOldestPermsFuncCall is an object that describes an
 // invocation of method UserIDsWithOldestPerms on an instance of
 // MockPermsStore.
@@ -56304,6 +65247,18 @@ func (f *PermsStoreWithFunc) History() []PermsStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PermsStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PermsStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockPermsStore.
 type PermsStoreWithFuncCall struct {
@@ -56440,6 +65395,70 @@ func NewStrictMockPhabricatorStore() *MockPhabricatorStore {
 	}
 }
 
+// NewStrictMockPhabricatorStoreWithT creates a new mock of the
+// PhabricatorStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockPhabricatorStoreWithT(t testing.TB) *MockPhabricatorStore {
+	return &MockPhabricatorStore{
+		CreateFunc: &PhabricatorStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 api.RepoName, v3 string) (r0 *types.PhabricatorRepo, r1 error) {
+				t.Errorf("unexpected call of MockPhabricatorStore.Create(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		CreateIfNotExistsFunc: &PhabricatorStoreCreateIfNotExistsFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 api.RepoName, v3 string) (r0 *types.PhabricatorRepo, r1 error) {
+				t.Errorf("unexpected call of MockPhabricatorStore.CreateIfNotExists(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		CreateOrUpdateFunc: &PhabricatorStoreCreateOrUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 api.RepoName, v3 string) (r0 *types.PhabricatorRepo, r1 error) {
+				t.Errorf("unexpected call of MockPhabricatorStore.CreateOrUpdate(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		GetByNameFunc: &PhabricatorStoreGetByNameFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName) (r0 *types.PhabricatorRepo, r1 error) {
+				t.Errorf("unexpected call of MockPhabricatorStore.GetByName(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &PhabricatorStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockPhabricatorStore.Handle()")
+				return
+			},
+		},
+		WithFunc: &PhabricatorStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.PhabricatorStore) {
+				t.Errorf("unexpected call of MockPhabricatorStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &PhabricatorStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.PhabricatorStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockPhabricatorStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockPhabricatorStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockPhabricatorStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CreateFunc.assertExpectations(t, "MockPhabricatorStore.Create")
+	m.CreateIfNotExistsFunc.assertExpectations(t, "MockPhabricatorStore.CreateIfNotExists")
+	m.CreateOrUpdateFunc.assertExpectations(t, "MockPhabricatorStore.CreateOrUpdate")
+	m.GetByNameFunc.assertExpectations(t, "MockPhabricatorStore.GetByName")
+	m.HandleFunc.assertExpectations(t, "MockPhabricatorStore.Handle")
+	m.WithFunc.assertExpectations(t, "MockPhabricatorStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockPhabricatorStore.WithTransact")
+}
+
 // NewMockPhabricatorStoreFrom creates a new mock of the
 // MockPhabricatorStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -56548,6 +65567,18 @@ func (f *PhabricatorStoreCreateFunc) History() []PhabricatorStoreCreateFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PhabricatorStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PhabricatorStoreCreateFuncCall is an object that describes an invocation
 // of method Create on an instance of MockPhabricatorStore.
 type PhabricatorStoreCreateFuncCall struct {
@@ -56664,6 +65695,18 @@ func (f *PhabricatorStoreCreateIfNotExistsFunc) History() []PhabricatorStoreCrea
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PhabricatorStoreCreateIfNotExistsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PhabricatorStoreCreateIfNotExistsFuncCall is an object that describes an
 // invocation of method CreateIfNotExists on an instance of
 // MockPhabricatorStore.
@@ -56780,6 +65823,18 @@ func (f *PhabricatorStoreCreateOrUpdateFunc) History() []PhabricatorStoreCreateO
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PhabricatorStoreCreateOrUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PhabricatorStoreCreateOrUpdateFuncCall is an object that describes an
 // invocation of method CreateOrUpdate on an instance of
 // MockPhabricatorStore.
@@ -56895,6 +65950,18 @@ func (f *PhabricatorStoreGetByNameFunc) History() []PhabricatorStoreGetByNameFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PhabricatorStoreGetByNameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PhabricatorStoreGetByNameFuncCall is an object that describes an
 // invocation of method GetByName on an instance of MockPhabricatorStore.
 type PhabricatorStoreGetByNameFuncCall struct {
@@ -57003,6 +66070,18 @@ func (f *PhabricatorStoreHandleFunc) History() []PhabricatorStoreHandleFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PhabricatorStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PhabricatorStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockPhabricatorStore.
 type PhabricatorStoreHandleFuncCall struct {
@@ -57102,6 +66181,18 @@ func (f *PhabricatorStoreWithFunc) History() []PhabricatorStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PhabricatorStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PhabricatorStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockPhabricatorStore.
 type PhabricatorStoreWithFuncCall struct {
@@ -57205,6 +66296,18 @@ func (f *PhabricatorStoreWithTransactFunc) History() []PhabricatorStoreWithTrans
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *PhabricatorStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // PhabricatorStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockPhabricatorStore.
 type PhabricatorStoreWithTransactFuncCall struct {
@@ -57306,6 +66409,49 @@ func NewStrictMockRecentContributionSignalStore() *MockRecentContributionSignalS
 	}
 }
 
+// NewStrictMockRecentContributionSignalStoreWithT creates a new mock of the
+// RecentContributionSignalStore interface. All methods fail t with their
+// arguments and return zero values for all results, unless overwritten.
+func NewStrictMockRecentContributionSignalStoreWithT(t testing.TB) *MockRecentContributionSignalStore {
+	return &MockRecentContributionSignalStore{
+		AddCommitFunc: &RecentContributionSignalStoreAddCommitFunc{
+			defaultHook: func(v0 context.Context, v1 database.Commit) (r0 error) {
+				t.Errorf("unexpected call of MockRecentContributionSignalStore.AddCommit(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ClearSignalsFunc: &RecentContributionSignalStoreClearSignalsFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 error) {
+				t.Errorf("unexpected call of MockRecentContributionSignalStore.ClearSignals(%v, %v)", v0, v1)
+				return
+			},
+		},
+		FindRecentAuthorsFunc: &RecentContributionSignalStoreFindRecentAuthorsFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID, v2 string) (r0 []database.RecentContributorSummary, r1 error) {
+				t.Errorf("unexpected call of MockRecentContributionSignalStore.FindRecentAuthors(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		WithTransactFunc: &RecentContributionSignalStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(store database.RecentContributionSignalStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockRecentContributionSignalStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockRecentContributionSignalStore instance with PushHook or PushReturn which
+// was not called.
+func (m *MockRecentContributionSignalStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.AddCommitFunc.assertExpectations(t, "MockRecentContributionSignalStore.AddCommit")
+	m.ClearSignalsFunc.assertExpectations(t, "MockRecentContributionSignalStore.ClearSignals")
+	m.FindRecentAuthorsFunc.assertExpectations(t, "MockRecentContributionSignalStore.FindRecentAuthors")
+	m.WithTransactFunc.assertExpectations(t, "MockRecentContributionSignalStore.WithTransact")
+}
+
 // NewMockRecentContributionSignalStoreFrom creates a new mock of the
 // MockRecentContributionSignalStore interface. All methods delegate to the
 // given implementation, unless overwritten.
@@ -57408,6 +66554,18 @@ func (f *RecentContributionSignalStoreAddCommitFunc) History() []RecentContribut
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RecentContributionSignalStoreAddCommitFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RecentContributionSignalStoreAddCommitFuncCall is an object that
 // describes an invocation of method AddCommit on an instance of
 // MockRecentContributionSignalStore.
@@ -57517,6 +66675,18 @@ func (f *RecentContributionSignalStoreClearSignalsFunc) History() []RecentContri
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RecentContributionSignalStoreClearSignalsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RecentContributionSignalStoreClearSignalsFuncCall is an object that
 // describes an invocation of method ClearSignals on an instance of
 // MockRecentContributionSignalStore.
@@ -57626,6 +66796,18 @@ func (f *RecentContributionSignalStoreFindRecentAuthorsFunc) History() []RecentC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RecentContributionSignalStoreFindRecentAuthorsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RecentContributionSignalStoreFindRecentAuthorsFuncCall is an object that
 // describes an invocation of method FindRecentAuthors on an instance of
 // MockRecentContributionSignalStore.
@@ -57741,6 +66923,18 @@ func (f *RecentContributionSignalStoreWithTransactFunc) History() []RecentContri
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RecentContributionSignalStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RecentContributionSignalStoreWithTransactFuncCall is an object that
 // describes an invocation of method WithTransact on an instance of
 // MockRecentContributionSignalStore.
@@ -57843,6 +67037,49 @@ func NewStrictMockRecentViewSignalStore() *MockRecentViewSignalStore {
 	}
 }
 
+// NewStrictMockRecentViewSignalStoreWithT creates a new mock of the
+// RecentViewSignalStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockRecentViewSignalStoreWithT(t testing.TB) *MockRecentViewSignalStore {
+	return &MockRecentViewSignalStore{
+		BuildAggregateFromEventsFunc: &RecentViewSignalStoreBuildAggregateFromEventsFunc{
+			defaultHook: func(v0 context.Context, v1 []*database.Event) (r0 error) {
+				t.Errorf("unexpected call of MockRecentViewSignalStore.BuildAggregateFromEvents(%v, %v)", v0, v1)
+				return
+			},
+		},
+		InsertFunc: &RecentViewSignalStoreInsertFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int, v3 int) (r0 error) {
+				t.Errorf("unexpected call of MockRecentViewSignalStore.Insert(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		InsertPathsFunc: &RecentViewSignalStoreInsertPathsFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 map[int]int) (r0 error) {
+				t.Errorf("unexpected call of MockRecentViewSignalStore.InsertPaths(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		ListFunc: &RecentViewSignalStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListRecentViewSignalOpts) (r0 []database.RecentViewSummary, r1 error) {
+				t.Errorf("unexpected call of MockRecentViewSignalStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockRecentViewSignalStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockRecentViewSignalStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.BuildAggregateFromEventsFunc.assertExpectations(t, "MockRecentViewSignalStore.BuildAggregateFromEvents")
+	m.InsertFunc.assertExpectations(t, "MockRecentViewSignalStore.Insert")
+	m.InsertPathsFunc.assertExpectations(t, "MockRecentViewSignalStore.InsertPaths")
+	m.ListFunc.assertExpectations(t, "MockRecentViewSignalStore.List")
+}
+
 // NewMockRecentViewSignalStoreFrom creates a new mock of the
 // MockRecentViewSignalStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -57945,6 +67182,18 @@ func (f *RecentViewSignalStoreBuildAggregateFromEventsFunc) History() []RecentVi
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RecentViewSignalStoreBuildAggregateFromEventsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RecentViewSignalStoreBuildAggregateFromEventsFuncCall is an object that
 // describes an invocation of method BuildAggregateFromEvents on an instance
 // of MockRecentViewSignalStore.
@@ -58051,6 +67300,18 @@ func (f *RecentViewSignalStoreInsertFunc) History() []RecentViewSignalStoreInser
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RecentViewSignalStoreInsertFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RecentViewSignalStoreInsertFuncCall is an object that describes an
 // invocation of method Insert on an instance of MockRecentViewSignalStore.
 type RecentViewSignalStoreInsertFuncCall struct {
@@ -58164,6 +67425,18 @@ func (f *RecentViewSignalStoreInsertPathsFunc) History() []RecentViewSignalStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RecentViewSignalStoreInsertPathsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RecentViewSignalStoreInsertPathsFuncCall is an object that describes an
 // invocation of method InsertPaths on an instance of
 // MockRecentViewSignalStore.
@@ -58273,6 +67546,18 @@ func (f *RecentViewSignalStoreListFunc) History() []RecentViewSignalStoreListFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RecentViewSignalStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RecentViewSignalStoreListFuncCall is an object that describes an
 // invocation of method List on an instance of MockRecentViewSignalStore.
 type RecentViewSignalStoreListFuncCall struct {
@@ -58365,6 +67650,42 @@ func NewStrictMockRepoCommitsChangelistsStore() *MockRepoCommitsChangelistsStore
 	}
 }
 
+// NewStrictMockRepoCommitsChangelistsStoreWithT creates a new mock of the
+// RepoCommitsChangelistsStore interface. All methods fail t with their
+// arguments and return zero values for all results, unless overwritten.
+func NewStrictMockRepoCommitsChangelistsStoreWithT(t testing.TB) *MockRepoCommitsChangelistsStore {
+	return &MockRepoCommitsChangelistsStore{
+		BatchInsertCommitSHAsWithPerforceChangelistIDFunc: &RepoCommitsChangelistsStoreBatchInsertCommitSHAsWithPerforceChangelistIDFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID, v2 []types.PerforceChangelist) (r0 error) {
+				t.Errorf("unexpected call of MockRepoCommitsChangelistsStore.BatchInsertCommitSHAsWithPerforceChangelistID(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		GetLatestForRepoFunc: &RepoCommitsChangelistsStoreGetLatestForRepoFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 *types.RepoCommit, r1 error) {
+				t.Errorf("unexpected call of MockRepoCommitsChangelistsStore.GetLatestForRepo(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetRepoCommitChangelistFunc: &RepoCommitsChangelistsStoreGetRepoCommitChangelistFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID, v2 int64) (r0 *types.RepoCommit, r1 error) {
+				t.Errorf("unexpected call of MockRepoCommitsChangelistsStore.GetRepoCommitChangelist(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockRepoCommitsChangelistsStore instance with PushHook or PushReturn which
+// was not called.
+func (m *MockRepoCommitsChangelistsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.BatchInsertCommitSHAsWithPerforceChangelistIDFunc.assertExpectations(t, "MockRepoCommitsChangelistsStore.BatchInsertCommitSHAsWithPerforceChangelistID")
+	m.GetLatestForRepoFunc.assertExpectations(t, "MockRepoCommitsChangelistsStore.GetLatestForRepo")
+	m.GetRepoCommitChangelistFunc.assertExpectations(t, "MockRepoCommitsChangelistsStore.GetRepoCommitChangelist")
+}
+
 // NewMockRepoCommitsChangelistsStoreFrom creates a new mock of the
 // MockRepoCommitsChangelistsStore interface. All methods delegate to the
 // given implementation, unless overwritten.
@@ -58467,6 +67788,18 @@ func (f *RepoCommitsChangelistsStoreBatchInsertCommitSHAsWithPerforceChangelistI
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoCommitsChangelistsStoreBatchInsertCommitSHAsWithPerforceChangelistIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoCommitsChangelistsStoreBatchInsertCommitSHAsWithPerforceChangelistIDFuncCall
 // is an object that describes an invocation of method
 // BatchInsertCommitSHAsWithPerforceChangelistID on an instance of
@@ -58580,6 +67913,18 @@ func (f *RepoCommitsChangelistsStoreGetLatestForRepoFunc) History() []RepoCommit
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoCommitsChangelistsStoreGetLatestForRepoFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoCommitsChangelistsStoreGetLatestForRepoFuncCall is an object that
 // describes an invocation of method GetLatestForRepo on an instance of
 // MockRepoCommitsChangelistsStore.
@@ -58693,6 +68038,18 @@ func (f *RepoCommitsChangelistsStoreGetRepoCommitChangelistFunc) History() []Rep
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoCommitsChangelistsStoreGetRepoCommitChangelistFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoCommitsChangelistsStoreGetRepoCommitChangelistFuncCall is an object
 // that describes an invocation of method GetRepoCommitChangelist on an
 // instance of MockRepoCommitsChangelistsStore.
@@ -58772,6 +68129,34 @@ func NewStrictMockRepoPathStore() *MockRepoPathStore {
 	}
 }
 
+// NewStrictMockRepoPathStoreWithT creates a new mock of the RepoPathStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockRepoPathStoreWithT(t testing.TB) *MockRepoPathStore {
+	return &MockRepoPathStore{
+		AggregateFileCountFunc: &RepoPathStoreAggregateFileCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.TreeLocationOpts) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockRepoPathStore.AggregateFileCount(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateFileCountsFunc: &RepoPathStoreUpdateFileCountsFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID, v2 database.RepoTreeCounts, v3 time.Time) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockRepoPathStore.UpdateFileCounts(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockRepoPathStore instance with PushHook or PushReturn which was not called.
+func (m *MockRepoPathStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.AggregateFileCountFunc.assertExpectations(t, "MockRepoPathStore.AggregateFileCount")
+	m.UpdateFileCountsFunc.assertExpectations(t, "MockRepoPathStore.UpdateFileCounts")
+}
+
 // NewMockRepoPathStoreFrom creates a new mock of the MockRepoPathStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -58867,6 +68252,18 @@ func (f *RepoPathStoreAggregateFileCountFunc) History() []RepoPathStoreAggregate
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoPathStoreAggregateFileCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoPathStoreAggregateFileCountFuncCall is an object that describes an
 // invocation of method AggregateFileCount on an instance of
 // MockRepoPathStore.
@@ -58977,6 +68374,18 @@ func (f *RepoPathStoreUpdateFileCountsFunc) History() []RepoPathStoreUpdateFileC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoPathStoreUpdateFileCountsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoPathStoreUpdateFileCountsFuncCall is an object that describes an
 // invocation of method UpdateFileCounts on an instance of
 // MockRepoPathStore.
@@ -59143,6 +68552,77 @@ func NewStrictMockRepoStatisticsStore() *MockRepoStatisticsStore {
 	}
 }
 
+// NewStrictMockRepoStatisticsStoreWithT creates a new mock of the
+// RepoStatisticsStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockRepoStatisticsStoreWithT(t testing.TB) *MockRepoStatisticsStore {
+	return &MockRepoStatisticsStore{
+		CompactGitserverReposStatisticsFunc: &RepoStatisticsStoreCompactGitserverReposStatisticsFunc{
+			defaultHook: func(v0 context.Context) (r0 error) {
+				t.Errorf("unexpected call of MockRepoStatisticsStore.CompactGitserverReposStatistics(%v)", v0)
+				return
+			},
+		},
+		CompactRepoStatisticsFunc: &RepoStatisticsStoreCompactRepoStatisticsFunc{
+			defaultHook: func(v0 context.Context) (r0 error) {
+				t.Errorf("unexpected call of MockRepoStatisticsStore.CompactRepoStatistics(%v)", v0)
+				return
+			},
+		},
+		DeleteAndRecreateStatisticsFunc: &RepoStatisticsStoreDeleteAndRecreateStatisticsFunc{
+			defaultHook: func(v0 context.Context) (r0 error) {
+				t.Errorf("unexpected call of MockRepoStatisticsStore.DeleteAndRecreateStatistics(%v)", v0)
+				return
+			},
+		},
+		GetGitserverReposStatisticsFunc: &RepoStatisticsStoreGetGitserverReposStatisticsFunc{
+			defaultHook: func(v0 context.Context) (r0 []database.GitserverReposStatistic, r1 error) {
+				t.Errorf("unexpected call of MockRepoStatisticsStore.GetGitserverReposStatistics(%v)", v0)
+				return
+			},
+		},
+		GetRepoStatisticsFunc: &RepoStatisticsStoreGetRepoStatisticsFunc{
+			defaultHook: func(v0 context.Context) (r0 database.RepoStatistics, r1 error) {
+				t.Errorf("unexpected call of MockRepoStatisticsStore.GetRepoStatistics(%v)", v0)
+				return
+			},
+		},
+		HandleFunc: &RepoStatisticsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockRepoStatisticsStore.Handle()")
+				return
+			},
+		},
+		WithFunc: &RepoStatisticsStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.RepoStatisticsStore) {
+				t.Errorf("unexpected call of MockRepoStatisticsStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &RepoStatisticsStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.RepoStatisticsStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockRepoStatisticsStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockRepoStatisticsStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockRepoStatisticsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CompactGitserverReposStatisticsFunc.assertExpectations(t, "MockRepoStatisticsStore.CompactGitserverReposStatistics")
+	m.CompactRepoStatisticsFunc.assertExpectations(t, "MockRepoStatisticsStore.CompactRepoStatistics")
+	m.DeleteAndRecreateStatisticsFunc.assertExpectations(t, "MockRepoStatisticsStore.DeleteAndRecreateStatistics")
+	m.GetGitserverReposStatisticsFunc.assertExpectations(t, "MockRepoStatisticsStore.GetGitserverReposStatistics")
+	m.GetRepoStatisticsFunc.assertExpectations(t, "MockRepoStatisticsStore.GetRepoStatistics")
+	m.HandleFunc.assertExpectations(t, "MockRepoStatisticsStore.Handle")
+	m.WithFunc.assertExpectations(t, "MockRepoStatisticsStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockRepoStatisticsStore.WithTransact")
+}
+
 // NewMockRepoStatisticsStoreFrom creates a new mock of the
 // MockRepoStatisticsStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -59257,6 +68737,18 @@ func (f *RepoStatisticsStoreCompactGitserverReposStatisticsFunc) History() []Rep
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStatisticsStoreCompactGitserverReposStatisticsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStatisticsStoreCompactGitserverReposStatisticsFuncCall is an object
 // that describes an invocation of method CompactGitserverReposStatistics on
 // an instance of MockRepoStatisticsStore.
@@ -59363,6 +68855,18 @@ func (f *RepoStatisticsStoreCompactRepoStatisticsFunc) History() []RepoStatistic
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStatisticsStoreCompactRepoStatisticsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStatisticsStoreCompactRepoStatisticsFuncCall is an object that
 // describes an invocation of method CompactRepoStatistics on an instance of
 // MockRepoStatisticsStore.
@@ -59469,6 +68973,18 @@ func (f *RepoStatisticsStoreDeleteAndRecreateStatisticsFunc) History() []RepoSta
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStatisticsStoreDeleteAndRecreateStatisticsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStatisticsStoreDeleteAndRecreateStatisticsFuncCall is an object that
 // describes an invocation of method DeleteAndRecreateStatistics on an
 // instance of MockRepoStatisticsStore.
@@ -59575,6 +69091,18 @@ func (f *RepoStatisticsStoreGetGitserverReposStatisticsFunc) History() []RepoSta
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStatisticsStoreGetGitserverReposStatisticsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStatisticsStoreGetGitserverReposStatisticsFuncCall is an object that
 // describes an invocation of method GetGitserverReposStatistics on an
 // instance of MockRepoStatisticsStore.
@@ -59684,6 +69212,18 @@ func (f *RepoStatisticsStoreGetRepoStatisticsFunc) History() []RepoStatisticsSto
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStatisticsStoreGetRepoStatisticsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStatisticsStoreGetRepoStatisticsFuncCall is an object that describes
 // an invocation of method GetRepoStatistics on an instance of
 // MockRepoStatisticsStore.
@@ -59790,6 +69330,18 @@ func (f *RepoStatisticsStoreHandleFunc) History() []RepoStatisticsStoreHandleFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStatisticsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStatisticsStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockRepoStatisticsStore.
 type RepoStatisticsStoreHandleFuncCall struct {
@@ -59889,6 +69441,18 @@ func (f *RepoStatisticsStoreWithFunc) History() []RepoStatisticsStoreWithFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStatisticsStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStatisticsStoreWithFuncCall is an object that describes an invocation
 // of method With on an instance of MockRepoStatisticsStore.
 type RepoStatisticsStoreWithFuncCall struct {
@@ -59993,6 +69557,18 @@ func (f *RepoStatisticsStoreWithTransactFunc) History() []RepoStatisticsStoreWit
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStatisticsStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStatisticsStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of
 // MockRepoStatisticsStore.
@@ -60328,6 +69904,174 @@ func NewStrictMockRepoStore() *MockRepoStore {
 	}
 }
 
+// NewStrictMockRepoStoreWithT creates a new mock of the RepoStore interface.
+// All methods fail t with their arguments and return zero values for all
+// results, unless overwritten.
+func NewStrictMockRepoStoreWithT(t testing.TB) *MockRepoStore {
+	return &MockRepoStore{
+		CountFunc: &RepoStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.ReposListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &RepoStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 ...*types.Repo) (r0 error) {
+				t.Errorf("unexpected call of MockRepoStore.Create(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteFunc: &RepoStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 ...api.RepoID) (r0 error) {
+				t.Errorf("unexpected call of MockRepoStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &RepoStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockRepoStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetFunc: &RepoStoreGetFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 *types.Repo, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.Get(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByHashedNameFunc: &RepoStoreGetByHashedNameFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoHashedName) (r0 *types.Repo, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.GetByHashedName(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDsFunc: &RepoStoreGetByIDsFunc{
+			defaultHook: func(v0 context.Context, v1 ...api.RepoID) (r0 []*types.Repo, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.GetByIDs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByNameFunc: &RepoStoreGetByNameFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName) (r0 *types.Repo, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.GetByName(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetFirstRepoByCloneURLFunc: &RepoStoreGetFirstRepoByCloneURLFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *types.Repo, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.GetFirstRepoByCloneURL(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetFirstRepoNameByCloneURLFunc: &RepoStoreGetFirstRepoNameByCloneURLFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 api.RepoName, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.GetFirstRepoNameByCloneURL(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetRepoDescriptionsByIDsFunc: &RepoStoreGetRepoDescriptionsByIDsFunc{
+			defaultHook: func(v0 context.Context, v1 ...api.RepoID) (r0 map[api.RepoID]string, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.GetRepoDescriptionsByIDs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetReposSetByIDsFunc: &RepoStoreGetReposSetByIDsFunc{
+			defaultHook: func(v0 context.Context, v1 ...api.RepoID) (r0 map[api.RepoID]*types.Repo, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.GetReposSetByIDs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &RepoStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockRepoStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &RepoStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.ReposListOptions) (r0 []*types.Repo, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListMinimalReposFunc: &RepoStoreListMinimalReposFunc{
+			defaultHook: func(v0 context.Context, v1 database.ReposListOptions) (r0 []types.MinimalRepo, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.ListMinimalRepos(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListSourcegraphDotComIndexableReposFunc: &RepoStoreListSourcegraphDotComIndexableReposFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListSourcegraphDotComIndexableReposOptions) (r0 []types.MinimalRepo, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.ListSourcegraphDotComIndexableRepos(%v, %v)", v0, v1)
+				return
+			},
+		},
+		MetadataFunc: &RepoStoreMetadataFunc{
+			defaultHook: func(v0 context.Context, v1 ...api.RepoID) (r0 []*types.SearchedRepo, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.Metadata(%v, %v)", v0, v1)
+				return
+			},
+		},
+		QueryFunc: &RepoStoreQueryFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 *sql.Rows, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.Query(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RepoEmbeddingExistsFunc: &RepoStoreRepoEmbeddingExistsFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.RepoEmbeddingExists(%v, %v)", v0, v1)
+				return
+			},
+		},
+		StreamMinimalReposFunc: &RepoStoreStreamMinimalReposFunc{
+			defaultHook: func(v0 context.Context, v1 database.ReposListOptions, v2 func(*types.MinimalRepo)) (r0 error) {
+				t.Errorf("unexpected call of MockRepoStore.StreamMinimalRepos(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		TransactFunc: &RepoStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.RepoStore, r1 error) {
+				t.Errorf("unexpected call of MockRepoStore.Transact(%v)", v0)
+				return
+			},
+		},
+		WithFunc: &RepoStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.RepoStore) {
+				t.Errorf("unexpected call of MockRepoStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockRepoStore instance with PushHook or PushReturn which was not called.
+func (m *MockRepoStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockRepoStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockRepoStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockRepoStore.Delete")
+	m.DoneFunc.assertExpectations(t, "MockRepoStore.Done")
+	m.GetFunc.assertExpectations(t, "MockRepoStore.Get")
+	m.GetByHashedNameFunc.assertExpectations(t, "MockRepoStore.GetByHashedName")
+	m.GetByIDsFunc.assertExpectations(t, "MockRepoStore.GetByIDs")
+	m.GetByNameFunc.assertExpectations(t, "MockRepoStore.GetByName")
+	m.GetFirstRepoByCloneURLFunc.assertExpectations(t, "MockRepoStore.GetFirstRepoByCloneURL")
+	m.GetFirstRepoNameByCloneURLFunc.assertExpectations(t, "MockRepoStore.GetFirstRepoNameByCloneURL")
+	m.GetRepoDescriptionsByIDsFunc.assertExpectations(t, "MockRepoStore.GetRepoDescriptionsByIDs")
+	m.GetReposSetByIDsFunc.assertExpectations(t, "MockRepoStore.GetReposSetByIDs")
+	m.HandleFunc.assertExpectations(t, "MockRepoStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockRepoStore.List")
+	m.ListMinimalReposFunc.assertExpectations(t, "MockRepoStore.ListMinimalRepos")
+	m.ListSourcegraphDotComIndexableReposFunc.assertExpectations(t, "MockRepoStore.ListSourcegraphDotComIndexableRepos")
+	m.MetadataFunc.assertExpectations(t, "MockRepoStore.Metadata")
+	m.QueryFunc.assertExpectations(t, "MockRepoStore.Query")
+	m.RepoEmbeddingExistsFunc.assertExpectations(t, "MockRepoStore.RepoEmbeddingExists")
+	m.StreamMinimalReposFunc.assertExpectations(t, "MockRepoStore.StreamMinimalRepos")
+	m.TransactFunc.assertExpectations(t, "MockRepoStore.Transact")
+	m.WithFunc.assertExpectations(t, "MockRepoStore.With")
+}
+
 // NewMockRepoStoreFrom creates a new mock of the MockRepoStore interface.
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockRepoStoreFrom(i database.RepoStore) *MockRepoStore {
@@ -60479,6 +70223,18 @@ func (f *RepoStoreCountFunc) History() []RepoStoreCountFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreCountFuncCall is an object that describes an invocation of
 // method Count on an instance of MockRepoStore.
 type RepoStoreCountFuncCall struct {
@@ -60586,6 +70342,18 @@ func (f *RepoStoreCreateFunc) History() []RepoStoreCreateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreCreateFuncCall is an object that describes an invocation of
 // method Create on an instance of MockRepoStore.
 type RepoStoreCreateFuncCall struct {
@@ -60697,6 +70465,18 @@ func (f *RepoStoreDeleteFunc) History() []RepoStoreDeleteFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreDeleteFuncCall is an object that describes an invocation of
 // method Delete on an instance of MockRepoStore.
 type RepoStoreDeleteFuncCall struct {
@@ -60808,6 +70588,18 @@ func (f *RepoStoreDoneFunc) History() []RepoStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreDoneFuncCall is an object that describes an invocation of method
 // Done on an instance of MockRepoStore.
 type RepoStoreDoneFuncCall struct {
@@ -60909,6 +70701,18 @@ func (f *RepoStoreGetFunc) History() []RepoStoreGetFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreGetFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreGetFuncCall is an object that describes an invocation of method
 // Get on an instance of MockRepoStore.
 type RepoStoreGetFuncCall struct {
@@ -61017,6 +70821,18 @@ func (f *RepoStoreGetByHashedNameFunc) History() []RepoStoreGetByHashedNameFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreGetByHashedNameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreGetByHashedNameFuncCall is an object that describes an
 // invocation of method GetByHashedName on an instance of MockRepoStore.
 type RepoStoreGetByHashedNameFuncCall struct {
@@ -61124,6 +70940,18 @@ func (f *RepoStoreGetByIDsFunc) History() []RepoStoreGetByIDsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreGetByIDsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreGetByIDsFuncCall is an object that describes an invocation of
 // method GetByIDs on an instance of MockRepoStore.
 type RepoStoreGetByIDsFuncCall struct {
@@ -61238,6 +71066,18 @@ func (f *RepoStoreGetByNameFunc) History() []RepoStoreGetByNameFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreGetByNameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreGetByNameFuncCall is an object that describes an invocation of
 // method GetByName on an instance of MockRepoStore.
 type RepoStoreGetByNameFuncCall struct {
@@ -61348,6 +71188,18 @@ func (f *RepoStoreGetFirstRepoByCloneURLFunc) History() []RepoStoreGetFirstRepoB
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreGetFirstRepoByCloneURLFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreGetFirstRepoByCloneURLFuncCall is an object that describes an
 // invocation of method GetFirstRepoByCloneURL on an instance of
 // MockRepoStore.
@@ -61459,6 +71311,18 @@ func (f *RepoStoreGetFirstRepoNameByCloneURLFunc) History() []RepoStoreGetFirstR
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreGetFirstRepoNameByCloneURLFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreGetFirstRepoNameByCloneURLFuncCall is an object that describes
 // an invocation of method GetFirstRepoNameByCloneURL on an instance of
 // MockRepoStore.
@@ -61570,6 +71434,18 @@ func (f *RepoStoreGetRepoDescriptionsByIDsFunc) History() []RepoStoreGetRepoDesc
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreGetRepoDescriptionsByIDsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreGetRepoDescriptionsByIDsFuncCall is an object that describes an
 // invocation of method GetRepoDescriptionsByIDs on an instance of
 // MockRepoStore.
@@ -61686,6 +71562,18 @@ func (f *RepoStoreGetReposSetByIDsFunc) History() []RepoStoreGetReposSetByIDsFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreGetReposSetByIDsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreGetReposSetByIDsFuncCall is an object that describes an
 // invocation of method GetReposSetByIDs on an instance of MockRepoStore.
 type RepoStoreGetReposSetByIDsFuncCall struct {
@@ -61800,6 +71688,18 @@ func (f *RepoStoreHandleFunc) History() []RepoStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockRepoStore.
 type RepoStoreHandleFuncCall struct {
@@ -61898,6 +71798,18 @@ func (f *RepoStoreListFunc) History() []RepoStoreListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreListFuncCall is an object that describes an invocation of method
 // List on an instance of MockRepoStore.
 type RepoStoreListFuncCall struct {
@@ -62006,6 +71918,18 @@ func (f *RepoStoreListMinimalReposFunc) History() []RepoStoreListMinimalReposFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreListMinimalReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreListMinimalReposFuncCall is an object that describes an
 // invocation of method ListMinimalRepos on an instance of MockRepoStore.
 type RepoStoreListMinimalReposFuncCall struct {
@@ -62118,6 +72042,18 @@ func (f *RepoStoreListSourcegraphDotComIndexableReposFunc) History() []RepoStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreListSourcegraphDotComIndexableReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreListSourcegraphDotComIndexableReposFuncCall is an object that
 // describes an invocation of method ListSourcegraphDotComIndexableRepos on
 // an instance of MockRepoStore.
@@ -62226,6 +72162,18 @@ func (f *RepoStoreMetadataFunc) History() []RepoStoreMetadataFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreMetadataFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreMetadataFuncCall is an object that describes an invocation of
 // method Metadata on an instance of MockRepoStore.
 type RepoStoreMetadataFuncCall struct {
@@ -62340,6 +72288,18 @@ func (f *RepoStoreQueryFunc) History() []RepoStoreQueryFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreQueryFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreQueryFuncCall is an object that describes an invocation of
 // method Query on an instance of MockRepoStore.
 type RepoStoreQueryFuncCall struct {
@@ -62449,6 +72409,18 @@ func (f *RepoStoreRepoEmbeddingExistsFunc) History() []RepoStoreRepoEmbeddingExi
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreRepoEmbeddingExistsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreRepoEmbeddingExistsFuncCall is an object that describes an
 // invocation of method RepoEmbeddingExists on an instance of MockRepoStore.
 type RepoStoreRepoEmbeddingExistsFuncCall struct {
@@ -62558,6 +72530,18 @@ func (f *RepoStoreStreamMinimalReposFunc) History() []RepoStoreStreamMinimalRepo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreStreamMinimalReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreStreamMinimalReposFuncCall is an object that describes an
 // invocation of method StreamMinimalRepos on an instance of MockRepoStore.
 type RepoStoreStreamMinimalReposFuncCall struct {
@@ -62665,6 +72649,18 @@ func (f *RepoStoreTransactFunc) History() []RepoStoreTransactFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreTransactFuncCall is an object that describes an invocation of
 // method Transact on an instance of MockRepoStore.
 type RepoStoreTransactFuncCall struct {
@@ -62769,6 +72765,18 @@ func (f *RepoStoreWithFunc) History() []RepoStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RepoStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RepoStoreWithFuncCall is an object that describes an invocation of method
 // With on an instance of MockRepoStore.
 type RepoStoreWithFuncCall struct {
@@ -62988,6 +72996,112 @@ func NewStrictMockRolePermissionStore() *MockRolePermissionStore {
 	}
 }
 
+// NewStrictMockRolePermissionStoreWithT creates a new mock of the
+// RolePermissionStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockRolePermissionStoreWithT(t testing.TB) *MockRolePermissionStore {
+	return &MockRolePermissionStore{
+		AssignFunc: &RolePermissionStoreAssignFunc{
+			defaultHook: func(v0 context.Context, v1 database.AssignRolePermissionOpts) (r0 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.Assign(%v, %v)", v0, v1)
+				return
+			},
+		},
+		AssignToSystemRoleFunc: &RolePermissionStoreAssignToSystemRoleFunc{
+			defaultHook: func(v0 context.Context, v1 database.AssignToSystemRoleOpts) (r0 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.AssignToSystemRole(%v, %v)", v0, v1)
+				return
+			},
+		},
+		BulkAssignPermissionsToRoleFunc: &RolePermissionStoreBulkAssignPermissionsToRoleFunc{
+			defaultHook: func(v0 context.Context, v1 database.BulkAssignPermissionsToRoleOpts) (r0 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.BulkAssignPermissionsToRole(%v, %v)", v0, v1)
+				return
+			},
+		},
+		BulkAssignPermissionsToSystemRolesFunc: &RolePermissionStoreBulkAssignPermissionsToSystemRolesFunc{
+			defaultHook: func(v0 context.Context, v1 database.BulkAssignPermissionsToSystemRolesOpts) (r0 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.BulkAssignPermissionsToSystemRoles(%v, %v)", v0, v1)
+				return
+			},
+		},
+		BulkRevokePermissionsForRoleFunc: &RolePermissionStoreBulkRevokePermissionsForRoleFunc{
+			defaultHook: func(v0 context.Context, v1 database.BulkRevokePermissionsForRoleOpts) (r0 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.BulkRevokePermissionsForRole(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByPermissionIDFunc: &RolePermissionStoreGetByPermissionIDFunc{
+			defaultHook: func(v0 context.Context, v1 database.GetRolePermissionOpts) (r0 []*types.RolePermission, r1 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.GetByPermissionID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByRoleIDFunc: &RolePermissionStoreGetByRoleIDFunc{
+			defaultHook: func(v0 context.Context, v1 database.GetRolePermissionOpts) (r0 []*types.RolePermission, r1 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.GetByRoleID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByRoleIDAndPermissionIDFunc: &RolePermissionStoreGetByRoleIDAndPermissionIDFunc{
+			defaultHook: func(v0 context.Context, v1 database.GetRolePermissionOpts) (r0 *types.RolePermission, r1 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.GetByRoleIDAndPermissionID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &RolePermissionStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockRolePermissionStore.Handle()")
+				return
+			},
+		},
+		RevokeFunc: &RolePermissionStoreRevokeFunc{
+			defaultHook: func(v0 context.Context, v1 database.RevokeRolePermissionOpts) (r0 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.Revoke(%v, %v)", v0, v1)
+				return
+			},
+		},
+		SetPermissionsForRoleFunc: &RolePermissionStoreSetPermissionsForRoleFunc{
+			defaultHook: func(v0 context.Context, v1 database.SetPermissionsForRoleOpts) (r0 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.SetPermissionsForRole(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &RolePermissionStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.RolePermissionStore) {
+				t.Errorf("unexpected call of MockRolePermissionStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &RolePermissionStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.RolePermissionStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockRolePermissionStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockRolePermissionStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockRolePermissionStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.AssignFunc.assertExpectations(t, "MockRolePermissionStore.Assign")
+	m.AssignToSystemRoleFunc.assertExpectations(t, "MockRolePermissionStore.AssignToSystemRole")
+	m.BulkAssignPermissionsToRoleFunc.assertExpectations(t, "MockRolePermissionStore.BulkAssignPermissionsToRole")
+	m.BulkAssignPermissionsToSystemRolesFunc.assertExpectations(t, "MockRolePermissionStore.BulkAssignPermissionsToSystemRoles")
+	m.BulkRevokePermissionsForRoleFunc.assertExpectations(t, "MockRolePermissionStore.BulkRevokePermissionsForRole")
+	m.GetByPermissionIDFunc.assertExpectations(t, "MockRolePermissionStore.GetByPermissionID")
+	m.GetByRoleIDFunc.assertExpectations(t, "MockRolePermissionStore.GetByRoleID")
+	m.GetByRoleIDAndPermissionIDFunc.assertExpectations(t, "MockRolePermissionStore.GetByRoleIDAndPermissionID")
+	m.HandleFunc.assertExpectations(t, "MockRolePermissionStore.Handle")
+	m.RevokeFunc.assertExpectations(t, "MockRolePermissionStore.Revoke")
+	m.SetPermissionsForRoleFunc.assertExpectations(t, "MockRolePermissionStore.SetPermissionsForRole")
+	m.WithFunc.assertExpectations(t, "MockRolePermissionStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockRolePermissionStore.WithTransact")
+}
+
 // NewMockRolePermissionStoreFrom creates a new mock of the
 // MockRolePermissionStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -63114,6 +73228,18 @@ func (f *RolePermissionStoreAssignFunc) History() []RolePermissionStoreAssignFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreAssignFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreAssignFuncCall is an object that describes an
 // invocation of method Assign on an instance of MockRolePermissionStore.
 type RolePermissionStoreAssignFuncCall struct {
@@ -63222,6 +73348,18 @@ func (f *RolePermissionStoreAssignToSystemRoleFunc) History() []RolePermissionSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreAssignToSystemRoleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreAssignToSystemRoleFuncCall is an object that describes
 // an invocation of method AssignToSystemRole on an instance of
 // MockRolePermissionStore.
@@ -63331,6 +73469,18 @@ func (f *RolePermissionStoreBulkAssignPermissionsToRoleFunc) History() []RolePer
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreBulkAssignPermissionsToRoleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreBulkAssignPermissionsToRoleFuncCall is an object that
 // describes an invocation of method BulkAssignPermissionsToRole on an
 // instance of MockRolePermissionStore.
@@ -63440,6 +73590,18 @@ func (f *RolePermissionStoreBulkAssignPermissionsToSystemRolesFunc) History() []
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreBulkAssignPermissionsToSystemRolesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreBulkAssignPermissionsToSystemRolesFuncCall is an
 // object that describes an invocation of method
 // BulkAssignPermissionsToSystemRoles on an instance of
@@ -63550,6 +73712,18 @@ func (f *RolePermissionStoreBulkRevokePermissionsForRoleFunc) History() []RolePe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreBulkRevokePermissionsForRoleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreBulkRevokePermissionsForRoleFuncCall is an object that
 // describes an invocation of method BulkRevokePermissionsForRole on an
 // instance of MockRolePermissionStore.
@@ -63659,6 +73833,18 @@ func (f *RolePermissionStoreGetByPermissionIDFunc) History() []RolePermissionSto
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreGetByPermissionIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreGetByPermissionIDFuncCall is an object that describes
 // an invocation of method GetByPermissionID on an instance of
 // MockRolePermissionStore.
@@ -63769,6 +73955,18 @@ func (f *RolePermissionStoreGetByRoleIDFunc) History() []RolePermissionStoreGetB
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreGetByRoleIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreGetByRoleIDFuncCall is an object that describes an
 // invocation of method GetByRoleID on an instance of
 // MockRolePermissionStore.
@@ -63881,6 +74079,18 @@ func (f *RolePermissionStoreGetByRoleIDAndPermissionIDFunc) History() []RolePerm
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreGetByRoleIDAndPermissionIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreGetByRoleIDAndPermissionIDFuncCall is an object that
 // describes an invocation of method GetByRoleIDAndPermissionID on an
 // instance of MockRolePermissionStore.
@@ -63990,6 +74200,18 @@ func (f *RolePermissionStoreHandleFunc) History() []RolePermissionStoreHandleFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockRolePermissionStore.
 type RolePermissionStoreHandleFuncCall struct {
@@ -64089,6 +74311,18 @@ func (f *RolePermissionStoreRevokeFunc) History() []RolePermissionStoreRevokeFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreRevokeFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreRevokeFuncCall is an object that describes an
 // invocation of method Revoke on an instance of MockRolePermissionStore.
 type RolePermissionStoreRevokeFuncCall struct {
@@ -64197,6 +74431,18 @@ func (f *RolePermissionStoreSetPermissionsForRoleFunc) History() []RolePermissio
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreSetPermissionsForRoleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreSetPermissionsForRoleFuncCall is an object that
 // describes an invocation of method SetPermissionsForRole on an instance of
 // MockRolePermissionStore.
@@ -64303,6 +74549,18 @@ func (f *RolePermissionStoreWithFunc) History() []RolePermissionStoreWithFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreWithFuncCall is an object that describes an invocation
 // of method With on an instance of MockRolePermissionStore.
 type RolePermissionStoreWithFuncCall struct {
@@ -64407,6 +74665,18 @@ func (f *RolePermissionStoreWithTransactFunc) History() []RolePermissionStoreWit
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RolePermissionStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RolePermissionStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of
 // MockRolePermissionStore.
@@ -64545,6 +74815,69 @@ func NewStrictMockRoleStore() *MockRoleStore {
 	}
 }
 
+// NewStrictMockRoleStoreWithT creates a new mock of the RoleStore interface.
+// All methods fail t with their arguments and return zero values for all
+// results, unless overwritten.
+func NewStrictMockRoleStoreWithT(t testing.TB) *MockRoleStore {
+	return &MockRoleStore{
+		CountFunc: &RoleStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.RolesListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockRoleStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &RoleStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 bool) (r0 *types.Role, r1 error) {
+				t.Errorf("unexpected call of MockRoleStore.Create(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DeleteFunc: &RoleStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 database.DeleteRoleOpts) (r0 error) {
+				t.Errorf("unexpected call of MockRoleStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetFunc: &RoleStoreGetFunc{
+			defaultHook: func(v0 context.Context, v1 database.GetRoleOpts) (r0 *types.Role, r1 error) {
+				t.Errorf("unexpected call of MockRoleStore.Get(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &RoleStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockRoleStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &RoleStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.RolesListOptions) (r0 []*types.Role, r1 error) {
+				t.Errorf("unexpected call of MockRoleStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateFunc: &RoleStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.Role) (r0 *types.Role, r1 error) {
+				t.Errorf("unexpected call of MockRoleStore.Update(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockRoleStore instance with PushHook or PushReturn which was not called.
+func (m *MockRoleStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockRoleStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockRoleStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockRoleStore.Delete")
+	m.GetFunc.assertExpectations(t, "MockRoleStore.Get")
+	m.HandleFunc.assertExpectations(t, "MockRoleStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockRoleStore.List")
+	m.UpdateFunc.assertExpectations(t, "MockRoleStore.Update")
+}
+
 // NewMockRoleStoreFrom creates a new mock of the MockRoleStore interface.
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockRoleStoreFrom(i database.RoleStore) *MockRoleStore {
@@ -64651,6 +74984,18 @@ func (f *RoleStoreCountFunc) History() []RoleStoreCountFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RoleStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RoleStoreCountFuncCall is an object that describes an invocation of
 // method Count on an instance of MockRoleStore.
 type RoleStoreCountFuncCall struct {
@@ -64758,6 +75103,18 @@ func (f *RoleStoreCreateFunc) History() []RoleStoreCreateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RoleStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RoleStoreCreateFuncCall is an object that describes an invocation of
 // method Create on an instance of MockRoleStore.
 type RoleStoreCreateFuncCall struct {
@@ -64868,6 +75225,18 @@ func (f *RoleStoreDeleteFunc) History() []RoleStoreDeleteFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RoleStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RoleStoreDeleteFuncCall is an object that describes an invocation of
 // method Delete on an instance of MockRoleStore.
 type RoleStoreDeleteFuncCall struct {
@@ -64972,6 +75341,18 @@ func (f *RoleStoreGetFunc) History() []RoleStoreGetFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RoleStoreGetFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RoleStoreGetFuncCall is an object that describes an invocation of method
 // Get on an instance of MockRoleStore.
 type RoleStoreGetFuncCall struct {
@@ -65079,6 +75460,18 @@ func (f *RoleStoreHandleFunc) History() []RoleStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RoleStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RoleStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockRoleStore.
 type RoleStoreHandleFuncCall struct {
@@ -65177,6 +75570,18 @@ func (f *RoleStoreListFunc) History() []RoleStoreListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RoleStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RoleStoreListFuncCall is an object that describes an invocation of method
 // List on an instance of MockRoleStore.
 type RoleStoreListFuncCall struct {
@@ -65284,6 +75689,18 @@ func (f *RoleStoreUpdateFunc) History() []RoleStoreUpdateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *RoleStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // RoleStoreUpdateFuncCall is an object that describes an invocation of
 // method Update on an instance of MockRoleStore.
 type RoleStoreUpdateFuncCall struct {
@@ -65507,6 +75924,112 @@ func NewStrictMockSavedSearchStore() *MockSavedSearchStore {
 	}
 }
 
+// NewStrictMockSavedSearchStoreWithT creates a new mock of the
+// SavedSearchStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockSavedSearchStoreWithT(t testing.TB) *MockSavedSearchStore {
+	return &MockSavedSearchStore{
+		CountSavedSearchesByOrgOrUserFunc: &SavedSearchStoreCountSavedSearchesByOrgOrUserFunc{
+			defaultHook: func(v0 context.Context, v1 *int32, v2 *int32) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.CountSavedSearchesByOrgOrUser(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreateFunc: &SavedSearchStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.SavedSearch) (r0 *types.SavedSearch, r1 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.Create(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteFunc: &SavedSearchStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &SavedSearchStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *api.SavedQuerySpecAndConfig, r1 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &SavedSearchStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockSavedSearchStore.Handle()")
+				return
+			},
+		},
+		IsEmptyFunc: &SavedSearchStoreIsEmptyFunc{
+			defaultHook: func(v0 context.Context) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.IsEmpty(%v)", v0)
+				return
+			},
+		},
+		ListAllFunc: &SavedSearchStoreListAllFunc{
+			defaultHook: func(v0 context.Context) (r0 []api.SavedQuerySpecAndConfig, r1 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.ListAll(%v)", v0)
+				return
+			},
+		},
+		ListSavedSearchesByOrgIDFunc: &SavedSearchStoreListSavedSearchesByOrgIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []*types.SavedSearch, r1 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.ListSavedSearchesByOrgID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListSavedSearchesByOrgOrUserFunc: &SavedSearchStoreListSavedSearchesByOrgOrUserFunc{
+			defaultHook: func(v0 context.Context, v1 *int32, v2 *int32, v3 *database.PaginationArgs) (r0 []*types.SavedSearch, r1 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.ListSavedSearchesByOrgOrUser(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		ListSavedSearchesByUserIDFunc: &SavedSearchStoreListSavedSearchesByUserIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 []*types.SavedSearch, r1 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.ListSavedSearchesByUserID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateFunc: &SavedSearchStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.SavedSearch) (r0 *types.SavedSearch, r1 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.Update(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &SavedSearchStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.SavedSearchStore) {
+				t.Errorf("unexpected call of MockSavedSearchStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &SavedSearchStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.SavedSearchStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockSavedSearchStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockSavedSearchStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockSavedSearchStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountSavedSearchesByOrgOrUserFunc.assertExpectations(t, "MockSavedSearchStore.CountSavedSearchesByOrgOrUser")
+	m.CreateFunc.assertExpectations(t, "MockSavedSearchStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockSavedSearchStore.Delete")
+	m.GetByIDFunc.assertExpectations(t, "MockSavedSearchStore.GetByID")
+	m.HandleFunc.assertExpectations(t, "MockSavedSearchStore.Handle")
+	m.IsEmptyFunc.assertExpectations(t, "MockSavedSearchStore.IsEmpty")
+	m.ListAllFunc.assertExpectations(t, "MockSavedSearchStore.ListAll")
+	m.ListSavedSearchesByOrgIDFunc.assertExpectations(t, "MockSavedSearchStore.ListSavedSearchesByOrgID")
+	m.ListSavedSearchesByOrgOrUserFunc.assertExpectations(t, "MockSavedSearchStore.ListSavedSearchesByOrgOrUser")
+	m.ListSavedSearchesByUserIDFunc.assertExpectations(t, "MockSavedSearchStore.ListSavedSearchesByUserID")
+	m.UpdateFunc.assertExpectations(t, "MockSavedSearchStore.Update")
+	m.WithFunc.assertExpectations(t, "MockSavedSearchStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockSavedSearchStore.WithTransact")
+}
+
 // NewMockSavedSearchStoreFrom creates a new mock of the
 // MockSavedSearchStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -65636,6 +76159,18 @@ func (f *SavedSearchStoreCountSavedSearchesByOrgOrUserFunc) History() []SavedSea
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreCountSavedSearchesByOrgOrUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreCountSavedSearchesByOrgOrUserFuncCall is an object that
 // describes an invocation of method CountSavedSearchesByOrgOrUser on an
 // instance of MockSavedSearchStore.
@@ -65748,6 +76283,18 @@ func (f *SavedSearchStoreCreateFunc) History() []SavedSearchStoreCreateFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreCreateFuncCall is an object that describes an invocation
 // of method Create on an instance of MockSavedSearchStore.
 type SavedSearchStoreCreateFuncCall struct {
@@ -65856,6 +76403,18 @@ func (f *SavedSearchStoreDeleteFunc) History() []SavedSearchStoreDeleteFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreDeleteFuncCall is an object that describes an invocation
 // of method Delete on an instance of MockSavedSearchStore.
 type SavedSearchStoreDeleteFuncCall struct {
@@ -65961,6 +76520,18 @@ func (f *SavedSearchStoreGetByIDFunc) History() []SavedSearchStoreGetByIDFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreGetByIDFuncCall is an object that describes an invocation
 // of method GetByID on an instance of MockSavedSearchStore.
 type SavedSearchStoreGetByIDFuncCall struct {
@@ -66069,6 +76640,18 @@ func (f *SavedSearchStoreHandleFunc) History() []SavedSearchStoreHandleFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockSavedSearchStore.
 type SavedSearchStoreHandleFuncCall struct {
@@ -66168,6 +76751,18 @@ func (f *SavedSearchStoreIsEmptyFunc) History() []SavedSearchStoreIsEmptyFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreIsEmptyFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreIsEmptyFuncCall is an object that describes an invocation
 // of method IsEmpty on an instance of MockSavedSearchStore.
 type SavedSearchStoreIsEmptyFuncCall struct {
@@ -66273,6 +76868,18 @@ func (f *SavedSearchStoreListAllFunc) History() []SavedSearchStoreListAllFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreListAllFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreListAllFuncCall is an object that describes an invocation
 // of method ListAll on an instance of MockSavedSearchStore.
 type SavedSearchStoreListAllFuncCall struct {
@@ -66381,6 +76988,18 @@ func (f *SavedSearchStoreListSavedSearchesByOrgIDFunc) History() []SavedSearchSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreListSavedSearchesByOrgIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreListSavedSearchesByOrgIDFuncCall is an object that
 // describes an invocation of method ListSavedSearchesByOrgID on an instance
 // of MockSavedSearchStore.
@@ -66493,6 +77112,18 @@ func (f *SavedSearchStoreListSavedSearchesByOrgOrUserFunc) History() []SavedSear
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreListSavedSearchesByOrgOrUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreListSavedSearchesByOrgOrUserFuncCall is an object that
 // describes an invocation of method ListSavedSearchesByOrgOrUser on an
 // instance of MockSavedSearchStore.
@@ -66611,6 +77242,18 @@ func (f *SavedSearchStoreListSavedSearchesByUserIDFunc) History() []SavedSearchS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreListSavedSearchesByUserIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreListSavedSearchesByUserIDFuncCall is an object that
 // describes an invocation of method ListSavedSearchesByUserID on an
 // instance of MockSavedSearchStore.
@@ -66720,6 +77363,18 @@ func (f *SavedSearchStoreUpdateFunc) History() []SavedSearchStoreUpdateFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreUpdateFuncCall is an object that describes an invocation
 // of method Update on an instance of MockSavedSearchStore.
 type SavedSearchStoreUpdateFuncCall struct {
@@ -66828,6 +77483,18 @@ func (f *SavedSearchStoreWithFunc) History() []SavedSearchStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockSavedSearchStore.
 type SavedSearchStoreWithFuncCall struct {
@@ -66931,6 +77598,18 @@ func (f *SavedSearchStoreWithTransactFunc) History() []SavedSearchStoreWithTrans
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SavedSearchStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SavedSearchStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockSavedSearchStore.
 type SavedSearchStoreWithTransactFuncCall struct {
@@ -67222,6 +77901,147 @@ func NewStrictMockSearchContextsStore() *MockSearchContextsStore {
 	}
 }
 
+// NewStrictMockSearchContextsStoreWithT creates a new mock of the
+// SearchContextsStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockSearchContextsStoreWithT(t testing.TB) *MockSearchContextsStore {
+	return &MockSearchContextsStore{
+		CountSearchContextsFunc: &SearchContextsStoreCountSearchContextsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListSearchContextsOptions) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.CountSearchContexts(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateSearchContextStarForUserFunc: &SearchContextsStoreCreateSearchContextStarForUserFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int64) (r0 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.CreateSearchContextStarForUser(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreateSearchContextWithRepositoryRevisionsFunc: &SearchContextsStoreCreateSearchContextWithRepositoryRevisionsFunc{
+			defaultHook: func(v0 context.Context, v1 *types.SearchContext, v2 []*types.SearchContextRepositoryRevisions) (r0 *types.SearchContext, r1 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.CreateSearchContextWithRepositoryRevisions(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DeleteSearchContextFunc: &SearchContextsStoreDeleteSearchContextFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.DeleteSearchContext(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteSearchContextStarForUserFunc: &SearchContextsStoreDeleteSearchContextStarForUserFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int64) (r0 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.DeleteSearchContextStarForUser(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DoneFunc: &SearchContextsStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.Done(%v)", v0)
+				return
+			},
+		},
+		ExecFunc: &SearchContextsStoreExecFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.Exec(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetAllQueriesFunc: &SearchContextsStoreGetAllQueriesFunc{
+			defaultHook: func(v0 context.Context) (r0 []string, r1 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.GetAllQueries(%v)", v0)
+				return
+			},
+		},
+		GetAllRevisionsForReposFunc: &SearchContextsStoreGetAllRevisionsForReposFunc{
+			defaultHook: func(v0 context.Context, v1 []api.RepoID) (r0 map[api.RepoID][]string, r1 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.GetAllRevisionsForRepos(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetDefaultSearchContextForCurrentUserFunc: &SearchContextsStoreGetDefaultSearchContextForCurrentUserFunc{
+			defaultHook: func(v0 context.Context) (r0 *types.SearchContext, r1 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.GetDefaultSearchContextForCurrentUser(%v)", v0)
+				return
+			},
+		},
+		GetSearchContextFunc: &SearchContextsStoreGetSearchContextFunc{
+			defaultHook: func(v0 context.Context, v1 database.GetSearchContextOptions) (r0 *types.SearchContext, r1 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.GetSearchContext(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetSearchContextRepositoryRevisionsFunc: &SearchContextsStoreGetSearchContextRepositoryRevisionsFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 []*types.SearchContextRepositoryRevisions, r1 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.GetSearchContextRepositoryRevisions(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &SearchContextsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockSearchContextsStore.Handle()")
+				return
+			},
+		},
+		ListSearchContextsFunc: &SearchContextsStoreListSearchContextsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListSearchContextsPageOptions, v2 database.ListSearchContextsOptions) (r0 []*types.SearchContext, r1 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.ListSearchContexts(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetSearchContextRepositoryRevisionsFunc: &SearchContextsStoreSetSearchContextRepositoryRevisionsFunc{
+			defaultHook: func(v0 context.Context, v1 int64, v2 []*types.SearchContextRepositoryRevisions) (r0 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.SetSearchContextRepositoryRevisions(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetUserDefaultSearchContextIDFunc: &SearchContextsStoreSetUserDefaultSearchContextIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int64) (r0 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.SetUserDefaultSearchContextID(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		TransactFunc: &SearchContextsStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.SearchContextsStore, r1 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.Transact(%v)", v0)
+				return
+			},
+		},
+		UpdateSearchContextWithRepositoryRevisionsFunc: &SearchContextsStoreUpdateSearchContextWithRepositoryRevisionsFunc{
+			defaultHook: func(v0 context.Context, v1 *types.SearchContext, v2 []*types.SearchContextRepositoryRevisions) (r0 *types.SearchContext, r1 error) {
+				t.Errorf("unexpected call of MockSearchContextsStore.UpdateSearchContextWithRepositoryRevisions(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockSearchContextsStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockSearchContextsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountSearchContextsFunc.assertExpectations(t, "MockSearchContextsStore.CountSearchContexts")
+	m.CreateSearchContextStarForUserFunc.assertExpectations(t, "MockSearchContextsStore.CreateSearchContextStarForUser")
+	m.CreateSearchContextWithRepositoryRevisionsFunc.assertExpectations(t, "MockSearchContextsStore.CreateSearchContextWithRepositoryRevisions")
+	m.DeleteSearchContextFunc.assertExpectations(t, "MockSearchContextsStore.DeleteSearchContext")
+	m.DeleteSearchContextStarForUserFunc.assertExpectations(t, "MockSearchContextsStore.DeleteSearchContextStarForUser")
+	m.DoneFunc.assertExpectations(t, "MockSearchContextsStore.Done")
+	m.ExecFunc.assertExpectations(t, "MockSearchContextsStore.Exec")
+	m.GetAllQueriesFunc.assertExpectations(t, "MockSearchContextsStore.GetAllQueries")
+	m.GetAllRevisionsForReposFunc.assertExpectations(t, "MockSearchContextsStore.GetAllRevisionsForRepos")
+	m.GetDefaultSearchContextForCurrentUserFunc.assertExpectations(t, "MockSearchContextsStore.GetDefaultSearchContextForCurrentUser")
+	m.GetSearchContextFunc.assertExpectations(t, "MockSearchContextsStore.GetSearchContext")
+	m.GetSearchContextRepositoryRevisionsFunc.assertExpectations(t, "MockSearchContextsStore.GetSearchContextRepositoryRevisions")
+	m.HandleFunc.assertExpectations(t, "MockSearchContextsStore.Handle")
+	m.ListSearchContextsFunc.assertExpectations(t, "MockSearchContextsStore.ListSearchContexts")
+	m.SetSearchContextRepositoryRevisionsFunc.assertExpectations(t, "MockSearchContextsStore.SetSearchContextRepositoryRevisions")
+	m.SetUserDefaultSearchContextIDFunc.assertExpectations(t, "MockSearchContextsStore.SetUserDefaultSearchContextID")
+	m.TransactFunc.assertExpectations(t, "MockSearchContextsStore.Transact")
+	m.UpdateSearchContextWithRepositoryRevisionsFunc.assertExpectations(t, "MockSearchContextsStore.UpdateSearchContextWithRepositoryRevisions")
+}
+
 // NewMockSearchContextsStoreFrom creates a new mock of the
 // MockSearchContextsStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -67366,6 +78186,18 @@ func (f *SearchContextsStoreCountSearchContextsFunc) History() []SearchContextsS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreCountSearchContextsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreCountSearchContextsFuncCall is an object that
 // describes an invocation of method CountSearchContexts on an instance of
 // MockSearchContextsStore.
@@ -67478,6 +78310,18 @@ func (f *SearchContextsStoreCreateSearchContextStarForUserFunc) History() []Sear
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreCreateSearchContextStarForUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreCreateSearchContextStarForUserFuncCall is an object
 // that describes an invocation of method CreateSearchContextStarForUser on
 // an instance of MockSearchContextsStore.
@@ -67592,6 +78436,18 @@ func (f *SearchContextsStoreCreateSearchContextWithRepositoryRevisionsFunc) Hist
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreCreateSearchContextWithRepositoryRevisionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreCreateSearchContextWithRepositoryRevisionsFuncCall is
 // an object that describes an invocation of method
 // CreateSearchContextWithRepositoryRevisions on an instance of
@@ -67708,6 +78564,18 @@ func (f *SearchContextsStoreDeleteSearchContextFunc) History() []SearchContextsS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreDeleteSearchContextFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreDeleteSearchContextFuncCall is an object that
 // describes an invocation of method DeleteSearchContext on an instance of
 // MockSearchContextsStore.
@@ -67817,6 +78685,18 @@ func (f *SearchContextsStoreDeleteSearchContextStarForUserFunc) History() []Sear
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreDeleteSearchContextStarForUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreDeleteSearchContextStarForUserFuncCall is an object
 // that describes an invocation of method DeleteSearchContextStarForUser on
 // an instance of MockSearchContextsStore.
@@ -67926,6 +78806,18 @@ func (f *SearchContextsStoreDoneFunc) History() []SearchContextsStoreDoneFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreDoneFuncCall is an object that describes an invocation
 // of method Done on an instance of MockSearchContextsStore.
 type SearchContextsStoreDoneFuncCall struct {
@@ -68028,6 +78920,18 @@ func (f *SearchContextsStoreExecFunc) History() []SearchContextsStoreExecFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreExecFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreExecFuncCall is an object that describes an invocation
 // of method Exec on an instance of MockSearchContextsStore.
 type SearchContextsStoreExecFuncCall struct {
@@ -68135,6 +79039,18 @@ func (f *SearchContextsStoreGetAllQueriesFunc) History() []SearchContextsStoreGe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreGetAllQueriesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreGetAllQueriesFuncCall is an object that describes an
 // invocation of method GetAllQueries on an instance of
 // MockSearchContextsStore.
@@ -68244,6 +79160,18 @@ func (f *SearchContextsStoreGetAllRevisionsForReposFunc) History() []SearchConte
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreGetAllRevisionsForReposFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreGetAllRevisionsForReposFuncCall is an object that
 // describes an invocation of method GetAllRevisionsForRepos on an instance
 // of MockSearchContextsStore.
@@ -68357,6 +79285,18 @@ func (f *SearchContextsStoreGetDefaultSearchContextForCurrentUserFunc) History()
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreGetDefaultSearchContextForCurrentUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreGetDefaultSearchContextForCurrentUserFuncCall is an
 // object that describes an invocation of method
 // GetDefaultSearchContextForCurrentUser on an instance of
@@ -68466,6 +79406,18 @@ func (f *SearchContextsStoreGetSearchContextFunc) History() []SearchContextsStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreGetSearchContextFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreGetSearchContextFuncCall is an object that describes
 // an invocation of method GetSearchContext on an instance of
 // MockSearchContextsStore.
@@ -68579,6 +79531,18 @@ func (f *SearchContextsStoreGetSearchContextRepositoryRevisionsFunc) History() [
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreGetSearchContextRepositoryRevisionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreGetSearchContextRepositoryRevisionsFuncCall is an
 // object that describes an invocation of method
 // GetSearchContextRepositoryRevisions on an instance of
@@ -68689,6 +79653,18 @@ func (f *SearchContextsStoreHandleFunc) History() []SearchContextsStoreHandleFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockSearchContextsStore.
 type SearchContextsStoreHandleFuncCall struct {
@@ -68791,6 +79767,18 @@ func (f *SearchContextsStoreListSearchContextsFunc) History() []SearchContextsSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreListSearchContextsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreListSearchContextsFuncCall is an object that describes
 // an invocation of method ListSearchContexts on an instance of
 // MockSearchContextsStore.
@@ -68907,6 +79895,18 @@ func (f *SearchContextsStoreSetSearchContextRepositoryRevisionsFunc) History() [
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreSetSearchContextRepositoryRevisionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreSetSearchContextRepositoryRevisionsFuncCall is an
 // object that describes an invocation of method
 // SetSearchContextRepositoryRevisions on an instance of
@@ -69020,6 +80020,18 @@ func (f *SearchContextsStoreSetUserDefaultSearchContextIDFunc) History() []Searc
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreSetUserDefaultSearchContextIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreSetUserDefaultSearchContextIDFuncCall is an object
 // that describes an invocation of method SetUserDefaultSearchContextID on
 // an instance of MockSearchContextsStore.
@@ -69129,6 +80141,18 @@ func (f *SearchContextsStoreTransactFunc) History() []SearchContextsStoreTransac
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreTransactFuncCall is an object that describes an
 // invocation of method Transact on an instance of MockSearchContextsStore.
 type SearchContextsStoreTransactFuncCall struct {
@@ -69239,6 +80263,18 @@ func (f *SearchContextsStoreUpdateSearchContextWithRepositoryRevisionsFunc) Hist
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SearchContextsStoreUpdateSearchContextWithRepositoryRevisionsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SearchContextsStoreUpdateSearchContextWithRepositoryRevisionsFuncCall is
 // an object that describes an invocation of method
 // UpdateSearchContextWithRepositoryRevisions on an instance of
@@ -69374,6 +80410,63 @@ func NewStrictMockSecurityEventLogsStore() *MockSecurityEventLogsStore {
 	}
 }
 
+// NewStrictMockSecurityEventLogsStoreWithT creates a new mock of the
+// SecurityEventLogsStore interface. All methods fail t with their arguments
+// and return zero values for all results, unless overwritten.
+func NewStrictMockSecurityEventLogsStoreWithT(t testing.TB) *MockSecurityEventLogsStore {
+	return &MockSecurityEventLogsStore{
+		HandleFunc: &SecurityEventLogsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockSecurityEventLogsStore.Handle()")
+				return
+			},
+		},
+		InsertFunc: &SecurityEventLogsStoreInsertFunc{
+			defaultHook: func(v0 context.Context, v1 *database.SecurityEvent) (r0 error) {
+				t.Errorf("unexpected call of MockSecurityEventLogsStore.Insert(%v, %v)", v0, v1)
+				return
+			},
+		},
+		InsertListFunc: &SecurityEventLogsStoreInsertListFunc{
+			defaultHook: func(v0 context.Context, v1 []*database.SecurityEvent) (r0 error) {
+				t.Errorf("unexpected call of MockSecurityEventLogsStore.InsertList(%v, %v)", v0, v1)
+				return
+			},
+		},
+		LogEventFunc: &SecurityEventLogsStoreLogEventFunc{
+			defaultHook: func(v0 context.Context, v1 *database.SecurityEvent) {
+				t.Errorf("unexpected call of MockSecurityEventLogsStore.LogEvent(%v, %v)", v0, v1)
+				return
+			},
+		},
+		LogEventListFunc: &SecurityEventLogsStoreLogEventListFunc{
+			defaultHook: func(v0 context.Context, v1 []*database.SecurityEvent) {
+				t.Errorf("unexpected call of MockSecurityEventLogsStore.LogEventList(%v, %v)", v0, v1)
+				return
+			},
+		},
+		LogSecurityEventFunc: &SecurityEventLogsStoreLogSecurityEventFunc{
+			defaultHook: func(v0 context.Context, v1 database.SecurityEventName, v2 string, v3 uint32, v4 string, v5 string, v6 interface{}) (r0 error) {
+				t.Errorf("unexpected call of MockSecurityEventLogsStore.LogSecurityEvent(%v, %v, %v, %v, %v, %v, %v)", v0, v1, v2, v3, v4, v5, v6)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockSecurityEventLogsStore instance with PushHook or PushReturn which was
+// not called.
+func (m *MockSecurityEventLogsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.HandleFunc.assertExpectations(t, "MockSecurityEventLogsStore.Handle")
+	m.InsertFunc.assertExpectations(t, "MockSecurityEventLogsStore.Insert")
+	m.InsertListFunc.assertExpectations(t, "MockSecurityEventLogsStore.InsertList")
+	m.LogEventFunc.assertExpectations(t, "MockSecurityEventLogsStore.LogEvent")
+	m.LogEventListFunc.assertExpectations(t, "MockSecurityEventLogsStore.LogEventList")
+	m.LogSecurityEventFunc.assertExpectations(t, "MockSecurityEventLogsStore.LogSecurityEvent")
+}
+
 // NewMockSecurityEventLogsStoreFrom creates a new mock of the
 // MockSecurityEventLogsStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -69479,6 +80572,18 @@ func (f *SecurityEventLogsStoreHandleFunc) History() []SecurityEventLogsStoreHan
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SecurityEventLogsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SecurityEventLogsStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockSecurityEventLogsStore.
 type SecurityEventLogsStoreHandleFuncCall struct {
@@ -69578,6 +80683,18 @@ func (f *SecurityEventLogsStoreInsertFunc) History() []SecurityEventLogsStoreIns
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SecurityEventLogsStoreInsertFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SecurityEventLogsStoreInsertFuncCall is an object that describes an
 // invocation of method Insert on an instance of MockSecurityEventLogsStore.
 type SecurityEventLogsStoreInsertFuncCall struct {
@@ -69685,6 +80802,18 @@ func (f *SecurityEventLogsStoreInsertListFunc) History() []SecurityEventLogsStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SecurityEventLogsStoreInsertListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SecurityEventLogsStoreInsertListFuncCall is an object that describes an
 // invocation of method InsertList on an instance of
 // MockSecurityEventLogsStore.
@@ -69792,6 +80921,18 @@ func (f *SecurityEventLogsStoreLogEventFunc) History() []SecurityEventLogsStoreL
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SecurityEventLogsStoreLogEventFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SecurityEventLogsStoreLogEventFuncCall is an object that describes an
 // invocation of method LogEvent on an instance of
 // MockSecurityEventLogsStore.
@@ -69897,6 +81038,18 @@ func (f *SecurityEventLogsStoreLogEventListFunc) History() []SecurityEventLogsSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SecurityEventLogsStoreLogEventListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SecurityEventLogsStoreLogEventListFuncCall is an object that describes an
 // invocation of method LogEventList on an instance of
 // MockSecurityEventLogsStore.
@@ -70003,6 +81156,18 @@ func (f *SecurityEventLogsStoreLogSecurityEventFunc) History() []SecurityEventLo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SecurityEventLogsStoreLogSecurityEventFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SecurityEventLogsStoreLogSecurityEventFuncCall is an object that
 // describes an invocation of method LogSecurityEvent on an instance of
 // MockSecurityEventLogsStore.
@@ -70169,6 +81334,76 @@ func NewStrictMockSettingsStore() *MockSettingsStore {
 	}
 }
 
+// NewStrictMockSettingsStoreWithT creates a new mock of the SettingsStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockSettingsStoreWithT(t testing.TB) *MockSettingsStore {
+	return &MockSettingsStore{
+		CreateIfUpToDateFunc: &SettingsStoreCreateIfUpToDateFunc{
+			defaultHook: func(v0 context.Context, v1 api.SettingsSubject, v2 *int32, v3 *int32, v4 string) (r0 *api.Settings, r1 error) {
+				t.Errorf("unexpected call of MockSettingsStore.CreateIfUpToDate(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		DoneFunc: &SettingsStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockSettingsStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetLatestFunc: &SettingsStoreGetLatestFunc{
+			defaultHook: func(v0 context.Context, v1 api.SettingsSubject) (r0 *api.Settings, r1 error) {
+				t.Errorf("unexpected call of MockSettingsStore.GetLatest(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetLatestSchemaSettingsFunc: &SettingsStoreGetLatestSchemaSettingsFunc{
+			defaultHook: func(v0 context.Context, v1 api.SettingsSubject) (r0 *schema.Settings, r1 error) {
+				t.Errorf("unexpected call of MockSettingsStore.GetLatestSchemaSettings(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &SettingsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockSettingsStore.Handle()")
+				return
+			},
+		},
+		ListAllFunc: &SettingsStoreListAllFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 []*api.Settings, r1 error) {
+				t.Errorf("unexpected call of MockSettingsStore.ListAll(%v, %v)", v0, v1)
+				return
+			},
+		},
+		TransactFunc: &SettingsStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.SettingsStore, r1 error) {
+				t.Errorf("unexpected call of MockSettingsStore.Transact(%v)", v0)
+				return
+			},
+		},
+		WithFunc: &SettingsStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.SettingsStore) {
+				t.Errorf("unexpected call of MockSettingsStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockSettingsStore instance with PushHook or PushReturn which was not called.
+func (m *MockSettingsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CreateIfUpToDateFunc.assertExpectations(t, "MockSettingsStore.CreateIfUpToDate")
+	m.DoneFunc.assertExpectations(t, "MockSettingsStore.Done")
+	m.GetLatestFunc.assertExpectations(t, "MockSettingsStore.GetLatest")
+	m.GetLatestSchemaSettingsFunc.assertExpectations(t, "MockSettingsStore.GetLatestSchemaSettings")
+	m.HandleFunc.assertExpectations(t, "MockSettingsStore.Handle")
+	m.ListAllFunc.assertExpectations(t, "MockSettingsStore.ListAll")
+	m.TransactFunc.assertExpectations(t, "MockSettingsStore.Transact")
+	m.WithFunc.assertExpectations(t, "MockSettingsStore.With")
+}
+
 // NewMockSettingsStoreFrom creates a new mock of the MockSettingsStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -70281,6 +81516,18 @@ func (f *SettingsStoreCreateIfUpToDateFunc) History() []SettingsStoreCreateIfUpT
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SettingsStoreCreateIfUpToDateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SettingsStoreCreateIfUpToDateFuncCall is an object that describes an
 // invocation of method CreateIfUpToDate on an instance of
 // MockSettingsStore.
@@ -70398,6 +81645,18 @@ func (f *SettingsStoreDoneFunc) History() []SettingsStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SettingsStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SettingsStoreDoneFuncCall is an object that describes an invocation of
 // method Done on an instance of MockSettingsStore.
 type SettingsStoreDoneFuncCall struct {
@@ -70500,6 +81759,18 @@ func (f *SettingsStoreGetLatestFunc) History() []SettingsStoreGetLatestFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SettingsStoreGetLatestFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SettingsStoreGetLatestFuncCall is an object that describes an invocation
 // of method GetLatest on an instance of MockSettingsStore.
 type SettingsStoreGetLatestFuncCall struct {
@@ -70611,6 +81882,18 @@ func (f *SettingsStoreGetLatestSchemaSettingsFunc) History() []SettingsStoreGetL
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SettingsStoreGetLatestSchemaSettingsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SettingsStoreGetLatestSchemaSettingsFuncCall is an object that describes
 // an invocation of method GetLatestSchemaSettings on an instance of
 // MockSettingsStore.
@@ -70719,6 +82002,18 @@ func (f *SettingsStoreHandleFunc) History() []SettingsStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SettingsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SettingsStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockSettingsStore.
 type SettingsStoreHandleFuncCall struct {
@@ -70818,6 +82113,18 @@ func (f *SettingsStoreListAllFunc) History() []SettingsStoreListAllFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SettingsStoreListAllFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SettingsStoreListAllFuncCall is an object that describes an invocation of
 // method ListAll on an instance of MockSettingsStore.
 type SettingsStoreListAllFuncCall struct {
@@ -70926,6 +82233,18 @@ func (f *SettingsStoreTransactFunc) History() []SettingsStoreTransactFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SettingsStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SettingsStoreTransactFuncCall is an object that describes an invocation
 // of method Transact on an instance of MockSettingsStore.
 type SettingsStoreTransactFuncCall struct {
@@ -71030,6 +82349,18 @@ func (f *SettingsStoreWithFunc) History() []SettingsStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SettingsStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SettingsStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockSettingsStore.
 type SettingsStoreWithFuncCall struct {
@@ -71128,6 +82459,49 @@ func NewStrictMockSignalConfigurationStore() *MockSignalConfigurationStore {
 	}
 }
 
+// NewStrictMockSignalConfigurationStoreWithT creates a new mock of the
+// SignalConfigurationStore interface. All methods fail t with their arguments
+// and return zero values for all results, unless overwritten.
+func NewStrictMockSignalConfigurationStoreWithT(t testing.TB) *MockSignalConfigurationStore {
+	return &MockSignalConfigurationStore{
+		IsEnabledFunc: &SignalConfigurationStoreIsEnabledFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockSignalConfigurationStore.IsEnabled(%v, %v)", v0, v1)
+				return
+			},
+		},
+		LoadConfigurationsFunc: &SignalConfigurationStoreLoadConfigurationsFunc{
+			defaultHook: func(v0 context.Context, v1 database.LoadSignalConfigurationArgs) (r0 []database.SignalConfiguration, r1 error) {
+				t.Errorf("unexpected call of MockSignalConfigurationStore.LoadConfigurations(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateConfigurationFunc: &SignalConfigurationStoreUpdateConfigurationFunc{
+			defaultHook: func(v0 context.Context, v1 database.UpdateSignalConfigurationArgs) (r0 error) {
+				t.Errorf("unexpected call of MockSignalConfigurationStore.UpdateConfiguration(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithTransactFunc: &SignalConfigurationStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(store database.SignalConfigurationStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockSignalConfigurationStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockSignalConfigurationStore instance with PushHook or PushReturn which was
+// not called.
+func (m *MockSignalConfigurationStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.IsEnabledFunc.assertExpectations(t, "MockSignalConfigurationStore.IsEnabled")
+	m.LoadConfigurationsFunc.assertExpectations(t, "MockSignalConfigurationStore.LoadConfigurations")
+	m.UpdateConfigurationFunc.assertExpectations(t, "MockSignalConfigurationStore.UpdateConfiguration")
+	m.WithTransactFunc.assertExpectations(t, "MockSignalConfigurationStore.WithTransact")
+}
+
 // NewMockSignalConfigurationStoreFrom creates a new mock of the
 // MockSignalConfigurationStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -71229,6 +82603,18 @@ func (f *SignalConfigurationStoreIsEnabledFunc) History() []SignalConfigurationS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SignalConfigurationStoreIsEnabledFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SignalConfigurationStoreIsEnabledFuncCall is an object that describes an
 // invocation of method IsEnabled on an instance of
 // MockSignalConfigurationStore.
@@ -71341,6 +82727,18 @@ func (f *SignalConfigurationStoreLoadConfigurationsFunc) History() []SignalConfi
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SignalConfigurationStoreLoadConfigurationsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SignalConfigurationStoreLoadConfigurationsFuncCall is an object that
 // describes an invocation of method LoadConfigurations on an instance of
 // MockSignalConfigurationStore.
@@ -71453,6 +82851,18 @@ func (f *SignalConfigurationStoreUpdateConfigurationFunc) History() []SignalConf
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SignalConfigurationStoreUpdateConfigurationFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SignalConfigurationStoreUpdateConfigurationFuncCall is an object that
 // describes an invocation of method UpdateConfiguration on an instance of
 // MockSignalConfigurationStore.
@@ -71562,6 +82972,18 @@ func (f *SignalConfigurationStoreWithTransactFunc) History() []SignalConfigurati
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SignalConfigurationStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SignalConfigurationStoreWithTransactFuncCall is an object that describes
 // an invocation of method WithTransact on an instance of
 // MockSignalConfigurationStore.
@@ -71768,6 +83190,105 @@ func NewStrictMockSubRepoPermsStore() *MockSubRepoPermsStore {
 	}
 }
 
+// NewStrictMockSubRepoPermsStoreWithT creates a new mock of the
+// SubRepoPermsStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockSubRepoPermsStoreWithT(t testing.TB) *MockSubRepoPermsStore {
+	return &MockSubRepoPermsStore{
+		DeleteByUserFunc: &SubRepoPermsStoreDeleteByUserFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.DeleteByUser(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &SubRepoPermsStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetFunc: &SubRepoPermsStoreGetFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 api.RepoID) (r0 *authz.SubRepoPermissions, r1 error) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.Get(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		GetByUserFunc: &SubRepoPermsStoreGetByUserFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 map[api.RepoName]authz.SubRepoPermissions, r1 error) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.GetByUser(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByUserAndServiceFunc: &SubRepoPermsStoreGetByUserAndServiceFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string, v3 string) (r0 map[api.ExternalRepoSpec]authz.SubRepoPermissions, r1 error) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.GetByUserAndService(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		HandleFunc: &SubRepoPermsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.Handle()")
+				return
+			},
+		},
+		RepoIDSupportedFunc: &SubRepoPermsStoreRepoIDSupportedFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.RepoIDSupported(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RepoSupportedFunc: &SubRepoPermsStoreRepoSupportedFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoName) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.RepoSupported(%v, %v)", v0, v1)
+				return
+			},
+		},
+		TransactFunc: &SubRepoPermsStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.SubRepoPermsStore, r1 error) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.Transact(%v)", v0)
+				return
+			},
+		},
+		UpsertFunc: &SubRepoPermsStoreUpsertFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 api.RepoID, v3 authz.SubRepoPermissions) (r0 error) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.Upsert(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		UpsertWithSpecFunc: &SubRepoPermsStoreUpsertWithSpecFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 api.ExternalRepoSpec, v3 authz.SubRepoPermissions) (r0 error) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.UpsertWithSpec(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		WithFunc: &SubRepoPermsStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.SubRepoPermsStore) {
+				t.Errorf("unexpected call of MockSubRepoPermsStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockSubRepoPermsStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockSubRepoPermsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.DeleteByUserFunc.assertExpectations(t, "MockSubRepoPermsStore.DeleteByUser")
+	m.DoneFunc.assertExpectations(t, "MockSubRepoPermsStore.Done")
+	m.GetFunc.assertExpectations(t, "MockSubRepoPermsStore.Get")
+	m.GetByUserFunc.assertExpectations(t, "MockSubRepoPermsStore.GetByUser")
+	m.GetByUserAndServiceFunc.assertExpectations(t, "MockSubRepoPermsStore.GetByUserAndService")
+	m.HandleFunc.assertExpectations(t, "MockSubRepoPermsStore.Handle")
+	m.RepoIDSupportedFunc.assertExpectations(t, "MockSubRepoPermsStore.RepoIDSupported")
+	m.RepoSupportedFunc.assertExpectations(t, "MockSubRepoPermsStore.RepoSupported")
+	m.TransactFunc.assertExpectations(t, "MockSubRepoPermsStore.Transact")
+	m.UpsertFunc.assertExpectations(t, "MockSubRepoPermsStore.Upsert")
+	m.UpsertWithSpecFunc.assertExpectations(t, "MockSubRepoPermsStore.UpsertWithSpec")
+	m.WithFunc.assertExpectations(t, "MockSubRepoPermsStore.With")
+}
+
 // NewMockSubRepoPermsStoreFrom creates a new mock of the
 // MockSubRepoPermsStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -71892,6 +83413,18 @@ func (f *SubRepoPermsStoreDeleteByUserFunc) History() []SubRepoPermsStoreDeleteB
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreDeleteByUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreDeleteByUserFuncCall is an object that describes an
 // invocation of method DeleteByUser on an instance of
 // MockSubRepoPermsStore.
@@ -71998,6 +83531,18 @@ func (f *SubRepoPermsStoreDoneFunc) History() []SubRepoPermsStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreDoneFuncCall is an object that describes an invocation
 // of method Done on an instance of MockSubRepoPermsStore.
 type SubRepoPermsStoreDoneFuncCall struct {
@@ -72100,6 +83645,18 @@ func (f *SubRepoPermsStoreGetFunc) History() []SubRepoPermsStoreGetFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreGetFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreGetFuncCall is an object that describes an invocation of
 // method Get on an instance of MockSubRepoPermsStore.
 type SubRepoPermsStoreGetFuncCall struct {
@@ -72211,6 +83768,18 @@ func (f *SubRepoPermsStoreGetByUserFunc) History() []SubRepoPermsStoreGetByUserF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreGetByUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreGetByUserFuncCall is an object that describes an
 // invocation of method GetByUser on an instance of MockSubRepoPermsStore.
 type SubRepoPermsStoreGetByUserFuncCall struct {
@@ -72322,6 +83891,18 @@ func (f *SubRepoPermsStoreGetByUserAndServiceFunc) History() []SubRepoPermsStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreGetByUserAndServiceFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreGetByUserAndServiceFuncCall is an object that describes
 // an invocation of method GetByUserAndService on an instance of
 // MockSubRepoPermsStore.
@@ -72437,6 +84018,18 @@ func (f *SubRepoPermsStoreHandleFunc) History() []SubRepoPermsStoreHandleFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockSubRepoPermsStore.
 type SubRepoPermsStoreHandleFuncCall struct {
@@ -72538,6 +84131,18 @@ func (f *SubRepoPermsStoreRepoIDSupportedFunc) History() []SubRepoPermsStoreRepo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreRepoIDSupportedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreRepoIDSupportedFuncCall is an object that describes an
 // invocation of method RepoIDSupported on an instance of
 // MockSubRepoPermsStore.
@@ -72648,6 +84253,18 @@ func (f *SubRepoPermsStoreRepoSupportedFunc) History() []SubRepoPermsStoreRepoSu
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreRepoSupportedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreRepoSupportedFuncCall is an object that describes an
 // invocation of method RepoSupported on an instance of
 // MockSubRepoPermsStore.
@@ -72757,6 +84374,18 @@ func (f *SubRepoPermsStoreTransactFunc) History() []SubRepoPermsStoreTransactFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreTransactFuncCall is an object that describes an
 // invocation of method Transact on an instance of MockSubRepoPermsStore.
 type SubRepoPermsStoreTransactFuncCall struct {
@@ -72862,6 +84491,18 @@ func (f *SubRepoPermsStoreUpsertFunc) History() []SubRepoPermsStoreUpsertFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreUpsertFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreUpsertFuncCall is an object that describes an invocation
 // of method Upsert on an instance of MockSubRepoPermsStore.
 type SubRepoPermsStoreUpsertFuncCall struct {
@@ -72975,6 +84616,18 @@ func (f *SubRepoPermsStoreUpsertWithSpecFunc) History() []SubRepoPermsStoreUpser
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreUpsertWithSpecFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreUpsertWithSpecFuncCall is an object that describes an
 // invocation of method UpsertWithSpec on an instance of
 // MockSubRepoPermsStore.
@@ -73087,6 +84740,18 @@ func (f *SubRepoPermsStoreWithFunc) History() []SubRepoPermsStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *SubRepoPermsStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // SubRepoPermsStoreWithFuncCall is an object that describes an invocation
 // of method With on an instance of MockSubRepoPermsStore.
 type SubRepoPermsStoreWithFuncCall struct {
@@ -73325,6 +84990,125 @@ func NewStrictMockTeamStore() *MockTeamStore {
 	}
 }
 
+// NewStrictMockTeamStoreWithT creates a new mock of the TeamStore interface.
+// All methods fail t with their arguments and return zero values for all
+// results, unless overwritten.
+func NewStrictMockTeamStoreWithT(t testing.TB) *MockTeamStore {
+	return &MockTeamStore{
+		ContainsTeamFunc: &TeamStoreContainsTeamFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 database.ListTeamsOpts) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockTeamStore.ContainsTeam(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CountTeamMembersFunc: &TeamStoreCountTeamMembersFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListTeamMembersOpts) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockTeamStore.CountTeamMembers(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountTeamsFunc: &TeamStoreCountTeamsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListTeamsOpts) (r0 int32, r1 error) {
+				t.Errorf("unexpected call of MockTeamStore.CountTeams(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateTeamFunc: &TeamStoreCreateTeamFunc{
+			defaultHook: func(v0 context.Context, v1 *types.Team) (r0 *types.Team, r1 error) {
+				t.Errorf("unexpected call of MockTeamStore.CreateTeam(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateTeamMemberFunc: &TeamStoreCreateTeamMemberFunc{
+			defaultHook: func(v0 context.Context, v1 ...*types.TeamMember) (r0 error) {
+				t.Errorf("unexpected call of MockTeamStore.CreateTeamMember(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteTeamFunc: &TeamStoreDeleteTeamFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockTeamStore.DeleteTeam(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteTeamMemberFunc: &TeamStoreDeleteTeamMemberFunc{
+			defaultHook: func(v0 context.Context, v1 ...*types.TeamMember) (r0 error) {
+				t.Errorf("unexpected call of MockTeamStore.DeleteTeamMember(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &TeamStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockTeamStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetTeamByIDFunc: &TeamStoreGetTeamByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *types.Team, r1 error) {
+				t.Errorf("unexpected call of MockTeamStore.GetTeamByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetTeamByNameFunc: &TeamStoreGetTeamByNameFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *types.Team, r1 error) {
+				t.Errorf("unexpected call of MockTeamStore.GetTeamByName(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &TeamStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockTeamStore.Handle()")
+				return
+			},
+		},
+		IsTeamMemberFunc: &TeamStoreIsTeamMemberFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 int32) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockTeamStore.IsTeamMember(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		ListTeamMembersFunc: &TeamStoreListTeamMembersFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListTeamMembersOpts) (r0 []*types.TeamMember, r1 *database.TeamMemberListCursor, r2 error) {
+				t.Errorf("unexpected call of MockTeamStore.ListTeamMembers(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListTeamsFunc: &TeamStoreListTeamsFunc{
+			defaultHook: func(v0 context.Context, v1 database.ListTeamsOpts) (r0 []*types.Team, r1 int32, r2 error) {
+				t.Errorf("unexpected call of MockTeamStore.ListTeams(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateTeamFunc: &TeamStoreUpdateTeamFunc{
+			defaultHook: func(v0 context.Context, v1 *types.Team) (r0 error) {
+				t.Errorf("unexpected call of MockTeamStore.UpdateTeam(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockTeamStore instance with PushHook or PushReturn which was not called.
+func (m *MockTeamStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.ContainsTeamFunc.assertExpectations(t, "MockTeamStore.ContainsTeam")
+	m.CountTeamMembersFunc.assertExpectations(t, "MockTeamStore.CountTeamMembers")
+	m.CountTeamsFunc.assertExpectations(t, "MockTeamStore.CountTeams")
+	m.CreateTeamFunc.assertExpectations(t, "MockTeamStore.CreateTeam")
+	m.CreateTeamMemberFunc.assertExpectations(t, "MockTeamStore.CreateTeamMember")
+	m.DeleteTeamFunc.assertExpectations(t, "MockTeamStore.DeleteTeam")
+	m.DeleteTeamMemberFunc.assertExpectations(t, "MockTeamStore.DeleteTeamMember")
+	m.DoneFunc.assertExpectations(t, "MockTeamStore.Done")
+	m.GetTeamByIDFunc.assertExpectations(t, "MockTeamStore.GetTeamByID")
+	m.GetTeamByNameFunc.assertExpectations(t, "MockTeamStore.GetTeamByName")
+	m.HandleFunc.assertExpectations(t, "MockTeamStore.Handle")
+	m.IsTeamMemberFunc.assertExpectations(t, "MockTeamStore.IsTeamMember")
+	m.ListTeamMembersFunc.assertExpectations(t, "MockTeamStore.ListTeamMembers")
+	m.ListTeamsFunc.assertExpectations(t, "MockTeamStore.ListTeams")
+	m.UpdateTeamFunc.assertExpectations(t, "MockTeamStore.UpdateTeam")
+}
+
 // NewMockTeamStoreFrom creates a new mock of the MockTeamStore interface.
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockTeamStoreFrom(i database.TeamStore) *MockTeamStore {
@@ -73456,6 +85240,18 @@ func (f *TeamStoreContainsTeamFunc) History() []TeamStoreContainsTeamFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreContainsTeamFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreContainsTeamFuncCall is an object that describes an invocation
 // of method ContainsTeam on an instance of MockTeamStore.
 type TeamStoreContainsTeamFuncCall struct {
@@ -73567,6 +85363,18 @@ func (f *TeamStoreCountTeamMembersFunc) History() []TeamStoreCountTeamMembersFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreCountTeamMembersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreCountTeamMembersFuncCall is an object that describes an
 // invocation of method CountTeamMembers on an instance of MockTeamStore.
 type TeamStoreCountTeamMembersFuncCall struct {
@@ -73674,6 +85482,18 @@ func (f *TeamStoreCountTeamsFunc) History() []TeamStoreCountTeamsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreCountTeamsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreCountTeamsFuncCall is an object that describes an invocation of
 // method CountTeams on an instance of MockTeamStore.
 type TeamStoreCountTeamsFuncCall struct {
@@ -73781,6 +85601,18 @@ func (f *TeamStoreCreateTeamFunc) History() []TeamStoreCreateTeamFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreCreateTeamFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreCreateTeamFuncCall is an object that describes an invocation of
 // method CreateTeam on an instance of MockTeamStore.
 type TeamStoreCreateTeamFuncCall struct {
@@ -73889,6 +85721,18 @@ func (f *TeamStoreCreateTeamMemberFunc) History() []TeamStoreCreateTeamMemberFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreCreateTeamMemberFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreCreateTeamMemberFuncCall is an object that describes an
 // invocation of method CreateTeamMember on an instance of MockTeamStore.
 type TeamStoreCreateTeamMemberFuncCall struct {
@@ -74000,6 +85844,18 @@ func (f *TeamStoreDeleteTeamFunc) History() []TeamStoreDeleteTeamFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreDeleteTeamFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreDeleteTeamFuncCall is an object that describes an invocation of
 // method DeleteTeam on an instance of MockTeamStore.
 type TeamStoreDeleteTeamFuncCall struct {
@@ -74105,6 +85961,18 @@ func (f *TeamStoreDeleteTeamMemberFunc) History() []TeamStoreDeleteTeamMemberFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreDeleteTeamMemberFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreDeleteTeamMemberFuncCall is an object that describes an
 // invocation of method DeleteTeamMember on an instance of MockTeamStore.
 type TeamStoreDeleteTeamMemberFuncCall struct {
@@ -74216,6 +86084,18 @@ func (f *TeamStoreDoneFunc) History() []TeamStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreDoneFuncCall is an object that describes an invocation of method
 // Done on an instance of MockTeamStore.
 type TeamStoreDoneFuncCall struct {
@@ -74318,6 +86198,18 @@ func (f *TeamStoreGetTeamByIDFunc) History() []TeamStoreGetTeamByIDFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreGetTeamByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreGetTeamByIDFuncCall is an object that describes an invocation of
 // method GetTeamByID on an instance of MockTeamStore.
 type TeamStoreGetTeamByIDFuncCall struct {
@@ -74426,6 +86318,18 @@ func (f *TeamStoreGetTeamByNameFunc) History() []TeamStoreGetTeamByNameFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreGetTeamByNameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreGetTeamByNameFuncCall is an object that describes an invocation
 // of method GetTeamByName on an instance of MockTeamStore.
 type TeamStoreGetTeamByNameFuncCall struct {
@@ -74533,6 +86437,18 @@ func (f *TeamStoreHandleFunc) History() []TeamStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockTeamStore.
 type TeamStoreHandleFuncCall struct {
@@ -74632,6 +86548,18 @@ func (f *TeamStoreIsTeamMemberFunc) History() []TeamStoreIsTeamMemberFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreIsTeamMemberFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreIsTeamMemberFuncCall is an object that describes an invocation
 // of method IsTeamMember on an instance of MockTeamStore.
 type TeamStoreIsTeamMemberFuncCall struct {
@@ -74743,6 +86671,18 @@ func (f *TeamStoreListTeamMembersFunc) History() []TeamStoreListTeamMembersFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreListTeamMembersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreListTeamMembersFuncCall is an object that describes an
 // invocation of method ListTeamMembers on an instance of MockTeamStore.
 type TeamStoreListTeamMembersFuncCall struct {
@@ -74853,6 +86793,18 @@ func (f *TeamStoreListTeamsFunc) History() []TeamStoreListTeamsFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreListTeamsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreListTeamsFuncCall is an object that describes an invocation of
 // method ListTeams on an instance of MockTeamStore.
 type TeamStoreListTeamsFuncCall struct {
@@ -74963,6 +86915,18 @@ func (f *TeamStoreUpdateTeamFunc) History() []TeamStoreUpdateTeamFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TeamStoreUpdateTeamFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TeamStoreUpdateTeamFuncCall is an object that describes an invocation of
 // method UpdateTeam on an instance of MockTeamStore.
 type TeamStoreUpdateTeamFuncCall struct {
@@ -75116,6 +87080,77 @@ func NewStrictMockTelemetryEventsExportQueueStore() *MockTelemetryEventsExportQu
 	}
 }
 
+// NewStrictMockTelemetryEventsExportQueueStoreWithT creates a new mock of the
+// TelemetryEventsExportQueueStore interface. All methods fail t with their
+// arguments and return zero values for all results, unless overwritten.
+func NewStrictMockTelemetryEventsExportQueueStoreWithT(t testing.TB) *MockTelemetryEventsExportQueueStore {
+	return &MockTelemetryEventsExportQueueStore{
+		CountRecentlyExportedFunc: &TelemetryEventsExportQueueStoreCountRecentlyExportedFunc{
+			defaultHook: func(v0 context.Context) (r0 int64, r1 error) {
+				t.Errorf("unexpected call of MockTelemetryEventsExportQueueStore.CountRecentlyExported(%v)", v0)
+				return
+			},
+		},
+		CountUnexportedFunc: &TelemetryEventsExportQueueStoreCountUnexportedFunc{
+			defaultHook: func(v0 context.Context) (r0 int64, r1 time.Time, r2 error) {
+				t.Errorf("unexpected call of MockTelemetryEventsExportQueueStore.CountUnexported(%v)", v0)
+				return
+			},
+		},
+		DeletedExportedFunc: &TelemetryEventsExportQueueStoreDeletedExportedFunc{
+			defaultHook: func(v0 context.Context, v1 time.Time) (r0 int64, r1 error) {
+				t.Errorf("unexpected call of MockTelemetryEventsExportQueueStore.DeletedExported(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &TelemetryEventsExportQueueStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockTelemetryEventsExportQueueStore.Handle()")
+				return
+			},
+		},
+		ListForExportFunc: &TelemetryEventsExportQueueStoreListForExportFunc{
+			defaultHook: func(v0 context.Context, v1 int) (r0 []*v1.Event, r1 error) {
+				t.Errorf("unexpected call of MockTelemetryEventsExportQueueStore.ListForExport(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListRecentlyExportedFunc: &TelemetryEventsExportQueueStoreListRecentlyExportedFunc{
+			defaultHook: func(v0 context.Context, v1 int, v2 *time.Time) (r0 []database.ExportedTelemetryEvent, r1 error) {
+				t.Errorf("unexpected call of MockTelemetryEventsExportQueueStore.ListRecentlyExported(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		MarkAsExportedFunc: &TelemetryEventsExportQueueStoreMarkAsExportedFunc{
+			defaultHook: func(v0 context.Context, v1 []string) (r0 error) {
+				t.Errorf("unexpected call of MockTelemetryEventsExportQueueStore.MarkAsExported(%v, %v)", v0, v1)
+				return
+			},
+		},
+		QueueForExportFunc: &TelemetryEventsExportQueueStoreQueueForExportFunc{
+			defaultHook: func(v0 context.Context, v1 []*v1.Event) (r0 error) {
+				t.Errorf("unexpected call of MockTelemetryEventsExportQueueStore.QueueForExport(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockTelemetryEventsExportQueueStore instance with PushHook or PushReturn
+// which was not called.
+func (m *MockTelemetryEventsExportQueueStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountRecentlyExportedFunc.assertExpectations(t, "MockTelemetryEventsExportQueueStore.CountRecentlyExported")
+	m.CountUnexportedFunc.assertExpectations(t, "MockTelemetryEventsExportQueueStore.CountUnexported")
+	m.DeletedExportedFunc.assertExpectations(t, "MockTelemetryEventsExportQueueStore.DeletedExported")
+	m.HandleFunc.assertExpectations(t, "MockTelemetryEventsExportQueueStore.Handle")
+	m.ListForExportFunc.assertExpectations(t, "MockTelemetryEventsExportQueueStore.ListForExport")
+	m.ListRecentlyExportedFunc.assertExpectations(t, "MockTelemetryEventsExportQueueStore.ListRecentlyExported")
+	m.MarkAsExportedFunc.assertExpectations(t, "MockTelemetryEventsExportQueueStore.MarkAsExported")
+	m.QueueForExportFunc.assertExpectations(t, "MockTelemetryEventsExportQueueStore.QueueForExport")
+}
+
 // NewMockTelemetryEventsExportQueueStoreFrom creates a new mock of the
 // MockTelemetryEventsExportQueueStore interface. All methods delegate to
 // the given implementation, unless overwritten.
@@ -75231,6 +87266,18 @@ func (f *TelemetryEventsExportQueueStoreCountRecentlyExportedFunc) History() []T
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TelemetryEventsExportQueueStoreCountRecentlyExportedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TelemetryEventsExportQueueStoreCountRecentlyExportedFuncCall is an object
 // that describes an invocation of method CountRecentlyExported on an
 // instance of MockTelemetryEventsExportQueueStore.
@@ -75340,6 +87387,18 @@ func (f *TelemetryEventsExportQueueStoreCountUnexportedFunc) History() []Telemet
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TelemetryEventsExportQueueStoreCountUnexportedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TelemetryEventsExportQueueStoreCountUnexportedFuncCall is an object that
 // describes an invocation of method CountUnexported on an instance of
 // MockTelemetryEventsExportQueueStore.
@@ -75452,6 +87511,18 @@ func (f *TelemetryEventsExportQueueStoreDeletedExportedFunc) History() []Telemet
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TelemetryEventsExportQueueStoreDeletedExportedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TelemetryEventsExportQueueStoreDeletedExportedFuncCall is an object that
 // describes an invocation of method DeletedExported on an instance of
 // MockTelemetryEventsExportQueueStore.
@@ -75564,6 +87635,18 @@ func (f *TelemetryEventsExportQueueStoreHandleFunc) History() []TelemetryEventsE
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TelemetryEventsExportQueueStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TelemetryEventsExportQueueStoreHandleFuncCall is an object that describes
 // an invocation of method Handle on an instance of
 // MockTelemetryEventsExportQueueStore.
@@ -75667,6 +87750,18 @@ func (f *TelemetryEventsExportQueueStoreListForExportFunc) History() []Telemetry
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TelemetryEventsExportQueueStoreListForExportFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TelemetryEventsExportQueueStoreListForExportFuncCall is an object that
 // describes an invocation of method ListForExport on an instance of
 // MockTelemetryEventsExportQueueStore.
@@ -75779,6 +87874,18 @@ func (f *TelemetryEventsExportQueueStoreListRecentlyExportedFunc) History() []Te
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TelemetryEventsExportQueueStoreListRecentlyExportedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TelemetryEventsExportQueueStoreListRecentlyExportedFuncCall is an object
 // that describes an invocation of method ListRecentlyExported on an
 // instance of MockTelemetryEventsExportQueueStore.
@@ -75894,6 +88001,18 @@ func (f *TelemetryEventsExportQueueStoreMarkAsExportedFunc) History() []Telemetr
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TelemetryEventsExportQueueStoreMarkAsExportedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TelemetryEventsExportQueueStoreMarkAsExportedFuncCall is an object that
 // describes an invocation of method MarkAsExported on an instance of
 // MockTelemetryEventsExportQueueStore.
@@ -76003,6 +88122,18 @@ func (f *TelemetryEventsExportQueueStoreQueueForExportFunc) History() []Telemetr
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TelemetryEventsExportQueueStoreQueueForExportFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TelemetryEventsExportQueueStoreQueueForExportFuncCall is an object that
 // describes an invocation of method QueueForExport on an instance of
 // MockTelemetryEventsExportQueueStore.
@@ -76106,6 +88237,49 @@ func NewStrictMockTemporarySettingsStore() *MockTemporarySettingsStore {
 	}
 }
 
+// NewStrictMockTemporarySettingsStoreWithT creates a new mock of the
+// TemporarySettingsStore interface. All methods fail t with their arguments
+// and return zero values for all results, unless overwritten.
+func NewStrictMockTemporarySettingsStoreWithT(t testing.TB) *MockTemporarySettingsStore {
+	return &MockTemporarySettingsStore{
+		EditTemporarySettingsFunc: &TemporarySettingsStoreEditTemporarySettingsFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 error) {
+				t.Errorf("unexpected call of MockTemporarySettingsStore.EditTemporarySettings(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		GetTemporarySettingsFunc: &TemporarySettingsStoreGetTemporarySettingsFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *temporarysettings.TemporarySettings, r1 error) {
+				t.Errorf("unexpected call of MockTemporarySettingsStore.GetTemporarySettings(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &TemporarySettingsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockTemporarySettingsStore.Handle()")
+				return
+			},
+		},
+		OverwriteTemporarySettingsFunc: &TemporarySettingsStoreOverwriteTemporarySettingsFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 error) {
+				t.Errorf("unexpected call of MockTemporarySettingsStore.OverwriteTemporarySettings(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockTemporarySettingsStore instance with PushHook or PushReturn which was
+// not called.
+func (m *MockTemporarySettingsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.EditTemporarySettingsFunc.assertExpectations(t, "MockTemporarySettingsStore.EditTemporarySettings")
+	m.GetTemporarySettingsFunc.assertExpectations(t, "MockTemporarySettingsStore.GetTemporarySettings")
+	m.HandleFunc.assertExpectations(t, "MockTemporarySettingsStore.Handle")
+	m.OverwriteTemporarySettingsFunc.assertExpectations(t, "MockTemporarySettingsStore.OverwriteTemporarySettings")
+}
+
 // NewMockTemporarySettingsStoreFrom creates a new mock of the
 // MockTemporarySettingsStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -76208,6 +88382,18 @@ func (f *TemporarySettingsStoreEditTemporarySettingsFunc) History() []TemporaryS
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TemporarySettingsStoreEditTemporarySettingsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TemporarySettingsStoreEditTemporarySettingsFuncCall is an object that
 // describes an invocation of method EditTemporarySettings on an instance of
 // MockTemporarySettingsStore.
@@ -76320,6 +88506,18 @@ func (f *TemporarySettingsStoreGetTemporarySettingsFunc) History() []TemporarySe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TemporarySettingsStoreGetTemporarySettingsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TemporarySettingsStoreGetTemporarySettingsFuncCall is an object that
 // describes an invocation of method GetTemporarySettings on an instance of
 // MockTemporarySettingsStore.
@@ -76429,6 +88627,18 @@ func (f *TemporarySettingsStoreHandleFunc) History() []TemporarySettingsStoreHan
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TemporarySettingsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TemporarySettingsStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockTemporarySettingsStore.
 type TemporarySettingsStoreHandleFuncCall struct {
@@ -76532,6 +88742,18 @@ func (f *TemporarySettingsStoreOverwriteTemporarySettingsFunc) History() []Tempo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *TemporarySettingsStoreOverwriteTemporarySettingsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // TemporarySettingsStoreOverwriteTemporarySettingsFuncCall is an object
 // that describes an invocation of method OverwriteTemporarySettings on an
 // instance of MockTemporarySettingsStore.
@@ -76702,6 +88924,84 @@ func NewStrictMockUserCredentialsStore() *MockUserCredentialsStore {
 	}
 }
 
+// NewStrictMockUserCredentialsStoreWithT creates a new mock of the
+// UserCredentialsStore interface. All methods fail t with their arguments and
+// return zero values for all results, unless overwritten.
+func NewStrictMockUserCredentialsStoreWithT(t testing.TB) *MockUserCredentialsStore {
+	return &MockUserCredentialsStore{
+		CreateFunc: &UserCredentialsStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 database.UserCredentialScope, v2 auth.Authenticator) (r0 *database.UserCredential, r1 error) {
+				t.Errorf("unexpected call of MockUserCredentialsStore.Create(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DeleteFunc: &UserCredentialsStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 error) {
+				t.Errorf("unexpected call of MockUserCredentialsStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &UserCredentialsStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *database.UserCredential, r1 error) {
+				t.Errorf("unexpected call of MockUserCredentialsStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByScopeFunc: &UserCredentialsStoreGetByScopeFunc{
+			defaultHook: func(v0 context.Context, v1 database.UserCredentialScope) (r0 *database.UserCredential, r1 error) {
+				t.Errorf("unexpected call of MockUserCredentialsStore.GetByScope(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &UserCredentialsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockUserCredentialsStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &UserCredentialsStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.UserCredentialsListOpts) (r0 []*database.UserCredential, r1 int, r2 error) {
+				t.Errorf("unexpected call of MockUserCredentialsStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateFunc: &UserCredentialsStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 *database.UserCredential) (r0 error) {
+				t.Errorf("unexpected call of MockUserCredentialsStore.Update(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &UserCredentialsStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.UserCredentialsStore) {
+				t.Errorf("unexpected call of MockUserCredentialsStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &UserCredentialsStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.UserCredentialsStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockUserCredentialsStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockUserCredentialsStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockUserCredentialsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CreateFunc.assertExpectations(t, "MockUserCredentialsStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockUserCredentialsStore.Delete")
+	m.GetByIDFunc.assertExpectations(t, "MockUserCredentialsStore.GetByID")
+	m.GetByScopeFunc.assertExpectations(t, "MockUserCredentialsStore.GetByScope")
+	m.HandleFunc.assertExpectations(t, "MockUserCredentialsStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockUserCredentialsStore.List")
+	m.UpdateFunc.assertExpectations(t, "MockUserCredentialsStore.Update")
+	m.WithFunc.assertExpectations(t, "MockUserCredentialsStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockUserCredentialsStore.WithTransact")
+}
+
 // NewMockUserCredentialsStoreFrom creates a new mock of the
 // MockUserCredentialsStore interface. All methods delegate to the given
 // implementation, unless overwritten.
@@ -76816,6 +89116,18 @@ func (f *UserCredentialsStoreCreateFunc) History() []UserCredentialsStoreCreateF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserCredentialsStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserCredentialsStoreCreateFuncCall is an object that describes an
 // invocation of method Create on an instance of MockUserCredentialsStore.
 type UserCredentialsStoreCreateFuncCall struct {
@@ -76927,6 +89239,18 @@ func (f *UserCredentialsStoreDeleteFunc) History() []UserCredentialsStoreDeleteF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserCredentialsStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserCredentialsStoreDeleteFuncCall is an object that describes an
 // invocation of method Delete on an instance of MockUserCredentialsStore.
 type UserCredentialsStoreDeleteFuncCall struct {
@@ -77032,6 +89356,18 @@ func (f *UserCredentialsStoreGetByIDFunc) History() []UserCredentialsStoreGetByI
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserCredentialsStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserCredentialsStoreGetByIDFuncCall is an object that describes an
 // invocation of method GetByID on an instance of MockUserCredentialsStore.
 type UserCredentialsStoreGetByIDFuncCall struct {
@@ -77141,6 +89477,18 @@ func (f *UserCredentialsStoreGetByScopeFunc) History() []UserCredentialsStoreGet
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserCredentialsStoreGetByScopeFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserCredentialsStoreGetByScopeFuncCall is an object that describes an
 // invocation of method GetByScope on an instance of
 // MockUserCredentialsStore.
@@ -77250,6 +89598,18 @@ func (f *UserCredentialsStoreHandleFunc) History() []UserCredentialsStoreHandleF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserCredentialsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserCredentialsStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of MockUserCredentialsStore.
 type UserCredentialsStoreHandleFuncCall struct {
@@ -77349,6 +89709,18 @@ func (f *UserCredentialsStoreListFunc) History() []UserCredentialsStoreListFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserCredentialsStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserCredentialsStoreListFuncCall is an object that describes an
 // invocation of method List on an instance of MockUserCredentialsStore.
 type UserCredentialsStoreListFuncCall struct {
@@ -77460,6 +89832,18 @@ func (f *UserCredentialsStoreUpdateFunc) History() []UserCredentialsStoreUpdateF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserCredentialsStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserCredentialsStoreUpdateFuncCall is an object that describes an
 // invocation of method Update on an instance of MockUserCredentialsStore.
 type UserCredentialsStoreUpdateFuncCall struct {
@@ -77565,6 +89949,18 @@ func (f *UserCredentialsStoreWithFunc) History() []UserCredentialsStoreWithFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserCredentialsStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserCredentialsStoreWithFuncCall is an object that describes an
 // invocation of method With on an instance of MockUserCredentialsStore.
 type UserCredentialsStoreWithFuncCall struct {
@@ -77669,6 +90065,18 @@ func (f *UserCredentialsStoreWithTransactFunc) History() []UserCredentialsStoreW
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserCredentialsStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserCredentialsStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of
 // MockUserCredentialsStore.
@@ -77940,6 +90348,140 @@ func NewStrictMockUserEmailsStore() *MockUserEmailsStore {
 	}
 }
 
+// NewStrictMockUserEmailsStoreWithT creates a new mock of the UserEmailsStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockUserEmailsStoreWithT(t testing.TB) *MockUserEmailsStore {
+	return &MockUserEmailsStore{
+		AddFunc: &UserEmailsStoreAddFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string, v3 *string) (r0 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.Add(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		DoneFunc: &UserEmailsStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.Done(%v)", v0)
+				return
+			},
+		},
+		GetFunc: &UserEmailsStoreGetFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 string, r1 bool, r2 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.Get(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		GetInitialSiteAdminInfoFunc: &UserEmailsStoreGetInitialSiteAdminInfoFunc{
+			defaultHook: func(v0 context.Context) (r0 string, r1 bool, r2 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.GetInitialSiteAdminInfo(%v)", v0)
+				return
+			},
+		},
+		GetLatestVerificationSentEmailFunc: &UserEmailsStoreGetLatestVerificationSentEmailFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *database.UserEmail, r1 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.GetLatestVerificationSentEmail(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetPrimaryEmailFunc: &UserEmailsStoreGetPrimaryEmailFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 string, r1 bool, r2 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.GetPrimaryEmail(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetVerifiedEmailsFunc: &UserEmailsStoreGetVerifiedEmailsFunc{
+			defaultHook: func(v0 context.Context, v1 ...string) (r0 []*database.UserEmail, r1 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.GetVerifiedEmails(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &UserEmailsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockUserEmailsStore.Handle()")
+				return
+			},
+		},
+		HasVerifiedEmailFunc: &UserEmailsStoreHasVerifiedEmailFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.HasVerifiedEmail(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListByUserFunc: &UserEmailsStoreListByUserFunc{
+			defaultHook: func(v0 context.Context, v1 database.UserEmailsListOptions) (r0 []*database.UserEmail, r1 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.ListByUser(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RemoveFunc: &UserEmailsStoreRemoveFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.Remove(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetLastVerificationFunc: &UserEmailsStoreSetLastVerificationFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string, v3 string, v4 time.Time) (r0 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.SetLastVerification(%v, %v, %v, %v, %v)", v0, v1, v2, v3, v4)
+				return
+			},
+		},
+		SetPrimaryEmailFunc: &UserEmailsStoreSetPrimaryEmailFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.SetPrimaryEmail(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetVerifiedFunc: &UserEmailsStoreSetVerifiedFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string, v3 bool) (r0 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.SetVerified(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		TransactFunc: &UserEmailsStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.UserEmailsStore, r1 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.Transact(%v)", v0)
+				return
+			},
+		},
+		VerifyFunc: &UserEmailsStoreVerifyFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string, v3 string) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockUserEmailsStore.Verify(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		WithFunc: &UserEmailsStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.UserEmailsStore) {
+				t.Errorf("unexpected call of MockUserEmailsStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockUserEmailsStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockUserEmailsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.AddFunc.assertExpectations(t, "MockUserEmailsStore.Add")
+	m.DoneFunc.assertExpectations(t, "MockUserEmailsStore.Done")
+	m.GetFunc.assertExpectations(t, "MockUserEmailsStore.Get")
+	m.GetInitialSiteAdminInfoFunc.assertExpectations(t, "MockUserEmailsStore.GetInitialSiteAdminInfo")
+	m.GetLatestVerificationSentEmailFunc.assertExpectations(t, "MockUserEmailsStore.GetLatestVerificationSentEmail")
+	m.GetPrimaryEmailFunc.assertExpectations(t, "MockUserEmailsStore.GetPrimaryEmail")
+	m.GetVerifiedEmailsFunc.assertExpectations(t, "MockUserEmailsStore.GetVerifiedEmails")
+	m.HandleFunc.assertExpectations(t, "MockUserEmailsStore.Handle")
+	m.HasVerifiedEmailFunc.assertExpectations(t, "MockUserEmailsStore.HasVerifiedEmail")
+	m.ListByUserFunc.assertExpectations(t, "MockUserEmailsStore.ListByUser")
+	m.RemoveFunc.assertExpectations(t, "MockUserEmailsStore.Remove")
+	m.SetLastVerificationFunc.assertExpectations(t, "MockUserEmailsStore.SetLastVerification")
+	m.SetPrimaryEmailFunc.assertExpectations(t, "MockUserEmailsStore.SetPrimaryEmail")
+	m.SetVerifiedFunc.assertExpectations(t, "MockUserEmailsStore.SetVerified")
+	m.TransactFunc.assertExpectations(t, "MockUserEmailsStore.Transact")
+	m.VerifyFunc.assertExpectations(t, "MockUserEmailsStore.Verify")
+	m.WithFunc.assertExpectations(t, "MockUserEmailsStore.With")
+}
+
 // NewMockUserEmailsStoreFrom creates a new mock of the MockUserEmailsStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -78078,6 +90620,18 @@ func (f *UserEmailsStoreAddFunc) History() []UserEmailsStoreAddFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreAddFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreAddFuncCall is an object that describes an invocation of
 // method Add on an instance of MockUserEmailsStore.
 type UserEmailsStoreAddFuncCall struct {
@@ -78189,6 +90743,18 @@ func (f *UserEmailsStoreDoneFunc) History() []UserEmailsStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreDoneFuncCall is an object that describes an invocation of
 // method Done on an instance of MockUserEmailsStore.
 type UserEmailsStoreDoneFuncCall struct {
@@ -78291,6 +90857,18 @@ func (f *UserEmailsStoreGetFunc) History() []UserEmailsStoreGetFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreGetFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreGetFuncCall is an object that describes an invocation of
 // method Get on an instance of MockUserEmailsStore.
 type UserEmailsStoreGetFuncCall struct {
@@ -78408,6 +90986,18 @@ func (f *UserEmailsStoreGetInitialSiteAdminInfoFunc) History() []UserEmailsStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreGetInitialSiteAdminInfoFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreGetInitialSiteAdminInfoFuncCall is an object that
 // describes an invocation of method GetInitialSiteAdminInfo on an instance
 // of MockUserEmailsStore.
@@ -78520,6 +91110,18 @@ func (f *UserEmailsStoreGetLatestVerificationSentEmailFunc) History() []UserEmai
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreGetLatestVerificationSentEmailFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreGetLatestVerificationSentEmailFuncCall is an object that
 // describes an invocation of method GetLatestVerificationSentEmail on an
 // instance of MockUserEmailsStore.
@@ -78630,6 +91232,18 @@ func (f *UserEmailsStoreGetPrimaryEmailFunc) History() []UserEmailsStoreGetPrima
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreGetPrimaryEmailFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreGetPrimaryEmailFuncCall is an object that describes an
 // invocation of method GetPrimaryEmail on an instance of
 // MockUserEmailsStore.
@@ -78744,6 +91358,18 @@ func (f *UserEmailsStoreGetVerifiedEmailsFunc) History() []UserEmailsStoreGetVer
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreGetVerifiedEmailsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreGetVerifiedEmailsFuncCall is an object that describes an
 // invocation of method GetVerifiedEmails on an instance of
 // MockUserEmailsStore.
@@ -78860,6 +91486,18 @@ func (f *UserEmailsStoreHandleFunc) History() []UserEmailsStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockUserEmailsStore.
 type UserEmailsStoreHandleFuncCall struct {
@@ -78961,6 +91599,18 @@ func (f *UserEmailsStoreHasVerifiedEmailFunc) History() []UserEmailsStoreHasVeri
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreHasVerifiedEmailFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreHasVerifiedEmailFuncCall is an object that describes an
 // invocation of method HasVerifiedEmail on an instance of
 // MockUserEmailsStore.
@@ -79070,6 +91720,18 @@ func (f *UserEmailsStoreListByUserFunc) History() []UserEmailsStoreListByUserFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreListByUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreListByUserFuncCall is an object that describes an
 // invocation of method ListByUser on an instance of MockUserEmailsStore.
 type UserEmailsStoreListByUserFuncCall struct {
@@ -79178,6 +91840,18 @@ func (f *UserEmailsStoreRemoveFunc) History() []UserEmailsStoreRemoveFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreRemoveFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreRemoveFuncCall is an object that describes an invocation
 // of method Remove on an instance of MockUserEmailsStore.
 type UserEmailsStoreRemoveFuncCall struct {
@@ -79288,6 +91962,18 @@ func (f *UserEmailsStoreSetLastVerificationFunc) History() []UserEmailsStoreSetL
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreSetLastVerificationFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreSetLastVerificationFuncCall is an object that describes an
 // invocation of method SetLastVerification on an instance of
 // MockUserEmailsStore.
@@ -79404,6 +92090,18 @@ func (f *UserEmailsStoreSetPrimaryEmailFunc) History() []UserEmailsStoreSetPrima
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreSetPrimaryEmailFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreSetPrimaryEmailFuncCall is an object that describes an
 // invocation of method SetPrimaryEmail on an instance of
 // MockUserEmailsStore.
@@ -79513,6 +92211,18 @@ func (f *UserEmailsStoreSetVerifiedFunc) History() []UserEmailsStoreSetVerifiedF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreSetVerifiedFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreSetVerifiedFuncCall is an object that describes an
 // invocation of method SetVerified on an instance of MockUserEmailsStore.
 type UserEmailsStoreSetVerifiedFuncCall struct {
@@ -79624,6 +92334,18 @@ func (f *UserEmailsStoreTransactFunc) History() []UserEmailsStoreTransactFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreTransactFuncCall is an object that describes an invocation
 // of method Transact on an instance of MockUserEmailsStore.
 type UserEmailsStoreTransactFuncCall struct {
@@ -79729,6 +92451,18 @@ func (f *UserEmailsStoreVerifyFunc) History() []UserEmailsStoreVerifyFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreVerifyFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreVerifyFuncCall is an object that describes an invocation
 // of method Verify on an instance of MockUserEmailsStore.
 type UserEmailsStoreVerifyFuncCall struct {
@@ -79843,6 +92577,18 @@ func (f *UserEmailsStoreWithFunc) History() []UserEmailsStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserEmailsStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserEmailsStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockUserEmailsStore.
 type UserEmailsStoreWithFuncCall struct {
@@ -80123,6 +92869,147 @@ func NewStrictMockUserExternalAccountsStore() *MockUserExternalAccountsStore {
 	}
 }
 
+// NewStrictMockUserExternalAccountsStoreWithT creates a new mock of the
+// UserExternalAccountsStore interface. All methods fail t with their arguments
+// and return zero values for all results, unless overwritten.
+func NewStrictMockUserExternalAccountsStoreWithT(t testing.TB) *MockUserExternalAccountsStore {
+	return &MockUserExternalAccountsStore{
+		CountFunc: &UserExternalAccountsStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExternalAccountsListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteFunc: &UserExternalAccountsStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExternalAccountsDeleteOptions) (r0 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &UserExternalAccountsStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.Done(%v)", v0)
+				return
+			},
+		},
+		ExecResultFunc: &UserExternalAccountsStoreExecResultFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 sql.Result, r1 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.ExecResult(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetFunc: &UserExternalAccountsStoreGetFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *extsvc.Account, r1 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.Get(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &UserExternalAccountsStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.Handle()")
+				return
+			},
+		},
+		InsertFunc: &UserExternalAccountsStoreInsertFunc{
+			defaultHook: func(v0 context.Context, v1 *extsvc.Account) (r0 *extsvc.Account, r1 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.Insert(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListFunc: &UserExternalAccountsStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.ExternalAccountsListOptions) (r0 []*extsvc.Account, r1 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListForUsersFunc: &UserExternalAccountsStoreListForUsersFunc{
+			defaultHook: func(v0 context.Context, v1 []int32) (r0 map[int32][]*extsvc.Account, r1 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.ListForUsers(%v, %v)", v0, v1)
+				return
+			},
+		},
+		QueryRowFunc: &UserExternalAccountsStoreQueryRowFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 *sql.Row) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.QueryRow(%v, %v)", v0, v1)
+				return
+			},
+		},
+		TouchExpiredFunc: &UserExternalAccountsStoreTouchExpiredFunc{
+			defaultHook: func(v0 context.Context, v1 ...int32) (r0 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.TouchExpired(%v, %v)", v0, v1)
+				return
+			},
+		},
+		TouchLastValidFunc: &UserExternalAccountsStoreTouchLastValidFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.TouchLastValid(%v, %v)", v0, v1)
+				return
+			},
+		},
+		TransactFunc: &UserExternalAccountsStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.UserExternalAccountsStore, r1 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.Transact(%v)", v0)
+				return
+			},
+		},
+		UpdateFunc: &UserExternalAccountsStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 *extsvc.Account) (r0 *extsvc.Account, r1 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.Update(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpsertFunc: &UserExternalAccountsStoreUpsertFunc{
+			defaultHook: func(v0 context.Context, v1 *extsvc.Account) (r0 *extsvc.Account, r1 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.Upsert(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpsertSCIMDataFunc: &UserExternalAccountsStoreUpsertSCIMDataFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string, v3 extsvc.AccountData) (r0 error) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.UpsertSCIMData(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		WithFunc: &UserExternalAccountsStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.UserExternalAccountsStore) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.With(%v)", v0)
+				return
+			},
+		},
+		WithEncryptionKeyFunc: &UserExternalAccountsStoreWithEncryptionKeyFunc{
+			defaultHook: func(v0 encryption.Key) (r0 database.UserExternalAccountsStore) {
+				t.Errorf("unexpected call of MockUserExternalAccountsStore.WithEncryptionKey(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockUserExternalAccountsStore instance with PushHook or PushReturn which was
+// not called.
+func (m *MockUserExternalAccountsStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockUserExternalAccountsStore.Count")
+	m.DeleteFunc.assertExpectations(t, "MockUserExternalAccountsStore.Delete")
+	m.DoneFunc.assertExpectations(t, "MockUserExternalAccountsStore.Done")
+	m.ExecResultFunc.assertExpectations(t, "MockUserExternalAccountsStore.ExecResult")
+	m.GetFunc.assertExpectations(t, "MockUserExternalAccountsStore.Get")
+	m.HandleFunc.assertExpectations(t, "MockUserExternalAccountsStore.Handle")
+	m.InsertFunc.assertExpectations(t, "MockUserExternalAccountsStore.Insert")
+	m.ListFunc.assertExpectations(t, "MockUserExternalAccountsStore.List")
+	m.ListForUsersFunc.assertExpectations(t, "MockUserExternalAccountsStore.ListForUsers")
+	m.QueryRowFunc.assertExpectations(t, "MockUserExternalAccountsStore.QueryRow")
+	m.TouchExpiredFunc.assertExpectations(t, "MockUserExternalAccountsStore.TouchExpired")
+	m.TouchLastValidFunc.assertExpectations(t, "MockUserExternalAccountsStore.TouchLastValid")
+	m.TransactFunc.assertExpectations(t, "MockUserExternalAccountsStore.Transact")
+	m.UpdateFunc.assertExpectations(t, "MockUserExternalAccountsStore.Update")
+	m.UpsertFunc.assertExpectations(t, "MockUserExternalAccountsStore.Upsert")
+	m.UpsertSCIMDataFunc.assertExpectations(t, "MockUserExternalAccountsStore.UpsertSCIMData")
+	m.WithFunc.assertExpectations(t, "MockUserExternalAccountsStore.With")
+	m.WithEncryptionKeyFunc.assertExpectations(t, "MockUserExternalAccountsStore.WithEncryptionKey")
+}
+
 // NewMockUserExternalAccountsStoreFrom creates a new mock of the
 // MockUserExternalAccountsStore interface. All methods delegate to the
 // given implementation, unless overwritten.
@@ -80264,6 +93151,18 @@ func (f *UserExternalAccountsStoreCountFunc) History() []UserExternalAccountsSto
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreCountFuncCall is an object that describes an
 // invocation of method Count on an instance of
 // MockUserExternalAccountsStore.
@@ -80375,6 +93274,18 @@ func (f *UserExternalAccountsStoreDeleteFunc) History() []UserExternalAccountsSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreDeleteFuncCall is an object that describes an
 // invocation of method Delete on an instance of
 // MockUserExternalAccountsStore.
@@ -80481,6 +93392,18 @@ func (f *UserExternalAccountsStoreDoneFunc) History() []UserExternalAccountsStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreDoneFuncCall is an object that describes an
 // invocation of method Done on an instance of
 // MockUserExternalAccountsStore.
@@ -80586,6 +93509,18 @@ func (f *UserExternalAccountsStoreExecResultFunc) History() []UserExternalAccoun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreExecResultFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreExecResultFuncCall is an object that describes
 // an invocation of method ExecResult on an instance of
 // MockUserExternalAccountsStore.
@@ -80695,6 +93630,18 @@ func (f *UserExternalAccountsStoreGetFunc) History() []UserExternalAccountsStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreGetFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreGetFuncCall is an object that describes an
 // invocation of method Get on an instance of MockUserExternalAccountsStore.
 type UserExternalAccountsStoreGetFuncCall struct {
@@ -80805,6 +93752,18 @@ func (f *UserExternalAccountsStoreHandleFunc) History() []UserExternalAccountsSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreHandleFuncCall is an object that describes an
 // invocation of method Handle on an instance of
 // MockUserExternalAccountsStore.
@@ -80907,6 +93866,18 @@ func (f *UserExternalAccountsStoreInsertFunc) History() []UserExternalAccountsSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreInsertFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreInsertFuncCall is an object that describes an
 // invocation of method Insert on an instance of
 // MockUserExternalAccountsStore.
@@ -81016,6 +93987,18 @@ func (f *UserExternalAccountsStoreListFunc) History() []UserExternalAccountsStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreListFuncCall is an object that describes an
 // invocation of method List on an instance of
 // MockUserExternalAccountsStore.
@@ -81128,6 +94111,18 @@ func (f *UserExternalAccountsStoreListForUsersFunc) History() []UserExternalAcco
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreListForUsersFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreListForUsersFuncCall is an object that describes
 // an invocation of method ListForUsers on an instance of
 // MockUserExternalAccountsStore.
@@ -81239,6 +94234,18 @@ func (f *UserExternalAccountsStoreQueryRowFunc) History() []UserExternalAccounts
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreQueryRowFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreQueryRowFuncCall is an object that describes an
 // invocation of method QueryRow on an instance of
 // MockUserExternalAccountsStore.
@@ -81348,6 +94355,18 @@ func (f *UserExternalAccountsStoreTouchExpiredFunc) History() []UserExternalAcco
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreTouchExpiredFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreTouchExpiredFuncCall is an object that describes
 // an invocation of method TouchExpired on an instance of
 // MockUserExternalAccountsStore.
@@ -81464,6 +94483,18 @@ func (f *UserExternalAccountsStoreTouchLastValidFunc) History() []UserExternalAc
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreTouchLastValidFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreTouchLastValidFuncCall is an object that
 // describes an invocation of method TouchLastValid on an instance of
 // MockUserExternalAccountsStore.
@@ -81572,6 +94603,18 @@ func (f *UserExternalAccountsStoreTransactFunc) History() []UserExternalAccounts
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreTransactFuncCall is an object that describes an
 // invocation of method Transact on an instance of
 // MockUserExternalAccountsStore.
@@ -81680,6 +94723,18 @@ func (f *UserExternalAccountsStoreUpdateFunc) History() []UserExternalAccountsSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreUpdateFuncCall is an object that describes an
 // invocation of method Update on an instance of
 // MockUserExternalAccountsStore.
@@ -81791,6 +94846,18 @@ func (f *UserExternalAccountsStoreUpsertFunc) History() []UserExternalAccountsSt
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreUpsertFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreUpsertFuncCall is an object that describes an
 // invocation of method Upsert on an instance of
 // MockUserExternalAccountsStore.
@@ -81903,6 +94970,18 @@ func (f *UserExternalAccountsStoreUpsertSCIMDataFunc) History() []UserExternalAc
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreUpsertSCIMDataFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreUpsertSCIMDataFuncCall is an object that
 // describes an invocation of method UpsertSCIMData on an instance of
 // MockUserExternalAccountsStore.
@@ -82015,6 +95094,18 @@ func (f *UserExternalAccountsStoreWithFunc) History() []UserExternalAccountsStor
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreWithFuncCall is an object that describes an
 // invocation of method With on an instance of
 // MockUserExternalAccountsStore.
@@ -82121,6 +95212,18 @@ func (f *UserExternalAccountsStoreWithEncryptionKeyFunc) History() []UserExterna
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserExternalAccountsStoreWithEncryptionKeyFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserExternalAccountsStoreWithEncryptionKeyFuncCall is an object that
 // describes an invocation of method WithEncryptionKey on an instance of
 // MockUserExternalAccountsStore.
@@ -82348,6 +95451,118 @@ func NewStrictMockUserRoleStore() *MockUserRoleStore {
 	}
 }
 
+// NewStrictMockUserRoleStoreWithT creates a new mock of the UserRoleStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockUserRoleStoreWithT(t testing.TB) *MockUserRoleStore {
+	return &MockUserRoleStore{
+		AssignFunc: &UserRoleStoreAssignFunc{
+			defaultHook: func(v0 context.Context, v1 database.AssignUserRoleOpts) (r0 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.Assign(%v, %v)", v0, v1)
+				return
+			},
+		},
+		AssignSystemRoleFunc: &UserRoleStoreAssignSystemRoleFunc{
+			defaultHook: func(v0 context.Context, v1 database.AssignSystemRoleOpts) (r0 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.AssignSystemRole(%v, %v)", v0, v1)
+				return
+			},
+		},
+		BulkAssignRolesToUserFunc: &UserRoleStoreBulkAssignRolesToUserFunc{
+			defaultHook: func(v0 context.Context, v1 database.BulkAssignRolesToUserOpts) (r0 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.BulkAssignRolesToUser(%v, %v)", v0, v1)
+				return
+			},
+		},
+		BulkAssignSystemRolesToUserFunc: &UserRoleStoreBulkAssignSystemRolesToUserFunc{
+			defaultHook: func(v0 context.Context, v1 database.BulkAssignSystemRolesToUserOpts) (r0 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.BulkAssignSystemRolesToUser(%v, %v)", v0, v1)
+				return
+			},
+		},
+		BulkRevokeRolesForUserFunc: &UserRoleStoreBulkRevokeRolesForUserFunc{
+			defaultHook: func(v0 context.Context, v1 database.BulkRevokeRolesForUserOpts) (r0 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.BulkRevokeRolesForUser(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByRoleIDFunc: &UserRoleStoreGetByRoleIDFunc{
+			defaultHook: func(v0 context.Context, v1 database.GetUserRoleOpts) (r0 []*types.UserRole, r1 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.GetByRoleID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByRoleIDAndUserIDFunc: &UserRoleStoreGetByRoleIDAndUserIDFunc{
+			defaultHook: func(v0 context.Context, v1 database.GetUserRoleOpts) (r0 *types.UserRole, r1 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.GetByRoleIDAndUserID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByUserIDFunc: &UserRoleStoreGetByUserIDFunc{
+			defaultHook: func(v0 context.Context, v1 database.GetUserRoleOpts) (r0 []*types.UserRole, r1 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.GetByUserID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &UserRoleStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockUserRoleStore.Handle()")
+				return
+			},
+		},
+		RevokeFunc: &UserRoleStoreRevokeFunc{
+			defaultHook: func(v0 context.Context, v1 database.RevokeUserRoleOpts) (r0 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.Revoke(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RevokeSystemRoleFunc: &UserRoleStoreRevokeSystemRoleFunc{
+			defaultHook: func(v0 context.Context, v1 database.RevokeSystemRoleOpts) (r0 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.RevokeSystemRole(%v, %v)", v0, v1)
+				return
+			},
+		},
+		SetRolesForUserFunc: &UserRoleStoreSetRolesForUserFunc{
+			defaultHook: func(v0 context.Context, v1 database.SetRolesForUserOpts) (r0 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.SetRolesForUser(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &UserRoleStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.UserRoleStore) {
+				t.Errorf("unexpected call of MockUserRoleStore.With(%v)", v0)
+				return
+			},
+		},
+		WithTransactFunc: &UserRoleStoreWithTransactFunc{
+			defaultHook: func(v0 context.Context, v1 func(database.UserRoleStore) error) (r0 error) {
+				t.Errorf("unexpected call of MockUserRoleStore.WithTransact(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockUserRoleStore instance with PushHook or PushReturn which was not called.
+func (m *MockUserRoleStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.AssignFunc.assertExpectations(t, "MockUserRoleStore.Assign")
+	m.AssignSystemRoleFunc.assertExpectations(t, "MockUserRoleStore.AssignSystemRole")
+	m.BulkAssignRolesToUserFunc.assertExpectations(t, "MockUserRoleStore.BulkAssignRolesToUser")
+	m.BulkAssignSystemRolesToUserFunc.assertExpectations(t, "MockUserRoleStore.BulkAssignSystemRolesToUser")
+	m.BulkRevokeRolesForUserFunc.assertExpectations(t, "MockUserRoleStore.BulkRevokeRolesForUser")
+	m.GetByRoleIDFunc.assertExpectations(t, "MockUserRoleStore.GetByRoleID")
+	m.GetByRoleIDAndUserIDFunc.assertExpectations(t, "MockUserRoleStore.GetByRoleIDAndUserID")
+	m.GetByUserIDFunc.assertExpectations(t, "MockUserRoleStore.GetByUserID")
+	m.HandleFunc.assertExpectations(t, "MockUserRoleStore.Handle")
+	m.RevokeFunc.assertExpectations(t, "MockUserRoleStore.Revoke")
+	m.RevokeSystemRoleFunc.assertExpectations(t, "MockUserRoleStore.RevokeSystemRole")
+	m.SetRolesForUserFunc.assertExpectations(t, "MockUserRoleStore.SetRolesForUser")
+	m.WithFunc.assertExpectations(t, "MockUserRoleStore.With")
+	m.WithTransactFunc.assertExpectations(t, "MockUserRoleStore.WithTransact")
+}
+
 // NewMockUserRoleStoreFrom creates a new mock of the MockUserRoleStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -82476,6 +95691,18 @@ func (f *UserRoleStoreAssignFunc) History() []UserRoleStoreAssignFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreAssignFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreAssignFuncCall is an object that describes an invocation of
 // method Assign on an instance of MockUserRoleStore.
 type UserRoleStoreAssignFuncCall struct {
@@ -82582,6 +95809,18 @@ func (f *UserRoleStoreAssignSystemRoleFunc) History() []UserRoleStoreAssignSyste
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreAssignSystemRoleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreAssignSystemRoleFuncCall is an object that describes an
 // invocation of method AssignSystemRole on an instance of
 // MockUserRoleStore.
@@ -82690,6 +95929,18 @@ func (f *UserRoleStoreBulkAssignRolesToUserFunc) History() []UserRoleStoreBulkAs
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreBulkAssignRolesToUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreBulkAssignRolesToUserFuncCall is an object that describes an
 // invocation of method BulkAssignRolesToUser on an instance of
 // MockUserRoleStore.
@@ -82799,6 +96050,18 @@ func (f *UserRoleStoreBulkAssignSystemRolesToUserFunc) History() []UserRoleStore
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreBulkAssignSystemRolesToUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreBulkAssignSystemRolesToUserFuncCall is an object that
 // describes an invocation of method BulkAssignSystemRolesToUser on an
 // instance of MockUserRoleStore.
@@ -82907,6 +96170,18 @@ func (f *UserRoleStoreBulkRevokeRolesForUserFunc) History() []UserRoleStoreBulkR
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreBulkRevokeRolesForUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreBulkRevokeRolesForUserFuncCall is an object that describes
 // an invocation of method BulkRevokeRolesForUser on an instance of
 // MockUserRoleStore.
@@ -83013,6 +96288,18 @@ func (f *UserRoleStoreGetByRoleIDFunc) History() []UserRoleStoreGetByRoleIDFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreGetByRoleIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreGetByRoleIDFuncCall is an object that describes an
 // invocation of method GetByRoleID on an instance of MockUserRoleStore.
 type UserRoleStoreGetByRoleIDFuncCall struct {
@@ -83123,6 +96410,18 @@ func (f *UserRoleStoreGetByRoleIDAndUserIDFunc) History() []UserRoleStoreGetByRo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreGetByRoleIDAndUserIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreGetByRoleIDAndUserIDFuncCall is an object that describes an
 // invocation of method GetByRoleIDAndUserID on an instance of
 // MockUserRoleStore.
@@ -83232,6 +96531,18 @@ func (f *UserRoleStoreGetByUserIDFunc) History() []UserRoleStoreGetByUserIDFuncC
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreGetByUserIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreGetByUserIDFuncCall is an object that describes an
 // invocation of method GetByUserID on an instance of MockUserRoleStore.
 type UserRoleStoreGetByUserIDFuncCall struct {
@@ -83339,6 +96650,18 @@ func (f *UserRoleStoreHandleFunc) History() []UserRoleStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockUserRoleStore.
 type UserRoleStoreHandleFuncCall struct {
@@ -83437,6 +96760,18 @@ func (f *UserRoleStoreRevokeFunc) History() []UserRoleStoreRevokeFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreRevokeFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreRevokeFuncCall is an object that describes an invocation of
 // method Revoke on an instance of MockUserRoleStore.
 type UserRoleStoreRevokeFuncCall struct {
@@ -83543,6 +96878,18 @@ func (f *UserRoleStoreRevokeSystemRoleFunc) History() []UserRoleStoreRevokeSyste
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreRevokeSystemRoleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreRevokeSystemRoleFuncCall is an object that describes an
 // invocation of method RevokeSystemRole on an instance of
 // MockUserRoleStore.
@@ -83650,6 +96997,18 @@ func (f *UserRoleStoreSetRolesForUserFunc) History() []UserRoleStoreSetRolesForU
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreSetRolesForUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreSetRolesForUserFuncCall is an object that describes an
 // invocation of method SetRolesForUser on an instance of MockUserRoleStore.
 type UserRoleStoreSetRolesForUserFuncCall struct {
@@ -83754,6 +97113,18 @@ func (f *UserRoleStoreWithFunc) History() []UserRoleStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockUserRoleStore.
 type UserRoleStoreWithFuncCall struct {
@@ -83856,6 +97227,18 @@ func (f *UserRoleStoreWithTransactFunc) History() []UserRoleStoreWithTransactFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserRoleStoreWithTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserRoleStoreWithTransactFuncCall is an object that describes an
 // invocation of method WithTransact on an instance of MockUserRoleStore.
 type UserRoleStoreWithTransactFuncCall struct {
@@ -84451,6 +97834,314 @@ func NewStrictMockUserStore() *MockUserStore {
 	}
 }
 
+// NewStrictMockUserStoreWithT creates a new mock of the UserStore interface.
+// All methods fail t with their arguments and return zero values for all
+// results, unless overwritten.
+func NewStrictMockUserStoreWithT(t testing.TB) *MockUserStore {
+	return &MockUserStore{
+		ChangeCodyPlanFunc: &UserStoreChangeCodyPlanFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 bool) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.ChangeCodyPlan(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CheckAndDecrementInviteQuotaFunc: &UserStoreCheckAndDecrementInviteQuotaFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.CheckAndDecrementInviteQuota(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountFunc: &UserStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 *database.UsersListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CountForSCIMFunc: &UserStoreCountForSCIMFunc{
+			defaultHook: func(v0 context.Context, v1 *database.UsersListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.CountForSCIM(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &UserStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 database.NewUser) (r0 *types.User, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.Create(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateInTransactionFunc: &UserStoreCreateInTransactionFunc{
+			defaultHook: func(v0 context.Context, v1 database.NewUser, v2 *extsvc.AccountSpec) (r0 *types.User, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.CreateInTransaction(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreatePasswordFunc: &UserStoreCreatePasswordFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.CreatePassword(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		CreateWithExternalAccountFunc: &UserStoreCreateWithExternalAccountFunc{
+			defaultHook: func(v0 context.Context, v1 database.NewUser, v2 *extsvc.Account) (r0 *types.User, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.CreateWithExternalAccount(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		DeleteFunc: &UserStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteListFunc: &UserStoreDeleteListFunc{
+			defaultHook: func(v0 context.Context, v1 []int32) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.DeleteList(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeletePasswordResetCodeFunc: &UserStoreDeletePasswordResetCodeFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.DeletePasswordResetCode(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DoneFunc: &UserStoreDoneFunc{
+			defaultHook: func(v0 error) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.Done(%v)", v0)
+				return
+			},
+		},
+		ExecFunc: &UserStoreExecFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.Exec(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ExecResultFunc: &UserStoreExecResultFunc{
+			defaultHook: func(v0 context.Context, v1 *sqlf.Query) (r0 sql.Result, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.ExecResult(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByCurrentAuthUserFunc: &UserStoreGetByCurrentAuthUserFunc{
+			defaultHook: func(v0 context.Context) (r0 *types.User, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.GetByCurrentAuthUser(%v)", v0)
+				return
+			},
+		},
+		GetByIDFunc: &UserStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *types.User, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByUsernameFunc: &UserStoreGetByUsernameFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *types.User, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.GetByUsername(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByUsernamesFunc: &UserStoreGetByUsernamesFunc{
+			defaultHook: func(v0 context.Context, v1 ...string) (r0 []*types.User, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.GetByUsernames(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByVerifiedEmailFunc: &UserStoreGetByVerifiedEmailFunc{
+			defaultHook: func(v0 context.Context, v1 string) (r0 *types.User, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.GetByVerifiedEmail(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetChatCompletionsQuotaFunc: &UserStoreGetChatCompletionsQuotaFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *int, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.GetChatCompletionsQuota(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetCodeCompletionsQuotaFunc: &UserStoreGetCodeCompletionsQuotaFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *int, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.GetCodeCompletionsQuota(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &UserStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockUserStore.Handle()")
+				return
+			},
+		},
+		HardDeleteFunc: &UserStoreHardDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.HardDelete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HardDeleteListFunc: &UserStoreHardDeleteListFunc{
+			defaultHook: func(v0 context.Context, v1 []int32) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.HardDeleteList(%v, %v)", v0, v1)
+				return
+			},
+		},
+		InvalidateSessionsByIDFunc: &UserStoreInvalidateSessionsByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.InvalidateSessionsByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		InvalidateSessionsByIDsFunc: &UserStoreInvalidateSessionsByIDsFunc{
+			defaultHook: func(v0 context.Context, v1 []int32) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.InvalidateSessionsByIDs(%v, %v)", v0, v1)
+				return
+			},
+		},
+		IsPasswordFunc: &UserStoreIsPasswordFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.IsPassword(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		ListFunc: &UserStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 *database.UsersListOptions) (r0 []*types.User, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		ListByOrgFunc: &UserStoreListByOrgFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 *database.PaginationArgs, v3 *string) (r0 []*types.User, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.ListByOrg(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		ListDatesFunc: &UserStoreListDatesFunc{
+			defaultHook: func(v0 context.Context) (r0 []types.UserDates, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.ListDates(%v)", v0)
+				return
+			},
+		},
+		ListForSCIMFunc: &UserStoreListForSCIMFunc{
+			defaultHook: func(v0 context.Context, v1 *database.UsersListOptions) (r0 []*types.UserForSCIM, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.ListForSCIM(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RandomizePasswordAndClearPasswordResetRateLimitFunc: &UserStoreRandomizePasswordAndClearPasswordResetRateLimitFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.RandomizePasswordAndClearPasswordResetRateLimit(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RecoverUsersListFunc: &UserStoreRecoverUsersListFunc{
+			defaultHook: func(v0 context.Context, v1 []int32) (r0 []int32, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.RecoverUsersList(%v, %v)", v0, v1)
+				return
+			},
+		},
+		RenewPasswordResetCodeFunc: &UserStoreRenewPasswordResetCodeFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 string, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.RenewPasswordResetCode(%v, %v)", v0, v1)
+				return
+			},
+		},
+		SetChatCompletionsQuotaFunc: &UserStoreSetChatCompletionsQuotaFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 *int) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.SetChatCompletionsQuota(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetCodeCompletionsQuotaFunc: &UserStoreSetCodeCompletionsQuotaFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 *int) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.SetCodeCompletionsQuota(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetIsSiteAdminFunc: &UserStoreSetIsSiteAdminFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 bool) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.SetIsSiteAdmin(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		SetPasswordFunc: &UserStoreSetPasswordFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string, v3 string) (r0 bool, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.SetPassword(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		TransactFunc: &UserStoreTransactFunc{
+			defaultHook: func(v0 context.Context) (r0 database.UserStore, r1 error) {
+				t.Errorf("unexpected call of MockUserStore.Transact(%v)", v0)
+				return
+			},
+		},
+		UpdateFunc: &UserStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 database.UserUpdate) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.Update(%v, %v, %v)", v0, v1, v2)
+				return
+			},
+		},
+		UpdatePasswordFunc: &UserStoreUpdatePasswordFunc{
+			defaultHook: func(v0 context.Context, v1 int32, v2 string, v3 string) (r0 error) {
+				t.Errorf("unexpected call of MockUserStore.UpdatePassword(%v, %v, %v, %v)", v0, v1, v2, v3)
+				return
+			},
+		},
+		WithFunc: &UserStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.UserStore) {
+				t.Errorf("unexpected call of MockUserStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockUserStore instance with PushHook or PushReturn which was not called.
+func (m *MockUserStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.ChangeCodyPlanFunc.assertExpectations(t, "MockUserStore.ChangeCodyPlan")
+	m.CheckAndDecrementInviteQuotaFunc.assertExpectations(t, "MockUserStore.CheckAndDecrementInviteQuota")
+	m.CountFunc.assertExpectations(t, "MockUserStore.Count")
+	m.CountForSCIMFunc.assertExpectations(t, "MockUserStore.CountForSCIM")
+	m.CreateFunc.assertExpectations(t, "MockUserStore.Create")
+	m.CreateInTransactionFunc.assertExpectations(t, "MockUserStore.CreateInTransaction")
+	m.CreatePasswordFunc.assertExpectations(t, "MockUserStore.CreatePassword")
+	m.CreateWithExternalAccountFunc.assertExpectations(t, "MockUserStore.CreateWithExternalAccount")
+	m.DeleteFunc.assertExpectations(t, "MockUserStore.Delete")
+	m.DeleteListFunc.assertExpectations(t, "MockUserStore.DeleteList")
+	m.DeletePasswordResetCodeFunc.assertExpectations(t, "MockUserStore.DeletePasswordResetCode")
+	m.DoneFunc.assertExpectations(t, "MockUserStore.Done")
+	m.ExecFunc.assertExpectations(t, "MockUserStore.Exec")
+	m.ExecResultFunc.assertExpectations(t, "MockUserStore.ExecResult")
+	m.GetByCurrentAuthUserFunc.assertExpectations(t, "MockUserStore.GetByCurrentAuthUser")
+	m.GetByIDFunc.assertExpectations(t, "MockUserStore.GetByID")
+	m.GetByUsernameFunc.assertExpectations(t, "MockUserStore.GetByUsername")
+	m.GetByUsernamesFunc.assertExpectations(t, "MockUserStore.GetByUsernames")
+	m.GetByVerifiedEmailFunc.assertExpectations(t, "MockUserStore.GetByVerifiedEmail")
+	m.GetChatCompletionsQuotaFunc.assertExpectations(t, "MockUserStore.GetChatCompletionsQuota")
+	m.GetCodeCompletionsQuotaFunc.assertExpectations(t, "MockUserStore.GetCodeCompletionsQuota")
+	m.HandleFunc.assertExpectations(t, "MockUserStore.Handle")
+	m.HardDeleteFunc.assertExpectations(t, "MockUserStore.HardDelete")
+	m.HardDeleteListFunc.assertExpectations(t, "MockUserStore.HardDeleteList")
+	m.InvalidateSessionsByIDFunc.assertExpectations(t, "MockUserStore.InvalidateSessionsByID")
+	m.InvalidateSessionsByIDsFunc.assertExpectations(t, "MockUserStore.InvalidateSessionsByIDs")
+	m.IsPasswordFunc.assertExpectations(t, "MockUserStore.IsPassword")
+	m.ListFunc.assertExpectations(t, "MockUserStore.List")
+	m.ListByOrgFunc.assertExpectations(t, "MockUserStore.ListByOrg")
+	m.ListDatesFunc.assertExpectations(t, "MockUserStore.ListDates")
+	m.ListForSCIMFunc.assertExpectations(t, "MockUserStore.ListForSCIM")
+	m.RandomizePasswordAndClearPasswordResetRateLimitFunc.assertExpectations(t, "MockUserStore.RandomizePasswordAndClearPasswordResetRateLimit")
+	m.RecoverUsersListFunc.assertExpectations(t, "MockUserStore.RecoverUsersList")
+	m.RenewPasswordResetCodeFunc.assertExpectations(t, "MockUserStore.RenewPasswordResetCode")
+	m.SetChatCompletionsQuotaFunc.assertExpectations(t, "MockUserStore.SetChatCompletionsQuota")
+	m.SetCodeCompletionsQuotaFunc.assertExpectations(t, "MockUserStore.SetCodeCompletionsQuota")
+	m.SetIsSiteAdminFunc.assertExpectations(t, "MockUserStore.SetIsSiteAdmin")
+	m.SetPasswordFunc.assertExpectations(t, "MockUserStore.SetPassword")
+	m.TransactFunc.assertExpectations(t, "MockUserStore.Transact")
+	m.UpdateFunc.assertExpectations(t, "MockUserStore.Update")
+	m.UpdatePasswordFunc.assertExpectations(t, "MockUserStore.UpdatePassword")
+	m.WithFunc.assertExpectations(t, "MockUserStore.With")
+}
+
 // NewMockUserStoreFrom creates a new mock of the MockUserStore interface.
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockUserStoreFrom(i database.UserStore) *MockUserStore {
@@ -84663,6 +98354,18 @@ func (f *UserStoreChangeCodyPlanFunc) History() []UserStoreChangeCodyPlanFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreChangeCodyPlanFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreChangeCodyPlanFuncCall is an object that describes an invocation
 // of method ChangeCodyPlan on an instance of MockUserStore.
 type UserStoreChangeCodyPlanFuncCall struct {
@@ -84774,6 +98477,18 @@ func (f *UserStoreCheckAndDecrementInviteQuotaFunc) History() []UserStoreCheckAn
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreCheckAndDecrementInviteQuotaFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreCheckAndDecrementInviteQuotaFuncCall is an object that describes
 // an invocation of method CheckAndDecrementInviteQuota on an instance of
 // MockUserStore.
@@ -84882,6 +98597,18 @@ func (f *UserStoreCountFunc) History() []UserStoreCountFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreCountFuncCall is an object that describes an invocation of
 // method Count on an instance of MockUserStore.
 type UserStoreCountFuncCall struct {
@@ -84990,6 +98717,18 @@ func (f *UserStoreCountForSCIMFunc) History() []UserStoreCountForSCIMFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreCountForSCIMFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreCountForSCIMFuncCall is an object that describes an invocation
 // of method CountForSCIM on an instance of MockUserStore.
 type UserStoreCountForSCIMFuncCall struct {
@@ -85097,6 +98836,18 @@ func (f *UserStoreCreateFunc) History() []UserStoreCreateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreCreateFuncCall is an object that describes an invocation of
 // method Create on an instance of MockUserStore.
 type UserStoreCreateFuncCall struct {
@@ -85206,6 +98957,18 @@ func (f *UserStoreCreateInTransactionFunc) History() []UserStoreCreateInTransact
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreCreateInTransactionFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreCreateInTransactionFuncCall is an object that describes an
 // invocation of method CreateInTransaction on an instance of MockUserStore.
 type UserStoreCreateInTransactionFuncCall struct {
@@ -85317,6 +99080,18 @@ func (f *UserStoreCreatePasswordFunc) History() []UserStoreCreatePasswordFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreCreatePasswordFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreCreatePasswordFuncCall is an object that describes an invocation
 // of method CreatePassword on an instance of MockUserStore.
 type UserStoreCreatePasswordFuncCall struct {
@@ -85427,6 +99202,18 @@ func (f *UserStoreCreateWithExternalAccountFunc) History() []UserStoreCreateWith
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreCreateWithExternalAccountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreCreateWithExternalAccountFuncCall is an object that describes an
 // invocation of method CreateWithExternalAccount on an instance of
 // MockUserStore.
@@ -85538,6 +99325,18 @@ func (f *UserStoreDeleteFunc) History() []UserStoreDeleteFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreDeleteFuncCall is an object that describes an invocation of
 // method Delete on an instance of MockUserStore.
 type UserStoreDeleteFuncCall struct {
@@ -85642,6 +99441,18 @@ func (f *UserStoreDeleteListFunc) History() []UserStoreDeleteListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreDeleteListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreDeleteListFuncCall is an object that describes an invocation of
 // method DeleteList on an instance of MockUserStore.
 type UserStoreDeleteListFuncCall struct {
@@ -85749,6 +99560,18 @@ func (f *UserStoreDeletePasswordResetCodeFunc) History() []UserStoreDeletePasswo
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreDeletePasswordResetCodeFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreDeletePasswordResetCodeFuncCall is an object that describes an
 // invocation of method DeletePasswordResetCode on an instance of
 // MockUserStore.
@@ -85854,6 +99677,18 @@ func (f *UserStoreDoneFunc) History() []UserStoreDoneFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreDoneFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreDoneFuncCall is an object that describes an invocation of method
 // Done on an instance of MockUserStore.
 type UserStoreDoneFuncCall struct {
@@ -85955,6 +99790,18 @@ func (f *UserStoreExecFunc) History() []UserStoreExecFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreExecFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreExecFuncCall is an object that describes an invocation of method
 // Exec on an instance of MockUserStore.
 type UserStoreExecFuncCall struct {
@@ -86059,6 +99906,18 @@ func (f *UserStoreExecResultFunc) History() []UserStoreExecResultFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreExecResultFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreExecResultFuncCall is an object that describes an invocation of
 // method ExecResult on an instance of MockUserStore.
 type UserStoreExecResultFuncCall struct {
@@ -86168,6 +100027,18 @@ func (f *UserStoreGetByCurrentAuthUserFunc) History() []UserStoreGetByCurrentAut
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreGetByCurrentAuthUserFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreGetByCurrentAuthUserFuncCall is an object that describes an
 // invocation of method GetByCurrentAuthUser on an instance of
 // MockUserStore.
@@ -86273,6 +100144,18 @@ func (f *UserStoreGetByIDFunc) History() []UserStoreGetByIDFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreGetByIDFuncCall is an object that describes an invocation of
 // method GetByID on an instance of MockUserStore.
 type UserStoreGetByIDFuncCall struct {
@@ -86381,6 +100264,18 @@ func (f *UserStoreGetByUsernameFunc) History() []UserStoreGetByUsernameFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreGetByUsernameFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreGetByUsernameFuncCall is an object that describes an invocation
 // of method GetByUsername on an instance of MockUserStore.
 type UserStoreGetByUsernameFuncCall struct {
@@ -86489,6 +100384,18 @@ func (f *UserStoreGetByUsernamesFunc) History() []UserStoreGetByUsernamesFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreGetByUsernamesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreGetByUsernamesFuncCall is an object that describes an invocation
 // of method GetByUsernames on an instance of MockUserStore.
 type UserStoreGetByUsernamesFuncCall struct {
@@ -86605,6 +100512,18 @@ func (f *UserStoreGetByVerifiedEmailFunc) History() []UserStoreGetByVerifiedEmai
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreGetByVerifiedEmailFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreGetByVerifiedEmailFuncCall is an object that describes an
 // invocation of method GetByVerifiedEmail on an instance of MockUserStore.
 type UserStoreGetByVerifiedEmailFuncCall struct {
@@ -86715,6 +100634,18 @@ func (f *UserStoreGetChatCompletionsQuotaFunc) History() []UserStoreGetChatCompl
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreGetChatCompletionsQuotaFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreGetChatCompletionsQuotaFuncCall is an object that describes an
 // invocation of method GetChatCompletionsQuota on an instance of
 // MockUserStore.
@@ -86826,6 +100757,18 @@ func (f *UserStoreGetCodeCompletionsQuotaFunc) History() []UserStoreGetCodeCompl
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreGetCodeCompletionsQuotaFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreGetCodeCompletionsQuotaFuncCall is an object that describes an
 // invocation of method GetCodeCompletionsQuota on an instance of
 // MockUserStore.
@@ -86934,6 +100877,18 @@ func (f *UserStoreHandleFunc) History() []UserStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockUserStore.
 type UserStoreHandleFuncCall struct {
@@ -87032,6 +100987,18 @@ func (f *UserStoreHardDeleteFunc) History() []UserStoreHardDeleteFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreHardDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreHardDeleteFuncCall is an object that describes an invocation of
 // method HardDelete on an instance of MockUserStore.
 type UserStoreHardDeleteFuncCall struct {
@@ -87137,6 +101104,18 @@ func (f *UserStoreHardDeleteListFunc) History() []UserStoreHardDeleteListFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreHardDeleteListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreHardDeleteListFuncCall is an object that describes an invocation
 // of method HardDeleteList on an instance of MockUserStore.
 type UserStoreHardDeleteListFuncCall struct {
@@ -87244,6 +101223,18 @@ func (f *UserStoreInvalidateSessionsByIDFunc) History() []UserStoreInvalidateSes
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreInvalidateSessionsByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreInvalidateSessionsByIDFuncCall is an object that describes an
 // invocation of method InvalidateSessionsByID on an instance of
 // MockUserStore.
@@ -87352,6 +101343,18 @@ func (f *UserStoreInvalidateSessionsByIDsFunc) History() []UserStoreInvalidateSe
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreInvalidateSessionsByIDsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreInvalidateSessionsByIDsFuncCall is an object that describes an
 // invocation of method InvalidateSessionsByIDs on an instance of
 // MockUserStore.
@@ -87457,6 +101460,18 @@ func (f *UserStoreIsPasswordFunc) History() []UserStoreIsPasswordFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreIsPasswordFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreIsPasswordFuncCall is an object that describes an invocation of
 // method IsPassword on an instance of MockUserStore.
 type UserStoreIsPasswordFuncCall struct {
@@ -87567,6 +101582,18 @@ func (f *UserStoreListFunc) History() []UserStoreListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreListFuncCall is an object that describes an invocation of method
 // List on an instance of MockUserStore.
 type UserStoreListFuncCall struct {
@@ -87674,6 +101701,18 @@ func (f *UserStoreListByOrgFunc) History() []UserStoreListByOrgFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreListByOrgFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreListByOrgFuncCall is an object that describes an invocation of
 // method ListByOrg on an instance of MockUserStore.
 type UserStoreListByOrgFuncCall struct {
@@ -87787,6 +101826,18 @@ func (f *UserStoreListDatesFunc) History() []UserStoreListDatesFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreListDatesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreListDatesFuncCall is an object that describes an invocation of
 // method ListDates on an instance of MockUserStore.
 type UserStoreListDatesFuncCall struct {
@@ -87892,6 +101943,18 @@ func (f *UserStoreListForSCIMFunc) History() []UserStoreListForSCIMFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreListForSCIMFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreListForSCIMFuncCall is an object that describes an invocation of
 // method ListForSCIM on an instance of MockUserStore.
 type UserStoreListForSCIMFuncCall struct {
@@ -88004,6 +102067,18 @@ func (f *UserStoreRandomizePasswordAndClearPasswordResetRateLimitFunc) History()
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreRandomizePasswordAndClearPasswordResetRateLimitFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreRandomizePasswordAndClearPasswordResetRateLimitFuncCall is an
 // object that describes an invocation of method
 // RandomizePasswordAndClearPasswordResetRateLimit on an instance of
@@ -88111,6 +102186,18 @@ func (f *UserStoreRecoverUsersListFunc) History() []UserStoreRecoverUsersListFun
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreRecoverUsersListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreRecoverUsersListFuncCall is an object that describes an
 // invocation of method RecoverUsersList on an instance of MockUserStore.
 type UserStoreRecoverUsersListFuncCall struct {
@@ -88221,6 +102308,18 @@ func (f *UserStoreRenewPasswordResetCodeFunc) History() []UserStoreRenewPassword
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreRenewPasswordResetCodeFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreRenewPasswordResetCodeFuncCall is an object that describes an
 // invocation of method RenewPasswordResetCode on an instance of
 // MockUserStore.
@@ -88332,6 +102431,18 @@ func (f *UserStoreSetChatCompletionsQuotaFunc) History() []UserStoreSetChatCompl
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreSetChatCompletionsQuotaFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreSetChatCompletionsQuotaFuncCall is an object that describes an
 // invocation of method SetChatCompletionsQuota on an instance of
 // MockUserStore.
@@ -88443,6 +102554,18 @@ func (f *UserStoreSetCodeCompletionsQuotaFunc) History() []UserStoreSetCodeCompl
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreSetCodeCompletionsQuotaFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreSetCodeCompletionsQuotaFuncCall is an object that describes an
 // invocation of method SetCodeCompletionsQuota on an instance of
 // MockUserStore.
@@ -88552,6 +102675,18 @@ func (f *UserStoreSetIsSiteAdminFunc) History() []UserStoreSetIsSiteAdminFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreSetIsSiteAdminFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreSetIsSiteAdminFuncCall is an object that describes an invocation
 // of method SetIsSiteAdmin on an instance of MockUserStore.
 type UserStoreSetIsSiteAdminFuncCall struct {
@@ -88660,6 +102795,18 @@ func (f *UserStoreSetPasswordFunc) History() []UserStoreSetPasswordFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreSetPasswordFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreSetPasswordFuncCall is an object that describes an invocation of
 // method SetPassword on an instance of MockUserStore.
 type UserStoreSetPasswordFuncCall struct {
@@ -88773,6 +102920,18 @@ func (f *UserStoreTransactFunc) History() []UserStoreTransactFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreTransactFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreTransactFuncCall is an object that describes an invocation of
 // method Transact on an instance of MockUserStore.
 type UserStoreTransactFuncCall struct {
@@ -88877,6 +103036,18 @@ func (f *UserStoreUpdateFunc) History() []UserStoreUpdateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreUpdateFuncCall is an object that describes an invocation of
 // method Update on an instance of MockUserStore.
 type UserStoreUpdateFuncCall struct {
@@ -88985,6 +103156,18 @@ func (f *UserStoreUpdatePasswordFunc) History() []UserStoreUpdatePasswordFuncCal
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreUpdatePasswordFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreUpdatePasswordFuncCall is an object that describes an invocation
 // of method UpdatePassword on an instance of MockUserStore.
 type UserStoreUpdatePasswordFuncCall struct {
@@ -89095,6 +103278,18 @@ func (f *UserStoreWithFunc) History() []UserStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *UserStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // UserStoreWithFuncCall is an object that describes an invocation of method
 // With on an instance of MockUserStore.
 type UserStoreWithFuncCall struct {
@@ -89218,6 +103413,63 @@ func NewStrictMockWebhookLogStore() *MockWebhookLogStore {
 	}
 }
 
+// NewStrictMockWebhookLogStoreWithT creates a new mock of the WebhookLogStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockWebhookLogStoreWithT(t testing.TB) *MockWebhookLogStore {
+	return &MockWebhookLogStore{
+		CountFunc: &WebhookLogStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.WebhookLogListOpts) (r0 int64, r1 error) {
+				t.Errorf("unexpected call of MockWebhookLogStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &WebhookLogStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.WebhookLog) (r0 error) {
+				t.Errorf("unexpected call of MockWebhookLogStore.Create(%v, %v)", v0, v1)
+				return
+			},
+		},
+		DeleteStaleFunc: &WebhookLogStoreDeleteStaleFunc{
+			defaultHook: func(v0 context.Context, v1 time.Duration) (r0 error) {
+				t.Errorf("unexpected call of MockWebhookLogStore.DeleteStale(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &WebhookLogStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int64) (r0 *types.WebhookLog, r1 error) {
+				t.Errorf("unexpected call of MockWebhookLogStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &WebhookLogStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockWebhookLogStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &WebhookLogStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.WebhookLogListOpts) (r0 []*types.WebhookLog, r1 int64, r2 error) {
+				t.Errorf("unexpected call of MockWebhookLogStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockWebhookLogStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockWebhookLogStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockWebhookLogStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockWebhookLogStore.Create")
+	m.DeleteStaleFunc.assertExpectations(t, "MockWebhookLogStore.DeleteStale")
+	m.GetByIDFunc.assertExpectations(t, "MockWebhookLogStore.GetByID")
+	m.HandleFunc.assertExpectations(t, "MockWebhookLogStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockWebhookLogStore.List")
+}
+
 // NewMockWebhookLogStoreFrom creates a new mock of the MockWebhookLogStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -89323,6 +103575,18 @@ func (f *WebhookLogStoreCountFunc) History() []WebhookLogStoreCountFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookLogStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookLogStoreCountFuncCall is an object that describes an invocation of
 // method Count on an instance of MockWebhookLogStore.
 type WebhookLogStoreCountFuncCall struct {
@@ -89431,6 +103695,18 @@ func (f *WebhookLogStoreCreateFunc) History() []WebhookLogStoreCreateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookLogStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookLogStoreCreateFuncCall is an object that describes an invocation
 // of method Create on an instance of MockWebhookLogStore.
 type WebhookLogStoreCreateFuncCall struct {
@@ -89536,6 +103812,18 @@ func (f *WebhookLogStoreDeleteStaleFunc) History() []WebhookLogStoreDeleteStaleF
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookLogStoreDeleteStaleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookLogStoreDeleteStaleFuncCall is an object that describes an
 // invocation of method DeleteStale on an instance of MockWebhookLogStore.
 type WebhookLogStoreDeleteStaleFuncCall struct {
@@ -89641,6 +103929,18 @@ func (f *WebhookLogStoreGetByIDFunc) History() []WebhookLogStoreGetByIDFuncCall
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookLogStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookLogStoreGetByIDFuncCall is an object that describes an invocation
 // of method GetByID on an instance of MockWebhookLogStore.
 type WebhookLogStoreGetByIDFuncCall struct {
@@ -89749,6 +104049,18 @@ func (f *WebhookLogStoreHandleFunc) History() []WebhookLogStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookLogStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookLogStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockWebhookLogStore.
 type WebhookLogStoreHandleFuncCall struct {
@@ -89848,6 +104160,18 @@ func (f *WebhookLogStoreListFunc) History() []WebhookLogStoreListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookLogStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookLogStoreListFuncCall is an object that describes an invocation of
 // method List on an instance of MockWebhookLogStore.
 type WebhookLogStoreListFuncCall struct {
@@ -90004,6 +104328,76 @@ func NewStrictMockWebhookStore() *MockWebhookStore {
 	}
 }
 
+// NewStrictMockWebhookStoreWithT creates a new mock of the WebhookStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockWebhookStoreWithT(t testing.TB) *MockWebhookStore {
+	return &MockWebhookStore{
+		CountFunc: &WebhookStoreCountFunc{
+			defaultHook: func(v0 context.Context, v1 database.WebhookListOptions) (r0 int, r1 error) {
+				t.Errorf("unexpected call of MockWebhookStore.Count(%v, %v)", v0, v1)
+				return
+			},
+		},
+		CreateFunc: &WebhookStoreCreateFunc{
+			defaultHook: func(v0 context.Context, v1 string, v2 string, v3 string, v4 int32, v5 *encryption.Encryptable) (r0 *types.Webhook, r1 error) {
+				t.Errorf("unexpected call of MockWebhookStore.Create(%v, %v, %v, %v, %v, %v)", v0, v1, v2, v3, v4, v5)
+				return
+			},
+		},
+		DeleteFunc: &WebhookStoreDeleteFunc{
+			defaultHook: func(v0 context.Context, v1 database.DeleteWebhookOpts) (r0 error) {
+				t.Errorf("unexpected call of MockWebhookStore.Delete(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByIDFunc: &WebhookStoreGetByIDFunc{
+			defaultHook: func(v0 context.Context, v1 int32) (r0 *types.Webhook, r1 error) {
+				t.Errorf("unexpected call of MockWebhookStore.GetByID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		GetByUUIDFunc: &WebhookStoreGetByUUIDFunc{
+			defaultHook: func(v0 context.Context, v1 uuid.UUID) (r0 *types.Webhook, r1 error) {
+				t.Errorf("unexpected call of MockWebhookStore.GetByUUID(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &WebhookStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockWebhookStore.Handle()")
+				return
+			},
+		},
+		ListFunc: &WebhookStoreListFunc{
+			defaultHook: func(v0 context.Context, v1 database.WebhookListOptions) (r0 []*types.Webhook, r1 error) {
+				t.Errorf("unexpected call of MockWebhookStore.List(%v, %v)", v0, v1)
+				return
+			},
+		},
+		UpdateFunc: &WebhookStoreUpdateFunc{
+			defaultHook: func(v0 context.Context, v1 *types.Webhook) (r0 *types.Webhook, r1 error) {
+				t.Errorf("unexpected call of MockWebhookStore.Update(%v, %v)", v0, v1)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockWebhookStore instance with PushHook or PushReturn which was not called.
+func (m *MockWebhookStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.CountFunc.assertExpectations(t, "MockWebhookStore.Count")
+	m.CreateFunc.assertExpectations(t, "MockWebhookStore.Create")
+	m.DeleteFunc.assertExpectations(t, "MockWebhookStore.Delete")
+	m.GetByIDFunc.assertExpectations(t, "MockWebhookStore.GetByID")
+	m.GetByUUIDFunc.assertExpectations(t, "MockWebhookStore.GetByUUID")
+	m.HandleFunc.assertExpectations(t, "MockWebhookStore.Handle")
+	m.ListFunc.assertExpectations(t, "MockWebhookStore.List")
+	m.UpdateFunc.assertExpectations(t, "MockWebhookStore.Update")
+}
+
 // NewMockWebhookStoreFrom creates a new mock of the MockWebhookStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -90114,6 +104508,18 @@ func (f *WebhookStoreCountFunc) History() []WebhookStoreCountFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookStoreCountFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookStoreCountFuncCall is an object that describes an invocation of
 // method Count on an instance of MockWebhookStore.
 type WebhookStoreCountFuncCall struct {
@@ -90221,6 +104627,18 @@ func (f *WebhookStoreCreateFunc) History() []WebhookStoreCreateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookStoreCreateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookStoreCreateFuncCall is an object that describes an invocation of
 // method Create on an instance of MockWebhookStore.
 type WebhookStoreCreateFuncCall struct {
@@ -90340,6 +104758,18 @@ func (f *WebhookStoreDeleteFunc) History() []WebhookStoreDeleteFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookStoreDeleteFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookStoreDeleteFuncCall is an object that describes an invocation of
 // method Delete on an instance of MockWebhookStore.
 type WebhookStoreDeleteFuncCall struct {
@@ -90445,6 +104875,18 @@ func (f *WebhookStoreGetByIDFunc) History() []WebhookStoreGetByIDFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookStoreGetByIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookStoreGetByIDFuncCall is an object that describes an invocation of
 // method GetByID on an instance of MockWebhookStore.
 type WebhookStoreGetByIDFuncCall struct {
@@ -90553,6 +104995,18 @@ func (f *WebhookStoreGetByUUIDFunc) History() []WebhookStoreGetByUUIDFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookStoreGetByUUIDFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookStoreGetByUUIDFuncCall is an object that describes an invocation
 // of method GetByUUID on an instance of MockWebhookStore.
 type WebhookStoreGetByUUIDFuncCall struct {
@@ -90660,6 +105114,18 @@ func (f *WebhookStoreHandleFunc) History() []WebhookStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookStoreHandleFuncCall is an object that describes an invocation of
 // method Handle on an instance of MockWebhookStore.
 type WebhookStoreHandleFuncCall struct {
@@ -90758,6 +105224,18 @@ func (f *WebhookStoreListFunc) History() []WebhookStoreListFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookStoreListFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookStoreListFuncCall is an object that describes an invocation of
 // method List on an instance of MockWebhookStore.
 type WebhookStoreListFuncCall struct {
@@ -90865,6 +105343,18 @@ func (f *WebhookStoreUpdateFunc) History() []WebhookStoreUpdateFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *WebhookStoreUpdateFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // WebhookStoreUpdateFuncCall is an object that describes an invocation of
 // method Update on an instance of MockWebhookStore.
 type WebhookStoreUpdateFuncCall struct {
@@ -90981,6 +105471,56 @@ func NewStrictMockZoektReposStore() *MockZoektReposStore {
 	}
 }
 
+// NewStrictMockZoektReposStoreWithT creates a new mock of the ZoektReposStore
+// interface. All methods fail t with their arguments and return zero values
+// for all results, unless overwritten.
+func NewStrictMockZoektReposStoreWithT(t testing.TB) *MockZoektReposStore {
+	return &MockZoektReposStore{
+		GetStatisticsFunc: &ZoektReposStoreGetStatisticsFunc{
+			defaultHook: func(v0 context.Context) (r0 database.ZoektRepoStatistics, r1 error) {
+				t.Errorf("unexpected call of MockZoektReposStore.GetStatistics(%v)", v0)
+				return
+			},
+		},
+		GetZoektRepoFunc: &ZoektReposStoreGetZoektRepoFunc{
+			defaultHook: func(v0 context.Context, v1 api.RepoID) (r0 *database.ZoektRepo, r1 error) {
+				t.Errorf("unexpected call of MockZoektReposStore.GetZoektRepo(%v, %v)", v0, v1)
+				return
+			},
+		},
+		HandleFunc: &ZoektReposStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				t.Errorf("unexpected call of MockZoektReposStore.Handle()")
+				return
+			},
+		},
+		UpdateIndexStatusesFunc: &ZoektReposStoreUpdateIndexStatusesFunc{
+			defaultHook: func(v0 context.Context, v1 zoekt.ReposMap) (r0 error) {
+				t.Errorf("unexpected call of MockZoektReposStore.UpdateIndexStatuses(%v, %v)", v0, v1)
+				return
+			},
+		},
+		WithFunc: &ZoektReposStoreWithFunc{
+			defaultHook: func(v0 basestore.ShareableStore) (r0 database.ZoektReposStore) {
+				t.Errorf("unexpected call of MockZoektReposStore.With(%v)", v0)
+				return
+			},
+		},
+	}
+}
+
+// AssertExpectations fails t for every hook pushed to the methods of the
+// MockZoektReposStore instance with PushHook or PushReturn which was not
+// called.
+func (m *MockZoektReposStore) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.GetStatisticsFunc.assertExpectations(t, "MockZoektReposStore.GetStatistics")
+	m.GetZoektRepoFunc.assertExpectations(t, "MockZoektReposStore.GetZoektRepo")
+	m.HandleFunc.assertExpectations(t, "MockZoektReposStore.Handle")
+	m.UpdateIndexStatusesFunc.assertExpectations(t, "MockZoektReposStore.UpdateIndexStatuses")
+	m.WithFunc.assertExpectations(t, "MockZoektReposStore.With")
+}
+
 // NewMockZoektReposStoreFrom creates a new mock of the MockZoektReposStore
 // interface. All methods delegate to the given implementation, unless
 // overwritten.
@@ -91084,6 +105624,18 @@ func (f *ZoektReposStoreGetStatisticsFunc) History() []ZoektReposStoreGetStatist
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ZoektReposStoreGetStatisticsFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ZoektReposStoreGetStatisticsFuncCall is an object that describes an
 // invocation of method GetStatistics on an instance of MockZoektReposStore.
 type ZoektReposStoreGetStatisticsFuncCall struct {
@@ -91190,6 +105742,18 @@ func (f *ZoektReposStoreGetZoektRepoFunc) History() []ZoektReposStoreGetZoektRep
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ZoektReposStoreGetZoektRepoFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ZoektReposStoreGetZoektRepoFuncCall is an object that describes an
 // invocation of method GetZoektRepo on an instance of MockZoektReposStore.
 type ZoektReposStoreGetZoektRepoFuncCall struct {
@@ -91298,6 +105862,18 @@ func (f *ZoektReposStoreHandleFunc) History() []ZoektReposStoreHandleFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ZoektReposStoreHandleFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ZoektReposStoreHandleFuncCall is an object that describes an invocation
 // of method Handle on an instance of MockZoektReposStore.
 type ZoektReposStoreHandleFuncCall struct {
@@ -91399,6 +105975,18 @@ func (f *ZoektReposStoreUpdateIndexStatusesFunc) History() []ZoektReposStoreUpda
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ZoektReposStoreUpdateIndexStatusesFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ZoektReposStoreUpdateIndexStatusesFuncCall is an object that describes an
 // invocation of method UpdateIndexStatuses on an instance of
 // MockZoektReposStore.
@@ -91505,6 +106093,18 @@ func (f *ZoektReposStoreWithFunc) History() []ZoektReposStoreWithFuncCall {
 	return history
 }
 
+// assertExpectations fails t if hooks pushed with PushHook or PushReturn
+// weren't called.
+func (f *ZoektReposStoreWithFunc) assertExpectations(t testing.TB, method string) {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) > 0 {
+		t.Errorf("%d hooks of %s were not called", len(f.hooks), method)
+	}
+}
+
 // ZoektReposStoreWithFuncCall is an object that describes an invocation of
 // method With on an instance of MockZoektReposStore.
 type ZoektReposStoreWithFuncCall struct {
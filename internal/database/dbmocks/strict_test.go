@@ -0,0 +1,89 @@
+package dbmocks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestNewStrictMockWithT(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unstubbed calls fail the test", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		db := NewStrictMockDBWithT(rt)
+		users := NewStrictMockUserStoreWithT(rt)
+
+		user, err := users.GetByID(ctx, 42)
+		require.Nil(t, user)
+		require.NoError(t, err)
+		require.Nil(t, db.Users())
+
+		require.Equal(t, []string{
+			"unexpected call of MockUserStore.GetByID(context.Background, 42)",
+			"unexpected call of MockDB.Users()",
+		}, rt.errors)
+	})
+
+	t.Run("stubbed calls pass", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		users := NewStrictMockUserStoreWithT(rt)
+		users.GetByIDFunc.SetDefaultReturn(&types.User{ID: 42}, nil)
+
+		user, err := users.GetByID(ctx, 42)
+		require.NoError(t, err)
+		require.Equal(t, int32(42), user.ID)
+		require.Empty(t, rt.errors)
+	})
+}
+
+func TestAssertExpectations(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("satisfied", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		users := NewStrictMockUserStoreWithT(rt)
+		// Default hooks are stubs, which needn't be called.
+		users.GetByIDFunc.SetDefaultReturn(&types.User{ID: 1}, nil)
+		users.GetByUsernameFunc.PushReturn(&types.User{Username: "alice"}, nil)
+		users.GetByUsernameFunc.PushReturn(&types.User{Username: "bob"}, nil)
+
+		_, _ = users.GetByUsername(ctx, "alice")
+		_, _ = users.GetByUsername(ctx, "bob")
+
+		users.AssertExpectations(rt)
+		require.Empty(t, rt.errors)
+	})
+
+	t.Run("unmet", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		users := NewStrictMockUserStoreWithT(rt)
+		users.GetByUsernameFunc.PushReturn(&types.User{Username: "alice"}, nil)
+		users.GetByUsernameFunc.PushReturn(&types.User{Username: "bob"}, nil)
+		users.GetByVerifiedEmailFunc.PushReturn(&types.User{Username: "carol"}, nil)
+
+		_, _ = users.GetByUsername(ctx, "alice")
+
+		users.AssertExpectations(rt)
+		require.Equal(t, []string{
+			"1 hooks of MockUserStore.GetByUsername were not called",
+			"1 hooks of MockUserStore.GetByVerifiedEmail were not called",
+		}, rt.errors)
+	})
+}
+
+// recordingT records the failures of a test instead of failing it.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
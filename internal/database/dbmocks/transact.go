@@ -0,0 +1,162 @@
+package dbmocks
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TransactionCounts is the number of transactions opened, committed and
+// rolled back on the mocks passed to Transactional. Nested transactions,
+// which are savepoints in the database, are counted too.
+type TransactionCounts struct {
+	Opened     int
+	Committed  int
+	RolledBack int
+}
+
+// Transactions records the transactions of the mocks passed to Transactional.
+type Transactions struct {
+	t      testing.TB
+	mu     sync.Mutex
+	counts TransactionCounts
+	depth  int
+}
+
+// Counts returns the number of transactions so far.
+func (txs *Transactions) Counts() TransactionCounts {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+	return txs.counts
+}
+
+func (txs *Transactions) begin() {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+	txs.counts.Opened++
+	txs.depth++
+}
+
+func (txs *Transactions) end(method string, rollback bool) {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+	if txs.depth == 0 {
+		txs.t.Errorf("unexpected call of %s outside of a transaction", method)
+		return
+	}
+	txs.depth--
+	if rollback {
+		txs.counts.RolledBack++
+	} else {
+		txs.counts.Committed++
+	}
+}
+
+// Transactional makes the transactions of mocks work like they do against
+// the database, and records them in the returned Transactions:
+//
+//   - WithTransact calls its callback with the mock itself, and commits the
+//     transaction unless the callback returns an error or panics.
+//   - Transact returns the mock itself, and Done commits the transaction
+//     unless it is given an error, which it returns like basestore does.
+//
+// Since the transaction is the mock itself, the stores a MockDB returns from
+// within WithTransact are the ones stubbed on it, and transactions nest.
+//
+//	db := dbmocks.NewMockDB()
+//	db.UsersFunc.SetDefaultReturn(users)
+//	txs := dbmocks.Transactional(t, db, users)
+//	...
+//	require.Equal(t, dbmocks.TransactionCounts{Opened: 1, Committed: 1}, txs.Counts())
+//
+// Transactional replaces the default hooks of these methods, so call it
+// before stubbing them.
+func Transactional(t testing.TB, mocks ...any) *Transactions {
+	t.Helper()
+
+	txs := &Transactions{t: t}
+	for _, mock := range mocks {
+		self := reflect.ValueOf(mock)
+		forEachFunc(t, mock, func(method string, f reflect.Value) {
+			setDefaultHook := f.MethodByName("SetDefaultHook")
+			hookType := setDefaultHook.Type().In(0)
+
+			var hook func(args []reflect.Value) []reflect.Value
+			switch {
+			case isMethod(method, "WithTransact"):
+				hook = func(args []reflect.Value) (results []reflect.Value) {
+					callback := args[len(args)-1]
+					txs.begin()
+					defer func() {
+						if r := recover(); r != nil {
+							txs.end(method, true)
+							panic(r)
+						}
+						txs.end(method, !results[0].IsNil())
+					}()
+					return callback.Call([]reflect.Value{asType(t, method, self, callback.Type().In(0))})
+				}
+
+			case isMethod(method, "Transact"):
+				hook = func(args []reflect.Value) []reflect.Value {
+					txs.begin()
+					return []reflect.Value{asType(t, method, self, hookType.Out(0)), reflect.Zero(hookType.Out(1))}
+				}
+
+			case isMethod(method, "Done"):
+				hook = func(args []reflect.Value) []reflect.Value {
+					err := args[0]
+					txs.end(method, !err.IsNil())
+					return []reflect.Value{err}
+				}
+
+			default:
+				return
+			}
+			setDefaultHook.Call([]reflect.Value{reflect.MakeFunc(hookType, hook)})
+		})
+	}
+
+	return txs
+}
+
+// isMethod reports whether method, as named by forEachFunc, is name.
+func isMethod(method, name string) bool {
+	return strings.HasSuffix(method, "."+name)
+}
+
+// asType returns the mock as the store type which method returns, or fails
+// t if the mock doesn't implement it.
+func asType(t testing.TB, method string, mock reflect.Value, typ reflect.Type) reflect.Value {
+	if !mock.Type().AssignableTo(typ) {
+		t.Fatalf("%s: %s does not implement %s", method, mock.Type(), typ)
+	}
+	v := reflect.New(typ).Elem()
+	v.Set(mock)
+	return v
+}
+
+// forEachFunc calls fn with the <Method>Func fields of mock, which must be a
+// pointer to a struct generated by go-mockgen.
+func forEachFunc(t testing.TB, mock any, fn func(method string, f reflect.Value)) {
+	t.Helper()
+
+	v := reflect.ValueOf(mock)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		t.Fatalf("expected a pointer to a generated mock, got %T", mock)
+	}
+
+	mockName := v.Elem().Type().Name()
+	for i := range v.Elem().NumField() {
+		field := v.Elem().Type().Field(i)
+		f := v.Elem().Field(i)
+		if !field.IsExported() || !strings.HasSuffix(field.Name, "Func") || f.Kind() != reflect.Pointer || f.IsNil() {
+			continue
+		}
+		if !f.MethodByName("SetDefaultHook").IsValid() {
+			continue
+		}
+		fn(mockName+"."+strings.TrimSuffix(field.Name, "Func"), f)
+	}
+}
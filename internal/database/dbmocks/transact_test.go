@@ -0,0 +1,118 @@
+package dbmocks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// createOrgWithMember writes to the users and the orgs stores within one
+// transaction.
+func createOrgWithMember(ctx context.Context, db database.DB, username, orgName string) error {
+	return db.WithTransact(ctx, func(tx database.DB) error {
+		user, err := tx.Users().Create(ctx, database.NewUser{Username: username})
+		if err != nil {
+			return err
+		}
+		org, err := tx.Orgs().Create(ctx, orgName, nil)
+		if err != nil {
+			return err
+		}
+		_, err = tx.OrgMembers().Create(ctx, org.ID, user.ID)
+		return err
+	})
+}
+
+func TestTransactional(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*MockDB, *MockUserStore, *MockOrgStore, *Transactions) {
+		users := NewMockUserStore()
+		users.CreateFunc.SetDefaultReturn(&types.User{ID: 1}, nil)
+		orgs := NewMockOrgStore()
+		orgs.CreateFunc.SetDefaultReturn(&types.Org{ID: 2}, nil)
+		db := NewMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+		db.OrgsFunc.SetDefaultReturn(orgs)
+		db.OrgMembersFunc.SetDefaultReturn(NewMockOrgMemberStore())
+		return db, users, orgs, Transactional(t, db)
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		db, users, orgs, txs := setup(t)
+
+		require.NoError(t, createOrgWithMember(ctx, db, "alice", "acme"))
+		require.Equal(t, TransactionCounts{Opened: 1, Committed: 1}, txs.Counts())
+		require.Len(t, users.CreateFunc.History(), 1)
+		require.Len(t, orgs.CreateFunc.History(), 1)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		db, _, orgs, txs := setup(t)
+		orgs.CreateFunc.SetDefaultReturn(nil, errors.New("name taken"))
+
+		require.ErrorContains(t, createOrgWithMember(ctx, db, "alice", "acme"), "name taken")
+		require.Equal(t, TransactionCounts{Opened: 1, RolledBack: 1}, txs.Counts())
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		db, _, orgs, txs := setup(t)
+		orgs.CreateFunc.SetDefaultHook(func(context.Context, string, *string) (*types.Org, error) {
+			panic("boom")
+		})
+
+		require.PanicsWithValue(t, "boom", func() {
+			_ = createOrgWithMember(ctx, db, "alice", "acme")
+		})
+		require.Equal(t, TransactionCounts{Opened: 1, RolledBack: 1}, txs.Counts())
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		db, users, _, txs := setup(t)
+
+		err := db.WithTransact(ctx, func(tx database.DB) error {
+			if err := createOrgWithMember(ctx, tx, "alice", "acme"); err != nil {
+				return err
+			}
+			_ = tx.WithTransact(ctx, func(tx database.DB) error {
+				return errors.New("rolled back to the savepoint")
+			})
+			return createOrgWithMember(ctx, tx, "bob", "globex")
+		})
+		require.NoError(t, err)
+		require.Equal(t, TransactionCounts{Opened: 4, Committed: 3, RolledBack: 1}, txs.Counts())
+		require.Len(t, users.CreateFunc.History(), 2)
+	})
+
+	t.Run("basestore transactions of stores", func(t *testing.T) {
+		users := NewMockUserStore()
+		txs := Transactional(t, users)
+
+		err := basestore.InTransaction[database.UserStore](ctx, users, func(tx database.UserStore) error {
+			require.Same(t, users, tx)
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = basestore.InTransaction[database.UserStore](ctx, users, func(tx database.UserStore) error {
+			return errors.New("failed")
+		})
+		require.Error(t, err)
+		require.Equal(t, TransactionCounts{Opened: 2, Committed: 1, RolledBack: 1}, txs.Counts())
+	})
+
+	t.Run("Done outside of a transaction", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		users := NewMockUserStore()
+		Transactional(rt, users)
+
+		require.NoError(t, users.Done(nil))
+		require.Equal(t, []string{"unexpected call of MockUserStore.Done outside of a transaction"}, rt.errors)
+	})
+}
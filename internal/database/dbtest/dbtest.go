@@ -1,6 +1,7 @@
 package dbtest
 
 import (
+	"context"
 	crand "crypto/rand"
 	"database/sql"
 	"encoding/binary"
@@ -13,11 +14,14 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
 
 	connections "github.com/sourcegraph/sourcegraph/internal/database/connections/test"
 	"github.com/sourcegraph/sourcegraph/internal/database/migration/schemas"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 
 	"github.com/sourcegraph/log"
 	"github.com/sourcegraph/log/logtest"
@@ -66,14 +70,54 @@ var rngLock sync.Mutex
 // the same schema as Sourcegraph's production Postgres database.
 func NewDB(t testing.TB) *sql.DB {
 	logger := logtest.Scoped(t)
-	return newDB(logger, t, "migrated", schemas.Frontend, schemas.CodeIntel)
+	return newDB(logger, t, dbOptions{}, "migrated", schemas.Frontend, schemas.CodeIntel)
 }
 
+// NewParallelDB is like NewDB, but for tests which call t.Parallel(). The
+// database is dropped when the test finishes, even if it failed. At most
+// TESTDB_MAXPARALLEL of these databases (4 by default) exist at a time, so that
+// parallel tests don't exhaust the connections of Postgres: NewParallelDB
+// blocks until another test is done with its database, so a single test must
+// not hold more than that.
+func NewParallelDB(t testing.TB, opts ...ParallelOption) *sql.DB {
+	logger := logtest.Scoped(t)
+	options := dbOptions{parallel: true}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return newDB(logger, t, options, "migrated", schemas.Frontend, schemas.CodeIntel)
+}
+
+// ParallelOption configures the databases returned by NewParallelDB.
+type ParallelOption func(*dbOptions)
+
+// WithLeakDetection fails the test if connections to the database are still in
+// use when the test finishes, which happens when rows or transactions are not
+// closed.
+func WithLeakDetection() ParallelOption {
+	return func(o *dbOptions) { o.detectLeaks = true }
+}
+
+type dbOptions struct {
+	// parallel is set for the databases of NewParallelDB.
+	parallel    bool
+	detectLeaks bool
+}
+
+// parallelDBs holds a token for every database of NewParallelDB.
+var parallelDBs = sync.OnceValue(func() chan struct{} {
+	n, err := strconv.Atoi(os.Getenv("TESTDB_MAXPARALLEL"))
+	if err != nil || n <= 0 {
+		n = 4
+	}
+	return make(chan struct{}, n)
+})
+
 // NewCodeintelDB returns a connection to a new clean temporary testing database
 // with only the codeintel schema applied
 func NewCodeintelDB(t testing.TB) *sql.DB {
 	logger := logtest.Scoped(t)
-	return newDB(logger, t, "migrated-codeintel", schemas.CodeIntel)
+	return newDB(logger, t, dbOptions{}, "migrated-codeintel", schemas.CodeIntel)
 }
 
 // NewDBAtRev returns a connection to a clean, new temporary testing database with
@@ -82,6 +126,7 @@ func NewDBAtRev(logger log.Logger, t testing.TB, rev string) *sql.DB {
 	return newDB(
 		logger,
 		t,
+		dbOptions{},
 		fmt.Sprintf("migrated-%s", rev),
 		getSchemaAtRev(t, "frontend", rev),
 		getSchemaAtRev(t, "codeintel", rev),
@@ -100,46 +145,68 @@ func getSchemaAtRev(t testing.TB, name, rev string) *schemas.Schema {
 // NewInsightsDB returns a connection to a clean, new temporary testing database with
 // the same schema as Sourcegraph's CodeInsights production Postgres database.
 func NewInsightsDB(logger log.Logger, t testing.TB) *sql.DB {
-	return newDB(logger, t, "insights", schemas.CodeInsights)
+	return newDB(logger, t, dbOptions{}, "insights", schemas.CodeInsights)
 }
 
 // NewRawDB returns a connection to a clean, new temporary testing database.
 func NewRawDB(logger log.Logger, t testing.TB) *sql.DB {
-	return newDB(logger, t, "raw")
+	return newDB(logger, t, dbOptions{}, "raw")
 }
 
-func newDB(logger log.Logger, t testing.TB, name string, schemas ...*schemas.Schema) *sql.DB {
+func newDB(logger log.Logger, t testing.TB, opts dbOptions, name string, schemas ...*schemas.Schema) *sql.DB {
 	if testing.Short() {
 		t.Skip("DB tests disabled since go test -short is specified")
 	}
 
-	onceByName(name).Do(func() { initTemplateDB(logger, t, name, schemas) })
-	return newFromDSN(logger, t, name)
+	tmpl := templateByName(name)
+	tmpl.once.Do(func() { tmpl.init(logger, t, name, schemas) })
+	return newFromDSN(logger, t, opts, tmpl, schemas)
+}
+
+// template is the template database of a namespace, which is migrated once
+// and then copied by every call to newDB.
+type template struct {
+	once sync.Once
+
+	// name is the name of the template database. It is empty if the
+	// template could not be created, in which case every database is
+	// migrated from scratch.
+	name string
+	// migration is how long the migration of the template took, or zero if
+	// the template was created by an earlier run.
+	migration time.Duration
 }
 
 var (
-	onceByNameMap   = map[string]*sync.Once{}
-	onceByNameMutex sync.Mutex
+	templatesByName      = map[string]*template{}
+	templatesByNameMutex sync.Mutex
 )
 
-func onceByName(name string) *sync.Once {
-	onceByNameMutex.Lock()
-	defer onceByNameMutex.Unlock()
+func templateByName(name string) *template {
+	templatesByNameMutex.Lock()
+	defer templatesByNameMutex.Unlock()
 
-	if once, ok := onceByNameMap[name]; ok {
-		return once
+	if tmpl, ok := templatesByName[name]; ok {
+		return tmpl
 	}
 
-	once := new(sync.Once)
-	onceByNameMap[name] = once
-	return once
+	tmpl := new(template)
+	templatesByName[name] = tmpl
+	return tmpl
 }
 
-func newFromDSN(logger log.Logger, t testing.TB, templateNamespace string) *sql.DB {
+func newFromDSN(logger log.Logger, t testing.TB, opts dbOptions, tmpl *template, schemas []*schemas.Schema) *sql.DB {
 	if testing.Short() {
 		t.Skip("skipping DB test since -short specified")
 	}
 
+	if opts.parallel {
+		parallelDBs() <- struct{}{}
+		// Cleanups run last in first out, so the token is returned once the
+		// database is dropped.
+		t.Cleanup(func() { <-parallelDBs() })
+	}
+
 	config, err := GetDSN()
 	if err != nil {
 		t.Fatalf("failed to parse dsn: %s", err)
@@ -150,11 +217,29 @@ func newFromDSN(logger log.Logger, t testing.TB, templateNamespace string) *sql.
 	rngLock.Unlock()
 
 	db := dbConn(logger, t, config)
-	dbExec(t, db, `CREATE DATABASE `+pq.QuoteIdentifier(dbname)+` TEMPLATE `+pq.QuoteIdentifier(templateDBName(templateNamespace)))
+	start := time.Now()
+	if tmpl.name != "" {
+		dbExec(t, db, `CREATE DATABASE `+pq.QuoteIdentifier(dbname)+` TEMPLATE `+pq.QuoteIdentifier(tmpl.name))
+	} else {
+		dbExec(t, db, `CREATE DATABASE `+pq.QuoteIdentifier(dbname)+` TEMPLATE template0`)
+	}
 
 	config.Path = "/" + dbname
-	testDB := dbConn(logger, t, config)
-	t.Logf("testdb: %s", config.String())
+	var testDB *sql.DB
+	if tmpl.name != "" {
+		testDB = dbConn(logger, t, config)
+		t.Logf("testdb: %s", config.String())
+		if tmpl.migration > 0 {
+			elapsed := time.Since(start)
+			t.Logf("testdb: copied the template in %s instead of migrating for %s (%.0fx faster)", elapsed.Round(time.Millisecond), tmpl.migration.Round(time.Millisecond), float64(tmpl.migration)/float64(elapsed))
+		} else {
+			t.Logf("testdb: copied the template in %s", time.Since(start).Round(time.Millisecond))
+		}
+	} else {
+		testDB = dbConn(logger, t, config, schemas...)
+		t.Logf("testdb: %s", config.String())
+		t.Logf("testdb: migrated in %s without a template", time.Since(start).Round(time.Millisecond))
+	}
 
 	// Some tests that exercise concurrency need lots of connections or they block forever.
 	// e.g. TestIntegration/DBStore/Syncer/MultipleServices
@@ -168,7 +253,13 @@ func newFromDSN(logger log.Logger, t testing.TB, templateNamespace string) *sql.
 	t.Cleanup(func() {
 		defer db.Close()
 
-		if t.Failed() && os.Getenv("CI") != "true" {
+		if opts.detectLeaks {
+			if inUse := testDB.Stats().InUse; inUse > 0 {
+				t.Errorf("%d connections to the test database are still in use, close all rows and transactions", inUse)
+			}
+		}
+
+		if t.Failed() && !opts.parallel && os.Getenv("CI") != "true" {
 			t.Logf("DATABASE %s left intact for inspection", dbname)
 			return
 		}
@@ -183,10 +274,17 @@ func newFromDSN(logger log.Logger, t testing.TB, templateNamespace string) *sql.
 	return testDB
 }
 
-// initTemplateDB creates a template database with a fully migrated schema for the
-// current package. New databases can then do a cheap copy of the migrated schema
-// rather than running the full migration every time.
-func initTemplateDB(logger log.Logger, t testing.TB, templateNamespace string, dbSchemas []*schemas.Schema) {
+// init finds or creates the template database with a fully migrated schema.
+// New databases can then do a cheap copy of the migrated schema rather than
+// running the full migration every time.
+//
+// The name of the template contains a hash of the migrations, so templates
+// are shared by the packages and the runs using the same migrations, and
+// rebuilt when they change. An advisory lock keeps packages tested in parallel
+// from creating the same template twice. If the template can't be created,
+// for example because the user is not allowed to, tmpl.name is left empty
+// and the databases are migrated from scratch.
+func (tmpl *template) init(logger log.Logger, t testing.TB, templateNamespace string, dbSchemas []*schemas.Schema) {
 	config, err := GetDSN()
 	if err != nil {
 		t.Fatalf("failed to parse dsn: %s", err)
@@ -195,44 +293,112 @@ func initTemplateDB(logger log.Logger, t testing.TB, templateNamespace string, d
 	db := dbConn(logger, t, config)
 	defer db.Close()
 
-	init := func(templateNamespace string, schemas []*schemas.Schema) {
-		templateName := templateDBName(templateNamespace)
-		name := pq.QuoteIdentifier(templateName)
+	name := templateDBName(templateNamespace, dbSchemas)
+	start := time.Now()
+	created, err := createTemplateDB(logger, t, db, config, name, dbSchemas)
+	if err != nil {
+		t.Logf("testdb: migrating every database, failed to create template %s: %s", name, err)
+		return
+	}
+
+	tmpl.name = name
+	if created {
+		tmpl.migration = time.Since(start)
+	}
+}
+
+// templateCompleteComment marks the template databases which are fully
+// migrated, so that an interrupted migration is not mistaken for a template.
+const templateCompleteComment = "sourcegraph-test-template-complete"
+
+// createTemplateDB creates the template database name unless a complete one
+// exists, and reports whether it did.
+func createTemplateDB(logger log.Logger, t testing.TB, db *sql.DB, config *url.URL, name string, dbSchemas []*schemas.Schema) (created bool, err error) {
+	ctx := context.Background()
 
-		// We must first drop the template database because
-		// migrations would not run on it if they had already ran,
-		// even if the content of the migrations had changed during development.
+	// Advisory locks are held by a session, so the lock and the unlock must
+	// use the same connection.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
 
-		dbExec(t, db, `DROP DATABASE IF EXISTS `+name)
-		dbExec(t, db, `CREATE DATABASE `+name+` TEMPLATE template0`)
+	lockKey := int64(hash(name))
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return false, errors.Wrap(err, "acquiring lock")
+	}
+	defer func() {
+		if _, unlockErr := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, lockKey); unlockErr != nil {
+			err = errors.Append(err, errors.Wrap(unlockErr, "releasing lock"))
+		}
+	}()
 
-		cfgCopy := *config
-		cfgCopy.Path = "/" + templateName
-		dbConn(logger, t, &cfgCopy, schemas...).Close()
+	var comment sql.NullString
+	err = conn.QueryRowContext(ctx, `SELECT shobj_description(oid, 'pg_database') FROM pg_database WHERE datname = $1`, name).Scan(&comment)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	if comment.String == templateCompleteComment {
+		return false, nil
 	}
 
-	init(templateNamespace, dbSchemas)
+	// A template without the comment is left over from an interrupted run.
+	quoted := pq.QuoteIdentifier(name)
+	for _, q := range []string{
+		`DROP DATABASE IF EXISTS ` + quoted,
+		`CREATE DATABASE ` + quoted + ` TEMPLATE template0`,
+	} {
+		if _, err := conn.ExecContext(ctx, q); err != nil {
+			return false, err
+		}
+	}
+
+	cfgCopy := *config
+	cfgCopy.Path = "/" + name
+	templateDB, err := connections.NewTestDB(t, logger, cfgCopy.String(), dbSchemas...)
+	if err != nil {
+		return false, errors.Wrap(err, "migrating")
+	}
+	if err := templateDB.Close(); err != nil {
+		return false, err
+	}
+
+	if _, err := conn.ExecContext(ctx, `COMMENT ON DATABASE `+quoted+` IS `+pq.QuoteLiteral(templateCompleteComment)); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// templateDBName returns the name of the template database for the currently running package and namespace.
-func templateDBName(templateNamespace string) string {
+// templateDBName returns the name of the template database for the namespace
+// and the migrations of schemas.
+func templateDBName(templateNamespace string, schemas []*schemas.Schema) string {
 	parts := []string{
 		"sourcegraph-test-template",
-		wdHash(),
+		strconv.FormatUint(schemasHash(schemas), 36),
 		templateNamespace,
 	}
 
 	return strings.Join(parts, "-")
 }
 
-// wdHash returns a hash of the current working directory.
-// This is useful to get a stable identifier for the package running
-// the tests.
-func wdHash() string {
+// schemasHash returns a hash of the migrations of schemas, which changes when
+// a migration is added or edited.
+func schemasHash(schemas []*schemas.Schema) uint64 {
+	h := fnv.New64()
+	for _, schema := range schemas {
+		fmt.Fprintf(h, "schema %s\n", schema.Name)
+		for _, def := range schema.Definitions.All() {
+			fmt.Fprintf(h, "migration %d\n%s\n", def.ID, def.UpQuery.Query(sqlf.PostgresBindVar))
+		}
+	}
+	return h.Sum64()
+}
+
+func hash(s string) uint64 {
 	h := fnv.New64()
-	wd, _ := os.Getwd()
-	h.Write([]byte(wd))
-	return strconv.FormatUint(h.Sum64(), 10)
+	h.Write([]byte(s))
+	return h.Sum64()
 }
 
 func dbConn(logger log.Logger, t testing.TB, cfg *url.URL, schemas ...*schemas.Schema) *sql.DB {
@@ -0,0 +1,36 @@
+package dbtest
+
+import (
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/definition"
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/schemas"
+)
+
+func TestTemplateDBName(t *testing.T) {
+	newSchema := func(upQuery string) *schemas.Schema {
+		definitions, err := definition.NewDefinitions([]definition.Definition{
+			{ID: 1, UpQuery: sqlf.Sprintf(upQuery)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &schemas.Schema{Name: "frontend", Definitions: definitions}
+	}
+
+	name := templateDBName("migrated", []*schemas.Schema{newSchema("CREATE TABLE t()")})
+	if len(name) > 63 {
+		t.Errorf("name %q is longer than the Postgres limit", name)
+	}
+	if got := templateDBName("migrated", []*schemas.Schema{newSchema("CREATE TABLE t()")}); got != name {
+		t.Errorf("same migrations got different names %q and %q", name, got)
+	}
+	if got := templateDBName("migrated", []*schemas.Schema{newSchema("CREATE TABLE u()")}); got == name {
+		t.Errorf("edited migration got the same name %q", got)
+	}
+	if got := templateDBName("raw", []*schemas.Schema{newSchema("CREATE TABLE t()")}); got == name {
+		t.Errorf("other namespace got the same name %q", got)
+	}
+}
@@ -0,0 +1,360 @@
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Snapshot is the state of tables of a test database, which Restore brings
+// back. It lets the subtests of a suite share the rows seeded once, rather
+// than seeding them again in every subtest or seeing each other's changes:
+//
+//	db := dbtest.NewDB(t)
+//	seed(t, db)
+//	snapshot := dbtest.NewSnapshot(t, db, "users", "orgs", "org_members")
+//
+//	t.Run("...", func(t *testing.T) {
+//		t.Cleanup(func() { snapshot.Restore(t) })
+//		...
+//	})
+//
+// The rows of the tables are copied into a schema of the database, and the
+// values of the sequences the tables own are recorded, so that the IDs
+// assigned after a restore are the same as after the seeding.
+//
+// Restore empties the tables which reference the tables of the snapshot,
+// since it truncates them with CASCADE, so these tables should be part of the
+// snapshot if they have rows to keep. Triggers defined on the tables don't
+// fire while their rows are restored. The snapshot is invalid once the
+// schema of its tables changes, e.g. when a test adds a column: Restore then
+// fails rather than restore the rows partially. The other changes to the
+// schema, like new tables, are not undone.
+type Snapshot struct {
+	db     *sql.DB
+	schema string
+	// tables are ordered so that a table comes after the tables it
+	// references, which is the order their rows are restored in.
+	tables    []snapshotTable
+	sequences []snapshotSequence
+}
+
+type snapshotTable struct {
+	name string
+	// columns are the columns whose values are restored, i.e. not the
+	// generated ones.
+	columns []string
+}
+
+type snapshotSequence struct {
+	name      string
+	lastValue sql.NullInt64
+	start     int64
+}
+
+// NewSnapshot takes a snapshot of tables, or of every table of the public
+// schema if none are given. The snapshot is dropped when the test finishes.
+func NewSnapshot(t testing.TB, db *sql.DB, tables ...string) *Snapshot {
+	t.Helper()
+
+	rngLock.Lock()
+	schema := "dbtest_snapshot_" + strconv.FormatUint(rng.Uint64(), 10)
+	rngLock.Unlock()
+
+	s := &Snapshot{db: db, schema: schema}
+	if err := s.take(context.Background(), tables); err != nil {
+		t.Fatalf("failed to take snapshot: %s", err)
+	}
+	t.Cleanup(func() {
+		dbExec(t, db, `DROP SCHEMA IF EXISTS `+pq.QuoteIdentifier(schema)+` CASCADE`)
+	})
+	return s
+}
+
+func (s *Snapshot) take(ctx context.Context, tables []string) (err error) {
+	// The rows and the sequences are read from the same database snapshot,
+	// so that the sequences are consistent with the IDs of the rows.
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Append(err, tx.Rollback())
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	if len(tables) == 0 {
+		if tables, err = publicTables(ctx, tx); err != nil {
+			return errors.Wrap(err, "listing tables")
+		}
+	}
+	if tables, err = orderByReferences(ctx, tx, tables); err != nil {
+		return errors.Wrap(err, "ordering tables")
+	}
+
+	if _, err := tx.ExecContext(ctx, `CREATE SCHEMA `+pq.QuoteIdentifier(s.schema)); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		columns, err := restorableColumns(ctx, tx, table)
+		if err != nil {
+			return errors.Wrapf(err, "listing columns of %s", table)
+		}
+		if len(columns) == 0 {
+			return errors.Newf("table %q does not exist", table)
+		}
+		s.tables = append(s.tables, snapshotTable{name: table, columns: columns})
+
+		q := `CREATE TABLE ` + s.copyOf(table) + ` AS SELECT ` + quoteIdentifiers(columns) + ` FROM ` + quoteTable(table)
+		if _, err := tx.ExecContext(ctx, q); err != nil {
+			return errors.Wrapf(err, "copying %s", table)
+		}
+	}
+
+	s.sequences, err = ownedSequences(ctx, tx, tables)
+	return errors.Wrap(err, "reading sequences")
+}
+
+// Restore brings the tables of the snapshot back to the state they were in
+// when the snapshot was taken.
+func (s *Snapshot) Restore(t testing.TB) {
+	t.Helper()
+
+	if err := s.restore(context.Background()); err != nil {
+		t.Fatalf("failed to restore snapshot: %s", err)
+	}
+}
+
+func (s *Snapshot) restore(ctx context.Context) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Append(err, tx.Rollback())
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// Deferrable constraints, e.g. between tables which reference each other,
+	// are checked once every table is restored.
+	if _, err := tx.ExecContext(ctx, `SET CONSTRAINTS ALL DEFERRED`); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(s.tables))
+	for _, table := range s.tables {
+		columns, err := restorableColumns(ctx, tx, table.name)
+		if err != nil {
+			return errors.Wrapf(err, "listing columns of %s", table.name)
+		}
+		if !slices.Equal(columns, table.columns) {
+			return errors.Newf("the columns of %s changed since the snapshot was taken", table.name)
+		}
+		names = append(names, quoteTable(table.name))
+	}
+
+	var queries []string
+	// DISABLE TRIGGER USER keeps the constraints, unlike
+	// session_replication_role, which also requires a superuser.
+	for _, name := range names {
+		queries = append(queries, `ALTER TABLE `+name+` DISABLE TRIGGER USER`)
+	}
+	queries = append(queries, `TRUNCATE `+strings.Join(names, ", ")+` CASCADE`)
+	for _, table := range s.tables {
+		columns := quoteIdentifiers(table.columns)
+		queries = append(queries, `INSERT INTO `+quoteTable(table.name)+` (`+columns+`) OVERRIDING SYSTEM VALUE SELECT `+columns+` FROM `+s.copyOf(table.name))
+	}
+	for _, name := range names {
+		queries = append(queries, `ALTER TABLE `+name+` ENABLE TRIGGER USER`)
+	}
+	for _, q := range queries {
+		if _, err := tx.ExecContext(ctx, q); err != nil {
+			return errors.Wrapf(err, "executing %q", q)
+		}
+	}
+
+	for _, seq := range s.sequences {
+		value, isCalled := seq.lastValue.Int64, true
+		if !seq.lastValue.Valid {
+			// nextval was never called on the sequence.
+			value, isCalled = seq.start, false
+		}
+		if _, err := tx.ExecContext(ctx, `SELECT setval($1::regclass, $2, $3)`, quoteTable(seq.name), value, isCalled); err != nil {
+			return errors.Wrapf(err, "resetting sequence %s", seq.name)
+		}
+	}
+
+	return nil
+}
+
+// copyOf returns the quoted name of the copy of table.
+func (s *Snapshot) copyOf(table string) string {
+	return pq.QuoteIdentifier(s.schema) + "." + pq.QuoteIdentifier(table)
+}
+
+// publicTables returns the tables of the public schema. Partitions are left
+// out, since their rows are restored through their partitioned table.
+func publicTables(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	return scanStrings(tx.QueryContext(ctx, `
+		SELECT c.relname
+		FROM pg_class c
+		WHERE
+			c.relnamespace = 'public'::regnamespace AND
+			c.relkind IN ('r', 'p') AND
+			NOT c.relispartition
+		ORDER BY c.relname
+	`))
+}
+
+// restorableColumns returns the columns of table, except the generated ones,
+// whose values can't be inserted.
+func restorableColumns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	return scanStrings(tx.QueryContext(ctx, `
+		SELECT a.attname
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		WHERE
+			c.relnamespace = 'public'::regnamespace AND
+			c.relname = $1 AND
+			a.attnum > 0 AND
+			NOT a.attisdropped AND
+			a.attgenerated = ''
+		ORDER BY a.attnum
+	`, table))
+}
+
+// orderByReferences orders tables so that a table comes after the tables it
+// references through a foreign key. Tables which reference each other are
+// kept in the order they are given in, and rely on deferred constraints.
+func orderByReferences(ctx context.Context, tx *sql.Tx, tables []string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT c.relname, r.relname
+		FROM pg_constraint k
+		JOIN pg_class c ON c.oid = k.conrelid
+		JOIN pg_class r ON r.oid = k.confrelid
+		WHERE
+			k.contype = 'f' AND
+			c.relnamespace = 'public'::regnamespace AND
+			r.relnamespace = 'public'::regnamespace AND
+			c.relname = ANY($1) AND
+			r.relname = ANY($1) AND
+			c.oid <> r.oid
+	`, pq.Array(tables))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	references := map[string][]string{}
+	for rows.Next() {
+		var table, referenced string
+		if err := rows.Scan(&table, &referenced); err != nil {
+			return nil, err
+		}
+		references[table] = append(references[table], referenced)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, referenced := range references {
+		sort.Strings(referenced)
+	}
+
+	ordered := make([]string, 0, len(tables))
+	// state is 1 while the references of a table are visited, and 2 once the
+	// table is ordered.
+	state := map[string]int{}
+	var visit func(table string)
+	visit = func(table string) {
+		if state[table] != 0 {
+			return
+		}
+		state[table] = 1
+		for _, referenced := range references[table] {
+			visit(referenced)
+		}
+		state[table] = 2
+		ordered = append(ordered, table)
+	}
+	for _, table := range tables {
+		visit(table)
+	}
+	return ordered, nil
+}
+
+// ownedSequences returns the sequences owned by the columns of tables, such
+// as the sequences of serial and identity columns, with their current value.
+func ownedSequences(ctx context.Context, tx *sql.Tx, tables []string) ([]snapshotSequence, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT s.sequencename, s.last_value, s.start_value
+		FROM pg_depend d
+		JOIN pg_class seq ON seq.oid = d.objid
+		JOIN pg_class t ON t.oid = d.refobjid
+		JOIN pg_sequences s ON s.schemaname = 'public' AND s.sequencename = seq.relname
+		WHERE
+			d.classid = 'pg_class'::regclass AND
+			d.refclassid = 'pg_class'::regclass AND
+			d.deptype IN ('a', 'i') AND
+			seq.relkind = 'S' AND
+			seq.relnamespace = 'public'::regnamespace AND
+			t.relname = ANY($1)
+		ORDER BY s.sequencename
+	`, pq.Array(tables))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sequences []snapshotSequence
+	for rows.Next() {
+		var seq snapshotSequence
+		if err := rows.Scan(&seq.name, &seq.lastValue, &seq.start); err != nil {
+			return nil, err
+		}
+		sequences = append(sequences, seq)
+	}
+	return sequences, rows.Err()
+}
+
+func scanStrings(rows *sql.Rows, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+func quoteTable(table string) string {
+	return "public." + pq.QuoteIdentifier(table)
+}
+
+func quoteIdentifiers(identifiers []string) string {
+	quoted := make([]string, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		quoted = append(quoted, pq.QuoteIdentifier(identifier))
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,112 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	db := NewDB(t)
+
+	exec := func(t *testing.T, q string, args ...any) {
+		t.Helper()
+		if _, err := db.Exec(q, args...); err != nil {
+			t.Fatalf("failed to exec %q: %s", q, err)
+		}
+	}
+	queryInt := func(t *testing.T, q string, args ...any) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(q, args...).Scan(&n); err != nil {
+			t.Fatalf("failed to query %q: %s", q, err)
+		}
+		return n
+	}
+
+	exec(t, `INSERT INTO users (username) VALUES ('alice'), ('bob')`)
+	exec(t, `INSERT INTO orgs (name) VALUES ('acme')`)
+	exec(t, `INSERT INTO org_members (org_id, user_id) SELECT o.id, u.id FROM orgs o, users u`)
+
+	// org_members is listed first, so that the snapshot has to order it
+	// after the tables it references.
+	snapshot := NewSnapshot(t, db, "org_members", "orgs", "users")
+
+	// requireSeeded checks that the database has the seeded rows, and that the
+	// next user gets the same ID as it would right after the seeding.
+	requireSeeded := func(t *testing.T) {
+		t.Helper()
+		if n := queryInt(t, `SELECT COUNT(*) FROM users`); n != 2 {
+			t.Fatalf("got %d users, want 2", n)
+		}
+		if n := queryInt(t, `SELECT COUNT(*) FROM org_members`); n != 2 {
+			t.Fatalf("got %d org members, want 2", n)
+		}
+		var name string
+		if err := db.QueryRow(`SELECT name FROM orgs`).Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		if name != "acme" {
+			t.Fatalf("got org %q, want acme", name)
+		}
+		if id := queryInt(t, `SELECT last_value FROM users_id_seq`); id != 2 {
+			t.Fatalf("got user ID sequence at %d, want 2", id)
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(t *testing.T)
+	}{
+		{
+			name: "insert",
+			mutate: func(t *testing.T) {
+				exec(t, `INSERT INTO users (username) VALUES ('carol')`)
+				exec(t, `INSERT INTO org_members (org_id, user_id) SELECT o.id, u.id FROM orgs o, users u WHERE u.username = 'carol'`)
+			},
+		},
+		{
+			name: "update",
+			mutate: func(t *testing.T) {
+				exec(t, `UPDATE orgs SET name = 'evil-corp'`)
+				exec(t, `UPDATE users SET site_admin = true`)
+			},
+		},
+		{
+			name: "delete",
+			mutate: func(t *testing.T) {
+				exec(t, `DELETE FROM org_members`)
+				exec(t, `DELETE FROM users WHERE username = 'bob'`)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() { snapshot.Restore(t) })
+
+			requireSeeded(t)
+			if n := queryInt(t, `SELECT COUNT(*) FROM users WHERE site_admin`); n != 0 {
+				t.Fatalf("got %d site admins, want 0", n)
+			}
+			tc.mutate(t)
+		})
+	}
+
+	requireSeeded(t)
+
+	var id int
+	if err := db.QueryRow(`INSERT INTO users (username) VALUES ('dave') RETURNING id`).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	if id != 3 {
+		t.Fatalf("got ID %d for the first user after the restores, want 3", id)
+	}
+
+	t.Run("changed schema", func(t *testing.T) {
+		exec(t, `ALTER TABLE orgs ADD COLUMN motto text`)
+		if err := snapshot.restore(context.Background()); err == nil {
+			t.Fatal("expected an error restoring a table whose columns changed")
+		}
+		if n := queryInt(t, `SELECT COUNT(*) FROM users`); n != 3 {
+			t.Fatalf("got %d users after the failed restore, want 3", n)
+		}
+	})
+}
@@ -0,0 +1,306 @@
+package fakedb_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/database/fakedb"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// conformance runs test against the fakes, and against the stores backed by
+// Postgres, to check that the fakes behave like the database.
+func conformance(t *testing.T, test func(t *testing.T, db database.DB)) {
+	t.Run("fake", func(t *testing.T) {
+		db := dbmocks.NewMockDB()
+		fakedb.New().Wire(db)
+		test(t, db)
+	})
+	t.Run("postgres", func(t *testing.T) {
+		logger := logtest.Scoped(t)
+		test(t, database.NewDB(logger, dbtest.NewDB(t)))
+	})
+}
+
+func TestUsersConformance(t *testing.T) {
+	conformance(t, func(t *testing.T, db database.DB) {
+		ctx := context.Background()
+		users := db.Users()
+
+		alice, err := users.Create(ctx, database.NewUser{Username: "alice", DisplayName: "Alice", Password: "p4ssw0rd"})
+		require.NoError(t, err)
+		require.True(t, alice.SiteAdmin, "the first user is the site admin")
+		require.True(t, alice.BuiltinAuth)
+		bob, err := users.Create(ctx, database.NewUser{Username: "bob"})
+		require.NoError(t, err)
+		require.False(t, bob.SiteAdmin)
+		require.Greater(t, bob.ID, alice.ID)
+
+		_, err = users.Create(ctx, database.NewUser{Username: "ALICE"})
+		require.True(t, database.IsUsernameExists(err), "got %v", err)
+		_, err = db.Orgs().Create(ctx, "carol", nil)
+		require.NoError(t, err)
+		_, err = users.Create(ctx, database.NewUser{Username: "carol"})
+		require.True(t, database.IsUsernameExists(err), "got %v", err)
+
+		got, err := users.GetByUsername(ctx, "Alice")
+		require.NoError(t, err)
+		require.Equal(t, alice.ID, got.ID)
+		got, err = users.GetByID(ctx, bob.ID)
+		require.NoError(t, err)
+		require.Equal(t, "bob", got.Username)
+
+		for _, tc := range []struct {
+			name string
+			opts *database.UsersListOptions
+			want []int32
+		}{
+			{"all", nil, []int32{alice.ID, bob.ID}},
+			{"query", &database.UsersListOptions{Query: "LIC"}, []int32{alice.ID}},
+			{"no user IDs", &database.UsersListOptions{UserIDs: []int32{}}, nil},
+			{"user IDs", &database.UsersListOptions{UserIDs: []int32{bob.ID}}, []int32{bob.ID}},
+			{"usernames", &database.UsersListOptions{Usernames: []string{"alice", "dave"}}, []int32{alice.ID}},
+			{"limit offset", &database.UsersListOptions{LimitOffset: &database.LimitOffset{Limit: 1, Offset: 1}}, []int32{bob.ID}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				list, err := users.List(ctx, tc.opts)
+				require.NoError(t, err)
+				require.Equal(t, tc.want, userIDs(list))
+			})
+		}
+		count, err := users.Count(ctx, &database.UsersListOptions{LimitOffset: &database.LimitOffset{Limit: 1}})
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+
+		require.NoError(t, users.Delete(ctx, bob.ID))
+		_, err = users.GetByID(ctx, bob.ID)
+		require.True(t, database.IsUserNotFoundErr(err), "got %v", err)
+		_, err = users.GetByUsername(ctx, "bob")
+		require.True(t, database.IsUserNotFoundErr(err), "got %v", err)
+		require.True(t, database.IsUserNotFoundErr(users.Delete(ctx, bob.ID)))
+		count, err = users.Count(ctx, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		// Soft deleting releases the username.
+		_, err = users.Create(ctx, database.NewUser{Username: "bob"})
+		require.NoError(t, err)
+	})
+}
+
+func TestOrgsConformance(t *testing.T) {
+	conformance(t, func(t *testing.T, db database.DB) {
+		ctx := context.Background()
+		orgs := db.Orgs()
+
+		acme, err := orgs.Create(ctx, "acme", pointers.Ptr("ACME Corp"))
+		require.NoError(t, err)
+		globex, err := orgs.Create(ctx, "globex", nil)
+		require.NoError(t, err)
+		require.Greater(t, globex.ID, acme.ID)
+
+		_, err = orgs.Create(ctx, "Acme", nil)
+		require.Error(t, err)
+		_, err = db.Users().Create(ctx, database.NewUser{Username: "alice"})
+		require.NoError(t, err)
+		_, err = orgs.Create(ctx, "alice", nil)
+		require.Error(t, err)
+
+		got, err := orgs.GetByName(ctx, "ACME")
+		require.NoError(t, err)
+		require.Equal(t, acme.ID, got.ID)
+		require.Equal(t, "ACME Corp", *got.DisplayName)
+
+		list, err := orgs.List(ctx, &database.OrgsListOptions{Query: "corp"})
+		require.NoError(t, err)
+		require.Equal(t, []int32{acme.ID}, orgIDs(list))
+		list, err = orgs.List(ctx, &database.OrgsListOptions{LimitOffset: &database.LimitOffset{Limit: 1, Offset: 1}})
+		require.NoError(t, err)
+		require.Equal(t, []int32{globex.ID}, orgIDs(list))
+		count, err := orgs.Count(ctx, database.OrgsListOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+
+		updated, err := orgs.Update(ctx, globex.ID, pointers.Ptr("Globex"))
+		require.NoError(t, err)
+		require.Equal(t, "Globex", *updated.DisplayName)
+		got, err = orgs.GetByID(ctx, globex.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Globex", *got.DisplayName)
+
+		require.NoError(t, orgs.Delete(ctx, acme.ID))
+		var notFound *database.OrgNotFoundError
+		_, err = orgs.GetByID(ctx, acme.ID)
+		require.True(t, errors.As(err, &notFound), "got %v", err)
+		_, err = orgs.GetByName(ctx, "acme")
+		require.True(t, errors.As(err, &notFound), "got %v", err)
+		require.True(t, errors.As(orgs.Delete(ctx, acme.ID), &notFound))
+
+		// Soft deleting releases the name.
+		_, err = orgs.Create(ctx, "acme", nil)
+		require.NoError(t, err)
+	})
+}
+
+func TestReposConformance(t *testing.T) {
+	conformance(t, func(t *testing.T, db database.DB) {
+		ctx := actor.WithInternalActor(context.Background())
+		repos := db.Repos()
+
+		foo := &types.Repo{Name: "github.com/sourcegraph/foo"}
+		fork := &types.Repo{Name: "github.com/sourcegraph/fork", Fork: true}
+		private := &types.Repo{Name: "github.com/sourcegraph/private", Private: true, Archived: true}
+		require.NoError(t, repos.Create(ctx, foo, fork, private))
+		require.NotZero(t, foo.ID)
+		require.Greater(t, private.ID, fork.ID)
+		require.Error(t, repos.Create(ctx, &types.Repo{Name: "github.com/sourcegraph/FOO"}))
+
+		got, err := repos.GetByName(ctx, "github.com/sourcegraph/Fork")
+		require.NoError(t, err)
+		require.Equal(t, fork.ID, got.ID)
+		require.True(t, got.Fork)
+		got, err = repos.Get(ctx, private.ID)
+		require.NoError(t, err)
+		require.Equal(t, private.Name, got.Name)
+		list, err := repos.GetByIDs(ctx)
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		for _, tc := range []struct {
+			name string
+			opts database.ReposListOptions
+			want []api.RepoID
+		}{
+			{"all", database.ReposListOptions{}, []api.RepoID{foo.ID, fork.ID, private.ID}},
+			{"query", database.ReposListOptions{Query: "FO"}, []api.RepoID{foo.ID, fork.ID}},
+			{"IDs", database.ReposListOptions{IDs: []api.RepoID{private.ID, foo.ID}}, []api.RepoID{foo.ID, private.ID}},
+			{"names", database.ReposListOptions{Names: []string{"github.com/sourcegraph/fork"}}, []api.RepoID{fork.ID}},
+			{"no forks", database.ReposListOptions{NoForks: true}, []api.RepoID{foo.ID, private.ID}},
+			{"only archived", database.ReposListOptions{OnlyArchived: true}, []api.RepoID{private.ID}},
+			{"no private", database.ReposListOptions{NoPrivate: true}, []api.RepoID{foo.ID, fork.ID}},
+			{"limit offset", database.ReposListOptions{LimitOffset: &database.LimitOffset{Limit: 2, Offset: 1}}, []api.RepoID{fork.ID, private.ID}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				list, err := repos.List(ctx, tc.opts)
+				require.NoError(t, err)
+				require.Equal(t, tc.want, repoIDs(list))
+			})
+		}
+		count, err := repos.Count(ctx, database.ReposListOptions{OnlyPrivate: true})
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		require.NoError(t, repos.Delete(ctx, foo.ID))
+		var notFound *database.RepoNotFoundErr
+		_, err = repos.Get(ctx, foo.ID)
+		require.True(t, errors.As(err, &notFound), "got %v", err)
+		_, err = repos.GetByName(ctx, foo.Name)
+		require.True(t, errors.As(err, &notFound), "got %v", err)
+		list, err = repos.List(ctx, database.ReposListOptions{IDs: []api.RepoID{foo.ID}, IncludeDeleted: true})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.True(t, strings.HasPrefix(string(list[0].Name), "DELETED-"), "got %s", list[0].Name)
+		require.False(t, list[0].DeletedAt.IsZero())
+
+		// Soft deleting renames the repo, so that its name can be reused.
+		require.NoError(t, repos.Create(ctx, &types.Repo{Name: foo.Name}))
+	})
+}
+
+func TestSettingsConformance(t *testing.T) {
+	conformance(t, func(t *testing.T, db database.DB) {
+		ctx := context.Background()
+		settings := db.Settings()
+
+		alice, err := db.Users().Create(ctx, database.NewUser{Username: "alice"})
+		require.NoError(t, err)
+		bob, err := db.Users().Create(ctx, database.NewUser{Username: "bob"})
+		require.NoError(t, err)
+		acme, err := db.Orgs().Create(ctx, "acme", nil)
+		require.NoError(t, err)
+		site := api.SettingsSubject{Site: true}
+
+		latest, err := settings.GetLatest(ctx, site)
+		require.NoError(t, err)
+		require.Nil(t, latest)
+
+		_, err = settings.CreateIfUpToDate(ctx, site, nil, &alice.ID, `{"experimentalFeatures": 1}`)
+		require.Error(t, err, "invalid settings")
+
+		first, err := settings.CreateIfUpToDate(ctx, site, nil, &alice.ID, `{"site": 1}`)
+		require.NoError(t, err)
+		// Without the ID of the latest settings, the settings are not updated.
+		latest, err = settings.CreateIfUpToDate(ctx, site, nil, &alice.ID, `{"site": 2}`)
+		require.NoError(t, err)
+		require.Equal(t, first.ID, latest.ID)
+		latest, err = settings.CreateIfUpToDate(ctx, site, &first.ID, &bob.ID, `{"site": 3}`)
+		require.NoError(t, err)
+		require.Greater(t, latest.ID, first.ID)
+
+		got, err := settings.GetLatest(ctx, site)
+		require.NoError(t, err)
+		require.Equal(t, `{"site": 3}`, got.Contents)
+		require.True(t, got.Subject.Site)
+		require.Equal(t, bob.ID, *got.AuthorUserID)
+
+		_, err = settings.CreateIfUpToDate(ctx, api.SettingsSubject{Org: &acme.ID}, nil, &alice.ID, `{"org": 1}`)
+		require.NoError(t, err)
+		_, err = settings.CreateIfUpToDate(ctx, api.SettingsSubject{User: &bob.ID}, nil, &bob.ID, `{"user": 1}`)
+		require.NoError(t, err)
+
+		all, err := settings.ListAll(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, []string{`{"org": 1}`, `{"user": 1}`, `{"site": 1}`, `{"site": 3}`}, settingsContents(all))
+		all, err = settings.ListAll(ctx, "site")
+		require.NoError(t, err)
+		require.Equal(t, []string{`{"site": 1}`, `{"site": 3}`}, settingsContents(all))
+
+		// The settings of deleted users are gone, and so are the authors.
+		require.NoError(t, db.Users().Delete(ctx, bob.ID))
+		got, err = settings.GetLatest(ctx, api.SettingsSubject{User: &bob.ID})
+		require.NoError(t, err)
+		require.Nil(t, got)
+		got, err = settings.GetLatest(ctx, site)
+		require.NoError(t, err)
+		require.Nil(t, got.AuthorUserID)
+	})
+}
+
+func userIDs(users []*types.User) (ids []int32) {
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func orgIDs(orgs []*types.Org) (ids []int32) {
+	for _, o := range orgs {
+		ids = append(ids, o.ID)
+	}
+	return ids
+}
+
+func repoIDs(repos []*types.Repo) (ids []api.RepoID) {
+	for _, r := range repos {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func settingsContents(settings []*api.Settings) (contents []string) {
+	for _, s := range settings {
+		contents = append(contents, s.Contents)
+	}
+	return contents
+}
@@ -1,10 +1,12 @@
 // Package fakedb contains in-memory, partial implementations of stores
 // from the database package. This set of fakes is meant to be extended
-// as needed.
+// as needed. Calling a method which is not implemented by a fake panics
+// with the name of the method.
 package fakedb
 
 import (
 	"context"
+	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
@@ -12,12 +14,20 @@ import (
 
 // New creates a set of fakes currently available to database stores.
 func New() Fakes {
+	// Users and orgs share a namespace, like in the database.
+	names := names{}
 	teams := &Teams{}
-	users := &Users{}
+	users := &Users{UserStore: dbmocks.NewStrictMockUserStore(), names: names}
 	teams.users = users
+	orgs := &Orgs{OrgStore: dbmocks.NewStrictMockOrgStore(), names: names}
+	repos := &Repos{RepoStore: dbmocks.NewStrictMockRepoStore()}
+	settings := &Settings{SettingsStore: dbmocks.NewStrictMockSettingsStore(), users: users, orgs: orgs}
 	return Fakes{
-		TeamStore: teams,
-		UserStore: users,
+		TeamStore:     teams,
+		UserStore:     users,
+		OrgStore:      orgs,
+		RepoStore:     repos,
+		SettingsStore: settings,
 	}
 }
 
@@ -26,15 +36,55 @@ func New() Fakes {
 // or data validation for white-box testing. The methods that correspond
 // to specific stores are implemented next to the specific fake store.
 type Fakes struct {
-	TeamStore *Teams
-	UserStore *Users
+	TeamStore     *Teams
+	UserStore     *Users
+	OrgStore      *Orgs
+	RepoStore     *Repos
+	SettingsStore *Settings
 }
 
 // Wire injects fakes into a database.MockDB.
 func (fs Fakes) Wire(db *dbmocks.MockDB) {
 	db.TeamsFunc.SetDefaultReturn(fs.TeamStore)
 	db.UsersFunc.SetDefaultReturn(fs.UserStore)
+	db.OrgsFunc.SetDefaultReturn(fs.OrgStore)
+	db.ReposFunc.SetDefaultReturn(fs.RepoStore)
+	db.SettingsFunc.SetDefaultReturn(fs.SettingsStore)
 	db.WithTransactFunc.SetDefaultHook(func(_ context.Context, callback func(database.DB) error) error {
 		return callback(db)
 	})
 }
+
+// names is the namespace of user and org names. Like in the database, names
+// are case-insensitive.
+type names map[string]struct{}
+
+// reserve reports whether name was available, and reserves it.
+func (ns names) reserve(name string) bool {
+	name = strings.ToLower(name)
+	if _, ok := ns[name]; ok {
+		return false
+	}
+	ns[name] = struct{}{}
+	return true
+}
+
+func (ns names) release(name string) {
+	delete(ns, strings.ToLower(name))
+}
+
+// limitOffset returns the page of list selected by lo, which may be nil. Like
+// in SQL, a zero limit selects nothing.
+func limitOffset[T any](list []T, lo *database.LimitOffset) []T {
+	if lo == nil {
+		return list
+	}
+	if lo.Offset >= len(list) {
+		return list[:0]
+	}
+	list = list[lo.Offset:]
+	if len(list) > lo.Limit {
+		list = list[:lo.Limit]
+	}
+	return list
+}
@@ -0,0 +1,135 @@
+package fakedb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Orgs partially implements database.OrgStore using in-memory storage.
+// Org names share a namespace with usernames, like in the database.
+type Orgs struct {
+	database.OrgStore
+	lastUsedID int32
+	list       []types.Org
+	deleted    map[int32]bool
+	names      names
+}
+
+// errOrgNameAlreadyExists has the message of the unexported error returned by
+// the database.
+var errOrgNameAlreadyExists = errors.New("organization name is already taken (by a user, team, or another organization)")
+
+func (orgs *Orgs) Create(_ context.Context, name string, displayName *string) (*types.Org, error) {
+	if !orgs.names.reserve(name) {
+		return nil, errOrgNameAlreadyExists
+	}
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	orgs.lastUsedID++
+	o := types.Org{
+		ID:          orgs.lastUsedID,
+		Name:        name,
+		DisplayName: displayName,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	orgs.list = append(orgs.list, o)
+	return &o, nil
+}
+
+func (orgs *Orgs) GetByID(_ context.Context, id int32) (*types.Org, error) {
+	for _, o := range orgs.list {
+		if o.ID == id && !orgs.deleted[o.ID] {
+			return &o, nil
+		}
+	}
+	return nil, &database.OrgNotFoundError{Message: fmt.Sprintf("id %d", id)}
+}
+
+func (orgs *Orgs) GetByName(_ context.Context, name string) (*types.Org, error) {
+	for _, o := range orgs.list {
+		if strings.EqualFold(o.Name, name) && !orgs.deleted[o.ID] {
+			return &o, nil
+		}
+	}
+	return nil, &database.OrgNotFoundError{Message: fmt.Sprintf("name %s", name)}
+}
+
+func (orgs *Orgs) List(_ context.Context, opts *database.OrgsListOptions) ([]*types.Org, error) {
+	if opts == nil {
+		opts = &database.OrgsListOptions{}
+	}
+	ret := []*types.Org{}
+	for _, o := range orgs.list {
+		o := o
+		if orgs.matches(o, *opts) {
+			ret = append(ret, &o)
+		}
+	}
+	return limitOffset(ret, opts.LimitOffset), nil
+}
+
+func (orgs *Orgs) Count(ctx context.Context, opts database.OrgsListOptions) (int, error) {
+	opts.LimitOffset = nil
+	selected, err := orgs.List(ctx, &opts)
+	return len(selected), err
+}
+
+// Update changes the display name of the org if it is given. The name of an
+// org cannot be changed.
+func (orgs *Orgs) Update(_ context.Context, id int32, displayName *string) (*types.Org, error) {
+	for i, o := range orgs.list {
+		if o.ID == id && !orgs.deleted[o.ID] {
+			if displayName != nil {
+				o.DisplayName = displayName
+			}
+			o.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+			orgs.list[i] = o
+			return &o, nil
+		}
+	}
+	return nil, &database.OrgNotFoundError{Message: fmt.Sprintf("id %d", id)}
+}
+
+// Delete soft-deletes the org, and releases its name.
+func (orgs *Orgs) Delete(_ context.Context, id int32) error {
+	for _, o := range orgs.list {
+		if o.ID == id && !orgs.deleted[o.ID] {
+			if orgs.deleted == nil {
+				orgs.deleted = map[int32]bool{}
+			}
+			orgs.deleted[o.ID] = true
+			orgs.names.release(o.Name)
+			return nil
+		}
+	}
+	return &database.OrgNotFoundError{Message: fmt.Sprintf("id %d", id)}
+}
+
+// exists reports whether the org exists, even if it is soft-deleted.
+func (orgs *Orgs) exists(id int32) bool {
+	return slices.ContainsFunc(orgs.list, func(o types.Org) bool { return o.ID == id })
+}
+
+func (orgs *Orgs) matches(o types.Org, opts database.OrgsListOptions) bool {
+	if orgs.deleted[o.ID] {
+		return false
+	}
+	if opts.Query != "" {
+		query := strings.ToLower(opts.Query)
+		var displayName string
+		if o.DisplayName != nil {
+			displayName = *o.DisplayName
+		}
+		if !strings.Contains(strings.ToLower(o.Name), query) && !strings.Contains(strings.ToLower(displayName), query) {
+			return false
+		}
+	}
+	return true
+}
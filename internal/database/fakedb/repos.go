@@ -0,0 +1,180 @@
+package fakedb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Repos partially implements database.RepoStore using in-memory storage.
+// Unlike the database, it does not enforce repository permissions, so it
+// behaves like the database does for internal actors.
+type Repos struct {
+	database.RepoStore
+	lastUsedID api.RepoID
+	list       []types.Repo
+}
+
+// AddRepo creates new repo in the fake repo storage.
+// This method is tailored for data setup in tests - it does not fail,
+// and conveniently returns ID of newly created repo.
+func (fs Fakes) AddRepo(r types.Repo) api.RepoID {
+	fs.RepoStore.lastUsedID++
+	r.ID = fs.RepoStore.lastUsedID
+	fs.RepoStore.list = append(fs.RepoStore.list, r)
+	return r.ID
+}
+
+// Create assigns IDs to repos, and stores them. Like in the database, repo
+// names are unique, and repos cannot be created blocked.
+func (repos *Repos) Create(_ context.Context, newRepos ...*types.Repo) error {
+	for _, r := range newRepos {
+		if slices.ContainsFunc(repos.list, func(existing types.Repo) bool {
+			return strings.EqualFold(string(existing.Name), string(r.Name))
+		}) {
+			return errors.Newf("insert: repo name %q already exists", r.Name)
+		}
+	}
+	for _, r := range newRepos {
+		repos.lastUsedID++
+		r.ID = repos.lastUsedID
+		stored := *r
+		stored.Blocked = nil
+		repos.list = append(repos.list, stored)
+	}
+	return nil
+}
+
+func (repos *Repos) Get(_ context.Context, id api.RepoID) (*types.Repo, error) {
+	for _, r := range repos.list {
+		if r.ID == id && r.DeletedAt.IsZero() {
+			return &r, r.IsBlocked()
+		}
+	}
+	return nil, &database.RepoNotFoundErr{ID: id}
+}
+
+func (repos *Repos) GetByName(_ context.Context, name api.RepoName) (*types.Repo, error) {
+	for _, r := range repos.list {
+		if strings.EqualFold(string(r.Name), string(name)) && r.DeletedAt.IsZero() {
+			return &r, r.IsBlocked()
+		}
+	}
+	return nil, &database.RepoNotFoundErr{Name: name}
+}
+
+func (repos *Repos) GetByIDs(ctx context.Context, ids ...api.RepoID) ([]*types.Repo, error) {
+	if len(ids) == 0 {
+		return []*types.Repo{}, nil
+	}
+	return repos.List(ctx, database.ReposListOptions{IDs: ids})
+}
+
+// List supports the options which filter repos by their ID, name or flags,
+// and the ordering by ID. It fails on other options.
+func (repos *Repos) List(_ context.Context, opts database.ReposListOptions) ([]*types.Repo, error) {
+	if err := checkReposListOptions(opts); err != nil {
+		return nil, err
+	}
+	ret := []*types.Repo{}
+	for _, r := range repos.list {
+		r := r
+		if repos.matches(r, opts) {
+			ret = append(ret, &r)
+		}
+	}
+	if len(opts.OrderBy) > 0 && opts.OrderBy[0].Descending {
+		slices.Reverse(ret)
+	}
+	return limitOffset(ret, opts.LimitOffset), nil
+}
+
+func (repos *Repos) Count(ctx context.Context, opts database.ReposListOptions) (int, error) {
+	opts.OrderBy = nil
+	opts.LimitOffset = nil
+	selected, err := repos.List(ctx, opts)
+	return len(selected), err
+}
+
+// Delete soft-deletes the repos. Like in the database, they are renamed so
+// that their names can be reused.
+func (repos *Repos) Delete(_ context.Context, ids ...api.RepoID) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	for i, r := range repos.list {
+		if !slices.Contains(ids, r.ID) {
+			continue
+		}
+		if !strings.HasPrefix(string(r.Name), "DELETED-") {
+			r.Name = api.RepoName(fmt.Sprintf("DELETED-%d-%s", now.Unix(), r.Name))
+		}
+		if r.DeletedAt.IsZero() {
+			r.DeletedAt = now
+		}
+		repos.list[i] = r
+	}
+	return nil
+}
+
+func (repos *Repos) matches(r types.Repo, opts database.ReposListOptions) bool {
+	if !r.DeletedAt.IsZero() && !opts.IncludeDeleted {
+		return false
+	}
+	if r.Blocked != nil && !opts.IncludeBlocked {
+		return false
+	}
+	if opts.Query != "" {
+		// Unlike the database, GraphQL IDs are not matched.
+		id, err := strconv.ParseInt(opts.Query, 10, 32)
+		if !strings.Contains(strings.ToLower(string(r.Name)), strings.ToLower(opts.Query)) && (err != nil || api.RepoID(id) != r.ID) {
+			return false
+		}
+	}
+	if len(opts.IDs) > 0 && !slices.Contains(opts.IDs, r.ID) {
+		return false
+	}
+	if len(opts.Names) > 0 && !slices.ContainsFunc(opts.Names, func(name string) bool {
+		return strings.EqualFold(name, string(r.Name))
+	}) {
+		return false
+	}
+	return !(opts.NoForks && r.Fork) && !(opts.OnlyForks && !r.Fork) &&
+		!(opts.NoArchived && r.Archived) && !(opts.OnlyArchived && !r.Archived) &&
+		!(opts.NoPrivate && r.Private) && !(opts.OnlyPrivate && !r.Private)
+}
+
+// checkReposListOptions fails on options which the fake does not support,
+// so that tests don't silently pass with results the database would filter.
+func checkReposListOptions(opts database.ReposListOptions) error {
+	if len(opts.OrderBy) > 1 || (len(opts.OrderBy) == 1 && opts.OrderBy[0].Field != database.RepoListID) {
+		return errors.Newf("fakedb: Repos.List only supports ordering by ID, got %+v", opts.OrderBy)
+	}
+	supported := database.ReposListOptions{
+		Query:          opts.Query,
+		Names:          opts.Names,
+		IDs:            opts.IDs,
+		NoForks:        opts.NoForks,
+		OnlyForks:      opts.OnlyForks,
+		NoArchived:     opts.NoArchived,
+		OnlyArchived:   opts.OnlyArchived,
+		NoPrivate:      opts.NoPrivate,
+		OnlyPrivate:    opts.OnlyPrivate,
+		OrderBy:        opts.OrderBy,
+		IncludeBlocked: opts.IncludeBlocked,
+		IncludeDeleted: opts.IncludeDeleted,
+		ExcludeSources: opts.ExcludeSources,
+		LimitOffset:    opts.LimitOffset,
+	}
+	if !reflect.DeepEqual(opts, supported) {
+		return errors.Newf("fakedb: Repos.List does not support the options %+v", opts)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package fakedb
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Settings partially implements database.SettingsStore using in-memory
+// storage. It keeps every version of the settings of a subject, like the
+// database.
+type Settings struct {
+	database.SettingsStore
+	lastUsedID int32
+	list       []api.Settings
+	users      *Users
+	orgs       *Orgs
+}
+
+func (settings *Settings) CreateIfUpToDate(ctx context.Context, subject api.SettingsSubject, lastID *int32, authorUserID *int32, contents string) (*api.Settings, error) {
+	if problems := conf.ValidateSettings(contents); len(problems) > 0 {
+		return nil, errors.Errorf("invalid settings: %s", strings.Join(problems, ","))
+	}
+	// The database has foreign keys to the subject and to the author.
+	if subject.Org != nil && !settings.orgs.exists(*subject.Org) {
+		return nil, errors.Newf("fakedb: settings of missing org %d", *subject.Org)
+	}
+	if subject.User != nil && !settings.users.exists(*subject.User) {
+		return nil, errors.Newf("fakedb: settings of missing user %d", *subject.User)
+	}
+	if authorUserID != nil && !settings.users.exists(*authorUserID) {
+		return nil, errors.Newf("fakedb: settings authored by missing user %d", *authorUserID)
+	}
+
+	latest, err := settings.GetLatest(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	creatorIsUpToDate := latest != nil && lastID != nil && latest.ID == *lastID
+	if latest != nil && !creatorIsUpToDate {
+		return latest, nil
+	}
+
+	settings.lastUsedID++
+	s := api.Settings{
+		ID:           settings.lastUsedID,
+		Subject:      api.SettingsSubject{Org: subject.Org, User: subject.User},
+		AuthorUserID: authorUserID,
+		Contents:     contents,
+		CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+	}
+	settings.list = append(settings.list, s)
+	s.Subject = subject
+	return &s, nil
+}
+
+// GetLatest returns the latest settings of subject, or nil if there are
+// none. Like in the database, the settings of deleted users are not returned.
+func (settings *Settings) GetLatest(ctx context.Context, subject api.SettingsSubject) (*api.Settings, error) {
+	if subject.User != nil {
+		if _, err := settings.users.GetByID(ctx, *subject.User); err != nil {
+			return nil, nil
+		}
+	}
+	for i := len(settings.list) - 1; i >= 0; i-- {
+		s := settings.list[i]
+		if equalPtr(s.Subject.Org, subject.Org) && equalPtr(s.Subject.User, subject.User) {
+			return settings.read(ctx, s), nil
+		}
+	}
+	return nil, nil
+}
+
+// ListAll returns the latest settings of every subject and author which
+// contain impreciseSubstring.
+func (settings *Settings) ListAll(ctx context.Context, impreciseSubstring string) ([]*api.Settings, error) {
+	type key struct{ org, user, author int32 }
+	latest := map[key]api.Settings{}
+	for _, s := range settings.list {
+		// The database deletes the settings of hard-deleted users.
+		if s.Subject.User != nil && !settings.users.exists(*s.Subject.User) {
+			continue
+		}
+		// Later versions replace earlier ones.
+		latest[key{deref(s.Subject.Org), deref(s.Subject.User), deref(s.AuthorUserID)}] = s
+	}
+
+	var selected []api.Settings
+	for _, s := range latest {
+		if strings.Contains(s.Contents, impreciseSubstring) {
+			selected = append(selected, s)
+		}
+	}
+	slices.SortFunc(selected, func(a, b api.Settings) int {
+		return cmp.Or(
+			compareNullsLast(a.Subject.Org, b.Subject.Org),
+			compareNullsLast(a.Subject.User, b.Subject.User),
+			compareNullsLast(a.AuthorUserID, b.AuthorUserID),
+		)
+	})
+	ret := []*api.Settings{}
+	for _, s := range selected {
+		ret = append(ret, settings.read(ctx, s))
+	}
+	return ret, nil
+}
+
+// read returns a copy of s as returned by the database.
+func (settings *Settings) read(ctx context.Context, s api.Settings) *api.Settings {
+	s.Subject.Site = s.Subject.Org == nil && s.Subject.User == nil
+	if s.AuthorUserID != nil {
+		if _, err := settings.users.GetByID(ctx, *s.AuthorUserID); err != nil {
+			s.AuthorUserID = nil
+		}
+	}
+	return &s
+}
+
+func equalPtr(a, b *int32) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// deref returns the value of p, or 0 which is never a valid ID.
+func deref(p *int32) int32 {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+// compareNullsLast orders like Postgres does by default, with NULL last.
+func compareNullsLast(a, b *int32) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	default:
+		return cmp.Compare(*a, *b)
+	}
+}
@@ -2,6 +2,10 @@ package fakedb
 
 import (
 	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -16,13 +20,10 @@ type Users struct {
 	database.UserStore
 	lastUserID int32
 	list       []types.User
+	deleted    map[int32]bool
+	names      names
 }
 
-type userNotFoundErr struct{}
-
-func (err userNotFoundErr) Error() string  { return "user not found" }
-func (err userNotFoundErr) NotFound() bool { return true }
-
 // AddUser creates new user in the fake user storage.
 // This method is tailored for data setup in tests - it does not fail,
 // and conveniently returns ID of newly created user.
@@ -31,25 +32,53 @@ func (fs Fakes) AddUser(u types.User) int32 {
 	fs.UserStore.lastUserID = id
 	u.ID = id
 	fs.UserStore.list = append(fs.UserStore.list, u)
+	fs.UserStore.names.reserve(u.Username)
 	return id
 }
 
+func (users *Users) Create(_ context.Context, info database.NewUser) (*types.User, error) {
+	if info.Email != "" {
+		// Emails are not faked yet.
+		panic("fakedb: Users.Create with an email is not implemented")
+	}
+	if !users.names.reserve(info.Username) {
+		return nil, database.MockCannotCreateUserUsernameExistsErr
+	}
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	users.lastUserID++
+	u := types.User{
+		ID:          users.lastUserID,
+		Username:    info.Username,
+		DisplayName: info.DisplayName,
+		AvatarURL:   info.AvatarURL,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		// Like in the database, the first user to be created is the site admin.
+		SiteAdmin:             users.lastUserID == 1,
+		BuiltinAuth:           info.Password != "",
+		InvalidatedSessionsAt: now,
+		TosAccepted:           info.TosAccepted,
+	}
+	users.list = append(users.list, u)
+	return &u, nil
+}
+
 func (users *Users) GetByID(_ context.Context, id int32) (*types.User, error) {
 	for _, u := range users.list {
-		if u.ID == id {
+		if u.ID == id && !users.deleted[u.ID] {
 			return &u, nil
 		}
 	}
-	return nil, userNotFoundErr{}
+	return nil, database.NewUserNotFoundErr("id", id)
 }
 
 func (users *Users) GetByUsername(_ context.Context, username string) (*types.User, error) {
 	for _, u := range users.list {
-		if u.Username == username {
+		if strings.EqualFold(u.Username, username) && !users.deleted[u.ID] {
 			return &u, nil
 		}
 	}
-	return nil, userNotFoundErr{}
+	return nil, database.NewUserNotFoundErr("username", username)
 }
 
 func (users *Users) GetByCurrentAuthUser(ctx context.Context) (*types.User, error) {
@@ -61,21 +90,102 @@ func (users *Users) GetByCurrentAuthUser(ctx context.Context) (*types.User, erro
 }
 
 func (users *Users) List(_ context.Context, opts *database.UsersListOptions) ([]*types.User, error) {
-	if len(opts.UserIDs) == 0 {
-		return nil, errors.New("not implemented")
+	if opts == nil {
+		opts = &database.UsersListOptions{}
+	}
+	if err := checkUsersListOptions(*opts); err != nil {
+		return nil, err
 	}
 	ret := []*types.User{}
-	for _, wantID := range opts.UserIDs {
-		for _, u := range users.list {
-			u := u
-			if u.ID == wantID {
-				ret = append(ret, &u)
+	for _, u := range users.list {
+		u := u
+		if users.matches(u, *opts) {
+			ret = append(ret, &u)
+		}
+	}
+	return limitOffset(ret, opts.LimitOffset), nil
+}
+
+func (users *Users) Count(ctx context.Context, opts *database.UsersListOptions) (int, error) {
+	var unlimited database.UsersListOptions
+	if opts != nil {
+		unlimited = *opts
+	}
+	unlimited.LimitOffset = nil
+	selected, err := users.List(ctx, &unlimited)
+	return len(selected), err
+}
+
+// Delete soft-deletes the user, and releases the username.
+func (users *Users) Delete(_ context.Context, id int32) error {
+	for _, u := range users.list {
+		if u.ID == id && !users.deleted[u.ID] {
+			if users.deleted == nil {
+				users.deleted = map[int32]bool{}
 			}
+			users.deleted[u.ID] = true
+			users.names.release(u.Username)
+			return nil
 		}
 	}
-	return ret, nil
+	return database.NewUserNotFoundErr("Some users were not found. Expected to delete 1 users, but deleted only 0")
+}
+
+// HardDelete removes the user, which may have been soft-deleted before.
+func (users *Users) HardDelete(_ context.Context, id int32) error {
+	for i, u := range users.list {
+		if u.ID == id {
+			if !users.deleted[u.ID] {
+				users.names.release(u.Username)
+			}
+			delete(users.deleted, u.ID)
+			users.list = append(users.list[:i], users.list[i+1:]...)
+			return nil
+		}
+	}
+	return database.NewUserNotFoundErr("Some users were not found. Expected to hard delete 1 users, but deleted only 0")
 }
 
 func (users *Users) GetByVerifiedEmail(_ context.Context, _ string) (*types.User, error) {
 	return nil, nil
 }
+
+// exists reports whether the user exists, even if it is soft-deleted.
+func (users *Users) exists(id int32) bool {
+	return slices.ContainsFunc(users.list, func(u types.User) bool { return u.ID == id })
+}
+
+func (users *Users) matches(u types.User, opts database.UsersListOptions) bool {
+	if users.deleted[u.ID] {
+		return false
+	}
+	if opts.Query != "" {
+		query := strings.ToLower(opts.Query)
+		// Unlike the database, GraphQL IDs are not matched.
+		id, err := strconv.ParseInt(opts.Query, 10, 32)
+		if !strings.Contains(strings.ToLower(u.Username), query) &&
+			!strings.Contains(strings.ToLower(u.DisplayName), query) &&
+			(err != nil || int32(id) != u.ID) {
+			return false
+		}
+	}
+	// A non-nil but empty list of IDs matches no users.
+	if opts.UserIDs != nil && !slices.Contains(opts.UserIDs, u.ID) {
+		return false
+	}
+	if len(opts.Usernames) > 0 && !slices.ContainsFunc(opts.Usernames, func(username string) bool {
+		return strings.EqualFold(username, u.Username)
+	}) {
+		return false
+	}
+	return true
+}
+
+// checkUsersListOptions fails on options which the fake does not support,
+// so that tests don't silently pass with results the database would filter.
+func checkUsersListOptions(opts database.UsersListOptions) error {
+	if opts.OrgID != 0 || !opts.InactiveSince.IsZero() || opts.ExcludeSourcegraphAdmins || opts.ExcludeSourcegraphOperators {
+		return errors.Newf("fakedb: Users.List does not support the options %+v", opts)
+	}
+	return nil
+}
@@ -3,7 +3,6 @@ package container
 import (
 	"sort"
 
-	"github.com/grafana/regexp"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -11,8 +10,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
-var imageRegexp = regexp.MustCompile(`(.+)/([^:]+):(.+)`)
-
 // NewContainer creates a new k8s Container with some default values set.
 func NewContainer(name string, cfg config.StandardComponent, defaults config.ContainerConfig) corev1.Container {
 	ctr := corev1.Container{
@@ -39,9 +36,7 @@ func NewContainer(name string, cfg config.StandardComponent, defaults config.Con
 				ctr.Resources = *ctrConfig.Resources
 			}
 
-			if ctrConfig.Image != "" {
-				ctr.Image = imageRegexp.ReplaceAllString(ctr.Image, "$1/"+ctrConfig.Image)
-			}
+			ctr.Image = ctrConfig.ImageFor(ctr.Image)
 		}
 	}
 
@@ -94,6 +89,20 @@ func NewEnvVarSecretKeyRef(name, secretName, secretKey string) corev1.EnvVar {
 	}
 }
 
+func NewEnvVarConfigMapKeyRef(name, configMapName, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: configMapName,
+				},
+				Key: key,
+			},
+		},
+	}
+}
+
 func NewEnvVarFieldRef(name, fieldPath string) corev1.EnvVar {
 	return corev1.EnvVar{
 		Name: name,
@@ -105,10 +114,10 @@ func NewEnvVarFieldRef(name, fieldPath string) corev1.EnvVar {
 	}
 }
 
-func EnvVarsRedis() []corev1.EnvVar {
+func EnvVarsRedis(cacheSecretName, storeSecretName string) []corev1.EnvVar {
 	return []corev1.EnvVar{
-		NewEnvVarSecretKeyRef("REDIS_CACHE_ENDPOINT", "redis-cache", "endpoint"),
-		NewEnvVarSecretKeyRef("REDIS_STORE_ENDPOINT", "redis-store", "endpoint"),
+		NewEnvVarSecretKeyRef("REDIS_CACHE_ENDPOINT", cacheSecretName, "endpoint"),
+		NewEnvVarSecretKeyRef("REDIS_STORE_ENDPOINT", storeSecretName, "endpoint"),
 	}
 }
 
@@ -134,6 +143,19 @@ func EnvVarsPostgres(secretName string) []corev1.EnvVar {
 	}
 }
 
+// EnvVarsPostgresClient returns the env vars that Sourcegraph services use to
+// connect to a Postgres database, e.g. PGHOST. The prefix is prepended to each
+// variable name, e.g. "CODEINTEL_".
+func EnvVarsPostgresClient(prefix, secretName string) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		NewEnvVarSecretKeyRef(prefix+"PGDATABASE", secretName, "database"),
+		NewEnvVarSecretKeyRef(prefix+"PGHOST", secretName, "host"),
+		NewEnvVarSecretKeyRef(prefix+"PGPASSWORD", secretName, "password"),
+		NewEnvVarSecretKeyRef(prefix+"PGPORT", secretName, "port"),
+		NewEnvVarSecretKeyRef(prefix+"PGUSER", secretName, "user"),
+	}
+}
+
 func EnvVarsPostgresExporter(secretName string) []corev1.EnvVar {
 	return []corev1.EnvVar{
 		NewEnvVarSecretKeyRef("DATA_SOURCE_DB", secretName, "database"),
@@ -147,6 +169,20 @@ func EnvVarsPostgresExporter(secretName string) []corev1.EnvVar {
 	}
 }
 
+// EnvVarsFromConfig returns the env vars set by a service's Env and EnvFrom
+// config, in that order.
+func EnvVarsFromConfig(cfg config.StandardComponent) []corev1.EnvVar {
+	envVars := newSortedEnvVars(cfg.GetEnv())
+	for _, ref := range cfg.GetEnvFrom() {
+		if ref.SecretName != "" {
+			envVars = append(envVars, NewEnvVarSecretKeyRef(ref.Name, ref.SecretName, ref.Key))
+		} else {
+			envVars = append(envVars, NewEnvVarConfigMapKeyRef(ref.Name, ref.ConfigMapName, ref.Key))
+		}
+	}
+	return envVars
+}
+
 func newSortedEnvVars(vars map[string]string) []corev1.EnvVar {
 	keys := make([]string, len(vars))
 	i := 0
@@ -0,0 +1,29 @@
+package cronjob
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// NewCronJob creates a new k8s CronJob with some default values set.
+func NewCronJob(name, namespace, version string) batchv1.CronJob {
+	return batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/component": name,
+				"app.kubernetes.io/name":      "sourcegraph",
+				"app.kubernetes.io/version":   version,
+				"deploy":                      "sourcegraph",
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: pointers.Ptr[int32](3),
+			FailedJobsHistoryLimit:     pointers.Ptr[int32](3),
+		},
+	}
+}
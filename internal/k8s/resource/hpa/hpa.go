@@ -0,0 +1,28 @@
+package hpa
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewHorizontalPodAutoscaler creates a new k8s HorizontalPodAutoscaler with
+// some default values set. It scales the apps/v1 object of the given kind, e.g.
+// Deployment, with the same name.
+func NewHorizontalPodAutoscaler(name, namespace, kind string) autoscalingv2.HorizontalPodAutoscaler {
+	return autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"deploy": "sourcegraph",
+			},
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       kind,
+				Name:       name,
+			},
+		},
+	}
+}
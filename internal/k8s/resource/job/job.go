@@ -0,0 +1,28 @@
+package job
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// NewJob creates a new k8s Job with some default values set. It isn't retried
+// on failure.
+func NewJob(name, namespace, version string) batchv1.Job {
+	return batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/component": name,
+				"app.kubernetes.io/name":      "sourcegraph",
+				"app.kubernetes.io/version":   version,
+				"deploy":                      "sourcegraph",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointers.Ptr[int32](0),
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package priorityclass
+
+import (
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewPriorityClass creates a new k8s PriorityClass with some default values
+// set. PriorityClasses are cluster-scoped, so they are labeled with the
+// namespace of the deployment they belong to.
+func NewPriorityClass(name, namespace string, value int32) schedulingv1.PriorityClass {
+	return schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"for-namespace": namespace,
+				"deploy":        "sourcegraph",
+			},
+		},
+		Value: value,
+	}
+}
@@ -1,6 +1,8 @@
 package serviceaccount
 
 import (
+	"maps"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -21,7 +23,14 @@ func NewServiceAccount(name, namespace string, cfg config.StandardComponent) cor
 	}
 
 	if cfg != nil {
-		sa.SetAnnotations(cfg.GetServiceAccountAnnotations())
+		annotations := maps.Clone(cfg.GetServiceAccountAnnotations())
+		if saAnnotations := cfg.GetServiceAccountConfig().Annotations; len(saAnnotations) > 0 {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			maps.Copy(annotations, saAnnotations)
+		}
+		sa.SetAnnotations(annotations)
 	}
 
 	return sa
@@ -2,6 +2,7 @@ package observation
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -59,6 +60,65 @@ func TestContextTB(t testing.TB) *Context {
 	}
 }
 
+// CapturedContext is a Context created by TestContextCaptured, which captures
+// the logs of the operations observed with it, including the errors of failed
+// operations.
+type CapturedContext struct {
+	*Context
+	exportLogs func() logtest.CapturedLogs
+}
+
+// CaptureOption configures TestContextCaptured.
+type CaptureOption func(t testing.TB, c *CapturedContext)
+
+// FailOnErrorLogs fails the test when it finishes if any error was logged.
+func FailOnErrorLogs() CaptureOption {
+	return func(t testing.TB, c *CapturedContext) {
+		t.Cleanup(func() { c.RequireNoErrors(t) })
+	}
+}
+
+// TestContextCaptured creates a Context like TestContextTB, whose logger also
+// captures the logs, so that a test can assert on them.
+func TestContextCaptured(t testing.TB, opts ...CaptureOption) *CapturedContext {
+	logger, exportLogs := logtest.Captured(t)
+	c := &CapturedContext{
+		Context:    ContextWithLogger(logger, TestContextTB(t)),
+		exportLogs: exportLogs,
+	}
+	for _, opt := range opts {
+		opt(t, c)
+	}
+	return c
+}
+
+// Logs returns the logs captured so far.
+func (c *CapturedContext) Logs() logtest.CapturedLogs {
+	return c.exportLogs()
+}
+
+// RequireNoErrors fails t if any error was logged so far.
+func (c *CapturedContext) RequireNoErrors(t testing.TB) {
+	t.Helper()
+
+	for _, l := range c.Logs().Filter(func(l logtest.CapturedLog) bool { return l.Level == log.LevelError }) {
+		t.Errorf("unexpected error log from %s: %s %v", l.Scope, l.Message, l.Fields)
+	}
+}
+
+// RequireLogged fails t unless a message containing substring was logged at
+// level so far.
+func (c *CapturedContext) RequireLogged(t testing.TB, level log.Level, substring string) {
+	t.Helper()
+
+	logged := c.Logs().Contains(func(l logtest.CapturedLog) bool {
+		return l.Level == level && strings.Contains(l.Message, substring)
+	})
+	if !logged {
+		t.Fatalf("expected a %s log containing %q, got %q", level, substring, c.Logs().Messages())
+	}
+}
+
 // ContextWithLogger creates a live Context with the given logger instance.
 func ContextWithLogger(logger log.Logger, parent *Context) *Context {
 	return &Context{
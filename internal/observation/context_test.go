@@ -0,0 +1,42 @@
+package observation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sourcegraph/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestCapturedContext(t *testing.T) {
+	c := TestContextCaptured(t)
+	c.RequireNoErrors(t)
+
+	op := c.Operation(Op{Name: "test.operation"})
+	_, _, endObservation := op.With(context.Background(), pointer(errors.New("boom")), Args{})
+	endObservation(1, Args{})
+
+	c.RequireLogged(t, log.LevelError, "operation.error")
+
+	rt := &recordingT{TB: t}
+	c.RequireNoErrors(rt)
+	require.Len(t, rt.errors, 1)
+	require.Contains(t, rt.errors[0], "boom")
+}
+
+func pointer[T any](v T) *T { return &v }
+
+// recordingT records the failures of a test instead of failing it.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ResolveRepositoryRevisions returns the revisions a search job searches in
+// the repository of repoRevSpec. Revisions which resolved to the same commit as
+// an earlier one are dropped.
+//
+// The repo worker creates one task per returned revision and
+// EstimateSearchJob counts them, so both have to use this.
+func ResolveRepositoryRevisions(ctx context.Context, q SearchQuery, repoRevSpec types.RepositoryRevSpecs) ([]types.RepositoryRevision, error) {
+	repoRevs, err := q.ResolveRepositoryRevSpec(ctx, repoRevSpec)
+	if err != nil {
+		return nil, err
+	}
+	return dedupeRepoRevisions(repoRevs), nil
+}
+
+// dedupeRepoRevisions drops the revisions which resolved to the same commit as
+// an earlier one, for example a branch and the commit it points to. The first
+// revision is kept, so its spec is the one shown to the user. Revisions which
+// could not be resolved are only deduplicated by name.
+func dedupeRepoRevisions(repoRevs []types.RepositoryRevision) []types.RepositoryRevision {
+	seenCommits := make(map[api.CommitID]struct{}, len(repoRevs))
+	seenRevisions := make(map[string]struct{}, len(repoRevs))
+	var deduped []types.RepositoryRevision
+	for _, repoRev := range repoRevs {
+		if repoRev.CommitID != "" {
+			if _, ok := seenCommits[repoRev.CommitID]; ok {
+				continue
+			}
+			seenCommits[repoRev.CommitID] = struct{}{}
+		} else {
+			if _, ok := seenRevisions[repoRev.Revision]; ok {
+				continue
+			}
+			seenRevisions[repoRev.Revision] = struct{}{}
+		}
+		deduped = append(deduped, repoRev)
+	}
+	return deduped
+}
+
+// SampleRepositoryRevisions returns the revisions of repoRevs which a search
+// job with the SampleRate rate and the SampleSeed seed searches. Each revision
+// is kept with probability rate, decided by a PRNG seeded with seed, the
+// repository and the revision. So the choice doesn't depend on the order in
+// which the workers expand the repositories, and jobs with the same seed
+// search the same revisions. A rate of 0 keeps every revision.
+func SampleRepositoryRevisions(repoRevs []types.RepositoryRevision, rate float64, seed int64) []types.RepositoryRevision {
+	if rate <= 0 || rate >= 1 {
+		return repoRevs
+	}
+	var sampled []types.RepositoryRevision
+	for _, repoRev := range repoRevs {
+		h := fnv.New64a()
+		fmt.Fprintf(h, "%d@%s", repoRev.Repository, repoRev.Revision)
+		if rand.New(rand.NewPCG(uint64(seed), h.Sum64())).Float64() < rate {
+			sampled = append(sampled, repoRev)
+		}
+	}
+	return sampled
+}
+
+// EstimateTimeout is how long EstimateSearchJob resolves repositories and
+// revisions before it returns a partial estimate.
+var EstimateTimeout = 30 * time.Second
+
+// EstimateSize buckets the number of revisions a search job searches.
+type EstimateSize string
+
+const (
+	EstimateSizeSmall  EstimateSize = "small"
+	EstimateSizeMedium EstimateSize = "medium"
+	EstimateSizeLarge  EstimateSize = "large"
+	EstimateSizeHuge   EstimateSize = "huge"
+)
+
+// estimateSize returns the bucket of revisionCount.
+func estimateSize(revisionCount int) EstimateSize {
+	switch {
+	case revisionCount < 100:
+		return EstimateSizeSmall
+	case revisionCount < 1_000:
+		return EstimateSizeMedium
+	case revisionCount < 10_000:
+		return EstimateSizeLarge
+	default:
+		return EstimateSizeHuge
+	}
+}
+
+// SearchJobEstimate is the size of the search job a query would create.
+type SearchJobEstimate struct {
+	// RepoCount is the number of repositories the job would search.
+	RepoCount int
+
+	// RevisionCount is the number of revisions the job would search. Every
+	// revision is a task of the job.
+	RevisionCount int
+
+	// Size is the bucket of RevisionCount.
+	Size EstimateSize
+
+	// Partial is true if resolving the repositories and revisions took longer
+	// than EstimateTimeout. The counts are a lower bound then.
+	Partial bool
+}
+
+// EstimateSearchJob resolves the repositories and revisions a search job for
+// query would search, like the workers do once the job is created, and returns
+// how many there are. Nothing is persisted.
+//
+// The repositories and revisions may change until a job is created, so the
+// estimate may differ from the tasks of the job.
+func (s *Service) EstimateSearchJob(ctx context.Context, query string) (_ *SearchJobEstimate, err error) {
+	ctx, _, endObservation := s.operations.estimateSearchJob.With(ctx, &err, opAttrs(
+		attribute.String("query", query),
+	))
+	defer endObservation(1, observation.Args{})
+
+	actor := actor.FromContext(ctx)
+	if !actor.IsAuthenticated() {
+		return nil, errors.New("search jobs can only be estimated by an authenticated user")
+	}
+	if err := validateQuery(query); err != nil {
+		return nil, err
+	}
+
+	q, err := s.newSearcher.NewSearch(ctx, actor.UID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	estimateCtx, cancel := context.WithTimeout(ctx, EstimateTimeout)
+	defer cancel()
+
+	var estimate SearchJobEstimate
+	err = func() error {
+		it := q.RepositoryRevSpecs(estimateCtx)
+		for it.Next() {
+			repoRevs, err := ResolveRepositoryRevisions(estimateCtx, q, it.Current())
+			if err != nil {
+				return err
+			}
+			estimate.RepoCount++
+			estimate.RevisionCount += len(repoRevs)
+		}
+		return it.Err()
+	}()
+	// Only our own timeout makes the estimate partial. If the caller gave up,
+	// we return the error.
+	if err != nil && errors.Is(estimateCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		estimate.Partial = true
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	estimate.Size = estimateSize(estimate.RevisionCount)
+	return &estimate, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+func TestEstimateSearchJob(t *testing.T) {
+	// The service has no store, so it panics if it tries to persist anything.
+	svc := New(observation.TestContextTB(t), nil, nil, NewSearcherFake())
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+
+	// rev1 and refs/heads/rev1 resolve to the same commit, so they are one
+	// task.
+	estimate, err := svc.EstimateSearchJob(ctx, "1@rev1 1@refs/heads/rev1 1@rev2 2@rev3")
+	require.NoError(t, err)
+	require.Equal(t, &SearchJobEstimate{RepoCount: 2, RevisionCount: 3, Size: EstimateSizeSmall}, estimate)
+
+	_, err = svc.EstimateSearchJob(context.Background(), "1@rev1")
+	require.Error(t, err)
+
+	var qErr *QueryError
+	_, err = svc.EstimateSearchJob(ctx, "1@rev1 select:repo")
+	require.ErrorAs(t, err, &qErr)
+}
+
+func TestEstimateSearchJob_Timeout(t *testing.T) {
+	old := EstimateTimeout
+	EstimateTimeout = 10 * time.Millisecond
+	t.Cleanup(func() { EstimateTimeout = old })
+
+	// Resolving the revisions of repository 2 never finishes.
+	newSearcher := newSearcherFunc(func(ctx context.Context, userID int32, q string) (SearchQuery, error) {
+		sq, err := fakeNewSearch(ctx, userID, q)
+		if err != nil {
+			return nil, err
+		}
+		return blockingSearchQuery{SearchQuery: sq, repo: 2}, nil
+	})
+	svc := New(observation.TestContextTB(t), nil, nil, newSearcher)
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+
+	estimate, err := svc.EstimateSearchJob(ctx, "1@rev1 1@rev2 2@rev3")
+	require.NoError(t, err)
+	require.Equal(t, &SearchJobEstimate{RepoCount: 1, RevisionCount: 2, Size: EstimateSizeSmall, Partial: true}, estimate)
+
+	// If the caller gives up, there is no estimate.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = svc.EstimateSearchJob(canceledCtx, "1@rev1 1@rev2 2@rev3")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestEstimateSize(t *testing.T) {
+	for count, want := range map[int]EstimateSize{
+		0:      EstimateSizeSmall,
+		99:     EstimateSizeSmall,
+		100:    EstimateSizeMedium,
+		999:    EstimateSizeMedium,
+		1_000:  EstimateSizeLarge,
+		10_000: EstimateSizeHuge,
+	} {
+		require.Equal(t, want, estimateSize(count), "count %d", count)
+	}
+}
+
+func TestSampleRepositoryRevisions(t *testing.T) {
+	var repoRevs []types.RepositoryRevision
+	for repo := range 100 {
+		for rev := range 100 {
+			repoRevs = append(repoRevs, types.RepositoryRevision{
+				RepositoryRevSpecs: types.RepositoryRevSpecs{Repository: api.RepoID(repo + 1)},
+				Revision:           fmt.Sprintf("rev%d", rev),
+			})
+		}
+	}
+
+	// Two expansions with the same seed search the same revisions, whatever
+	// the order in which the repositories are expanded.
+	sampled := SampleRepositoryRevisions(repoRevs, 0.1, 42)
+	require.Equal(t, sampled, SampleRepositoryRevisions(repoRevs, 0.1, 42))
+	reversed := slices.Clone(repoRevs)
+	slices.Reverse(reversed)
+	resampled := SampleRepositoryRevisions(reversed, 0.1, 42)
+	slices.Reverse(resampled)
+	require.Equal(t, sampled, resampled)
+	require.NotEqual(t, sampled, SampleRepositoryRevisions(repoRevs, 0.1, 43))
+
+	// The number of sampled revisions is roughly proportional to the rate.
+	// The bounds are more than 5 standard deviations away from the mean.
+	for _, rate := range []float64{0.01, 0.1, 0.5} {
+		want := rate * float64(len(repoRevs))
+		got := float64(len(SampleRepositoryRevisions(repoRevs, rate, 42)))
+		require.InDelta(t, want, got, 5*math.Sqrt(want*(1-rate))+1, "rate %v", rate)
+	}
+
+	// A rate of 0 keeps every revision.
+	require.Equal(t, repoRevs, SampleRepositoryRevisions(repoRevs, 0, 42))
+}
+
+// blockingSearchQuery blocks resolving the revisions of repo until ctx is done.
+type blockingSearchQuery struct {
+	SearchQuery
+	repo api.RepoID
+}
+
+func (q blockingSearchQuery) ResolveRepositoryRevSpec(ctx context.Context, repoRevSpec types.RepositoryRevSpecs) ([]types.RepositoryRevision, error) {
+	if repoRevSpec.Repository == q.repo {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return q.SearchQuery.ResolveRepositoryRevSpec(ctx, repoRevSpec)
+}
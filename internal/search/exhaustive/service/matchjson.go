@@ -2,38 +2,128 @@ package service
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/uploadstore"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
+const (
+	// resultsBufferSize is how many bytes of matches a MatchJSONWriter
+	// buffers before it compresses them and streams them to the upload
+	// store. Together with the part buffers of the upload store, it bounds
+	// the memory used for the results of a search, however many there are.
+	resultsBufferSize = 4 * 1024 * 1024 // 4 MiB
+
+	// maxShardSize is how many bytes of matches are written to a blob before
+	// a MatchJSONWriter starts the next shard.
+	maxShardSize = 100 * 1024 * 1024 // 100 MiB
+)
+
+// ResultsKeyPrefix returns the prefix that the repo revision job taskID of the
+// search job jobID passes to NewJSONWriter. A batch writes its results with
+// the ID of the first task of each chunk.
+//
+// The keys aren't stored on the repo revision jobs. Instead, groupResultKeys
+// derives the task of each blob from its key, which is safe because:
+//
+//   - only the workers write blobs under the prefix of a search job, with this
+//     prefix, and the other keys of a job, such as its summary, don't start
+//     with a task ID
+//   - task IDs are never reused, and the shard number follows a "-", so the
+//     keys of task 1 never look like those of task 12
+//   - a retried task deletes the shards of its earlier attempts before it
+//     writes any, see NewJSONWriter
+//
+// Always build the prefix with this function, so that it can't drift from
+// what groupResultKeys parses.
+func ResultsKeyPrefix(jobID, taskID int64) string {
+	return getPrefix(jobID) + strconv.FormatInt(taskID, 10)
+}
+
 // NewJSONWriter creates a MatchJSONWriter which appends matches to a JSON array
-// and uploads them to the object store once the internal buffer size has
-// reached 100 MiB or Flush() is called. The object key combines a prefix with
+// and streams them to the object store. The object key combines a prefix with
 // the shard number, except for the first shard where the shard number is
-// omitted.
+// omitted. A new shard is started once 100 MiB were written to the current
+// one. Blobs are gzip compressed, which is recorded by the suffix
+// gzipKeySuffix of their key.
+//
+// The upload of a shard only completes when the next shard is started or
+// Flush is called. Abort discards the results written so far, so that a
+// failed search doesn't leave partial results behind.
+//
+// Any shards left behind by a previous writer with the same prefix are deleted
+// first, so that a retried task replaces its earlier results instead of adding
+// to them.
 func NewJSONWriter(ctx context.Context, store uploadstore.Store, prefix string) (*MatchJSONWriter, error) {
+	return newJSONWriter(ctx, store, prefix, resultsBufferSize, maxShardSize)
+}
+
+func newJSONWriter(ctx context.Context, store uploadstore.Store, prefix string, bufferSize, shardSize int) (*MatchJSONWriter, error) {
+	if err := deleteShards(ctx, store, prefix); err != nil {
+		return nil, err
+	}
+
 	blobUploader := &blobUploader{
-		ctx:    ctx,
-		store:  store,
-		prefix: prefix,
-		shard:  1,
+		ctx:          ctx,
+		store:        store,
+		prefix:       prefix,
+		maxShardSize: shardSize,
 	}
 
 	return &MatchJSONWriter{
-		w: newBufferedWriter(1024*1024*100, blobUploader.write)}, nil
+		w:        newBufferedWriter(bufferSize, blobUploader.write),
+		uploader: blobUploader,
+	}, nil
 }
 
 type MatchJSONWriter struct {
 	w *bufferedWriter
+
+	// uploader is nil if the matches aren't uploaded, for example in tests.
+	uploader *blobUploader
 }
 
+// Flush writes the buffered matches and completes the upload of the current
+// shard. The writer must not be used afterwards.
 func (m MatchJSONWriter) Flush() error {
-	return m.w.Flush()
+	if err := m.w.Flush(); err != nil {
+		if m.uploader != nil {
+			err = errors.Append(err, m.uploader.abort(err))
+		}
+		return err
+	}
+	if m.uploader == nil {
+		return nil
+	}
+	return m.uploader.finish()
+}
+
+// BytesWritten returns the compressed size of the shards which were uploaded
+// and kept by Flush. It is 0 before Flush returned and if the upload failed or
+// was aborted, so it only counts results which are stored.
+func (m MatchJSONWriter) BytesWritten() int64 {
+	if m.uploader == nil {
+		return 0
+	}
+	return m.uploader.keptBytes
+}
+
+// Abort stops the upload of the current shard and deletes the shards which
+// were already uploaded. It does nothing after Flush.
+func (m MatchJSONWriter) Abort() error {
+	if m.uploader == nil {
+		return nil
+	}
+	return m.uploader.abort(errUploadAborted)
 }
 
 func (m MatchJSONWriter) Write(match result.Match) error {
@@ -45,31 +135,199 @@ func (m MatchJSONWriter) Write(match result.Match) error {
 	return m.w.Append(eventMatch)
 }
 
+// errUploadAborted fails the upload of a shard which is discarded.
+var errUploadAborted = errors.New("upload aborted")
+
+// blobUploader streams what it is given to the current shard in the upload
+// store. The store reads it with its own bounded buffers, for example as the
+// parts of a multipart upload, so a shard is never held in memory as a whole.
 type blobUploader struct {
-	ctx    context.Context
-	store  uploadstore.Store
-	prefix string
-	shard  int
+	ctx          context.Context
+	store        uploadstore.Store
+	prefix       string
+	maxShardSize int
+
+	// shard is the number of the current shard. It is 0 before the first
+	// write.
+	shard int
+
+	// shardSize is the number of uncompressed bytes written to the current
+	// shard.
+	shardSize int
+
+	// pw and zw write to the current shard and done receives the result of
+	// its upload. They are nil if there is no upload in progress. wc counts
+	// the compressed bytes written to pw.
+	pw   *io.PipeWriter
+	zw   *gzip.Writer
+	wc   *writeCounter
+	done chan error
+
+	// uploaded are the keys of the shards which were uploaded completely and
+	// uploadedBytes is their compressed size.
+	uploaded      []string
+	uploadedBytes int64
+
+	// keptBytes is the compressed size of the shards kept by finish.
+	keptBytes int64
 }
 
-func (b *blobUploader) write(p []byte) error {
-	key := ""
+// gzipKeySuffix is appended to the key of blobs which are gzip compressed.
+// Blobs written before we compressed them don't have it.
+const gzipKeySuffix = ".gz"
+
+func (b *blobUploader) key() string {
 	if b.shard == 1 {
-		key = b.prefix
-	} else {
-		key = fmt.Sprintf("%s-%d", b.prefix, b.shard)
+		return b.prefix + gzipKeySuffix
 	}
+	return fmt.Sprintf("%s-%d%s", b.prefix, b.shard, gzipKeySuffix)
+}
 
-	_, err := b.store.Upload(b.ctx, key, bytes.NewBuffer(p))
+func (b *blobUploader) write(p []byte) error {
+	if b.pw != nil && b.shardSize >= b.maxShardSize {
+		if err := b.finishShard(); err != nil {
+			return err
+		}
+	}
+	if b.pw == nil {
+		if err := b.startShard(); err != nil {
+			return err
+		}
+	}
+
+	// This blocks until the store read what we wrote. If the upload failed,
+	// the pipe returns its error.
+	if _, err := b.zw.Write(p); err != nil {
+		return errors.Append(err, b.abort(err))
+	}
+	b.shardSize += len(p)
+
+	return nil
+}
+
+// startShard starts the upload of the next shard.
+func (b *blobUploader) startShard() error {
+	pr, pw := io.Pipe()
+	wc := &writeCounter{w: pw}
+
+	// Results are repetitive JSON, so they compress well even at the fastest
+	// level.
+	zw, err := gzip.NewWriterLevel(wc, gzip.BestSpeed)
 	if err != nil {
 		return err
 	}
 
-	b.shard += 1
+	b.shard++
+	b.shardSize = 0
+	b.pw, b.zw, b.wc = pw, zw, wc
+	b.done = make(chan error, 1)
+
+	go func(key string, done chan<- error) {
+		_, err := b.store.Upload(b.ctx, key, pr)
+		// Unblocks the writer if the store stopped reading early.
+		pr.CloseWithError(err)
+		done <- err
+	}(b.key(), b.done)
+
+	return nil
+}
+
+// finishShard completes the upload of the current shard, if there is one.
+func (b *blobUploader) finishShard() error {
+	if b.pw == nil {
+		return nil
+	}
+
+	if err := b.zw.Close(); err != nil {
+		return errors.Append(err, b.abort(err))
+	}
+	b.pw.Close()
+	err := <-b.done
+	n := b.wc.n
+	b.pw, b.zw, b.wc, b.done = nil, nil, nil, nil
+	if err != nil {
+		// The store discards a failed upload, but earlier shards are complete.
+		return errors.Append(err, b.deleteUploaded())
+	}
 
+	b.uploaded = append(b.uploaded, b.key())
+	b.uploadedBytes += n
+	return nil
+}
+
+// finish completes the upload of the current shard. The uploaded shards are
+// kept from then on.
+func (b *blobUploader) finish() error {
+	if err := b.finishShard(); err != nil {
+		return err
+	}
+	b.keptBytes += b.uploadedBytes
+	b.uploaded, b.uploadedBytes = nil, 0
 	return nil
 }
 
+// abort fails the upload of the current shard with cause, so the store
+// discards it, and deletes the shards which were already uploaded.
+func (b *blobUploader) abort(cause error) error {
+	if b.pw != nil {
+		b.pw.CloseWithError(cause)
+		// The error is cause or why the upload failed before.
+		<-b.done
+		b.pw, b.zw, b.wc, b.done = nil, nil, nil, nil
+	}
+	return b.deleteUploaded()
+}
+
+func (b *blobUploader) deleteUploaded() error {
+	// ctx may be canceled already, for example if the job was canceled, but
+	// we still want to clean up.
+	ctx := context.WithoutCancel(b.ctx)
+
+	var errs error
+	for _, key := range b.uploaded {
+		if err := b.store.Delete(ctx, key); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "deleting key %q", key))
+		}
+	}
+	b.uploaded, b.uploadedBytes = nil, 0
+	return errs
+}
+
+// isShardKey returns true if key is one of the keys blobUploader writes for
+// prefix, compressed or not.
+func isShardKey(key, prefix string) bool {
+	key = strings.TrimSuffix(key, gzipKeySuffix)
+	if key == prefix {
+		return true
+	}
+	shard, ok := strings.CutPrefix(key, prefix+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(shard)
+	return err == nil
+}
+
+// deleteShards deletes all blobs written for prefix. Other keys sharing the
+// prefix, such as those of a task whose ID starts with the same digits, are
+// left alone.
+func deleteShards(ctx context.Context, store uploadstore.Store, prefix string) error {
+	iter, err := store.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for iter.Next() {
+		key := iter.Current()
+		if !isShardKey(key, prefix) {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			return errors.Wrapf(err, "deleting key %q", key)
+		}
+	}
+	return iter.Err()
+}
+
 type bufferedWriter struct {
 	flushSize int
 	buf       bytes.Buffer
@@ -2,9 +2,13 @@ package service
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/hexops/autogold/v2"
@@ -43,11 +47,7 @@ func TestMatchJsonWriter(t *testing.T) {
 	}
 	require.Equal(t, 1, uploadedFilesCount)
 
-	blob, err := mockStore.Get(context.Background(), "dummy_prefix")
-	require.NoError(t, err)
-
-	blobBytes, err := io.ReadAll(blob)
-	require.NoError(t, err)
+	blobBytes := readBlob(t, mockStore, "dummy_prefix.gz")
 
 	autogold.Expect(`{"type":"content","path":"internal/search.go","repositoryID":1,"repository":"repo","hunks":null,"chunkMatches":[{"content":"","contentStart":{"offset":0,"line":0,"column":0},"ranges":[{"start":{"offset":0,"line":18,"column":0},"end":{"offset":0,"line":18,"column":0}}]},{"content":"","contentStart":{"offset":0,"line":0,"column":0},"ranges":[{"start":{"offset":0,"line":27,"column":0},"end":{"offset":0,"line":27,"column":0}}]}],"language":"Go"}
 {"type":"content","path":"internal/service.go","repositoryID":1,"repository":"repo","hunks":null,"chunkMatches":[{"content":"","contentStart":{"offset":0,"line":0,"column":0},"ranges":[{"start":{"offset":0,"line":3,"column":0},"end":{"offset":0,"line":3,"column":0}}]},{"content":"","contentStart":{"offset":0,"line":0,"column":0},"ranges":[{"start":{"offset":0,"line":7,"column":0},"end":{"offset":0,"line":7,"column":0}}]}],"language":"Go"}
@@ -77,20 +77,16 @@ func mkFileMatch(repo types.MinimalRepo, path string, lineNumbers ...int) *resul
 func TestBufferedWriter(t *testing.T) {
 	mockStore := setupMockStore(t)
 
-	uploader := blobUploader{
-		ctx:    context.Background(),
-		store:  mockStore,
-		prefix: "blob",
-		shard:  1,
-	}
-
-	w := newBufferedWriter(24, uploader.write)
+	// Every shard holds at most 24 bytes.
+	mw, err := newJSONWriter(context.Background(), mockStore, "blob", 24, 24)
+	require.NoError(t, err)
+	w := mw.w
 
 	testData := func(val string) any {
 		return struct{ Key string }{Key: val}
 	}
 
-	err := w.Append(testData("a")) // {"Key":"a"}\n 12 bytes
+	err = w.Append(testData("a")) // {"Key":"a"}\n 12 bytes
 	require.NoError(t, err)
 	err = w.Append(testData("b"))
 	require.NoError(t, err)
@@ -99,7 +95,7 @@ func TestBufferedWriter(t *testing.T) {
 	err = w.Append(testData("c"))
 	require.NoError(t, err)
 
-	err = w.Flush()
+	err = mw.Flush()
 	require.NoError(t, err)
 
 	wantFiles := 2
@@ -116,24 +112,45 @@ func TestBufferedWriter(t *testing.T) {
 		wantBlob string
 	}{
 		{
-			wantKey:  "blob",
+			wantKey:  "blob.gz",
 			wantBlob: "{\"Key\":\"a\"}\n{\"Key\":\"b\"}\n",
 		},
 		{
-			wantKey:  "blob-2",
+			wantKey:  "blob-2.gz",
 			wantBlob: "{\"Key\":\"c\"}\n",
 		},
 	}
 
 	for _, c := range tc {
-		blob, err := mockStore.Get(context.Background(), c.wantKey)
-		require.NoError(t, err)
+		require.Equal(t, c.wantBlob, string(readBlob(t, mockStore, c.wantKey)))
+	}
+}
 
-		blobBytes, err := io.ReadAll(blob)
+func TestJSONWriterCompresses(t *testing.T) {
+	mockStore := setupMockStore(t)
+
+	w, err := NewJSONWriter(context.Background(), mockStore, "1-1")
+	require.NoError(t, err)
+
+	// Results of large jobs are very repetitive.
+	for i := range 1000 {
+		err := w.Write(mkFileMatch(types.MinimalRepo{ID: 1, Name: "github.com/sourcegraph/sourcegraph"}, fmt.Sprintf("internal/search/file%d.go", i), i, i+1))
 		require.NoError(t, err)
+	}
+	err = w.Flush()
+	require.NoError(t, err)
 
-		require.Equal(t, c.wantBlob, string(blobBytes))
+	var uploaded int64
+	for _, call := range mockStore.UploadFunc.History() {
+		uploaded += call.Result0
 	}
+
+	blob := readBlob(t, mockStore, "1-1.gz")
+	require.Equal(t, 1000, bytes.Count(blob, []byte("\n")))
+	require.Less(t, uploaded*10, int64(len(blob)), "expected a size reduction of at least 90%%, uploaded %d of %d bytes", uploaded, len(blob))
+
+	// The writer counts the compressed bytes.
+	require.Equal(t, uploaded, w.BytesWritten())
 }
 
 func TestNoUploadIfNotData(t *testing.T) {
@@ -153,6 +170,173 @@ func TestNoUploadIfNotData(t *testing.T) {
 	}
 }
 
+func TestJSONWriterReplacesPreviousShards(t *testing.T) {
+	ctx := context.Background()
+	mockStore := setupMockStore(t)
+
+	// A previous attempt of task 1 wrote two shards, one of them before blobs
+	// were compressed. Task 12 shares the prefix "1-1" but must be left alone.
+	for _, key := range []string{"1-1", "1-1-2.gz", "1-12"} {
+		_, err := mockStore.Upload(ctx, key, strings.NewReader("stale\n"))
+		require.NoError(t, err)
+	}
+
+	w, err := NewJSONWriter(ctx, mockStore, "1-1")
+	require.NoError(t, err)
+	err = w.Write(mkFileMatch(types.MinimalRepo{ID: 1, Name: "repo"}, "main.go", 1))
+	require.NoError(t, err)
+	err = w.Flush()
+	require.NoError(t, err)
+
+	iter, err := mockStore.List(ctx, "")
+	require.NoError(t, err)
+	keys, err := iterator.Collect(iter)
+	require.NoError(t, err)
+	sort.Strings(keys)
+	require.Equal(t, []string{"1-1.gz", "1-12"}, keys)
+
+	require.Contains(t, string(readBlob(t, mockStore, "1-1.gz")), `"path":"main.go"`)
+}
+
+func TestJSONWriterStreamsLargeResults(t *testing.T) {
+	ctx := context.Background()
+	mockStore := setupMockStore(t)
+
+	// Like the multipart uploads of S3, the store reads the blob in parts
+	// while it is being written.
+	const partSize = 4 * 1024
+	var mu sync.Mutex
+	var parts []int
+	blobs := map[string][]byte{}
+	mockStore.UploadFunc.SetDefaultHook(func(_ context.Context, key string, r io.Reader) (int64, error) {
+		var blob []byte
+		buf := make([]byte, partSize)
+		for {
+			n, err := io.ReadFull(r, buf)
+			mu.Lock()
+			if n > 0 {
+				blob = append(blob, buf[:n]...)
+				parts = append(parts, n)
+			}
+			mu.Unlock()
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+		mu.Lock()
+		blobs[key] = blob
+		mu.Unlock()
+		return int64(len(blob)), nil
+	})
+	mockStore.GetFunc.SetDefaultHook(func(_ context.Context, key string) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return io.NopCloser(bytes.NewReader(blobs[key])), nil
+	})
+
+	const bufferSize = 64 * 1024
+	w, err := newJSONWriter(ctx, mockStore, "1-1", bufferSize, maxShardSize)
+	require.NoError(t, err)
+
+	for i := range 10_000 {
+		err := w.Write(mkFileMatch(types.MinimalRepo{ID: 1, Name: "github.com/sourcegraph/sourcegraph"}, fmt.Sprintf("generated/file%d.go", i), i, i+1))
+		require.NoError(t, err)
+
+		// The matches are never buffered as a whole.
+		require.Less(t, w.w.Len(), bufferSize)
+		require.LessOrEqual(t, w.w.buf.Cap(), 4*bufferSize)
+	}
+
+	// Most of the blob was read before the upload is completed.
+	mu.Lock()
+	partsBeforeFlush := len(parts)
+	mu.Unlock()
+	require.Greater(t, partsBeforeFlush, 1)
+
+	err = w.Flush()
+	require.NoError(t, err)
+
+	blob := readBlob(t, mockStore, "1-1.gz")
+	require.Equal(t, 10_000, bytes.Count(blob, []byte("\n")))
+	require.Len(t, mockStore.UploadFunc.History(), 1)
+}
+
+func TestJSONWriterAbort(t *testing.T) {
+	ctx := context.Background()
+	mockStore := setupMockStore(t)
+
+	// The first shard is complete when the writer is aborted while writing
+	// the second.
+	w, err := newJSONWriter(ctx, mockStore, "1-1", 24, 24)
+	require.NoError(t, err)
+	for _, path := range []string{"a.go", "b.go"} {
+		err := w.Write(mkFileMatch(types.MinimalRepo{ID: 1, Name: "repo"}, path, 1))
+		require.NoError(t, err)
+	}
+
+	err = w.Abort()
+	require.NoError(t, err)
+
+	iter, err := mockStore.List(ctx, "")
+	require.NoError(t, err)
+	keys, err := iterator.Collect(iter)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	// The upload of the second shard failed, so the store didn't keep it.
+	history := mockStore.UploadFunc.History()
+	require.Len(t, history, 2)
+	require.NoError(t, history[0].Result1)
+	require.ErrorIs(t, history[1].Result1, errUploadAborted)
+	// Nothing was kept, so nothing is counted.
+	require.Zero(t, w.BytesWritten())
+
+	// Aborting after Flush keeps the results.
+	w, err = newJSONWriter(ctx, mockStore, "1-2", resultsBufferSize, maxShardSize)
+	require.NoError(t, err)
+	err = w.Write(mkFileMatch(types.MinimalRepo{ID: 1, Name: "repo"}, "main.go", 1))
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+	require.NoError(t, w.Abort())
+	require.Contains(t, string(readBlob(t, mockStore, "1-2.gz")), `"path":"main.go"`)
+	require.Equal(t, mockStore.UploadFunc.History()[2].Result0, w.BytesWritten())
+}
+
+func TestIsShardKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"1-1":      true,
+		"1-1.gz":   true,
+		"1-1-2":    true,
+		"1-1-2.gz": true,
+		"1-1-10":   true,
+		"1-12":     false,
+		"1-12.gz":  false,
+		"1-1-":     false,
+		"1-1-foo":  false,
+		"1-":       false,
+	} {
+		require.Equal(t, want, isShardKey(key, "1-1"), key)
+	}
+}
+
+// readBlob returns the decompressed content of the gzip compressed blob key.
+func readBlob(t *testing.T, store *mocks.MockStore, key string) []byte {
+	t.Helper()
+
+	blob, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer blob.Close()
+
+	zr, err := gzip.NewReader(blob)
+	require.NoError(t, err)
+	b, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	return b
+}
+
 func setupMockStore(t *testing.T) *mocks.MockStore {
 	t.Helper()
 
@@ -170,6 +354,11 @@ func setupMockStore(t *testing.T) *mocks.MockStore {
 		return int64(len(b)), nil
 	})
 
+	mockStore.DeleteFunc.SetDefaultHook(func(ctx context.Context, key string) error {
+		delete(bucket, key)
+		return nil
+	})
+
 	mockStore.ListFunc.SetDefaultHook(func(ctx context.Context, prefix string) (*iterator.Iterator[string], error) {
 		var keys []string
 		for k := range bucket {
@@ -0,0 +1,677 @@
+package service
+
+import (
+	"cmp"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+// resultShard is one blob written by a repo revision job.
+type resultShard struct {
+	key   string
+	shard int
+	// compressed is true if the blob is gzip compressed.
+	compressed bool
+}
+
+// groupResultKeys groups the blob keys of a search job by the ID of the repo
+// revision job which wrote them. The shards of each repo revision job are
+// sorted in the order they were written. Keys which do not follow the naming
+// scheme of NewJSONWriter and ResultsKeyPrefix are ignored. See
+// ResultsKeyPrefix for why the task can be derived from the key.
+func groupResultKeys(iter *iterator.Iterator[string], prefix string) (map[int64][]resultShard, error) {
+	shards := make(map[int64][]resultShard)
+	for iter.Next() {
+		key := iter.Current()
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		rest, compressed := strings.CutSuffix(rest, gzipKeySuffix)
+
+		taskStr, shardStr, hasShard := strings.Cut(rest, "-")
+		taskID, err := strconv.ParseInt(taskStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		shard := 1
+		if hasShard {
+			if shard, err = strconv.Atoi(shardStr); err != nil {
+				continue
+			}
+		}
+
+		shards[taskID] = append(shards[taskID], resultShard{key: key, shard: shard, compressed: compressed})
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, s := range shards {
+		slices.SortFunc(s, func(a, b resultShard) int {
+			return cmp.Compare(a.shard, b.shard)
+		})
+	}
+
+	return shards, nil
+}
+
+// mergeBatchShards moves the shards of the tasks of a batch to the ID of the
+// batch. The workers store the results of all tasks of a batch which succeeded
+// in the same attempt as one chunk, named after the first of them, so the
+// results of a batch may be spread over the shards of several of its tasks.
+// batches maps the ID of each batch to the IDs of its tasks.
+func mergeBatchShards(shards map[int64][]resultShard, batches map[int64][]int64) {
+	for batchID, taskIDs := range batches {
+		slices.Sort(taskIDs)
+		var merged []resultShard
+		for _, id := range taskIDs {
+			merged = append(merged, shards[id]...)
+			delete(shards, id)
+		}
+		if len(merged) > 0 {
+			shards[batchID] = merged
+		}
+	}
+}
+
+// ResultFormat is the format in which the results of a search job are
+// downloaded.
+type ResultFormat int
+
+const (
+	// ResultFormatCSV writes one row per matched line with the columns of
+	// types.ResultsColumns. This is the default.
+	ResultFormatCSV ResultFormat = iota
+	// ResultFormatJSONL writes one JSON object per line with the full match,
+	// including the content and ranges of content matches.
+	ResultFormatJSONL
+	// ResultFormatParquet writes the rows of ResultFormatCSV as a Parquet
+	// file, with one row group per repository.
+	ResultFormatParquet
+)
+
+func (f ResultFormat) String() string {
+	switch f {
+	case ResultFormatCSV:
+		return "csv"
+	case ResultFormatJSONL:
+		return "jsonl"
+	case ResultFormatParquet:
+		return "parquet"
+	default:
+		return fmt.Sprintf("ResultFormat(%d)", int(f))
+	}
+}
+
+// resultWriter writes the results of a search job in a ResultFormat.
+//
+// The results are written one chunk at a time, where a chunk is the ordered
+// records of one repository. How chunks are merged into a single download
+// depends on the format: CSV and JSONL chunks are concatenated, while Parquet
+// chunks become row groups of one file which is only complete after close.
+type resultWriter interface {
+	// encode returns the records of a match found by task. match is the JSON
+	// written by MatchJSONWriter.
+	encode(task types.SearchJobLog, match json.RawMessage) ([]resultRecord, error)
+	// header writes what comes before the first chunk.
+	header() error
+	// writeRow adds r to the current chunk.
+	writeRow(r resultRecord) error
+	// flush ends the current chunk.
+	flush() error
+	// close writes what comes after the last chunk.
+	close() error
+}
+
+// resultRecord is an encoded part of a match, with the keys the records of a
+// repository are ordered by.
+type resultRecord struct {
+	path string
+	// line is the 1-based number of the matched line, or 0 if the record is
+	// not for a line.
+	line int
+	// row is set by the tabular formats.
+	row resultRow
+	// data is set by ResultFormatJSONL.
+	data []byte
+
+	// hash identifies the record apart from the revision it was found in, see
+	// recordHash. revisions are the revisions it was found in. Both are only
+	// set if the results are deduplicated.
+	hash      [sha256.Size]byte
+	revisions []string
+}
+
+// resultRow is a row of the tabular formats, with the columns of
+// types.ResultsColumns.
+type resultRow struct {
+	repository string
+	// repositoryID is 0 if the match doesn't include it.
+	repositoryID int32
+	revision     string
+	commit       string
+	path         string
+	// line is 1-based, or 0 if the row is not for a line.
+	line    int
+	preview string
+}
+
+// strings returns the values of r as strings, in the order of
+// types.ResultsColumns. Missing numbers are empty.
+func (r resultRow) strings() []string {
+	var repositoryID, line string
+	if r.repositoryID != 0 {
+		repositoryID = strconv.Itoa(int(r.repositoryID))
+	}
+	if r.line > 0 {
+		line = strconv.Itoa(r.line)
+	}
+	return []string{
+		r.repository,
+		repositoryID,
+		r.revision,
+		r.commit,
+		r.path,
+		line,
+		r.preview,
+	}
+}
+
+// newResultWriter returns the resultWriter of format. Only the Parquet format
+// can carry sample, the others leave it to the caller to report it. If
+// deduplicate is true, the records are hashed, and their revisions are written
+// as types.ResultsRevisionsColumn.
+func newResultWriter(format ResultFormat, w io.Writer, sample *types.ResultsSample, deduplicate bool) (resultWriter, error) {
+	switch format {
+	case ResultFormatCSV:
+		return &csvResultWriter{cw: csv.NewWriter(w), deduplicate: deduplicate}, nil
+	case ResultFormatJSONL:
+		return &jsonlResultWriter{w: w, deduplicate: deduplicate}, nil
+	case ResultFormatParquet:
+		return newParquetResultWriter(w, sample, deduplicate), nil
+	default:
+		return nil, errors.Errorf("unsupported result format %s", format)
+	}
+}
+
+// csvMatch is the subset of the fields of the match events written by
+// MatchJSONWriter that we include in the CSV.
+type csvMatch struct {
+	Type         string `json:"type"`
+	RepositoryID int32  `json:"repositoryID"`
+	Repository   string `json:"repository"`
+	Commit       string `json:"commit"`
+	// OID is the commit of commit matches.
+	OID          string `json:"oid"`
+	Path         string `json:"path"`
+	ChunkMatches []struct {
+		Content      string `json:"content"`
+		ContentStart struct {
+			Line int `json:"line"`
+		} `json:"contentStart"`
+		Ranges []struct {
+			Start struct {
+				Line int `json:"line"`
+			} `json:"start"`
+			End struct {
+				Line int `json:"line"`
+			} `json:"end"`
+		} `json:"ranges"`
+	} `json:"chunkMatches"`
+	Symbols []struct {
+		Name string `json:"name"`
+		Line int    `json:"line"`
+	} `json:"symbols"`
+}
+
+// csvLine is a matched line of a csvMatch.
+type csvLine struct {
+	// line is 1-based, or 0 if the match is not on a line.
+	line    int
+	preview string
+}
+
+// lines returns the matched lines of a content match, one per line even if
+// several ranges are on it, and the lines of the symbols of a symbol match.
+// Every other type of match, and matches without lines, have a single line
+// without a number.
+func (m *csvMatch) lines() []csvLine {
+	var lines []csvLine
+	switch m.Type {
+	case "content":
+		for _, cm := range m.ChunkMatches {
+			contentLines := strings.Split(cm.Content, "\n")
+			seen := make(map[int]struct{}, len(cm.Ranges))
+			for _, r := range cm.Ranges {
+				if _, ok := seen[r.Start.Line]; ok {
+					continue
+				}
+				seen[r.Start.Line] = struct{}{}
+
+				var preview string
+				if i := r.Start.Line - cm.ContentStart.Line; i >= 0 && i < len(contentLines) {
+					preview = strings.TrimSuffix(contentLines[i], "\r")
+				}
+				// Ranges are 0-based.
+				lines = append(lines, csvLine{line: r.Start.Line + 1, preview: preview})
+			}
+		}
+	case "symbol":
+		for _, sym := range m.Symbols {
+			lines = append(lines, csvLine{line: sym.Line, preview: sym.Name})
+		}
+	}
+	if len(lines) == 0 {
+		return []csvLine{{}}
+	}
+	return lines
+}
+
+// identity returns the fields which identify m apart from the revision it was
+// found in: its type, its path, and the commit of commit matches. The commit
+// of other matches differs between revisions even if their content doesn't.
+func (m *csvMatch) identity() []string {
+	return []string{m.Type, m.Path, m.OID}
+}
+
+// recordHash hashes fields, which mustn't depend on the revision a match was
+// found in. Records with the same hash are the same match, or the same
+// matched line, in several revisions.
+func recordHash(fields ...string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, f := range fields {
+		// The lengths keep adjacent fields from running into each other.
+		fmt.Fprintf(h, "%d:%s", len(f), f)
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// normalizeContent normalizes the line endings of content, so content which
+// was checked out with different line endings is the same.
+func normalizeContent(content string) string {
+	return strings.ReplaceAll(content, "\r\n", "\n")
+}
+
+// tableRecords returns one record per matched line of match, for the
+// tabular formats. If deduplicate is true, every record is hashed by the
+// identity of match, and its line and preview.
+func tableRecords(task types.SearchJobLog, match json.RawMessage, deduplicate bool) ([]resultRecord, error) {
+	var m csvMatch
+	if err := json.Unmarshal(match, &m); err != nil {
+		return nil, err
+	}
+
+	lines := m.lines()
+	records := make([]resultRecord, 0, len(lines))
+	for _, l := range lines {
+		var hash [sha256.Size]byte
+		var revisions []string
+		if deduplicate {
+			hash = recordHash(append(m.identity(), strconv.Itoa(l.line), normalizeContent(l.preview))...)
+			revisions = []string{task.Revision}
+		}
+		records = append(records, resultRecord{
+			path: m.Path,
+			line: l.line,
+			row: resultRow{
+				repository:   string(task.RepoName),
+				repositoryID: m.RepositoryID,
+				revision:     task.Revision,
+				commit:       cmp.Or(m.Commit, m.OID),
+				path:         m.Path,
+				line:         l.line,
+				preview:      l.preview,
+			},
+			hash:      hash,
+			revisions: revisions,
+		})
+	}
+	return records, nil
+}
+
+// csvResultWriter writes the header once, followed by one row per matched
+// line. Values are quoted by encoding/csv, so they may contain commas, quotes
+// and newlines.
+type csvResultWriter struct {
+	cw          *csv.Writer
+	deduplicate bool
+}
+
+func (w *csvResultWriter) encode(task types.SearchJobLog, match json.RawMessage) ([]resultRecord, error) {
+	return tableRecords(task, match, w.deduplicate)
+}
+
+func (w *csvResultWriter) header() error {
+	if w.deduplicate {
+		return w.cw.Write(append(slices.Clone(types.ResultsColumns), types.ResultsRevisionsColumn))
+	}
+	return w.cw.Write(types.ResultsColumns)
+}
+
+func (w *csvResultWriter) writeRow(r resultRecord) error {
+	if w.deduplicate {
+		return w.cw.Write(append(r.row.strings(), strings.Join(r.revisions, " ")))
+	}
+	return w.cw.Write(r.row.strings())
+}
+
+func (w *csvResultWriter) flush() error {
+	w.cw.Flush()
+	return w.cw.Error()
+}
+
+func (w *csvResultWriter) close() error {
+	return w.flush()
+}
+
+type jsonlResultWriter struct {
+	w           io.Writer
+	deduplicate bool
+}
+
+func (w *jsonlResultWriter) encode(task types.SearchJobLog, match json.RawMessage) ([]resultRecord, error) {
+	// The match only includes the resolved commit, so we add the revision
+	// that was searched.
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(match, &m); err != nil {
+		return nil, err
+	}
+	revision, err := json.Marshal(task.Revision)
+	if err != nil {
+		return nil, err
+	}
+	m["revision"] = revision
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	// A match is ordered by its first matched line, like in the CSV.
+	var cm csvMatch
+	if err := json.Unmarshal(match, &cm); err != nil {
+		return nil, err
+	}
+	first := slices.MinFunc(cm.lines(), func(a, b csvLine) int {
+		return cmp.Compare(a.line, b.line)
+	})
+	record := resultRecord{
+		path: cm.Path,
+		line: first.line,
+		data: append(data, '\n'),
+	}
+	if w.deduplicate {
+		record.hash = recordHash(jsonlIdentity(&cm)...)
+		record.revisions = []string{task.Revision}
+	}
+	return []resultRecord{record}, nil
+}
+
+// jsonlIdentity returns the identity of m together with the content and the
+// line spans of its chunks and symbols, which is what the JSONL export
+// includes of a match.
+func jsonlIdentity(m *csvMatch) []string {
+	fields := m.identity()
+	for _, cm := range m.ChunkMatches {
+		fields = append(fields, strconv.Itoa(cm.ContentStart.Line), normalizeContent(cm.Content))
+		for _, r := range cm.Ranges {
+			fields = append(fields, fmt.Sprintf("%d-%d", r.Start.Line, r.End.Line))
+		}
+	}
+	for _, sym := range m.Symbols {
+		fields = append(fields, strconv.Itoa(sym.Line), sym.Name)
+	}
+	return fields
+}
+
+func (w *jsonlResultWriter) header() error {
+	return nil
+}
+
+func (w *jsonlResultWriter) writeRow(r resultRecord) error {
+	data := r.data
+	if w.deduplicate {
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		revisions, err := json.Marshal(r.revisions)
+		if err != nil {
+			return err
+		}
+		m["revisions"] = revisions
+		if data, err = json.Marshal(m); err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	}
+	_, err := w.w.Write(data)
+	return err
+}
+
+func (w *jsonlResultWriter) flush() error {
+	return nil
+}
+
+func (w *jsonlResultWriter) close() error {
+	return nil
+}
+
+// writeSearchJobResults writes the results of tasks to w in the given format.
+// Results are ordered by repository, path and line, no matter in which order
+// the tasks finished. Results with the same keys are ordered by revision.
+//
+// The results of one repository are read into memory to order them, so memory
+// use depends on the number of results of the largest repository, but not on
+// the number of repositories.
+//
+// Tasks without results, including failed ones, are skipped. Their state is
+// reported by the job logs.
+//
+// sample is the types.ResultsSample of the job, or nil if its results are
+// complete. If deduplicate is true, records which are the same in several
+// revisions of a repository are only written for the first revision, see
+// types.ResultsRevisionsColumn. Since only the revisions of the same repository
+// are compared, this happens while the records of a repository are in memory
+// anyway, and doesn't depend on how many results the job has.
+func writeSearchJobResults(ctx context.Context, tasks []types.SearchJobLog, shards map[int64][]resultShard, uploadStore uploadstore.Store, format ResultFormat, sample *types.ResultsSample, deduplicate bool, w io.Writer) (int64, error) {
+	slices.SortFunc(tasks, func(a, b types.SearchJobLog) int {
+		return cmp.Or(
+			cmp.Compare(a.RepoName, b.RepoName),
+			cmp.Compare(a.Revision, b.Revision),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	writeCounter := &writeCounter{w: w}
+	rw, err := newResultWriter(format, writeCounter, sample, deduplicate)
+	if err != nil {
+		return 0, err
+	}
+	if err := rw.header(); err != nil {
+		return writeCounter.n, err
+	}
+
+	readShard := func(s resultShard, f func(match json.RawMessage) error) error {
+		rc, err := uploadStore.Get(ctx, s.key)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		var r io.Reader = rc
+		if s.compressed {
+			zr, err := gzip.NewReader(rc)
+			if err != nil {
+				return err
+			}
+			defer zr.Close()
+			r = zr
+		}
+
+		dec := json.NewDecoder(r)
+		for {
+			var match json.RawMessage
+			if err := dec.Decode(&match); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err := f(match); err != nil {
+				return err
+			}
+		}
+	}
+
+	readKey := func(task types.SearchJobLog, s resultShard, records []resultRecord) ([]resultRecord, error) {
+		err := readShard(s, func(match json.RawMessage) error {
+			matchRecords, err := rw.encode(task, match)
+			if err != nil {
+				return err
+			}
+			records = append(records, matchRecords...)
+			return nil
+		})
+		return records, err
+	}
+
+	// The tasks of a batch share the shards of the batch, see
+	// mergeBatchShards. They are read once, when the first task of the batch
+	// is written, and split by repository, since a batch has at most one
+	// revision of a repository. The matches of a batch are dropped once all
+	// its tasks were written.
+	batchTasks := make(map[int64]int)
+	for _, task := range tasks {
+		if task.BatchID != 0 {
+			batchTasks[task.BatchID]++
+		}
+	}
+	batchMatches := make(map[int64]map[api.RepoID][]json.RawMessage)
+
+	readBatch := func(task types.SearchJobLog, records []resultRecord) ([]resultRecord, error) {
+		byRepo, ok := batchMatches[task.BatchID]
+		if !ok {
+			byRepo = make(map[api.RepoID][]json.RawMessage)
+			for _, s := range shards[task.BatchID] {
+				err := readShard(s, func(match json.RawMessage) error {
+					var m struct {
+						RepositoryID api.RepoID `json:"repositoryID"`
+					}
+					if err := json.Unmarshal(match, &m); err != nil {
+						return err
+					}
+					byRepo[m.RepositoryID] = append(byRepo[m.RepositoryID], match)
+					return nil
+				})
+				if err != nil {
+					return records, errors.Wrapf(err, "writing %s for key %q", format, s.key)
+				}
+			}
+			batchMatches[task.BatchID] = byRepo
+		}
+
+		for _, match := range byRepo[task.RepoID] {
+			matchRecords, err := rw.encode(task, match)
+			if err != nil {
+				return records, err
+			}
+			records = append(records, matchRecords...)
+		}
+
+		batchTasks[task.BatchID]--
+		if batchTasks[task.BatchID] == 0 {
+			delete(batchMatches, task.BatchID)
+		}
+		return records, nil
+	}
+
+	for len(tasks) > 0 {
+		// The tasks of a repository are next to each other.
+		repoTasks := tasks
+		for i, task := range tasks {
+			if task.RepoName != tasks[0].RepoName {
+				repoTasks = tasks[:i]
+				break
+			}
+		}
+		tasks = tasks[len(repoTasks):]
+
+		var records []resultRecord
+		for _, task := range repoTasks {
+			if task.BatchID != 0 {
+				if records, err = readBatch(task, records); err != nil {
+					return writeCounter.n, err
+				}
+				continue
+			}
+			for _, s := range shards[task.ID] {
+				if records, err = readKey(task, s, records); err != nil {
+					return writeCounter.n, errors.Wrapf(err, "writing %s for key %q", format, s.key)
+				}
+			}
+		}
+
+		// The records were read in order of revision, which the stable sort
+		// keeps for records with the same keys.
+		slices.SortStableFunc(records, func(a, b resultRecord) int {
+			return cmp.Or(
+				cmp.Compare(a.path, b.path),
+				cmp.Compare(a.line, b.line),
+			)
+		})
+		if deduplicate {
+			records = deduplicateRecords(records)
+		}
+		for _, r := range records {
+			if err := rw.writeRow(r); err != nil {
+				return writeCounter.n, err
+			}
+		}
+		if err := rw.flush(); err != nil {
+			return writeCounter.n, err
+		}
+	}
+
+	err = rw.close()
+	return writeCounter.n, err
+}
+
+// deduplicateRecords keeps the first of the records with the same hash, and
+// appends the revisions of the others to its revisions. The records must be
+// those of one repository, in the order they are written.
+func deduplicateRecords(records []resultRecord) []resultRecord {
+	first := make(map[[sha256.Size]byte]int, len(records))
+	deduplicated := records[:0]
+	for _, r := range records {
+		i, ok := first[r.hash]
+		if !ok {
+			first[r.hash] = len(deduplicated)
+			deduplicated = append(deduplicated, r)
+			continue
+		}
+		// A match may be found twice in the same revision, for example if
+		// a symbol is defined twice on the same line.
+		for _, rev := range r.revisions {
+			if !slices.Contains(deduplicated[i].revisions, rev) {
+				deduplicated[i].revisions = append(deduplicated[i].revisions, rev)
+			}
+		}
+	}
+	return deduplicated
+}
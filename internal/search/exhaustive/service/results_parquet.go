@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/parquet"
+	"github.com/apache/arrow/go/v14/parquet/compress"
+	"github.com/apache/arrow/go/v14/parquet/pqarrow"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+// parquetFields are the columns of types.ResultsColumns, in the same order.
+// The numbers which are empty in the CSV are null.
+var parquetFields = []arrow.Field{
+	{Name: "repository", Type: arrow.BinaryTypes.String},
+	{Name: "repository_id", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+	{Name: "revision", Type: arrow.BinaryTypes.String},
+	{Name: "commit", Type: arrow.BinaryTypes.String},
+	{Name: "path", Type: arrow.BinaryTypes.String},
+	{Name: "line", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+	{Name: "preview", Type: arrow.BinaryTypes.String},
+}
+
+// parquetRevisionsField is types.ResultsRevisionsColumn, which follows
+// parquetFields if the results are deduplicated.
+var parquetRevisionsField = arrow.Field{Name: types.ResultsRevisionsColumn, Type: arrow.BinaryTypes.String}
+
+// parquetResultWriter writes the rows of the CSV export as a Parquet file.
+// The rows of a chunk are buffered and written as one row group, since a row
+// group stores its columns one after the other. The file can only be read
+// once close wrote its footer.
+//
+// The results of a sampled job are marked in the key-value metadata of the
+// file, see types.ResultsSample.Metadata.
+type parquetResultWriter struct {
+	w           io.Writer
+	schema      *arrow.Schema
+	deduplicate bool
+	fw          *pqarrow.FileWriter
+	b           *array.RecordBuilder
+}
+
+func newParquetResultWriter(w io.Writer, sample *types.ResultsSample, deduplicate bool) *parquetResultWriter {
+	var metadata *arrow.Metadata
+	if sample != nil {
+		md := arrow.NewMetadata(sample.Metadata())
+		metadata = &md
+	}
+	fields := parquetFields
+	if deduplicate {
+		fields = append(slices.Clone(parquetFields), parquetRevisionsField)
+	}
+	return &parquetResultWriter{w: w, schema: arrow.NewSchema(fields, metadata), deduplicate: deduplicate}
+}
+
+func (w *parquetResultWriter) encode(task types.SearchJobLog, match json.RawMessage) ([]resultRecord, error) {
+	return tableRecords(task, match, w.deduplicate)
+}
+
+func (w *parquetResultWriter) header() error {
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	fw, err := pqarrow.NewFileWriter(w.schema, w.w, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return err
+	}
+	w.fw = fw
+	w.b = array.NewRecordBuilder(memory.DefaultAllocator, w.schema)
+	return nil
+}
+
+func (w *parquetResultWriter) writeRow(r resultRecord) error {
+	appendNullableInt32 := func(b *array.Int32Builder, v int) {
+		if v == 0 {
+			b.AppendNull()
+		} else {
+			b.Append(int32(v))
+		}
+	}
+
+	w.b.Field(0).(*array.StringBuilder).Append(r.row.repository)
+	appendNullableInt32(w.b.Field(1).(*array.Int32Builder), int(r.row.repositoryID))
+	w.b.Field(2).(*array.StringBuilder).Append(r.row.revision)
+	w.b.Field(3).(*array.StringBuilder).Append(r.row.commit)
+	w.b.Field(4).(*array.StringBuilder).Append(r.row.path)
+	appendNullableInt32(w.b.Field(5).(*array.Int32Builder), r.row.line)
+	w.b.Field(6).(*array.StringBuilder).Append(r.row.preview)
+	if w.deduplicate {
+		w.b.Field(7).(*array.StringBuilder).Append(strings.Join(r.revisions, " "))
+	}
+	return nil
+}
+
+func (w *parquetResultWriter) flush() error {
+	rec := w.b.NewRecord()
+	defer rec.Release()
+
+	// Repositories without results don't get an empty row group.
+	if rec.NumRows() == 0 {
+		return nil
+	}
+	return w.fw.Write(rec)
+}
+
+func (w *parquetResultWriter) close() error {
+	w.b.Release()
+	return w.fw.Close()
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+// The modes of "search.jobs.downloadPermissionCheck". Any other value, including
+// none, turns the check off.
+const (
+	downloadPermissionCheckFilter = "filter"
+	downloadPermissionCheckRefuse = "refuse"
+)
+
+// InaccessibleReposError refuses the download of the results of a search job,
+// since its initiator can't read some of the repositories it found results in
+// anymore.
+type InaccessibleReposError struct {
+	// Count is the number of repositories the initiator can't read anymore.
+	Count int
+}
+
+func (e *InaccessibleReposError) Error() string {
+	return fmt.Sprintf("refusing to download the results of the search job, since its initiator lost access to %d of its repositories after it ran", e.Count)
+}
+
+// checkRepoPermissions re-checks that the initiator of job can still read the
+// repositories of its tasks, if "search.jobs.downloadPermissionCheck" asks for
+// it. Results are kept for a long time, and permissions may change in the
+// meantime. It returns the repositories whose results must be left out in the
+// "filter" mode, and an InaccessibleReposError in the "refuse" mode.
+//
+// Site admins who download the results of another user bypass the check, which
+// is audited. The caller has to check that the actor may read job.
+func (s *Service) checkRepoPermissions(ctx context.Context, job *types.ExhaustiveSearchJob) (map[api.RepoID]struct{}, error) {
+	mode := conf.Get().SearchJobsDownloadPermissionCheck
+	if mode != downloadPermissionCheckFilter && mode != downloadPermissionCheckRefuse {
+		return nil, nil
+	}
+
+	if a := actor.FromContext(ctx); a.IsInternal() || a.UID != job.InitiatorID {
+		s.auditAccess(ctx, "downloadPermissionCheckBypassed", job)
+		return nil, nil
+	}
+
+	repoIDs, err := s.store.ListInaccessibleRepos(ctx, job.ID, job.InitiatorID)
+	if err != nil {
+		return nil, err
+	}
+	if len(repoIDs) == 0 {
+		return nil, nil
+	}
+	if mode == downloadPermissionCheckRefuse {
+		return nil, &InaccessibleReposError{Count: len(repoIDs)}
+	}
+
+	hidden := make(map[api.RepoID]struct{}, len(repoIDs))
+	for _, id := range repoIDs {
+		hidden[id] = struct{}{}
+	}
+	return hidden, nil
+}
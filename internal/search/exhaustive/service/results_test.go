@@ -0,0 +1,509 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/parquet/file"
+	"github.com/apache/arrow/go/v14/parquet/metadata"
+	"github.com/apache/arrow/go/v14/parquet/pqarrow"
+	"github.com/hexops/autogold/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+func setupResultsStore(t *testing.T, blobs map[string]string) (*mocks.MockStore, map[int64][]resultShard) {
+	t.Helper()
+
+	ctx := context.Background()
+	mockStore := setupMockStore(t)
+	for key, blob := range blobs {
+		// Blobs with the gzip suffix are stored compressed, like
+		// blobUploader does.
+		var r io.Reader = strings.NewReader(blob)
+		if strings.HasSuffix(key, gzipKeySuffix) {
+			var buf bytes.Buffer
+			zw := gzip.NewWriter(&buf)
+			_, err := zw.Write([]byte(blob))
+			require.NoError(t, err)
+			require.NoError(t, zw.Close())
+			r = &buf
+		}
+		_, err := mockStore.Upload(ctx, key, r)
+		require.NoError(t, err)
+	}
+
+	iter, err := mockStore.List(ctx, getPrefix(7))
+	require.NoError(t, err)
+	shards, err := groupResultKeys(iter, getPrefix(7))
+	require.NoError(t, err)
+
+	return mockStore, shards
+}
+
+func TestWriteSearchJobResults(t *testing.T) {
+	mockStore, shards := setupResultsStore(t, map[string]string{
+		// task 1: repob@main, written in two shards. The second one is
+		// compressed.
+		"7-1":      `{"type":"content","path":"a.go","repositoryID":2,"repository":"repob","commit":"c1","chunkMatches":[{"content":"first\nsecond","contentStart":{"line":9},"ranges":[{"start":{"line":10}},{"start":{"line":10}},{"start":{"line":9}}]}]}` + "\n",
+		"7-1-2.gz": `{"type":"path","path":"b.go","repositoryID":2,"repository":"repob","commit":"c1"}` + "\n",
+		// task 2: repoa@main
+		"7-2": `{"type":"symbol","path":"c.go","repositoryID":1,"repository":"repoa","commit":"c2","symbols":[{"name":"Foo","line":3},{"name":"bar","line":1}]}` + "\n" +
+			`{"type":"repo","repositoryID":1,"repository":"repoa"}` + "\n",
+		// task 3: repoa@dev, failed after writing one result
+		"7-3": `{"type":"content","path":"c.go","repositoryID":1,"repository":"repoa","commit":"c3","chunkMatches":[{"content":"two\r","contentStart":{"line":1},"ranges":[{"start":{"line":1}}]}]}` + "\n",
+		// task 4 has no results. Keys of other jobs are ignored.
+		"7-x":  "not a result\n",
+		"70-1": "not a result\n",
+	})
+
+	tasks := []types.SearchJobLog{
+		{ID: 1, RepoName: "repob", Revision: "main", State: types.JobStateCompleted},
+		{ID: 2, RepoName: "repoa", Revision: "main", State: types.JobStateCompleted},
+		{ID: 3, RepoName: "repoa", Revision: "dev", State: types.JobStateFailed},
+		{ID: 4, RepoName: "repoc", Revision: "main", State: types.JobStateCompleted},
+	}
+
+	// The results of a repository are ordered by path and line, not by the
+	// revision they were found in.
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatCSV, nil, false, &buf)
+		require.NoError(t, err)
+		require.Equal(t, int64(buf.Len()), n)
+
+		autogold.Expect(`repository,repository_id,revision,commit,path,line,preview
+repoa,1,main,,,,
+repoa,1,main,c2,c.go,1,bar
+repoa,1,dev,c3,c.go,2,two
+repoa,1,main,c2,c.go,3,Foo
+repob,2,main,c1,a.go,10,first
+repob,2,main,c1,a.go,11,second
+repob,2,main,c1,b.go,,
+`).Equal(t, buf.String())
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatJSONL, nil, false, &buf)
+		require.NoError(t, err)
+		require.Equal(t, int64(buf.Len()), n)
+
+		autogold.Expect(`{"repository":"repoa","repositoryID":1,"revision":"main","type":"repo"}
+{"commit":"c2","path":"c.go","repository":"repoa","repositoryID":1,"revision":"main","symbols":[{"name":"Foo","line":3},{"name":"bar","line":1}],"type":"symbol"}
+{"chunkMatches":[{"content":"two\r","contentStart":{"line":1},"ranges":[{"start":{"line":1}}]}],"commit":"c3","path":"c.go","repository":"repoa","repositoryID":1,"revision":"dev","type":"content"}
+{"chunkMatches":[{"content":"first\nsecond","contentStart":{"line":9},"ranges":[{"start":{"line":10}},{"start":{"line":10}},{"start":{"line":9}}]}],"commit":"c1","path":"a.go","repository":"repob","repositoryID":2,"revision":"main","type":"content"}
+{"commit":"c1","path":"b.go","repository":"repob","repositoryID":2,"revision":"main","type":"path"}
+`).Equal(t, buf.String())
+	})
+
+	t.Run("parquet", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatParquet, nil, false, &buf)
+		require.NoError(t, err)
+		require.Equal(t, int64(buf.Len()), n)
+
+		var csvBuf bytes.Buffer
+		_, err = writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatCSV, nil, false, &csvBuf)
+		require.NoError(t, err)
+		want, err := csv.NewReader(&csvBuf).ReadAll()
+		require.NoError(t, err)
+
+		// The Parquet file has the rows of the CSV export, with one row group
+		// per repository with results.
+		rows, rowGroups := readParquetResults(t, buf.Bytes())
+		require.Equal(t, want, rows)
+		require.Equal(t, 2, rowGroups)
+		require.Nil(t, readParquetMetadata(t, buf.Bytes()).FindValue("sourcegraph.sampled"))
+	})
+
+	// Sampled results are marked in the metadata of the Parquet file.
+	t.Run("parquet sample", func(t *testing.T) {
+		var buf bytes.Buffer
+		sample := &types.ResultsSample{Rate: 0.25, Seed: 42, SampledTasks: 4, TotalTasks: 15}
+		_, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatParquet, sample, false, &buf)
+		require.NoError(t, err)
+
+		md := readParquetMetadata(t, buf.Bytes())
+		for key, want := range map[string]string{
+			"sourcegraph.sampled":       "true",
+			"sourcegraph.sample_rate":   "0.25",
+			"sourcegraph.sample_seed":   "42",
+			"sourcegraph.sampled_tasks": "4",
+			"sourcegraph.total_tasks":   "15",
+		} {
+			require.NotNil(t, md.FindValue(key), key)
+			require.Equal(t, want, *md.FindValue(key), key)
+		}
+	})
+}
+
+// readParquetMetadata returns the key-value metadata of the Parquet export b.
+func readParquetMetadata(t *testing.T, b []byte) metadata.KeyValueMetadata {
+	t.Helper()
+
+	rdr, err := file.NewParquetReader(bytes.NewReader(b))
+	require.NoError(t, err)
+	defer rdr.Close()
+
+	return rdr.MetaData().KeyValueMetadata()
+}
+
+// readParquetResults returns the rows of the Parquet export b in the format of
+// the CSV export, including the header, and the number of its row groups.
+func readParquetResults(t *testing.T, b []byte) ([][]string, int) {
+	t.Helper()
+
+	rdr, err := file.NewParquetReader(bytes.NewReader(b))
+	require.NoError(t, err)
+	defer rdr.Close()
+
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	require.NoError(t, err)
+	tbl, err := fr.ReadTable(context.Background())
+	require.NoError(t, err)
+	defer tbl.Release()
+
+	var header []string
+	for _, field := range tbl.Schema().Fields() {
+		header = append(header, field.Name)
+	}
+
+	rows := make([][]string, tbl.NumRows())
+	for i := 0; i < int(tbl.NumCols()); i++ {
+		row := 0
+		for _, chunk := range tbl.Column(i).Data().Chunks() {
+			for j := 0; j < chunk.Len(); j++ {
+				var v string
+				switch chunk := chunk.(type) {
+				case *array.String:
+					v = chunk.Value(j)
+				case *array.Int32:
+					if !chunk.IsNull(j) {
+						v = strconv.Itoa(int(chunk.Value(j)))
+					}
+				default:
+					t.Fatalf("unexpected column type %s", chunk.DataType())
+				}
+				rows[row] = append(rows[row], v)
+				row++
+			}
+		}
+	}
+
+	return append([][]string{header}, rows...), rdr.NumRowGroups()
+}
+
+// TestWriteSearchJobResults_Order checks that the results don't depend on the
+// order in which the tasks finished, which decides their IDs and shards.
+func TestWriteSearchJobResults_Order(t *testing.T) {
+	match := func(repo, path string, line int) string {
+		return fmt.Sprintf(`{"type":"content","path":%q,"repositoryID":1,"repository":%q,"commit":"c","chunkMatches":[{"content":"l%d","contentStart":{"line":%d},"ranges":[{"start":{"line":%d}}]}]}`+"\n", path, repo, line, line, line)
+	}
+
+	write := func(blobs map[string]string, tasks []types.SearchJobLog) string {
+		mockStore, shards := setupResultsStore(t, blobs)
+		var buf bytes.Buffer
+		_, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatCSV, nil, false, &buf)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	// main finished first and wrote its results in one shard.
+	first := write(map[string]string{
+		"7-1": match("repo", "b.go", 1) + match("repo", "a.go", 5),
+		"7-2": match("repo", "a.go", 2) + match("repo", "b.go", 0),
+	}, []types.SearchJobLog{
+		{ID: 1, RepoName: "repo", Revision: "main"},
+		{ID: 2, RepoName: "repo", Revision: "dev"},
+	})
+
+	// dev finished first and main wrote its results in two shards.
+	second := write(map[string]string{
+		"7-1":      match("repo", "b.go", 0) + match("repo", "a.go", 2),
+		"7-2":      match("repo", "a.go", 5),
+		"7-2-2.gz": match("repo", "b.go", 1),
+	}, []types.SearchJobLog{
+		{ID: 2, RepoName: "repo", Revision: "main"},
+		{ID: 1, RepoName: "repo", Revision: "dev"},
+	})
+
+	require.Equal(t, first, second)
+	autogold.Expect(`repository,repository_id,revision,commit,path,line,preview
+repo,1,dev,c,a.go,3,l2
+repo,1,main,c,a.go,6,l5
+repo,1,dev,c,b.go,1,l0
+repo,1,main,c,b.go,2,l1
+`).Equal(t, first)
+}
+
+func TestWriteSearchJobResults_Deduplicate(t *testing.T) {
+	match := func(path, commit, content string) string {
+		return fmt.Sprintf(`{"type":"content","path":%q,"repositoryID":1,"repository":"repo","commit":%q,"chunkMatches":[{"content":%q,"contentStart":{"line":0},"ranges":[{"start":{"line":0},"end":{"line":0}}]}]}`+"\n", path, commit, content)
+	}
+
+	// a.go is the same in both revisions, except for its line endings. b.go
+	// is only in dev.
+	mockStore, shards := setupResultsStore(t, map[string]string{
+		"7-1": match("a.go", "c1", "shared\nline"),
+		"7-2": match("a.go", "c2", "shared\r\nline") + match("b.go", "c2", "only dev"),
+	})
+	tasks := []types.SearchJobLog{
+		{ID: 1, RepoName: "repo", Revision: "main"},
+		{ID: 2, RepoName: "repo", Revision: "dev"},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatCSV, nil, true, &buf)
+		require.NoError(t, err)
+
+		// The row of the first revision is kept.
+		autogold.Expect(`repository,repository_id,revision,commit,path,line,preview,revisions
+repo,1,dev,c2,a.go,1,shared,dev main
+repo,1,dev,c2,b.go,1,only dev,dev
+`).Equal(t, buf.String())
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatJSONL, nil, true, &buf)
+		require.NoError(t, err)
+
+		type jsonlMatch struct {
+			Path      string   `json:"path"`
+			Revision  string   `json:"revision"`
+			Revisions []string `json:"revisions"`
+		}
+		var got []jsonlMatch
+		sc := bufio.NewScanner(&buf)
+		for sc.Scan() {
+			var m jsonlMatch
+			require.NoError(t, json.Unmarshal(sc.Bytes(), &m))
+			got = append(got, m)
+		}
+		require.Equal(t, []jsonlMatch{
+			{Path: "a.go", Revision: "dev", Revisions: []string{"dev", "main"}},
+			{Path: "b.go", Revision: "dev", Revisions: []string{"dev"}},
+		}, got)
+	})
+
+	t.Run("parquet", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatParquet, nil, true, &buf)
+		require.NoError(t, err)
+
+		var csvBuf bytes.Buffer
+		_, err = writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatCSV, nil, true, &csvBuf)
+		require.NoError(t, err)
+		want, err := csv.NewReader(&csvBuf).ReadAll()
+		require.NoError(t, err)
+
+		rows, _ := readParquetResults(t, buf.Bytes())
+		require.Equal(t, want, rows)
+	})
+}
+
+func TestWriteSearchJobResults_SpecialCharacters(t *testing.T) {
+	path := "dir, with commas/file\n\"name\".go"
+	content := "func a() {\n\treturn \"a,b\" // <a&b>\n}"
+
+	match, err := json.Marshal(map[string]any{
+		"type":       "content",
+		"path":       path,
+		"repository": "repo",
+		"commit":     "c1",
+		"chunkMatches": []map[string]any{{
+			"content": content,
+			"ranges":  []any{map[string]any{"start": map[string]any{"line": 1}}},
+		}},
+	})
+	require.NoError(t, err)
+
+	mockStore, shards := setupResultsStore(t, map[string]string{"7-1": string(match) + "\n"})
+	revision := `rev,"tricky"`
+	tasks := []types.SearchJobLog{{ID: 1, RepoName: "repo, with \"quotes\"", Revision: revision}}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatCSV, nil, false, &buf)
+		require.NoError(t, err)
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			types.ResultsColumns,
+			{"repo, with \"quotes\"", "", revision, "c1", path, "2", "\treturn \"a,b\" // <a&b>"},
+		}, records)
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatJSONL, nil, false, &buf)
+		require.NoError(t, err)
+
+		// Every match must be on a single line.
+		sc := bufio.NewScanner(&buf)
+		require.True(t, sc.Scan())
+		var got struct {
+			Path         string `json:"path"`
+			Revision     string `json:"revision"`
+			ChunkMatches []struct {
+				Content string `json:"content"`
+			} `json:"chunkMatches"`
+		}
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &got))
+		require.False(t, sc.Scan())
+
+		require.Equal(t, path, got.Path)
+		require.Equal(t, revision, got.Revision)
+		require.Len(t, got.ChunkMatches, 1)
+		require.Equal(t, content, got.ChunkMatches[0].Content)
+	})
+}
+
+func TestWriteSearchJobResults_Batches(t *testing.T) {
+	repoa := `{"type":"path","path":"a.go","repositoryID":1,"repository":"repoa","commit":"c1"}` + "\n"
+	repob := `{"type":"path","path":"b.go","repositoryID":2,"repository":"repob","commit":"c2"}` + "\n"
+	repoc := `{"type":"path","path":"c.go","repositoryID":3,"repository":"repoc","commit":"c3"}` + "\n"
+	repod := `{"type":"path","path":"d.go","repositoryID":4,"repository":"repod","commit":"c4"}` + "\n"
+
+	write := func(t *testing.T, blobs map[string]string, tasks []types.SearchJobLog) string {
+		t.Helper()
+		mockStore, shards := setupResultsStore(t, blobs)
+		batches := make(map[int64][]int64)
+		for _, task := range tasks {
+			if task.BatchID != 0 {
+				batches[task.BatchID] = append(batches[task.BatchID], task.ID)
+			}
+		}
+		mergeBatchShards(shards, batches)
+
+		var buf bytes.Buffer
+		_, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatCSV, nil, false, &buf)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	unbatched := write(t, map[string]string{
+		"7-10": repob,
+		"7-11": repoa,
+		"7-12": repoc,
+		"7-13": repod,
+	}, []types.SearchJobLog{
+		{ID: 10, RepoID: 2, RepoName: "repob", Revision: "main"},
+		{ID: 11, RepoID: 1, RepoName: "repoa", Revision: "main"},
+		{ID: 12, RepoID: 3, RepoName: "repoc", Revision: "main"},
+		{ID: 13, RepoID: 4, RepoName: "repod", Revision: "main"},
+	})
+
+	// Tasks 10 and 11 succeeded in the first attempt of batch 10, task 12 in
+	// the second one. Task 13 isn't batched.
+	batched := write(t, map[string]string{
+		"7-10": repob + repoa,
+		"7-12": repoc,
+		"7-13": repod,
+	}, []types.SearchJobLog{
+		{ID: 10, RepoID: 2, RepoName: "repob", Revision: "main", BatchID: 10},
+		{ID: 11, RepoID: 1, RepoName: "repoa", Revision: "main", BatchID: 10},
+		{ID: 12, RepoID: 3, RepoName: "repoc", Revision: "main", BatchID: 10},
+		{ID: 13, RepoID: 4, RepoName: "repod", Revision: "main"},
+	})
+
+	require.Equal(t, unbatched, batched)
+	require.Equal(t, 5, strings.Count(batched, "\n"), "the header and one row per repository")
+}
+
+func TestWriteSearchJobResults_NoResults(t *testing.T) {
+	mockStore, shards := setupResultsStore(t, map[string]string{})
+	tasks := []types.SearchJobLog{{ID: 1, RepoName: "repo", Revision: "main"}}
+
+	var buf bytes.Buffer
+	n, err := writeSearchJobResults(context.Background(), tasks, shards, mockStore, ResultFormatCSV, nil, false, &buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), n)
+
+	// The header is written even if there are no results.
+	require.Equal(t, "repository,repository_id,revision,commit,path,line,preview\n", buf.String())
+}
+
+func TestWriteSearchJobLogs(t *testing.T) {
+	finishedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	workerStartedAt := time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+	logs := []types.SearchJobLog{
+		{RepoName: "repo,a", Revision: `rev,"tricky"`, CommitID: "c1", State: types.JobStateFailed, FinishedAt: finishedAt, FailureMessage: "line 1\nline 2", WorkerHostname: "worker-0", WorkerStartedAt: workerStartedAt},
+		{RepoName: "repob", Revision: "main", State: types.JobStateQueued},
+	}
+
+	var buf bytes.Buffer
+	n, err := writeSearchJobLogs(func(f func(types.SearchJobLog) error) error {
+		for _, log := range logs {
+			if err := f(log); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, &buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), n)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		logsCSVHeader,
+		{"repo,a", `rev,"tricky"`, "c1", "NULL", finishedAt.Format(time.RFC3339), "failed", "line 1\nline 2", "worker-0", workerStartedAt.Format(time.RFC3339)},
+		{"repob", "main", "", "NULL", "NULL", "queued", "", "", "NULL"},
+	}, records)
+}
+
+func TestGroupResultKeys(t *testing.T) {
+	keys := []string{"3-10-2.gz", "3-10", "3-10-11.gz", "3-2", "3-", "3-2-x", "3-2-x.gz", "4-1"}
+
+	shards, err := groupResultKeys(iterator.From(keys), "3-")
+	require.NoError(t, err)
+
+	require.Equal(t, map[int64][]resultShard{
+		2: {{key: "3-2", shard: 1}},
+		10: {
+			{key: "3-10", shard: 1},
+			{key: "3-10-2.gz", shard: 2, compressed: true},
+			{key: "3-10-11.gz", shard: 11, compressed: true},
+		},
+	}, shards)
+}
+
+func TestGroupResultKeys_ResultsKeyPrefix(t *testing.T) {
+	keys := []string{
+		ResultsKeyPrefix(3, 1) + gzipKeySuffix,
+		ResultsKeyPrefix(3, 1) + "-2" + gzipKeySuffix,
+		ResultsKeyPrefix(3, 12) + gzipKeySuffix,
+		getSummaryKey(3),
+		getAggregatedResultsKey(3, ResultFormatCSV),
+	}
+
+	shards, err := groupResultKeys(iterator.From(keys), getPrefix(3))
+	require.NoError(t, err)
+
+	require.Equal(t, map[int64][]resultShard{
+		1: {
+			{key: "3-1.gz", shard: 1, compressed: true},
+			{key: "3-1-2.gz", shard: 2, compressed: true},
+		},
+		12: {{key: "3-12.gz", shard: 1, compressed: true}},
+	}, shards)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DefaultResultsURLExpiry is how long a presigned URL returned by
+// GetSearchJobResultsURL is valid if the caller doesn't ask for an expiry.
+const DefaultResultsURLExpiry = 15 * time.Minute
+
+// SearchJobResultsURL is a URL to download the results of a search job.
+type SearchJobResultsURL struct {
+	URL string
+
+	// Presigned is true if URL reads the results directly from the upload
+	// store without further authentication until ExpiresAt. Otherwise URL
+	// streams the results through the export API, which authenticates the
+	// request like any other API request.
+	Presigned bool
+	ExpiresAt time.Time
+}
+
+// GetSearchJobResultsURL returns a URL to download the results of job id in
+// format, so browsers don't have to download them through the frontend.
+//
+// Once the job finished, its results are written to a single object in the
+// upload store and the URL is presigned for it. It is valid for expiry, or
+// DefaultResultsURLExpiry if expiry is 0. If the job is still running or the
+// upload store can't presign URLs, for example the bundled blobstore, the URL
+// of the export API is returned instead.
+func (s *Service) GetSearchJobResultsURL(ctx context.Context, id int64, format ResultFormat, expiry time.Duration) (_ *SearchJobResultsURL, err error) {
+	ctx, _, endObservation := s.operations.getSearchJobResultsURL.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+		attribute.Stringer("format", format),
+		attribute.Stringer("expiry", expiry)))
+	defer endObservation(1, observation.Args{})
+
+	if expiry < 0 {
+		return nil, errors.New("expiry must not be negative")
+	}
+	if expiry == 0 {
+		expiry = DefaultResultsURLExpiry
+	}
+
+	// 🚨 SECURITY: only someone with access to the job may get a URL for its
+	// results. A presigned URL grants access to anyone who has it.
+	job, err := s.store.GetExhaustiveSearchJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden, err := s.checkRepoPermissions(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	// The aggregated results include all repositories, so results which leave
+	// some out are streamed through the export API, which checks the
+	// permissions again.
+	var resultsURL *SearchJobResultsURL
+	if len(hidden) == 0 {
+		resultsURL, err = s.presignResults(ctx, job, format, expiry)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resultsURL == nil {
+		u, err := url.JoinPath(conf.ExternalURL(), fmt.Sprintf("/.api/search/export/%d.%s", id, format))
+		if err != nil {
+			return nil, err
+		}
+		resultsURL = &SearchJobResultsURL{URL: u}
+	}
+
+	s.audit(ctx, "resultsURLIssued", job)
+
+	return resultsURL, nil
+}
+
+// presignResults returns a presigned URL for the aggregated results of job in
+// format. It returns nil if the results can't be presigned.
+func (s *Service) presignResults(ctx context.Context, job *types.ExhaustiveSearchJob, format ResultFormat, expiry time.Duration) (*SearchJobResultsURL, error) {
+	// The results of a running job still change, so we only aggregate them
+	// once it finished.
+	switch job.AggState {
+	case types.JobStateCompleted, types.JobStateFailed, types.JobStateCanceled:
+	default:
+		return nil, nil
+	}
+
+	key := getAggregatedResultsKey(job.ID, format)
+	expiresAt := s.clock.Now().Add(expiry)
+	u, err := uploadstore.PresignGet(ctx, s.uploadStore, key, expiry)
+	if err != nil {
+		if errors.Is(err, uploadstore.ErrPresignUnsupported) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := s.aggregateResults(ctx, job, format, key); err != nil {
+		return nil, errors.Wrap(err, "aggregating results")
+	}
+
+	return &SearchJobResultsURL{URL: u, Presigned: true, ExpiresAt: expiresAt}, nil
+}
+
+// getAggregatedResultsKey returns the key of the object with all results of
+// job id in format. It has the prefix of the job, so it is deleted with the
+// job, but groupResultKeys ignores it.
+//
+// The key includes types.ResultsSchemaVersion, so results aggregated with an
+// older schema are never handed out.
+func getAggregatedResultsKey(id int64, format ResultFormat) string {
+	return fmt.Sprintf("%s%sv%d.%s", getPrefix(id), aggregatedResultsKeyPrefix, types.ResultsSchemaVersion, format)
+}
+
+const aggregatedResultsKeyPrefix = "results."
+
+// aggregateResults writes the results of job in format to the object at key
+// unless it exists already.
+func (s *Service) aggregateResults(ctx context.Context, job *types.ExhaustiveSearchJob, format ResultFormat, key string) error {
+	exists, err := s.objectExists(ctx, key)
+	if err != nil || exists {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := s.writeResults(ctx, job, format, nil, pw)
+		pw.CloseWithError(err)
+	}()
+
+	_, err = s.uploadStore.Upload(ctx, key, pr)
+	// Stops writeResults if the upload failed.
+	pr.CloseWithError(err)
+	return err
+}
+
+// deleteAggregatedResults deletes the aggregated results of job id in all
+// formats and its summary, so they are written again with the current results.
+func (s *Service) deleteAggregatedResults(ctx context.Context, id int64) error {
+	for _, prefix := range []string{getPrefix(id) + aggregatedResultsKeyPrefix, getSummaryKey(id)} {
+		iter, err := s.uploadStore.List(ctx, prefix)
+		if err != nil {
+			return err
+		}
+		for iter.Next() {
+			if err := s.uploadStore.Delete(ctx, iter.Current()); err != nil {
+				return err
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
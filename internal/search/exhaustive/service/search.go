@@ -1,9 +1,9 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"fmt"
-	"io"
 	"strconv"
 	"strings"
 
@@ -80,19 +80,6 @@ type MatchWriter interface {
 	Write(match result.Match) error
 }
 
-// CSVWriter makes it, so we can avoid caring about search types and leave it
-// up to the search job to decide the shape of data.
-type CSVWriter interface {
-	// WriteHeader should be called first and only once.
-	WriteHeader(...string) error
-
-	// WriteRow should have the same number of values as WriteHeader and can be
-	// called zero or more times.
-	WriteRow(...string) error
-
-	io.Closer
-}
-
 // NewSearcherFake is a convenient working implementation of SearchQuery which
 // always will write results generated from the repoRevs. It expects a query
 // string which looks like
@@ -102,8 +89,10 @@ type CSVWriter interface {
 //	This is a space separated list of {repoid}@{revision}.
 //
 //	- RepositoryRevSpecs will return one RepositoryRevSpec per unique repository.
-//	- ResolveRepositoryRevSpec returns the repoRevs for that repository.
-//	- Search will write one result which is just the repo and revision.
+//	- ResolveRepositoryRevSpec returns the repoRevs for that repository. A
+//	  revision resolves to itself without a "refs/heads/" prefix, so
+//	  "rev1" and "refs/heads/rev1" resolve to the same commit.
+//	- Search will write one result which is just the repo and commit.
 func NewSearcherFake() NewSearcher {
 	return newSearcherFunc(fakeNewSearch)
 }
@@ -145,7 +134,7 @@ type searcherFake struct {
 
 func (s searcherFake) RepositoryRevSpecs(ctx context.Context) *iterator.Iterator[types.RepositoryRevSpecs] {
 	if err := isSameUser(ctx, s.userID); err != nil {
-		iterator.New(func() ([]types.RepositoryRevSpecs, error) {
+		return iterator.New(func() ([]types.RepositoryRevSpecs, error) {
 			return nil, err
 		})
 	}
@@ -170,6 +159,7 @@ func (s searcherFake) ResolveRepositoryRevSpec(ctx context.Context, repoRevSpec
 	var repoRevs []types.RepositoryRevision
 	for _, r := range s.repoRevs {
 		if r.RepositoryRevSpecs == repoRevSpec {
+			r.CommitID = api.CommitID(strings.TrimPrefix(r.Revision, "refs/heads/"))
 			repoRevs = append(repoRevs, r)
 		}
 	}
@@ -184,7 +174,7 @@ func (s searcherFake) Search(ctx context.Context, r types.RepositoryRevision, w
 	return w.Write(&result.FileMatch{
 		File: result.File{
 			Repo:     types2.MinimalRepo{ID: r.Repository, Name: "repo" + api.RepoName(strconv.Itoa(int(r.Repository)))},
-			CommitID: api.CommitID(r.Revision),
+			CommitID: cmp.Or(r.CommitID, api.CommitID(r.Revision)),
 			Path:     "path/to/file.go",
 		},
 	})
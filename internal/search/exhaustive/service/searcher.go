@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"sync"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/client"
@@ -70,7 +72,7 @@ type searchQuery struct {
 
 func (s searchQuery) RepositoryRevSpecs(ctx context.Context) *iterator.Iterator[types.RepositoryRevSpecs] {
 	if err := isSameUser(ctx, s.userID); err != nil {
-		iterator.New(func() ([]types.RepositoryRevSpecs, error) {
+		return iterator.New(func() ([]types.RepositoryRevSpecs, error) {
 			return nil, err
 		})
 	}
@@ -133,15 +135,31 @@ func (s searchQuery) ResolveRepositoryRevSpec(ctx context.Context, repoRevSpec t
 			return nil, errors.Errorf("ResolveRepositoryRevSpec returned a different repo (%d) to the input %v", repoRev.Repo.ID, repoRevSpec)
 		}
 		for _, rev := range repoRev.Revs {
+			commitID, err := s.resolveCommit(ctx, repoRev.Repo.Name, rev)
+			if err != nil {
+				return nil, err
+			}
 			repoRevs = append(repoRevs, types.RepositoryRevision{
 				RepositoryRevSpecs: repoRevSpec,
 				Revision:           rev,
+				CommitID:           commitID,
 			})
 		}
 	}
 	return repoRevs, nil
 }
 
+// resolveCommit returns the commit rev points to. A revision which does not
+// exist, such as HEAD of an empty repository, resolves to an empty commit ID,
+// so that its search reports what is wrong.
+func (s searchQuery) resolveCommit(ctx context.Context, repo api.RepoName, rev string) (api.CommitID, error) {
+	commitID, err := s.clients.Gitserver.ResolveRevision(ctx, repo, rev, gitserver.ResolveRevisionOptions{EnsureRevision: false})
+	if errors.HasType(err, &gitdomain.RevisionNotFoundError{}) || errors.HasType(err, &gitdomain.RepoNotExistError{}) {
+		return "", nil
+	}
+	return commitID, err
+}
+
 func (s searchQuery) toRepoRevSpecs(ctx context.Context, repoRevSpec types.RepositoryRevSpecs) (repos.RepoRevSpecs, error) {
 	repo, err := s.minimalRepo(ctx, repoRevSpec.Repository)
 	if err != nil {
@@ -173,9 +191,17 @@ func (s searchQuery) Search(ctx context.Context, repoRev types.RepositoryRevisio
 		return err
 	}
 
+	// We search the commit the revision resolved to when the job was
+	// expanded, so that all tasks of the job see the same state even if a
+	// branch moves while the job is running.
+	rev := repoRev.Revision
+	if repoRev.CommitID != "" {
+		rev = string(repoRev.CommitID)
+	}
+
 	job := s.exhaustive.Job(&search.RepositoryRevisions{
 		Repo: repo,
-		Revs: []string{repoRev.Revision},
+		Revs: []string{rev},
 	})
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -217,6 +243,10 @@ func (s searchQuery) Search(ctx context.Context, repoRev types.RepositoryRevisio
 	return err
 }
 
+// minimalRepo looks up repoID with the repo permissions of the actor of ctx.
+// The workers run every task as the initiator of the job, so a task of a
+// repository the initiator lost access to since the job was created, for
+// example before the job was resumed, fails with a RepoNotAccessibleError.
 func (s searchQuery) minimalRepo(ctx context.Context, repoID api.RepoID) (sgtypes.MinimalRepo, error) {
 	minimalRepos, err := s.clients.DB.Repos().ListMinimalRepos(ctx, database.ReposListOptions{
 		IDs: []api.RepoID{repoID},
@@ -224,12 +254,29 @@ func (s searchQuery) minimalRepo(ctx context.Context, repoID api.RepoID) (sgtype
 	if err != nil {
 		return sgtypes.MinimalRepo{}, err
 	}
+	if len(minimalRepos) == 0 {
+		return sgtypes.MinimalRepo{}, &RepoNotAccessibleError{RepoID: repoID}
+	}
 	if len(minimalRepos) != 1 {
 		return sgtypes.MinimalRepo{}, errors.Errorf("looking up repo %d found %d entries", repoID, len(minimalRepos))
 	}
 	return minimalRepos[0], nil
 }
 
+// RepoNotAccessibleError is returned if the initiator of a search job can't
+// see a repository of the job, either because it was deleted or because the
+// initiator lost access to it. Retrying doesn't help.
+type RepoNotAccessibleError struct {
+	RepoID api.RepoID
+}
+
+func (e *RepoNotAccessibleError) Error() string {
+	return fmt.Sprintf("repository %d does not exist or is not accessible to the initiator of the search job", e.RepoID)
+}
+
+func (e *RepoNotAccessibleError) IsRepoDenied() bool { return true }
+func (e *RepoNotAccessibleError) NonRetryable() bool { return true }
+
 func isReposMissingError(err error) bool {
 	var m repos.MissingRepoRevsError
 	return errors.Is(err, repos.ErrNoResolvedRepos) || errors.HasType(err, &m)
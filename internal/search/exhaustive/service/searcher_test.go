@@ -20,6 +20,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
@@ -171,6 +172,67 @@ func TestFromSearchClient(t *testing.T) {
 	})
 }
 
+func TestFromSearchClient_RepoNotAccessible(t *testing.T) {
+	repoMocks := []repoMock{{
+		ID:   1,
+		Name: "foo1",
+		Branches: map[string]string{
+			"HEAD": "commitfoo0",
+		},
+	}}
+
+	ctx := featureflag.WithFlags(context.Background(), featureflag.NewMemoryStore(nil, nil, nil))
+	userID := int32(1)
+	ctx = actor.WithActor(ctx, actor.FromMockUser(userID))
+
+	// The repo store filters by the permissions of the actor, so a repo the
+	// user lost access to isn't returned anymore.
+	accessible := true
+	allRepos := mockRepoStore(repoMocks)
+	repos := dbmocks.NewMockRepoStore()
+	repos.ListMinimalReposFunc.SetDefaultHook(func(ctx context.Context, opts database.ReposListOptions) ([]types.MinimalRepo, error) {
+		if !accessible {
+			return nil, nil
+		}
+		return allRepos.ListMinimalRepos(ctx, opts)
+	})
+	db := dbmocks.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	newSearcher := FromSearchClient(client.Mocked(job.RuntimeClients{
+		Logger:       logtest.Scoped(t),
+		DB:           db,
+		Zoekt:        mockZoekt(repoMocks),
+		Gitserver:    mockGitserver(repoMocks),
+		SearcherURLs: mockSearcher(t, repoMocks),
+	}))
+
+	searcher, err := newSearcher.NewSearch(ctx, userID, "repo:foo content")
+	require.NoError(t, err)
+
+	refSpecs, err := iterator.Collect(searcher.RepositoryRevSpecs(ctx))
+	require.NoError(t, err)
+	require.Equal(t, "RepositoryRevSpec{1@HEAD}", joinStringer(refSpecs))
+
+	repoRevs, err := searcher.ResolveRepositoryRevSpec(ctx, refSpecs[0])
+	require.NoError(t, err)
+	require.Len(t, repoRevs, 1)
+
+	accessible = false
+
+	_, err = searcher.ResolveRepositoryRevSpec(ctx, refSpecs[0])
+	require.True(t, errcode.IsRepoDenied(err), "unexpected error %v", err)
+	require.True(t, errcode.IsNonRetryable(err), "unexpected error %v", err)
+
+	err = searcher.Search(ctx, repoRevs[0], MatchJSONWriter{w: newBufferedWriter(1024, func([]byte) error { return nil })})
+	require.True(t, errcode.IsRepoDenied(err), "unexpected error %v", err)
+
+	// Only the initiator of a job may expand it.
+	otherCtx := actor.WithActor(ctx, actor.FromMockUser(userID+1))
+	_, err = iterator.Collect(searcher.RepositoryRevSpecs(otherCtx))
+	require.Error(t, err)
+}
+
 type repoMock struct {
 	ID       int
 	Name     string
@@ -301,7 +363,13 @@ func mockSearcher(t *testing.T, repoMocks []repoMock) *endpoint.Map {
 		for _, r := range repoMocks {
 			if api.RepoID(r.ID) == repo.ID {
 				found = true
+				// Search jobs search the commit a branch resolved to.
 				commit, ok := r.Branches[rev]
+				for _, c := range r.Branches {
+					if c == rev {
+						commit, ok = c, true
+					}
+				}
 				if !ok {
 					return false, &gitdomain.RevisionNotFoundError{Spec: rev}
 				}
@@ -367,7 +435,7 @@ func testNewSearcher(t *testing.T, ctx context.Context, newSearcher NewSearcher,
 		return err
 	}
 
-	matchWriter := MatchJSONWriter{bw}
+	matchWriter := MatchJSONWriter{w: bw}
 
 	// Test Search
 	for _, repoRev := range repoRevs {
@@ -1,26 +1,33 @@
 package service
 
 import (
-	"bufio"
 	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
+	"math"
+	"math/rand/v2"
 	"sync"
 	"time"
 
+	"github.com/derision-test/glock"
 	"github.com/sourcegraph/log"
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/internal/search/limits"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
-	"github.com/sourcegraph/sourcegraph/lib/iterator"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 // New returns a Service.
@@ -30,7 +37,13 @@ func New(
 	uploadStore uploadstore.Store,
 	newSearcher NewSearcher,
 ) *Service {
-	logger := log.Scoped("searchjobs.Service")
+	logger := observationCtx.Logger.Scoped("searchjobs.Service")
+
+	// Tests of the validation create the service without a store.
+	var clock glock.Clock = glock.NewRealClock()
+	if store != nil {
+		clock = store.Clock()
+	}
 
 	svc := &Service{
 		logger:      logger,
@@ -38,6 +51,7 @@ func New(
 		uploadStore: uploadStore,
 		newSearcher: newSearcher,
 		operations:  newOperations(observationCtx),
+		clock:       clock,
 	}
 
 	return svc
@@ -49,6 +63,10 @@ type Service struct {
 	uploadStore uploadstore.Store
 	newSearcher NewSearcher
 	operations  *operations
+
+	// clock is the clock of the store, so that the times the service
+	// computes, like deadlines, line up with the timestamps the store writes.
+	clock glock.Clock
 }
 
 func opAttrs(attrs ...attribute.KeyValue) observation.Args {
@@ -56,12 +74,34 @@ func opAttrs(attrs ...attribute.KeyValue) observation.Args {
 }
 
 type operations struct {
-	createSearchJob          *observation.Operation
-	getSearchJob             *observation.Operation
-	deleteSearchJob          *observation.Operation
-	listSearchJobs           *observation.Operation
-	cancelSearchJob          *observation.Operation
-	getAggregateRepoRevState *observation.Operation
+	createSearchJob               *observation.Operation
+	duplicateSearchJob            *observation.Operation
+	updateSearchJobMetadata       *observation.Operation
+	estimateSearchJob             *observation.Operation
+	getSearchJob                  *observation.Operation
+	deleteSearchJob               *observation.Operation
+	deleteExpiredSearchJobs       *observation.Operation
+	releaseExpiredIdempotencyKeys *observation.Operation
+	abortIncompleteUploads        *observation.Operation
+	listSearchJobs                *observation.Operation
+	countSearchJobs               *observation.Operation
+	cancelSearchJob               *observation.Operation
+	resumeSearchJob               *observation.Operation
+	retryFailedTasks              *observation.Operation
+	getAggregateRepoRevState      *observation.Operation
+	jobProgress                   *observation.Operation
+	globalBacklog                 *observation.Operation
+	exportVolumeByUser            *observation.Operation
+	usageStats                    *observation.Operation
+	listFailedTasks               *observation.Operation
+	listTasks                     *observation.Operation
+	getTask                       *observation.Operation
+	forceFailTask                 *observation.Operation
+	forceRequeueTask              *observation.Operation
+	getSearchJobLogs              *observation.Operation
+	getSearchJobResultsURL        *observation.Operation
+	writeSearchJobSummary         *observation.Operation
+	getSearchJobSummary           *observation.Operation
 
 	getSearchJobResultsWriterTo operationWithWriterTo
 	getSearchJobLogsWriterTo    operationWithWriterTo
@@ -102,12 +142,34 @@ func newOperations(observationCtx *observation.Context) *operations {
 		}
 
 		singletonOperations = &operations{
-			createSearchJob:          op("CreateSearchJob"),
-			getSearchJob:             op("GetSearchJob"),
-			deleteSearchJob:          op("DeleteSearchJob"),
-			listSearchJobs:           op("ListSearchJobs"),
-			cancelSearchJob:          op("CancelSearchJob"),
-			getAggregateRepoRevState: op("GetAggregateRepoRevState"),
+			createSearchJob:               op("CreateSearchJob"),
+			duplicateSearchJob:            op("DuplicateSearchJob"),
+			updateSearchJobMetadata:       op("UpdateSearchJobMetadata"),
+			estimateSearchJob:             op("EstimateSearchJob"),
+			getSearchJob:                  op("GetSearchJob"),
+			deleteSearchJob:               op("DeleteSearchJob"),
+			deleteExpiredSearchJobs:       op("DeleteExpiredSearchJobs"),
+			releaseExpiredIdempotencyKeys: op("ReleaseExpiredIdempotencyKeys"),
+			abortIncompleteUploads:        op("AbortIncompleteUploads"),
+			listSearchJobs:                op("ListSearchJobs"),
+			countSearchJobs:               op("CountSearchJobs"),
+			cancelSearchJob:               op("CancelSearchJob"),
+			resumeSearchJob:               op("ResumeSearchJob"),
+			retryFailedTasks:              op("RetryFailedTasks"),
+			getAggregateRepoRevState:      op("GetAggregateRepoRevState"),
+			jobProgress:                   op("JobProgress"),
+			globalBacklog:                 op("GlobalBacklog"),
+			exportVolumeByUser:            op("ExportVolumeByUser"),
+			usageStats:                    op("UsageStats"),
+			listFailedTasks:               op("ListFailedTasks"),
+			listTasks:                     op("ListTasks"),
+			getTask:                       op("GetTask"),
+			forceFailTask:                 op("ForceFailTask"),
+			forceRequeueTask:              op("ForceRequeueTask"),
+			getSearchJobLogs:              op("GetSearchJobLogs"),
+			getSearchJobResultsURL:        op("GetSearchJobResultsURL"),
+			writeSearchJobSummary:         op("WriteSearchJobSummary"),
+			getSearchJobSummary:           op("GetSearchJobSummary"),
 
 			getSearchJobResultsWriterTo: operationWithWriterTo{
 				get:      op("GetSearchJobResultsWriterTo"),
@@ -127,13 +189,84 @@ func (s *Service) ValidateSearchJob(ctx context.Context, query string) error {
 	if !actor.IsAuthenticated() {
 		return errors.New("search jobs can only be validated by an authenticated user")
 	}
+	if err := validateQuery(query); err != nil {
+		return err
+	}
 	_, err := s.newSearcher.NewSearch(ctx, actor.UID, query)
 	return err
 }
 
-func (s *Service) CreateSearchJob(ctx context.Context, query string) (_ *types.ExhaustiveSearchJob, err error) {
+// CreateSearchJobOpts are the options of a new search job.
+type CreateSearchJobOpts struct {
+	// MaxResults stops the job once it wrote this many results. Work which is
+	// in progress when the limit is reached is discarded, so a job may write
+	// more results than this. 0 means there is no limit.
+	MaxResults int
+
+	// MaxDuration stops the job once it ran this long after it was created.
+	// Repositories and revisions which weren't searched by then are skipped,
+	// so the results are partial. 0 means there is no deadline.
+	MaxDuration time.Duration
+
+	// WebhookURL is called once the job finished, in addition to emailing the
+	// initiator. The request body is signed with WebhookSecret.
+	WebhookURL    string
+	WebhookSecret string
+
+	// Priority decides in which order the workers pick up the job relative to
+	// the jobs of other users. Only site admins may create jobs with
+	// types.JobPriorityHigh.
+	Priority types.JobPriority
+
+	// SampleRate makes the job search a random sample of the revisions of its
+	// query, keeping each with this probability, for example to explore the
+	// results of a huge query. It must be greater than 0 and at most 1. 0
+	// searches every revision, like 1.
+	//
+	// SampleSeed seeds the sample, so a job with the same seed searches the
+	// same revisions. If it is 0, a random seed is chosen.
+	SampleRate float64
+	SampleSeed int64
+
+	// DeduplicateResults makes the exports of the job report a match which
+	// was found in several revisions of a repository once, see
+	// types.ExhaustiveSearchJob.DeduplicateResults.
+	DeduplicateResults bool
+
+	// Description and Labels are the metadata of the job, see
+	// types.ExhaustiveSearchJob.Labels. They can be changed later with
+	// UpdateSearchJobMetadata.
+	Description string
+	Labels      map[string]string
+
+	// IdempotencyKey makes retries of a request create the job once. If the
+	// actor created a job with the same key and query within the idempotency
+	// window of the site configuration, that job is returned instead of
+	// creating another one. The same key with a different query is an
+	// ErrIdempotencyKeyConflict. Keys can be used again once the window
+	// passed.
+	IdempotencyKey string
+
+	// Scheduled is set by callers which create the job on a schedule on behalf
+	// of the actor, instead of on a request of the actor. It decides the
+	// CreationSource of the job.
+	Scheduled bool
+
+	// createdFromJobID is set by DuplicateSearchJob to the ID of the job it
+	// duplicates.
+	createdFromJobID int64
+}
+
+func (s *Service) CreateSearchJob(ctx context.Context, query string, opts CreateSearchJobOpts) (job *types.ExhaustiveSearchJob, err error) {
 	ctx, _, endObservation := s.operations.createSearchJob.With(ctx, &err, opAttrs(
 		attribute.String("query", query),
+		attribute.Int("maxResults", opts.MaxResults),
+		attribute.Stringer("maxDuration", opts.MaxDuration),
+		attribute.Stringer("priority", opts.Priority),
+		attribute.Float64("sampleRate", opts.SampleRate),
+		attribute.Bool("deduplicateResults", opts.DeduplicateResults),
+		attribute.Int("labels", len(opts.Labels)),
+		attribute.Bool("idempotencyKey", opts.IdempotencyKey != ""),
 	))
 	defer endObservation(1, observation.Args{})
 
@@ -146,57 +279,525 @@ func (s *Service) CreateSearchJob(ctx context.Context, query string) (_ *types.E
 		return nil, errors.New("search jobs can only be created by an authenticated user")
 	}
 
+	if opts.MaxResults < 0 {
+		return nil, errors.New("the result limit of a search job must not be negative")
+	}
+
+	if opts.MaxDuration < 0 {
+		return nil, errors.New("the maximum duration of a search job must not be negative")
+	}
+	var deadline time.Time
+	if opts.MaxDuration > 0 {
+		deadline = s.clock.Now().Add(opts.MaxDuration)
+	}
+
+	switch opts.Priority {
+	case types.JobPriorityLow, types.JobPriorityNormal, types.JobPriorityHigh:
+	default:
+		return nil, errors.Errorf("unknown search job priority %d", opts.Priority)
+	}
+
+	if opts.SampleRate < 0 || opts.SampleRate > 1 || math.IsNaN(opts.SampleRate) {
+		return nil, errors.New("the sample rate of a search job must be greater than 0 and at most 1")
+	}
+	var sampleRate float64
+	var sampleSeed int64
+	if opts.SampleRate > 0 && opts.SampleRate < 1 {
+		sampleRate, sampleSeed = opts.SampleRate, opts.SampleSeed
+		for sampleSeed == 0 {
+			sampleSeed = rand.Int64()
+		}
+	}
+
+	if err := validateMetadata(opts.Description, opts.Labels); err != nil {
+		return nil, err
+	}
+
+	if err := validateIdempotencyKey(opts.IdempotencyKey); err != nil {
+		return nil, err
+	}
+
+	if opts.WebhookURL != "" {
+		if err := outbound.CheckURL(opts.WebhookURL); err != nil {
+			return nil, err
+		}
+		if opts.WebhookSecret == "" {
+			return nil, errors.New("a webhook of a search job needs a secret to sign its requests")
+		}
+	}
+
 	// Validate query
 	err = s.ValidateSearchJob(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
+	// A retry of a request returns the job the request created.
+	if opts.IdempotencyKey != "" {
+		existing, err := s.getSearchJobByIdempotencyKey(ctx, actor.UID, opts.IdempotencyKey, query)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+
+	// This runs after the transaction committed.
+	defer func() {
+		if err == nil {
+			s.audit(ctx, "created", job)
+		}
+	}()
+
 	tx, err := s.store.Transact(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { err = tx.Done(err) }()
 
+	if err := checkQuota(ctx, tx, actor.UID); err != nil {
+		return nil, err
+	}
+
 	// XXX(keegancsmith) this API for creating seems easy to mess up since the
 	// ExhaustiveSearchJob type has lots of fields, but reading the store
-	// implementation only two fields are read.
+	// implementation only six fields are read.
 	jobID, err := tx.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{
-		InitiatorID: actor.UID,
-		Query:       query,
+		InitiatorID:        actor.UID,
+		Query:              query,
+		MaxResults:         opts.MaxResults,
+		Priority:           opts.Priority,
+		CreatedFromJobID:   opts.createdFromJobID,
+		Deadline:           deadline,
+		CreationSource:     creationSource(ctx, opts.Scheduled),
+		SampleRate:         sampleRate,
+		SampleSeed:         sampleSeed,
+		DeduplicateResults: opts.DeduplicateResults,
+		Description:        opts.Description,
+		Labels:             opts.Labels,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	if opts.WebhookURL != "" {
+		err = tx.SetExhaustiveSearchJobWebhook(ctx, jobID, opts.WebhookURL, opts.WebhookSecret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.IdempotencyKey != "" {
+		// A concurrent request with the same key may have created a job since
+		// we looked, in which case the unique index rejects the key.
+		window := time.Duration(limits.SearchLimits(conf.Get()).SearchJobIdempotencyWindowSeconds) * time.Second
+		err = tx.SetIdempotencyKey(ctx, jobID, opts.IdempotencyKey, window)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return tx.GetExhaustiveSearchJob(ctx, jobID)
 }
 
+// ErrIdempotencyKeyConflict is returned by CreateSearchJob if the idempotency
+// key was used for a job with a different query.
+var ErrIdempotencyKeyConflict = errors.New("the idempotency key was already used for a search job with a different query")
+
+// getSearchJobByIdempotencyKey returns the job of user initiatorID which holds
+// key, or nil if there is none. It returns ErrIdempotencyKeyConflict if the
+// job has a different query.
+func (s *Service) getSearchJobByIdempotencyKey(ctx context.Context, initiatorID int32, key, query string) (*types.ExhaustiveSearchJob, error) {
+	id, existingQuery, err := s.store.GetSearchJobByIdempotencyKey(ctx, initiatorID, key)
+	if errors.Is(err, store.ErrNoResults) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if existingQuery != query {
+		return nil, ErrIdempotencyKeyConflict
+	}
+	// Retries usually follow the request which created the job closely, so a
+	// replica may not have the job yet.
+	return s.store.GetExhaustiveSearchJob(store.PinPrimary(ctx), id)
+}
+
+// creationSource returns where a job created by the actor of ctx comes from.
+// Only the web app authenticates with a session cookie.
+func creationSource(ctx context.Context, scheduled bool) types.JobCreationSource {
+	switch {
+	case scheduled:
+		return types.JobCreationSourceScheduled
+	case actor.FromContext(ctx).FromSessionCookie:
+		return types.JobCreationSourceWeb
+	default:
+		return types.JobCreationSourceAPI
+	}
+}
+
+// DuplicateSearchJob creates a new search job owned by the actor with the
+// query and the options of job id. The actor must have access to job id. The
+// repositories and revisions are resolved again, so the new job searches the
+// repositories which match the query now. The webhook of job id isn't copied,
+// since its secret belongs to the initiator of job id. A sampled job keeps its
+// seed, so the new job searches the same sample of the revisions which didn't
+// change.
+func (s *Service) DuplicateSearchJob(ctx context.Context, id int64) (_ *types.ExhaustiveSearchJob, err error) {
+	ctx, _, endObservation := s.operations.duplicateSearchJob.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: GetSearchJob checks that the actor has access to job id.
+	job, err := s.GetSearchJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := CreateSearchJobOpts{
+		MaxResults:         job.MaxResults,
+		Priority:           job.Priority,
+		SampleRate:         job.SampleRate,
+		SampleSeed:         job.SampleSeed,
+		DeduplicateResults: job.DeduplicateResults,
+		Description:        job.Description,
+		Labels:             job.Labels,
+		createdFromJobID:   job.ID,
+	}
+	// The new job gets as much time as job id had.
+	if !job.Deadline.IsZero() {
+		opts.MaxDuration = job.Deadline.Sub(job.CreatedAt)
+	}
+
+	return s.CreateSearchJob(ctx, job.Query, opts)
+}
+
+// UpdateSearchJobMetadata replaces the description and the labels of job id.
+// Only the initiator of the job may change them.
+func (s *Service) UpdateSearchJobMetadata(ctx context.Context, id int64, description string, labels map[string]string) (_ *types.ExhaustiveSearchJob, err error) {
+	ctx, _, endObservation := s.operations.updateSearchJobMetadata.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+		attribute.Int("labels", len(labels)),
+	))
+	defer endObservation(1, observation.Args{})
+
+	if err := validateMetadata(description, labels); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// 🚨 SECURITY: UpdateExhaustiveSearchJobMetadata checks that the actor is
+	// the initiator of the job.
+	if err := tx.UpdateExhaustiveSearchJobMetadata(ctx, id, description, labels); err != nil {
+		if errors.Is(err, store.ErrNoResults) {
+			return nil, &SearchJobNotFoundError{ID: id}
+		}
+		return nil, err
+	}
+
+	return tx.GetExhaustiveSearchJob(ctx, id)
+}
+
+// QuotaError is returned by CreateSearchJob if the user or the instance
+// already runs as many search jobs as the site configuration allows.
+type QuotaError struct {
+	// Limit is the number of search jobs which may run at once.
+	Limit int
+
+	// PerUser is true if the limit of the user was reached, false if the
+	// limit of the instance was reached.
+	PerUser bool
+}
+
+func (e *QuotaError) Error() string {
+	if e.PerUser {
+		return fmt.Sprintf("you can't run more than %d search jobs at once, wait for one of them to finish or cancel it", e.Limit)
+	}
+	return fmt.Sprintf("the instance can't run more than %d search jobs at once, try again once other search jobs finished", e.Limit)
+}
+
+func (e *QuotaError) Extensions() map[string]any {
+	return map[string]any{"code": "ErrSearchJobQuotaExceeded", "limit": e.Limit, "perUser": e.PerUser}
+}
+
+// checkQuota returns a *QuotaError if user can't start another search job
+// because of the "search.limits" in the site configuration. Site admins are
+// only limited by the limit of the instance.
+//
+// Two jobs created at the same time may both pass the check, so the limits can
+// be exceeded by a few jobs.
+func checkQuota(ctx context.Context, s *store.Store, userID int32) error {
+	searchLimits := limits.SearchLimits(conf.Get())
+
+	if maxPerUser := searchLimits.MaxActiveSearchJobsPerUser; maxPerUser > 0 {
+		isAdmin, err := s.CurrentUserIsSiteAdmin(ctx)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			active, err := s.CountActiveSearchJobs(ctx, userID)
+			if err != nil {
+				return err
+			}
+			if active >= maxPerUser {
+				return &QuotaError{Limit: maxPerUser, PerUser: true}
+			}
+		}
+	}
+
+	if maxActive := searchLimits.MaxActiveSearchJobs; maxActive > 0 {
+		active, err := s.CountActiveSearchJobs(ctx, 0)
+		if err != nil {
+			return err
+		}
+		if active >= maxActive {
+			return &QuotaError{Limit: maxActive}
+		}
+	}
+
+	return nil
+}
+
+// ErrSearchJobFinished is returned by CancelSearchJob if the job already
+// completed or failed.
+var ErrSearchJobFinished = errors.New("search job has already finished")
+
+// SearchJobNotFoundError is returned if a search job doesn't exist.
+type SearchJobNotFoundError struct {
+	ID int64
+}
+
+func (e *SearchJobNotFoundError) Error() string {
+	return fmt.Sprintf("search job %d not found", e.ID)
+}
+
+func (e *SearchJobNotFoundError) NotFound() bool {
+	return true
+}
+
+// CancelSearchJob cancels job id. Queued tasks are canceled right away, the
+// workers stop tasks in progress the next time they heartbeat. Canceling a
+// canceled job is a no-op.
 func (s *Service) CancelSearchJob(ctx context.Context, id int64) (err error) {
 	ctx, _, endObservation := s.operations.cancelSearchJob.With(ctx, &err, opAttrs(
 		attribute.Int64("id", id),
 	))
 	defer endObservation(1, observation.Args{})
 
+	// canceledJob is set once the job is canceled. This runs after the
+	// transaction committed.
+	var canceledJob *types.ExhaustiveSearchJob
+	defer func() {
+		if err == nil && canceledJob != nil {
+			s.audit(ctx, "canceled", canceledJob)
+		}
+	}()
+
 	tx, err := s.store.Transact(ctx)
 	if err != nil {
 		return err
 	}
 	defer func() { err = tx.Done(err) }()
 
-	_, err = tx.CancelSearchJob(ctx, id)
+	// 🚨 SECURITY: CancelSearchJob checks that the user may access the job.
+	res, err := tx.CancelSearchJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNoResults) {
+			return &SearchJobNotFoundError{ID: id}
+		}
+		return err
+	}
+
+	switch res.PriorState {
+	case types.JobStateCanceled:
+		return nil
+	case types.JobStateCompleted, types.JobStateFailed:
+		return ErrSearchJobFinished
+	}
+
+	// The job is canceled as soon as the transaction commits, even if workers
+	// still have to stop its tasks in progress.
+	_, err = tx.EnqueueSearchJobNotifications(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	canceledJob, err = tx.GetExhaustiveSearchJob(ctx, id)
 	return err
 }
 
+// ErrSearchJobNotCanceled is returned by ResumeSearchJob if the job wasn't
+// canceled.
+var ErrSearchJobNotCanceled = errors.New("only canceled search jobs can be resumed")
+
+// ErrSearchJobStopping is returned by ResumeSearchJob if the workers are still
+// stopping the tasks of the canceled job.
+var ErrSearchJobStopping = errors.New("search job is still stopping, try again later")
+
+// ResumeSearchJob continues the canceled job id where it stopped. The tasks
+// which were canceled are queued again, completed tasks and their results are
+// kept. Like a new job, the resumed job counts against the quota of its
+// initiator.
+func (s *Service) ResumeSearchJob(ctx context.Context, id int64) (err error) {
+	ctx, _, endObservation := s.operations.resumeSearchJob.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// resumedJob is set once the job is resumed. This runs after the
+	// transaction committed.
+	var resumedJob *types.ExhaustiveSearchJob
+	defer func() {
+		if err == nil && resumedJob != nil {
+			s.audit(ctx, "resumed", resumedJob)
+		}
+	}()
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// 🚨 SECURITY: LockExhaustiveSearchJob checks that the user may access the job.
+	//
+	// The lock keeps DeleteSearchJob from deleting the job while we resume it.
+	if err := tx.LockExhaustiveSearchJob(ctx, id); err != nil {
+		return err
+	}
+	job, err := tx.GetExhaustiveSearchJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !job.Cancel {
+		return ErrSearchJobNotCanceled
+	}
+
+	// A task which is still processing would be marked failed once its worker
+	// stopped, after we requeued the other tasks.
+	progress, err := tx.GetSearchJobProgress(ctx, id)
+	if err != nil {
+		return err
+	}
+	if progress.Remaining > 0 {
+		return ErrSearchJobStopping
+	}
+
+	if err := checkQuota(ctx, tx, job.InitiatorID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ResumeSearchJob(ctx, id); err != nil {
+		return err
+	}
+
+	// The aggregated results are missing the results of the resumed tasks.
+	if err := s.deleteAggregatedResults(ctx, id); err != nil {
+		return err
+	}
+
+	resumedJob = job
+
+	return nil
+}
+
+// RetryFailedTasks requeues the failed tasks of search job id and returns the
+// number of tasks requeued. Completed tasks are not touched, so only the failed
+// repositories and revisions are searched again.
+func (s *Service) RetryFailedTasks(ctx context.Context, id int64) (_ int, err error) {
+	ctx, _, endObservation := s.operations.retryFailedTasks.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// The lock keeps DeleteSearchJob from deleting the job while we retry its
+	// tasks.
+	if err := tx.LockExhaustiveSearchJob(ctx, id); err != nil {
+		return 0, err
+	}
+
+	n, err := tx.RetryFailedSearchJobTasks(ctx, id)
+	if err != nil || n == 0 {
+		return n, err
+	}
+
+	// The aggregated results are missing the results of the retried tasks.
+	return n, s.deleteAggregatedResults(ctx, id)
+}
+
 func (s *Service) GetSearchJob(ctx context.Context, id int64) (_ *types.ExhaustiveSearchJob, err error) {
 	ctx, _, endObservation := s.operations.getSearchJob.With(ctx, &err, opAttrs(
 		attribute.Int64("id", id),
 	))
 	defer endObservation(1, observation.Args{})
 
-	return s.store.GetExhaustiveSearchJob(ctx, id)
+	job, err := s.store.GetExhaustiveSearchJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditAccess(ctx, "viewed", job)
+
+	return job, nil
+}
+
+// auditAccess writes an audit log entry if the actor acted on the search job
+// of another user. Only site admins can do that.
+func (s *Service) auditAccess(ctx context.Context, action string, job *types.ExhaustiveSearchJob) {
+	a := actor.FromContext(ctx)
+	if a.IsInternal() || a.UID == job.InitiatorID {
+		return
+	}
+
+	s.audit(ctx, action, job)
+}
+
+// audit writes an audit log entry for action on job. The lifecycle of a job is
+// always audited, reads only by auditAccess. The query is replaced with
+// "REDACTED" if "log.auditLog.redactSearchJobQueries" is set.
+//
+// audit.Log only writes a log entry, so auditing never fails the operation.
+func (s *Service) audit(ctx context.Context, action string, job *types.ExhaustiveSearchJob) {
+	a := actor.FromContext(ctx)
+
+	query := job.Query
+	if audit.IsEnabled(conf.SiteConfig(), audit.RedactSearchJobQueries) {
+		query = "REDACTED"
+	}
+
+	audit.Log(ctx, s.logger, audit.Record{
+		Entity: "search job",
+		Action: action,
+		Fields: []log.Field{
+			log.Int64("id", job.ID),
+			log.Int32("initiatorID", job.InitiatorID),
+			log.String("initiatorUsername", job.InitiatorUsername),
+			log.String("creationSource", string(job.CreationSource)),
+			log.String("query", query),
+			log.Bool("adminAccess", !a.IsInternal() && a.UID != job.InitiatorID),
+		},
+	})
 }
 
+// MaxSearchJobsPageSize is the maximum number of jobs ListSearchJobs returns
+// at once.
+const MaxSearchJobsPageSize = 100
+
+// ListSearchJobs returns a page of the jobs matching args. If args has no
+// pagination, the first MaxSearchJobsPageSize jobs ordered by ID are returned.
+//
+// Callers may ask for one more job than MaxSearchJobsPageSize to find out
+// whether there is another page.
 func (s *Service) ListSearchJobs(ctx context.Context, args store.ListArgs) (jobs []*types.ExhaustiveSearchJob, err error) {
 	ctx, _, endObservation := s.operations.listSearchJobs.With(ctx, &err, observation.Args{})
 	defer func() {
@@ -205,9 +806,36 @@ func (s *Service) ListSearchJobs(ctx context.Context, args store.ListArgs) (jobs
 		))
 	}()
 
+	p := database.PaginationArgs{Ascending: true}
+	if args.PaginationArgs != nil {
+		p = *args.PaginationArgs
+	}
+	if p.First == nil && p.Last == nil {
+		p.First = pointers.Ptr(MaxSearchJobsPageSize)
+	}
+	for _, limit := range []*int{p.First, p.Last} {
+		if limit != nil && *limit > MaxSearchJobsPageSize+1 {
+			return nil, errors.Newf("cannot list more than %d search jobs at once", MaxSearchJobsPageSize)
+		}
+	}
+	args.PaginationArgs = &p
+
 	return s.store.ListExhaustiveSearchJobs(ctx, args)
 }
 
+// CountSearchJobs returns the number of jobs matching args, ignoring
+// args.PaginationArgs.
+func (s *Service) CountSearchJobs(ctx context.Context, args store.ListArgs) (count int, err error) {
+	ctx, _, endObservation := s.operations.countSearchJobs.With(ctx, &err, observation.Args{})
+	defer func() {
+		endObservation(1, opAttrs(
+			attribute.Int("count", count),
+		))
+	}()
+
+	return s.store.CountExhaustiveSearchJobs(ctx, args)
+}
+
 // GetSearchJobLogsWriterTo returns a WriterTo which can be called once to
 // write the logs for job id. Note: ctx is used by WriterTo.
 //
@@ -224,8 +852,6 @@ func (s *Service) GetSearchJobLogsWriterTo(parentCtx context.Context, id int64)
 		return nil, err
 	}
 
-	iter := s.getJobLogsIter(ctx, id)
-
 	return writerToFunc(func(w io.Writer) (n int64, err error) {
 		_, _, endObservation := s.operations.getSearchJobLogsWriterTo.writerTo.With(parentCtx, &err, opAttrs(
 			attribute.Int64("id", id)))
@@ -233,43 +859,21 @@ func (s *Service) GetSearchJobLogsWriterTo(parentCtx context.Context, id int64)
 			endObservation(1, opAttrs(attribute.Int64("bytesWritten", n)))
 		}()
 
-		return writeSearchJobLogs(iter, w)
+		return writeSearchJobLogs(func(f func(types.SearchJobLog) error) error {
+			return s.scanJobLogs(ctx, id, f)
+		}, w)
 	}), nil
 }
 
-// JobLogsIterLimit is the number of lines the iterator will read from the
-// database per page. Assuming 100 bytes per line, this will be ~1MB of memory
-// per 10k repo-rev jobs.
-var JobLogsIterLimit = 10_000
-
-func (s *Service) getJobLogsIter(ctx context.Context, id int64) *iterator.Iterator[types.SearchJobLog] {
-	var cursor int64
-	limit := JobLogsIterLimit
-
-	return iterator.New(func() ([]types.SearchJobLog, error) {
-		if cursor == -1 {
-			return nil, nil
-		}
-
-		opts := &store.GetJobLogsOpts{
-			From:  cursor,
-			Limit: limit + 1,
-		}
-
-		logs, err := s.store.GetJobLogs(ctx, id, opts)
-		if err != nil {
-			return nil, err
-		}
-
-		if len(logs) > limit {
-			cursor = logs[len(logs)-1].ID
-			logs = logs[:len(logs)-1]
-		} else {
-			cursor = -1
-		}
+// JobLogsBatchSize is the number of lines read from the database at once.
+// Assuming 100 bytes per line, this will be ~1MB of memory per 10k repo-rev
+// jobs.
+var JobLogsBatchSize = 10_000
 
-		return logs, nil
-	})
+// scanJobLogs calls f with the logs of job id. The caller has to check that
+// the actor may read the job.
+func (s *Service) scanJobLogs(ctx context.Context, id int64, f func(types.SearchJobLog) error) error {
+	return s.store.ScanJobLogs(ctx, id, JobLogsBatchSize, f)
 }
 
 func formatOrNULL(t time.Time) string {
@@ -284,6 +888,12 @@ func getPrefix(id int64) string {
 	return fmt.Sprintf("%d-", id)
 }
 
+// ErrSearchJobRunning is returned by DeleteSearchJob if the job is still
+// running and has not been canceled.
+var ErrSearchJobRunning = errors.New("search job is still running, cancel it before deleting it")
+
+// DeleteSearchJob deletes job id, its repo and repo revision jobs and its
+// results. Deleting a job which does not exist is a no-op.
 func (s *Service) DeleteSearchJob(ctx context.Context, id int64) (err error) {
 	ctx, _, endObservation := s.operations.deleteSearchJob.With(ctx, &err, opAttrs(
 		attribute.Int64("id", id)))
@@ -291,10 +901,43 @@ func (s *Service) DeleteSearchJob(ctx context.Context, id int64) (err error) {
 		endObservation(1, observation.Args{})
 	}()
 
+	// deletedJob is set once the job is deleted. This runs after the
+	// transaction committed.
+	var deletedJob *types.ExhaustiveSearchJob
+	defer func() {
+		if err == nil && deletedJob != nil {
+			s.audit(ctx, "deleted", deletedJob)
+		}
+	}()
+
+	// The job is deleted in a transaction which locks it first. Resuming and
+	// retrying a job lock it as well, so the job can't start running again
+	// between the check of its state below and its deletion, while workers
+	// would write results we already deleted.
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
 	// 🚨 SECURITY: only someone with access to the job may delete data and the db entries
-	if err := s.store.UserHasAccess(ctx, id); err != nil {
+	if err := tx.LockExhaustiveSearchJob(ctx, id); err != nil {
+		if errors.Is(err, store.ErrNoResults) {
+			return nil
+		}
 		return err
 	}
+	job, err := tx.GetExhaustiveSearchJob(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Workers of a running job would keep writing results after we deleted
+	// them. A canceled or finished job only runs again if it is resumed or
+	// retried, which waits for our lock.
+	if !job.Cancel && (job.AggState == types.JobStateQueued || job.AggState == types.JobStateProcessing) {
+		return ErrSearchJobRunning
+	}
 
 	iter, err := s.uploadStore.List(ctx, getPrefix(id))
 	if err != nil {
@@ -314,40 +957,160 @@ func (s *Service) DeleteSearchJob(ctx context.Context, id int64) (err error) {
 		return err
 	}
 
-	return s.store.DeleteExhaustiveSearchJob(ctx, id)
+	err = tx.DeleteExhaustiveSearchJob(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	deletedJob = job
+
+	return nil
+}
+
+// DeleteExpiredSearchJobs deletes at most limit jobs created before the given
+// time, like DeleteSearchJob, and returns how many were deleted. Jobs which are
+// still running are canceled first.
+func (s *Service) DeleteExpiredSearchJobs(ctx context.Context, before time.Time, limit int) (deleted int, err error) {
+	ctx, _, endObservation := s.operations.deleteExpiredSearchJobs.With(ctx, &err, opAttrs(
+		attribute.String("before", before.String()),
+		attribute.Int("limit", limit)))
+	defer endObservation(1, observation.Args{})
+
+	ids, err := s.store.ListExpiredExhaustiveSearchJobIDs(ctx, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		err := s.DeleteSearchJob(ctx, id)
+		if errors.Is(err, ErrSearchJobRunning) {
+			if err := s.CancelSearchJob(ctx, id); err != nil && !errors.Is(err, ErrSearchJobFinished) {
+				return deleted, errors.Wrapf(err, "canceling search job %d", id)
+			}
+			err = s.DeleteSearchJob(ctx, id)
+		}
+		if err != nil {
+			return deleted, errors.Wrapf(err, "deleting search job %d", id)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// ReleaseExpiredIdempotencyKeys removes the idempotency keys which expired
+// before the given time from their jobs, so they can be used again, and
+// returns how many were removed.
+func (s *Service) ReleaseExpiredIdempotencyKeys(ctx context.Context, before time.Time) (released int, err error) {
+	ctx, _, endObservation := s.operations.releaseExpiredIdempotencyKeys.With(ctx, &err, opAttrs(
+		attribute.String("before", before.String())))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: the store only lets internal actors release keys.
+	return s.store.ReleaseExpiredIdempotencyKeys(ctx, before)
+}
+
+// AbortIncompleteUploads deletes the parts of result uploads which were started
+// more than maxAge ago and never completed or aborted, for example because the
+// worker uploading them died.
+func (s *Service) AbortIncompleteUploads(ctx context.Context, maxAge time.Duration) (err error) {
+	ctx, _, endObservation := s.operations.abortIncompleteUploads.With(ctx, &err, opAttrs(
+		attribute.Stringer("maxAge", maxAge)))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: this affects the results of all users.
+	if !actor.FromContext(ctx).IsInternal() {
+		return errors.New("can only abort incomplete uploads as an internal actor")
+	}
+
+	return uploadstore.AbortIncompleteUploads(ctx, s.uploadStore, "", maxAge)
 }
 
 // GetSearchJobResultsWriterTo returns a WriterTo which can be called once to
-// write all CSVs associated with a search job to the given writer for job id.
-// Note: ctx is used by WriterTo.
+// write the results of job id in the given format. Results are ordered by
+// repository and revision. Note: ctx is used by WriterTo.
 //
 // io.WriterTo is a specialization of an io.Reader. We expect callers of this
 // function to want to write a http response, so we avoid an io.Pipe and
 // instead pass a more direct use.
-func (s *Service) GetSearchJobResultsWriterTo(parentCtx context.Context, id int64) (_ io.WriterTo, err error) {
+func (s *Service) GetSearchJobResultsWriterTo(parentCtx context.Context, id int64, format ResultFormat) (_ *SearchJobResultsWriterTo, err error) {
 	ctx, _, endObservation := s.operations.getSearchJobResultsWriterTo.get.With(parentCtx, &err, opAttrs(
-		attribute.Int64("id", id)))
+		attribute.Int64("id", id),
+		attribute.Stringer("format", format)))
 	defer endObservation(1, observation.Args{})
 
 	// 🚨 SECURITY: only someone with access to the job may copy the blobs
-	if err := s.store.UserHasAccess(ctx, id); err != nil {
+	job, err := s.store.GetExhaustiveSearchJob(ctx, id)
+	if err != nil {
 		return nil, err
 	}
 
-	iter, err := s.uploadStore.List(ctx, getPrefix(id))
+	hidden, err := s.checkRepoPermissions(ctx, job)
 	if err != nil {
 		return nil, err
 	}
 
-	return writerToFunc(func(w io.Writer) (n int64, err error) {
-		ctx, _, endObservation := s.operations.getSearchJobResultsWriterTo.writerTo.With(parentCtx, &err, opAttrs(
-			attribute.Int64("id", id)))
-		defer func() {
-			endObservation(1, opAttrs(attribute.Int64("bytesWritten", n)))
-		}()
+	s.audit(ctx, "downloaded", job)
+
+	return &SearchJobResultsWriterTo{
+		WriterTo: writerToFunc(func(w io.Writer) (n int64, err error) {
+			ctx, _, endObservation := s.operations.getSearchJobResultsWriterTo.writerTo.With(parentCtx, &err, opAttrs(
+				attribute.Int64("id", id),
+				attribute.Stringer("format", format)))
+			defer func() {
+				endObservation(1, opAttrs(attribute.Int64("bytesWritten", n)))
+			}()
+
+			return s.writeResults(ctx, job, format, hidden, w)
+		}),
+		Sample: job.Sample(),
+	}, nil
+}
 
-		return writeSearchJobJSON(ctx, iter, s.uploadStore, w)
-	}), nil
+// SearchJobResultsWriterTo writes the results of a search job, see
+// GetSearchJobResultsWriterTo.
+type SearchJobResultsWriterTo struct {
+	io.WriterTo
+
+	// Sample describes the sample of the results of the query which the
+	// results are. It is nil if they are complete. Only the Parquet format
+	// marks sampled results itself, so callers have to report it alongside
+	// the others.
+	Sample *types.ResultsSample
+}
+
+// writeResults writes the results of job in format to w, except those of the
+// hidden repositories, see checkRepoPermissions. The caller has to check that
+// the actor may read the job.
+func (s *Service) writeResults(ctx context.Context, job *types.ExhaustiveSearchJob, format ResultFormat, hidden map[api.RepoID]struct{}, w io.Writer) (int64, error) {
+	// We need all tasks to order the output, but each task is small. The
+	// results themselves are streamed.
+	var tasks []types.SearchJobLog
+	batches := make(map[int64][]int64)
+	err := s.scanJobLogs(ctx, job.ID, func(task types.SearchJobLog) error {
+		if task.BatchID != 0 {
+			batches[task.BatchID] = append(batches[task.BatchID], task.ID)
+		}
+		if _, ok := hidden[task.RepoID]; !ok {
+			tasks = append(tasks, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	iter, err := s.uploadStore.List(ctx, getPrefix(job.ID))
+	if err != nil {
+		return 0, err
+	}
+	shards, err := groupResultKeys(iter, getPrefix(job.ID))
+	if err != nil {
+		return 0, err
+	}
+	mergeBatchShards(shards, batches)
+
+	return writeSearchJobResults(ctx, tasks, shards, s.uploadStore, format, job.Sample(), job.DeduplicateResults, w)
 }
 
 // GetAggregateRepoRevState returns the map of state -> count for all repo
@@ -362,7 +1125,329 @@ func (s *Service) GetAggregateRepoRevState(ctx context.Context, id int64) (_ *ty
 		return nil, err
 	}
 
+	return aggregateRepoRevState(m)
+}
+
+// JobProgress returns how many tasks of job id are left, for example to show
+// a progress bar while the job is running.
+func (s *Service) JobProgress(ctx context.Context, id int64) (_ *types.SearchJobProgress, err error) {
+	ctx, _, endObservation := s.operations.jobProgress.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id)))
+	defer endObservation(1, observation.Args{})
+
+	progress, err := s.store.GetSearchJobProgress(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// GlobalBacklog returns the work the workers have left for the search jobs of
+// all users. Only site admins may see it.
+func (s *Service) GlobalBacklog(ctx context.Context) (_ *types.SearchJobsBacklog, err error) {
+	ctx, _, endObservation := s.operations.globalBacklog.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	backlog, err := s.store.GetSearchJobsBacklog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &backlog, nil
+}
+
+// ExportVolumeByUser returns how much result data the search jobs created in
+// [after, before) produced, by user, largest first. A zero before means until
+// now. Only site admins may see it.
+func (s *Service) ExportVolumeByUser(ctx context.Context, after, before time.Time) (_ []types.UserExportVolume, err error) {
+	ctx, _, endObservation := s.operations.exportVolumeByUser.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	if before.IsZero() {
+		before = s.clock.Now()
+	}
+	if !after.Before(before) {
+		return nil, errors.New("the start of the time window must be before its end")
+	}
+
+	return s.store.GetExportVolumeByUser(ctx, after, before)
+}
+
+// MaxUsageStatsWindow is the longest window UsageStats covers, which bounds
+// the number of jobs it reads.
+const MaxUsageStatsWindow = 366 * 24 * time.Hour
+
+// usageStatsTopUsers is the number of users UsageStats ranks.
+const usageStatsTopUsers = 10
+
+// UsageStats returns how heavily search jobs were used in the window which
+// ends now: the jobs created per day, how they ended and how much result data
+// they produced, and the users whose jobs have the most tasks. Only site
+// admins may see it.
+func (s *Service) UsageStats(ctx context.Context, window time.Duration) (_ *types.UsageStats, err error) {
+	ctx, _, endObservation := s.operations.usageStats.With(ctx, &err, opAttrs(
+		attribute.Stringer("window", window),
+	))
+	defer endObservation(1, observation.Args{})
+
+	if window <= 0 || window > MaxUsageStatsWindow {
+		return nil, errors.Newf("the time window must be longer than 0 and at most %d days", MaxUsageStatsWindow/(24*time.Hour))
+	}
+	before := s.clock.Now()
+	after := before.Add(-window)
+
+	days, err := s.store.GetDailyUsage(ctx, after, before)
+	if err != nil {
+		return nil, err
+	}
+	topUsers, err := s.store.GetTopUsersByTaskVolume(ctx, after, before, usageStatsTopUsers)
+	if err != nil {
+		return nil, err
+	}
+	return &types.UsageStats{Days: days, TopUsers: topUsers}, nil
+}
+
+// MaxFailedTasksPageSize is the maximum number of tasks ListFailedTasks
+// returns at once.
+const MaxFailedTasksPageSize = 1000
+
+// ListFailedTasksArgs are the pagination arguments of ListFailedTasks.
+type ListFailedTasksArgs struct {
+	// After is the ID of the last task of the previous page. The first page
+	// is returned if it is 0.
+	After int64
+
+	// First is the number of tasks to return. It defaults to
+	// MaxFailedTasksPageSize.
+	First int
+}
+
+// ListFailedTasks returns a page of the failed repo revision tasks of search
+// job id, ordered by ID. Each task has the repository, the revision and the
+// reason it failed.
+func (s *Service) ListFailedTasks(ctx context.Context, id int64, args ListFailedTasksArgs) (tasks []types.SearchJobLog, err error) {
+	ctx, _, endObservation := s.operations.listFailedTasks.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+		attribute.Int64("after", args.After),
+		attribute.Int("first", args.First)))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("len", len(tasks))))
+	}()
+
+	if args.First < 0 || args.First > MaxFailedTasksPageSize {
+		return nil, errors.Newf("first must be between 0 and %d", MaxFailedTasksPageSize)
+	}
+	if args.First == 0 {
+		args.First = MaxFailedTasksPageSize
+	}
+
+	// 🚨 SECURITY: GetJobLogs checks that the actor has access to the job.
+	return s.store.GetJobLogs(ctx, id, &store.GetJobLogsOpts{
+		From:  args.After + 1,
+		Limit: args.First,
+		State: types.JobStateFailed,
+	})
+}
+
+// MaxTasksPageSize is the maximum number of tasks ListTasks returns at once.
+const MaxTasksPageSize = 1000
+
+// ListTasksArgs are the filter and pagination arguments of ListTasks.
+type ListTasksArgs struct {
+	// State only returns the tasks in this state if set.
+	State types.JobState
+
+	// After is the ID of the last task of the previous page. The first page
+	// is returned if it is 0.
+	After int64
+
+	// First is the number of tasks to return. It defaults to
+	// MaxTasksPageSize.
+	First int
+}
+
+// ListTasks returns a page of the repo revision tasks of search job id, ordered
+// by ID, with their timestamps, attempts, worker and last error. It lets
+// support inspect the queue of a job. Only site admins may list tasks.
+func (s *Service) ListTasks(ctx context.Context, id int64, args ListTasksArgs) (tasks []types.SearchJobTask, err error) {
+	ctx, _, endObservation := s.operations.listTasks.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+		attribute.String("state", string(args.State)),
+		attribute.Int64("after", args.After),
+		attribute.Int("first", args.First)))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("len", len(tasks))))
+	}()
+
+	if args.First < 0 || args.First > MaxTasksPageSize {
+		return nil, errors.Newf("first must be between 0 and %d", MaxTasksPageSize)
+	}
+	if args.First == 0 {
+		args.First = MaxTasksPageSize
+	}
+
+	// 🚨 SECURITY: ListSearchJobTasks checks that the actor is a site admin.
+	return s.store.ListSearchJobTasks(ctx, id, store.ListSearchJobTasksOpts{
+		State: args.State,
+		After: args.After,
+		Limit: args.First,
+	})
+}
+
+// GetTask returns the full record of repo revision task id. Only site admins
+// may get tasks.
+func (s *Service) GetTask(ctx context.Context, id int64) (_ *types.SearchJobTask, err error) {
+	ctx, _, endObservation := s.operations.getTask.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: GetSearchJobTask checks that the actor is a site admin.
+	return s.store.GetSearchJobTask(ctx, id)
+}
+
+// ErrTaskStateConflict is returned by ForceFailTask and ForceRequeueTask if the
+// task is in a state it can't be moved out of.
+var ErrTaskStateConflict = errors.New("the task can't be changed in its current state")
+
+// ForceFailTask marks repo revision task id as failed with reason, for example
+// to stop a task which keeps crashing its workers. Queued, errored and
+// processing tasks can be failed. Only site admins may fail tasks, and every
+// change is audited.
+func (s *Service) ForceFailTask(ctx context.Context, id int64, reason string) (err error) {
+	ctx, _, endObservation := s.operations.forceFailTask.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	if reason == "" {
+		return errors.New("a reason is required to fail a task")
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// 🚨 SECURITY: GetSearchJobTask and ForceFailSearchJobTask check that the
+	// actor is a site admin.
+	task, err := tx.GetSearchJobTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	failed, err := tx.ForceFailSearchJobTask(ctx, id, reason)
+	if err != nil {
+		return err
+	}
+	if !failed {
+		return ErrTaskStateConflict
+	}
+
+	s.auditTask(ctx, "force-failed", task, log.String("reason", reason))
+
+	return nil
+}
+
+// ForceRequeueTask puts repo revision task id, which failed or errored, back
+// into the queue with all its retries, so that the workers search the revision
+// again. Unlike RetryFailedTasks it requeues a single task. Only site admins
+// may requeue tasks, and every change is audited.
+func (s *Service) ForceRequeueTask(ctx context.Context, id int64) (err error) {
+	ctx, _, endObservation := s.operations.forceRequeueTask.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// 🚨 SECURITY: GetSearchJobTask and ForceRequeueSearchJobTask check that
+	// the actor is a site admin.
+	task, err := tx.GetSearchJobTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	requeued, err := tx.ForceRequeueSearchJobTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !requeued {
+		return ErrTaskStateConflict
+	}
+
+	s.auditTask(ctx, "force-requeued", task)
+
+	// The aggregated results are missing the results of the requeued task.
+	return s.deleteAggregatedResults(ctx, task.SearchJobID)
+}
+
+// auditTask writes an audit log entry for action on task. Unlike audit, it
+// doesn't log the query of the job, only which task was changed and its state
+// before.
+func (s *Service) auditTask(ctx context.Context, action string, task *types.SearchJobTask, fields ...log.Field) {
+	audit.Log(ctx, s.logger, audit.Record{
+		Entity: "search job task",
+		Action: action,
+		Fields: append([]log.Field{
+			log.Int64("id", task.ID),
+			log.Int64("searchJobID", task.SearchJobID),
+			log.String("repoName", string(task.RepoName)),
+			log.String("revision", task.Revision),
+			log.String("previousState", string(task.State)),
+		}, fields...),
+	})
+}
+
+// MaxSearchJobLogsPageSize is the maximum number of lines GetSearchJobLogs
+// returns at once.
+const MaxSearchJobLogsPageSize = 1000
+
+// GetSearchJobLogsArgs are the pagination arguments of GetSearchJobLogs.
+type GetSearchJobLogsArgs struct {
+	// After is the ID of the last line of the previous page. The first page
+	// is returned if it is 0.
+	After int64
+
+	// First is the number of lines to return. It defaults to
+	// MaxSearchJobLogsPageSize.
+	First int
+}
+
+// GetSearchJobLogs returns a page of the log the workers wrote while they ran
+// the tasks of search job id, in the order it was written. The log has a line
+// for every task which started, finished, failed, was retried or skipped.
+func (s *Service) GetSearchJobLogs(ctx context.Context, id int64, args GetSearchJobLogsArgs) (lines []types.SearchJobLogLine, err error) {
+	ctx, _, endObservation := s.operations.getSearchJobLogs.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+		attribute.Int64("after", args.After),
+		attribute.Int("first", args.First)))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("len", len(lines))))
+	}()
+
+	if args.First < 0 || args.First > MaxSearchJobLogsPageSize {
+		return nil, errors.Newf("first must be between 0 and %d", MaxSearchJobLogsPageSize)
+	}
+	if args.First == 0 {
+		args.First = MaxSearchJobLogsPageSize
+	}
+
+	// 🚨 SECURITY: ListSearchJobLogLines checks that the actor has access to
+	// the job.
+	return s.store.ListSearchJobLogLines(ctx, id, store.ListSearchJobLogLinesOpts{
+		After: args.After,
+		Limit: args.First,
+	})
+}
+
+// aggregateRepoRevState converts the map of state -> count returned by the
+// store into RepoRevJobStats.
+func aggregateRepoRevState(m map[string]int) (*types.RepoRevJobStats, error) {
 	stats := types.RepoRevJobStats{}
+	canceled := false
 	for state, count := range m {
 		switch types.JobState(state) {
 		case types.JobStateCompleted:
@@ -372,47 +1457,40 @@ func (s *Service) GetAggregateRepoRevState(ctx context.Context, id int64) (_ *ty
 		case types.JobStateProcessing, types.JobStateErrored, types.JobStateQueued:
 			stats.InProgress += int32(count)
 		case types.JobStateCanceled:
-			stats.InProgress = 0
+			canceled = true
+		case types.JobStateSkipped:
+			// Skipped jobs were never run, so they don't count towards the
+			// progress of the job.
 		default:
 			return nil, errors.Newf("unknown job state %q", state)
 		}
 	}
 
+	// Once a job is canceled, none of its remaining jobs will make progress.
+	// We check this after the loop since map iteration order is random.
+	if canceled {
+		stats.InProgress = 0
+	}
+
 	stats.Total = stats.Completed + stats.Failed + stats.InProgress
 
 	return &stats, nil
 }
 
-func writeSearchJobJSON(ctx context.Context, iter *iterator.Iterator[string], uploadStore uploadstore.Store, w io.Writer) (int64, error) {
-	// keep a single bufio.Reader so we can reuse its buffer.
-	var br bufio.Reader
-
-	writeKey := func(key string) (int64, error) {
-		rc, err := uploadStore.Get(ctx, key)
-		if err != nil {
-			return 0, err
-		}
-		defer rc.Close()
-
-		br.Reset(rc)
-
-		return br.WriteTo(w)
-	}
-
-	var n int64
-	for iter.Next() {
-		key := iter.Current()
-		m, err := writeKey(key)
-		n += m
-		if err != nil {
-			return n, errors.Wrapf(err, "writing JSON for key %q", key)
-		}
-	}
-
-	return n, iter.Err()
+// logsCSVHeader is the header of the CSV of search job logs.
+var logsCSVHeader = []string{
+	"repository",
+	"revision",
+	"commit",
+	"started_at",
+	"finished_at",
+	"status",
+	"failure_message",
+	"worker",
+	"worker_started_at",
 }
 
-func writeSearchJobLogs(iter *iterator.Iterator[types.SearchJobLog], w io.Writer) (int64, error) {
+func writeSearchJobLogs(scan func(func(types.SearchJobLog) error) error, w io.Writer) (int64, error) {
 	// For csv.NewWriter we have no way to track bytes written, so we wrap
 	// w to find out. The implementation of csv writer uses a
 	// bufio.NewWriter and avoids any uses of optimized interfaces like
@@ -420,35 +1498,25 @@ func writeSearchJobLogs(iter *iterator.Iterator[types.SearchJobLog], w io.Writer
 	writeCounter := &writeCounter{w: w}
 	cw := csv.NewWriter(writeCounter)
 
-	header := []string{
-		"repository",
-		"revision",
-		"started_at",
-		"finished_at",
-		"status",
-		"failure_message",
-	}
-	err := cw.Write(header)
+	err := cw.Write(logsCSVHeader)
 	if err != nil {
 		return writeCounter.n, err
 	}
 
-	for iter.Next() {
-		job := iter.Current()
-		err = cw.Write([]string{
+	err = scan(func(job types.SearchJobLog) error {
+		return cw.Write([]string{
 			string(job.RepoName),
 			job.Revision,
+			string(job.CommitID),
 			formatOrNULL(job.StartedAt),
 			formatOrNULL(job.FinishedAt),
 			string(job.State),
 			job.FailureMessage,
+			job.WorkerHostname,
+			formatOrNULL(job.WorkerStartedAt),
 		})
-		if err != nil {
-			return writeCounter.n, err
-		}
-	}
-
-	if err := iter.Err(); err != nil {
+	})
+	if err != nil {
 		return writeCounter.n, err
 	}
 
@@ -1,45 +1,23 @@
 package service
 
 import (
-	"bytes"
 	"context"
-	"io"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
-	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
-	"github.com/sourcegraph/sourcegraph/lib/iterator"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
-// Test_copyBlobs tests that we concatenate objects from the object store
-// properly.
-func Test_copyBlobs(t *testing.T) {
-	keysIter := iterator.From([]string{"a", "b", "c"})
-
-	blobs := map[string]io.Reader{
-		"a": bytes.NewReader([]byte("{\"Key\":\"a\"}\n{\"Key\":\"b\"}\n")),
-		"b": bytes.NewReader([]byte("{\"Key\":\"c\"}\n")),
-		"c": bytes.NewReader([]byte("{\"Key\":\"d\"}\n{\"Key\":\"e\"}\n{\"Key\":\"f\"}\n")),
-	}
-
-	blobstore := mocks.NewMockStore()
-	blobstore.GetFunc.SetDefaultHook(func(ctx context.Context, key string) (io.ReadCloser, error) {
-		return io.NopCloser(blobs[key]), nil
-	})
-
-	w := &bytes.Buffer{}
-
-	n, err := writeSearchJobJSON(context.Background(), keysIter, blobstore, w)
-	require.NoError(t, err)
-	require.Equal(t, int64(72), n)
-
-	want := "{\"Key\":\"a\"}\n{\"Key\":\"b\"}\n{\"Key\":\"c\"}\n{\"Key\":\"d\"}\n{\"Key\":\"e\"}\n{\"Key\":\"f\"}\n"
-	require.Equal(t, want, w.String())
-}
-
 func TestIsEnabled(t *testing.T) {
 	defer conf.Mock(nil)
 
@@ -80,3 +58,206 @@ func TestIsEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregateRepoRevState(t *testing.T) {
+	cases := []struct {
+		name  string
+		state map[string]int
+		want  types.RepoRevJobStats
+	}{
+		{
+			name:  "queued",
+			state: map[string]int{"queued": 1},
+			want:  types.RepoRevJobStats{Total: 1, InProgress: 1},
+		},
+		{
+			name:  "in progress",
+			state: map[string]int{"completed": 3, "processing": 2, "errored": 1, "queued": 4},
+			want:  types.RepoRevJobStats{Total: 10, Completed: 3, InProgress: 7},
+		},
+		{
+			name:  "completed with failures",
+			state: map[string]int{"completed": 8, "failed": 2},
+			want:  types.RepoRevJobStats{Total: 10, Completed: 8, Failed: 2},
+		},
+		{
+			name:  "canceled",
+			state: map[string]int{"completed": 3, "failed": 1, "processing": 2, "queued": 4, "canceled": 1},
+			want:  types.RepoRevJobStats{Total: 4, Completed: 3, Failed: 1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// Map iteration order is random, so repeat to catch order
+			// dependent results.
+			for range 20 {
+				got, err := aggregateRepoRevState(c.state)
+				require.NoError(t, err)
+				require.Equal(t, c.want, *got)
+			}
+		})
+	}
+
+	_, err := aggregateRepoRevState(map[string]int{"unknown": 1})
+	require.Error(t, err)
+}
+
+func TestListSearchJobs_Limit(t *testing.T) {
+	svc := New(observation.TestContextTB(t), nil, nil, nil)
+
+	for _, args := range []*database.PaginationArgs{
+		{First: pointers.Ptr(MaxSearchJobsPageSize + 2)},
+		{Last: pointers.Ptr(MaxSearchJobsPageSize + 2)},
+	} {
+		_, err := svc.ListSearchJobs(context.Background(), store.ListArgs{PaginationArgs: args})
+		require.ErrorContains(t, err, "cannot list more than 100 search jobs at once")
+	}
+}
+
+func TestCreationSource(t *testing.T) {
+	userCtx := actor.WithActor(context.Background(), actor.FromUser(1))
+
+	webActor := actor.FromUser(1)
+	webActor.FromSessionCookie = true
+	webCtx := actor.WithActor(context.Background(), webActor)
+
+	require.Equal(t, types.JobCreationSourceAPI, creationSource(userCtx, false))
+	require.Equal(t, types.JobCreationSourceWeb, creationSource(webCtx, false))
+
+	// Scheduled jobs are created on behalf of the user, whatever the actor
+	// authenticated with.
+	require.Equal(t, types.JobCreationSourceScheduled, creationSource(userCtx, true))
+	require.Equal(t, types.JobCreationSourceScheduled, creationSource(webCtx, true))
+}
+
+func TestCreateSearchJob_InvalidQuery(t *testing.T) {
+	enabled := true
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{SearchJobs: &enabled}}})
+	defer conf.Mock(nil)
+
+	// The service has no store, so it panics if it tries to create a job.
+	svc := New(observation.TestContextTB(t), nil, nil, NewSearcherFake())
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+
+	for _, tc := range []struct {
+		query string
+		token string
+		start int
+	}{
+		{query: "1@rev1 select:repo", token: "select:repo", start: 7},
+		{query: "1@rev1 type:symbol", token: "type:symbol", start: 7},
+		{query: "1@rev1 file:has.content(foo)", token: "file:has.content(foo)", start: 7},
+		{query: "patterntype:structural 1@rev1", token: "patterntype:structural", start: 0},
+		{query: "1@rev1 -type:file"},
+		{query: "1@rev1 repo:("},
+		{query: "1@rev1 author:alice"},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			_, err := svc.CreateSearchJob(ctx, tc.query, CreateSearchJobOpts{})
+
+			var qErr *QueryError
+			require.ErrorAs(t, err, &qErr)
+			require.Equal(t, tc.token, qErr.Token)
+			require.Equal(t, tc.start, qErr.Start)
+			if tc.token != "" {
+				require.Equal(t, tc.token, tc.query[qErr.Start:qErr.End])
+			}
+		})
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	// Queries which are valid, even if they are expensive.
+	for _, q := range []string{
+		"foo",
+		"repo:.* foo",
+		"type:diff author:alice",
+		"type:file type:path foo",
+		"file:\\.go$ patterntype:regexp fo+",
+		"repo:has.file(go.mod) foo",
+	} {
+		require.NoError(t, validateQuery(q), q)
+	}
+}
+
+func TestValidateMetadata(t *testing.T) {
+	tooManyLabels := map[string]string{}
+	for i := range MaxLabels + 1 {
+		tooManyLabels[fmt.Sprintf("key%d", i)] = "value"
+	}
+
+	for _, tc := range []struct {
+		name        string
+		description string
+		labels      map[string]string
+		wantErr     string
+	}{
+		{
+			name:        "valid",
+			description: "weekly audit",
+			labels:      map[string]string{"team": "search", "env": ""},
+		},
+		{
+			// Lengths are counted in characters, "€" is 3 bytes long.
+			name:        "longest description",
+			description: strings.Repeat("€", MaxDescriptionLength),
+		},
+		{
+			name:        "description too long",
+			description: strings.Repeat("a", MaxDescriptionLength+1),
+			wantErr:     "the description of a search job must not be longer than 1000 characters",
+		},
+		{
+			name:    "too many labels",
+			labels:  tooManyLabels,
+			wantErr: "a search job must not have more than 20 labels",
+		},
+		{
+			name:    "empty key",
+			labels:  map[string]string{"": "search"},
+			wantErr: "the labels of a search job must have a key",
+		},
+		{
+			name:    "key too long",
+			labels:  map[string]string{strings.Repeat("k", MaxLabelKeyLength+1): "search"},
+			wantErr: "the label keys of a search job must not be longer than 64 characters",
+		},
+		{
+			name:    "value too long",
+			labels:  map[string]string{"team": strings.Repeat("v", MaxLabelValueLength+1)},
+			wantErr: "the label values of a search job must not be longer than 256 characters",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateMetadata(tc.description, tc.labels)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSearchJobMetadata_Invalid(t *testing.T) {
+	enabled := true
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{SearchJobs: &enabled}}})
+	defer conf.Mock(nil)
+
+	// The service has no store, so it panics if it gets past the validation.
+	svc := New(observation.TestContextTB(t), nil, nil, NewSearcherFake())
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+
+	labels := map[string]string{"team": strings.Repeat("v", MaxLabelValueLength+1)}
+
+	_, err := svc.CreateSearchJob(ctx, "1@rev1", CreateSearchJobOpts{Labels: labels})
+	require.ErrorContains(t, err, "the label values of a search job must not be longer")
+
+	_, err = svc.UpdateSearchJobMetadata(ctx, 1, "", labels)
+	require.ErrorContains(t, err, "the label values of a search job must not be longer")
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ErrSearchJobSummaryNotFound is returned by GetSearchJobSummary if the job has
+// no summary, because it is still running or its summary wasn't written yet.
+var ErrSearchJobSummaryNotFound = errors.New("the search job has no summary yet, it is written once the job finished")
+
+// getSummaryKey returns the key of the object with the summary of job id. It
+// has the prefix of the job, so it is deleted with the job, but
+// groupResultKeys ignores it.
+func getSummaryKey(id int64) string {
+	return getPrefix(id) + "summary.json"
+}
+
+// WriteSearchJobSummary writes the types.SearchJobSummary of job id next to its
+// results. Nothing is written if the job is running, for example because it was
+// resumed since it finished.
+//
+// The workers call it for the summary notification, which is enqueued exactly
+// once every time the job finishes, see store.EnqueueSearchJobNotifications.
+// The summary is deleted when the job is resumed or retried, and written again
+// once the job finishes again. A summary which exists already is overwritten,
+// so that a notification of an earlier finish can't leave a stale summary
+// behind.
+func (s *Service) WriteSearchJobSummary(ctx context.Context, id int64) (err error) {
+	ctx, _, endObservation := s.operations.writeSearchJobSummary.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id)))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers write summaries, users may only read them.
+	if !actor.FromContext(ctx).IsInternal() {
+		return errors.New("can only write search job summaries as an internal actor")
+	}
+
+	job, err := s.store.GetExhaustiveSearchJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	switch job.AggState {
+	case types.JobStateCompleted, types.JobStateFailed, types.JobStateCanceled:
+	default:
+		return nil
+	}
+
+	summary, err := s.newSearchJobSummary(ctx, job)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.uploadStore.Upload(ctx, getSummaryKey(id), bytes.NewReader(b))
+	return err
+}
+
+// newSearchJobSummary returns the summary of job, which must have finished.
+func (s *Service) newSearchJobSummary(ctx context.Context, job *types.ExhaustiveSearchJob) (*types.SearchJobSummary, error) {
+	summary := &types.SearchJobSummary{
+		SchemaVersion:        types.SummarySchemaVersion,
+		JobID:                job.ID,
+		Query:                job.Query,
+		Format:               ResultFormatJSONL.String(),
+		ResultsSchemaVersion: types.ResultsSchemaVersion,
+		State:                job.AggState,
+		Initiator: types.SearchJobSummaryInitiator{
+			ID:       job.InitiatorID,
+			Username: job.InitiatorUsername,
+		},
+		CreatedAt:          job.CreatedAt,
+		FinishedAt:         job.FinishedAt,
+		ResultCount:        job.ResultCount,
+		BytesWritten:       job.BytesWritten,
+		Truncated:          job.Truncated,
+		DeadlineExceeded:   job.DeadlineExceeded,
+		Sampled:            job.Sample() != nil,
+		SampleRate:         job.SampleRate,
+		FailedRepositories: []types.SearchJobSummaryFailure{},
+	}
+	if !job.StartedAt.IsZero() {
+		startedAt := job.StartedAt
+		summary.StartedAt = &startedAt
+	}
+
+	err := s.scanJobLogs(ctx, job.ID, func(task types.SearchJobLog) error {
+		summary.Tasks.Total++
+		switch task.State {
+		case types.JobStateCompleted:
+			summary.Tasks.Completed++
+		case types.JobStateFailed:
+			summary.Tasks.Failed++
+			summary.FailedRepositories = append(summary.FailedRepositories, types.SearchJobSummaryFailure{
+				Repository:     task.RepoName,
+				RepositoryID:   task.RepoID,
+				Revision:       task.Revision,
+				FailureMessage: task.FailureMessage,
+			})
+		case types.JobStateCanceled:
+			summary.Tasks.Canceled++
+		case types.JobStateSkipped:
+			summary.Tasks.Skipped++
+		}
+		if task.FinishedAt.After(summary.FinishedAt) {
+			summary.FinishedAt = task.FinishedAt
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if summary.FinishedAt.IsZero() {
+		// A job which was canceled before it started never finished.
+		summary.FinishedAt = job.UpdatedAt
+	}
+
+	summary.Complete = summary.State == types.JobStateCompleted &&
+		summary.Tasks.Failed == 0 &&
+		!summary.Truncated &&
+		!summary.DeadlineExceeded &&
+		!summary.Sampled
+
+	return summary, nil
+}
+
+// GetSearchJobSummary returns the summary of job id, see WriteSearchJobSummary.
+// It returns ErrSearchJobSummaryNotFound if the job has no summary yet.
+func (s *Service) GetSearchJobSummary(ctx context.Context, id int64) (_ *types.SearchJobSummary, err error) {
+	ctx, _, endObservation := s.operations.getSearchJobSummary.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id)))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may read its summary
+	if err := s.store.UserHasAccess(ctx, id); err != nil {
+		return nil, err
+	}
+
+	key := getSummaryKey(id)
+	exists, err := s.objectExists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrSearchJobSummaryNotFound
+	}
+
+	rc, err := s.uploadStore.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var summary types.SearchJobSummary
+	if err := json.NewDecoder(rc).Decode(&summary); err != nil {
+		return nil, errors.Wrap(err, "decoding search job summary")
+	}
+	return &summary, nil
+}
+
+// objectExists returns true if the upload store has an object at key.
+func (s *Service) objectExists(ctx context.Context, key string) (bool, error) {
+	iter, err := s.uploadStore.List(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	for iter.Next() {
+		if iter.Current() == key {
+			return true, nil
+		}
+	}
+	return false, iter.Err()
+}
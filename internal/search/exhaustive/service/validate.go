@@ -0,0 +1,166 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// QueryError is returned when a search job is created for a query which has a
+// syntax error or which search jobs can't run.
+type QueryError struct {
+	Msg string
+
+	// Token is the part of the query which caused the error. It is empty if
+	// the error is not about a single token, for example an unbalanced
+	// expression.
+	Token string
+
+	// Start and End are the byte offsets of Token in the query. End is
+	// exclusive.
+	Start, End int
+}
+
+func (e *QueryError) Error() string {
+	if e.Token == "" {
+		return "invalid query: " + e.Msg
+	}
+	return fmt.Sprintf("invalid query: %s (%q at position %d)", e.Msg, e.Token, e.Start)
+}
+
+func (e *QueryError) Extensions() map[string]any {
+	ext := map[string]any{"code": "ErrInvalidSearchJobQuery"}
+	if e.Token != "" {
+		ext["token"] = e.Token
+		ext["start"] = e.Start
+		ext["end"] = e.End
+	}
+	return ext
+}
+
+// exhaustiveSupportedResultTypes are the values of type: search jobs can run.
+// Keep in sync with jobutil.NewExhaustive.
+var exhaustiveSupportedResultTypes = result.TypeCommit | result.TypeDiff | result.TypeFile | result.TypePath
+
+// validateQuery parses q and returns a *QueryError if it has a syntax error or
+// uses a filter search jobs don't support. It does not resolve anything, so
+// queries which match nothing or a lot are valid.
+//
+// The searcher validates the query again once it plans the search. This
+// catches the mistakes it can point to in the query before a job is created.
+func validateQuery(q string) error {
+	nodes, err := query.ParseSearchType(q, patternType(q))
+	if err != nil {
+		return &QueryError{Msg: err.Error()}
+	}
+
+	var qErr *QueryError
+	reject := func(msg string, annotation query.Annotation) {
+		if qErr != nil {
+			return
+		}
+		start, end := annotation.Range.Start.Column, annotation.Range.End.Column
+		token := ""
+		if 0 <= start && start < end && end <= len(q) {
+			token = q[start:end]
+		}
+		qErr = &QueryError{Msg: msg, Token: token, Start: start, End: end}
+	}
+
+	query.VisitParameter(nodes, func(field, value string, negated bool, annotation query.Annotation) {
+		switch field {
+		case query.FieldPatternType:
+			if value == "structural" {
+				reject("structural search is not supported in search jobs", annotation)
+			}
+		case query.FieldSelect:
+			reject("select: is not supported in search jobs", annotation)
+		case query.FieldType:
+			t, ok := result.TypeFromString[value]
+			if !negated && ok && t.Without(exhaustiveSupportedResultTypes) != 0 {
+				reject(fmt.Sprintf("type:%s is not supported in search jobs, only %v are", value, exhaustiveSupportedResultTypes), annotation)
+			}
+		case query.FieldFile:
+			if pred, _, ok := query.ScanPredicate(field, []byte(value), query.DefaultPredicateRegistry); ok {
+				reject(fmt.Sprintf("file predicates like %s are not supported in search jobs", pred), annotation)
+			}
+		}
+	})
+	if qErr != nil {
+		return qErr
+	}
+
+	return nil
+}
+
+// patternType returns the search type the searcher will use for q. This
+// mirrors the default of the V3 API and the patterntype: filter.
+func patternType(q string) query.SearchType {
+	searchType := query.SearchTypeStandard
+	nodes, err := query.Parse(q, query.SearchTypeLiteral)
+	if err != nil {
+		return searchType
+	}
+	query.VisitField(query.LowercaseFieldNames(nodes), query.FieldPatternType, func(value string, _ bool, _ query.Annotation) {
+		switch strings.ToLower(value) {
+		case "regex", "regexp":
+			searchType = query.SearchTypeRegex
+		case "literal":
+			searchType = query.SearchTypeLiteral
+		case "structural":
+			searchType = query.SearchTypeStructural
+		case "keyword":
+			searchType = query.SearchTypeKeyword
+		}
+	})
+	return searchType
+}
+
+// The limits of the metadata of a search job. Lengths are counted in
+// characters.
+const (
+	MaxDescriptionLength = 1000
+	MaxLabels            = 20
+	MaxLabelKeyLength    = 64
+	MaxLabelValueLength  = 256
+)
+
+// validateMetadata returns an error if the description or the labels of a
+// search job exceed the limits above. Label keys must not be empty.
+func validateMetadata(description string, labels map[string]string) error {
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return errors.Newf("the description of a search job must not be longer than %d characters", MaxDescriptionLength)
+	}
+	if len(labels) > MaxLabels {
+		return errors.Newf("a search job must not have more than %d labels", MaxLabels)
+	}
+	for key, value := range labels {
+		if key == "" {
+			return errors.New("the labels of a search job must have a key")
+		}
+		if utf8.RuneCountInString(key) > MaxLabelKeyLength {
+			return errors.Newf("the label keys of a search job must not be longer than %d characters", MaxLabelKeyLength)
+		}
+		if utf8.RuneCountInString(value) > MaxLabelValueLength {
+			return errors.Newf("the label values of a search job must not be longer than %d characters", MaxLabelValueLength)
+		}
+	}
+	return nil
+}
+
+// MaxIdempotencyKeyLength is the maximum length of the idempotency key of a
+// search job, in characters.
+const MaxIdempotencyKeyLength = 255
+
+// validateIdempotencyKey returns an error if key is longer than
+// MaxIdempotencyKeyLength.
+func validateIdempotencyKey(key string) error {
+	if utf8.RuneCountInString(key) > MaxIdempotencyKeyLength {
+		return errors.Newf("the idempotency key of a search job must not be longer than %d characters", MaxIdempotencyKeyLength)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ErrIdempotencyKeyInUse is returned by SetIdempotencyKey if another job of
+// the same user holds the key, for example one created concurrently.
+var ErrIdempotencyKeyInUse = errors.New("idempotency key is in use by another search job")
+
+// GetSearchJobByIdempotencyKey returns the ID and the query of the job of user
+// initiatorID which holds key. Keys which expired are ignored. It returns
+// ErrNoResults if no job holds the key.
+func (s *Store) GetSearchJobByIdempotencyKey(ctx context.Context, initiatorID int32, key string) (id int64, query string, err error) {
+	ctx, _, endObservation := s.operations.getSearchJobByIdempotencyKey.With(ctx, &err, opAttrs(
+		attribute.Int("initiatorID", int(initiatorID)),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: keys are scoped to their user, only the user may look them
+	// up.
+	if err := auth.CheckSameUser(ctx, initiatorID); err != nil {
+		return 0, "", err
+	}
+
+	row := s.QueryRow(ctx, sqlf.Sprintf(getSearchJobByIdempotencyKeyFmtStr, initiatorID, key, s.clock.Now()))
+	if err := row.Scan(&id, &query); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, "", ErrNoResults
+		}
+		return 0, "", err
+	}
+	return id, query, nil
+}
+
+const getSearchJobByIdempotencyKeyFmtStr = `
+SELECT id, query
+FROM exhaustive_search_jobs
+WHERE initiator_id = %s AND idempotency_key = %s AND idempotency_key_expires_at > %s
+`
+
+// SetIdempotencyKey makes job id hold key for window. If an expired key of the
+// initiator of the job is still stored, it is released first. It returns
+// ErrIdempotencyKeyInUse if another job of the initiator holds key.
+func (s *Store) SetIdempotencyKey(ctx context.Context, id int64, key string, window time.Duration) (err error) {
+	ctx, _, endObservation := s.operations.setIdempotencyKey.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may set its key
+	if err := s.UserHasAccess(ctx, id); err != nil {
+		return err
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	now := s.clock.Now()
+	if err := tx.Exec(ctx, sqlf.Sprintf(releaseIdempotencyKeyFmtStr, id, key, now)); err != nil {
+		return err
+	}
+
+	err = tx.Exec(ctx, sqlf.Sprintf(setIdempotencyKeyFmtStr, key, now.Add(window), id))
+	if dbutil.IsPostgresError(err, "23505") {
+		return ErrIdempotencyKeyInUse
+	}
+	return err
+}
+
+const releaseIdempotencyKeyFmtStr = `
+UPDATE exhaustive_search_jobs
+SET idempotency_key = NULL, idempotency_key_expires_at = NULL
+WHERE
+    initiator_id = (SELECT initiator_id FROM exhaustive_search_jobs WHERE id = %s)
+    AND idempotency_key = %s
+    AND idempotency_key_expires_at <= %s
+`
+
+const setIdempotencyKeyFmtStr = `
+UPDATE exhaustive_search_jobs
+SET idempotency_key = %s, idempotency_key_expires_at = %s
+WHERE id = %s
+`
+
+// ReleaseExpiredIdempotencyKeys removes the idempotency keys which expired
+// before the given time from their jobs, so they can be used again, and
+// returns how many were removed.
+func (s *Store) ReleaseExpiredIdempotencyKeys(ctx context.Context, before time.Time) (released int, err error) {
+	ctx, _, endObservation := s.operations.releaseExpiredIdempotencyKeys.With(ctx, &err, opAttrs(
+		attribute.String("before", before.String()),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("released", released)))
+	}()
+
+	// 🚨 SECURITY: this affects the jobs of all users, so only internal actors
+	// may call it.
+	if !actor.FromContext(ctx).IsInternal() {
+		return 0, errors.New("can only release expired idempotency keys as an internal actor")
+	}
+
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(releaseExpiredIdempotencyKeysFmtStr, before))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+const releaseExpiredIdempotencyKeysFmtStr = `
+UPDATE exhaustive_search_jobs
+SET idempotency_key = NULL, idempotency_key_expires_at = NULL
+WHERE idempotency_key IS NOT NULL AND idempotency_key_expires_at <= %s
+`
@@ -0,0 +1,100 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+func TestStore_IdempotencyKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	aliceID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	bobID, err := createUser(bs, "bob")
+	require.NoError(t, err)
+
+	clock := glock.NewMockClockAt(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	s := store.NewWithClock(db, observation.TestContextTB(t), clock)
+	aliceCtx := actor.WithActor(context.Background(), actor.FromUser(aliceID))
+	bobCtx := actor.WithActor(context.Background(), actor.FromUser(bobID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	createJob := func(ctx context.Context, query string) int64 {
+		t.Helper()
+		id, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{
+			InitiatorID: actor.FromContext(ctx).UID,
+			Query:       query,
+		})
+		require.NoError(t, err)
+		return id
+	}
+
+	job1 := createJob(aliceCtx, "repo:a")
+	require.NoError(t, s.SetIdempotencyKey(aliceCtx, job1, "key", time.Hour))
+
+	id, query, err := s.GetSearchJobByIdempotencyKey(aliceCtx, aliceID, "key")
+	require.NoError(t, err)
+	require.Equal(t, job1, id)
+	require.Equal(t, "repo:a", query)
+
+	_, _, err = s.GetSearchJobByIdempotencyKey(aliceCtx, aliceID, "other")
+	require.ErrorIs(t, err, store.ErrNoResults)
+
+	t.Run("keys are scoped to their user", func(t *testing.T) {
+		var authErr *auth.InsufficientAuthorizationError
+		_, _, err := s.GetSearchJobByIdempotencyKey(bobCtx, aliceID, "key")
+		require.ErrorAs(t, err, &authErr)
+
+		_, _, err = s.GetSearchJobByIdempotencyKey(bobCtx, bobID, "key")
+		require.ErrorIs(t, err, store.ErrNoResults)
+
+		require.NoError(t, s.SetIdempotencyKey(bobCtx, createJob(bobCtx, "repo:b"), "key", time.Hour))
+	})
+
+	t.Run("a key is held by one job", func(t *testing.T) {
+		err := s.SetIdempotencyKey(aliceCtx, createJob(aliceCtx, "repo:a"), "key", time.Hour)
+		require.ErrorIs(t, err, store.ErrIdempotencyKeyInUse)
+	})
+
+	t.Run("expired keys", func(t *testing.T) {
+		clock.Advance(2 * time.Hour)
+
+		_, _, err := s.GetSearchJobByIdempotencyKey(aliceCtx, aliceID, "key")
+		require.ErrorIs(t, err, store.ErrNoResults)
+
+		// The expired key of job1 is released for the new job.
+		job2 := createJob(aliceCtx, "repo:c")
+		require.NoError(t, s.SetIdempotencyKey(aliceCtx, job2, "key", time.Hour))
+		id, _, err := s.GetSearchJobByIdempotencyKey(aliceCtx, aliceID, "key")
+		require.NoError(t, err)
+		require.Equal(t, job2, id)
+
+		// Only internal actors release the expired keys of all users. The key
+		// of bob expired, the key of job2 didn't.
+		_, err = s.ReleaseExpiredIdempotencyKeys(aliceCtx, clock.Now())
+		require.Error(t, err)
+		released, err := s.ReleaseExpiredIdempotencyKeys(workerCtx, clock.Now())
+		require.NoError(t, err)
+		require.Equal(t, 1, released)
+	})
+}
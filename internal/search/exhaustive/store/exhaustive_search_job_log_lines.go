@@ -0,0 +1,194 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/keegancsmith/sqlf"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+// AppendSearchJobLogLine adds line to the log of its search job. Once the log
+// has maxLines lines, the last one is replaced by a line with
+// types.SearchJobLogEventTruncated and further lines are dropped. A maxLines of
+// 0 means the log is unlimited.
+//
+// Only the workers may write to the logs.
+func (s *Store) AppendSearchJobLogLine(ctx context.Context, line types.SearchJobLogLine, maxLines int) (err error) {
+	ctx, _, endObservation := s.operations.appendSearchJobLogLine.With(ctx, &err, opAttrs(
+		attribute.Int64("searchJobID", line.SearchJobID),
+		attribute.String("event", string(line.Event)),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers may write to the logs.
+	if err := checkInternalActor(ctx); err != nil {
+		return err
+	}
+
+	return s.appendSearchJobLogLines(ctx, line.SearchJobID, []types.SearchJobLogLine{line}, maxLines)
+}
+
+// AppendSearchJobLogLines is like AppendSearchJobLogLine, but it adds several
+// lines of search job searchJobID at once, in order. The workers use it for
+// the lines of a batch of tasks.
+func (s *Store) AppendSearchJobLogLines(ctx context.Context, searchJobID int64, lines []types.SearchJobLogLine, maxLines int) (err error) {
+	ctx, _, endObservation := s.operations.appendSearchJobLogLines.With(ctx, &err, opAttrs(
+		attribute.Int64("searchJobID", searchJobID),
+		attribute.Int("length", len(lines)),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers may write to the logs.
+	if err := checkInternalActor(ctx); err != nil {
+		return err
+	}
+
+	return s.appendSearchJobLogLines(ctx, searchJobID, lines, maxLines)
+}
+
+func (s *Store) appendSearchJobLogLines(ctx context.Context, searchJobID int64, lines []types.SearchJobLogLine, maxLines int) (err error) {
+	if len(lines) == 0 {
+		return nil
+	}
+	if maxLines <= 0 {
+		maxLines = math.MaxInt32
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// The row of the job is locked until we commit, so concurrent writers
+	// can't exceed the cap.
+	count, ok, err := basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(incrementLogLineCountFmtStr, searchJobID, len(lines), maxLines, maxLines)))
+	if err != nil || !ok {
+		return err
+	}
+
+	values := make([]*sqlf.Query, 0, len(lines))
+	for _, line := range lines {
+		count++
+		if count > maxLines {
+			break
+		}
+		if count == maxLines {
+			line = types.SearchJobLogLine{
+				Event:   types.SearchJobLogEventTruncated,
+				Message: fmt.Sprintf("the log was truncated after %d lines", maxLines),
+			}
+		}
+		values = append(values, sqlf.Sprintf(
+			"(%s, %s, %s, %s, %s)",
+			searchJobID,
+			dbutil.NullInt32Column(int32(line.RepoID)),
+			dbutil.NullStringColumn(line.Revision),
+			line.Event,
+			line.Message,
+		))
+	}
+
+	return tx.Exec(ctx, sqlf.Sprintf(appendSearchJobLogLinesFmtStr, sqlf.Join(values, ",")))
+}
+
+// incrementLogLineCountFmtStr counts the lines we add, up to the cap, and
+// returns the number of lines before them.
+const incrementLogLineCountFmtStr = `
+WITH job AS (
+    SELECT id, log_line_count
+    FROM exhaustive_search_jobs
+    WHERE id = %s
+    FOR UPDATE
+)
+UPDATE exhaustive_search_jobs j
+SET log_line_count = LEAST(job.log_line_count + %s, %s)
+FROM job
+WHERE j.id = job.id AND job.log_line_count < %s
+RETURNING job.log_line_count
+`
+
+const appendSearchJobLogLinesFmtStr = `
+INSERT INTO exhaustive_search_job_log_lines (search_job_id, repo_id, revision, event, message)
+VALUES %s
+`
+
+// ListSearchJobLogLinesOpts are the pagination options of
+// ListSearchJobLogLines.
+type ListSearchJobLogLinesOpts struct {
+	// After is the ID of the last line of the previous page.
+	After int64
+	Limit int
+}
+
+// ListSearchJobLogLines returns the lines of the log of search job id in the
+// order they were written.
+func (s *Store) ListSearchJobLogLines(ctx context.Context, id int64, opts ListSearchJobLogLinesOpts) (lines []types.SearchJobLogLine, err error) {
+	ctx, _, endObservation := s.operations.listSearchJobLogLines.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Int64("after", opts.After),
+		attribute.Int("limit", opts.Limit),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("length", len(lines))))
+	}()
+
+	// 🚨 SECURITY: only someone with access to the job may read its log.
+	if err := s.UserHasAccess(ctx, id); err != nil {
+		return nil, err
+	}
+
+	limit := sqlf.Sprintf("")
+	if opts.Limit > 0 {
+		limit = sqlf.Sprintf("LIMIT %s", opts.Limit)
+	}
+
+	rows, err := s.Query(ctx, sqlf.Sprintf(listSearchJobLogLinesFmtStr, id, opts.After, limit))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	for rows.Next() {
+		var line types.SearchJobLogLine
+		if err := rows.Scan(
+			&line.ID,
+			&line.SearchJobID,
+			&dbutil.NullInt32{N: (*int32)(&line.RepoID)},
+			&dbutil.NullString{S: (*string)(&line.RepoName)},
+			&dbutil.NullString{S: &line.Revision},
+			&line.Event,
+			&line.Message,
+			&line.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+const listSearchJobLogLinesFmtStr = `
+SELECT
+	l.id,
+	l.search_job_id,
+	l.repo_id,
+	r.name,
+	l.revision,
+	l.event,
+	l.message,
+	l.created_at
+FROM exhaustive_search_job_log_lines l
+LEFT JOIN repo r ON r.id = l.repo_id
+WHERE l.search_job_id = %s AND l.id > %s
+ORDER BY l.id ASC
+%s
+`
@@ -0,0 +1,116 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+func TestStore_SearchJobLogLines(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	repoID, err := createRepo(db, "repo1")
+	require.NoError(t, err)
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	malloryID, err := createUser(bs, "mallory")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	jobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{
+		InitiatorID: userID,
+		Query:       "repo:job1",
+	})
+	require.NoError(t, err)
+
+	// The log holds 3 lines, the last of which is the truncation marker.
+	events := []types.SearchJobLogEvent{
+		types.SearchJobLogEventStarted,
+		types.SearchJobLogEventFailed,
+		types.SearchJobLogEventStarted,
+		types.SearchJobLogEventFinished,
+	}
+	for _, event := range events {
+		err := s.AppendSearchJobLogLine(workerCtx, types.SearchJobLogLine{
+			SearchJobID: jobID,
+			RepoID:      repoID,
+			Revision:    "main",
+			Event:       event,
+			Message:     string(event),
+		}, 3)
+		require.NoError(t, err)
+	}
+
+	lines, err := s.ListSearchJobLogLines(ctx, jobID, store.ListSearchJobLogLinesOpts{})
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	for i, line := range lines[:2] {
+		require.Equal(t, jobID, line.SearchJobID)
+		require.Equal(t, repoID, line.RepoID)
+		require.Equal(t, api.RepoName("repo1"), line.RepoName)
+		require.Equal(t, "main", line.Revision)
+		require.Equal(t, events[i], line.Event)
+	}
+	require.Equal(t, types.SearchJobLogEventTruncated, lines[2].Event)
+	require.Zero(t, lines[2].RepoID)
+	require.Empty(t, lines[2].RepoName)
+	require.Equal(t, "the log was truncated after 3 lines", lines[2].Message)
+
+	// Lines which are added at once are capped the same way.
+	otherJobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{
+		InitiatorID: userID,
+		Query:       "repo:job2",
+	})
+	require.NoError(t, err)
+	var batch []types.SearchJobLogLine
+	for _, event := range events {
+		batch = append(batch, types.SearchJobLogLine{RepoID: repoID, Revision: "main", Event: event, Message: string(event)})
+	}
+	require.NoError(t, s.AppendSearchJobLogLines(workerCtx, otherJobID, batch[:1], 3))
+	require.NoError(t, s.AppendSearchJobLogLines(workerCtx, otherJobID, batch[1:], 3))
+	otherLines, err := s.ListSearchJobLogLines(ctx, otherJobID, store.ListSearchJobLogLinesOpts{})
+	require.NoError(t, err)
+	require.Len(t, otherLines, 3)
+	for i, line := range otherLines {
+		require.Equal(t, lines[i].Event, line.Event)
+		require.Equal(t, lines[i].Message, line.Message)
+		require.Equal(t, otherJobID, line.SearchJobID)
+	}
+
+	// Only the workers write to the logs.
+	err = s.AppendSearchJobLogLines(ctx, jobID, batch, 0)
+	require.ErrorIs(t, err, store.ErrNotInternalActor)
+	err = s.AppendSearchJobLogLine(ctx, types.SearchJobLogLine{SearchJobID: jobID, Event: types.SearchJobLogEventStarted}, 0)
+	require.ErrorIs(t, err, store.ErrNotInternalActor)
+
+	// Pages start after the last line of the previous page.
+	page, err := s.ListSearchJobLogLines(ctx, jobID, store.ListSearchJobLogLinesOpts{After: lines[0].ID, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, lines[1:2], page)
+
+	// Only the initiator and site admins may read the log.
+	malloryCtx := actor.WithActor(context.Background(), actor.FromUser(malloryID))
+	_, err = s.ListSearchJobLogLines(malloryCtx, jobID, store.ListSearchJobLogLinesOpts{})
+	require.ErrorIs(t, err, auth.ErrMustBeSiteAdminOrSameUser)
+}
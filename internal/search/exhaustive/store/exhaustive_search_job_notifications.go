@@ -0,0 +1,234 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var notificationWorkerOpts = dbworkerstore.Options[*types.ExhaustiveSearchJobNotification]{
+	Name:              "exhaustive_search_job_notification_worker_store",
+	TableName:         "exhaustive_search_job_notifications",
+	ColumnExpressions: notificationColumns,
+
+	Scan: dbworkerstore.BuildWorkerScan(scanNotification),
+
+	OrderByExpression: sqlf.Sprintf("exhaustive_search_job_notifications.state = 'errored', exhaustive_search_job_notifications.updated_at DESC"),
+
+	StalledMaxAge:       DefaultStalledMaxAge,
+	MaxNumResets:        maxNumResets,
+	ResetFailureMessage: stalledFailureMessage,
+
+	RetryAfter:    time.Minute,
+	MaxNumRetries: maxNumRetries,
+}
+
+// NewNotificationWorkerStore returns a dbworkerstore.Store that wraps the "exhaustive_search_job_notifications" table.
+// Records without a heartbeat for stalledMaxAge are requeued by the resetter.
+func NewNotificationWorkerStore(observationCtx *observation.Context, handle basestore.TransactableHandle, stalledMaxAge time.Duration) dbworkerstore.Store[*types.ExhaustiveSearchJobNotification] {
+	opts := notificationWorkerOpts
+	opts.StalledMaxAge = stalledMaxAge
+	return dbworkerstore.New(observationCtx, handle, opts)
+}
+
+var notificationColumns = []*sqlf.Query{
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("state"),
+	sqlf.Sprintf("search_job_id"),
+	sqlf.Sprintf("kind"),
+	sqlf.Sprintf("job_state"),
+	sqlf.Sprintf("failure_message"),
+	sqlf.Sprintf("started_at"),
+	sqlf.Sprintf("finished_at"),
+	sqlf.Sprintf("process_after"),
+	sqlf.Sprintf("num_resets"),
+	sqlf.Sprintf("num_failures"),
+	sqlf.Sprintf("execution_logs"),
+	sqlf.Sprintf("worker_hostname"),
+	sqlf.Sprintf("cancel"),
+	sqlf.Sprintf("created_at"),
+	sqlf.Sprintf("updated_at"),
+}
+
+// SetExhaustiveSearchJobWebhook configures job id to call url once it
+// finished. The request body is signed with secret. Both are encrypted at rest.
+func (s *Store) SetExhaustiveSearchJobWebhook(ctx context.Context, id int64, url, secret string) (err error) {
+	ctx, _, endObservation := s.operations.setExhaustiveSearchJobWebhook.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may configure its webhook
+	err = s.UserHasAccess(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	encryptedURL, keyID, err := encryption.NewUnencrypted(url).Encrypt(ctx, s.key)
+	if err != nil {
+		return errors.Wrap(err, "encrypting webhook URL")
+	}
+	encryptedSecret, _, err := encryption.NewUnencrypted(secret).Encrypt(ctx, s.key)
+	if err != nil {
+		return errors.Wrap(err, "encrypting webhook secret")
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(setExhaustiveSearchJobWebhookFmtStr, encryptedURL, encryptedSecret, dbutil.NullStringColumn(keyID), id))
+}
+
+const setExhaustiveSearchJobWebhookFmtStr = `
+UPDATE exhaustive_search_jobs
+SET webhook_url = %s, webhook_secret = %s, webhook_encryption_key_id = %s
+WHERE id = %s
+`
+
+// GetExhaustiveSearchJobWebhook returns the decrypted webhook URL and secret
+// of job id. The URL is empty if the job has no webhook.
+func (s *Store) GetExhaustiveSearchJobWebhook(ctx context.Context, id int64) (url, secret string, err error) {
+	ctx, _, endObservation := s.operations.getExhaustiveSearchJobWebhook.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: the secret is never shown to users, so only internal actors
+	// may read it.
+	if !actor.FromContext(ctx).IsInternal() {
+		return "", "", errors.New("can only read webhooks as an internal actor")
+	}
+
+	var rawURL, rawSecret, keyID string
+	err = s.QueryRow(ctx, sqlf.Sprintf(getExhaustiveSearchJobWebhookFmtStr, id)).Scan(
+		&dbutil.NullString{S: &rawURL},
+		&dbutil.NullString{S: &rawSecret},
+		&dbutil.NullString{S: &keyID},
+	)
+	if err != nil {
+		return "", "", err
+	}
+	if rawURL == "" {
+		return "", "", nil
+	}
+
+	url, err = encryption.NewEncrypted(rawURL, keyID, s.key).Decrypt(ctx)
+	if err != nil {
+		return "", "", errors.Wrap(err, "decrypting webhook URL")
+	}
+	secret, err = encryption.NewEncrypted(rawSecret, keyID, s.key).Decrypt(ctx)
+	if err != nil {
+		return "", "", errors.Wrap(err, "decrypting webhook secret")
+	}
+
+	return url, secret, nil
+}
+
+const getExhaustiveSearchJobWebhookFmtStr = `
+SELECT webhook_url, webhook_secret, webhook_encryption_key_id
+FROM exhaustive_search_jobs
+WHERE id = %s
+`
+
+// EnqueueSearchJobNotifications enqueues the notifications of job id if the job
+// finished and they weren't enqueued yet. It returns true if it enqueued them.
+// Every finished job gets an email and a summary notification, and a webhook
+// notification if it has a webhook.
+//
+// It has to be called after every transition of a task of the job to a
+// terminal state, in the same transaction if possible. The job row is locked
+// before its aggregate state is computed, so if several workers race on the
+// last tasks of a job, exactly one of them sees the job finished without
+// notifications.
+func (s *Store) EnqueueSearchJobNotifications(ctx context.Context, id int64) (enqueued bool, err error) {
+	ctx, _, endObservation := s.operations.enqueueSearchJobNotifications.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// !ok means the job was deleted in the meantime.
+	notified, ok, err := basestore.ScanFirstBool(tx.Query(ctx, sqlf.Sprintf(lockSearchJobForNotificationFmtStr, id)))
+	if err != nil || !ok || notified {
+		return false, err
+	}
+
+	// This runs after we acquired the lock, so we see the state transitions of
+	// all workers which checked before us.
+	aggState, err := basestore.ScanAny[string](tx.QueryRow(ctx, sqlf.Sprintf(
+		aggStateSubQuery,
+		sqlf.Sprintf(getAggregateStateTable, id, id, id),
+	)))
+	if err != nil {
+		return false, err
+	}
+
+	switch types.JobState(aggState) {
+	case types.JobStateCompleted, types.JobStateFailed, types.JobStateCanceled:
+	default:
+		return false, nil
+	}
+
+	return true, tx.Exec(ctx, sqlf.Sprintf(enqueueSearchJobNotificationsFmtStr, tx.clock.Now(), id, aggState, aggState, aggState))
+}
+
+const lockSearchJobForNotificationFmtStr = `
+SELECT notified_at IS NOT NULL
+FROM exhaustive_search_jobs
+WHERE id = %s
+FOR UPDATE
+`
+
+const enqueueSearchJobNotificationsFmtStr = `
+WITH updated_job AS (
+    UPDATE exhaustive_search_jobs
+    SET notified_at = %s
+    WHERE id = %s
+    RETURNING id, webhook_url IS NOT NULL AS has_webhook
+)
+INSERT INTO exhaustive_search_job_notifications (search_job_id, kind, job_state)
+SELECT id, 'email', %s FROM updated_job
+UNION ALL
+SELECT id, 'summary', %s FROM updated_job
+UNION ALL
+SELECT id, 'webhook', %s FROM updated_job WHERE has_webhook
+`
+
+func scanNotification(sc dbutil.Scanner) (*types.ExhaustiveSearchJobNotification, error) {
+	var job types.ExhaustiveSearchJobNotification
+	// required field for the sync worker, but
+	// the value is thrown out here
+	var executionLogs *[]any
+
+	return &job, sc.Scan(
+		&job.ID,
+		&job.State,
+		&job.SearchJobID,
+		&job.Kind,
+		&job.JobState,
+		&dbutil.NullString{S: &job.FailureMessage},
+		&dbutil.NullTime{Time: &job.StartedAt},
+		&dbutil.NullTime{Time: &job.FinishedAt},
+		&dbutil.NullTime{Time: &job.ProcessAfter},
+		&job.NumResets,
+		&job.NumFailures,
+		&executionLogs,
+		&job.WorkerHostname,
+		&job.Cancel,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+}
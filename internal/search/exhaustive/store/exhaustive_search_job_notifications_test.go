@@ -0,0 +1,140 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+func TestStore_EnqueueSearchJobNotifications(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	_, err := createRepo(db, "repo1")
+	require.NoError(t, err)
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+
+	notifications := func(jobID int64) []string {
+		kinds, err := basestore.ScanStrings(s.Query(ctx, sqlf.Sprintf(
+			"SELECT kind || ':' || job_state FROM exhaustive_search_job_notifications WHERE search_job_id = %s ORDER BY id",
+			jobID,
+		)))
+		require.NoError(t, err)
+		return kinds
+	}
+
+	setRepoRevJobStates := func(jobID int64, state types.JobState) {
+		err := s.Exec(ctx, sqlf.Sprintf(`
+UPDATE exhaustive_search_repo_revision_jobs rrj
+SET state = %s
+FROM exhaustive_search_repo_jobs rj
+WHERE rrj.search_repo_job_id = rj.id AND rj.search_job_id = %s`, state, jobID))
+		require.NoError(t, err)
+	}
+
+	t.Run("running job", func(t *testing.T) {
+		jobID := createJobCascade(t, ctx, s, stateCascade{
+			searchJob:   types.JobStateCompleted,
+			repoJobs:    []types.JobState{types.JobStateCompleted},
+			repoRevJobs: []types.JobState{types.JobStateCompleted, types.JobStateProcessing},
+		})
+
+		enqueued, err := s.EnqueueSearchJobNotifications(ctx, jobID)
+		require.NoError(t, err)
+		require.False(t, enqueued)
+		require.Empty(t, notifications(jobID))
+	})
+
+	t.Run("finished job", func(t *testing.T) {
+		jobID := createJobCascade(t, ctx, s, stateCascade{
+			searchJob:   types.JobStateCompleted,
+			repoJobs:    []types.JobState{types.JobStateCompleted},
+			repoRevJobs: []types.JobState{types.JobStateCompleted, types.JobStateCompleted},
+		})
+
+		enqueued, err := s.EnqueueSearchJobNotifications(ctx, jobID)
+		require.NoError(t, err)
+		require.True(t, enqueued)
+
+		// Only the first call enqueues notifications.
+		enqueued, err = s.EnqueueSearchJobNotifications(ctx, jobID)
+		require.NoError(t, err)
+		require.False(t, enqueued)
+
+		require.Equal(t, []string{"email:completed", "summary:completed"}, notifications(jobID))
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		jobID := createJobCascade(t, ctx, s, stateCascade{
+			searchJob:   types.JobStateCompleted,
+			repoJobs:    []types.JobState{types.JobStateCompleted},
+			repoRevJobs: []types.JobState{types.JobStateCompleted},
+		})
+
+		err := s.SetExhaustiveSearchJobWebhook(ctx, jobID, "https://example.com/hook", "secret")
+		require.NoError(t, err)
+
+		enqueued, err := s.EnqueueSearchJobNotifications(ctx, jobID)
+		require.NoError(t, err)
+		require.True(t, enqueued)
+		require.Equal(t, []string{"email:completed", "summary:completed", "webhook:completed"}, notifications(jobID))
+
+		// Only internal actors can read the secret.
+		_, _, err = s.GetExhaustiveSearchJobWebhook(ctx, jobID)
+		require.Error(t, err)
+
+		url, secret, err := s.GetExhaustiveSearchJobWebhook(actor.WithInternalActor(context.Background()), jobID)
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/hook", url)
+		require.Equal(t, "secret", secret)
+	})
+
+	t.Run("retried job", func(t *testing.T) {
+		jobID := createJobCascade(t, ctx, s, stateCascade{
+			searchJob:   types.JobStateCompleted,
+			repoJobs:    []types.JobState{types.JobStateCompleted},
+			repoRevJobs: []types.JobState{types.JobStateCompleted, types.JobStateFailed},
+		})
+
+		enqueued, err := s.EnqueueSearchJobNotifications(ctx, jobID)
+		require.NoError(t, err)
+		require.True(t, enqueued)
+
+		retried, err := s.RetryFailedSearchJobTasks(ctx, jobID)
+		require.NoError(t, err)
+		require.Equal(t, 1, retried)
+
+		enqueued, err = s.EnqueueSearchJobNotifications(ctx, jobID)
+		require.NoError(t, err)
+		require.False(t, enqueued)
+
+		// Every time the job finishes, its initiator is notified and its summary
+		// is written again.
+		setRepoRevJobStates(jobID, types.JobStateCompleted)
+		enqueued, err = s.EnqueueSearchJobNotifications(ctx, jobID)
+		require.NoError(t, err)
+		require.True(t, enqueued)
+
+		require.Equal(t, []string{"email:failed", "summary:failed", "email:completed", "summary:completed"}, notifications(jobID))
+	})
+}
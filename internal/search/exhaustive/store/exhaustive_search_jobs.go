@@ -10,6 +10,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -23,6 +24,15 @@ import (
 const maxNumResets = 5
 const maxNumRetries = 3
 
+// DefaultStalledMaxAge is how long a worker may not send a heartbeat for a
+// record it processes before the resetter assumes the worker died and
+// requeues the record.
+const DefaultStalledMaxAge = 60 * time.Second
+
+// stalledFailureMessage is the failure message of records which were requeued
+// maxNumResets times because their workers stopped sending heartbeats.
+const stalledFailureMessage = "stalled: the worker processing this task stopped sending heartbeats too many times"
+
 var exhaustiveSearchJobWorkerOpts = dbworkerstore.Options[*types.ExhaustiveSearchJob]{
 	Name:              "exhaustive_search_worker_store",
 	TableName:         "exhaustive_search_jobs",
@@ -30,18 +40,23 @@ var exhaustiveSearchJobWorkerOpts = dbworkerstore.Options[*types.ExhaustiveSearc
 
 	Scan: dbworkerstore.BuildWorkerScan(scanExhaustiveSearchJob),
 
-	OrderByExpression: sqlf.Sprintf("exhaustive_search_jobs.state = 'errored', exhaustive_search_jobs.updated_at DESC"),
+	// Jobs with a higher priority go first, then the oldest job.
+	OrderByExpression: sqlf.Sprintf("exhaustive_search_jobs.state = 'errored', exhaustive_search_jobs.priority DESC, exhaustive_search_jobs.created_at"),
 
-	StalledMaxAge: 60 * time.Second,
-	MaxNumResets:  maxNumResets,
+	StalledMaxAge:       DefaultStalledMaxAge,
+	MaxNumResets:        maxNumResets,
+	ResetFailureMessage: stalledFailureMessage,
 
 	RetryAfter:    5 * time.Second,
 	MaxNumRetries: maxNumRetries,
 }
 
 // NewExhaustiveSearchJobWorkerStore returns a dbworkerstore.Store that wraps the "exhaustive_search_jobs" table.
-func NewExhaustiveSearchJobWorkerStore(observationCtx *observation.Context, handle basestore.TransactableHandle) dbworkerstore.Store[*types.ExhaustiveSearchJob] {
-	return dbworkerstore.New(observationCtx, handle, exhaustiveSearchJobWorkerOpts)
+// Records without a heartbeat for stalledMaxAge are requeued by the resetter.
+func NewExhaustiveSearchJobWorkerStore(observationCtx *observation.Context, handle basestore.TransactableHandle, stalledMaxAge time.Duration) dbworkerstore.Store[*types.ExhaustiveSearchJob] {
+	opts := exhaustiveSearchJobWorkerOpts
+	opts.StalledMaxAge = stalledMaxAge
+	return dbworkerstore.New(observationCtx, handle, opts)
 }
 
 var exhaustiveSearchJobColumns = []*sqlf.Query{
@@ -60,6 +75,23 @@ var exhaustiveSearchJobColumns = []*sqlf.Query{
 	sqlf.Sprintf("cancel"),
 	sqlf.Sprintf("created_at"),
 	sqlf.Sprintf("updated_at"),
+	sqlf.Sprintf("max_results"),
+	sqlf.Sprintf("result_count"),
+	sqlf.Sprintf("truncated"),
+	sqlf.Sprintf("priority"),
+	sqlf.Sprintf("created_from_job_id"),
+	sqlf.Sprintf("deadline"),
+	sqlf.Sprintf("deadline_exceeded"),
+	sqlf.Sprintf("expanded_at"),
+	sqlf.Sprintf("creation_source"),
+	sqlf.Sprintf("bytes_written"),
+	sqlf.Sprintf("sample_rate"),
+	sqlf.Sprintf("sample_seed"),
+	sqlf.Sprintf("sampled_task_count"),
+	sqlf.Sprintf("total_task_count"),
+	sqlf.Sprintf("deduplicate_results"),
+	sqlf.Sprintf("description"),
+	sqlf.Sprintf("labels"),
 }
 
 func (s *Store) CreateExhaustiveSearchJob(ctx context.Context, job types.ExhaustiveSearchJob) (_ int64, err error) {
@@ -81,9 +113,23 @@ func (s *Store) CreateExhaustiveSearchJob(ctx context.Context, job types.Exhaust
 		return 0, err
 	}
 
+	// 🚨 SECURITY: only site admins may jump the queue.
+	if job.Priority > types.JobPriorityNormal {
+		if err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db); err != nil {
+			return 0, err
+		}
+	}
+
+	// The column is NOT NULL, but a nil map would be stored as JSON null.
+	labels := job.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	now := s.clock.Now()
 	return basestore.ScanAny[int64](s.Store.QueryRow(
 		ctx,
-		sqlf.Sprintf(createExhaustiveSearchJobQueryFmtr, job.Query, job.InitiatorID),
+		sqlf.Sprintf(createExhaustiveSearchJobQueryFmtr, job.Query, job.InitiatorID, dbutil.NewNullInt(job.MaxResults), job.Priority, dbutil.NewNullInt64(job.CreatedFromJobID), dbutil.NullTimeColumn(job.Deadline), dbutil.NullStringColumn(string(job.CreationSource)), job.SampleRate, job.SampleSeed, job.DeduplicateResults, job.Description, dbutil.JSONMessage(&labels), now, now),
 	))
 }
 
@@ -94,38 +140,87 @@ var MissingQueryErr = errors.New("missing query")
 var MissingInitiatorIDErr = errors.New("missing initiator ID")
 
 const createExhaustiveSearchJobQueryFmtr = `
-INSERT INTO exhaustive_search_jobs (query, initiator_id)
-VALUES (%s, %s)
+INSERT INTO exhaustive_search_jobs (query, initiator_id, max_results, priority, created_from_job_id, deadline, creation_source, sample_rate, sample_seed, deduplicate_results, description, labels, created_at, updated_at)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING id
 `
 
-func (s *Store) CancelSearchJob(ctx context.Context, id int64) (totalCanceled int, err error) {
+// CancelSearchJobResult is the result of CancelSearchJob.
+type CancelSearchJobResult struct {
+	// PriorState is the aggregate state of the job before it was canceled. If
+	// it is canceled, completed or failed, nothing was changed.
+	PriorState types.JobState
+
+	// TotalCanceled is the number of jobs, repo jobs and repo revision jobs
+	// which were canceled.
+	TotalCanceled int
+}
+
+// CancelSearchJob cancels job id and its tasks which didn't finish yet. Jobs
+// which were already canceled or finished are left alone, their state is
+// returned as the PriorState of the result.
+//
+// It returns ErrNoResults if the job doesn't exist, and an auth error if the
+// actor may not access it.
+func (s *Store) CancelSearchJob(ctx context.Context, id int64) (res CancelSearchJobResult, err error) {
 	ctx, _, endObservation := s.operations.cancelSearchJob.With(ctx, &err, opAttrs(
 		attribute.Int64("ID", id),
 	))
 	defer endObservation(1, observation.Args{})
 
-	// 🚨 SECURITY: only someone with access to the job may cancel the job
-	err = s.UserHasAccess(ctx, id)
-	if err != nil {
-		return -1, err
-	}
+	// 🚨 SECURITY: only someone with access to the job may cancel the job. The
+	// initiator is checked by the statement that cancels the job, so the job
+	// can't change hands in between.
+	a := actor.FromContext(ctx)
+	mayCancelAny := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db) == nil
 
-	now := time.Now()
-	q := sqlf.Sprintf(cancelJobFmtStr, now, id, now, now)
+	now := s.clock.Now()
+	q := sqlf.Sprintf(
+		cancelJobFmtStr,
+		sqlf.Sprintf(
+			aggStateSubQuery,
+			sqlf.Sprintf(
+				getAggregateStateTable,
+				sqlf.Sprintf("exhaustive_search_jobs.id"),
+				sqlf.Sprintf("exhaustive_search_jobs.id"),
+				sqlf.Sprintf("exhaustive_search_jobs.id"),
+			),
+		),
+		id,
+		a.UID,
+		mayCancelAny,
+		now,
+		now,
+		now,
+	)
 
-	row := s.QueryRow(ctx, q)
+	var priorState *string
+	if err := s.QueryRow(ctx, q).Scan(&priorState, &res.TotalCanceled); err != nil {
+		return CancelSearchJobResult{}, err
+	}
 
-	err = row.Scan(&totalCanceled)
-	if err != nil {
-		return -1, err
+	if priorState == nil {
+		// Nothing was canceled. Tell apart a job which doesn't exist from one
+		// the actor may not access.
+		if err := s.UserHasAccess(ctx, id); err != nil {
+			return CancelSearchJobResult{}, err
+		}
+		return CancelSearchJobResult{}, ErrNoResults
 	}
+	res.PriorState = types.JobState(*priorState)
 
-	return totalCanceled, nil
+	return res, nil
 }
 
 const cancelJobFmtStr = `
-WITH updated_jobs AS (
+WITH target_job AS (
+    -- Lock the job, so its aggregate state can't change until we are done.
+    SELECT id, (%s) AS agg_state
+    FROM exhaustive_search_jobs
+    WHERE id = %s AND (initiator_id = %s OR %s)
+    FOR UPDATE
+),
+updated_jobs AS (
     -- Update the state of the main job
     UPDATE exhaustive_search_jobs
     SET CANCEL = TRUE,
@@ -133,7 +228,8 @@ WITH updated_jobs AS (
     -- state, so the worker can do teardown and later mark it failed.
     state = CASE WHEN exhaustive_search_jobs.state = 'processing' THEN exhaustive_search_jobs.state ELSE 'canceled' END,
     finished_at = CASE WHEN exhaustive_search_jobs.state = 'processing' THEN exhaustive_search_jobs.finished_at ELSE %s END
-    WHERE id = %s
+    -- Canceled and finished jobs keep their state.
+    WHERE id IN (SELECT id FROM target_job WHERE agg_state NOT IN ('canceled', 'completed', 'failed'))
     RETURNING id
 ),
 updated_repo_jobs AS (
@@ -144,7 +240,8 @@ updated_repo_jobs AS (
     -- state, so the worker can do teardown and later mark it failed.
     state = CASE WHEN exhaustive_search_repo_jobs.state = 'processing' THEN exhaustive_search_repo_jobs.state ELSE 'canceled' END,
     finished_at = CASE WHEN exhaustive_search_repo_jobs.state = 'processing' THEN exhaustive_search_repo_jobs.finished_at ELSE %s END
-    WHERE search_job_id IN (SELECT id FROM updated_jobs)
+    -- Finished repo jobs keep their state.
+    WHERE search_job_id IN (SELECT id FROM updated_jobs) AND state NOT IN ('completed', 'failed', 'canceled')
     RETURNING id
 ),
 updated_repo_revision_jobs AS (
@@ -155,10 +252,416 @@ updated_repo_revision_jobs AS (
 	-- state, so the worker can do teardown and later mark it failed.
     state = CASE WHEN exhaustive_search_repo_revision_jobs.state = 'processing' THEN exhaustive_search_repo_revision_jobs.state ELSE 'canceled' END,
     finished_at = CASE WHEN exhaustive_search_repo_revision_jobs.state = 'processing' THEN exhaustive_search_repo_revision_jobs.finished_at ELSE %s END
-    WHERE search_repo_job_id IN (SELECT id FROM updated_repo_jobs)
+    -- Finished repo revision jobs keep their state.
+    WHERE search_repo_job_id IN (SELECT rj.id FROM exhaustive_search_repo_jobs rj JOIN updated_jobs ON rj.search_job_id = updated_jobs.id)
+      AND state NOT IN ('completed', 'failed', 'canceled')
+    RETURNING id
+)
+SELECT
+    (SELECT agg_state FROM target_job) AS prior_state,
+    (SELECT count(*) FROM updated_jobs) + (SELECT count(*) FROM updated_repo_jobs) + (SELECT count(*) FROM updated_repo_revision_jobs) as total_canceled
+`
+
+// ResumeSearchJob clears the cancellation of job id and requeues the tasks
+// which were stopped by it, so the job continues where it stopped. It returns
+// how many were requeued. Completed tasks are left alone.
+//
+// Tasks which were in progress when the job was canceled were marked failed by
+// the workers. Tasks which failed before the job was canceled weren't
+// canceled, so they stay failed. The caller has to check that the job was
+// canceled and that none of its tasks is still in progress.
+func (s *Store) ResumeSearchJob(ctx context.Context, id int64) (totalResumed int, err error) {
+	ctx, _, endObservation := s.operations.resumeSearchJob.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may resume the job
+	err = s.UserHasAccess(ctx, id)
+	if err != nil {
+		return -1, err
+	}
+
+	q := sqlf.Sprintf(resumeJobFmtStr, id)
+
+	row := s.QueryRow(ctx, q)
+
+	err = row.Scan(&totalResumed)
+	if err != nil {
+		return -1, err
+	}
+
+	return totalResumed, nil
+}
+
+// resumeJobFmtStr resets stopped jobs like retryFailedTasksFmtStr. The search
+// job itself may have completed before it was canceled, so only its
+// cancellation and notification are reset then.
+const resumeJobFmtStr = `
+WITH updated_jobs AS (
+    UPDATE exhaustive_search_jobs
+    SET cancel = FALSE,
+    notified_at = NULL,
+    state = CASE WHEN state IN ('canceled', 'failed') THEN 'queued' ELSE state END,
+    failure_message = CASE WHEN state IN ('canceled', 'failed') THEN NULL ELSE failure_message END,
+    started_at = CASE WHEN state IN ('canceled', 'failed') THEN NULL ELSE started_at END,
+    finished_at = CASE WHEN state IN ('canceled', 'failed') THEN NULL ELSE finished_at END,
+    process_after = NULL,
+    num_failures = 0
+    WHERE id = %s AND cancel
+    RETURNING id, state = 'queued' AS requeued
+),
+updated_repo_jobs AS (
+    UPDATE exhaustive_search_repo_jobs
+    SET state = 'queued',
+    cancel = FALSE,
+    failure_message = NULL,
+    started_at = NULL,
+    finished_at = NULL,
+    process_after = NULL,
+    num_failures = 0,
+    num_resets = num_resets + 1
+    WHERE search_job_id IN (SELECT id FROM updated_jobs) AND cancel AND state IN ('canceled', 'failed')
+    RETURNING id
+),
+updated_repo_revision_jobs AS (
+    UPDATE exhaustive_search_repo_revision_jobs rrj
+    SET state = 'queued',
+    cancel = FALSE,
+    failure_message = NULL,
+    started_at = NULL,
+    finished_at = NULL,
+    process_after = NULL,
+    num_failures = 0,
+    num_resets = rrj.num_resets + 1
+    FROM exhaustive_search_repo_jobs rj
+    WHERE rrj.search_repo_job_id = rj.id AND rj.search_job_id IN (SELECT id FROM updated_jobs)
+      AND rrj.cancel AND rrj.state IN ('canceled', 'failed')
+    RETURNING rrj.id
+)
+SELECT (SELECT count(*) FROM updated_jobs WHERE requeued) + (SELECT count(*) FROM updated_repo_jobs) + (SELECT count(*) FROM updated_repo_revision_jobs) as total_resumed
+`
+
+// RetryFailedSearchJobTasks requeues the failed search job, repo jobs and repo
+// revision jobs of job id and returns how many were requeued. Completed and
+// canceled jobs are left alone.
+//
+// A failed search job or repo job did not create any children, since they are
+// created in a transaction, so requeueing them does not duplicate work.
+func (s *Store) RetryFailedSearchJobTasks(ctx context.Context, id int64) (totalRetried int, err error) {
+	ctx, _, endObservation := s.operations.retryFailedSearchJobTasks.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may retry the job
+	err = s.UserHasAccess(ctx, id)
+	if err != nil {
+		return -1, err
+	}
+
+	q := sqlf.Sprintf(retryFailedTasksFmtStr, id, id, id)
+
+	row := s.QueryRow(ctx, q)
+
+	err = row.Scan(&totalRetried)
+	if err != nil {
+		return -1, err
+	}
+
+	return totalRetried, nil
+}
+
+// retryFailedTasksFmtStr resets failed jobs like dbworker does for new jobs.
+// num_failures is reset so that retried jobs get all their retries again. The
+// search job runs again if any of its jobs is requeued, so its notification is
+// reset in the same statement, and its initiator is notified again once it
+// finished.
+const retryFailedTasksFmtStr = `
+WITH target_job AS (
+    SELECT id, state = 'failed' AND NOT cancel AS failed
+    FROM exhaustive_search_jobs
+    WHERE id = %s
+),
+updated_repo_jobs AS (
+    UPDATE exhaustive_search_repo_jobs
+    SET state = 'queued',
+    failure_message = NULL,
+    started_at = NULL,
+    finished_at = NULL,
+    process_after = NULL,
+    num_failures = 0,
+    num_resets = num_resets + 1
+    WHERE search_job_id = %s AND state = 'failed' AND NOT cancel
+    RETURNING id
+),
+updated_repo_revision_jobs AS (
+    UPDATE exhaustive_search_repo_revision_jobs rrj
+    SET state = 'queued',
+    failure_message = NULL,
+    started_at = NULL,
+    finished_at = NULL,
+    process_after = NULL,
+    num_failures = 0,
+    num_resets = rrj.num_resets + 1
+    FROM exhaustive_search_repo_jobs rj
+    WHERE rrj.search_repo_job_id = rj.id AND rj.search_job_id = %s AND rrj.state = 'failed' AND NOT rrj.cancel
+    RETURNING rrj.id
+),
+updated_jobs AS (
+    UPDATE exhaustive_search_jobs sj
+    SET notified_at = NULL,
+    state = CASE WHEN tj.failed THEN 'queued' ELSE sj.state END,
+    failure_message = CASE WHEN tj.failed THEN NULL ELSE sj.failure_message END,
+    started_at = CASE WHEN tj.failed THEN NULL ELSE sj.started_at END,
+    finished_at = CASE WHEN tj.failed THEN NULL ELSE sj.finished_at END,
+    process_after = CASE WHEN tj.failed THEN NULL ELSE sj.process_after END,
+    num_failures = CASE WHEN tj.failed THEN 0 ELSE sj.num_failures END,
+    num_resets = CASE WHEN tj.failed THEN sj.num_resets + 1 ELSE sj.num_resets END
+    FROM target_job tj
+    WHERE sj.id = tj.id
+      AND (tj.failed OR EXISTS (SELECT 1 FROM updated_repo_jobs) OR EXISTS (SELECT 1 FROM updated_repo_revision_jobs))
+    RETURNING tj.failed AS requeued
+)
+SELECT (SELECT count(*) FROM updated_jobs WHERE requeued) + (SELECT count(*) FROM updated_repo_jobs) + (SELECT count(*) FROM updated_repo_revision_jobs) as total_retried
+`
+
+// AddResultCount adds n to the number of results written by job id. Once the
+// job reaches its result limit, it is marked as truncated and its queued repo
+// and repo revision jobs are skipped. AddResultCount returns true if the limit
+// was already reached before adding n.
+//
+// The count is incremented in a single statement, so concurrent workers never
+// lose an update.
+func (s *Store) AddResultCount(ctx context.Context, id int64, n int) (limitReached bool, err error) {
+	ctx, _, endObservation := s.operations.addResultCount.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Int("n", n),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers may count results.
+	if err := checkInternalActor(ctx); err != nil {
+		return false, err
+	}
+
+	now := s.clock.Now()
+	limitReached, _, err = basestore.ScanFirstBool(s.Store.Query(ctx, sqlf.Sprintf(addResultCountFmtStr, n, n, id, n, now, now)))
+	return limitReached, err
+}
+
+const addResultCountFmtStr = `
+WITH updated_job AS (
+    UPDATE exhaustive_search_jobs
+    SET result_count = result_count + %s,
+    truncated = truncated OR (max_results IS NOT NULL AND result_count + %s >= max_results)
+    WHERE id = %s
+    -- result_count is the updated value here, so subtracting n gives the value
+    -- before the update.
+    RETURNING id, truncated, max_results IS NOT NULL AND result_count - %s >= max_results AS limit_reached
+),
+skipped_repo_jobs AS (
+    UPDATE exhaustive_search_repo_jobs
+    SET state = 'skipped', finished_at = %s
+    WHERE search_job_id IN (SELECT id FROM updated_job WHERE truncated)
+      AND state IN ('queued', 'errored')
+),
+skipped_repo_revision_jobs AS (
+    UPDATE exhaustive_search_repo_revision_jobs rrj
+    SET state = 'skipped', finished_at = %s
+    FROM exhaustive_search_repo_jobs rj
+    WHERE rrj.search_repo_job_id = rj.id
+      AND rj.search_job_id IN (SELECT id FROM updated_job WHERE truncated)
+      AND rrj.state IN ('queued', 'errored')
+)
+SELECT limit_reached FROM updated_job
+`
+
+// AddBytesWritten adds n to the BytesWritten of job id. The workers call it
+// once the results of a task were uploaded completely.
+func (s *Store) AddBytesWritten(ctx context.Context, id int64, n int64) (err error) {
+	ctx, _, endObservation := s.operations.addBytesWritten.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Int64("bytes", n),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers write results.
+	if err := checkInternalActor(ctx); err != nil {
+		return err
+	}
+
+	// A single statement, so concurrent tasks of the job don't lose updates.
+	return s.Exec(ctx, sqlf.Sprintf("UPDATE exhaustive_search_jobs SET bytes_written = bytes_written + %s WHERE id = %s", n, id))
+}
+
+// AddSampledTasks adds sampled and total to the SampledTaskCount and
+// TotalTaskCount of job id. The workers call it in the transaction which
+// creates the sampled tasks of a repository.
+func (s *Store) AddSampledTasks(ctx context.Context, id int64, sampled, total int) (err error) {
+	ctx, _, endObservation := s.operations.addSampledTasks.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Int("sampled", sampled),
+		attribute.Int("total", total),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers create tasks.
+	if err := checkInternalActor(ctx); err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(addSampledTasksFmtStr, sampled, total, id))
+}
+
+const addSampledTasksFmtStr = `
+UPDATE exhaustive_search_jobs
+SET sampled_task_count = sampled_task_count + %s,
+    total_task_count = total_task_count + %s
+WHERE id = %s
+`
+
+// MarkSearchJobExpanded records that job id created the tasks for all the
+// repositories of its query, see types.ExhaustiveSearchJob.ExpandedAt.
+func (s *Store) MarkSearchJobExpanded(ctx context.Context, id int64) (err error) {
+	ctx, _, endObservation := s.operations.markSearchJobExpanded.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers create tasks.
+	if err := checkInternalActor(ctx); err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf("UPDATE exhaustive_search_jobs SET expanded_at = %s WHERE id = %s", s.clock.Now(), id))
+}
+
+// RepairStalledSearchJobs fixes up the records of search jobs whose workers
+// died, for example because all workers were down, which the resetters can't
+// fix by requeueing them:
+//
+//   - A search job which created all its tasks, but stalled before its worker
+//     marked it completed, is marked completed. Requeueing it would only
+//     resolve its repositories again.
+//   - Records of canceled jobs are marked canceled, since no worker is left to
+//     tear them down.
+//
+// It returns the IDs of all jobs with stalled records, including the ones it
+// repaired. The caller requeues their remaining stalled records with the
+// resetters, and then enqueues their notifications, since their tasks may
+// have finished without a worker to do it.
+//
+// Records are stalled if they had no heartbeat for stalledMaxAge. Every
+// update checks that the record is still stalled, so it is safe to call
+// concurrently from several workers.
+func (s *Store) RepairStalledSearchJobs(ctx context.Context, stalledMaxAge time.Duration) (ids []int64, err error) {
+	ctx, _, endObservation := s.operations.repairStalledSearchJobs.With(ctx, &err, opAttrs(
+		attribute.Stringer("stalledMaxAge", stalledMaxAge),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("jobs", len(ids))))
+	}()
+
+	// 🚨 SECURITY: only the workers may repair jobs.
+	if err := checkInternalActor(ctx); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	stalledBefore := now.Add(-stalledMaxAge)
+	return basestore.ScanInt64s(s.Query(ctx, sqlf.Sprintf(
+		repairStalledSearchJobsFmtStr,
+		now, stalledBefore,
+		now, stalledBefore,
+		now, stalledBefore,
+		stalledBefore, stalledBefore, stalledBefore,
+	)))
+}
+
+// repairStalledSearchJobsFmtStr returns the jobs with stalled records as of
+// before the updates, since all statements of the query see the same
+// snapshot.
+const repairStalledSearchJobsFmtStr = `
+WITH repaired_jobs AS (
+    UPDATE exhaustive_search_jobs
+    SET state = CASE WHEN cancel THEN 'canceled' ELSE 'completed' END,
+    finished_at = %s
+    WHERE state = 'processing' AND last_heartbeat_at < %s
+      AND (expanded_at IS NOT NULL OR cancel)
+),
+canceled_repo_jobs AS (
+    UPDATE exhaustive_search_repo_jobs
+    SET state = 'canceled', finished_at = %s
+    WHERE state = 'processing' AND last_heartbeat_at < %s AND cancel
+),
+canceled_repo_revision_jobs AS (
+    UPDATE exhaustive_search_repo_revision_jobs
+    SET state = 'canceled', finished_at = %s
+    WHERE state = 'processing' AND last_heartbeat_at < %s AND cancel
+)
+SELECT id
+FROM exhaustive_search_jobs
+WHERE state = 'processing' AND last_heartbeat_at < %s
+UNION
+SELECT search_job_id
+FROM exhaustive_search_repo_jobs
+WHERE state = 'processing' AND last_heartbeat_at < %s
+UNION
+SELECT rj.search_job_id
+FROM exhaustive_search_repo_revision_jobs rrj
+JOIN exhaustive_search_repo_jobs rj ON rrj.search_repo_job_id = rj.id
+WHERE rrj.state = 'processing' AND rrj.last_heartbeat_at < %s
+ORDER BY 1
+`
+
+// SkipTasksAfterDeadline checks whether job id reached its deadline. If it did,
+// the job is marked as DeadlineExceeded and its queued and errored repo and
+// repo revision jobs are skipped. Tasks in progress on other workers finish.
+//
+// The handlers call it before they start a task. They pass the ID of the repo
+// job or repo revision job they are processing, so it is skipped too. The
+// other ID is 0.
+func (s *Store) SkipTasksAfterDeadline(ctx context.Context, id, repoJobID, repoRevJobID int64) (exceeded bool, err error) {
+	ctx, _, endObservation := s.operations.skipTasksAfterDeadline.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Int64("repoJobID", repoJobID),
+		attribute.Int64("repoRevJobID", repoRevJobID),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers may skip tasks.
+	if err := checkInternalActor(ctx); err != nil {
+		return false, err
+	}
+
+	now := s.clock.Now()
+	exceeded, _, err = basestore.ScanFirstBool(s.Store.Query(ctx, sqlf.Sprintf(skipTasksAfterDeadlineFmtStr, id, now, now, repoJobID, now, repoRevJobID)))
+	return exceeded, err
+}
+
+const skipTasksAfterDeadlineFmtStr = `
+WITH updated_job AS (
+    UPDATE exhaustive_search_jobs
+    SET deadline_exceeded = true
+    WHERE id = %s AND deadline IS NOT NULL AND deadline <= %s
     RETURNING id
+),
+skipped_repo_jobs AS (
+    UPDATE exhaustive_search_repo_jobs
+    SET state = 'skipped', finished_at = %s
+    WHERE search_job_id IN (SELECT id FROM updated_job)
+      AND (state IN ('queued', 'errored') OR (id = %s AND state = 'processing'))
+),
+skipped_repo_revision_jobs AS (
+    UPDATE exhaustive_search_repo_revision_jobs rrj
+    SET state = 'skipped', finished_at = %s
+    FROM exhaustive_search_repo_jobs rj
+    WHERE rrj.search_repo_job_id = rj.id
+      AND rj.search_job_id IN (SELECT id FROM updated_job)
+      AND (rrj.state IN ('queued', 'errored') OR (rrj.id = %s AND rrj.state = 'processing'))
 )
-SELECT (SELECT count(*) FROM updated_jobs) + (SELECT count(*) FROM updated_repo_jobs) + (SELECT count(*) FROM updated_repo_revision_jobs) as total_canceled
+SELECT true FROM updated_job
 `
 
 func listSearchJobQuery(where *sqlf.Query) *sqlf.Query {
@@ -174,6 +677,7 @@ func listSearchJobQuery(where *sqlf.Query) *sqlf.Query {
 				sqlf.Sprintf("exhaustive_search_jobs.id"),
 			),
 		),
+		types.DeletedInitiatorUsername,
 		where,
 	)
 }
@@ -187,7 +691,11 @@ func (s *Store) GetExhaustiveSearchJob(ctx context.Context, id int64) (_ *types.
 	where := sqlf.Sprintf("WHERE id = %d", id)
 	q := listSearchJobQuery(where)
 
-	job, err := scanExhaustiveSearchJobList(s.Store.QueryRow(ctx, q))
+	var job *types.ExhaustiveSearchJob
+	err = s.read(ctx, func(db *basestore.Store) (err error) {
+		job, err = scanExhaustiveSearchJobList(db.QueryRow(ctx, q))
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.Wrapf(ErrNoResults, "failed to scan job with id %d: %s", id, err.Error())
@@ -242,6 +750,91 @@ func (s *Store) UserHasAccess(ctx context.Context, id int64) (err error) {
 	return auth.CheckSiteAdminOrSameUser(ctx, s.db, initiatorID)
 }
 
+// ListInaccessibleRepos returns the IDs of the repositories which job id has
+// tasks in, but which user userID can't read, for example since their
+// permissions changed after the job ran. Deleted repositories can't be read
+// either.
+func (s *Store) ListInaccessibleRepos(ctx context.Context, id int64, userID int32) (repoIDs []api.RepoID, err error) {
+	ctx, _, endObservation := s.operations.listInaccessibleRepos.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Int("userID", int(userID)),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("inaccessible", len(repoIDs))))
+	}()
+
+	// 🚨 SECURITY: only someone with access to the job may learn about its
+	// repositories.
+	if err := s.UserHasAccess(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: the permissions of userID apply, not those of the actor.
+	authzConds, err := database.AuthzQueryConds(actor.WithActor(ctx, actor.FromUser(userID)), s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.read(ctx, func(db *basestore.Store) (err error) {
+		repoIDs, err = basestore.NewSliceScanner(basestore.ScanAny[api.RepoID])(db.Query(ctx, sqlf.Sprintf(listInaccessibleReposFmtStr, id, authzConds)))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repoIDs, nil
+}
+
+const listInaccessibleReposFmtStr = `
+SELECT DISTINCT rj.repo_id
+FROM exhaustive_search_repo_jobs rj
+WHERE
+	rj.search_job_id = %s
+	AND EXISTS (SELECT 1 FROM exhaustive_search_repo_revision_jobs rjj WHERE rjj.search_repo_job_id = rj.id)
+	AND NOT EXISTS (
+		SELECT 1 FROM repo
+		WHERE repo.id = rj.repo_id AND repo.deleted_at IS NULL AND %s
+	)
+ORDER BY rj.repo_id
+`
+
+// UpdateExhaustiveSearchJobMetadata replaces the description and the labels of
+// job id. It returns ErrNoResults if the job doesn't exist.
+func (s *Store) UpdateExhaustiveSearchJobMetadata(ctx context.Context, id int64, description string, labels map[string]string) (err error) {
+	ctx, _, endObservation := s.operations.updateExhaustiveSearchJobMetadata.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Int("labels", len(labels)),
+	))
+	defer endObservation(1, observation.Args{})
+
+	initiatorID, err := basestore.ScanAny[int32](s.Store.QueryRow(ctx, sqlf.Sprintf("SELECT initiator_id FROM exhaustive_search_jobs WHERE id = %s", id)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrapf(ErrNoResults, "failed to scan job with id %d: %s", id, err.Error())
+		}
+		return err
+	}
+
+	// 🚨 SECURITY: the metadata belongs to the initiator, so unlike the rest of
+	// the job, not even site admins may change it.
+	if err := auth.CheckSameUser(ctx, initiatorID); err != nil {
+		return err
+	}
+
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(updateExhaustiveSearchJobMetadataFmtStr, description, dbutil.JSONMessage(&labels), s.clock.Now(), id, initiatorID))
+}
+
+const updateExhaustiveSearchJobMetadataFmtStr = `
+UPDATE exhaustive_search_jobs
+SET description = %s, labels = %s, updated_at = %s
+-- The initiator is checked again, so the job can't change hands in between.
+WHERE id = %s AND initiator_id = %s
+`
+
 // aggStateSubQuery takes the results from getAggregateStateTable and computes a
 // single aggregate state that reflects the state of the entire search job
 // cascade better than the state of the top-level worker.
@@ -293,18 +886,25 @@ type ListArgs struct {
 	Query   string
 	States  []string
 	UserIDs []int32
-}
 
-func (s *Store) ListExhaustiveSearchJobs(ctx context.Context, args ListArgs) (jobs []*types.ExhaustiveSearchJob, err error) {
-	ctx, _, endObservation := s.operations.listExhaustiveSearchJobs.With(ctx, &err, observation.Args{})
-	defer func() {
-		endObservation(1, opAttrs(attribute.Int("length", len(jobs))))
-	}()
+	// AllUsers lists the jobs of all users. Only site admins may set it. Site
+	// admins also see the jobs of all users if they neither set AllUsers nor
+	// UserIDs.
+	AllUsers bool
+
+	// Labels only lists the jobs which have all of these labels, with the
+	// same values. The jobs may have other labels too.
+	Labels map[string]string
+}
 
+// listConds returns the conditions to filter the jobs listed by
+// ListExhaustiveSearchJobs and CountExhaustiveSearchJobs, without pagination.
+func (s *Store) listConds(ctx context.Context, args ListArgs) ([]*sqlf.Query, error) {
 	a := actor.FromContext(ctx)
 
-	// 🚨 SECURITY: Only authenticated users can list search jobs.
-	if !a.IsAuthenticated() {
+	// 🚨 SECURITY: Only internal actors and authenticated users can list search
+	// jobs.
+	if !a.IsInternal() && !a.IsAuthenticated() {
 		return nil, errors.New("can only list jobs for an authenticated user")
 	}
 
@@ -324,9 +924,14 @@ func (s *Store) ListExhaustiveSearchJobs(ctx context.Context, args ListArgs) (jo
 		conds = append(conds, sqlf.Sprintf("agg_state in (%s)", sqlf.Join(states, ",")))
 	}
 
-	// 🚨 SECURITY: Site admins see any job and may filter based on args.UserIDs.
-	// Other users only see their own jobs.
-	isSiteAdmin := auth.CheckUserIsSiteAdmin(ctx, s.db, a.UID) == nil
+	// Filter by labels.
+	if len(args.Labels) > 0 {
+		conds = append(conds, sqlf.Sprintf("labels @> %s::jsonb", dbutil.JSONMessage(&args.Labels)))
+	}
+
+	// 🚨 SECURITY: Internal actors and site admins see any job and may filter
+	// based on args.UserIDs. Other users only see their own jobs.
+	isSiteAdmin := a.IsInternal() || auth.CheckUserIsSiteAdmin(ctx, s.db, a.UID) == nil
 	if isSiteAdmin {
 		if len(args.UserIDs) > 0 {
 			ids := make([]*sqlf.Query, len(args.UserIDs))
@@ -336,41 +941,316 @@ func (s *Store) ListExhaustiveSearchJobs(ctx context.Context, args ListArgs) (jo
 			conds = append(conds, sqlf.Sprintf("initiator_id in (%s)", sqlf.Join(ids, ",")))
 		}
 	} else {
-		if len(args.UserIDs) > 0 {
-			return nil, errors.New("cannot filter by user id if not a site admin")
+		if args.AllUsers || len(args.UserIDs) > 0 {
+			return nil, auth.ErrMustBeSiteAdmin
 		}
 		conds = append(conds, sqlf.Sprintf("initiator_id = %d", a.UID))
 	}
 
-	var pagination *database.QueryArgs
-	if args.PaginationArgs != nil {
-		pagination = args.PaginationArgs.SQL()
-		if pagination.Where != nil {
-			conds = append(conds, pagination.Where)
+	return conds, nil
+}
+
+func whereClause(conds []*sqlf.Query) *sqlf.Query {
+	if len(conds) == 0 {
+		return sqlf.Sprintf("")
+	}
+	return sqlf.Sprintf("WHERE %s", sqlf.Join(conds, "\n AND "))
+}
+
+func (s *Store) ListExhaustiveSearchJobs(ctx context.Context, args ListArgs) (jobs []*types.ExhaustiveSearchJob, err error) {
+	ctx, _, endObservation := s.operations.listExhaustiveSearchJobs.With(ctx, &err, observation.Args{})
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("length", len(jobs))))
+	}()
+
+	conds, err := s.listConds(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyset *basestore.Keyset
+	var limit *sqlf.Query
+	if p := args.PaginationArgs; p != nil {
+		keyset = &basestore.Keyset{Columns: []string{"id"}, Descending: !p.Ascending}
+		if len(p.OrderBy) > 0 {
+			keyset.Columns = p.OrderBy.Columns()
+		}
+		if len(p.After) > 0 {
+			conds = append(conds, keyset.After(p.After))
+		}
+		if len(p.Before) > 0 {
+			conds = append(conds, keyset.Before(p.Before))
+		}
+		if p.First != nil {
+			limit = sqlf.Sprintf("LIMIT %d", *p.First)
+		} else if p.Last != nil {
+			// The connection resolver reverses the rows of the last page.
+			*keyset = keyset.Reverse()
+			limit = sqlf.Sprintf("LIMIT %d", *p.Last)
 		}
 	}
 
-	var whereClause *sqlf.Query
-	if len(conds) != 0 {
-		whereClause = sqlf.Sprintf("WHERE %s", sqlf.Join(conds, "\n AND "))
-	} else {
-		whereClause = sqlf.Sprintf("")
+	q := listSearchJobQuery(whereClause(conds))
+	if keyset != nil {
+		q = sqlf.Sprintf("%s ORDER BY %s", q, keyset.OrderBy())
+	}
+	if limit != nil {
+		q = sqlf.Sprintf("%s %s", q, limit)
 	}
 
-	q := listSearchJobQuery(whereClause)
-	if pagination != nil {
-		q = pagination.AppendOrderToQuery(q)
-		q = pagination.AppendLimitToQuery(q)
+	err = s.read(ctx, func(db *basestore.Store) (err error) {
+		jobs, err = scanExhaustiveSearchJobsList(db.Query(ctx, q))
+		return err
+	})
+	return jobs, err
+}
+
+// CountExhaustiveSearchJobs returns the number of jobs ListExhaustiveSearchJobs
+// returns for args, ignoring args.PaginationArgs.
+func (s *Store) CountExhaustiveSearchJobs(ctx context.Context, args ListArgs) (count int, err error) {
+	ctx, _, endObservation := s.operations.countExhaustiveSearchJobs.With(ctx, &err, observation.Args{})
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("count", count)))
+	}()
+
+	conds, err := s.listConds(ctx, args)
+	if err != nil {
+		return 0, err
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM (%s) AS jobs", listSearchJobQuery(whereClause(conds)))
+	err = s.read(ctx, func(db *basestore.Store) (err error) {
+		count, err = basestore.ScanInt(db.QueryRow(ctx, q))
+		return err
+	})
+	return count, err
+}
+
+// CountActiveSearchJobs returns the number of search jobs which are queued or
+// in progress. A job is in progress until all of its tasks finished or it was
+// canceled. If initiatorID is not 0, only the jobs of this user are counted.
+func (s *Store) CountActiveSearchJobs(ctx context.Context, initiatorID int32) (count int, err error) {
+	ctx, _, endObservation := s.operations.countActiveSearchJobs.With(ctx, &err, opAttrs(
+		attribute.Int("initiatorID", int(initiatorID)),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("count", count)))
+	}()
+
+	cond := sqlf.Sprintf("TRUE")
+	if initiatorID != 0 {
+		cond = sqlf.Sprintf("sj.initiator_id = %s", initiatorID)
+	}
+
+	return basestore.ScanInt(s.Store.QueryRow(ctx, sqlf.Sprintf(countActiveSearchJobsFmtStr, cond)))
+}
+
+// countActiveSearchJobsFmtStr relies on the indexes on the initiator and the
+// parent IDs, so it only looks at the tasks of the counted jobs.
+const countActiveSearchJobsFmtStr = `
+SELECT COUNT(*)
+FROM exhaustive_search_jobs sj
+WHERE %s AND NOT sj.cancel AND (
+    sj.state IN ('queued', 'processing', 'errored')
+    OR EXISTS (
+        SELECT 1
+        FROM exhaustive_search_repo_jobs rj
+        WHERE rj.search_job_id = sj.id AND rj.state IN ('queued', 'processing', 'errored')
+    )
+    OR EXISTS (
+        SELECT 1
+        FROM exhaustive_search_repo_revision_jobs rrj
+        JOIN exhaustive_search_repo_jobs rj ON rrj.search_repo_job_id = rj.id
+        WHERE rj.search_job_id = sj.id AND rrj.state IN ('queued', 'processing', 'errored')
+    )
+)
+`
+
+// GetSearchJobProgress returns how many tasks of job id are left.
+func (s *Store) GetSearchJobProgress(ctx context.Context, id int64) (_ types.SearchJobProgress, err error) {
+	ctx, _, endObservation := s.operations.getSearchJobProgress.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may see its progress
+	if err := s.UserHasAccess(ctx, id); err != nil {
+		return types.SearchJobProgress{}, err
+	}
+
+	var progress types.SearchJobProgress
+	err = s.read(ctx, func(db *basestore.Store) error {
+		return db.QueryRow(ctx, sqlf.Sprintf(getSearchJobProgressFmtStr, id, id, id)).Scan(
+			&progress.Total,
+			&progress.Remaining,
+		)
+	})
+	return progress, err
+}
+
+// getSearchJobProgressFmtStr is polled by the UI. The indexes on the parent ID
+// and the state of the repo and repo revision jobs keep it from scanning the
+// tasks of other jobs.
+const getSearchJobProgressFmtStr = `
+SELECT
+    COUNT(*),
+    COUNT(*) FILTER (WHERE state IN ('queued', 'processing', 'errored'))
+FROM (
+    SELECT sj.state
+    FROM exhaustive_search_jobs sj
+    WHERE sj.id = %s
+    UNION ALL
+    SELECT rj.state
+    FROM exhaustive_search_repo_jobs rj
+    WHERE rj.search_job_id = %s
+    UNION ALL
+    SELECT rrj.state
+    FROM exhaustive_search_repo_revision_jobs rrj
+    JOIN exhaustive_search_repo_jobs rj ON rrj.search_repo_job_id = rj.id
+    WHERE rj.search_job_id = %s
+) AS tasks
+`
+
+// GetSearchJobsBacklog returns the number of records of all search jobs the
+// workers still have to process.
+func (s *Store) GetSearchJobsBacklog(ctx context.Context) (_ types.SearchJobsBacklog, err error) {
+	ctx, _, endObservation := s.operations.getSearchJobsBacklog.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: the backlog includes the jobs of all users.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db); err != nil {
+		return types.SearchJobsBacklog{}, err
 	}
 
-	return scanExhaustiveSearchJobsList(s.Store.Query(ctx, q))
+	var backlog types.SearchJobsBacklog
+	err = s.read(ctx, func(db *basestore.Store) error {
+		return db.QueryRow(ctx, sqlf.Sprintf(getSearchJobsBacklogFmtStr)).Scan(
+			&backlog.Jobs,
+			&backlog.RepoJobs,
+			&backlog.RevisionJobs,
+			&backlog.Notifications,
+		)
+	})
+	return backlog, err
+}
+
+// getSearchJobsBacklogFmtStr only reads the records in the counted states, via
+// the indexes on state.
+const getSearchJobsBacklogFmtStr = `
+SELECT
+    (SELECT COUNT(*) FROM exhaustive_search_jobs WHERE state IN ('queued', 'processing', 'errored')),
+    (SELECT COUNT(*) FROM exhaustive_search_repo_jobs WHERE state IN ('queued', 'processing', 'errored')),
+    (SELECT COUNT(*) FROM exhaustive_search_repo_revision_jobs WHERE state IN ('queued', 'processing', 'errored')),
+    (SELECT COUNT(*) FROM exhaustive_search_job_notifications WHERE state IN ('queued', 'processing', 'errored'))
+`
+
+// GetExportVolumeByUser returns how much result data the jobs created in
+// [after, before) produced, by initiator, ordered by the bytes written. Only
+// site admins may see it.
+func (s *Store) GetExportVolumeByUser(ctx context.Context, after, before time.Time) (volumes []types.UserExportVolume, err error) {
+	ctx, _, endObservation := s.operations.getExportVolumeByUser.With(ctx, &err, opAttrs(
+		attribute.String("after", after.String()),
+		attribute.String("before", before.String()),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: the volume includes the jobs of all users.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db); err != nil {
+		return nil, err
+	}
+
+	err = s.read(ctx, func(db *basestore.Store) (err error) {
+		volumes, err = scanUserExportVolumes(db.Query(ctx, sqlf.Sprintf(getExportVolumeByUserFmtStr, after, before)))
+		return err
+	})
+	return volumes, err
+}
+
+var scanUserExportVolumes = basestore.NewSliceScanner(func(sc dbutil.Scanner) (v types.UserExportVolume, err error) {
+	err = sc.Scan(&v.UserID, &v.Username, &v.Jobs, &v.BytesWritten)
+	return v, err
+})
+
+const getExportVolumeByUserFmtStr = `
+SELECT j.initiator_id, u.username, COUNT(*), SUM(j.bytes_written)
+FROM exhaustive_search_jobs j
+JOIN users u ON u.id = j.initiator_id
+WHERE j.created_at >= %s AND j.created_at < %s
+GROUP BY j.initiator_id, u.username
+ORDER BY SUM(j.bytes_written) DESC, j.initiator_id
+`
+
+// GetWorkerTaskStats returns how many repo revision jobs each worker process
+// finished in [after, before), ordered by the number of failed jobs. Jobs
+// which no worker claimed are left out. Only site admins may see it.
+func (s *Store) GetWorkerTaskStats(ctx context.Context, after, before time.Time) (stats []types.WorkerTaskStats, err error) {
+	ctx, _, endObservation := s.operations.getWorkerTaskStats.With(ctx, &err, opAttrs(
+		attribute.String("after", after.String()),
+		attribute.String("before", before.String()),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: the stats include the jobs of all users.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db); err != nil {
+		return nil, err
+	}
+
+	err = s.read(ctx, func(db *basestore.Store) (err error) {
+		stats, err = scanWorkerTaskStats(db.Query(ctx, sqlf.Sprintf(getWorkerTaskStatsFmtStr, after, before)))
+		return err
+	})
+	return stats, err
+}
+
+var scanWorkerTaskStats = basestore.NewSliceScanner(func(sc dbutil.Scanner) (v types.WorkerTaskStats, err error) {
+	err = sc.Scan(&v.WorkerHostname, &dbutil.NullTime{Time: &v.WorkerStartedAt}, &v.Tasks, &v.Completed, &v.Failed)
+	return v, err
+})
+
+const getWorkerTaskStatsFmtStr = `
+SELECT
+    worker_hostname,
+    worker_started_at,
+    COUNT(*),
+    COUNT(*) FILTER (WHERE state = 'completed'),
+    COUNT(*) FILTER (WHERE state = 'failed')
+FROM exhaustive_search_repo_revision_jobs
+WHERE finished_at >= %s AND finished_at < %s AND worker_hostname != ''
+GROUP BY worker_hostname, worker_started_at
+ORDER BY COUNT(*) FILTER (WHERE state = 'failed') DESC, worker_hostname, worker_started_at
+`
+
+// CurrentUserIsSiteAdmin returns true if the actor of ctx is a site admin or
+// an internal actor.
+func (s *Store) CurrentUserIsSiteAdmin(ctx context.Context) (bool, error) {
+	err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db)
+	if errors.Is(err, auth.ErrMustBeSiteAdmin) {
+		return false, nil
+	}
+	return err == nil, err
 }
 
 const listExhaustiveSearchJobsQueryFmtStr = `
-SELECT * FROM (SELECT %s, (%s) as agg_state FROM exhaustive_search_jobs) as outer_query
+SELECT * FROM (
+    SELECT %s, (%s) as agg_state, initiator.username as initiator_username, initiator.email as initiator_email
+    FROM exhaustive_search_jobs
+    -- The columns of the search job aren't qualified, so the initiator is
+    -- joined laterally, which only adds the columns it selects.
+    LEFT JOIN LATERAL (
+        SELECT
+            CASE WHEN u.deleted_at IS NULL THEN u.username::text ELSE %s END AS username,
+            CASE WHEN u.deleted_at IS NULL THEN e.email END AS email
+        FROM users u
+        LEFT JOIN user_emails e ON e.user_id = u.id AND e.is_primary
+        WHERE u.id = exhaustive_search_jobs.initiator_id
+    ) initiator ON true
+) as outer_query
 %s -- whereClause
 `
 
+// deleteExhaustiveSearchJobQueryFmtStr deletes a search job. Its repo and repo
+// revision jobs are deleted in the same statement by the ON DELETE CASCADE
+// foreign keys.
 const deleteExhaustiveSearchJobQueryFmtStr = `
 DELETE FROM exhaustive_search_jobs
 WHERE id = %d
@@ -391,6 +1271,75 @@ func (s *Store) DeleteExhaustiveSearchJob(ctx context.Context, id int64) (err er
 	return s.Exec(ctx, sqlf.Sprintf(deleteExhaustiveSearchJobQueryFmtStr, id))
 }
 
+// LockExhaustiveSearchJob locks the row of search job id until the end of the
+// transaction, which s must be. Deleting, resuming, and retrying a job lock it
+// first, so that a job can't start running again while it is being deleted.
+func (s *Store) LockExhaustiveSearchJob(ctx context.Context, id int64) (err error) {
+	ctx, _, endObservation := s.operations.lockExhaustiveSearchJob.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	if !s.InTransaction() {
+		return errors.New("can only lock a search job in a transaction")
+	}
+
+	// 🚨 SECURITY: only someone with access to the job may lock the job
+	err = s.UserHasAccess(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	_, ok, err := basestore.ScanFirstInt64(s.Store.Query(ctx, sqlf.Sprintf(lockExhaustiveSearchJobFmtStr, id)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// The job was deleted after we checked the access.
+		return ErrNoResults
+	}
+	return nil
+}
+
+const lockExhaustiveSearchJobFmtStr = `
+SELECT id
+FROM exhaustive_search_jobs
+WHERE id = %s
+FOR UPDATE
+`
+
+// ListExpiredExhaustiveSearchJobIDs returns the IDs of at most limit jobs
+// created before the given time, oldest first.
+func (s *Store) ListExpiredExhaustiveSearchJobIDs(ctx context.Context, before time.Time, limit int) (ids []int64, err error) {
+	ctx, _, endObservation := s.operations.listExpiredExhaustiveSearchJobIDs.With(ctx, &err, opAttrs(
+		attribute.String("before", before.String()),
+		attribute.Int("limit", limit),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("length", len(ids))))
+	}()
+
+	// 🚨 SECURITY: this lists the jobs of all users, so only internal actors
+	// may call it.
+	if !actor.FromContext(ctx).IsInternal() {
+		return nil, errors.New("can only list expired jobs as an internal actor")
+	}
+
+	return basestore.ScanInt64s(s.Store.Query(ctx, sqlf.Sprintf(listExpiredExhaustiveSearchJobIDsFmtStr, before, limit)))
+}
+
+const listExpiredExhaustiveSearchJobIDsFmtStr = `
+SELECT id
+FROM exhaustive_search_jobs
+WHERE created_at < %s
+ORDER BY created_at, id
+LIMIT %s
+`
+
+// getAggregateStateTable counts the states of a search job and all its repo
+// and repo revision jobs. It is a single statement, so the counts come from
+// one snapshot even while workers are transitioning rows.
+//
 // | state      | count |
 // |------------|-------|
 // | processing | 2     |
@@ -432,11 +1381,24 @@ func (s *Store) GetAggregateRepoRevState(ctx context.Context, id int64) (_ map[s
 
 	q := sqlf.Sprintf(getAggregateStateTable, id, id, id)
 
-	rows, err := s.Store.Query(ctx, q)
+	var m map[string]int
+	err = s.read(ctx, func(db *basestore.Store) (err error) {
+		m, err = scanStateCounts(db.Query(ctx, q))
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return m, nil
+}
+
+// scanStateCounts scans the rows of getAggregateStateTable into a map from the
+// state to its count.
+func scanStateCounts(rows *sql.Rows, queryErr error) (_ map[string]int, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
 
 	m := make(map[string]int)
 	for rows.Next() {
@@ -448,7 +1410,6 @@ func (s *Store) GetAggregateRepoRevState(ctx context.Context, id int64) (_ map[s
 
 		m[state] = count
 	}
-
 	return m, nil
 }
 
@@ -456,11 +1417,16 @@ const getJobLogsFmtStr = `
 SELECT
 rjj.id,
 r.name,
+rj.repo_id,
 rjj.revision,
+rjj.commit_id,
 rjj.state,
 rjj.failure_message,
 rjj.started_at,
-rjj.finished_at
+rjj.finished_at,
+rjj.worker_hostname,
+rjj.worker_started_at,
+rjj.batch_id
 FROM exhaustive_search_repo_revision_jobs rjj
 JOIN exhaustive_search_repo_jobs rj ON rjj.search_repo_job_id = rj.id
 JOIN repo r ON r.id = rj.repo_id
@@ -470,12 +1436,21 @@ JOIN repo r ON r.id = rj.repo_id
 type GetJobLogsOpts struct {
 	From  int64
 	Limit int
+
+	// State only returns the logs of repo revision jobs in this state if set.
+	State types.JobState
 }
 
-func (s *Store) GetJobLogs(ctx context.Context, id int64, opts *GetJobLogsOpts) ([]types.SearchJobLog, error) {
+func (s *Store) GetJobLogs(ctx context.Context, id int64, opts *GetJobLogsOpts) (logs []types.SearchJobLog, err error) {
+	ctx, _, endObservation := s.operations.getJobLogs.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("length", len(logs))))
+	}()
+
 	// 🚨 SECURITY: only someone with access to the job may access the logs
-	err := s.UserHasAccess(ctx, id)
-	if err != nil {
+	if err := s.UserHasAccess(ctx, id); err != nil {
 		return nil, err
 	}
 
@@ -486,6 +1461,10 @@ func (s *Store) GetJobLogs(ctx context.Context, id int64, opts *GetJobLogsOpts)
 			conds = append(conds, sqlf.Sprintf("rjj.id >= %s", opts.From))
 		}
 
+		if opts.State != "" {
+			conds = append(conds, sqlf.Sprintf("rjj.state = %s", opts.State))
+		}
+
 		if opts.Limit != 0 {
 			limit = sqlf.Sprintf("LIMIT %s", opts.Limit)
 		}
@@ -499,30 +1478,65 @@ func (s *Store) GetJobLogs(ctx context.Context, id int64, opts *GetJobLogsOpts)
 		q = sqlf.Sprintf("%v %v", q, limit)
 	}
 
-	rows, err := s.Store.Query(ctx, q)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	err = s.read(ctx, func(db *basestore.Store) (err error) {
+		logs, err = basestore.NewSliceScanner(scanJobLog)(db.Query(ctx, q))
+		return err
+	})
+	return logs, err
+}
 
-	var jobs []types.SearchJobLog
-	for rows.Next() {
-		job := types.SearchJobLog{}
-		if err := rows.Scan(
-			&job.ID,
-			&job.RepoName,
-			&job.Revision,
-			&job.State,
-			&dbutil.NullString{S: &job.FailureMessage},
-			&dbutil.NullTime{Time: &job.StartedAt},
-			&dbutil.NullTime{Time: &job.FinishedAt},
-		); err != nil {
-			return nil, err
-		}
-		jobs = append(jobs, job)
+// ScanJobLogs calls f with the logs of the repo revision jobs of job id,
+// ordered by ID. The logs are read batchSize at a time, so that the logs of
+// large jobs are neither held in memory nor read in a single query.
+func (s *Store) ScanJobLogs(ctx context.Context, id int64, batchSize int, f func(types.SearchJobLog) error) (err error) {
+	ctx, _, endObservation := s.operations.scanJobLogs.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Int("batchSize", batchSize),
+	))
+	var scanned int
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("scanned", scanned)))
+	}()
+
+	// 🚨 SECURITY: only someone with access to the job may access the logs
+	if err := s.UserHasAccess(ctx, id); err != nil {
+		return err
 	}
 
-	return jobs, nil
+	return basestore.ScanBatches(
+		ctx,
+		s.Store,
+		basestore.Keyset{Columns: []string{"rjj.id"}},
+		batchSize,
+		func(cond *sqlf.Query) *sqlf.Query {
+			return sqlf.Sprintf(getJobLogsFmtStr, sqlf.Sprintf("WHERE rj.search_job_id = %s AND %s", id, cond))
+		},
+		func(sc dbutil.Scanner) ([]any, error) {
+			log, err := scanJobLog(sc)
+			if err != nil {
+				return nil, err
+			}
+			scanned++
+			return []any{log.ID}, f(log)
+		},
+	)
+}
+
+func scanJobLog(sc dbutil.Scanner) (log types.SearchJobLog, _ error) {
+	return log, sc.Scan(
+		&log.ID,
+		&log.RepoName,
+		&log.RepoID,
+		&log.Revision,
+		&dbutil.NullString{S: (*string)(&log.CommitID)},
+		&log.State,
+		&dbutil.NullString{S: &log.FailureMessage},
+		&dbutil.NullTime{Time: &log.StartedAt},
+		&dbutil.NullTime{Time: &log.FinishedAt},
+		&log.WorkerHostname,
+		&dbutil.NullTime{Time: &log.WorkerStartedAt},
+		&dbutil.NullInt64{N: &log.BatchID},
+	)
 }
 
 func defaultScanTargets(job *types.ExhaustiveSearchJob) []any {
@@ -546,6 +1560,23 @@ func defaultScanTargets(job *types.ExhaustiveSearchJob) []any {
 		&job.Cancel,
 		&job.CreatedAt,
 		&job.UpdatedAt,
+		&dbutil.NullInt{N: &job.MaxResults},
+		&job.ResultCount,
+		&job.Truncated,
+		&job.Priority,
+		&dbutil.NullInt64{N: &job.CreatedFromJobID},
+		&dbutil.NullTime{Time: &job.Deadline},
+		&job.DeadlineExceeded,
+		&dbutil.NullTime{Time: &job.ExpandedAt},
+		&dbutil.NullString{S: (*string)(&job.CreationSource)},
+		&job.BytesWritten,
+		&job.SampleRate,
+		&job.SampleSeed,
+		&job.SampledTaskCount,
+		&job.TotalTaskCount,
+		&job.DeduplicateResults,
+		&job.Description,
+		dbutil.JSONMessage(&job.Labels),
 	}
 }
 
@@ -564,6 +1595,8 @@ func scanExhaustiveSearchJobList(sc dbutil.Scanner) (*types.ExhaustiveSearchJob,
 		append(
 			defaultScanTargets(&job),
 			&job.AggState,
+			&dbutil.NullString{S: &job.InitiatorUsername},
+			&dbutil.NullString{S: &job.InitiatorEmail},
 		)...,
 	)
 }
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/derision-test/glock"
 	"github.com/google/go-cmp/cmp"
 	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/log"
 	"github.com/sourcegraph/log/logtest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore/querytest"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
@@ -105,6 +110,34 @@ func TestStore_CreateExhaustiveSearchJob(t *testing.T) {
 			actor:       &actor.Actor{UID: malloryID},
 			expectedErr: auth.ErrMustBeSiteAdminOrSameUser,
 		},
+		{
+			name: "admin can create high priority job",
+			job: types.ExhaustiveSearchJob{
+				InitiatorID: adminID,
+				Query:       "me first",
+				Priority:    types.JobPriorityHigh,
+			},
+			actor:       &actor.Actor{UID: adminID},
+			expectedErr: nil,
+		},
+		{
+			name: "user cant create high priority job",
+			job: types.ExhaustiveSearchJob{
+				InitiatorID: userID,
+				Query:       "me first",
+				Priority:    types.JobPriorityHigh,
+			},
+			expectedErr: auth.ErrMustBeSiteAdmin,
+		},
+		{
+			name: "user can create low priority job",
+			job: types.ExhaustiveSearchJob{
+				InitiatorID: userID,
+				Query:       "take your time",
+				Priority:    types.JobPriorityLow,
+			},
+			expectedErr: nil,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -152,8 +185,8 @@ func TestStore_GetAndListSearchJobs(t *testing.T) {
 	s := store.New(db, observation.TestContextTB(t))
 
 	jobs := []types.ExhaustiveSearchJob{
-		{InitiatorID: userID, Query: "repo:job1"},
-		{InitiatorID: userID, Query: "repo:job2"},
+		{InitiatorID: userID, Query: "repo:job1", Description: "first", Labels: map[string]string{"team": "search", "env": "prod"}},
+		{InitiatorID: userID, Query: "repo:job2", Labels: map[string]string{"team": "search"}},
 		{InitiatorID: userID, Query: "repo:job3"},
 	}
 
@@ -177,6 +210,12 @@ func TestStore_GetAndListSearchJobs(t *testing.T) {
 		assert.Equal(t, haveJob.State, types.JobStateQueued)
 		assert.NotZero(t, haveJob.CreatedAt)
 		assert.NotZero(t, haveJob.UpdatedAt)
+		assert.Equal(t, job.Description, haveJob.Description)
+		if len(job.Labels) > 0 {
+			assert.Equal(t, job.Labels, haveJob.Labels)
+		} else {
+			assert.Empty(t, haveJob.Labels)
+		}
 	}
 
 	// Now list them all
@@ -221,7 +260,31 @@ func TestStore_GetAndListSearchJobs(t *testing.T) {
 			},
 			wantIDs: []int64{jobs[0].ID},
 		},
+		{
+			name: "labels: 1 job",
+			ctx:  ctx,
+			args: store.ListArgs{
+				Labels: map[string]string{"team": "search", "env": "prod"},
+			},
+			wantIDs: []int64{jobs[0].ID},
+		},
+		{
+			name: "labels: jobs with other labels too",
+			ctx:  ctx,
+			args: store.ListArgs{
+				Labels: map[string]string{"team": "search"},
+			},
+			wantIDs: []int64{jobs[0].ID, jobs[1].ID},
+		},
 		// negative test
+		{
+			name: "labels: no result",
+			ctx:  ctx,
+			args: store.ListArgs{
+				Labels: map[string]string{"team": "prod"},
+			},
+			wantIDs: []int64{},
+		},
 		{
 			name: "query: no result",
 			ctx:  ctx,
@@ -247,6 +310,12 @@ func TestStore_GetAndListSearchJobs(t *testing.T) {
 			},
 			wantIDs: []int64{jobs[0].ID, jobs[1].ID, jobs[2].ID},
 		},
+		{
+			name:    "internal actors see the jobs of all users",
+			ctx:     actor.WithInternalActor(context.Background()),
+			args:    store.ListArgs{},
+			wantIDs: []int64{jobs[0].ID, jobs[1].ID, jobs[2].ID},
+		},
 		{
 			name: "userIDs: Non-admins CANNOT ask for userIDs",
 			ctx:  ctx,
@@ -276,8 +345,139 @@ func TestStore_GetAndListSearchJobs(t *testing.T) {
 			if diff := cmp.Diff(haveIDs, c.wantIDs); diff != "" {
 				t.Fatalf("List returned wrong jobs: %s", diff)
 			}
+
+			haveCount, err := s.CountExhaustiveSearchJobs(c.ctx, c.args)
+			if c.wantErr {
+				require.Error(t, err)
+			} else if c.args.PaginationArgs == nil {
+				require.NoError(t, err)
+				require.Equal(t, len(c.wantIDs), haveCount)
+			}
+		})
+	}
+
+	t.Run("pagination: page through all jobs", func(t *testing.T) {
+		var after []any
+		for _, want := range jobs {
+			page, err := s.ListExhaustiveSearchJobs(ctx, store.ListArgs{
+				PaginationArgs: &database.PaginationArgs{First: intptr(1), After: after, Ascending: true},
+			})
+			require.NoError(t, err)
+			require.Len(t, page, 1)
+			require.Equal(t, want.ID, page[0].ID)
+
+			after = []any{page[0].ID}
+		}
+
+		page, err := s.ListExhaustiveSearchJobs(ctx, store.ListArgs{
+			PaginationArgs: &database.PaginationArgs{First: intptr(1), After: after, Ascending: true},
+		})
+		require.NoError(t, err)
+		require.Empty(t, page)
+	})
+
+	t.Run("pagination: last page", func(t *testing.T) {
+		page, err := s.ListExhaustiveSearchJobs(ctx, store.ListArgs{
+			PaginationArgs: &database.PaginationArgs{Last: intptr(2), Before: []any{jobs[2].ID}, Ascending: true},
+		})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		// The rows of the last page are in reverse order, the connection
+		// resolver puts them back in order.
+		require.Equal(t, []int64{jobs[1].ID, jobs[0].ID}, []int64{page[0].ID, page[1].ID})
+	})
+
+	t.Run("pagination: descending by updated_at", func(t *testing.T) {
+		haveJobs, err := s.ListExhaustiveSearchJobs(ctx, store.ListArgs{
+			PaginationArgs: &database.PaginationArgs{
+				First:   intptr(3),
+				OrderBy: database.OrderBy{{Field: "updated_at"}, {Field: "id"}},
+			},
 		})
+		require.NoError(t, err)
+		require.Len(t, haveJobs, 3)
+		require.Equal(t, []int64{jobs[2].ID, jobs[1].ID, jobs[0].ID}, []int64{haveJobs[0].ID, haveJobs[1].ID, haveJobs[2].ID})
+	})
+
+	t.Run("states: failed jobs", func(t *testing.T) {
+		err := bs.Exec(ctx, sqlf.Sprintf("UPDATE exhaustive_search_jobs SET state = 'failed' WHERE id = %s", jobs[1].ID))
+		require.NoError(t, err)
+
+		args := store.ListArgs{States: []string{string(types.JobStateFailed)}}
+		haveJobs, err := s.ListExhaustiveSearchJobs(ctx, args)
+		require.NoError(t, err)
+		require.Len(t, haveJobs, 1)
+		require.Equal(t, jobs[1].ID, haveJobs[0].ID)
+		require.Equal(t, types.JobStateFailed, haveJobs[0].AggState)
+
+		count, err := s.CountExhaustiveSearchJobs(ctx, args)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+}
+
+func TestStore_SearchJobInitiator(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
 	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	aliceID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	bobID, err := createUser(bs, "bob")
+	require.NoError(t, err)
+	adminID, err := createUser(bs, "admin")
+	require.NoError(t, err)
+
+	err = bs.Exec(context.Background(), sqlf.Sprintf("INSERT INTO user_emails (user_id, email, is_primary) VALUES (%s, 'alice@example.com', true), (%s, 'bob@example.com', true)", aliceID, bobID))
+	require.NoError(t, err)
+
+	aliceCtx := actor.WithActor(context.Background(), actor.FromUser(aliceID))
+	bobCtx := actor.WithActor(context.Background(), actor.FromUser(bobID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+
+	s := store.New(db, observation.TestContextTB(t))
+
+	aliceJobID, err := s.CreateExhaustiveSearchJob(aliceCtx, types.ExhaustiveSearchJob{
+		InitiatorID:    aliceID,
+		Query:          "repo:job1",
+		CreationSource: types.JobCreationSourceWeb,
+	})
+	require.NoError(t, err)
+	bobJobID, err := s.CreateExhaustiveSearchJob(bobCtx, types.ExhaustiveSearchJob{
+		InitiatorID:    bobID,
+		Query:          "repo:job2",
+		CreationSource: types.JobCreationSourceScheduled,
+	})
+	require.NoError(t, err)
+
+	job, err := s.GetExhaustiveSearchJob(aliceCtx, aliceJobID)
+	require.NoError(t, err)
+	require.Equal(t, "alice", job.InitiatorUsername)
+	require.Equal(t, "alice@example.com", job.InitiatorEmail)
+	require.Equal(t, types.JobCreationSourceWeb, job.CreationSource)
+
+	// Soft-deleted users keep their jobs, which hide who they were.
+	err = bs.Exec(context.Background(), sqlf.Sprintf("UPDATE users SET deleted_at = NOW() WHERE id = %s", bobID))
+	require.NoError(t, err)
+
+	jobs, err := s.ListExhaustiveSearchJobs(adminCtx, store.ListArgs{
+		PaginationArgs: &database.PaginationArgs{Ascending: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+
+	require.Equal(t, aliceJobID, jobs[0].ID)
+	require.Equal(t, "alice", jobs[0].InitiatorUsername)
+	require.Equal(t, "alice@example.com", jobs[0].InitiatorEmail)
+
+	require.Equal(t, bobJobID, jobs[1].ID)
+	require.Equal(t, types.DeletedInitiatorUsername, jobs[1].InitiatorUsername)
+	require.Empty(t, jobs[1].InitiatorEmail)
+	require.Equal(t, types.JobCreationSourceScheduled, jobs[1].CreationSource)
 }
 
 // TestStore_GetAggregateStatus tests that ListExhaustiveSearchJobs returns the
@@ -362,6 +562,15 @@ func TestStore_AggregateStatus(t *testing.T) {
 			},
 			want: types.JobStateCanceled,
 		},
+		{
+			name: "result limit reached, the remaining jobs were skipped",
+			c: stateCascade{
+				searchJob:   types.JobStateCompleted,
+				repoJobs:    []types.JobState{types.JobStateCompleted, types.JobStateSkipped},
+				repoRevJobs: []types.JobState{types.JobStateCompleted},
+			},
+			want: types.JobStateCompleted,
+		},
 		{
 			name: "top-level search job finished, but the other jobs haven't started yet",
 			c: stateCascade{
@@ -396,6 +605,585 @@ func TestStore_AggregateStatus(t *testing.T) {
 	}
 }
 
+func TestStore_CancelSearchJob(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	_, err := createRepo(db, "repo1")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+
+	aliceID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	malloryID, err := createUser(bs, "mallory")
+	require.NoError(t, err)
+	adminID, err := createUser(bs, "admin")
+	require.NoError(t, err)
+	err = bs.Exec(context.Background(), sqlf.Sprintf("UPDATE users SET site_admin = TRUE WHERE id = %s", adminID))
+	require.NoError(t, err)
+
+	aliceCtx := actor.WithActor(context.Background(), actor.FromUser(aliceID))
+	malloryCtx := actor.WithActor(context.Background(), actor.FromUser(malloryID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+
+	running := createJobCascade(t, aliceCtx, s, stateCascade{
+		searchJob:   types.JobStateCompleted,
+		repoJobs:    []types.JobState{types.JobStateCompleted, types.JobStateQueued},
+		repoRevJobs: []types.JobState{types.JobStateProcessing, types.JobStateCompleted},
+	})
+
+	// Other users can't cancel the job, and the job is left alone.
+	_, err = s.CancelSearchJob(malloryCtx, running)
+	require.ErrorIs(t, err, auth.ErrMustBeSiteAdminOrSameUser)
+
+	// The search job, the queued repo job and the 2 processing repo revision
+	// jobs are canceled.
+	res, err := s.CancelSearchJob(aliceCtx, running)
+	require.NoError(t, err)
+	require.Equal(t, store.CancelSearchJobResult{PriorState: types.JobStateProcessing, TotalCanceled: 4}, res)
+
+	// Canceling a canceled job changes nothing.
+	res, err = s.CancelSearchJob(aliceCtx, running)
+	require.NoError(t, err)
+	require.Equal(t, store.CancelSearchJobResult{PriorState: types.JobStateCanceled}, res)
+
+	finished := createJobCascade(t, aliceCtx, s, stateCascade{
+		searchJob:   types.JobStateCompleted,
+		repoJobs:    []types.JobState{types.JobStateCompleted},
+		repoRevJobs: []types.JobState{types.JobStateCompleted, types.JobStateFailed},
+	})
+
+	// Site admins may cancel the jobs of other users, but finished jobs are
+	// left alone.
+	res, err = s.CancelSearchJob(adminCtx, finished)
+	require.NoError(t, err)
+	require.Equal(t, store.CancelSearchJobResult{PriorState: types.JobStateFailed}, res)
+
+	queued := createJobCascade(t, aliceCtx, s, stateCascade{searchJob: types.JobStateQueued})
+	res, err = s.CancelSearchJob(adminCtx, queued)
+	require.NoError(t, err)
+	require.Equal(t, store.CancelSearchJobResult{PriorState: types.JobStateQueued, TotalCanceled: 1}, res)
+
+	_, err = s.CancelSearchJob(aliceCtx, queued+100)
+	require.ErrorIs(t, err, store.ErrNoResults)
+}
+
+func TestStore_LockExhaustiveSearchJob(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	_, err := createRepo(db, "repo1")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+
+	aliceID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	malloryID, err := createUser(bs, "mallory")
+	require.NoError(t, err)
+
+	aliceCtx := actor.WithActor(context.Background(), actor.FromUser(aliceID))
+	malloryCtx := actor.WithActor(context.Background(), actor.FromUser(malloryID))
+
+	jobID := createJobCascade(t, aliceCtx, s, stateCascade{searchJob: types.JobStateCompleted})
+
+	// Locks only last as long as a transaction.
+	err = s.LockExhaustiveSearchJob(aliceCtx, jobID)
+	require.Error(t, err)
+
+	tx, err := s.Transact(aliceCtx)
+	require.NoError(t, err)
+	require.NoError(t, tx.LockExhaustiveSearchJob(aliceCtx, jobID))
+
+	// Other users can't lock the job.
+	other, err := s.Transact(malloryCtx)
+	require.NoError(t, err)
+	err = other.LockExhaustiveSearchJob(malloryCtx, jobID)
+	require.ErrorIs(t, err, auth.ErrMustBeSiteAdminOrSameUser)
+	require.NoError(t, other.Done(nil))
+
+	// A second transaction waits for the lock.
+	other, err = s.Transact(aliceCtx)
+	require.NoError(t, err)
+	require.NoError(t, other.Exec(aliceCtx, sqlf.Sprintf("SET LOCAL lock_timeout = '100ms'")))
+	err = other.LockExhaustiveSearchJob(aliceCtx, jobID)
+	require.ErrorContains(t, err, "lock timeout")
+	require.Error(t, other.Done(err))
+
+	// Once the lock is released, the job can be locked again, until it is
+	// deleted.
+	require.NoError(t, tx.DeleteExhaustiveSearchJob(aliceCtx, jobID))
+	require.NoError(t, tx.Done(nil))
+	tx, err = s.Transact(aliceCtx)
+	require.NoError(t, err)
+	err = tx.LockExhaustiveSearchJob(aliceCtx, jobID)
+	require.ErrorIs(t, err, store.ErrNoResults)
+	require.NoError(t, tx.Done(nil))
+}
+
+func TestStore_UpdateExhaustiveSearchJobMetadata(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	s := store.New(db, observation.TestContextTB(t))
+
+	aliceID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	adminID, err := createUser(bs, "admin")
+	require.NoError(t, err)
+	err = bs.Exec(context.Background(), sqlf.Sprintf("UPDATE users SET site_admin = TRUE WHERE id = %s", adminID))
+	require.NoError(t, err)
+
+	aliceCtx := actor.WithActor(context.Background(), actor.FromUser(aliceID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+
+	jobID, err := s.CreateExhaustiveSearchJob(aliceCtx, types.ExhaustiveSearchJob{
+		InitiatorID: aliceID,
+		Query:       "repo:job1",
+		Description: "before",
+		Labels:      map[string]string{"team": "search"},
+	})
+	require.NoError(t, err)
+
+	// The labels are replaced, not merged.
+	err = s.UpdateExhaustiveSearchJobMetadata(aliceCtx, jobID, "after", map[string]string{"env": "prod"})
+	require.NoError(t, err)
+
+	job, err := s.GetExhaustiveSearchJob(aliceCtx, jobID)
+	require.NoError(t, err)
+	require.Equal(t, "after", job.Description)
+	require.Equal(t, map[string]string{"env": "prod"}, job.Labels)
+
+	// Not even site admins may change the metadata of other users.
+	err = s.UpdateExhaustiveSearchJobMetadata(adminCtx, jobID, "admin", nil)
+	var authErr *auth.InsufficientAuthorizationError
+	require.ErrorAs(t, err, &authErr)
+
+	// Clearing the labels stores an empty object, which the label filter
+	// doesn't match.
+	err = s.UpdateExhaustiveSearchJobMetadata(aliceCtx, jobID, "", nil)
+	require.NoError(t, err)
+	jobs, err := s.ListExhaustiveSearchJobs(aliceCtx, store.ListArgs{Labels: map[string]string{"env": "prod"}})
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+
+	err = s.UpdateExhaustiveSearchJobMetadata(aliceCtx, jobID+100, "", nil)
+	require.ErrorIs(t, err, store.ErrNoResults)
+}
+
+// TestStore_Queries compares the queries of the store to golden files, so
+// that changes to them show up in reviews. Run the test with
+// -update=TestStore_Queries to update the golden files.
+func TestStore_Queries(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+
+	rec := querytest.NewRecorder()
+	s := store.New(database.NewDBWith(logger, rec.Store(db)), observation.TestContextTB(t))
+	ctx := actor.WithInternalActor(context.Background())
+
+	jobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{InitiatorID: userID, Query: "repo:job1"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), jobID, "the golden files expect the first job")
+
+	t.Run("list", func(t *testing.T) {
+		rec.Reset()
+		_, err := s.ListExhaustiveSearchJobs(ctx, store.ListArgs{
+			Query:  "job",
+			States: []string{string(types.JobStateQueued), string(types.JobStateProcessing)},
+		})
+		require.NoError(t, err)
+		rec.AssertGolden(t, "testdata/golden/"+t.Name(), update(t.Name()))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		rec.Reset()
+		_, err := s.CancelSearchJob(ctx, jobID)
+		require.NoError(t, err)
+		rec.AssertGolden(t, "testdata/golden/"+t.Name(), update(t.Name()))
+	})
+}
+
+func TestStore_Clock(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+
+	clock := glock.NewMockClockAt(time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC))
+	s := store.NewWithClock(db, observation.TestContextTB(t), clock)
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	createdAt := clock.Now()
+	jobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{InitiatorID: userID, Query: "repo:job1"})
+	require.NoError(t, err)
+
+	clock.Advance(time.Hour)
+	require.NoError(t, s.MarkSearchJobExpanded(workerCtx, jobID))
+
+	job, err := s.GetExhaustiveSearchJob(ctx, jobID)
+	require.NoError(t, err)
+	require.True(t, createdAt.Equal(job.CreatedAt), "got created_at %s, want %s", job.CreatedAt, createdAt)
+	require.True(t, createdAt.Equal(job.UpdatedAt), "got updated_at %s, want %s", job.UpdatedAt, createdAt)
+	require.True(t, clock.Now().Equal(job.ExpandedAt), "got expanded_at %s, want %s", job.ExpandedAt, clock.Now())
+}
+
+func TestStore_Observability(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	slowQueryThreshold := store.SlowQueryThreshold
+	store.SlowQueryThreshold = time.Nanosecond
+	t.Cleanup(func() { store.SlowQueryThreshold = slowQueryThreshold })
+
+	observationCtx := observation.TestContextCaptured(t)
+	registry := prometheus.NewRegistry()
+	observationCtx.Registerer = registry
+
+	db := database.NewDB(observationCtx.Logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+
+	s := store.New(db, observationCtx.Context)
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+
+	jobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{InitiatorID: userID, Query: "repo:job1"})
+	require.NoError(t, err)
+	_, err = s.GetExhaustiveSearchJob(ctx, jobID)
+	require.NoError(t, err)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	counts := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "src_searchjobs_store_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "op" {
+					counts[label.GetValue()] = m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	require.Equal(t, float64(1), counts["CreateExhaustiveSearchJob"])
+	require.Equal(t, float64(1), counts["GetExhaustiveSearchJob"])
+
+	observationCtx.RequireLogged(t, log.LevelWarn, "slow query")
+}
+
+func TestStore_AddResultCount(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	_, err := createRepo(db, "repo1")
+	require.NoError(t, err)
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	repoRevJobStates := func(jobID int64) []string {
+		states, err := basestore.ScanStrings(s.Query(ctx, sqlf.Sprintf(`
+SELECT rrj.state
+FROM exhaustive_search_repo_revision_jobs rrj
+JOIN exhaustive_search_repo_jobs rj ON rrj.search_repo_job_id = rj.id
+WHERE rj.search_job_id = %s
+ORDER BY rrj.id`, jobID)))
+		require.NoError(t, err)
+		return states
+	}
+
+	c := stateCascade{
+		searchJob: types.JobStateCompleted,
+		repoJobs:  []types.JobState{types.JobStateCompleted},
+		repoRevJobs: []types.JobState{
+			types.JobStateCompleted,
+			types.JobStateProcessing,
+			types.JobStateQueued,
+			types.JobStateErrored,
+		},
+	}
+
+	t.Run("users can't count results", func(t *testing.T) {
+		jobID := createJobCascade(t, ctx, s, c)
+
+		_, err := s.AddResultCount(ctx, jobID, 1)
+		require.ErrorIs(t, err, store.ErrNotInternalActor)
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		jobID := createJobCascade(t, ctx, s, c)
+
+		limitReached, err := s.AddResultCount(workerCtx, jobID, 1000)
+		require.NoError(t, err)
+		require.False(t, limitReached)
+
+		job, err := s.GetExhaustiveSearchJob(ctx, jobID)
+		require.NoError(t, err)
+		require.Equal(t, 1000, job.ResultCount)
+		require.False(t, job.Truncated)
+		require.Equal(t, []string{"completed", "processing", "queued", "errored"}, repoRevJobStates(jobID))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		jobID := createJobCascade(t, ctx, s, c)
+		err := s.Exec(ctx, sqlf.Sprintf("UPDATE exhaustive_search_jobs SET max_results = 2 WHERE id = %s", jobID))
+		require.NoError(t, err)
+
+		limitReached, err := s.AddResultCount(workerCtx, jobID, 1)
+		require.NoError(t, err)
+		require.False(t, limitReached)
+
+		job, err := s.GetExhaustiveSearchJob(ctx, jobID)
+		require.NoError(t, err)
+		require.Equal(t, 2, job.MaxResults)
+		require.False(t, job.Truncated)
+
+		// This reaches the limit, so the jobs which haven't run yet are
+		// skipped.
+		limitReached, err = s.AddResultCount(workerCtx, jobID, 1)
+		require.NoError(t, err)
+		require.False(t, limitReached)
+
+		job, err = s.GetExhaustiveSearchJob(ctx, jobID)
+		require.NoError(t, err)
+		require.Equal(t, 2, job.ResultCount)
+		require.True(t, job.Truncated)
+		require.Equal(t, []string{"completed", "processing", "skipped", "skipped"}, repoRevJobStates(jobID))
+
+		limitReached, err = s.AddResultCount(workerCtx, jobID, 1)
+		require.NoError(t, err)
+		require.True(t, limitReached)
+	})
+}
+
+func TestStore_AddBytesWritten(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	aliceID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	bobID, err := createUser(bs, "bob")
+	require.NoError(t, err)
+	adminID, err := createUser(bs, "admin")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+	aliceCtx := actor.WithActor(context.Background(), actor.FromUser(aliceID))
+	bobCtx := actor.WithActor(context.Background(), actor.FromUser(bobID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	createJob := func(ctx context.Context) int64 {
+		jobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{
+			InitiatorID: actor.FromContext(ctx).UID,
+			Query:       "repo:job1",
+		})
+		require.NoError(t, err)
+		return jobID
+	}
+
+	aliceJob1, aliceJob2, bobJob := createJob(aliceCtx), createJob(aliceCtx), createJob(bobCtx)
+
+	// Only the workers count bytes.
+	err = s.AddBytesWritten(aliceCtx, aliceJob1, 1)
+	require.ErrorIs(t, err, store.ErrNotInternalActor)
+
+	for _, add := range []struct {
+		jobID int64
+		n     int64
+	}{
+		{aliceJob1, 100},
+		{aliceJob1, 50},
+		{aliceJob2, 10},
+		{bobJob, 500},
+	} {
+		require.NoError(t, s.AddBytesWritten(workerCtx, add.jobID, add.n))
+	}
+
+	job, err := s.GetExhaustiveSearchJob(aliceCtx, aliceJob1)
+	require.NoError(t, err)
+	require.Equal(t, int64(150), job.BytesWritten)
+
+	t.Run("export volume", func(t *testing.T) {
+		now := time.Now()
+		volumes, err := s.GetExportVolumeByUser(adminCtx, now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, []types.UserExportVolume{
+			{UserID: bobID, Username: "bob", Jobs: 1, BytesWritten: 500},
+			{UserID: aliceID, Username: "alice", Jobs: 2, BytesWritten: 160},
+		}, volumes)
+
+		// The jobs were created before the window.
+		volumes, err = s.GetExportVolumeByUser(adminCtx, now.Add(time.Hour), now.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, volumes)
+
+		_, err = s.GetExportVolumeByUser(aliceCtx, now.Add(-time.Hour), now.Add(time.Hour))
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+	})
+}
+
+func TestStore_AddSampledTasks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	aliceID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+	aliceCtx := actor.WithActor(context.Background(), actor.FromUser(aliceID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	jobID, err := s.CreateExhaustiveSearchJob(aliceCtx, types.ExhaustiveSearchJob{
+		InitiatorID: aliceID,
+		Query:       "repo:job1",
+		SampleRate:  0.25,
+		SampleSeed:  42,
+	})
+	require.NoError(t, err)
+
+	// Only the workers count tasks.
+	err = s.AddSampledTasks(aliceCtx, jobID, 1, 4)
+	require.ErrorIs(t, err, store.ErrNotInternalActor)
+
+	require.NoError(t, s.AddSampledTasks(workerCtx, jobID, 1, 4))
+	require.NoError(t, s.AddSampledTasks(workerCtx, jobID, 0, 3))
+
+	job, err := s.GetExhaustiveSearchJob(aliceCtx, jobID)
+	require.NoError(t, err)
+	require.Equal(t, 0.25, job.SampleRate)
+	require.Equal(t, int64(42), job.SampleSeed)
+	require.Equal(t, 1, job.SampledTaskCount)
+	require.Equal(t, 7, job.TotalTaskCount)
+}
+
+func TestStore_RepairStalledSearchJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	_, err = createRepo(db, "repo1")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+	userCtx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	processing := []types.JobState{types.JobStateProcessing}
+	completed := []types.JobState{types.JobStateCompleted}
+
+	// expanded created all its tasks, which finished, before its worker died.
+	expanded := createJobCascade(t, userCtx, s, stateCascade{searchJob: types.JobStateProcessing, repoJobs: completed, repoRevJobs: completed})
+	// orphaned has a task whose worker died.
+	orphaned := createJobCascade(t, userCtx, s, stateCascade{searchJob: types.JobStateCompleted, repoJobs: completed, repoRevJobs: processing})
+	// unexpanded has to create its tasks again.
+	unexpanded := createJobCascade(t, userCtx, s, stateCascade{searchJob: types.JobStateProcessing})
+	// canceled was canceled while its worker was down.
+	canceled := createJobCascade(t, userCtx, s, stateCascade{searchJob: types.JobStateProcessing, repoJobs: completed, repoRevJobs: processing})
+	// live is processed by a live worker.
+	live := createJobCascade(t, userCtx, s, stateCascade{searchJob: types.JobStateProcessing, repoJobs: completed, repoRevJobs: processing})
+
+	stale := time.Now().Add(-time.Hour)
+	for _, q := range []*sqlf.Query{
+		sqlf.Sprintf("UPDATE exhaustive_search_jobs SET last_heartbeat_at = %s", stale),
+		sqlf.Sprintf("UPDATE exhaustive_search_repo_jobs SET last_heartbeat_at = %s", stale),
+		sqlf.Sprintf("UPDATE exhaustive_search_repo_revision_jobs SET last_heartbeat_at = %s", stale),
+		sqlf.Sprintf("UPDATE exhaustive_search_jobs SET expanded_at = %s WHERE id <> %s", stale, unexpanded),
+		sqlf.Sprintf("UPDATE exhaustive_search_jobs SET cancel = true WHERE id = %s", canceled),
+		sqlf.Sprintf("UPDATE exhaustive_search_repo_revision_jobs SET cancel = true WHERE search_repo_job_id IN (SELECT id FROM exhaustive_search_repo_jobs WHERE search_job_id = %s)", canceled),
+		sqlf.Sprintf("UPDATE exhaustive_search_jobs SET last_heartbeat_at = NOW() WHERE id = %s", live),
+		sqlf.Sprintf("UPDATE exhaustive_search_repo_revision_jobs SET last_heartbeat_at = NOW() WHERE search_repo_job_id IN (SELECT id FROM exhaustive_search_repo_jobs WHERE search_job_id = %s)", live),
+	} {
+		require.NoError(t, s.Exec(context.Background(), q))
+	}
+
+	// Only the workers repair jobs.
+	_, err = s.RepairStalledSearchJobs(userCtx, time.Minute)
+	require.ErrorIs(t, err, store.ErrNotInternalActor)
+
+	ids, err := s.RepairStalledSearchJobs(workerCtx, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, []int64{expanded, orphaned, unexpanded, canceled}, ids)
+
+	wantAggStates := map[int64]types.JobState{
+		expanded:   types.JobStateCompleted,
+		orphaned:   types.JobStateProcessing, // until the resetter requeues the task
+		unexpanded: types.JobStateProcessing, // until the resetter requeues the job
+		canceled:   types.JobStateCanceled,
+		live:       types.JobStateProcessing,
+	}
+	for id, want := range wantAggStates {
+		job, err := s.GetExhaustiveSearchJob(userCtx, id)
+		require.NoError(t, err)
+		require.Equal(t, want, job.AggState, "job %d", id)
+	}
+
+	// The stalled records which are left are for the resetters.
+	ids, err = s.RepairStalledSearchJobs(workerCtx, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, []int64{orphaned, unexpanded}, ids)
+}
+
 // createJobCascade creates a cascade of jobs (1 search job -> n repo jobs -> m
 // repo rev jobs) with states as defined in stateCascade.
 //
@@ -414,6 +1202,7 @@ func createJobCascade(
 		InitiatorID: actor.FromContext(ctx).UID,
 		Query:       "repo:job1",
 		WorkerJob:   types.WorkerJob{State: casc.searchJob},
+		Priority:    casc.priority,
 	}
 
 	repoJobs := make([]types.ExhaustiveSearchRepoJob, len(casc.repoJobs))
@@ -421,7 +1210,8 @@ func createJobCascade(
 		repoJobs[i] = types.ExhaustiveSearchRepoJob{
 			WorkerJob: types.WorkerJob{State: r},
 			RepoID:    1, // same repo for all tests
-			RefSpec:   "HEAD",
+			// A job has one repo job per repository and ref spec.
+			RefSpec: fmt.Sprintf("HEAD~%d", i),
 		}
 	}
 
@@ -440,9 +1230,12 @@ func createJobCascade(
 	err = stor.Exec(ctx, sqlf.Sprintf("UPDATE exhaustive_search_jobs SET state = %s WHERE id = %s", casc.searchJob, jobID))
 	require.NoError(t, err)
 
+	// Only the workers create tasks.
+	workerCtx := actor.WithInternalActor(context.Background())
+
 	for i, r := range repoJobs {
 		r.SearchJobID = jobID
-		repoJobID, err := stor.CreateExhaustiveSearchRepoJob(ctx, r)
+		repoJobID, err := stor.CreateExhaustiveSearchRepoJob(workerCtx, r)
 		require.NoError(t, err)
 		assert.NotZero(t, repoJobID)
 
@@ -451,7 +1244,7 @@ func createJobCascade(
 
 		for j, rr := range repoRevJobs {
 			rr.SearchRepoJobID = repoJobID
-			repoRevJobID, err := stor.CreateExhaustiveSearchRepoRevisionJob(ctx, rr)
+			repoRevJobID, err := stor.CreateExhaustiveSearchRepoRevisionJob(workerCtx, rr)
 			require.NoError(t, err)
 			assert.NotZero(t, repoRevJobID)
 			require.NoError(t, err)
@@ -468,6 +1261,7 @@ type stateCascade struct {
 	searchJob   types.JobState
 	repoJobs    []types.JobState
 	repoRevJobs []types.JobState
+	priority    types.JobPriority
 }
 
 func intptr(s int) *int { return &s }
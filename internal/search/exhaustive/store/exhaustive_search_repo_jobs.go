@@ -5,13 +5,16 @@ import (
 	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
 )
 
 var repoSearchJobWorkerOpts = dbworkerstore.Options[*types.ExhaustiveSearchRepoJob]{
@@ -21,18 +24,27 @@ var repoSearchJobWorkerOpts = dbworkerstore.Options[*types.ExhaustiveSearchRepoJ
 
 	Scan: dbworkerstore.BuildWorkerScan(scanRepoSearchJob),
 
-	OrderByExpression: sqlf.Sprintf("exhaustive_search_repo_jobs.state = 'errored', exhaustive_search_repo_jobs.updated_at DESC"),
+	// Repo jobs of search jobs with a higher priority go first, then the oldest
+	// repo job.
+	OrderByExpression: sqlf.Sprintf(`
+exhaustive_search_repo_jobs.state = 'errored',
+(SELECT j.priority FROM exhaustive_search_jobs j WHERE j.id = exhaustive_search_repo_jobs.search_job_id) DESC,
+exhaustive_search_repo_jobs.created_at`),
 
-	StalledMaxAge: 60 * time.Second,
-	MaxNumResets:  maxNumResets,
+	StalledMaxAge:       DefaultStalledMaxAge,
+	MaxNumResets:        maxNumResets,
+	ResetFailureMessage: stalledFailureMessage,
 
 	RetryAfter:    5 * time.Second,
 	MaxNumRetries: maxNumRetries,
 }
 
 // NewRepoSearchJobWorkerStore returns a dbworkerstore.Store that wraps the "exhaustive_search_repo_jobs" table.
-func NewRepoSearchJobWorkerStore(observationCtx *observation.Context, handle basestore.TransactableHandle) dbworkerstore.Store[*types.ExhaustiveSearchRepoJob] {
-	return dbworkerstore.New(observationCtx, handle, repoSearchJobWorkerOpts)
+// Records without a heartbeat for stalledMaxAge are requeued by the resetter.
+func NewRepoSearchJobWorkerStore(observationCtx *observation.Context, handle basestore.TransactableHandle, stalledMaxAge time.Duration) dbworkerstore.Store[*types.ExhaustiveSearchRepoJob] {
+	opts := repoSearchJobWorkerOpts
+	opts.StalledMaxAge = stalledMaxAge
+	return dbworkerstore.New(observationCtx, handle, opts)
 }
 
 var repoSearchJobColumns = []*sqlf.Query{
@@ -59,6 +71,11 @@ func (s *Store) CreateExhaustiveSearchRepoJob(ctx context.Context, job types.Exh
 	ctx, _, endObservation := s.operations.createExhaustiveSearchRepoJob.With(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
 
+	// 🚨 SECURITY: only the workers may create tasks.
+	if err = checkInternalActor(ctx); err != nil {
+		return 0, err
+	}
+
 	if job.SearchJobID <= 0 {
 		return 0, MissingSearchJobIDErr
 	}
@@ -96,6 +113,71 @@ VALUES (%s, %s, %s)
 RETURNING id
 `
 
+// repoJobsBatchSize is the number of tasks CreateExhaustiveSearchRepoJobs
+// inserts per statement.
+const repoJobsBatchSize = 5000
+
+// CreateExhaustiveSearchRepoJobs creates a repo job of search job searchJobID
+// for every repository revision spec of it. The repo jobs are inserted in
+// batches of repoJobsBatchSize rows while it is read, and each batch is
+// committed on its own, so starting a large search job doesn't hold a long
+// transaction. Specs which already have a repo job are skipped, so a search
+// job whose expansion was interrupted can be expanded again. It returns the
+// number of repo jobs which were created.
+//
+// Only the workers may create tasks.
+func (s *Store) CreateExhaustiveSearchRepoJobs(ctx context.Context, searchJobID int64, it *iterator.Iterator[types.RepositoryRevSpecs]) (created int, err error) {
+	ctx, _, endObservation := s.operations.createExhaustiveSearchRepoJobs.With(ctx, &err, opAttrs(
+		attribute.Int64("searchJobID", searchJobID),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("created", created)))
+	}()
+
+	// 🚨 SECURITY: only the workers may create tasks.
+	if err := checkInternalActor(ctx); err != nil {
+		return 0, err
+	}
+
+	if searchJobID <= 0 {
+		return 0, MissingSearchJobIDErr
+	}
+
+	err = batch.WithInserterWithReturn(
+		ctx,
+		s.Handle(),
+		"exhaustive_search_repo_jobs",
+		repoJobsBatchSize*len(createRepoJobsColumns),
+		createRepoJobsColumns,
+		"ON CONFLICT (search_job_id, repo_id, ref_spec) DO NOTHING",
+		[]string{"id"},
+		// Rows which already existed are not returned.
+		func(dbutil.Scanner) error {
+			created++
+			return nil
+		},
+		func(inserter *batch.Inserter) error {
+			for it.Next() {
+				spec := it.Current()
+				if spec.Repository <= 0 {
+					return MissingRepoIDErr
+				}
+				refSpec := spec.RevisionSpecifiers.String()
+				if refSpec == "" {
+					return MissingRefSpecErr
+				}
+				if err := inserter.Insert(ctx, spec.Repository, searchJobID, refSpec); err != nil {
+					return err
+				}
+			}
+			return it.Err()
+		},
+	)
+	return created, err
+}
+
+var createRepoJobsColumns = []string{"repo_id", "search_job_id", "ref_spec"}
+
 func scanRepoSearchJob(sc dbutil.Scanner) (*types.ExhaustiveSearchRepoJob, error) {
 	var job types.ExhaustiveSearchRepoJob
 	// required field for the sync worker, but
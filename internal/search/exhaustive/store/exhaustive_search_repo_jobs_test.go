@@ -2,8 +2,10 @@ package store_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/log/logtest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
 )
 
 func TestStore_CreateExhaustiveSearchRepoJob(t *testing.T) {
@@ -37,6 +40,9 @@ func TestStore_CreateExhaustiveSearchRepoJob(t *testing.T) {
 		UID: userID,
 	})
 
+	// Only the workers create tasks.
+	workerCtx := actor.WithInternalActor(context.Background())
+
 	s := store.New(db, observation.TestContextTB(t))
 
 	searchJobID, err := s.CreateExhaustiveSearchJob(
@@ -86,7 +92,7 @@ func TestStore_CreateExhaustiveSearchRepoJob(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			jobID, err := s.CreateExhaustiveSearchRepoJob(ctx, test.job)
+			jobID, err := s.CreateExhaustiveSearchRepoJob(workerCtx, test.job)
 
 			if test.expectedErr != nil {
 				require.Error(t, err)
@@ -97,4 +103,151 @@ func TestStore_CreateExhaustiveSearchRepoJob(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("users can't create tasks", func(t *testing.T) {
+		_, err := s.CreateExhaustiveSearchRepoJob(ctx, types.ExhaustiveSearchRepoJob{
+			SearchJobID: searchJobID,
+			RepoID:      repoID,
+			RefSpec:     "bar:baz",
+		})
+		require.ErrorIs(t, err, store.ErrNotInternalActor)
+	})
+}
+
+func TestStore_CreateExhaustiveSearchRepoJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	repoID, err := createRepo(db, "repo-test")
+	require.NoError(t, err)
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	s := store.New(db, observation.TestContextTB(t))
+
+	searchJobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{InitiatorID: userID, Query: "repo:repo-test"})
+	require.NoError(t, err)
+
+	specs := []types.RepositoryRevSpecs{
+		{Repository: repoID, RevisionSpecifiers: types.RevisionSpecifiers("main")},
+		{Repository: repoID, RevisionSpecifiers: types.RevisionSpecifiers("dev")},
+	}
+
+	created, err := s.CreateExhaustiveSearchRepoJobs(workerCtx, searchJobID, iterator.From(specs[:1]))
+	require.NoError(t, err)
+	require.Equal(t, 1, created)
+
+	// An interrupted expansion which is retried only creates the missing
+	// tasks.
+	created, err = s.CreateExhaustiveSearchRepoJobs(workerCtx, searchJobID, iterator.From(specs))
+	require.NoError(t, err)
+	require.Equal(t, 1, created)
+
+	count, _, err := basestore.ScanFirstInt(bs.Query(ctx, sqlf.Sprintf("SELECT COUNT(*) FROM exhaustive_search_repo_jobs WHERE search_job_id = %s", searchJobID)))
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	// The job is starting until it is marked as expanded.
+	job, err := s.GetExhaustiveSearchJob(ctx, searchJobID)
+	require.NoError(t, err)
+	require.True(t, job.ExpandedAt.IsZero())
+
+	require.NoError(t, s.MarkSearchJobExpanded(workerCtx, searchJobID))
+	job, err = s.GetExhaustiveSearchJob(ctx, searchJobID)
+	require.NoError(t, err)
+	require.False(t, job.ExpandedAt.IsZero())
+
+	t.Run("iterator errors are returned", func(t *testing.T) {
+		it := iterator.New(func() ([]types.RepositoryRevSpecs, error) {
+			return nil, errors.New("boom")
+		})
+		_, err := s.CreateExhaustiveSearchRepoJobs(workerCtx, searchJobID, it)
+		require.ErrorContains(t, err, "boom")
+	})
+
+	t.Run("users can't create tasks", func(t *testing.T) {
+		_, err := s.CreateExhaustiveSearchRepoJobs(ctx, searchJobID, iterator.From(specs))
+		require.ErrorIs(t, err, store.ErrNotInternalActor)
+
+		err = s.MarkSearchJobExpanded(ctx, searchJobID)
+		require.ErrorIs(t, err, store.ErrNotInternalActor)
+	})
+}
+
+// BenchmarkStore_CreateExhaustiveSearchRepoJobs compares creating the tasks of
+// a large search job one by one in a transaction, like the workers used to, to
+// creating them in batches.
+func BenchmarkStore_CreateExhaustiveSearchRepoJobs(b *testing.B) {
+	const count = 50_000
+
+	logger := logtest.Scoped(b)
+	db := database.NewDB(logger, dbtest.NewDB(b))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	userID, err := createUser(bs, "alice")
+	require.NoError(b, err)
+	repoID, err := createRepo(db, "repo-test")
+	require.NoError(b, err)
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	s := store.New(db, observation.TestContextTB(b))
+
+	specs := make([]types.RepositoryRevSpecs, count)
+	for i := range specs {
+		specs[i] = types.RepositoryRevSpecs{
+			Repository:         repoID,
+			RevisionSpecifiers: types.RevisionSpecifiers(fmt.Sprintf("rev-%d", i)),
+		}
+	}
+
+	newSearchJob := func(b *testing.B) int64 {
+		b.Helper()
+
+		b.StopTimer()
+		defer b.StartTimer()
+
+		id, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{InitiatorID: userID, Query: "repo:repo-test"})
+		require.NoError(b, err)
+		return id
+	}
+
+	b.Run(fmt.Sprintf("one by one/count=%d", count), func(b *testing.B) {
+		for range b.N {
+			searchJobID := newSearchJob(b)
+
+			tx, err := s.Transact(workerCtx)
+			require.NoError(b, err)
+			for _, spec := range specs {
+				_, err = tx.CreateExhaustiveSearchRepoJob(workerCtx, types.ExhaustiveSearchRepoJob{
+					SearchJobID: searchJobID,
+					RepoID:      spec.Repository,
+					RefSpec:     spec.RevisionSpecifiers.String(),
+				})
+				if err != nil {
+					break
+				}
+			}
+			require.NoError(b, tx.Done(err))
+		}
+	})
+
+	b.Run(fmt.Sprintf("batch/count=%d", count), func(b *testing.B) {
+		for range b.N {
+			searchJobID := newSearchJob(b)
+
+			created, err := s.CreateExhaustiveSearchRepoJobs(workerCtx, searchJobID, iterator.From(specs))
+			require.NoError(b, err)
+			require.Equal(b, count, created)
+		}
+	})
 }
@@ -3,9 +3,13 @@ package store
 import (
 	"context"
 	"time"
+	"unicode/utf8"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -21,18 +25,54 @@ var revSearchJobWorkerOpts = dbworkerstore.Options[*types.ExhaustiveSearchRepoRe
 
 	Scan: dbworkerstore.BuildWorkerScan(scanRevSearchJob),
 
-	OrderByExpression: sqlf.Sprintf("exhaustive_search_repo_revision_jobs.state = 'errored', exhaustive_search_repo_revision_jobs.updated_at DESC"),
+	OrderByExpression: sqlf.Sprintf(revSearchJobOrderByFmtStr),
 
-	StalledMaxAge: 60 * time.Second,
-	MaxNumResets:  maxNumResets,
+	StalledMaxAge:       DefaultStalledMaxAge,
+	MaxNumResets:        maxNumResets,
+	ResetFailureMessage: stalledFailureMessage,
 
 	RetryAfter:    5 * time.Second,
 	MaxNumRetries: maxNumRetries,
 }
 
+// revSearchJobOrderByFmtStr orders the repo revision jobs of search jobs with a
+// higher priority first. Among search jobs with the same priority, the repo
+// revision jobs of the code host with the fewest repo revision jobs in progress
+// go first, so the workers take turns between code hosts. Within a code host,
+// the repo revision jobs of the search job with the fewest repo revision jobs
+// in progress go first, so the workers take turns between search jobs instead
+// of working through a huge search job before they start on the next one. The
+// oldest repo revision job breaks ties.
+const revSearchJobOrderByFmtStr = `
+exhaustive_search_repo_revision_jobs.state = 'errored',
+(
+    SELECT j.priority
+    FROM exhaustive_search_repo_jobs rj
+    JOIN exhaustive_search_jobs j ON j.id = rj.search_job_id
+    WHERE rj.id = exhaustive_search_repo_revision_jobs.search_repo_job_id
+) DESC,
+(
+    SELECT COUNT(*)
+    FROM exhaustive_search_repo_revision_jobs rrj
+    WHERE rrj.state = 'processing' AND rrj.code_host = exhaustive_search_repo_revision_jobs.code_host
+),
+(
+    SELECT COUNT(*)
+    FROM exhaustive_search_repo_revision_jobs rrj
+    JOIN exhaustive_search_repo_jobs rj ON rj.id = rrj.search_repo_job_id
+    WHERE rrj.state = 'processing' AND rj.search_job_id = (
+        SELECT search_job_id FROM exhaustive_search_repo_jobs WHERE id = exhaustive_search_repo_revision_jobs.search_repo_job_id
+    )
+),
+exhaustive_search_repo_revision_jobs.created_at
+`
+
 // NewRevSearchJobWorkerStore returns a dbworkerstore.Store that wraps the "exhaustive_search_repo_revision_jobs" table.
-func NewRevSearchJobWorkerStore(observationCtx *observation.Context, handle basestore.TransactableHandle) dbworkerstore.Store[*types.ExhaustiveSearchRepoRevisionJob] {
-	return dbworkerstore.New(observationCtx, handle, revSearchJobWorkerOpts)
+// Records without a heartbeat for stalledMaxAge are requeued by the resetter.
+func NewRevSearchJobWorkerStore(observationCtx *observation.Context, handle basestore.TransactableHandle, stalledMaxAge time.Duration) dbworkerstore.Store[*types.ExhaustiveSearchRepoRevisionJob] {
+	opts := revSearchJobWorkerOpts
+	opts.StalledMaxAge = stalledMaxAge
+	return dbworkerstore.New(observationCtx, handle, opts)
 }
 
 var revSearchJobColumns = []*sqlf.Query{
@@ -40,6 +80,8 @@ var revSearchJobColumns = []*sqlf.Query{
 	sqlf.Sprintf("state"),
 	sqlf.Sprintf("search_repo_job_id"),
 	sqlf.Sprintf("revision"),
+	sqlf.Sprintf("commit_id"),
+	sqlf.Sprintf("code_host"),
 	sqlf.Sprintf("failure_message"),
 	sqlf.Sprintf("started_at"),
 	sqlf.Sprintf("finished_at"),
@@ -49,6 +91,7 @@ var revSearchJobColumns = []*sqlf.Query{
 	sqlf.Sprintf("execution_logs"),
 	sqlf.Sprintf("worker_hostname"),
 	sqlf.Sprintf("cancel"),
+	sqlf.Sprintf("batch_id"),
 	sqlf.Sprintf("created_at"),
 	sqlf.Sprintf("updated_at"),
 }
@@ -58,6 +101,11 @@ func (s *Store) CreateExhaustiveSearchRepoRevisionJob(ctx context.Context, job t
 	ctx, _, endObservation := s.operations.createExhaustiveSearchRepoJob.With(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
 
+	// 🚨 SECURITY: only the workers may create tasks.
+	if err = checkInternalActor(ctx); err != nil {
+		return 0, err
+	}
+
 	if job.SearchRepoJobID <= 0 {
 		return 0, MissingSearchRepoJobIDErr
 	}
@@ -67,7 +115,7 @@ func (s *Store) CreateExhaustiveSearchRepoRevisionJob(ctx context.Context, job t
 
 	row := s.Store.QueryRow(
 		ctx,
-		sqlf.Sprintf(createExhaustiveSearchRepoRevisionJobQueryFmtr, job.Revision, job.SearchRepoJobID),
+		sqlf.Sprintf(createExhaustiveSearchRepoRevisionJobQueryFmtr, job.Revision, dbutil.NullStringColumn(string(job.CommitID)), job.SearchRepoJobID, job.SearchRepoJobID),
 	)
 
 	var id int64
@@ -83,12 +131,369 @@ var MissingSearchRepoJobIDErr = errors.New("missing search repo job ID")
 // MissingRevisionErr is returned when a revision is missing.
 var MissingRevisionErr = errors.New("missing revision")
 
+// createExhaustiveSearchRepoRevisionJobQueryFmtr copies the code host of the
+// repository into the task, so the workers don't have to join the repository
+// when they dequeue tasks.
 const createExhaustiveSearchRepoRevisionJobQueryFmtr = `
-INSERT INTO exhaustive_search_repo_revision_jobs (revision, search_repo_job_id)
-VALUES (%s, %s)
+INSERT INTO exhaustive_search_repo_revision_jobs (revision, commit_id, search_repo_job_id, code_host)
+VALUES (%s, %s, %s, (
+    SELECT COALESCE(r.external_service_id, '')
+    FROM exhaustive_search_repo_jobs rj
+    JOIN repo r ON r.id = rj.repo_id
+    WHERE rj.id = %s
+))
 RETURNING id
 `
 
+// TaskBatchOptions configure how CreateBatchedRepoRevisionJob batches the
+// repo revision jobs of small repositories.
+type TaskBatchOptions struct {
+	// Size is the maximum number of repo revision jobs of a batch.
+	Size int
+
+	// MaxRepoSizeBytes is the size of the largest repository which is
+	// batched. Repositories of unknown size are never batched.
+	MaxRepoSizeBytes int64
+
+	// FillTimeout is how long the workers wait for a batch to fill up before
+	// they search it anyway. A batch is searched right away once it is full.
+	FillTimeout time.Duration
+}
+
+// CreateBatchedRepoRevisionJob is like CreateExhaustiveSearchRepoRevisionJob,
+// but it adds job to a batch of other repo revision jobs of the same search job
+// and code host if its repository is small. The workers search a batch with
+// one dequeue, one result chunk and one update of the progress of the job, see
+// RevSearchJobDequeueCondition. It returns false if job was created on its own.
+//
+// A batch stays open for more repo revision jobs until it is full, its fill
+// timeout passed or a worker picked it up. It has at most one revision of a
+// repository.
+func (s *Store) CreateBatchedRepoRevisionJob(ctx context.Context, job types.ExhaustiveSearchRepoRevisionJob, opts TaskBatchOptions) (id int64, batched bool, err error) {
+	ctx, _, endObservation := s.operations.createBatchedRepoRevisionJob.With(ctx, &err, opAttrs(
+		attribute.Int64("searchRepoJobID", job.SearchRepoJobID),
+		attribute.Int("size", opts.Size),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Bool("batched", batched)))
+	}()
+
+	// 🚨 SECURITY: only the workers may create tasks.
+	if err := checkInternalActor(ctx); err != nil {
+		return 0, false, err
+	}
+
+	if job.SearchRepoJobID <= 0 {
+		return 0, false, MissingSearchRepoJobIDErr
+	}
+	if job.Revision == "" {
+		return 0, false, MissingRevisionErr
+	}
+
+	var (
+		searchJobID int64
+		repoID      int64
+		codeHost    string
+		repoSize    *int64
+	)
+	row := s.QueryRow(ctx, sqlf.Sprintf(getBatchedRepoRevisionJobRepoFmtStr, job.SearchRepoJobID))
+	if err := row.Scan(&searchJobID, &repoID, &codeHost, &repoSize); err != nil {
+		return 0, false, err
+	}
+	if opts.Size <= 1 || repoSize == nil || *repoSize > opts.MaxRepoSizeBytes {
+		id, err := s.CreateExhaustiveSearchRepoRevisionJob(ctx, job)
+		return id, false, err
+	}
+
+	now := s.clock.Now()
+	row = s.QueryRow(ctx, sqlf.Sprintf(
+		createBatchedRepoRevisionJobFmtStr,
+		searchJobID,
+		codeHost,
+		now,
+		opts.Size,
+		repoID,
+		job.Revision,
+		dbutil.NullStringColumn(string(job.CommitID)),
+		job.SearchRepoJobID,
+		codeHost,
+		now.Add(opts.FillTimeout),
+		opts.Size,
+	))
+	if err := row.Scan(&id); err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+const getBatchedRepoRevisionJobRepoFmtStr = `
+SELECT rj.search_job_id, rj.repo_id, COALESCE(r.external_service_id, ''), gr.repo_size_bytes
+FROM exhaustive_search_repo_jobs rj
+JOIN repo r ON r.id = rj.repo_id
+LEFT JOIN gitserver_repos gr ON gr.repo_id = rj.repo_id
+WHERE rj.id = %s
+`
+
+// createBatchedRepoRevisionJobFmtStr adds a repo revision job to the oldest
+// open batch, or starts a new batch with it. A batch is open as long as its
+// first task is queued before its fill timeout. The first task is locked, so
+// that a worker can't dequeue the batch while we add to it, and concurrent
+// writers start new batches instead of waiting for each other. The fill
+// timeout of a batch is cleared once it is full, so that it is searched right
+// away.
+const createBatchedRepoRevisionJobFmtStr = `
+WITH batch AS (
+    SELECT b.id, (SELECT COUNT(*) FROM exhaustive_search_repo_revision_jobs m WHERE m.batch_id = b.id) AS size
+    FROM exhaustive_search_repo_revision_jobs b
+    JOIN exhaustive_search_repo_jobs rj ON rj.id = b.search_repo_job_id
+    WHERE
+        rj.search_job_id = %s
+        AND b.code_host = %s
+        AND b.batch_id = b.id
+        AND b.state = 'queued'
+        AND b.num_failures = 0
+        AND NOT b.cancel
+        AND b.process_after > %s
+        AND (SELECT COUNT(*) FROM exhaustive_search_repo_revision_jobs m WHERE m.batch_id = b.id) < %s
+        AND NOT EXISTS (
+            SELECT 1
+            FROM exhaustive_search_repo_revision_jobs m
+            JOIN exhaustive_search_repo_jobs mrj ON mrj.id = m.search_repo_job_id
+            WHERE m.batch_id = b.id AND mrj.repo_id = %s
+        )
+    ORDER BY b.id
+    LIMIT 1
+    FOR UPDATE OF b SKIP LOCKED
+),
+next AS (
+    SELECT nextval('exhaustive_search_repo_revision_jobs_id_seq') AS id
+),
+inserted AS (
+    INSERT INTO exhaustive_search_repo_revision_jobs (id, revision, commit_id, search_repo_job_id, code_host, batch_id, process_after)
+    SELECT
+        next.id, %s, %s, %s, %s,
+        COALESCE((SELECT id FROM batch), next.id),
+        CASE WHEN EXISTS (SELECT 1 FROM batch) THEN NULL ELSE %s::timestamptz END
+    FROM next
+    RETURNING id
+),
+closed AS (
+    UPDATE exhaustive_search_repo_revision_jobs b
+    SET process_after = NULL
+    FROM batch
+    WHERE b.id = batch.id AND batch.size + 1 >= %s
+)
+SELECT id FROM inserted
+`
+
+// RevSearchJobDequeueCondition restricts the dequeue of repo revision jobs to
+// those that aren't batched, and to the first task of a batch which is still
+// to be searched, as long as no other task of its batch is processing. That
+// task searches the other tasks of the batch too, see
+// ListRepoRevisionBatchMembers.
+func RevSearchJobDequeueCondition() *sqlf.Query {
+	return sqlf.Sprintf(revSearchJobDequeueConditionFmtStr)
+}
+
+const revSearchJobDequeueConditionFmtStr = `
+(
+    exhaustive_search_repo_revision_jobs.batch_id IS NULL
+    OR NOT EXISTS (
+        SELECT 1
+        FROM exhaustive_search_repo_revision_jobs m
+        WHERE
+            m.batch_id = exhaustive_search_repo_revision_jobs.batch_id
+            AND m.id <> exhaustive_search_repo_revision_jobs.id
+            AND (
+                m.state = 'processing'
+                OR (m.state IN ('queued', 'errored') AND NOT m.cancel AND m.id < exhaustive_search_repo_revision_jobs.id)
+            )
+    )
+)
+`
+
+// ListRepoRevisionBatchMembers returns the other tasks of the batch of
+// leader, a repo revision job which is processing, that are still to be
+// searched, ordered by ID.
+func (s *Store) ListRepoRevisionBatchMembers(ctx context.Context, leader *types.ExhaustiveSearchRepoRevisionJob) (members []types.RepoRevisionBatchMember, err error) {
+	ctx, _, endObservation := s.operations.listRepoRevisionBatchMembers.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", leader.ID),
+		attribute.Int64("batchID", leader.BatchID),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("length", len(members))))
+	}()
+
+	// 🚨 SECURITY: only the workers may read the tasks of any user.
+	if err := checkInternalActor(ctx); err != nil {
+		return nil, err
+	}
+
+	if leader.BatchID == 0 {
+		return nil, nil
+	}
+	return scanRepoRevisionBatchMembers(s.Query(ctx, sqlf.Sprintf(listRepoRevisionBatchMembersFmtStr, leader.BatchID, leader.ID)))
+}
+
+const listRepoRevisionBatchMembersFmtStr = `
+SELECT
+    rrj.id,
+    rrj.state,
+    rrj.search_repo_job_id,
+    rrj.revision,
+    rrj.commit_id,
+    rrj.code_host,
+    rrj.failure_message,
+    rrj.started_at,
+    rrj.finished_at,
+    rrj.process_after,
+    rrj.num_resets,
+    rrj.num_failures,
+    rrj.worker_hostname,
+    rrj.cancel,
+    rrj.batch_id,
+    rrj.created_at,
+    rrj.updated_at,
+    rj.repo_id,
+    rj.ref_spec
+FROM exhaustive_search_repo_revision_jobs rrj
+JOIN exhaustive_search_repo_jobs rj ON rj.id = rrj.search_repo_job_id
+WHERE rrj.batch_id = %s AND rrj.id <> %s AND rrj.state IN ('queued', 'errored') AND NOT rrj.cancel
+ORDER BY rrj.id
+`
+
+var scanRepoRevisionBatchMembers = basestore.NewSliceScanner(func(sc dbutil.Scanner) (m types.RepoRevisionBatchMember, err error) {
+	err = sc.Scan(
+		&m.ID,
+		&m.State,
+		&m.SearchRepoJobID,
+		&m.Revision,
+		&dbutil.NullString{S: (*string)(&m.CommitID)},
+		&m.CodeHost,
+		&dbutil.NullString{S: &m.FailureMessage},
+		&dbutil.NullTime{Time: &m.StartedAt},
+		&dbutil.NullTime{Time: &m.FinishedAt},
+		&dbutil.NullTime{Time: &m.ProcessAfter},
+		&m.NumResets,
+		&m.NumFailures,
+		&m.WorkerHostname,
+		&m.Cancel,
+		&dbutil.NullInt64{N: &m.BatchID},
+		&m.CreatedAt,
+		&m.UpdatedAt,
+		&m.RepoRev.Repository,
+		&m.RepoRev.RevisionSpecifiers,
+	)
+	m.RepoRev.Revision = m.Revision
+	m.RepoRev.CommitID = m.CommitID
+	return m, err
+})
+
+// BatchMemberOutcome is the outcome of the search of a task of a batch, see
+// FinishRepoRevisionBatchMembers.
+type BatchMemberOutcome struct {
+	ID int64
+
+	// State is completed, failed, or queued if the search is retried.
+	State types.JobState
+
+	// FailureMessage is the error of the search, if it failed.
+	FailureMessage string
+}
+
+// FinishRepoRevisionBatchMembers records the outcomes of the searches of the
+// other tasks of the batch of leader, which started at startedAt. Tasks which
+// are retried are put back into the queue until retryAfter, and count the
+// attempt as a failure like RequeueRepoRevisionJob. The tasks are marked with
+// the worker of leader. Tasks which were canceled or finished in the meantime
+// are left alone.
+//
+// The worker marks leader itself once its handler returns.
+func (s *Store) FinishRepoRevisionBatchMembers(ctx context.Context, leader *types.ExhaustiveSearchRepoRevisionJob, startedAt, retryAfter time.Time, outcomes []BatchMemberOutcome) (err error) {
+	ctx, _, endObservation := s.operations.finishRepoRevisionBatchMembers.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", leader.ID),
+		attribute.Int64("batchID", leader.BatchID),
+		attribute.Int("length", len(outcomes)),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers may mark tasks.
+	if err := checkInternalActor(ctx); err != nil {
+		return err
+	}
+
+	if len(outcomes) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(outcomes))
+	states := make([]string, 0, len(outcomes))
+	messages := make([]string, 0, len(outcomes))
+	for _, o := range outcomes {
+		ids = append(ids, o.ID)
+		states = append(states, string(o.State))
+		messages = append(messages, TruncateFailureMessage(o.FailureMessage))
+	}
+
+	now := s.clock.Now()
+	return s.Exec(ctx, sqlf.Sprintf(
+		finishRepoRevisionBatchMembersFmtStr,
+		startedAt,
+		now,
+		now,
+		retryAfter,
+		pq.Array(ids),
+		pq.Array(states),
+		pq.Array(messages),
+		leader.BatchID,
+		leader.ID,
+	))
+}
+
+const finishRepoRevisionBatchMembersFmtStr = `
+UPDATE exhaustive_search_repo_revision_jobs rrj
+SET
+    state = o.state,
+    started_at = CASE WHEN o.state = 'queued' THEN NULL ELSE %s::timestamptz END,
+    finished_at = CASE WHEN o.state = 'queued' THEN NULL ELSE %s::timestamptz END,
+    queued_at = CASE WHEN o.state = 'queued' THEN %s::timestamptz ELSE rrj.queued_at END,
+    process_after = CASE WHEN o.state = 'queued' THEN %s::timestamptz ELSE rrj.process_after END,
+    failure_message = NULLIF(o.failure_message, ''),
+    num_failures = rrj.num_failures + CASE WHEN o.state = 'completed' THEN 0 ELSE 1 END,
+    worker_hostname = l.worker_hostname,
+    worker_started_at = l.worker_started_at
+FROM unnest(%s::integer[], %s::text[], %s::text[]) AS o(id, state, failure_message), exhaustive_search_repo_revision_jobs l
+WHERE
+    rrj.id = o.id
+    AND rrj.batch_id = %s
+    AND l.id = %s
+    AND rrj.state IN ('queued', 'errored')
+    AND NOT rrj.cancel
+`
+
+// HasRepoRevisionJobs returns true if repo job searchRepoJobID created its
+// repo revision jobs already. A repo job creates all of them in one
+// transaction, so it is retried without creating them twice if its worker
+// died before it marked it as completed.
+func (s *Store) HasRepoRevisionJobs(ctx context.Context, searchRepoJobID int64) (has bool, err error) {
+	ctx, _, endObservation := s.operations.hasRepoRevisionJobs.With(ctx, &err, opAttrs(
+		attribute.Int64("searchRepoJobID", searchRepoJobID),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers may read the tasks of any user.
+	if err := checkInternalActor(ctx); err != nil {
+		return false, err
+	}
+
+	has, _, err = basestore.ScanFirstBool(s.Query(ctx, sqlf.Sprintf(hasRepoRevisionJobsFmtStr, searchRepoJobID)))
+	return has, err
+}
+
+const hasRepoRevisionJobsFmtStr = `
+SELECT EXISTS (SELECT 1 FROM exhaustive_search_repo_revision_jobs WHERE search_repo_job_id = %s)
+`
+
 const getQueryRepoRevFmtStr = `
 SELECT sj.id, sj.initiator_id, sj.query, srj.repo_id, srj.ref_spec
 FROM exhaustive_search_repo_jobs srj
@@ -103,15 +508,350 @@ func (s *Store) GetQueryRepoRev(ctx context.Context, job *types.ExhaustiveSearch
 	initiatorID int32,
 	err error,
 ) {
+	ctx, _, endObservation := s.operations.getQueryRepoRev.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", job.ID),
+		attribute.Int64("searchRepoJobID", job.SearchRepoJobID),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers may read the tasks of any user.
+	if err := checkInternalActor(ctx); err != nil {
+		return 0, "", types.RepositoryRevision{}, -1, err
+	}
+
 	row := s.QueryRow(ctx, sqlf.Sprintf(getQueryRepoRevFmtStr, job.SearchRepoJobID))
 	err = row.Scan(&id, &initiatorID, &query, &repoRev.Repository, &repoRev.RevisionSpecifiers)
 	if err != nil {
 		return 0, "", types.RepositoryRevision{}, -1, err
 	}
 	repoRev.Revision = job.Revision
+	repoRev.CommitID = job.CommitID
 	return id, query, repoRev, initiatorID, nil
 }
 
+// RequeueRepoRevisionJob puts repo revision job id, which is being processed,
+// back into the queue to be retried after after. It counts the attempt as a
+// failure and keeps failureMessage as the last error. It returns false if the
+// job is not processing anymore or was canceled.
+func (s *Store) RequeueRepoRevisionJob(ctx context.Context, id int64, after time.Time, failureMessage string) (requeued bool, err error) {
+	ctx, _, endObservation := s.operations.requeueRepoRevisionJob.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Stringer("after", after),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers may requeue tasks.
+	if err := checkInternalActor(ctx); err != nil {
+		return false, err
+	}
+
+	_, requeued, err = basestore.ScanFirstInt64(s.Query(ctx, sqlf.Sprintf(requeueRepoRevisionJobFmtStr, after, TruncateFailureMessage(failureMessage), id)))
+	return requeued, err
+}
+
+const requeueRepoRevisionJobFmtStr = `
+UPDATE exhaustive_search_repo_revision_jobs
+SET state = 'queued',
+    queued_at = clock_timestamp(),
+    started_at = NULL,
+    process_after = %s,
+    failure_message = %s,
+    num_failures = num_failures + 1
+WHERE id = %s AND state = 'processing' AND NOT cancel
+RETURNING id
+`
+
+// PostponeRepoRevisionJob puts repo revision job id, which is being processed,
+// back into the queue to be picked up after after. Unlike
+// RequeueRepoRevisionJob it doesn't count as an attempt. It returns false if
+// the job is not processing anymore or was canceled.
+func (s *Store) PostponeRepoRevisionJob(ctx context.Context, id int64, after time.Time) (postponed bool, err error) {
+	ctx, _, endObservation := s.operations.postponeRepoRevisionJob.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Stringer("after", after),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers may postpone tasks.
+	if err := checkInternalActor(ctx); err != nil {
+		return false, err
+	}
+
+	_, postponed, err = basestore.ScanFirstInt64(s.Query(ctx, sqlf.Sprintf(postponeRepoRevisionJobFmtStr, after, id)))
+	return postponed, err
+}
+
+const postponeRepoRevisionJobFmtStr = `
+UPDATE exhaustive_search_repo_revision_jobs
+SET state = 'queued',
+    queued_at = clock_timestamp(),
+    started_at = NULL,
+    process_after = %s
+WHERE id = %s AND state = 'processing' AND NOT cancel
+RETURNING id
+`
+
+// SetRepoRevisionJobWorkerStartedAt records when the process of the worker
+// which claimed repo revision job id started. The worker stored its hostname
+// when it dequeued the job. Both are kept once the job finished, to tell which
+// worker processed it.
+func (s *Store) SetRepoRevisionJobWorkerStartedAt(ctx context.Context, id int64, startedAt time.Time) (err error) {
+	ctx, _, endObservation := s.operations.setRepoRevisionJobWorkerStartedAt.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only the workers claim tasks.
+	if err := checkInternalActor(ctx); err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(setRepoRevisionJobWorkerStartedAtFmtStr, startedAt, id))
+}
+
+const setRepoRevisionJobWorkerStartedAtFmtStr = `
+UPDATE exhaustive_search_repo_revision_jobs
+SET worker_started_at = %s
+WHERE id = %s AND state = 'processing'
+`
+
+// ListSearchJobTasksOpts are the options of ListSearchJobTasks.
+type ListSearchJobTasksOpts struct {
+	// State only returns the tasks in this state if set.
+	State types.JobState
+
+	// After only returns the tasks with a greater ID if set.
+	After int64
+
+	// Limit is the maximum number of tasks returned, all if 0.
+	Limit int
+}
+
+// ListSearchJobTasks returns the repo revision jobs of search job id, ordered
+// by ID. Only site admins may list them. Unlike GetJobLogs, it reads the
+// primary, since support acts on what it reads.
+func (s *Store) ListSearchJobTasks(ctx context.Context, id int64, opts ListSearchJobTasksOpts) (tasks []types.SearchJobTask, err error) {
+	ctx, _, endObservation := s.operations.listSearchJobTasks.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.String("state", string(opts.State)),
+		attribute.Int64("after", opts.After),
+		attribute.Int("limit", opts.Limit),
+	))
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("length", len(tasks))))
+	}()
+
+	// 🚨 SECURITY: the tasks are listed for support, regardless of who
+	// created the job.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db); err != nil {
+		return nil, err
+	}
+
+	conds := []*sqlf.Query{sqlf.Sprintf("rj.search_job_id = %s", id)}
+	if opts.State != "" {
+		conds = append(conds, sqlf.Sprintf("rrj.state = %s", opts.State))
+	}
+	if opts.After != 0 {
+		conds = append(conds, sqlf.Sprintf("rrj.id > %s", opts.After))
+	}
+	limit := sqlf.Sprintf("")
+	if opts.Limit != 0 {
+		limit = sqlf.Sprintf("LIMIT %s", opts.Limit)
+	}
+
+	return scanSearchJobTasks(s.Query(ctx, sqlf.Sprintf(listSearchJobTasksFmtStr, sqlf.Join(conds, "AND"), limit)))
+}
+
+const searchJobTaskColumnsFmtStr = `
+SELECT
+    rrj.id,
+    rrj.state,
+    rrj.search_repo_job_id,
+    rrj.revision,
+    rrj.commit_id,
+    rrj.code_host,
+    rrj.failure_message,
+    rrj.started_at,
+    rrj.finished_at,
+    rrj.process_after,
+    rrj.num_resets,
+    rrj.num_failures,
+    rrj.last_heartbeat_at,
+    rrj.worker_hostname,
+    rrj.worker_started_at,
+    rrj.cancel,
+    rrj.batch_id,
+    rrj.created_at,
+    rrj.updated_at,
+    rrj.queued_at,
+    rj.search_job_id,
+    rj.repo_id,
+    r.name
+FROM exhaustive_search_repo_revision_jobs rrj
+JOIN exhaustive_search_repo_jobs rj ON rj.id = rrj.search_repo_job_id
+JOIN repo r ON r.id = rj.repo_id
+`
+
+const listSearchJobTasksFmtStr = searchJobTaskColumnsFmtStr + `
+WHERE %s
+ORDER BY rrj.id
+%s
+`
+
+// GetSearchJobTask returns repo revision job id, or ErrNoResults if it doesn't
+// exist. Only site admins may get it.
+func (s *Store) GetSearchJobTask(ctx context.Context, id int64) (_ *types.SearchJobTask, err error) {
+	ctx, _, endObservation := s.operations.getSearchJobTask.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: the task is read for support, regardless of who created
+	// the job.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db); err != nil {
+		return nil, err
+	}
+
+	task, ok, err := scanSearchJobTask(s.Query(ctx, sqlf.Sprintf(getSearchJobTaskFmtStr, id)))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoResults
+	}
+	return &task, nil
+}
+
+const getSearchJobTaskFmtStr = searchJobTaskColumnsFmtStr + `
+WHERE rrj.id = %s
+`
+
+// ForceFailSearchJobTask marks repo revision job id as failed with
+// failureMessage, so that the workers don't pick it up (again). Queued,
+// errored and processing jobs can be failed; a worker which is still
+// processing the job can't mark it as completed afterwards. It returns false
+// if the job is in another state. Only site admins may fail jobs.
+func (s *Store) ForceFailSearchJobTask(ctx context.Context, id int64, failureMessage string) (failed bool, err error) {
+	ctx, _, endObservation := s.operations.forceFailSearchJobTask.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only site admins may fail the tasks of any user.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db); err != nil {
+		return false, err
+	}
+
+	_, failed, err = basestore.ScanFirstInt64(s.Query(ctx, sqlf.Sprintf(forceFailSearchJobTaskFmtStr, s.clock.Now(), TruncateFailureMessage(failureMessage), id)))
+	return failed, err
+}
+
+const forceFailSearchJobTaskFmtStr = `
+UPDATE exhaustive_search_repo_revision_jobs
+SET state = 'failed',
+    finished_at = %s,
+    failure_message = %s
+WHERE id = %s AND state IN ('queued', 'errored', 'processing')
+RETURNING id
+`
+
+// ForceRequeueSearchJobTask puts repo revision job id, which failed or
+// errored, back into the queue with all its retries, like
+// RetryFailedSearchJobTasks does for the failed jobs of a search job. A job
+// which is stuck in processing has to be failed first. The initiator of the
+// search job is notified again once it finished. It returns false if the job
+// is in another state or was canceled. Only site admins may requeue jobs.
+func (s *Store) ForceRequeueSearchJobTask(ctx context.Context, id int64) (requeued bool, err error) {
+	ctx, _, endObservation := s.operations.forceRequeueSearchJobTask.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only site admins may requeue the tasks of any user.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db); err != nil {
+		return false, err
+	}
+
+	_, requeued, err = basestore.ScanFirstInt64(s.Query(ctx, sqlf.Sprintf(forceRequeueSearchJobTaskFmtStr, s.clock.Now(), id)))
+	return requeued, err
+}
+
+const forceRequeueSearchJobTaskFmtStr = `
+WITH requeued AS (
+    UPDATE exhaustive_search_repo_revision_jobs
+    SET state = 'queued',
+        queued_at = %s,
+        failure_message = NULL,
+        started_at = NULL,
+        finished_at = NULL,
+        process_after = NULL,
+        num_failures = 0,
+        num_resets = num_resets + 1
+    WHERE id = %s AND state IN ('failed', 'errored') AND NOT cancel
+    RETURNING id, search_repo_job_id
+),
+notify AS (
+    UPDATE exhaustive_search_jobs j
+    SET notified_at = NULL
+    FROM requeued
+    JOIN exhaustive_search_repo_jobs rj ON rj.id = requeued.search_repo_job_id
+    WHERE j.id = rj.search_job_id
+)
+SELECT id FROM requeued
+`
+
+var scanSearchJobTasks = basestore.NewSliceScanner(scanSearchJobTaskRow)
+var scanSearchJobTask = basestore.NewFirstScanner(scanSearchJobTaskRow)
+
+func scanSearchJobTaskRow(sc dbutil.Scanner) (task types.SearchJobTask, _ error) {
+	return task, sc.Scan(
+		&task.ID,
+		&task.State,
+		&task.SearchRepoJobID,
+		&task.Revision,
+		&dbutil.NullString{S: (*string)(&task.CommitID)},
+		&task.CodeHost,
+		&dbutil.NullString{S: &task.FailureMessage},
+		&dbutil.NullTime{Time: &task.StartedAt},
+		&dbutil.NullTime{Time: &task.FinishedAt},
+		&dbutil.NullTime{Time: &task.ProcessAfter},
+		&task.NumResets,
+		&task.NumFailures,
+		&dbutil.NullTime{Time: &task.LastHeartbeatAt},
+		&task.WorkerHostname,
+		&dbutil.NullTime{Time: &task.WorkerStartedAt},
+		&task.Cancel,
+		&dbutil.NullInt64{N: &task.BatchID},
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&dbutil.NullTime{Time: &task.QueuedAt},
+		&task.SearchJobID,
+		&task.RepoID,
+		&task.RepoName,
+	)
+}
+
+// MaxFailureMessageLength is the maximum length in bytes of the failure message
+// kept for a repo revision job. Search errors can contain large responses of
+// other services, which we don't want to store for every revision of a job.
+const MaxFailureMessageLength = 1024
+
+// truncatedSuffix marks a failure message which was truncated.
+const truncatedSuffix = "... (truncated)"
+
+// TruncateFailureMessage shortens msg to at most MaxFailureMessageLength bytes
+// without splitting a UTF-8 encoded character.
+func TruncateFailureMessage(msg string) string {
+	if len(msg) <= MaxFailureMessageLength {
+		return msg
+	}
+	cut := MaxFailureMessageLength - len(truncatedSuffix)
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	return msg[:cut] + truncatedSuffix
+}
+
 func scanRevSearchJob(sc dbutil.Scanner) (*types.ExhaustiveSearchRepoRevisionJob, error) {
 	var job types.ExhaustiveSearchRepoRevisionJob
 	// required field for the sync worker, but
@@ -123,6 +863,8 @@ func scanRevSearchJob(sc dbutil.Scanner) (*types.ExhaustiveSearchRepoRevisionJob
 		&job.State,
 		&job.SearchRepoJobID,
 		&job.Revision,
+		&dbutil.NullString{S: (*string)(&job.CommitID)},
+		&job.CodeHost,
 		&dbutil.NullString{S: &job.FailureMessage},
 		&dbutil.NullTime{Time: &job.StartedAt},
 		&dbutil.NullTime{Time: &job.FinishedAt},
@@ -132,6 +874,7 @@ func scanRevSearchJob(sc dbutil.Scanner) (*types.ExhaustiveSearchRepoRevisionJob
 		&executionLogs,
 		&job.WorkerHostname,
 		&job.Cancel,
+		&dbutil.NullInt64{N: &job.BatchID},
 		&job.CreatedAt,
 		&job.UpdatedAt,
 	)
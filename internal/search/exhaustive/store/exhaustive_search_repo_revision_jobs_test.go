@@ -2,19 +2,26 @@ package store_test
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/log/logtest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -45,12 +52,19 @@ func TestStore_CreateExhaustiveSearchRepoRevisionJob(t *testing.T) {
 	)
 	require.NoError(t, err)
 
+	// Only the workers create tasks.
+	workerCtx := actor.WithInternalActor(context.Background())
+
 	repoJobID, err := s.CreateExhaustiveSearchRepoJob(
-		context.Background(),
+		workerCtx,
 		types.ExhaustiveSearchRepoJob{SearchJobID: searchJobID, RepoID: repoID, RefSpec: "main"},
 	)
 	require.NoError(t, err)
 
+	has, err := s.HasRepoRevisionJobs(workerCtx, repoJobID)
+	require.NoError(t, err)
+	require.False(t, has)
+
 	tests := []struct {
 		name        string
 		job         types.ExhaustiveSearchRepoRevisionJob
@@ -64,6 +78,15 @@ func TestStore_CreateExhaustiveSearchRepoRevisionJob(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "New job with resolved commit",
+			job: types.ExhaustiveSearchRepoRevisionJob{
+				SearchRepoJobID: repoJobID,
+				Revision:        "main",
+				CommitID:        "deadbeef",
+			},
+			expectedErr: nil,
+		},
 		{
 			name: "Missing revision",
 			job: types.ExhaustiveSearchRepoRevisionJob{
@@ -81,7 +104,7 @@ func TestStore_CreateExhaustiveSearchRepoRevisionJob(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			jobID, err := s.CreateExhaustiveSearchRepoRevisionJob(ctx, test.job)
+			jobID, err := s.CreateExhaustiveSearchRepoRevisionJob(workerCtx, test.job)
 
 			if test.expectedErr != nil {
 				require.Error(t, err)
@@ -92,4 +115,588 @@ func TestStore_CreateExhaustiveSearchRepoRevisionJob(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("has repo revision jobs", func(t *testing.T) {
+		has, err := s.HasRepoRevisionJobs(workerCtx, repoJobID)
+		require.NoError(t, err)
+		require.True(t, has)
+
+		_, err = s.HasRepoRevisionJobs(ctx, repoJobID)
+		require.ErrorIs(t, err, store.ErrNotInternalActor)
+	})
+
+	t.Run("code host", func(t *testing.T) {
+		err := bs.Exec(context.Background(), sqlf.Sprintf("UPDATE repo SET external_service_id = %s WHERE id = %s", "https://github.com/", repoID))
+		require.NoError(t, err)
+
+		jobID, err := s.CreateExhaustiveSearchRepoRevisionJob(workerCtx, types.ExhaustiveSearchRepoRevisionJob{
+			SearchRepoJobID: repoJobID,
+			Revision:        "main",
+		})
+		require.NoError(t, err)
+
+		// The task keeps the code host the repository had when it was
+		// created.
+		codeHost, err := basestore.ScanAny[string](bs.QueryRow(context.Background(), sqlf.Sprintf("SELECT code_host FROM exhaustive_search_repo_revision_jobs WHERE id = %s", jobID)))
+		require.NoError(t, err)
+		require.Equal(t, "https://github.com/", codeHost)
+	})
+
+	t.Run("users can't create tasks", func(t *testing.T) {
+		_, err := s.CreateExhaustiveSearchRepoRevisionJob(ctx, types.ExhaustiveSearchRepoRevisionJob{
+			SearchRepoJobID: repoJobID,
+			Revision:        "main",
+		})
+		require.ErrorIs(t, err, store.ErrNotInternalActor)
+	})
+}
+
+func TestStore_ScanJobLogs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	repoID, err := createRepo(db, "repo-test")
+	require.NoError(t, err)
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	s := store.New(db, observation.TestContextTB(t))
+
+	searchJobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{InitiatorID: userID, Query: "repo:test"})
+	require.NoError(t, err)
+	repoJobID, err := s.CreateExhaustiveSearchRepoJob(workerCtx, types.ExhaustiveSearchRepoJob{SearchJobID: searchJobID, RepoID: repoID, RefSpec: "*"})
+	require.NoError(t, err)
+
+	createTask := func(revision string) int64 {
+		t.Helper()
+		id, err := s.CreateExhaustiveSearchRepoRevisionJob(workerCtx, types.ExhaustiveSearchRepoRevisionJob{SearchRepoJobID: repoJobID, Revision: revision})
+		require.NoError(t, err)
+		return id
+	}
+
+	var wantIDs []int64
+	for _, revision := range []string{"a", "b", "c", "d", "e"} {
+		wantIDs = append(wantIDs, createTask(revision))
+	}
+
+	t.Run("batches", func(t *testing.T) {
+		for _, batchSize := range []int{1, 2, 5, 10} {
+			var ids []int64
+			err := s.ScanJobLogs(ctx, searchJobID, batchSize, func(log types.SearchJobLog) error {
+				require.Equal(t, api.RepoName("repo-test"), log.RepoName)
+				ids = append(ids, log.ID)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, wantIDs, ids, "batch size %d", batchSize)
+		}
+	})
+
+	t.Run("tasks created while scanning", func(t *testing.T) {
+		// The tasks created during the scan come after the cursor, so every
+		// task is seen once, and the new ones too.
+		var ids []int64
+		err := s.ScanJobLogs(ctx, searchJobID, 2, func(log types.SearchJobLog) error {
+			if len(ids) == 0 {
+				wantIDs = append(wantIDs, createTask("f"))
+			}
+			ids = append(ids, log.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, wantIDs, ids)
+	})
+
+	t.Run("errors stop the scan", func(t *testing.T) {
+		calls := 0
+		wantErr := errors.New("stop")
+		err := s.ScanJobLogs(ctx, searchJobID, 2, func(types.SearchJobLog) error {
+			calls++
+			return wantErr
+		})
+		require.ErrorIs(t, err, wantErr)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("other users can't scan the logs", func(t *testing.T) {
+		otherID, err := createUser(bs, "bob")
+		require.NoError(t, err)
+
+		err = s.ScanJobLogs(actor.WithActor(context.Background(), actor.FromUser(otherID)), searchJobID, 2, func(types.SearchJobLog) error {
+			t.Fatal("unexpected log")
+			return nil
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestStore_WorkerIdentity(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	adminID, err := createUser(bs, "admin")
+	require.NoError(t, err)
+	repoID, err := createRepo(db, "repo-test")
+	require.NoError(t, err)
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	s := store.New(db, observation.TestContextTB(t))
+
+	searchJobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{InitiatorID: userID, Query: "repo:test"})
+	require.NoError(t, err)
+	repoJobID, err := s.CreateExhaustiveSearchRepoJob(workerCtx, types.ExhaustiveSearchRepoJob{SearchJobID: searchJobID, RepoID: repoID, RefSpec: "*"})
+	require.NoError(t, err)
+
+	workerA := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	workerB := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	// runTask claims a task as worker hostname, started at startedAt, and
+	// finishes it in state.
+	runTask := func(hostname string, startedAt time.Time, state types.JobState) int64 {
+		t.Helper()
+		id, err := s.CreateExhaustiveSearchRepoRevisionJob(workerCtx, types.ExhaustiveSearchRepoRevisionJob{SearchRepoJobID: repoJobID, Revision: "main"})
+		require.NoError(t, err)
+		err = bs.Exec(context.Background(), sqlf.Sprintf("UPDATE exhaustive_search_repo_revision_jobs SET state = 'processing', worker_hostname = %s WHERE id = %s", hostname, id))
+		require.NoError(t, err)
+		require.NoError(t, s.SetRepoRevisionJobWorkerStartedAt(workerCtx, id, startedAt))
+		err = bs.Exec(context.Background(), sqlf.Sprintf("UPDATE exhaustive_search_repo_revision_jobs SET state = %s, finished_at = NOW() WHERE id = %s", string(state), id))
+		require.NoError(t, err)
+		return id
+	}
+
+	runTask("worker-a", workerA, types.JobStateCompleted)
+	runTask("worker-a", workerA, types.JobStateCompleted)
+	runTask("worker-b", workerB, types.JobStateFailed)
+	runTask("worker-b", workerB, types.JobStateCompleted)
+	runTask("worker-a", workerB, types.JobStateFailed)
+
+	t.Run("only workers record their start time", func(t *testing.T) {
+		err := s.SetRepoRevisionJobWorkerStartedAt(ctx, 1, workerA)
+		require.ErrorIs(t, err, store.ErrNotInternalActor)
+	})
+
+	t.Run("job logs", func(t *testing.T) {
+		logs, err := s.GetJobLogs(ctx, searchJobID, nil)
+		require.NoError(t, err)
+		require.Len(t, logs, 5)
+		require.Equal(t, "worker-b", logs[2].WorkerHostname)
+		require.True(t, workerB.Equal(logs[2].WorkerStartedAt))
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		now := time.Now()
+		stats, err := s.GetWorkerTaskStats(adminCtx, now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, stats, 3)
+		for i := range stats {
+			stats[i].WorkerStartedAt = stats[i].WorkerStartedAt.UTC()
+		}
+		require.Equal(t, []types.WorkerTaskStats{
+			{WorkerHostname: "worker-a", WorkerStartedAt: workerB, Tasks: 1, Failed: 1},
+			{WorkerHostname: "worker-b", WorkerStartedAt: workerB, Tasks: 2, Completed: 1, Failed: 1},
+			{WorkerHostname: "worker-a", WorkerStartedAt: workerA, Tasks: 2, Completed: 2},
+		}, stats)
+		require.Equal(t, 0.5, stats[1].FailureRate())
+
+		// The tasks finished before the window.
+		stats, err = s.GetWorkerTaskStats(adminCtx, now.Add(time.Hour), now.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, stats)
+
+		_, err = s.GetWorkerTaskStats(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+	})
+}
+
+func TestStore_SearchJobTasks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	adminID, err := createUser(bs, "admin")
+	require.NoError(t, err)
+	repoID, err := createRepo(db, "repo-test")
+	require.NoError(t, err)
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	s := store.New(db, observation.TestContextTB(t))
+
+	searchJobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{InitiatorID: userID, Query: "repo:test"})
+	require.NoError(t, err)
+	repoJobID, err := s.CreateExhaustiveSearchRepoJob(workerCtx, types.ExhaustiveSearchRepoJob{SearchJobID: searchJobID, RepoID: repoID, RefSpec: "*"})
+	require.NoError(t, err)
+
+	var ids []int64
+	for _, rev := range []string{"a", "b", "c"} {
+		id, err := s.CreateExhaustiveSearchRepoRevisionJob(workerCtx, types.ExhaustiveSearchRepoRevisionJob{SearchRepoJobID: repoJobID, Revision: rev})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	t.Run("only site admins", func(t *testing.T) {
+		_, err := s.ListSearchJobTasks(ctx, searchJobID, store.ListSearchJobTasksOpts{})
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = s.GetSearchJobTask(ctx, ids[0])
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = s.ForceFailSearchJobTask(ctx, ids[0], "stuck")
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = s.ForceRequeueSearchJobTask(ctx, ids[0])
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+	})
+
+	t.Run("list and get", func(t *testing.T) {
+		tasks, err := s.ListSearchJobTasks(adminCtx, searchJobID, store.ListSearchJobTasksOpts{})
+		require.NoError(t, err)
+		require.Len(t, tasks, 3)
+		require.Equal(t, "repo-test", string(tasks[0].RepoName))
+		require.Equal(t, searchJobID, tasks[0].SearchJobID)
+		require.Equal(t, types.JobStateQueued, tasks[0].State)
+		require.False(t, tasks[0].QueuedAt.IsZero())
+
+		tasks, err = s.ListSearchJobTasks(adminCtx, searchJobID, store.ListSearchJobTasksOpts{After: ids[0], Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, ids[1], tasks[0].ID)
+
+		task, err := s.GetSearchJobTask(adminCtx, ids[2])
+		require.NoError(t, err)
+		require.Equal(t, "c", task.Revision)
+
+		_, err = s.GetSearchJobTask(adminCtx, 9999)
+		require.ErrorIs(t, err, store.ErrNoResults)
+	})
+
+	t.Run("force fail and requeue", func(t *testing.T) {
+		err := bs.Exec(context.Background(), sqlf.Sprintf("UPDATE exhaustive_search_jobs SET notified_at = NOW() WHERE id = %s", searchJobID))
+		require.NoError(t, err)
+
+		ok, err := s.ForceFailSearchJobTask(adminCtx, ids[0], "stuck")
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// Failed tasks can't be failed again.
+		ok, err = s.ForceFailSearchJobTask(adminCtx, ids[0], "stuck")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		tasks, err := s.ListSearchJobTasks(adminCtx, searchJobID, store.ListSearchJobTasksOpts{State: types.JobStateFailed})
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, "stuck", tasks[0].FailureMessage)
+		require.False(t, tasks[0].FinishedAt.IsZero())
+
+		// Queued tasks can't be requeued.
+		ok, err = s.ForceRequeueSearchJobTask(adminCtx, ids[1])
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = s.ForceRequeueSearchJobTask(adminCtx, ids[0])
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		task, err := s.GetSearchJobTask(adminCtx, ids[0])
+		require.NoError(t, err)
+		require.Equal(t, types.JobStateQueued, task.State)
+		require.Empty(t, task.FailureMessage)
+		require.True(t, task.FinishedAt.IsZero())
+		require.Equal(t, int64(1), task.NumResets)
+
+		// The initiator is notified again once the job finished.
+		notified, _, err := basestore.ScanFirstBool(bs.Query(context.Background(), sqlf.Sprintf("SELECT notified_at IS NOT NULL FROM exhaustive_search_jobs WHERE id = %s", searchJobID)))
+		require.NoError(t, err)
+		require.False(t, notified)
+	})
+
+	t.Run("canceled tasks are not requeued", func(t *testing.T) {
+		err := bs.Exec(context.Background(), sqlf.Sprintf("UPDATE exhaustive_search_repo_revision_jobs SET state = 'failed', cancel = true WHERE id = %s", ids[2]))
+		require.NoError(t, err)
+
+		ok, err := s.ForceRequeueSearchJobTask(adminCtx, ids[2])
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestRevSearchJobWorkerStore_Dequeue(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	_, err := createRepo(db, "repo1")
+	require.NoError(t, err)
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+	workerStore := store.NewRevSearchJobWorkerStore(observation.TestContextTB(t), db.Handle(), store.DefaultStalledMaxAge)
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+
+	queued := func(n int) []types.JobState {
+		states := make([]types.JobState, n)
+		for i := range states {
+			states[i] = types.JobStateQueued
+		}
+		return states
+	}
+
+	// dequeue dequeues n repo revision jobs and returns the ID of the search job
+	// of each. If complete is true, every job is marked completed before the
+	// next one is dequeued.
+	dequeue := func(t *testing.T, n int, complete bool) []int64 {
+		t.Helper()
+
+		var searchJobIDs []int64
+		for range n {
+			job, ok, err := workerStore.Dequeue(ctx, "worker", nil)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			searchJobID, err := basestore.ScanAny[int64](s.QueryRow(ctx, sqlf.Sprintf(
+				"SELECT search_job_id FROM exhaustive_search_repo_jobs WHERE id = %s",
+				job.SearchRepoJobID,
+			)))
+			require.NoError(t, err)
+			searchJobIDs = append(searchJobIDs, searchJobID)
+
+			if complete {
+				_, err = workerStore.MarkComplete(ctx, job.RecordID(), dbworkerstore.MarkFinalOptions{})
+				require.NoError(t, err)
+			}
+		}
+		return searchJobIDs
+	}
+
+	t.Run("priority", func(t *testing.T) {
+		large := createJobCascade(t, ctx, s, stateCascade{
+			searchJob:   types.JobStateCompleted,
+			repoJobs:    []types.JobState{types.JobStateCompleted},
+			repoRevJobs: queued(5),
+			priority:    types.JobPriorityLow,
+		})
+		small := createJobCascade(t, ctx, s, stateCascade{
+			searchJob:   types.JobStateCompleted,
+			repoJobs:    []types.JobState{types.JobStateCompleted},
+			repoRevJobs: queued(2),
+		})
+
+		// The small job was created last, but all its tasks complete before
+		// the large job's tasks.
+		got := dequeue(t, 7, true)
+		require.Equal(t, []int64{small, small, large, large, large, large, large}, got)
+	})
+
+	t.Run("fairness", func(t *testing.T) {
+		first := createJobCascade(t, ctx, s, stateCascade{
+			searchJob:   types.JobStateCompleted,
+			repoJobs:    []types.JobState{types.JobStateCompleted},
+			repoRevJobs: queued(3),
+		})
+		second := createJobCascade(t, ctx, s, stateCascade{
+			searchJob:   types.JobStateCompleted,
+			repoJobs:    []types.JobState{types.JobStateCompleted},
+			repoRevJobs: queued(2),
+		})
+
+		// Jobs with the same priority take turns while their tasks are in
+		// progress.
+		got := dequeue(t, 5, false)
+		require.Equal(t, []int64{first, second, first, second, first}, got)
+	})
+}
+
+func TestStore_RepoRevisionBatches(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	adminID, err := createUser(bs, "admin")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+	workerStore := store.NewRevSearchJobWorkerStore(observation.TestContextTB(t), db.Handle(), store.DefaultStalledMaxAge)
+	ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+	workerCtx := actor.WithInternalActor(context.Background())
+
+	searchJobID, err := s.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{InitiatorID: userID, Query: "repo:batches"})
+	require.NoError(t, err)
+
+	// createRepoJob creates a repository of size bytes on codeHost, and a
+	// repo job for it.
+	createRepoJob := func(t *testing.T, name, codeHost string, size int64) (api.RepoID, int64) {
+		t.Helper()
+		repoID, err := createRepo(db, name)
+		require.NoError(t, err)
+		require.NoError(t, bs.Exec(context.Background(), sqlf.Sprintf("UPDATE repo SET external_service_id = %s WHERE id = %s", codeHost, repoID)))
+		require.NoError(t, bs.Exec(context.Background(), sqlf.Sprintf(`
+INSERT INTO gitserver_repos (repo_id, shard_id, repo_size_bytes) VALUES (%s, '', %s)
+ON CONFLICT (repo_id) DO UPDATE SET repo_size_bytes = EXCLUDED.repo_size_bytes`, repoID, size)))
+		repoJobID, err := s.CreateExhaustiveSearchRepoJob(workerCtx, types.ExhaustiveSearchRepoJob{SearchJobID: searchJobID, RepoID: repoID, RefSpec: "main"})
+		require.NoError(t, err)
+		return repoID, repoJobID
+	}
+
+	opts := store.TaskBatchOptions{Size: 3, MaxRepoSizeBytes: 1000, FillTimeout: time.Hour}
+	create := func(t *testing.T, repoJobID int64, revision string) (int64, bool) {
+		t.Helper()
+		id, batched, err := s.CreateBatchedRepoRevisionJob(workerCtx, types.ExhaustiveSearchRepoRevisionJob{SearchRepoJobID: repoJobID, Revision: revision}, opts)
+		require.NoError(t, err)
+		return id, batched
+	}
+	batchID := func(t *testing.T, id int64) int64 {
+		t.Helper()
+		var batchID int64
+		err := bs.QueryRow(context.Background(), sqlf.Sprintf("SELECT COALESCE(batch_id, 0) FROM exhaustive_search_repo_revision_jobs WHERE id = %s", id)).Scan(&batchID)
+		require.NoError(t, err)
+		return batchID
+	}
+	dequeue := func(t *testing.T) (*types.ExhaustiveSearchRepoRevisionJob, bool) {
+		t.Helper()
+		job, ok, err := workerStore.Dequeue(ctx, "worker", []*sqlf.Query{store.RevSearchJobDequeueCondition()})
+		require.NoError(t, err)
+		return job, ok
+	}
+
+	_, repoJob1 := createRepoJob(t, "repo1", "github", 100)
+	_, repoJob2 := createRepoJob(t, "repo2", "github", 100)
+	repo3, repoJob3 := createRepoJob(t, "repo3", "github", 100)
+	_, largeRepoJob := createRepoJob(t, "large", "github", 1_000_000)
+	_, otherRepoJob := createRepoJob(t, "other", "gitlab", 100)
+
+	first, batched := create(t, repoJob1, "main")
+	require.True(t, batched)
+	require.Equal(t, first, batchID(t, first), "the first task starts a batch")
+
+	large, batched := create(t, largeRepoJob, "main")
+	require.False(t, batched, "large repositories aren't batched")
+	require.Zero(t, batchID(t, large))
+
+	other, _ := create(t, otherRepoJob, "main")
+	require.Equal(t, other, batchID(t, other), "other code hosts start their own batch")
+
+	dev, _ := create(t, repoJob1, "dev")
+	require.Equal(t, dev, batchID(t, dev), "a batch has one revision of a repository")
+
+	second, _ := create(t, repoJob2, "main")
+	require.Equal(t, first, batchID(t, second))
+
+	// Batches wait to fill up.
+	job, ok := dequeue(t)
+	require.True(t, ok)
+	require.Equal(t, large, job.ID)
+	_, ok = dequeue(t)
+	require.False(t, ok)
+
+	// The batch is searched once it's full. Its other tasks aren't dequeued
+	// while it's processing.
+	third, _ := create(t, repoJob3, "main")
+	require.Equal(t, first, batchID(t, third))
+	leader, ok := dequeue(t)
+	require.True(t, ok)
+	require.Equal(t, first, leader.ID)
+	require.Equal(t, first, leader.BatchID)
+	_, ok = dequeue(t)
+	require.False(t, ok)
+
+	// A full batch takes no more tasks.
+	_, repoJob4 := createRepoJob(t, "repo4", "github", 100)
+	fourth, _ := create(t, repoJob4, "main")
+	require.Equal(t, dev, batchID(t, fourth))
+
+	members, err := s.ListRepoRevisionBatchMembers(workerCtx, leader)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	require.Equal(t, second, members[0].ID)
+	require.Equal(t, third, members[1].ID)
+	require.Equal(t, repo3, members[1].RepoRev.Repository)
+	require.Equal(t, "main", members[1].RepoRev.Revision)
+	require.Equal(t, types.RevisionSpecifiers("main"), members[1].RepoRev.RevisionSpecifiers)
+
+	// The outcomes of the other tasks are recorded together. The task which
+	// is retried is the next one dequeued once the batch finished.
+	startedAt := time.Now().Add(-time.Minute)
+	err = s.FinishRepoRevisionBatchMembers(workerCtx, leader, startedAt, time.Now().Add(-time.Second), []store.BatchMemberOutcome{
+		{ID: second, State: types.JobStateCompleted},
+		{ID: third, State: types.JobStateQueued, FailureMessage: "timeout"},
+	})
+	require.NoError(t, err)
+
+	tasks, err := s.ListSearchJobTasks(adminCtx, searchJobID, store.ListSearchJobTasksOpts{})
+	require.NoError(t, err)
+	byID := make(map[int64]types.SearchJobTask)
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	require.Equal(t, types.JobStateCompleted, byID[second].State)
+	require.Equal(t, "worker", byID[second].WorkerHostname)
+	require.Zero(t, byID[second].NumFailures)
+	require.Equal(t, types.JobStateQueued, byID[third].State)
+	require.Equal(t, "timeout", byID[third].FailureMessage)
+	require.Equal(t, int64(1), byID[third].NumFailures)
+
+	_, err = workerStore.MarkComplete(ctx, leader.RecordID(), dbworkerstore.MarkFinalOptions{})
+	require.NoError(t, err)
+	job, ok = dequeue(t)
+	require.True(t, ok)
+	require.Equal(t, third, job.ID)
+	require.Equal(t, first, job.BatchID)
+
+	// Only the workers batch tasks.
+	_, _, err = s.CreateBatchedRepoRevisionJob(ctx, types.ExhaustiveSearchRepoRevisionJob{SearchRepoJobID: repoJob1, Revision: "main"}, opts)
+	require.ErrorIs(t, err, store.ErrNotInternalActor)
+	_, err = s.ListRepoRevisionBatchMembers(ctx, leader)
+	require.ErrorIs(t, err, store.ErrNotInternalActor)
+	err = s.FinishRepoRevisionBatchMembers(ctx, leader, startedAt, startedAt, nil)
+	require.ErrorIs(t, err, store.ErrNotInternalActor)
+}
+
+func TestTruncateFailureMessage(t *testing.T) {
+	require.Equal(t, "short", store.TruncateFailureMessage("short"))
+
+	exact := strings.Repeat("a", store.MaxFailureMessageLength)
+	require.Equal(t, exact, store.TruncateFailureMessage(exact))
+
+	// "€" is 3 bytes long, so the message is not cut at a character boundary.
+	long := strings.Repeat("€", store.MaxFailureMessageLength)
+	got := store.TruncateFailureMessage(long)
+	require.LessOrEqual(t, len(got), store.MaxFailureMessageLength)
+	require.True(t, utf8.ValidString(got))
+	require.True(t, strings.HasSuffix(got, "(truncated)"))
 }
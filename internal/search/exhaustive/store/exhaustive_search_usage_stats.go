@@ -0,0 +1,146 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+// GetDailyUsage returns how many jobs were created in [after, before) and how
+// they ended, by UTC day, oldest first. Every day the window touches has an
+// entry, even if no job was created on it, so the first and the last day may
+// only be covered in part. Only site admins may see it.
+func (s *Store) GetDailyUsage(ctx context.Context, after, before time.Time) (usage []types.DailyUsage, err error) {
+	ctx, _, endObservation := s.operations.getDailyUsage.With(ctx, &err, opAttrs(
+		attribute.String("after", after.String()),
+		attribute.String("before", before.String()),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: the usage includes the jobs of all users.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db); err != nil {
+		return nil, err
+	}
+
+	var days []types.DailyUsage
+	err = s.read(ctx, func(db *basestore.Store) (err error) {
+		days, err = scanDailyUsage(db.Query(ctx, sqlf.Sprintf(
+			getDailyUsageFmtStr,
+			sqlf.Sprintf(aggStateSubQuery, sqlf.Sprintf(getAggregateStateTable, sqlf.Sprintf("j.id"), sqlf.Sprintf("j.id"), sqlf.Sprintf("j.id"))),
+			after,
+			before,
+		)))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in the days without jobs.
+	byDay := make(map[int64]types.DailyUsage, len(days))
+	for _, d := range days {
+		byDay[d.Day.Unix()] = d
+	}
+	const day = 24 * time.Hour
+	for d := after.UTC().Truncate(day); d.Before(before); d = d.Add(day) {
+		u, ok := byDay[d.Unix()]
+		if !ok {
+			u = types.DailyUsage{Day: d}
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+var scanDailyUsage = basestore.NewSliceScanner(func(sc dbutil.Scanner) (u types.DailyUsage, err error) {
+	var avgDurationSeconds float64
+	err = sc.Scan(&u.Day, &u.Jobs, &u.Completed, &u.Failed, &avgDurationSeconds, &u.BytesWritten)
+	u.Day = u.Day.UTC()
+	u.AverageDuration = time.Duration(avgDurationSeconds * float64(time.Second)).Round(time.Millisecond)
+	return u, err
+})
+
+// getDailyUsageFmtStr finds the jobs of the window via the index on
+// created_at, and their tasks via the indexes on the search job ids. A job
+// ends when its last record finishes.
+const getDailyUsageFmtStr = `
+WITH jobs AS (
+    SELECT
+        date_trunc('day', j.created_at AT TIME ZONE 'UTC') AS day,
+        j.created_at,
+        j.bytes_written,
+        (%s) AS agg_state,
+        GREATEST(
+            j.finished_at,
+            (SELECT MAX(rj.finished_at) FROM exhaustive_search_repo_jobs rj WHERE rj.search_job_id = j.id),
+            (
+                SELECT MAX(rrj.finished_at)
+                FROM exhaustive_search_repo_revision_jobs rrj
+                JOIN exhaustive_search_repo_jobs rj ON rrj.search_repo_job_id = rj.id
+                WHERE rj.search_job_id = j.id
+            )
+        ) AS ended_at
+    FROM exhaustive_search_jobs j
+    WHERE j.created_at >= %s AND j.created_at < %s
+)
+SELECT
+    day,
+    COUNT(*),
+    COUNT(*) FILTER (WHERE agg_state = 'completed'),
+    COUNT(*) FILTER (WHERE agg_state = 'failed'),
+    COALESCE(EXTRACT(EPOCH FROM AVG(ended_at - created_at) FILTER (WHERE agg_state IN ('completed', 'failed'))), 0),
+    SUM(bytes_written)
+FROM jobs
+GROUP BY day
+ORDER BY day
+`
+
+// GetTopUsersByTaskVolume returns the at most limit users whose jobs created
+// in [after, before) have the most tasks, most first. Only site admins may see
+// it.
+func (s *Store) GetTopUsersByTaskVolume(ctx context.Context, after, before time.Time, limit int) (volumes []types.UserTaskVolume, err error) {
+	ctx, _, endObservation := s.operations.getTopUsersByTaskVolume.With(ctx, &err, opAttrs(
+		attribute.String("after", after.String()),
+		attribute.String("before", before.String()),
+		attribute.Int("limit", limit),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: the volume includes the jobs of all users.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, s.db); err != nil {
+		return nil, err
+	}
+
+	err = s.read(ctx, func(db *basestore.Store) (err error) {
+		volumes, err = scanUserTaskVolumes(db.Query(ctx, sqlf.Sprintf(getTopUsersByTaskVolumeFmtStr, after, before, limit)))
+		return err
+	})
+	return volumes, err
+}
+
+var scanUserTaskVolumes = basestore.NewSliceScanner(func(sc dbutil.Scanner) (v types.UserTaskVolume, err error) {
+	err = sc.Scan(&v.UserID, &v.Username, &v.Jobs, &v.Tasks)
+	return v, err
+})
+
+// getTopUsersByTaskVolumeFmtStr counts the jobs without tasks too, e.g. the
+// ones which are still being expanded.
+const getTopUsersByTaskVolumeFmtStr = `
+SELECT j.initiator_id, u.username, COUNT(DISTINCT j.id), COUNT(rrj.id)
+FROM exhaustive_search_jobs j
+JOIN users u ON u.id = j.initiator_id
+LEFT JOIN exhaustive_search_repo_jobs rj ON rj.search_job_id = j.id
+LEFT JOIN exhaustive_search_repo_revision_jobs rrj ON rrj.search_repo_job_id = rj.id
+WHERE j.created_at >= %s AND j.created_at < %s
+GROUP BY j.initiator_id, u.username
+ORDER BY COUNT(rrj.id) DESC, j.initiator_id
+LIMIT %s
+`
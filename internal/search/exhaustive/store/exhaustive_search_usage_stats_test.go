@@ -0,0 +1,134 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+func TestStore_UsageStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	_, err := createRepo(db, "repo1")
+	require.NoError(t, err)
+	aliceID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+	bobID, err := createUser(bs, "bob")
+	require.NoError(t, err)
+	adminID, err := createUser(bs, "admin")
+	require.NoError(t, err)
+
+	s := store.New(db, observation.TestContextTB(t))
+	aliceCtx := actor.WithActor(context.Background(), actor.FromUser(aliceID))
+	bobCtx := actor.WithActor(context.Background(), actor.FromUser(bobID))
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(adminID))
+
+	const day = 24 * time.Hour
+	day0 := time.Now().UTC().Truncate(day).Add(-3 * day)
+
+	// createJob creates a job at createdAt whose tasks finish after took.
+	createJob := func(ctx context.Context, c stateCascade, createdAt time.Time, took time.Duration, bytesWritten int64) {
+		t.Helper()
+		jobID := createJobCascade(t, ctx, s, c)
+		err := bs.Exec(context.Background(), sqlf.Sprintf(
+			"UPDATE exhaustive_search_jobs SET created_at = %s, finished_at = %s, bytes_written = %s WHERE id = %s",
+			createdAt, createdAt.Add(time.Second), bytesWritten, jobID,
+		))
+		require.NoError(t, err)
+		err = bs.Exec(context.Background(), sqlf.Sprintf(
+			"UPDATE exhaustive_search_repo_revision_jobs SET finished_at = %s WHERE search_repo_job_id IN (SELECT id FROM exhaustive_search_repo_jobs WHERE search_job_id = %s)",
+			createdAt.Add(took), jobID,
+		))
+		require.NoError(t, err)
+	}
+
+	createJob(aliceCtx, stateCascade{
+		searchJob:   types.JobStateCompleted,
+		repoJobs:    []types.JobState{types.JobStateCompleted},
+		repoRevJobs: []types.JobState{types.JobStateCompleted, types.JobStateCompleted},
+	}, day0.Add(time.Hour), 10*time.Minute, 100)
+	createJob(bobCtx, stateCascade{
+		searchJob:   types.JobStateCompleted,
+		repoJobs:    []types.JobState{types.JobStateCompleted},
+		repoRevJobs: []types.JobState{types.JobStateCompleted, types.JobStateFailed},
+	}, day0.Add(2*time.Hour), 30*time.Minute, 50)
+	// Nothing happened on the second day.
+	createJob(aliceCtx, stateCascade{
+		searchJob:   types.JobStateCompleted,
+		repoJobs:    []types.JobState{types.JobStateCompleted},
+		repoRevJobs: []types.JobState{types.JobStateCompleted, types.JobStateCompleted, types.JobStateCompleted},
+	}, day0.Add(2*day+time.Hour), 20*time.Minute, 10)
+	// A job which is still queued counts, but didn't end yet.
+	createJob(aliceCtx, stateCascade{
+		searchJob: types.JobStateQueued,
+	}, day0.Add(2*day+2*time.Hour), 0, 0)
+
+	t.Run("daily usage", func(t *testing.T) {
+		usage, err := s.GetDailyUsage(adminCtx, day0, day0.Add(3*day))
+		require.NoError(t, err)
+		require.Equal(t, []types.DailyUsage{
+			{Day: day0, Jobs: 2, Completed: 1, Failed: 1, AverageDuration: 20 * time.Minute, BytesWritten: 150},
+			{Day: day0.Add(day)},
+			{Day: day0.Add(2 * day), Jobs: 2, Completed: 1, AverageDuration: 20 * time.Minute, BytesWritten: 10},
+		}, usage)
+		require.Equal(t, 0.5, usage[0].FailureRate())
+		require.Equal(t, 0.0, usage[1].FailureRate())
+
+		// The window only covers part of the first and the last day.
+		usage, err = s.GetDailyUsage(adminCtx, day0.Add(90*time.Minute), day0.Add(2*day+90*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, []types.DailyUsage{
+			{Day: day0, Jobs: 1, Failed: 1, AverageDuration: 30 * time.Minute, BytesWritten: 50},
+			{Day: day0.Add(day)},
+			{Day: day0.Add(2 * day), Jobs: 1, Completed: 1, AverageDuration: 20 * time.Minute, BytesWritten: 10},
+		}, usage)
+
+		// No jobs were created in the window.
+		usage, err = s.GetDailyUsage(adminCtx, day0.Add(3*day), day0.Add(4*day))
+		require.NoError(t, err)
+		require.Equal(t, []types.DailyUsage{{Day: day0.Add(3 * day)}}, usage)
+
+		_, err = s.GetDailyUsage(aliceCtx, day0, day0.Add(3*day))
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+	})
+
+	t.Run("top users", func(t *testing.T) {
+		volumes, err := s.GetTopUsersByTaskVolume(adminCtx, day0, day0.Add(3*day), 10)
+		require.NoError(t, err)
+		require.Equal(t, []types.UserTaskVolume{
+			{UserID: aliceID, Username: "alice", Jobs: 3, Tasks: 5},
+			{UserID: bobID, Username: "bob", Jobs: 1, Tasks: 2},
+		}, volumes)
+
+		volumes, err = s.GetTopUsersByTaskVolume(adminCtx, day0, day0.Add(3*day), 1)
+		require.NoError(t, err)
+		require.Equal(t, []types.UserTaskVolume{{UserID: aliceID, Username: "alice", Jobs: 3, Tasks: 5}}, volumes)
+
+		// No jobs were created in the window.
+		volumes, err = s.GetTopUsersByTaskVolume(adminCtx, day0.Add(3*day), day0.Add(4*day), 10)
+		require.NoError(t, err)
+		require.Empty(t, volumes)
+
+		_, err = s.GetTopUsersByTaskVolume(aliceCtx, day0, day0.Add(3*day), 10)
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+	})
+}
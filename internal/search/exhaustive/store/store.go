@@ -2,14 +2,19 @@ package store
 
 import (
 	"context"
-	"fmt"
+	"time"
 
+	"github.com/derision-test/glock"
 	"github.com/sourcegraph/log"
 	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
-	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore/observed"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -17,6 +22,21 @@ import (
 // ErrNoResults is returned by Store method calls that found no results.
 var ErrNoResults = errors.New("no results")
 
+// ErrNotInternalActor is returned by the Store methods reserved for the
+// workers if they are called without an internal actor.
+var ErrNotInternalActor = errors.New("only the search job workers may do this")
+
+// checkInternalActor returns ErrNotInternalActor unless the actor of ctx is
+// internal. The workers run as internal actors, users never do, so a user
+// context that leaks into a worker code path fails loudly instead of acting
+// on the rows the user may access.
+func checkInternalActor(ctx context.Context) error {
+	if !actor.FromContext(ctx).IsInternal() {
+		return ErrNotInternalActor
+	}
+	return nil
+}
+
 // Store exposes methods to read and write to the DB for exhaustive searches.
 type Store struct {
 	logger log.Logger
@@ -24,22 +44,94 @@ type Store struct {
 	*basestore.Store
 	operations     *operations
 	observationCtx *observation.Context
+
+	// key encrypts the webhooks of search jobs.
+	key encryption.Key
+
+	// clock is consulted for the timestamps the store writes, rather than
+	// the clock of the database, so that tests can control them.
+	clock glock.Clock
+
+	// replica serves the reads of users which tolerate replication lag, see
+	// read. It is nil if there is no replica, and in transactions.
+	replica *basestore.Store
+}
+
+// SlowQueryThreshold is how long a query of the store may take before it is
+// logged with its SQL. It applies to the stores created afterwards.
+var SlowQueryThreshold = env.MustGetDuration("SEARCH_JOBS_SLOW_QUERY_THRESHOLD", 2*time.Second, "Queries of the search jobs store which take longer than this are logged. 0 disables the logging.")
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithReplica makes the store send the heavy reads of users, like listing jobs
+// and their progress, to replica, a read replica of the database. The workers
+// and all writes use the primary.
+func WithReplica(replica database.DB) Option {
+	return func(s *Store) {
+		s.replica = basestore.NewWithHandle(observed.LogSlowQueries(replica.Handle(), s.logger, SlowQueryThreshold))
+	}
 }
 
 // New returns a new Store backed by the given database.
-func New(db database.DB, observationCtx *observation.Context) *Store {
-	return &Store{
+func New(db database.DB, observationCtx *observation.Context, opts ...Option) *Store {
+	return NewWithClock(db, observationCtx, glock.NewRealClock(), opts...)
+}
+
+// NewWithClock is like New, but the store reads the time from clock. Tests
+// pass a glock.MockClock to advance the time instead of sleeping.
+func NewWithClock(db database.DB, observationCtx *observation.Context, clock glock.Clock, opts ...Option) *Store {
+	s := &Store{
 		logger:         observationCtx.Logger,
 		db:             db,
-		Store:          basestore.NewWithHandle(db.Handle()),
+		Store:          basestore.NewWithHandle(observed.LogSlowQueries(db.Handle(), observationCtx.Logger, SlowQueryThreshold)),
 		operations:     newOperations(observationCtx),
 		observationCtx: observationCtx,
+		key:            keyring.Default().OutboundWebhookKey,
+		clock:          clock,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type pinPrimaryKey struct{}
+
+// PinPrimary returns a context in which the store reads from the primary even
+// if it has a replica. Callers pin the context of the reads which must see
+// their own writes, since the replica may lag behind.
+func PinPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pinPrimaryKey{}, true)
+}
+
+// read calls f with the replica if the store has one, unless ctx is pinned to
+// the primary or belongs to an internal actor, since the workers act on what
+// they read. f is called again with the primary if it fails on the replica,
+// so it must not keep state between calls.
+func (s *Store) read(ctx context.Context, f func(*basestore.Store) error) error {
+	if s.replica == nil || ctx.Value(pinPrimaryKey{}) != nil || actor.FromContext(ctx).IsInternal() {
+		return f(s.Store)
 	}
+
+	err := f(s.replica)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	// A missing row may only be missing on the replica yet, so we retry those
+	// too.
+	s.logger.Warn("search jobs replica query failed, retrying on the primary", log.Error(err))
+	return f(s.Store)
+}
+
+// Clock returns the clock the store reads the time from.
+func (s *Store) Clock() glock.Clock {
+	return s.clock
 }
 
 // Transact creates a new transaction.
 // It's required to implement this method and wrap the Transact method of the
-// underlying basestore.Store.
+// underlying basestore.Store. Transactions only use the primary.
 func (s *Store) Transact(ctx context.Context) (*Store, error) {
 	txBase, err := s.Store.Transact(ctx)
 	if err != nil {
@@ -51,6 +143,8 @@ func (s *Store) Transact(ctx context.Context) (*Store, error) {
 		Store:          txBase,
 		operations:     s.operations,
 		observationCtx: s.observationCtx,
+		key:            s.key,
+		clock:          s.clock,
 	}, nil
 }
 
@@ -61,46 +155,129 @@ func opAttrs(attrs ...attribute.KeyValue) observation.Args {
 type operations struct {
 	createExhaustiveSearchJob *observation.Operation
 	cancelSearchJob           *observation.Operation
+	resumeSearchJob           *observation.Operation
+	retryFailedSearchJobTasks *observation.Operation
+	addResultCount            *observation.Operation
+	addBytesWritten           *observation.Operation
+	addSampledTasks           *observation.Operation
+	skipTasksAfterDeadline    *observation.Operation
+	markSearchJobExpanded     *observation.Operation
+	repairStalledSearchJobs   *observation.Operation
 	getExhaustiveSearchJob    *observation.Operation
 	userHasAccess             *observation.Operation
+	listInaccessibleRepos     *observation.Operation
 	listExhaustiveSearchJobs  *observation.Operation
+	countExhaustiveSearchJobs *observation.Operation
+	countActiveSearchJobs     *observation.Operation
+	getSearchJobProgress      *observation.Operation
+	getSearchJobsBacklog      *observation.Operation
+	getExportVolumeByUser     *observation.Operation
+	getWorkerTaskStats        *observation.Operation
+	getDailyUsage             *observation.Operation
+	getTopUsersByTaskVolume   *observation.Operation
 	deleteExhaustiveSearchJob *observation.Operation
+	lockExhaustiveSearchJob   *observation.Operation
+
+	listExpiredExhaustiveSearchJobIDs *observation.Operation
+	updateExhaustiveSearchJobMetadata *observation.Operation
+
+	getSearchJobByIdempotencyKey  *observation.Operation
+	setIdempotencyKey             *observation.Operation
+	releaseExpiredIdempotencyKeys *observation.Operation
+
+	getJobLogs  *observation.Operation
+	scanJobLogs *observation.Operation
+
+	setExhaustiveSearchJobWebhook *observation.Operation
+	getExhaustiveSearchJobWebhook *observation.Operation
+	enqueueSearchJobNotifications *observation.Operation
+
+	appendSearchJobLogLine  *observation.Operation
+	appendSearchJobLogLines *observation.Operation
+	listSearchJobLogLines   *observation.Operation
 
 	createExhaustiveSearchRepoJob         *observation.Operation
+	createExhaustiveSearchRepoJobs        *observation.Operation
 	createExhaustiveSearchRepoRevisionJob *observation.Operation
+	createBatchedRepoRevisionJob          *observation.Operation
+	listRepoRevisionBatchMembers          *observation.Operation
+	finishRepoRevisionBatchMembers        *observation.Operation
+	hasRepoRevisionJobs                   *observation.Operation
+	getQueryRepoRev                       *observation.Operation
+	requeueRepoRevisionJob                *observation.Operation
+	postponeRepoRevisionJob               *observation.Operation
+	setRepoRevisionJobWorkerStartedAt     *observation.Operation
 	getAggregateRepoRevState              *observation.Operation
-}
 
-var m = new(metrics.SingletonREDMetrics)
+	listSearchJobTasks        *observation.Operation
+	getSearchJobTask          *observation.Operation
+	forceFailSearchJobTask    *observation.Operation
+	forceRequeueSearchJobTask *observation.Operation
+}
 
 func newOperations(observationCtx *observation.Context) *operations {
-	redMetrics := m.Get(func() *metrics.REDMetrics {
-		return metrics.NewREDMetrics(
-			observationCtx.Registerer,
-			"searchjobs_store",
-			metrics.WithLabels("op"),
-			metrics.WithCountHelp("Total number of method invocations."),
-		)
-	})
-
-	op := func(name string) *observation.Operation {
-		return observationCtx.Operation(observation.Op{
-			Name:              fmt.Sprintf("searchjobs.store.%s", name),
-			MetricLabelValues: []string{name},
-			Metrics:           redMetrics,
-		})
-	}
+	op := observed.NewOperations(observationCtx, "searchjobs_store").Operation
 
 	return &operations{
 		createExhaustiveSearchJob: op("CreateExhaustiveSearchJob"),
 		cancelSearchJob:           op("CancelSearchJob"),
+		resumeSearchJob:           op("ResumeSearchJob"),
+		retryFailedSearchJobTasks: op("RetryFailedSearchJobTasks"),
+		addResultCount:            op("AddResultCount"),
+		addBytesWritten:           op("AddBytesWritten"),
+		addSampledTasks:           op("AddSampledTasks"),
+		skipTasksAfterDeadline:    op("SkipTasksAfterDeadline"),
+		markSearchJobExpanded:     op("MarkSearchJobExpanded"),
+		repairStalledSearchJobs:   op("RepairStalledSearchJobs"),
 		getExhaustiveSearchJob:    op("GetExhaustiveSearchJob"),
 		userHasAccess:             op("UserHasAccess"),
+		listInaccessibleRepos:     op("ListInaccessibleRepos"),
 		listExhaustiveSearchJobs:  op("ListExhaustiveSearchJobs"),
+		countExhaustiveSearchJobs: op("CountExhaustiveSearchJobs"),
+		countActiveSearchJobs:     op("CountActiveSearchJobs"),
+		getSearchJobProgress:      op("GetSearchJobProgress"),
+		getSearchJobsBacklog:      op("GetSearchJobsBacklog"),
+		getExportVolumeByUser:     op("GetExportVolumeByUser"),
+		getWorkerTaskStats:        op("GetWorkerTaskStats"),
+		getDailyUsage:             op("GetDailyUsage"),
+		getTopUsersByTaskVolume:   op("GetTopUsersByTaskVolume"),
 		deleteExhaustiveSearchJob: op("DeleteExhaustiveSearchJob"),
+		lockExhaustiveSearchJob:   op("LockExhaustiveSearchJob"),
+
+		listExpiredExhaustiveSearchJobIDs: op("ListExpiredExhaustiveSearchJobIDs"),
+		updateExhaustiveSearchJobMetadata: op("UpdateExhaustiveSearchJobMetadata"),
+
+		getSearchJobByIdempotencyKey:  op("GetSearchJobByIdempotencyKey"),
+		setIdempotencyKey:             op("SetIdempotencyKey"),
+		releaseExpiredIdempotencyKeys: op("ReleaseExpiredIdempotencyKeys"),
+
+		getJobLogs:  op("GetJobLogs"),
+		scanJobLogs: op("ScanJobLogs"),
+
+		setExhaustiveSearchJobWebhook: op("SetExhaustiveSearchJobWebhook"),
+		getExhaustiveSearchJobWebhook: op("GetExhaustiveSearchJobWebhook"),
+		enqueueSearchJobNotifications: op("EnqueueSearchJobNotifications"),
+
+		appendSearchJobLogLine:  op("AppendSearchJobLogLine"),
+		appendSearchJobLogLines: op("AppendSearchJobLogLines"),
+		listSearchJobLogLines:   op("ListSearchJobLogLines"),
 
 		createExhaustiveSearchRepoJob:         op("CreateExhaustiveSearchRepoJob"),
+		createExhaustiveSearchRepoJobs:        op("CreateExhaustiveSearchRepoJobs"),
 		createExhaustiveSearchRepoRevisionJob: op("CreateExhaustiveSearchRepoRevisionJob"),
+		createBatchedRepoRevisionJob:          op("CreateBatchedRepoRevisionJob"),
+		listRepoRevisionBatchMembers:          op("ListRepoRevisionBatchMembers"),
+		finishRepoRevisionBatchMembers:        op("FinishRepoRevisionBatchMembers"),
+		hasRepoRevisionJobs:                   op("HasRepoRevisionJobs"),
+		getQueryRepoRev:                       op("GetQueryRepoRev"),
+		requeueRepoRevisionJob:                op("RequeueRepoRevisionJob"),
+		postponeRepoRevisionJob:               op("PostponeRepoRevisionJob"),
+		setRepoRevisionJobWorkerStartedAt:     op("SetRepoRevisionJobWorkerStartedAt"),
 		getAggregateRepoRevState:              op("GetAggregateRepoRevState"),
+
+		listSearchJobTasks:        op("ListSearchJobTasks"),
+		getSearchJobTask:          op("GetSearchJobTask"),
+		forceFailSearchJobTask:    op("ForceFailSearchJobTask"),
+		forceRequeueSearchJobTask: op("ForceRequeueSearchJobTask"),
 	}
 }
@@ -2,15 +2,37 @@ package store_test
 
 import (
 	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
 
+	"github.com/grafana/regexp"
 	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	exhaustivetypes "github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
+var updateRegex = flag.String("update", "", "Update testdata of tests matching the given regex")
+
+func update(name string) bool {
+	if updateRegex == nil || *updateRegex == "" {
+		return false
+	}
+	return regexp.MustCompile(*updateRegex).MatchString(name)
+}
+
 func createUser(store *basestore.Store, username string) (int32, error) {
 	admin := username == "admin"
 	q := sqlf.Sprintf(`INSERT INTO users(username, site_admin) VALUES(%s, %s) RETURNING id`, username, admin)
@@ -23,3 +45,93 @@ func createRepo(db database.DB, name string) (api.RepoID, error) {
 	err := repoStore.Create(context.Background(), &repo)
 	return repo.ID, err
 }
+
+// TestNoDirectTimeNow checks that the store, and the service built on it, read
+// the time from the clock of the store rather than from the time package or the
+// database, so that the tests which replace the clock control every timestamp
+// the store writes.
+func TestNoDirectTimeNow(t *testing.T) {
+	directTimeNow := regexp.MustCompile(`\btime\.(Now|Since|Until)\(|\bNOW\(\)`)
+
+	for _, pattern := range []string{"*.go", "../service/*.go"} {
+		files, err := filepath.Glob(pattern)
+		require.NoError(t, err)
+		require.NotEmpty(t, files, pattern)
+
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			b, err := os.ReadFile(file)
+			require.NoError(t, err)
+			for i, line := range strings.Split(string(b), "\n") {
+				if directTimeNow.MatchString(line) {
+					t.Errorf("%s:%d reads the current time directly, use the clock of the store instead: %s", file, i+1, strings.TrimSpace(line))
+				}
+			}
+		}
+	}
+}
+
+func TestStore_Replica(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	observationCtx := observation.TestContextTB(t)
+
+	// The primary and the replica are distinct databases, each with a job of
+	// alice with ID 1 but a different query, so we can tell which one the
+	// store read from.
+	newDB := func(query string) (database.DB, int32) {
+		db := database.NewDB(logger, dbtest.NewDB(t))
+		userID, err := createUser(basestore.NewWithHandle(db.Handle()), "alice")
+		require.NoError(t, err)
+		_, err = store.New(db, observationCtx).CreateExhaustiveSearchJob(
+			actor.WithActor(context.Background(), actor.FromUser(userID)),
+			exhaustivetypes.ExhaustiveSearchJob{InitiatorID: userID, Query: query},
+		)
+		require.NoError(t, err)
+		return db, userID
+	}
+	primary, aliceID := newDB("repo:primary")
+	replica, _ := newDB("repo:replica")
+
+	s := store.New(primary, observationCtx, store.WithReplica(replica))
+	aliceCtx := actor.WithActor(context.Background(), actor.FromUser(aliceID))
+
+	requireQuery := func(ctx context.Context, s *store.Store, id int64, want string) {
+		t.Helper()
+		job, err := s.GetExhaustiveSearchJob(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, want, job.Query)
+	}
+
+	// Reads of users go to the replica.
+	requireQuery(aliceCtx, s, 1, "repo:replica")
+	count, err := s.CountExhaustiveSearchJobs(aliceCtx, store.ListArgs{})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	// Pinned contexts and internal actors read from the primary.
+	requireQuery(store.PinPrimary(aliceCtx), s, 1, "repo:primary")
+	requireQuery(actor.WithInternalActor(context.Background()), s, 1, "repo:primary")
+
+	// Writes go to the primary. A job which isn't on the replica yet is read
+	// from the primary instead.
+	id, err := s.CreateExhaustiveSearchJob(aliceCtx, exhaustivetypes.ExhaustiveSearchJob{InitiatorID: aliceID, Query: "repo:new"})
+	require.NoError(t, err)
+	requireQuery(aliceCtx, s, id, "repo:new")
+
+	// If the replica fails, the store falls back to the primary.
+	_, err = replica.ExecContext(context.Background(), "DROP TABLE exhaustive_search_jobs CASCADE")
+	require.NoError(t, err)
+	requireQuery(aliceCtx, s, 1, "repo:primary")
+	count, err = s.CountExhaustiveSearchJobs(aliceCtx, store.ListArgs{})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	// Without a replica, everything is read from the primary.
+	requireQuery(aliceCtx, store.New(primary, observationCtx), 1, "repo:primary")
+}
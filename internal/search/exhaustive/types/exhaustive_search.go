@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 )
@@ -63,6 +64,11 @@ type RepositoryRevision struct {
 	// Revision is a resolved revision specifier. eg HEAD, branch-name,
 	// commit-hash, etc.
 	Revision string
+
+	// CommitID is the commit Revision pointed to when the search job was
+	// expanded. It is empty if Revision could not be resolved, in which case
+	// Revision is searched.
+	CommitID api.CommitID
 }
 
 func (r RepositoryRevision) String() string {
@@ -75,3 +81,95 @@ type RepoRevJobStats struct {
 	Failed     int32
 	InProgress int32
 }
+
+// SearchJobProgress is how many tasks of a search job are left. The tasks of a
+// job are the job itself, its repo jobs and its repo revision jobs.
+type SearchJobProgress struct {
+	// Total is the number of tasks of the job. It grows while the workers
+	// expand the job into repositories and revisions.
+	Total int
+
+	// Remaining is the number of tasks which are queued, processing or
+	// errored. Errored tasks are retried.
+	Remaining int
+}
+
+// SearchJobsBacklog is the number of records of all search jobs the workers
+// still have to process, by worker tier. Like SearchJobProgress, it counts
+// queued, processing and errored records.
+type SearchJobsBacklog struct {
+	Jobs          int
+	RepoJobs      int
+	RevisionJobs  int
+	Notifications int
+}
+
+// Total returns the number of records of all tiers.
+func (b SearchJobsBacklog) Total() int {
+	return b.Jobs + b.RepoJobs + b.RevisionJobs + b.Notifications
+}
+
+// UserExportVolume is how much result data the search jobs of a user created
+// in a time window produced.
+type UserExportVolume struct {
+	UserID   int32
+	Username string
+
+	// Jobs is the number of search jobs the user created in the window.
+	Jobs int
+
+	// BytesWritten is the sum of ExhaustiveSearchJob.BytesWritten of Jobs.
+	BytesWritten int64
+}
+
+// UsageStats is how heavily search jobs were used in a time window, for the
+// site admin dashboard.
+type UsageStats struct {
+	// Days has an entry for each UTC day of the window, oldest first, including
+	// the days without jobs.
+	Days []DailyUsage
+
+	// TopUsers are the users whose jobs have the most tasks, most first.
+	TopUsers []UserTaskVolume
+}
+
+// DailyUsage is how many search jobs were created on a day, and how they
+// ended.
+type DailyUsage struct {
+	// Day is the start of the UTC day.
+	Day time.Time
+
+	// Jobs is the number of search jobs created on Day, out of which Completed
+	// completed and Failed failed. The others haven't finished yet or were
+	// canceled.
+	Jobs      int
+	Completed int
+	Failed    int
+
+	// AverageDuration is how long the completed and failed jobs of Jobs took on
+	// average, from their creation until their last task finished.
+	AverageDuration time.Duration
+
+	// BytesWritten is the sum of ExhaustiveSearchJob.BytesWritten of Jobs.
+	BytesWritten int64
+}
+
+// FailureRate returns the share of the finished jobs of the day which failed.
+func (u DailyUsage) FailureRate() float64 {
+	if u.Completed+u.Failed == 0 {
+		return 0
+	}
+	return float64(u.Failed) / float64(u.Completed+u.Failed)
+}
+
+// UserTaskVolume is how many tasks the search jobs of a user created in a time
+// window have.
+type UserTaskVolume struct {
+	UserID   int32
+	Username string
+
+	// Jobs is the number of search jobs the user created in the window, and
+	// Tasks the number of repo revision jobs they have.
+	Jobs  int
+	Tasks int
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,8 +17,86 @@ type ExhaustiveSearchJob struct {
 	// Currently, this is always the person who created the search.
 	InitiatorID int32
 
+	// InitiatorUsername is the username of InitiatorID. Like AggState, it is
+	// only set when the job is returned from ListSearchJobs or GetSearchJob.
+	// It is DeletedInitiatorUsername if the initiator was deleted.
+	InitiatorUsername string
+
+	// InitiatorEmail is the primary email address of InitiatorID, set like
+	// InitiatorUsername. It is empty if the initiator has no primary email
+	// address or was deleted.
+	InitiatorEmail string
+
+	// CreationSource is where the job was created. It is empty for jobs
+	// created before the source was recorded.
+	CreationSource JobCreationSource
+
 	Query string
 
+	// MaxResults is the number of results after which the job stops. 0 means
+	// there is no limit.
+	MaxResults int
+
+	// ResultCount is the number of results written by the job so far.
+	ResultCount int
+
+	// BytesWritten is the size of the results the job stored so far, as
+	// uploaded, that is compressed. Results which were discarded, for example
+	// of a search which failed, are not counted.
+	BytesWritten int64
+
+	// SampleRate is the probability with which the job searches each revision
+	// of each repository of its query. It is 0 if the job searches all of
+	// them. SampleSeed seeds the choice, so a job with the same query, rate
+	// and seed searches the same revisions, as long as the repositories and
+	// revisions that the query resolves to didn't change.
+	SampleRate float64
+	SampleSeed int64
+
+	// SampledTaskCount is the number of revisions the job searches, out of
+	// TotalTaskCount revisions that its query resolved to. Both are only
+	// counted for jobs with a SampleRate, and grow while the job starts.
+	SampledTaskCount int
+	TotalTaskCount   int
+
+	// DeduplicateResults makes the exports of the job report a match which was
+	// found in several revisions of a repository once, together with the
+	// revisions it was found in. See types.ResultsRevisionsColumn.
+	DeduplicateResults bool
+
+	// Description and Labels are free-form metadata the initiator attaches to
+	// the job, e.g. to tell apart jobs with similar queries. Jobs can be
+	// listed by their labels. Both can be changed after the job was created.
+	Description string
+	Labels      map[string]string
+
+	// Truncated is true if the job reached MaxResults and skipped the
+	// remaining repositories and revisions.
+	Truncated bool
+
+	// Deadline is the time after which the job stops. It is zero if the job
+	// has no deadline.
+	Deadline time.Time
+
+	// DeadlineExceeded is true if the job reached its Deadline and skipped the
+	// remaining repositories and revisions, so its results are partial.
+	DeadlineExceeded bool
+
+	// ExpandedAt is the time the job finished creating the tasks for the
+	// repositories of its query. It is zero while the job is starting, or if a
+	// worker crashed while creating the tasks, in which case the tasks are
+	// created again when the job is retried.
+	ExpandedAt time.Time
+
+	// Priority decides, together with CreatedAt, in which order the workers
+	// pick up the tasks of this job relative to the tasks of other jobs.
+	Priority JobPriority
+
+	// CreatedFromJobID is the ID of the job this job duplicates. It is 0 if
+	// the job was not created by duplicating another job. The original job
+	// may have been deleted since.
+	CreatedFromJobID int64
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
@@ -34,3 +113,65 @@ func (j *ExhaustiveSearchJob) RecordID() int {
 func (j *ExhaustiveSearchJob) RecordUID() string {
 	return strconv.FormatInt(j.ID, 10)
 }
+
+// DeletedInitiatorUsername is the InitiatorUsername of jobs whose initiator was
+// deleted.
+const DeletedInitiatorUsername = "(deleted user)"
+
+// JobCreationSource is where a search job was created. The values are stored in
+// the creation_source column of exhaustive_search_jobs and must not change.
+type JobCreationSource string
+
+const (
+	// JobCreationSourceWeb is a job created in the web app, which
+	// authenticates with a session cookie.
+	JobCreationSourceWeb JobCreationSource = "web"
+
+	// JobCreationSourceAPI is a job created by a client which authenticates
+	// otherwise, usually with an access token.
+	JobCreationSourceAPI JobCreationSource = "api"
+
+	// JobCreationSourceScheduled is a job created on a schedule instead of on
+	// a request of its initiator.
+	JobCreationSourceScheduled JobCreationSource = "scheduled"
+)
+
+// JobPriority is the priority of a search job. The workers dequeue the tasks of
+// jobs with a higher priority before the tasks of jobs with a lower priority.
+// Tasks of jobs with the same priority are dequeued oldest first.
+type JobPriority int
+
+// JobPriority constants. The values are stored in the priority column of
+// exhaustive_search_jobs and must not change.
+const (
+	JobPriorityLow    JobPriority = -1
+	JobPriorityNormal JobPriority = 0
+
+	// JobPriorityHigh is reserved for site admins.
+	JobPriorityHigh JobPriority = 1
+)
+
+func (p JobPriority) String() string {
+	switch p {
+	case JobPriorityLow:
+		return "low"
+	case JobPriorityNormal:
+		return "normal"
+	case JobPriorityHigh:
+		return "high"
+	}
+	return strconv.Itoa(int(p))
+}
+
+// ToGraphQL returns the GraphQL representation of the priority.
+func (p JobPriority) ToGraphQL() string { return strings.ToUpper(p.String()) }
+
+// JobPriorityFromGraphQL is the inverse of JobPriority.ToGraphQL.
+func JobPriorityFromGraphQL(s string) (JobPriority, bool) {
+	for _, p := range []JobPriority{JobPriorityLow, JobPriorityNormal, JobPriorityHigh} {
+		if p.ToGraphQL() == s {
+			return p, true
+		}
+	}
+	return 0, false
+}
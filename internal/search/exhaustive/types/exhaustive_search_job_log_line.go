@@ -0,0 +1,57 @@
+package types
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// SearchJobLogEvent is what happened to a task of a search job.
+type SearchJobLogEvent string
+
+const (
+	// SearchJobLogEventStarted is logged before a revision is searched.
+	SearchJobLogEventStarted SearchJobLogEvent = "started"
+
+	// SearchJobLogEventFinished is logged once a revision was searched.
+	SearchJobLogEventFinished SearchJobLogEvent = "finished"
+
+	// SearchJobLogEventRetried is logged if searching a revision failed and it
+	// is searched again later.
+	SearchJobLogEventRetried SearchJobLogEvent = "retried"
+
+	// SearchJobLogEventFailed is logged if searching a revision failed for
+	// good.
+	SearchJobLogEventFailed SearchJobLogEvent = "failed"
+
+	// SearchJobLogEventSkipped is logged if a revision is not searched because
+	// the job reached its deadline.
+	SearchJobLogEventSkipped SearchJobLogEvent = "skipped"
+
+	// SearchJobLogEventTruncated is the last line of a log which reached its
+	// size cap.
+	SearchJobLogEventTruncated SearchJobLogEvent = "truncated"
+)
+
+// SearchJobLogLine is a line of the log the workers write while they run the
+// tasks of a search job, so the initiator and site admins can find out what
+// happened without the logs of the workers.
+// Maps to the `exhaustive_search_job_log_lines` database table.
+type SearchJobLogLine struct {
+	ID          int64
+	SearchJobID int64
+
+	// RepoID and Revision are the revision the line is about. They are empty
+	// for lines about the whole job.
+	RepoID   api.RepoID
+	Revision string
+
+	// RepoName is the name of the repository with RepoID. It is only set by
+	// the store when reading lines.
+	RepoName api.RepoName
+
+	Event   SearchJobLogEvent
+	Message string
+
+	CreatedAt time.Time
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"strconv"
+	"time"
+)
+
+// NotificationKind is the channel a search job notification is sent on.
+type NotificationKind string
+
+const (
+	// NotificationKindEmail is an email to the initiator of the search job.
+	NotificationKindEmail NotificationKind = "email"
+
+	// NotificationKindWebhook is a request to the webhook configured when the
+	// search job was created.
+	NotificationKindWebhook NotificationKind = "webhook"
+
+	// NotificationKindSummary writes the SearchJobSummary of the search job
+	// next to its results.
+	NotificationKindSummary NotificationKind = "summary"
+)
+
+// ExhaustiveSearchJobNotification is a job that notifies the initiator of a
+// search job that the search job finished, or records that it finished, see
+// NotificationKindSummary.
+// Maps to the `exhaustive_search_job_notifications` database table.
+type ExhaustiveSearchJobNotification struct {
+	WorkerJob
+
+	ID int64
+
+	SearchJobID int64
+	Kind        NotificationKind
+
+	// JobState is the aggregate state the search job finished in. It is one of
+	// JobStateCompleted, JobStateFailed and JobStateCanceled.
+	JobState JobState
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (j *ExhaustiveSearchJobNotification) RecordID() int {
+	return int(j.ID)
+}
+
+func (j *ExhaustiveSearchJobNotification) RecordUID() string {
+	return strconv.FormatInt(j.ID, 10)
+}
@@ -16,6 +16,22 @@ type ExhaustiveSearchRepoRevisionJob struct {
 
 	SearchRepoJobID int64
 	Revision        string
+	// CommitID is the commit Revision resolved to. It is empty if the
+	// revision could not be resolved.
+	CommitID api.CommitID
+
+	// CodeHost is the external service ID of the repository, for example
+	// "https://github.com/", at the time the job was created. The workers
+	// limit how many revisions of each code host they search concurrently. It
+	// is empty for repositories without an external service.
+	CodeHost string
+
+	// BatchID is the ID of the first task of the batch the task belongs to,
+	// or 0 if the task is searched on its own. The task of a batch with the
+	// lowest ID which is still to be searched is the only one the workers
+	// dequeue, and it searches the revisions of all of them. See
+	// RepoRevisionBatchMember.
+	BatchID int64
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -29,13 +45,77 @@ func (j *ExhaustiveSearchRepoRevisionJob) RecordUID() string {
 	return strconv.FormatInt(j.ID, 10)
 }
 
+// RepoRevisionBatchMember is a task of a batch which is still to be searched,
+// with the revision it searches. The workers batch the revisions of small
+// repositories of the same code host, so they don't spend more time on the
+// queue than on the searches. A batch has at most one revision of a
+// repository, so its results can be told apart by repository.
+type RepoRevisionBatchMember struct {
+	ExhaustiveSearchRepoRevisionJob
+
+	RepoRev RepositoryRevision
+}
+
+// SearchJobTask is the full record of a repo revision job, with the search job
+// and the repository it belongs to, for site admins to inspect the queue.
+type SearchJobTask struct {
+	ExhaustiveSearchRepoRevisionJob
+
+	SearchJobID int64
+	RepoID      api.RepoID
+	RepoName    api.RepoName
+
+	// QueuedAt is when the task was last put into the queue.
+	QueuedAt time.Time
+
+	// WorkerStartedAt identifies the worker process which claimed the task
+	// last together with WorkerHostname, see SearchJobLog.
+	WorkerStartedAt time.Time
+}
+
 type SearchJobLog struct {
 	ID       int64
 	RepoName api.RepoName
+	RepoID   api.RepoID
 	Revision string
+	CommitID api.CommitID
 
 	State          JobState
 	FailureMessage string
 	StartedAt      time.Time
 	FinishedAt     time.Time
+
+	// BatchID is the batch of the task, see
+	// ExhaustiveSearchRepoRevisionJob.BatchID. The results of the tasks of a
+	// batch are stored together.
+	BatchID int64
+
+	// WorkerHostname and WorkerStartedAt identify the worker process which
+	// claimed the task last, by its hostname or pod name and the time it
+	// started. They are empty if no worker claimed the task yet.
+	WorkerHostname  string
+	WorkerStartedAt time.Time
+}
+
+// WorkerTaskStats is how many repo revision jobs a worker process finished in
+// a time window, to spot a worker which fails more tasks than the others.
+type WorkerTaskStats struct {
+	// WorkerHostname and WorkerStartedAt identify the worker process, see
+	// SearchJobLog.
+	WorkerHostname  string
+	WorkerStartedAt time.Time
+
+	// Tasks is the number of tasks the worker finished in the window, out of
+	// which Completed succeeded and Failed failed.
+	Tasks     int
+	Completed int
+	Failed    int
+}
+
+// FailureRate returns the share of the tasks of the worker which failed.
+func (s WorkerTaskStats) FailureRate() float64 {
+	if s.Tasks == 0 {
+		return 0
+	}
+	return float64(s.Failed) / float64(s.Tasks)
 }
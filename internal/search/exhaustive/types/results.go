@@ -0,0 +1,89 @@
+package types
+
+import "strconv"
+
+// ResultsSchemaVersion is the version of the columns of the CSV and Parquet
+// exports of search job results, and of the order of the rows of all exports.
+// It is reported with every export, so consumers can detect changes.
+//
+// 🚨 Bump it whenever ResultsColumns or the order of the rows change.
+const ResultsSchemaVersion = 2
+
+// ResultsColumns are the columns of the CSV and Parquet exports of search job
+// results, in order. The Parquet export stores repository_id and line as
+// nullable 32-bit integers:
+//
+//   - repository: the name of the repository.
+//   - repository_id: the ID of the repository.
+//   - revision: the revision spec that was searched, for example a branch.
+//   - commit: the commit the revision resolved to.
+//   - path: the path of the matched file. Empty for repository and commit
+//     matches.
+//   - line: the 1-based number of the matched line. Empty for matches which
+//     are not on a line, like path matches.
+//   - preview: the content of the matched line, or the name of the matched
+//     symbol.
+//
+// Rows are ordered by repository, path and line. Rows with the same keys are
+// ordered by revision.
+var ResultsColumns = []string{
+	"repository",
+	"repository_id",
+	"revision",
+	"commit",
+	"path",
+	"line",
+	"preview",
+}
+
+// ResultsRevisionsColumn is the column which the CSV and Parquet exports of
+// jobs with DeduplicateResults have after ResultsColumns. Rows which are the
+// same in several revisions of a repository, that is which have the same
+// path, line and preview, are only exported for the first revision in the
+// order of the rows, and this column lists every revision they were found in,
+// separated by spaces. The JSONL export adds the revisions of a match as a
+// "revisions" array instead.
+const ResultsRevisionsColumn = "revisions"
+
+// ResultsSample describes the results of a job with a SampleRate, which are a
+// sample of the results of its query. Every export of such results carries
+// it, so consumers don't mistake them for complete results.
+type ResultsSample struct {
+	// Rate and Seed are the SampleRate and SampleSeed of the job.
+	Rate float64
+	Seed int64
+
+	// SampledTasks and TotalTasks are the SampledTaskCount and TotalTaskCount
+	// of the job.
+	SampledTasks int
+	TotalTasks   int
+}
+
+// Sample returns the ResultsSample of the results of j, or nil if j searches
+// every revision of its query.
+func (j *ExhaustiveSearchJob) Sample() *ResultsSample {
+	if j.SampleRate <= 0 || j.SampleRate >= 1 {
+		return nil
+	}
+	return &ResultsSample{
+		Rate:         j.SampleRate,
+		Seed:         j.SampleSeed,
+		SampledTasks: j.SampledTaskCount,
+		TotalTasks:   j.TotalTaskCount,
+	}
+}
+
+// Metadata returns s as the key-value metadata of the Parquet export, in a
+// stable order.
+func (s *ResultsSample) Metadata() (keys, values []string) {
+	add := func(key, value string) {
+		keys = append(keys, "sourcegraph."+key)
+		values = append(values, value)
+	}
+	add("sampled", "true")
+	add("sample_rate", strconv.FormatFloat(s.Rate, 'g', -1, 64))
+	add("sample_seed", strconv.FormatInt(s.Seed, 10))
+	add("sampled_tasks", strconv.Itoa(s.SampledTasks))
+	add("total_tasks", strconv.Itoa(s.TotalTasks))
+	return keys, values
+}
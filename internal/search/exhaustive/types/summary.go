@@ -0,0 +1,95 @@
+package types
+
+import (
+	_ "embed"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// SummarySchemaVersion is the version of SearchJobSummary. It is written to
+// every summary, so consumers can detect changes.
+//
+// 🚨 Bump it whenever a field of SearchJobSummary is removed, renamed or
+// changes its meaning, and update SearchJobSummarySchema.
+const SummarySchemaVersion = 1
+
+// SearchJobSummarySchema is the JSON schema of SearchJobSummary, for consumers
+// which validate the summaries they read.
+//
+//go:embed summary.schema.json
+var SearchJobSummarySchema string
+
+// SearchJobSummary describes how a search job finished. It is written as JSON
+// next to the results of the job once the job finished, so automation which
+// picks up the results can tell whether they are complete.
+type SearchJobSummary struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	JobID         int64  `json:"jobID"`
+	Query         string `json:"query"`
+
+	// Format is the format of the result chunks stored next to the summary,
+	// and ResultsSchemaVersion the ResultsSchemaVersion of the results.
+	Format               string `json:"format"`
+	ResultsSchemaVersion int    `json:"resultsSchemaVersion"`
+
+	// State is the aggregate state the job finished in. It is one of
+	// JobStateCompleted, JobStateFailed and JobStateCanceled.
+	State JobState `json:"state"`
+
+	// Complete is true if the results have a match for every repository and
+	// revision of the query: the job completed without failed tasks, and was
+	// neither truncated, stopped by its deadline nor sampled.
+	Complete bool `json:"complete"`
+
+	Initiator SearchJobSummaryInitiator `json:"initiator"`
+
+	// StartedAt is nil if the job was canceled before a worker started it.
+	// FinishedAt is the time the last task of the job finished.
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt"`
+	FinishedAt time.Time  `json:"finishedAt"`
+
+	Tasks SearchJobSummaryTasks `json:"tasks"`
+
+	// ResultCount is the number of results, which are the rows of the CSV
+	// export, and BytesWritten their stored size.
+	ResultCount  int   `json:"resultCount"`
+	BytesWritten int64 `json:"bytesWritten"`
+
+	// Truncated, DeadlineExceeded, SampleRate are the fields of the job
+	// with the same names. Sampled is true if SampleRate left out revisions.
+	Truncated        bool    `json:"truncated"`
+	DeadlineExceeded bool    `json:"deadlineExceeded"`
+	Sampled          bool    `json:"sampled"`
+	SampleRate       float64 `json:"sampleRate"`
+
+	// FailedRepositories are the repository revisions whose search failed,
+	// ordered by task. It is empty, not null, if none failed.
+	FailedRepositories []SearchJobSummaryFailure `json:"failedRepositories"`
+}
+
+// SearchJobSummaryInitiator is the user who created a search job. Username is
+// DeletedInitiatorUsername if the user was deleted.
+type SearchJobSummaryInitiator struct {
+	ID       int32  `json:"id"`
+	Username string `json:"username"`
+}
+
+// SearchJobSummaryTasks counts the repo revision tasks of a search job by the
+// state they finished in.
+type SearchJobSummaryTasks struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	Canceled  int `json:"canceled"`
+	Skipped   int `json:"skipped"`
+}
+
+// SearchJobSummaryFailure is a repository revision whose search failed.
+type SearchJobSummaryFailure struct {
+	Repository     api.RepoName `json:"repository"`
+	RepositoryID   api.RepoID   `json:"repositoryID"`
+	Revision       string       `json:"revision"`
+	FailureMessage string       `json:"failureMessage"`
+}
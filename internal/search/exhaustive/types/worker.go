@@ -30,6 +30,11 @@ const (
 	JobStateFailed     JobState = "failed"
 	JobStateCompleted  JobState = "completed"
 	JobStateCanceled   JobState = "canceled"
+
+	// JobStateSkipped is the state of repo and repo revision jobs which were
+	// not run because their search job reached its result limit or its
+	// deadline.
+	JobStateSkipped JobState = "skipped"
 )
 
 // ToGraphQL returns the GraphQL representation of the worker state.
@@ -18,6 +18,15 @@ const (
 	// We still set limits for exhaustive to prevent runaway jobs.
 	DefaultMaxSearchResultsExhaustive = 1_000_000
 	DefaultTimeoutExhaustive          = time.Hour
+
+	// The default number of search jobs which can run at once per user and
+	// on the instance.
+	DefaultMaxActiveSearchJobsPerUser = 5
+	DefaultMaxActiveSearchJobs        = 100
+
+	// DefaultSearchJobIdempotencyWindow is how long the idempotency key of a
+	// search job is kept by default.
+	DefaultSearchJobIdempotencyWindow = 24 * time.Hour
 )
 
 func SearchLimits(c *conf.Unified) schema.SearchLimits {
@@ -43,6 +52,16 @@ func SearchLimits(c *conf.Unified) schema.SearchLimits {
 	withDefault(&limits.CommitDiffMaxRepos, 50)
 	withDefault(&limits.CommitDiffWithTimeFilterMaxRepos, 10000)
 	withDefault(&limits.MaxTimeoutSeconds, 60)
+	withDefault(&limits.SearchJobIdempotencyWindowSeconds, int(DefaultSearchJobIdempotencyWindow/time.Second))
+
+	// Negative search job limits mean unlimited, so we only default unset
+	// ones.
+	if limits.MaxActiveSearchJobsPerUser == 0 {
+		limits.MaxActiveSearchJobsPerUser = DefaultMaxActiveSearchJobsPerUser
+	}
+	if limits.MaxActiveSearchJobs == 0 {
+		limits.MaxActiveSearchJobs = DefaultMaxActiveSearchJobs
+	}
 
 	return limits
 }
@@ -146,6 +146,9 @@ func (s *gcsStore) Upload(ctx context.Context, key string, r io.Reader) (_ int64
 
 	n, err := io.Copy(writer, r)
 	if err != nil {
+		// Canceling the context before closing the writer discards what was
+		// written so far instead of creating an object with partial content.
+		cancel()
 		return 0, errors.Wrap(err, "failed to upload object")
 	}
 
@@ -15,7 +15,11 @@ type lazyStore struct {
 	initialized bool
 }
 
-var _ Store = &gcsStore{}
+var (
+	_ Store                   = &lazyStore{}
+	_ Presigner               = &lazyStore{}
+	_ IncompleteUploadAborter = &lazyStore{}
+)
 
 func newLazyStore(store Store) Store {
 	return &lazyStore{store: store}
@@ -73,6 +77,22 @@ func (s *lazyStore) ExpireObjects(ctx context.Context, prefix string, maxAge tim
 	return s.store.ExpireObjects(ctx, prefix, maxAge)
 }
 
+func (s *lazyStore) PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if err := s.initOnce(ctx); err != nil {
+		return "", err
+	}
+
+	return PresignGet(ctx, s.store, key, expiry)
+}
+
+func (s *lazyStore) AbortIncompleteUploads(ctx context.Context, prefix string, maxAge time.Duration) error {
+	if err := s.initOnce(ctx); err != nil {
+		return err
+	}
+
+	return AbortIncompleteUploads(ctx, s.store, prefix, maxAge)
+}
+
 // initOnce serializes access to the underlying store's Init method. If the
 // Init method completes successfully, all future calls to this function will
 // no-op.
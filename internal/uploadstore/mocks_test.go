@@ -1407,6 +1407,9 @@ type MockS3API struct {
 	// HeadObjectFunc is an instance of a mock function object controlling
 	// the behavior of the method HeadObject.
 	HeadObjectFunc *S3APIHeadObjectFunc
+	// ListMultipartUploadsFunc is an instance of a mock function object
+	// controlling the behavior of the method ListMultipartUploads.
+	ListMultipartUploadsFunc *S3APIListMultipartUploadsFunc
 	// NewListObjectsV2PaginatorFunc is an instance of a mock function
 	// object controlling the behavior of the method
 	// NewListObjectsV2Paginator.
@@ -1460,6 +1463,11 @@ func NewMockS3API() *MockS3API {
 				return
 			},
 		},
+		ListMultipartUploadsFunc: &S3APIListMultipartUploadsFunc{
+			defaultHook: func(context.Context, *s3.ListMultipartUploadsInput) (r0 *s3.ListMultipartUploadsOutput, r1 error) {
+				return
+			},
+		},
 		NewListObjectsV2PaginatorFunc: &S3APINewListObjectsV2PaginatorFunc{
 			defaultHook: func(*s3.ListObjectsV2Input) (r0 *s3.ListObjectsV2Paginator) {
 				return
@@ -1517,6 +1525,11 @@ func NewStrictMockS3API() *MockS3API {
 				panic("unexpected invocation of MockS3API.HeadObject")
 			},
 		},
+		ListMultipartUploadsFunc: &S3APIListMultipartUploadsFunc{
+			defaultHook: func(context.Context, *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error) {
+				panic("unexpected invocation of MockS3API.ListMultipartUploads")
+			},
+		},
 		NewListObjectsV2PaginatorFunc: &S3APINewListObjectsV2PaginatorFunc{
 			defaultHook: func(*s3.ListObjectsV2Input) *s3.ListObjectsV2Paginator {
 				panic("unexpected invocation of MockS3API.NewListObjectsV2Paginator")
@@ -1542,6 +1555,7 @@ type surrogateMockS3API interface {
 	DeleteObjects(context.Context, *s3.DeleteObjectsInput, ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
 	GetObject(context.Context, *s3.GetObjectInput) (*s3.GetObjectOutput, error)
 	HeadObject(context.Context, *s3.HeadObjectInput) (*s3.HeadObjectOutput, error)
+	ListMultipartUploads(context.Context, *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error)
 	NewListObjectsV2Paginator(*s3.ListObjectsV2Input) *s3.ListObjectsV2Paginator
 	UploadPartCopy(context.Context, *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error)
 }
@@ -1574,6 +1588,9 @@ func NewMockS3APIFrom(i surrogateMockS3API) *MockS3API {
 		HeadObjectFunc: &S3APIHeadObjectFunc{
 			defaultHook: i.HeadObject,
 		},
+		ListMultipartUploadsFunc: &S3APIListMultipartUploadsFunc{
+			defaultHook: i.ListMultipartUploads,
+		},
 		NewListObjectsV2PaginatorFunc: &S3APINewListObjectsV2PaginatorFunc{
 			defaultHook: i.NewListObjectsV2Paginator,
 		},
@@ -2453,6 +2470,114 @@ func (c S3APIHeadObjectFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// S3APIListMultipartUploadsFunc describes the behavior when the
+// ListMultipartUploads method of the parent MockS3API instance is invoked.
+type S3APIListMultipartUploadsFunc struct {
+	defaultHook func(context.Context, *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error)
+	hooks       []func(context.Context, *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error)
+	history     []S3APIListMultipartUploadsFuncCall
+	mutex       sync.Mutex
+}
+
+// ListMultipartUploads delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockS3API) ListMultipartUploads(v0 context.Context, v1 *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error) {
+	r0, r1 := m.ListMultipartUploadsFunc.nextHook()(v0, v1)
+	m.ListMultipartUploadsFunc.appendCall(S3APIListMultipartUploadsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListMultipartUploads
+// method of the parent MockS3API instance is invoked and the hook queue is
+// empty.
+func (f *S3APIListMultipartUploadsFunc) SetDefaultHook(hook func(context.Context, *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListMultipartUploads method of the parent MockS3API instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *S3APIListMultipartUploadsFunc) PushHook(hook func(context.Context, *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *S3APIListMultipartUploadsFunc) SetDefaultReturn(r0 *s3.ListMultipartUploadsOutput, r1 error) {
+	f.SetDefaultHook(func(context.Context, *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *S3APIListMultipartUploadsFunc) PushReturn(r0 *s3.ListMultipartUploadsOutput, r1 error) {
+	f.PushHook(func(context.Context, *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error) {
+		return r0, r1
+	})
+}
+
+func (f *S3APIListMultipartUploadsFunc) nextHook() func(context.Context, *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *S3APIListMultipartUploadsFunc) appendCall(r0 S3APIListMultipartUploadsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of S3APIListMultipartUploadsFuncCall objects
+// describing the invocations of this function.
+func (f *S3APIListMultipartUploadsFunc) History() []S3APIListMultipartUploadsFuncCall {
+	f.mutex.Lock()
+	history := make([]S3APIListMultipartUploadsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// S3APIListMultipartUploadsFuncCall is an object that describes an
+// invocation of method ListMultipartUploads on an instance of MockS3API.
+type S3APIListMultipartUploadsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *s3.ListMultipartUploadsInput
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *s3.ListMultipartUploadsOutput
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c S3APIListMultipartUploadsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c S3APIListMultipartUploadsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // S3APINewListObjectsV2PaginatorFunc describes the behavior when the
 // NewListObjectsV2Paginator method of the parent MockS3API instance is
 // invoked.
@@ -14,6 +14,9 @@ type Operations struct {
 	Delete        *observation.Operation
 	ExpireObjects *observation.Operation
 	List          *observation.Operation
+	PresignGet    *observation.Operation
+
+	AbortIncompleteUploads *observation.Operation
 }
 
 func NewOperations(observationCtx *observation.Context, domain, storeName string) *Operations {
@@ -39,5 +42,8 @@ func NewOperations(observationCtx *observation.Context, domain, storeName string
 		Delete:        op("Delete"),
 		ExpireObjects: op("ExpireObjects"),
 		List:          op("List"),
+		PresignGet:    op("PresignGet"),
+
+		AbortIncompleteUploads: op("AbortIncompleteUploads"),
 	}
 }
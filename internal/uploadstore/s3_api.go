@@ -2,6 +2,7 @@ package uploadstore
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -12,6 +13,7 @@ type s3API interface {
 	GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.GetObjectOutput, error)
 	CreateMultipartUpload(ctx context.Context, input *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error)
 	AbortMultipartUpload(ctx context.Context, input *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error)
+	ListMultipartUploads(ctx context.Context, input *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error)
 	DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error)
 	UploadPartCopy(ctx context.Context, input *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error)
 	CompleteMultipartUpload(ctx context.Context, input *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error)
@@ -24,14 +26,20 @@ type s3Uploader interface {
 	Upload(ctx context.Context, input *s3.PutObjectInput) error
 }
 
+type s3Presigner interface {
+	PresignGetObject(ctx context.Context, input *s3.GetObjectInput, expiry time.Duration) (string, error)
+}
+
 type (
-	s3APIShim      struct{ *s3.Client }
-	s3UploaderShim struct{ *manager.Uploader }
+	s3APIShim       struct{ *s3.Client }
+	s3UploaderShim  struct{ *manager.Uploader }
+	s3PresignerShim struct{ *s3.PresignClient }
 )
 
 var (
-	_ s3API      = &s3APIShim{}
-	_ s3Uploader = &s3UploaderShim{}
+	_ s3API       = &s3APIShim{}
+	_ s3Uploader  = &s3UploaderShim{}
+	_ s3Presigner = &s3PresignerShim{}
 )
 
 func (s *s3APIShim) CreateBucket(ctx context.Context, input *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
@@ -58,6 +66,10 @@ func (s *s3APIShim) AbortMultipartUpload(ctx context.Context, input *s3.AbortMul
 	return s.Client.AbortMultipartUpload(ctx, input)
 }
 
+func (s *s3APIShim) ListMultipartUploads(ctx context.Context, input *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error) {
+	return s.Client.ListMultipartUploads(ctx, input)
+}
+
 func (s *s3APIShim) UploadPartCopy(ctx context.Context, input *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error) {
 	return s.Client.UploadPartCopy(ctx, input)
 }
@@ -74,6 +86,14 @@ func (s *s3APIShim) NewListObjectsV2Paginator(input *s3.ListObjectsV2Input) *s3.
 	return s3.NewListObjectsV2Paginator(s.Client, input)
 }
 
+func (s *s3PresignerShim) PresignGetObject(ctx context.Context, input *s3.GetObjectInput, expiry time.Duration) (string, error) {
+	req, err := s.PresignClient.PresignGetObject(ctx, input, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
 func (s *s3UploaderShim) Upload(ctx context.Context, input *s3.PutObjectInput) error {
 	_, err := s.Uploader.Upload(ctx, input)
 	return err
@@ -28,9 +28,16 @@ type s3Store struct {
 	client       s3API
 	uploader     s3Uploader
 	operations   *Operations
+
+	// presigner is nil if the backend can't presign URLs.
+	presigner s3Presigner
 }
 
-var _ Store = &s3Store{}
+var (
+	_ Store                   = &s3Store{}
+	_ Presigner               = &s3Store{}
+	_ IncompleteUploadAborter = &s3Store{}
+)
 
 type S3Config struct {
 	IsBlobstore     bool
@@ -52,7 +59,15 @@ func newS3FromConfig(ctx context.Context, config Config, operations *Operations)
 	s3Client := s3.NewFromConfig(cfg, s3ClientOptions(config.S3))
 	api := &s3APIShim{s3Client}
 	uploader := &s3UploaderShim{manager.NewUploader(s3Client)}
-	return newS3WithClients(api, uploader, config.Bucket, config.ManageBucket, operations), nil
+	store := newS3WithClients(api, uploader, config.Bucket, config.ManageBucket, operations)
+
+	// The bundled blobstore is only reachable from within the instance, so
+	// URLs presigned for it are useless to clients.
+	if !config.S3.IsBlobstore {
+		store.presigner = &s3PresignerShim{s3.NewPresignClient(s3Client)}
+	}
+
+	return store, nil
 }
 
 func newS3WithClients(client s3API, uploader s3Uploader, bucket string, manageBucket bool, operations *Operations) *s3Store {
@@ -310,6 +325,66 @@ func (s *s3Store) Delete(ctx context.Context, key string) (err error) {
 	return errors.Wrap(err, "failed to delete object")
 }
 
+func (s *s3Store) PresignGet(ctx context.Context, key string, expiry time.Duration) (_ string, err error) {
+	ctx, _, endObservation := s.operations.PresignGet.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("key", key),
+		attribute.Stringer("expiry", expiry),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if s.presigner == nil {
+		return "", ErrPresignUnsupported
+	}
+
+	url, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, expiry)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to presign object URL")
+	}
+
+	return url, nil
+}
+
+func (s *s3Store) AbortIncompleteUploads(ctx context.Context, prefix string, maxAge time.Duration) (err error) {
+	ctx, _, endObservation := s.operations.AbortIncompleteUploads.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("prefix", prefix),
+		attribute.Stringer("maxAge", maxAge),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+	for {
+		page, err := s.client.ListMultipartUploads(ctx, input)
+		if err != nil {
+			return errors.Wrap(err, "failed to list multipart uploads")
+		}
+
+		for _, upload := range page.Uploads {
+			if upload.Initiated == nil || time.Since(*upload.Initiated) < maxAge {
+				continue
+			}
+			if _, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(s.bucket),
+				Key:      upload.Key,
+				UploadId: upload.UploadId,
+			}); err != nil {
+				return errors.Wrap(err, "failed to abort multipart upload")
+			}
+		}
+
+		if !page.IsTruncated {
+			return nil
+		}
+		input.KeyMarker = page.NextKeyMarker
+		input.UploadIdMarker = page.NextUploadIdMarker
+	}
+}
+
 func (s *s3Store) ExpireObjects(ctx context.Context, prefix string, maxAge time.Duration) (err error) {
 	ctx, _, endObservation := s.operations.ExpireObjects.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
 		attribute.String("prefix", prefix),
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -363,6 +364,93 @@ func TestS3Delete(t *testing.T) {
 	}
 }
 
+func TestS3PresignGet(t *testing.T) {
+	var gotInput *s3.GetObjectInput
+	var gotExpiry time.Duration
+	store := rawS3Client(NewMockS3API(), nil)
+	store.presigner = fakeS3Presigner(func(_ context.Context, input *s3.GetObjectInput, expiry time.Duration) (string, error) {
+		gotInput, gotExpiry = input, expiry
+		return "https://test-bucket.s3.amazonaws.com/test-key?X-Amz-Signature=sig", nil
+	})
+	client := newLazyStore(store)
+
+	url, err := PresignGet(context.Background(), client, "test-key", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error presigning key: %s", err)
+	}
+	if want := "https://test-bucket.s3.amazonaws.com/test-key?X-Amz-Signature=sig"; url != want {
+		t.Errorf("unexpected url. want=%s have=%s", want, url)
+	}
+	if value := *gotInput.Bucket; value != "test-bucket" {
+		t.Errorf("unexpected bucket argument. want=%s have=%s", "test-bucket", value)
+	}
+	if value := *gotInput.Key; value != "test-key" {
+		t.Errorf("unexpected key argument. want=%s have=%s", "test-key", value)
+	}
+	if gotExpiry != time.Minute {
+		t.Errorf("unexpected expiry. want=%s have=%s", time.Minute, gotExpiry)
+	}
+}
+
+func TestS3PresignGetUnsupported(t *testing.T) {
+	// Stores created for the bundled blobstore have no presigner.
+	client := testS3Client(NewMockS3API(), nil)
+	if _, err := PresignGet(context.Background(), client, "test-key", time.Minute); !errors.Is(err, ErrPresignUnsupported) {
+		t.Fatalf("unexpected error. want=%s have=%s", ErrPresignUnsupported, err)
+	}
+}
+
+func TestS3AbortIncompleteUploads(t *testing.T) {
+	old := time.Now().Add(-2 * time.Hour)
+	recent := time.Now()
+
+	s3Client := NewMockS3API()
+	s3Client.ListMultipartUploadsFunc.PushReturn(&s3.ListMultipartUploadsOutput{
+		Uploads: []s3types.MultipartUpload{
+			{Key: aws.String("1-1"), UploadId: aws.String("u1"), Initiated: &old},
+			{Key: aws.String("1-2"), UploadId: aws.String("u2"), Initiated: &recent},
+		},
+		IsTruncated:        true,
+		NextKeyMarker:      aws.String("1-2"),
+		NextUploadIdMarker: aws.String("u2"),
+	}, nil)
+	s3Client.ListMultipartUploadsFunc.PushReturn(&s3.ListMultipartUploadsOutput{
+		Uploads: []s3types.MultipartUpload{
+			{Key: aws.String("1-3"), UploadId: aws.String("u3"), Initiated: &old},
+		},
+	}, nil)
+
+	client := testS3Client(s3Client, nil)
+	if err := AbortIncompleteUploads(context.Background(), client, "1-", time.Hour); err != nil {
+		t.Fatalf("unexpected error aborting uploads: %s", err)
+	}
+
+	listCalls := s3Client.ListMultipartUploadsFunc.History()
+	if len(listCalls) != 2 {
+		t.Fatalf("unexpected number of ListMultipartUploads calls. want=%d have=%d", 2, len(listCalls))
+	}
+	if value := *listCalls[0].Arg1.Prefix; value != "1-" {
+		t.Errorf("unexpected prefix argument. want=%s have=%s", "1-", value)
+	}
+	if value := *listCalls[1].Arg1.KeyMarker; value != "1-2" {
+		t.Errorf("unexpected key marker argument. want=%s have=%s", "1-2", value)
+	}
+
+	var aborted []string
+	for _, call := range s3Client.AbortMultipartUploadFunc.History() {
+		aborted = append(aborted, *call.Arg1.UploadId)
+	}
+	if diff := cmp.Diff([]string{"u1", "u3"}, aborted); diff != "" {
+		t.Errorf("unexpected aborted uploads (-want +got):\n%s", diff)
+	}
+}
+
+type fakeS3Presigner func(ctx context.Context, input *s3.GetObjectInput, expiry time.Duration) (string, error)
+
+func (f fakeS3Presigner) PresignGetObject(ctx context.Context, input *s3.GetObjectInput, expiry time.Duration) (string, error) {
+	return f(ctx, input, expiry)
+}
+
 func testS3Client(client s3API, uploader s3Uploader) Store {
 	return newLazyStore(rawS3Client(client, uploader))
 }
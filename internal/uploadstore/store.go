@@ -37,6 +37,53 @@ type Store interface {
 	List(ctx context.Context, prefix string) (*iterator.Iterator[string], error)
 }
 
+// Presigner is implemented by stores which can create URLs that grant
+// temporary read access to an object, so clients can download it directly from
+// the blob store.
+type Presigner interface {
+	// PresignGet returns a URL which reads the object at the given key without
+	// further credentials until expiry has passed. The object doesn't have to
+	// exist yet.
+	PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// ErrPresignUnsupported is returned by PresignGet if the store can't create
+// presigned URLs.
+var ErrPresignUnsupported = errors.New("upload store does not support presigned URLs")
+
+// PresignGet returns a presigned URL for the object at the given key if store
+// implements Presigner, and ErrPresignUnsupported otherwise.
+func PresignGet(ctx context.Context, store Store, key string, expiry time.Duration) (string, error) {
+	presigner, ok := store.(Presigner)
+	if !ok {
+		return "", ErrPresignUnsupported
+	}
+
+	return presigner.PresignGet(ctx, key, expiry)
+}
+
+// IncompleteUploadAborter is implemented by stores which keep the uploaded
+// parts of multipart uploads that were neither completed nor aborted, for
+// example because the uploading process died.
+type IncompleteUploadAborter interface {
+	// AbortIncompleteUploads aborts the multipart uploads to keys with the
+	// given prefix which were started more than maxAge ago, and deletes their
+	// parts.
+	AbortIncompleteUploads(ctx context.Context, prefix string, maxAge time.Duration) error
+}
+
+// AbortIncompleteUploads calls AbortIncompleteUploads of store if it implements
+// IncompleteUploadAborter. Other stores don't keep the parts of incomplete
+// uploads, so there is nothing to do.
+func AbortIncompleteUploads(ctx context.Context, store Store, prefix string, maxAge time.Duration) error {
+	aborter, ok := store.(IncompleteUploadAborter)
+	if !ok {
+		return nil
+	}
+
+	return aborter.AbortIncompleteUploads(ctx, prefix, maxAge)
+}
+
 var storeConstructors = map[string]func(ctx context.Context, config Config, operations *Operations) (Store, error){
 	"s3":        newS3FromConfig,
 	"blobstore": newS3FromConfig,
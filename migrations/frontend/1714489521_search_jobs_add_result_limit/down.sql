@@ -0,0 +1,4 @@
+ALTER TABLE exhaustive_search_jobs
+    DROP COLUMN IF EXISTS max_results,
+    DROP COLUMN IF EXISTS result_count,
+    DROP COLUMN IF EXISTS truncated;
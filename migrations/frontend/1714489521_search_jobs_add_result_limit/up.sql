@@ -0,0 +1,4 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS max_results integer,
+    ADD COLUMN IF NOT EXISTS result_count integer NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS truncated boolean NOT NULL DEFAULT false;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS exhaustive_search_job_notifications;
+
+ALTER TABLE exhaustive_search_jobs
+    DROP COLUMN IF EXISTS webhook_url,
+    DROP COLUMN IF EXISTS webhook_secret,
+    DROP COLUMN IF EXISTS webhook_encryption_key_id,
+    DROP COLUMN IF EXISTS notified_at;
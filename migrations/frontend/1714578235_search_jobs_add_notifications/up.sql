@@ -0,0 +1,29 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS webhook_url text,
+    ADD COLUMN IF NOT EXISTS webhook_secret text,
+    ADD COLUMN IF NOT EXISTS webhook_encryption_key_id text,
+    ADD COLUMN IF NOT EXISTS notified_at timestamp with time zone;
+
+CREATE TABLE IF NOT EXISTS exhaustive_search_job_notifications
+(
+    id                SERIAL PRIMARY KEY,
+    state             text                     DEFAULT 'queued'::text,
+    search_job_id     integer                                NOT NULL REFERENCES exhaustive_search_jobs (id) ON DELETE CASCADE,
+    kind              text                                   NOT NULL,
+    job_state         text                                   NOT NULL,
+    failure_message   text,
+    started_at        timestamp with time zone,
+    finished_at       timestamp with time zone,
+    process_after     timestamp with time zone,
+    num_resets        integer                  DEFAULT 0     NOT NULL,
+    num_failures      integer                  DEFAULT 0     NOT NULL,
+    last_heartbeat_at timestamp with time zone,
+    execution_logs    json[],
+    worker_hostname   text                                   not null default '',
+    cancel            boolean                                not null default false,
+    created_at        timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at        timestamp with time zone DEFAULT now() NOT NULL,
+    queued_at         timestamp with time zone DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS exhaustive_search_job_notifications_state ON exhaustive_search_job_notifications (state);
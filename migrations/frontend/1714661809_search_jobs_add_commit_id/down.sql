@@ -0,0 +1,2 @@
+ALTER TABLE exhaustive_search_repo_revision_jobs
+    DROP COLUMN IF EXISTS commit_id;
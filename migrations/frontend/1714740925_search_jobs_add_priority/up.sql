@@ -0,0 +1,2 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS priority integer NOT NULL DEFAULT 0;
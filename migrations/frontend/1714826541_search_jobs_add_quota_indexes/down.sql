@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS exhaustive_search_jobs_initiator_id;
+DROP INDEX IF EXISTS exhaustive_search_repo_jobs_search_job_id;
+DROP INDEX IF EXISTS exhaustive_search_repo_revision_jobs_search_repo_job_id;
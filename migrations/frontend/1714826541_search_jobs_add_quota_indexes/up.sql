@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS exhaustive_search_jobs_initiator_id ON exhaustive_search_jobs (initiator_id);
+CREATE INDEX IF NOT EXISTS exhaustive_search_repo_jobs_search_job_id ON exhaustive_search_repo_jobs (search_job_id);
+CREATE INDEX IF NOT EXISTS exhaustive_search_repo_revision_jobs_search_repo_job_id ON exhaustive_search_repo_revision_jobs (search_repo_job_id);
@@ -0,0 +1,2 @@
+ALTER TABLE exhaustive_search_jobs
+    DROP COLUMN IF EXISTS created_from_job_id;
@@ -0,0 +1,2 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS created_from_job_id integer;
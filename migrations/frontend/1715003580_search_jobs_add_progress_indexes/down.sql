@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS exhaustive_search_repo_jobs_search_job_id ON exhaustive_search_repo_jobs (search_job_id);
+CREATE INDEX IF NOT EXISTS exhaustive_search_repo_revision_jobs_search_repo_job_id ON exhaustive_search_repo_revision_jobs (search_repo_job_id);
+
+DROP INDEX IF EXISTS exhaustive_search_repo_jobs_search_job_id_state;
+DROP INDEX IF EXISTS exhaustive_search_repo_revision_jobs_search_repo_job_id_state;
@@ -0,0 +1,3 @@
+ALTER TABLE exhaustive_search_jobs
+    DROP COLUMN IF EXISTS deadline,
+    DROP COLUMN IF EXISTS deadline_exceeded;
@@ -0,0 +1,3 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS deadline timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS deadline_exceeded boolean NOT NULL DEFAULT false;
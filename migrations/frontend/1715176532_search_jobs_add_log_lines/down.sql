@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS exhaustive_search_job_log_lines;
+
+ALTER TABLE exhaustive_search_jobs
+    DROP COLUMN IF EXISTS log_line_count;
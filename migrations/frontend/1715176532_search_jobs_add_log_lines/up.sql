@@ -0,0 +1,15 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS log_line_count integer NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS exhaustive_search_job_log_lines
+(
+    id            BIGSERIAL PRIMARY KEY,
+    search_job_id integer                                NOT NULL REFERENCES exhaustive_search_jobs (id) ON DELETE CASCADE,
+    repo_id       integer,
+    revision      text,
+    event         text                                   NOT NULL,
+    message       text                     DEFAULT ''    NOT NULL,
+    created_at    timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS exhaustive_search_job_log_lines_search_job_id ON exhaustive_search_job_log_lines (search_job_id, id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS exhaustive_search_repo_jobs_search_job_id_repo_id_ref_spec;
+
+ALTER TABLE exhaustive_search_jobs
+    DROP COLUMN IF EXISTS expanded_at;
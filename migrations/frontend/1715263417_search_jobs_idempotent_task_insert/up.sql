@@ -0,0 +1,20 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS expanded_at timestamp with time zone;
+
+-- Jobs which already finished created all their tasks.
+UPDATE exhaustive_search_jobs
+SET expanded_at = finished_at
+WHERE expanded_at IS NULL AND finished_at IS NOT NULL;
+
+-- Expanding the same job concurrently may have created duplicate repo jobs.
+-- Keep the oldest of them, so that the unique index can be created. The repo
+-- revision jobs of the others are deleted with them.
+DELETE FROM exhaustive_search_repo_jobs rj
+USING exhaustive_search_repo_jobs other
+WHERE rj.search_job_id = other.search_job_id
+    AND rj.repo_id = other.repo_id
+    AND rj.ref_spec = other.ref_spec
+    AND rj.id > other.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS exhaustive_search_repo_jobs_search_job_id_repo_id_ref_spec
+    ON exhaustive_search_repo_jobs (search_job_id, repo_id, ref_spec);
@@ -0,0 +1,5 @@
+ALTER TABLE exhaustive_search_jobs
+    DROP COLUMN IF EXISTS sample_rate,
+    DROP COLUMN IF EXISTS sample_seed,
+    DROP COLUMN IF EXISTS sampled_task_count,
+    DROP COLUMN IF EXISTS total_task_count;
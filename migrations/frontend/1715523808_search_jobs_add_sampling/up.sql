@@ -0,0 +1,5 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS sample_rate double precision NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS sample_seed bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS sampled_task_count integer NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS total_task_count integer NOT NULL DEFAULT 0;
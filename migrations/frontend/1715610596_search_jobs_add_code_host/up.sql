@@ -0,0 +1,9 @@
+ALTER TABLE exhaustive_search_repo_revision_jobs
+    ADD COLUMN IF NOT EXISTS code_host text NOT NULL DEFAULT '';
+
+-- The workers only look at the code host of the tasks that still run.
+UPDATE exhaustive_search_repo_revision_jobs rrj
+SET code_host = COALESCE(r.external_service_id, '')
+FROM exhaustive_search_repo_jobs rj
+JOIN repo r ON r.id = rj.repo_id
+WHERE rj.id = rrj.search_repo_job_id AND rrj.state IN ('queued', 'errored', 'processing');
@@ -0,0 +1,2 @@
+ALTER TABLE exhaustive_search_jobs
+    DROP COLUMN IF EXISTS deduplicate_results;
@@ -0,0 +1,2 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS deduplicate_results boolean NOT NULL DEFAULT false;
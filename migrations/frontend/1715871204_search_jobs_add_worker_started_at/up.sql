@@ -0,0 +1,2 @@
+ALTER TABLE exhaustive_search_repo_revision_jobs
+    ADD COLUMN IF NOT EXISTS worker_started_at timestamp with time zone;
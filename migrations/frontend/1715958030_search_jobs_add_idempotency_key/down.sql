@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS exhaustive_search_jobs_initiator_id_idempotency_key;
+
+ALTER TABLE exhaustive_search_jobs
+    DROP COLUMN IF EXISTS idempotency_key,
+    DROP COLUMN IF EXISTS idempotency_key_expires_at;
@@ -0,0 +1,7 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS idempotency_key text,
+    ADD COLUMN IF NOT EXISTS idempotency_key_expires_at timestamp with time zone;
+
+CREATE UNIQUE INDEX IF NOT EXISTS exhaustive_search_jobs_initiator_id_idempotency_key
+    ON exhaustive_search_jobs (initiator_id, idempotency_key)
+    WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS exhaustive_search_jobs_created_at;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS exhaustive_search_jobs_created_at ON exhaustive_search_jobs (created_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS exhaustive_search_repo_revision_jobs_batch_id;
+
+ALTER TABLE exhaustive_search_repo_revision_jobs
+    DROP COLUMN IF EXISTS batch_id;
@@ -0,0 +1,4 @@
+ALTER TABLE exhaustive_search_repo_revision_jobs
+    ADD COLUMN IF NOT EXISTS batch_id integer;
+
+CREATE INDEX IF NOT EXISTS exhaustive_search_repo_revision_jobs_batch_id ON exhaustive_search_repo_revision_jobs (batch_id) WHERE batch_id IS NOT NULL;
@@ -22,6 +22,65 @@ func prometheusAlertName(level, service, name string) string {
 	return fmt.Sprintf("%s_%s_%s", level, service, name)
 }
 
+// AlertRule is an alert of an observable, as documented in the alerts
+// reference.
+type AlertRule struct {
+	// Name is the name of the alert, <level>_<service>_<observable>.
+	Name       string
+	Service    string
+	Observable string
+	Level      string
+
+	Description string
+	Query       string
+	Threshold   float64
+	For         time.Duration
+}
+
+// AlertRules returns the alerts of the observables of the given dashboards, in
+// the order of the alerts reference.
+func AlertRules(dashboards ...*Dashboard) ([]AlertRule, error) {
+	var rules []AlertRule
+	for _, c := range dashboards {
+		for _, g := range c.Groups {
+			for _, r := range g.Rows {
+				for _, o := range r {
+					for _, alert := range []struct {
+						level     string
+						threshold *ObservableAlertDefinition
+					}{
+						{level: "warning", threshold: o.Warning},
+						{level: "critical", threshold: o.Critical},
+					} {
+						if alert.threshold.isEmpty() {
+							continue
+						}
+						description, err := c.alertDescription(o, alert.threshold)
+						if err != nil {
+							return nil, errors.Errorf("%s.%s.%s: unable to generate description: %+v", c.Name, o.Name, alert.level, err)
+						}
+						query, err := alert.threshold.generateAlertQuery(o, nil, newVariableApplier(c.Variables))
+						if err != nil {
+							return nil, errors.Errorf("%s.%s.%s: unable to generate query: %+v", c.Name, o.Name, alert.level, err)
+						}
+						rules = append(rules, AlertRule{
+							Name:        prometheusAlertName(alert.level, c.Name, o.Name),
+							Service:     c.Name,
+							Observable:  o.Name,
+							Level:       alert.level,
+							Description: description,
+							Query:       query,
+							Threshold:   alert.threshold.threshold,
+							For:         alert.threshold.duration,
+						})
+					}
+				}
+			}
+		}
+	}
+	return rules, nil
+}
+
 // PrometheusRule is a subset of a Prometheus recording or alert rule definition.
 type PrometheusRule struct {
 	// either Record or Alert
@@ -82,6 +82,8 @@ type AuditLog struct {
 	GraphQL bool `json:"graphQL"`
 	// InternalTraffic description: Capture security events performed by the internal traffic (adds significant noise).
 	InternalTraffic bool `json:"internalTraffic"`
+	// RedactSearchJobQueries description: Replace the query of search jobs with REDACTED in the audit log entries of search jobs.
+	RedactSearchJobQueries bool `json:"redactSearchJobQueries,omitempty"`
 	// SeverityLevel description: DEPRECATED: No effect, audit logs are always set to SRC_LOG_LEVEL
 	SeverityLevel string `json:"severityLevel,omitempty"`
 }
@@ -2311,10 +2313,16 @@ type SearchLimits struct {
 	CommitDiffMaxRepos int `json:"commitDiffMaxRepos,omitempty"`
 	// CommitDiffWithTimeFilterMaxRepos description: The maximum number of repositories to search across when doing a "type:diff" or "type:commit" with a "after:" or "before:" filter. The user is prompted to narrow their query if the limit is exceeded. There is a separate limit (commitDiffMaxRepos) when "after:" or "before:" is not specified because those queries are slower. Defaults to 10000.
 	CommitDiffWithTimeFilterMaxRepos int `json:"commitDiffWithTimeFilterMaxRepos,omitempty"`
+	// MaxActiveSearchJobs description: The maximum number of search jobs which can run at once on the instance. Search jobs which are queued or in progress count as running. New search jobs are rejected once the limit is reached. A negative value means unlimited. Defaults to 100.
+	MaxActiveSearchJobs int `json:"maxActiveSearchJobs,omitempty"`
+	// MaxActiveSearchJobsPerUser description: The maximum number of search jobs a user can have running at once. Search jobs which are queued or in progress count as running. Site admins are exempt. A negative value means unlimited. Defaults to 5.
+	MaxActiveSearchJobsPerUser int `json:"maxActiveSearchJobsPerUser,omitempty"`
 	// MaxRepos description: The maximum number of repositories to search across. The user is prompted to narrow their query if exceeded. Any value less than or equal to zero means unlimited.
 	MaxRepos int `json:"maxRepos,omitempty"`
 	// MaxTimeoutSeconds description: The maximum value for "timeout:" that search will respect. "timeout:" values larger than maxTimeoutSeconds are capped at maxTimeoutSeconds. Note: You need to ensure your load balancer / reverse proxy in front of Sourcegraph won't timeout the request for larger values. Note: Too many large rearch requests may harm Soucregraph for other users. Note: Experimental search jobs do not respect this limit. Defaults to 1 minute.
 	MaxTimeoutSeconds int `json:"maxTimeoutSeconds,omitempty"`
+	// SearchJobIdempotencyWindowSeconds description: How long the idempotency key of a search job is kept after the job was created. Creating a search job with the same key and query within this window returns the existing job instead of creating another one. Defaults to 1 day.
+	SearchJobIdempotencyWindowSeconds int `json:"searchJobIdempotencyWindowSeconds,omitempty"`
 }
 
 // SearchSanitization description: Allows site admins to specify a list of regular expressions representing matched content that should be omitted from search results. Also allows admins to specify the name of an organization within their Sourcegraph instance whose members are trusted and will not have their search results sanitized. Enable this feature by adding at least one valid regular expression to the value of the `sanitizePatterns` field on this object. Site admins will not have their searches sanitized.
@@ -2969,6 +2977,8 @@ type SiteConfiguration struct {
 	SearchIndexShardConcurrency int `json:"search.index.shardConcurrency,omitempty"`
 	// SearchIndexSymbolsEnabled description: Whether indexed symbol search is enabled. This is contingent on the indexed search configuration, and is true by default for instances with indexed search enabled. Enabling this will cause every repository to re-index, which is a time consuming (several hours) operation. Additionally, it requires more storage and ram to accommodate the added symbols information in the search index.
 	SearchIndexSymbolsEnabled *bool `json:"search.index.symbols.enabled,omitempty"`
+	// SearchJobsDownloadPermissionCheck description: Re-checks the current repository permissions of the initiator of a search job when its results are downloaded, since they may have changed since the job ran. "filter" leaves out the results of repositories the initiator can't read anymore, "refuse" refuses the download if there are any. Site admins who download the results of other users bypass the check, which is recorded in the audit log. Defaults to "off".
+	SearchJobsDownloadPermissionCheck string `json:"search.jobs.downloadPermissionCheck,omitempty"`
 	// SearchLargeFiles description: A list of file glob patterns where matching files will be indexed and searched regardless of their size. Files still need to be valid utf-8 to be indexed. The glob pattern syntax can be found here: https://github.com/bmatcuk/doublestar#patterns.
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
 	// SearchLimits description: Limits that search applies for number of repositories searched and timeouts.